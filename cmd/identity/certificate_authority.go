@@ -46,8 +46,9 @@ var (
 		Annotations: map[string]string{"type": "setup"},
 	}
 	revokeCACmd = &cobra.Command{
-		Use:         "revoke",
+		Use:         "revoke [service]",
 		Short:       "Revoke the identity's CA certificate (creates backup)",
+		Args:        cobra.MaximumNArgs(1),
 		RunE:        cmdRevokeCA,
 		Annotations: map[string]string{"type": "setup"},
 	}
@@ -71,11 +72,6 @@ var (
 		CA identity.FullCAConfig
 	}
 
-	revokeCACfg struct {
-		CA identity.FullCAConfig
-		// TODO: add "broadcast" option to send revocation to network nodes
-	}
-
 	revokePeerCACfg struct {
 		CA              identity.FullCAConfig
 		PeerCA          identity.PeerCAConfig
@@ -109,7 +105,7 @@ func init() {
 	cfgstruct.Bind(newCACmd.Flags(), &newCACfg, isDev, cfgstruct.IdentityDir(defaultIdentityDir))
 	cfgstruct.Bind(getIDCmd.Flags(), &getIDCfg, isDev, cfgstruct.IdentityDir(defaultIdentityDir))
 	cfgstruct.Bind(caExtCmd.Flags(), &caExtCfg, isDev, cfgstruct.IdentityDir(defaultIdentityDir))
-	cfgstruct.Bind(revokeCACmd.Flags(), &revokeCACfg, isDev, cfgstruct.IdentityDir(defaultIdentityDir))
+	cfgstruct.Bind(revokeCACmd.Flags(), &revokeCACfg, isDev, cfgstruct.ConfDir(defaultConfigDir), cfgstruct.IdentityDir(defaultIdentityDir))
 	cfgstruct.Bind(revokePeerCACmd.Flags(), &revokePeerCACfg, isDev, cfgstruct.ConfDir(defaultConfigDir), cfgstruct.IdentityDir(defaultIdentityDir))
 }
 
@@ -128,30 +124,6 @@ func cmdGetID(cmd *cobra.Command, args []string) (err error) {
 	return nil
 }
 
-func cmdRevokeCA(cmd *cobra.Command, args []string) (err error) {
-	ca, err := revokeCACfg.CA.Load()
-	if err != nil {
-		return err
-	}
-
-	// NB: backup original cert
-	if err := revokeCACfg.CA.SaveBackup(ca); err != nil {
-		return err
-	}
-
-	if err := extensions.AddRevocationExt(ca.Key, ca.Cert, ca.Cert); err != nil {
-		return err
-	}
-
-	updateCfg := identity.FullCAConfig{
-		CertPath: revokeCACfg.CA.CertPath,
-	}
-	if err := updateCfg.Save(ca); err != nil {
-		return err
-	}
-	return nil
-}
-
 func cmdRevokePeerCA(cmd *cobra.Command, args []string) (err error) {
 	argLen := len(args)
 	switch {
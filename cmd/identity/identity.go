@@ -11,7 +11,6 @@ import (
 
 	"storj.io/storj/pkg/cfgstruct"
 	"storj.io/storj/pkg/identity"
-	"storj.io/storj/pkg/peertls/extensions"
 )
 
 var (
@@ -40,8 +39,9 @@ var (
 	}
 
 	revokeLeafCmd = &cobra.Command{
-		Use:         "revoke",
+		Use:         "revoke [service]",
 		Short:       "Revoke the identity's leaf certificate (creates backup)",
+		Args:        cobra.MaximumNArgs(1),
 		RunE:        cmdRevokeLeaf,
 		Annotations: map[string]string{"type": "setup"},
 	}
@@ -54,12 +54,6 @@ var (
 	leafExtCfg struct {
 		Identity identity.PeerConfig
 	}
-
-	revokeLeafCfg struct {
-		CA       identity.FullCAConfig
-		Identity identity.PeerConfig
-		// TODO: add "broadcast" option to send revocation to network nodes
-	}
 )
 
 func init() {
@@ -70,7 +64,7 @@ func init() {
 
 	cfgstruct.Bind(newIDCmd.Flags(), &newIDCfg, isDev, cfgstruct.IdentityDir(defaultIdentityDir))
 	cfgstruct.Bind(leafExtCmd.Flags(), &leafExtCfg, isDev, cfgstruct.IdentityDir(defaultIdentityDir))
-	cfgstruct.Bind(revokeLeafCmd.Flags(), &revokeLeafCfg, isDev, cfgstruct.IdentityDir(defaultIdentityDir))
+	cfgstruct.Bind(revokeLeafCmd.Flags(), &revokeLeafCfg, isDev, cfgstruct.ConfDir(defaultConfigDir), cfgstruct.IdentityDir(defaultIdentityDir))
 }
 
 func cmdNewID(cmd *cobra.Command, args []string) (err error) {
@@ -104,36 +98,3 @@ func cmdLeafExtensions(cmd *cobra.Command, args []string) (err error) {
 
 	return printExtensions(ident.Leaf.Raw, ident.Leaf.ExtraExtensions)
 }
-
-func cmdRevokeLeaf(cmd *cobra.Command, args []string) (err error) {
-	ca, err := revokeLeafCfg.CA.Load()
-	if err != nil {
-		return err
-	}
-	originalIdent, err := revokeLeafCfg.Identity.Load()
-	if err != nil {
-		return err
-	}
-
-	updatedIdent, err := ca.NewIdentity()
-	if err != nil {
-		return err
-	}
-
-	if err := extensions.AddRevocationExt(ca.Key, originalIdent.Leaf, updatedIdent.Leaf); err != nil {
-		return err
-	}
-
-	// NB: backup original cert
-	if err := revokeLeafCfg.Identity.SaveBackup(originalIdent); err != nil {
-		return err
-	}
-
-	updateCfg := identity.Config{
-		CertPath: revokeLeafCfg.Identity.CertPath,
-	}
-	if err := updateCfg.Save(updatedIdent); err != nil {
-		return err
-	}
-	return nil
-}
@@ -26,7 +26,7 @@ var (
 	}
 
 	revCfg struct {
-		RevocationDBURL string `default:"bolt://$CONFDIR/revocations.db" help:"url for revocation database (e.g. bolt://some.db OR redis://127.0.0.1:6378?db=2&password=abc123)"`
+		RevocationDBURL string `default:"bolt://$CONFDIR/revocations.db" help:"url for revocation database (e.g. bolt://some.db OR redis://127.0.0.1:6378?db=2&password=abc123 OR redis-sentinel://127.0.0.1:26379?master=main&db=2)"`
 	}
 )
 
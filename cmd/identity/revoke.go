@@ -0,0 +1,223 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/cfgstruct"
+	"storj.io/storj/pkg/identity"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/peertls/extensions"
+	"storj.io/storj/pkg/peertls/tlsopts"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/revocation"
+	"storj.io/storj/pkg/transport"
+)
+
+var (
+	revokeCmd = &cobra.Command{
+		Use:         "revoke",
+		Short:       "Revoke certificates of an identity and publish the revocations",
+		Annotations: map[string]string{"type": "setup"},
+	}
+
+	revokeLeafServiceCmd = &cobra.Command{
+		Use:         "leaf [service]",
+		Short:       "Revoke the identity's leaf certificate, replacing it with a new one (creates backup)",
+		Args:        cobra.MaximumNArgs(1),
+		RunE:        cmdRevokeLeaf,
+		Annotations: map[string]string{"type": "setup"},
+	}
+
+	revokeCAServiceCmd = &cobra.Command{
+		Use:         "ca [service]",
+		Short:       "Revoke the identity's CA certificate (creates backup)",
+		Args:        cobra.MaximumNArgs(1),
+		RunE:        cmdRevokeCA,
+		Annotations: map[string]string{"type": "setup"},
+	}
+
+	revokeLeafCfg struct {
+		CA              identity.FullCAConfig
+		Identity        identity.Config
+		RevocationDBURL string `default:"bolt://$CONFDIR/revocations.db" help:"url for revocation database the revocation is added to"`
+		Publish         string `default:"" help:"comma separated list of peers (nodeid@address) to publish the revocation to"`
+	}
+
+	revokeCACfg struct {
+		CA              identity.FullCAConfig
+		Identity        identity.Config
+		RevocationDBURL string `default:"bolt://$CONFDIR/revocations.db" help:"url for revocation database the revocation is added to"`
+		Publish         string `default:"" help:"comma separated list of peers (nodeid@address) to publish the revocation to"`
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(revokeCmd)
+	revokeCmd.AddCommand(revokeLeafServiceCmd)
+	revokeCmd.AddCommand(revokeCAServiceCmd)
+
+	cfgstruct.Bind(revokeLeafServiceCmd.Flags(), &revokeLeafCfg, isDev, cfgstruct.ConfDir(defaultConfigDir), cfgstruct.IdentityDir(defaultIdentityDir))
+	cfgstruct.Bind(revokeCAServiceCmd.Flags(), &revokeCACfg, isDev, cfgstruct.ConfDir(defaultConfigDir), cfgstruct.IdentityDir(defaultIdentityDir))
+}
+
+func cmdRevokeLeaf(cmd *cobra.Command, args []string) (err error) {
+	if len(args) > 0 {
+		revokeLeafCfg.CA = serviceCAConfig(args[0])
+		revokeLeafCfg.Identity = serviceIdentityConfig(args[0])
+		revokeLeafCfg.RevocationDBURL = serviceRevocationDBURL(args[0])
+	}
+
+	peers, err := revocation.ParsePeers(revokeLeafCfg.Publish)
+	if err != nil {
+		return err
+	}
+
+	ca, err := revokeLeafCfg.CA.Load()
+	if err != nil {
+		return err
+	}
+	originalIdent, err := revokeLeafCfg.Identity.Load()
+	if err != nil {
+		return err
+	}
+
+	updatedIdent, err := ca.NewIdentity()
+	if err != nil {
+		return err
+	}
+
+	ext, err := extensions.NewRevocationExt(ca.Key, originalIdent.Leaf)
+	if err != nil {
+		return err
+	}
+	if err := extensions.AddExtension(updatedIdent.Leaf, ext); err != nil {
+		return err
+	}
+
+	// NB: the revocation is stored first, so the identity is left untouched
+	// when it is rejected
+	if err := storeRevocation(revokeLeafCfg.RevocationDBURL, ca.Cert, ext); err != nil {
+		return err
+	}
+
+	// NB: backup original cert and key
+	if err := revokeLeafCfg.Identity.SaveBackup(originalIdent); err != nil {
+		return err
+	}
+	if err := revokeLeafCfg.Identity.Save(updatedIdent); err != nil {
+		return err
+	}
+	return publishRevocation(process.Ctx(cmd), updatedIdent, peers, ext)
+}
+
+func cmdRevokeCA(cmd *cobra.Command, args []string) (err error) {
+	if len(args) > 0 {
+		revokeCACfg.CA = serviceCAConfig(args[0])
+		revokeCACfg.Identity = serviceIdentityConfig(args[0])
+		revokeCACfg.RevocationDBURL = serviceRevocationDBURL(args[0])
+	}
+
+	peers, err := revocation.ParsePeers(revokeCACfg.Publish)
+	if err != nil {
+		return err
+	}
+
+	ca, err := revokeCACfg.CA.Load()
+	if err != nil {
+		return err
+	}
+
+	// NB: the identity is only used to connect to the peers
+	var ident *identity.FullIdentity
+	if len(peers) > 0 {
+		ident, err = revokeCACfg.Identity.Load()
+		if err != nil {
+			return err
+		}
+	}
+
+	ext, err := extensions.NewRevocationExt(ca.Key, ca.Cert)
+	if err != nil {
+		return err
+	}
+
+	// NB: the revocation is stored first, so the CA is left untouched when
+	// it is rejected
+	if err := storeRevocation(revokeCACfg.RevocationDBURL, ca.Cert, ext); err != nil {
+		return err
+	}
+
+	// NB: backup original cert
+	if err := revokeCACfg.CA.SaveBackup(ca); err != nil {
+		return err
+	}
+	if err := extensions.AddExtension(ca.Cert, ext); err != nil {
+		return err
+	}
+
+	updateCfg := identity.FullCAConfig{
+		CertPath: revokeCACfg.CA.CertPath,
+	}
+	if err := updateCfg.Save(ca); err != nil {
+		return err
+	}
+	return publishRevocation(process.Ctx(cmd), ident, peers, ext)
+}
+
+// storeRevocation adds a revocation signed by ca to the local revocation database
+func storeRevocation(revocationDBURL string, ca *x509.Certificate, ext pkix.Extension) (err error) {
+	revDB, err := identity.NewRevocationDB(revocationDBURL)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, revDB.Close()) }()
+
+	// NB: the revocation database only uses the CA of the chain
+	return revDB.Put([]*x509.Certificate{nil, ca}, ext)
+}
+
+// publishRevocation sends the revocation to the peers, connecting with ident
+func publishRevocation(ctx context.Context, ident *identity.FullIdentity, peers []*pb.Node, ext pkix.Extension) error {
+	if len(peers) == 0 {
+		return nil
+	}
+
+	tlsOpts, err := tlsopts.NewOptions(ident, tlsopts.Config{})
+	if err != nil {
+		return err
+	}
+
+	if err := revocation.Publish(ctx, transport.NewClient(tlsOpts), peers, ext); err != nil {
+		return err
+	}
+	fmt.Printf("published revocation to %d peer(s)\n", len(peers))
+	return nil
+}
+
+func serviceCAConfig(service string) identity.FullCAConfig {
+	return identity.FullCAConfig{
+		CertPath: filepath.Join(identityDir, service, "ca.cert"),
+		KeyPath:  filepath.Join(identityDir, service, "ca.key"),
+	}
+}
+
+func serviceIdentityConfig(service string) identity.Config {
+	return identity.Config{
+		CertPath: filepath.Join(identityDir, service, "identity.cert"),
+		KeyPath:  filepath.Join(identityDir, service, "identity.key"),
+	}
+}
+
+func serviceRevocationDBURL(service string) string {
+	return "bolt://" + filepath.Join(configDir, service, "revocations.db")
+}
@@ -11,16 +11,20 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gogo/protobuf/jsonpb"
 	"github.com/gogo/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	prompt "github.com/segmentio/go-prompt"
 	"github.com/spf13/cobra"
 	"github.com/zeebo/errs"
 
+	"storj.io/storj/internal/debugbundle"
 	"storj.io/storj/pkg/identity"
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/process"
@@ -48,6 +52,14 @@ var (
 	ErrArgs = errs.Class("error with CLI args:")
 
 	irreparableLimit int32
+	auditDryRun      bool
+
+	profileCfg struct {
+		Address  string
+		Token    string
+		Duration time.Duration
+		Output   string
+	}
 
 	// Commander CLI
 	rootCmd = &cobra.Command{
@@ -101,6 +113,12 @@ var (
 		Args:  cobra.MinimumNArgs(1),
 		RunE:  GetStats,
 	}
+	reputationCmd = &cobra.Command{
+		Use:   "reputation <node_id> [days]",
+		Short: "Get the reputation of a node and its daily scores",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE:  ReputationHistory,
+	}
 	getCSVStatsCmd = &cobra.Command{
 		Use:   "getcsvstats <path to node ID csv file>",
 		Short: "Get node stats from csv",
@@ -121,6 +139,56 @@ var (
 		Args:  cobra.MinimumNArgs(1),
 		RunE:  CreateCSVStats,
 	}
+	segmentHealthCmd = &cobra.Command{
+		Use:   "health <path>",
+		Short: "report the health of a segment or of all the segments of an object",
+		Args:  cobra.ExactArgs(1),
+		RunE:  SegmentHealth,
+	}
+	auditCmd = &cobra.Command{
+		Use:   "audit",
+		Short: "commands for the audit history",
+	}
+	auditNodeCmd = &cobra.Command{
+		Use:   "node <node_id> [limit]",
+		Short: "list the most recent audits of a node",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE:  NodeAuditHistory,
+	}
+	auditSegmentCmd = &cobra.Command{
+		Use:   "segment <path> [limit]",
+		Short: "list the most recent audits of a segment",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE:  SegmentAuditHistory,
+	}
+	disqualificationCmd = &cobra.Command{
+		Use:   "dq",
+		Short: "commands for reviewing and reinstating disqualified nodes",
+	}
+	disqualifiedCmd = &cobra.Command{
+		Use:   "list",
+		Short: "list the disqualified nodes with their latest event",
+		Args:  cobra.NoArgs,
+		RunE:  ListDisqualified,
+	}
+	disqualificationEventsCmd = &cobra.Command{
+		Use:   "events <node_id> [limit]",
+		Short: "list the most recent disqualifications and reinstatements of a node",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE:  DisqualificationEvents,
+	}
+	reinstateCmd = &cobra.Command{
+		Use:   "reinstate <node_id> <detail>",
+		Short: "clear the disqualification of a node, detail records why",
+		Args:  cobra.ExactArgs(2),
+		RunE:  Reinstate,
+	}
+	profileCmd = &cobra.Command{
+		Use:   "profile",
+		Short: "Capture a bundle of profiles from the debug endpoint of a running process",
+		Args:  cobra.NoArgs,
+		RunE:  CaptureProfile,
+	}
 )
 
 // Inspector gives access to kademlia, overlay cache
@@ -129,6 +197,9 @@ type Inspector struct {
 	kadclient     pb.KadInspectorClient
 	overlayclient pb.OverlayInspectorClient
 	irrdbclient   pb.IrreparableInspectorClient
+	healthclient  pb.HealthInspectorClient
+	auditclient   pb.AuditInspectorClient
+	dqclient      pb.DisqualificationInspectorClient
 }
 
 // NewInspector creates a new gRPC inspector client for access to kad,
@@ -154,6 +225,9 @@ func NewInspector(address, path string) (*Inspector, error) {
 		kadclient:     pb.NewKadInspectorClient(conn),
 		overlayclient: pb.NewOverlayInspectorClient(conn),
 		irrdbclient:   pb.NewIrreparableInspectorClient(conn),
+		healthclient:  pb.NewHealthInspectorClient(conn),
+		auditclient:   pb.NewAuditInspectorClient(conn),
+		dqclient:      pb.NewDisqualificationInspectorClient(conn),
 	}, nil
 }
 
@@ -309,6 +383,55 @@ func GetStats(cmd *cobra.Command, args []string) (err error) {
 	return nil
 }
 
+// ReputationHistory gets the reputation of a node and its daily scores from overlay
+func ReputationHistory(cmd *cobra.Command, args []string) (err error) {
+	i, err := NewInspector(*Addr, *IdentityPath)
+	if err != nil {
+		return ErrInspectorDial.Wrap(err)
+	}
+
+	nodeID, err := storj.NodeIDFromString(args[0])
+	if err != nil {
+		return err
+	}
+
+	days := int64(30)
+	if len(args) > 1 {
+		days, err = strconv.ParseInt(args[1], 10, 32)
+		if err != nil {
+			return ErrArgs.Wrap(err)
+		}
+	}
+
+	res, err := i.overlayclient.ReputationHistory(context.Background(), &pb.ReputationHistoryRequest{
+		NodeId: nodeID,
+		Days:   int32(days),
+	})
+	if err != nil {
+		return ErrRequest.Wrap(err)
+	}
+
+	fmt.Printf("Reputation for ID %s:\n", nodeID)
+	fmt.Printf("Audit alpha: %f, beta: %f\n", res.AuditAlpha, res.AuditBeta)
+	fmt.Printf("Uptime alpha: %f, beta: %f\n", res.UptimeAlpha, res.UptimeBeta)
+	if res.Disqualified != nil {
+		disqualified, err := ptypes.Timestamp(res.Disqualified)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Disqualified: %s\n", disqualified.Format(time.RFC3339))
+	}
+
+	for _, score := range res.Scores {
+		day, err := ptypes.Timestamp(score.IntervalStart)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s AuditScore: %f, UptimeScore: %f\n", day.Format("2006-01-02"), score.AuditScore, score.UptimeScore)
+	}
+	return nil
+}
+
 // GetCSVStats gets node stats from overlay based on a csv
 func GetCSVStats(cmd *cobra.Command, args []string) (err error) {
 	i, err := NewInspector(*Addr, *IdentityPath)
@@ -504,10 +627,188 @@ func sortSegments(segments []*pb.IrreparableSegment) map[string][]*pb.Irreparabl
 	return objects
 }
 
+// SegmentHealth prints the health of a segment or of all the segments of an object
+func SegmentHealth(cmd *cobra.Command, args []string) (err error) {
+	i, err := NewInspector(*Addr, *IdentityPath)
+	if err != nil {
+		return ErrInspectorDial.Wrap(err)
+	}
+
+	res, err := i.healthclient.SegmentHealth(context.Background(), &pb.SegmentHealthRequest{
+		Path: []byte(args[0]),
+	})
+	if err != nil {
+		return ErrRequest.Wrap(err)
+	}
+
+	for _, segment := range res.Segments {
+		fmt.Printf("Segment %s\n", segment.Path)
+		if segment.Inline {
+			fmt.Println("  inline segment, no pieces on nodes")
+			continue
+		}
+
+		fmt.Printf("  health: %s\n", segmentState(segment))
+		fmt.Printf("  healthy pieces: %d (min %d, repair %d, success %d, total %d)\n",
+			segment.HealthyPieces, segment.MinReq, segment.RepairThreshold, segment.SuccessThreshold, segment.Total)
+		fmt.Printf("  queued for repair: %t\n", segment.QueuedForRepair)
+
+		for _, piece := range segment.Pieces {
+			fmt.Printf("  piece %d on %s: online %t, reputable %t, audits %d (%.2f), uptime checks %d (%.2f)\n",
+				piece.PieceNum, piece.NodeId, piece.Online, piece.Reputable,
+				piece.AuditCount, piece.AuditSuccessRatio, piece.UptimeCount, piece.UptimeRatio)
+		}
+	}
+	return nil
+}
+
+// segmentState describes the health of a segment compared to its thresholds
+func segmentState(segment *pb.SegmentHealth) string {
+	switch {
+	case segment.HealthyPieces < segment.MinReq:
+		return "irreparable"
+	case segment.HealthyPieces < segment.RepairThreshold:
+		return "needs repair"
+	default:
+		return "healthy"
+	}
+}
+
+// NodeAuditHistory prints the most recent audits of a node
+func NodeAuditHistory(cmd *cobra.Command, args []string) (err error) {
+	nodeID, err := storj.NodeIDFromString(args[0])
+	if err != nil {
+		return ErrArgs.Wrap(err)
+	}
+	return printAuditHistory(&pb.AuditHistoryRequest{NodeId: nodeID}, args[1:])
+}
+
+// SegmentAuditHistory prints the most recent audits of a segment
+func SegmentAuditHistory(cmd *cobra.Command, args []string) (err error) {
+	return printAuditHistory(&pb.AuditHistoryRequest{Path: []byte(args[0])}, args[1:])
+}
+
+// printAuditHistory requests the audit records, limited by the optional argument, and prints them
+func printAuditHistory(req *pb.AuditHistoryRequest, args []string) (err error) {
+	if len(args) > 0 {
+		limit, err := strconv.ParseInt(args[0], 10, 32)
+		if err != nil {
+			return ErrArgs.Wrap(err)
+		}
+		req.Limit = int32(limit)
+	}
+	req.DryRun = auditDryRun
+
+	i, err := NewInspector(*Addr, *IdentityPath)
+	if err != nil {
+		return ErrInspectorDial.Wrap(err)
+	}
+
+	res, err := i.auditclient.AuditHistory(context.Background(), req)
+	if err != nil {
+		return ErrRequest.Wrap(err)
+	}
+
+	for _, record := range res.Records {
+		createdAt, err := ptypes.Timestamp(record.CreatedAt)
+		if err != nil {
+			return err
+		}
+		reverify := ""
+		if record.Reverify {
+			reverify = " (reverify)"
+		}
+		fmt.Printf("%s %s stripe %d node %s: %s%s\n",
+			createdAt.Format(time.RFC3339), record.SegmentPath, record.StripeIndex, record.NodeId, record.Outcome, reverify)
+	}
+	return nil
+}
+
+// ListDisqualified prints the disqualified nodes with their latest event
+func ListDisqualified(cmd *cobra.Command, args []string) (err error) {
+	i, err := NewInspector(*Addr, *IdentityPath)
+	if err != nil {
+		return ErrInspectorDial.Wrap(err)
+	}
+
+	res, err := i.dqclient.Disqualified(context.Background(), &pb.DisqualifiedRequest{})
+	if err != nil {
+		return ErrRequest.Wrap(err)
+	}
+	return printDisqualificationEvents(res.Events)
+}
+
+// DisqualificationEvents prints the most recent disqualifications and reinstatements of a node
+func DisqualificationEvents(cmd *cobra.Command, args []string) (err error) {
+	nodeID, err := storj.NodeIDFromString(args[0])
+	if err != nil {
+		return ErrArgs.Wrap(err)
+	}
+
+	req := &pb.DisqualificationEventsRequest{NodeId: nodeID}
+	if len(args) > 1 {
+		limit, err := strconv.ParseInt(args[1], 10, 32)
+		if err != nil {
+			return ErrArgs.Wrap(err)
+		}
+		req.Limit = int32(limit)
+	}
+
+	i, err := NewInspector(*Addr, *IdentityPath)
+	if err != nil {
+		return ErrInspectorDial.Wrap(err)
+	}
+
+	res, err := i.dqclient.DisqualificationEvents(context.Background(), req)
+	if err != nil {
+		return ErrRequest.Wrap(err)
+	}
+	return printDisqualificationEvents(res.Events)
+}
+
+// Reinstate clears the disqualification of a node
+func Reinstate(cmd *cobra.Command, args []string) (err error) {
+	nodeID, err := storj.NodeIDFromString(args[0])
+	if err != nil {
+		return ErrArgs.Wrap(err)
+	}
+
+	i, err := NewInspector(*Addr, *IdentityPath)
+	if err != nil {
+		return ErrInspectorDial.Wrap(err)
+	}
+
+	_, err = i.dqclient.Reinstate(context.Background(), &pb.ReinstateRequest{
+		NodeId: nodeID,
+		Detail: args[1],
+	})
+	if err != nil {
+		return ErrRequest.Wrap(err)
+	}
+
+	fmt.Printf("Reinstated node %s\n", nodeID)
+	return nil
+}
+
+// printDisqualificationEvents prints a line for every event
+func printDisqualificationEvents(events []*pb.DisqualificationEvent) error {
+	for _, event := range events {
+		createdAt, err := ptypes.Timestamp(event.CreatedAt)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s node %s: %s (%s)\n", createdAt.Format(time.RFC3339), event.NodeId, event.Reason, event.Detail)
+	}
+	return nil
+}
+
 func init() {
 	rootCmd.AddCommand(kadCmd)
 	rootCmd.AddCommand(statsCmd)
 	rootCmd.AddCommand(irreparableCmd)
+	rootCmd.AddCommand(segmentHealthCmd)
+	rootCmd.AddCommand(auditCmd)
+	rootCmd.AddCommand(disqualificationCmd)
 
 	kadCmd.AddCommand(countNodeCmd)
 	kadCmd.AddCommand(pingNodeCmd)
@@ -517,14 +818,51 @@ func init() {
 
 	statsCmd.AddCommand(getStatsCmd)
 	statsCmd.AddCommand(getCSVStatsCmd)
+	statsCmd.AddCommand(reputationCmd)
 	statsCmd.AddCommand(createStatsCmd)
 	statsCmd.AddCommand(createCSVStatsCmd)
 
+	auditCmd.AddCommand(auditNodeCmd)
+	auditCmd.AddCommand(auditSegmentCmd)
+
+	disqualificationCmd.AddCommand(disqualifiedCmd)
+	disqualificationCmd.AddCommand(disqualificationEventsCmd)
+	disqualificationCmd.AddCommand(reinstateCmd)
+
 	irreparableCmd.Flags().Int32Var(&irreparableLimit, "limit", 50, "max number of results per page")
+	auditCmd.PersistentFlags().BoolVar(&auditDryRun, "dry-run", false, "list the audits of the dry run instead")
+
+	rootCmd.AddCommand(profileCmd)
+	profileCmd.Flags().StringVar(&profileCfg.Address, "debug-address", "127.0.0.1:7777", "debug address of the process to profile")
+	profileCmd.Flags().StringVar(&profileCfg.Token, "debug-token", "", "debug token configured on the process")
+	profileCmd.Flags().DurationVar(&profileCfg.Duration, "duration", 30*time.Second, "how long to capture the cpu profile and the execution trace for")
+	profileCmd.Flags().StringVar(&profileCfg.Output, "output", "profile.tar.gz", "file to write the profiles to")
 
 	flag.Parse()
 }
 
+// CaptureProfile captures the profiles of a running process into an archive
+func CaptureProfile(cmd *cobra.Command, args []string) (err error) {
+	file, err := os.Create(profileCfg.Output)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, file.Close()) }()
+
+	fmt.Printf("Capturing profiles from %s for %v\n", profileCfg.Address, profileCfg.Duration)
+	err = debugbundle.Capture(process.Ctx(cmd), http.DefaultClient, debugbundle.Options{
+		Address:  profileCfg.Address,
+		Token:    profileCfg.Token,
+		Duration: profileCfg.Duration,
+	}, file)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Profiles written to %s\n", profileCfg.Output)
+	return nil
+}
+
 func main() {
 	process.Exec(rootCmd)
 }
@@ -0,0 +1,71 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/audit"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/satellite/satellitedb"
+)
+
+// cmdAuditReport prints the audits per day, the estimated share of the
+// segments audited and the nodes nearing disqualification.
+func cmdAuditReport(cmd *cobra.Command, args []string) (err error) {
+	ctx := process.Ctx(cmd)
+
+	if auditReportCfg.Days <= 0 {
+		return errs.New("days must be positive")
+	}
+
+	database, err := satellitedb.New(zap.L().Named("db"), auditReportCfg.Database)
+	if err != nil {
+		return errs.New("error connecting to master database on satellite: %+v", err)
+	}
+	defer func() {
+		err = errs.Combine(err, database.Close())
+	}()
+
+	report, err := audit.BuildReport(ctx, database.AuditStats(), auditReportCfg.Days, auditReportCfg.Audit.ReportWarningScore)
+	if err != nil {
+		return err
+	}
+
+	const padding = 3
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, padding, ' ', tabwriter.AlignRight|tabwriter.Debug)
+	fmt.Fprintln(w, "Day\tSegments\tSuccess\tFailure\tOffline\tContained\tTotal Segments\t")
+	for _, day := range report.Days {
+		fmt.Fprint(w, day.IntervalStart.Format("2006-01-02"), "\t", day.SegmentsAudited, "\t",
+			day.Outcomes[audit.OutcomeSuccess], "\t", day.Outcomes[audit.OutcomeFailure], "\t",
+			day.Outcomes[audit.OutcomeOffline], "\t", day.Outcomes[audit.OutcomeContained], "\t",
+			day.TotalSegments, "\t\n")
+	}
+	fmt.Fprint(w, "Total\t", report.SegmentsAudited, "\t",
+		report.Outcomes[audit.OutcomeSuccess], "\t", report.Outcomes[audit.OutcomeFailure], "\t",
+		report.Outcomes[audit.OutcomeOffline], "\t", report.Outcomes[audit.OutcomeContained], "\t",
+		report.TotalSegments, "\t\n")
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nEstimated segments audited in the last %d days: %.2f%%\n", auditReportCfg.Days, report.Coverage*100)
+
+	fmt.Printf("\nNodes with an audit score below %v: %d\n", auditReportCfg.Audit.ReportWarningScore, len(report.NodesAtRisk))
+	if len(report.NodesAtRisk) == 0 {
+		return nil
+	}
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, padding, ' ', tabwriter.AlignRight|tabwriter.Debug)
+	fmt.Fprintln(w, "NodeID\tAudit Score\t")
+	for _, node := range report.NodesAtRisk {
+		fmt.Fprintf(w, "%s\t%.4f\t\n", node.NodeID, node.AuditScore)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,103 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/storj/internal/memory"
+	"storj.io/storj/pkg/datarepair/checker"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/satellite/satellitedb"
+)
+
+// cmdEstimateRepair reports what repairing the segments needing repair with
+// the given repair overrides would transfer, without repairing anything.
+func cmdEstimateRepair(cmd *cobra.Command, args []string) (err error) {
+	ctx := process.Ctx(cmd)
+
+	overrides, err := checker.ParseRepairOverrides(estimateRepairCfg.RepairOverrides)
+	if err != nil {
+		return err
+	}
+
+	database, err := satellitedb.New(zap.L().Named("db"), estimateRepairCfg.Database)
+	if err != nil {
+		return errs.New("error connecting to master database on satellite: %+v", err)
+	}
+	defer func() {
+		err = errs.Combine(err, database.Close())
+	}()
+
+	db, err := pointerdb.NewStore(estimateRepairCfg.DatabaseURL)
+	if err != nil {
+		return errs.New("error connecting to pointerdb: %+v", err)
+	}
+	defer func() {
+		err = errs.Combine(err, db.Close())
+	}()
+
+	cache := overlay.NewCache(zap.L().Named("overlay"), database.OverlayCache(),
+		estimateRepairCfg.Overlay.Node, estimateRepairCfg.Overlay.Reputation)
+
+	// the reliable nodes are loaded once, so every segment is checked
+	// against the same nodes
+	reliability := overlay.NewReliabilityCache(cache, math.MaxInt64)
+
+	estimate, err := checker.EstimateRepair(ctx, db, cache, reliability, overrides)
+	if err != nil {
+		return err
+	}
+
+	if estimateRepairCfg.Output == "" {
+		return printRepairEstimate(os.Stdout, estimate)
+	}
+	return writeFile(estimateRepairCfg.Output, func(w io.Writer) error {
+		return printRepairEstimate(w, estimate)
+	})
+}
+
+// printRepairEstimate prints the totals of the estimate and a table of the
+// segments needing repair.
+func printRepairEstimate(w io.Writer, estimate *checker.Estimate) error {
+	fmt.Fprintf(w, "Segments checked: %d, needing repair: %d, irreparable: %d, without enough new nodes: %d, invalid pointers: %d\n",
+		estimate.SegmentsChecked, estimate.SegmentsNeedingRepair, estimate.SegmentsIrreparable,
+		estimate.SegmentsUnplaceable, estimate.InvalidPointers)
+	fmt.Fprintf(w, "Pieces uploaded: %d, downloaded: %s, uploaded: %s\n\n",
+		estimate.PiecesUploaded, memory.Size(estimate.BytesDownloaded), memory.Size(estimate.BytesUploaded))
+
+	const padding = 3
+	tw := tabwriter.NewWriter(w, 0, 0, padding, ' ', tabwriter.Debug)
+	fmt.Fprintln(tw, "Path\tHealth\tHealthy\tThreshold\tMissing\tSelected Nodes\tDownload\tUpload\tError\t")
+
+	for _, segment := range estimate.Segments {
+		fmt.Fprintf(tw, "%s\t%.3f\t%d\t%d\t", strconv.Quote(segment.Path), segment.Health,
+			segment.HealthyPieces, segment.RepairThreshold)
+
+		repair := segment.Repair
+		if repair == nil {
+			fmt.Fprint(tw, "\t\t\t\tirreparable\t\n")
+			continue
+		}
+		var selectionError string
+		if repair.SelectionError != nil {
+			selectionError = repair.SelectionError.Error()
+		}
+		fmt.Fprintf(tw, "%d\t%d\t%s\t%s\t%s\t\n", repair.MissingPieces, repair.SelectedNodes,
+			memory.Size(repair.DownloadedBytes()), memory.Size(repair.UploadedBytes()), selectionError)
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,133 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/datarepair/checker"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/satellite/satellitedb"
+)
+
+// cmdIrreparableList lists the irreparable segments with their lost pieces
+// and the error of their last repair attempt.
+func cmdIrreparableList(cmd *cobra.Command, args []string) (err error) {
+	ctx := process.Ctx(cmd)
+
+	database, err := satellitedb.New(zap.L().Named("db"), irreparableListCfg.Database)
+	if err != nil {
+		return errs.New("error connecting to master database on satellite: %+v", err)
+	}
+	defer func() {
+		err = errs.Combine(err, database.Close())
+	}()
+
+	count, err := database.Irreparable().Count(ctx)
+	if err != nil {
+		return err
+	}
+	segments, err := database.Irreparable().GetLimited(ctx, irreparableListCfg.Limit, irreparableListCfg.Offset)
+	if err != nil {
+		return err
+	}
+
+	const padding = 3
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, padding, ' ', tabwriter.AlignRight|tabwriter.Debug)
+	fmt.Fprintln(w, "Path\tLost Pieces\tAttempts\tLast Attempt\tLast Error\t")
+	for _, segment := range segments {
+		lastAttempt := time.Unix(segment.GetLastRepairAttempt(), 0).UTC().Format(time.RFC3339)
+		fmt.Fprintf(w, "%s\t%v\t%d\t%s\t%s\t\n", segment.GetPath(), segment.GetLostPieceNums(),
+			segment.GetRepairAttemptCount(), lastAttempt, segment.GetLastError())
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nIrreparable segments: %d\n", count)
+	return nil
+}
+
+// cmdIrreparableRetry moves irreparable segments back to the repair queue,
+// e.g. after the nodes storing their pieces came back online. The checker
+// refreshes the lost pieces of the queued segments on its next iteration.
+func cmdIrreparableRetry(cmd *cobra.Command, args []string) (err error) {
+	ctx := process.Ctx(cmd)
+
+	database, err := satellitedb.New(zap.L().Named("db"), irreparableRetryCfg.Database)
+	if err != nil {
+		return errs.New("error connecting to master database on satellite: %+v", err)
+	}
+	defer func() {
+		err = errs.Combine(err, database.Close())
+	}()
+
+	for _, path := range args {
+		segment, err := database.Irreparable().Get(ctx, []byte(path))
+		if err != nil {
+			return errs.New("error getting irreparable segment %q: %+v", path, err)
+		}
+
+		remote := segment.GetSegmentDetail().GetRemote()
+		numHealthy := int32(len(remote.GetRemotePieces()) - len(segment.GetLostPieceNums()))
+		_, err = database.RepairQueue().Insert(ctx, &pb.InjuredSegment{
+			Path:       path,
+			LostPieces: segment.GetLostPieceNums(),
+			Health:     checker.SegmentHealth(numHealthy, remote.GetRedundancy()),
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := database.Irreparable().Delete(ctx, []byte(path)); err != nil {
+			return err
+		}
+		fmt.Printf("Segment %q queued for repair\n", path)
+	}
+	return nil
+}
+
+// cmdIrreparablePurge removes segments from the irreparable registry, leaving
+// their pointers untouched. The checker adds the segments whose pointers still
+// have too few healthy pieces again on its next iteration.
+func cmdIrreparablePurge(cmd *cobra.Command, args []string) (err error) {
+	ctx := process.Ctx(cmd)
+
+	if irreparablePurgeCfg.All == (len(args) > 0) {
+		return errs.New("either paths or --all must be given")
+	}
+
+	database, err := satellitedb.New(zap.L().Named("db"), irreparablePurgeCfg.Database)
+	if err != nil {
+		return errs.New("error connecting to master database on satellite: %+v", err)
+	}
+	defer func() {
+		err = errs.Combine(err, database.Close())
+	}()
+
+	if irreparablePurgeCfg.All {
+		count, err := database.Irreparable().DeleteAll(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Purged %d irreparable segments\n", count)
+		return nil
+	}
+
+	for _, path := range args {
+		if err := database.Irreparable().Delete(ctx, []byte(path)); err != nil {
+			return err
+		}
+		fmt.Printf("Segment %q purged\n", path)
+	}
+	return nil
+}
@@ -11,12 +11,17 @@ import (
 	"text/tabwriter"
 	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/zeebo/errs"
 	"go.uber.org/zap"
 
 	"storj.io/storj/internal/fpath"
+	"storj.io/storj/pkg/accounting/payments"
+	"storj.io/storj/pkg/audit"
 	"storj.io/storj/pkg/cfgstruct"
+	"storj.io/storj/pkg/overlay"
 	"storj.io/storj/pkg/process"
 	"storj.io/storj/satellite"
 	"storj.io/storj/satellite/satellitedb"
@@ -55,6 +60,55 @@ var (
 		Short: "Repair Queue Diagnostic Tool support",
 		RunE:  cmdQDiag,
 	}
+	detectZombiesCmd = &cobra.Command{
+		Use:   "detect-zombies",
+		Short: "Report orphaned segments and inconsistent objects of a pointerdb read replica or backup",
+		Args:  cobra.NoArgs,
+		RunE:  cmdDetectZombies,
+	}
+	deleteSegmentsCmd = &cobra.Command{
+		Use:   "delete-segments [path]...",
+		Short: "Delete segment pointers from the pointerdb, leaving their pieces to expire or be garbage collected",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  cmdDeleteSegments,
+	}
+	estimateRepairCmd = &cobra.Command{
+		Use:   "estimate-repair",
+		Short: "Report what repairing the segments of a pointerdb read replica or backup would transfer",
+		Long:  "Report the segments needing repair with the given repair overrides, the bytes their repair would download and upload and whether enough new nodes are found, without repairing anything",
+		Args:  cobra.NoArgs,
+		RunE:  cmdEstimateRepair,
+	}
+	auditReportCmd = &cobra.Command{
+		Use:   "audit-report",
+		Short: "Report the audits per day, the estimated share of the segments audited and the nodes nearing disqualification",
+		Args:  cobra.NoArgs,
+		RunE:  cmdAuditReport,
+	}
+	irreparableCmd = &cobra.Command{
+		Use:   "irreparable",
+		Short: "Manage the segments that couldn't be repaired",
+	}
+	irreparableListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the irreparable segments with their lost pieces and last repair error",
+		Args:  cobra.NoArgs,
+		RunE:  cmdIrreparableList,
+	}
+	irreparableRetryCmd = &cobra.Command{
+		Use:   "retry [path]...",
+		Short: "Move irreparable segments back to the repair queue",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  cmdIrreparableRetry,
+	}
+	irreparablePurgeCmd = &cobra.Command{
+		Use:   "purge [path]...",
+		Short: "Remove segments from the irreparable registry, leaving their pointers untouched",
+		Long: "Remove segments from the irreparable registry, leaving their pointers untouched. " +
+			"The checker adds a segment again while its pointer exists with fewer healthy pieces than required, " +
+			"so purge a segment only after deleting its pointer or once its nodes are back online.",
+		RunE: cmdIrreparablePurge,
+	}
 	reportsCmd = &cobra.Command{
 		Use:   "reports",
 		Short: "Generate a report",
@@ -66,6 +120,13 @@ var (
 		Args:  cobra.MinimumNArgs(2),
 		RunE:  cmdNodeUsage,
 	}
+	paymentsCmd = &cobra.Command{
+		Use:   "payments",
+		Short: "Generate a report of the amounts owed to nodes for a month",
+		Long:  "Generate a report of the amounts owed to nodes for a month, to feed a payout pipeline. Format the period using YYYY-MM",
+		Args:  cobra.NoArgs,
+		RunE:  cmdPayments,
+	}
 
 	runCfg   Satellite
 	setupCfg Satellite
@@ -81,6 +142,45 @@ var (
 		Database string `help:"satellite database connection string" default:"sqlite3://$CONFDIR/master.db"`
 		Output   string `help:"destination of report output" default:""`
 	}
+	detectZombiesCfg struct {
+		DatabaseURL    string        `help:"pointerdb connection string of a read replica or a backup" default:"bolt://$CONFDIR/pointerdb.db"`
+		Output         string        `help:"destination of report output" default:""`
+		DeletionScript string        `help:"destination of a script deleting the zombie segments, no script is written if empty" default:""`
+		MinAge         time.Duration `help:"minimum age of the segments of an object for it to be checked" default:"24h"`
+	}
+	estimateRepairCfg struct {
+		Database        string `help:"satellite database connection string" default:"sqlite3://$CONFDIR/master.db"`
+		DatabaseURL     string `help:"pointerdb connection string of a read replica or a backup" default:"bolt://$CONFDIR/pointerdb.db"`
+		Output          string `help:"destination of report output" default:""`
+		RepairOverrides string `help:"comma separated repair thresholds used instead of the thresholds of the pointers, as min/success/total-threshold, e.g. 29/80/95-52" default:""`
+		Overlay         overlay.Config
+	}
+	auditReportCfg struct {
+		Database string `help:"satellite database connection string" default:"sqlite3://$CONFDIR/master.db"`
+		Days     int    `help:"number of days to report, including today" default:"30"`
+		Audit    audit.Config
+	}
+	irreparableListCfg struct {
+		Database string `help:"satellite database connection string" default:"sqlite3://$CONFDIR/master.db"`
+		Limit    int    `help:"maximum number of segments to list" default:"1000"`
+		Offset   int64  `help:"number of segments to skip" default:"0"`
+	}
+	irreparableRetryCfg struct {
+		Database string `help:"satellite database connection string" default:"sqlite3://$CONFDIR/master.db"`
+	}
+	irreparablePurgeCfg struct {
+		Database string `help:"satellite database connection string" default:"sqlite3://$CONFDIR/master.db"`
+		All      bool   `help:"purge all irreparable segments" default:"false"`
+	}
+	deleteSegmentsCfg struct {
+		DatabaseURL string `help:"pointerdb connection string" default:"bolt://$CONFDIR/pointerdb.db"`
+	}
+	paymentsCfg struct {
+		Database string `help:"satellite database connection string" default:"sqlite3://$CONFDIR/master.db"`
+		Output   string `help:"destination of report output" default:""`
+		Period   string `help:"month to generate the report for (YYYY-MM)" default:""`
+		Payments payments.Config
+	}
 	confDir     string
 	identityDir string
 	isDev       bool
@@ -96,13 +196,30 @@ func init() {
 	rootCmd.AddCommand(setupCmd)
 	rootCmd.AddCommand(diagCmd)
 	rootCmd.AddCommand(qdiagCmd)
+	rootCmd.AddCommand(detectZombiesCmd)
+	rootCmd.AddCommand(deleteSegmentsCmd)
+	rootCmd.AddCommand(estimateRepairCmd)
+	rootCmd.AddCommand(auditReportCmd)
+	rootCmd.AddCommand(irreparableCmd)
+	irreparableCmd.AddCommand(irreparableListCmd)
+	irreparableCmd.AddCommand(irreparableRetryCmd)
+	irreparableCmd.AddCommand(irreparablePurgeCmd)
 	rootCmd.AddCommand(reportsCmd)
 	reportsCmd.AddCommand(nodeUsageCmd)
+	reportsCmd.AddCommand(paymentsCmd)
 	cfgstruct.Bind(runCmd.Flags(), &runCfg, isDev, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
 	cfgstruct.BindSetup(setupCmd.Flags(), &setupCfg, isDev, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
 	cfgstruct.Bind(diagCmd.Flags(), &diagCfg, isDev, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
 	cfgstruct.Bind(qdiagCmd.Flags(), &qdiagCfg, isDev, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
+	cfgstruct.Bind(detectZombiesCmd.Flags(), &detectZombiesCfg, isDev, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
+	cfgstruct.Bind(deleteSegmentsCmd.Flags(), &deleteSegmentsCfg, isDev, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
+	cfgstruct.Bind(estimateRepairCmd.Flags(), &estimateRepairCfg, isDev, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
+	cfgstruct.Bind(auditReportCmd.Flags(), &auditReportCfg, isDev, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
+	cfgstruct.Bind(irreparableListCmd.Flags(), &irreparableListCfg, isDev, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
+	cfgstruct.Bind(irreparableRetryCmd.Flags(), &irreparableRetryCfg, isDev, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
+	cfgstruct.Bind(irreparablePurgeCmd.Flags(), &irreparablePurgeCfg, isDev, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
 	cfgstruct.Bind(nodeUsageCmd.Flags(), &nodeUsageCfg, isDev, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
+	cfgstruct.Bind(paymentsCmd.Flags(), &paymentsCfg, isDev, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
 }
 
 func cmdRun(cmd *cobra.Command, args []string) (err error) {
@@ -138,6 +255,14 @@ func cmdRun(cmd *cobra.Command, args []string) (err error) {
 		return err
 	}
 
+	process.ReloadOnSignal(ctx, cmd, func(flags *pflag.FlagSet) func(context.Context) error {
+		var reloadCfg Satellite
+		cfgstruct.Bind(flags, &reloadCfg, isDev, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
+		return func(ctx context.Context) error {
+			return peer.Reload(ctx, &reloadCfg.Config)
+		}
+	})
+
 	runError := peer.Run(ctx)
 	closeError := peer.Close()
 	return errs.Combine(runError, closeError)
@@ -209,7 +334,7 @@ func cmdQDiag(cmd *cobra.Command, args []string) (err error) {
 		}
 	}()
 
-	list, err := database.RepairQueue().Peekqueue(context.Background(), qdiagCfg.QListLimit)
+	list, err := database.RepairQueue().SelectN(context.Background(), qdiagCfg.QListLimit)
 	if err != nil {
 		return err
 	}
@@ -217,11 +342,15 @@ func cmdQDiag(cmd *cobra.Command, args []string) (err error) {
 	// initialize the table header (fields)
 	const padding = 3
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, padding, ' ', tabwriter.AlignRight|tabwriter.Debug)
-	fmt.Fprintln(w, "Path\tLost Pieces\t")
+	fmt.Fprintln(w, "Path\tLost Pieces\tHealth\tAttempts\tQueued Since\t")
 
 	// populate the row fields
 	for _, v := range list {
-		fmt.Fprint(w, v.GetPath(), "\t", v.GetLostPieces(), "\t")
+		queued := ""
+		if insertedAt, err := ptypes.Timestamp(v.GetInsertedAt()); err == nil {
+			queued = insertedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%v\t%.4f\t%d\t%s\t\n", v.GetPath(), v.GetLostPieces(), v.GetHealth(), v.GetAttempts(), queued)
 	}
 
 	// display the data
@@ -264,6 +393,32 @@ func cmdNodeUsage(cmd *cobra.Command, args []string) (err error) {
 	return generateCSV(ctx, start, end, file)
 }
 
+func cmdPayments(cmd *cobra.Command, args []string) (err error) {
+	ctx := process.Ctx(cmd)
+
+	start, end, err := payments.Period(paymentsCfg.Period)
+	if err != nil {
+		return err
+	}
+
+	// send output to stdout
+	if paymentsCfg.Output == "" {
+		return generatePaymentsCSV(ctx, start, end, os.Stdout)
+	}
+
+	// send output to file
+	file, err := os.Create(paymentsCfg.Output)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		err = errs.Combine(err, file.Close())
+	}()
+
+	return generatePaymentsCSV(ctx, start, end, file)
+}
+
 func main() {
 	process.Exec(rootCmd)
 }
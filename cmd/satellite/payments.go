@@ -0,0 +1,90 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/accounting/payments"
+	"storj.io/storj/satellite/satellitedb"
+)
+
+// generatePaymentsCSV creates a report with the amounts owed to all nodes in a given period
+func generatePaymentsCSV(ctx context.Context, start time.Time, end time.Time, output io.Writer) (err error) {
+	db, err := satellitedb.New(zap.L().Named("db"), paymentsCfg.Database)
+	if err != nil {
+		return errs.New("error connecting to master database on satellite: %+v", err)
+	}
+	defer func() {
+		err = errs.Combine(err, db.Close())
+	}()
+
+	rows, err := db.Accounting().QueryPaymentInfo(ctx, start, end)
+	if err != nil {
+		return err
+	}
+
+	w := csv.NewWriter(output)
+	headers := []string{
+		"nodeID",
+		"walletAddress",
+		"nodeMonth",
+		"byte-hours:AtRest",
+		"bytes:BWGet",
+		"bytes:BWRepair-GET",
+		"bytes:BWAudit",
+		"surgePercent",
+		"heldPercent",
+		"dollars:Gross",
+		"dollars:Held",
+		"dollars:Owed",
+	}
+	if err := w.Write(headers); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		payment, err := payments.Compute(paymentsCfg.Payments, row, start)
+		if err != nil {
+			return err
+		}
+		if err := w.Write(paymentToStringSlice(payment)); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+	if output != os.Stdout {
+		fmt.Println("Generated node payments report")
+	}
+	return nil
+}
+
+func paymentToStringSlice(p payments.Payment) []string {
+	return []string{
+		p.NodeID.String(),
+		p.Wallet,
+		strconv.Itoa(p.Month),
+		strconv.FormatFloat(p.AtRestTotal, 'f', 5, 64),
+		strconv.FormatInt(p.GetTotal, 10),
+		strconv.FormatInt(p.GetRepairTotal, 10),
+		strconv.FormatInt(p.GetAuditTotal, 10),
+		strconv.FormatInt(p.SurgePercent, 10),
+		strconv.FormatInt(p.HeldPercent, 10),
+		payments.FormatDollars(p.Gross),
+		payments.FormatDollars(p.Held),
+		payments.FormatDollars(p.Owed),
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/pkg/pointerdb/zombies"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/storage"
+)
+
+func cmdDetectZombies(cmd *cobra.Command, args []string) (err error) {
+	ctx := process.Ctx(cmd)
+
+	db, err := pointerdb.NewStore(detectZombiesCfg.DatabaseURL)
+	if err != nil {
+		return errs.New("error connecting to pointerdb: %+v", err)
+	}
+	defer func() {
+		err = errs.Combine(err, db.Close())
+	}()
+
+	report, err := zombies.Detect(ctx, db, zombies.Options{
+		MinAge: detectZombiesCfg.MinAge,
+		Now:    time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if detectZombiesCfg.Output == "" {
+		err = printZombies(os.Stdout, report)
+	} else {
+		err = writeFile(detectZombiesCfg.Output, func(w io.Writer) error {
+			return printZombies(w, report)
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	if detectZombiesCfg.DeletionScript != "" {
+		err = writeFile(detectZombiesCfg.DeletionScript, report.WriteDeletionScript)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Deletion script written to %s\n", detectZombiesCfg.DeletionScript)
+	}
+	return nil
+}
+
+// printZombies prints the report as a table
+func printZombies(w io.Writer, report *zombies.Report) error {
+	fmt.Fprintf(w, "Segments: %d, objects: %d, skipped recent objects: %d, zombie segments: %d\n\n",
+		report.Segments, report.Objects, report.Skipped, len(report.Zombies))
+
+	// initialize the table header (fields)
+	const padding = 3
+	tw := tabwriter.NewWriter(w, 0, 0, padding, ' ', tabwriter.Debug)
+	fmt.Fprintln(tw, "Path\tKind\tReason\t")
+
+	// populate the row fields
+	for _, zombie := range report.Zombies {
+		fmt.Fprint(tw, strconv.Quote(zombie.Path), "\t", zombie.Kind, "\t", zombie.Reason, "\t\n")
+	}
+
+	// display the data
+	return tw.Flush()
+}
+
+func cmdDeleteSegments(cmd *cobra.Command, args []string) (err error) {
+	db, err := pointerdb.NewStore(deleteSegmentsCfg.DatabaseURL)
+	if err != nil {
+		return errs.New("error connecting to pointerdb: %+v", err)
+	}
+	defer func() {
+		err = errs.Combine(err, db.Close())
+	}()
+
+	for _, path := range args {
+		err := db.Delete(storage.Key(path))
+		if storage.ErrKeyNotFound.Has(err) {
+			fmt.Printf("Segment %q already deleted\n", path)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Deleted segment %q\n", path)
+	}
+	return nil
+}
+
+// writeFile creates the file at path and writes it with write
+func writeFile(path string, write func(io.Writer) error) (err error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errs.Combine(err, file.Close())
+	}()
+
+	return write(file)
+}
@@ -0,0 +1,75 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/storagenode/storagenodedb"
+	"storj.io/storj/storagenode/storagenodedb/benchsuite"
+)
+
+func cmdBench(cmd *cobra.Command, args []string) (err error) {
+	ctx := process.Ctx(cmd)
+
+	dir := benchCfg.Dir
+	if dir == "" {
+		dir, err = ioutil.TempDir("", "storagenode-bench")
+		if err != nil {
+			return err
+		}
+		defer func() { err = errs.Combine(err, os.RemoveAll(dir)) }()
+	}
+
+	fmt.Printf("Benchmarking with %d satellites and %d entries per satellite in %s\n",
+		benchCfg.Satellites, benchCfg.Orders, dir)
+
+	run := 0
+	for _, bench := range benchsuite.Benchmarks {
+		bench := bench
+		var benchErr error
+		result := testing.Benchmark(func(b *testing.B) {
+			// NB: each run gets a new database, see benchsuite.Benchmark
+			run++
+			runDir := filepath.Join(dir, fmt.Sprintf("run-%d", run))
+			defer func() { benchErr = errs.Combine(benchErr, os.RemoveAll(runDir)) }()
+
+			db, err := storagenodedb.New(zap.NewNop(), storagenodedb.Config{
+				Storage:  runDir,
+				Info:     filepath.Join(runDir, "piecestore.db"),
+				Info2:    filepath.Join(runDir, "info.db"),
+				Pieces:   runDir,
+				Kademlia: filepath.Join(runDir, "kademlia"),
+			})
+			if err != nil {
+				benchErr = err
+				b.SkipNow()
+			}
+			defer func() { benchErr = errs.Combine(benchErr, db.Close()) }()
+
+			if err := db.CreateTables(); err != nil {
+				benchErr = err
+				b.SkipNow()
+			}
+
+			b.ReportAllocs()
+			bench.Run(ctx, b, db, benchCfg.Config)
+		})
+		if benchErr != nil {
+			return benchErr
+		}
+
+		fmt.Printf("%-32s %s %s\n", bench.Name, result.String(), result.MemString())
+	}
+	return nil
+}
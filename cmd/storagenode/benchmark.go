@@ -0,0 +1,86 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/storj/internal/memory"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/storagenode/benchmark"
+	"storj.io/storj/storagenode/pieces"
+	"storj.io/storj/storagenode/storagenodedb"
+)
+
+func cmdBenchmark(cmd *cobra.Command, args []string) (err error) {
+	ctx := process.Ctx(cmd)
+
+	// NB: the benchmark runs on the disk of the pieces, but never with the stored pieces and databases
+	parent := benchmarkCfg.Dir
+	if parent == "" {
+		parent = benchmarkCfg.Storage.Paths()[0]
+	}
+	if err := os.MkdirAll(parent, 0700); err != nil {
+		return err
+	}
+	dir, err := ioutil.TempDir(parent, "benchmark-")
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, os.RemoveAll(dir)) }()
+
+	db, err := storagenodedb.New(zap.L().Named("db"), storagenodedb.Config{
+		Storage:     dir,
+		Info:        filepath.Join(dir, "piecestore.db"),
+		Info2:       filepath.Join(dir, "info.db"),
+		Pieces:      dir,
+		Kademlia:    filepath.Join(dir, "kademlia"),
+		Preallocate: benchmarkCfg.Storage2.Preallocate,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, db.Close()) }()
+
+	if err := db.CreateTables(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Benchmarking %d pieces of %s and %d orders with %d concurrent operations in %s\n",
+		benchmarkCfg.Benchmark.Pieces, benchmarkCfg.Benchmark.PieceSize, benchmarkCfg.Benchmark.Orders, benchmarkCfg.Benchmark.Concurrency, dir)
+
+	store := pieces.NewStore(zap.L().Named("pieces"), db.Pieces())
+	results, err := benchmark.Run(ctx, store, db.Orders(), benchmarkCfg.Benchmark)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(w, "\tOperations\tOps/s\tThroughput\tp50\tp90\tp99\tMax\t\n")
+	for _, result := range results {
+		throughput := "-"
+		if result.Bytes > 0 {
+			throughput = memory.Size(result.BytesPerSecond()).String() + "/s"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%.1f\t%s\t%s\t%s\t%s\t%s\t\n",
+			result.Name, result.Operations, result.OperationsPerSecond(), throughput,
+			latency(result.Percentile(50)), latency(result.Percentile(90)),
+			latency(result.Percentile(99)), latency(result.Percentile(100)))
+	}
+	return w.Flush()
+}
+
+// latency formats a latency rounded to a readable precision.
+func latency(d time.Duration) string {
+	return d.Round(10 * time.Microsecond).String()
+}
@@ -5,23 +5,26 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
-	"runtime"
-	"strings"
+	"sync"
 	"text/tabwriter"
 	"time"
 
 	"github.com/fatih/color"
 	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/spf13/cobra"
+	"github.com/zeebo/errs"
 	"go.uber.org/zap"
 
+	"storj.io/storj/internal/cui"
 	"storj.io/storj/internal/memory"
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/process"
-	"storj.io/storj/pkg/transport"
+	"storj.io/storj/pkg/storj"
 )
 
 const contactWindow = time.Minute * 10
@@ -34,8 +37,8 @@ func (dash *dashboardClient) dashboard(ctx context.Context) (*pb.DashboardRespon
 	return dash.client.Dashboard(ctx, &pb.DashboardRequest{})
 }
 
-func newDashboardClient(ctx context.Context, address string) (*dashboardClient, error) {
-	conn, err := transport.DialAddressInsecure(ctx, address)
+func newDashboardClient(ctx context.Context, address, token string) (*dashboardClient, error) {
+	conn, err := dialPrivate(ctx, address, token)
 	if err != nil {
 		return &dashboardClient{}, err
 	}
@@ -46,7 +49,21 @@ func newDashboardClient(ctx context.Context, address string) (*dashboardClient,
 }
 
 func cmdDashboard(cmd *cobra.Command, args []string) (err error) {
-	ctx := process.Ctx(cmd)
+	ctx, cancel := context.WithCancel(process.Ctx(cmd))
+	defer cancel()
+
+	client, err := newDashboardClient(ctx, dashboardCfg.Address, dashboardCfg.Token)
+	if err != nil {
+		return err
+	}
+
+	if dashboardCfg.JSON {
+		data, err := client.dashboard(ctx)
+		if err != nil {
+			return err
+		}
+		return printDashboardJSON(os.Stdout, data)
+	}
 
 	ident, err := runCfg.Identity.Load()
 	if err != nil {
@@ -55,47 +72,146 @@ func cmdDashboard(cmd *cobra.Command, args []string) (err error) {
 		zap.S().Info("Node ID: ", ident.ID)
 	}
 
-	client, err := newDashboardClient(ctx, dashboardCfg.Address)
+	// the screen doesn't interpret escape codes
+	color.NoColor = true
+
+	screen, err := cui.NewScreen()
 	if err != nil {
 		return err
 	}
 
+	tabs := &dashboardTabs{}
+	redraw := make(chan struct{}, 1)
+	screen.OnKey(func(key cui.Key) {
+		if tabs.press(key) {
+			select {
+			case redraw <- struct{}{}:
+			default:
+			}
+		}
+	})
+
+	var screenErr error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer cancel()
+		screenErr = screen.Run()
+	}()
+	defer func() {
+		screen.Interrupt()
+		<-done
+		err = errs.Combine(err, screenErr, screen.Close())
+	}()
+
+	ticker := time.NewTicker(dashboardCfg.Interval)
+	defer ticker.Stop()
+
+	data, err := client.dashboard(ctx)
 	for {
-		data, err := client.dashboard(ctx)
 		if err != nil {
 			return err
 		}
-
-		if err := printDashboard(data); err != nil {
+		tabs.update(data)
+		if err := renderDashboard(screen, data, tabs.selected()); err != nil {
 			return err
 		}
 
-		// Refresh the dashboard every 3 seconds
-		time.Sleep(3 * time.Second)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-redraw:
+		case <-ticker.C:
+			data, err = client.dashboard(ctx)
+		}
 	}
 }
 
-func printDashboard(data *pb.DashboardResponse) error {
-	clearScreen()
-	color.NoColor = !useColor
+// dashboardTabs is the tab shown by the dashboard, the overview is tab 0
+// and the satellites follow in the order of the dashboard response.
+type dashboardTabs struct {
+	mu    sync.Mutex
+	tab   int
+	count int
+}
 
-	heading := color.New(color.FgGreen, color.Bold)
-	_, _ = heading.Printf("\nStorage Node Dashboard\n")
-	_, _ = heading.Printf("\n======================\n\n")
+// update sets the number of satellite tabs from the dashboard.
+func (tabs *dashboardTabs) update(data *pb.DashboardResponse) {
+	tabs.mu.Lock()
+	defer tabs.mu.Unlock()
+	tabs.count = len(data.GetSatellites())
+	if tabs.tab > tabs.count {
+		tabs.tab = 0
+	}
+}
 
-	w := tabwriter.NewWriter(color.Output, 0, 0, 1, ' ', 0)
-	fmt.Fprintf(w, "ID\t%s\n", color.YellowString(data.NodeId.String()))
+// press switches the tab by key and returns whether the tab changed.
+func (tabs *dashboardTabs) press(key cui.Key) bool {
+	tabs.mu.Lock()
+	defer tabs.mu.Unlock()
 
-	lastContacted, err := ptypes.Timestamp(data.LastPinged)
-	if err != nil {
-		lastContacted = time.Time{}
+	previous := tabs.tab
+	switch {
+	case key == cui.KeyTab || key == cui.KeyRight:
+		tabs.tab = (tabs.tab + 1) % (tabs.count + 1)
+	case key == cui.KeyLeft:
+		tabs.tab = (tabs.tab + tabs.count) % (tabs.count + 1)
+	case key >= '0' && key <= '9' && int(key-'0') <= tabs.count:
+		tabs.tab = int(key - '0')
 	}
-	lastQueried, err := ptypes.Timestamp(data.LastQueried)
-	if err == nil {
-		if lastQueried.After(lastContacted) {
-			lastContacted = lastQueried
+	return tabs.tab != previous
+}
+
+func (tabs *dashboardTabs) selected() int {
+	tabs.mu.Lock()
+	defer tabs.mu.Unlock()
+	return tabs.tab
+}
+
+// renderDashboard draws the tab of the dashboard on the screen.
+func renderDashboard(screen *cui.Screen, data *pb.DashboardResponse, tab int) error {
+	screen.Lock()
+	defer screen.Unlock()
+
+	if err := printDashboard(screen, data, tab); err != nil {
+		return err
+	}
+	return screen.Flush()
+}
+
+func printDashboard(out io.Writer, data *pb.DashboardResponse, tab int) error {
+	heading := color.New(color.FgGreen, color.Bold)
+	_, _ = heading.Fprintf(out, "Storage Node Dashboard\n")
+	_, _ = heading.Fprintf(out, "======================\n\n")
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	names := []string{"Overview"}
+	for _, satellite := range data.GetSatellites() {
+		names = append(names, shortID(satellite.SatelliteId))
+	}
+	for i, name := range names {
+		if i == tab {
+			fmt.Fprintf(w, "[%d %s]\t", i, name)
+		} else {
+			fmt.Fprintf(w, " %d %s \t", i, name)
 		}
 	}
+	fmt.Fprintf(w, "\n(tab, arrows or the number switch tabs, esc quits)\n\n")
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if tab == 0 || tab > len(data.GetSatellites()) {
+		return printOverview(out, data)
+	}
+	return printSatellite(out, data, data.GetSatellites()[tab-1])
+}
+
+func printOverview(out io.Writer, data *pb.DashboardResponse) error {
+	w := tabwriter.NewWriter(out, 0, 0, 1, ' ', 0)
+	fmt.Fprintf(w, "ID\t%s\n", color.YellowString(data.NodeId.String()))
+
+	lastContacted := lastContact(data)
 	switch {
 	case lastContacted.IsZero():
 		fmt.Fprintf(w, "Last Contact\t%s\n", color.RedString("NEVER"))
@@ -127,7 +243,7 @@ func printDashboard(data *pb.DashboardResponse) error {
 		usedEgress := color.WhiteString(memory.Size(stats.GetUsedEgress()).Base10String())
 		usedIngress := color.WhiteString(memory.Size(stats.GetUsedIngress()).Base10String())
 
-		w = tabwriter.NewWriter(color.Output, 0, 0, 5, ' ', tabwriter.AlignRight)
+		w = tabwriter.NewWriter(out, 0, 0, 5, ' ', tabwriter.AlignRight)
 		fmt.Fprintf(w, "\n\t%s\t%s\t%s\t%s\t\n", color.GreenString("Available"), color.GreenString("Used"), color.GreenString("Egress"), color.GreenString("Ingress"))
 		fmt.Fprintf(w, "Bandwidth\t%s\t%s\t%s\t%s\t\n", availableBandwidth, usedBandwidth, usedEgress, usedIngress)
 		fmt.Fprintf(w, "Disk\t%s\t%s\t\n", availableSpace, usedSpace)
@@ -136,38 +252,269 @@ func printDashboard(data *pb.DashboardResponse) error {
 		}
 
 	} else {
-		color.Yellow("Loading...\n")
+		fmt.Fprintln(out, color.YellowString("Loading..."))
 	}
 
-	w = tabwriter.NewWriter(color.Output, 0, 0, 1, ' ', 0)
+	w = tabwriter.NewWriter(out, 0, 0, 1, ' ', 0)
 	// TODO: Get addresses from server data
 	fmt.Fprintf(w, "\nBootstrap\t%s\n", color.WhiteString(data.GetBootstrapAddress()))
 	fmt.Fprintf(w, "Internal\t%s\n", color.WhiteString(dashboardCfg.Address))
 	fmt.Fprintf(w, "External\t%s\n", color.WhiteString(data.GetExternalAddress()))
 	fmt.Fprintf(w, "\nNeighborhood Size %+v\n", whiteInt(data.GetNodeConnections()))
+	if corrupted := data.GetCorruptedPieces(); corrupted > 0 {
+		fmt.Fprintf(w, "Corrupted Pieces %s\n", color.RedString(fmt.Sprintf("%d", corrupted)))
+	}
+	if health := data.GetDiskHealth(); health.GetFailing() {
+		fmt.Fprintf(w, "Disk Health %s\n", color.RedString("failing, refusing uploads: "+health.GetReason()))
+	} else if health.GetDegraded() {
+		fmt.Fprintf(w, "Disk Health %s\n", color.YellowString("degraded: "+health.GetReason()))
+	}
 	if err = w.Flush(); err != nil {
 		return err
 	}
 
+	bandwidthToday := data.GetBandwidthToday()
+	if len(bandwidthToday) > 0 {
+		w = tabwriter.NewWriter(out, 0, 0, 5, ' ', 0)
+		fmt.Fprintf(w, "\n%s\t%s\t%s\t\n", color.GreenString("Bandwidth Today (UTC)"), color.GreenString("Ingress"), color.GreenString("Egress"))
+		for _, summary := range bandwidthToday {
+			fmt.Fprintf(w, "%s\t%s\t%s\t\n", summary.SatelliteId.String(),
+				color.WhiteString(memory.Size(summary.GetIngress()).Base10String()),
+				color.WhiteString(memory.Size(summary.GetEgress()).Base10String()))
+		}
+		if err = w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	unsentOrders := data.GetUnsentOrders()
+	if len(unsentOrders) > 0 {
+		w = tabwriter.NewWriter(out, 0, 0, 5, ' ', 0)
+		fmt.Fprintf(w, "\n%s\t%s\t%s\t\n", color.GreenString("Unsettled Bandwidth"), color.GreenString("Orders"), color.GreenString("Amount"))
+		for _, unsent := range unsentOrders {
+			fmt.Fprintf(w, "%s\t%s\t%s\t\n", unsent.SatelliteId.String(), whiteInt(unsent.GetOrderCount()),
+				color.WhiteString(memory.Size(unsent.GetAmount()).Base10String()))
+		}
+		if err = w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	backoffs := data.GetSettlementBackoffs()
+	if len(backoffs) > 0 {
+		w = tabwriter.NewWriter(out, 0, 0, 5, ' ', 0)
+		fmt.Fprintf(w, "\n%s\t%s\t%s\t\n", color.GreenString("Settlement Retries"), color.GreenString("Failures"), color.GreenString("Next Retry"))
+		for _, backoff := range backoffs {
+			nextRetry, err := ptypes.Timestamp(backoff.GetNextRetry())
+			if err != nil {
+				fmt.Fprintf(w, "%s\t%s\t%s\t\n", backoff.SatelliteId.String(), whiteInt(int64(backoff.GetFailures())), color.RedString("UNKNOWN"))
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t\n", backoff.SatelliteId.String(), whiteInt(int64(backoff.GetFailures())),
+				color.YellowString(fmt.Sprintf("in %s", time.Until(nextRetry).Truncate(time.Second))))
+		}
+		if err = w.Flush(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-func whiteInt(value int64) string {
-	return color.WhiteString(fmt.Sprintf("%+v", value))
+func printSatellite(out io.Writer, data *pb.DashboardResponse, satellite *pb.SatelliteSummary) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Satellite\t%s\n", color.YellowString(satellite.SatelliteId.String()))
+	if disqualified, err := ptypes.Timestamp(satellite.GetDisqualified()); err == nil {
+		fmt.Fprintf(w, "Disqualified\t%s\n", color.RedString(disqualified.Format(time.RFC3339)))
+	}
+
+	fmt.Fprintf(w, "\nDisk Used\t%s\n", color.WhiteString(memory.Size(satellite.GetSpaceUsed()).Base10String()))
+
+	fmt.Fprintf(w, "\n%s\t%s\t%s\n", color.GreenString("Bandwidth"), color.GreenString("Ingress"), color.GreenString("Egress"))
+	fmt.Fprintf(w, "This Month\t%s\t%s\n",
+		color.WhiteString(memory.Size(satellite.GetIngress()).Base10String()),
+		color.WhiteString(memory.Size(satellite.GetEgress()).Base10String()))
+	for _, summary := range data.GetBandwidthToday() {
+		if summary.SatelliteId == satellite.SatelliteId {
+			fmt.Fprintf(w, "Today (UTC)\t%s\t%s\n",
+				color.WhiteString(memory.Size(summary.GetIngress()).Base10String()),
+				color.WhiteString(memory.Size(summary.GetEgress()).Base10String()))
+		}
+	}
+
+	fmt.Fprintf(w, "\nUnsent Orders\t%s\t%s\n", whiteInt(satellite.GetUnsentOrderCount()),
+		color.WhiteString(memory.Size(satellite.GetUnsentAmount()).Base10String()))
+	for _, backoff := range data.GetSettlementBackoffs() {
+		if backoff.SatelliteId != satellite.SatelliteId {
+			continue
+		}
+		nextRetry, err := ptypes.Timestamp(backoff.GetNextRetry())
+		if err != nil {
+			fmt.Fprintf(w, "Settlement Retries\t%s\t%s\n", whiteInt(int64(backoff.GetFailures())), color.RedString("UNKNOWN"))
+			continue
+		}
+		fmt.Fprintf(w, "Settlement Retries\t%s\t%s\n", whiteInt(int64(backoff.GetFailures())),
+			color.YellowString(fmt.Sprintf("in %s", time.Until(nextRetry).Truncate(time.Second))))
+	}
+
+	updated, err := ptypes.Timestamp(satellite.GetReputationUpdatedAt())
+	if err != nil {
+		fmt.Fprintf(w, "\nReputation\t%s\n", color.YellowString("not fetched yet"))
+	} else {
+		fmt.Fprintf(w, "\n%s\t%s\t%s\n", color.GreenString("Reputation"), color.GreenString("Count"), color.GreenString("Ratio"))
+		fmt.Fprintf(w, "Audits\t%s\t%s\n", whiteInt(satellite.GetAuditCount()), ratio(satellite.GetAuditSuccessRatio()))
+		fmt.Fprintf(w, "Uptime Checks\t%s\t%s\n", whiteInt(satellite.GetUptimeCount()), ratio(satellite.GetUptimeRatio()))
+		fmt.Fprintf(w, "Updated\t%s ago\n", time.Since(updated).Truncate(time.Second))
+	}
+
+	return w.Flush()
 }
 
-// clearScreen clears the screen so it can be redrawn
-func clearScreen() {
-	switch runtime.GOOS {
-	case "linux", "darwin":
-		cmd := exec.Command("clear")
-		cmd.Stdout = os.Stdout
-		_ = cmd.Run()
-	case "windows":
-		cmd := exec.Command("cmd", "/c", "cls")
-		cmd.Stdout = os.Stdout
-		_ = cmd.Run()
-	default:
-		fmt.Print(strings.Repeat("\n", 100))
+// dashboardJSON is the dashboard printed by --json.
+type dashboardJSON struct {
+	NodeID           storj.NodeID `json:"nodeID"`
+	ExternalAddress  string       `json:"externalAddress"`
+	BootstrapAddress string       `json:"bootstrapAddress"`
+	LastContact      *time.Time   `json:"lastContact"`
+	Uptime           string       `json:"uptime"`
+	NodeConnections  int64        `json:"nodeConnections"`
+	CorruptedPieces  int64        `json:"corruptedPieces"`
+
+	UsedSpace          int64 `json:"usedSpace"`
+	AvailableSpace     int64 `json:"availableSpace"`
+	UsedBandwidth      int64 `json:"usedBandwidth"`
+	AvailableBandwidth int64 `json:"availableBandwidth"`
+	UsedIngress        int64 `json:"usedIngress"`
+	UsedEgress         int64 `json:"usedEgress"`
+
+	DiskHealth struct {
+		Degraded bool   `json:"degraded"`
+		Failing  bool   `json:"failing"`
+		Reason   string `json:"reason"`
+	} `json:"diskHealth"`
+
+	Satellites []satelliteJSON `json:"satellites"`
+}
+
+type satelliteJSON struct {
+	ID        storj.NodeID `json:"id"`
+	SpaceUsed int64        `json:"spaceUsed"`
+	// Ingress and Egress are of the current month
+	Ingress      int64 `json:"ingress"`
+	Egress       int64 `json:"egress"`
+	IngressToday int64 `json:"ingressToday"`
+	EgressToday  int64 `json:"egressToday"`
+
+	UnsentOrderCount   int64      `json:"unsentOrderCount"`
+	UnsentAmount       int64      `json:"unsentAmount"`
+	SettlementFailures int32      `json:"settlementFailures"`
+	NextSettlement     *time.Time `json:"nextSettlement"`
+
+	AuditCount          int64      `json:"auditCount"`
+	AuditSuccessRatio   float64    `json:"auditSuccessRatio"`
+	UptimeCount         int64      `json:"uptimeCount"`
+	UptimeRatio         float64    `json:"uptimeRatio"`
+	Disqualified        *time.Time `json:"disqualified"`
+	ReputationUpdatedAt *time.Time `json:"reputationUpdatedAt"`
+}
+
+func printDashboardJSON(out io.Writer, data *pb.DashboardResponse) error {
+	dashboard := dashboardJSON{
+		NodeID:           data.NodeId,
+		ExternalAddress:  data.GetExternalAddress(),
+		BootstrapAddress: data.GetBootstrapAddress(),
+		NodeConnections:  data.GetNodeConnections(),
+		CorruptedPieces:  data.GetCorruptedPieces(),
+		Satellites:       []satelliteJSON{},
+	}
+	if lastContacted := lastContact(data); !lastContacted.IsZero() {
+		dashboard.LastContact = &lastContacted
 	}
+	if uptime, err := ptypes.Duration(data.GetUptime()); err == nil {
+		dashboard.Uptime = uptime.Truncate(time.Second).String()
+	}
+	if stats := data.GetStats(); stats != nil {
+		dashboard.UsedSpace = stats.GetUsedSpace()
+		dashboard.AvailableSpace = stats.GetAvailableSpace()
+		dashboard.UsedBandwidth = stats.GetUsedBandwidth()
+		dashboard.AvailableBandwidth = stats.GetAvailableBandwidth()
+		dashboard.UsedIngress = stats.GetUsedIngress()
+		dashboard.UsedEgress = stats.GetUsedEgress()
+	}
+	if health := data.GetDiskHealth(); health != nil {
+		dashboard.DiskHealth.Degraded = health.GetDegraded()
+		dashboard.DiskHealth.Failing = health.GetFailing()
+		dashboard.DiskHealth.Reason = health.GetReason()
+	}
+
+	for _, summary := range data.GetSatellites() {
+		satellite := satelliteJSON{
+			ID:                summary.SatelliteId,
+			SpaceUsed:         summary.GetSpaceUsed(),
+			Ingress:           summary.GetIngress(),
+			Egress:            summary.GetEgress(),
+			UnsentOrderCount:  summary.GetUnsentOrderCount(),
+			UnsentAmount:      summary.GetUnsentAmount(),
+			AuditCount:        summary.GetAuditCount(),
+			AuditSuccessRatio: summary.GetAuditSuccessRatio(),
+			UptimeCount:       summary.GetUptimeCount(),
+			UptimeRatio:       summary.GetUptimeRatio(),
+		}
+		for _, today := range data.GetBandwidthToday() {
+			if today.SatelliteId == summary.SatelliteId {
+				satellite.IngressToday += today.GetIngress()
+				satellite.EgressToday += today.GetEgress()
+			}
+		}
+		for _, backoff := range data.GetSettlementBackoffs() {
+			if backoff.SatelliteId == summary.SatelliteId {
+				satellite.SettlementFailures = backoff.GetFailures()
+				satellite.NextSettlement = optionalTime(backoff.GetNextRetry())
+			}
+		}
+		satellite.Disqualified = optionalTime(summary.GetDisqualified())
+		satellite.ReputationUpdatedAt = optionalTime(summary.GetReputationUpdatedAt())
+		dashboard.Satellites = append(dashboard.Satellites, satellite)
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(dashboard)
+}
+
+// lastContact returns when the node was last pinged or queried.
+func lastContact(data *pb.DashboardResponse) time.Time {
+	lastContacted, err := ptypes.Timestamp(data.LastPinged)
+	if err != nil {
+		lastContacted = time.Time{}
+	}
+	lastQueried, err := ptypes.Timestamp(data.LastQueried)
+	if err == nil {
+		if lastQueried.After(lastContacted) {
+			lastContacted = lastQueried
+		}
+	}
+	return lastContacted
+}
+
+// optionalTime returns nil when the timestamp isn't set.
+func optionalTime(timestamp *timestamp.Timestamp) *time.Time {
+	t, err := ptypes.Timestamp(timestamp)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// shortID returns the beginning of the id for the tab names.
+func shortID(id storj.NodeID) string {
+	return id.String()[:8]
+}
+
+func ratio(value float64) string {
+	return color.WhiteString(fmt.Sprintf("%.2f%%", value*100))
+}
+
+func whiteInt(value int64) string {
+	return color.WhiteString(fmt.Sprintf("%+v", value))
 }
@@ -0,0 +1,430 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/accounting"
+	"storj.io/storj/pkg/accounting/payments"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/bandwidth"
+	"storj.io/storj/storagenode/dbstats"
+	"storj.io/storj/storagenode/orders"
+	"storj.io/storj/storagenode/pieces"
+	"storj.io/storj/storagenode/reputation"
+	"storj.io/storj/storagenode/storagenodedb"
+)
+
+// satelliteDiag contains the local information about a satellite
+type satelliteDiag struct {
+	Stored         int64
+	Usage          bandwidth.Usage
+	UnsentOrders   int64
+	UnsentBytes    int64
+	UnsentValue    int64
+	ForecastEgress int64
+	Forecast       int64
+
+	Pieces pieces.Summary
+	// Orders are the unsent and archived orders by status
+	Orders map[orders.Status]*orderDiag
+}
+
+// orderDiag contains the orders of a satellite with a status
+type orderDiag struct {
+	Count int64
+	Usage bandwidth.Usage
+	Value int64
+}
+
+func cmdDiag(cmd *cobra.Command, args []string) (err error) {
+	ctx := process.Ctx(cmd)
+
+	diagDir, err := filepath.Abs(confDir)
+	if err != nil {
+		return err
+	}
+
+	// check if the directory exists
+	_, err = os.Stat(diagDir)
+	if err != nil {
+		fmt.Println("Storagenode directory doesn't exist", diagDir)
+		return err
+	}
+
+	db, err := storagenodedb.New(zap.L().Named("db"), databaseConfig(diagCfg))
+	if err != nil {
+		return errs.New("Error starting master database on storagenode: %v", err)
+	}
+	defer func() {
+		err = errs.Combine(err, db.Close())
+	}()
+
+	now := time.Now()
+	year, month, _ := now.Date()
+	start := time.Date(year, month, 1, 0, 0, 0, 0, now.Location())
+	end := start.AddDate(0, 1, 0)
+
+	diags, err := collectDiag(ctx, db, diagCfg.Payments, start, end, now)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Month of %s, as of %s\n\n", start.Format("2006-01"), now.Format(time.RFC3339))
+	if err := printDiag(os.Stdout, diags); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	if err := printOrders(os.Stdout, diags); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	if err := printPieces(os.Stdout, diags); err != nil {
+		return err
+	}
+
+	stats, err := db.Stats().Stats(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	if err := printDatabase(os.Stdout, stats); err != nil {
+		return err
+	}
+
+	summaries, err := db.Bandwidth().DailySummaries(ctx, start, now)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	if err := printDailyBandwidth(os.Stdout, summaries); err != nil {
+		return err
+	}
+
+	reputations, err := db.Reputation().All(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	if err := printReputation(os.Stdout, reputations); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	return printAgreements(os.Stdout, db)
+}
+
+// collectDiag collects the information about each satellite for the month
+// from start to end, forecasting the earnings from the usage until now
+func collectDiag(ctx context.Context, db storagenode.DB, config payments.Config, start, end, now time.Time) (map[storj.NodeID]*satelliteDiag, error) {
+	diags := make(map[storj.NodeID]*satelliteDiag)
+	diag := func(satelliteID storj.NodeID) *satelliteDiag {
+		if _, ok := diags[satelliteID]; !ok {
+			diags[satelliteID] = &satelliteDiag{Orders: map[orders.Status]*orderDiag{}}
+		}
+		return diags[satelliteID]
+	}
+
+	summaries, err := db.PieceInfo().SummarizeBySatellite(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for satelliteID, summary := range summaries {
+		diag(satelliteID).Pieces = summary
+		diag(satelliteID).Stored = summary.Size
+	}
+
+	usages, err := db.Bandwidth().SummaryBySatellite(ctx, start, now)
+	if err != nil {
+		return nil, err
+	}
+	for satelliteID, usage := range usages {
+		diag(satelliteID).Usage = *usage
+	}
+
+	orderSummaries, err := db.Orders().SummarizeOrders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, summary := range orderSummaries {
+		d := diag(summary.SatelliteID)
+		status, ok := d.Orders[summary.Status]
+		if !ok {
+			status = &orderDiag{}
+			d.Orders[summary.Status] = status
+		}
+		status.Count += summary.Count
+		status.Usage.Include(summary.Action, summary.Amount)
+	}
+	for _, d := range diags {
+		for _, status := range d.Orders {
+			status.Value = payments.Gross(config, usageRow(status.Usage, 0))
+		}
+		if unsent, ok := d.Orders[orders.StatusUnsent]; ok {
+			d.UnsentOrders = unsent.Count
+			d.UnsentBytes = unsent.Usage.Total()
+			d.UnsentValue = unsent.Value
+		}
+	}
+
+	for _, d := range diags {
+		forecast, gross := bandwidth.EstimatePayout(config, d.Usage, d.Stored, start, end, now)
+		d.ForecastEgress = forecast.Total()
+		d.Forecast = gross
+	}
+	return diags, nil
+}
+
+// usageRow converts a bandwidth usage and the byte-hours at rest into a row
+// for computing payments
+func usageRow(usage bandwidth.Usage, atRest float64) *accounting.CSVRow {
+	return &accounting.CSVRow{
+		AtRestTotal:    atRest,
+		GetRepairTotal: usage.GetRepair,
+		PutRepairTotal: usage.PutRepair,
+		GetAuditTotal:  usage.GetAudit,
+		PutTotal:       usage.Put,
+		GetTotal:       usage.Get,
+	}
+}
+
+// printDiag prints the information about each satellite as a table
+func printDiag(w io.Writer, diags map[storj.NodeID]*satelliteDiag) error {
+	satelliteIDs := sortedSatellites(diags)
+
+	// initialize the table header (fields)
+	const padding = 3
+	tw := tabwriter.NewWriter(w, 0, 0, padding, ' ', tabwriter.AlignRight|tabwriter.Debug)
+	fmt.Fprintln(tw, "SatelliteID\tStored\tIngress\tEgress\tAudit Egress\tRepair Egress\tUnsent Orders\tUnsent Bytes\tUnsent Value ($)\tForecast Egress\tForecast ($)\t")
+
+	// populate the row fields
+	var total satelliteDiag
+	for _, satelliteID := range satelliteIDs {
+		d := diags[satelliteID]
+		printDiagRow(tw, satelliteID.String(), d)
+
+		total.Stored += d.Stored
+		total.Usage.Add(&d.Usage)
+		total.UnsentOrders += d.UnsentOrders
+		total.UnsentBytes += d.UnsentBytes
+		total.UnsentValue += d.UnsentValue
+		total.ForecastEgress += d.ForecastEgress
+		total.Forecast += d.Forecast
+	}
+	printDiagRow(tw, "Total", &total)
+
+	// display the data
+	return tw.Flush()
+}
+
+func printDiagRow(w io.Writer, name string, d *satelliteDiag) {
+	fmt.Fprint(w, name, "\t", d.Stored, "\t", d.Usage.Ingress(), "\t", d.Usage.Egress(), "\t",
+		d.Usage.GetAudit, "\t", d.Usage.GetRepair, "\t", d.UnsentOrders, "\t", d.UnsentBytes, "\t",
+		payments.FormatDollars(d.UnsentValue), "\t", d.ForecastEgress, "\t", payments.FormatDollars(d.Forecast), "\t\n")
+}
+
+// diagStatuses are the order statuses printed by diag, in order
+var diagStatuses = []orders.Status{orders.StatusUnsent, orders.StatusAccepted, orders.StatusRejected, orders.StatusExpired}
+
+// printOrders prints the number, bytes and value of the unsent and archived orders of each satellite
+func printOrders(w io.Writer, diags map[storj.NodeID]*satelliteDiag) error {
+	const padding = 3
+	tw := tabwriter.NewWriter(w, 0, 0, padding, ' ', tabwriter.AlignRight|tabwriter.Debug)
+	fmt.Fprintln(tw, "SatelliteID\tStatus\tOrders\tBytes\tValue ($)\t")
+	for _, satelliteID := range sortedSatellites(diags) {
+		for _, status := range diagStatuses {
+			d, ok := diags[satelliteID].Orders[status]
+			if !ok {
+				continue
+			}
+			fmt.Fprint(tw, satelliteID, "\t", status, "\t", d.Count, "\t", d.Usage.Total(), "\t",
+				payments.FormatDollars(d.Value), "\t\n")
+		}
+	}
+	return tw.Flush()
+}
+
+// printPieces prints the number and size of the pieces of each satellite
+func printPieces(w io.Writer, diags map[storj.NodeID]*satelliteDiag) error {
+	const padding = 3
+	tw := tabwriter.NewWriter(w, 0, 0, padding, ' ', tabwriter.AlignRight|tabwriter.Debug)
+	fmt.Fprintln(tw, "SatelliteID\tPieces\tSize\tCorrupted Pieces\t")
+	var total pieces.Summary
+	for _, satelliteID := range sortedSatellites(diags) {
+		summary := diags[satelliteID].Pieces
+		fmt.Fprint(tw, satelliteID, "\t", summary.Count, "\t", summary.Size, "\t", summary.Corrupted, "\t\n")
+
+		total.Count += summary.Count
+		total.Size += summary.Size
+		total.Corrupted += summary.Corrupted
+	}
+	fmt.Fprint(tw, "Total\t", total.Count, "\t", total.Size, "\t", total.Corrupted, "\t\n")
+	return tw.Flush()
+}
+
+// printDatabase prints the sizes of the largest tables and of the database files
+func printDatabase(w io.Writer, stats *dbstats.Stats) error {
+	const padding = 3
+	tw := tabwriter.NewWriter(w, 0, 0, padding, ' ', tabwriter.AlignRight|tabwriter.Debug)
+	fmt.Fprintln(tw, "Table\tRows\t")
+	fmt.Fprint(tw, "Unsent Orders\t", stats.UnsentOrders, "\t\n")
+	fmt.Fprint(tw, "Archived Orders\t", stats.ArchivedOrders, "\t\n")
+	fmt.Fprint(tw, "Pieces\t", stats.Pieces, "\t\n")
+	fmt.Fprint(tw, "Used Serials\t", stats.UsedSerials, "\t\n")
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(stats.FileSizes))
+	for name := range stats.FileSizes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 0, padding, ' ', tabwriter.AlignRight|tabwriter.Debug)
+	fmt.Fprintln(tw, "Database\tSize\t")
+	for _, name := range names {
+		fmt.Fprint(tw, name, "\t", stats.FileSizes[name], "\t\n")
+	}
+	return tw.Flush()
+}
+
+// sortedSatellites returns the satellites of diags in order
+func sortedSatellites(diags map[storj.NodeID]*satelliteDiag) storj.NodeIDList {
+	satelliteIDs := storj.NodeIDList{}
+	for satelliteID := range diags {
+		satelliteIDs = append(satelliteIDs, satelliteID)
+	}
+	sort.Sort(satelliteIDs)
+	return satelliteIDs
+}
+
+// printDailyBandwidth prints the ingress and egress of each satellite per day
+func printDailyBandwidth(w io.Writer, summaries []bandwidth.Summary) error {
+	const padding = 3
+	tw := tabwriter.NewWriter(w, 0, 0, padding, ' ', tabwriter.AlignRight|tabwriter.Debug)
+	fmt.Fprintln(tw, "Day (UTC)\tSatelliteID\tIngress\tEgress\t")
+	for _, summary := range summaries {
+		fmt.Fprint(tw, summary.Day.Format("2006-01-02"), "\t", summary.SatelliteID, "\t",
+			summary.Ingress, "\t", summary.Egress, "\t\n")
+	}
+	return tw.Flush()
+}
+
+// printReputation prints the reputation last reported by each satellite
+func printReputation(w io.Writer, stats []reputation.Stats) error {
+	const padding = 3
+	tw := tabwriter.NewWriter(w, 0, 0, padding, ' ', tabwriter.AlignRight|tabwriter.Debug)
+	fmt.Fprintln(tw, "SatelliteID\tAudits\tAudit Successes\tAudit Ratio\tUptime Checks\tUptime Successes\tUptime Ratio\tVetted\tDisqualified\tUpdated (UTC)\t")
+	for _, s := range stats {
+		// the vetting progress is shown until the node passed vetting
+		vetted := fmt.Sprintf("%d/%d audits, %d/%d uptime checks",
+			s.AuditCount, s.VettingAuditCount, s.UptimeCount, s.VettingUptimeCount)
+		if s.VettedAt != nil {
+			vetted = s.VettedAt.UTC().Format(time.RFC3339)
+		}
+		disqualified := "-"
+		if s.Disqualified != nil {
+			disqualified = s.Disqualified.UTC().Format(time.RFC3339)
+		}
+		fmt.Fprint(tw, s.SatelliteID, "\t", s.AuditCount, "\t", s.AuditSuccessCount, "\t",
+			fmt.Sprintf("%.4f", s.AuditSuccessRatio), "\t", s.UptimeCount, "\t", s.UptimeSuccessCount, "\t",
+			fmt.Sprintf("%.4f", s.UptimeRatio), "\t", vetted, "\t", disqualified, "\t", s.UpdatedAt.UTC().Format(time.RFC3339), "\t\n")
+	}
+	return tw.Flush()
+}
+
+// printAgreements prints a summary of the bandwidth agreements of the old piecestore
+func printAgreements(w io.Writer, db storagenode.DB) error {
+	//get all bandwidth aggrements entries already ordered
+	bwAgreements, err := db.PSDB().GetBandwidthAllocations()
+	if err != nil {
+		fmt.Printf("storage node 'bandwidth_agreements' table read error: %v\n", err)
+		return err
+	}
+
+	// Agreement is a struct that contains a bandwidth agreement and the associated signature
+	type SatelliteSummary struct {
+		TotalBytes           int64
+		PutActionCount       int64
+		GetActionCount       int64
+		GetAuditActionCount  int64
+		GetRepairActionCount int64
+		PutRepairActionCount int64
+		TotalTransactions    int64
+		// additional attributes add here ...
+	}
+
+	// attributes per satelliteid
+	summaries := make(map[storj.NodeID]*SatelliteSummary)
+	satelliteIDs := storj.NodeIDList{}
+
+	for _, rbaVal := range bwAgreements {
+		for _, rbaDataVal := range rbaVal {
+			rba := rbaDataVal.Agreement
+			pba := rba.PayerAllocation
+
+			summary, ok := summaries[pba.SatelliteId]
+			if !ok {
+				summaries[pba.SatelliteId] = &SatelliteSummary{}
+				satelliteIDs = append(satelliteIDs, pba.SatelliteId)
+				summary = summaries[pba.SatelliteId]
+			}
+
+			// fill the summary info
+			summary.TotalBytes += rba.Total
+			summary.TotalTransactions++
+			switch pba.Action {
+			case pb.BandwidthAction_PUT:
+				summary.PutActionCount++
+			case pb.BandwidthAction_GET:
+				summary.GetActionCount++
+			case pb.BandwidthAction_GET_AUDIT:
+				summary.GetAuditActionCount++
+			case pb.BandwidthAction_GET_REPAIR:
+				summary.GetRepairActionCount++
+			case pb.BandwidthAction_PUT_REPAIR:
+				summary.PutRepairActionCount++
+			}
+		}
+	}
+
+	// initialize the table header (fields)
+	const padding = 3
+	tw := tabwriter.NewWriter(w, 0, 0, padding, ' ', tabwriter.AlignRight|tabwriter.Debug)
+	fmt.Fprintln(tw, "SatelliteID\tTotal\t# Of Transactions\tPUT Action\tGET Action\tGET (Audit) Action\tGET (Repair) Action\tPUT (Repair) Action\t")
+
+	// populate the row fields
+	sort.Sort(satelliteIDs)
+	for _, satelliteID := range satelliteIDs {
+		summary := summaries[satelliteID]
+		fmt.Fprint(tw, satelliteID, "\t", summary.TotalBytes, "\t", summary.TotalTransactions, "\t",
+			summary.PutActionCount, "\t", summary.GetActionCount, "\t", summary.GetAuditActionCount,
+			"\t", summary.GetRepairActionCount, "\t", summary.PutRepairActionCount, "\t\n")
+	}
+
+	// display the data
+	return tw.Flush()
+}
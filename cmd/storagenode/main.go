@@ -4,23 +4,25 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
-	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/zeebo/errs"
 	"go.uber.org/zap"
 
 	"storj.io/storj/internal/fpath"
+	"storj.io/storj/internal/memory"
 	"storj.io/storj/pkg/cfgstruct"
-	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/process"
-	"storj.io/storj/pkg/storj"
 	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/benchmark"
 	"storj.io/storj/storagenode/storagenodedb"
+	"storj.io/storj/storagenode/storagenodedb/benchsuite"
 )
 
 // StorageNodeFlags defines storage node configuration
@@ -54,23 +56,137 @@ var (
 		Annotations: map[string]string{"type": "setup"},
 	}
 	diagCmd = &cobra.Command{
-		Use:         "diag",
-		Short:       "Diagnostic Tool support",
+		Use:   "diag",
+		Short: "Diagnostic Tool support",
+		Long: "Read the local databases while the storagenode is stopped and print, for each satellite, the stored bytes, " +
+			"the bandwidth used this month, the value of the orders not yet sent and an earnings forecast for this month. " +
+			"It also prints the reputation last reported by each satellite, with the vetting progress and the disqualification, " +
+			"the pieces, the database sizes and the daily bandwidth.",
 		RunE:        cmdDiag,
 		Annotations: map[string]string{"type": "helper"},
 	}
+	benchCmd = &cobra.Command{
+		Use:         "bench",
+		Short:       "Benchmark the orders, bandwidth and pieces operations on this machine",
+		Args:        cobra.NoArgs,
+		RunE:        cmdBench,
+		Annotations: map[string]string{"type": "helper"},
+	}
+	benchmarkCmd = &cobra.Command{
+		Use:   "benchmark",
+		Short: "Benchmark the disk of the pieces and the databases before joining the network",
+		Long: "Write, read and delete pieces, then enqueue and archive orders, in a temporary directory of the first directory of " +
+			"storage.path, and report the throughput and the latency percentiles of every operation. The stored pieces and databases are left untouched.",
+		Args:        cobra.NoArgs,
+		RunE:        cmdBenchmark,
+		Annotations: map[string]string{"type": "helper"},
+	}
+	migrateInfoCmd = &cobra.Command{
+		Use:   "migrate-info",
+		Short: "Copy info.db into the database at storage2.database-url while the storagenode is stopped",
+		Long: "Copy the orders, pieces information, bandwidth usage and used serials from info.db in the storage path " +
+			"into the empty database at storage2.database-url. The storagenode uses that database once restarted, info.db is left untouched.",
+		Args:        cobra.NoArgs,
+		RunE:        cmdMigrateInfo,
+		Annotations: map[string]string{"type": "helper"},
+	}
+	migrateStorageCmd = &cobra.Command{
+		Use:   "migrate-storage",
+		Short: "Copy the pieces and databases into a new storage directory while the storagenode is stopped",
+		Long: "Copy the pieces, including the trashed and the quarantined ones, and the databases of the storage directory --from, " +
+			"the first directory of storage.path by default, into the directory --to, verifying every copy, then replace --from with --to " +
+			"in storage.path of the config file. An interrupted copy skips the files already copied, --from is left untouched.",
+		Args:        cobra.NoArgs,
+		RunE:        cmdMigrateStorage,
+		Annotations: map[string]string{"type": "helper"},
+	}
+	rebuildPieceInfoCmd = &cobra.Command{
+		Use:   "rebuild-pieceinfo",
+		Short: "Restore the missing pieces information from the stored pieces while the storagenode is stopped",
+		Long: "Scan the stored pieces and restore the pieces information missing from the database, after it was lost, " +
+			"from the header stored with every piece. The information of the pieces stored before the header existed can't be restored.",
+		Args:        cobra.NoArgs,
+		RunE:        cmdRebuildPieceInfo,
+		Annotations: map[string]string{"type": "helper"},
+	}
+	ordersCmd = &cobra.Command{
+		Use:         "orders",
+		Short:       "Inspect the orders of the storagenode",
+		Annotations: map[string]string{"type": "helper"},
+	}
+	ordersExportCmd = &cobra.Command{
+		Use:   "export",
+		Short: "Export the unsent and archived orders to stdout while the storagenode is stopped",
+		Long: "Export the unsent orders, whose order limit expires within --from and --to, followed by the orders archived " +
+			"within --from and --to, as json or csv to stdout. The orders are streamed from the database.",
+		Args:        cobra.NoArgs,
+		RunE:        cmdOrdersExport,
+		Annotations: map[string]string{"type": "helper"},
+	}
+	payoutsCmd = &cobra.Command{
+		Use:   "payouts",
+		Short: "Print the payouts and held amounts reported by the satellites while the storagenode is stopped",
+		Long: "Print, for each satellite and month, the gross amount, the amount held back and the amount paid, " +
+			"as last fetched by the storagenode from the satellite, followed by the totals of the satellite.",
+		Args:        cobra.NoArgs,
+		RunE:        cmdPayouts,
+		Annotations: map[string]string{"type": "helper"},
+	}
+	maintenanceCmd = &cobra.Command{
+		Use:   "maintenance [on|off]",
+		Short: "Print, enter or leave the maintenance mode of the running storagenode",
+		Long: "In maintenance mode the storagenode rejects uploads while still serving downloads and audits, " +
+			"until it's left or a configuration reload applies storage2.read-only.",
+		Args:        cobra.MaximumNArgs(1),
+		RunE:        cmdMaintenance,
+		Annotations: map[string]string{"type": "helper"},
+	}
 	dashboardCmd = &cobra.Command{
-		Use:         "dashboard",
-		Short:       "Display a dashbaord",
+		Use:   "dashboard",
+		Short: "Display a live dashboard of the running storagenode",
+		Long: "Display a dashboard which refreshes while the storagenode runs, with a tab for the whole node " +
+			"and a tab for each satellite. With --json the dashboard is printed once as json for scripts.",
 		RunE:        cmdDashboard,
 		Annotations: map[string]string{"type": "helper"},
 	}
 
-	runCfg       StorageNodeFlags
-	setupCfg     StorageNodeFlags
-	diagCfg      storagenode.Config
+	runCfg   StorageNodeFlags
+	setupCfg StorageNodeFlags
+	diagCfg  storagenode.Config
+	benchCfg struct {
+		Dir string `default:"" help:"directory for the benchmark databases, a temporary directory if empty"`
+		benchsuite.Config
+	}
+	benchmarkCfg struct {
+		storagenode.Config
+		Benchmark benchmark.Config
+		Dir       string `default:"" help:"directory the temporary directory of the benchmark is created in, the first directory of storage.path when empty"`
+	}
+	migrateInfoCfg    storagenode.Config
+	migrateStorageCfg struct {
+		storagenode.Config
+		From    string      `default:"" help:"storage directory the pieces and databases are copied from, the first directory of storage.path when empty"`
+		To      string      `default:"" help:"new storage directory the pieces and databases are copied into"`
+		MaxRate memory.Size `default:"0" help:"maximum number of bytes copied per second, unlimited when 0"`
+	}
+	rebuildPieceInfoCfg storagenode.Config
+	payoutsCfg          storagenode.Config
+	ordersExportCfg     struct {
+		storagenode.Config
+		Satellite string `default:"" help:"id of the satellite whose orders are exported, all satellites when empty"`
+		From      string `default:"" help:"export the orders from this RFC3339 time, from the first order when empty"`
+		To        string `default:"" help:"export the orders before this RFC3339 time, up to the last order when empty"`
+		Format    string `default:"json" help:"format of the exported orders, json or csv"`
+	}
 	dashboardCfg struct {
-		Address string `default:"127.0.0.1:7778" help:"address for dashboard service"`
+		Address  string        `default:"127.0.0.1:7778" help:"address for dashboard service"`
+		Token    string        `default:"" help:"token of the private address of the storagenode, the operator.token of the storagenode"`
+		Interval time.Duration `default:"3s" help:"how frequently the dashboard is refreshed"`
+		JSON     bool          `default:"false" help:"print the dashboard once as json instead of showing it live"`
+	}
+	maintenanceCfg struct {
+		Address string `default:"127.0.0.1:7778" help:"private address of the storagenode"`
+		Token   string `default:"" help:"token of the private address of the storagenode, the operator.token of the storagenode"`
 	}
 	defaultDiagDir string
 	confDir        string
@@ -97,20 +213,44 @@ func init() {
 	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(diagCmd)
 	rootCmd.AddCommand(dashboardCmd)
+	rootCmd.AddCommand(benchCmd)
+	rootCmd.AddCommand(benchmarkCmd)
+	rootCmd.AddCommand(migrateInfoCmd)
+	rootCmd.AddCommand(migrateStorageCmd)
+	rootCmd.AddCommand(rebuildPieceInfoCmd)
+	rootCmd.AddCommand(payoutsCmd)
+	rootCmd.AddCommand(maintenanceCmd)
+	rootCmd.AddCommand(ordersCmd)
+	ordersCmd.AddCommand(ordersExportCmd)
 	cfgstruct.Bind(runCmd.Flags(), &runCfg, isDev, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
 	cfgstruct.BindSetup(setupCmd.Flags(), &setupCfg, isDev, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
 	cfgstruct.BindSetup(configCmd.Flags(), &setupCfg, isDev, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
 	cfgstruct.Bind(diagCmd.Flags(), &diagCfg, isDev, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
+	cfgstruct.Bind(benchCmd.Flags(), &benchCfg, isDev, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
+	cfgstruct.Bind(benchmarkCmd.Flags(), &benchmarkCfg, isDev, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
+	cfgstruct.Bind(migrateInfoCmd.Flags(), &migrateInfoCfg, isDev, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
+	cfgstruct.Bind(migrateStorageCmd.Flags(), &migrateStorageCfg, isDev, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
+	cfgstruct.Bind(rebuildPieceInfoCmd.Flags(), &rebuildPieceInfoCfg, isDev, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
+	cfgstruct.Bind(payoutsCmd.Flags(), &payoutsCfg, isDev, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
+	cfgstruct.Bind(ordersExportCmd.Flags(), &ordersExportCfg, isDev, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
 	cfgstruct.Bind(dashboardCmd.Flags(), &dashboardCfg, isDev, cfgstruct.ConfDir(defaultDiagDir))
+	cfgstruct.Bind(maintenanceCmd.Flags(), &maintenanceCfg, isDev, cfgstruct.ConfDir(defaultDiagDir))
 }
 
 func databaseConfig(config storagenode.Config) storagenodedb.Config {
+	paths := config.Storage.Paths()
 	return storagenodedb.Config{
-		Storage:  config.Storage.Path,
-		Info:     filepath.Join(config.Storage.Path, "piecestore.db"),
-		Info2:    filepath.Join(config.Storage.Path, "info.db"),
-		Pieces:   config.Storage.Path,
-		Kademlia: config.Kademlia.DBPath,
+		Storage:     paths[0],
+		Info:        filepath.Join(paths[0], "piecestore.db"),
+		Info2:       filepath.Join(paths[0], "info.db"),
+		Pieces:      paths[0],
+		ExtraPieces: paths[1:],
+		Kademlia:    config.Kademlia.DBPath,
+
+		DatabaseURL: config.Storage2.DatabaseURL,
+		AutoRecover: config.Storage2.DatabaseAutoRecover,
+		KeyFile:     config.Storage2.DatabaseKeyFile,
+		Preallocate: config.Storage2.Preallocate,
 	}
 }
 
@@ -152,6 +292,14 @@ func cmdRun(cmd *cobra.Command, args []string) (err error) {
 		return err
 	}
 
+	process.ReloadOnSignal(ctx, cmd, func(flags *pflag.FlagSet) func(context.Context) error {
+		var reloadCfg StorageNodeFlags
+		cfgstruct.Bind(flags, &reloadCfg, isDev, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
+		return func(ctx context.Context) error {
+			return peer.Reload(ctx, reloadCfg.Config)
+		}
+	})
+
 	runError := peer.Run(ctx)
 	closeError := peer.Close()
 
@@ -218,97 +366,21 @@ func cmdConfig(cmd *cobra.Command, args []string) (err error) {
 	return fpath.EditFile(conf)
 }
 
-func cmdDiag(cmd *cobra.Command, args []string) (err error) {
-	diagDir, err := filepath.Abs(confDir)
-	if err != nil {
-		return err
-	}
-
-	// check if the directory exists
-	_, err = os.Stat(diagDir)
-	if err != nil {
-		fmt.Println("Storagenode directory doesn't exist", diagDir)
-		return err
-	}
+func cmdMigrateInfo(cmd *cobra.Command, args []string) (err error) {
+	ctx := process.Ctx(cmd)
 
-	db, err := storagenodedb.New(zap.L().Named("db"), databaseConfig(diagCfg))
-	if err != nil {
-		return errs.New("Error starting master database on storagenode: %v", err)
+	if migrateInfoCfg.Storage2.DatabaseURL == "" {
+		return errs.New("storage2.database-url is required")
 	}
-	defer func() {
-		err = errs.Combine(err, db.Close())
-	}()
 
-	//get all bandwidth aggrements entries already ordered
-	bwAgreements, err := db.PSDB().GetBandwidthAllocations()
+	infoPath := databaseConfig(migrateInfoCfg).Info2
+	err = storagenodedb.MigrateInfo(ctx, zap.L().Named("db"), infoPath, migrateInfoCfg.Storage2.DatabaseURL)
 	if err != nil {
-		fmt.Printf("storage node 'bandwidth_agreements' table read error: %v\n", err)
 		return err
 	}
 
-	// Agreement is a struct that contains a bandwidth agreement and the associated signature
-	type SatelliteSummary struct {
-		TotalBytes           int64
-		PutActionCount       int64
-		GetActionCount       int64
-		GetAuditActionCount  int64
-		GetRepairActionCount int64
-		PutRepairActionCount int64
-		TotalTransactions    int64
-		// additional attributes add here ...
-	}
-
-	// attributes per satelliteid
-	summaries := make(map[storj.NodeID]*SatelliteSummary)
-	satelliteIDs := storj.NodeIDList{}
-
-	for _, rbaVal := range bwAgreements {
-		for _, rbaDataVal := range rbaVal {
-			rba := rbaDataVal.Agreement
-			pba := rba.PayerAllocation
-
-			summary, ok := summaries[pba.SatelliteId]
-			if !ok {
-				summaries[pba.SatelliteId] = &SatelliteSummary{}
-				satelliteIDs = append(satelliteIDs, pba.SatelliteId)
-				summary = summaries[pba.SatelliteId]
-			}
-
-			// fill the summary info
-			summary.TotalBytes += rba.Total
-			summary.TotalTransactions++
-			switch pba.Action {
-			case pb.BandwidthAction_PUT:
-				summary.PutActionCount++
-			case pb.BandwidthAction_GET:
-				summary.GetActionCount++
-			case pb.BandwidthAction_GET_AUDIT:
-				summary.GetAuditActionCount++
-			case pb.BandwidthAction_GET_REPAIR:
-				summary.GetRepairActionCount++
-			case pb.BandwidthAction_PUT_REPAIR:
-				summary.PutRepairActionCount++
-			}
-		}
-	}
-
-	// initialize the table header (fields)
-	const padding = 3
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, padding, ' ', tabwriter.AlignRight|tabwriter.Debug)
-	fmt.Fprintln(w, "SatelliteID\tTotal\t# Of Transactions\tPUT Action\tGET Action\tGET (Audit) Action\tGET (Repair) Action\tPUT (Repair) Action\t")
-
-	// populate the row fields
-	sort.Sort(satelliteIDs)
-	for _, satelliteID := range satelliteIDs {
-		summary := summaries[satelliteID]
-		fmt.Fprint(w, satelliteID, "\t", summary.TotalBytes, "\t", summary.TotalTransactions, "\t",
-			summary.PutActionCount, "\t", summary.GetActionCount, "\t", summary.GetAuditActionCount,
-			"\t", summary.GetRepairActionCount, "\t", summary.PutRepairActionCount, "\t\n")
-	}
-
-	// display the data
-	err = w.Flush()
-	return err
+	fmt.Printf("Copied %s, the storagenode uses %s once restarted\n", infoPath, migrateInfoCfg.Storage2.DatabaseURL)
+	return nil
 }
 
 func main() {
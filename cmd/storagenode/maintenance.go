@@ -0,0 +1,63 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/zeebo/errs"
+	"google.golang.org/grpc"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/transport"
+)
+
+func cmdMaintenance(cmd *cobra.Command, args []string) (err error) {
+	ctx := process.Ctx(cmd)
+
+	conn, err := dialPrivate(ctx, maintenanceCfg.Address, maintenanceCfg.Token)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, conn.Close()) }()
+
+	client := pb.NewNodeOperatorClient(conn)
+
+	var response *pb.MaintenanceResponse
+	if len(args) == 0 {
+		response, err = client.Maintenance(ctx, &pb.MaintenanceRequest{})
+	} else {
+		switch args[0] {
+		case "on":
+			response, err = client.SetMaintenance(ctx, &pb.SetMaintenanceRequest{Enabled: true})
+		case "off":
+			response, err = client.SetMaintenance(ctx, &pb.SetMaintenanceRequest{Enabled: false})
+		default:
+			return errs.New("invalid argument %q, expected on or off", args[0])
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if response.Enabled {
+		fmt.Println("maintenance mode: on, uploads are rejected")
+	} else {
+		fmt.Println("maintenance mode: off")
+	}
+	return nil
+}
+
+// dialPrivate dials the private address of the storagenode, with the token
+// when the storagenode requires one.
+func dialPrivate(ctx context.Context, address, token string) (*grpc.ClientConn, error) {
+	var opts []grpc.DialOption
+	if token != "" {
+		opts = append(opts, transport.WithToken(token))
+	}
+	return transport.DialAddressInsecure(ctx, address, opts...)
+}
@@ -0,0 +1,104 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/storagenode/storagenodedb"
+)
+
+func cmdMigrateStorage(cmd *cobra.Command, args []string) (err error) {
+	ctx := process.Ctx(cmd)
+
+	if migrateStorageCfg.To == "" {
+		return errs.New("--to is required")
+	}
+
+	paths := migrateStorageCfg.Storage.Paths()
+	from := migrateStorageCfg.From
+	if from == "" {
+		from = paths[0]
+	}
+
+	index := -1
+	for i, path := range paths {
+		if filepath.Clean(path) == filepath.Clean(from) {
+			index = i
+		}
+	}
+	if index < 0 {
+		return errs.New("%s is not a directory of storage.path %q", from, migrateStorageCfg.Storage.Path)
+	}
+
+	err = storagenodedb.MigrateStorage(ctx, zap.L().Named("db"), databaseConfig(migrateStorageCfg.Config),
+		from, migrateStorageCfg.To, migrateStorageCfg.MaxRate.Int64())
+	if err != nil {
+		return err
+	}
+
+	paths[index] = migrateStorageCfg.To
+	storagePath := strings.Join(paths, ",")
+
+	configFile := filepath.Join(confDir, "config.yaml")
+	if err := setConfigValue(configFile, "storage.path", storagePath); err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+		fmt.Printf("Copied %s into %s, set storage.path to %q before restarting the storagenode\n", from, migrateStorageCfg.To, storagePath)
+		return nil
+	}
+
+	fmt.Printf("Copied %s into %s and changed storage.path in %s, the storagenode uses %s once restarted and %s can be removed\n",
+		from, migrateStorageCfg.To, configFile, migrateStorageCfg.To, from)
+	return nil
+}
+
+// setConfigValue replaces the value of key in the config file, or adds it.
+// The file is replaced at once, so that it's never left half written.
+func setConfigValue(configFile, key, value string) error {
+	info, err := os.Stat(configFile)
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		return err
+	}
+
+	var out bytes.Buffer
+	found := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(strings.TrimSpace(line), key+":") {
+			line = fmt.Sprintf("%s: %q", key, value)
+			found = true
+		}
+		fmt.Fprintln(&out, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if !found {
+		fmt.Fprintf(&out, "%s: %q\n", key, value)
+	}
+
+	temp := configFile + ".tmp"
+	if err := ioutil.WriteFile(temp, out.Bytes(), info.Mode()); err != nil {
+		return err
+	}
+	return os.Rename(temp, configFile)
+}
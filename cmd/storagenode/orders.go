@@ -0,0 +1,208 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/spf13/cobra"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storagenode/orders"
+	"storj.io/storj/storagenode/storagenodedb"
+)
+
+// exportedOrder is an unsent or archived order as exported.
+type exportedOrder struct {
+	SatelliteID     string `json:"satellite_id"`
+	SerialNumber    string `json:"serial_number"`
+	UplinkID        string `json:"uplink_id"`
+	PieceID         string `json:"piece_id"`
+	Action          string `json:"action"`
+	Limit           int64  `json:"limit"`
+	Amount          int64  `json:"amount"`
+	OrderExpiration string `json:"order_expiration"`
+	Status          string `json:"status"`
+	ArchivedAt      string `json:"archived_at,omitempty"`
+}
+
+// exportedOrderHeader is the header of the csv export.
+var exportedOrderHeader = []string{
+	"satellite_id", "serial_number", "uplink_id", "piece_id", "action",
+	"limit", "amount", "order_expiration", "status", "archived_at",
+}
+
+// record returns the csv record of the order.
+func (order *exportedOrder) record() []string {
+	return []string{
+		order.SatelliteID, order.SerialNumber, order.UplinkID, order.PieceID, order.Action,
+		strconv.FormatInt(order.Limit, 10), strconv.FormatInt(order.Amount, 10), order.OrderExpiration, order.Status, order.ArchivedAt,
+	}
+}
+
+func newExportedOrder(info *orders.Info, status orders.Status, archivedAt time.Time) *exportedOrder {
+	order := &exportedOrder{
+		SatelliteID:  info.Limit.SatelliteId.String(),
+		SerialNumber: info.Limit.SerialNumber.String(),
+		UplinkID:     info.Limit.UplinkId.String(),
+		PieceID:      info.Limit.PieceId.String(),
+		Action:       info.Limit.Action.String(),
+		Limit:        info.Limit.Limit,
+		Amount:       info.Order.Amount,
+		Status:       status.String(),
+	}
+	if expiration, err := ptypes.Timestamp(info.Limit.OrderExpiration); err == nil {
+		order.OrderExpiration = expiration.Format(time.RFC3339)
+	}
+	if !archivedAt.IsZero() {
+		order.ArchivedAt = archivedAt.Format(time.RFC3339)
+	}
+	return order
+}
+
+// orderWriter writes the exported orders in a format.
+type orderWriter interface {
+	Write(order *exportedOrder) error
+	Close() error
+}
+
+// jsonOrderWriter writes the orders as a json array, one order per line.
+type jsonOrderWriter struct {
+	w     *bufio.Writer
+	count int
+}
+
+func (writer *jsonOrderWriter) Write(order *exportedOrder) error {
+	separator := ",\n"
+	if writer.count == 0 {
+		separator = "[\n"
+	}
+	writer.count++
+
+	data, err := json.Marshal(order)
+	if err != nil {
+		return err
+	}
+	if _, err := writer.w.WriteString(separator); err != nil {
+		return err
+	}
+	_, err = writer.w.Write(data)
+	return err
+}
+
+func (writer *jsonOrderWriter) Close() error {
+	end := "\n]\n"
+	if writer.count == 0 {
+		end = "[]\n"
+	}
+	if _, err := writer.w.WriteString(end); err != nil {
+		return err
+	}
+	return writer.w.Flush()
+}
+
+// csvOrderWriter writes the orders as csv with a header.
+type csvOrderWriter struct {
+	w *csv.Writer
+}
+
+func (writer *csvOrderWriter) Write(order *exportedOrder) error {
+	return writer.w.Write(order.record())
+}
+
+func (writer *csvOrderWriter) Close() error {
+	writer.w.Flush()
+	return writer.w.Error()
+}
+
+func newOrderWriter(w io.Writer, format string) (orderWriter, error) {
+	switch format {
+	case "json":
+		return &jsonOrderWriter{w: bufio.NewWriter(w)}, nil
+	case "csv":
+		writer := &csvOrderWriter{w: csv.NewWriter(w)}
+		return writer, writer.w.Write(exportedOrderHeader)
+	default:
+		return nil, errs.New("unknown format %q, expected json or csv", format)
+	}
+}
+
+// parseExportTime parses an optional RFC3339 time.
+func parseExportTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func cmdOrdersExport(cmd *cobra.Command, args []string) (err error) {
+	ctx := process.Ctx(cmd)
+
+	var satelliteID storj.NodeID
+	if ordersExportCfg.Satellite != "" {
+		satelliteID, err = storj.NodeIDFromString(ordersExportCfg.Satellite)
+		if err != nil {
+			return errs.New("invalid satellite: %v", err)
+		}
+	}
+	from, err := parseExportTime(ordersExportCfg.From)
+	if err != nil {
+		return errs.New("invalid from: %v", err)
+	}
+	to, err := parseExportTime(ordersExportCfg.To)
+	if err != nil {
+		return errs.New("invalid to: %v", err)
+	}
+
+	writer, err := newOrderWriter(os.Stdout, ordersExportCfg.Format)
+	if err != nil {
+		return err
+	}
+
+	db, err := storagenodedb.New(zap.L().Named("db"), databaseConfig(ordersExportCfg.Config))
+	if err != nil {
+		return errs.New("Error starting master database on storagenode: %v", err)
+	}
+	defer func() {
+		err = errs.Combine(err, db.Close())
+	}()
+
+	err = exportOrders(ctx, db.Orders(), writer, satelliteID, from, to)
+	return errs.Combine(err, writer.Close())
+}
+
+// exportOrders writes the unsent orders whose limit expires between from and
+// to, then the orders archived between from and to, of the satellite or of
+// every satellite when satelliteID is zero.
+func exportOrders(ctx context.Context, ordersdb orders.DB, writer orderWriter, satelliteID storj.NodeID, from, to time.Time) error {
+	err := ordersdb.IterateUnsent(ctx, orders.UnsentFilter{
+		SatelliteID:   satelliteID,
+		ExpiresAfter:  from,
+		ExpiresBefore: to,
+	}, func(info *orders.Info) error {
+		return writer.Write(newExportedOrder(info, orders.StatusUnsent, time.Time{}))
+	})
+	if err != nil {
+		return err
+	}
+
+	return ordersdb.IterateArchived(ctx, orders.ArchiveCursor{
+		SatelliteID:    satelliteID,
+		ArchivedAfter:  from,
+		ArchivedBefore: to,
+	}, func(info *orders.ArchivedInfo) error {
+		return writer.Write(newExportedOrder(&orders.Info{Limit: info.Limit, Order: info.Order}, info.Status, info.ArchivedAt))
+	})
+}
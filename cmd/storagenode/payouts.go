@@ -0,0 +1,72 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/accounting/payments"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/storagenode/heldamount"
+	"storj.io/storj/storagenode/storagenodedb"
+)
+
+func cmdPayouts(cmd *cobra.Command, args []string) (err error) {
+	ctx := process.Ctx(cmd)
+
+	db, err := storagenodedb.New(zap.L().Named("db"), databaseConfig(payoutsCfg))
+	if err != nil {
+		return errs.New("Error starting master database on storagenode: %v", err)
+	}
+	defer func() {
+		err = errs.Combine(err, db.Close())
+	}()
+
+	all, err := db.HeldAmount().All(ctx)
+	if err != nil {
+		return err
+	}
+
+	return printPayouts(os.Stdout, all)
+}
+
+// printPayouts prints the payouts of each satellite by month, followed by
+// the totals of the satellite
+func printPayouts(w io.Writer, all []heldamount.Payout) error {
+	const padding = 3
+	tw := tabwriter.NewWriter(w, 0, 0, padding, ' ', tabwriter.AlignRight|tabwriter.Debug)
+	fmt.Fprintln(tw, "SatelliteID\tMonth\tNode Month\tSurge (%)\tHeld (%)\tGross ($)\tHeld ($)\tPaid ($)\t")
+
+	var total heldamount.Payout
+	printTotal := func() {
+		fmt.Fprint(tw, total.SatelliteID, "\tTotal\t\t\t\t", payments.FormatDollars(total.Gross), "\t",
+			payments.FormatDollars(total.Held), "\t", payments.FormatDollars(total.Paid), "\t\n")
+	}
+	for i, payout := range all {
+		if i > 0 && payout.SatelliteID != total.SatelliteID {
+			printTotal()
+			total = heldamount.Payout{}
+		}
+		total.SatelliteID = payout.SatelliteID
+		total.Gross += payout.Gross
+		total.Held += payout.Held
+		total.Paid += payout.Paid
+
+		fmt.Fprint(tw, payout.SatelliteID, "\t", payout.Period.UTC().Format("2006-01"), "\t", payout.NodeMonth, "\t",
+			payout.SurgePercent, "\t", payout.HeldPercent, "\t", payments.FormatDollars(payout.Gross), "\t",
+			payments.FormatDollars(payout.Held), "\t", payments.FormatDollars(payout.Paid), "\t\n")
+	}
+	if len(all) > 0 {
+		printTotal()
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,42 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/storagenode/pieces"
+	"storj.io/storj/storagenode/storagenodedb"
+)
+
+func cmdRebuildPieceInfo(cmd *cobra.Command, args []string) (err error) {
+	ctx := process.Ctx(cmd)
+
+	db, err := storagenodedb.New(zap.L().Named("db"), databaseConfig(rebuildPieceInfoCfg))
+	if err != nil {
+		return errs.New("Error starting master database on storagenode: %v", err)
+	}
+	defer func() {
+		err = errs.Combine(err, db.Close())
+	}()
+
+	if err := db.CreateTables(); err != nil {
+		return errs.New("Error creating tables for master database on storagenode: %v", err)
+	}
+
+	store := pieces.NewStore(zap.L().Named("pieces"), db.Pieces())
+	stats, err := store.RebuildPieceInfo(ctx, db.PieceInfo())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Restored the information of %d pieces, %d pieces already had their information, %d pieces couldn't be restored\n",
+		stats.Restored, stats.Existing, stats.Unrecoverable)
+	return nil
+}
@@ -13,12 +13,21 @@ import (
 	"storj.io/storj/pkg/storj"
 )
 
+var (
+	paddingPowerOfTwo *bool
+	paddingBoundary   *int64
+	convergent        *bool
+)
+
 func init() {
-	addCmd(&cobra.Command{
+	mbCmd := addCmd(&cobra.Command{
 		Use:   "mb",
 		Short: "Create a new bucket",
 		RunE:  makeBucket,
 	}, RootCmd)
+	paddingPowerOfTwo = mbCmd.Flags().Bool("padding-power-of-two", false, "if true, pad the size of the uploaded objects to the next power of two")
+	paddingBoundary = mbCmd.Flags().Int64("padding-boundary", 0, "if non-zero, pad the size of the uploaded objects to a multiple of this number of bytes")
+	convergent = mbCmd.Flags().Bool("convergent", false, "if true, derive the encryption keys from the content to allow deduplication of identical objects (reveals which objects are equal)")
 }
 
 func makeBucket(cmd *cobra.Command, args []string) error {
@@ -53,7 +62,14 @@ func makeBucket(cmd *cobra.Command, args []string) error {
 	if !storj.ErrBucketNotFound.Has(err) {
 		return err
 	}
-	_, err = metainfo.CreateBucket(ctx, dst.Bucket(), &storj.Bucket{PathCipher: storj.Cipher(cfg.Enc.PathType)})
+	_, err = metainfo.CreateBucket(ctx, dst.Bucket(), &storj.Bucket{
+		PathCipher: storj.Cipher(cfg.Enc.PathType),
+		Padding: storj.Padding{
+			PowerOfTwo: *paddingPowerOfTwo,
+			Boundary:   *paddingBoundary,
+		},
+		Convergent: *convergent,
+	})
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,91 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/storj/internal/fpath"
+	"storj.io/storj/pkg/cfgstruct"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/versioncontrol"
+)
+
+var (
+	rootCmd = &cobra.Command{
+		Use:   "versioncontrol",
+		Short: "versioncontrol",
+	}
+	runCmd = &cobra.Command{
+		Use:   "run",
+		Short: "Run the version control server",
+		RunE:  cmdRun,
+	}
+	setupCmd = &cobra.Command{
+		Use:         "setup",
+		Short:       "Create config files",
+		RunE:        cmdSetup,
+		Annotations: map[string]string{"type": "setup"},
+	}
+
+	runCfg   versioncontrol.Config
+	setupCfg versioncontrol.Config
+
+	confDir string
+	isDev   bool
+)
+
+func init() {
+	defaultConfDir := fpath.ApplicationDir("storj", "versioncontrol")
+	cfgstruct.SetupFlag(zap.L(), rootCmd, &confDir, "config-dir", defaultConfDir, "main directory for versioncontrol configuration")
+	cfgstruct.DevFlag(rootCmd, &isDev, false, "use development and test configuration settings")
+	rootCmd.AddCommand(runCmd)
+	rootCmd.AddCommand(setupCmd)
+	cfgstruct.Bind(runCmd.Flags(), &runCfg, isDev, cfgstruct.ConfDir(confDir))
+	cfgstruct.BindSetup(setupCmd.Flags(), &setupCfg, isDev, cfgstruct.ConfDir(confDir))
+}
+
+func cmdRun(cmd *cobra.Command, args []string) (err error) {
+	log := zap.L()
+
+	peer, err := versioncontrol.New(log, &runCfg)
+	if err != nil {
+		return err
+	}
+
+	ctx := process.Ctx(cmd)
+	runError := peer.Run(ctx)
+	closeError := peer.Close()
+
+	return errs.Combine(runError, closeError)
+}
+
+func cmdSetup(cmd *cobra.Command, args []string) (err error) {
+	setupDir, err := filepath.Abs(confDir)
+	if err != nil {
+		return err
+	}
+
+	valid, _ := fpath.IsValidSetupDir(setupDir)
+	if !valid {
+		return fmt.Errorf("versioncontrol configuration already exists (%v)", setupDir)
+	}
+
+	err = os.MkdirAll(setupDir, 0700)
+	if err != nil {
+		return err
+	}
+
+	return process.SaveConfigWithAllDefaults(cmd.Flags(), filepath.Join(setupDir, "config.yaml"), nil)
+}
+
+func main() {
+	process.Exec(rootCmd)
+}
@@ -25,6 +25,16 @@ type Point struct{ X, Y int }
 // Rect is a 2D rectangle in console, excluding Max edge
 type Rect struct{ Min, Max Point }
 
+// Key is a key pressed on the screen, printable keys are their character
+type Key rune
+
+// Keys without a printable character
+const (
+	KeyTab   Key = '\t'
+	KeyLeft  Key = -1
+	KeyRight Key = -2
+)
+
 // Screen is a writable area on screen
 type Screen struct {
 	rendering sync.Mutex
@@ -33,6 +43,7 @@ type Screen struct {
 	closed   bool
 	flushed  frame
 	pending  frame
+	onKey    func(Key)
 }
 
 type frame struct {
@@ -81,6 +92,36 @@ func (screen *Screen) Close() error {
 	return nil
 }
 
+// OnKey sets the function called with the keys pressed while the event loop
+// runs, except for the keys closing the screen. fn must not block.
+func (screen *Screen) OnKey(fn func(Key)) {
+	screen.blitting.Lock()
+	screen.onKey = fn
+	screen.blitting.Unlock()
+}
+
+func (screen *Screen) pressed(key Key) {
+	screen.blitting.Lock()
+	fn := screen.onKey
+	screen.blitting.Unlock()
+	if fn != nil {
+		fn(key)
+	}
+}
+
+// Interrupt stops the event loop of Run.
+func (screen *Screen) Interrupt() {
+	screen.blitting.Lock()
+	closed := screen.closed
+	screen.closed = true
+	screen.blitting.Unlock()
+	if !closed {
+		// termbox blocks until the event loop polls, which it may not do
+		// anymore when it was closed by a key concurrently
+		go termbox.Interrupt()
+	}
+}
+
 // Run runs the event loop
 func (screen *Screen) Run() error {
 	defer screen.markClosed()
@@ -93,8 +134,16 @@ func (screen *Screen) Run() error {
 			switch ev.Key {
 			case termbox.KeyCtrlC, termbox.KeyEsc:
 				return nil
+			case termbox.KeyTab:
+				screen.pressed(KeyTab)
+			case termbox.KeyArrowLeft:
+				screen.pressed(KeyLeft)
+			case termbox.KeyArrowRight:
+				screen.pressed(KeyRight)
 			default:
-				// ignore key presses
+				if ev.Ch != 0 {
+					screen.pressed(Key(ev.Ch))
+				}
 			}
 		case termbox.EventError:
 			return ev.Err
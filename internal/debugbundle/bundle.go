@@ -0,0 +1,182 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package debugbundle captures profiles from the debug endpoints of a running
+// process into a single archive, e.g. for support cases.
+package debugbundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+	"golang.org/x/sync/errgroup"
+)
+
+// Error is the error class for capturing bundles
+var Error = errs.Class("debug bundle error")
+
+// ErrUnauthorized is returned when the process rejects the token
+var ErrUnauthorized = errs.Class("unauthorized")
+
+// Options configures what is captured
+type Options struct {
+	// Address is the debug address of the process, e.g. localhost:7777
+	Address string
+	// Token is the token configured with debug.token on the process
+	Token string
+	// Duration is how long the CPU profile and the execution trace are captured for
+	Duration time.Duration
+}
+
+// Info describes a captured bundle
+type Info struct {
+	Address  string            `json:"address"`
+	Started  time.Time         `json:"started"`
+	Finished time.Time         `json:"finished"`
+	Duration time.Duration     `json:"duration"`
+	Errors   map[string]string `json:"errors,omitempty"`
+}
+
+// file is a single captured file of a bundle
+type file struct {
+	name string
+	path string
+	data []byte
+}
+
+// snapshots are the files that are captured instantly
+var snapshots = []file{
+	{name: "goroutine.txt", path: "/debug/pprof/goroutine?debug=2"},
+	{name: "heap.pb.gz", path: "/debug/pprof/heap"},
+	{name: "allocs.pb.gz", path: "/debug/pprof/allocs"},
+	{name: "block.pb.gz", path: "/debug/pprof/block"},
+	{name: "mutex.pb.gz", path: "/debug/pprof/mutex"},
+	{name: "threadcreate.pb.gz", path: "/debug/pprof/threadcreate"},
+	{name: "cmdline.txt", path: "/debug/pprof/cmdline"},
+	{name: "mon-stats.txt", path: "/mon/stats"},
+	{name: "mon-funcs.txt", path: "/mon/funcs"},
+	{name: "mon-ps.txt", path: "/mon/ps"},
+}
+
+// Capture captures the profiles of the process and writes them as a gzipped
+// tar archive to w. Files that fail to capture are listed in the info.json of
+// the archive, a rejected token fails the whole capture.
+func Capture(ctx context.Context, client *http.Client, opts Options, w io.Writer) (err error) {
+	if opts.Duration <= 0 {
+		return Error.New("duration must be positive")
+	}
+
+	info := Info{
+		Address:  opts.Address,
+		Started:  time.Now().UTC(),
+		Duration: opts.Duration,
+		Errors:   map[string]string{},
+	}
+
+	seconds := strconv.Itoa(int((opts.Duration + time.Second - 1) / time.Second))
+	files := append([]file{
+		{name: "cpu.pb.gz", path: "/debug/pprof/profile?seconds=" + seconds},
+		{name: "trace.out", path: "/debug/pprof/trace?seconds=" + seconds},
+	}, snapshots...)
+
+	var mu sync.Mutex
+	group, groupCtx := errgroup.WithContext(ctx)
+	// the timed captures run concurrently with the snapshots
+	for i := range files {
+		file := &files[i]
+		group.Go(func() error {
+			data, err := fetch(groupCtx, client, opts, file.path)
+			if ErrUnauthorized.Has(err) {
+				return err
+			}
+			if err != nil {
+				mu.Lock()
+				info.Errors[file.name] = err.Error()
+				mu.Unlock()
+				return nil
+			}
+			file.data = data
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return err
+	}
+	info.Finished = time.Now().UTC()
+
+	infoData, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	files = append(files, file{name: "info.json", data: infoData})
+
+	return Error.Wrap(write(w, info.Finished, files))
+}
+
+// fetch requests path from the debug endpoint of the process
+func fetch(ctx context.Context, client *http.Client, opts Options, path string) (_ []byte, err error) {
+	address := opts.Address
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+
+	req, err := http.NewRequest(http.MethodGet, address+path, nil)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if opts.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+opts.Token)
+	}
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, Error.Wrap(resp.Body.Close())) }()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return nil, ErrUnauthorized.New("%s", path)
+	case resp.StatusCode != http.StatusOK:
+		return nil, Error.New("unexpected status %q", resp.Status)
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	return data, Error.Wrap(err)
+}
+
+// write writes the captured files as a gzipped tar archive
+func write(w io.Writer, modified time.Time, files []file) (err error) {
+	gz := gzip.NewWriter(w)
+	archive := tar.NewWriter(gz)
+	defer func() { err = errs.Combine(err, archive.Close(), gz.Close()) }()
+
+	for _, file := range files {
+		if file.data == nil {
+			continue
+		}
+		err := archive.WriteHeader(&tar.Header{
+			Name:    file.name,
+			Mode:    0644,
+			Size:    int64(len(file.data)),
+			ModTime: modified,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := archive.Write(file.data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package debugbundle_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/http/pprof"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/internal/debugbundle"
+	"storj.io/storj/internal/testcontext"
+)
+
+func TestCapture(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	var mux http.ServeMux
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	address := strings.TrimPrefix(server.URL, "http://")
+
+	{ // a wrong token fails the capture
+		var buffer bytes.Buffer
+		err := debugbundle.Capture(ctx, server.Client(), debugbundle.Options{
+			Address:  address,
+			Token:    "wrong",
+			Duration: time.Second,
+		}, &buffer)
+		assert.True(t, debugbundle.ErrUnauthorized.Has(err))
+	}
+
+	var buffer bytes.Buffer
+	err := debugbundle.Capture(ctx, server.Client(), debugbundle.Options{
+		Address:  address,
+		Token:    "secret",
+		Duration: time.Second,
+	}, &buffer)
+	require.NoError(t, err)
+
+	gz, err := gzip.NewReader(&buffer)
+	require.NoError(t, err)
+	archive := tar.NewReader(gz)
+
+	files := map[string][]byte{}
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		files[header.Name], err = ioutil.ReadAll(archive)
+		require.NoError(t, err)
+	}
+
+	for _, name := range []string{"cpu.pb.gz", "trace.out", "goroutine.txt", "heap.pb.gz", "cmdline.txt", "info.json"} {
+		assert.NotEmpty(t, files[name], name)
+	}
+
+	var info debugbundle.Info
+	require.NoError(t, json.Unmarshal(files["info.json"], &info))
+	assert.Equal(t, address, info.Address)
+	assert.Equal(t, time.Second, info.Duration)
+	// monkit endpoints aren't served by the test server
+	assert.Contains(t, info.Errors, "mon-stats.txt")
+	assert.NotContains(t, info.Errors, "heap.pb.gz")
+}
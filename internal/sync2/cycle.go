@@ -9,8 +9,11 @@ import (
 	"time"
 
 	"golang.org/x/sync/errgroup"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
 )
 
+var mon = monkit.Package()
+
 // Cycle implements a controllable recurring event.
 //
 // Cycle control methods don't have any effect after the cycle has completed.
@@ -69,7 +72,7 @@ func (cycle *Cycle) Run(ctx context.Context, fn func(ctx context.Context) error)
 
 	currentInterval := cycle.interval
 	cycle.ticker = time.NewTicker(currentInterval)
-	if err := fn(ctx); err != nil {
+	if err := cycle.runOnce(ctx, fn); err != nil {
 		return err
 	}
 	for {
@@ -101,7 +104,7 @@ func (cycle *Cycle) Run(ctx context.Context, fn func(ctx context.Context) error)
 
 			case cycleTrigger:
 				// trigger the function
-				if err := fn(ctx); err != nil {
+				if err := cycle.runOnce(ctx, fn); err != nil {
 					return err
 				}
 				if message.done != nil {
@@ -115,13 +118,20 @@ func (cycle *Cycle) Run(ctx context.Context, fn func(ctx context.Context) error)
 
 		case <-cycle.ticker.C:
 			// trigger the function
-			if err := fn(ctx); err != nil {
+			if err := cycle.runOnce(ctx, fn); err != nil {
 				return err
 			}
 		}
 	}
 }
 
+// runOnce runs fn once in a new trace, so that every execution can be
+// inspected on its own rather than as a part of the never-ending Run.
+func (cycle *Cycle) runOnce(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	defer mon.FuncNamed("cycle").ResetTrace(&ctx)(&err)
+	return fn(ctx)
+}
+
 // Close closes all resources associated with it.
 func (cycle *Cycle) Close() {
 	cycle.Stop()
@@ -0,0 +1,165 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information
+
+package testplanet
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"storj.io/storj/bootstrap"
+	"storj.io/storj/pkg/identity"
+	"storj.io/storj/pkg/kademlia"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/server"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/satellite"
+	"storj.io/storj/storagenode"
+)
+
+// errPartitioned is returned for the requests between partitioned members
+var errPartitioned = errors.New("partitioned from the peer")
+
+// Member is a member of the planet, either a peer or an uplink
+type Member interface {
+	ID() storj.NodeID
+}
+
+// partition blocks the traffic between two members
+type partition struct {
+	a, b storj.NodeID
+}
+
+// StartPeer starts a stopped peer again, with the same identity, addresses,
+// database and data directory. The restarted peer replaces the stopped one
+// in the planet.
+func (planet *Planet) StartPeer(peer Peer) error {
+	for i := range planet.peers {
+		p := &planet.peers[i]
+		if p.peer != peer {
+			continue
+		}
+		if p.restart == nil {
+			return errors.New("peer can't be restarted")
+		}
+
+		// ensure the peer is stopped, so it releases its addresses
+		if err := p.Close(); err != nil {
+			return err
+		}
+
+		restarted, err := p.restart(peer.Addr(), peer.PrivateAddr())
+		if err != nil {
+			return err
+		}
+
+		p.peer = restarted
+		p.close = sync.Once{}
+		p.err = nil
+		p.ctx, p.cancel = context.WithCancel(planet.ctx)
+		ctx := p.ctx
+
+		planet.replacePeer(peer, restarted)
+		planet.filterPartitions(restarted)
+		planet.run.Go(func() error {
+			return restarted.Run(ctx)
+		})
+
+		service := kademliaOf(restarted)
+		if len(service.GetBootstrapNodes()) == 0 {
+			service.SetBootstrapNodes([]pb.Node{planet.Bootstrap.Local()})
+		}
+		service.WaitForBootstrap()
+		return nil
+	}
+	return errors.New("unknown peer")
+}
+
+// PartitionPeers blocks the traffic between the members a and b, until the
+// partitions are healed. The requests between them fail, as if they were
+// offline for each other.
+func (planet *Planet) PartitionPeers(a, b Member) {
+	planet.partitionsMu.Lock()
+	defer planet.partitionsMu.Unlock()
+
+	if planet.partitions == nil {
+		planet.partitions = map[partition]struct{}{}
+	}
+	planet.partitions[partition{a.ID(), b.ID()}] = struct{}{}
+	planet.partitions[partition{b.ID(), a.ID()}] = struct{}{}
+}
+
+// HealPartitions removes all the partitions between the members of the planet
+func (planet *Planet) HealPartitions() {
+	planet.partitionsMu.Lock()
+	defer planet.partitionsMu.Unlock()
+
+	planet.partitions = nil
+}
+
+// partitioned returns whether the traffic between the members is blocked
+func (planet *Planet) partitioned(a, b storj.NodeID) bool {
+	planet.partitionsMu.RLock()
+	defer planet.partitionsMu.RUnlock()
+
+	_, ok := planet.partitions[partition{a, b}]
+	return ok
+}
+
+// filterPartitions makes the peer reject the requests of the members it's
+// partitioned from
+func (planet *Planet) filterPartitions(peer Peer) {
+	server := serverOf(peer)
+	if server == nil {
+		return
+	}
+
+	id := peer.ID()
+	server.SetPeerFilter(func(other *identity.PeerIdentity) error {
+		if planet.partitioned(id, other.ID) {
+			return errPartitioned
+		}
+		return nil
+	})
+}
+
+// replacePeer replaces the peer in the exported lists of the planet
+func (planet *Planet) replacePeer(stopped, restarted Peer) {
+	for i, peer := range planet.Satellites {
+		if Peer(peer) == stopped {
+			planet.Satellites[i] = restarted.(*satellite.Peer)
+		}
+	}
+	for i, peer := range planet.StorageNodes {
+		if Peer(peer) == stopped {
+			planet.StorageNodes[i] = restarted.(*storagenode.Peer)
+		}
+	}
+}
+
+// serverOf returns the server of the peer
+func serverOf(peer Peer) *server.Server {
+	switch peer := peer.(type) {
+	case *bootstrap.Peer:
+		return peer.Server
+	case *satellite.Peer:
+		return peer.Server
+	case *storagenode.Peer:
+		return peer.Server
+	}
+	return nil
+}
+
+// kademliaOf returns the kademlia service of the peer
+func kademliaOf(peer Peer) *kademlia.Kademlia {
+	switch peer := peer.(type) {
+	case *bootstrap.Peer:
+		return peer.Kademlia.Service
+	case *satellite.Peer:
+		return peer.Kademlia.Service
+	case *storagenode.Peer:
+		return peer.Kademlia.Service
+	}
+	return nil
+}
@@ -27,6 +27,7 @@ import (
 	"storj.io/storj/bootstrap/bootstrapdb"
 	"storj.io/storj/bootstrap/bootstrapweb/bootstrapserver"
 	"storj.io/storj/internal/memory"
+	"storj.io/storj/pkg/accounting/payments"
 	"storj.io/storj/pkg/accounting/rollup"
 	"storj.io/storj/pkg/accounting/tally"
 	"storj.io/storj/pkg/audit"
@@ -47,18 +48,35 @@ import (
 	"storj.io/storj/satellite"
 	"storj.io/storj/satellite/console"
 	"storj.io/storj/satellite/console/consoleweb"
+	"storj.io/storj/satellite/disqualification"
+	"storj.io/storj/satellite/expireddeletion"
+	"storj.io/storj/satellite/gc"
 	"storj.io/storj/satellite/mailservice"
+	"storj.io/storj/satellite/metainfo"
 	"storj.io/storj/satellite/satellitedb"
+	"storj.io/storj/storage/boltdb"
 	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/bandwidth"
+	"storj.io/storj/storagenode/collector"
+	"storj.io/storj/storagenode/console/consoleserver"
+	"storj.io/storj/storagenode/diskhealth"
+	"storj.io/storj/storagenode/heldamount"
+	"storj.io/storj/storagenode/notification"
 	"storj.io/storj/storagenode/orders"
+	"storj.io/storj/storagenode/pieces"
 	"storj.io/storj/storagenode/piecestore"
+	"storj.io/storj/storagenode/reputation"
+	"storj.io/storj/storagenode/scrubber"
 	"storj.io/storj/storagenode/storagenodedb"
+	"storj.io/storj/storagenode/storageusage"
+	"storj.io/storj/storagenode/trust"
 )
 
 // Peer represents one of StorageNode or Satellite
 type Peer interface {
 	ID() storj.NodeID
 	Addr() string
+	PrivateAddr() string
 	Local() pb.Node
 
 	Run(context.Context) error
@@ -96,12 +114,19 @@ type Planet struct {
 	identities    *Identities
 	whitelistPath string // TODO: in-memory
 
+	partitionsMu sync.RWMutex
+	partitions   map[partition]struct{}
+
 	run    errgroup.Group
+	ctx    context.Context
 	cancel func()
 }
 
 type closablePeer struct {
 	peer Peer
+	// restart creates the peer again listening on the addresses, nil when
+	// the peer can't be restarted
+	restart func(address, privateAddress string) (Peer, error)
 
 	ctx    context.Context
 	cancel func()
@@ -208,11 +233,12 @@ func NewCustom(log *zap.Logger, config Config) (*Planet, error) {
 // Start starts all the nodes.
 func (planet *Planet) Start(ctx context.Context) {
 	ctx, cancel := context.WithCancel(ctx)
-	planet.cancel = cancel
+	planet.ctx, planet.cancel = ctx, cancel
 
 	for i := range planet.peers {
 		peer := &planet.peers[i]
 		peer.ctx, peer.cancel = context.WithCancel(ctx)
+		planet.filterPartitions(peer.peer)
 		planet.run.Go(func() error {
 			return peer.peer.Run(peer.ctx)
 		})
@@ -268,7 +294,9 @@ func (planet *Planet) Reconnect(ctx context.Context) {
 	_ = group.Wait() // none of the goroutines return an error
 }
 
-// StopPeer stops a single peer in the planet
+// StopPeer stops a single peer in the planet. The peer closes its servers and
+// the databases it owns, while its main database and its data directory are
+// kept, so it can be started again with StartPeer.
 func (planet *Planet) StopPeer(peer Peer) error {
 	for i := range planet.peers {
 		p := &planet.peers[i]
@@ -332,7 +360,7 @@ func (planet *Planet) Shutdown() error {
 func (planet *Planet) newUplinks(prefix string, count, storageNodeCount int) ([]*Uplink, error) {
 	var xs []*Uplink
 	for i := 0; i < count; i++ {
-		uplink, err := planet.newUplink(prefix+strconv.Itoa(i), storageNodeCount)
+		uplink, err := planet.newUplink(prefix+strconv.Itoa(i), i, storageNodeCount)
 		if err != nil {
 			return nil, err
 		}
@@ -346,9 +374,10 @@ func (planet *Planet) newUplinks(prefix string, count, storageNodeCount int) ([]
 func (planet *Planet) newSatellites(count int) ([]*satellite.Peer, error) {
 	// TODO: move into separate file
 	var xs []*satellite.Peer
+	var restarts []func(address, privateAddress string) (Peer, error)
 	defer func() {
-		for _, x := range xs {
-			planet.peers = append(planet.peers, closablePeer{peer: x})
+		for i, x := range xs {
+			planet.peers = append(planet.peers, closablePeer{peer: x, restart: restarts[i]})
 		}
 	}()
 
@@ -414,6 +443,18 @@ func (planet *Planet) newSatellites(count int) ([]*satellite.Peer, error) {
 					AuditCount:            0,
 					NewNodeAuditThreshold: 0,
 					NewNodePercentage:     0,
+					// all the storage nodes share localhost
+					DistinctIP: false,
+				},
+				Reputation: overlay.ReputationConfig{
+					AuditAlpha0:  20,
+					AuditLambda:  0.95,
+					AuditWeight:  1,
+					AuditDQ:      0.6,
+					UptimeAlpha0: 100,
+					UptimeLambda: 0.99,
+					UptimeWeight: 1,
+					UptimeDQ:     0.6,
 				},
 			},
 			Discovery: discovery.Config{
@@ -429,19 +470,50 @@ func (planet *Planet) newSatellites(count int) ([]*satellite.Peer, error) {
 				Overlay:              true,
 				BwExpiration:         45,
 			},
+			MetainfoLoop: metainfo.LoopConfig{
+				CoalesceDuration: 1 * time.Second,
+			},
 			BwAgreement: bwagreement.Config{},
 			Checker: checker.Config{
-				Interval: 30 * time.Second,
+				Interval:                  30 * time.Second,
+				ReliabilityCacheStaleness: 1 * time.Minute,
 			},
 			Repairer: repairer.Config{
-				MaxRepair:    10,
-				Interval:     time.Hour,
-				MaxBufferMem: 4 * memory.MiB,
+				MaxRepair:     10,
+				Interval:      time.Hour,
+				StatsInterval: time.Hour,
+				MaxBufferMem:  4 * memory.MiB,
 			},
 			Audit: audit.Config{
-				MaxRetriesStatDB:  0,
-				Interval:          30 * time.Second,
-				MinBytesPerSecond: 1 * memory.KB,
+				MaxRetriesStatDB:   0,
+				MaxReverifyCount:   3,
+				Interval:           30 * time.Second,
+				MinBytesPerSecond:  1 * memory.KB,
+				DialTimeout:        10 * time.Second,
+				MinDownloadTimeout: 10 * time.Second,
+				LongTailTimeout:    5 * time.Second,
+				WorkerConcurrency:  2,
+				Slots:              3,
+				ChoreInterval:      time.Hour,
+			},
+			GarbageCollection: gc.Config{
+				Interval:          time.Hour,
+				InitialPieces:     10,
+				FalsePositiveRate: 0.1,
+			},
+			Disqualification: disqualification.Config{
+				Interval:          time.Hour,
+				ContainedFailures: 6,
+				OfflineDuration:   720 * time.Hour,
+			},
+			ExpiredDeletion: expireddeletion.Config{
+				Interval:  time.Hour,
+				Enabled:   true,
+				BatchSize: 100,
+			},
+			Bolt: boltdb.ChoreConfig{
+				Interval:  time.Hour,
+				FreePages: 25600,
 			},
 			Tally: tally.Config{
 				Interval: 30 * time.Second,
@@ -449,6 +521,13 @@ func (planet *Planet) newSatellites(count int) ([]*satellite.Peer, error) {
 			Rollup: rollup.Config{
 				Interval: 120 * time.Second,
 			},
+			Payments: payments.Config{
+				AtRestPrice:       1.5,
+				EgressPrice:       20,
+				RepairEgressPrice: 10,
+				AuditEgressPrice:  10,
+				SurgePercent:      100,
+			},
 			Mail: mailservice.Config{
 				SMTPServerAddress: "smtp.mail.example.com:587",
 				From:              "Labs <storj@example.com>",
@@ -481,6 +560,12 @@ func (planet *Planet) newSatellites(count int) ([]*satellite.Peer, error) {
 
 		log.Debug("id=" + peer.ID().String() + " addr=" + peer.Addr())
 		xs = append(xs, peer)
+		restarts = append(restarts, func(address, privateAddress string) (Peer, error) {
+			config := config
+			config.Server.Address = address
+			config.Server.PrivateAddress = privateAddress
+			return satellite.New(log, identity, db, &config)
+		})
 	}
 	return xs, nil
 }
@@ -489,9 +574,10 @@ func (planet *Planet) newSatellites(count int) ([]*satellite.Peer, error) {
 func (planet *Planet) newStorageNodes(count int, whitelistedSatelliteIDs []string) ([]*storagenode.Peer, error) {
 	// TODO: move into separate file
 	var xs []*storagenode.Peer
+	var restarts []func(address, privateAddress string) (Peer, error)
 	defer func() {
-		for _, x := range xs {
-			planet.peers = append(planet.peers, closablePeer{peer: x})
+		for i, x := range xs {
+			planet.peers = append(planet.peers, closablePeer{peer: x, restart: restarts[i]})
 		}
 	}()
 
@@ -562,10 +648,85 @@ func (planet *Planet) newStorageNodes(count int, whitelistedSatelliteIDs []strin
 				WhitelistedSatelliteIDs: strings.Join(whitelistedSatelliteIDs, ","),
 			},
 			Storage2: piecestore.Config{
+				DBMaintenanceInterval: time.Hour,
+				DBStatsInterval:       time.Hour,
 				Sender: orders.SenderConfig{
-					Interval: time.Hour,
-					Timeout:  time.Hour,
+					Interval:  time.Hour,
+					Timeout:   time.Hour,
+					BatchSize: 10,
+				},
+				Orders: orders.CleanupConfig{
+					Interval:   time.Hour,
+					ArchiveTTL: 7 * 24 * time.Hour,
+				},
+				Retain: pieces.RetainConfig{
+					Status:      "enabled",
+					MaxTimeSkew: time.Minute,
+					PageSize:    100,
+				},
+				Deleter: pieces.DeleterConfig{
+					Workers:   2,
+					BatchSize: 100,
+				},
+				Cache: pieces.CacheConfig{
+					PersistInterval:   time.Hour,
+					ReconcileInterval: time.Hour,
 				},
+				Trash: pieces.TrashConfig{
+					RestoreWindow: 7 * 24 * time.Hour,
+					Interval:      time.Hour,
+				},
+			},
+			Collector: collector.Config{
+				Interval:  time.Hour,
+				BatchSize: 100,
+			},
+			Scrubber: scrubber.Config{
+				Interval:       time.Hour,
+				PiecesPerCycle: 100,
+				QuarantineDir:  filepath.Join(storageDir, "quarantine"),
+			},
+			Bandwidth: bandwidth.Config{
+				Interval: time.Hour,
+			},
+			Trust: trust.Config{
+				RefreshInterval: time.Hour,
+				FetchTimeout:    time.Minute,
+			},
+			DiskHealth: diskhealth.Config{
+				Interval:      time.Hour,
+				TestSize:      memory.KiB,
+				FailThreshold: 3,
+			},
+			Bolt: boltdb.ChoreConfig{
+				Interval:  time.Hour,
+				FreePages: 25600,
+			},
+			Notification: notification.Config{
+				Interval:         time.Hour,
+				OfflineThreshold: time.Hour,
+				DiskUsedRatio:    0.9,
+				WebhookTimeout:   time.Minute,
+			},
+			Reputation: reputation.Config{
+				Interval: time.Hour,
+			},
+			HeldAmount: heldamount.Config{
+				Interval: time.Hour,
+			},
+			StorageUsage: storageusage.Config{
+				Interval: time.Hour,
+			},
+			Payments: payments.Config{
+				AtRestPrice:       1.5,
+				EgressPrice:       20,
+				RepairEgressPrice: 10,
+				AuditEgressPrice:  10,
+				SurgePercent:      100,
+			},
+			Console: consoleserver.Config{
+				Address: "127.0.0.1:0",
+				Metrics: true,
 			},
 		}
 		if planet.config.Reconfigure.StorageNode != nil {
@@ -579,6 +740,12 @@ func (planet *Planet) newStorageNodes(count int, whitelistedSatelliteIDs []strin
 
 		log.Debug("id=" + peer.ID().String() + " addr=" + peer.Addr())
 		xs = append(xs, peer)
+		restarts = append(restarts, func(address, privateAddress string) (Peer, error) {
+			config := config
+			config.Server.Address = address
+			config.Server.PrivateAddress = privateAddress
+			return storagenode.New(log, identity, db, config)
+		})
 	}
 	return xs, nil
 }
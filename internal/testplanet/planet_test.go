@@ -54,6 +54,61 @@ func TestBasic(t *testing.T) {
 	time.Sleep(time.Second)
 }
 
+func TestStartPeer(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	planet, err := testplanet.New(t, 1, 4, 1)
+	require.NoError(t, err)
+	defer ctx.Check(planet.Shutdown)
+
+	planet.Start(ctx)
+
+	stopped := planet.StorageNodes[1]
+	require.NoError(t, planet.StopPeer(stopped))
+
+	_, err = planet.StorageNodes[0].Kademlia.Service.Ping(ctx, stopped.Local())
+	require.Error(t, err)
+
+	require.NoError(t, planet.StartPeer(stopped))
+
+	restarted := planet.StorageNodes[1]
+	require.NotEqual(t, stopped, restarted)
+	require.Equal(t, stopped.ID(), restarted.ID())
+	require.Equal(t, stopped.Addr(), restarted.Addr())
+
+	_, err = planet.StorageNodes[0].Kademlia.Service.Ping(ctx, restarted.Local())
+	require.NoError(t, err)
+
+	// the restarted peer can be stopped again
+	require.NoError(t, planet.StopPeer(restarted))
+}
+
+func TestPartitionPeers(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	planet, err := testplanet.New(t, 1, 4, 1)
+	require.NoError(t, err)
+	defer ctx.Check(planet.Shutdown)
+
+	planet.Start(ctx)
+
+	planet.PartitionPeers(planet.StorageNodes[0], planet.StorageNodes[1])
+
+	_, err = planet.StorageNodes[0].Kademlia.Service.Ping(ctx, planet.StorageNodes[1].Local())
+	require.Error(t, err)
+	_, err = planet.StorageNodes[1].Kademlia.Service.Ping(ctx, planet.StorageNodes[0].Local())
+	require.Error(t, err)
+	_, err = planet.StorageNodes[0].Kademlia.Service.Ping(ctx, planet.StorageNodes[2].Local())
+	require.NoError(t, err)
+
+	planet.HealPartitions()
+
+	_, err = planet.StorageNodes[0].Kademlia.Service.Ping(ctx, planet.StorageNodes[1].Local())
+	require.NoError(t, err)
+}
+
 func BenchmarkCreate(b *testing.B) {
 	storageNodes := []int{4, 10, 100}
 	for _, count := range storageNodes {
@@ -9,9 +9,11 @@ import (
 	"storj.io/storj/bootstrap"
 	"storj.io/storj/satellite"
 	"storj.io/storj/storagenode"
+	"storj.io/storj/uplink"
 )
 
-// Reconfigure allows to change node configurations
+// Reconfigure allows to change node configurations. The callbacks are called
+// with the index of the peer, after the default configuration is set.
 type Reconfigure struct {
 	NewBootstrapDB func(index int) (bootstrap.DB, error)
 	Bootstrap      func(index int, config *bootstrap.Config)
@@ -21,6 +23,56 @@ type Reconfigure struct {
 
 	NewStorageNodeDB func(index int) (storagenode.DB, error)
 	StorageNode      func(index int, config *storagenode.Config)
+
+	Uplink func(log *zap.Logger, index int, config *uplink.Config)
+}
+
+// Combine returns a Reconfigure, which calls the callbacks of all the
+// reconfigures in order. The databases are created by the last reconfigure
+// which sets them.
+func Combine(reconfigures ...Reconfigure) Reconfigure {
+	var combined Reconfigure
+	for _, reconfigure := range reconfigures {
+		if reconfigure.NewBootstrapDB != nil {
+			combined.NewBootstrapDB = reconfigure.NewBootstrapDB
+		}
+		if reconfigure.NewSatelliteDB != nil {
+			combined.NewSatelliteDB = reconfigure.NewSatelliteDB
+		}
+		if reconfigure.NewStorageNodeDB != nil {
+			combined.NewStorageNodeDB = reconfigure.NewStorageNodeDB
+		}
+	}
+
+	combined.Bootstrap = func(index int, config *bootstrap.Config) {
+		for _, reconfigure := range reconfigures {
+			if reconfigure.Bootstrap != nil {
+				reconfigure.Bootstrap(index, config)
+			}
+		}
+	}
+	combined.Satellite = func(log *zap.Logger, index int, config *satellite.Config) {
+		for _, reconfigure := range reconfigures {
+			if reconfigure.Satellite != nil {
+				reconfigure.Satellite(log, index, config)
+			}
+		}
+	}
+	combined.StorageNode = func(index int, config *storagenode.Config) {
+		for _, reconfigure := range reconfigures {
+			if reconfigure.StorageNode != nil {
+				reconfigure.StorageNode(index, config)
+			}
+		}
+	}
+	combined.Uplink = func(log *zap.Logger, index int, config *uplink.Config) {
+		for _, reconfigure := range reconfigures {
+			if reconfigure.Uplink != nil {
+				reconfigure.Uplink(log, index, config)
+			}
+		}
+	}
+	return combined
 }
 
 // DisablePeerCAWhitelist returns a `Reconfigure` that sets `UsePeerCAWhitelist` for
@@ -0,0 +1,51 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information
+
+package testplanet_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/satellite"
+	"storj.io/storj/uplink"
+)
+
+func TestReconfigure(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	planet, err := testplanet.NewCustom(zaptest.NewLogger(t), testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 5, UplinkCount: 2,
+		Reconfigure: testplanet.Combine(
+			testplanet.Reconfigure{
+				Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+					config.Checker.Interval = time.Hour
+				},
+			},
+			testplanet.Reconfigure{
+				Uplink: func(log *zap.Logger, index int, config *uplink.Config) {
+					config.RS.MinThreshold = index + 1
+					config.RS.MaxThreshold = 5
+				},
+			},
+		),
+	})
+	require.NoError(t, err)
+	defer ctx.Check(planet.Shutdown)
+
+	planet.Start(ctx)
+
+	for i, uplink := range planet.Uplinks {
+		config := uplink.GetConfig(planet.Satellites[0])
+		assert.Equal(t, i+1, config.RS.MinThreshold)
+		assert.Equal(t, 5, config.RS.MaxThreshold)
+	}
+}
@@ -39,7 +39,7 @@ func Run(t *testing.T, config Config, test func(t *testing.T, ctx *testcontext.C
 			planetConfig := config
 			planetConfig.Reconfigure.NewBootstrapDB = nil
 			planetConfig.Reconfigure.NewSatelliteDB = func(log *zap.Logger, index int) (satellite.DB, error) {
-				schema := strings.ToLower(t.Name() + "-satellite/" + strconv.Itoa(index) + "-" + schemaSuffix)
+				schema := satelliteSchemaName(t, "satellite", index, schemaSuffix)
 				db, err := satellitedb.New(log, pgutil.ConnstrWithSchema(satelliteDB.URL, schema))
 				if err != nil {
 					t.Fatal(err)
@@ -50,11 +50,33 @@ func Run(t *testing.T, config Config, test func(t *testing.T, ctx *testcontext.C
 					t.Fatal(err)
 				}
 
+				schemas := []string{schema}
+				if satelliteDB.Name == "Postgres" {
+					// the pointerdb gets its own schema, so its tables
+					// don't mix with the tables of the satellite
+					pointerSchema := satelliteSchemaName(t, "pointerdb", index, schemaSuffix)
+					err = db.CreateSchema(pointerSchema)
+					if err != nil {
+						t.Fatal(err)
+					}
+					schemas = append(schemas, pointerSchema)
+				}
+
 				return &satelliteSchema{
-					DB:     db,
-					schema: schema,
+					DB:      db,
+					schemas: schemas,
 				}, nil
 			}
+			if satelliteDB.Name == "Postgres" {
+				reconfigureSatellite := config.Reconfigure.Satellite
+				planetConfig.Reconfigure.Satellite = func(log *zap.Logger, index int, config *satellite.Config) {
+					pointerSchema := satelliteSchemaName(t, "pointerdb", index, schemaSuffix)
+					config.PointerDB.DatabaseURL = pgutil.ConnstrWithSchema(satelliteDB.URL, pointerSchema)
+					if reconfigureSatellite != nil {
+						reconfigureSatellite(log, index, config)
+					}
+				}
+			}
 			planetConfig.Reconfigure.NewStorageNodeDB = nil
 
 			planet, err := NewCustom(zaptest.NewLogger(t), planetConfig)
@@ -69,15 +91,23 @@ func Run(t *testing.T, config Config, test func(t *testing.T, ctx *testcontext.C
 	}
 }
 
-// satelliteSchema closes database and drops the associated schema
+// satelliteSchemaName returns the name of the schema for a database of the
+// satellite with the index
+func satelliteSchemaName(t *testing.T, database string, index int, suffix string) string {
+	return strings.ToLower(t.Name() + "-" + database + "/" + strconv.Itoa(index) + "-" + suffix)
+}
+
+// satelliteSchema closes database and drops the associated schemas
 type satelliteSchema struct {
 	satellite.DB
-	schema string
+	schemas []string
 }
 
 func (db *satelliteSchema) Close() error {
-	return errs.Combine(
-		db.DB.DropSchema(db.schema),
-		db.DB.Close(),
-	)
+	var group errs.Group
+	for _, schema := range db.schemas {
+		group.Add(db.DB.DropSchema(schema))
+	}
+	group.Add(db.DB.Close())
+	return group.Err()
 }
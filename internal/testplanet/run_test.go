@@ -6,8 +6,12 @@ package testplanet_test
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	"storj.io/storj/internal/testcontext"
 	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/storage"
 )
 
 func TestRun(t *testing.T) {
@@ -15,5 +19,12 @@ func TestRun(t *testing.T) {
 		SatelliteCount: 1, StorageNodeCount: 1, UplinkCount: 1,
 	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
 		t.Log("running test")
+
+		// in the Postgres mode the pointerdb is stored in Postgres too
+		pointers := planet.Satellites[0].Metainfo.Database
+		require.NoError(t, pointers.Put(storage.Key("a/b"), storage.Value("c")))
+		value, err := pointers.Get(storage.Key("a/b"))
+		require.NoError(t, err)
+		assert.Equal(t, storage.Value("c"), value)
 	})
 }
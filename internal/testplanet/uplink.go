@@ -38,10 +38,13 @@ type Uplink struct {
 	Transport        transport.Client
 	StorageNodeCount int
 	APIKey           map[storj.NodeID]string
+
+	index       int
+	reconfigure func(log *zap.Logger, index int, config *uplink.Config)
 }
 
 // newUplink creates a new uplink
-func (planet *Planet) newUplink(name string, storageNodeCount int) (*Uplink, error) {
+func (planet *Planet) newUplink(name string, index, storageNodeCount int) (*Uplink, error) {
 	identity, err := planet.NewIdentity()
 	if err != nil {
 		return nil, err
@@ -56,6 +59,9 @@ func (planet *Planet) newUplink(name string, storageNodeCount int) (*Uplink, err
 		Log:              planet.log.Named(name),
 		Identity:         identity,
 		StorageNodeCount: storageNodeCount,
+
+		index:       index,
+		reconfigure: planet.config.Reconfigure.Uplink,
 	}
 
 	uplink.Log.Debug("id=" + identity.ID.String())
@@ -289,7 +295,8 @@ func (uplink *Uplink) Delete(ctx context.Context, satellite *satellite.Peer, buc
 	return metainfo.DeleteObject(ctx, bucket, path)
 }
 
-// GetConfig returns a default config for a given satellite.
+// GetConfig returns a default config for a given satellite, changed by the
+// Uplink callback of the planet Reconfigure.
 func (uplink *Uplink) GetConfig(satellite *satellite.Peer) uplink.Config {
 	config := getDefaultConfig()
 	config.Client.SatelliteAddr = satellite.Addr()
@@ -304,6 +311,10 @@ func (uplink *Uplink) GetConfig(satellite *satellite.Peer) uplink.Config {
 	config.TLS.Extensions.Revocation = false
 	config.TLS.Extensions.WhitelistSignedLeaf = false
 
+	if uplink.reconfigure != nil {
+		uplink.reconfigure(uplink.Log, uplink.index, &config)
+	}
+
 	return config
 }
 
@@ -0,0 +1,114 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package testrand implements reproducible random values for tests.
+//
+// The random sources are seeded from the -testrand-seed flag or the
+// STORJ_TESTRAND_SEED environment variable, or from the time when neither is
+// set. The seed is logged by the test, so a failure can be reproduced by
+// running the test again with the same seed.
+package testrand
+
+import (
+	"flag"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"storj.io/storj/pkg/storj"
+)
+
+var seed = flag.Int64("testrand-seed", envSeed(), "seed for the random values of the tests, 0 picks a new seed")
+
+// envSeed returns the seed from the environment, or 0 when it's not set
+func envSeed() int64 {
+	seed, _ := strconv.ParseInt(os.Getenv("STORJ_TESTRAND_SEED"), 10, 64)
+	return seed
+}
+
+// Rand is a seeded source of random values
+type Rand struct {
+	*rand.Rand
+	seed int64
+}
+
+// New returns a random source for the test and logs its seed
+func New(tb testing.TB) *Rand {
+	tb.Helper()
+
+	seed := *seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	tb.Logf("testrand seed %d, reproduce with -testrand-seed=%d", seed, seed)
+
+	return NewSeeded(seed)
+}
+
+// NewSeeded returns a random source with the seed
+func NewSeeded(seed int64) *Rand {
+	return &Rand{
+		Rand: rand.New(rand.NewSource(seed)),
+		seed: seed,
+	}
+}
+
+// Seed returns the seed of the random source
+func (r *Rand) Seed() int64 { return r.seed }
+
+// Bytes returns n random bytes
+func (r *Rand) Bytes(n int) []byte {
+	data := make([]byte, n)
+	_, _ = r.Read(data)
+	return data
+}
+
+// NodeID returns a random node ID
+func (r *Rand) NodeID() (id storj.NodeID) {
+	_, _ = r.Read(id[:])
+	return id
+}
+
+// NodeIDs returns n random node IDs
+func (r *Rand) NodeIDs(n int) storj.NodeIDList {
+	ids := make(storj.NodeIDList, n)
+	for i := range ids {
+		ids[i] = r.NodeID()
+	}
+	return ids
+}
+
+// PieceID returns a random piece ID
+func (r *Rand) PieceID() (id storj.PieceID) {
+	_, _ = r.Read(id[:])
+	return id
+}
+
+// SerialNumber returns a random serial number
+func (r *Rand) SerialNumber() (serial storj.SerialNumber) {
+	_, _ = r.Read(serial[:])
+	return serial
+}
+
+// Path returns a random path of the number of segments. The segments are
+// lowercase letters, so the path is valid in every bucket.
+func (r *Rand) Path(segments int) storj.Path {
+	parts := make([]string, segments)
+	for i := range parts {
+		parts[i] = r.segment(1 + r.Intn(16))
+	}
+	return strings.Join(parts, "/")
+}
+
+// segment returns a random path segment of the length
+func (r *Rand) segment(length int) string {
+	const letters = "abcdefghijklmnopqrstuvwxyz"
+	segment := make([]byte, length)
+	for i := range segment {
+		segment[i] = letters[r.Intn(len(letters))]
+	}
+	return string(segment)
+}
@@ -0,0 +1,37 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package testrand_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/internal/testrand"
+)
+
+func TestReproducible(t *testing.T) {
+	a := testrand.New(t)
+	b := testrand.NewSeeded(a.Seed())
+
+	assert.Equal(t, a.Bytes(64), b.Bytes(64))
+	assert.Equal(t, a.NodeID(), b.NodeID())
+	assert.Equal(t, a.NodeIDs(3), b.NodeIDs(3))
+	assert.Equal(t, a.PieceID(), b.PieceID())
+	assert.Equal(t, a.SerialNumber(), b.SerialNumber())
+	assert.Equal(t, a.Path(4), b.Path(4))
+}
+
+func TestPath(t *testing.T) {
+	r := testrand.New(t)
+	for segments := 1; segments < 10; segments++ {
+		path := r.Path(segments)
+		parts := strings.Split(path, "/")
+		assert.Len(t, parts, segments)
+		for _, part := range parts {
+			assert.NotEmpty(t, part)
+		}
+	}
+}
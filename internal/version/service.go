@@ -0,0 +1,189 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package version
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/internal/sync2"
+	"storj.io/storj/pkg/storj"
+)
+
+var mon = monkit.Package()
+
+// Config contains the configurable values for checking the version server
+type Config struct {
+	ServerAddress  string        `help:"server address to check its version against, empty disables the check" default:"https://version.alpha.storj.io"`
+	RequestTimeout time.Duration `help:"request timeout for version checks" default:"1m"`
+	CheckInterval  time.Duration `help:"interval between version checks" default:"15m"`
+	RefuseEnforced bool          `help:"refuse to start when the running version is below the version enforced by the satellites" default:"false"`
+}
+
+// Service periodically checks whether the running binary is still allowed
+// by the version server.
+type Service struct {
+	log     *zap.Logger
+	config  Config
+	info    Info
+	process string
+	id      storj.NodeID
+	client  http.Client
+
+	mu      sync.Mutex
+	allowed bool
+	latest  *Process
+}
+
+// NewService creates a version check service for the named process type.
+// The id is used to determine whether the node is part of a rollout and may be zero.
+func NewService(log *zap.Logger, config Config, info Info, process string, id storj.NodeID) *Service {
+	return &Service{
+		log:     log,
+		config:  config,
+		info:    info,
+		process: process,
+		id:      id,
+		client:  http.Client{Timeout: config.RequestTimeout},
+
+		allowed: true,
+	}
+}
+
+// Run periodically checks the version of the running binary until ctx is canceled
+func (service *Service) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if service.config.ServerAddress == "" {
+		service.log.Debug("version checks are disabled")
+		return nil
+	}
+
+	loop := sync2.NewCycle(service.config.CheckInterval)
+	defer loop.Close()
+
+	return loop.Run(ctx, func(ctx context.Context) error {
+		_, err := service.CheckVersion(ctx)
+		if err != nil {
+			// a failing version server must not take the process down
+			service.log.Warn("failed to check version", zap.Error(err))
+		}
+		return nil
+	})
+}
+
+// CheckStartup returns an error when configured to refuse starting and the
+// running version is below the version enforced by the satellites. The
+// process starts when the version server can't be reached.
+func (service *Service) CheckStartup(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if service.config.ServerAddress == "" || !service.config.RefuseEnforced {
+		return nil
+	}
+
+	if _, err := service.CheckVersion(ctx); err != nil {
+		service.log.Warn("failed to check version", zap.Error(err))
+		return nil
+	}
+
+	latest, _ := service.Latest()
+	if latest.Refuses(service.info.Version) {
+		return Error.New("running version %s is below the version %s enforced by the satellites", service.info.Version, latest.Enforced)
+	}
+	return nil
+}
+
+// IsAllowed returns whether the last version check allowed the running binary.
+// It returns true until the version server has been reached.
+func (service *Service) IsAllowed() bool {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	return service.allowed
+}
+
+// Latest returns the version requirements received by the last successful
+// version check, ok is false until the version server has been reached.
+func (service *Service) Latest() (_ Process, ok bool) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	if service.latest == nil {
+		return Process{}, false
+	}
+	return *service.latest, true
+}
+
+// Info returns the version information of the running binary.
+func (service *Service) Info() Info { return service.info }
+
+// CheckVersion queries the version server and returns whether the running
+// binary is allowed, the result is kept for IsAllowed and Latest.
+func (service *Service) CheckVersion(ctx context.Context) (allowed bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	versions, err := QueryVersions(ctx, &service.client, service.config.ServerAddress)
+	if err != nil {
+		return false, err
+	}
+
+	process, err := versions.Process(service.process)
+	if err != nil {
+		return false, err
+	}
+
+	current := service.info.Version
+	allowed = process.Allows(current)
+
+	service.mu.Lock()
+	service.allowed = allowed
+	service.latest = &process
+	service.mu.Unlock()
+
+	if process.Refuses(current) {
+		service.log.Error("running version is below the version enforced by the satellites, the satellites don't work with this node anymore",
+			zap.Stringer("version", current), zap.Stringer("enforced", process.Enforced))
+	}
+	if !allowed {
+		service.log.Error("running version is below the minimum allowed version",
+			zap.Stringer("version", current), zap.Stringer("minimum", process.Minimum))
+		return false, nil
+	}
+
+	if process.Suggests(service.id, current) {
+		service.log.Info("a newer version is available",
+			zap.Stringer("version", current), zap.Stringer("suggested", process.Suggested))
+	}
+
+	return true, nil
+}
+
+// QueryVersions fetches the allowed versions from the version server at address
+func QueryVersions(ctx context.Context, client *http.Client, address string) (versions AllowedVersions, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	req, err := http.NewRequest(http.MethodGet, address, nil)
+	if err != nil {
+		return versions, Error.Wrap(err)
+	}
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return versions, Error.Wrap(err)
+	}
+	defer func() { err = Error.Wrap(errs.Combine(err, resp.Body.Close())) }()
+
+	if resp.StatusCode != http.StatusOK {
+		return versions, Error.New("unexpected status %q", resp.Status)
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&versions)
+	return versions, Error.Wrap(err)
+}
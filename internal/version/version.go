@@ -0,0 +1,281 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package version
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+)
+
+// Error is the error class for version handling
+var Error = errs.Class("version error")
+
+var (
+	// the following fields are set by linker flags, e.g.
+	//   -ldflags "-X storj.io/storj/internal/version.buildVersion=v0.12.0"
+	buildTimestamp  string
+	buildCommitHash string
+	buildVersion    string
+	buildRelease    string
+
+	// Build is the version information of the current binary
+	Build Info
+)
+
+var versionRegex = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)$`)
+
+// SemVer is a semantic version without pre-release or build metadata
+type SemVer struct {
+	Major int64
+	Minor int64
+	Patch int64
+}
+
+// Info contains the version information of a binary
+type Info struct {
+	Timestamp  time.Time `json:"timestamp,omitempty"`
+	CommitHash string    `json:"commitHash,omitempty"`
+	Version    SemVer    `json:"version"`
+	Release    bool      `json:"release,omitempty"`
+}
+
+// AllowedVersions contains the version requirements of every process type
+type AllowedVersions struct {
+	Satellite   Process `json:"satellite"`
+	Storagenode Process `json:"storagenode"`
+	Uplink      Process `json:"uplink"`
+	Gateway     Process `json:"gateway"`
+	Bootstrap   Process `json:"bootstrap"`
+}
+
+// Process contains the version requirements of a single process type
+type Process struct {
+	// Minimum is the oldest version that is still accepted
+	Minimum SemVer `json:"minimum"`
+	// Enforced is the oldest version the satellites still work with, it is
+	// zero when the satellites don't enforce any version
+	Enforced SemVer `json:"enforced"`
+	// Suggested is the version processes should upgrade to, once included in Rollout
+	Suggested SemVer `json:"suggested"`
+	// Rollout selects which part of the network should upgrade to Suggested
+	Rollout Rollout `json:"rollout"`
+}
+
+// Rollout selects a deterministic percentage of nodes for a staged release
+type Rollout struct {
+	// Seed makes the selection different for every release
+	Seed string `json:"seed"`
+	// Cursor is the percentage of nodes, between 0 and 100, that are selected
+	Cursor int `json:"cursor"`
+}
+
+func init() {
+	info, err := newInfo(buildTimestamp, buildCommitHash, buildVersion, buildRelease)
+	if err != nil {
+		panic(err)
+	}
+	Build = info
+}
+
+// newInfo parses the values set by linker flags
+func newInfo(timestamp, commitHash, version, release string) (Info, error) {
+	info := Info{
+		CommitHash: commitHash,
+		Release:    release == "true",
+	}
+
+	if timestamp != "" {
+		seconds, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return Info{}, Error.New("invalid build timestamp %q: %v", timestamp, err)
+		}
+		info.Timestamp = time.Unix(seconds, 0).UTC()
+	}
+
+	if version != "" {
+		semver, err := NewSemVer(version)
+		if err != nil {
+			return Info{}, err
+		}
+		info.Version = semver
+	}
+
+	return info, nil
+}
+
+// NewSemVer parses a version in the form of "v1.2.3" or "1.2.3"
+func NewSemVer(version string) (SemVer, error) {
+	matches := versionRegex.FindStringSubmatch(version)
+	if matches == nil {
+		return SemVer{}, Error.New("invalid semantic version %q", version)
+	}
+
+	var semver SemVer
+	var err error
+	if semver.Major, err = strconv.ParseInt(matches[1], 10, 64); err != nil {
+		return SemVer{}, Error.Wrap(err)
+	}
+	if semver.Minor, err = strconv.ParseInt(matches[2], 10, 64); err != nil {
+		return SemVer{}, Error.Wrap(err)
+	}
+	if semver.Patch, err = strconv.ParseInt(matches[3], 10, 64); err != nil {
+		return SemVer{}, Error.Wrap(err)
+	}
+	return semver, nil
+}
+
+// String returns the version in the form of "v1.2.3"
+func (semver SemVer) String() string {
+	return fmt.Sprintf("v%d.%d.%d", semver.Major, semver.Minor, semver.Patch)
+}
+
+// IsZero returns whether the version is unset
+func (semver SemVer) IsZero() bool {
+	return semver == SemVer{}
+}
+
+// Compare returns -1, 0 or 1 when semver is older, equal or newer than other
+func (semver SemVer) Compare(other SemVer) int {
+	switch {
+	case semver.Major != other.Major:
+		return compareInt64(semver.Major, other.Major)
+	case semver.Minor != other.Minor:
+		return compareInt64(semver.Minor, other.Minor)
+	default:
+		return compareInt64(semver.Patch, other.Patch)
+	}
+}
+
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (semver SemVer) MarshalText() ([]byte, error) {
+	return []byte(semver.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (semver *SemVer) UnmarshalText(data []byte) (err error) {
+	*semver, err = NewSemVer(string(data))
+	return err
+}
+
+// Proto converts the version information to its protobuf representation
+func (info Info) Proto() (*pb.NodeVersion, error) {
+	version := &pb.NodeVersion{
+		Version:    info.Version.String(),
+		CommitHash: info.CommitHash,
+		Release:    info.Release,
+	}
+
+	if !info.Timestamp.IsZero() {
+		timestamp, err := ptypes.TimestampProto(info.Timestamp)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		version.Timestamp = timestamp
+	}
+
+	return version, nil
+}
+
+// InfoFromProto converts the protobuf representation to version information
+func InfoFromProto(version *pb.NodeVersion) (Info, error) {
+	if version == nil {
+		return Info{}, Error.New("missing version")
+	}
+
+	semver, err := NewSemVer(version.GetVersion())
+	if err != nil {
+		return Info{}, err
+	}
+
+	info := Info{
+		CommitHash: version.GetCommitHash(),
+		Version:    semver,
+		Release:    version.GetRelease(),
+	}
+
+	if version.GetTimestamp() != nil {
+		info.Timestamp, err = ptypes.Timestamp(version.GetTimestamp())
+		if err != nil {
+			return Info{}, Error.Wrap(err)
+		}
+	}
+
+	return info, nil
+}
+
+// Process returns the version requirements for the named process type
+func (versions AllowedVersions) Process(name string) (Process, error) {
+	switch name {
+	case "satellite":
+		return versions.Satellite, nil
+	case "storagenode":
+		return versions.Storagenode, nil
+	case "uplink":
+		return versions.Uplink, nil
+	case "gateway":
+		return versions.Gateway, nil
+	case "bootstrap":
+		return versions.Bootstrap, nil
+	default:
+		return Process{}, Error.New("unknown process %q", name)
+	}
+}
+
+// Allows returns whether version satisfies the minimum version requirement
+func (process Process) Allows(version SemVer) bool {
+	return version.Compare(process.Minimum) >= 0
+}
+
+// Refuses returns whether version is below the version enforced by the satellites
+func (process Process) Refuses(version SemVer) bool {
+	return !process.Enforced.IsZero() && version.Compare(process.Enforced) < 0
+}
+
+// Suggests returns whether the node should upgrade from version to the
+// suggested version, taking the rollout into account.
+func (process Process) Suggests(id storj.NodeID, version SemVer) bool {
+	if version.Compare(process.Suggested) >= 0 {
+		return false
+	}
+	// versions below the minimum must upgrade regardless of the rollout
+	return !process.Allows(version) || process.Rollout.Includes(id)
+}
+
+// Includes returns whether the node falls under the rollout cursor
+func (rollout Rollout) Includes(id storj.NodeID) bool {
+	if rollout.Cursor <= 0 {
+		return false
+	}
+	if rollout.Cursor >= 100 {
+		return true
+	}
+
+	hash := sha256.New()
+	_, _ = hash.Write([]byte(rollout.Seed))
+	_, _ = hash.Write(id.Bytes())
+	sum := hash.Sum(nil)
+
+	return binary.BigEndian.Uint64(sum[:8])%100 < uint64(rollout.Cursor)
+}
@@ -0,0 +1,157 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package version_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/internal/testrand"
+	"storj.io/storj/internal/version"
+)
+
+func TestSemVer(t *testing.T) {
+	for _, tt := range []struct {
+		version  string
+		expected version.SemVer
+	}{
+		{"v0.0.0", version.SemVer{}},
+		{"0.1.2", version.SemVer{Major: 0, Minor: 1, Patch: 2}},
+		{"v1.20.300", version.SemVer{Major: 1, Minor: 20, Patch: 300}},
+	} {
+		semver, err := version.NewSemVer(tt.version)
+		require.NoError(t, err, tt.version)
+		assert.Equal(t, tt.expected, semver, tt.version)
+	}
+
+	for _, invalid := range []string{"", "v1", "v1.2", "1.2.3.4", "v1.2.3-rc", "latest"} {
+		_, err := version.NewSemVer(invalid)
+		assert.Error(t, err, invalid)
+	}
+
+	semver := version.SemVer{Major: 1, Minor: 2, Patch: 3}
+	assert.Equal(t, "v1.2.3", semver.String())
+
+	data, err := json.Marshal(semver)
+	require.NoError(t, err)
+	assert.Equal(t, `"v1.2.3"`, string(data))
+
+	var decoded version.SemVer
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, semver, decoded)
+}
+
+func TestSemVerCompare(t *testing.T) {
+	ordered := []version.SemVer{
+		{Major: 0, Minor: 0, Patch: 0},
+		{Major: 0, Minor: 0, Patch: 1},
+		{Major: 0, Minor: 1, Patch: 0},
+		{Major: 0, Minor: 10, Patch: 0},
+		{Major: 1, Minor: 0, Patch: 0},
+		{Major: 1, Minor: 0, Patch: 10},
+	}
+
+	for i, a := range ordered {
+		for k, b := range ordered {
+			switch {
+			case i < k:
+				assert.Equal(t, -1, a.Compare(b), "%s < %s", a, b)
+			case i > k:
+				assert.Equal(t, 1, a.Compare(b), "%s > %s", a, b)
+			default:
+				assert.Equal(t, 0, a.Compare(b), "%s == %s", a, b)
+			}
+		}
+	}
+}
+
+func TestInfoProto(t *testing.T) {
+	info := version.Info{
+		Timestamp:  time.Unix(1554000000, 0).UTC(),
+		CommitHash: "a1b2c3",
+		Version:    version.SemVer{Major: 0, Minor: 11, Patch: 2},
+		Release:    true,
+	}
+
+	pbversion, err := info.Proto()
+	require.NoError(t, err)
+	assert.Equal(t, "v0.11.2", pbversion.Version)
+
+	decoded, err := version.InfoFromProto(pbversion)
+	require.NoError(t, err)
+	assert.Equal(t, info, decoded)
+
+	_, err = version.InfoFromProto(nil)
+	assert.Error(t, err)
+}
+
+func TestRollout(t *testing.T) {
+	rand := testrand.New(t)
+	ids := rand.NodeIDs(1000)
+
+	count := func(rollout version.Rollout) int {
+		included := 0
+		for _, id := range ids {
+			if rollout.Includes(id) {
+				included++
+			}
+		}
+		return included
+	}
+
+	assert.Equal(t, 0, count(version.Rollout{Seed: "release", Cursor: 0}))
+	assert.Equal(t, len(ids), count(version.Rollout{Seed: "release", Cursor: 100}))
+
+	half := count(version.Rollout{Seed: "release", Cursor: 50})
+	assert.InDelta(t, len(ids)/2, half, float64(len(ids))/10)
+
+	// raising the cursor only adds nodes to the rollout
+	for _, id := range ids {
+		if (version.Rollout{Seed: "release", Cursor: 20}).Includes(id) {
+			assert.True(t, version.Rollout{Seed: "release", Cursor: 60}.Includes(id))
+		}
+	}
+
+	// different seeds select different nodes
+	same := 0
+	for _, id := range ids {
+		a := version.Rollout{Seed: "a", Cursor: 50}.Includes(id)
+		b := version.Rollout{Seed: "b", Cursor: 50}.Includes(id)
+		if a == b {
+			same++
+		}
+	}
+	assert.True(t, same < len(ids), "seeds should select different nodes")
+}
+
+func TestProcess(t *testing.T) {
+	process := version.Process{
+		Minimum:   version.SemVer{Major: 0, Minor: 10, Patch: 0},
+		Suggested: version.SemVer{Major: 0, Minor: 11, Patch: 0},
+	}
+
+	assert.False(t, process.Allows(version.SemVer{Major: 0, Minor: 9, Patch: 9}))
+	assert.True(t, process.Allows(version.SemVer{Major: 0, Minor: 10, Patch: 0}))
+	assert.True(t, process.Allows(version.SemVer{Major: 1, Minor: 0, Patch: 0}))
+
+	// nothing is refused until a version is enforced
+	assert.False(t, process.Refuses(version.SemVer{}))
+	process.Enforced = version.SemVer{Major: 0, Minor: 8, Patch: 0}
+	assert.True(t, process.Refuses(version.SemVer{Major: 0, Minor: 7, Patch: 9}))
+	assert.False(t, process.Refuses(version.SemVer{Major: 0, Minor: 8, Patch: 0}))
+
+	id := testrand.New(t).NodeID()
+
+	// outside of the rollout only outdated versions are told to upgrade
+	assert.True(t, process.Suggests(id, version.SemVer{Major: 0, Minor: 9, Patch: 0}))
+	assert.False(t, process.Suggests(id, version.SemVer{Major: 0, Minor: 10, Patch: 0}))
+
+	process.Rollout.Cursor = 100
+	assert.True(t, process.Suggests(id, version.SemVer{Major: 0, Minor: 10, Patch: 0}))
+	assert.False(t, process.Suggests(id, version.SemVer{Major: 0, Minor: 11, Patch: 0}))
+}
@@ -36,6 +36,14 @@ type Caveat interface {
 // CreateBucketOptions holds the bucket opts
 type CreateBucketOptions struct {
 	Encryption Encryption
+	// Padding obscures the size of the objects uploaded to the bucket
+	Padding storj.Padding
+	// Convergent derives the content encryption keys from the content, so
+	// identical objects uploaded with the same root key can be deduplicated.
+	// It reveals which objects have equal content to anyone who can see the
+	// encrypted data, and lets anyone knowing the root key confirm whether
+	// a known plaintext is stored in the bucket.
+	Convergent bool
 }
 
 // CreateBucket creates a bucket from the passed opts
@@ -45,7 +53,11 @@ func (a *Access) CreateBucket(ctx context.Context, bucket string, opts CreateBuc
 		return storj.Bucket{}, Error.Wrap(err)
 	}
 
-	return metainfo.CreateBucket(ctx, bucket, &storj.Bucket{PathCipher: opts.Encryption.PathCipher})
+	return metainfo.CreateBucket(ctx, bucket, &storj.Bucket{
+		PathCipher: opts.Encryption.PathCipher,
+		Padding:    opts.Padding,
+		Convergent: opts.Convergent,
+	})
 }
 
 // DeleteBucket deletes a bucket if authorized
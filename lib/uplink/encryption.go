@@ -0,0 +1,252 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"storj.io/storj/pkg/encryption"
+	"storj.io/storj/pkg/storj"
+)
+
+// EncryptionRestriction is a bucket and an unencrypted path prefix within it.
+// It can also be used as a Caveat for restricting a Macaroon to the same
+// bucket and prefix.
+type EncryptionRestriction struct {
+	Bucket     string
+	PathPrefix storj.Path
+}
+
+// EncryptionAccess holds the keys for encrypting and decrypting paths and
+// content. It holds either the root key, giving access to everything, or
+// only the keys for a set of bucket and path prefix pairs.
+type EncryptionAccess struct {
+	root *storj.Key
+	keys []restrictedKey
+}
+
+// restrictedKey is the key derived for a bucket and path prefix pair
+type restrictedKey struct {
+	EncryptionRestriction
+	EncPathPrefix storj.Path
+	Key           storj.Key
+}
+
+// NewEncryptionAccess creates an encryption access with the root key
+func NewEncryptionAccess(root storj.Key) *EncryptionAccess {
+	return &EncryptionAccess{root: &root}
+}
+
+// Restrict derives a new encryption access containing only the keys for the
+// given bucket and path prefix pairs. The keys cannot be used to derive the
+// keys for any path outside of the prefixes. It returns an error if any of
+// the prefixes is not accessible with the encryption access.
+func (a *EncryptionAccess) Restrict(pathCipher storj.Cipher, restrictions ...EncryptionRestriction) (*EncryptionAccess, error) {
+	restricted := &EncryptionAccess{}
+	for _, restriction := range restrictions {
+		if restriction.Bucket == "" {
+			return nil, storj.ErrNoBucket.New("")
+		}
+
+		key, err := a.derive(pathCipher, restriction)
+		if err != nil {
+			return nil, err
+		}
+		restricted.keys = append(restricted.keys, key)
+	}
+	restricted.normalize()
+	return restricted, nil
+}
+
+// PathKey returns the key for the path within the bucket. The path must be
+// covered by the encryption access.
+func (a *EncryptionAccess) PathKey(bucket string, path storj.Path) (*storj.Key, error) {
+	key, err := a.derive(storj.Unencrypted, EncryptionRestriction{Bucket: bucket, PathPrefix: path})
+	if err != nil {
+		return nil, err
+	}
+	return &key.Key, nil
+}
+
+// EncryptedPathPrefix returns the encrypted form of the path prefix of the
+// given restriction. The prefix must be covered by the encryption access.
+func (a *EncryptionAccess) EncryptedPathPrefix(pathCipher storj.Cipher, restriction EncryptionRestriction) (storj.Path, error) {
+	key, err := a.derive(pathCipher, restriction)
+	if err != nil {
+		return "", err
+	}
+	return key.EncPathPrefix, nil
+}
+
+// Caveats returns the restrictions of the encryption access as caveats, so
+// the Macaroon shared alongside it can be restricted to the same buckets
+// and prefixes. An encryption access with the root key has no caveats.
+func (a *EncryptionAccess) Caveats() []Caveat {
+	if a.root != nil {
+		return nil
+	}
+	caveats := make([]Caveat, 0, len(a.keys))
+	for _, key := range a.keys {
+		caveats = append(caveats, key.EncryptionRestriction)
+	}
+	return caveats
+}
+
+// derive derives the key for the restriction from the most specific key
+// that covers it.
+func (a *EncryptionAccess) derive(pathCipher storj.Cipher, restriction EncryptionRestriction) (restrictedKey, error) {
+	var base *restrictedKey
+	for i := range a.keys {
+		key := &a.keys[i]
+		if key.Bucket != restriction.Bucket || !hasPathPrefix(restriction.PathPrefix, key.PathPrefix) {
+			continue
+		}
+		if base == nil || len(key.PathPrefix) > len(base.PathPrefix) {
+			base = key
+		}
+	}
+
+	if base == nil {
+		if a.root == nil {
+			return restrictedKey{}, Error.New("no access to %q in bucket %q", restriction.PathPrefix, restriction.Bucket)
+		}
+
+		bucketKey, err := encryption.DeriveKey(a.root, "path:"+restriction.Bucket)
+		if err != nil {
+			return restrictedKey{}, Error.Wrap(err)
+		}
+		base = &restrictedKey{
+			EncryptionRestriction: EncryptionRestriction{Bucket: restriction.Bucket},
+			Key:                   *bucketKey,
+		}
+	}
+
+	rest := strings.TrimPrefix(strings.TrimPrefix(restriction.PathPrefix, base.PathPrefix), "/")
+	if rest == "" {
+		derived := *base
+		derived.PathPrefix = restriction.PathPrefix
+		return derived, nil
+	}
+
+	encRest, err := encryption.EncryptPath(rest, pathCipher, &base.Key)
+	if err != nil {
+		return restrictedKey{}, Error.Wrap(err)
+	}
+
+	key, err := encryption.DerivePathKey(rest, &base.Key, len(storj.SplitPath(rest)))
+	if err != nil {
+		return restrictedKey{}, Error.Wrap(err)
+	}
+
+	encPathPrefix := encRest
+	if base.EncPathPrefix != "" {
+		encPathPrefix = storj.JoinPaths(base.EncPathPrefix, encRest)
+	}
+
+	return restrictedKey{
+		EncryptionRestriction: restriction,
+		EncPathPrefix:         encPathPrefix,
+		Key:                   *key,
+	}, nil
+}
+
+// normalize sorts the keys and removes the ones covered by other keys
+func (a *EncryptionAccess) normalize() {
+	sort.Slice(a.keys, func(i, k int) bool {
+		if a.keys[i].Bucket != a.keys[k].Bucket {
+			return a.keys[i].Bucket < a.keys[k].Bucket
+		}
+		return a.keys[i].PathPrefix < a.keys[k].PathPrefix
+	})
+
+	keys := a.keys[:0]
+	for _, key := range a.keys {
+		if n := len(keys); n > 0 {
+			last := keys[n-1]
+			if last.Bucket == key.Bucket && hasPathPrefix(key.PathPrefix, last.PathPrefix) {
+				continue
+			}
+		}
+		keys = append(keys, key)
+	}
+	a.keys = keys
+}
+
+// MergeEncryptionAccess merges the encryption accesses into one that gives
+// access to everything accessible with any of them. Keys of restricted
+// accesses are dropped when they are covered by a root key or by the key of
+// a broader prefix.
+func MergeEncryptionAccess(accesses ...*EncryptionAccess) (*EncryptionAccess, error) {
+	merged := &EncryptionAccess{}
+	seen := map[EncryptionRestriction]storj.Key{}
+	for _, access := range accesses {
+		if access.root != nil {
+			if merged.root != nil && *merged.root != *access.root {
+				return nil, Error.New("cannot merge encryption accesses with different root keys")
+			}
+			merged.root = access.root
+		}
+		for _, key := range access.keys {
+			if existing, ok := seen[key.EncryptionRestriction]; ok && existing != key.Key {
+				return nil, Error.New("cannot merge different keys for %q in bucket %q", key.PathPrefix, key.Bucket)
+			}
+			seen[key.EncryptionRestriction] = key.Key
+		}
+		merged.keys = append(merged.keys, access.keys...)
+	}
+
+	if merged.root != nil {
+		merged.keys = nil
+		return merged, nil
+	}
+
+	merged.normalize()
+	return merged, nil
+}
+
+// serializedEncryptionAccess is the serialized form of EncryptionAccess
+type serializedEncryptionAccess struct {
+	Root []byte          `json:",omitempty"`
+	Keys []restrictedKey `json:",omitempty"`
+}
+
+// Serialize serializes the encryption access, so it can be shared
+func (a *EncryptionAccess) Serialize() ([]byte, error) {
+	serialized := serializedEncryptionAccess{Keys: a.keys}
+	if a.root != nil {
+		serialized.Root = a.root[:]
+	}
+
+	data, err := json.Marshal(serialized)
+	return data, Error.Wrap(err)
+}
+
+// ParseEncryptionAccess parses a serialized encryption access
+func ParseEncryptionAccess(data []byte) (*EncryptionAccess, error) {
+	var serialized serializedEncryptionAccess
+	if err := json.Unmarshal(data, &serialized); err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	access := &EncryptionAccess{keys: serialized.Keys}
+	if serialized.Root != nil {
+		if len(serialized.Root) != storj.KeySize {
+			return nil, Error.New("invalid root key size %d", len(serialized.Root))
+		}
+		access.root = new(storj.Key)
+		copy(access.root[:], serialized.Root)
+	}
+	access.normalize()
+	return access, nil
+}
+
+// hasPathPrefix returns whether path is equal to prefix or is within it
+func hasPathPrefix(path, prefix storj.Path) bool {
+	if prefix == "" || path == prefix {
+		return true
+	}
+	return strings.HasPrefix(path, prefix+"/")
+}
@@ -0,0 +1,107 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/pkg/encryption"
+	"storj.io/storj/pkg/storage/streams"
+	"storj.io/storj/pkg/storj"
+)
+
+func TestEncryptionAccessRestrict(t *testing.T) {
+	root := storj.Key{1, 2, 3}
+	access := NewEncryptionAccess(root)
+
+	restricted, err := access.Restrict(storj.AESGCM,
+		EncryptionRestriction{Bucket: "bucket", PathPrefix: "a/b"},
+		EncryptionRestriction{Bucket: "other"},
+	)
+	require.NoError(t, err)
+
+	for _, path := range []storj.Path{"bucket/a/b", "bucket/a/b/c", "bucket/a/b/c/d", "other/x"} {
+		comps := storj.SplitPath(path)
+		expected, err := encryption.DerivePathKey(path, &root, len(comps))
+		require.NoError(t, err)
+
+		key, err := restricted.PathKey(comps[0], storj.JoinPaths(comps[1:]...))
+		require.NoError(t, err, path)
+		assert.Equal(t, expected, key, path)
+	}
+
+	encPrefix, err := restricted.EncryptedPathPrefix(storj.AESGCM, EncryptionRestriction{Bucket: "bucket", PathPrefix: "a/b/c"})
+	require.NoError(t, err)
+	expected, err := streams.EncryptAfterBucket("bucket/a/b/c", storj.AESGCM, &root)
+	require.NoError(t, err)
+	assert.Equal(t, expected, storj.JoinPaths("bucket", encPrefix))
+
+	for _, restriction := range []EncryptionRestriction{
+		{Bucket: "bucket"},
+		{Bucket: "bucket", PathPrefix: "a"},
+		{Bucket: "bucket", PathPrefix: "a/bc"},
+		{Bucket: "third"},
+	} {
+		_, err := restricted.PathKey(restriction.Bucket, restriction.PathPrefix)
+		assert.Error(t, err, restriction)
+
+		_, err = restricted.Restrict(storj.AESGCM, restriction)
+		assert.Error(t, err, restriction)
+	}
+
+	assert.Equal(t, []Caveat{
+		EncryptionRestriction{Bucket: "bucket", PathPrefix: "a/b"},
+		EncryptionRestriction{Bucket: "other"},
+	}, restricted.Caveats())
+	assert.Empty(t, access.Caveats())
+}
+
+func TestEncryptionAccessSerialize(t *testing.T) {
+	access := NewEncryptionAccess(storj.Key{1})
+	restricted, err := access.Restrict(storj.SecretBox, EncryptionRestriction{Bucket: "bucket", PathPrefix: "a"})
+	require.NoError(t, err)
+
+	for _, access := range []*EncryptionAccess{access, restricted} {
+		data, err := access.Serialize()
+		require.NoError(t, err)
+
+		parsed, err := ParseEncryptionAccess(data)
+		require.NoError(t, err)
+		assert.Equal(t, access, parsed)
+	}
+
+	_, err = ParseEncryptionAccess([]byte(`{"Root":"AQI="}`))
+	assert.Error(t, err)
+}
+
+func TestMergeEncryptionAccess(t *testing.T) {
+	access := NewEncryptionAccess(storj.Key{1})
+
+	first, err := access.Restrict(storj.AESGCM, EncryptionRestriction{Bucket: "bucket", PathPrefix: "a/b"})
+	require.NoError(t, err)
+	second, err := access.Restrict(storj.AESGCM,
+		EncryptionRestriction{Bucket: "bucket", PathPrefix: "a"},
+		EncryptionRestriction{Bucket: "other", PathPrefix: "x"},
+	)
+	require.NoError(t, err)
+
+	merged, err := MergeEncryptionAccess(first, second)
+	require.NoError(t, err)
+	assert.Equal(t, second, merged)
+
+	merged, err = MergeEncryptionAccess(first, access)
+	require.NoError(t, err)
+	assert.Equal(t, access, merged)
+
+	_, err = MergeEncryptionAccess(access, NewEncryptionAccess(storj.Key{2}))
+	assert.Error(t, err)
+
+	other, err := NewEncryptionAccess(storj.Key{2}).Restrict(storj.AESGCM, EncryptionRestriction{Bucket: "bucket", PathPrefix: "a"})
+	require.NoError(t, err)
+	_, err = MergeEncryptionAccess(second, other)
+	assert.Error(t, err)
+}
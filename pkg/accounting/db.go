@@ -52,6 +52,8 @@ type DB interface {
 	SaveRollup(ctx context.Context, latestTally time.Time, stats RollupStats) error
 	// QueryPaymentInfo queries Overlay, Accounting Rollup on nodeID
 	QueryPaymentInfo(ctx context.Context, start time.Time, end time.Time) ([]*CSVRow, error)
+	// QueryNodePaymentInfo queries Overlay, Accounting Rollup of a single node, it returns nil when the node doesn't exist
+	QueryNodePaymentInfo(ctx context.Context, nodeID storj.NodeID, start time.Time, end time.Time) (*CSVRow, error)
 	// DeleteRawBefore deletes all raw tallies prior to some time
 	DeleteRawBefore(ctx context.Context, latestRollup time.Time) error
 }
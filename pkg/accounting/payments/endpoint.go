@@ -0,0 +1,105 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package payments
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/pkg/accounting"
+	"storj.io/storj/pkg/identity"
+	"storj.io/storj/pkg/pb"
+)
+
+var mon = monkit.Package()
+
+// Endpoint serves the storage nodes their monthly payouts
+type Endpoint struct {
+	log    *zap.Logger
+	db     accounting.DB
+	config Config
+}
+
+// NewEndpoint creates a new payouts endpoint
+func NewEndpoint(log *zap.Logger, db accounting.DB, config Config) *Endpoint {
+	return &Endpoint{
+		log:    log,
+		db:     db,
+		config: config,
+	}
+}
+
+// History returns the payouts of the calling storage node for every month
+// since the requested one, including the current month so far
+func (endpoint *Endpoint) History(ctx context.Context, req *pb.PayoutHistoryRequest) (_ *pb.PayoutHistoryResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	peer, err := identity.PeerIdentityFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	var since time.Time
+	if req.Since != nil {
+		since, err = ptypes.Timestamp(req.Since)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	now := time.Now().UTC()
+	current := monthStart(now)
+
+	payouts := []*pb.Payout{}
+	for start := current; !start.Before(monthStart(since)); start = start.AddDate(0, -1, 0) {
+		row, err := endpoint.db.QueryNodePaymentInfo(ctx, peer.ID, start, start.AddDate(0, 1, 0))
+		if err != nil {
+			endpoint.log.Error("failed to query payment info", zap.Stringer("node", peer.ID), zap.Error(err))
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		if row == nil {
+			return nil, status.Error(codes.NotFound, "unknown node")
+		}
+		if start.Before(monthStart(row.NodeCreationDate)) {
+			break
+		}
+
+		payment, err := Compute(endpoint.config, row, start)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		period, err := ptypes.TimestampProto(start)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+
+		payouts = append(payouts, &pb.Payout{
+			Period:       period,
+			NodeMonth:    int32(payment.Month),
+			SurgePercent: payment.SurgePercent,
+			HeldPercent:  payment.HeldPercent,
+			Gross:        payment.Gross,
+			Held:         payment.Held,
+			Paid:         payment.Owed,
+		})
+	}
+
+	// oldest month first
+	for i, j := 0, len(payouts)-1; i < j; i, j = i+1, j-1 {
+		payouts[i], payouts[j] = payouts[j], payouts[i]
+	}
+	return &pb.PayoutHistoryResponse{Payouts: payouts}, nil
+}
+
+// monthStart returns the start of the month of t in UTC
+func monthStart(t time.Time) time.Time {
+	year, month, _ := t.UTC().Date()
+	return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,120 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package payments
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/accounting"
+)
+
+// Error is the default payments errs class
+var Error = errs.Class("payments error")
+
+const (
+	// hoursPerMonth is the number of hours in a payment month
+	hoursPerMonth = 720
+	// bytesPerTB is the number of bytes in a TB
+	bytesPerTB = 1e12
+	// microDollars is the number of micro dollars in a dollar
+	microDollars = 1e6
+)
+
+// heldPercents is the percentage of the payment held back, by month of node
+// operation; nodes older than the schedule have nothing held back
+var heldPercents = []int64{75, 75, 75, 50, 50, 50, 25, 25, 25}
+
+// Config contains the rates used to compute the payments of nodes
+type Config struct {
+	AtRestPrice       float64 `help:"price paid to nodes in dollars per TB-month of data at rest" default:"1.5"`
+	EgressPrice       float64 `help:"price paid to nodes in dollars per TB of GET bandwidth" default:"20"`
+	RepairEgressPrice float64 `help:"price paid to nodes in dollars per TB of repair GET bandwidth" default:"10"`
+	AuditEgressPrice  float64 `help:"price paid to nodes in dollars per TB of audit bandwidth" default:"10"`
+	SurgePercent      int64   `help:"percentage all the payments are multiplied by" default:"100"`
+}
+
+// Payment is the amount owed to a node for a period, in micro dollars
+type Payment struct {
+	accounting.CSVRow
+
+	// Month is the month of operation of the node during the period, starting at 1
+	Month        int
+	SurgePercent int64
+	HeldPercent  int64
+
+	Gross int64
+	Held  int64
+	Owed  int64
+}
+
+// Period parses a month period formatted as YYYY-MM and returns its start and end
+func Period(period string) (start, end time.Time, err error) {
+	start, err = time.Parse("2006-01", period)
+	if err != nil {
+		return time.Time{}, time.Time{}, Error.New("invalid period %q, use YYYY-MM", period)
+	}
+	return start, start.AddDate(0, 1, 0), nil
+}
+
+// Compute computes the payment of a node for the period starting at start
+func Compute(config Config, row *accounting.CSVRow, start time.Time) (Payment, error) {
+	if config.SurgePercent < 0 {
+		return Payment{}, Error.New("invalid surge percent %d", config.SurgePercent)
+	}
+
+	payment := Payment{
+		CSVRow:       *row,
+		Month:        monthOfOperation(row.NodeCreationDate, start),
+		SurgePercent: config.SurgePercent,
+	}
+	payment.HeldPercent = heldPercent(payment.Month)
+
+	payment.Gross = Gross(config, row)
+	payment.Held = payment.Gross * payment.HeldPercent / 100
+	payment.Owed = payment.Gross - payment.Held
+	return payment, nil
+}
+
+// Gross returns the amount owed for the usage in row, in micro dollars, before
+// anything is held back
+func Gross(config Config, row *accounting.CSVRow) int64 {
+	dollars := row.AtRestTotal/hoursPerMonth/bytesPerTB*config.AtRestPrice +
+		float64(row.GetTotal)/bytesPerTB*config.EgressPrice +
+		float64(row.GetRepairTotal)/bytesPerTB*config.RepairEgressPrice +
+		float64(row.GetAuditTotal)/bytesPerTB*config.AuditEgressPrice
+
+	return int64(math.Round(dollars*microDollars)) * config.SurgePercent / 100
+}
+
+// FormatDollars formats an amount of micro dollars as dollars
+func FormatDollars(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	return fmt.Sprintf("%s%d.%06d", sign, amount/microDollars, amount%microDollars)
+}
+
+// monthOfOperation returns the month of operation, starting at 1, of a node
+// created at created during the period starting at start
+func monthOfOperation(created, start time.Time) int {
+	created, start = created.UTC(), start.UTC()
+	months := (start.Year()-created.Year())*12 + int(start.Month()-created.Month())
+	if months < 0 {
+		return 1
+	}
+	return months + 1
+}
+
+// heldPercent returns the percentage held back during a month of operation
+func heldPercent(month int) int64 {
+	if month > len(heldPercents) {
+		return 0
+	}
+	return heldPercents[month-1]
+}
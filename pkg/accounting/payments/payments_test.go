@@ -0,0 +1,83 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package payments_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/pkg/accounting"
+	"storj.io/storj/pkg/accounting/payments"
+)
+
+func TestPeriod(t *testing.T) {
+	start, end, err := payments.Period("2019-03")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2019, 4, 1, 0, 0, 0, 0, time.UTC), end)
+
+	for _, invalid := range []string{"", "2019", "2019-13", "2019-03-01"} {
+		_, _, err := payments.Period(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestCompute(t *testing.T) {
+	config := payments.Config{
+		AtRestPrice:       1.5,
+		EgressPrice:       20,
+		RepairEgressPrice: 10,
+		AuditEgressPrice:  10,
+		SurgePercent:      100,
+	}
+	start, _, err := payments.Period("2019-03")
+	require.NoError(t, err)
+
+	row := &accounting.CSVRow{
+		NodeCreationDate: time.Date(2019, 1, 15, 0, 0, 0, 0, time.UTC),
+		AtRestTotal:      720e12, // 1 TB-month
+		GetTotal:         1e12,
+		GetRepairTotal:   5e11,
+		GetAuditTotal:    1e11,
+		Wallet:           "0x0123",
+	}
+
+	for _, tt := range []struct {
+		created time.Time
+		surge   int64
+		month   int
+		held    int64
+		gross   int64
+	}{
+		{created: time.Date(2019, 3, 20, 0, 0, 0, 0, time.UTC), surge: 100, month: 1, held: 75, gross: 27500000},
+		{created: time.Date(2019, 1, 15, 0, 0, 0, 0, time.UTC), surge: 100, month: 3, held: 75, gross: 27500000},
+		{created: time.Date(2018, 12, 1, 0, 0, 0, 0, time.UTC), surge: 200, month: 4, held: 50, gross: 55000000},
+		{created: time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC), surge: 150, month: 13, held: 0, gross: 41250000},
+	} {
+		config.SurgePercent = tt.surge
+		row.NodeCreationDate = tt.created
+
+		payment, err := payments.Compute(config, row, start)
+		require.NoError(t, err)
+		assert.Equal(t, tt.month, payment.Month)
+		assert.Equal(t, tt.held, payment.HeldPercent)
+		assert.Equal(t, tt.gross, payment.Gross)
+		assert.Equal(t, tt.gross*tt.held/100, payment.Held)
+		assert.Equal(t, payment.Gross-payment.Held, payment.Owed)
+		assert.Equal(t, "0x0123", payment.Wallet)
+	}
+
+	config.SurgePercent = -1
+	_, err = payments.Compute(config, row, start)
+	assert.Error(t, err)
+}
+
+func TestFormatDollars(t *testing.T) {
+	assert.Equal(t, "0.000000", payments.FormatDollars(0))
+	assert.Equal(t, "27.500000", payments.FormatDollars(27500000))
+	assert.Equal(t, "-0.000001", payments.FormatDollars(-1))
+}
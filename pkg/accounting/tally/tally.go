@@ -122,7 +122,7 @@ func (t *Tally) calculateAtRestData(ctx context.Context) (latestTally time.Time,
 	var bucketCount int64
 	var totalStats, currentBucketStats stats
 
-	err = t.pointerdb.Iterate("", "", true, false,
+	err = t.pointerdb.IterateSnapshot("", "", true, false,
 		func(it storage.Iterator) error {
 			var item storage.ListItem
 			for it.Next(&item) {
@@ -0,0 +1,127 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package audit
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/internal/sync2"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/satellite/metainfo"
+)
+
+// Chore populates the audit queue of the Cursor with the segments sampled
+// from every node during a metainfo loop.
+type Chore struct {
+	log           *zap.Logger
+	rand          *rand.Rand
+	slots         int
+	unvettedSlots int
+	overlay       *overlay.Cache
+	cursor        *Cursor
+
+	history          HistoryDB
+	historyRetention time.Duration
+	stats            StatsDB
+
+	metainfoLoop *metainfo.Loop
+
+	Loop sync2.Cycle
+}
+
+// NewChore instantiates a Chore that fills cursor, it also removes the
+// audit records older than the retention from history and stores the number
+// of remote segments in stats. The unvetted nodes get enough slots to be
+// audited at the minimum rate between two refills.
+func NewChore(log *zap.Logger, cursor *Cursor, history HistoryDB, stats StatsDB, overlay *overlay.Cache, metainfoLoop *metainfo.Loop, config Config) *Chore {
+	unvettedSlots := int(math.Ceil(config.MinUnvettedAuditsPerHour * config.ChoreInterval.Hours()))
+	if unvettedSlots < config.Slots {
+		unvettedSlots = config.Slots
+	}
+
+	return &Chore{
+		log:           log,
+		rand:          rand.New(rand.NewSource(time.Now().Unix())),
+		slots:         config.Slots,
+		unvettedSlots: unvettedSlots,
+		overlay:       overlay,
+		cursor:        cursor,
+
+		history:          history,
+		historyRetention: config.HistoryRetention,
+		stats:            stats,
+
+		metainfoLoop: metainfoLoop,
+
+		Loop: *sync2.NewCycle(config.ChoreInterval),
+	}
+}
+
+// Run starts the chore.
+func (chore *Chore) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	// continue with the queue of the previous run instead of sampling anew
+	restored, err := chore.cursor.Restore(ctx)
+	if err != nil {
+		chore.log.Error("error restoring the audit queue", zap.Error(err))
+	}
+	if restored > 0 {
+		chore.log.Info("continuing with the restored audit queue", zap.Int("segments", restored))
+	}
+
+	return chore.Loop.Run(ctx, func(ctx context.Context) (err error) {
+		defer mon.Task()(&ctx)(&err)
+
+		if chore.historyRetention > 0 {
+			deleted, err := chore.history.DeleteBefore(ctx, time.Now().Add(-chore.historyRetention))
+			if err != nil {
+				chore.log.Error("error deleting old audit records", zap.Error(err))
+			}
+			mon.IntVal("audit_history_deleted").Observe(deleted)
+		}
+
+		if restored > 0 {
+			restored = 0
+			return nil
+		}
+
+		collector := NewPathCollector(chore.slots, chore.unvettedSlots, chore.overlay, chore.rand)
+		err = chore.metainfoLoop.Join(ctx, collector)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			chore.log.Error("error joining metainfoloop", zap.Error(err))
+			return nil
+		}
+
+		// the audit report estimates the coverage from the number of segments
+		err = chore.stats.RecordTotalSegments(ctx, time.Now(), collector.Segments)
+		if err != nil {
+			chore.log.Error("error storing the number of segments", zap.Error(err))
+		}
+
+		paths := collector.Paths()
+		err = chore.cursor.Swap(ctx, paths)
+		if err != nil {
+			chore.log.Error("error storing the audit queue", zap.Error(err))
+			return nil
+		}
+		mon.IntVal("audit_queue_length").Observe(int64(len(paths)))
+		mon.IntVal("audit_unvetted_nodes").Observe(int64(len(collector.Unvetted)))
+		return nil
+	})
+}
+
+// Close closes the chore.
+func (chore *Chore) Close() error {
+	chore.Loop.Close()
+	return nil
+}
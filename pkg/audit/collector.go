@@ -0,0 +1,118 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package audit
+
+import (
+	"context"
+	"math/rand"
+
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+)
+
+// PathCollector uses the metainfo loop to add paths to node reservoirs.
+//
+// The nodes that aren't vetted yet get a bigger reservoir, and their paths
+// are audited first, so that they are audited at a minimum rate and their
+// vetting completes in bounded time. Segments counts the remote segments seen.
+type PathCollector struct {
+	Reservoirs    map[storj.NodeID]*Reservoir
+	Unvetted      map[storj.NodeID]bool
+	Segments      int64
+	slotCount     int
+	unvettedSlots int
+	overlay       *overlay.Cache
+	rand          *rand.Rand
+}
+
+// NewPathCollector instantiates a path collector. The vetting of the nodes is
+// checked with overlay, a nil overlay treats every node as vetted.
+func NewPathCollector(reservoirSlots, unvettedSlots int, overlay *overlay.Cache, r *rand.Rand) *PathCollector {
+	return &PathCollector{
+		Reservoirs:    make(map[storj.NodeID]*Reservoir),
+		Unvetted:      make(map[storj.NodeID]bool),
+		slotCount:     reservoirSlots,
+		unvettedSlots: unvettedSlots,
+		overlay:       overlay,
+		rand:          r,
+	}
+}
+
+// RemoteSegment takes a remote segment found in metainfo and creates a reservoir for it if it doesn't exist already.
+func (collector *PathCollector) RemoteSegment(ctx context.Context, path storj.Path, pointer *pb.Pointer) (err error) {
+	collector.Segments++
+	for _, piece := range pointer.GetRemote().GetRemotePieces() {
+		reservoir, ok := collector.Reservoirs[piece.NodeId]
+		if !ok {
+			reservoir, err = collector.newReservoir(ctx, piece.NodeId)
+			if err != nil {
+				return err
+			}
+			collector.Reservoirs[piece.NodeId] = reservoir
+		}
+		reservoir.Sample(collector.rand, path)
+	}
+	return nil
+}
+
+// newReservoir creates the reservoir of a node, sized by its vetting.
+func (collector *PathCollector) newReservoir(ctx context.Context, nodeID storj.NodeID) (*Reservoir, error) {
+	if collector.overlay == nil || collector.unvettedSlots <= collector.slotCount {
+		return NewReservoir(collector.slotCount), nil
+	}
+
+	vetted, err := collector.overlay.IsVetted(ctx, nodeID)
+	if err != nil {
+		// the node left the network, there's no vetting to complete
+		if overlay.ErrNodeNotFound.Has(err) {
+			return NewReservoir(collector.slotCount), nil
+		}
+		return nil, err
+	}
+	if vetted {
+		return NewReservoir(collector.slotCount), nil
+	}
+
+	collector.Unvetted[nodeID] = true
+	return NewReservoir(collector.unvettedSlots), nil
+}
+
+// RemoteObject returns nil because the audit service does not interact with remote objects.
+func (collector *PathCollector) RemoteObject(ctx context.Context, path storj.Path, pointer *pb.Pointer) (err error) {
+	return nil
+}
+
+// InlineSegment returns nil because we're only auditing for storage nodes for now.
+func (collector *PathCollector) InlineSegment(ctx context.Context, path storj.Path, pointer *pb.Pointer) (err error) {
+	return nil
+}
+
+// Paths returns the sampled paths without duplicates. The paths of the
+// unvetted nodes come first, and the first slot of every reservoir is taken
+// before the second one, so that every node gets audited before any node is
+// audited twice.
+func (collector *PathCollector) Paths() []storj.Path {
+	var paths []storj.Path
+	seen := make(map[storj.Path]struct{})
+	add := func(unvetted bool, slots int) {
+		for slot := 0; slot < slots; slot++ {
+			for nodeID, reservoir := range collector.Reservoirs {
+				if collector.Unvetted[nodeID] != unvetted || slot >= len(reservoir.Paths) {
+					continue
+				}
+				path := reservoir.Paths[slot]
+				if _, ok := seen[path]; ok {
+					continue
+				}
+				seen[path] = struct{}{}
+				paths = append(paths, path)
+			}
+		}
+	}
+
+	add(true, collector.unvettedSlots)
+	add(false, collector.slotCount)
+	return paths
+}
@@ -0,0 +1,43 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package audit
+
+import (
+	"context"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/storj"
+)
+
+var (
+	// ErrContainedNotFound is the errs class for when a pending audit isn't found
+	ErrContainedNotFound = errs.Class("pending audit not found")
+)
+
+// PendingAudit contains the share that a contained node has to deliver to
+// leave containment.
+type PendingAudit struct {
+	NodeID            storj.NodeID
+	PieceID           storj.PieceID
+	StripeIndex       int64
+	ShareSize         int32
+	ExpectedShareHash []byte
+	ReverifyCount     int32
+	// Path is the path of the segment of the share.
+	Path storj.Path
+}
+
+// Containment holds the pending audits of the nodes that didn't deliver an
+// audited share in time.
+type Containment interface {
+	// Get returns the pending audit of the node.
+	Get(ctx context.Context, nodeID storj.NodeID) (*PendingAudit, error)
+	// IncrementPending creates the pending audit of the node, or increments
+	// the reverify count of the pending audit the node already has.
+	IncrementPending(ctx context.Context, pendingAudit *PendingAudit) error
+	// Delete removes the node from containment, it returns whether the node
+	// was contained.
+	Delete(ctx context.Context, nodeID storj.NodeID) (bool, error)
+}
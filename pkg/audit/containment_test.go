@@ -0,0 +1,112 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package audit_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/internal/testrand"
+	"storj.io/storj/pkg/audit"
+	"storj.io/storj/pkg/pkcrypto"
+	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/satellitedb/satellitedbtest"
+)
+
+func TestContainment(t *testing.T) {
+	satellitedbtest.Run(t, func(t *testing.T, db satellite.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		containment := db.Containment()
+		random := testrand.New(t)
+
+		pending := &audit.PendingAudit{
+			NodeID:            random.NodeID(),
+			PieceID:           random.PieceID(),
+			StripeIndex:       3,
+			ShareSize:         256,
+			ExpectedShareHash: pkcrypto.SHA256Hash(random.Bytes(256)),
+			Path:              random.Path(4),
+		}
+
+		_, err := containment.Get(ctx, pending.NodeID)
+		require.True(t, audit.ErrContainedNotFound.Has(err))
+
+		require.NoError(t, containment.IncrementPending(ctx, pending))
+		stored, err := containment.Get(ctx, pending.NodeID)
+		require.NoError(t, err)
+		require.Equal(t, pending, stored)
+
+		// a contained node keeps the share it was contained for
+		other := *pending
+		other.StripeIndex = 5
+		other.ExpectedShareHash = pkcrypto.SHA256Hash(random.Bytes(256))
+		require.NoError(t, containment.IncrementPending(ctx, &other))
+		stored, err = containment.Get(ctx, pending.NodeID)
+		require.NoError(t, err)
+		require.Equal(t, pending.ExpectedShareHash, stored.ExpectedShareHash)
+		require.Equal(t, pending.StripeIndex, stored.StripeIndex)
+		require.Equal(t, int32(1), stored.ReverifyCount)
+
+		deleted, err := containment.Delete(ctx, pending.NodeID)
+		require.NoError(t, err)
+		require.True(t, deleted)
+
+		deleted, err = containment.Delete(ctx, pending.NodeID)
+		require.NoError(t, err)
+		require.False(t, deleted)
+
+		_, err = containment.Get(ctx, pending.NodeID)
+		require.True(t, audit.ErrContainedNotFound.Has(err))
+	})
+}
+
+func TestReportPendingAudits(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 1, UplinkCount: 0,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		nodeID := planet.StorageNodes[0].ID()
+		containment := satellite.DB.Containment()
+
+		reporter := audit.NewReporter(satellite.Overlay.Service, containment, 1, 2)
+		pending := &audit.PendingAudit{
+			NodeID:            nodeID,
+			PieceID:           testrand.New(t).PieceID(),
+			ShareSize:         256,
+			ExpectedShareHash: pkcrypto.SHA256Hash(nil),
+		}
+
+		before, err := satellite.Overlay.Service.Get(ctx, nodeID)
+		require.NoError(t, err)
+
+		// the node stays contained while it hasn't failed too many times
+		for i := 0; i < 2; i++ {
+			failed, err := reporter.RecordAudits(ctx, &audit.RecordAuditsInfo{PendingAudits: []*audit.PendingAudit{pending}})
+			require.NoError(t, err)
+			require.Nil(t, failed)
+
+			stored, err := containment.Get(ctx, nodeID)
+			require.NoError(t, err)
+			require.Equal(t, int32(i), stored.ReverifyCount)
+		}
+
+		// then it fails the audit and leaves containment
+		failed, err := reporter.RecordAudits(ctx, &audit.RecordAuditsInfo{PendingAudits: []*audit.PendingAudit{pending}})
+		require.NoError(t, err)
+		require.Nil(t, failed)
+
+		_, err = containment.Get(ctx, nodeID)
+		require.True(t, audit.ErrContainedNotFound.Has(err))
+
+		after, err := satellite.Overlay.Service.Get(ctx, nodeID)
+		require.NoError(t, err)
+		require.Equal(t, before.GetReputation().GetAuditCount()+1, after.GetReputation().GetAuditCount())
+		require.Equal(t, before.GetReputation().GetAuditSuccessCount(), after.GetReputation().GetAuditSuccessCount())
+	})
+}
@@ -15,8 +15,8 @@ import (
 	"storj.io/storj/pkg/eestream"
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/pointerdb"
-	"storj.io/storj/pkg/storage/meta"
 	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storage"
 )
 
 // Stripe keeps track of a stripe's index and its parent segment
@@ -26,66 +26,114 @@ type Stripe struct {
 	SegmentPath storj.Path
 }
 
-// Cursor keeps track of audit location in pointer db
+// Cursor keeps track of the segments that are waiting to be audited.
+//
+// The segments are sampled from every node by the Chore, so that the audits
+// cover the nodes in proportion to the data they store. The queue is stored
+// in the QueueDB as well, so that a restart doesn't lose it.
 type Cursor struct {
 	pointerdb *pointerdb.Service
-	lastPath  storj.Path
+	queueDB   QueueDB
 	mutex     sync.Mutex
+	queue     []storj.Path
 }
 
-// NewCursor creates a Cursor which iterates over pointer db
-func NewCursor(pointerdb *pointerdb.Service) *Cursor {
+// NewCursor creates a Cursor which gets its segments from pointer db
+func NewCursor(pointerdb *pointerdb.Service, queueDB QueueDB) *Cursor {
 	return &Cursor{
 		pointerdb: pointerdb,
+		queueDB:   queueDB,
 	}
 }
 
-// NextStripe returns a random stripe to be audited
-func (cursor *Cursor) NextStripe(ctx context.Context) (stripe *Stripe, err error) {
+// Restore loads the queue stored before a restart, it returns the number of
+// segments waiting to be audited.
+func (cursor *Cursor) Restore(ctx context.Context) (_ int, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	paths, err := cursor.queueDB.All(ctx)
+	if err != nil {
+		return 0, err
+	}
+
 	cursor.mutex.Lock()
 	defer cursor.mutex.Unlock()
 
-	var pointerItems []*pb.ListResponse_Item
-	var path storj.Path
-	var more bool
+	cursor.queue = paths
+	return len(paths), nil
+}
+
+// Swap replaces the segments waiting to be audited with paths. The queue is
+// stored before it replaces the one in memory, so the workers keep auditing
+// the previous queue meanwhile.
+func (cursor *Cursor) Swap(ctx context.Context, paths []storj.Path) (err error) {
+	defer mon.Task()(&ctx)(&err)
 
-	pointerItems, more, err = cursor.pointerdb.List("", cursor.lastPath, "", true, 0, meta.None)
+	err = cursor.queueDB.Swap(ctx, paths)
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	cursor.mutex.Lock()
+	defer cursor.mutex.Unlock()
+
+	cursor.queue = paths
+	return nil
+}
+
+// Len returns the number of segments waiting to be audited.
+func (cursor *Cursor) Len() int {
+	cursor.mutex.Lock()
+	defer cursor.mutex.Unlock()
+
+	return len(cursor.queue)
+}
+
+// next removes the next segment path from the queue.
+func (cursor *Cursor) next() (path storj.Path, ok bool) {
+	cursor.mutex.Lock()
+	defer cursor.mutex.Unlock()
+
+	if len(cursor.queue) == 0 {
+		return "", false
 	}
+	path = cursor.queue[0]
+	cursor.queue = cursor.queue[1:]
+	return path, true
+}
+
+// NextStripe returns a random stripe of the next segment to be audited. It
+// returns nil when there is no segment waiting or the segment can't be audited.
+func (cursor *Cursor) NextStripe(ctx context.Context) (stripe *Stripe, err error) {
+	defer mon.Task()(&ctx)(&err)
 
-	if len(pointerItems) == 0 {
+	path, ok := cursor.next()
+	if !ok {
 		return nil, nil
 	}
 
-	pointerItem, err := getRandomPointer(pointerItems)
+	err = cursor.queueDB.Remove(ctx, path)
 	if err != nil {
 		return nil, err
 	}
 
-	path = pointerItem.Path
-
-	// keep track of last path listed
-	if !more {
-		cursor.lastPath = ""
-	} else {
-		cursor.lastPath = pointerItems[len(pointerItems)-1].Path
-	}
-
-	// get pointer info
 	pointer, err := cursor.pointerdb.Get(path)
 	if err != nil {
+		// the segment was deleted after it was sampled
+		if storage.ErrKeyNotFound.Has(err) {
+			return nil, nil
+		}
 		return nil, err
 	}
 
-	//delete expired items rather than auditing them
+	// expired segments aren't audited, the expired deletion chore deletes them
 	if expiration := pointer.GetExpirationDate(); expiration != nil {
 		t, err := ptypes.Timestamp(expiration)
 		if err != nil {
 			return nil, err
 		}
 		if t.Before(time.Now()) {
-			return nil, cursor.pointerdb.Delete(path)
+			return nil, nil
 		}
 	}
 
@@ -127,12 +175,3 @@ func getRandomStripe(pointer *pb.Pointer) (index int64, err error) {
 
 	return randomStripeIndex.Int64(), nil
 }
-
-func getRandomPointer(pointerItems []*pb.ListResponse_Item) (pointer *pb.ListResponse_Item, err error) {
-	randomNum, err := rand.Int(rand.Reader, big.NewInt(int64(len(pointerItems))))
-	if err != nil {
-		return &pb.ListResponse_Item{}, err
-	}
-
-	return pointerItems[randomNum.Int64()], nil
-}
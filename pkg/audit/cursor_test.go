@@ -4,16 +4,13 @@
 package audit_test
 
 import (
-	"crypto/rand"
-	"math"
-	"math/big"
-	"reflect"
 	"testing"
 	"time"
 
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 
 	"storj.io/storj/internal/testcontext"
 	"storj.io/storj/internal/testplanet"
@@ -23,122 +20,59 @@ import (
 	"storj.io/storj/pkg/pointerdb"
 	"storj.io/storj/pkg/storage/meta"
 	"storj.io/storj/pkg/storj"
+	"storj.io/storj/satellite"
 )
 
 func TestAuditSegment(t *testing.T) {
 	testplanet.Run(t, testplanet.Config{
 		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
 	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
-		type pathCount struct {
-			path  storj.Path
-			count int
+		// populate pointerdb with 10 non-expired pointers of test data
+		tests, cursor, _ := populateTestData(t, planet, &timestamp.Timestamp{Seconds: time.Now().Unix() + 3000})
+		require.Equal(t, len(tests), cursor.Len())
+
+		// every segment is audited once, as every one is on a different node
+		audited := map[storj.Path]bool{}
+		for range tests {
+			stripe, err := cursor.NextStripe(ctx)
+			require.NoError(t, err)
+			require.NotNil(t, stripe)
+			require.False(t, audited[stripe.SegmentPath])
+			audited[stripe.SegmentPath] = true
+		}
+		for _, tt := range tests {
+			assert.True(t, audited[tt.path], tt.path)
 		}
 
-		// note: to simulate better,
-		// change limit in library to 5 in
-		// list api call, default is  0 == 1000 listing
-		//populate pointerdb with 10 non-expired pointers of test data
-		tests, cursor, pointerdb := populateTestData(t, planet, &timestamp.Timestamp{Seconds: time.Now().Unix() + 3000})
-
-		t.Run("NextStripe", func(t *testing.T) {
-			for _, tt := range tests {
-				t.Run(tt.bm, func(t *testing.T) {
-					assert1 := assert.New(t)
-					stripe, err := cursor.NextStripe(ctx)
-					if err != nil {
-						assert1.Error(err)
-						assert1.Nil(stripe)
-					}
-					if stripe != nil {
-						assert1.Nil(err)
-					}
-				})
-			}
-		})
-
-		// test to see how random paths are
-		t.Run("probabilisticTest", func(t *testing.T) {
-			list, _, err := pointerdb.List("", "", "", true, 10, meta.None)
-			require.NoError(t, err)
-			require.Len(t, list, 10)
-
-			// get count of items picked at random
-			uniquePathCounted := []pathCount{}
-			pathCounter := []pathCount{}
-
-			// get a list of 100 paths generated from random
-			for i := 0; i < 100; i++ {
-				randomNum, err := rand.Int(rand.Reader, big.NewInt(int64(len(list))))
-				if err != nil {
-					t.Error("num error: failed to get num")
-				}
-				pointerItem := list[randomNum.Int64()]
-				path := pointerItem.Path
-				val := pathCount{path: path, count: 1}
-				pathCounter = append(pathCounter, val)
-			}
-
-			// get a count for paths in list
-			for _, pc := range pathCounter {
-				skip := false
-				for i, up := range uniquePathCounted {
-					if reflect.DeepEqual(pc.path, up.path) {
-						up.count = up.count + 1
-						uniquePathCounted[i] = up
-						skip = true
-						break
-					}
-				}
-				if !skip {
-					uniquePathCounted = append(uniquePathCounted, pc)
-				}
-			}
-
-			// Section: binomial test for randomness
-			n := float64(100) // events
-			p := float64(.10) // theoretical probability of getting  1/10 paths
-			m := n * p
-			s := math.Sqrt(m * (1 - p)) // binomial distribution
-
-			// if values fall outside of the critical values of test statistics (ie Z value)
-			// in a 2-tail test
-			// we can assume, 95% confidence, it's not sampling according to a 10% probability
-			for _, v := range uniquePathCounted {
-				z := (float64(v.count) - m) / s
-				if z <= -1.96 || z >= 1.96 {
-					t.Log(false)
-				} else {
-					t.Log(true)
-				}
-			}
-		})
+		stripe, err := cursor.NextStripe(ctx)
+		require.NoError(t, err)
+		require.Nil(t, stripe)
 	})
 }
 
-func TestDeleteExpired(t *testing.T) {
+func TestSkipExpired(t *testing.T) {
 	testplanet.Run(t, testplanet.Config{
 		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.ExpiredDeletion.Enabled = false
+			},
+		},
 	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
-		//populate pointerdb with 10 expired pointers of test data
+		// populate pointerdb with 10 expired pointers of test data
 		tests, cursor, pointerdb := populateTestData(t, planet, &timestamp.Timestamp{})
-		//make sure it they're in there
+
+		// the expired segments aren't audited
+		for range tests {
+			stripe, err := cursor.NextStripe(ctx)
+			require.NoError(t, err)
+			require.Nil(t, stripe)
+		}
+
+		// but they aren't deleted by the audit either
 		list, _, err := pointerdb.List("", "", "", true, 10, meta.None)
 		require.NoError(t, err)
 		require.Len(t, list, 10)
-		// make sure its all null and no errors
-		t.Run("NextStripe", func(t *testing.T) {
-			for _, tt := range tests {
-				t.Run(tt.bm, func(t *testing.T) {
-					stripe, err := cursor.NextStripe(ctx)
-					require.NoError(t, err)
-					require.Nil(t, stripe)
-				})
-			}
-		})
-		//make sure it they're not in there anymore
-		list, _, err = pointerdb.List("", "", "", true, 10, meta.None)
-		require.NoError(t, err)
-		require.Len(t, list, 0)
 	})
 }
 
@@ -160,8 +94,11 @@ func populateTestData(t *testing.T, planet *testplanet.Planet, expiration *times
 		{bm: "success-9", path: "Pictures/Animals/Dogs/dogs.png"},
 		{bm: "success-10", path: "Nada/ビデオ/😶"},
 	}
-	pointerdb := planet.Satellites[0].Metainfo.Service
-	cursor := audit.NewCursor(pointerdb)
+	satellite := planet.Satellites[0]
+	pointerdb := satellite.Metainfo.Service
+
+	// stop auditing the segments as soon as they are sampled
+	require.NoError(t, satellite.Audit.Service.Close())
 
 	// put 10 pointers in db with expirations
 	t.Run("putToDB", func(t *testing.T) {
@@ -172,14 +109,16 @@ func populateTestData(t *testing.T, planet *testplanet.Planet, expiration *times
 			})
 		}
 	})
-	return tests, cursor, pointerdb
+
+	satellite.Audit.Chore.Loop.TriggerWait()
+	return tests, satellite.Audit.Service.Cursor, pointerdb
 }
 
 func makePointer(path storj.Path, expiration *timestamp.Timestamp) *pb.Pointer {
 	var rps []*pb.RemotePiece
 	rps = append(rps, &pb.RemotePiece{
 		PieceNum: 1,
-		NodeId:   teststorj.NodeIDFromString("testId"),
+		NodeId:   teststorj.NodeIDFromString(path),
 	})
 	return &pb.Pointer{
 		ExpirationDate: expiration,
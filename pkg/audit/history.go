@@ -0,0 +1,99 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package audit
+
+import (
+	"context"
+	"time"
+
+	"storj.io/storj/pkg/storj"
+)
+
+// Outcome is the result of auditing a node for a stripe.
+type Outcome int
+
+const (
+	// OutcomeSuccess is a node that delivered the correct share.
+	OutcomeSuccess = Outcome(0)
+	// OutcomeFailure is a node that delivered a wrong share or none at all.
+	OutcomeFailure = Outcome(1)
+	// OutcomeOffline is a node that couldn't be reached.
+	OutcomeOffline = Outcome(2)
+	// OutcomeContained is a node that was too slow and has to deliver the share later.
+	OutcomeContained = Outcome(3)
+)
+
+// String returns the name of the outcome.
+func (outcome Outcome) String() string {
+	switch outcome {
+	case OutcomeSuccess:
+		return "success"
+	case OutcomeFailure:
+		return "failure"
+	case OutcomeOffline:
+		return "offline"
+	case OutcomeContained:
+		return "contained"
+	default:
+		return "unknown"
+	}
+}
+
+// Record is the outcome of auditing a node for a stripe of a segment.
+type Record struct {
+	SegmentPath storj.Path
+	StripeIndex int64
+	NodeID      storj.NodeID
+	Outcome     Outcome
+	// Reverify is set for the audits of the share a contained node owed.
+	Reverify  bool
+	CreatedAt time.Time
+}
+
+// HistoryDB stores the audit records.
+type HistoryDB interface {
+	// Insert stores the records, setting their creation time.
+	Insert(ctx context.Context, records []Record) error
+	// ByNode returns the most recent records of the node, newest first.
+	ByNode(ctx context.Context, nodeID storj.NodeID, limit int) ([]Record, error)
+	// BySegment returns the most recent records of the segment, newest first.
+	BySegment(ctx context.Context, path storj.Path, limit int) ([]Record, error)
+	// DeleteBefore removes the records created before the given time.
+	DeleteBefore(ctx context.Context, before time.Time) (deleted int64, err error)
+}
+
+// Records returns the audit records of the report for the stripe. The
+// outcomes of a reverification are recorded for the stripe of the pending
+// audit, which may belong to another segment.
+func (info *RecordAuditsInfo) Records(stripe *Stripe, reverify bool) []Record {
+	var records []Record
+	add := func(nodeID storj.NodeID, outcome Outcome) {
+		record := Record{
+			SegmentPath: stripe.SegmentPath,
+			StripeIndex: stripe.Index,
+			NodeID:      nodeID,
+			Outcome:     outcome,
+			Reverify:    reverify,
+		}
+		// NB: the pending audits created before their path was stored have none
+		if pending, ok := info.Reverified[nodeID]; ok && pending.Path != "" {
+			record.SegmentPath, record.StripeIndex = pending.Path, pending.StripeIndex
+		}
+		records = append(records, record)
+	}
+
+	for _, nodeID := range info.SuccessNodeIDs {
+		add(nodeID, OutcomeSuccess)
+	}
+	for _, nodeID := range info.FailNodeIDs {
+		add(nodeID, OutcomeFailure)
+	}
+	for _, nodeID := range info.OfflineNodeIDs {
+		add(nodeID, OutcomeOffline)
+	}
+	for _, pending := range info.PendingAudits {
+		add(pending.NodeID, OutcomeContained)
+	}
+	return records
+}
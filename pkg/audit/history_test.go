@@ -0,0 +1,96 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package audit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/teststorj"
+	"storj.io/storj/pkg/audit"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/satellitedb/satellitedbtest"
+)
+
+func TestHistoryDB(t *testing.T) {
+	satellitedbtest.Run(t, func(t *testing.T, db satellite.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		history := db.AuditHistory()
+		node1, node2 := teststorj.NodeIDFromString("node1"), teststorj.NodeIDFromString("node2")
+
+		records, err := history.ByNode(ctx, node1, 10)
+		require.NoError(t, err)
+		require.Empty(t, records)
+
+		require.NoError(t, history.Insert(ctx, []audit.Record{
+			{SegmentPath: "a", StripeIndex: 1, NodeID: node1, Outcome: audit.OutcomeSuccess},
+			{SegmentPath: "a", StripeIndex: 1, NodeID: node2, Outcome: audit.OutcomeOffline},
+		}))
+		require.NoError(t, history.Insert(ctx, []audit.Record{
+			{SegmentPath: "b", StripeIndex: 2, NodeID: node1, Outcome: audit.OutcomeContained, Reverify: true},
+		}))
+
+		records, err = history.ByNode(ctx, node1, 10)
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		// newest first
+		require.Equal(t, "b", records[0].SegmentPath)
+		require.Equal(t, int64(2), records[0].StripeIndex)
+		require.Equal(t, node1, records[0].NodeID)
+		require.Equal(t, audit.OutcomeContained, records[0].Outcome)
+		require.True(t, records[0].Reverify)
+		require.False(t, records[0].CreatedAt.IsZero())
+		require.Equal(t, "a", records[1].SegmentPath)
+		require.Equal(t, audit.OutcomeSuccess, records[1].Outcome)
+
+		records, err = history.ByNode(ctx, node1, 1)
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+
+		records, err = history.BySegment(ctx, "a", 10)
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		for _, record := range records {
+			require.Equal(t, "a", record.SegmentPath)
+		}
+
+		deleted, err := history.DeleteBefore(ctx, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, int64(0), deleted)
+
+		deleted, err = history.DeleteBefore(ctx, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, int64(3), deleted)
+
+		records, err = history.BySegment(ctx, "a", 10)
+		require.NoError(t, err)
+		require.Empty(t, records)
+	})
+}
+
+func TestRecordsReverified(t *testing.T) {
+	node1, node2 := teststorj.NodeIDFromString("node1"), teststorj.NodeIDFromString("node2")
+	stripe := &audit.Stripe{SegmentPath: "a", Index: 1}
+
+	info := &audit.RecordAuditsInfo{
+		SuccessNodeIDs: storj.NodeIDList{node1},
+		OfflineNodeIDs: storj.NodeIDList{node2},
+		Reverified: map[storj.NodeID]*audit.PendingAudit{
+			node1: {NodeID: node1, StripeIndex: 5, Path: "b"},
+			node2: {NodeID: node2, StripeIndex: 7},
+		},
+	}
+
+	// the outcomes are recorded for the stripe of the pending audit
+	records := info.Records(stripe, true)
+	require.Len(t, records, 2)
+	require.Equal(t, audit.Record{SegmentPath: "b", StripeIndex: 5, NodeID: node1, Outcome: audit.OutcomeSuccess, Reverify: true}, records[0])
+	require.Equal(t, audit.Record{SegmentPath: "a", StripeIndex: 1, NodeID: node2, Outcome: audit.OutcomeOffline, Reverify: true}, records[1])
+}
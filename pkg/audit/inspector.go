@@ -0,0 +1,141 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package audit
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+)
+
+const (
+	// defaultHistoryLimit is the number of records returned when the request doesn't set a limit.
+	defaultHistoryLimit = 100
+	// DefaultReportDays is the number of days reported when the request doesn't set them.
+	DefaultReportDays = 30
+)
+
+// Inspector is a gRPC service for inspecting the audit history and statistics
+type Inspector struct {
+	history      HistoryDB
+	dryRun       HistoryDB
+	stats        StatsDB
+	warningScore float64
+}
+
+// NewInspector creates an Inspector, the audit report lists the nodes whose
+// audit score is below warningScore unless the request sets another score
+func NewInspector(history, dryRun HistoryDB, stats StatsDB, warningScore float64) *Inspector {
+	return &Inspector{
+		history:      history,
+		dryRun:       dryRun,
+		stats:        stats,
+		warningScore: warningScore,
+	}
+}
+
+// AuditHistory returns the most recent audit records of a node or of a segment, either of the audits or of the dry run
+func (srv *Inspector) AuditHistory(ctx context.Context, req *pb.AuditHistoryRequest) (_ *pb.AuditHistoryResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultHistoryLimit
+	}
+
+	history := srv.history
+	if req.DryRun {
+		history = srv.dryRun
+	}
+
+	var records []Record
+	switch {
+	case !req.NodeId.IsZero():
+		records, err = history.ByNode(ctx, req.NodeId, limit)
+	case len(req.Path) > 0:
+		records, err = history.BySegment(ctx, storj.Path(req.Path), limit)
+	default:
+		return nil, status.Error(codes.InvalidArgument, "either node id or path is required")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	response := &pb.AuditHistoryResponse{}
+	for _, record := range records {
+		createdAt, err := ptypes.TimestampProto(record.CreatedAt)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		response.Records = append(response.Records, &pb.AuditRecord{
+			SegmentPath: []byte(record.SegmentPath),
+			StripeIndex: record.StripeIndex,
+			NodeId:      record.NodeID,
+			Outcome:     pb.AuditRecord_Outcome(record.Outcome),
+			Reverify:    record.Reverify,
+			CreatedAt:   createdAt,
+		})
+	}
+	return response, nil
+}
+
+// AuditReport returns the audits per day, the estimated coverage and the nodes nearing disqualification
+func (srv *Inspector) AuditReport(ctx context.Context, req *pb.AuditReportRequest) (_ *pb.AuditReportResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	days := int(req.Days)
+	if days <= 0 {
+		days = DefaultReportDays
+	}
+	warningScore := req.WarningScore
+	if warningScore <= 0 {
+		warningScore = srv.warningScore
+	}
+
+	report, err := BuildReport(ctx, srv.stats, days, warningScore)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	response := &pb.AuditReportResponse{
+		Outcomes:        convertOutcomes(report.Outcomes),
+		SegmentsAudited: report.SegmentsAudited,
+		TotalSegments:   report.TotalSegments,
+		Coverage:        report.Coverage,
+	}
+	for _, day := range report.Days {
+		intervalStart, err := ptypes.TimestampProto(day.IntervalStart)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		response.Days = append(response.Days, &pb.AuditDay{
+			IntervalStart:   intervalStart,
+			Outcomes:        convertOutcomes(day.Outcomes),
+			SegmentsAudited: day.SegmentsAudited,
+			TotalSegments:   day.TotalSegments,
+		})
+	}
+	for _, node := range report.NodesAtRisk {
+		response.NodesAtRisk = append(response.NodesAtRisk, &pb.NodeAuditScore{
+			NodeId:     node.NodeID,
+			AuditScore: node.AuditScore,
+		})
+	}
+	return response, nil
+}
+
+// convertOutcomes converts the outcome counts to their protobuf form
+func convertOutcomes(outcomes map[Outcome]int64) *pb.AuditOutcomes {
+	return &pb.AuditOutcomes{
+		Success:   outcomes[OutcomeSuccess],
+		Failure:   outcomes[OutcomeFailure],
+		Offline:   outcomes[OutcomeOffline],
+		Contained: outcomes[OutcomeContained],
+	}
+}
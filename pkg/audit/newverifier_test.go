@@ -4,8 +4,9 @@
 package audit_test
 
 import (
-	"math/rand"
+	"io"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -14,7 +15,11 @@ import (
 	"storj.io/storj/internal/memory"
 	"storj.io/storj/internal/testcontext"
 	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/internal/testrand"
 	"storj.io/storj/pkg/audit"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/storage"
+	"storj.io/storj/storagenode"
 )
 
 func TestVerifierHappyPath(t *testing.T) {
@@ -26,31 +31,27 @@ func TestVerifierHappyPath(t *testing.T) {
 		assert.NoError(t, err)
 
 		uplink := planet.Uplinks[0]
-		testData := make([]byte, 1*memory.MiB)
-		_, err = rand.Read(testData)
-		assert.NoError(t, err)
+		testData := testrand.New(t).Bytes(1 * memory.MiB.Int())
 
 		err = uplink.Upload(ctx, planet.Satellites[0], "testbucket", "test/path", testData)
 		assert.NoError(t, err)
 
-		pointerdb := planet.Satellites[0].Metainfo.Service
 		overlay := planet.Satellites[0].Overlay.Service
-		cursor := audit.NewCursor(pointerdb)
 
-		var stripe *audit.Stripe
-		for {
-			stripe, err = cursor.NextStripe(ctx)
-			if stripe != nil || err != nil {
-				break
-			}
-		}
+		planet.Satellites[0].Audit.Chore.Loop.TriggerWait()
+		stripe, err := planet.Satellites[0].Audit.Service.Cursor.NextStripe(ctx)
 		require.NoError(t, err)
 		require.NotNil(t, stripe)
 
 		transport := planet.Satellites[0].Transport
 		orders := planet.Satellites[0].Orders.Service
-		minBytesPerSecond := 128 * memory.B
-		verifier := audit.NewVerifier(zap.L(), transport, overlay, orders, planet.Satellites[0].Identity, minBytesPerSecond)
+		config := audit.Config{
+			MinBytesPerSecond:  128 * memory.B,
+			DialTimeout:        10 * time.Second,
+			MinDownloadTimeout: 10 * time.Second,
+			LongTailTimeout:    time.Second,
+		}
+		verifier := audit.NewVerifier(zap.L(), transport, overlay, planet.Satellites[0].DB.Containment(), orders, planet.Satellites[0].Identity, config)
 		require.NotNil(t, verifier)
 
 		// stop some storage nodes to ensure audit can deal with it
@@ -65,7 +66,124 @@ func TestVerifierHappyPath(t *testing.T) {
 		err = planet.Satellites[0].Overlay.Service.Delete(ctx, planet.StorageNodes[1].ID())
 		require.NoError(t, err)
 
-		_, err = verifier.Verify(ctx, stripe)
+		_, err = verifier.Verify(ctx, stripe, nil)
 		assert.NoError(t, err)
 	})
 }
+
+func TestVerifierPieceHash(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 6, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		err := satellite.Audit.Service.Close()
+		require.NoError(t, err)
+
+		testData := testrand.New(t).Bytes(1 * memory.MiB.Int())
+		err = planet.Uplinks[0].Upload(ctx, satellite, "testbucket", "test/path", testData)
+		require.NoError(t, err)
+
+		satellite.Audit.Chore.Loop.TriggerWait()
+		stripe, err := satellite.Audit.Service.Cursor.NextStripe(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, stripe)
+
+		config := audit.Config{
+			MinBytesPerSecond:  128 * memory.B,
+			DialTimeout:        10 * time.Second,
+			MinDownloadTimeout: 10 * time.Second,
+			LongTailTimeout:    time.Second,
+			VerifyPieceHashes:  true,
+		}
+		verifier := audit.NewVerifier(zap.L(), satellite.Transport, satellite.Overlay.Service, satellite.DB.Containment(), satellite.Orders.Service, satellite.Identity, config)
+
+		report, err := verifier.Verify(ctx, stripe, nil)
+		require.NoError(t, err)
+		require.Empty(t, report.FailNodeIDs)
+		require.NotEmpty(t, report.SuccessNodeIDs)
+
+		// the node keeps the hash signed by the uplink, which no longer
+		// matches the pointer
+		tampered := report.SuccessNodeIDs[0]
+		for _, piece := range stripe.Segment.GetRemote().GetRemotePieces() {
+			if piece.NodeId == tampered {
+				piece.Hash = &pb.PieceHash{Hash: []byte("tampered")}
+			}
+		}
+
+		report, err = verifier.Verify(ctx, stripe, nil)
+		require.NoError(t, err)
+		require.Contains(t, report.FailNodeIDs, tampered)
+		require.NotContains(t, report.SuccessNodeIDs, tampered)
+
+		config.VerifyPieceHashes = false
+		verifier = audit.NewVerifier(zap.L(), satellite.Transport, satellite.Overlay.Service, satellite.DB.Containment(), satellite.Orders.Service, satellite.Identity, config)
+
+		report, err = verifier.Verify(ctx, stripe, nil)
+		require.NoError(t, err)
+		require.Empty(t, report.FailNodeIDs)
+	})
+}
+
+func TestVerifierPieceWithoutHeader(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 6, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		err := satellite.Audit.Service.Close()
+		require.NoError(t, err)
+
+		testData := testrand.New(t).Bytes(1 * memory.MiB.Int())
+		err = planet.Uplinks[0].Upload(ctx, satellite, "testbucket", "test/path", testData)
+		require.NoError(t, err)
+
+		satellite.Audit.Chore.Loop.TriggerWait()
+		stripe, err := satellite.Audit.Service.Cursor.NextStripe(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, stripe)
+
+		// store the piece of a node in format V0, as it was stored before
+		// the nodes wrote headers, while the pointer keeps its hash
+		remote := stripe.Segment.GetRemote()
+		piece := remote.GetRemotePieces()[0]
+		require.NotNil(t, piece.GetHash())
+
+		var node *storagenode.Peer
+		for _, storageNode := range planet.StorageNodes {
+			if storageNode.ID() == piece.NodeId {
+				node = storageNode
+			}
+		}
+		require.NotNil(t, node)
+
+		pieceID := remote.RootPieceId.Derive(piece.NodeId)
+		reader, err := node.Storage2.Store.Reader(ctx, satellite.ID(), pieceID)
+		require.NoError(t, err)
+		data := make([]byte, reader.Size())
+		_, err = io.ReadFull(reader, data)
+		require.NoError(t, err)
+		require.NoError(t, reader.Close())
+
+		ref := storage.BlobRef{Namespace: satellite.ID().Bytes(), Key: pieceID.Bytes()}
+		require.NoError(t, node.DB.Pieces().Delete(ctx, ref))
+		blob, err := node.DB.Pieces().Create(ctx, ref, storage.FormatV0, int64(len(data)))
+		require.NoError(t, err)
+		_, err = blob.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, blob.Commit())
+
+		config := audit.Config{
+			MinBytesPerSecond:  128 * memory.B,
+			DialTimeout:        10 * time.Second,
+			MinDownloadTimeout: 10 * time.Second,
+			LongTailTimeout:    time.Second,
+			VerifyPieceHashes:  true,
+		}
+		verifier := audit.NewVerifier(zap.L(), satellite.Transport, satellite.Overlay.Service, satellite.DB.Containment(), satellite.Orders.Service, satellite.Identity, config)
+
+		report, err := verifier.Verify(ctx, stripe, nil)
+		require.NoError(t, err)
+		require.Empty(t, report.FailNodeIDs)
+		require.Contains(t, report.SuccessNodeIDs, piece.NodeId)
+	})
+}
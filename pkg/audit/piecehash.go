@@ -0,0 +1,99 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package audit
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/auth/signing"
+	"storj.io/storj/pkg/identity"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/pkcrypto"
+	"storj.io/storj/pkg/storj"
+)
+
+// ErrPieceHash is the error class for the piece headers that don't prove the
+// uplink signed the hash of the piece.
+var ErrPieceHash = errs.Class("piece hash verification")
+
+// verifyPieceHeader checks that the header of the piece of the node carries
+// the order limit this satellite issued for the upload of the piece, and the
+// hash of the piece signed by the uplink of that order limit, which has to
+// match the hash stored in the pointer.
+//
+// The pieces of format V0 have no header, so a missing header only counts
+// in the metrics. The pointer doesn't tell which format the node stored, so
+// the pieces uploaded before the nodes stored format V1, or to nodes which
+// weren't upgraded yet, pass even though their hash is in the pointer.
+func (verifier *Verifier) verifyPieceHeader(ctx context.Context, pointer *pb.Pointer, nodeID storj.NodeID, header *pb.PieceHeader) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if !verifier.verifyPieceHashes {
+		return nil
+	}
+
+	remote := pointer.GetRemote()
+	var stored *pb.PieceHash
+	for _, piece := range remote.GetRemotePieces() {
+		if piece.NodeId == nodeID {
+			stored = piece.GetHash()
+			break
+		}
+	}
+
+	if header == nil {
+		mon.Meter("audit_piece_header_missing").Mark(1)
+		return nil
+	}
+
+	limit := header.OrderLimit
+	if limit == nil {
+		return ErrPieceHash.New("header without order limit")
+	}
+	if err := signing.VerifyOrderLimitSignature(signing.SigneeFromPeerIdentity(verifier.auditor), limit); err != nil {
+		return ErrPieceHash.New("order limit not signed by the satellite: %v", err)
+	}
+	if limit.Action != pb.PieceAction_PUT && limit.Action != pb.PieceAction_PUT_REPAIR {
+		return ErrPieceHash.New("order limit of action %v", limit.Action)
+	}
+	if limit.StorageNodeId != nodeID {
+		return ErrPieceHash.New("order limit of node %v", limit.StorageNodeId)
+	}
+	if remote == nil || limit.PieceId != remote.RootPieceId.Derive(nodeID) {
+		return ErrPieceHash.New("order limit of piece %v", limit.PieceId)
+	}
+
+	certs, err := pkcrypto.CertsFromDER(header.UplinkCertChain)
+	if err != nil {
+		return ErrPieceHash.Wrap(err)
+	}
+	if len(certs) < 2 {
+		return ErrPieceHash.New("header without uplink certificates")
+	}
+	uplink, err := identity.PeerIdentityFromCerts(certs[0], certs[1], certs[2:])
+	if err != nil {
+		return ErrPieceHash.Wrap(err)
+	}
+	if uplink.ID != limit.UplinkId {
+		return ErrPieceHash.New("uplink %v doesn't match the order limit uplink %v", uplink.ID, limit.UplinkId)
+	}
+
+	hash := &pb.PieceHash{
+		PieceId:   limit.PieceId,
+		Hash:      header.Hash,
+		Signature: header.Signature,
+	}
+	if err := signing.VerifyPieceHashSignature(signing.SigneeFromPeerIdentity(uplink), hash); err != nil {
+		return ErrPieceHash.New("hash not signed by the uplink: %v", err)
+	}
+
+	// the pointers of older uplinks may lack the hash
+	if len(stored.GetHash()) > 0 && !bytes.Equal(stored.GetHash(), header.Hash) {
+		return ErrPieceHash.New("hash doesn't match the hash stored in the pointer")
+	}
+	return nil
+}
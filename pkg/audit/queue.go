@@ -0,0 +1,21 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package audit
+
+import (
+	"context"
+
+	"storj.io/storj/pkg/storj"
+)
+
+// QueueDB stores the segments waiting to be audited, so that the audits
+// continue where they left off when the satellite restarts.
+type QueueDB interface {
+	// Swap replaces the segments waiting to be audited with paths.
+	Swap(ctx context.Context, paths []storj.Path) error
+	// Remove removes the segment from the queue.
+	Remove(ctx context.Context, path storj.Path) error
+	// All returns the segments waiting to be audited, in order.
+	All(ctx context.Context) ([]storj.Path, error)
+}
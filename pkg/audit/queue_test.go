@@ -0,0 +1,67 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package audit_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/pkg/audit"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/satellitedb/satellitedbtest"
+)
+
+func TestQueueDB(t *testing.T) {
+	satellitedbtest.Run(t, func(t *testing.T, db satellite.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		queue := db.AuditQueue()
+
+		paths, err := queue.All(ctx)
+		require.NoError(t, err)
+		require.Empty(t, paths)
+
+		require.NoError(t, queue.Swap(ctx, []storj.Path{"c", "a", "b"}))
+		paths, err = queue.All(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []storj.Path{"c", "a", "b"}, paths)
+
+		require.NoError(t, queue.Remove(ctx, "a"))
+		require.NoError(t, queue.Remove(ctx, "unknown"))
+		paths, err = queue.All(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []storj.Path{"c", "b"}, paths)
+
+		require.NoError(t, queue.Swap(ctx, []storj.Path{"d"}))
+		paths, err = queue.All(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []storj.Path{"d"}, paths)
+
+		require.NoError(t, queue.Swap(ctx, nil))
+		paths, err = queue.All(ctx)
+		require.NoError(t, err)
+		require.Empty(t, paths)
+	})
+}
+
+func TestCursorRestore(t *testing.T) {
+	satellitedbtest.Run(t, func(t *testing.T, db satellite.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		cursor := audit.NewCursor(nil, db.AuditQueue())
+		require.NoError(t, cursor.Swap(ctx, []storj.Path{"a", "b", "c"}))
+
+		// a new cursor, as after a restart, continues with the same queue
+		restarted := audit.NewCursor(nil, db.AuditQueue())
+		restored, err := restarted.Restore(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 3, restored)
+		require.Equal(t, 3, restarted.Len())
+	})
+}
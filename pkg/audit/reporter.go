@@ -6,6 +6,8 @@ package audit
 import (
 	"context"
 
+	"github.com/zeebo/errs"
+
 	"storj.io/storj/pkg/overlay"
 	"storj.io/storj/pkg/storj"
 )
@@ -16,8 +18,11 @@ type reporter interface {
 
 // Reporter records audit reports in overlay and implements the reporter interface
 type Reporter struct {
-	overlay    *overlay.Cache
-	maxRetries int
+	overlay     *overlay.Cache
+	containment Containment
+	maxRetries  int
+
+	maxReverifyCount int32
 }
 
 // RecordAuditsInfo is a struct containing arguments/return values for RecordAudits()
@@ -25,11 +30,28 @@ type RecordAuditsInfo struct {
 	SuccessNodeIDs storj.NodeIDList
 	FailNodeIDs    storj.NodeIDList
 	OfflineNodeIDs storj.NodeIDList
+	PendingAudits  []*PendingAudit
+	// Reverified are the pending audits the nodes of a reverification owed.
+	Reverified map[storj.NodeID]*PendingAudit
+}
+
+// NodeIDs returns the nodes that the report is about.
+func (info *RecordAuditsInfo) NodeIDs() map[storj.NodeID]bool {
+	nodes := make(map[storj.NodeID]bool)
+	for _, list := range []storj.NodeIDList{info.SuccessNodeIDs, info.FailNodeIDs, info.OfflineNodeIDs} {
+		for _, nodeID := range list {
+			nodes[nodeID] = true
+		}
+	}
+	for _, pending := range info.PendingAudits {
+		nodes[pending.NodeID] = true
+	}
+	return nodes
 }
 
 // NewReporter instantiates a reporter
-func NewReporter(overlay *overlay.Cache, maxRetries int) *Reporter {
-	return &Reporter{overlay: overlay, maxRetries: maxRetries}
+func NewReporter(overlay *overlay.Cache, containment Containment, maxRetries int, maxReverifyCount int32) *Reporter {
+	return &Reporter{overlay: overlay, containment: containment, maxRetries: maxRetries, maxReverifyCount: maxReverifyCount}
 }
 
 // RecordAudits saves failed audit details to overlay
@@ -37,12 +59,13 @@ func (reporter *Reporter) RecordAudits(ctx context.Context, req *RecordAuditsInf
 	successNodeIDs := req.SuccessNodeIDs
 	failNodeIDs := req.FailNodeIDs
 	offlineNodeIDs := req.OfflineNodeIDs
+	pendingAudits := req.PendingAudits
 
 	var errNodeIDs storj.NodeIDList
 
 	retries := 0
 	for retries < reporter.maxRetries {
-		if len(successNodeIDs) == 0 && len(failNodeIDs) == 0 && len(offlineNodeIDs) == 0 {
+		if len(successNodeIDs) == 0 && len(failNodeIDs) == 0 && len(offlineNodeIDs) == 0 && len(pendingAudits) == 0 {
 			return nil, nil
 		}
 
@@ -66,6 +89,14 @@ func (reporter *Reporter) RecordAudits(ctx context.Context, req *RecordAuditsInf
 				errNodeIDs = append(errNodeIDs, offlineNodeIDs...)
 			}
 		}
+		if len(pendingAudits) > 0 {
+			pendingAudits, err = reporter.recordPendingAudits(ctx, pendingAudits)
+			if err != nil {
+				for _, pendingAudit := range pendingAudits {
+					errNodeIDs = append(errNodeIDs, pendingAudit.NodeID)
+				}
+			}
+		}
 
 		retries++
 	}
@@ -74,6 +105,7 @@ func (reporter *Reporter) RecordAudits(ctx context.Context, req *RecordAuditsInf
 			SuccessNodeIDs: successNodeIDs,
 			FailNodeIDs:    failNodeIDs,
 			OfflineNodeIDs: offlineNodeIDs,
+			PendingAudits:  pendingAudits,
 		}, Error.New("some nodes failed to be updated in overlay")
 	}
 	return nil, nil
@@ -135,3 +167,52 @@ func (reporter *Reporter) recordAuditSuccessStatus(ctx context.Context, successN
 	}
 	return nil, nil
 }
+
+// recordPendingAudits places the nodes in containment, a node that keeps
+// failing to deliver the share it is contained for fails the audit and leaves
+// containment.
+func (reporter *Reporter) recordPendingAudits(ctx context.Context, pendingAudits []*PendingAudit) (failed []*PendingAudit, err error) {
+	var errlist errs.Group
+
+	for _, pendingAudit := range pendingAudits {
+		reverifyCount := int32(0)
+		existing, err := reporter.containment.Get(ctx, pendingAudit.NodeID)
+		switch {
+		case err == nil:
+			reverifyCount = existing.ReverifyCount + 1
+		case !ErrContainedNotFound.Has(err):
+			failed = append(failed, pendingAudit)
+			errlist.Add(err)
+			continue
+		}
+
+		if reverifyCount < reporter.maxReverifyCount {
+			err = reporter.containment.IncrementPending(ctx, pendingAudit)
+			if err != nil {
+				failed = append(failed, pendingAudit)
+				errlist.Add(err)
+			}
+			continue
+		}
+
+		_, err = reporter.overlay.UpdateStats(ctx, &overlay.UpdateRequest{
+			NodeID:       pendingAudit.NodeID,
+			IsUp:         true,
+			AuditSuccess: false,
+		})
+		if err != nil {
+			failed = append(failed, pendingAudit)
+			errlist.Add(err)
+			continue
+		}
+
+		_, err = reporter.containment.Delete(ctx, pendingAudit.NodeID)
+		if err != nil {
+			errlist.Add(err)
+		}
+	}
+	if len(failed) > 0 {
+		return failed, Error.New("failed to record some pending audits: %v", errlist.Err())
+	}
+	return nil, errlist.Err()
+}
@@ -0,0 +1,40 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package audit
+
+import (
+	"math/rand"
+
+	"storj.io/storj/pkg/storj"
+)
+
+// Reservoir holds a uniform random sample of the segment paths of a node.
+type Reservoir struct {
+	Paths []storj.Path
+	size  int
+	index int64
+}
+
+// NewReservoir instantiates a Reservoir that keeps at most size paths.
+func NewReservoir(size int) *Reservoir {
+	return &Reservoir{
+		Paths: make([]storj.Path, 0, size),
+		size:  size,
+	}
+}
+
+// Sample makes sure that every path seen so far has an equal chance of being
+// in the reservoir, without knowing how many paths there will be.
+func (reservoir *Reservoir) Sample(r *rand.Rand, path storj.Path) {
+	reservoir.index++
+	if len(reservoir.Paths) < reservoir.size {
+		reservoir.Paths = append(reservoir.Paths, path)
+		return
+	}
+
+	random := r.Int63n(reservoir.index)
+	if random < int64(reservoir.size) {
+		reservoir.Paths[random] = path
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package audit_test
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/pkg/audit"
+	"storj.io/storj/pkg/storj"
+)
+
+func TestReservoir(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	reservoir := audit.NewReservoir(3)
+	reservoir.Sample(r, "a")
+	reservoir.Sample(r, "b")
+	require.Equal(t, []storj.Path{"a", "b"}, reservoir.Paths)
+
+	for i := 0; i < 100; i++ {
+		reservoir.Sample(r, strconv.Itoa(i))
+	}
+	require.Len(t, reservoir.Paths, 3)
+}
+
+func TestReservoirUniform(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	const paths, runs = 10, 10000
+	counts := map[storj.Path]int{}
+	for run := 0; run < runs; run++ {
+		reservoir := audit.NewReservoir(1)
+		for i := 0; i < paths; i++ {
+			reservoir.Sample(r, strconv.Itoa(i))
+		}
+		counts[reservoir.Paths[0]]++
+	}
+
+	// every path is expected runs/paths times, the bounds are about 5 standard deviations
+	require.Len(t, counts, paths)
+	for path, count := range counts {
+		assert.InDelta(t, runs/paths, count, 150, path)
+	}
+}
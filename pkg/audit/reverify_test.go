@@ -0,0 +1,86 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package audit_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/internal/memory"
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/internal/testrand"
+	"storj.io/storj/pkg/audit"
+	"storj.io/storj/pkg/pkcrypto"
+	"storj.io/storj/pkg/storj"
+)
+
+func TestReverify(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 5, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		require.NoError(t, satellite.Audit.Service.Close())
+
+		err := planet.Uplinks[0].Upload(ctx, satellite, "testbucket", "test/path", testrand.New(t).Bytes(10*memory.KiB.Int()))
+		require.NoError(t, err)
+
+		satellite.Audit.Chore.Loop.TriggerWait()
+		stripe, err := satellite.Audit.Service.Cursor.NextStripe(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, stripe)
+
+		remote := stripe.Segment.GetRemote()
+		shareSize := remote.GetRedundancy().GetErasureShareSize()
+		pieces := remote.GetRemotePieces()
+		honest, dishonest := pieces[0].NodeId, pieces[1].NodeId
+
+		// contain one node with the share it stores and another with a different one
+		share := readShare(ctx, t, planet, honest, remote.RootPieceId, stripe.Index, shareSize)
+		containment := satellite.DB.Containment()
+		for nodeID, hash := range map[storj.NodeID][]byte{
+			honest:    pkcrypto.SHA256Hash(share),
+			dishonest: pkcrypto.SHA256Hash(nil),
+		} {
+			require.NoError(t, containment.IncrementPending(ctx, &audit.PendingAudit{
+				NodeID:            nodeID,
+				PieceID:           remote.RootPieceId,
+				StripeIndex:       stripe.Index,
+				ShareSize:         shareSize,
+				ExpectedShareHash: hash,
+			}))
+		}
+
+		report, err := satellite.Audit.Service.Verifier.Reverify(ctx, stripe)
+		require.NoError(t, err)
+		require.Equal(t, storj.NodeIDList{honest}, report.SuccessNodeIDs)
+		require.Equal(t, storj.NodeIDList{dishonest}, report.FailNodeIDs)
+		require.Empty(t, report.OfflineNodeIDs)
+		require.Empty(t, report.PendingAudits)
+
+		for _, nodeID := range []storj.NodeID{honest, dishonest} {
+			_, err = containment.Get(ctx, nodeID)
+			require.True(t, audit.ErrContainedNotFound.Has(err))
+		}
+	})
+}
+
+func readShare(ctx *testcontext.Context, t *testing.T, planet *testplanet.Planet, nodeID storj.NodeID, rootPieceID storj.PieceID, stripeIndex int64, shareSize int32) []byte {
+	for _, node := range planet.StorageNodes {
+		if node.ID() != nodeID {
+			continue
+		}
+		reader, err := node.Storage2.Store.Reader(ctx, planet.Satellites[0].ID(), rootPieceID.Derive(nodeID))
+		require.NoError(t, err)
+		defer ctx.Check(reader.Close)
+
+		share := make([]byte, shareSize)
+		_, err = reader.ReadAt(share, stripeIndex*int64(shareSize))
+		require.NoError(t, err)
+		return share
+	}
+	t.Fatalf("no such node: %s", nodeID)
+	return nil
+}
@@ -0,0 +1,119 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package audit
+
+import (
+	"sync"
+	"time"
+
+	"storj.io/storj/pkg/storj"
+)
+
+// schedulerWindow is the period over which the audits of a node are counted.
+const schedulerWindow = time.Hour
+
+// Scheduler keeps track of the audits every node had during the last hour,
+// so that the nodes storing a lot of data aren't audited for every stripe.
+type Scheduler struct {
+	maxPerHour int
+
+	mu     sync.Mutex
+	audits map[storj.NodeID][]time.Time
+}
+
+// NewScheduler creates a Scheduler that allows maxPerHour audits of a node
+// per hour, 0 means unlimited.
+func NewScheduler(maxPerHour int) *Scheduler {
+	return &Scheduler{
+		maxPerHour: maxPerHour,
+		audits:     make(map[storj.NodeID][]time.Time),
+	}
+}
+
+// Audited records that the node was audited at now.
+func (scheduler *Scheduler) Audited(nodeID storj.NodeID, now time.Time) {
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+
+	scheduler.audits[nodeID] = append(scheduler.recent(nodeID, now), now)
+}
+
+// Count returns the number of audits of the node during the hour before now.
+func (scheduler *Scheduler) Count(nodeID storj.NodeID, now time.Time) int {
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+
+	return len(scheduler.recent(nodeID, now))
+}
+
+// Throttle adds the nodes of the stripe that reached their audit limit to
+// skip. The nodes with the most audits are throttled first, and only as long
+// as the number of audited pieces stays at the repair threshold, so that the
+// stripe can still be verified.
+func (scheduler *Scheduler) Throttle(stripe *Stripe, skip map[storj.NodeID]bool, now time.Time) (throttled storj.NodeIDList) {
+	if scheduler.maxPerHour <= 0 {
+		return nil
+	}
+
+	redundancy := stripe.Segment.GetRemote().GetRedundancy()
+	minimum := int(redundancy.GetRepairThreshold())
+	if minimum <= int(redundancy.GetMinReq()) {
+		minimum = int(redundancy.GetMinReq()) + 1
+	}
+
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+
+	audited := 0
+	var candidates storj.NodeIDList
+	counts := make(map[storj.NodeID]int)
+	for _, piece := range stripe.Segment.GetRemote().GetRemotePieces() {
+		if skip[piece.NodeId] {
+			continue
+		}
+		audited++
+
+		count := len(scheduler.recent(piece.NodeId, now))
+		if count >= scheduler.maxPerHour {
+			candidates = append(candidates, piece.NodeId)
+			counts[piece.NodeId] = count
+		}
+	}
+
+	// throttle the most audited nodes first
+	for len(candidates) > 0 && audited > minimum {
+		most := 0
+		for i, nodeID := range candidates {
+			if counts[nodeID] > counts[candidates[most]] {
+				most = i
+			}
+		}
+
+		skip[candidates[most]] = true
+		throttled = append(throttled, candidates[most])
+		candidates = append(candidates[:most], candidates[most+1:]...)
+		audited--
+	}
+	return throttled
+}
+
+// recent drops the audits of the node that are older than the window and
+// returns the remaining ones, the caller must hold the lock.
+func (scheduler *Scheduler) recent(nodeID storj.NodeID, now time.Time) []time.Time {
+	audits := scheduler.audits[nodeID]
+
+	cutoff := now.Add(-schedulerWindow)
+	expired := 0
+	for expired < len(audits) && !audits[expired].After(cutoff) {
+		expired++
+	}
+	audits = audits[expired:]
+
+	if len(audits) == 0 {
+		delete(scheduler.audits, nodeID)
+		return nil
+	}
+	scheduler.audits[nodeID] = audits
+	return audits
+}
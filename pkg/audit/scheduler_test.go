@@ -0,0 +1,141 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package audit_test
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/internal/teststorj"
+	"storj.io/storj/pkg/audit"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/satellite"
+)
+
+func TestSchedulerCount(t *testing.T) {
+	scheduler := audit.NewScheduler(10)
+	node := teststorj.NodeIDFromString("node")
+	now := time.Now()
+
+	scheduler.Audited(node, now.Add(-90*time.Minute))
+	scheduler.Audited(node, now.Add(-30*time.Minute))
+	scheduler.Audited(node, now)
+	require.Equal(t, 2, scheduler.Count(node, now))
+	require.Equal(t, 1, scheduler.Count(node, now.Add(45*time.Minute)))
+	require.Equal(t, 0, scheduler.Count(node, now.Add(2*time.Hour)))
+}
+
+func TestSchedulerThrottle(t *testing.T) {
+	var nodes storj.NodeIDList
+	var pieces []*pb.RemotePiece
+	for i := 0; i < 5; i++ {
+		node := teststorj.NodeIDFromString("node" + strconv.Itoa(i))
+		nodes = append(nodes, node)
+		pieces = append(pieces, &pb.RemotePiece{PieceNum: int32(i), NodeId: node})
+	}
+	stripe := &audit.Stripe{
+		Segment: &pb.Pointer{
+			Type: pb.Pointer_REMOTE,
+			Remote: &pb.RemoteSegment{
+				Redundancy:   &pb.RedundancyScheme{MinReq: 2, RepairThreshold: 3, SuccessThreshold: 4, Total: 5},
+				RemotePieces: pieces,
+			},
+		},
+	}
+	now := time.Now()
+
+	t.Run("unlimited", func(t *testing.T) {
+		scheduler := audit.NewScheduler(0)
+		for i := 0; i < 100; i++ {
+			scheduler.Audited(nodes[0], now)
+		}
+		require.Empty(t, scheduler.Throttle(stripe, map[storj.NodeID]bool{}, now))
+	})
+
+	t.Run("limited", func(t *testing.T) {
+		scheduler := audit.NewScheduler(2)
+		// nodes 0 to 3 reached the limit, node 0 the most
+		for i, count := range []int{4, 2, 3, 2} {
+			for j := 0; j < count; j++ {
+				scheduler.Audited(nodes[i], now)
+			}
+		}
+
+		skip := map[storj.NodeID]bool{}
+		throttled := scheduler.Throttle(stripe, skip, now)
+
+		// the repair threshold of pieces is still audited
+		require.Equal(t, storj.NodeIDList{nodes[0], nodes[2]}, throttled)
+		require.Equal(t, map[storj.NodeID]bool{nodes[0]: true, nodes[2]: true}, skip)
+	})
+
+	t.Run("skipped", func(t *testing.T) {
+		scheduler := audit.NewScheduler(1)
+		for _, node := range nodes {
+			scheduler.Audited(node, now)
+		}
+
+		// a node skipped for reverification leaves room for one throttled node only
+		skip := map[storj.NodeID]bool{nodes[4]: true}
+		throttled := scheduler.Throttle(stripe, skip, now)
+		require.Len(t, throttled, 1)
+		require.Len(t, skip, 2)
+	})
+}
+
+func TestPathCollectorUnvetted(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 2, UplinkCount: 0,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Overlay.Node.AuditCount = 1
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		cache := planet.Satellites[0].Overlay.Service
+		vetted, unvetted := planet.StorageNodes[0].ID(), planet.StorageNodes[1].ID()
+
+		_, err := cache.UpdateStats(ctx, &overlay.UpdateRequest{NodeID: vetted, AuditSuccess: true, IsUp: true})
+		require.NoError(t, err)
+
+		isVetted, err := cache.IsVetted(ctx, vetted)
+		require.NoError(t, err)
+		require.True(t, isVetted)
+		isVetted, err = cache.IsVetted(ctx, unvetted)
+		require.NoError(t, err)
+		require.False(t, isVetted)
+
+		collector := audit.NewPathCollector(1, 3, cache, rand.New(rand.NewSource(1)))
+		for i := 0; i < 3; i++ {
+			for _, node := range []storj.NodeID{vetted, unvetted} {
+				path := node.String() + "/" + strconv.Itoa(i)
+				pointer := &pb.Pointer{
+					Type: pb.Pointer_REMOTE,
+					Remote: &pb.RemoteSegment{
+						RemotePieces: []*pb.RemotePiece{{NodeId: node}},
+					},
+				}
+				require.NoError(t, collector.RemoteSegment(ctx, path, pointer))
+			}
+		}
+		require.Equal(t, map[storj.NodeID]bool{unvetted: true}, collector.Unvetted)
+
+		// all the paths of the unvetted node come first
+		paths := collector.Paths()
+		require.Len(t, paths, 4)
+		for _, path := range paths[:3] {
+			require.Contains(t, path, unvetted.String())
+		}
+		require.Contains(t, paths[3], vetted.String())
+	})
+}
@@ -5,15 +5,18 @@ package audit
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
+	"golang.org/x/time/rate"
 
 	"storj.io/storj/internal/memory"
 	"storj.io/storj/internal/sync2"
 	"storj.io/storj/pkg/identity"
 	"storj.io/storj/pkg/overlay"
 	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/pkg/storj"
 	"storj.io/storj/pkg/transport"
 	"storj.io/storj/satellite/orders"
 )
@@ -21,46 +24,118 @@ import (
 // Config contains configurable values for audit service
 type Config struct {
 	MaxRetriesStatDB  int           `help:"max number of times to attempt updating a statdb batch" default:"3"`
-	Interval          time.Duration `help:"how frequently segments are audited" default:"30s"`
+	MaxReverifyCount  int           `help:"number of times a contained node may fail to deliver the share it is contained for before failing the audit" default:"3"`
+	Interval          time.Duration `help:"how frequently the audit workers are started for the segments waiting to be audited" default:"30s"`
 	MinBytesPerSecond memory.Size   `help:"the minimum acceptable bytes that storage nodes can transfer per second to the satellite" default:"128B"`
+
+	DialTimeout        time.Duration `help:"how long to wait for a storage node to accept an audit connection" default:"10s"`
+	MinDownloadTimeout time.Duration `help:"the minimum duration for downloading a share from a storage node before timing out" default:"1m"`
+	LongTailTimeout    time.Duration `help:"how long to wait for the remaining shares once the required shares of the stripe were downloaded" default:"10s"`
+	VerifyPieceHashes  bool          `help:"fail the nodes whose audited share comes with a piece hash that wasn't signed by the uplink at upload" default:"true"`
+
+	WorkerConcurrency    int     `help:"number of workers auditing segments concurrently" default:"2"`
+	MaxSegmentsPerSecond float64 `help:"maximum number of segments audited per second by all the workers together, 0 means unlimited" default:"0"`
+
+	MaxAuditsPerNodePerHour  int     `help:"maximum number of audits of a node per hour, the nodes over the limit are left out of further stripes while enough pieces remain, 0 means unlimited" default:"60"`
+	MinUnvettedAuditsPerHour float64 `help:"minimum number of audits per hour of the nodes that aren't vetted yet, so that their vetting completes in bounded time" default:"1"`
+
+	HistoryRetention   time.Duration `help:"how long the audit records of the nodes and segments are kept" default:"720h"`
+	ReportWarningScore float64       `help:"the audit score below which the audit report lists a node as nearing disqualification" default:"0.7"`
+
+	DryRun DryRunConfig
+
+	Slots         int           `help:"number of segments sampled from every node for each audit queue" default:"3"`
+	ChoreInterval time.Duration `help:"how frequently the audit queue is refilled from the metainfo loop" default:"4h"`
+}
+
+// DryRunConfig configures the dry run, which audits every stripe a second
+// time with its own verifier settings alongside the real audits. Its
+// outcomes are only recorded in the dry run history, so that new settings
+// can be compared with the real audits without affecting the nodes.
+type DryRunConfig struct {
+	Enabled            bool          `help:"audit every stripe a second time with the dry run settings, recording the outcomes only in the dry run history" default:"false"`
+	MinBytesPerSecond  memory.Size   `help:"the minimum bytes per second of the share downloads of the dry run" default:"128B"`
+	MinDownloadTimeout time.Duration `help:"the minimum duration for downloading a share in the dry run" default:"1m"`
+	LongTailTimeout    time.Duration `help:"how long the dry run waits for the remaining shares once the required shares were downloaded" default:"10s"`
+	VerifyPieceHashes  bool          `help:"fail the nodes in the dry run whose share comes with a piece hash that wasn't signed by the uplink" default:"true"`
+}
+
+// apply returns config with the verifier settings of the dry run.
+func (dryRun DryRunConfig) apply(config Config) Config {
+	config.MinBytesPerSecond = dryRun.MinBytesPerSecond
+	config.MinDownloadTimeout = dryRun.MinDownloadTimeout
+	config.LongTailTimeout = dryRun.LongTailTimeout
+	config.VerifyPieceHashes = dryRun.VerifyPieceHashes
+	return config
 }
 
 // Service helps coordinate Cursor and Verifier to run the audit process continuously
 type Service struct {
-	log *zap.Logger
+	log     *zap.Logger
+	workers int
+	limiter *rate.Limiter
 
-	Cursor   *Cursor
-	Verifier *Verifier
-	Reporter reporter
+	Cursor    *Cursor
+	Verifier  *Verifier
+	Reporter  reporter
+	History   HistoryDB
+	Stats     StatsDB
+	Scheduler *Scheduler
+
+	// DryRun is nil unless the dry run is enabled
+	DryRun        *Verifier
+	DryRunHistory HistoryDB
 
 	Loop sync2.Cycle
 }
 
-// NewService instantiates a Service with access to a Cursor and Verifier
+// NewService instantiates a Service with access to a Cursor and Verifier. The
+// outcomes of the dry run are recorded to dryRunHistory.
 func NewService(log *zap.Logger, config Config, pointerdb *pointerdb.Service,
 	orders *orders.Service, transport transport.Client, overlay *overlay.Cache,
-	identity *identity.FullIdentity) (service *Service, err error) {
-	return &Service{
-		log: log,
+	containment Containment, queueDB QueueDB, history, dryRunHistory HistoryDB, stats StatsDB, identity *identity.FullIdentity) (service *Service, err error) {
+	limit := rate.Inf
+	if config.MaxSegmentsPerSecond > 0 {
+		limit = rate.Limit(config.MaxSegmentsPerSecond)
+	}
+
+	workers := config.WorkerConcurrency
+	if workers < 1 {
+		workers = 1
+	}
+
+	service = &Service{
+		log:     log,
+		workers: workers,
+		limiter: rate.NewLimiter(limit, 1),
 
-		Cursor:   NewCursor(pointerdb),
-		Verifier: NewVerifier(log.Named("audit:verifier"), transport, overlay, orders, identity, config.MinBytesPerSecond),
-		Reporter: NewReporter(overlay, config.MaxRetriesStatDB),
+		Cursor:    NewCursor(pointerdb, queueDB),
+		Verifier:  NewVerifier(log.Named("audit:verifier"), transport, overlay, containment, orders, identity, config),
+		Reporter:  NewReporter(overlay, containment, config.MaxRetriesStatDB, int32(config.MaxReverifyCount)),
+		History:   history,
+		Stats:     stats,
+		Scheduler: NewScheduler(config.MaxAuditsPerNodePerHour),
+
+		DryRunHistory: dryRunHistory,
 
 		Loop: *sync2.NewCycle(config.Interval),
-	}, nil
+	}
+	if config.DryRun.Enabled {
+		service.DryRun = NewVerifier(log.Named("audit:dryrun"), transport, overlay, containment, orders, identity, config.DryRun.apply(config))
+	}
+	return service, nil
 }
 
 // Run runs auditing service
 func (service *Service) Run(ctx context.Context) (err error) {
 	defer mon.Task()(&ctx)(&err)
 	service.log.Info("Audit cron is starting up")
+	if service.DryRun != nil {
+		service.log.Info("Audits are repeated in a dry run with its own settings")
+	}
 
 	return service.Loop.Run(ctx, func(ctx context.Context) error {
-		err := service.process(ctx)
-		if err != nil {
-			service.log.Error("process", zap.Error(err))
-		}
+		service.processQueue(ctx)
 		return nil
 	})
 }
@@ -71,8 +146,39 @@ func (service *Service) Close() error {
 	return nil
 }
 
+// processQueue starts the workers and waits until they audited all the
+// segments waiting in the cursor, or ctx is canceled.
+func (service *Service) processQueue(ctx context.Context) {
+	var workers sync.WaitGroup
+	for i := 0; i < service.workers; i++ {
+		workers.Add(1)
+		go func(log *zap.Logger) {
+			defer workers.Done()
+			service.work(ctx, log)
+		}(service.log.With(zap.Int("worker", i)))
+	}
+	workers.Wait()
+}
+
+// work audits segments from the cursor until the queue is empty or ctx is
+// canceled. An error only ends the audit of its own segment.
+func (service *Service) work(ctx context.Context, log *zap.Logger) {
+	for service.Cursor.Len() > 0 {
+		if err := service.limiter.Wait(ctx); err != nil {
+			return
+		}
+
+		err := service.process(ctx)
+		if err != nil {
+			log.Error("process", zap.Error(err))
+		}
+	}
+}
+
 // process picks a random stripe and verifies correctness
-func (service *Service) process(ctx context.Context) error {
+func (service *Service) process(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
 	stripe, err := service.Cursor.NextStripe(ctx)
 	if err != nil {
 		return err
@@ -81,7 +187,32 @@ func (service *Service) process(ctx context.Context) error {
 		return nil
 	}
 
-	verifiedNodes, err := service.Verifier.Verify(ctx, stripe)
+	// the contained nodes of the segment have to deliver their pending shares first
+	reverifiedNodes, err := service.Verifier.Reverify(ctx, stripe)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.Reporter.RecordAudits(ctx, reverifiedNodes)
+	if err != nil {
+		return err
+	}
+
+	reverifiedRecords := reverifiedNodes.Records(stripe, true)
+	err = service.recordHistory(ctx, reverifiedRecords)
+	if err != nil {
+		return err
+	}
+
+	// the nodes audited too often during the last hour sit this stripe out
+	skip := reverifiedNodes.NodeIDs()
+	throttled := service.Scheduler.Throttle(stripe, skip, time.Now())
+	if len(throttled) > 0 {
+		mon.IntVal("audit_throttled_nodes").Observe(int64(len(throttled)))
+		service.log.Debug("throttled nodes", zap.String("segment", stripe.SegmentPath), zap.Int("count", len(throttled)))
+	}
+
+	verifiedNodes, err := service.Verifier.Verify(ctx, stripe, skip)
 	if err != nil {
 		return err
 	}
@@ -92,5 +223,59 @@ func (service *Service) process(ctx context.Context) error {
 		return err
 	}
 
-	return nil
+	verifiedRecords := verifiedNodes.Records(stripe, false)
+	err = service.recordHistory(ctx, verifiedRecords)
+	if err != nil {
+		return err
+	}
+
+	err = service.Stats.RecordSegment(ctx, time.Now(), countOutcomes(append(reverifiedRecords, verifiedRecords...)))
+	if err != nil {
+		return err
+	}
+
+	if service.DryRun == nil {
+		return nil
+	}
+	return service.auditDryRun(ctx, stripe, skip)
+}
+
+// auditDryRun audits the stripe again with the dry run verifier, skipping the
+// same nodes as the real audit. The outcomes are only recorded in the dry run
+// history, they don't count towards the reputation, the containment or the
+// throttling of the nodes.
+func (service *Service) auditDryRun(ctx context.Context, stripe *Stripe, skip map[storj.NodeID]bool) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	verifiedNodes, err := service.DryRun.Verify(ctx, stripe, skip)
+	if err != nil {
+		return err
+	}
+
+	records := verifiedNodes.Records(stripe, false)
+	if len(records) == 0 {
+		return nil
+	}
+	return service.DryRunHistory.Insert(ctx, records)
+}
+
+// recordHistory logs the audit records and stores them for the inspector.
+func (service *Service) recordHistory(ctx context.Context, records []Record) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(records) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	for _, record := range records {
+		service.Scheduler.Audited(record.NodeID, now)
+		service.log.Debug("audited",
+			zap.String("segment", record.SegmentPath),
+			zap.Int64("stripe", record.StripeIndex),
+			zap.Stringer("node", record.NodeID),
+			zap.Stringer("outcome", record.Outcome),
+			zap.Bool("reverify", record.Reverify))
+	}
+	return service.History.Insert(ctx, records)
 }
@@ -0,0 +1,102 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package audit_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"storj.io/storj/internal/memory"
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/internal/testrand"
+	"storj.io/storj/pkg/audit"
+	"storj.io/storj/satellite"
+)
+
+func TestWorkersAuditQueue(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 5, UplinkCount: 1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Audit.MaxRetriesStatDB = 1
+				config.Audit.WorkerConcurrency = 3
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		defer ctx.Check(satellite.Audit.Service.Close)
+
+		for i := 0; i < 4; i++ {
+			path := "test/path" + strconv.Itoa(i)
+			err := planet.Uplinks[0].Upload(ctx, satellite, "testbucket", path, testrand.New(t).Bytes(10*memory.KiB.Int()))
+			require.NoError(t, err)
+		}
+
+		satellite.Audit.Chore.Loop.TriggerWait()
+		require.NotZero(t, satellite.Audit.Service.Cursor.Len())
+
+		satellite.Audit.Service.Loop.TriggerWait()
+		require.Zero(t, satellite.Audit.Service.Cursor.Len())
+
+		var audits int64
+		for _, node := range planet.StorageNodes {
+			stats, err := satellite.Overlay.Service.GetStats(ctx, node.ID())
+			require.NoError(t, err)
+			require.Equal(t, stats.AuditCount, stats.AuditSuccessCount)
+			audits += stats.AuditCount
+		}
+		require.NotZero(t, audits)
+	})
+}
+
+func TestDryRun(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 5, UplinkCount: 1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Audit.MaxRetriesStatDB = 1
+				config.Audit.DryRun = audit.DryRunConfig{
+					Enabled:            true,
+					MinBytesPerSecond:  config.Audit.MinBytesPerSecond,
+					MinDownloadTimeout: config.Audit.MinDownloadTimeout,
+					LongTailTimeout:    config.Audit.LongTailTimeout,
+				}
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		defer ctx.Check(satellite.Audit.Service.Close)
+
+		err := planet.Uplinks[0].Upload(ctx, satellite, "testbucket", "test/path", testrand.New(t).Bytes(10*memory.KiB.Int()))
+		require.NoError(t, err)
+
+		satellite.Audit.Chore.Loop.TriggerWait()
+		require.NotZero(t, satellite.Audit.Service.Cursor.Len())
+
+		satellite.Audit.Service.Loop.TriggerWait()
+		require.Zero(t, satellite.Audit.Service.Cursor.Len())
+
+		// the dry run audits the same nodes as the real audits, which are still
+		// recorded as usual
+		var audited int
+		for _, node := range planet.StorageNodes {
+			records, err := satellite.DB.AuditHistory().ByNode(ctx, node.ID(), 10)
+			require.NoError(t, err)
+
+			dryRecords, err := satellite.DB.AuditDryRun().ByNode(ctx, node.ID(), 10)
+			require.NoError(t, err)
+			require.Len(t, dryRecords, len(records))
+			for _, record := range dryRecords {
+				require.Equal(t, audit.OutcomeSuccess, record.Outcome)
+				require.False(t, record.Reverify)
+			}
+			audited += len(dryRecords)
+		}
+		require.NotZero(t, audited)
+	})
+}
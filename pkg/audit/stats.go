@@ -0,0 +1,107 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package audit
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"storj.io/storj/pkg/storj"
+)
+
+// DailyStats are the aggregated audits of a day.
+type DailyStats struct {
+	IntervalStart   time.Time
+	Outcomes        map[Outcome]int64
+	SegmentsAudited int64
+	// TotalSegments is the number of remote segments the audit chore saw on
+	// that day, zero when it didn't run.
+	TotalSegments int64
+}
+
+// NodeScore is the audit score of a node.
+type NodeScore struct {
+	NodeID     storj.NodeID
+	AuditScore float64
+}
+
+// StatsDB stores the daily aggregates of the audits.
+type StatsDB interface {
+	// RecordSegment adds an audited segment and the outcomes of its nodes to the day of now.
+	RecordSegment(ctx context.Context, now time.Time, outcomes map[Outcome]int64) error
+	// RecordTotalSegments stores the number of remote segments seen on the day of now.
+	RecordTotalSegments(ctx context.Context, now time.Time, total int64) error
+	// Daily returns the stats of the days since the given time, oldest first.
+	Daily(ctx context.Context, since time.Time) ([]DailyStats, error)
+	// NodesBelow returns the nodes that aren't disqualified and whose audit
+	// score is below score.
+	NodesBelow(ctx context.Context, score float64) ([]NodeScore, error)
+}
+
+// Report summarizes the audits of the last days.
+type Report struct {
+	Days            []DailyStats
+	Outcomes        map[Outcome]int64
+	SegmentsAudited int64
+	// TotalSegments is the most recent number of remote segments.
+	TotalSegments int64
+	// Coverage estimates the share of the segments audited during the days,
+	// it overestimates when segments were audited more than once.
+	Coverage float64
+	// NodesAtRisk are the nodes nearing disqualification.
+	NodesAtRisk []NodeScore
+}
+
+// BuildReport summarizes the audits of the last days, listing the nodes
+// whose audit score is below warningScore.
+func BuildReport(ctx context.Context, stats StatsDB, days int, warningScore float64) (_ *Report, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	daily, err := stats.Daily(ctx, today.AddDate(0, 0, 1-days))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		Days:     daily,
+		Outcomes: make(map[Outcome]int64),
+	}
+	for _, day := range daily {
+		for outcome, count := range day.Outcomes {
+			report.Outcomes[outcome] += count
+		}
+		report.SegmentsAudited += day.SegmentsAudited
+		if day.TotalSegments > 0 {
+			report.TotalSegments = day.TotalSegments
+		}
+	}
+
+	if report.TotalSegments > 0 {
+		report.Coverage = float64(report.SegmentsAudited) / float64(report.TotalSegments)
+		if report.Coverage > 1 {
+			report.Coverage = 1
+		}
+	}
+
+	report.NodesAtRisk, err = stats.NodesBelow(ctx, warningScore)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(report.NodesAtRisk, func(i, k int) bool {
+		return report.NodesAtRisk[i].AuditScore < report.NodesAtRisk[k].AuditScore
+	})
+	return report, nil
+}
+
+// countOutcomes returns the number of records of every outcome.
+func countOutcomes(records []Record) map[Outcome]int64 {
+	outcomes := make(map[Outcome]int64)
+	for _, record := range records {
+		outcomes[record.Outcome]++
+	}
+	return outcomes
+}
@@ -0,0 +1,101 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package audit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/pkg/audit"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/satellitedb/satellitedbtest"
+)
+
+func TestStatsReport(t *testing.T) {
+	satellitedbtest.Run(t, func(t *testing.T, db satellite.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		stats := db.AuditStats()
+		now := time.Now().UTC()
+		yesterday, longAgo := now.AddDate(0, 0, -1), now.AddDate(0, 0, -40)
+
+		require.NoError(t, stats.RecordSegment(ctx, longAgo, map[audit.Outcome]int64{audit.OutcomeFailure: 5}))
+		require.NoError(t, stats.RecordTotalSegments(ctx, longAgo, 1000))
+
+		require.NoError(t, stats.RecordTotalSegments(ctx, yesterday, 10))
+		require.NoError(t, stats.RecordSegment(ctx, yesterday, map[audit.Outcome]int64{
+			audit.OutcomeSuccess: 3,
+			audit.OutcomeOffline: 1,
+		}))
+		require.NoError(t, stats.RecordSegment(ctx, now, map[audit.Outcome]int64{audit.OutcomeSuccess: 4}))
+		require.NoError(t, stats.RecordSegment(ctx, now, map[audit.Outcome]int64{
+			audit.OutcomeSuccess:   2,
+			audit.OutcomeContained: 1,
+		}))
+		// a later count replaces the earlier one of the same day
+		require.NoError(t, stats.RecordTotalSegments(ctx, now, 7))
+		require.NoError(t, stats.RecordTotalSegments(ctx, now, 8))
+
+		days, err := stats.Daily(ctx, time.Date(yesterday.Year(), yesterday.Month(), yesterday.Day(), 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		require.Len(t, days, 2)
+		require.Equal(t, int64(1), days[0].SegmentsAudited)
+		require.Equal(t, int64(10), days[0].TotalSegments)
+		require.Equal(t, int64(3), days[0].Outcomes[audit.OutcomeSuccess])
+		require.Equal(t, int64(1), days[0].Outcomes[audit.OutcomeOffline])
+		require.Equal(t, int64(2), days[1].SegmentsAudited)
+		require.Equal(t, int64(8), days[1].TotalSegments)
+		require.Equal(t, int64(6), days[1].Outcomes[audit.OutcomeSuccess])
+		require.Equal(t, int64(1), days[1].Outcomes[audit.OutcomeContained])
+
+		cache := overlay.NewCache(zap.NewNop(), db.OverlayCache(), overlay.NodeSelectionConfig{}, overlay.ReputationConfig{
+			AuditAlpha0: 2, AuditLambda: 1, AuditWeight: 1,
+			UptimeAlpha0: 1, UptimeLambda: 1, UptimeWeight: 1,
+		})
+		lowScore, good := storj.NodeID{1}, storj.NodeID{2}
+		for _, id := range []storj.NodeID{lowScore, good} {
+			require.NoError(t, cache.Put(ctx, id, pb.Node{
+				Id:           id,
+				Type:         pb.NodeType_STORAGE,
+				Address:      &pb.NodeAddress{Address: "127.0.0.1:0"},
+				Restrictions: &pb.NodeRestrictions{},
+				Reputation:   &pb.NodeStats{},
+			}))
+		}
+		// audit score 2/4
+		for i := 0; i < 2; i++ {
+			_, err := cache.UpdateStats(ctx, &overlay.UpdateRequest{NodeID: lowScore, AuditSuccess: false, IsUp: true})
+			require.NoError(t, err)
+		}
+		_, err = cache.UpdateStats(ctx, &overlay.UpdateRequest{NodeID: good, AuditSuccess: true, IsUp: true})
+		require.NoError(t, err)
+
+		report, err := audit.BuildReport(ctx, stats, 7, 0.7)
+		require.NoError(t, err)
+		require.Len(t, report.Days, 2)
+		require.Equal(t, int64(9), report.Outcomes[audit.OutcomeSuccess])
+		require.Equal(t, int64(0), report.Outcomes[audit.OutcomeFailure])
+		require.Equal(t, int64(3), report.SegmentsAudited)
+		require.Equal(t, int64(8), report.TotalSegments)
+		require.InDelta(t, 3.0/8.0, report.Coverage, 1e-9)
+		require.Len(t, report.NodesAtRisk, 1)
+		require.Equal(t, lowScore, report.NodesAtRisk[0].NodeID)
+		require.InDelta(t, 0.5, report.NodesAtRisk[0].AuditScore, 1e-9)
+
+		// only today is reported
+		report, err = audit.BuildReport(ctx, stats, 1, 0.4)
+		require.NoError(t, err)
+		require.Len(t, report.Days, 1)
+		require.Empty(t, report.NodesAtRisk)
+		require.InDelta(t, 2.0/8.0, report.Coverage, 1e-9)
+	})
+}
@@ -4,18 +4,22 @@
 package audit_test
 
 import (
+	"context"
 	"fmt"
-	"math/rand"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 
 	"storj.io/storj/internal/memory"
 	"storj.io/storj/internal/testcontext"
 	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/internal/testrand"
 	"storj.io/storj/pkg/audit"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/pkcrypto"
 	"storj.io/storj/pkg/storj"
 	"storj.io/storj/pkg/transport"
 )
@@ -32,24 +36,15 @@ func TestGetShareTimeout(t *testing.T) {
 		require.NoError(t, err)
 
 		uplink := planet.Uplinks[0]
-		testData := make([]byte, 1*memory.MiB)
-		_, err = rand.Read(testData)
-		require.NoError(t, err)
+		testData := testrand.New(t).Bytes(1 * memory.MiB.Int())
 
 		err = uplink.Upload(ctx, planet.Satellites[0], "testbucket", "test/path", testData)
 		require.NoError(t, err)
 
-		pointerdb := planet.Satellites[0].Metainfo.Service
 		overlay := planet.Satellites[0].Overlay.Service
-		cursor := audit.NewCursor(pointerdb)
-
-		var stripe *audit.Stripe
-		for {
-			stripe, err = cursor.NextStripe(ctx)
-			if stripe != nil || err != nil {
-				break
-			}
-		}
+
+		planet.Satellites[0].Audit.Chore.Loop.TriggerWait()
+		stripe, err := planet.Satellites[0].Audit.Service.Cursor.NextStripe(ctx)
 		require.NoError(t, err)
 		require.NotNil(t, stripe)
 
@@ -61,12 +56,16 @@ func TestGetShareTimeout(t *testing.T) {
 		slowClient := network.NewClient(planet.Satellites[0].Transport)
 		require.NotNil(t, slowClient)
 
-		// This config value will create a very short timeframe allowed for receiving
-		// data from storage nodes. This will cause context to cancel and start
-		// downloading from new nodes.
-		minBytesPerSecond := 110 * memory.KB
+		// These config values create a very short timeframe allowed for dialing
+		// and receiving data from storage nodes. This will cause context to cancel
+		// and start downloading from new nodes.
+		config := audit.Config{
+			MinBytesPerSecond: 110 * memory.KB,
+			DialTimeout:       time.Second,
+			LongTailTimeout:   time.Second,
+		}
 		orders := planet.Satellites[0].Orders.Service
-		verifier := audit.NewVerifier(zap.L(), slowClient, overlay, orders, planet.Satellites[0].Identity, minBytesPerSecond)
+		verifier := audit.NewVerifier(zap.L(), slowClient, overlay, planet.Satellites[0].DB.Containment(), orders, planet.Satellites[0].Identity, config)
 		require.NotNil(t, verifier)
 
 		// stop some storage nodes to ensure audit can deal with it
@@ -81,7 +80,7 @@ func TestGetShareTimeout(t *testing.T) {
 			require.NoError(t, err)
 		}
 
-		_, err = verifier.Verify(ctx, stripe)
+		_, err = verifier.Verify(ctx, stripe, nil)
 		require.NoError(t, err)
 	})
 }
@@ -94,3 +93,64 @@ func stopStorageNode(planet *testplanet.Planet, nodeID storj.NodeID) error {
 	}
 	return fmt.Errorf("no such node: %s", nodeID.String())
 }
+
+// TestLongTailCutOff checks that the verifier stops waiting for a slow node
+// once the other nodes delivered their shares, and contains the slow node with
+// the share it should have delivered.
+func TestLongTailCutOff(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 5, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		require.NoError(t, satellite.Audit.Service.Close())
+
+		err := planet.Uplinks[0].Upload(ctx, satellite, "testbucket", "test/path", testrand.New(t).Bytes(10*memory.KiB.Int()))
+		require.NoError(t, err)
+
+		satellite.Audit.Chore.Loop.TriggerWait()
+		stripe, err := satellite.Audit.Service.Cursor.NextStripe(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, stripe)
+
+		remote := stripe.Segment.GetRemote()
+		slowNode := remote.GetRemotePieces()[0].NodeId
+
+		config := audit.Config{
+			MinBytesPerSecond:  1 * memory.KB,
+			DialTimeout:        time.Hour,
+			MinDownloadTimeout: time.Hour,
+			LongTailTimeout:    100 * time.Millisecond,
+		}
+		transport := &stallingTransport{Client: satellite.Transport, node: slowNode}
+		verifier := audit.NewVerifier(zap.L(), transport, satellite.Overlay.Service, satellite.DB.Containment(), satellite.Orders.Service, satellite.Identity, config)
+
+		report, err := verifier.Verify(ctx, stripe, nil)
+		require.NoError(t, err)
+		require.Len(t, report.SuccessNodeIDs, len(remote.GetRemotePieces())-1)
+		require.NotContains(t, report.SuccessNodeIDs, slowNode)
+		require.Empty(t, report.FailNodeIDs)
+		require.Empty(t, report.OfflineNodeIDs)
+
+		require.Len(t, report.PendingAudits, 1)
+		pending := report.PendingAudits[0]
+		require.Equal(t, slowNode, pending.NodeID)
+		require.Equal(t, stripe.Index, pending.StripeIndex)
+
+		share := readShare(ctx, t, planet, slowNode, remote.RootPieceId, stripe.Index, pending.ShareSize)
+		require.Equal(t, pkcrypto.SHA256Hash(share), pending.ExpectedShareHash)
+	})
+}
+
+// stallingTransport never connects to node.
+type stallingTransport struct {
+	transport.Client
+	node storj.NodeID
+}
+
+func (client *stallingTransport) DialNode(ctx context.Context, node *pb.Node, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	if node.Id == client.node {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return client.Client.DialNode(ctx, node, opts...)
+}
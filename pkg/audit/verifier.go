@@ -12,6 +12,8 @@ import (
 	"github.com/vivint/infectious"
 	"github.com/zeebo/errs"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	monkit "gopkg.in/spacemonkeygo/monkit.v2"
 
 	"storj.io/storj/internal/memory"
@@ -19,6 +21,7 @@ import (
 	"storj.io/storj/pkg/identity"
 	"storj.io/storj/pkg/overlay"
 	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/pkcrypto"
 	"storj.io/storj/pkg/storj"
 	"storj.io/storj/pkg/transport"
 	"storj.io/storj/satellite/orders"
@@ -34,18 +37,25 @@ type Share struct {
 	Error    error
 	PieceNum int
 	Data     []byte
+	// Header is the header of the piece sent by the storage node, nil for
+	// the pieces of format V0.
+	Header *pb.PieceHeader
 }
 
 // Verifier helps verify the correctness of a given stripe
 type Verifier struct {
-	orders  *orders.Service
-	auditor *identity.PeerIdentity
+	log         *zap.Logger
+	orders      *orders.Service
+	containment Containment
+	auditor     *identity.PeerIdentity
+
+	verifyPieceHashes bool
 
 	downloader downloader
 }
 
 type downloader interface {
-	DownloadShares(ctx context.Context, limits []*pb.AddressedOrderLimit, stripeIndex int64, shareSize int32) (shares map[int]Share, nodes map[int]storj.NodeID, err error)
+	DownloadShares(ctx context.Context, limits []*pb.AddressedOrderLimit, stripeIndex int64, shareSize int32, required int) (shares map[int]Share, nodes map[int]storj.NodeID, err error)
 }
 
 // defaultDownloader downloads shares from networked storage nodes
@@ -55,21 +65,42 @@ type defaultDownloader struct {
 	overlay   *overlay.Cache
 	reporter
 
-	minBytesPerSecond memory.Size
+	minBytesPerSecond  memory.Size
+	dialTimeout        time.Duration
+	minDownloadTimeout time.Duration
+	longTailTimeout    time.Duration
 }
 
 // newDefaultDownloader creates a defaultDownloader
-func newDefaultDownloader(log *zap.Logger, transport transport.Client, overlay *overlay.Cache, id *identity.FullIdentity, minBytesPerSecond memory.Size) *defaultDownloader {
-	return &defaultDownloader{log: log, transport: transport, overlay: overlay, minBytesPerSecond: minBytesPerSecond}
+func newDefaultDownloader(log *zap.Logger, transport transport.Client, overlay *overlay.Cache, config Config) *defaultDownloader {
+	return &defaultDownloader{
+		log:       log,
+		transport: transport,
+		overlay:   overlay,
+
+		minBytesPerSecond:  config.MinBytesPerSecond,
+		dialTimeout:        config.DialTimeout,
+		minDownloadTimeout: config.MinDownloadTimeout,
+		longTailTimeout:    config.LongTailTimeout,
+	}
 }
 
 // NewVerifier creates a Verifier
-func NewVerifier(log *zap.Logger, transport transport.Client, overlay *overlay.Cache, orders *orders.Service, id *identity.FullIdentity, minBytesPerSecond memory.Size) *Verifier {
-	return &Verifier{downloader: newDefaultDownloader(log, transport, overlay, id, minBytesPerSecond), orders: orders, auditor: id.PeerIdentity()}
+func NewVerifier(log *zap.Logger, transport transport.Client, overlay *overlay.Cache, containment Containment, orders *orders.Service, id *identity.FullIdentity, config Config) *Verifier {
+	return &Verifier{
+		log:         log,
+		downloader:  newDefaultDownloader(log, transport, overlay, config),
+		orders:      orders,
+		containment: containment,
+		auditor:     id.PeerIdentity(),
+
+		verifyPieceHashes: config.VerifyPieceHashes,
+	}
 }
 
-// Verify downloads shares then verifies the data correctness at the given stripe
-func (verifier *Verifier) Verify(ctx context.Context, stripe *Stripe) (verifiedNodes *RecordAuditsInfo, err error) {
+// Verify downloads shares then verifies the data correctness at the given stripe,
+// the nodes in skip aren't audited.
+func (verifier *Verifier) Verify(ctx context.Context, stripe *Stripe, skip map[storj.NodeID]bool) (verifiedNodes *RecordAuditsInfo, err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	pointer := stripe.Segment
@@ -81,32 +112,50 @@ func (verifier *Verifier) Verify(ctx context.Context, stripe *Stripe) (verifiedN
 		return nil, err
 	}
 
-	shares, nodes, err := verifier.downloader.DownloadShares(ctx, orderLimits, stripe.Index, shareSize)
+	for i, limit := range orderLimits {
+		if limit != nil && skip[limit.GetLimit().StorageNodeId] {
+			orderLimits[i] = nil
+		}
+	}
+
+	required := int(pointer.Remote.Redundancy.GetMinReq())
+	total := int(pointer.Remote.Redundancy.GetTotal())
+
+	shares, nodes, err := verifier.downloader.DownloadShares(ctx, orderLimits, stripe.Index, shareSize, required)
 	if err != nil {
 		return nil, err
 	}
 
 	var offlineNodes storj.NodeIDList
 	var failedNodes storj.NodeIDList
+	containedNodes := make(map[int]storj.NodeID)
 	sharesToAudit := make(map[int]Share)
 
 	for pieceNum, share := range shares {
-		if shares[pieceNum].Error != nil {
-			if shares[pieceNum].Error == context.DeadlineExceeded ||
-				!transport.Error.Has(shares[pieceNum].Error) {
+		switch {
+		case share.Error == nil:
+			// consistent shares of a piece the uplink never signed still fail
+			if err := verifier.verifyPieceHeader(ctx, pointer, nodes[pieceNum], share.Header); err != nil {
+				verifier.log.Debug("piece hash verification failed", zap.Stringer("Node ID", nodes[pieceNum]), zap.Error(err))
 				failedNodes = append(failedNodes, nodes[pieceNum])
-			} else {
-				offlineNodes = append(offlineNodes, nodes[pieceNum])
+				break
 			}
-		} else {
 			sharesToAudit[pieceNum] = share
+		case transport.Error.Has(share.Error):
+			offlineNodes = append(offlineNodes, nodes[pieceNum])
+		case isTimeout(share.Error):
+			// the node may just be slow, it has to deliver this share later
+			containedNodes[pieceNum] = nodes[pieceNum]
+		default:
+			failedNodes = append(failedNodes, nodes[pieceNum])
 		}
 	}
 
-	required := int(pointer.Remote.Redundancy.GetMinReq())
-	total := int(pointer.Remote.Redundancy.GetTotal())
-
 	if len(sharesToAudit) < required {
+		// the expected shares can't be known, so the slow nodes can't be contained
+		for _, nodeID := range containedNodes {
+			offlineNodes = append(offlineNodes, nodeID)
+		}
 		return &RecordAuditsInfo{
 			SuccessNodeIDs: nil,
 			FailNodeIDs:    failedNodes,
@@ -114,7 +163,7 @@ func (verifier *Verifier) Verify(ctx context.Context, stripe *Stripe) (verifiedN
 		}, nil
 	}
 
-	pieceNums, err := auditShares(ctx, required, total, sharesToAudit)
+	pieceNums, correctedShares, err := auditShares(ctx, required, total, sharesToAudit)
 	if err != nil {
 		return nil, err
 	}
@@ -123,40 +172,151 @@ func (verifier *Verifier) Verify(ctx context.Context, stripe *Stripe) (verifiedN
 		failedNodes = append(failedNodes, nodes[pieceNum])
 	}
 
-	successNodes := getSuccessNodes(ctx, nodes, failedNodes, offlineNodes)
+	successNodes := getSuccessNodes(ctx, nodes, failedNodes, offlineNodes, containedNodes)
+
+	pendingAudits, err := createPendingAudits(ctx, containedNodes, correctedShares, stripe)
+	if err != nil {
+		return nil, err
+	}
 
 	return &RecordAuditsInfo{
 		SuccessNodeIDs: successNodes,
 		FailNodeIDs:    failedNodes,
 		OfflineNodeIDs: offlineNodes,
+		PendingAudits:  pendingAudits,
 	}, nil
 }
 
-// Download Shares downloads shares from the nodes where remote pieces are located
-func (d *defaultDownloader) DownloadShares(ctx context.Context, limits []*pb.AddressedOrderLimit, stripeIndex int64, shareSize int32) (shares map[int]Share, nodes map[int]storj.NodeID, err error) {
+// Reverify requests the shares that the contained nodes of the stripe's segment
+// failed to deliver earlier, and checks them against the expected hashes. A
+// node that delivers its share, whether correct or not, leaves containment.
+func (verifier *Verifier) Reverify(ctx context.Context, stripe *Stripe) (report *RecordAuditsInfo, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	report = &RecordAuditsInfo{Reverified: map[storj.NodeID]*PendingAudit{}}
+
+	for _, piece := range stripe.Segment.GetRemote().GetRemotePieces() {
+		pending, err := verifier.containment.Get(ctx, piece.NodeId)
+		if err != nil {
+			if ErrContainedNotFound.Has(err) {
+				continue
+			}
+			return nil, Error.Wrap(err)
+		}
+		report.Reverified[pending.NodeID] = pending
+
+		// the pending audit may belong to another segment of the node, the
+		// ones created before their path was stored are attributed to this one
+		path := pending.Path
+		if path == "" {
+			path = stripe.SegmentPath
+		}
+
+		limit, err := verifier.orders.CreateAuditOrderLimit(ctx, verifier.auditor, createBucketID(path), pending.NodeID, pending.PieceID, pending.ShareSize)
+		if err != nil {
+			if overlay.ErrNodeOffline.Has(err) {
+				report.OfflineNodeIDs = append(report.OfflineNodeIDs, pending.NodeID)
+				continue
+			}
+			return nil, Error.Wrap(err)
+		}
+
+		shares, _, err := verifier.downloader.DownloadShares(ctx, []*pb.AddressedOrderLimit{limit}, pending.StripeIndex, pending.ShareSize, 1)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		share := shares[0]
+
+		switch {
+		case share.Error == nil:
+			if bytes.Equal(pkcrypto.SHA256Hash(share.Data), pending.ExpectedShareHash) {
+				report.SuccessNodeIDs = append(report.SuccessNodeIDs, pending.NodeID)
+			} else {
+				report.FailNodeIDs = append(report.FailNodeIDs, pending.NodeID)
+			}
+		case transport.Error.Has(share.Error):
+			// the node stays contained until it can be reached
+			report.OfflineNodeIDs = append(report.OfflineNodeIDs, pending.NodeID)
+			continue
+		case isTimeout(share.Error):
+			report.PendingAudits = append(report.PendingAudits, pending)
+			continue
+		default:
+			verifier.log.Debug("contained node failed to deliver its share", zap.Stringer("Node ID", pending.NodeID), zap.Error(share.Error))
+			report.FailNodeIDs = append(report.FailNodeIDs, pending.NodeID)
+		}
+
+		_, err = verifier.containment.Delete(ctx, pending.NodeID)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+	}
+
+	return report, nil
+}
+
+// DownloadShares downloads the shares from the nodes where remote pieces are located concurrently.
+// Once the required number of shares were downloaded, the remaining downloads get the long tail
+// timeout to finish, the ones that don't finish in time fail with context.DeadlineExceeded.
+func (d *defaultDownloader) DownloadShares(ctx context.Context, limits []*pb.AddressedOrderLimit, stripeIndex int64, shareSize int32, required int) (shares map[int]Share, nodes map[int]storj.NodeID, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	shares = make(map[int]Share, len(limits))
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan Share, len(limits))
 	nodes = make(map[int]storj.NodeID, len(limits))
 
 	for i, limit := range limits {
 		if limit == nil {
 			continue
 		}
+		nodes[i] = limit.GetLimit().StorageNodeId
+
+		go func(pieceNum int, limit *pb.AddressedOrderLimit) {
+			share, err := d.getShare(ctx, limit, stripeIndex, shareSize, pieceNum)
+			if err != nil {
+				share = Share{
+					Error:    err,
+					PieceNum: pieceNum,
+					Data:     nil,
+				}
+			}
+			results <- share
+		}(i, limit)
+	}
 
-		share, err := d.getShare(ctx, limit, stripeIndex, shareSize, i)
-		if err != nil {
-			share = Share{
-				Error:    err,
-				PieceNum: i,
-				Data:     nil,
+	shares = make(map[int]Share, len(nodes))
+	successful, slow := 0, 0
+	cutOff := false
+
+	var longTail <-chan time.Time
+	for len(shares) < len(nodes) {
+		select {
+		case share := <-results:
+			if share.Error != nil && cutOff {
+				// the download was canceled, the node was too slow
+				share.Error = context.DeadlineExceeded
+				slow++
+			}
+			shares[share.PieceNum] = share
+
+			if share.Error == nil {
+				successful++
+				if successful == required && longTail == nil {
+					timer := time.NewTimer(d.longTailTimeout)
+					defer timer.Stop()
+					longTail = timer.C
+				}
 			}
+		case <-longTail:
+			cutOff = true
+			longTail = nil
+			cancel()
 		}
-
-		shares[share.PieceNum] = share
-		nodes[share.PieceNum] = limit.GetLimit().StorageNodeId
 	}
 
+	mon.IntVal("audit_long_tail_cut_off").Observe(int64(slow))
 	return shares, nodes, nil
 }
 
@@ -164,20 +324,16 @@ func (d *defaultDownloader) DownloadShares(ctx context.Context, limits []*pb.Add
 func (d *defaultDownloader) getShare(ctx context.Context, limit *pb.AddressedOrderLimit, stripeIndex int64, shareSize int32, pieceNum int) (share Share, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	bandwidthMsgSize := shareSize
+	storageNodeID := limit.GetLimit().StorageNodeId
 
-	// determines number of seconds allotted for receiving data from a storage node
-	timedCtx := ctx
-	if d.minBytesPerSecond > 0 {
-		maxTransferTime := time.Duration(int64(time.Second) * int64(bandwidthMsgSize) / d.minBytesPerSecond.Int64())
+	dialCtx := ctx
+	if d.dialTimeout > 0 {
 		var cancel func()
-		timedCtx, cancel = context.WithTimeout(ctx, maxTransferTime)
+		dialCtx, cancel = context.WithTimeout(ctx, d.dialTimeout)
 		defer cancel()
 	}
 
-	storageNodeID := limit.GetLimit().StorageNodeId
-
-	conn, err := d.transport.DialNode(timedCtx, &pb.Node{
+	conn, err := d.transport.DialNode(dialCtx, &pb.Node{
 		Id:      storageNodeID,
 		Address: limit.GetStorageNodeAddress(),
 		Type:    pb.NodeType_STORAGE,
@@ -191,6 +347,19 @@ func (d *defaultDownloader) getShare(ctx context.Context, limit *pb.AddressedOrd
 		conn,
 		piecestore.DefaultConfig,
 	)
+	defer func() { err = errs.Combine(err, ps.Close()) }()
+
+	// determines number of seconds allotted for receiving data from a storage node
+	timedCtx := ctx
+	if d.minBytesPerSecond > 0 {
+		maxTransferTime := time.Duration(int64(time.Second) * int64(shareSize) / d.minBytesPerSecond.Int64())
+		if maxTransferTime < d.minDownloadTimeout {
+			maxTransferTime = d.minDownloadTimeout
+		}
+		var cancel func()
+		timedCtx, cancel = context.WithTimeout(ctx, maxTransferTime)
+		defer cancel()
+	}
 
 	offset := int64(shareSize) * stripeIndex
 
@@ -210,26 +379,28 @@ func (d *defaultDownloader) getShare(ctx context.Context, limit *pb.AddressedOrd
 		Error:    nil,
 		PieceNum: pieceNum,
 		Data:     buf,
+		Header:   downloader.GetPieceHeader(),
 	}, nil
 }
 
 // auditShares takes the downloaded shares and uses infectious's Correct function to check that they
-// haven't been altered. auditShares returns a slice containing the piece numbers of altered shares.
-func auditShares(ctx context.Context, required, total int, originals map[int]Share) (pieceNums []int, err error) {
+// haven't been altered. auditShares returns a slice containing the piece numbers of altered shares,
+// and the corrected shares.
+func auditShares(ctx context.Context, required, total int, originals map[int]Share) (pieceNums []int, corrected []infectious.Share, err error) {
 	defer mon.Task()(&ctx)(&err)
 	f, err := infectious.NewFEC(required, total)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	copies, err := makeCopies(ctx, originals)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	err = f.Correct(copies)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	for _, share := range copies {
@@ -237,7 +408,53 @@ func auditShares(ctx context.Context, required, total int, originals map[int]Sha
 			pieceNums = append(pieceNums, share.Number)
 		}
 	}
-	return pieceNums, nil
+	return pieceNums, copies, nil
+}
+
+// createPendingAudits computes the shares that the contained nodes should
+// have delivered from the corrected shares of the stripe.
+func createPendingAudits(ctx context.Context, containedNodes map[int]storj.NodeID, correctedShares []infectious.Share, stripe *Stripe) (pending []*PendingAudit, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(containedNodes) == 0 {
+		return nil, nil
+	}
+
+	redundancy := stripe.Segment.GetRemote().GetRedundancy()
+	required := int(redundancy.GetMinReq())
+	total := int(redundancy.GetTotal())
+	shareSize := redundancy.GetErasureShareSize()
+
+	fec, err := infectious.NewFEC(required, total)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	stripeData := make([]byte, required*int(shareSize))
+	err = fec.Rebuild(correctedShares, func(share infectious.Share) {
+		copy(stripeData[share.Number*int(shareSize):], share.Data)
+	})
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	for pieceNum, nodeID := range containedNodes {
+		share := make([]byte, shareSize)
+		err = fec.EncodeSingle(stripeData, share, pieceNum)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		pending = append(pending, &PendingAudit{
+			NodeID:            nodeID,
+			PieceID:           stripe.Segment.GetRemote().RootPieceId,
+			StripeIndex:       stripe.Index,
+			ShareSize:         shareSize,
+			ExpectedShareHash: pkcrypto.SHA256Hash(share),
+			Path:              stripe.SegmentPath,
+		})
+	}
+
+	return pending, nil
 }
 
 // makeCopies takes in a map of audit Shares and deep copies their data to a slice of infectious Shares
@@ -252,8 +469,8 @@ func makeCopies(ctx context.Context, originals map[int]Share) (copies []infectio
 	return copies, nil
 }
 
-// getSuccessNodes uses the failed nodes and offline nodes arrays to determine which nodes passed the audit
-func getSuccessNodes(ctx context.Context, nodes map[int]storj.NodeID, failedNodes, offlineNodes storj.NodeIDList) (successNodes storj.NodeIDList) {
+// getSuccessNodes uses the failed, offline and contained nodes to determine which nodes passed the audit
+func getSuccessNodes(ctx context.Context, nodes map[int]storj.NodeID, failedNodes, offlineNodes storj.NodeIDList, containedNodes map[int]storj.NodeID) (successNodes storj.NodeIDList) {
 	fails := make(map[storj.NodeID]bool)
 	for _, fail := range failedNodes {
 		fails[fail] = true
@@ -261,6 +478,9 @@ func getSuccessNodes(ctx context.Context, nodes map[int]storj.NodeID, failedNode
 	for _, offline := range offlineNodes {
 		fails[offline] = true
 	}
+	for _, contained := range containedNodes {
+		fails[contained] = true
+	}
 
 	for _, node := range nodes {
 		if !fails[node] {
@@ -271,6 +491,12 @@ func getSuccessNodes(ctx context.Context, nodes map[int]storj.NodeID, failedNode
 	return successNodes
 }
 
+// isTimeout returns whether err is caused by the node not delivering the share in time
+func isTimeout(err error) bool {
+	err = errs.Unwrap(err)
+	return err == context.DeadlineExceeded || status.Code(err) == codes.DeadlineExceeded
+}
+
 func createBucketID(path storj.Path) []byte {
 	comps := storj.SplitPath(path)
 	if len(comps) < 2 {
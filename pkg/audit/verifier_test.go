@@ -7,13 +7,13 @@ package audit_test
 
 import (
 	"context"
-	"crypto/rand"
 	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/vivint/infectious"
 
+	"storj.io/storj/internal/testrand"
 	"storj.io/storj/internal/teststorj"
 	"storj.io/storj/pkg/audit"
 	"storj.io/storj/pkg/pb"
@@ -37,7 +37,7 @@ func TestPassingAudit(t *testing.T) {
 	}{
 		{nodeAmt: 30, shareAmt: 30, required: 20, total: 40, err: nil},
 	} {
-		someData := randData(32 * 1024)
+		someData := testrand.New(t).Bytes(32 * 1024)
 		for i := 0; i < tt.shareAmt; i++ {
 			mockShares[i] = audit.Share{
 				Error:       tt.err,
@@ -73,7 +73,7 @@ func TestSomeNodesPassAudit(t *testing.T) {
 	}{
 		{nodeAmt: 30, shareAmt: 30, required: 20, total: 40, err0: Error.New("unable to get node"), err1: nil},
 	} {
-		someData := randData(32 * 1024)
+		someData := testrand.New(t).Bytes(32 * 1024)
 		for i := 0; i < 10; i++ {
 			mockShares[i] = share{
 				Error:       tt.err0,
@@ -241,12 +241,3 @@ func makePointer(nodeAmt int) *pb.Pointer {
 	}
 	return pr
 }
-
-func randData(amount int) []byte {
-	buf := make([]byte, amount)
-	_, err := rand.Read(buf)
-	if err != nil {
-		panic(err)
-	}
-	return buf
-}
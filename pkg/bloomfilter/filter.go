@@ -0,0 +1,136 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package bloomfilter implements a bloom filter of piece IDs, which are sent
+// by the satellites to the storage nodes for garbage collection.
+package bloomfilter
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"math"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/storj"
+)
+
+// Error is the default error class for bloom filters.
+var Error = errs.Class("bloom filter")
+
+const (
+	// version is the version of the serialized filter.
+	version = 1
+	// maxHashCount is the maximum number of hash functions of a filter.
+	maxHashCount = 32
+	// maxOffset is the last offset of the 16 bytes of the piece id used for hashing.
+	maxOffset = len(storj.PieceID{}) - 16
+)
+
+// Filter is a bloom filter of piece IDs.
+//
+// As piece IDs are random, the hashes are taken from the piece ID bytes, at
+// an offset depending on the seed, so that a different seed gives different
+// false positives.
+type Filter struct {
+	seed      byte
+	hashCount byte
+	table     []byte
+}
+
+// NewOptimal returns a filter sized for expectedElements, with about
+// falsePositiveRate false positives, and a random seed.
+func NewOptimal(expectedElements int, falsePositiveRate float64) *Filter {
+	bitsPerElement := -math.Log2(falsePositiveRate) / math.Ln2
+	hashCount := int(math.Ceil(bitsPerElement * math.Ln2))
+	if hashCount < 1 {
+		hashCount = 1
+	} else if hashCount > maxHashCount {
+		hashCount = maxHashCount
+	}
+
+	sizeInBytes := int(math.Ceil(float64(expectedElements) * bitsPerElement / 8))
+	if sizeInBytes < 1 {
+		sizeInBytes = 1
+	}
+
+	var seed [1]byte
+	_, _ = rand.Read(seed[:])
+
+	return NewExplicit(seed[0], byte(hashCount), sizeInBytes)
+}
+
+// NewExplicit returns an empty filter with the given parameters.
+func NewExplicit(seed, hashCount byte, sizeInBytes int) *Filter {
+	return &Filter{
+		seed:      seed,
+		hashCount: hashCount,
+		table:     make([]byte, sizeInBytes),
+	}
+}
+
+// Add adds the piece ID to the filter.
+func (filter *Filter) Add(pieceID storj.PieceID) {
+	filter.bits(pieceID, func(bucket uint64, bit byte) bool {
+		filter.table[bucket] |= bit
+		return true
+	})
+}
+
+// Contains returns whether the piece ID may have been added to the filter,
+// it is false only when the piece ID was never added.
+func (filter *Filter) Contains(pieceID storj.PieceID) bool {
+	contains := true
+	filter.bits(pieceID, func(bucket uint64, bit byte) bool {
+		contains = filter.table[bucket]&bit != 0
+		return contains
+	})
+	return contains
+}
+
+// bits calls fn with the bits of the piece ID in the table, until fn returns false.
+func (filter *Filter) bits(pieceID storj.PieceID, fn func(bucket uint64, bit byte) bool) {
+	offset := int(filter.seed) % (maxOffset + 1)
+	h1 := binary.LittleEndian.Uint64(pieceID[offset : offset+8])
+	h2 := binary.LittleEndian.Uint64(pieceID[offset+8:offset+16]) | 1
+
+	size := uint64(len(filter.table)) * 8
+	for k := uint64(0); k < uint64(filter.hashCount); k++ {
+		index := (h1 + k*h2) % size
+		if !fn(index/8, 1<<(index%8)) {
+			return
+		}
+	}
+}
+
+// Size returns the size of the serialized filter.
+func (filter *Filter) Size() int {
+	return 3 + len(filter.table)
+}
+
+// Bytes serializes the filter.
+func (filter *Filter) Bytes() []byte {
+	data := make([]byte, 0, filter.Size())
+	data = append(data, version, filter.seed, filter.hashCount)
+	return append(data, filter.table...)
+}
+
+// NewFromBytes deserializes a filter serialized with Bytes.
+func NewFromBytes(data []byte) (*Filter, error) {
+	if len(data) < 4 {
+		return nil, Error.New("too short")
+	}
+	if data[0] != version {
+		return nil, Error.New("unsupported version %d", data[0])
+	}
+
+	filter := &Filter{
+		seed:      data[1],
+		hashCount: data[2],
+		table:     append([]byte{}, data[3:]...),
+	}
+	if filter.hashCount == 0 || filter.hashCount > maxHashCount {
+		return nil, Error.New("invalid hash count %d", filter.hashCount)
+	}
+	return filter, nil
+}
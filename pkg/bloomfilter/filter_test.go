@@ -0,0 +1,74 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package bloomfilter_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/pkg/bloomfilter"
+	"storj.io/storj/pkg/storj"
+)
+
+func TestFilter(t *testing.T) {
+	const count = 10000
+
+	for _, test := range []struct {
+		filter            *bloomfilter.Filter
+		falsePositiveRate float64
+	}{
+		{bloomfilter.NewOptimal(count, 0.05), 0.05},
+		{bloomfilter.NewOptimal(count, 0.01), 0.01},
+		// 8 bits per element with 4 hashes, (1 - e^(-4/8))^4
+		{bloomfilter.NewExplicit(7, 4, count), 0.024},
+		{bloomfilter.NewExplicit(200, 4, count), 0.024},
+	} {
+		filter := test.filter
+
+		added := make([]storj.PieceID, count)
+		for i := range added {
+			added[i] = storj.NewPieceID()
+			filter.Add(added[i])
+		}
+
+		// there are no false negatives
+		for _, pieceID := range added {
+			require.True(t, filter.Contains(pieceID))
+		}
+
+		var falsePositives int
+		for i := 0; i < count; i++ {
+			if filter.Contains(storj.NewPieceID()) {
+				falsePositives++
+			}
+		}
+		assert.InDelta(t, test.falsePositiveRate, float64(falsePositives)/count, test.falsePositiveRate/2)
+	}
+}
+
+func TestFilterBytes(t *testing.T) {
+	filter := bloomfilter.NewOptimal(1000, 0.1)
+	added := storj.NewPieceID()
+	filter.Add(added)
+
+	data := filter.Bytes()
+	require.Equal(t, filter.Size(), len(data))
+
+	decoded, err := bloomfilter.NewFromBytes(data)
+	require.NoError(t, err)
+	require.True(t, decoded.Contains(added))
+	require.Equal(t, data, decoded.Bytes())
+
+	for _, invalid := range [][]byte{
+		nil,
+		{1, 0, 1},
+		{2, 0, 1, 0},
+		{1, 0, 0, 0},
+	} {
+		_, err := bloomfilter.NewFromBytes(invalid)
+		require.True(t, bloomfilter.Error.Has(err), "%v", invalid)
+	}
+}
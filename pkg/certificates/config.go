@@ -72,7 +72,7 @@ func (c CertServerConfig) NewAuthDB() (*AuthorizationDB, error) {
 		if err != nil {
 			return nil, ErrAuthorizationDB.Wrap(err)
 		}
-	case "redis":
+	case "redis", "redis-sentinel", "redis-cluster":
 		redisClient, err := redis.NewClientFrom(c.AuthorizationDBURL)
 		if err != nil {
 			return nil, ErrAuthorizationDB.Wrap(err)
@@ -5,9 +5,9 @@ package checker
 
 import (
 	"context"
+	"fmt"
 	"time"
 
-	"github.com/gogo/protobuf/proto"
 	"github.com/zeebo/errs"
 	"go.uber.org/zap"
 	monkit "gopkg.in/spacemonkeygo/monkit.v2"
@@ -19,7 +19,7 @@ import (
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/pointerdb"
 	"storj.io/storj/pkg/storj"
-	"storj.io/storj/storage"
+	"storj.io/storj/satellite/metainfo"
 )
 
 // Error is a standard error class for this package.
@@ -30,29 +30,44 @@ var (
 
 // Config contains configurable values for checker
 type Config struct {
-	Interval time.Duration `help:"how frequently checker should audit segments" default:"30s"`
+	Interval                  time.Duration `help:"how frequently checker should audit segments" default:"30s"`
+	RepairOverrides           string        `help:"comma separated repair thresholds used instead of the thresholds of the pointers, as min/success/total-threshold, e.g. 29/80/95-52" default:""`
+	ReliabilityCacheStaleness time.Duration `help:"how stale the reliable nodes used by the checker and the repairer may get before they are reloaded" default:"5m0s"`
 }
 
 // Checker contains the information needed to do checks for missing pieces
 type Checker struct {
-	pointerdb   *pointerdb.Service
-	repairQueue queue.RepairQueue
-	overlay     *overlay.Cache
-	irrdb       irreparable.DB
-	logger      *zap.Logger
-	Loop        sync2.Cycle
+	metainfoLoop *metainfo.Loop
+	pointerdb    *pointerdb.Service
+	repairQueue  queue.RepairQueue
+	overlay      *overlay.Cache
+	reliability  *overlay.ReliabilityCache
+	irrdb        irreparable.DB
+	logger       *zap.Logger
+	Loop         sync2.Cycle
+
+	repairOverrides RepairOverrides
+
+	// irreparableCount is the number of irreparable segments after the last
+	// iteration, -1 before the first
+	irreparableCount int64
 }
 
 // NewChecker creates a new instance of checker
-func NewChecker(pointerdb *pointerdb.Service, repairQueue queue.RepairQueue, overlay *overlay.Cache, irrdb irreparable.DB, limit int, logger *zap.Logger, interval time.Duration) *Checker {
+func NewChecker(metainfoLoop *metainfo.Loop, pointerdb *pointerdb.Service, repairQueue queue.RepairQueue, overlay *overlay.Cache, reliability *overlay.ReliabilityCache, irrdb irreparable.DB, limit int, logger *zap.Logger, interval time.Duration, repairOverrides RepairOverrides) *Checker {
 	// TODO: reorder arguments
 	checker := &Checker{
-		pointerdb:   pointerdb,
-		repairQueue: repairQueue,
-		overlay:     overlay,
-		irrdb:       irrdb,
-		logger:      logger,
-		Loop:        *sync2.NewCycle(interval),
+		metainfoLoop: metainfoLoop,
+		pointerdb:    pointerdb,
+		repairQueue:  repairQueue,
+		overlay:      overlay,
+		reliability:  reliability,
+		irrdb:        irrdb,
+		logger:       logger,
+		Loop:         *sync2.NewCycle(interval),
+
+		repairOverrides:  repairOverrides,
+		irreparableCount: -1,
 	}
 	return checker
 }
@@ -64,6 +79,9 @@ func (checker *Checker) Run(ctx context.Context) (err error) {
 	return checker.Loop.Run(ctx, func(ctx context.Context) error {
 		err := checker.IdentifyInjuredSegments(ctx)
 		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
 			checker.logger.Error("error with injured segments identification: ", zap.Error(err))
 		}
 		return nil
@@ -76,122 +94,176 @@ func (checker *Checker) Close() error {
 	return nil
 }
 
-// IdentifyInjuredSegments checks for missing pieces off of the pointerdb and overlay cache
+// IdentifyInjuredSegments checks for missing pieces off of the metainfo loop and overlay cache
 func (checker *Checker) IdentifyInjuredSegments(ctx context.Context) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var remoteSegmentsChecked int64
-	var remoteSegmentsNeedingRepair int64
-	var remoteSegmentsLost int64
-	var remoteSegmentInfo []string
-
-	err = checker.pointerdb.Iterate("", "", true, false,
-		func(it storage.Iterator) error {
-			var item storage.ListItem
-			for it.Next(&item) {
-				pointer := &pb.Pointer{}
-
-				err = proto.Unmarshal(item.Value, pointer)
-				if err != nil {
-					return Error.New("error unmarshalling pointer %s", err)
-				}
-
-				remote := pointer.GetRemote()
-				if remote == nil {
-					continue
-				}
-
-				pieces := remote.GetRemotePieces()
-				if pieces == nil {
-					checker.logger.Debug("no pieces on remote segment")
-					continue
-				}
-
-				var nodeIDs storj.NodeIDList
-				for _, p := range pieces {
-					nodeIDs = append(nodeIDs, p.NodeId)
-				}
-
-				// Find all offline nodes
-				offlineNodes, err := checker.overlay.OfflineNodes(ctx, nodeIDs)
-				if err != nil {
-					return Error.New("error getting offline nodes %s", err)
-				}
-
-				invalidNodes, err := checker.invalidNodes(ctx, nodeIDs)
-				if err != nil {
-					return Error.New("error getting invalid nodes %s", err)
-				}
-
-				missingPieces := combineOfflineWithInvalid(offlineNodes, invalidNodes)
-
-				remoteSegmentsChecked++
-				numHealthy := len(nodeIDs) - len(missingPieces)
-				if (int32(numHealthy) >= pointer.Remote.Redundancy.MinReq) && (int32(numHealthy) < pointer.Remote.Redundancy.RepairThreshold) {
-					remoteSegmentsNeedingRepair++
-					err = checker.repairQueue.Enqueue(ctx, &pb.InjuredSegment{
-						Path:       string(item.Key),
-						LostPieces: missingPieces,
-					})
-					if err != nil {
-						return Error.New("error adding injured segment to queue %s", err)
-					}
-				} else if int32(numHealthy) < pointer.Remote.Redundancy.MinReq {
-					pathElements := storj.SplitPath(storj.Path(item.Key))
-					// check to make sure there are at least *4* path elements. the first three
-					// are project, segment, and bucket name, but we want to make sure we're talking
-					// about an actual object, and that there's an object name specified
-					if len(pathElements) >= 4 {
-						project, bucketName, segmentpath := pathElements[0], pathElements[2], pathElements[3]
-						lostSegInfo := storj.JoinPaths(project, bucketName, segmentpath)
-						if contains(remoteSegmentInfo, lostSegInfo) == false {
-							remoteSegmentInfo = append(remoteSegmentInfo, lostSegInfo)
-						}
-					}
-
-					// TODO: irreparable segment should be using storj.NodeID or something, since at the point of repair
-					//       it may have been already repaired once.
-
-					remoteSegmentsLost++
-					// make an entry in to the irreparable table
-					segmentInfo := &pb.IrreparableSegment{
-						Path:               item.Key,
-						SegmentDetail:      pointer,
-						LostPieces:         int32(len(missingPieces)),
-						LastRepairAttempt:  time.Now().Unix(),
-						RepairAttemptCount: int64(1),
-					}
-
-					//add the entry if new or update attempt count if already exists
-					err := checker.irrdb.IncrementRepairAttempts(ctx, segmentInfo)
-					if err != nil {
-						return Error.New("error handling irreparable segment to queue %s", err)
-					}
-				}
-			}
-			return nil
-		},
-	)
+	// NB: the reliable nodes are loaded before joining the metainfo loop, so
+	// checking the segments doesn't hold up the loop shared with the other
+	// observers with overlay queries
+	if err := checker.reliability.Refresh(ctx); err != nil {
+		return Error.Wrap(err)
+	}
+
+	observer := &checkerObserver{checker: checker}
+	err = checker.metainfoLoop.Join(ctx, observer)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	mon.IntVal("remote_segments_checked").Observe(observer.remoteSegmentsChecked)
+	mon.IntVal("remote_segments_needing_repair").Observe(observer.remoteSegmentsNeedingRepair)
+	mon.IntVal("new_remote_segments_needing_repair").Observe(observer.newRemoteSegmentsNeedingRepair)
+	mon.IntVal("remote_segments_lost").Observe(observer.remoteSegmentsLost)
+	mon.IntVal("remote_files_lost").Observe(int64(len(observer.remoteSegmentInfo)))
+
+	return checker.reportIrreparable(ctx)
+}
+
+// reportIrreparable reports the number of irreparable segments and warns when
+// it grew since the last iteration.
+func (checker *Checker) reportIrreparable(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	count, err := checker.irrdb.Count(ctx)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	mon.IntVal("irreparable_segments").Observe(count)
+
+	if checker.irreparableCount >= 0 && count > checker.irreparableCount {
+		mon.Meter("irreparable_segments_added").Mark64(count - checker.irreparableCount)
+		checker.logger.Warn("irreparable segments count grew",
+			zap.Int64("previous", checker.irreparableCount),
+			zap.Int64("count", count))
+	}
+	checker.irreparableCount = count
+	return nil
+}
+
+// SegmentHealth returns the health of a segment with numHealthy healthy
+// pieces: 0 when only the minimum required pieces are left and 1 when the
+// pieces of the success threshold are healthy. A segment that can't be
+// recovered anymore has a negative health.
+func SegmentHealth(numHealthy int32, redundancy *pb.RedundancyScheme) float64 {
+	minReq := redundancy.GetMinReq()
+	target := redundancy.GetSuccessThreshold()
+	if target < redundancy.GetRepairThreshold() {
+		// NB: the success threshold may be missing from older pointers
+		target = redundancy.GetRepairThreshold()
+	}
+	if target <= minReq {
+		return float64(numHealthy - minReq)
+	}
+	return float64(numHealthy-minReq) / float64(target-minReq)
+}
+
+// checkerObserver checks the segments of a single iteration of the metainfo loop
+type checkerObserver struct {
+	checker *Checker
+
+	remoteSegmentsChecked          int64
+	remoteSegmentsNeedingRepair    int64
+	newRemoteSegmentsNeedingRepair int64
+	remoteSegmentsLost             int64
+	remoteSegmentInfo              []string
+}
+
+// RemoteSegment enqueues the segment for repair when it has fewer healthy
+// pieces than the repair threshold, or the override of its redundancy scheme.
+func (observer *checkerObserver) RemoteSegment(ctx context.Context, path storj.Path, pointer *pb.Pointer) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	checker := observer.checker
+
+	pieces := pointer.GetRemote().GetRemotePieces()
+	if pieces == nil {
+		checker.logger.Debug("no pieces on remote segment")
+		return nil
+	}
+
+	missingPieces, err := checker.reliability.MissingPieces(ctx, pieces)
 	if err != nil {
-		return err
+		return Error.New("error getting missing pieces %s", err)
 	}
-	mon.IntVal("remote_segments_checked").Observe(remoteSegmentsChecked)
-	mon.IntVal("remote_segments_needing_repair").Observe(remoteSegmentsNeedingRepair)
-	mon.IntVal("remote_segments_lost").Observe(remoteSegmentsLost)
-	mon.IntVal("remote_files_lost").Observe(int64(len(remoteSegmentInfo)))
 
+	observer.remoteSegmentsChecked++
+	redundancy := pointer.Remote.Redundancy
+	repairThreshold := checker.repairOverrides.RepairThreshold(redundancy)
+	numHealthy := int32(len(pieces) - len(missingPieces))
+	if numHealthy >= redundancy.MinReq && numHealthy < repairThreshold {
+		observer.remoteSegmentsNeedingRepair++
+		alreadyInserted, err := checker.repairQueue.Insert(ctx, &pb.InjuredSegment{
+			Path:       path,
+			LostPieces: missingPieces,
+			Health:     SegmentHealth(numHealthy, redundancy),
+		})
+		if err != nil {
+			return Error.New("error adding injured segment to queue %s", err)
+		}
+		if !alreadyInserted {
+			observer.newRemoteSegmentsNeedingRepair++
+		}
+	} else if numHealthy < redundancy.MinReq {
+		pathElements := storj.SplitPath(path)
+		// check to make sure there are at least *4* path elements. the first three
+		// are project, segment, and bucket name, but we want to make sure we're talking
+		// about an actual object, and that there's an object name specified
+		if len(pathElements) >= 4 {
+			project, bucketName, segmentpath := pathElements[0], pathElements[2], pathElements[3]
+			lostSegInfo := storj.JoinPaths(project, bucketName, segmentpath)
+			if contains(observer.remoteSegmentInfo, lostSegInfo) == false {
+				observer.remoteSegmentInfo = append(observer.remoteSegmentInfo, lostSegInfo)
+			}
+		}
+
+		// TODO: irreparable segment should be using storj.NodeID or something, since at the point of repair
+		//       it may have been already repaired once.
+
+		observer.remoteSegmentsLost++
+		// make an entry in to the irreparable table, the segment isn't
+		// repaired so no repair attempt is counted
+		segmentInfo := &pb.IrreparableSegment{
+			Path:              []byte(path),
+			SegmentDetail:     pointer,
+			LostPieces:        int32(len(missingPieces)),
+			LostPieceNums:     missingPieces,
+			LastRepairAttempt: time.Now().Unix(),
+			LastError:         fmt.Sprintf("%d healthy pieces, %d required", numHealthy, redundancy.MinReq),
+		}
+
+		// add the entry if new or update its lost pieces if already exists
+		err := checker.irrdb.Upsert(ctx, segmentInfo)
+		if err != nil {
+			return Error.New("error handling irreparable segment to queue %s", err)
+		}
+	}
+	return nil
+}
+
+// RemoteObject is called for the last segment of every remote object.
+func (observer *checkerObserver) RemoteObject(ctx context.Context, path storj.Path, pointer *pb.Pointer) (err error) {
+	return nil
+}
+
+// InlineSegment is called for every inline segment, inline segments are never repaired.
+func (observer *checkerObserver) InlineSegment(ctx context.Context, path storj.Path, pointer *pb.Pointer) (err error) {
 	return nil
 }
 
 // Find invalidNodes by checking the audit results that are place in overlay
 func (checker *Checker) invalidNodes(ctx context.Context, nodeIDs storj.NodeIDList) (invalidNodes []int, err error) {
+	return findInvalidNodes(ctx, checker.overlay, nodeIDs)
+}
+
+// findInvalidNodes returns the indexes of the nodes marked invalid by the overlay.
+func findInvalidNodes(ctx context.Context, cache *overlay.Cache, nodeIDs storj.NodeIDList) (invalidNodes []int, err error) {
 	// filter if nodeIDs have invalid pieces from auditing results
 	maxStats := &overlay.NodeStats{
 		AuditSuccessRatio: 0, // TODO: update when we have stats added to overlay
 		UptimeRatio:       0, // TODO: update when we have stats added to overlay
 	}
 
-	invalidIDs, err := checker.overlay.FindInvalidNodes(ctx, nodeIDs, maxStats)
+	invalidIDs, err := cache.FindInvalidNodes(ctx, nodeIDs, maxStats)
 	if err != nil {
 		return nil, Error.New("error getting valid nodes from overlay %s", err)
 	}
@@ -10,12 +10,16 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 
 	"storj.io/storj/internal/testcontext"
 	"storj.io/storj/internal/testplanet"
 	"storj.io/storj/internal/teststorj"
+	"storj.io/storj/pkg/datarepair/checker"
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/storj"
+	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/disqualification"
 	"storj.io/storj/storage"
 )
 
@@ -41,11 +45,13 @@ func TestIdentifyInjuredSegments(t *testing.T) {
 
 		//check if the expected segments were added to the queue
 		repairQueue := planet.Satellites[0].DB.RepairQueue()
-		injuredSegment, err := repairQueue.Dequeue(ctx)
+		injuredSegment, err := repairQueue.Select(ctx)
 		assert.NoError(t, err)
 
 		numValidNode := int32(len(planet.StorageNodes))
 		assert.Equal(t, "b", injuredSegment.Path)
+		// one more than the minimum required pieces with a repair threshold of minReq+2
+		assert.Equal(t, 0.5, injuredSegment.Health)
 		assert.Equal(t, len(planet.StorageNodes), len(injuredSegment.LostPieces))
 		for _, lostPiece := range injuredSegment.LostPieces {
 			// makePointer() starts with numValidNode good pieces
@@ -56,6 +62,48 @@ func TestIdentifyInjuredSegments(t *testing.T) {
 	})
 }
 
+func TestIdentifyDisqualifiedPieces(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 0,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		satellite.Repair.Checker.Loop.Stop()
+
+		pieces := make([]*pb.RemotePiece, 0, len(planet.StorageNodes))
+		for i, storagenode := range planet.StorageNodes {
+			pieces = append(pieces, &pb.RemotePiece{
+				PieceNum: int32(i),
+				NodeId:   storagenode.ID(),
+			})
+		}
+		pointer := &pb.Pointer{
+			Remote: &pb.RemoteSegment{
+				Redundancy: &pb.RedundancyScheme{
+					MinReq:          int32(2),
+					RepairThreshold: int32(4),
+				},
+				RootPieceId:  teststorj.PieceIDFromString("disqualified"),
+				RemotePieces: pieces,
+			},
+		}
+		err := satellite.Metainfo.Service.Put("disqualified", pointer)
+		require.NoError(t, err)
+
+		// the disqualified node is online but its piece doesn't count
+		_, err = satellite.DB.Disqualification().Disqualify(ctx, planet.StorageNodes[3].ID(), disqualification.ReasonAuditScore, "")
+		require.NoError(t, err)
+
+		err = satellite.Repair.Checker.IdentifyInjuredSegments(ctx)
+		require.NoError(t, err)
+
+		injuredSegment, err := satellite.DB.RepairQueue().Select(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "disqualified", injuredSegment.Path)
+		assert.Equal(t, []int32{3}, injuredSegment.LostPieces)
+		assert.Equal(t, 0.5, injuredSegment.Health)
+	})
+}
+
 func TestIdentifyIrreparableSegments(t *testing.T) {
 	testplanet.Run(t, testplanet.Config{
 		SatelliteCount: 1, StorageNodeCount: 3, UplinkCount: 0,
@@ -103,7 +151,7 @@ func TestIdentifyIrreparableSegments(t *testing.T) {
 
 		// check if nothing was added to repair queue
 		repairQueue := planet.Satellites[0].DB.RepairQueue()
-		_, err = repairQueue.Dequeue(ctx)
+		_, err = repairQueue.Select(ctx)
 		assert.True(t, storage.ErrEmptyQueue.Has(err))
 
 		//check if the expected segments were added to the irreparable DB
@@ -112,8 +160,9 @@ func TestIdentifyIrreparableSegments(t *testing.T) {
 		assert.NoError(t, err)
 
 		assert.Equal(t, len(expectedLostPieces), int(remoteSegmentInfo.LostPieces))
-		assert.Equal(t, 1, int(remoteSegmentInfo.RepairAttemptCount))
-		firstRepair := remoteSegmentInfo.LastRepairAttempt
+		// the checker doesn't attempt to repair the segment
+		assert.Equal(t, 0, int(remoteSegmentInfo.RepairAttemptCount))
+		firstSeen := remoteSegmentInfo.LastRepairAttempt
 
 		// check irreparable once again but wait a second
 		time.Sleep(1 * time.Second)
@@ -124,12 +173,197 @@ func TestIdentifyIrreparableSegments(t *testing.T) {
 		assert.NoError(t, err)
 
 		assert.Equal(t, len(expectedLostPieces), int(remoteSegmentInfo.LostPieces))
-		// check if repair attempt count was incremented
-		assert.Equal(t, 2, int(remoteSegmentInfo.RepairAttemptCount))
-		assert.True(t, firstRepair < remoteSegmentInfo.LastRepairAttempt)
+		// check the repair attempts weren't counted by checking again
+		assert.Equal(t, 0, int(remoteSegmentInfo.RepairAttemptCount))
+		assert.Equal(t, firstSeen, remoteSegmentInfo.LastRepairAttempt)
 	})
 }
 
+func TestSegmentHealthScore(t *testing.T) {
+	redundancy := &pb.RedundancyScheme{
+		MinReq:           29,
+		RepairThreshold:  35,
+		SuccessThreshold: 80,
+		Total:            95,
+	}
+	assert.Equal(t, 1.0, checker.SegmentHealth(80, redundancy))
+	assert.Equal(t, 0.0, checker.SegmentHealth(29, redundancy))
+	assert.InDelta(t, 5.0/51.0, checker.SegmentHealth(34, redundancy), 1e-9)
+	assert.True(t, checker.SegmentHealth(28, redundancy) < 0)
+	assert.True(t, checker.SegmentHealth(30, redundancy) < checker.SegmentHealth(31, redundancy))
+}
+
+func TestIdentifyInjuredSegmentsRepairOverrides(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 0,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Checker.RepairOverrides = "2/4/6-5"
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		checker := planet.Satellites[0].Repair.Checker
+		checker.Loop.Stop()
+
+		pointerdb := planet.Satellites[0].Metainfo.Service
+		put := func(path string, redundancy *pb.RedundancyScheme) {
+			var pieces []*pb.RemotePiece
+			for i, node := range planet.StorageNodes {
+				pieces = append(pieces, &pb.RemotePiece{
+					PieceNum: int32(i),
+					NodeId:   node.ID(),
+				})
+			}
+			err := pointerdb.Put(path, &pb.Pointer{
+				Remote: &pb.RemoteSegment{
+					Redundancy:   redundancy,
+					RootPieceId:  teststorj.PieceIDFromString(path),
+					RemotePieces: pieces,
+				},
+			})
+			require.NoError(t, err)
+		}
+
+		// all 4 pieces are healthy, which is above the repair threshold of
+		// the pointers but below the override of the first scheme
+		put("overridden", &pb.RedundancyScheme{
+			MinReq: 2, RepairThreshold: 3, SuccessThreshold: 4, Total: 6,
+		})
+		put("not-overridden", &pb.RedundancyScheme{
+			MinReq: 2, RepairThreshold: 3, SuccessThreshold: 4, Total: 5,
+		})
+
+		err := checker.IdentifyInjuredSegments(ctx)
+		require.NoError(t, err)
+
+		injured, err := planet.Satellites[0].DB.RepairQueue().SelectN(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, injured, 1)
+		assert.Equal(t, "overridden", injured[0].Path)
+		assert.Empty(t, injured[0].LostPieces)
+	})
+}
+
+func TestEstimateRepair(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 6, UplinkCount: 0,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		satellite.Repair.Checker.Loop.Stop()
+
+		// put stores a pointer with pieces on the first online storage nodes
+		// and on offline unknown nodes
+		put := func(path string, online, offline int, redundancy *pb.RedundancyScheme) {
+			var pieces []*pb.RemotePiece
+			for i := 0; i < online; i++ {
+				pieces = append(pieces, &pb.RemotePiece{
+					PieceNum: int32(i),
+					NodeId:   planet.StorageNodes[i].ID(),
+				})
+			}
+			for i := 0; i < offline; i++ {
+				pieces = append(pieces, &pb.RemotePiece{
+					PieceNum: int32(online + i),
+					NodeId:   storj.NodeID{byte(i + 1)},
+				})
+			}
+			redundancy.ErasureShareSize = 256
+			err := satellite.Metainfo.Service.Put(path, &pb.Pointer{
+				Type:        pb.Pointer_REMOTE,
+				SegmentSize: 10 * 1024,
+				Remote: &pb.RemoteSegment{
+					Redundancy:   redundancy,
+					RootPieceId:  teststorj.PieceIDFromString(path),
+					RemotePieces: pieces,
+				},
+			})
+			require.NoError(t, err)
+		}
+
+		put("healthy", 4, 0, &pb.RedundancyScheme{MinReq: 2, RepairThreshold: 3, SuccessThreshold: 4, Total: 4})
+		put("injured", 4, 2, &pb.RedundancyScheme{MinReq: 2, RepairThreshold: 5, SuccessThreshold: 5, Total: 6})
+		put("irreparable", 1, 3, &pb.RedundancyScheme{MinReq: 2, RepairThreshold: 3, SuccessThreshold: 4, Total: 4})
+		put("overridden", 4, 0, &pb.RedundancyScheme{MinReq: 2, RepairThreshold: 3, SuccessThreshold: 4, Total: 5})
+
+		overrides, err := checker.ParseRepairOverrides("2/4/5-5")
+		require.NoError(t, err)
+
+		// the checker loaded the reliable nodes when it started
+		require.NoError(t, satellite.Repair.ReliabilityCache.Refresh(ctx))
+
+		estimate, err := checker.EstimateRepair(ctx, satellite.Metainfo.Database, satellite.Overlay.Service, satellite.Repair.ReliabilityCache, overrides)
+		require.NoError(t, err)
+
+		assert.EqualValues(t, 4, estimate.SegmentsChecked)
+		assert.EqualValues(t, 2, estimate.SegmentsNeedingRepair)
+		assert.EqualValues(t, 1, estimate.SegmentsIrreparable)
+		assert.EqualValues(t, 0, estimate.SegmentsUnplaceable)
+		assert.EqualValues(t, 3, estimate.PiecesUploaded)
+
+		segments := map[storj.Path]checker.SegmentEstimate{}
+		for _, segment := range estimate.Segments {
+			segments[segment.Path] = segment
+		}
+		require.Len(t, segments, 3)
+		assert.Nil(t, segments["irreparable"].Repair)
+
+		injured := segments["injured"].Repair
+		require.NotNil(t, injured)
+		assert.Equal(t, 4, injured.HealthyPieces)
+		assert.Equal(t, 4, injured.DownloadedPieces)
+		assert.Equal(t, 2, injured.MissingPieces)
+		assert.Equal(t, 2, injured.SelectedNodes)
+		assert.NoError(t, injured.SelectionError)
+
+		overridden := segments["overridden"]
+		assert.EqualValues(t, 5, overridden.RepairThreshold)
+		require.NotNil(t, overridden.Repair)
+		assert.Equal(t, 1, overridden.Repair.MissingPieces)
+		assert.Equal(t, 1, overridden.Repair.SelectedNodes)
+
+		assert.Equal(t, injured.DownloadedBytes()+overridden.Repair.DownloadedBytes(), estimate.BytesDownloaded)
+		assert.Equal(t, 3*injured.PieceSize, estimate.BytesUploaded)
+
+		// nothing is queued
+		queued, err := satellite.DB.RepairQueue().SelectN(ctx, 10)
+		require.NoError(t, err)
+		assert.Empty(t, queued)
+	})
+}
+
+func TestParseRepairOverrides(t *testing.T) {
+	overrides, err := checker.ParseRepairOverrides("29/80/95-52, 4/6/8-5")
+	require.NoError(t, err)
+	assert.Equal(t, checker.RepairOverrides{
+		{MinReq: 29, SuccessThreshold: 80, Total: 95}: 52,
+		{MinReq: 4, SuccessThreshold: 6, Total: 8}:    5,
+	}, overrides)
+
+	assert.EqualValues(t, 52, overrides.RepairThreshold(&pb.RedundancyScheme{
+		MinReq: 29, RepairThreshold: 35, SuccessThreshold: 80, Total: 95,
+	}))
+	assert.EqualValues(t, 35, overrides.RepairThreshold(&pb.RedundancyScheme{
+		MinReq: 29, RepairThreshold: 35, SuccessThreshold: 80, Total: 130,
+	}))
+
+	overrides, err = checker.ParseRepairOverrides("")
+	require.NoError(t, err)
+	assert.Empty(t, overrides)
+
+	for _, invalid := range []string{
+		"29/80/95",
+		"29/80-52",
+		"29/80/95-x",
+		"29/80/95-20",
+		"29/80/95-96",
+		"80/29/95-52",
+		"29/80/95-52,29/80/95-50",
+	} {
+		_, err := checker.ParseRepairOverrides(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
 func makePointer(t *testing.T, planet *testplanet.Planet, pieceID string, createLost bool) {
 	numOfStorageNodes := len(planet.StorageNodes)
 	pieces := make([]*pb.RemotePiece, 0, numOfStorageNodes)
@@ -0,0 +1,125 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package checker
+
+import (
+	"context"
+
+	"github.com/gogo/protobuf/proto"
+
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storage/segments"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storage"
+)
+
+// Estimate is what repairing the segments needing repair would transfer.
+type Estimate struct {
+	SegmentsChecked       int64
+	SegmentsNeedingRepair int64
+	SegmentsIrreparable   int64
+	// SegmentsUnplaceable is the number of segments for which fewer new
+	// nodes than missing pieces were found
+	SegmentsUnplaceable int64
+	InvalidPointers     int64
+
+	PiecesUploaded  int64
+	BytesDownloaded int64
+	BytesUploaded   int64
+
+	Segments []SegmentEstimate
+}
+
+// SegmentEstimate is the estimate of a segment needing repair.
+type SegmentEstimate struct {
+	Path            storj.Path
+	Health          float64
+	HealthyPieces   int32
+	RepairThreshold int32
+	// Repair is nil when the segment is irreparable
+	Repair *segments.RepairEstimate
+}
+
+// EstimateRepair checks the segments of the pointers in db like the checker
+// does, using the reliable nodes of reliability and the repair overrides, and
+// estimates what repairing the segments needing repair would transfer. Nothing is queued or repaired, so the cost of
+// changing the repair thresholds can be estimated on a read replica or a
+// backup of the pointerdb.
+func EstimateRepair(ctx context.Context, db storage.KeyValueStore, cache *overlay.Cache, reliability *overlay.ReliabilityCache, overrides RepairOverrides) (_ *Estimate, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	estimate := &Estimate{}
+	err = db.Iterate(storage.IterateOptions{Recurse: true, Snapshot: true},
+		func(it storage.Iterator) error {
+			var item storage.ListItem
+			for it.Next(&item) {
+				pointer := &pb.Pointer{}
+				if err := proto.Unmarshal(item.Value, pointer); err != nil {
+					estimate.InvalidPointers++
+					continue
+				}
+				if pointer.GetType() != pb.Pointer_REMOTE {
+					continue
+				}
+
+				err := estimate.add(ctx, cache, reliability, overrides, storj.Path(item.Key), pointer)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return estimate, nil
+}
+
+// add checks the segment and estimates its repair when it needs one.
+func (estimate *Estimate) add(ctx context.Context, cache *overlay.Cache, reliability *overlay.ReliabilityCache, overrides RepairOverrides, path storj.Path, pointer *pb.Pointer) error {
+	pieces := pointer.GetRemote().GetRemotePieces()
+	if len(pieces) == 0 {
+		return nil
+	}
+	estimate.SegmentsChecked++
+
+	lostPieces, err := reliability.MissingPieces(ctx, pieces)
+	if err != nil {
+		return err
+	}
+
+	redundancy := pointer.GetRemote().GetRedundancy()
+	numHealthy := int32(len(pieces) - len(lostPieces))
+	segment := SegmentEstimate{
+		Path:            path,
+		Health:          SegmentHealth(numHealthy, redundancy),
+		HealthyPieces:   numHealthy,
+		RepairThreshold: overrides.RepairThreshold(redundancy),
+	}
+	if numHealthy >= segment.RepairThreshold {
+		return nil
+	}
+
+	if numHealthy >= redundancy.GetMinReq() {
+		segment.Repair, err = segments.EstimateRepair(ctx, cache, path, pointer, lostPieces)
+		if err != nil && !segments.ErrIrreparable.Has(err) {
+			return err
+		}
+	}
+	estimate.Segments = append(estimate.Segments, segment)
+
+	if segment.Repair == nil {
+		estimate.SegmentsIrreparable++
+		return nil
+	}
+	estimate.SegmentsNeedingRepair++
+	if segment.Repair.SelectionError != nil {
+		estimate.SegmentsUnplaceable++
+	}
+	estimate.PiecesUploaded += int64(segment.Repair.SelectedNodes)
+	estimate.BytesDownloaded += segment.Repair.DownloadedBytes()
+	estimate.BytesUploaded += segment.Repair.UploadedBytes()
+	return nil
+}
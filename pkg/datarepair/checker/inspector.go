@@ -0,0 +1,211 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package checker
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storage"
+)
+
+// Inspector is a gRPC service for inspecting the health of segments
+type Inspector struct {
+	checker *Checker
+}
+
+// NewInspector creates an Inspector
+func NewInspector(checker *Checker) *Inspector {
+	return &Inspector{checker: checker}
+}
+
+// segment is a pointer and its path
+type segment struct {
+	path    storj.Path
+	pointer *pb.Pointer
+}
+
+// SegmentHealth returns the health of a segment or of all the segments of an
+// object, using the same criteria as the checker
+func (srv *Inspector) SegmentHealth(ctx context.Context, req *pb.SegmentHealthRequest) (_ *pb.SegmentHealthResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	segments, err := srv.segments(string(req.Path))
+	if err != nil {
+		return nil, err
+	}
+
+	queued, err := srv.queuedPaths(ctx)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	resp := &pb.SegmentHealthResponse{}
+	for _, seg := range segments {
+		health, err := srv.segmentHealth(ctx, seg.pointer)
+		if err != nil {
+			return nil, err
+		}
+		health.Path = []byte(seg.path)
+		health.QueuedForRepair = queued[seg.path]
+		resp.Segments = append(resp.Segments, health)
+	}
+	return resp, nil
+}
+
+// segments returns the segment at path, or all the segments of the object at path
+func (srv *Inspector) segments(path storj.Path) ([]segment, error) {
+	elements := storj.SplitPath(path)
+	if len(elements) < 3 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid path %q", path)
+	}
+
+	if isSegmentIndex(elements[1]) {
+		pointer, err := srv.get(path)
+		if err != nil {
+			return nil, err
+		}
+		return []segment{{path: path, pointer: pointer}}, nil
+	}
+
+	// NB: the number of segments is part of the encrypted stream info, so the
+	// segments are probed until one is missing
+	project, rest := elements[0], elements[1:]
+
+	lastPath := storj.JoinPaths(append([]string{project, "l"}, rest...)...)
+	last, err := srv.get(lastPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var segments []segment
+	for index := 0; ; index++ {
+		segmentPath := storj.JoinPaths(append([]string{project, "s" + strconv.Itoa(index)}, rest...)...)
+		pointer, err := srv.get(segmentPath)
+		if status.Code(err) == codes.NotFound {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, segment{path: segmentPath, pointer: pointer})
+	}
+	return append(segments, segment{path: lastPath, pointer: last}), nil
+}
+
+// get returns the pointer at path
+func (srv *Inspector) get(path storj.Path) (*pb.Pointer, error) {
+	pointer, err := srv.checker.pointerdb.Get(path)
+	if storage.ErrKeyNotFound.Has(err) {
+		return nil, status.Errorf(codes.NotFound, "segment %q not found", path)
+	}
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return pointer, nil
+}
+
+// queuedPaths returns the paths of the segments in the repair queue
+func (srv *Inspector) queuedPaths(ctx context.Context) (map[storj.Path]bool, error) {
+	// NB: at most storage.LookupLimit segments are returned
+	injured, err := srv.checker.repairQueue.SelectN(ctx, storage.LookupLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	queued := make(map[storj.Path]bool, len(injured))
+	for _, seg := range injured {
+		queued[seg.Path] = true
+	}
+	return queued, nil
+}
+
+// segmentHealth returns the health of the pieces of a segment
+func (srv *Inspector) segmentHealth(ctx context.Context, pointer *pb.Pointer) (*pb.SegmentHealth, error) {
+	remote := pointer.GetRemote()
+	if remote == nil {
+		return &pb.SegmentHealth{Inline: true}, nil
+	}
+
+	redundancy := remote.GetRedundancy()
+	health := &pb.SegmentHealth{
+		MinReq:           redundancy.GetMinReq(),
+		RepairThreshold:  srv.checker.repairOverrides.RepairThreshold(redundancy),
+		SuccessThreshold: redundancy.GetSuccessThreshold(),
+		Total:            redundancy.GetTotal(),
+	}
+
+	pieces := remote.GetRemotePieces()
+	if len(pieces) == 0 {
+		return health, nil
+	}
+
+	var nodeIDs storj.NodeIDList
+	for _, piece := range pieces {
+		nodeIDs = append(nodeIDs, piece.NodeId)
+	}
+
+	offlineNodes, err := srv.checker.overlay.OfflineNodes(ctx, nodeIDs)
+	if err != nil {
+		return nil, Error.New("error getting offline nodes %s", err)
+	}
+	invalidNodes, err := srv.checker.invalidNodes(ctx, nodeIDs)
+	if err != nil {
+		return nil, err
+	}
+	missingPieces := combineOfflineWithInvalid(offlineNodes, invalidNodes)
+	health.HealthyPieces = int32(len(nodeIDs) - len(missingPieces))
+
+	offline := make(map[int]bool, len(offlineNodes))
+	for _, i := range offlineNodes {
+		offline[i] = true
+	}
+	invalid := make(map[int]bool, len(invalidNodes))
+	for _, i := range invalidNodes {
+		invalid[i] = true
+	}
+
+	for i, piece := range pieces {
+		pieceHealth := &pb.PieceHealth{
+			PieceNum:  piece.PieceNum,
+			NodeId:    piece.NodeId,
+			Online:    !offline[i],
+			Reputable: !invalid[i],
+		}
+
+		// NB: offline nodes are not in the overlay and have no stats
+		if pieceHealth.Online {
+			stats, err := srv.checker.overlay.GetStats(ctx, piece.NodeId)
+			if err != nil {
+				return nil, Error.New("error getting stats of node %s: %s", piece.NodeId, err)
+			}
+			pieceHealth.AuditCount = stats.AuditCount
+			pieceHealth.AuditSuccessRatio = stats.AuditSuccessRatio
+			pieceHealth.UptimeCount = stats.UptimeCount
+			pieceHealth.UptimeRatio = stats.UptimeRatio
+		}
+
+		health.Pieces = append(health.Pieces, pieceHealth)
+	}
+
+	return health, nil
+}
+
+// isSegmentIndex returns whether element is the segment index of a path, i.e.
+// "l" for the last segment or "s<n>" for the others
+func isSegmentIndex(element string) bool {
+	if element == "l" {
+		return true
+	}
+	if len(element) < 2 || element[0] != 's' {
+		return false
+	}
+	_, err := strconv.ParseUint(element[1:], 10, 64)
+	return err == nil
+}
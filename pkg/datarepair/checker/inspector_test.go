@@ -0,0 +1,95 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package checker_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/internal/teststorj"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+)
+
+func TestSegmentHealth(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 0,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		satellite.Repair.Checker.Loop.Stop()
+
+		pieces := make([]*pb.RemotePiece, 0, 2*len(planet.StorageNodes))
+		for i, storagenode := range planet.StorageNodes {
+			pieces = append(pieces, &pb.RemotePiece{
+				PieceNum: int32(i),
+				NodeId:   storagenode.ID(),
+			})
+		}
+		// simulate offline nodes
+		for i := len(pieces); i < 2*len(planet.StorageNodes); i++ {
+			pieces = append(pieces, &pb.RemotePiece{
+				PieceNum: int32(i),
+				NodeId:   storj.NodeID{byte(i)},
+			})
+		}
+
+		pointerdb := satellite.Metainfo.Service
+		require.NoError(t, pointerdb.Put("project/s0/bucket/object", &pb.Pointer{
+			Remote: &pb.RemoteSegment{
+				Redundancy: &pb.RedundancyScheme{
+					MinReq:           int32(2),
+					RepairThreshold:  int32(6),
+					SuccessThreshold: int32(7),
+					Total:            int32(8),
+				},
+				RootPieceId:  teststorj.PieceIDFromString("object-piece-id"),
+				RemotePieces: pieces,
+			},
+		}))
+		require.NoError(t, pointerdb.Put("project/l/bucket/object", &pb.Pointer{
+			Type:          pb.Pointer_INLINE,
+			InlineSegment: []byte("data"),
+		}))
+
+		require.NoError(t, satellite.Repair.Checker.IdentifyInjuredSegments(ctx))
+
+		inspector := satellite.Repair.HealthInspector
+
+		resp, err := inspector.SegmentHealth(ctx, &pb.SegmentHealthRequest{Path: []byte("project/bucket/object")})
+		require.NoError(t, err)
+		require.Len(t, resp.Segments, 2)
+
+		remote := resp.Segments[0]
+		assert.Equal(t, "project/s0/bucket/object", string(remote.Path))
+		assert.False(t, remote.Inline)
+		assert.Equal(t, int32(2), remote.MinReq)
+		assert.Equal(t, int32(6), remote.RepairThreshold)
+		assert.Equal(t, int32(len(planet.StorageNodes)), remote.HealthyPieces)
+		assert.True(t, remote.QueuedForRepair)
+		require.Len(t, remote.Pieces, len(pieces))
+		for i, piece := range remote.Pieces {
+			assert.Equal(t, pieces[i].NodeId, piece.NodeId)
+			assert.Equal(t, i < len(planet.StorageNodes), piece.Online)
+		}
+
+		inline := resp.Segments[1]
+		assert.Equal(t, "project/l/bucket/object", string(inline.Path))
+		assert.True(t, inline.Inline)
+		assert.False(t, inline.QueuedForRepair)
+
+		resp, err = inspector.SegmentHealth(ctx, &pb.SegmentHealthRequest{Path: []byte("project/s0/bucket/object")})
+		require.NoError(t, err)
+		require.Len(t, resp.Segments, 1)
+		assert.Equal(t, remote, resp.Segments[0])
+
+		_, err = inspector.SegmentHealth(ctx, &pb.SegmentHealthRequest{Path: []byte("project/bucket/missing")})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
@@ -0,0 +1,84 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package checker
+
+import (
+	"strconv"
+	"strings"
+
+	"storj.io/storj/pkg/pb"
+)
+
+// RepairOverrideKey identifies a redundancy scheme by its thresholds.
+type RepairOverrideKey struct {
+	MinReq           int32
+	SuccessThreshold int32
+	Total            int32
+}
+
+// RepairOverrides maps redundancy schemes to the repair thresholds used
+// instead of the repair thresholds stored in their pointers.
+type RepairOverrides map[RepairOverrideKey]int32
+
+// ParseRepairOverrides parses a comma separated list of overrides formatted as
+// "min/success/total-threshold", e.g. "29/80/95-52".
+func ParseRepairOverrides(s string) (RepairOverrides, error) {
+	overrides := RepairOverrides{}
+	for _, override := range strings.Split(s, ",") {
+		override = strings.TrimSpace(override)
+		if override == "" {
+			continue
+		}
+
+		parts := strings.Split(override, "-")
+		if len(parts) != 2 {
+			return nil, Error.New("invalid repair override %q", override)
+		}
+		scheme := strings.Split(parts[0], "/")
+		if len(scheme) != 3 {
+			return nil, Error.New("invalid redundancy scheme in repair override %q", override)
+		}
+
+		var values [4]int32
+		for i, str := range append(scheme, parts[1]) {
+			value, err := strconv.ParseInt(str, 10, 32)
+			if err != nil || value <= 0 {
+				return nil, Error.New("invalid number %q in repair override %q", str, override)
+			}
+			values[i] = int32(value)
+		}
+
+		key := RepairOverrideKey{
+			MinReq:           values[0],
+			SuccessThreshold: values[1],
+			Total:            values[2],
+		}
+		threshold := values[3]
+		if key.MinReq > key.SuccessThreshold || key.SuccessThreshold > key.Total {
+			return nil, Error.New("invalid redundancy scheme in repair override %q", override)
+		}
+		if threshold <= key.MinReq || threshold > key.Total {
+			return nil, Error.New("repair threshold in repair override %q must be between min and total", override)
+		}
+		if _, ok := overrides[key]; ok {
+			return nil, Error.New("duplicate repair override %q", override)
+		}
+		overrides[key] = threshold
+	}
+	return overrides, nil
+}
+
+// RepairThreshold returns the repair threshold of the redundancy scheme, using
+// the override of the scheme when there's one.
+func (overrides RepairOverrides) RepairThreshold(redundancy *pb.RedundancyScheme) int32 {
+	key := RepairOverrideKey{
+		MinReq:           redundancy.GetMinReq(),
+		SuccessThreshold: redundancy.GetSuccessThreshold(),
+		Total:            redundancy.GetTotal(),
+	}
+	if threshold, ok := overrides[key]; ok {
+		return threshold
+	}
+	return redundancy.GetRepairThreshold()
+}
@@ -11,12 +11,21 @@ import (
 
 // DB stores information about repairs that have failed.
 type DB interface {
-	// IncrementRepairAttempts increments the repair attempts.
+	// IncrementRepairAttempts adds the segment, or increments the repair
+	// attempts of a segment that is already stored and updates its detail,
+	// lost pieces and last error.
 	IncrementRepairAttempts(ctx context.Context, segmentInfo *pb.IrreparableSegment) error
+	// Upsert adds the segment, or updates the detail and the lost pieces of a
+	// segment that is already stored, without counting a repair attempt.
+	Upsert(ctx context.Context, segmentInfo *pb.IrreparableSegment) error
 	// Get returns irreparable segment info based on segmentPath.
 	Get(ctx context.Context, segmentPath []byte) (*pb.IrreparableSegment, error)
 	// GetLimited number of segments from offset
 	GetLimited(ctx context.Context, limit int, offset int64) ([]*pb.IrreparableSegment, error)
+	// Count returns the number of irreparable segments.
+	Count(ctx context.Context) (int64, error)
 	// Delete removes irreparable segment info based on segmentPath.
 	Delete(ctx context.Context, segmentPath []byte) error
+	// DeleteAll removes all irreparable segments and returns how many were removed.
+	DeleteAll(ctx context.Context) (int64, error)
 }
@@ -30,8 +30,10 @@ func TestIrreparable(t *testing.T) {
 				Path:               []byte(strconv.Itoa(i)),
 				SegmentDetail:      &pb.Pointer{},
 				LostPieces:         int32(i),
+				LostPieceNums:      []int32{int32(i), int32(i + 4)},
 				LastRepairAttempt:  time.Now().Unix(),
 				RepairAttemptCount: int64(10),
+				LastError:          "not enough healthy pieces",
 			})
 			err := irrdb.IncrementRepairAttempts(ctx, segments[i])
 			assert.NoError(t, err)
@@ -75,6 +77,12 @@ func TestIrreparable(t *testing.T) {
 			assert.Equal(t, segments[2], segs[1])
 		}
 
+		{ // Count
+			count, err := irrdb.Count(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(3), count)
+		}
+
 		{ // Test repair count incrementation
 			err := irrdb.IncrementRepairAttempts(ctx, segments[0])
 			assert.NoError(t, err)
@@ -85,6 +93,56 @@ func TestIrreparable(t *testing.T) {
 			assert.Equal(t, segments[0], dbxInfo)
 		}
 
+		{ // Test the lost pieces and the last error are updated
+			segments[0].LostPieceNums = []int32{1, 2, 3}
+			segments[0].LostPieces = 3
+			segments[0].LastError = "download failed"
+			err := irrdb.IncrementRepairAttempts(ctx, segments[0])
+			assert.NoError(t, err)
+			segments[0].RepairAttemptCount++
+
+			dbxInfo, err := irrdb.Get(ctx, segments[0].Path)
+			assert.NoError(t, err)
+			assert.Equal(t, segments[0], dbxInfo)
+		}
+
+		{ // Test Upsert updates the lost pieces without counting a repair attempt
+			segments[0].LostPieceNums = []int32{1, 2, 3, 4}
+			segments[0].LostPieces = 4
+			err := irrdb.Upsert(ctx, &pb.IrreparableSegment{
+				Path:              segments[0].Path,
+				SegmentDetail:     segments[0].SegmentDetail,
+				LostPieces:        segments[0].LostPieces,
+				LostPieceNums:     segments[0].LostPieceNums,
+				LastRepairAttempt: time.Now().Unix() + 10,
+				LastError:         "4 healthy pieces, 5 required",
+			})
+			assert.NoError(t, err)
+
+			dbxInfo, err := irrdb.Get(ctx, segments[0].Path)
+			assert.NoError(t, err)
+			assert.Equal(t, segments[0], dbxInfo)
+		}
+
+		{ // Test Upsert adds a new segment
+			segment := &pb.IrreparableSegment{
+				Path:              []byte("upserted"),
+				SegmentDetail:     &pb.Pointer{},
+				LostPieces:        1,
+				LostPieceNums:     []int32{1},
+				LastRepairAttempt: time.Now().Unix(),
+				LastError:         "4 healthy pieces, 5 required",
+			}
+			err := irrdb.Upsert(ctx, segment)
+			assert.NoError(t, err)
+
+			dbxInfo, err := irrdb.Get(ctx, segment.Path)
+			assert.NoError(t, err)
+			assert.Equal(t, segment, dbxInfo)
+
+			assert.NoError(t, irrdb.Delete(ctx, segment.Path))
+		}
+
 		{ //Delete existing entry
 			err := irrdb.Delete(ctx, segments[0].Path)
 			assert.NoError(t, err)
@@ -92,5 +150,15 @@ func TestIrreparable(t *testing.T) {
 			_, err = irrdb.Get(ctx, segments[0].Path)
 			assert.Error(t, err)
 		}
+
+		{ // DeleteAll removes the remaining entries
+			count, err := irrdb.DeleteAll(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(2), count)
+
+			count, err = irrdb.Count(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(0), count)
+		}
 	})
 }
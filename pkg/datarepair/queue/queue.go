@@ -5,82 +5,39 @@ package queue
 
 import (
 	"context"
-
-	"github.com/gogo/protobuf/proto"
-	"go.uber.org/zap"
+	"time"
 
 	"storj.io/storj/pkg/pb"
-	"storj.io/storj/storage"
 )
 
 // RepairQueue implements queueing for segments that need repairing.
+//
+// A segment stays in the queue until it is repaired: Select leases the least
+// healthy segment, which is then deleted once repaired or requeued with a
+// delay when the repair failed. A segment whose lease expired, e.g. because
+// the satellite restarted during the repair, is selected again.
 type RepairQueue interface {
-	// Enqueue adds an injured segment.
-	Enqueue(ctx context.Context, qi *pb.InjuredSegment) error
-	// Dequeue removes an injured segment.
-	Dequeue(ctx context.Context) (pb.InjuredSegment, error)
-	// Peekqueue lists limit amount of injured segments.
-	Peekqueue(ctx context.Context, limit int) ([]pb.InjuredSegment, error)
-}
-
-// Queue implements the RepairQueue interface
-type Queue struct {
-	db storage.Queue
-}
-
-// NewQueue returns a pointer to a new Queue instance with an initialized connection to Redis
-func NewQueue(client storage.Queue) *Queue {
-	zap.L().Info("Initializing new data repair queue")
-	return &Queue{db: client}
-}
-
-// Enqueue adds a repair segment to the queue
-func (q *Queue) Enqueue(ctx context.Context, qi *pb.InjuredSegment) error {
-	val, err := proto.Marshal(qi)
-	if err != nil {
-		return Error.New("error marshalling injured seg %s", err)
-	}
-
-	err = q.db.Enqueue(val)
-	if err != nil {
-		return Error.New("error adding injured seg to queue %s", err)
-	}
-	return nil
-}
-
-// Dequeue returns the next repair segement and removes it from the queue
-func (q *Queue) Dequeue(ctx context.Context) (pb.InjuredSegment, error) {
-	val, err := q.db.Dequeue()
-	if err != nil {
-		if storage.ErrEmptyQueue.Has(err) {
-			return pb.InjuredSegment{}, err
-		}
-		return pb.InjuredSegment{}, Error.New("error obtaining item from repair queue %s", err)
-	}
-	seg := &pb.InjuredSegment{}
-	err = proto.Unmarshal(val, seg)
-	if err != nil {
-		return pb.InjuredSegment{}, Error.New("error unmarshalling segment %s", err)
-	}
-	return *seg, nil
+	// Insert adds an injured segment, or updates the health and the lost
+	// pieces of a segment that is already queued.
+	Insert(ctx context.Context, seg *pb.InjuredSegment) (alreadyInserted bool, err error)
+	// Select leases the injured segment with the lowest health and counts
+	// the attempt, it returns storage.ErrEmptyQueue when no segment is left.
+	Select(ctx context.Context) (*pb.InjuredSegment, error)
+	// Requeue releases the lease of a segment whose repair failed, the
+	// segment isn't selected again before delay elapsed.
+	Requeue(ctx context.Context, seg *pb.InjuredSegment, delay time.Duration) error
+	// Delete removes a repaired segment.
+	Delete(ctx context.Context, seg *pb.InjuredSegment) error
+	// SelectN lists limit injured segments, the least healthy first.
+	SelectN(ctx context.Context, limit int) ([]pb.InjuredSegment, error)
+	// Stats returns the number of queued segments and the age of the oldest.
+	Stats(ctx context.Context) (Stats, error)
 }
 
-// Peekqueue returns upto 'limit' of the entries from the repair queue
-func (q *Queue) Peekqueue(ctx context.Context, limit int) ([]pb.InjuredSegment, error) {
-	if limit < 0 || limit > storage.LookupLimit {
-		limit = storage.LookupLimit
-	}
-	result, err := q.db.Peekqueue(limit)
-	if err != nil {
-		return []pb.InjuredSegment{}, Error.New("error peeking into repair queue %s", err)
-	}
-	segs := make([]pb.InjuredSegment, 0)
-	for _, v := range result {
-		seg := &pb.InjuredSegment{}
-		if err = proto.Unmarshal(v, seg); err != nil {
-			return nil, err
-		}
-		segs = append(segs, *seg)
-	}
-	return segs, nil
+// Stats describes the segments in the repair queue.
+type Stats struct {
+	Count int64
+	// Oldest is when the segment that is queued the longest was inserted,
+	// zero when the queue is empty.
+	Oldest time.Time
 }
@@ -8,20 +8,19 @@ import (
 	"strconv"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"storj.io/storj/internal/testcontext"
-	"storj.io/storj/pkg/datarepair/queue"
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/satellite"
 	"storj.io/storj/satellite/satellitedb/satellitedbtest"
-	"storj.io/storj/storage/redis"
-	"storj.io/storj/storage/redis/redisserver"
-	"storj.io/storj/storage/testqueue"
+	"storj.io/storj/storage"
 )
 
-func TestEnqueueDequeue(t *testing.T) {
+func TestInsertSelect(t *testing.T) {
 	satellitedbtest.Run(t, func(t *testing.T, db satellite.DB) {
 		ctx := testcontext.New(t)
 		defer ctx.Cleanup()
@@ -32,197 +31,186 @@ func TestEnqueueDequeue(t *testing.T) {
 			Path:       "abc",
 			LostPieces: []int32{int32(1), int32(3)},
 		}
-		err := q.Enqueue(ctx, seg)
-		assert.NoError(t, err)
-
-		s, err := q.Dequeue(ctx)
-		assert.NoError(t, err)
-		assert.True(t, pb.Equal(&s, seg))
+		alreadyInserted, err := q.Insert(ctx, seg)
+		require.NoError(t, err)
+		assert.False(t, alreadyInserted)
+
+		s, err := q.Select(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, seg.Path, s.Path)
+		assert.Equal(t, seg.LostPieces, s.LostPieces)
+		assert.Equal(t, int32(1), s.Attempts)
+		assert.NotNil(t, s.InsertedAt)
 	})
 }
 
-func TestDequeueEmptyQueue(t *testing.T) {
+func TestInsertDuplicate(t *testing.T) {
 	satellitedbtest.Run(t, func(t *testing.T, db satellite.DB) {
 		ctx := testcontext.New(t)
 		defer ctx.Cleanup()
 
 		q := db.RepairQueue()
 
-		s, err := q.Dequeue(ctx)
-		assert.Error(t, err)
-		assert.Equal(t, pb.InjuredSegment{}, s)
+		alreadyInserted, err := q.Insert(ctx, &pb.InjuredSegment{Path: "abc", LostPieces: []int32{1}, Health: 0.5})
+		require.NoError(t, err)
+		assert.False(t, alreadyInserted)
+
+		// the checker found more lost pieces since
+		alreadyInserted, err = q.Insert(ctx, &pb.InjuredSegment{Path: "abc", LostPieces: []int32{1, 2}, Health: 0.2})
+		require.NoError(t, err)
+		assert.True(t, alreadyInserted)
+
+		stats, err := q.Stats(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), stats.Count)
+
+		s, err := q.Select(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []int32{1, 2}, s.LostPieces)
+		assert.Equal(t, 0.2, s.Health)
 	})
 }
 
-func TestSequential(t *testing.T) {
+func TestSelectEmptyQueue(t *testing.T) {
 	satellitedbtest.Run(t, func(t *testing.T, db satellite.DB) {
 		ctx := testcontext.New(t)
 		defer ctx.Cleanup()
 
 		q := db.RepairQueue()
 
-		const N = 100
-		var addSegs []*pb.InjuredSegment
-		for i := 0; i < N; i++ {
-			seg := &pb.InjuredSegment{
-				Path:       strconv.Itoa(i),
-				LostPieces: []int32{int32(i)},
-			}
-			err := q.Enqueue(ctx, seg)
-			assert.NoError(t, err)
-			addSegs = append(addSegs, seg)
-		}
-
-		list, err := q.Peekqueue(ctx, 100)
-		assert.NoError(t, err)
-		for i := 0; i < N; i++ {
-			assert.True(t, pb.Equal(addSegs[i], &list[i]))
-		}
+		s, err := q.Select(ctx)
+		assert.True(t, storage.ErrEmptyQueue.Has(err))
+		assert.Nil(t, s)
 
-		// TODO: fix out of order issue
-		for i := 0; i < N; i++ {
-			dequeued, err := q.Dequeue(ctx)
-			assert.NoError(t, err)
-			expected := dequeued.LostPieces[0]
-			assert.True(t, pb.Equal(addSegs[expected], &dequeued))
-		}
+		stats, err := q.Stats(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), stats.Count)
+		assert.True(t, stats.Oldest.IsZero())
 	})
 }
 
-func TestParallel(t *testing.T) {
+func TestSelectLowestHealth(t *testing.T) {
 	satellitedbtest.Run(t, func(t *testing.T, db satellite.DB) {
 		ctx := testcontext.New(t)
 		defer ctx.Cleanup()
 
 		q := db.RepairQueue()
-		const N = 100
-		errs := make(chan error, N*2)
-		entries := make(chan *pb.InjuredSegment, N*2)
-		var wg sync.WaitGroup
-		wg.Add(N)
-		// Add to queue concurrently
-		for i := 0; i < N; i++ {
-			go func(i int) {
-				defer wg.Done()
-				err := q.Enqueue(ctx, &pb.InjuredSegment{
-					Path:       strconv.Itoa(i),
-					LostPieces: []int32{int32(i)},
-				})
-				if err != nil {
-					errs <- err
-				}
-			}(i)
-		}
-		wg.Wait()
 
-		wg.Add(N)
-		// Remove from queue concurrently
-		for i := 0; i < N; i++ {
-			go func(i int) {
-				defer wg.Done()
-				segment, err := q.Dequeue(ctx)
-				if err != nil {
-					errs <- err
-				}
-				entries <- &segment
-			}(i)
+		for i, health := range []float64{0.5, 0.1, 0.9, 0.1} {
+			_, err := q.Insert(ctx, &pb.InjuredSegment{
+				Path:   strconv.Itoa(i),
+				Health: health,
+			})
+			require.NoError(t, err)
 		}
-		wg.Wait()
-		close(errs)
-		close(entries)
 
-		for err := range errs {
-			t.Error(err)
+		// the least healthy segments first, in insertion order
+		for _, path := range []string{"1", "3", "0", "2"} {
+			s, err := q.Select(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, path, s.Path)
 		}
+	})
+}
 
-		var items []*pb.InjuredSegment
-		for segment := range entries {
-			items = append(items, segment)
-		}
+func TestRequeueDelete(t *testing.T) {
+	satellitedbtest.Run(t, func(t *testing.T, db satellite.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
 
-		sort.Slice(items, func(i, k int) bool {
-			return items[i].LostPieces[0] < items[k].LostPieces[0]
-		})
+		q := db.RepairQueue()
 
-		// check if the enqueued and dequeued elements match
-		for i := 0; i < N; i++ {
-			assert.Equal(t, items[i].LostPieces[0], int32(i))
-		}
+		_, err := q.Insert(ctx, &pb.InjuredSegment{Path: "abc"})
+		require.NoError(t, err)
+
+		s, err := q.Select(ctx)
+		require.NoError(t, err)
+
+		// the leased segment stays queued but isn't selected
+		_, err = q.Select(ctx)
+		assert.True(t, storage.ErrEmptyQueue.Has(err))
+		stats, err := q.Stats(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), stats.Count)
+		assert.False(t, stats.Oldest.IsZero())
+
+		require.NoError(t, q.Requeue(ctx, s, 0))
+
+		s, err = q.Select(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "abc", s.Path)
+		assert.Equal(t, int32(2), s.Attempts)
+
+		// a delayed segment isn't selected before the delay elapsed
+		require.NoError(t, q.Requeue(ctx, s, time.Hour))
+		_, err = q.Select(ctx)
+		assert.True(t, storage.ErrEmptyQueue.Has(err))
+		stats, err = q.Stats(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), stats.Count)
+
+		require.NoError(t, q.Delete(ctx, s))
+
+		_, err = q.Select(ctx)
+		assert.True(t, storage.ErrEmptyQueue.Has(err))
+		stats, err = q.Stats(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), stats.Count)
 	})
 }
 
-func BenchmarkRedisSequential(b *testing.B) {
-	addr, cleanup, err := redisserver.Start()
-	defer cleanup()
-	assert.NoError(b, err)
-	client, err := redis.NewQueue(addr, "", 1)
-	assert.NoError(b, err)
-	q := queue.NewQueue(client)
-	benchmarkSequential(b, q)
-}
-
-func BenchmarkTeststoreSequential(b *testing.B) {
-	q := queue.NewQueue(testqueue.New())
-	benchmarkSequential(b, q)
-}
+func TestSequential(t *testing.T) {
+	satellitedbtest.Run(t, func(t *testing.T, db satellite.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
 
-func benchmarkSequential(b *testing.B, q queue.RepairQueue) {
-	ctx := testcontext.New(b)
-	defer ctx.Cleanup()
+		q := db.RepairQueue()
 
-	b.ResetTimer()
-	for n := 0; n < b.N; n++ {
 		const N = 100
 		var addSegs []*pb.InjuredSegment
 		for i := 0; i < N; i++ {
 			seg := &pb.InjuredSegment{
 				Path:       strconv.Itoa(i),
 				LostPieces: []int32{int32(i)},
+				Health:     float64(i),
 			}
-			err := q.Enqueue(ctx, seg)
-			assert.NoError(b, err)
+			_, err := q.Insert(ctx, seg)
+			require.NoError(t, err)
 			addSegs = append(addSegs, seg)
 		}
+
+		list, err := q.SelectN(ctx, 100)
+		require.NoError(t, err)
+		require.Len(t, list, N)
 		for i := 0; i < N; i++ {
-			dqSeg, err := q.Dequeue(ctx)
-			assert.NoError(b, err)
-			assert.True(b, pb.Equal(addSegs[i], &dqSeg))
+			assert.Equal(t, addSegs[i].Path, list[i].Path)
+			assert.Equal(t, addSegs[i].LostPieces, list[i].LostPieces)
 		}
-	}
-}
-
-func BenchmarkRedisParallel(b *testing.B) {
-	addr, cleanup, err := redisserver.Start()
-	defer cleanup()
-	assert.NoError(b, err)
-	client, err := redis.NewQueue(addr, "", 1)
-	assert.NoError(b, err)
-	q := queue.NewQueue(client)
-	benchmarkParallel(b, q)
-}
 
-func BenchmarkTeststoreParallel(b *testing.B) {
-	q := queue.NewQueue(testqueue.New())
-	benchmarkParallel(b, q)
+		for i := 0; i < N; i++ {
+			selected, err := q.Select(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, addSegs[i].Path, selected.Path)
+		}
+	})
 }
 
-func benchmarkParallel(b *testing.B, q queue.RepairQueue) {
-	ctx := testcontext.New(b)
-	defer ctx.Cleanup()
+func TestParallel(t *testing.T) {
+	satellitedbtest.Run(t, func(t *testing.T, db satellite.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
 
-	b.ResetTimer()
-	for n := 0; n < b.N; n++ {
+		q := db.RepairQueue()
 		const N = 100
 		errs := make(chan error, N*2)
 		entries := make(chan *pb.InjuredSegment, N*2)
 		var wg sync.WaitGroup
-
 		wg.Add(N)
 		// Add to queue concurrently
 		for i := 0; i < N; i++ {
 			go func(i int) {
 				defer wg.Done()
-				err := q.Enqueue(ctx, &pb.InjuredSegment{
+				_, err := q.Insert(ctx, &pb.InjuredSegment{
 					Path:       strconv.Itoa(i),
 					LostPieces: []int32{int32(i)},
 				})
@@ -230,19 +218,20 @@ func benchmarkParallel(b *testing.B, q queue.RepairQueue) {
 					errs <- err
 				}
 			}(i)
-
 		}
 		wg.Wait()
+
 		wg.Add(N)
-		// Remove from queue concurrently
+		// Select from queue concurrently
 		for i := 0; i < N; i++ {
 			go func(i int) {
 				defer wg.Done()
-				segment, err := q.Dequeue(ctx)
+				segment, err := q.Select(ctx)
 				if err != nil {
 					errs <- err
+					return
 				}
-				entries <- &segment
+				entries <- segment
 			}(i)
 		}
 		wg.Wait()
@@ -250,7 +239,7 @@ func benchmarkParallel(b *testing.B, q queue.RepairQueue) {
 		close(entries)
 
 		for err := range errs {
-			b.Error(err)
+			t.Error(err)
 		}
 
 		var items []*pb.InjuredSegment
@@ -258,10 +247,14 @@ func benchmarkParallel(b *testing.B, q queue.RepairQueue) {
 			items = append(items, segment)
 		}
 
-		sort.Slice(items, func(i, k int) bool { return items[i].LostPieces[0] < items[k].LostPieces[0] })
-		// check if the enqueued and dequeued elements match
+		sort.Slice(items, func(i, k int) bool {
+			return items[i].LostPieces[0] < items[k].LostPieces[0]
+		})
+
+		// check if the inserted and selected elements match
+		require.Len(t, items, N)
 		for i := 0; i < N; i++ {
-			assert.Equal(b, items[i].LostPieces[0], int32(i))
+			assert.Equal(t, items[i].LostPieces[0], int32(i))
 		}
-	}
+	})
 }
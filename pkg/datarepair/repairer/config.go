@@ -19,17 +19,29 @@ import (
 
 // Config contains configurable values for repairer
 type Config struct {
-	MaxRepair    int           `help:"maximum segments that can be repaired concurrently" default:"100"`
-	Interval     time.Duration `help:"how frequently checker should audit segments" default:"1h0m0s"`
-	Timeout      time.Duration `help:"time limit for uploading repaired pieces to new storage nodes" default:"1m0s"`
-	MaxBufferMem memory.Size   `help:"maximum buffer memory (in bytes) to be allocated for read buffers" default:"4M"`
+	MaxRepair     int           `help:"maximum segments that can be repaired concurrently" default:"100"`
+	Interval      time.Duration `help:"how frequently the repair queue is checked for segments once it's empty" default:"1h0m0s"`
+	StatsInterval time.Duration `help:"how frequently the length and the oldest segment of the repair queue are sampled" default:"5m0s"`
+	Timeout       time.Duration `help:"time limit for uploading repaired pieces to new storage nodes" default:"1m0s"`
+	WorkerTimeout time.Duration `help:"time limit for the repair of a single segment" default:"30m0s"`
+	MaxBufferMem  memory.Size   `help:"maximum buffer memory (in bytes) to be allocated for read buffers" default:"4M"`
+	MaxIngress    memory.Size   `help:"how many bytes per second all the repairs may download, unlimited when 0" default:"0"`
+	MaxEgress     memory.Size   `help:"how many bytes per second all the repairs may upload, unlimited when 0" default:"0"`
+
+	MaxAttempts     int           `help:"how many times the repair of a segment is attempted before it's recorded as irreparable, unlimited when 0" default:"10"`
+	RetryBackoff    time.Duration `help:"how long a segment whose repair failed transiently is skipped, doubled with every attempt" default:"5m0s"`
+	MaxRetryBackoff time.Duration `help:"maximum time a segment whose repair failed transiently is skipped" default:"24h0m0s"`
+
+	EncoderConcurrency int `help:"maximum number of erasure shares encoded concurrently per segment (0 means unlimited)" default:"0"`
 }
 
 // GetSegmentRepairer creates a new segment repairer from storeConfig values
-func (c Config) GetSegmentRepairer(ctx context.Context, tc transport.Client, pointerdb *pointerdb.Service, orders *orders.Service, cache *overlay.Cache, identity *identity.FullIdentity) (ss SegmentRepairer, err error) {
+func (c Config) GetSegmentRepairer(ctx context.Context, tc transport.Client, pointerdb *pointerdb.Service, orders *orders.Service, cache *overlay.Cache, reliability *overlay.ReliabilityCache, identity *identity.FullIdentity) (ss SegmentRepairer, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	ec := ecclient.NewClient(tc, c.MaxBufferMem.Int())
+	ec := ecclient.NewClient(tc, c.MaxBufferMem.Int(), c.EncoderConcurrency, 0)
+
+	throttle := segments.NewThrottle(c.MaxIngress, c.MaxEgress)
 
-	return segments.NewSegmentRepairer(pointerdb, orders, cache, ec, identity, c.Timeout), nil
+	return segments.NewSegmentRepairer(pointerdb, orders, cache, reliability, ec, identity, c.Timeout, throttle), nil
 }
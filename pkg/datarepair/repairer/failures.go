@@ -0,0 +1,91 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package repairer
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/vivint/infectious"
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/storage/segments"
+	"storj.io/storj/pkg/transport"
+	"storj.io/storj/storage"
+)
+
+// failure is the class of a failed repair.
+type failure string
+
+const (
+	// failureIrreparable is a segment with fewer healthy pieces than required
+	failureIrreparable = failure("irreparable")
+	// failureMissingPointer is a segment deleted since it was queued
+	failureMissingPointer = failure("missing_pointer")
+	// failureDecode is a segment whose downloaded pieces can't be decoded
+	failureDecode = failure("decode")
+
+	// failureNotEnoughNodes is a repair without enough new nodes for the pieces
+	failureNotEnoughNodes = failure("not_enough_nodes")
+	// failureTimeout is a repair that timed out
+	failureTimeout = failure("timeout")
+	// failureDial is a repair that couldn't connect to the nodes
+	failureDial = failure("dial")
+	// failurePointerChanged is a segment changed during its repair
+	failurePointerChanged = failure("pointer_changed")
+	// failureUnknown is any other failure
+	failureUnknown = failure("unknown")
+)
+
+// transient returns whether retrying the repair later may succeed.
+func (f failure) transient() bool {
+	switch f {
+	case failureIrreparable, failureMissingPointer, failureDecode:
+		return false
+	default:
+		return true
+	}
+}
+
+// classifyFailure returns the class of the error of a repair that failed
+// within ctx.
+func classifyFailure(ctx context.Context, err error) failure {
+	cause := errs.Unwrap(err)
+	switch {
+	case segments.ErrIrreparable.Has(err):
+		return failureIrreparable
+	case storage.ErrKeyNotFound.Has(err):
+		return failureMissingPointer
+	case infectious.TooManyErrors.Contains(cause):
+		return failureDecode
+	case overlay.ErrNotEnoughNodes.Has(err):
+		return failureNotEnoughNodes
+	case storage.ErrValueChanged.Has(err):
+		return failurePointerChanged
+	case ctx.Err() == context.DeadlineExceeded || cause == context.DeadlineExceeded:
+		return failureTimeout
+	case transport.Error.Has(err):
+		return failureDial
+	}
+	if netErr, ok := cause.(net.Error); ok && netErr.Timeout() {
+		return failureTimeout
+	}
+	return failureUnknown
+}
+
+// retryDelay returns how long a segment is skipped after its attempts-th
+// repair failed transiently, the delay doubles with every attempt up to
+// maxBackoff, it doesn't grow when maxBackoff is 0.
+func retryDelay(attempts int32, backoff, maxBackoff time.Duration) time.Duration {
+	delay := backoff
+	for i := int32(1); i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if maxBackoff > 0 && delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
@@ -0,0 +1,58 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package repairer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vivint/infectious"
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/storage/segments"
+	"storj.io/storj/pkg/transport"
+	"storj.io/storj/storage"
+)
+
+func TestClassifyFailure(t *testing.T) {
+	ctx := context.Background()
+	segmentError := segments.Error
+
+	for _, test := range []struct {
+		err       error
+		failure   failure
+		transient bool
+	}{
+		{segments.ErrIrreparable.New("2 healthy pieces, 3 required"), failureIrreparable, false},
+		{segmentError.Wrap(storage.ErrKeyNotFound.New("path")), failureMissingPointer, false},
+		{segmentError.Wrap(errs.Wrap(infectious.TooManyErrors.New("decode"))), failureDecode, false},
+		{segmentError.Wrap(overlay.ErrNotEnoughNodes.New("requested 4 found 2")), failureNotEnoughNodes, true},
+		{segmentError.Wrap(storage.ErrValueChanged.New("path")), failurePointerChanged, true},
+		{segmentError.Wrap(context.DeadlineExceeded), failureTimeout, true},
+		{segmentError.Wrap(transport.Error.New("dial")), failureDial, true},
+		{segmentError.New("something"), failureUnknown, true},
+	} {
+		failure := classifyFailure(ctx, test.err)
+		assert.Equal(t, test.failure, failure, test.err.Error())
+		assert.Equal(t, test.transient, failure.transient(), test.err.Error())
+	}
+
+	// a repair exceeding the worker timeout is a timeout, whatever its error
+	timedOut, cancel := context.WithTimeout(ctx, 0)
+	defer cancel()
+	<-timedOut.Done()
+	assert.Equal(t, failureTimeout, classifyFailure(timedOut, errs.New("download failed")))
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, time.Minute, retryDelay(1, time.Minute, time.Hour))
+	assert.Equal(t, 2*time.Minute, retryDelay(2, time.Minute, time.Hour))
+	assert.Equal(t, 8*time.Minute, retryDelay(4, time.Minute, time.Hour))
+	assert.Equal(t, time.Hour, retryDelay(10, time.Minute, time.Hour))
+	assert.Equal(t, time.Hour, retryDelay(1000, time.Minute, time.Hour))
+	assert.Equal(t, time.Minute, retryDelay(10, time.Minute, 0))
+}
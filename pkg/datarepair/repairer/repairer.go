@@ -7,11 +7,15 @@ import (
 	"context"
 	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
 
 	"storj.io/storj/internal/sync2"
+	"storj.io/storj/pkg/datarepair/irreparable"
 	"storj.io/storj/pkg/datarepair/queue"
 	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/pointerdb"
 	"storj.io/storj/pkg/storj"
 	"storj.io/storj/pkg/transport"
@@ -27,6 +31,7 @@ type SegmentRepairer interface {
 // Service contains the information needed to run the repair service
 type Service struct {
 	queue     queue.RepairQueue
+	irrdb     irreparable.DB
 	config    *Config
 	limiter   *sync2.Limiter
 	ticker    *time.Ticker
@@ -34,13 +39,19 @@ type Service struct {
 	pointerdb *pointerdb.Service
 	orders    *orders.Service
 	cache     *overlay.Cache
-	repairer  SegmentRepairer
+
+	reliability *overlay.ReliabilityCache
+	repairer    SegmentRepairer
+
+	// Stats samples the metrics of the repair queue
+	Stats sync2.Cycle
 }
 
 // NewService creates repairing service
-func NewService(queue queue.RepairQueue, config *Config, interval time.Duration, concurrency int, transport transport.Client, pointerdb *pointerdb.Service, orders *orders.Service, cache *overlay.Cache) *Service {
-	return &Service{
+func NewService(queue queue.RepairQueue, irrdb irreparable.DB, config *Config, interval time.Duration, concurrency int, transport transport.Client, pointerdb *pointerdb.Service, orders *orders.Service, cache *overlay.Cache, reliability *overlay.ReliabilityCache) *Service {
+	service := &Service{
 		queue:     queue,
+		irrdb:     irrdb,
 		config:    config,
 		limiter:   sync2.NewLimiter(concurrency),
 		ticker:    time.NewTicker(interval),
@@ -48,7 +59,11 @@ func NewService(queue queue.RepairQueue, config *Config, interval time.Duration,
 		pointerdb: pointerdb,
 		orders:    orders,
 		cache:     cache,
+
+		reliability: reliability,
 	}
+	service.Stats.SetInterval(config.StatsInterval)
+	return service
 }
 
 // Close closes resources
@@ -65,45 +80,171 @@ func (service *Service) Run(ctx context.Context) (err error) {
 		service.pointerdb,
 		service.orders,
 		service.cache,
+		service.reliability,
 		service.transport.Identity(),
 	)
 	if err != nil {
 		return err
 	}
 
-	// wait for all repairs to complete
-	defer service.limiter.Wait()
+	group, ctx := errgroup.WithContext(ctx)
+	service.Stats.Start(ctx, group, func(ctx context.Context) error {
+		if err := service.sampleStats(ctx); err != nil {
+			zap.L().Error("sampling the repair queue failed", zap.Error(err))
+		}
+		return nil
+	})
+	group.Go(func() error {
+		// wait for all repairs to complete
+		defer service.limiter.Wait()
+
+		for {
+			err := service.process(ctx)
+			if err != nil {
+				zap.L().Error("process", zap.Error(err))
+			}
+
+			select {
+			case <-service.ticker.C: // wait for the next interval to happen
+			case <-ctx.Done(): // or the repairer service is canceled via context
+				return ctx.Err()
+			}
+		}
+	})
+	return group.Wait()
+}
+
+// sampleStats observes the length of the repair queue and the age of its
+// oldest segment.
+func (service *Service) sampleStats(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	stats, err := service.queue.Stats(ctx)
+	if err != nil {
+		return err
+	}
+	mon.IntVal("repair_queue_length").Observe(stats.Count)
+	if !stats.Oldest.IsZero() {
+		mon.FloatVal("repair_queue_oldest_age_seconds").Observe(time.Since(stats.Oldest).Seconds())
+	}
+	return nil
+}
 
+// process hands the segments of the repair queue to the repair workers until
+// the queue is empty. A segment that is selected a second time in the same pass
+// was requeued by a failed repair, its lease is released and the pass ends, so
+// it's retried on the next interval instead of in a loop.
+func (service *Service) process(ctx context.Context) error {
+	selected := map[string]struct{}{}
 	for {
-		err := service.process(ctx)
+		seg, err := service.queue.Select(ctx)
 		if err != nil {
-			zap.L().Error("process", zap.Error(err))
+			if storage.ErrEmptyQueue.Has(err) {
+				return nil
+			}
+			return err
+		}
+		if _, ok := selected[seg.GetPath()]; ok {
+			return service.queue.Requeue(ctx, seg, 0)
 		}
+		selected[seg.GetPath()] = struct{}{}
+		mon.IntVal("repair_segment_attempts").Observe(int64(seg.GetAttempts()))
 
-		select {
-		case <-service.ticker.C: // wait for the next interval to happen
-		case <-ctx.Done(): // or the repairer service is canceled via context
+		// wait for a free worker, a segment that isn't repaired when the
+		// service stops is selected again once its lease expires
+		if !service.limiter.Go(ctx, func() { service.worker(ctx, seg) }) {
 			return ctx.Err()
 		}
 	}
 }
 
-// process picks an item from repair queue and spawns a repair worker
-func (service *Service) process(ctx context.Context) error {
-	seg, err := service.queue.Dequeue(ctx)
+// worker repairs a single segment within the worker timeout.
+func (service *Service) worker(ctx context.Context, seg *pb.InjuredSegment) {
+	workerCtx := ctx
+	if service.config.WorkerTimeout > 0 {
+		var cancel context.CancelFunc
+		workerCtx, cancel = context.WithTimeout(ctx, service.config.WorkerTimeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := service.repairer.Repair(workerCtx, seg.GetPath(), seg.GetLostPieces())
+	mon.FloatVal("repair_segment_duration_seconds").Observe(time.Since(start).Seconds())
+
 	if err != nil {
-		if storage.ErrEmptyQueue.Has(err) {
-			return nil
+		service.handleFailure(ctx, seg, classifyFailure(workerCtx, err), err)
+		return
+	}
+
+	mon.Meter("repair_segments_repaired").Mark(1)
+	if err := service.queue.Delete(ctx, seg); err != nil {
+		zap.L().Error("deleting the repaired segment from the queue failed", zap.Error(err))
+	}
+	if insertedAt, err := ptypes.Timestamp(seg.GetInsertedAt()); err == nil {
+		mon.FloatVal("repair_segment_age_seconds").Observe(time.Since(insertedAt).Seconds())
+	}
+}
+
+// handleFailure requeues the segment of a repair that failed transiently with
+// a delay growing with its attempts. The segments whose repair failed
+// permanently or too many times are recorded as irreparable, from where an
+// operator can retry them, and deleted from the queue.
+func (service *Service) handleFailure(ctx context.Context, seg *pb.InjuredSegment, failure failure, err error) {
+	if ctx.Err() != nil {
+		// the service is stopping, the segment is selected again once its
+		// lease expires
+		return
+	}
+	mon.Meter("repair_failures_" + string(failure)).Mark(1)
+
+	if failure.transient() {
+		mon.Meter("repair_failures_transient").Mark(1)
+		if service.config.MaxAttempts <= 0 || int(seg.GetAttempts()) < service.config.MaxAttempts {
+			delay := retryDelay(seg.GetAttempts(), service.config.RetryBackoff, service.config.MaxRetryBackoff)
+			zap.L().Warn("repair failed, retrying later", zap.String("path", seg.GetPath()),
+				zap.String("failure", string(failure)), zap.Duration("delay", delay), zap.Error(err))
+			if err := service.queue.Requeue(ctx, seg, delay); err != nil {
+				zap.L().Error("requeue failed", zap.Error(err))
+			}
+			return
 		}
-		return err
+		err = Error.New("repair failed %d times: %v", seg.GetAttempts(), err)
+	} else {
+		mon.Meter("repair_failures_permanent").Mark(1)
 	}
 
-	service.limiter.Go(ctx, func() {
-		err := service.repairer.Repair(ctx, seg.GetPath(), seg.GetLostPieces())
-		if err != nil {
-			zap.L().Error("Repair failed", zap.Error(err))
+	// a deleted segment doesn't need to be repaired anymore
+	if failure != failureMissingPointer {
+		zap.L().Warn("segment is irreparable", zap.String("path", seg.GetPath()),
+			zap.String("failure", string(failure)), zap.Error(err))
+		if err := service.recordIrreparable(ctx, seg, err); err != nil {
+			zap.L().Error("recording the irreparable segment failed", zap.Error(err))
+			return
 		}
-	})
+	}
+	if err := service.queue.Delete(ctx, seg); err != nil {
+		zap.L().Error("deleting the segment from the queue failed", zap.Error(err))
+	}
+}
 
-	return nil
+// recordIrreparable records the segment in the irreparable registry with the
+// error of the failed repair.
+func (service *Service) recordIrreparable(ctx context.Context, seg *pb.InjuredSegment, repairErr error) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	pointer, err := service.pointerdb.Get(seg.GetPath())
+	if err != nil {
+		return err
+	}
+
+	mon.Meter("repair_segments_irreparable").Mark(1)
+	return service.irrdb.IncrementRepairAttempts(ctx, &pb.IrreparableSegment{
+		Path:               []byte(seg.GetPath()),
+		SegmentDetail:      pointer,
+		LostPieces:         int32(len(seg.GetLostPieces())),
+		LostPieceNums:      seg.GetLostPieces(),
+		LastRepairAttempt:  time.Now().Unix(),
+		RepairAttemptCount: int64(seg.GetAttempts()),
+		LastError:          repairErr.Error(),
+	})
 }
@@ -6,6 +6,7 @@ package discovery
 import (
 	"context"
 	"crypto/rand"
+	"sync"
 	"time"
 
 	"github.com/zeebo/errs"
@@ -13,6 +14,7 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"storj.io/storj/internal/sync2"
+	"storj.io/storj/internal/version"
 	"storj.io/storj/pkg/kademlia"
 	"storj.io/storj/pkg/overlay"
 	"storj.io/storj/pkg/pb"
@@ -42,7 +44,10 @@ type Discovery struct {
 
 	// refreshOffset tracks the offset of the current refresh cycle
 	refreshOffset int64
-	refreshLimit  int
+
+	// refreshLimit can be changed while the refresh is running
+	refreshMu    sync.Mutex
+	refreshLimit int
 
 	Refresh   sync2.Cycle
 	Graveyard sync2.Cycle
@@ -75,6 +80,13 @@ func (discovery *Discovery) Close() error {
 	return nil
 }
 
+// SetRefreshLimit changes the amount of nodes refreshed at each interval
+func (discovery *Discovery) SetRefreshLimit(limit int) {
+	discovery.refreshMu.Lock()
+	defer discovery.refreshMu.Unlock()
+	discovery.refreshLimit = limit
+}
+
 // Run runs the discovery service
 func (discovery *Discovery) Run(ctx context.Context) error {
 	var group errgroup.Group
@@ -113,7 +125,11 @@ func (discovery *Discovery) refresh(ctx context.Context) error {
 		}
 	}
 
-	list, more, err := discovery.cache.Paginate(ctx, discovery.refreshOffset, discovery.refreshLimit)
+	discovery.refreshMu.Lock()
+	limit := discovery.refreshLimit
+	discovery.refreshMu.Unlock()
+
+	list, more, err := discovery.cache.Paginate(ctx, discovery.refreshOffset, limit)
 	if err != nil {
 		return Error.Wrap(err)
 	}
@@ -152,6 +168,7 @@ func (discovery *Discovery) refresh(ctx context.Context) error {
 		if err != nil {
 			discovery.log.Error("could not put node into cache", zap.String("ID", ping.Id.String()), zap.Error(err))
 		}
+		discovery.cache.UpdateNetwork(ctx, ping.Id, ping.GetAddress().GetAddress())
 
 		// update wallet with correct info
 		info, err := discovery.kad.FetchInfo(ctx, *node)
@@ -166,6 +183,19 @@ func (discovery *Discovery) refresh(ctx context.Context) error {
 		if err != nil {
 			discovery.log.Warn("could not update node operator", zap.String("ID", ping.GetAddress().String()))
 		}
+
+		if info.GetVersion() != nil {
+			nodeVersion, err := version.InfoFromProto(info.GetVersion())
+			if err != nil {
+				discovery.log.Warn("invalid node version", zap.String("ID", ping.GetAddress().String()), zap.Error(err))
+				continue
+			}
+
+			err = discovery.cache.UpdateVersion(ctx, ping.Id, nodeVersion)
+			if err != nil {
+				discovery.log.Warn("could not update node version", zap.String("ID", ping.GetAddress().String()))
+			}
+		}
 	}
 
 	return nil
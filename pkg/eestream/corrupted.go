@@ -0,0 +1,49 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package eestream
+
+import (
+	"sort"
+	"sync"
+)
+
+// CorruptedPieces collects the numbers of the erasure pieces found to be
+// corrupted while decoding. It is safe for concurrent use. A nil
+// *CorruptedPieces ignores all reports.
+type CorruptedPieces struct {
+	mu     sync.Mutex
+	pieces map[int]struct{}
+}
+
+// NewCorruptedPieces creates an empty CorruptedPieces.
+func NewCorruptedPieces() *CorruptedPieces {
+	return &CorruptedPieces{pieces: make(map[int]struct{})}
+}
+
+// Add reports the given piece numbers as corrupted.
+func (c *CorruptedPieces) Add(nums ...int) {
+	if c == nil || len(nums) == 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, num := range nums {
+		c.pieces[num] = struct{}{}
+	}
+}
+
+// List returns the sorted numbers of the corrupted pieces.
+func (c *CorruptedPieces) List() []int {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	nums := make([]int, 0, len(c.pieces))
+	for num := range c.pieces {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+	return nums
+}
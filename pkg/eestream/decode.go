@@ -38,6 +38,12 @@ type decodedReader struct {
 // mbm is the maximum memory (in bytes) to be allocated for read buffers. If
 // set to 0, the minimum possible memory will be used.
 func DecodeReaders(ctx context.Context, rs map[int]io.ReadCloser, es ErasureScheme, expectedSize int64, mbm int) io.ReadCloser {
+	return DecodeReadersWithReport(ctx, rs, es, expectedSize, mbm, nil)
+}
+
+// DecodeReadersWithReport is like DecodeReaders, but additionally reports the
+// numbers of the erasure pieces found to be corrupted to corrupted.
+func DecodeReadersWithReport(ctx context.Context, rs map[int]io.ReadCloser, es ErasureScheme, expectedSize int64, mbm int, corrupted *CorruptedPieces) io.ReadCloser {
 	if expectedSize < 0 {
 		return readcloser.FatalReadCloser(Error.New("negative expected size"))
 	}
@@ -56,6 +62,7 @@ func DecodeReaders(ctx context.Context, rs map[int]io.ReadCloser, es ErasureSche
 		outbuf:          make([]byte, 0, es.StripeSize()),
 		expectedStripes: expectedSize / int64(es.StripeSize()),
 	}
+	dr.stripeReader.corrupted = corrupted
 	dr.ctx, dr.cancel = context.WithCancel(ctx)
 	// Kick off a goroutine to watch for context cancelation.
 	go func() {
@@ -112,10 +119,11 @@ func (dr *decodedReader) Close() error {
 }
 
 type decodedRanger struct {
-	es     ErasureScheme
-	rrs    map[int]ranger.Ranger
-	inSize int64
-	mbm    int // max buffer memory
+	es        ErasureScheme
+	rrs       map[int]ranger.Ranger
+	inSize    int64
+	mbm       int // max buffer memory
+	corrupted *CorruptedPieces
 }
 
 // Decode takes a map of Rangers and an ErasureScheme and returns a combined
@@ -125,6 +133,13 @@ type decodedRanger struct {
 // mbm is the maximum memory (in bytes) to be allocated for read buffers. If
 // set to 0, the minimum possible memory will be used.
 func Decode(rrs map[int]ranger.Ranger, es ErasureScheme, mbm int) (ranger.Ranger, error) {
+	return DecodeWithReport(rrs, es, mbm, nil)
+}
+
+// DecodeWithReport is like Decode, but additionally reports the numbers of the
+// erasure pieces found to be corrupted while reading from the returned Ranger
+// to corrupted.
+func DecodeWithReport(rrs map[int]ranger.Ranger, es ErasureScheme, mbm int, corrupted *CorruptedPieces) (ranger.Ranger, error) {
 	if err := checkMBM(mbm); err != nil {
 		return nil, err
 	}
@@ -151,10 +166,11 @@ func Decode(rrs map[int]ranger.Ranger, es ErasureScheme, mbm int) (ranger.Ranger
 			size, es.ErasureShareSize())
 	}
 	return &decodedRanger{
-		es:     es,
-		rrs:    rrs,
-		inSize: size,
-		mbm:    mbm,
+		es:        es,
+		rrs:       rrs,
+		inSize:    size,
+		mbm:       mbm,
+		corrupted: corrupted,
 	}, nil
 }
 
@@ -194,7 +210,7 @@ func (dr *decodedRanger) Range(ctx context.Context, offset, length int64) (io.Re
 		}
 	}
 	// decode from all those ranges
-	r := DecodeReaders(ctx, readers, dr.es, blockCount*int64(dr.es.StripeSize()), dr.mbm)
+	r := DecodeReadersWithReport(ctx, readers, dr.es, blockCount*int64(dr.es.StripeSize()), dr.mbm, dr.corrupted)
 	// offset might start a few bytes in, potentially discard the initial bytes
 	_, err := io.CopyN(ioutil.Discard, r,
 		offset-firstBlock*int64(dr.es.StripeSize()))
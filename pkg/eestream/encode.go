@@ -33,6 +33,11 @@ type ErasureScheme interface {
 	// 'in', and append the combined data to 'out', returning it.
 	Decode(out []byte, in map[int][]byte) ([]byte, error)
 
+	// DecodeAndIdentify is like Decode, but additionally returns the piece
+	// numbers of the erasure shares in 'in' that were found to be corrupted.
+	// The erasure shares in 'in' are not modified.
+	DecodeAndIdentify(out []byte, in map[int][]byte) (_ []byte, corrupted []int, _ error)
+
 	// ErasureShareSize is the size of the erasure shares that come from Encode
 	// and are passed to Decode.
 	ErasureShareSize() int
@@ -119,15 +124,28 @@ func (rs *RedundancyStrategy) OptimalThreshold() int {
 type encodedReader struct {
 	rs     RedundancyStrategy
 	pieces map[int]*encodedPiece
+	// limiter restricts the number of concurrently running encoders,
+	// it is nil when the concurrency is unlimited.
+	limiter chan struct{}
 }
 
 // EncodeReader takes a Reader and a RedundancyStrategy and returns a slice of
 // io.ReadClosers.
 func EncodeReader(ctx context.Context, r io.Reader, rs RedundancyStrategy) ([]io.ReadCloser, error) {
+	return EncodeReaderWithConcurrency(ctx, r, rs, 0)
+}
+
+// EncodeReaderWithConcurrency is like EncodeReader, but allows at most
+// concurrency erasure shares of the segment to be encoded at the same time.
+// A concurrency of 0 or less means that every piece is encoded independently.
+func EncodeReaderWithConcurrency(ctx context.Context, r io.Reader, rs RedundancyStrategy, concurrency int) ([]io.ReadCloser, error) {
 	er := &encodedReader{
 		rs:     rs,
 		pieces: make(map[int]*encodedPiece, rs.TotalCount()),
 	}
+	if concurrency > 0 && concurrency < rs.TotalCount() {
+		er.limiter = make(chan struct{}, concurrency)
+	}
 
 	pipeReaders, pipeWriter, err := sync2.NewTeeFile(rs.TotalCount(), os.TempDir())
 	if err != nil {
@@ -159,6 +177,15 @@ func (er *encodedReader) fillBuffer(ctx context.Context, r io.Reader, w sync2.Pi
 	}
 }
 
+// encodeSingle encodes the num-th erasure share while respecting the concurrency limit.
+func (er *encodedReader) encodeSingle(stripe, share []byte, num int) error {
+	if er.limiter != nil {
+		er.limiter <- struct{}{}
+		defer func() { <-er.limiter }()
+	}
+	return er.rs.EncodeSingle(stripe, share, num)
+}
+
 type encodedPiece struct {
 	er            *encodedReader
 	pipeReader    sync2.PipeReader
@@ -183,7 +210,7 @@ func (ep *encodedPiece) Read(p []byte) (n int, err error) {
 		}
 
 		// encode the num-th erasure share
-		err = ep.er.rs.EncodeSingle(ep.stripeBuf, ep.shareBuf, ep.num)
+		err = ep.er.encodeSingle(ep.stripeBuf, ep.shareBuf, ep.num)
 		if err != nil {
 			return 0, err
 		}
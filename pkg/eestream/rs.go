@@ -4,6 +4,8 @@
 package eestream
 
 import (
+	"bytes"
+
 	"github.com/vivint/infectious"
 )
 
@@ -36,6 +38,35 @@ func (s *rsScheme) Decode(out []byte, in map[int][]byte) ([]byte, error) {
 	return s.fc.Decode(out, shares)
 }
 
+func (s *rsScheme) DecodeAndIdentify(out []byte, in map[int][]byte) (_ []byte, corrupted []int, err error) {
+	if len(in) <= s.fc.Required() {
+		// there is no redundancy to detect any corruption with
+		out, err = s.Decode(out, in)
+		return out, nil, err
+	}
+
+	// decode copies of the erasure shares, since the error correction
+	// modifies the corrupted erasure shares in place
+	shares := make([]infectious.Share, 0, len(in))
+	buf := make([]byte, 0, len(in)*s.erasureShareSize)
+	for num, data := range in {
+		buf = append(buf, data...)
+		shares = append(shares, infectious.Share{Number: num, Data: buf[len(buf)-len(data):]})
+	}
+
+	out, err = s.fc.Decode(out, shares)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, share := range shares {
+		if !bytes.Equal(share.Data, in[share.Number]) {
+			corrupted = append(corrupted, share.Number)
+		}
+	}
+	return out, corrupted, nil
+}
+
 func (s *rsScheme) ErasureShareSize() int {
 	return s.erasureShareSize
 }
@@ -551,6 +551,64 @@ func TestDecoderErrorWithStalledReaders(t *testing.T) {
 	}
 }
 
+func TestRSDecodeAndIdentify(t *testing.T) {
+	fc, err := infectious.NewFEC(4, 10)
+	require.NoError(t, err)
+	es := NewRSScheme(fc, 64)
+
+	stripe := randData(es.StripeSize())
+	shares := make(map[int][]byte)
+	err = es.Encode(stripe, func(num int, data []byte) {
+		shares[num] = append([]byte{}, data...)
+	})
+	require.NoError(t, err)
+
+	corrupt := func(nums ...int) map[int][]byte {
+		in := make(map[int][]byte, len(shares))
+		for num, data := range shares {
+			in[num] = append([]byte{}, data...)
+		}
+		for _, num := range nums {
+			in[num][0]++
+		}
+		return in
+	}
+
+	for _, nums := range [][]int{nil, {2}, {1, 7}, {0, 5, 9}} {
+		in := corrupt(nums...)
+		original := corrupt(nums...)
+
+		out, corrupted, err := es.DecodeAndIdentify(nil, in)
+		require.NoError(t, err)
+		assert.Equal(t, stripe, out)
+		assert.ElementsMatch(t, nums, corrupted)
+		assert.Equal(t, original, in, "input must not be modified")
+	}
+
+	// with just the required number of shares, corruption cannot be detected
+	in := corrupt()
+	for num := range in {
+		if num >= es.RequiredCount() {
+			delete(in, num)
+		}
+	}
+	_, corrupted, err := es.DecodeAndIdentify(nil, in)
+	require.NoError(t, err)
+	assert.Empty(t, corrupted)
+}
+
+func TestCorruptedPieces(t *testing.T) {
+	var nilPieces *CorruptedPieces
+	nilPieces.Add(1, 2)
+	assert.Empty(t, nilPieces.List())
+
+	pieces := NewCorruptedPieces()
+	assert.Empty(t, pieces.List())
+	pieces.Add(5, 1)
+	pieces.Add(1, 3)
+	assert.Equal(t, []int{1, 3, 5}, pieces.List())
+}
+
 func BenchmarkReedSolomonErasureScheme(b *testing.B) {
 	data := randData(8 << 20)
 	output := make([]byte, 8<<20)
@@ -644,6 +702,108 @@ func BenchmarkReedSolomonErasureScheme(b *testing.B) {
 	}
 }
 
+func TestRSWithConcurrency(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	data := randData(32 * 1024)
+	fc, err := infectious.NewFEC(4, 8)
+	require.NoError(t, err)
+	rs, err := NewRedundancyStrategy(NewRSScheme(fc, 1024), 0, 0)
+	require.NoError(t, err)
+
+	for _, concurrency := range []int{0, 1, 3, 8, 16} {
+		readers, err := EncodeReaderWithConcurrency(ctx, bytes.NewReader(data), rs, concurrency)
+		require.NoError(t, err)
+
+		readerMap := make(map[int]io.ReadCloser, len(readers))
+		for i, reader := range readers {
+			readerMap[i] = reader
+		}
+		decoder := DecodeReaders(ctx, readerMap, rs, int64(len(data)), 0)
+		data2, err := ioutil.ReadAll(decoder)
+		require.NoError(t, err)
+		require.NoError(t, decoder.Close())
+		assert.Equal(t, data, data2, "concurrency %d", concurrency)
+	}
+}
+
+func BenchmarkEncodeDecodeReaders(b *testing.B) {
+	ctx := context.Background()
+	data := randData(4 << 20)
+
+	confs := []struct{ required, total int }{
+		{4, 8},
+		{29, 95},
+	}
+	shareSizes := []memory.Size{
+		1 * memory.KiB,
+		4 * memory.KiB,
+		64 * memory.KiB,
+	}
+	concurrencies := []int{0, 1, 4}
+
+	for _, conf := range confs {
+		for _, shareSize := range shareSizes {
+			fc, err := infectious.NewFEC(conf.required, conf.total)
+			if err != nil {
+				b.Fatal(err)
+			}
+			rs, err := NewRedundancyStrategy(NewRSScheme(fc, shareSize.Int()), 0, 0)
+			if err != nil {
+				b.Fatal(err)
+			}
+			confname := fmt.Sprintf("r%dt%d/%v", conf.required, conf.total, shareSize)
+			// the encoder expects the data to be a multiple of the stripe size
+			dataSize := len(data) / rs.StripeSize() * rs.StripeSize()
+
+			for _, concurrency := range concurrencies {
+				b.Run(fmt.Sprintf("Encode/%s/c%d", confname, concurrency), func(b *testing.B) {
+					b.SetBytes(int64(dataSize))
+					for i := 0; i < b.N; i++ {
+						readers, err := EncodeReaderWithConcurrency(ctx, bytes.NewReader(data[:dataSize]), rs, concurrency)
+						if err != nil {
+							b.Fatal(err)
+						}
+						if _, err := readAll(readers); err != nil {
+							b.Fatal(err)
+						}
+						for _, reader := range readers {
+							_ = reader.Close()
+						}
+					}
+				})
+			}
+
+			readers, err := EncodeReader(ctx, bytes.NewReader(data[:dataSize]), rs)
+			if err != nil {
+				b.Fatal(err)
+			}
+			pieces, err := readAll(readers)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.Run("Decode/"+confname, func(b *testing.B) {
+				b.SetBytes(int64(dataSize))
+				for i := 0; i < b.N; i++ {
+					readerMap := make(map[int]io.ReadCloser, conf.required)
+					for num := 0; num < conf.required; num++ {
+						readerMap[num] = ioutil.NopCloser(bytes.NewReader(pieces[num]))
+					}
+					decoder := DecodeReaders(ctx, readerMap, rs, int64(dataSize), 0)
+					if _, err := io.Copy(ioutil.Discard, decoder); err != nil {
+						b.Fatal(err)
+					}
+					if err := decoder.Close(); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
 func TestCalcPieceSize(t *testing.T) {
 	ctx := testcontext.New(t)
 	defer ctx.Cleanup()
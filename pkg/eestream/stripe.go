@@ -22,6 +22,7 @@ type StripeReader struct {
 	inbufs      map[int][]byte
 	inmap       map[int][]byte
 	errmap      map[int]error
+	corrupted   *CorruptedPieces
 }
 
 // NewStripeReader creates a new StripeReader from the given readers, erasure
@@ -95,7 +96,7 @@ func (r *StripeReader) ReadStripe(num int64, p []byte) ([]byte, error) {
 			r.cond.Wait()
 		}
 		if r.hasEnoughShares() {
-			out, err := r.scheme.Decode(p, r.inmap)
+			out, err := r.decode(p)
 			if err != nil {
 				if r.shouldWaitForMore(err) {
 					continue
@@ -109,6 +110,22 @@ func (r *StripeReader) ReadStripe(num int64, p []byte) ([]byte, error) {
 	return nil, r.combineErrs(num)
 }
 
+// decode decodes the erasure shares read so far. The corrupted shares are
+// only identified when somebody collects them, as it's more expensive than
+// decoding.
+func (r *StripeReader) decode(p []byte) ([]byte, error) {
+	if r.corrupted == nil {
+		return r.scheme.Decode(p, r.inmap)
+	}
+
+	out, corrupted, err := r.scheme.DecodeAndIdentify(p, r.inmap)
+	if err != nil {
+		return nil, err
+	}
+	r.corrupted.Add(corrupted...)
+	return out, nil
+}
+
 // readAvailableShares reads the available num-th erasure shares from the piece
 // buffers without blocking. The return value n is the number of erasure shares
 // read.
@@ -36,7 +36,7 @@ func NewRevocationDB(revocationDBURL string) (*RevocationDB, error) {
 		if err != nil {
 			return nil, extensions.ErrRevocationDB.Wrap(err)
 		}
-	case "redis":
+	case "redis", "redis-sentinel", "redis-cluster":
 		db, err = newRevocationDBRedis(revocationDBURL)
 		if err != nil {
 			return nil, extensions.ErrRevocationDB.Wrap(err)
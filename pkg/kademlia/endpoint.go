@@ -10,6 +10,7 @@ import (
 	"github.com/zeebo/errs"
 	"go.uber.org/zap"
 
+	"storj.io/storj/internal/version"
 	"storj.io/storj/pkg/pb"
 )
 
@@ -83,6 +84,11 @@ func (endpoint *Endpoint) Ping(ctx context.Context, req *pb.PingRequest) (*pb.Pi
 func (endpoint *Endpoint) RequestInfo(ctx context.Context, req *pb.InfoRequest) (*pb.InfoResponse, error) {
 	self := endpoint.service.Local()
 
+	nodeVersion, err := version.Build.Proto()
+	if err != nil {
+		return nil, EndpointError.Wrap(err)
+	}
+
 	return &pb.InfoResponse{
 		Type: self.GetType(),
 		Operator: &pb.NodeOperator{
@@ -93,5 +99,6 @@ func (endpoint *Endpoint) RequestInfo(ctx context.Context, req *pb.InfoRequest)
 			FreeBandwidth: self.GetRestrictions().GetFreeBandwidth(),
 			FreeDisk:      self.GetRestrictions().GetFreeDisk(),
 		},
+		Version: nodeVersion,
 	}, nil
 }
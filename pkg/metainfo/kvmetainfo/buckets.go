@@ -7,6 +7,7 @@ import (
 	"context"
 
 	"storj.io/storj/pkg/storage/buckets"
+	"storj.io/storj/pkg/storage/streams"
 	"storj.io/storj/pkg/storj"
 )
 
@@ -18,7 +19,7 @@ func (db *DB) CreateBucket(ctx context.Context, bucket string, info *storj.Bucke
 		return storj.Bucket{}, storj.ErrNoBucket.New("")
 	}
 
-	meta, err := db.buckets.Put(ctx, bucket, getPathCipher(info))
+	meta, err := db.buckets.Put(ctx, bucket, getPathCipher(info), getOptions(info))
 	if err != nil {
 		return storj.Bucket{}, err
 	}
@@ -104,10 +105,22 @@ func getPathCipher(info *storj.Bucket) storj.Cipher {
 	return info.PathCipher
 }
 
+func getOptions(info *storj.Bucket) streams.Options {
+	if info == nil {
+		return streams.Options{}
+	}
+	return streams.Options{
+		Padding:    info.Padding,
+		Convergent: info.Convergent,
+	}
+}
+
 func bucketFromMeta(bucket string, meta buckets.Meta) storj.Bucket {
 	return storj.Bucket{
 		Name:       bucket,
 		Created:    meta.Created,
 		PathCipher: meta.PathEncryptionType,
+		Padding:    meta.Padding,
+		Convergent: meta.Convergent,
 	}
 }
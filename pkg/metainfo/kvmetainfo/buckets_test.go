@@ -74,9 +74,9 @@ func TestBucketsBasic(t *testing.T) {
 }
 
 func TestBucketsReadNewWayWriteOldWay(t *testing.T) {
-	runTest(t, func(ctx context.Context, planet *testplanet.Planet, db *kvmetainfo.DB, buckets buckets.Store, streams streams.Store) {
+	runTest(t, func(ctx context.Context, planet *testplanet.Planet, db *kvmetainfo.DB, buckets buckets.Store, _ streams.Store) {
 		// (Old API) Create new bucket
-		_, err := buckets.Put(ctx, TestBucket, storj.AESGCM)
+		_, err := buckets.Put(ctx, TestBucket, storj.AESGCM, streams.Options{})
 		assert.NoError(t, err)
 
 		// (New API) Check that bucket list include the new bucket
@@ -354,7 +354,7 @@ func newMetainfoParts(planet *testplanet.Planet) (*kvmetainfo.DB, buckets.Store,
 		return nil, nil, nil, err
 	}
 
-	ec := ecclient.NewClient(planet.Uplinks[0].Transport, 0)
+	ec := ecclient.NewClient(planet.Uplinks[0].Transport, 0, 0, 0)
 	fc, err := infectious.NewFEC(2, 4)
 	if err != nil {
 		return nil, nil, nil, err
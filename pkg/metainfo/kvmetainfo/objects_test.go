@@ -153,6 +153,61 @@ func TestGetObjectStream(t *testing.T) {
 	})
 }
 
+func TestGetObjectStreamPadded(t *testing.T) {
+	runTest(t, func(ctx context.Context, planet *testplanet.Planet, db *kvmetainfo.DB, buckets buckets.Store, streams streams.Store) {
+		padding := storj.Padding{PowerOfTwo: true, Boundary: 8 * memory.KiB.Int64()}
+
+		bucket, err := db.CreateBucket(ctx, TestBucket, &storj.Bucket{PathCipher: storj.AESGCM, Padding: padding})
+		require.NoError(t, err)
+		assert.Equal(t, padding, bucket.Padding)
+
+		bucket, err = db.GetBucket(ctx, TestBucket)
+		require.NoError(t, err)
+		assert.Equal(t, padding, bucket.Padding)
+
+		for _, size := range []memory.Size{0, 4, 1 * memory.KiB, 5 * memory.KiB, 32*memory.KiB + 1} {
+			data := make([]byte, size.Int())
+			_, err := rand.Read(data)
+			require.NoError(t, err)
+
+			path := storj.Path(fmt.Sprintf("file-%d", size.Int()))
+			upload(ctx, t, db, streams, bucket, path, data)
+
+			readOnly, err := db.GetObjectStream(ctx, bucket.Name, path)
+			require.NoError(t, err)
+			assert.Equal(t, size.Int64(), readOnly.Info().Size)
+
+			download := stream.NewDownload(ctx, readOnly, streams)
+			downloaded := make([]byte, len(data))
+			_, err = io.ReadFull(download, downloaded)
+			assert.NoError(t, err)
+			assert.NoError(t, download.Close())
+			assert.Equal(t, data, downloaded)
+		}
+	})
+}
+
+func TestGetObjectStreamConvergent(t *testing.T) {
+	runTest(t, func(ctx context.Context, planet *testplanet.Planet, db *kvmetainfo.DB, buckets buckets.Store, streams streams.Store) {
+		bucket, err := db.CreateBucket(ctx, TestBucket, &storj.Bucket{PathCipher: storj.AESGCM, Convergent: true})
+		require.NoError(t, err)
+		assert.True(t, bucket.Convergent)
+
+		bucket, err = db.GetBucket(ctx, TestBucket)
+		require.NoError(t, err)
+		assert.True(t, bucket.Convergent)
+
+		data := make([]byte, 32*memory.KiB)
+		_, err = rand.Read(data)
+		require.NoError(t, err)
+
+		for _, path := range []storj.Path{"first-file", "second-file"} {
+			upload(ctx, t, db, streams, bucket, path, data)
+			assertStream(ctx, t, db, streams, bucket, path, int64(len(data)), data)
+		}
+	})
+}
+
 func upload(ctx context.Context, t *testing.T, db *kvmetainfo.DB, streams streams.Store, bucket storj.Bucket, path storj.Path, data []byte) {
 	obj, err := db.CreateObject(ctx, bucket.Name, path, nil)
 	require.NoError(t, err)
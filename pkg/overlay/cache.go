@@ -6,10 +6,13 @@ package overlay
 import (
 	"context"
 	"errors"
+	"net"
+	"time"
 
 	"github.com/zeebo/errs"
 	"go.uber.org/zap"
 
+	"storj.io/storj/internal/version"
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/storj"
 	"storj.io/storj/storage"
@@ -32,6 +35,9 @@ var ErrBucketNotFound = errs.New("bucket not found")
 // ErrNotEnoughNodes is when selecting nodes failed with the given parameters
 var ErrNotEnoughNodes = errs.Class("not enough nodes")
 
+// ErrNodeOffline is returned if a node is offline
+var ErrNodeOffline = errs.Class("node is offline")
+
 // OverlayError creates class of errors for stack traces
 var OverlayError = errs.Class("overlay error")
 
@@ -59,18 +65,36 @@ type DB interface {
 	CreateStats(ctx context.Context, nodeID storj.NodeID, initial *NodeStats) (stats *NodeStats, err error)
 	// GetStats returns node stats.
 	GetStats(ctx context.Context, nodeID storj.NodeID) (stats *NodeStats, err error)
-	// FindInvalidNodes finds a subset of storagenodes that have stats below provided reputation requirements.
+	// FindInvalidNodes finds a subset of storagenodes that have stats below provided reputation requirements or that were disqualified.
 	FindInvalidNodes(ctx context.Context, nodeIDs storj.NodeIDList, maxStats *NodeStats) (invalid storj.NodeIDList, err error)
 	// UpdateStats all parts of single storagenode's stats.
 	UpdateStats(ctx context.Context, request *UpdateRequest) (stats *NodeStats, err error)
 	// UpdateOperator updates the email and wallet for a given node ID for satellite payments.
 	UpdateOperator(ctx context.Context, node storj.NodeID, updatedOperator pb.NodeOperator) (stats *NodeStats, err error)
 	// UpdateUptime updates a single storagenode's uptime stats.
-	UpdateUptime(ctx context.Context, nodeID storj.NodeID, isUp bool) (stats *NodeStats, err error)
+	UpdateUptime(ctx context.Context, nodeID storj.NodeID, isUp bool, reputation ReputationUpdate) (stats *NodeStats, err error)
 	// UpdateBatch for updating multiple storage nodes' stats.
 	UpdateBatch(ctx context.Context, requests []*UpdateRequest) (statslist []*NodeStats, failed []*UpdateRequest, err error)
 	// CreateEntryIfNotExists creates a node stats entry if it didn't already exist.
 	CreateEntryIfNotExists(ctx context.Context, value *pb.Node) (stats *NodeStats, err error)
+	// ReputationHistory returns the daily reputation scores of a node since the given time.
+	ReputationHistory(ctx context.Context, nodeID storj.NodeID, since time.Time) ([]ReputationScore, error)
+
+	// UpdateVersion updates the software version a node reported.
+	UpdateVersion(ctx context.Context, nodeID storj.NodeID, nodeVersion version.Info) error
+	// FindOutdatedNodes finds a subset of storagenodes that run a version below the minimum.
+	FindOutdatedNodes(ctx context.Context, nodeIDs storj.NodeIDList, minimum version.SemVer) (outdated storj.NodeIDList, err error)
+
+	// Reliable returns the nodes that weren't disqualified and whose last contact didn't fail.
+	Reliable(ctx context.Context) (storj.NodeIDList, error)
+
+	// UpdateLastIP records the IP the node was last contacted at and its network.
+	UpdateLastIP(ctx context.Context, nodeID storj.NodeID, lastIP, lastNet string) error
+	// LastNets returns the network of the last IP of every node with a known IP.
+	LastNets(ctx context.Context) (map[storj.NodeID]string, error)
+
+	// UpdateVetted records that the node passed vetting, keeping the time of a node that already passed it.
+	UpdateVetted(ctx context.Context, nodeID storj.NodeID, vettedAt time.Time) error
 }
 
 // FindStorageNodesRequest defines easy request parameters.
@@ -94,7 +118,12 @@ type NodeCriteria struct {
 	UptimeCount        int64
 	UptimeSuccessRatio float64
 
+	MinimumVersion version.SemVer
+
 	Excluded []storj.NodeID
+	// DistinctIP selects nodes in distinct networks, which aren't the
+	// networks of the excluded nodes
+	DistinctIP bool
 }
 
 // NewNodeCriteria are the requirement for selecting new nodes
@@ -102,9 +131,17 @@ type NewNodeCriteria struct {
 	FreeBandwidth int64
 	FreeDisk      int64
 
-	AuditThreshold int64
+	// a node is new until it passed vetting by being audited AuditThreshold
+	// times and checked for uptime UptimeThreshold times
+	AuditThreshold  int64
+	UptimeThreshold int64
+
+	MinimumVersion version.SemVer
 
 	Excluded []storj.NodeID
+	// DistinctIP selects nodes in distinct networks, which aren't the
+	// networks of the excluded nodes
+	DistinctIP bool
 }
 
 // UpdateRequest is used to update a node status.
@@ -112,6 +149,10 @@ type UpdateRequest struct {
 	NodeID       storj.NodeID
 	AuditSuccess bool
 	IsUp         bool
+
+	// AuditReputation and UptimeReputation are set by the Cache from its config.
+	AuditReputation  ReputationUpdate
+	UptimeReputation ReputationUpdate
 }
 
 // NodeStats contains statistics about a node.
@@ -124,6 +165,12 @@ type NodeStats struct {
 	UptimeSuccessCount int64
 	UptimeCount        int64
 	Operator           pb.NodeOperator
+
+	AuditReputation  Reputation
+	UptimeReputation Reputation
+	Disqualified     *time.Time
+	// VettedAt is when the node passed vetting, nil when it didn't yet
+	VettedAt *time.Time
 }
 
 // Cache is used to store and handle node information
@@ -131,15 +178,27 @@ type Cache struct {
 	log         *zap.Logger
 	db          DB
 	preferences NodeSelectionConfig
+	reputation  ReputationConfig
+
+	// selection is nil when the selection cache is disabled
+	selection *SelectionCache
+	// reliability is invalidated when the cache disqualifies one of its
+	// reliable nodes
+	reliability *ReliabilityCache
 }
 
 // NewCache returns a new Cache
-func NewCache(log *zap.Logger, db DB, preferences NodeSelectionConfig) *Cache {
-	return &Cache{
+func NewCache(log *zap.Logger, db DB, preferences NodeSelectionConfig, reputation ReputationConfig) *Cache {
+	cache := &Cache{
 		log:         log,
 		db:          db,
 		preferences: preferences,
+		reputation:  reputation,
+	}
+	if preferences.SelectionCacheStaleness > 0 {
+		cache.selection = NewSelectionCache(db, preferences, preferences.SelectionCacheStaleness)
 	}
+	return cache
 }
 
 // Close closes resources
@@ -192,8 +251,12 @@ func (cache *Cache) OfflineNodes(ctx context.Context, nodes []storj.NodeID) (off
 	return offline, nil
 }
 
-// FindStorageNodes searches the overlay network for nodes that meet the provided requirements
+// FindStorageNodes searches the overlay network for nodes that meet the provided requirements,
+// the nodes are selected from the selection cache when it's enabled
 func (cache *Cache) FindStorageNodes(ctx context.Context, req FindStorageNodesRequest) ([]*pb.Node, error) {
+	if cache.selection != nil {
+		return cache.selection.SelectNodes(ctx, req)
+	}
 	return cache.FindStorageNodesWithPreferences(ctx, req, &cache.preferences)
 }
 
@@ -206,40 +269,30 @@ func (cache *Cache) FindStorageNodesWithPreferences(ctx context.Context, req Fin
 	// TODO: add sanity limits to requested node count
 	// TODO: add sanity limits to excluded nodes
 
-	reputableNodeCount := req.MinimumRequiredNodes
-	if reputableNodeCount <= 0 {
-		reputableNodeCount = req.RequestedCount
-	}
+	totalCount, newNodeCount := selectionCounts(req, preferences)
 
-	auditCount := preferences.AuditCount
-	if auditCount < preferences.NewNodeAuditThreshold {
-		auditCount = preferences.NewNodeAuditThreshold
+	criteria, newCriteria, err := selectionCriteria(req, preferences)
+	if err != nil {
+		return nil, err
 	}
 
-	reputableNodes, err := cache.db.SelectStorageNodes(ctx, reputableNodeCount, &NodeCriteria{
-		FreeBandwidth: req.FreeBandwidth,
-		FreeDisk:      req.FreeDisk,
-
-		AuditCount:         auditCount,
-		AuditSuccessRatio:  preferences.AuditSuccessRatio,
-		UptimeCount:        preferences.UptimeCount,
-		UptimeSuccessRatio: preferences.UptimeRatio,
-
-		Excluded: req.ExcludedNodes,
-	})
+	newNodes, err := cache.db.SelectNewStorageNodes(ctx, newNodeCount, newCriteria)
 	if err != nil {
 		return nil, err
 	}
 
-	newNodeCount := int64(float64(reputableNodeCount) * preferences.NewNodePercentage)
-	newNodes, err := cache.db.SelectNewStorageNodes(ctx, int(newNodeCount), &NewNodeCriteria{
-		FreeBandwidth: req.FreeBandwidth,
-		FreeDisk:      req.FreeDisk,
-
-		AuditThreshold: preferences.NewNodeAuditThreshold,
+	if preferences.DistinctIP {
+		// the reputable nodes mustn't share the subnets of the new nodes
+		excluded := append([]storj.NodeID{}, req.ExcludedNodes...)
+		for _, node := range newNodes {
+			excluded = append(excluded, node.Id)
+		}
+		criteria.Excluded = excluded
+	}
 
-		Excluded: req.ExcludedNodes,
-	})
+	// the reputable nodes make up for the missing new nodes
+	reputableNodeCount := totalCount - len(newNodes)
+	reputableNodes, err := cache.db.SelectStorageNodes(ctx, reputableNodeCount, criteria)
 	if err != nil {
 		return nil, err
 	}
@@ -248,13 +301,61 @@ func (cache *Cache) FindStorageNodesWithPreferences(ctx context.Context, req Fin
 	nodes = append(nodes, newNodes...)
 	nodes = append(nodes, reputableNodes...)
 
-	if len(reputableNodes) < reputableNodeCount {
-		return nodes, ErrNotEnoughNodes.New("requested %d found %d", reputableNodeCount, len(reputableNodes))
+	if len(nodes) < totalCount {
+		return nodes, ErrNotEnoughNodes.New("requested %d found %d", totalCount, len(nodes))
 	}
 
 	return nodes, nil
 }
 
+// selectionCounts returns how many nodes are selected for the request and
+// how many of them may be new nodes, the others are reputable nodes.
+func selectionCounts(req FindStorageNodesRequest, preferences *NodeSelectionConfig) (total, new int) {
+	total = req.MinimumRequiredNodes
+	if total <= 0 {
+		total = req.RequestedCount
+	}
+	return total, int(float64(total) * preferences.NewNodePercentage)
+}
+
+// selectionCriteria returns the criteria of the reputable and the new nodes selected for the request.
+func selectionCriteria(req FindStorageNodesRequest, preferences *NodeSelectionConfig) (*NodeCriteria, *NewNodeCriteria, error) {
+	minimumVersion, err := preferences.minimumVersion()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	criteria := &NodeCriteria{
+		FreeBandwidth: req.FreeBandwidth,
+		FreeDisk:      req.FreeDisk,
+
+		AuditCount:         preferences.vettingAuditCount(),
+		AuditSuccessRatio:  preferences.AuditSuccessRatio,
+		UptimeCount:        preferences.vettingUptimeCount(),
+		UptimeSuccessRatio: preferences.UptimeRatio,
+
+		MinimumVersion: minimumVersion,
+
+		Excluded:   req.ExcludedNodes,
+		DistinctIP: preferences.DistinctIP,
+	}
+
+	newCriteria := &NewNodeCriteria{
+		FreeBandwidth: req.FreeBandwidth,
+		FreeDisk:      req.FreeDisk,
+
+		AuditThreshold:  preferences.vettingAuditCount(),
+		UptimeThreshold: preferences.vettingUptimeCount(),
+
+		MinimumVersion: minimumVersion,
+
+		Excluded:   req.ExcludedNodes,
+		DistinctIP: preferences.DistinctIP,
+	}
+
+	return criteria, newCriteria, nil
+}
+
 // GetAll looks up the provided ids from the overlay cache
 func (cache *Cache) GetAll(ctx context.Context, ids storj.NodeIDList) (_ []*pb.Node, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -294,7 +395,36 @@ func (cache *Cache) Put(ctx context.Context, nodeID storj.NodeID, value pb.Node)
 		UptimeCount:        stats.UptimeCount,
 	}
 
-	return cache.db.Update(ctx, &value)
+	err = cache.db.Update(ctx, &value)
+	if err != nil {
+		return err
+	}
+
+	// NB: the network of a node with a literal IP is recorded right away,
+	// the host names are resolved off the hot path by UpdateNetwork
+	address := value.GetAddress().GetAddress()
+	if host, _, err := net.SplitHostPort(address); err == nil && net.ParseIP(host) != nil {
+		cache.UpdateNetwork(ctx, nodeID, address)
+	}
+	return nil
+}
+
+// UpdateNetwork resolves the address of the node and records its IP and
+// network, which are only used for selecting nodes in distinct subnets. A node
+// whose address can't be resolved keeps its last network.
+func (cache *Cache) UpdateNetwork(ctx context.Context, nodeID storj.NodeID, address string) {
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	lastIP, lastNet, err := resolveNetwork(ctx, address, cache.preferences)
+	if err != nil {
+		cache.log.Debug("unable to resolve the address of the node", zap.Stringer("node", nodeID), zap.Error(err))
+		return
+	}
+	err = cache.db.UpdateLastIP(ctx, nodeID, lastIP, lastNet)
+	if err != nil {
+		cache.log.Error("unable to record the network of the node", zap.Stringer("node", nodeID), zap.Error(err))
+	}
 }
 
 // Delete will remove the node from the cache. Used when a node hard disconnects or fails
@@ -320,7 +450,18 @@ func (cache *Cache) GetStats(ctx context.Context, nodeID storj.NodeID) (stats *N
 	return cache.db.GetStats(ctx, nodeID)
 }
 
-// FindInvalidNodes finds a subset of storagenodes that have stats below provided reputation requirements.
+// IsVetted returns whether the node has been audited and checked for uptime often enough to be selected as a reputable node.
+func (cache *Cache) IsVetted(ctx context.Context, nodeID storj.NodeID) (_ bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	stats, err := cache.db.GetStats(ctx, nodeID)
+	if err != nil {
+		return false, err
+	}
+	return stats.VettedAt != nil || cache.preferences.vetted(stats), nil
+}
+
+// FindInvalidNodes finds a subset of storagenodes that have stats below provided reputation requirements or that were disqualified.
 func (cache *Cache) FindInvalidNodes(ctx context.Context, nodeIDs storj.NodeIDList, maxStats *NodeStats) (invalid storj.NodeIDList, err error) {
 	defer mon.Task()(&ctx)(&err)
 	return cache.db.FindInvalidNodes(ctx, nodeIDs, maxStats)
@@ -329,7 +470,17 @@ func (cache *Cache) FindInvalidNodes(ctx context.Context, nodeIDs storj.NodeIDLi
 // UpdateStats all parts of single storagenode's stats.
 func (cache *Cache) UpdateStats(ctx context.Context, request *UpdateRequest) (stats *NodeStats, err error) {
 	defer mon.Task()(&ctx)(&err)
-	return cache.db.UpdateStats(ctx, request)
+
+	request.AuditReputation = cache.reputation.Audit()
+	request.UptimeReputation = cache.reputation.Uptime()
+
+	stats, err = cache.db.UpdateStats(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	cache.checkDisqualified(stats)
+	cache.checkVetted(ctx, stats)
+	return stats, nil
 }
 
 // UpdateOperator updates the email and wallet for a given node ID for satellite payments.
@@ -338,10 +489,76 @@ func (cache *Cache) UpdateOperator(ctx context.Context, node storj.NodeID, updat
 	return cache.db.UpdateOperator(ctx, node, updatedOperator)
 }
 
+// UpdateVersion updates the software version a node reported.
+func (cache *Cache) UpdateVersion(ctx context.Context, nodeID storj.NodeID, nodeVersion version.Info) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return cache.db.UpdateVersion(ctx, nodeID, nodeVersion)
+}
+
+// FindOutdatedNodes finds a subset of storagenodes that run a version below the configured minimum.
+func (cache *Cache) FindOutdatedNodes(ctx context.Context, nodeIDs storj.NodeIDList) (outdated storj.NodeIDList, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	minimum, err := cache.preferences.minimumVersion()
+	if err != nil {
+		return nil, err
+	}
+	if minimum.IsZero() {
+		return nil, nil
+	}
+
+	return cache.db.FindOutdatedNodes(ctx, nodeIDs, minimum)
+}
+
 // UpdateUptime updates a single storagenode's uptime stats.
 func (cache *Cache) UpdateUptime(ctx context.Context, nodeID storj.NodeID, isUp bool) (stats *NodeStats, err error) {
 	defer mon.Task()(&ctx)(&err)
-	return cache.db.UpdateUptime(ctx, nodeID, isUp)
+
+	stats, err = cache.db.UpdateUptime(ctx, nodeID, isUp, cache.reputation.Uptime())
+	if err != nil {
+		return nil, err
+	}
+	cache.checkDisqualified(stats)
+	cache.checkVetted(ctx, stats)
+	return stats, nil
+}
+
+// Reliable returns the nodes that weren't disqualified and whose last contact didn't fail.
+func (cache *Cache) Reliable(ctx context.Context) (_ storj.NodeIDList, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return cache.db.Reliable(ctx)
+}
+
+// checkDisqualified invalidates the reliability cache when the stats of one
+// of its reliable nodes show that it was disqualified.
+func (cache *Cache) checkDisqualified(stats *NodeStats) {
+	if cache.reliability != nil && stats != nil && stats.Disqualified != nil {
+		cache.reliability.nodeDisqualified(stats.NodeID)
+	}
+}
+
+// checkVetted records when the stats show that the node passed vetting for
+// the first time. The stats are already saved at this point, so a failure
+// is only logged: the vetting is recorded again with the next update.
+func (cache *Cache) checkVetted(ctx context.Context, stats *NodeStats) {
+	if stats == nil || stats.VettedAt != nil || !cache.preferences.vetted(stats) {
+		return
+	}
+
+	vettedAt := time.Now()
+	if err := cache.db.UpdateVetted(ctx, stats.NodeID, vettedAt); err != nil {
+		cache.log.Error("unable to record that the node was vetted", zap.Stringer("node", stats.NodeID), zap.Error(err))
+		mon.Meter("nodes_vetted_failed").Mark(1)
+		return
+	}
+	stats.VettedAt = &vettedAt
+	mon.Meter("nodes_vetted").Mark(1)
+}
+
+// ReputationHistory returns the daily reputation scores of a node since the given time.
+func (cache *Cache) ReputationHistory(ctx context.Context, nodeID storj.NodeID, since time.Time) (_ []ReputationScore, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return cache.db.ReputationHistory(ctx, nodeID, since)
 }
 
 // ConnFailure implements the Transport Observer `ConnFailure` function
@@ -352,7 +569,7 @@ func (cache *Cache) ConnFailure(ctx context.Context, node *pb.Node, failureError
 	// TODO: Kademlia paper specifies 5 unsuccessful PINGs before removing the node
 	// from our routing table, but this is the cache so maybe we want to treat
 	// it differently.
-	_, err = cache.db.UpdateUptime(ctx, node.Id, false)
+	_, err = cache.UpdateUptime(ctx, node.Id, false)
 	if err != nil {
 		zap.L().Debug("error updating uptime for node", zap.Error(err))
 	}
@@ -367,7 +584,7 @@ func (cache *Cache) ConnSuccess(ctx context.Context, node *pb.Node) {
 	if err != nil {
 		zap.L().Debug("error updating uptime for node", zap.Error(err))
 	}
-	_, err = cache.db.UpdateUptime(ctx, node.Id, true)
+	_, err = cache.UpdateUptime(ctx, node.Id, true)
 	if err != nil {
 		zap.L().Debug("error updating node connection info", zap.Error(err))
 	}
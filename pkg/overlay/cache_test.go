@@ -40,7 +40,7 @@ func testCache(ctx context.Context, t *testing.T, store overlay.DB) {
 	_, _ = rand.Read(valid2ID[:])
 	_, _ = rand.Read(missingID[:])
 
-	cache := overlay.NewCache(zaptest.NewLogger(t), store, overlay.NodeSelectionConfig{})
+	cache := overlay.NewCache(zaptest.NewLogger(t), store, overlay.NodeSelectionConfig{}, overlay.ReputationConfig{})
 
 	{ // Put
 		err := cache.Put(ctx, valid1ID, pb.Node{Id: valid1ID})
@@ -174,7 +174,7 @@ func testRandomizedSelection(t *testing.T, reputable bool) {
 				Reputation:   &pb.NodeStats{},
 			})
 			require.NoError(t, err)
-			_, err = cache.UpdateUptime(ctx, newID, true)
+			_, err = cache.UpdateUptime(ctx, newID, true, overlay.ReputationUpdate{})
 			require.NoError(t, err)
 			allIDs[i] = newID
 			nodeCounts[newID] = 0
@@ -5,10 +5,12 @@ package overlay
 
 import (
 	"strings"
+	"time"
 
 	"github.com/zeebo/errs"
 	monkit "gopkg.in/spacemonkeygo/monkit.v2"
 
+	"storj.io/storj/internal/version"
 	"storj.io/storj/pkg/storj"
 )
 
@@ -21,7 +23,8 @@ var (
 // Config is a configuration struct for everything you need to start the
 // Overlay cache responsibility.
 type Config struct {
-	Node NodeSelectionConfig
+	Node       NodeSelectionConfig
+	Reputation ReputationConfig
 }
 
 // LookupConfig is a configuration struct for querying the overlay cache with one or more node IDs
@@ -34,12 +37,86 @@ type LookupConfig struct {
 // values for nodes to select
 type NodeSelectionConfig struct {
 	UptimeRatio       float64 `help:"a node's ratio of being up/online vs. down/offline" default:"0"`
-	UptimeCount       int64   `help:"the number of times a node's uptime has been checked" default:"0"`
+	UptimeCount       int64   `help:"the number of uptime checks a node must have to be vetted and selected as a reputable node" default:"0"`
 	AuditSuccessRatio float64 `help:"a node's ratio of successful audits" default:"0"`
-	AuditCount        int64   `help:"the number of times a node has been audited" default:"0"`
+	AuditCount        int64   `help:"the number of audits a node must have to be vetted and selected as a reputable node" default:"0"`
 
 	NewNodeAuditThreshold int64   `help:"the number of audits a node must have to not be considered a New Node" default:"0"`
-	NewNodePercentage     float64 `help:"the percentage of new nodes allowed per request" default:"0.05"` // TODO: fix, this is not percentage, it's ratio
+	NewNodePercentage     float64 `help:"the percentage of unvetted new nodes allowed per request" default:"0.05"` // TODO: fix, this is not percentage, it's ratio
+
+	MinimumVersion string `help:"the minimum node software version for node selection and audits, empty disables the check" default:""`
+
+	SelectionCacheStaleness time.Duration `help:"how stale the nodes cached for selection may get before they are reloaded, 0 disables the selection cache" default:"3m0s"`
+
+	DistinctIP     bool `help:"select the nodes of a segment from distinct subnets, disable it when all nodes share an IP like in test networks" default:"true"`
+	SubnetMaskIPv4 int  `help:"the prefix length of the subnets of IPv4 nodes for distinct IP selection" default:"24"`
+	SubnetMaskIPv6 int  `help:"the prefix length of the subnets of IPv6 nodes for distinct IP selection" default:"64"`
+}
+
+// minimumVersion parses the configured minimum version, returning a zero version when unset
+func (config NodeSelectionConfig) minimumVersion() (version.SemVer, error) {
+	if config.MinimumVersion == "" {
+		return version.SemVer{}, nil
+	}
+	minimum, err := version.NewSemVer(config.MinimumVersion)
+	return minimum, Error.Wrap(err)
+}
+
+// vettingAuditCount returns the number of audits a node needs before it is selected as a reputable node
+func (config NodeSelectionConfig) vettingAuditCount() int64 {
+	if config.AuditCount < config.NewNodeAuditThreshold {
+		return config.NewNodeAuditThreshold
+	}
+	return config.AuditCount
+}
+
+// vettingUptimeCount returns the number of uptime checks a node needs before it is selected as a reputable node
+func (config NodeSelectionConfig) vettingUptimeCount() int64 {
+	return config.UptimeCount
+}
+
+// vetted returns whether the stats meet the vetting requirements
+func (config NodeSelectionConfig) vetted(stats *NodeStats) bool {
+	return stats.AuditCount >= config.vettingAuditCount() && stats.UptimeCount >= config.vettingUptimeCount()
+}
+
+// ReputationConfig configures the alpha/beta reputation of the nodes.
+//
+// Every audit and uptime check updates alpha and beta of the node as
+// alpha = lambda*alpha + weight*(1+v)/2 and beta = lambda*beta + weight*(1-v)/2,
+// where v is 1 for a success and -1 for a failure, and the score is
+// alpha/(alpha+beta). A lambda below 1 forgets old results, so that a node
+// can't live off the reputation it earned long ago.
+type ReputationConfig struct {
+	AuditAlpha0 float64 `help:"the initial audit alpha of a node, which is how many successful audits it is trusted with before it is audited" default:"20"`
+	AuditLambda float64 `help:"the forgetting factor of the audit reputation" default:"0.95"`
+	AuditWeight float64 `help:"the weight of a single audit in the audit reputation" default:"1"`
+	AuditDQ     float64 `help:"the audit score below which a node is disqualified, 0 disables disqualification" default:"0.6"`
+
+	UptimeAlpha0 float64 `help:"the initial uptime alpha of a node, which is how many successful uptime checks it is trusted with before it is checked" default:"100"`
+	UptimeLambda float64 `help:"the forgetting factor of the uptime reputation" default:"0.99"`
+	UptimeWeight float64 `help:"the weight of a single uptime check in the uptime reputation" default:"1"`
+	UptimeDQ     float64 `help:"the uptime score below which a node is disqualified, 0 disables disqualification" default:"0.6"`
+}
+
+// Audit returns the parameters for updating the audit reputation.
+func (config ReputationConfig) Audit() ReputationUpdate {
+	return ReputationUpdate{
+		Alpha0: config.AuditAlpha0,
+		Lambda: config.AuditLambda,
+		Weight: config.AuditWeight,
+		DQ:     config.AuditDQ,
+	}
+}
+
+// Uptime returns the parameters for updating the uptime reputation.
+func (config ReputationConfig) Uptime() ReputationUpdate {
+	return ReputationUpdate{
+		Alpha0: config.UptimeAlpha0,
+		Lambda: config.UptimeLambda,
+		Weight: config.UptimeWeight,
+		DQ:     config.UptimeDQ,
+	}
 }
 
 // ParseIDs converts the base58check encoded node ID strings from the config into node IDs
@@ -5,7 +5,9 @@ package overlay
 
 import (
 	"context"
+	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	"github.com/zeebo/errs"
 
 	"storj.io/storj/pkg/pb"
@@ -69,3 +71,45 @@ func (srv *Inspector) CreateStats(ctx context.Context, req *pb.CreateStatsReques
 
 	return &pb.CreateStatsResponse{}, nil
 }
+
+// ReputationHistory returns the reputation of a node and its daily scores
+func (srv *Inspector) ReputationHistory(ctx context.Context, req *pb.ReputationHistoryRequest) (*pb.ReputationHistoryResponse, error) {
+	stats, err := srv.cache.GetStats(ctx, req.NodeId)
+	if err != nil {
+		return nil, err
+	}
+
+	days := req.Days
+	if days <= 0 {
+		days = 30
+	}
+	history, err := srv.cache.ReputationHistory(ctx, req.NodeId, time.Now().AddDate(0, 0, -int(days)))
+	if err != nil {
+		return nil, err
+	}
+
+	response := &pb.ReputationHistoryResponse{
+		AuditAlpha:  stats.AuditReputation.Alpha,
+		AuditBeta:   stats.AuditReputation.Beta,
+		UptimeAlpha: stats.UptimeReputation.Alpha,
+		UptimeBeta:  stats.UptimeReputation.Beta,
+	}
+	if stats.Disqualified != nil {
+		response.Disqualified, err = ptypes.TimestampProto(*stats.Disqualified)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, score := range history {
+		intervalStart, err := ptypes.TimestampProto(score.IntervalStart)
+		if err != nil {
+			return nil, err
+		}
+		response.Scores = append(response.Scores, &pb.ReputationScore{
+			IntervalStart: intervalStart,
+			AuditScore:    score.AuditScore,
+			UptimeScore:   score.UptimeScore,
+		})
+	}
+	return response, nil
+}
@@ -0,0 +1,43 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package overlay
+
+import (
+	"context"
+	"net"
+)
+
+// resolveNetwork resolves the host of address and returns its IP and the
+// subnet of the IP, masked by the prefix lengths of the preferences.
+func resolveNetwork(ctx context.Context, address string, preferences NodeSelectionConfig) (lastIP, lastNet string, err error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", "", Error.Wrap(err)
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return "", "", Error.Wrap(err)
+		}
+		if len(addrs) == 0 {
+			return "", "", Error.New("no IP found for %q", host)
+		}
+		ip = addrs[0].IP
+	}
+
+	var mask net.IPMask
+	if ipv4 := ip.To4(); ipv4 != nil {
+		ip = ipv4
+		mask = net.CIDRMask(preferences.SubnetMaskIPv4, 8*net.IPv4len)
+	} else {
+		mask = net.CIDRMask(preferences.SubnetMaskIPv6, 8*net.IPv6len)
+	}
+	if mask == nil {
+		return "", "", Error.New("invalid subnet mask for %s", ip)
+	}
+
+	return ip.String(), ip.Mask(mask).String(), nil
+}
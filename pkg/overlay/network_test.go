@@ -0,0 +1,152 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package overlay_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testrand"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/satellitedb/satellitedbtest"
+)
+
+func TestDistinctIPSelection(t *testing.T) {
+	satellitedbtest.Run(t, func(t *testing.T, db satellite.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		rng := testrand.New(t)
+		preferences := overlay.NodeSelectionConfig{
+			DistinctIP:     true,
+			SubnetMaskIPv4: 24,
+			SubnetMaskIPv6: 64,
+		}
+		cache := overlay.NewCache(zaptest.NewLogger(t), db.OverlayCache(), preferences, overlay.ReputationConfig{})
+
+		for _, address := range []string{"10.0.1.1:7777", "10.0.1.2:7777", "10.0.2.1:7777", "10.0.2.2:7777", "[fd00::1]:7777"} {
+			nodeID := rng.NodeID()
+			require.NoError(t, cache.Put(ctx, nodeID, pb.Node{
+				Id:           nodeID,
+				Type:         pb.NodeType_STORAGE,
+				Address:      &pb.NodeAddress{Address: address},
+				Restrictions: &pb.NodeRestrictions{},
+			}))
+			_, err := cache.UpdateUptime(ctx, nodeID, true)
+			require.NoError(t, err)
+		}
+
+		nets, err := db.OverlayCache().LastNets(ctx)
+		require.NoError(t, err)
+		require.Len(t, nets, 5)
+		assert.ElementsMatch(t, []string{"10.0.1.0", "10.0.2.0", "fd00::"}, values(nets))
+
+		{ // a host name is only resolved by UpdateNetwork
+			nodeID := rng.NodeID()
+			require.NoError(t, cache.Put(ctx, nodeID, pb.Node{
+				Id:           nodeID,
+				Type:         pb.NodeType_STORAGE,
+				Address:      &pb.NodeAddress{Address: "localhost:7777"},
+				Restrictions: &pb.NodeRestrictions{},
+			}))
+			resolved, err := db.OverlayCache().LastNets(ctx)
+			require.NoError(t, err)
+			assert.NotContains(t, resolved, nodeID)
+
+			cache.UpdateNetwork(ctx, nodeID, "localhost:7777")
+			resolved, err = db.OverlayCache().LastNets(ctx)
+			require.NoError(t, err)
+			assert.Contains(t, resolved, nodeID)
+		}
+
+		// a node whose network isn't known is in a subnet of its own
+		unknown := rng.NodeID()
+		require.NoError(t, db.OverlayCache().Update(ctx, &pb.Node{
+			Id:           unknown,
+			Type:         pb.NodeType_STORAGE,
+			Restrictions: &pb.NodeRestrictions{},
+		}))
+		_, err = cache.UpdateUptime(ctx, unknown, true)
+		require.NoError(t, err)
+
+		selectionCache := overlay.NewSelectionCache(db.OverlayCache(), preferences, time.Hour)
+		for _, find := range []struct {
+			name  string
+			nodes func(ctx context.Context, req overlay.FindStorageNodesRequest) ([]*pb.Node, error)
+		}{
+			{"database", cache.FindStorageNodes},
+			{"cache", selectionCache.SelectNodes},
+		} {
+			distinct := func(nodes []*pb.Node) {
+				used := map[string]bool{}
+				for _, node := range nodes {
+					net, ok := nets[node.Id]
+					if !ok {
+						assert.Equal(t, unknown, node.Id, find.name)
+						continue
+					}
+					assert.False(t, used[net], find.name)
+					used[net] = true
+				}
+			}
+
+			// the nodes are in 3 known subnets and an unknown one
+			nodes, err := find.nodes(ctx, overlay.FindStorageNodesRequest{RequestedCount: 4})
+			require.NoError(t, err, find.name)
+			assert.Len(t, nodes, 4, find.name)
+			distinct(nodes)
+
+			_, err = find.nodes(ctx, overlay.FindStorageNodesRequest{RequestedCount: 5})
+			assert.True(t, overlay.ErrNotEnoughNodes.Has(err), find.name)
+
+			// every node of a subnet can be selected
+			seen := map[storj.NodeID]bool{}
+			for i := 0; i < 100 && len(seen) < 6; i++ {
+				nodes, err := find.nodes(ctx, overlay.FindStorageNodesRequest{RequestedCount: 4})
+				require.NoError(t, err, find.name)
+				for _, node := range nodes {
+					seen[node.Id] = true
+				}
+			}
+			assert.Len(t, seen, 6, find.name)
+
+			var excluded storj.NodeIDList
+			for nodeID, net := range nets {
+				if net == "10.0.1.0" {
+					excluded = append(excluded, nodeID)
+					break
+				}
+			}
+			nodes, err = find.nodes(ctx, overlay.FindStorageNodesRequest{RequestedCount: 3, ExcludedNodes: excluded})
+			require.NoError(t, err, find.name)
+			assert.Len(t, nodes, 3, find.name)
+			distinct(nodes)
+			for _, node := range nodes {
+				assert.NotEqual(t, "10.0.1.0", nets[node.Id], find.name)
+			}
+		}
+	})
+}
+
+// values returns the distinct values of nets.
+func values(nets map[storj.NodeID]string) []string {
+	seen := map[string]bool{}
+	var list []string
+	for _, net := range nets {
+		if !seen[net] {
+			seen[net] = true
+			list = append(list, net)
+		}
+	}
+	return list
+}
@@ -0,0 +1,159 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package overlay
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+)
+
+// ReliabilityCache caches the reliable nodes of the overlay, so checking the
+// pieces of a segment doesn't query the overlay. The cached nodes are reloaded
+// when they are older than the staleness bound or were invalidated.
+type ReliabilityCache struct {
+	overlay   *Cache
+	staleness time.Duration
+
+	// mu serializes the refreshes and invalidations
+	mu    sync.Mutex
+	state atomic.Value // *reliabilityState
+}
+
+// reliabilityState is a snapshot of the reliable nodes.
+type reliabilityState struct {
+	reliable    map[storj.NodeID]struct{}
+	created     time.Time
+	invalidated bool
+}
+
+// NewReliabilityCache creates a reliability cache of the nodes of overlay,
+// the overlay invalidates it when it disqualifies one of the reliable nodes.
+func NewReliabilityCache(overlay *Cache, staleness time.Duration) *ReliabilityCache {
+	cache := &ReliabilityCache{
+		overlay:   overlay,
+		staleness: staleness,
+	}
+	overlay.reliability = cache
+	return cache
+}
+
+// LastUpdate returns when the reliable nodes were loaded, the zero time
+// before the first load.
+func (cache *ReliabilityCache) LastUpdate() time.Time {
+	if state, ok := cache.state.Load().(*reliabilityState); ok {
+		return state.created
+	}
+	return time.Time{}
+}
+
+// Invalidate makes the next lookup reload the reliable nodes.
+func (cache *ReliabilityCache) Invalidate() {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	state, ok := cache.state.Load().(*reliabilityState)
+	if !ok || state.invalidated {
+		return
+	}
+	cache.state.Store(&reliabilityState{
+		reliable:    state.reliable,
+		created:     state.created,
+		invalidated: true,
+	})
+}
+
+// NodeDisqualificationChanged invalidates the cache when a node was
+// disqualified or reinstated.
+func (cache *ReliabilityCache) NodeDisqualificationChanged(nodeID storj.NodeID) {
+	cache.Invalidate()
+}
+
+// nodeDisqualified invalidates the cache when the node is one of the
+// reliable nodes.
+func (cache *ReliabilityCache) nodeDisqualified(nodeID storj.NodeID) {
+	state, ok := cache.state.Load().(*reliabilityState)
+	if !ok {
+		return
+	}
+	if _, reliable := state.reliable[nodeID]; reliable {
+		cache.Invalidate()
+	}
+}
+
+// Refresh reloads the reliable nodes.
+func (cache *ReliabilityCache) Refresh(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	_, err = cache.refreshLocked(ctx)
+	return err
+}
+
+// MissingPieces returns the piece numbers of the pieces stored on nodes that
+// aren't reliable.
+func (cache *ReliabilityCache) MissingPieces(ctx context.Context, pieces []*pb.RemotePiece) (_ []int32, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	state, err := cache.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []int32
+	for _, piece := range pieces {
+		if _, ok := state.reliable[piece.NodeId]; !ok {
+			missing = append(missing, piece.GetPieceNum())
+		}
+	}
+	return missing, nil
+}
+
+// load returns the reliable nodes, reloading them when they are stale.
+func (cache *ReliabilityCache) load(ctx context.Context) (*reliabilityState, error) {
+	if state, ok := cache.state.Load().(*reliabilityState); ok && cache.fresh(state) {
+		return state, nil
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	// another lookup may have reloaded them while waiting for the lock
+	if state, ok := cache.state.Load().(*reliabilityState); ok && cache.fresh(state) {
+		return state, nil
+	}
+	return cache.refreshLocked(ctx)
+}
+
+// fresh returns whether the state may still be used.
+func (cache *ReliabilityCache) fresh(state *reliabilityState) bool {
+	return !state.invalidated && time.Since(state.created) < cache.staleness
+}
+
+// refreshLocked reloads the reliable nodes, mu must be held.
+func (cache *ReliabilityCache) refreshLocked(ctx context.Context) (*reliabilityState, error) {
+	created := time.Now()
+	nodes, err := cache.overlay.Reliable(ctx)
+	if err != nil {
+		return nil, OverlayError.Wrap(err)
+	}
+
+	state := &reliabilityState{
+		reliable: make(map[storj.NodeID]struct{}, len(nodes)),
+		created:  created,
+	}
+	for _, nodeID := range nodes {
+		state.reliable[nodeID] = struct{}{}
+	}
+	cache.state.Store(state)
+
+	mon.IntVal("reliable_nodes").Observe(int64(len(nodes)))
+	return state, nil
+}
@@ -0,0 +1,97 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package overlay_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testrand"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/disqualification"
+	"storj.io/storj/satellite/satellitedb/satellitedbtest"
+)
+
+func TestReliabilityCache(t *testing.T) {
+	satellitedbtest.Run(t, func(t *testing.T, db satellite.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		cache := overlay.NewCache(zaptest.NewLogger(t), db.OverlayCache(), overlay.NodeSelectionConfig{}, overlay.ReputationConfig{
+			AuditAlpha0: 1,
+			AuditLambda: 1,
+			AuditWeight: 1,
+			AuditDQ:     0.6,
+		})
+		reliability := overlay.NewReliabilityCache(cache, time.Hour)
+		rng := testrand.New(t)
+
+		var pieces []*pb.RemotePiece
+		for i := 0; i < 4; i++ {
+			nodeID := rng.NodeID()
+			require.NoError(t, cache.Put(ctx, nodeID, pb.Node{Id: nodeID}))
+			pieces = append(pieces, &pb.RemotePiece{PieceNum: int32(i), NodeId: nodeID})
+		}
+		// a node the overlay doesn't know
+		pieces = append(pieces, &pb.RemotePiece{PieceNum: 4, NodeId: rng.NodeID()})
+
+		assert.True(t, reliability.LastUpdate().IsZero())
+		missing, err := reliability.MissingPieces(ctx, pieces)
+		require.NoError(t, err)
+		assert.Equal(t, []int32{4}, missing)
+		lastUpdate := reliability.LastUpdate()
+		assert.False(t, lastUpdate.IsZero())
+
+		{ // a failed contact is only seen after a refresh
+			_, err := cache.UpdateUptime(ctx, pieces[0].NodeId, false)
+			require.NoError(t, err)
+
+			missing, err = reliability.MissingPieces(ctx, pieces)
+			require.NoError(t, err)
+			assert.Equal(t, []int32{4}, missing)
+			assert.Equal(t, lastUpdate, reliability.LastUpdate())
+
+			require.NoError(t, reliability.Refresh(ctx))
+			missing, err = reliability.MissingPieces(ctx, pieces)
+			require.NoError(t, err)
+			assert.Equal(t, []int32{0, 4}, missing)
+		}
+
+		{ // the overlay invalidates the cache when it disqualifies a reliable node
+			stats, err := cache.UpdateStats(ctx, &overlay.UpdateRequest{
+				NodeID:       pieces[1].NodeId,
+				AuditSuccess: false,
+				IsUp:         true,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, stats.Disqualified)
+
+			missing, err = reliability.MissingPieces(ctx, pieces)
+			require.NoError(t, err)
+			assert.Equal(t, []int32{0, 1, 4}, missing)
+		}
+
+		{ // disqualification events invalidate the cache
+			service := disqualification.NewService(zaptest.NewLogger(t), db.Disqualification(), disqualification.Config{}, 0, reliability)
+
+			_, err := db.Disqualification().Disqualify(ctx, pieces[2].NodeId, disqualification.ReasonOffline, "")
+			require.NoError(t, err)
+			missing, err = reliability.MissingPieces(ctx, pieces)
+			require.NoError(t, err)
+			assert.Equal(t, []int32{0, 1, 4}, missing)
+
+			require.NoError(t, service.Reinstate(ctx, pieces[1].NodeId, "reinstated"))
+			missing, err = reliability.MissingPieces(ctx, pieces)
+			require.NoError(t, err)
+			assert.Equal(t, []int32{0, 2, 4}, missing)
+		}
+	})
+}
@@ -0,0 +1,130 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package overlay
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/identity"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+)
+
+// Reputation is the alpha/beta reputation of a node for audits or uptime.
+type Reputation struct {
+	Alpha float64
+	Beta  float64
+}
+
+// Score returns the probability that the next result of the node is a
+// success, a node without any results has the score 1.
+func (reputation Reputation) Score() float64 {
+	if reputation.Alpha+reputation.Beta <= 0 {
+		return 1
+	}
+	return reputation.Alpha / (reputation.Alpha + reputation.Beta)
+}
+
+// ReputationUpdate contains the parameters for updating a reputation.
+type ReputationUpdate struct {
+	Alpha0 float64
+	Lambda float64
+	Weight float64
+	DQ     float64
+}
+
+// Apply returns the reputation after the result of an audit or uptime check.
+// The zero reputation, of a node without any results, starts at Alpha0.
+func (update ReputationUpdate) Apply(reputation Reputation, success bool) Reputation {
+	if reputation.Alpha+reputation.Beta <= 0 {
+		reputation = Reputation{Alpha: update.Alpha0}
+	}
+
+	v := -1.0
+	if success {
+		v = 1.0
+	}
+	return Reputation{
+		Alpha: update.Lambda*reputation.Alpha + update.Weight*(1+v)/2,
+		Beta:  update.Lambda*reputation.Beta + update.Weight*(1-v)/2,
+	}
+}
+
+// Disqualifies returns whether the reputation is low enough to disqualify the node.
+func (update ReputationUpdate) Disqualifies(reputation Reputation) bool {
+	return reputation.Score() < update.DQ
+}
+
+// ReputationScore is the daily snapshot of a node's reputation scores.
+type ReputationScore struct {
+	NodeID        storj.NodeID
+	IntervalStart time.Time
+	AuditScore    float64
+	UptimeScore   float64
+}
+
+// ReputationEndpoint serves the storage nodes their own reputation
+type ReputationEndpoint struct {
+	log   *zap.Logger
+	cache *Cache
+}
+
+// NewReputationEndpoint creates a new reputation endpoint
+func NewReputationEndpoint(log *zap.Logger, cache *Cache) *ReputationEndpoint {
+	return &ReputationEndpoint{
+		log:   log,
+		cache: cache,
+	}
+}
+
+// Stats returns the reputation of the calling storage node
+func (endpoint *ReputationEndpoint) Stats(ctx context.Context, req *pb.ReputationStatsRequest) (_ *pb.ReputationStatsResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	peer, err := identity.PeerIdentityFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	stats, err := endpoint.cache.GetStats(ctx, peer.ID)
+	if err != nil {
+		if ErrNodeNotFound.Has(err) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		endpoint.log.Error("failed to get stats", zap.Stringer("node", peer.ID), zap.Error(err))
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	response := &pb.ReputationStatsResponse{
+		AuditCount:         stats.AuditCount,
+		AuditSuccessCount:  stats.AuditSuccessCount,
+		AuditSuccessRatio:  stats.AuditSuccessRatio,
+		UptimeCount:        stats.UptimeCount,
+		UptimeSuccessCount: stats.UptimeSuccessCount,
+		UptimeRatio:        stats.UptimeRatio,
+		AuditScore:         stats.AuditReputation.Score(),
+		UptimeScore:        stats.UptimeReputation.Score(),
+		VettingAuditCount:  endpoint.cache.preferences.vettingAuditCount(),
+		VettingUptimeCount: endpoint.cache.preferences.vettingUptimeCount(),
+	}
+	if stats.Disqualified != nil {
+		response.Disqualified, err = ptypes.TimestampProto(*stats.Disqualified)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+	if stats.VettedAt != nil {
+		response.Vetted, err = ptypes.TimestampProto(*stats.VettedAt)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+	return response, nil
+}
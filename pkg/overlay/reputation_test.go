@@ -0,0 +1,122 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package overlay_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/satellitedb/satellitedbtest"
+)
+
+func TestReputationUpdate(t *testing.T) {
+	update := overlay.ReputationUpdate{Alpha0: 10, Lambda: 0.5, Weight: 2, DQ: 0.5}
+
+	reputation := update.Apply(overlay.Reputation{}, true)
+	assert.Equal(t, overlay.Reputation{Alpha: 7, Beta: 0}, reputation)
+	assert.Equal(t, 1.0, reputation.Score())
+
+	reputation = update.Apply(reputation, false)
+	assert.Equal(t, overlay.Reputation{Alpha: 3.5, Beta: 2}, reputation)
+	assert.False(t, update.Disqualifies(reputation))
+
+	reputation = update.Apply(reputation, false)
+	assert.Equal(t, overlay.Reputation{Alpha: 1.75, Beta: 3}, reputation)
+	assert.True(t, update.Disqualifies(reputation))
+}
+
+func TestReputation(t *testing.T) {
+	satellitedbtest.Run(t, func(t *testing.T, db satellite.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		config := overlay.ReputationConfig{
+			AuditAlpha0: 2, AuditLambda: 1, AuditWeight: 1, AuditDQ: 0.45,
+			UptimeAlpha0: 1, UptimeLambda: 1, UptimeWeight: 1, UptimeDQ: 0,
+		}
+		cache := overlay.NewCache(zap.NewNop(), db.OverlayCache(), overlay.NodeSelectionConfig{}, config)
+
+		good, bad := storj.NodeID{1}, storj.NodeID{2}
+		for _, id := range []storj.NodeID{good, bad} {
+			require.NoError(t, cache.Put(ctx, id, pb.Node{
+				Id:           id,
+				Type:         pb.NodeType_STORAGE,
+				Address:      &pb.NodeAddress{Address: "127.0.0.1:0"},
+				Restrictions: &pb.NodeRestrictions{},
+				Reputation:   &pb.NodeStats{},
+			}))
+		}
+
+		stats, err := cache.GetStats(ctx, good)
+		require.NoError(t, err)
+		assert.Equal(t, overlay.Reputation{}, stats.AuditReputation)
+		assert.Nil(t, stats.Disqualified)
+
+		stats, err = cache.UpdateStats(ctx, &overlay.UpdateRequest{NodeID: good, AuditSuccess: true, IsUp: true})
+		require.NoError(t, err)
+		assert.Equal(t, overlay.Reputation{Alpha: 3}, stats.AuditReputation)
+		assert.Equal(t, overlay.Reputation{Alpha: 2}, stats.UptimeReputation)
+		assert.Nil(t, stats.Disqualified)
+
+		// the uptime doesn't disqualify with a zero threshold
+		stats, err = cache.UpdateUptime(ctx, bad, false)
+		require.NoError(t, err)
+		assert.Equal(t, overlay.Reputation{Alpha: 1, Beta: 1}, stats.UptimeReputation)
+		assert.Equal(t, overlay.Reputation{}, stats.AuditReputation)
+		assert.Nil(t, stats.Disqualified)
+
+		stats, err = cache.UpdateStats(ctx, &overlay.UpdateRequest{NodeID: bad, AuditSuccess: false, IsUp: true})
+		require.NoError(t, err)
+		assert.Equal(t, overlay.Reputation{Alpha: 2, Beta: 1}, stats.AuditReputation)
+		assert.Nil(t, stats.Disqualified)
+
+		stats, err = cache.UpdateStats(ctx, &overlay.UpdateRequest{NodeID: bad, AuditSuccess: false, IsUp: true})
+		require.NoError(t, err)
+		assert.Equal(t, overlay.Reputation{Alpha: 2, Beta: 2}, stats.AuditReputation)
+		assert.Nil(t, stats.Disqualified)
+
+		stats, err = cache.UpdateStats(ctx, &overlay.UpdateRequest{NodeID: bad, AuditSuccess: false, IsUp: true})
+		require.NoError(t, err)
+		require.NotNil(t, stats.Disqualified)
+		disqualified := *stats.Disqualified
+
+		// a disqualified node stays disqualified and is not selected anymore
+		stats, err = cache.UpdateStats(ctx, &overlay.UpdateRequest{NodeID: bad, AuditSuccess: true, IsUp: true})
+		require.NoError(t, err)
+		require.NotNil(t, stats.Disqualified)
+		assert.True(t, disqualified.Equal(*stats.Disqualified))
+
+		stats, err = cache.GetStats(ctx, bad)
+		require.NoError(t, err)
+		require.NotNil(t, stats.Disqualified)
+
+		nodes, err := cache.FindStorageNodes(ctx, overlay.FindStorageNodesRequest{RequestedCount: 1})
+		require.NoError(t, err)
+		require.Len(t, nodes, 1)
+		assert.Equal(t, good, nodes[0].Id)
+
+		_, err = cache.FindStorageNodes(ctx, overlay.FindStorageNodesRequest{RequestedCount: 2})
+		assert.True(t, overlay.ErrNotEnoughNodes.Has(err))
+
+		history, err := cache.ReputationHistory(ctx, bad, time.Now().Add(-24*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		assert.Equal(t, bad, history[0].NodeID)
+		assert.InDelta(t, 3.0/6.0, history[0].AuditScore, 1e-9)
+		assert.InDelta(t, 5.0/6.0, history[0].UptimeScore, 1e-9)
+
+		history, err = cache.ReputationHistory(ctx, bad, time.Now().Add(24*time.Hour))
+		require.NoError(t, err)
+		assert.Empty(t, history)
+	})
+}
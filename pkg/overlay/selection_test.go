@@ -100,21 +100,21 @@ func TestNodeSelection(t *testing.T) {
 				RequestCount:  5,
 				ExpectedCount: 5,
 			},
-			{ // all reputable nodes except one, reputable and new nodes requested
+			{ // all reputable nodes except one, reputable nodes make up for the missing new nodes
 				Preferences: overlay.NodeSelectionConfig{
 					NewNodeAuditThreshold: 1,
 					NewNodePercentage:     1,
 				},
 				RequestCount:  5,
-				ExpectedCount: 6,
+				ExpectedCount: 5,
 			},
-			{ // 50-50 reputable and new nodes, reputable and new nodes requested (new node ratio 1.0)
+			{ // 50-50 reputable and new nodes, only new nodes requested (new node ratio 1.0)
 				Preferences: overlay.NodeSelectionConfig{
 					NewNodeAuditThreshold: 5,
 					NewNodePercentage:     1,
 				},
 				RequestCount:  2,
-				ExpectedCount: 4,
+				ExpectedCount: 2,
 			},
 			{ // 50-50 reputable and new nodes, reputable and new nodes requested (new node ratio 0.5)
 				Preferences: overlay.NodeSelectionConfig{
@@ -122,32 +122,32 @@ func TestNodeSelection(t *testing.T) {
 					NewNodePercentage:     0.5,
 				},
 				RequestCount:  4,
-				ExpectedCount: 6,
+				ExpectedCount: 4,
 			},
 			{ // all new nodes except one, reputable and new nodes requested (happy path)
 				Preferences: overlay.NodeSelectionConfig{
 					NewNodeAuditThreshold: 8,
-					NewNodePercentage:     1,
+					NewNodePercentage:     0.5,
 				},
-				RequestCount:  1,
+				RequestCount:  2,
 				ExpectedCount: 2,
 			},
 			{ // all new nodes except one, reputable and new nodes requested (not happy path)
 				Preferences: overlay.NodeSelectionConfig{
 					NewNodeAuditThreshold: 9,
-					NewNodePercentage:     1,
+					NewNodePercentage:     0.5,
 				},
-				RequestCount:   2,
+				RequestCount:   4,
 				ExpectedCount:  3,
 				ShouldFailWith: &overlay.ErrNotEnoughNodes,
 			},
 			{ // all new nodes, reputable and new nodes requested
 				Preferences: overlay.NodeSelectionConfig{
 					NewNodeAuditThreshold: 50,
-					NewNodePercentage:     1,
+					NewNodePercentage:     0.5,
 				},
 				RequestCount:   2,
-				ExpectedCount:  2,
+				ExpectedCount:  1,
 				ShouldFailWith: &overlay.ErrNotEnoughNodes,
 			},
 			{ // audit threshold edge case (1)
@@ -0,0 +1,222 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package overlay
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+)
+
+// SelectionCache caches the nodes that may be selected for new pieces, so
+// selecting the nodes of an upload doesn't query the database. The reputable
+// and the new nodes are kept in separate pools, which are reloaded when they
+// are older than the staleness bound.
+type SelectionCache struct {
+	db          DB
+	preferences NodeSelectionConfig
+	staleness   time.Duration
+
+	// mu serializes the refreshes
+	mu    sync.Mutex
+	state atomic.Value // *selectionState
+}
+
+// selectionState is a snapshot of the nodes that may be selected.
+type selectionState struct {
+	reputable []*pb.Node
+	new       []*pb.Node
+	// nets are the subnets of the nodes with a known IP, they are only
+	// loaded for selecting nodes in distinct subnets
+	nets    map[storj.NodeID]string
+	created time.Time
+}
+
+// NewSelectionCache creates a selection cache of the nodes in db that meet
+// the preferences.
+func NewSelectionCache(db DB, preferences NodeSelectionConfig, staleness time.Duration) *SelectionCache {
+	return &SelectionCache{
+		db:          db,
+		preferences: preferences,
+		staleness:   staleness,
+	}
+}
+
+// Size returns the number of cached reputable and new nodes.
+func (cache *SelectionCache) Size() (reputable, new int) {
+	if state, ok := cache.state.Load().(*selectionState); ok {
+		return len(state.reputable), len(state.new)
+	}
+	return 0, 0
+}
+
+// Refresh reloads the nodes that may be selected.
+func (cache *SelectionCache) Refresh(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	_, err = cache.refreshLocked(ctx)
+	return err
+}
+
+// SelectNodes randomly selects the reputable and new nodes for the request
+// like Cache.FindStorageNodes does. The returned nodes are shared with the
+// cache and must not be modified.
+func (cache *SelectionCache) SelectNodes(ctx context.Context, req FindStorageNodesRequest) (_ []*pb.Node, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	state, err := cache.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	totalCount, newNodeCount := selectionCounts(req, &cache.preferences)
+
+	selection := newNodeSelection(state, req)
+	newNodes := selection.selectRandom(state.new, newNodeCount)
+	// the reputable nodes make up for the missing new nodes
+	reputableNodes := selection.selectRandom(state.reputable, totalCount-len(newNodes))
+
+	nodes := []*pb.Node{}
+	nodes = append(nodes, newNodes...)
+	nodes = append(nodes, reputableNodes...)
+
+	if len(nodes) < totalCount {
+		return nodes, ErrNotEnoughNodes.New("requested %d found %d", totalCount, len(nodes))
+	}
+
+	return nodes, nil
+}
+
+// nodeSelection selects the nodes of a single request.
+type nodeSelection struct {
+	req      FindStorageNodesRequest
+	nets     map[storj.NodeID]string
+	excluded map[storj.NodeID]struct{}
+	// usedNets are the subnets of the excluded and the selected nodes, nil
+	// unless the nodes are selected in distinct subnets
+	usedNets map[string]struct{}
+}
+
+// newNodeSelection creates a selection excluding the excluded nodes of the
+// request and, when the nets are loaded, their subnets.
+func newNodeSelection(state *selectionState, req FindStorageNodesRequest) *nodeSelection {
+	selection := &nodeSelection{
+		req:      req,
+		nets:     state.nets,
+		excluded: make(map[storj.NodeID]struct{}, len(req.ExcludedNodes)),
+	}
+	if state.nets != nil {
+		selection.usedNets = make(map[string]struct{})
+	}
+	for _, nodeID := range req.ExcludedNodes {
+		selection.excluded[nodeID] = struct{}{}
+		selection.useNet(nodeID)
+	}
+	return selection
+}
+
+// useNet marks the subnet of the node as used.
+func (selection *nodeSelection) useNet(nodeID storj.NodeID) {
+	if selection.usedNets == nil {
+		return
+	}
+	if net, ok := selection.nets[nodeID]; ok {
+		selection.usedNets[net] = struct{}{}
+	}
+}
+
+// selectRandom returns up to count random nodes of the pool that aren't
+// excluded, have enough free bandwidth and disk for the request and, when
+// the nodes are selected in distinct subnets, are in an unused subnet.
+func (selection *nodeSelection) selectRandom(pool []*pb.Node, count int) []*pb.Node {
+	if count <= 0 {
+		return nil
+	}
+
+	var selected []*pb.Node
+	for _, i := range rand.Perm(len(pool)) {
+		node := pool[i]
+		if _, ok := selection.excluded[node.Id]; ok {
+			continue
+		}
+		if node.GetRestrictions().GetFreeBandwidth() < selection.req.FreeBandwidth ||
+			node.GetRestrictions().GetFreeDisk() < selection.req.FreeDisk {
+			continue
+		}
+		if selection.usedNets != nil {
+			if net, ok := selection.nets[node.Id]; ok {
+				if _, used := selection.usedNets[net]; used {
+					continue
+				}
+			}
+		}
+
+		selected = append(selected, node)
+		selection.useNet(node.Id)
+		if len(selected) >= count {
+			break
+		}
+	}
+	return selected
+}
+
+// load returns the cached nodes, reloading them when they are stale.
+func (cache *SelectionCache) load(ctx context.Context) (*selectionState, error) {
+	if state, ok := cache.state.Load().(*selectionState); ok && time.Since(state.created) < cache.staleness {
+		return state, nil
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	// another selection may have reloaded them while waiting for the lock
+	if state, ok := cache.state.Load().(*selectionState); ok && time.Since(state.created) < cache.staleness {
+		return state, nil
+	}
+	return cache.refreshLocked(ctx)
+}
+
+// refreshLocked reloads the nodes that may be selected, mu must be held.
+func (cache *SelectionCache) refreshLocked(ctx context.Context) (_ *selectionState, err error) {
+	created := time.Now()
+
+	// free bandwidth, disk and subnets depend on the request, they are
+	// checked when selecting the nodes
+	criteria, newCriteria, err := selectionCriteria(FindStorageNodesRequest{}, &cache.preferences)
+	if err != nil {
+		return nil, err
+	}
+	criteria.DistinctIP = false
+	newCriteria.DistinctIP = false
+
+	state := &selectionState{created: created}
+	state.reputable, err = cache.db.SelectStorageNodes(ctx, math.MaxInt32, criteria)
+	if err != nil {
+		return nil, OverlayError.Wrap(err)
+	}
+	state.new, err = cache.db.SelectNewStorageNodes(ctx, math.MaxInt32, newCriteria)
+	if err != nil {
+		return nil, OverlayError.Wrap(err)
+	}
+	if cache.preferences.DistinctIP {
+		state.nets, err = cache.db.LastNets(ctx)
+		if err != nil {
+			return nil, OverlayError.Wrap(err)
+		}
+	}
+	cache.state.Store(state)
+
+	mon.IntVal("selection_cache_reputable_nodes").Observe(int64(len(state.reputable)))
+	mon.IntVal("selection_cache_new_nodes").Observe(int64(len(state.new)))
+	return state, nil
+}
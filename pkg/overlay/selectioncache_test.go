@@ -0,0 +1,163 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package overlay_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testrand"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/satellitedb/satellitedbtest"
+)
+
+func TestSelectionCache(t *testing.T) {
+	satellitedbtest.Run(t, func(t *testing.T, db satellite.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		rng := testrand.New(t)
+		preferences := overlay.NodeSelectionConfig{
+			AuditCount:            1,
+			NewNodeAuditThreshold: 1,
+			NewNodePercentage:     0.5,
+		}
+		store := db.OverlayCache()
+
+		// addNode adds an online node, which is reputable when it was audited
+		addNode := func(audited bool, freeDisk int64) storj.NodeID {
+			nodeID := rng.NodeID()
+			require.NoError(t, store.Update(ctx, &pb.Node{
+				Id:           nodeID,
+				Type:         pb.NodeType_STORAGE,
+				Address:      &pb.NodeAddress{Address: "127.0.0.1:0"},
+				Restrictions: &pb.NodeRestrictions{FreeBandwidth: 1000, FreeDisk: freeDisk},
+			}))
+			if audited {
+				_, err := store.UpdateStats(ctx, &overlay.UpdateRequest{
+					NodeID:       nodeID,
+					AuditSuccess: true,
+					IsUp:         true,
+				})
+				require.NoError(t, err)
+			} else {
+				_, err := store.UpdateUptime(ctx, nodeID, true, overlay.ReputationUpdate{})
+				require.NoError(t, err)
+			}
+			return nodeID
+		}
+
+		var reputable, unvetted storj.NodeIDList
+		for i := 0; i < 4; i++ {
+			reputable = append(reputable, addNode(true, 1000))
+		}
+		for i := 0; i < 2; i++ {
+			unvetted = append(unvetted, addNode(false, 1000))
+		}
+		full := addNode(true, 10)
+
+		cache := overlay.NewSelectionCache(store, preferences, time.Hour)
+		selectNodes := func(req overlay.FindStorageNodesRequest) (storj.NodeIDList, error) {
+			nodes, err := cache.SelectNodes(ctx, req)
+			var ids storj.NodeIDList
+			for _, node := range nodes {
+				ids = append(ids, node.Id)
+			}
+			return ids, err
+		}
+
+		{ // reputable and new nodes are selected from their own pool
+			selected, err := selectNodes(overlay.FindStorageNodesRequest{RequestedCount: 4, FreeDisk: 100})
+			require.NoError(t, err)
+			require.Len(t, selected, 4)
+			assert.Subset(t, unvetted, selected[:2])
+			assert.Subset(t, reputable, selected[2:])
+
+			reputableCount, newCount := cache.Size()
+			assert.Equal(t, 5, reputableCount)
+			assert.Equal(t, 2, newCount)
+		}
+
+		{ // excluded nodes and nodes without enough free space are skipped
+			selected, err := selectNodes(overlay.FindStorageNodesRequest{
+				RequestedCount: 2,
+				FreeDisk:       100,
+				ExcludedNodes:  append(storj.NodeIDList{unvetted[0]}, reputable[1:]...),
+			})
+			require.NoError(t, err)
+			assert.Equal(t, storj.NodeIDList{unvetted[1], reputable[0]}, selected)
+		}
+
+		{ // not enough reputable nodes
+			_, err := selectNodes(overlay.FindStorageNodesRequest{RequestedCount: 7, FreeDisk: 100})
+			assert.True(t, overlay.ErrNotEnoughNodes.Has(err))
+
+			selected, err := selectNodes(overlay.FindStorageNodesRequest{RequestedCount: 7})
+			require.NoError(t, err)
+			assert.Contains(t, selected, full)
+		}
+
+		{ // new nodes are only seen after a refresh
+			added := addNode(true, 1000)
+			reputableCount, _ := cache.Size()
+			assert.Equal(t, 5, reputableCount)
+
+			require.NoError(t, cache.Refresh(ctx))
+			reputableCount, _ = cache.Size()
+			assert.Equal(t, 6, reputableCount)
+
+			selected, err := selectNodes(overlay.FindStorageNodesRequest{RequestedCount: 8})
+			require.NoError(t, err)
+			assert.Contains(t, selected, added)
+		}
+	})
+}
+
+func TestSelectionCacheConcurrent(t *testing.T) {
+	satellitedbtest.Run(t, func(t *testing.T, db satellite.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		rng := testrand.New(t)
+		cache := overlay.NewCache(zaptest.NewLogger(t), db.OverlayCache(), overlay.NodeSelectionConfig{
+			SelectionCacheStaleness: time.Nanosecond,
+		}, overlay.ReputationConfig{})
+
+		for i := 0; i < 10; i++ {
+			nodeID := rng.NodeID()
+			require.NoError(t, cache.Put(ctx, nodeID, pb.Node{
+				Id:           nodeID,
+				Type:         pb.NodeType_STORAGE,
+				Address:      &pb.NodeAddress{Address: "127.0.0.1:0"},
+				Restrictions: &pb.NodeRestrictions{},
+			}))
+			_, err := cache.UpdateUptime(ctx, nodeID, true)
+			require.NoError(t, err)
+		}
+
+		// the cache is reloaded while the nodes are selected
+		for i := 0; i < 4; i++ {
+			ctx.Go(func() error {
+				for k := 0; k < 20; k++ {
+					nodes, err := cache.FindStorageNodes(ctx, overlay.FindStorageNodesRequest{RequestedCount: 5})
+					if err != nil {
+						return err
+					}
+					if len(nodes) != 5 {
+						return overlay.ErrNotEnoughNodes.New("selected %d", len(nodes))
+					}
+				}
+				return nil
+			})
+		}
+	})
+}
@@ -222,7 +222,7 @@ func testDatabase(ctx context.Context, t *testing.T, cache overlay.DB) {
 		assert.EqualValues(t, currUptimeSuccess, stats.UptimeSuccessCount)
 		assert.EqualValues(t, uptimeRatio, stats.UptimeRatio)
 
-		stats, err = cache.UpdateUptime(ctx, nodeID, false)
+		stats, err = cache.UpdateUptime(ctx, nodeID, false, overlay.ReputationUpdate{})
 		require.NoError(t, err)
 
 		currUptimeCount++
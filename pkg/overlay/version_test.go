@@ -0,0 +1,114 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package overlay_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/version"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/satellitedb/satellitedbtest"
+)
+
+func TestMinimumVersion(t *testing.T) {
+	satellitedbtest.Run(t, func(t *testing.T, db satellite.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		versions := []string{"v0.9.9", "v0.10.0", "v0.10.1", "v1.0.0"}
+
+		var nodeIDs storj.NodeIDList
+		for i, v := range versions {
+			id := storj.NodeID{byte(i + 1)}
+			nodeIDs = append(nodeIDs, id)
+
+			err := db.OverlayCache().Update(ctx, &pb.Node{
+				Id:           id,
+				Type:         pb.NodeType_STORAGE,
+				Address:      &pb.NodeAddress{Address: "127.0.0.1:0"},
+				Restrictions: &pb.NodeRestrictions{},
+				Reputation:   &pb.NodeStats{},
+			})
+			require.NoError(t, err)
+			_, err = db.OverlayCache().UpdateUptime(ctx, id, true, overlay.ReputationUpdate{})
+			require.NoError(t, err)
+
+			semver, err := version.NewSemVer(v)
+			require.NoError(t, err)
+			err = db.OverlayCache().UpdateVersion(ctx, id, version.Info{
+				Timestamp:  time.Now(),
+				CommitHash: "hash",
+				Version:    semver,
+				Release:    true,
+			})
+			require.NoError(t, err)
+		}
+
+		// nodes that were never asked for their version are treated as v0.0.0
+		unknownID := storj.NodeID{byte(len(versions) + 1)}
+		err := db.OverlayCache().Update(ctx, &pb.Node{
+			Id:           unknownID,
+			Type:         pb.NodeType_STORAGE,
+			Address:      &pb.NodeAddress{Address: "127.0.0.1:0"},
+			Restrictions: &pb.NodeRestrictions{},
+			Reputation:   &pb.NodeStats{},
+		})
+		require.NoError(t, err)
+		_, err = db.OverlayCache().UpdateUptime(ctx, unknownID, true, overlay.ReputationUpdate{})
+		require.NoError(t, err)
+		nodeIDs = append(nodeIDs, unknownID)
+
+		{ // without a minimum every node is selected
+			cache := overlay.NewCache(zap.NewNop(), db.OverlayCache(), overlay.NodeSelectionConfig{}, overlay.ReputationConfig{})
+
+			outdated, err := cache.FindOutdatedNodes(ctx, nodeIDs)
+			require.NoError(t, err)
+			assert.Empty(t, outdated)
+
+			nodes, err := cache.FindStorageNodes(ctx, overlay.FindStorageNodesRequest{RequestedCount: len(nodeIDs)})
+			require.NoError(t, err)
+			assert.Len(t, nodes, len(nodeIDs))
+		}
+
+		{ // nodes below the minimum are neither selected nor audited
+			cache := overlay.NewCache(zap.NewNop(), db.OverlayCache(), overlay.NodeSelectionConfig{
+				MinimumVersion: "v0.10.0",
+			}, overlay.ReputationConfig{})
+
+			outdated, err := cache.FindOutdatedNodes(ctx, nodeIDs)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, storj.NodeIDList{nodeIDs[0], unknownID}, outdated)
+
+			nodes, err := cache.FindStorageNodes(ctx, overlay.FindStorageNodesRequest{RequestedCount: 3})
+			require.NoError(t, err)
+
+			var selected storj.NodeIDList
+			for _, node := range nodes {
+				selected = append(selected, node.Id)
+			}
+			assert.ElementsMatch(t, nodeIDs[1:4], selected)
+
+			_, err = cache.FindStorageNodes(ctx, overlay.FindStorageNodesRequest{RequestedCount: 4})
+			assert.True(t, overlay.ErrNotEnoughNodes.Has(err))
+		}
+
+		{ // invalid minimum versions are reported
+			cache := overlay.NewCache(zap.NewNop(), db.OverlayCache(), overlay.NodeSelectionConfig{
+				MinimumVersion: "latest",
+			}, overlay.ReputationConfig{})
+
+			_, err := cache.FindOutdatedNodes(ctx, nodeIDs)
+			assert.Error(t, err)
+		}
+	})
+}
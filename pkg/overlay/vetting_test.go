@@ -0,0 +1,112 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package overlay_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testrand"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/satellitedb/satellitedbtest"
+)
+
+func TestVetting(t *testing.T) {
+	satellitedbtest.Run(t, func(t *testing.T, db satellite.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		rng := testrand.New(t)
+		preferences := overlay.NodeSelectionConfig{
+			AuditCount:        2,
+			UptimeCount:       3,
+			NewNodePercentage: 0.5,
+		}
+		cache := overlay.NewCache(zaptest.NewLogger(t), db.OverlayCache(), preferences, overlay.ReputationConfig{})
+
+		addNode := func() storj.NodeID {
+			nodeID := rng.NodeID()
+			require.NoError(t, cache.Put(ctx, nodeID, pb.Node{
+				Id:           nodeID,
+				Type:         pb.NodeType_STORAGE,
+				Address:      &pb.NodeAddress{Address: "127.0.0.1:0"},
+				Restrictions: &pb.NodeRestrictions{},
+			}))
+			return nodeID
+		}
+		audit := func(nodeID storj.NodeID) *overlay.NodeStats {
+			stats, err := cache.UpdateStats(ctx, &overlay.UpdateRequest{
+				NodeID:       nodeID,
+				AuditSuccess: true,
+				IsUp:         true,
+			})
+			require.NoError(t, err)
+			return stats
+		}
+
+		// vetted passes both the audits and the uptime checks
+		vetted := addNode()
+		audit(vetted)
+		stats := audit(vetted)
+		assert.Nil(t, stats.VettedAt)
+		stats, err := cache.UpdateUptime(ctx, vetted, true)
+		require.NoError(t, err)
+		require.NotNil(t, stats.VettedAt)
+
+		// audited passed the audits but not the uptime checks
+		audited := addNode()
+		audit(audited)
+		stats = audit(audited)
+		assert.Nil(t, stats.VettedAt)
+
+		unvetted := addNode()
+		_, err = cache.UpdateUptime(ctx, unvetted, true)
+		require.NoError(t, err)
+
+		for _, test := range []struct {
+			nodeID storj.NodeID
+			vetted bool
+		}{{vetted, true}, {audited, false}, {unvetted, false}} {
+			isVetted, err := cache.IsVetted(ctx, test.nodeID)
+			require.NoError(t, err)
+			assert.Equal(t, test.vetted, isVetted, test.nodeID.String())
+		}
+
+		// half of the requested nodes are selected from the new nodes
+		nodes, err := cache.FindStorageNodes(ctx, overlay.FindStorageNodesRequest{RequestedCount: 2})
+		require.NoError(t, err)
+		require.Len(t, nodes, 2)
+		assert.Contains(t, storj.NodeIDList{audited, unvetted}, nodes[0].Id)
+		assert.Equal(t, vetted, nodes[1].Id)
+
+		_, err = cache.FindStorageNodes(ctx, overlay.FindStorageNodesRequest{RequestedCount: 4})
+		assert.True(t, overlay.ErrNotEnoughNodes.Has(err))
+
+		{ // a vetted node stays vetted when the requirements are raised
+			raised := preferences
+			raised.AuditCount = 10
+			cache := overlay.NewCache(zaptest.NewLogger(t), db.OverlayCache(), raised, overlay.ReputationConfig{})
+
+			isVetted, err := cache.IsVetted(ctx, vetted)
+			require.NoError(t, err)
+			assert.True(t, isVetted)
+
+			nodes, err := cache.FindStorageNodes(ctx, overlay.FindStorageNodesRequest{RequestedCount: 2})
+			require.NoError(t, err)
+			require.Len(t, nodes, 2)
+			assert.Equal(t, vetted, nodes[1].Id)
+
+			stats, err := cache.GetStats(ctx, vetted)
+			require.NoError(t, err)
+			require.NotNil(t, stats.VettedAt)
+		}
+	})
+}
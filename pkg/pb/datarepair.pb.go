@@ -6,6 +6,7 @@ package pb
 import (
 	fmt "fmt"
 	proto "github.com/gogo/protobuf/proto"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
 	math "math"
 )
 
@@ -22,11 +23,17 @@ const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
 
 // InjuredSegment is the queue item used for the data repair queue
 type InjuredSegment struct {
-	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
-	LostPieces           []int32  `protobuf:"varint,2,rep,packed,name=lost_pieces,json=lostPieces,proto3" json:"lost_pieces,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Path       string  `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	LostPieces []int32 `protobuf:"varint,2,rep,packed,name=lost_pieces,json=lostPieces,proto3" json:"lost_pieces,omitempty"`
+	// health of the segment, the segments with the lowest health are repaired first
+	Health float64 `protobuf:"fixed64,3,opt,name=health,proto3" json:"health,omitempty"`
+	// number of times the repair of the segment was started, set by the repair queue
+	Attempts int32 `protobuf:"varint,4,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	// when the segment was first queued, set by the repair queue
+	InsertedAt           *timestamp.Timestamp `protobuf:"bytes,5,opt,name=inserted_at,json=insertedAt,proto3" json:"inserted_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
 func (m *InjuredSegment) Reset()         { *m = InjuredSegment{} }
@@ -67,6 +74,27 @@ func (m *InjuredSegment) GetLostPieces() []int32 {
 	return nil
 }
 
+func (m *InjuredSegment) GetHealth() float64 {
+	if m != nil {
+		return m.Health
+	}
+	return 0
+}
+
+func (m *InjuredSegment) GetAttempts() int32 {
+	if m != nil {
+		return m.Attempts
+	}
+	return 0
+}
+
+func (m *InjuredSegment) GetInsertedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.InsertedAt
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*InjuredSegment)(nil), "repair.InjuredSegment")
 }
@@ -74,13 +102,19 @@ func init() {
 func init() { proto.RegisterFile("datarepair.proto", fileDescriptor_b1b08e6fe9398aa6) }
 
 var fileDescriptor_b1b08e6fe9398aa6 = []byte{
-	// 119 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x48, 0x49, 0x2c, 0x49,
-	0x2c, 0x4a, 0x2d, 0x48, 0xcc, 0x2c, 0xd2, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x83, 0xf0,
-	0x94, 0x5c, 0xb9, 0xf8, 0x3c, 0xf3, 0xb2, 0x4a, 0x8b, 0x52, 0x53, 0x82, 0x53, 0xd3, 0x73, 0x53,
-	0xf3, 0x4a, 0x84, 0x84, 0xb8, 0x58, 0x0a, 0x12, 0x4b, 0x32, 0x24, 0x18, 0x15, 0x18, 0x35, 0x38,
-	0x83, 0xc0, 0x6c, 0x21, 0x79, 0x2e, 0xee, 0x9c, 0xfc, 0xe2, 0x92, 0xf8, 0x82, 0xcc, 0xd4, 0xe4,
-	0xd4, 0x62, 0x09, 0x26, 0x05, 0x66, 0x0d, 0xd6, 0x20, 0x2e, 0x90, 0x50, 0x00, 0x58, 0xc4, 0x89,
-	0x25, 0x8a, 0xa9, 0x20, 0x29, 0x89, 0x0d, 0x6c, 0xb6, 0x31, 0x20, 0x00, 0x00, 0xff, 0xff, 0x13,
-	0xff, 0xff, 0x1e, 0x6f, 0x00, 0x00, 0x00,
+	// 211 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x3c, 0x8e, 0x31, 0x4b, 0xc5, 0x30,
+	0x14, 0x46, 0xc9, 0x7b, 0x6d, 0xd1, 0x5b, 0x10, 0xc9, 0x20, 0xa1, 0xcb, 0x0b, 0x4e, 0x99, 0xfa,
+	0x40, 0x47, 0x27, 0xdd, 0xdc, 0x24, 0x3a, 0xb9, 0x3c, 0x6e, 0xed, 0xb5, 0xad, 0x34, 0x4d, 0x48,
+	0xee, 0xfb, 0x6b, 0xfe, 0x3e, 0x31, 0xb5, 0x6e, 0xdf, 0x77, 0x38, 0xc3, 0x81, 0xeb, 0x1e, 0x19,
+	0x23, 0x05, 0x9c, 0x62, 0x1b, 0xa2, 0x67, 0x2f, 0xab, 0xf5, 0x35, 0x87, 0xc1, 0xfb, 0x61, 0xa6,
+	0x63, 0xa6, 0xdd, 0xf9, 0xf3, 0xc8, 0x93, 0xa3, 0xc4, 0xe8, 0xc2, 0x2a, 0xde, 0x7e, 0x0b, 0xb8,
+	0x7a, 0x5e, 0xbe, 0xce, 0x91, 0xfa, 0x57, 0x1a, 0x1c, 0x2d, 0x2c, 0x25, 0x14, 0x01, 0x79, 0x54,
+	0x42, 0x0b, 0x73, 0x69, 0xf3, 0x96, 0x07, 0xa8, 0x67, 0x9f, 0xf8, 0x14, 0x26, 0xfa, 0xa0, 0xa4,
+	0x76, 0x7a, 0x6f, 0x4a, 0x0b, 0xbf, 0xe8, 0x25, 0x13, 0x79, 0x03, 0xd5, 0x48, 0x38, 0xf3, 0xa8,
+	0xf6, 0x5a, 0x18, 0x61, 0xff, 0x9e, 0x6c, 0xe0, 0x02, 0x99, 0xc9, 0x05, 0x4e, 0xaa, 0xd0, 0xc2,
+	0x94, 0xf6, 0xff, 0xcb, 0x07, 0xa8, 0xa7, 0x25, 0x51, 0x64, 0xea, 0x4f, 0xc8, 0xaa, 0xd4, 0xc2,
+	0xd4, 0x77, 0x4d, 0xbb, 0x26, 0xb7, 0x5b, 0x72, 0xfb, 0xb6, 0x25, 0x5b, 0xd8, 0xf4, 0x47, 0x7e,
+	0x2a, 0xde, 0x77, 0xa1, 0xeb, 0xaa, 0x6c, 0xdd, 0xff, 0x0c, 0x00, 0x0b, 0xff, 0x3f, 0x88, 0x02,
+	0x01, 0x00, 0x00,
 }
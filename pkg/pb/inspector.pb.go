@@ -25,6 +25,37 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
 
+type AuditRecord_Outcome int32
+
+const (
+	AuditRecord_SUCCESS   AuditRecord_Outcome = 0
+	AuditRecord_FAILURE   AuditRecord_Outcome = 1
+	AuditRecord_OFFLINE   AuditRecord_Outcome = 2
+	AuditRecord_CONTAINED AuditRecord_Outcome = 3
+)
+
+var AuditRecord_Outcome_name = map[int32]string{
+	0: "SUCCESS",
+	1: "FAILURE",
+	2: "OFFLINE",
+	3: "CONTAINED",
+}
+
+var AuditRecord_Outcome_value = map[string]int32{
+	"SUCCESS":   0,
+	"FAILURE":   1,
+	"OFFLINE":   2,
+	"CONTAINED": 3,
+}
+
+func (x AuditRecord_Outcome) String() string {
+	return proto.EnumName(AuditRecord_Outcome_name, int32(x))
+}
+
+func (AuditRecord_Outcome) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_a07d9034b2dd9d26, []int{34, 0}
+}
+
 // ListSegments
 type ListIrreparableSegmentsRequest struct {
 	Limit                int32    `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
@@ -78,6 +109,8 @@ type IrreparableSegment struct {
 	LostPieces           int32    `protobuf:"varint,3,opt,name=lost_pieces,json=lostPieces,proto3" json:"lost_pieces,omitempty"`
 	LastRepairAttempt    int64    `protobuf:"varint,4,opt,name=last_repair_attempt,json=lastRepairAttempt,proto3" json:"last_repair_attempt,omitempty"`
 	RepairAttemptCount   int64    `protobuf:"varint,5,opt,name=repair_attempt_count,json=repairAttemptCount,proto3" json:"repair_attempt_count,omitempty"`
+	LostPieceNums        []int32  `protobuf:"varint,6,rep,packed,name=lost_piece_nums,json=lostPieceNums,proto3" json:"lost_piece_nums,omitempty"`
+	LastError            string   `protobuf:"bytes,7,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -142,6 +175,20 @@ func (m *IrreparableSegment) GetRepairAttemptCount() int64 {
 	return 0
 }
 
+func (m *IrreparableSegment) GetLostPieceNums() []int32 {
+	if m != nil {
+		return m.LostPieceNums
+	}
+	return nil
+}
+
+func (m *IrreparableSegment) GetLastError() string {
+	if m != nil {
+		return m.LastError
+	}
+	return ""
+}
+
 type ListIrreparableSegmentsResponse struct {
 	Segments             []*IrreparableSegment `protobuf:"bytes,1,rep,name=segments,proto3" json:"segments,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
@@ -368,6 +415,179 @@ func (m *CreateStatsResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_CreateStatsResponse proto.InternalMessageInfo
 
+// ReputationHistory
+type ReputationHistoryRequest struct {
+	NodeId NodeID `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3,customtype=NodeID" json:"node_id"`
+	// days is how many days of scores are returned
+	Days                 int32    `protobuf:"varint,2,opt,name=days,proto3" json:"days,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReputationHistoryRequest) Reset()         { *m = ReputationHistoryRequest{} }
+func (m *ReputationHistoryRequest) String() string { return proto.CompactTextString(m) }
+func (*ReputationHistoryRequest) ProtoMessage()    {}
+func (*ReputationHistoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a07d9034b2dd9d26, []int{7}
+}
+func (m *ReputationHistoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReputationHistoryRequest.Unmarshal(m, b)
+}
+func (m *ReputationHistoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReputationHistoryRequest.Marshal(b, m, deterministic)
+}
+func (m *ReputationHistoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReputationHistoryRequest.Merge(m, src)
+}
+func (m *ReputationHistoryRequest) XXX_Size() int {
+	return xxx_messageInfo_ReputationHistoryRequest.Size(m)
+}
+func (m *ReputationHistoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReputationHistoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReputationHistoryRequest proto.InternalMessageInfo
+
+func (m *ReputationHistoryRequest) GetDays() int32 {
+	if m != nil {
+		return m.Days
+	}
+	return 0
+}
+
+type ReputationScore struct {
+	IntervalStart        *timestamp.Timestamp `protobuf:"bytes,1,opt,name=interval_start,json=intervalStart,proto3" json:"interval_start,omitempty"`
+	AuditScore           float64              `protobuf:"fixed64,2,opt,name=audit_score,json=auditScore,proto3" json:"audit_score,omitempty"`
+	UptimeScore          float64              `protobuf:"fixed64,3,opt,name=uptime_score,json=uptimeScore,proto3" json:"uptime_score,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ReputationScore) Reset()         { *m = ReputationScore{} }
+func (m *ReputationScore) String() string { return proto.CompactTextString(m) }
+func (*ReputationScore) ProtoMessage()    {}
+func (*ReputationScore) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a07d9034b2dd9d26, []int{8}
+}
+func (m *ReputationScore) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReputationScore.Unmarshal(m, b)
+}
+func (m *ReputationScore) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReputationScore.Marshal(b, m, deterministic)
+}
+func (m *ReputationScore) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReputationScore.Merge(m, src)
+}
+func (m *ReputationScore) XXX_Size() int {
+	return xxx_messageInfo_ReputationScore.Size(m)
+}
+func (m *ReputationScore) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReputationScore.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReputationScore proto.InternalMessageInfo
+
+func (m *ReputationScore) GetIntervalStart() *timestamp.Timestamp {
+	if m != nil {
+		return m.IntervalStart
+	}
+	return nil
+}
+
+func (m *ReputationScore) GetAuditScore() float64 {
+	if m != nil {
+		return m.AuditScore
+	}
+	return 0
+}
+
+func (m *ReputationScore) GetUptimeScore() float64 {
+	if m != nil {
+		return m.UptimeScore
+	}
+	return 0
+}
+
+type ReputationHistoryResponse struct {
+	AuditAlpha           float64              `protobuf:"fixed64,1,opt,name=audit_alpha,json=auditAlpha,proto3" json:"audit_alpha,omitempty"`
+	AuditBeta            float64              `protobuf:"fixed64,2,opt,name=audit_beta,json=auditBeta,proto3" json:"audit_beta,omitempty"`
+	UptimeAlpha          float64              `protobuf:"fixed64,3,opt,name=uptime_alpha,json=uptimeAlpha,proto3" json:"uptime_alpha,omitempty"`
+	UptimeBeta           float64              `protobuf:"fixed64,4,opt,name=uptime_beta,json=uptimeBeta,proto3" json:"uptime_beta,omitempty"`
+	Disqualified         *timestamp.Timestamp `protobuf:"bytes,5,opt,name=disqualified,proto3" json:"disqualified,omitempty"`
+	Scores               []*ReputationScore   `protobuf:"bytes,6,rep,name=scores,proto3" json:"scores,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ReputationHistoryResponse) Reset()         { *m = ReputationHistoryResponse{} }
+func (m *ReputationHistoryResponse) String() string { return proto.CompactTextString(m) }
+func (*ReputationHistoryResponse) ProtoMessage()    {}
+func (*ReputationHistoryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a07d9034b2dd9d26, []int{9}
+}
+func (m *ReputationHistoryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReputationHistoryResponse.Unmarshal(m, b)
+}
+func (m *ReputationHistoryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReputationHistoryResponse.Marshal(b, m, deterministic)
+}
+func (m *ReputationHistoryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReputationHistoryResponse.Merge(m, src)
+}
+func (m *ReputationHistoryResponse) XXX_Size() int {
+	return xxx_messageInfo_ReputationHistoryResponse.Size(m)
+}
+func (m *ReputationHistoryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReputationHistoryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReputationHistoryResponse proto.InternalMessageInfo
+
+func (m *ReputationHistoryResponse) GetAuditAlpha() float64 {
+	if m != nil {
+		return m.AuditAlpha
+	}
+	return 0
+}
+
+func (m *ReputationHistoryResponse) GetAuditBeta() float64 {
+	if m != nil {
+		return m.AuditBeta
+	}
+	return 0
+}
+
+func (m *ReputationHistoryResponse) GetUptimeAlpha() float64 {
+	if m != nil {
+		return m.UptimeAlpha
+	}
+	return 0
+}
+
+func (m *ReputationHistoryResponse) GetUptimeBeta() float64 {
+	if m != nil {
+		return m.UptimeBeta
+	}
+	return 0
+}
+
+func (m *ReputationHistoryResponse) GetDisqualified() *timestamp.Timestamp {
+	if m != nil {
+		return m.Disqualified
+	}
+	return nil
+}
+
+func (m *ReputationHistoryResponse) GetScores() []*ReputationScore {
+	if m != nil {
+		return m.Scores
+	}
+	return nil
+}
+
 // CountNodes
 type CountNodesResponse struct {
 	Count                int64    `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
@@ -380,7 +600,7 @@ func (m *CountNodesResponse) Reset()         { *m = CountNodesResponse{} }
 func (m *CountNodesResponse) String() string { return proto.CompactTextString(m) }
 func (*CountNodesResponse) ProtoMessage()    {}
 func (*CountNodesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a07d9034b2dd9d26, []int{7}
+	return fileDescriptor_a07d9034b2dd9d26, []int{10}
 }
 func (m *CountNodesResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CountNodesResponse.Unmarshal(m, b)
@@ -417,7 +637,7 @@ func (m *CountNodesRequest) Reset()         { *m = CountNodesRequest{} }
 func (m *CountNodesRequest) String() string { return proto.CompactTextString(m) }
 func (*CountNodesRequest) ProtoMessage()    {}
 func (*CountNodesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a07d9034b2dd9d26, []int{8}
+	return fileDescriptor_a07d9034b2dd9d26, []int{11}
 }
 func (m *CountNodesRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CountNodesRequest.Unmarshal(m, b)
@@ -448,7 +668,7 @@ func (m *GetBucketsRequest) Reset()         { *m = GetBucketsRequest{} }
 func (m *GetBucketsRequest) String() string { return proto.CompactTextString(m) }
 func (*GetBucketsRequest) ProtoMessage()    {}
 func (*GetBucketsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a07d9034b2dd9d26, []int{9}
+	return fileDescriptor_a07d9034b2dd9d26, []int{12}
 }
 func (m *GetBucketsRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetBucketsRequest.Unmarshal(m, b)
@@ -480,7 +700,7 @@ func (m *GetBucketsResponse) Reset()         { *m = GetBucketsResponse{} }
 func (m *GetBucketsResponse) String() string { return proto.CompactTextString(m) }
 func (*GetBucketsResponse) ProtoMessage()    {}
 func (*GetBucketsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a07d9034b2dd9d26, []int{10}
+	return fileDescriptor_a07d9034b2dd9d26, []int{13}
 }
 func (m *GetBucketsResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetBucketsResponse.Unmarshal(m, b)
@@ -519,7 +739,7 @@ func (m *GetBucketRequest) Reset()         { *m = GetBucketRequest{} }
 func (m *GetBucketRequest) String() string { return proto.CompactTextString(m) }
 func (*GetBucketRequest) ProtoMessage()    {}
 func (*GetBucketRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a07d9034b2dd9d26, []int{11}
+	return fileDescriptor_a07d9034b2dd9d26, []int{14}
 }
 func (m *GetBucketRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetBucketRequest.Unmarshal(m, b)
@@ -551,7 +771,7 @@ func (m *GetBucketResponse) Reset()         { *m = GetBucketResponse{} }
 func (m *GetBucketResponse) String() string { return proto.CompactTextString(m) }
 func (*GetBucketResponse) ProtoMessage()    {}
 func (*GetBucketResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a07d9034b2dd9d26, []int{12}
+	return fileDescriptor_a07d9034b2dd9d26, []int{15}
 }
 func (m *GetBucketResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetBucketResponse.Unmarshal(m, b)
@@ -589,7 +809,7 @@ func (m *Bucket) Reset()         { *m = Bucket{} }
 func (m *Bucket) String() string { return proto.CompactTextString(m) }
 func (*Bucket) ProtoMessage()    {}
 func (*Bucket) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a07d9034b2dd9d26, []int{13}
+	return fileDescriptor_a07d9034b2dd9d26, []int{16}
 }
 func (m *Bucket) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Bucket.Unmarshal(m, b)
@@ -627,7 +847,7 @@ func (m *BucketList) Reset()         { *m = BucketList{} }
 func (m *BucketList) String() string { return proto.CompactTextString(m) }
 func (*BucketList) ProtoMessage()    {}
 func (*BucketList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a07d9034b2dd9d26, []int{14}
+	return fileDescriptor_a07d9034b2dd9d26, []int{17}
 }
 func (m *BucketList) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BucketList.Unmarshal(m, b)
@@ -667,7 +887,7 @@ func (m *PingNodeRequest) Reset()         { *m = PingNodeRequest{} }
 func (m *PingNodeRequest) String() string { return proto.CompactTextString(m) }
 func (*PingNodeRequest) ProtoMessage()    {}
 func (*PingNodeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a07d9034b2dd9d26, []int{15}
+	return fileDescriptor_a07d9034b2dd9d26, []int{18}
 }
 func (m *PingNodeRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PingNodeRequest.Unmarshal(m, b)
@@ -705,7 +925,7 @@ func (m *PingNodeResponse) Reset()         { *m = PingNodeResponse{} }
 func (m *PingNodeResponse) String() string { return proto.CompactTextString(m) }
 func (*PingNodeResponse) ProtoMessage()    {}
 func (*PingNodeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a07d9034b2dd9d26, []int{16}
+	return fileDescriptor_a07d9034b2dd9d26, []int{19}
 }
 func (m *PingNodeResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PingNodeResponse.Unmarshal(m, b)
@@ -744,7 +964,7 @@ func (m *LookupNodeRequest) Reset()         { *m = LookupNodeRequest{} }
 func (m *LookupNodeRequest) String() string { return proto.CompactTextString(m) }
 func (*LookupNodeRequest) ProtoMessage()    {}
 func (*LookupNodeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a07d9034b2dd9d26, []int{17}
+	return fileDescriptor_a07d9034b2dd9d26, []int{20}
 }
 func (m *LookupNodeRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_LookupNodeRequest.Unmarshal(m, b)
@@ -790,7 +1010,7 @@ func (m *LookupNodeResponse) Reset()         { *m = LookupNodeResponse{} }
 func (m *LookupNodeResponse) String() string { return proto.CompactTextString(m) }
 func (*LookupNodeResponse) ProtoMessage()    {}
 func (*LookupNodeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a07d9034b2dd9d26, []int{18}
+	return fileDescriptor_a07d9034b2dd9d26, []int{21}
 }
 func (m *LookupNodeResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_LookupNodeResponse.Unmarshal(m, b)
@@ -836,7 +1056,7 @@ func (m *NodeInfoRequest) Reset()         { *m = NodeInfoRequest{} }
 func (m *NodeInfoRequest) String() string { return proto.CompactTextString(m) }
 func (*NodeInfoRequest) ProtoMessage()    {}
 func (*NodeInfoRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a07d9034b2dd9d26, []int{19}
+	return fileDescriptor_a07d9034b2dd9d26, []int{22}
 }
 func (m *NodeInfoRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_NodeInfoRequest.Unmarshal(m, b)
@@ -876,7 +1096,7 @@ func (m *NodeInfoResponse) Reset()         { *m = NodeInfoResponse{} }
 func (m *NodeInfoResponse) String() string { return proto.CompactTextString(m) }
 func (*NodeInfoResponse) ProtoMessage()    {}
 func (*NodeInfoResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a07d9034b2dd9d26, []int{20}
+	return fileDescriptor_a07d9034b2dd9d26, []int{23}
 }
 func (m *NodeInfoResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_NodeInfoResponse.Unmarshal(m, b)
@@ -930,7 +1150,7 @@ func (m *FindNearRequest) Reset()         { *m = FindNearRequest{} }
 func (m *FindNearRequest) String() string { return proto.CompactTextString(m) }
 func (*FindNearRequest) ProtoMessage()    {}
 func (*FindNearRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a07d9034b2dd9d26, []int{21}
+	return fileDescriptor_a07d9034b2dd9d26, []int{24}
 }
 func (m *FindNearRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_FindNearRequest.Unmarshal(m, b)
@@ -968,7 +1188,7 @@ func (m *FindNearResponse) Reset()         { *m = FindNearResponse{} }
 func (m *FindNearResponse) String() string { return proto.CompactTextString(m) }
 func (*FindNearResponse) ProtoMessage()    {}
 func (*FindNearResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a07d9034b2dd9d26, []int{22}
+	return fileDescriptor_a07d9034b2dd9d26, []int{25}
 }
 func (m *FindNearResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_FindNearResponse.Unmarshal(m, b)
@@ -1005,7 +1225,7 @@ func (m *DumpNodesRequest) Reset()         { *m = DumpNodesRequest{} }
 func (m *DumpNodesRequest) String() string { return proto.CompactTextString(m) }
 func (*DumpNodesRequest) ProtoMessage()    {}
 func (*DumpNodesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a07d9034b2dd9d26, []int{23}
+	return fileDescriptor_a07d9034b2dd9d26, []int{26}
 }
 func (m *DumpNodesRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DumpNodesRequest.Unmarshal(m, b)
@@ -1036,7 +1256,7 @@ func (m *DumpNodesResponse) Reset()         { *m = DumpNodesResponse{} }
 func (m *DumpNodesResponse) String() string { return proto.CompactTextString(m) }
 func (*DumpNodesResponse) ProtoMessage()    {}
 func (*DumpNodesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a07d9034b2dd9d26, []int{24}
+	return fileDescriptor_a07d9034b2dd9d26, []int{27}
 }
 func (m *DumpNodesResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DumpNodesResponse.Unmarshal(m, b)
@@ -1073,7 +1293,7 @@ func (m *StatsRequest) Reset()         { *m = StatsRequest{} }
 func (m *StatsRequest) String() string { return proto.CompactTextString(m) }
 func (*StatsRequest) ProtoMessage()    {}
 func (*StatsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a07d9034b2dd9d26, []int{25}
+	return fileDescriptor_a07d9034b2dd9d26, []int{28}
 }
 func (m *StatsRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatsRequest.Unmarshal(m, b)
@@ -1109,7 +1329,7 @@ func (m *StatSummaryResponse) Reset()         { *m = StatSummaryResponse{} }
 func (m *StatSummaryResponse) String() string { return proto.CompactTextString(m) }
 func (*StatSummaryResponse) ProtoMessage()    {}
 func (*StatSummaryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a07d9034b2dd9d26, []int{26}
+	return fileDescriptor_a07d9034b2dd9d26, []int{29}
 }
 func (m *StatSummaryResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatSummaryResponse.Unmarshal(m, b)
@@ -1181,7 +1401,7 @@ func (m *DashboardRequest) Reset()         { *m = DashboardRequest{} }
 func (m *DashboardRequest) String() string { return proto.CompactTextString(m) }
 func (*DashboardRequest) ProtoMessage()    {}
 func (*DashboardRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a07d9034b2dd9d26, []int{27}
+	return fileDescriptor_a07d9034b2dd9d26, []int{30}
 }
 func (m *DashboardRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DashboardRequest.Unmarshal(m, b)
@@ -1202,25 +1422,31 @@ func (m *DashboardRequest) XXX_DiscardUnknown() {
 var xxx_messageInfo_DashboardRequest proto.InternalMessageInfo
 
 type DashboardResponse struct {
-	NodeId               NodeID               `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3,customtype=NodeID" json:"node_id"`
-	NodeConnections      int64                `protobuf:"varint,2,opt,name=node_connections,json=nodeConnections,proto3" json:"node_connections,omitempty"`
-	BootstrapAddress     string               `protobuf:"bytes,3,opt,name=bootstrap_address,json=bootstrapAddress,proto3" json:"bootstrap_address,omitempty"`
-	InternalAddress      string               `protobuf:"bytes,4,opt,name=internal_address,json=internalAddress,proto3" json:"internal_address,omitempty"`
-	ExternalAddress      string               `protobuf:"bytes,5,opt,name=external_address,json=externalAddress,proto3" json:"external_address,omitempty"`
-	Stats                *StatSummaryResponse `protobuf:"bytes,6,opt,name=stats,proto3" json:"stats,omitempty"`
-	Uptime               *duration.Duration   `protobuf:"bytes,7,opt,name=uptime,proto3" json:"uptime,omitempty"`
-	LastPinged           *timestamp.Timestamp `protobuf:"bytes,8,opt,name=last_pinged,json=lastPinged,proto3" json:"last_pinged,omitempty"`
-	LastQueried          *timestamp.Timestamp `protobuf:"bytes,9,opt,name=last_queried,json=lastQueried,proto3" json:"last_queried,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+	NodeId               NodeID                `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3,customtype=NodeID" json:"node_id"`
+	NodeConnections      int64                 `protobuf:"varint,2,opt,name=node_connections,json=nodeConnections,proto3" json:"node_connections,omitempty"`
+	BootstrapAddress     string                `protobuf:"bytes,3,opt,name=bootstrap_address,json=bootstrapAddress,proto3" json:"bootstrap_address,omitempty"`
+	InternalAddress      string                `protobuf:"bytes,4,opt,name=internal_address,json=internalAddress,proto3" json:"internal_address,omitempty"`
+	ExternalAddress      string                `protobuf:"bytes,5,opt,name=external_address,json=externalAddress,proto3" json:"external_address,omitempty"`
+	Stats                *StatSummaryResponse  `protobuf:"bytes,6,opt,name=stats,proto3" json:"stats,omitempty"`
+	Uptime               *duration.Duration    `protobuf:"bytes,7,opt,name=uptime,proto3" json:"uptime,omitempty"`
+	LastPinged           *timestamp.Timestamp  `protobuf:"bytes,8,opt,name=last_pinged,json=lastPinged,proto3" json:"last_pinged,omitempty"`
+	LastQueried          *timestamp.Timestamp  `protobuf:"bytes,9,opt,name=last_queried,json=lastQueried,proto3" json:"last_queried,omitempty"`
+	SettlementBackoffs   []*SettlementBackoff  `protobuf:"bytes,10,rep,name=settlement_backoffs,json=settlementBackoffs,proto3" json:"settlement_backoffs,omitempty"`
+	UnsentOrders         []*UnsentOrderSummary `protobuf:"bytes,11,rep,name=unsent_orders,json=unsentOrders,proto3" json:"unsent_orders,omitempty"`
+	CorruptedPieces      int64                 `protobuf:"varint,12,opt,name=corrupted_pieces,json=corruptedPieces,proto3" json:"corrupted_pieces,omitempty"`
+	BandwidthToday       []*BandwidthSummary   `protobuf:"bytes,13,rep,name=bandwidth_today,json=bandwidthToday,proto3" json:"bandwidth_today,omitempty"`
+	DiskHealth           *DiskHealth           `protobuf:"bytes,14,opt,name=disk_health,json=diskHealth,proto3" json:"disk_health,omitempty"`
+	Satellites           []*SatelliteSummary   `protobuf:"bytes,15,rep,name=satellites,proto3" json:"satellites,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
 }
 
 func (m *DashboardResponse) Reset()         { *m = DashboardResponse{} }
 func (m *DashboardResponse) String() string { return proto.CompactTextString(m) }
 func (*DashboardResponse) ProtoMessage()    {}
 func (*DashboardResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a07d9034b2dd9d26, []int{28}
+	return fileDescriptor_a07d9034b2dd9d26, []int{31}
 }
 func (m *DashboardResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DashboardResponse.Unmarshal(m, b)
@@ -1296,183 +1522,1680 @@ func (m *DashboardResponse) GetLastQueried() *timestamp.Timestamp {
 	return nil
 }
 
-func init() {
-	proto.RegisterType((*ListIrreparableSegmentsRequest)(nil), "inspector.ListIrreparableSegmentsRequest")
-	proto.RegisterType((*IrreparableSegment)(nil), "inspector.IrreparableSegment")
-	proto.RegisterType((*ListIrreparableSegmentsResponse)(nil), "inspector.ListIrreparableSegmentsResponse")
-	proto.RegisterType((*GetStatsRequest)(nil), "inspector.GetStatsRequest")
-	proto.RegisterType((*GetStatsResponse)(nil), "inspector.GetStatsResponse")
-	proto.RegisterType((*CreateStatsRequest)(nil), "inspector.CreateStatsRequest")
-	proto.RegisterType((*CreateStatsResponse)(nil), "inspector.CreateStatsResponse")
-	proto.RegisterType((*CountNodesResponse)(nil), "inspector.CountNodesResponse")
-	proto.RegisterType((*CountNodesRequest)(nil), "inspector.CountNodesRequest")
-	proto.RegisterType((*GetBucketsRequest)(nil), "inspector.GetBucketsRequest")
-	proto.RegisterType((*GetBucketsResponse)(nil), "inspector.GetBucketsResponse")
-	proto.RegisterType((*GetBucketRequest)(nil), "inspector.GetBucketRequest")
-	proto.RegisterType((*GetBucketResponse)(nil), "inspector.GetBucketResponse")
-	proto.RegisterType((*Bucket)(nil), "inspector.Bucket")
-	proto.RegisterType((*BucketList)(nil), "inspector.BucketList")
-	proto.RegisterType((*PingNodeRequest)(nil), "inspector.PingNodeRequest")
-	proto.RegisterType((*PingNodeResponse)(nil), "inspector.PingNodeResponse")
-	proto.RegisterType((*LookupNodeRequest)(nil), "inspector.LookupNodeRequest")
-	proto.RegisterType((*LookupNodeResponse)(nil), "inspector.LookupNodeResponse")
-	proto.RegisterType((*NodeInfoRequest)(nil), "inspector.NodeInfoRequest")
-	proto.RegisterType((*NodeInfoResponse)(nil), "inspector.NodeInfoResponse")
-	proto.RegisterType((*FindNearRequest)(nil), "inspector.FindNearRequest")
-	proto.RegisterType((*FindNearResponse)(nil), "inspector.FindNearResponse")
-	proto.RegisterType((*DumpNodesRequest)(nil), "inspector.DumpNodesRequest")
-	proto.RegisterType((*DumpNodesResponse)(nil), "inspector.DumpNodesResponse")
-	proto.RegisterType((*StatsRequest)(nil), "inspector.StatsRequest")
-	proto.RegisterType((*StatSummaryResponse)(nil), "inspector.StatSummaryResponse")
-	proto.RegisterType((*DashboardRequest)(nil), "inspector.DashboardRequest")
-	proto.RegisterType((*DashboardResponse)(nil), "inspector.DashboardResponse")
+func (m *DashboardResponse) GetSettlementBackoffs() []*SettlementBackoff {
+	if m != nil {
+		return m.SettlementBackoffs
+	}
+	return nil
 }
 
-func init() { proto.RegisterFile("inspector.proto", fileDescriptor_a07d9034b2dd9d26) }
-
-var fileDescriptor_a07d9034b2dd9d26 = []byte{
-	// 1370 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x57, 0xcb, 0x72, 0x1b, 0x45,
-	0x14, 0xcd, 0xe8, 0x15, 0xe9, 0xca, 0xd1, 0xa3, 0x65, 0x88, 0x10, 0x89, 0x6d, 0xa6, 0x80, 0x38,
-	0x49, 0x95, 0x12, 0x44, 0x58, 0x04, 0x2a, 0x8b, 0x58, 0x26, 0x89, 0x2a, 0x21, 0x31, 0xe3, 0xb0,
-	0xa1, 0x52, 0xa8, 0x5a, 0x9a, 0xb6, 0x32, 0x65, 0x69, 0x7a, 0x32, 0xdd, 0x0a, 0xf8, 0x0f, 0xf8,
-	0x02, 0x16, 0x6c, 0xf9, 0x09, 0xaa, 0xf8, 0x01, 0x76, 0xec, 0x59, 0x64, 0x43, 0x15, 0xff, 0xc0,
-	0x8e, 0xea, 0xdb, 0x3d, 0x2f, 0x8d, 0x85, 0x5d, 0x14, 0xec, 0x34, 0xe7, 0x9e, 0x3e, 0x7d, 0xef,
-	0xed, 0xee, 0xd3, 0x2d, 0x68, 0x7a, 0xbe, 0x08, 0xd8, 0x54, 0xf2, 0xb0, 0x1f, 0x84, 0x5c, 0x72,
-	0x52, 0x8b, 0x81, 0x1e, 0xcc, 0xf8, 0x8c, 0x6b, 0xb8, 0x07, 0x3e, 0x77, 0x99, 0xf9, 0xdd, 0x0c,
-	0xb8, 0xe7, 0x4b, 0x16, 0xba, 0x13, 0x03, 0x6c, 0xcd, 0x38, 0x9f, 0xcd, 0xd9, 0x2d, 0xfc, 0x9a,
-	0x2c, 0x8f, 0x6e, 0xb9, 0xcb, 0x90, 0x4a, 0x8f, 0xfb, 0x26, 0xbe, 0xbd, 0x1a, 0x97, 0xde, 0x82,
-	0x09, 0x49, 0x17, 0x81, 0x26, 0xd8, 0x4f, 0x61, 0xeb, 0x89, 0x27, 0xe4, 0x28, 0x0c, 0x59, 0x40,
-	0x43, 0x3a, 0x99, 0xb3, 0x43, 0x36, 0x5b, 0x30, 0x5f, 0x0a, 0x87, 0xbd, 0x5a, 0x32, 0x21, 0xc9,
-	0x26, 0x94, 0xe7, 0xde, 0xc2, 0x93, 0x5d, 0x6b, 0xc7, 0xda, 0x2d, 0x3b, 0xfa, 0x83, 0xbc, 0x0d,
-	0x15, 0x7e, 0x74, 0x24, 0x98, 0xec, 0x16, 0x10, 0x36, 0x5f, 0xf6, 0x9f, 0x16, 0x90, 0xbc, 0x18,
-	0x21, 0x50, 0x0a, 0xa8, 0x7c, 0x89, 0x1a, 0x1b, 0x0e, 0xfe, 0x26, 0x77, 0xa1, 0x21, 0x74, 0x78,
-	0xec, 0x32, 0x49, 0xbd, 0x39, 0x4a, 0xd5, 0x07, 0xa4, 0x9f, 0x54, 0x79, 0xa0, 0x7f, 0x39, 0x97,
-	0x0c, 0x73, 0x1f, 0x89, 0x64, 0x1b, 0xea, 0x73, 0x2e, 0xe4, 0x38, 0xf0, 0xd8, 0x94, 0x89, 0x6e,
-	0x11, 0x53, 0x00, 0x05, 0x1d, 0x20, 0x42, 0xfa, 0xd0, 0x99, 0x53, 0x21, 0xc7, 0x2a, 0x11, 0x2f,
-	0x1c, 0x53, 0x29, 0xd9, 0x22, 0x90, 0xdd, 0xd2, 0x8e, 0xb5, 0x5b, 0x74, 0xda, 0x2a, 0xe4, 0x60,
-	0xe4, 0xbe, 0x0e, 0x90, 0xdb, 0xb0, 0x99, 0xa5, 0x8e, 0xa7, 0x7c, 0xe9, 0xcb, 0x6e, 0x19, 0x07,
-	0x90, 0x30, 0x4d, 0x1e, 0xaa, 0x88, 0xfd, 0x02, 0xb6, 0xd7, 0x36, 0x4e, 0x04, 0xdc, 0x17, 0x8c,
-	0xdc, 0x85, 0xaa, 0x49, 0x5b, 0x74, 0xad, 0x9d, 0xe2, 0x6e, 0x7d, 0x70, 0xb5, 0x9f, 0x2c, 0x7a,
-	0x7e, 0xa4, 0x13, 0xd3, 0xed, 0x4f, 0xa1, 0xf9, 0x90, 0xc9, 0x43, 0x49, 0x93, 0x75, 0xb8, 0x06,
-	0x17, 0xd5, 0x4e, 0x18, 0x7b, 0xae, 0xee, 0xe2, 0x5e, 0xe3, 0xd7, 0x37, 0xdb, 0x17, 0x7e, 0x7f,
-	0xb3, 0x5d, 0x79, 0xca, 0x5d, 0x36, 0xda, 0x77, 0x2a, 0x2a, 0x3c, 0x72, 0xed, 0x1f, 0x2d, 0x68,
-	0x25, 0x83, 0x4d, 0x2e, 0xdb, 0x50, 0xa7, 0x4b, 0xd7, 0x8b, 0xea, 0xb2, 0xb0, 0x2e, 0x40, 0x08,
-	0xeb, 0x49, 0x08, 0xb8, 0x7f, 0x70, 0x29, 0x2c, 0x43, 0x70, 0x14, 0x42, 0xde, 0x83, 0x8d, 0x65,
-	0xa0, 0xb6, 0x8f, 0x91, 0x28, 0xa2, 0x44, 0x5d, 0x63, 0x5a, 0x23, 0xa1, 0x68, 0x91, 0x12, 0x8a,
-	0x18, 0x0a, 0xaa, 0xd8, 0x7f, 0x58, 0x40, 0x86, 0x21, 0xa3, 0x92, 0xfd, 0xab, 0xe2, 0x56, 0xeb,
-	0x28, 0xe4, 0xea, 0xe8, 0x43, 0x47, 0x13, 0xc4, 0x72, 0x3a, 0x65, 0x42, 0x64, 0xb2, 0x6d, 0x63,
-	0xe8, 0x50, 0x47, 0x56, 0x73, 0xd6, 0xc4, 0x52, 0xbe, 0xac, 0xdb, 0xb0, 0x69, 0x28, 0x59, 0x4d,
-	0xb3, 0x39, 0x74, 0x2c, 0x2d, 0x6a, 0xbf, 0x05, 0x9d, 0x4c, 0x91, 0x7a, 0x11, 0xec, 0x1b, 0x40,
-	0x30, 0xae, 0x6a, 0x4a, 0x96, 0x66, 0x13, 0xca, 0xe9, 0x45, 0xd1, 0x1f, 0x76, 0x07, 0xda, 0x69,
-	0x2e, 0xb6, 0x49, 0x81, 0x0f, 0x99, 0xdc, 0x5b, 0x4e, 0x8f, 0x59, 0xdc, 0x3b, 0xfb, 0x11, 0x90,
-	0x34, 0x98, 0xa8, 0x4a, 0x2e, 0xe9, 0x3c, 0x52, 0xc5, 0x0f, 0x72, 0x05, 0x8a, 0x9e, 0x2b, 0xba,
-	0x85, 0x9d, 0xe2, 0xee, 0xc6, 0x1e, 0xa4, 0xfa, 0xab, 0x60, 0x7b, 0x80, 0x1b, 0x47, 0x2b, 0x45,
-	0x2b, 0xb3, 0x05, 0x85, 0xb5, 0x8b, 0x52, 0xf0, 0x5c, 0xfb, 0xab, 0x54, 0x4a, 0xf1, 0xe4, 0x67,
-	0x0c, 0x22, 0x3b, 0x50, 0x56, 0xeb, 0xa9, 0x13, 0xa9, 0x0f, 0xa0, 0x8f, 0x1e, 0xa7, 0x08, 0x8e,
-	0x0e, 0xd8, 0x37, 0xa0, 0xa2, 0x35, 0xcf, 0xc1, 0xed, 0x03, 0x68, 0xae, 0x3a, 0x90, 0x09, 0xdf,
-	0x5a, 0xc7, 0x7f, 0x0c, 0xcd, 0x03, 0xcf, 0x9f, 0x21, 0x74, 0xbe, 0x2a, 0x49, 0x17, 0x2e, 0x52,
-	0xd7, 0x0d, 0x99, 0x10, 0xb8, 0xe5, 0x6a, 0x4e, 0xf4, 0x69, 0xdb, 0xd0, 0x4a, 0xc4, 0x4c, 0xf9,
-	0x0d, 0x28, 0xf0, 0x63, 0x54, 0xab, 0x3a, 0x05, 0x7e, 0x6c, 0xdf, 0x83, 0xf6, 0x13, 0xce, 0x8f,
-	0x97, 0x41, 0x7a, 0xca, 0x46, 0x3c, 0x65, 0xed, 0x8c, 0x29, 0x5e, 0x00, 0x49, 0x0f, 0x8f, 0x7b,
-	0x5c, 0x52, 0xe5, 0xa0, 0x42, 0xb6, 0x4c, 0xc4, 0xc9, 0x87, 0x50, 0x5a, 0x30, 0x49, 0x63, 0x53,
-	0x8d, 0xe3, 0x5f, 0x30, 0x49, 0x5d, 0x2a, 0xa9, 0x83, 0x71, 0xfb, 0x1b, 0x68, 0x62, 0xa1, 0xfe,
-	0x11, 0x3f, 0x6f, 0x37, 0x6e, 0x66, 0x53, 0xad, 0x0f, 0xda, 0x89, 0xfa, 0x7d, 0x1d, 0x48, 0xb2,
-	0xff, 0xc1, 0x82, 0x56, 0x32, 0x81, 0x49, 0xde, 0x86, 0x92, 0x3c, 0x09, 0x74, 0xf2, 0x8d, 0x41,
-	0x23, 0x19, 0xfe, 0xfc, 0x24, 0x60, 0x0e, 0xc6, 0x48, 0x1f, 0xaa, 0x3c, 0x60, 0x21, 0x95, 0x3c,
-	0xcc, 0x17, 0xf1, 0xcc, 0x44, 0x9c, 0x98, 0xa3, 0xf8, 0x53, 0x1a, 0xd0, 0xa9, 0x27, 0x4f, 0xf0,
-	0xb8, 0x67, 0xf8, 0x43, 0x13, 0x71, 0x62, 0x8e, 0xbd, 0x80, 0xe6, 0x03, 0xcf, 0x77, 0x9f, 0x32,
-	0x1a, 0x9e, 0xb7, 0xf0, 0xf7, 0xa1, 0x2c, 0x24, 0x0d, 0xb5, 0xef, 0xe4, 0x29, 0x3a, 0x98, 0xdc,
-	0x98, 0xda, 0x74, 0xf4, 0x87, 0x7d, 0x07, 0x5a, 0xc9, 0x74, 0xa6, 0x0d, 0x67, 0xef, 0x6d, 0x02,
-	0xad, 0xfd, 0xe5, 0x22, 0xc8, 0xb8, 0xc0, 0x27, 0xd0, 0x4e, 0x61, 0xab, 0x52, 0x6b, 0xb7, 0x7d,
-	0x03, 0x36, 0xd2, 0x9e, 0x6b, 0xff, 0x65, 0x41, 0x47, 0x01, 0x87, 0xcb, 0xc5, 0x82, 0x86, 0x27,
-	0xb1, 0xd2, 0x55, 0x80, 0xa5, 0x60, 0xee, 0x58, 0x04, 0x74, 0xca, 0x8c, 0x7d, 0xd4, 0x14, 0x72,
-	0xa8, 0x00, 0x72, 0x0d, 0x9a, 0xf4, 0x35, 0xf5, 0xe6, 0xea, 0xe2, 0x32, 0x1c, 0xed, 0xc2, 0x8d,
-	0x18, 0xd6, 0x44, 0xe5, 0xac, 0x4a, 0xc7, 0xf3, 0x67, 0xb8, 0x55, 0xa2, 0x0b, 0x43, 0x30, 0x77,
-	0xa4, 0x21, 0xe5, 0xe6, 0x48, 0x61, 0x9a, 0xa1, 0xbd, 0x17, 0x67, 0xff, 0x5c, 0x13, 0x3e, 0x80,
-	0x06, 0x12, 0x26, 0xd4, 0x77, 0xbf, 0xf5, 0x5c, 0xf9, 0xd2, 0x98, 0xee, 0x25, 0x85, 0xee, 0x45,
-	0x20, 0xb9, 0x05, 0x9d, 0x24, 0xa7, 0x84, 0x5b, 0xd1, 0x06, 0x1d, 0x87, 0xe2, 0x01, 0xd8, 0x56,
-	0x2a, 0x5e, 0x4e, 0x38, 0x0d, 0xdd, 0xa8, 0x1f, 0xbf, 0x15, 0xa1, 0x9d, 0x02, 0x4d, 0x37, 0xce,
-	0x7d, 0x33, 0x5d, 0x87, 0x16, 0x12, 0xa7, 0xdc, 0xf7, 0xd9, 0x54, 0xbd, 0xc1, 0x84, 0x69, 0x4c,
-	0x53, 0xe1, 0xc3, 0x04, 0x26, 0x37, 0xa1, 0x3d, 0xe1, 0x5c, 0x0a, 0x19, 0xd2, 0x60, 0x1c, 0x9d,
-	0xa4, 0x22, 0x1e, 0xfa, 0x56, 0x1c, 0x30, 0x07, 0x49, 0xe9, 0xe2, 0x1b, 0xc8, 0xa7, 0xf3, 0x98,
-	0x5b, 0x42, 0x6e, 0x33, 0xc2, 0x53, 0x54, 0xf6, 0xdd, 0x0a, 0xb5, 0xac, 0xa9, 0x11, 0x1e, 0x51,
-	0xef, 0xe0, 0x4e, 0x96, 0x02, 0x7b, 0x54, 0x1f, 0x6c, 0xa5, 0x1e, 0x26, 0xa7, 0xec, 0x09, 0x47,
-	0x93, 0xc9, 0x47, 0x50, 0xd1, 0xb7, 0x5d, 0xf7, 0x22, 0x0e, 0x7b, 0xa7, 0xaf, 0xdf, 0x97, 0xfd,
-	0xe8, 0x7d, 0xd9, 0xdf, 0x37, 0xef, 0x4f, 0xc7, 0x10, 0xc9, 0x67, 0x50, 0xc7, 0x97, 0x58, 0xe0,
-	0xf9, 0x33, 0xe6, 0x76, 0xab, 0x38, 0xae, 0x97, 0x1b, 0xf7, 0x3c, 0x7a, 0x97, 0x3a, 0xa0, 0xe8,
-	0x07, 0xc8, 0x26, 0xf7, 0x60, 0x03, 0x07, 0xbf, 0x5a, 0xb2, 0xd0, 0x63, 0x6e, 0xb7, 0x76, 0xe6,
-	0x68, 0x9c, 0xec, 0x4b, 0x4d, 0x1f, 0xfc, 0x52, 0x84, 0x8d, 0xc7, 0xd4, 0x1d, 0x45, 0xa5, 0x91,
-	0x11, 0x40, 0x72, 0xa9, 0x92, 0x2b, 0xa9, 0xa2, 0x73, 0x77, 0x6d, 0xef, 0xea, 0x9a, 0xa8, 0xd9,
-	0x17, 0x43, 0xa8, 0x46, 0xbe, 0x4f, 0x7a, 0x29, 0xea, 0xca, 0xcd, 0xd2, 0x7b, 0xf7, 0xd4, 0x98,
-	0x11, 0x19, 0x01, 0x24, 0xce, 0x9e, 0xc9, 0x27, 0x77, 0x5f, 0x64, 0xf2, 0x39, 0xe5, 0x3a, 0x18,
-	0x42, 0x35, 0x72, 0xd9, 0x4c, 0x3e, 0x2b, 0xde, 0x9e, 0xc9, 0x27, 0x67, 0xcb, 0x43, 0xa8, 0x46,
-	0x1e, 0x95, 0x11, 0x59, 0xf1, 0xc9, 0x8c, 0x48, 0xce, 0xd4, 0x1e, 0x40, 0x2d, 0xb6, 0x27, 0x92,
-	0x66, 0xae, 0x1a, 0x59, 0xef, 0xca, 0xe9, 0x41, 0xad, 0x33, 0xf8, 0xb9, 0x00, 0xad, 0x67, 0xaf,
-	0x59, 0x38, 0xa7, 0x27, 0xff, 0xcb, 0x0a, 0xfe, 0x47, 0x79, 0xaa, 0xa6, 0x45, 0xcf, 0xed, 0x4c,
-	0xd3, 0x56, 0x1e, 0xf0, 0x99, 0xa6, 0xe5, 0xde, 0xe7, 0x4f, 0xa0, 0x9e, 0x7a, 0x31, 0x92, 0x4c,
-	0xea, 0xb9, 0xe7, 0x72, 0x6f, 0x6b, 0x5d, 0xd8, 0xb4, 0xee, 0x27, 0x0b, 0x3a, 0xf8, 0x4f, 0xe8,
-	0x50, 0xf2, 0x90, 0x25, 0xdd, 0xdb, 0x83, 0xb2, 0xd6, 0xbf, 0xbc, 0x72, 0xde, 0x4f, 0x55, 0x3e,
-	0xc5, 0x08, 0xec, 0x0b, 0xe4, 0x11, 0xd4, 0x62, 0x97, 0xcc, 0xb6, 0x6d, 0xc5, 0x50, 0xb3, 0x6d,
-	0x5b, 0x35, 0x56, 0xfb, 0xc2, 0xe0, 0x7b, 0x0b, 0x36, 0x53, 0xff, 0x82, 0x92, 0x34, 0x03, 0xb8,
-	0xbc, 0xe6, 0xbf, 0x15, 0xb9, 0x9e, 0x3e, 0x05, 0xff, 0xf8, 0xc7, 0xb5, 0x77, 0xe3, 0x3c, 0x54,
-	0x9d, 0xcc, 0x5e, 0xe9, 0xeb, 0x42, 0x30, 0x99, 0x54, 0xd0, 0x50, 0x3e, 0xfe, 0x3b, 0x00, 0x00,
-	0xff, 0xff, 0xe0, 0xc7, 0x2f, 0x50, 0x9b, 0x0f, 0x00, 0x00,
+func (m *DashboardResponse) GetUnsentOrders() []*UnsentOrderSummary {
+	if m != nil {
+		return m.UnsentOrders
+	}
+	return nil
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
-
-// KadInspectorClient is the client API for KadInspector service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type KadInspectorClient interface {
-	// CountNodes returns the number of nodes in the routing table
-	CountNodes(ctx context.Context, in *CountNodesRequest, opts ...grpc.CallOption) (*CountNodesResponse, error)
-	// PingNode sends a PING RPC to a node and returns its availability
-	PingNode(ctx context.Context, in *PingNodeRequest, opts ...grpc.CallOption) (*PingNodeResponse, error)
-	// LookupNode triggers a Kademlia FindNode and returns the response
-	LookupNode(ctx context.Context, in *LookupNodeRequest, opts ...grpc.CallOption) (*LookupNodeResponse, error)
-	// NodeInfo sends a PING RPC to a node and returns its local info
-	NodeInfo(ctx context.Context, in *NodeInfoRequest, opts ...grpc.CallOption) (*NodeInfoResponse, error)
-	// FindNear returns limit number of IDs "near" the Start ID
-	FindNear(ctx context.Context, in *FindNearRequest, opts ...grpc.CallOption) (*FindNearResponse, error)
-	// DumpNodes returns all the nodes in the node database
-	DumpNodes(ctx context.Context, in *DumpNodesRequest, opts ...grpc.CallOption) (*DumpNodesResponse, error)
+func (m *DashboardResponse) GetCorruptedPieces() int64 {
+	if m != nil {
+		return m.CorruptedPieces
+	}
+	return 0
 }
 
-type kadInspectorClient struct {
-	cc *grpc.ClientConn
+func (m *DashboardResponse) GetBandwidthToday() []*BandwidthSummary {
+	if m != nil {
+		return m.BandwidthToday
+	}
+	return nil
 }
 
-func NewKadInspectorClient(cc *grpc.ClientConn) KadInspectorClient {
-	return &kadInspectorClient{cc}
+func (m *DashboardResponse) GetDiskHealth() *DiskHealth {
+	if m != nil {
+		return m.DiskHealth
+	}
+	return nil
 }
 
-func (c *kadInspectorClient) CountNodes(ctx context.Context, in *CountNodesRequest, opts ...grpc.CallOption) (*CountNodesResponse, error) {
-	out := new(CountNodesResponse)
-	err := c.cc.Invoke(ctx, "/inspector.KadInspector/CountNodes", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *DashboardResponse) GetSatellites() []*SatelliteSummary {
+	if m != nil {
+		return m.Satellites
 	}
-	return out, nil
+	return nil
 }
 
-func (c *kadInspectorClient) PingNode(ctx context.Context, in *PingNodeRequest, opts ...grpc.CallOption) (*PingNodeResponse, error) {
-	out := new(PingNodeResponse)
-	err := c.cc.Invoke(ctx, "/inspector.KadInspector/PingNode", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+// AuditHistory
+type AuditHistoryRequest struct {
+	// either node_id or path selects the records
+	NodeId NodeID `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3,customtype=NodeID" json:"node_id"`
+	Path   []byte `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Limit  int32  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// dry_run selects the records of the audit dry run
+	DryRun               bool     `protobuf:"varint,4,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (c *kadInspectorClient) LookupNode(ctx context.Context, in *LookupNodeRequest, opts ...grpc.CallOption) (*LookupNodeResponse, error) {
+func (m *AuditHistoryRequest) Reset()         { *m = AuditHistoryRequest{} }
+func (m *AuditHistoryRequest) String() string { return proto.CompactTextString(m) }
+func (*AuditHistoryRequest) ProtoMessage()    {}
+func (*AuditHistoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a07d9034b2dd9d26, []int{32}
+}
+func (m *AuditHistoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditHistoryRequest.Unmarshal(m, b)
+}
+func (m *AuditHistoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditHistoryRequest.Marshal(b, m, deterministic)
+}
+func (m *AuditHistoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditHistoryRequest.Merge(m, src)
+}
+func (m *AuditHistoryRequest) XXX_Size() int {
+	return xxx_messageInfo_AuditHistoryRequest.Size(m)
+}
+func (m *AuditHistoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditHistoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditHistoryRequest proto.InternalMessageInfo
+
+func (m *AuditHistoryRequest) GetPath() []byte {
+	if m != nil {
+		return m.Path
+	}
+	return nil
+}
+
+func (m *AuditHistoryRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *AuditHistoryRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+type AuditHistoryResponse struct {
+	Records              []*AuditRecord `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *AuditHistoryResponse) Reset()         { *m = AuditHistoryResponse{} }
+func (m *AuditHistoryResponse) String() string { return proto.CompactTextString(m) }
+func (*AuditHistoryResponse) ProtoMessage()    {}
+func (*AuditHistoryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a07d9034b2dd9d26, []int{33}
+}
+func (m *AuditHistoryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditHistoryResponse.Unmarshal(m, b)
+}
+func (m *AuditHistoryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditHistoryResponse.Marshal(b, m, deterministic)
+}
+func (m *AuditHistoryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditHistoryResponse.Merge(m, src)
+}
+func (m *AuditHistoryResponse) XXX_Size() int {
+	return xxx_messageInfo_AuditHistoryResponse.Size(m)
+}
+func (m *AuditHistoryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditHistoryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditHistoryResponse proto.InternalMessageInfo
+
+func (m *AuditHistoryResponse) GetRecords() []*AuditRecord {
+	if m != nil {
+		return m.Records
+	}
+	return nil
+}
+
+type AuditRecord struct {
+	SegmentPath []byte              `protobuf:"bytes,1,opt,name=segment_path,json=segmentPath,proto3" json:"segment_path,omitempty"`
+	StripeIndex int64               `protobuf:"varint,2,opt,name=stripe_index,json=stripeIndex,proto3" json:"stripe_index,omitempty"`
+	NodeId      NodeID              `protobuf:"bytes,3,opt,name=node_id,json=nodeId,proto3,customtype=NodeID" json:"node_id"`
+	Outcome     AuditRecord_Outcome `protobuf:"varint,4,opt,name=outcome,proto3,enum=inspector.AuditRecord_Outcome" json:"outcome,omitempty"`
+	// reverify is set for the audits of the share a contained node owed
+	Reverify             bool                 `protobuf:"varint,5,opt,name=reverify,proto3" json:"reverify,omitempty"`
+	CreatedAt            *timestamp.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *AuditRecord) Reset()         { *m = AuditRecord{} }
+func (m *AuditRecord) String() string { return proto.CompactTextString(m) }
+func (*AuditRecord) ProtoMessage()    {}
+func (*AuditRecord) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a07d9034b2dd9d26, []int{34}
+}
+func (m *AuditRecord) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditRecord.Unmarshal(m, b)
+}
+func (m *AuditRecord) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditRecord.Marshal(b, m, deterministic)
+}
+func (m *AuditRecord) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditRecord.Merge(m, src)
+}
+func (m *AuditRecord) XXX_Size() int {
+	return xxx_messageInfo_AuditRecord.Size(m)
+}
+func (m *AuditRecord) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditRecord.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditRecord proto.InternalMessageInfo
+
+func (m *AuditRecord) GetSegmentPath() []byte {
+	if m != nil {
+		return m.SegmentPath
+	}
+	return nil
+}
+
+func (m *AuditRecord) GetStripeIndex() int64 {
+	if m != nil {
+		return m.StripeIndex
+	}
+	return 0
+}
+
+func (m *AuditRecord) GetOutcome() AuditRecord_Outcome {
+	if m != nil {
+		return m.Outcome
+	}
+	return AuditRecord_SUCCESS
+}
+
+func (m *AuditRecord) GetReverify() bool {
+	if m != nil {
+		return m.Reverify
+	}
+	return false
+}
+
+func (m *AuditRecord) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+// AuditReport
+type AuditReportRequest struct {
+	// days defaults to 30
+	Days int32 `protobuf:"varint,1,opt,name=days,proto3" json:"days,omitempty"`
+	// warning_score defaults to the configured audit.report-warning-score
+	WarningScore         float64  `protobuf:"fixed64,2,opt,name=warning_score,json=warningScore,proto3" json:"warning_score,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuditReportRequest) Reset()         { *m = AuditReportRequest{} }
+func (m *AuditReportRequest) String() string { return proto.CompactTextString(m) }
+func (*AuditReportRequest) ProtoMessage()    {}
+func (*AuditReportRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a07d9034b2dd9d26, []int{35}
+}
+func (m *AuditReportRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditReportRequest.Unmarshal(m, b)
+}
+func (m *AuditReportRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditReportRequest.Marshal(b, m, deterministic)
+}
+func (m *AuditReportRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditReportRequest.Merge(m, src)
+}
+func (m *AuditReportRequest) XXX_Size() int {
+	return xxx_messageInfo_AuditReportRequest.Size(m)
+}
+func (m *AuditReportRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditReportRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditReportRequest proto.InternalMessageInfo
+
+func (m *AuditReportRequest) GetDays() int32 {
+	if m != nil {
+		return m.Days
+	}
+	return 0
+}
+
+func (m *AuditReportRequest) GetWarningScore() float64 {
+	if m != nil {
+		return m.WarningScore
+	}
+	return 0
+}
+
+type AuditReportResponse struct {
+	Days            []*AuditDay    `protobuf:"bytes,1,rep,name=days,proto3" json:"days,omitempty"`
+	Outcomes        *AuditOutcomes `protobuf:"bytes,2,opt,name=outcomes,proto3" json:"outcomes,omitempty"`
+	SegmentsAudited int64          `protobuf:"varint,3,opt,name=segments_audited,json=segmentsAudited,proto3" json:"segments_audited,omitempty"`
+	TotalSegments   int64          `protobuf:"varint,4,opt,name=total_segments,json=totalSegments,proto3" json:"total_segments,omitempty"`
+	// coverage is the estimated share of the segments audited during the days
+	Coverage             float64           `protobuf:"fixed64,5,opt,name=coverage,proto3" json:"coverage,omitempty"`
+	NodesAtRisk          []*NodeAuditScore `protobuf:"bytes,6,rep,name=nodes_at_risk,json=nodesAtRisk,proto3" json:"nodes_at_risk,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *AuditReportResponse) Reset()         { *m = AuditReportResponse{} }
+func (m *AuditReportResponse) String() string { return proto.CompactTextString(m) }
+func (*AuditReportResponse) ProtoMessage()    {}
+func (*AuditReportResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a07d9034b2dd9d26, []int{36}
+}
+func (m *AuditReportResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditReportResponse.Unmarshal(m, b)
+}
+func (m *AuditReportResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditReportResponse.Marshal(b, m, deterministic)
+}
+func (m *AuditReportResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditReportResponse.Merge(m, src)
+}
+func (m *AuditReportResponse) XXX_Size() int {
+	return xxx_messageInfo_AuditReportResponse.Size(m)
+}
+func (m *AuditReportResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditReportResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditReportResponse proto.InternalMessageInfo
+
+func (m *AuditReportResponse) GetDays() []*AuditDay {
+	if m != nil {
+		return m.Days
+	}
+	return nil
+}
+
+func (m *AuditReportResponse) GetOutcomes() *AuditOutcomes {
+	if m != nil {
+		return m.Outcomes
+	}
+	return nil
+}
+
+func (m *AuditReportResponse) GetSegmentsAudited() int64 {
+	if m != nil {
+		return m.SegmentsAudited
+	}
+	return 0
+}
+
+func (m *AuditReportResponse) GetTotalSegments() int64 {
+	if m != nil {
+		return m.TotalSegments
+	}
+	return 0
+}
+
+func (m *AuditReportResponse) GetCoverage() float64 {
+	if m != nil {
+		return m.Coverage
+	}
+	return 0
+}
+
+func (m *AuditReportResponse) GetNodesAtRisk() []*NodeAuditScore {
+	if m != nil {
+		return m.NodesAtRisk
+	}
+	return nil
+}
+
+type AuditDay struct {
+	IntervalStart        *timestamp.Timestamp `protobuf:"bytes,1,opt,name=interval_start,json=intervalStart,proto3" json:"interval_start,omitempty"`
+	Outcomes             *AuditOutcomes       `protobuf:"bytes,2,opt,name=outcomes,proto3" json:"outcomes,omitempty"`
+	SegmentsAudited      int64                `protobuf:"varint,3,opt,name=segments_audited,json=segmentsAudited,proto3" json:"segments_audited,omitempty"`
+	TotalSegments        int64                `protobuf:"varint,4,opt,name=total_segments,json=totalSegments,proto3" json:"total_segments,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *AuditDay) Reset()         { *m = AuditDay{} }
+func (m *AuditDay) String() string { return proto.CompactTextString(m) }
+func (*AuditDay) ProtoMessage()    {}
+func (*AuditDay) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a07d9034b2dd9d26, []int{37}
+}
+func (m *AuditDay) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditDay.Unmarshal(m, b)
+}
+func (m *AuditDay) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditDay.Marshal(b, m, deterministic)
+}
+func (m *AuditDay) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditDay.Merge(m, src)
+}
+func (m *AuditDay) XXX_Size() int {
+	return xxx_messageInfo_AuditDay.Size(m)
+}
+func (m *AuditDay) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditDay.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditDay proto.InternalMessageInfo
+
+func (m *AuditDay) GetIntervalStart() *timestamp.Timestamp {
+	if m != nil {
+		return m.IntervalStart
+	}
+	return nil
+}
+
+func (m *AuditDay) GetOutcomes() *AuditOutcomes {
+	if m != nil {
+		return m.Outcomes
+	}
+	return nil
+}
+
+func (m *AuditDay) GetSegmentsAudited() int64 {
+	if m != nil {
+		return m.SegmentsAudited
+	}
+	return 0
+}
+
+func (m *AuditDay) GetTotalSegments() int64 {
+	if m != nil {
+		return m.TotalSegments
+	}
+	return 0
+}
+
+type AuditOutcomes struct {
+	Success              int64    `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Failure              int64    `protobuf:"varint,2,opt,name=failure,proto3" json:"failure,omitempty"`
+	Offline              int64    `protobuf:"varint,3,opt,name=offline,proto3" json:"offline,omitempty"`
+	Contained            int64    `protobuf:"varint,4,opt,name=contained,proto3" json:"contained,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuditOutcomes) Reset()         { *m = AuditOutcomes{} }
+func (m *AuditOutcomes) String() string { return proto.CompactTextString(m) }
+func (*AuditOutcomes) ProtoMessage()    {}
+func (*AuditOutcomes) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a07d9034b2dd9d26, []int{38}
+}
+func (m *AuditOutcomes) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditOutcomes.Unmarshal(m, b)
+}
+func (m *AuditOutcomes) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditOutcomes.Marshal(b, m, deterministic)
+}
+func (m *AuditOutcomes) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditOutcomes.Merge(m, src)
+}
+func (m *AuditOutcomes) XXX_Size() int {
+	return xxx_messageInfo_AuditOutcomes.Size(m)
+}
+func (m *AuditOutcomes) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditOutcomes.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditOutcomes proto.InternalMessageInfo
+
+func (m *AuditOutcomes) GetSuccess() int64 {
+	if m != nil {
+		return m.Success
+	}
+	return 0
+}
+
+func (m *AuditOutcomes) GetFailure() int64 {
+	if m != nil {
+		return m.Failure
+	}
+	return 0
+}
+
+func (m *AuditOutcomes) GetOffline() int64 {
+	if m != nil {
+		return m.Offline
+	}
+	return 0
+}
+
+func (m *AuditOutcomes) GetContained() int64 {
+	if m != nil {
+		return m.Contained
+	}
+	return 0
+}
+
+type NodeAuditScore struct {
+	NodeId               NodeID   `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3,customtype=NodeID" json:"node_id"`
+	AuditScore           float64  `protobuf:"fixed64,2,opt,name=audit_score,json=auditScore,proto3" json:"audit_score,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeAuditScore) Reset()         { *m = NodeAuditScore{} }
+func (m *NodeAuditScore) String() string { return proto.CompactTextString(m) }
+func (*NodeAuditScore) ProtoMessage()    {}
+func (*NodeAuditScore) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a07d9034b2dd9d26, []int{39}
+}
+func (m *NodeAuditScore) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeAuditScore.Unmarshal(m, b)
+}
+func (m *NodeAuditScore) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeAuditScore.Marshal(b, m, deterministic)
+}
+func (m *NodeAuditScore) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeAuditScore.Merge(m, src)
+}
+func (m *NodeAuditScore) XXX_Size() int {
+	return xxx_messageInfo_NodeAuditScore.Size(m)
+}
+func (m *NodeAuditScore) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeAuditScore.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeAuditScore proto.InternalMessageInfo
+
+func (m *NodeAuditScore) GetAuditScore() float64 {
+	if m != nil {
+		return m.AuditScore
+	}
+	return 0
+}
+
+// Disqualification
+type DisqualifiedRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DisqualifiedRequest) Reset()         { *m = DisqualifiedRequest{} }
+func (m *DisqualifiedRequest) String() string { return proto.CompactTextString(m) }
+func (*DisqualifiedRequest) ProtoMessage()    {}
+func (*DisqualifiedRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a07d9034b2dd9d26, []int{40}
+}
+func (m *DisqualifiedRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DisqualifiedRequest.Unmarshal(m, b)
+}
+func (m *DisqualifiedRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DisqualifiedRequest.Marshal(b, m, deterministic)
+}
+func (m *DisqualifiedRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DisqualifiedRequest.Merge(m, src)
+}
+func (m *DisqualifiedRequest) XXX_Size() int {
+	return xxx_messageInfo_DisqualifiedRequest.Size(m)
+}
+func (m *DisqualifiedRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DisqualifiedRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DisqualifiedRequest proto.InternalMessageInfo
+
+type DisqualifiedResponse struct {
+	Events               []*DisqualificationEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
+}
+
+func (m *DisqualifiedResponse) Reset()         { *m = DisqualifiedResponse{} }
+func (m *DisqualifiedResponse) String() string { return proto.CompactTextString(m) }
+func (*DisqualifiedResponse) ProtoMessage()    {}
+func (*DisqualifiedResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a07d9034b2dd9d26, []int{41}
+}
+func (m *DisqualifiedResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DisqualifiedResponse.Unmarshal(m, b)
+}
+func (m *DisqualifiedResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DisqualifiedResponse.Marshal(b, m, deterministic)
+}
+func (m *DisqualifiedResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DisqualifiedResponse.Merge(m, src)
+}
+func (m *DisqualifiedResponse) XXX_Size() int {
+	return xxx_messageInfo_DisqualifiedResponse.Size(m)
+}
+func (m *DisqualifiedResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DisqualifiedResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DisqualifiedResponse proto.InternalMessageInfo
+
+func (m *DisqualifiedResponse) GetEvents() []*DisqualificationEvent {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+type DisqualificationEventsRequest struct {
+	NodeId               NodeID   `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3,customtype=NodeID" json:"node_id"`
+	Limit                int32    `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DisqualificationEventsRequest) Reset()         { *m = DisqualificationEventsRequest{} }
+func (m *DisqualificationEventsRequest) String() string { return proto.CompactTextString(m) }
+func (*DisqualificationEventsRequest) ProtoMessage()    {}
+func (*DisqualificationEventsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a07d9034b2dd9d26, []int{42}
+}
+func (m *DisqualificationEventsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DisqualificationEventsRequest.Unmarshal(m, b)
+}
+func (m *DisqualificationEventsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DisqualificationEventsRequest.Marshal(b, m, deterministic)
+}
+func (m *DisqualificationEventsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DisqualificationEventsRequest.Merge(m, src)
+}
+func (m *DisqualificationEventsRequest) XXX_Size() int {
+	return xxx_messageInfo_DisqualificationEventsRequest.Size(m)
+}
+func (m *DisqualificationEventsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DisqualificationEventsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DisqualificationEventsRequest proto.InternalMessageInfo
+
+func (m *DisqualificationEventsRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type DisqualificationEventsResponse struct {
+	Events               []*DisqualificationEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
+}
+
+func (m *DisqualificationEventsResponse) Reset()         { *m = DisqualificationEventsResponse{} }
+func (m *DisqualificationEventsResponse) String() string { return proto.CompactTextString(m) }
+func (*DisqualificationEventsResponse) ProtoMessage()    {}
+func (*DisqualificationEventsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a07d9034b2dd9d26, []int{43}
+}
+func (m *DisqualificationEventsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DisqualificationEventsResponse.Unmarshal(m, b)
+}
+func (m *DisqualificationEventsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DisqualificationEventsResponse.Marshal(b, m, deterministic)
+}
+func (m *DisqualificationEventsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DisqualificationEventsResponse.Merge(m, src)
+}
+func (m *DisqualificationEventsResponse) XXX_Size() int {
+	return xxx_messageInfo_DisqualificationEventsResponse.Size(m)
+}
+func (m *DisqualificationEventsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DisqualificationEventsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DisqualificationEventsResponse proto.InternalMessageInfo
+
+func (m *DisqualificationEventsResponse) GetEvents() []*DisqualificationEvent {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+type DisqualificationEvent struct {
+	NodeId               NodeID               `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3,customtype=NodeID" json:"node_id"`
+	Reason               string               `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	Detail               string               `protobuf:"bytes,3,opt,name=detail,proto3" json:"detail,omitempty"`
+	CreatedAt            *timestamp.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *DisqualificationEvent) Reset()         { *m = DisqualificationEvent{} }
+func (m *DisqualificationEvent) String() string { return proto.CompactTextString(m) }
+func (*DisqualificationEvent) ProtoMessage()    {}
+func (*DisqualificationEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a07d9034b2dd9d26, []int{44}
+}
+func (m *DisqualificationEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DisqualificationEvent.Unmarshal(m, b)
+}
+func (m *DisqualificationEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DisqualificationEvent.Marshal(b, m, deterministic)
+}
+func (m *DisqualificationEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DisqualificationEvent.Merge(m, src)
+}
+func (m *DisqualificationEvent) XXX_Size() int {
+	return xxx_messageInfo_DisqualificationEvent.Size(m)
+}
+func (m *DisqualificationEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_DisqualificationEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DisqualificationEvent proto.InternalMessageInfo
+
+func (m *DisqualificationEvent) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *DisqualificationEvent) GetDetail() string {
+	if m != nil {
+		return m.Detail
+	}
+	return ""
+}
+
+func (m *DisqualificationEvent) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+type ReinstateRequest struct {
+	NodeId NodeID `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3,customtype=NodeID" json:"node_id"`
+	// detail records why the node is reinstated
+	Detail               string   `protobuf:"bytes,2,opt,name=detail,proto3" json:"detail,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReinstateRequest) Reset()         { *m = ReinstateRequest{} }
+func (m *ReinstateRequest) String() string { return proto.CompactTextString(m) }
+func (*ReinstateRequest) ProtoMessage()    {}
+func (*ReinstateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a07d9034b2dd9d26, []int{45}
+}
+func (m *ReinstateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReinstateRequest.Unmarshal(m, b)
+}
+func (m *ReinstateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReinstateRequest.Marshal(b, m, deterministic)
+}
+func (m *ReinstateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReinstateRequest.Merge(m, src)
+}
+func (m *ReinstateRequest) XXX_Size() int {
+	return xxx_messageInfo_ReinstateRequest.Size(m)
+}
+func (m *ReinstateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReinstateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReinstateRequest proto.InternalMessageInfo
+
+func (m *ReinstateRequest) GetDetail() string {
+	if m != nil {
+		return m.Detail
+	}
+	return ""
+}
+
+type ReinstateResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReinstateResponse) Reset()         { *m = ReinstateResponse{} }
+func (m *ReinstateResponse) String() string { return proto.CompactTextString(m) }
+func (*ReinstateResponse) ProtoMessage()    {}
+func (*ReinstateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a07d9034b2dd9d26, []int{46}
+}
+func (m *ReinstateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReinstateResponse.Unmarshal(m, b)
+}
+func (m *ReinstateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReinstateResponse.Marshal(b, m, deterministic)
+}
+func (m *ReinstateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReinstateResponse.Merge(m, src)
+}
+func (m *ReinstateResponse) XXX_Size() int {
+	return xxx_messageInfo_ReinstateResponse.Size(m)
+}
+func (m *ReinstateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReinstateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReinstateResponse proto.InternalMessageInfo
+
+// SegmentHealth
+type SegmentHealthRequest struct {
+	// path is either a segment path (project/segment/bucket/encrypted path)
+	// or an object path (project/bucket/encrypted path)
+	Path                 []byte   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SegmentHealthRequest) Reset()         { *m = SegmentHealthRequest{} }
+func (m *SegmentHealthRequest) String() string { return proto.CompactTextString(m) }
+func (*SegmentHealthRequest) ProtoMessage()    {}
+func (*SegmentHealthRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a07d9034b2dd9d26, []int{47}
+}
+func (m *SegmentHealthRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SegmentHealthRequest.Unmarshal(m, b)
+}
+func (m *SegmentHealthRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SegmentHealthRequest.Marshal(b, m, deterministic)
+}
+func (m *SegmentHealthRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SegmentHealthRequest.Merge(m, src)
+}
+func (m *SegmentHealthRequest) XXX_Size() int {
+	return xxx_messageInfo_SegmentHealthRequest.Size(m)
+}
+func (m *SegmentHealthRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SegmentHealthRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SegmentHealthRequest proto.InternalMessageInfo
+
+func (m *SegmentHealthRequest) GetPath() []byte {
+	if m != nil {
+		return m.Path
+	}
+	return nil
+}
+
+type SegmentHealthResponse struct {
+	Segments             []*SegmentHealth `protobuf:"bytes,1,rep,name=segments,proto3" json:"segments,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *SegmentHealthResponse) Reset()         { *m = SegmentHealthResponse{} }
+func (m *SegmentHealthResponse) String() string { return proto.CompactTextString(m) }
+func (*SegmentHealthResponse) ProtoMessage()    {}
+func (*SegmentHealthResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a07d9034b2dd9d26, []int{48}
+}
+func (m *SegmentHealthResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SegmentHealthResponse.Unmarshal(m, b)
+}
+func (m *SegmentHealthResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SegmentHealthResponse.Marshal(b, m, deterministic)
+}
+func (m *SegmentHealthResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SegmentHealthResponse.Merge(m, src)
+}
+func (m *SegmentHealthResponse) XXX_Size() int {
+	return xxx_messageInfo_SegmentHealthResponse.Size(m)
+}
+func (m *SegmentHealthResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SegmentHealthResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SegmentHealthResponse proto.InternalMessageInfo
+
+func (m *SegmentHealthResponse) GetSegments() []*SegmentHealth {
+	if m != nil {
+		return m.Segments
+	}
+	return nil
+}
+
+type SegmentHealth struct {
+	Path                 []byte         `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Inline               bool           `protobuf:"varint,2,opt,name=inline,proto3" json:"inline,omitempty"`
+	MinReq               int32          `protobuf:"varint,3,opt,name=min_req,json=minReq,proto3" json:"min_req,omitempty"`
+	RepairThreshold      int32          `protobuf:"varint,4,opt,name=repair_threshold,json=repairThreshold,proto3" json:"repair_threshold,omitempty"`
+	SuccessThreshold     int32          `protobuf:"varint,5,opt,name=success_threshold,json=successThreshold,proto3" json:"success_threshold,omitempty"`
+	Total                int32          `protobuf:"varint,6,opt,name=total,proto3" json:"total,omitempty"`
+	HealthyPieces        int32          `protobuf:"varint,7,opt,name=healthy_pieces,json=healthyPieces,proto3" json:"healthy_pieces,omitempty"`
+	Pieces               []*PieceHealth `protobuf:"bytes,8,rep,name=pieces,proto3" json:"pieces,omitempty"`
+	QueuedForRepair      bool           `protobuf:"varint,9,opt,name=queued_for_repair,json=queuedForRepair,proto3" json:"queued_for_repair,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *SegmentHealth) Reset()         { *m = SegmentHealth{} }
+func (m *SegmentHealth) String() string { return proto.CompactTextString(m) }
+func (*SegmentHealth) ProtoMessage()    {}
+func (*SegmentHealth) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a07d9034b2dd9d26, []int{49}
+}
+func (m *SegmentHealth) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SegmentHealth.Unmarshal(m, b)
+}
+func (m *SegmentHealth) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SegmentHealth.Marshal(b, m, deterministic)
+}
+func (m *SegmentHealth) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SegmentHealth.Merge(m, src)
+}
+func (m *SegmentHealth) XXX_Size() int {
+	return xxx_messageInfo_SegmentHealth.Size(m)
+}
+func (m *SegmentHealth) XXX_DiscardUnknown() {
+	xxx_messageInfo_SegmentHealth.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SegmentHealth proto.InternalMessageInfo
+
+func (m *SegmentHealth) GetPath() []byte {
+	if m != nil {
+		return m.Path
+	}
+	return nil
+}
+
+func (m *SegmentHealth) GetInline() bool {
+	if m != nil {
+		return m.Inline
+	}
+	return false
+}
+
+func (m *SegmentHealth) GetMinReq() int32 {
+	if m != nil {
+		return m.MinReq
+	}
+	return 0
+}
+
+func (m *SegmentHealth) GetRepairThreshold() int32 {
+	if m != nil {
+		return m.RepairThreshold
+	}
+	return 0
+}
+
+func (m *SegmentHealth) GetSuccessThreshold() int32 {
+	if m != nil {
+		return m.SuccessThreshold
+	}
+	return 0
+}
+
+func (m *SegmentHealth) GetTotal() int32 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+func (m *SegmentHealth) GetHealthyPieces() int32 {
+	if m != nil {
+		return m.HealthyPieces
+	}
+	return 0
+}
+
+func (m *SegmentHealth) GetPieces() []*PieceHealth {
+	if m != nil {
+		return m.Pieces
+	}
+	return nil
+}
+
+func (m *SegmentHealth) GetQueuedForRepair() bool {
+	if m != nil {
+		return m.QueuedForRepair
+	}
+	return false
+}
+
+type PieceHealth struct {
+	PieceNum             int32    `protobuf:"varint,1,opt,name=piece_num,json=pieceNum,proto3" json:"piece_num,omitempty"`
+	NodeId               NodeID   `protobuf:"bytes,2,opt,name=node_id,json=nodeId,proto3,customtype=NodeID" json:"node_id"`
+	Online               bool     `protobuf:"varint,3,opt,name=online,proto3" json:"online,omitempty"`
+	Reputable            bool     `protobuf:"varint,4,opt,name=reputable,proto3" json:"reputable,omitempty"`
+	AuditCount           int64    `protobuf:"varint,5,opt,name=audit_count,json=auditCount,proto3" json:"audit_count,omitempty"`
+	AuditSuccessRatio    float64  `protobuf:"fixed64,6,opt,name=audit_success_ratio,json=auditSuccessRatio,proto3" json:"audit_success_ratio,omitempty"`
+	UptimeCount          int64    `protobuf:"varint,7,opt,name=uptime_count,json=uptimeCount,proto3" json:"uptime_count,omitempty"`
+	UptimeRatio          float64  `protobuf:"fixed64,8,opt,name=uptime_ratio,json=uptimeRatio,proto3" json:"uptime_ratio,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PieceHealth) Reset()         { *m = PieceHealth{} }
+func (m *PieceHealth) String() string { return proto.CompactTextString(m) }
+func (*PieceHealth) ProtoMessage()    {}
+func (*PieceHealth) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a07d9034b2dd9d26, []int{50}
+}
+func (m *PieceHealth) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PieceHealth.Unmarshal(m, b)
+}
+func (m *PieceHealth) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PieceHealth.Marshal(b, m, deterministic)
+}
+func (m *PieceHealth) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PieceHealth.Merge(m, src)
+}
+func (m *PieceHealth) XXX_Size() int {
+	return xxx_messageInfo_PieceHealth.Size(m)
+}
+func (m *PieceHealth) XXX_DiscardUnknown() {
+	xxx_messageInfo_PieceHealth.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PieceHealth proto.InternalMessageInfo
+
+func (m *PieceHealth) GetPieceNum() int32 {
+	if m != nil {
+		return m.PieceNum
+	}
+	return 0
+}
+
+func (m *PieceHealth) GetOnline() bool {
+	if m != nil {
+		return m.Online
+	}
+	return false
+}
+
+func (m *PieceHealth) GetReputable() bool {
+	if m != nil {
+		return m.Reputable
+	}
+	return false
+}
+
+func (m *PieceHealth) GetAuditCount() int64 {
+	if m != nil {
+		return m.AuditCount
+	}
+	return 0
+}
+
+func (m *PieceHealth) GetAuditSuccessRatio() float64 {
+	if m != nil {
+		return m.AuditSuccessRatio
+	}
+	return 0
+}
+
+func (m *PieceHealth) GetUptimeCount() int64 {
+	if m != nil {
+		return m.UptimeCount
+	}
+	return 0
+}
+
+func (m *PieceHealth) GetUptimeRatio() float64 {
+	if m != nil {
+		return m.UptimeRatio
+	}
+	return 0
+}
+
+type SettlementBackoff struct {
+	SatelliteId          NodeID               `protobuf:"bytes,1,opt,name=satellite_id,json=satelliteId,proto3,customtype=NodeID" json:"satellite_id"`
+	Failures             int32                `protobuf:"varint,2,opt,name=failures,proto3" json:"failures,omitempty"`
+	NextRetry            *timestamp.Timestamp `protobuf:"bytes,3,opt,name=next_retry,json=nextRetry,proto3" json:"next_retry,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *SettlementBackoff) Reset()         { *m = SettlementBackoff{} }
+func (m *SettlementBackoff) String() string { return proto.CompactTextString(m) }
+func (*SettlementBackoff) ProtoMessage()    {}
+func (*SettlementBackoff) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a07d9034b2dd9d26, []int{51}
+}
+func (m *SettlementBackoff) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SettlementBackoff.Unmarshal(m, b)
+}
+func (m *SettlementBackoff) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SettlementBackoff.Marshal(b, m, deterministic)
+}
+func (m *SettlementBackoff) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SettlementBackoff.Merge(m, src)
+}
+func (m *SettlementBackoff) XXX_Size() int {
+	return xxx_messageInfo_SettlementBackoff.Size(m)
+}
+func (m *SettlementBackoff) XXX_DiscardUnknown() {
+	xxx_messageInfo_SettlementBackoff.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SettlementBackoff proto.InternalMessageInfo
+
+func (m *SettlementBackoff) GetFailures() int32 {
+	if m != nil {
+		return m.Failures
+	}
+	return 0
+}
+
+func (m *SettlementBackoff) GetNextRetry() *timestamp.Timestamp {
+	if m != nil {
+		return m.NextRetry
+	}
+	return nil
+}
+
+type UnsentOrderSummary struct {
+	SatelliteId          NodeID   `protobuf:"bytes,1,opt,name=satellite_id,json=satelliteId,proto3,customtype=NodeID" json:"satellite_id"`
+	OrderCount           int64    `protobuf:"varint,2,opt,name=order_count,json=orderCount,proto3" json:"order_count,omitempty"`
+	Amount               int64    `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UnsentOrderSummary) Reset()         { *m = UnsentOrderSummary{} }
+func (m *UnsentOrderSummary) String() string { return proto.CompactTextString(m) }
+func (*UnsentOrderSummary) ProtoMessage()    {}
+func (*UnsentOrderSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a07d9034b2dd9d26, []int{52}
+}
+func (m *UnsentOrderSummary) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnsentOrderSummary.Unmarshal(m, b)
+}
+func (m *UnsentOrderSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnsentOrderSummary.Marshal(b, m, deterministic)
+}
+func (m *UnsentOrderSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnsentOrderSummary.Merge(m, src)
+}
+func (m *UnsentOrderSummary) XXX_Size() int {
+	return xxx_messageInfo_UnsentOrderSummary.Size(m)
+}
+func (m *UnsentOrderSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnsentOrderSummary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnsentOrderSummary proto.InternalMessageInfo
+
+func (m *UnsentOrderSummary) GetOrderCount() int64 {
+	if m != nil {
+		return m.OrderCount
+	}
+	return 0
+}
+
+func (m *UnsentOrderSummary) GetAmount() int64 {
+	if m != nil {
+		return m.Amount
+	}
+	return 0
+}
+
+type BandwidthSummary struct {
+	SatelliteId          NodeID               `protobuf:"bytes,1,opt,name=satellite_id,json=satelliteId,proto3,customtype=NodeID" json:"satellite_id"`
+	Day                  *timestamp.Timestamp `protobuf:"bytes,2,opt,name=day,proto3" json:"day,omitempty"`
+	Ingress              int64                `protobuf:"varint,3,opt,name=ingress,proto3" json:"ingress,omitempty"`
+	Egress               int64                `protobuf:"varint,4,opt,name=egress,proto3" json:"egress,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *BandwidthSummary) Reset()         { *m = BandwidthSummary{} }
+func (m *BandwidthSummary) String() string { return proto.CompactTextString(m) }
+func (*BandwidthSummary) ProtoMessage()    {}
+func (*BandwidthSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a07d9034b2dd9d26, []int{53}
+}
+func (m *BandwidthSummary) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BandwidthSummary.Unmarshal(m, b)
+}
+func (m *BandwidthSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BandwidthSummary.Marshal(b, m, deterministic)
+}
+func (m *BandwidthSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BandwidthSummary.Merge(m, src)
+}
+func (m *BandwidthSummary) XXX_Size() int {
+	return xxx_messageInfo_BandwidthSummary.Size(m)
+}
+func (m *BandwidthSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_BandwidthSummary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BandwidthSummary proto.InternalMessageInfo
+
+func (m *BandwidthSummary) GetDay() *timestamp.Timestamp {
+	if m != nil {
+		return m.Day
+	}
+	return nil
+}
+
+func (m *BandwidthSummary) GetIngress() int64 {
+	if m != nil {
+		return m.Ingress
+	}
+	return 0
+}
+
+func (m *BandwidthSummary) GetEgress() int64 {
+	if m != nil {
+		return m.Egress
+	}
+	return 0
+}
+
+// SatelliteSummary is the state of the node on a trusted satellite, the
+// bandwidth is of the current month.
+type SatelliteSummary struct {
+	SatelliteId          NodeID               `protobuf:"bytes,1,opt,name=satellite_id,json=satelliteId,proto3,customtype=NodeID" json:"satellite_id"`
+	SpaceUsed            int64                `protobuf:"varint,2,opt,name=space_used,json=spaceUsed,proto3" json:"space_used,omitempty"`
+	Ingress              int64                `protobuf:"varint,3,opt,name=ingress,proto3" json:"ingress,omitempty"`
+	Egress               int64                `protobuf:"varint,4,opt,name=egress,proto3" json:"egress,omitempty"`
+	UnsentOrderCount     int64                `protobuf:"varint,5,opt,name=unsent_order_count,json=unsentOrderCount,proto3" json:"unsent_order_count,omitempty"`
+	UnsentAmount         int64                `protobuf:"varint,6,opt,name=unsent_amount,json=unsentAmount,proto3" json:"unsent_amount,omitempty"`
+	AuditCount           int64                `protobuf:"varint,7,opt,name=audit_count,json=auditCount,proto3" json:"audit_count,omitempty"`
+	AuditSuccessRatio    float64              `protobuf:"fixed64,8,opt,name=audit_success_ratio,json=auditSuccessRatio,proto3" json:"audit_success_ratio,omitempty"`
+	UptimeCount          int64                `protobuf:"varint,9,opt,name=uptime_count,json=uptimeCount,proto3" json:"uptime_count,omitempty"`
+	UptimeRatio          float64              `protobuf:"fixed64,10,opt,name=uptime_ratio,json=uptimeRatio,proto3" json:"uptime_ratio,omitempty"`
+	Disqualified         *timestamp.Timestamp `protobuf:"bytes,11,opt,name=disqualified,proto3" json:"disqualified,omitempty"`
+	ReputationUpdatedAt  *timestamp.Timestamp `protobuf:"bytes,12,opt,name=reputation_updated_at,json=reputationUpdatedAt,proto3" json:"reputation_updated_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *SatelliteSummary) Reset()         { *m = SatelliteSummary{} }
+func (m *SatelliteSummary) String() string { return proto.CompactTextString(m) }
+func (*SatelliteSummary) ProtoMessage()    {}
+func (*SatelliteSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a07d9034b2dd9d26, []int{54}
+}
+func (m *SatelliteSummary) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SatelliteSummary.Unmarshal(m, b)
+}
+func (m *SatelliteSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SatelliteSummary.Marshal(b, m, deterministic)
+}
+func (m *SatelliteSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SatelliteSummary.Merge(m, src)
+}
+func (m *SatelliteSummary) XXX_Size() int {
+	return xxx_messageInfo_SatelliteSummary.Size(m)
+}
+func (m *SatelliteSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_SatelliteSummary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SatelliteSummary proto.InternalMessageInfo
+
+func (m *SatelliteSummary) GetSpaceUsed() int64 {
+	if m != nil {
+		return m.SpaceUsed
+	}
+	return 0
+}
+
+func (m *SatelliteSummary) GetIngress() int64 {
+	if m != nil {
+		return m.Ingress
+	}
+	return 0
+}
+
+func (m *SatelliteSummary) GetEgress() int64 {
+	if m != nil {
+		return m.Egress
+	}
+	return 0
+}
+
+func (m *SatelliteSummary) GetUnsentOrderCount() int64 {
+	if m != nil {
+		return m.UnsentOrderCount
+	}
+	return 0
+}
+
+func (m *SatelliteSummary) GetUnsentAmount() int64 {
+	if m != nil {
+		return m.UnsentAmount
+	}
+	return 0
+}
+
+func (m *SatelliteSummary) GetAuditCount() int64 {
+	if m != nil {
+		return m.AuditCount
+	}
+	return 0
+}
+
+func (m *SatelliteSummary) GetAuditSuccessRatio() float64 {
+	if m != nil {
+		return m.AuditSuccessRatio
+	}
+	return 0
+}
+
+func (m *SatelliteSummary) GetUptimeCount() int64 {
+	if m != nil {
+		return m.UptimeCount
+	}
+	return 0
+}
+
+func (m *SatelliteSummary) GetUptimeRatio() float64 {
+	if m != nil {
+		return m.UptimeRatio
+	}
+	return 0
+}
+
+func (m *SatelliteSummary) GetDisqualified() *timestamp.Timestamp {
+	if m != nil {
+		return m.Disqualified
+	}
+	return nil
+}
+
+func (m *SatelliteSummary) GetReputationUpdatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.ReputationUpdatedAt
+	}
+	return nil
+}
+
+type DiskHealth struct {
+	Degraded             bool                 `protobuf:"varint,1,opt,name=degraded,proto3" json:"degraded,omitempty"`
+	Failing              bool                 `protobuf:"varint,2,opt,name=failing,proto3" json:"failing,omitempty"`
+	Reason               string               `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	LastChecked          *timestamp.Timestamp `protobuf:"bytes,4,opt,name=last_checked,json=lastChecked,proto3" json:"last_checked,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *DiskHealth) Reset()         { *m = DiskHealth{} }
+func (m *DiskHealth) String() string { return proto.CompactTextString(m) }
+func (*DiskHealth) ProtoMessage()    {}
+func (*DiskHealth) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a07d9034b2dd9d26, []int{55}
+}
+func (m *DiskHealth) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiskHealth.Unmarshal(m, b)
+}
+func (m *DiskHealth) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiskHealth.Marshal(b, m, deterministic)
+}
+func (m *DiskHealth) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiskHealth.Merge(m, src)
+}
+func (m *DiskHealth) XXX_Size() int {
+	return xxx_messageInfo_DiskHealth.Size(m)
+}
+func (m *DiskHealth) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiskHealth.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DiskHealth proto.InternalMessageInfo
+
+func (m *DiskHealth) GetDegraded() bool {
+	if m != nil {
+		return m.Degraded
+	}
+	return false
+}
+
+func (m *DiskHealth) GetFailing() bool {
+	if m != nil {
+		return m.Failing
+	}
+	return false
+}
+
+func (m *DiskHealth) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *DiskHealth) GetLastChecked() *timestamp.Timestamp {
+	if m != nil {
+		return m.LastChecked
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("inspector.AuditRecord_Outcome", AuditRecord_Outcome_name, AuditRecord_Outcome_value)
+	proto.RegisterType((*ListIrreparableSegmentsRequest)(nil), "inspector.ListIrreparableSegmentsRequest")
+	proto.RegisterType((*IrreparableSegment)(nil), "inspector.IrreparableSegment")
+	proto.RegisterType((*ListIrreparableSegmentsResponse)(nil), "inspector.ListIrreparableSegmentsResponse")
+	proto.RegisterType((*GetStatsRequest)(nil), "inspector.GetStatsRequest")
+	proto.RegisterType((*GetStatsResponse)(nil), "inspector.GetStatsResponse")
+	proto.RegisterType((*CreateStatsRequest)(nil), "inspector.CreateStatsRequest")
+	proto.RegisterType((*CreateStatsResponse)(nil), "inspector.CreateStatsResponse")
+	proto.RegisterType((*ReputationHistoryRequest)(nil), "inspector.ReputationHistoryRequest")
+	proto.RegisterType((*ReputationScore)(nil), "inspector.ReputationScore")
+	proto.RegisterType((*ReputationHistoryResponse)(nil), "inspector.ReputationHistoryResponse")
+	proto.RegisterType((*CountNodesResponse)(nil), "inspector.CountNodesResponse")
+	proto.RegisterType((*CountNodesRequest)(nil), "inspector.CountNodesRequest")
+	proto.RegisterType((*GetBucketsRequest)(nil), "inspector.GetBucketsRequest")
+	proto.RegisterType((*GetBucketsResponse)(nil), "inspector.GetBucketsResponse")
+	proto.RegisterType((*GetBucketRequest)(nil), "inspector.GetBucketRequest")
+	proto.RegisterType((*GetBucketResponse)(nil), "inspector.GetBucketResponse")
+	proto.RegisterType((*Bucket)(nil), "inspector.Bucket")
+	proto.RegisterType((*BucketList)(nil), "inspector.BucketList")
+	proto.RegisterType((*PingNodeRequest)(nil), "inspector.PingNodeRequest")
+	proto.RegisterType((*PingNodeResponse)(nil), "inspector.PingNodeResponse")
+	proto.RegisterType((*LookupNodeRequest)(nil), "inspector.LookupNodeRequest")
+	proto.RegisterType((*LookupNodeResponse)(nil), "inspector.LookupNodeResponse")
+	proto.RegisterType((*NodeInfoRequest)(nil), "inspector.NodeInfoRequest")
+	proto.RegisterType((*NodeInfoResponse)(nil), "inspector.NodeInfoResponse")
+	proto.RegisterType((*FindNearRequest)(nil), "inspector.FindNearRequest")
+	proto.RegisterType((*FindNearResponse)(nil), "inspector.FindNearResponse")
+	proto.RegisterType((*DumpNodesRequest)(nil), "inspector.DumpNodesRequest")
+	proto.RegisterType((*DumpNodesResponse)(nil), "inspector.DumpNodesResponse")
+	proto.RegisterType((*StatsRequest)(nil), "inspector.StatsRequest")
+	proto.RegisterType((*StatSummaryResponse)(nil), "inspector.StatSummaryResponse")
+	proto.RegisterType((*DashboardRequest)(nil), "inspector.DashboardRequest")
+	proto.RegisterType((*DashboardResponse)(nil), "inspector.DashboardResponse")
+	proto.RegisterType((*AuditHistoryRequest)(nil), "inspector.AuditHistoryRequest")
+	proto.RegisterType((*AuditHistoryResponse)(nil), "inspector.AuditHistoryResponse")
+	proto.RegisterType((*AuditRecord)(nil), "inspector.AuditRecord")
+	proto.RegisterType((*AuditReportRequest)(nil), "inspector.AuditReportRequest")
+	proto.RegisterType((*AuditReportResponse)(nil), "inspector.AuditReportResponse")
+	proto.RegisterType((*AuditDay)(nil), "inspector.AuditDay")
+	proto.RegisterType((*AuditOutcomes)(nil), "inspector.AuditOutcomes")
+	proto.RegisterType((*NodeAuditScore)(nil), "inspector.NodeAuditScore")
+	proto.RegisterType((*DisqualifiedRequest)(nil), "inspector.DisqualifiedRequest")
+	proto.RegisterType((*DisqualifiedResponse)(nil), "inspector.DisqualifiedResponse")
+	proto.RegisterType((*DisqualificationEventsRequest)(nil), "inspector.DisqualificationEventsRequest")
+	proto.RegisterType((*DisqualificationEventsResponse)(nil), "inspector.DisqualificationEventsResponse")
+	proto.RegisterType((*DisqualificationEvent)(nil), "inspector.DisqualificationEvent")
+	proto.RegisterType((*ReinstateRequest)(nil), "inspector.ReinstateRequest")
+	proto.RegisterType((*ReinstateResponse)(nil), "inspector.ReinstateResponse")
+	proto.RegisterType((*SegmentHealthRequest)(nil), "inspector.SegmentHealthRequest")
+	proto.RegisterType((*SegmentHealthResponse)(nil), "inspector.SegmentHealthResponse")
+	proto.RegisterType((*SegmentHealth)(nil), "inspector.SegmentHealth")
+	proto.RegisterType((*PieceHealth)(nil), "inspector.PieceHealth")
+	proto.RegisterType((*SettlementBackoff)(nil), "inspector.SettlementBackoff")
+	proto.RegisterType((*UnsentOrderSummary)(nil), "inspector.UnsentOrderSummary")
+	proto.RegisterType((*BandwidthSummary)(nil), "inspector.BandwidthSummary")
+	proto.RegisterType((*SatelliteSummary)(nil), "inspector.SatelliteSummary")
+	proto.RegisterType((*DiskHealth)(nil), "inspector.DiskHealth")
+}
+
+func init() { proto.RegisterFile("inspector.proto", fileDescriptor_a07d9034b2dd9d26) }
+
+var fileDescriptor_a07d9034b2dd9d26 = []byte{
+	// 2915 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x5a, 0xcd, 0x8f, 0x1b, 0xc7,
+	0xb1, 0xf7, 0x90, 0x4b, 0x2e, 0x59, 0xfc, 0xdc, 0xde, 0x95, 0x4c, 0x51, 0xda, 0x8f, 0x37, 0xf6,
+	0xb3, 0xd7, 0xb2, 0x41, 0xdb, 0x7c, 0x7a, 0x0f, 0xf6, 0x33, 0x9c, 0x60, 0xbf, 0x64, 0x2d, 0x2c,
+	0xed, 0x2a, 0x43, 0x09, 0x01, 0x0c, 0xc3, 0x44, 0x2f, 0xa7, 0x77, 0x77, 0xb0, 0xe4, 0xcc, 0xa8,
+	0xa7, 0x47, 0xd6, 0x22, 0x97, 0xe4, 0x96, 0x63, 0x80, 0x00, 0x81, 0x13, 0x20, 0x40, 0x82, 0x20,
+	0xa7, 0x20, 0xc8, 0x1f, 0x90, 0x73, 0x00, 0x9f, 0x72, 0xcb, 0x25, 0x07, 0x5f, 0xf2, 0x57, 0xe4,
+	0x16, 0xf4, 0xd7, 0x4c, 0xcf, 0x90, 0x14, 0x57, 0xb2, 0x03, 0xe4, 0xc6, 0xae, 0xfa, 0x75, 0x75,
+	0x75, 0x77, 0x55, 0xd7, 0xc7, 0x10, 0x5a, 0x9e, 0x1f, 0x85, 0x64, 0xc4, 0x02, 0xda, 0x0b, 0x69,
+	0xc0, 0x02, 0x54, 0x4d, 0x08, 0x5d, 0x38, 0x0b, 0xce, 0x02, 0x49, 0xee, 0x82, 0x1f, 0xb8, 0x44,
+	0xfd, 0x6e, 0x85, 0x81, 0xe7, 0x33, 0x42, 0xdd, 0x13, 0x45, 0xd8, 0x38, 0x0b, 0x82, 0xb3, 0x31,
+	0x79, 0x57, 0x8c, 0x4e, 0xe2, 0xd3, 0x77, 0xdd, 0x98, 0x62, 0xe6, 0x05, 0xbe, 0xe2, 0x6f, 0xe6,
+	0xf9, 0xcc, 0x9b, 0x90, 0x88, 0xe1, 0x49, 0x28, 0x01, 0xf6, 0x11, 0x6c, 0xdc, 0xf7, 0x22, 0x76,
+	0x48, 0x29, 0x09, 0x31, 0xc5, 0x27, 0x63, 0x32, 0x20, 0x67, 0x13, 0xe2, 0xb3, 0xc8, 0x21, 0x4f,
+	0x62, 0x12, 0x31, 0xb4, 0x06, 0xa5, 0xb1, 0x37, 0xf1, 0x58, 0xc7, 0xda, 0xb2, 0xb6, 0x4b, 0x8e,
+	0x1c, 0xa0, 0xeb, 0x50, 0x0e, 0x4e, 0x4f, 0x23, 0xc2, 0x3a, 0x05, 0x41, 0x56, 0x23, 0xfb, 0x8f,
+	0x05, 0x40, 0xd3, 0xc2, 0x10, 0x82, 0xa5, 0x10, 0xb3, 0x73, 0x21, 0xa3, 0xee, 0x88, 0xdf, 0xe8,
+	0x43, 0x68, 0x46, 0x92, 0x3d, 0x74, 0x09, 0xc3, 0xde, 0x58, 0x88, 0xaa, 0xf5, 0x51, 0x2f, 0xdd,
+	0xe5, 0x43, 0xf9, 0xcb, 0x69, 0x28, 0xe4, 0xbe, 0x00, 0xa2, 0x4d, 0xa8, 0x8d, 0x83, 0x88, 0x0d,
+	0x43, 0x8f, 0x8c, 0x48, 0xd4, 0x29, 0x0a, 0x15, 0x80, 0x93, 0x1e, 0x0a, 0x0a, 0xea, 0xc1, 0xea,
+	0x18, 0x47, 0x6c, 0xc8, 0x15, 0xf1, 0xe8, 0x10, 0x33, 0x46, 0x26, 0x21, 0xeb, 0x2c, 0x6d, 0x59,
+	0xdb, 0x45, 0x67, 0x85, 0xb3, 0x1c, 0xc1, 0xd9, 0x91, 0x0c, 0xf4, 0x1e, 0xac, 0x65, 0xa1, 0xc3,
+	0x51, 0x10, 0xfb, 0xac, 0x53, 0x12, 0x13, 0x10, 0x35, 0xc1, 0x7b, 0x9c, 0x83, 0xde, 0x80, 0x56,
+	0xaa, 0xc2, 0xd0, 0x8f, 0x27, 0x51, 0xa7, 0xbc, 0x55, 0xdc, 0x2e, 0x39, 0x8d, 0x44, 0x8d, 0xa3,
+	0x78, 0x12, 0xa1, 0x75, 0x00, 0xa1, 0x09, 0xa1, 0x34, 0xa0, 0x9d, 0xe5, 0x2d, 0x6b, 0xbb, 0xea,
+	0x54, 0x39, 0xe5, 0x80, 0x13, 0xec, 0xcf, 0x61, 0x73, 0xee, 0xf9, 0x47, 0x61, 0xe0, 0x47, 0x04,
+	0x7d, 0x08, 0x15, 0xb5, 0xfb, 0xa8, 0x63, 0x6d, 0x15, 0xb7, 0x6b, 0xfd, 0xf5, 0x5e, 0x6a, 0x3b,
+	0xd3, 0x33, 0x9d, 0x04, 0x6e, 0xff, 0x3f, 0xb4, 0x3e, 0x21, 0x6c, 0xc0, 0x70, 0x7a, 0x9d, 0x6f,
+	0xc2, 0x32, 0x37, 0xa8, 0xa1, 0xe7, 0xca, 0xcb, 0xd8, 0x6d, 0x7e, 0xfd, 0xcd, 0xe6, 0x2b, 0x7f,
+	0xff, 0x66, 0xb3, 0x7c, 0x14, 0xb8, 0xe4, 0x70, 0xdf, 0x29, 0x73, 0xf6, 0xa1, 0x6b, 0xff, 0xca,
+	0x82, 0x76, 0x3a, 0x59, 0xe9, 0xb2, 0x09, 0x35, 0x1c, 0xbb, 0x9e, 0x3e, 0x1e, 0x4b, 0x1c, 0x0f,
+	0x08, 0x92, 0x3c, 0x96, 0x04, 0x20, 0xcc, 0x50, 0xdc, 0xa8, 0xa5, 0x00, 0x0e, 0xa7, 0xa0, 0xff,
+	0x82, 0x7a, 0x1c, 0x72, 0x2b, 0x54, 0x22, 0x8a, 0x42, 0x44, 0x4d, 0xd2, 0xa4, 0x8c, 0x14, 0x22,
+	0x85, 0x2c, 0x09, 0x21, 0x0a, 0x22, 0xa4, 0xd8, 0xff, 0xb0, 0x00, 0xed, 0x51, 0x82, 0x19, 0x79,
+	0xa9, 0xcd, 0xe5, 0xf7, 0x51, 0x98, 0xda, 0x47, 0x0f, 0x56, 0x25, 0x20, 0x8a, 0x47, 0x23, 0x12,
+	0x45, 0x19, 0x6d, 0x57, 0x04, 0x6b, 0x20, 0x39, 0x79, 0x9d, 0x25, 0x70, 0x69, 0x7a, 0x5b, 0xef,
+	0xc1, 0x9a, 0x82, 0x64, 0x65, 0x2a, 0x1b, 0x93, 0x3c, 0x53, 0xa8, 0x7d, 0x0d, 0x56, 0x33, 0x9b,
+	0x94, 0x97, 0x60, 0xff, 0x10, 0x3a, 0x0e, 0x09, 0x63, 0x26, 0x1c, 0xfd, 0x9e, 0x17, 0xb1, 0x80,
+	0x5e, 0xbe, 0xf0, 0x09, 0x20, 0x58, 0x72, 0xf1, 0x65, 0xa4, 0xdc, 0x57, 0xfc, 0xb6, 0xbf, 0xb2,
+	0xa0, 0x95, 0x4a, 0x1e, 0x8c, 0x02, 0x4a, 0xd0, 0x0e, 0x34, 0x85, 0x0b, 0x3e, 0xc5, 0xe3, 0x61,
+	0xc4, 0x30, 0x95, 0x97, 0x5e, 0xeb, 0x77, 0x7b, 0xf2, 0x69, 0xe9, 0xe9, 0xa7, 0xa5, 0xf7, 0x48,
+	0x3f, 0x2d, 0x4e, 0x43, 0xcf, 0x18, 0xf0, 0x09, 0xe9, 0x61, 0x47, 0x5c, 0x62, 0xc6, 0x26, 0xe4,
+	0x1a, 0xe9, 0xe1, 0x49, 0x44, 0xd1, 0xbc, 0x70, 0x01, 0xb1, 0x7f, 0x5e, 0x80, 0x1b, 0x33, 0x36,
+	0x9d, 0x37, 0x4b, 0x3c, 0x0e, 0xcf, 0x71, 0xc7, 0x32, 0x56, 0xd8, 0xe1, 0x14, 0xee, 0x85, 0x12,
+	0x70, 0x42, 0x18, 0x56, 0x1a, 0x54, 0x05, 0x65, 0x97, 0x30, 0x6c, 0x28, 0x20, 0x05, 0x64, 0x14,
+	0x90, 0x12, 0x36, 0x41, 0x0d, 0xa5, 0x08, 0x69, 0x93, 0x20, 0x49, 0x42, 0xc6, 0xf7, 0xa0, 0xee,
+	0x7a, 0xd1, 0x93, 0x18, 0x8f, 0xbd, 0x53, 0x8f, 0xb8, 0x9d, 0xd2, 0xc2, 0x63, 0xca, 0xe0, 0x51,
+	0x1f, 0xca, 0x62, 0xf7, 0xf2, 0x1d, 0xe1, 0x33, 0x53, 0x27, 0xcf, 0x5d, 0x8a, 0xa3, 0x90, 0xf6,
+	0x6d, 0x40, 0xc2, 0x52, 0xf8, 0xdd, 0xa6, 0x4e, 0xba, 0x06, 0x25, 0xd3, 0x3d, 0xe5, 0xc0, 0x5e,
+	0x85, 0x15, 0x13, 0x2b, 0xcc, 0x85, 0x13, 0x3f, 0x21, 0x6c, 0x37, 0x1e, 0x5d, 0x90, 0xc4, 0x8b,
+	0xec, 0x7b, 0x80, 0x4c, 0x62, 0x2a, 0x95, 0x05, 0x0c, 0x8f, 0xb5, 0x54, 0x31, 0x40, 0xb7, 0xa0,
+	0xe8, 0xb9, 0xdc, 0x8a, 0x8a, 0xdb, 0xf5, 0x5d, 0x30, 0xec, 0x8c, 0x93, 0xed, 0x3e, 0xb4, 0x13,
+	0x49, 0xda, 0x42, 0x37, 0xa0, 0x30, 0xd7, 0x38, 0x0b, 0x9e, 0x6b, 0x3f, 0x36, 0x54, 0x4a, 0x16,
+	0x5f, 0x30, 0x09, 0x6d, 0x41, 0x89, 0xdb, 0xb5, 0x54, 0xa4, 0xd6, 0x87, 0x1e, 0x1f, 0xf5, 0x38,
+	0xc0, 0x91, 0x0c, 0xfb, 0x36, 0x94, 0xa5, 0xcc, 0x2b, 0x60, 0x7b, 0x00, 0x12, 0xcb, 0x9f, 0xe6,
+	0x14, 0x6f, 0xcd, 0xc3, 0x7f, 0x0a, 0xad, 0x87, 0x9e, 0x7f, 0x26, 0x48, 0x57, 0xdb, 0x25, 0xea,
+	0xc0, 0x32, 0x76, 0x5d, 0x4a, 0x22, 0xe9, 0x81, 0x55, 0x47, 0x0f, 0x6d, 0x1b, 0xda, 0xa9, 0x30,
+	0xb5, 0xfd, 0x26, 0x14, 0x82, 0x0b, 0x21, 0xad, 0xe2, 0x14, 0x82, 0x0b, 0xfb, 0x63, 0x58, 0xb9,
+	0x1f, 0x04, 0x17, 0x71, 0x68, 0x2e, 0xd9, 0x4c, 0x96, 0xac, 0x2e, 0x58, 0xe2, 0x73, 0x40, 0xe6,
+	0xf4, 0xe4, 0x8c, 0x97, 0xf8, 0x76, 0x94, 0x7f, 0x9b, 0xdb, 0x14, 0x74, 0xf4, 0x06, 0x2c, 0x4d,
+	0xb4, 0xf7, 0xf0, 0x28, 0x9d, 0xf0, 0x1f, 0x10, 0x86, 0x5d, 0xcc, 0xb0, 0x23, 0xf8, 0xf6, 0x17,
+	0xd0, 0x12, 0x1b, 0xf5, 0x4f, 0x83, 0xab, 0x9e, 0xc6, 0xdb, 0x59, 0x55, 0x6b, 0xfd, 0x95, 0x54,
+	0xfa, 0x8e, 0x64, 0xa4, 0xda, 0xff, 0xc2, 0x82, 0x76, 0xba, 0x80, 0x52, 0xde, 0x86, 0x25, 0x76,
+	0x19, 0x4a, 0xe5, 0x9b, 0xfd, 0x66, 0x3a, 0xfd, 0xd1, 0x65, 0x48, 0x1c, 0xc1, 0x43, 0x3d, 0xa8,
+	0x04, 0x21, 0xa1, 0x98, 0x05, 0x74, 0x7a, 0x13, 0xc7, 0x8a, 0xe3, 0x24, 0x18, 0x8e, 0x1f, 0xe1,
+	0x10, 0x8f, 0x3c, 0x76, 0xd9, 0x29, 0xe6, 0xf1, 0x7b, 0x8a, 0xe3, 0x24, 0x18, 0x7b, 0x02, 0xad,
+	0xbb, 0x9e, 0xef, 0x1e, 0x11, 0x4c, 0xaf, 0xba, 0xf1, 0xd7, 0xa1, 0x24, 0x1f, 0xd5, 0xc2, 0x4c,
+	0x88, 0x64, 0xa6, 0x29, 0x98, 0x0c, 0x3f, 0x72, 0x60, 0xdf, 0x81, 0x76, 0xba, 0x9c, 0x3a, 0x86,
+	0xc5, 0xb6, 0x8d, 0xa0, 0xbd, 0x1f, 0x4f, 0xc2, 0xcc, 0x2b, 0xf0, 0xbf, 0xb0, 0x62, 0xd0, 0xf2,
+	0xa2, 0xe6, 0x9a, 0x7d, 0x13, 0xea, 0x66, 0xf4, 0xb5, 0xff, 0x69, 0xc1, 0x2a, 0x27, 0x0c, 0xe2,
+	0xc9, 0x04, 0x1b, 0xaf, 0xf3, 0x3a, 0x40, 0x1c, 0x11, 0x77, 0x18, 0x85, 0x78, 0x44, 0xd4, 0xf3,
+	0x51, 0xe5, 0x94, 0x01, 0x27, 0xa0, 0x37, 0xa1, 0x85, 0x9f, 0x62, 0x6f, 0xcc, 0x53, 0x18, 0x85,
+	0x91, 0xf1, 0xb8, 0x99, 0x90, 0x25, 0x90, 0xbf, 0xd2, 0x5c, 0x8e, 0xe7, 0x9f, 0x09, 0x53, 0xd1,
+	0xa9, 0x43, 0x44, 0xdc, 0x43, 0x49, 0x12, 0xaf, 0x34, 0x87, 0x10, 0x89, 0x90, 0x51, 0x58, 0xac,
+	0x7e, 0x20, 0x01, 0xff, 0x0d, 0x4d, 0x01, 0x38, 0xc1, 0xbe, 0xfb, 0xa5, 0xe7, 0xb2, 0x73, 0x15,
+	0x7e, 0x1b, 0x9c, 0xba, 0xab, 0x89, 0xe8, 0x5d, 0x58, 0x4d, 0x75, 0x4a, 0xb1, 0x65, 0x81, 0x45,
+	0x09, 0x2b, 0x99, 0x20, 0x8e, 0x15, 0x47, 0xe7, 0x27, 0x01, 0xa6, 0xae, 0x3e, 0x8f, 0xaf, 0xcb,
+	0xb0, 0x62, 0x10, 0xd5, 0x69, 0x5c, 0x39, 0x42, 0xbf, 0x05, 0x6d, 0x01, 0x1c, 0x05, 0xbe, 0x4f,
+	0x46, 0xfc, 0xf1, 0x8f, 0xd4, 0xc1, 0xb4, 0x38, 0x7d, 0x2f, 0x25, 0xa3, 0xb7, 0x61, 0xe5, 0x24,
+	0x08, 0x58, 0xc4, 0x28, 0x0e, 0x87, 0xda, 0x93, 0x8a, 0xc2, 0xe9, 0xdb, 0x09, 0x43, 0x39, 0x12,
+	0x97, 0x2b, 0xe2, 0xb3, 0x8f, 0xc7, 0x09, 0x76, 0x49, 0x60, 0x5b, 0x9a, 0x6e, 0x40, 0xc9, 0xb3,
+	0x1c, 0xb4, 0x24, 0xa1, 0xe4, 0x59, 0x16, 0x7a, 0x47, 0x58, 0x32, 0x8b, 0xc4, 0x19, 0xd5, 0xfa,
+	0x1b, 0x46, 0xf4, 0x9a, 0x61, 0x13, 0x8e, 0x04, 0xa3, 0xf7, 0xa1, 0x2c, 0x43, 0xa8, 0xc8, 0x8c,
+	0x6b, 0xfd, 0x1b, 0x53, 0xe1, 0x72, 0x5f, 0x15, 0x34, 0x8e, 0x02, 0xa2, 0x8f, 0xa0, 0x26, 0x12,
+	0xea, 0xd0, 0xf3, 0xcf, 0x88, 0xdb, 0xa9, 0x2c, 0x0c, 0xb3, 0x22, 0xff, 0x7e, 0x28, 0xd0, 0xe8,
+	0x63, 0xa8, 0x8b, 0xc9, 0x4f, 0x62, 0x42, 0x79, 0x90, 0xae, 0x2e, 0x9c, 0x2d, 0x16, 0xfb, 0x81,
+	0x84, 0xa3, 0x07, 0xb0, 0x1a, 0x11, 0xc6, 0xc6, 0x44, 0x54, 0x2d, 0x27, 0x78, 0x74, 0xc1, 0xcb,
+	0x9e, 0x0e, 0x08, 0x0f, 0xb9, 0x65, 0x6e, 0x39, 0x41, 0xed, 0x4a, 0x90, 0x83, 0xa2, 0x3c, 0x29,
+	0x42, 0xbb, 0xd0, 0x88, 0xfd, 0x88, 0x8b, 0x0a, 0xa8, 0x4b, 0x68, 0xd4, 0xa9, 0x4d, 0xa5, 0xf7,
+	0x8f, 0x05, 0xff, 0x98, 0xb3, 0xf5, 0x11, 0xd6, 0xe3, 0x94, 0x26, 0xae, 0x68, 0x14, 0x50, 0x1a,
+	0x87, 0x8c, 0xb8, 0xba, 0x1e, 0xaa, 0x4b, 0x2b, 0x49, 0xe8, 0xaa, 0x28, 0xda, 0x87, 0x56, 0x62,
+	0xca, 0x43, 0x16, 0xb8, 0xf8, 0xb2, 0xd3, 0x10, 0x0b, 0xde, 0x34, 0x16, 0x4c, 0x4c, 0x5a, 0x2f,
+	0xd7, 0x4c, 0xe6, 0x3c, 0xe2, 0x53, 0xd0, 0xff, 0x41, 0xcd, 0xf5, 0xa2, 0x8b, 0xe1, 0x39, 0xc1,
+	0x63, 0x76, 0xde, 0x69, 0x8a, 0x13, 0xbc, 0x66, 0x48, 0xd8, 0xf7, 0xa2, 0x8b, 0x7b, 0x82, 0xe9,
+	0x80, 0x9b, 0xfc, 0x46, 0x1f, 0x01, 0x44, 0x98, 0x91, 0xf1, 0xd8, 0x63, 0x24, 0xea, 0xb4, 0xa6,
+	0x16, 0x1e, 0x68, 0xa6, 0x5e, 0xd8, 0x80, 0xdb, 0x3f, 0xb1, 0x60, 0x75, 0x87, 0xa7, 0x6b, 0xdf,
+	0x22, 0xdd, 0x15, 0x05, 0x68, 0xc1, 0x28, 0x40, 0x33, 0xcf, 0x6a, 0x52, 0xd9, 0xbe, 0x0a, 0xcb,
+	0x2e, 0xbd, 0x1c, 0xd2, 0xd8, 0x17, 0x5e, 0x51, 0x71, 0xca, 0x2e, 0xbd, 0x74, 0x62, 0xdf, 0xbe,
+	0x07, 0x6b, 0x59, 0x15, 0x94, 0x43, 0xbf, 0x07, 0xcb, 0x94, 0x8c, 0x02, 0xea, 0xea, 0xa7, 0xf2,
+	0xba, 0xb1, 0x2b, 0x31, 0xc3, 0x11, 0x6c, 0x47, 0xc3, 0xec, 0xbf, 0x16, 0xa0, 0x66, 0x30, 0xf8,
+	0xc3, 0xa6, 0x2b, 0x61, 0xa3, 0x4a, 0xae, 0x29, 0xda, 0x43, 0xae, 0x2b, 0x87, 0x30, 0xea, 0x85,
+	0x64, 0xe8, 0xf9, 0x2e, 0x79, 0xa6, 0x1e, 0x82, 0x9a, 0xa4, 0x1d, 0x72, 0x92, 0x79, 0x16, 0xc5,
+	0xe7, 0x9e, 0xc5, 0x07, 0xb0, 0x1c, 0xc4, 0x6c, 0x14, 0x4c, 0x88, 0xd8, 0x61, 0x33, 0xe3, 0xac,
+	0x86, 0x5e, 0xbd, 0x63, 0x89, 0x72, 0x34, 0x1c, 0x75, 0xa1, 0x42, 0xc9, 0x53, 0x42, 0xbd, 0xd3,
+	0x4b, 0xf1, 0x0e, 0x54, 0x9c, 0x64, 0x8c, 0x3e, 0x04, 0x18, 0x89, 0x62, 0xc5, 0x1d, 0x62, 0xd6,
+	0x29, 0x2f, 0x74, 0xac, 0xaa, 0x42, 0xef, 0x30, 0xfb, 0xfb, 0xb0, 0xac, 0x96, 0x42, 0x35, 0x58,
+	0x1e, 0x3c, 0xde, 0xdb, 0x3b, 0x18, 0x0c, 0xda, 0xaf, 0xf0, 0xc1, 0xdd, 0x9d, 0xc3, 0xfb, 0x8f,
+	0x9d, 0x83, 0xb6, 0xc5, 0x07, 0xc7, 0x77, 0xef, 0xde, 0x3f, 0x3c, 0x3a, 0x68, 0x17, 0x50, 0x03,
+	0xaa, 0x7b, 0xc7, 0x47, 0x8f, 0x76, 0x0e, 0x8f, 0x0e, 0xf6, 0xdb, 0x45, 0xfb, 0x01, 0x20, 0xa5,
+	0x77, 0x18, 0xd0, 0x24, 0xd3, 0xd4, 0x25, 0x8e, 0x95, 0x96, 0x38, 0xe8, 0x35, 0x68, 0x7c, 0x89,
+	0xa9, 0xef, 0xf9, 0x67, 0x99, 0x6a, 0xa4, 0xae, 0x88, 0xb2, 0xd8, 0xf8, 0x6d, 0x01, 0x56, 0x33,
+	0xf2, 0x92, 0xa7, 0x5b, 0x0b, 0xe4, 0xd7, 0xbc, 0x9a, 0x3f, 0xb5, 0x7d, 0x7c, 0xa9, 0x56, 0xb9,
+	0x03, 0x15, 0x75, 0x64, 0x3a, 0xa1, 0xe9, 0xe4, 0xc1, 0x6a, 0xc3, 0x91, 0x93, 0x20, 0xb9, 0x2b,
+	0xeb, 0xca, 0x7d, 0x28, 0x6a, 0x13, 0xe2, 0xaa, 0x18, 0xd7, 0xd2, 0xf4, 0x1d, 0x49, 0xe6, 0x61,
+	0x4c, 0xe4, 0xdf, 0x43, 0xcd, 0x50, 0xa1, 0xae, 0x21, 0xa8, 0xba, 0x85, 0xc0, 0xef, 0x6b, 0x14,
+	0x3c, 0x25, 0x14, 0x9f, 0x11, 0x71, 0x5f, 0x96, 0x93, 0x8c, 0xd1, 0xc7, 0xd0, 0x10, 0x61, 0x7c,
+	0x88, 0xd9, 0x90, 0x7a, 0xd1, 0x85, 0x2a, 0x3b, 0x6e, 0x18, 0x8a, 0x8a, 0xf4, 0x2b, 0x29, 0xd3,
+	0x9c, 0x9a, 0xc0, 0xef, 0x30, 0xc7, 0x8b, 0x2e, 0xec, 0xbf, 0x59, 0x50, 0xd1, 0xbb, 0xfe, 0x2e,
+	0x8a, 0xc4, 0xff, 0x90, 0x23, 0xb3, 0x7f, 0x04, 0x8d, 0xcc, 0x62, 0x3c, 0x8d, 0x56, 0xf5, 0xba,
+	0x4a, 0x5d, 0xf4, 0x90, 0x73, 0x4e, 0xb1, 0x37, 0x8e, 0xa9, 0x4e, 0x58, 0xf4, 0x90, 0x73, 0x82,
+	0xd3, 0xd3, 0xb1, 0xe7, 0x13, 0xa5, 0x8d, 0x1e, 0xa2, 0x5b, 0x50, 0x1d, 0x05, 0x3e, 0xc3, 0x9e,
+	0x4f, 0x5c, 0xa5, 0x40, 0x4a, 0xb0, 0x3f, 0x83, 0x66, 0xf6, 0xcc, 0x5f, 0xa2, 0xa3, 0x31, 0xa7,
+	0xc8, 0xe6, 0xcd, 0x84, 0x7d, 0xa3, 0xde, 0xd4, 0x49, 0xca, 0x43, 0x58, 0xcb, 0x92, 0x95, 0xad,
+	0x7f, 0x00, 0x65, 0xf2, 0xd4, 0xe8, 0x39, 0x6d, 0x65, 0x5f, 0x78, 0x35, 0x61, 0x24, 0x62, 0xf3,
+	0x01, 0x07, 0x3a, 0x0a, 0x6f, 0x7f, 0x01, 0xeb, 0x33, 0x01, 0x2f, 0xde, 0xa5, 0x49, 0x1e, 0xe8,
+	0x82, 0xf1, 0x40, 0xdb, 0x9f, 0xc1, 0xc6, 0x3c, 0xf9, 0xdf, 0x5a, 0xf7, 0x3f, 0x58, 0x70, 0x6d,
+	0x26, 0xe2, 0xea, 0x4a, 0x5f, 0x87, 0x32, 0x25, 0x38, 0x0a, 0x7c, 0x55, 0x75, 0xa9, 0x11, 0xa7,
+	0xab, 0x36, 0xa7, 0x4c, 0xcc, 0xd4, 0x28, 0xf7, 0x6e, 0x2e, 0xbd, 0xc8, 0xbb, 0x39, 0x80, 0xb6,
+	0x43, 0x3c, 0x3f, 0x62, 0x98, 0x91, 0x17, 0x3e, 0xdc, 0x54, 0x9f, 0x82, 0xa9, 0x0f, 0x6f, 0x09,
+	0x18, 0x42, 0x55, 0xcb, 0xe9, 0x36, 0xac, 0x29, 0x0f, 0x51, 0x91, 0x3d, 0x7d, 0x62, 0xf3, 0x7d,
+	0x5d, 0xfb, 0x01, 0x5c, 0xcb, 0x61, 0xd5, 0xb5, 0xdc, 0x99, 0x6a, 0x64, 0x76, 0x32, 0x29, 0x93,
+	0x39, 0x27, 0x41, 0xda, 0x7f, 0x29, 0x40, 0x23, 0xc3, 0x9b, 0xb5, 0x28, 0xdf, 0x8d, 0xe7, 0x0b,
+	0x87, 0x2b, 0xc8, 0xa0, 0x2d, 0x47, 0x3c, 0x9a, 0x4f, 0x3c, 0x7f, 0x48, 0xc9, 0x13, 0x15, 0xe5,
+	0xcb, 0x13, 0xcf, 0x77, 0xc8, 0x13, 0xfe, 0x72, 0xa8, 0x8e, 0x2f, 0x3b, 0xa7, 0x24, 0x3a, 0x0f,
+	0xc6, 0xd2, 0x1f, 0x4b, 0x4e, 0x4b, 0xd2, 0x1f, 0x69, 0x32, 0xcf, 0xae, 0x75, 0xc7, 0x2e, 0xc5,
+	0x96, 0x04, 0xb6, 0xad, 0x18, 0x29, 0x38, 0x69, 0x93, 0x94, 0xa5, 0xcd, 0x8a, 0x01, 0x7f, 0x7c,
+	0x64, 0xbe, 0x74, 0xa9, 0x73, 0xb4, 0x65, 0xc1, 0x6e, 0x28, 0x6a, 0xd2, 0xb6, 0x2e, 0x2b, 0x76,
+	0x65, 0x2a, 0x93, 0x10, 0x10, 0x75, 0x3a, 0x0a, 0x85, 0x6e, 0xc3, 0xca, 0x93, 0x98, 0xc4, 0xc4,
+	0x1d, 0x9e, 0x06, 0x54, 0x35, 0xbb, 0x45, 0x4e, 0x5b, 0x71, 0x5a, 0x92, 0x71, 0x37, 0xa0, 0xb2,
+	0xd3, 0x6d, 0xff, 0xa6, 0x00, 0x35, 0x43, 0x06, 0xba, 0x09, 0xd5, 0xa4, 0x77, 0xad, 0x42, 0x64,
+	0x25, 0x54, 0x6d, 0x6b, 0xd3, 0x8a, 0x0a, 0x8b, 0xac, 0x28, 0xf0, 0x93, 0x87, 0xae, 0xe2, 0xa8,
+	0x11, 0x7f, 0xe7, 0xa8, 0x68, 0x5a, 0x9d, 0x8c, 0x89, 0xca, 0xa3, 0x52, 0x42, 0xbe, 0xfd, 0x5a,
+	0x5a, 0xdc, 0x7e, 0x95, 0x9d, 0xe0, 0xb2, 0x78, 0xd5, 0x32, 0xed, 0xd7, 0xd9, 0x5d, 0xe5, 0xe5,
+	0xc5, 0x5d, 0xe5, 0xca, 0x74, 0x57, 0xf9, 0xd7, 0x16, 0xac, 0x4c, 0x65, 0xee, 0xe8, 0x7d, 0xa8,
+	0x27, 0x99, 0xe8, 0x7c, 0xb7, 0xaa, 0x25, 0x98, 0x43, 0x97, 0xc7, 0x5d, 0x15, 0x0a, 0x74, 0x83,
+	0x35, 0x19, 0x73, 0x7f, 0xf7, 0xc9, 0x33, 0xfe, 0x69, 0x82, 0x51, 0xdd, 0x57, 0x78, 0xae, 0xbf,
+	0x73, 0xb4, 0xc3, 0xc1, 0xf6, 0x8f, 0x2d, 0x40, 0xd3, 0x05, 0xc1, 0xcb, 0x28, 0xb8, 0x09, 0x35,
+	0x51, 0x72, 0x64, 0xfb, 0xdf, 0x82, 0x24, 0x4f, 0xeb, 0x3a, 0x94, 0xf1, 0xc4, 0x68, 0x79, 0xab,
+	0x91, 0xfd, 0x7b, 0x0b, 0xda, 0xf9, 0x12, 0xe1, 0x65, 0x14, 0x78, 0x07, 0x8a, 0xbc, 0xfe, 0x28,
+	0x2c, 0xdc, 0x3e, 0x87, 0xf1, 0x78, 0x9a, 0x2d, 0xfa, 0xf5, 0x90, 0xeb, 0x99, 0xa9, 0xf5, 0xd5,
+	0xc8, 0xfe, 0xd9, 0x12, 0xb4, 0xf3, 0x15, 0xc5, 0xcb, 0xe8, 0xb9, 0x0e, 0x20, 0x5a, 0x12, 0xc3,
+	0x38, 0x22, 0xae, 0x3a, 0xa7, 0xaa, 0xa0, 0x3c, 0x8e, 0x88, 0xfb, 0xe2, 0x8a, 0xa1, 0x77, 0x00,
+	0x99, 0x35, 0x5f, 0xc6, 0x03, 0xda, 0x46, 0x65, 0x27, 0xaf, 0xe1, 0xb5, 0xa4, 0x42, 0x54, 0xb7,
+	0x21, 0x3b, 0x10, 0xaa, 0x04, 0xdc, 0x99, 0x64, 0xbf, 0xb9, 0x98, 0xb6, 0x7f, 0x05, 0x6f, 0xaa,
+	0x5c, 0xd5, 0x9b, 0xaa, 0x8b, 0xbd, 0x09, 0xa6, 0xbc, 0x69, 0xaa, 0x21, 0x5e, 0x7b, 0xc1, 0x86,
+	0xf8, 0x11, 0x5c, 0xa3, 0x49, 0xdf, 0x7b, 0x18, 0x87, 0xae, 0x8e, 0x91, 0xf5, 0x85, 0x82, 0x56,
+	0xd3, 0x89, 0x8f, 0xe5, 0xbc, 0x1d, 0x66, 0xff, 0xd2, 0x02, 0x48, 0x6b, 0x53, 0xee, 0xa3, 0x2e,
+	0x39, 0xa3, 0xd8, 0x25, 0xae, 0xea, 0xac, 0x26, 0x63, 0x9d, 0xd9, 0x79, 0xfe, 0x99, 0x0a, 0x27,
+	0x7a, 0x68, 0x44, 0xf7, 0x62, 0x26, 0xba, 0xeb, 0xc6, 0xc2, 0xe8, 0x9c, 0x8c, 0x2e, 0x54, 0x6a,
+	0x77, 0x85, 0xc6, 0xc2, 0x9e, 0x84, 0xf7, 0xff, 0x5c, 0x84, 0xfa, 0xa7, 0xd8, 0x3d, 0xd4, 0xaf,
+	0x3d, 0x3a, 0x04, 0x48, 0xbb, 0xf5, 0xc8, 0x6c, 0x2d, 0x4c, 0x35, 0xf1, 0xbb, 0xeb, 0x73, 0xb8,
+	0x2a, 0xec, 0xee, 0x41, 0x45, 0x37, 0x94, 0x51, 0x37, 0x13, 0x50, 0x32, 0x2d, 0xeb, 0xee, 0xcd,
+	0x99, 0x3c, 0x25, 0xe4, 0x10, 0x20, 0x6d, 0x19, 0x67, 0xf4, 0x99, 0x6a, 0x44, 0x77, 0xd7, 0xe7,
+	0x70, 0x53, 0x7d, 0x74, 0xfb, 0x36, 0xa3, 0x4f, 0xae, 0x69, 0xdc, 0xbd, 0x39, 0x93, 0x97, 0x0a,
+	0xd1, 0xcd, 0xcf, 0x8c, 0x90, 0x5c, 0x03, 0xb6, 0x7b, 0x73, 0x26, 0x4f, 0x09, 0xb9, 0x0b, 0xd5,
+	0xa4, 0xef, 0x89, 0x4c, 0x64, 0xbe, 0x43, 0xda, 0xbd, 0x35, 0x9b, 0x29, 0xe5, 0xf4, 0xbf, 0x2a,
+	0x42, 0xfb, 0xf8, 0x29, 0xa1, 0x63, 0x7c, 0xf9, 0x6f, 0xb9, 0xc1, 0xef, 0x48, 0x4f, 0x7e, 0x68,
+	0xfa, 0x8b, 0x6e, 0xe6, 0xd0, 0x72, 0xdf, 0x88, 0xbb, 0x37, 0x67, 0xf2, 0x94, 0x90, 0xfb, 0x50,
+	0x33, 0x3e, 0x4a, 0xa2, 0x8c, 0xea, 0x53, 0x5f, 0x64, 0xbb, 0x1b, 0xf3, 0xd8, 0x4a, 0xda, 0x17,
+	0x3c, 0xdb, 0xcc, 0x7d, 0xd6, 0x43, 0xaf, 0xcd, 0xfc, 0xf4, 0x95, 0x6d, 0xfd, 0x74, 0x5f, 0x7f,
+	0x3e, 0x48, 0x5d, 0xcd, 0xef, 0x2c, 0x58, 0x15, 0x59, 0xcf, 0x80, 0x05, 0x94, 0xa4, 0xb7, 0xb3,
+	0x0b, 0x25, 0xa9, 0xff, 0xab, 0xb9, 0x46, 0xe5, 0x4c, 0xcd, 0x67, 0x74, 0x30, 0xed, 0x57, 0xd0,
+	0x3d, 0xa8, 0x26, 0xed, 0xdd, 0xec, 0xb5, 0xe4, 0x3a, 0xc1, 0xdd, 0x5b, 0xb3, 0x99, 0x5a, 0x52,
+	0xff, 0xa7, 0x16, 0xac, 0x19, 0x1f, 0xf2, 0x53, 0x35, 0x43, 0x78, 0x75, 0xce, 0xdf, 0x03, 0xd0,
+	0x5b, 0xa6, 0x97, 0x3d, 0xf7, 0x2f, 0x1c, 0xdd, 0xdb, 0x57, 0x81, 0xaa, 0x03, 0x23, 0xd0, 0x92,
+	0x0f, 0x64, 0xaa, 0x84, 0x93, 0x4f, 0xc0, 0x37, 0xe7, 0xa6, 0xed, 0x6a, 0xc1, 0xad, 0xf9, 0x00,
+	0xb5, 0xcc, 0x9f, 0x2c, 0x68, 0x8a, 0x32, 0x37, 0x5d, 0xe6, 0x18, 0xea, 0x66, 0x7f, 0x0d, 0x4d,
+	0x75, 0xa5, 0x72, 0x06, 0xb0, 0x39, 0x97, 0x9f, 0x5a, 0xaa, 0xd1, 0xc5, 0xc9, 0x58, 0xea, 0x74,
+	0xb7, 0xa8, 0xbb, 0x31, 0x8f, 0xad, 0x2d, 0xa9, 0x00, 0x37, 0xf2, 0xa5, 0x61, 0x46, 0xf9, 0x7d,
+	0x33, 0x78, 0x6d, 0xcc, 0x2c, 0x39, 0x89, 0x3b, 0x4b, 0xf9, 0x99, 0xf5, 0xf7, 0x04, 0xae, 0xcf,
+	0xae, 0x72, 0xd1, 0xf6, 0xa2, 0x6a, 0x36, 0xb9, 0xf7, 0xb7, 0xae, 0x80, 0x4c, 0x9f, 0x98, 0xa4,
+	0xea, 0xcb, 0xd8, 0x72, 0xbe, 0xc0, 0xec, 0xde, 0x9a, 0xcd, 0x94, 0x72, 0x76, 0x97, 0x3e, 0x2b,
+	0x84, 0x27, 0x27, 0x65, 0x11, 0xef, 0xfe, 0xe7, 0x5f, 0x03, 0x00, 0xd6, 0xd0, 0xc6, 0x92, 0xe4,
+	0x24, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// KadInspectorClient is the client API for KadInspector service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type KadInspectorClient interface {
+	// CountNodes returns the number of nodes in the routing table
+	CountNodes(ctx context.Context, in *CountNodesRequest, opts ...grpc.CallOption) (*CountNodesResponse, error)
+	// PingNode sends a PING RPC to a node and returns its availability
+	PingNode(ctx context.Context, in *PingNodeRequest, opts ...grpc.CallOption) (*PingNodeResponse, error)
+	// LookupNode triggers a Kademlia FindNode and returns the response
+	LookupNode(ctx context.Context, in *LookupNodeRequest, opts ...grpc.CallOption) (*LookupNodeResponse, error)
+	// NodeInfo sends a PING RPC to a node and returns its local info
+	NodeInfo(ctx context.Context, in *NodeInfoRequest, opts ...grpc.CallOption) (*NodeInfoResponse, error)
+	// FindNear returns limit number of IDs "near" the Start ID
+	FindNear(ctx context.Context, in *FindNearRequest, opts ...grpc.CallOption) (*FindNearResponse, error)
+	// DumpNodes returns all the nodes in the node database
+	DumpNodes(ctx context.Context, in *DumpNodesRequest, opts ...grpc.CallOption) (*DumpNodesResponse, error)
+}
+
+type kadInspectorClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewKadInspectorClient(cc *grpc.ClientConn) KadInspectorClient {
+	return &kadInspectorClient{cc}
+}
+
+func (c *kadInspectorClient) CountNodes(ctx context.Context, in *CountNodesRequest, opts ...grpc.CallOption) (*CountNodesResponse, error) {
+	out := new(CountNodesResponse)
+	err := c.cc.Invoke(ctx, "/inspector.KadInspector/CountNodes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kadInspectorClient) PingNode(ctx context.Context, in *PingNodeRequest, opts ...grpc.CallOption) (*PingNodeResponse, error) {
+	out := new(PingNodeResponse)
+	err := c.cc.Invoke(ctx, "/inspector.KadInspector/PingNode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kadInspectorClient) LookupNode(ctx context.Context, in *LookupNodeRequest, opts ...grpc.CallOption) (*LookupNodeResponse, error) {
 	out := new(LookupNodeResponse)
 	err := c.cc.Invoke(ctx, "/inspector.KadInspector/LookupNode", in, out, opts...)
 	if err != nil {
@@ -1681,6 +3404,8 @@ type OverlayInspectorClient interface {
 	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error)
 	// CreateStats creates a node with specified stats
 	CreateStats(ctx context.Context, in *CreateStatsRequest, opts ...grpc.CallOption) (*CreateStatsResponse, error)
+	// ReputationHistory returns the reputation of a node and its daily scores
+	ReputationHistory(ctx context.Context, in *ReputationHistoryRequest, opts ...grpc.CallOption) (*ReputationHistoryResponse, error)
 }
 
 type overlayInspectorClient struct {
@@ -1727,6 +3452,15 @@ func (c *overlayInspectorClient) CreateStats(ctx context.Context, in *CreateStat
 	return out, nil
 }
 
+func (c *overlayInspectorClient) ReputationHistory(ctx context.Context, in *ReputationHistoryRequest, opts ...grpc.CallOption) (*ReputationHistoryResponse, error) {
+	out := new(ReputationHistoryResponse)
+	err := c.cc.Invoke(ctx, "/inspector.OverlayInspector/ReputationHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // OverlayInspectorServer is the server API for OverlayInspector service.
 type OverlayInspectorServer interface {
 	// CountNodes returns the number of nodes in the cache
@@ -1737,6 +3471,8 @@ type OverlayInspectorServer interface {
 	GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error)
 	// CreateStats creates a node with specified stats
 	CreateStats(context.Context, *CreateStatsRequest) (*CreateStatsResponse, error)
+	// ReputationHistory returns the reputation of a node and its daily scores
+	ReputationHistory(context.Context, *ReputationHistoryRequest) (*ReputationHistoryResponse, error)
 }
 
 func RegisterOverlayInspectorServer(s *grpc.Server, srv OverlayInspectorServer) {
@@ -1815,6 +3551,24 @@ func _OverlayInspector_CreateStats_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OverlayInspector_ReputationHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReputationHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OverlayInspectorServer).ReputationHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/inspector.OverlayInspector/ReputationHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OverlayInspectorServer).ReputationHistory(ctx, req.(*ReputationHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _OverlayInspector_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "inspector.OverlayInspector",
 	HandlerType: (*OverlayInspectorServer)(nil),
@@ -1835,6 +3589,10 @@ var _OverlayInspector_serviceDesc = grpc.ServiceDesc{
 			MethodName: "CreateStats",
 			Handler:    _OverlayInspector_CreateStats_Handler,
 		},
+		{
+			MethodName: "ReputationHistory",
+			Handler:    _OverlayInspector_ReputationHistory_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "inspector.proto",
@@ -2006,3 +3764,306 @@ var _IrreparableInspector_serviceDesc = grpc.ServiceDesc{
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "inspector.proto",
 }
+
+// HealthInspectorClient is the client API for HealthInspector service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type HealthInspectorClient interface {
+	// SegmentHealth returns the health of a segment or of all the segments of an object
+	SegmentHealth(ctx context.Context, in *SegmentHealthRequest, opts ...grpc.CallOption) (*SegmentHealthResponse, error)
+}
+
+type healthInspectorClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewHealthInspectorClient(cc *grpc.ClientConn) HealthInspectorClient {
+	return &healthInspectorClient{cc}
+}
+
+func (c *healthInspectorClient) SegmentHealth(ctx context.Context, in *SegmentHealthRequest, opts ...grpc.CallOption) (*SegmentHealthResponse, error) {
+	out := new(SegmentHealthResponse)
+	err := c.cc.Invoke(ctx, "/inspector.HealthInspector/SegmentHealth", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// HealthInspectorServer is the server API for HealthInspector service.
+type HealthInspectorServer interface {
+	// SegmentHealth returns the health of a segment or of all the segments of an object
+	SegmentHealth(context.Context, *SegmentHealthRequest) (*SegmentHealthResponse, error)
+}
+
+func RegisterHealthInspectorServer(s *grpc.Server, srv HealthInspectorServer) {
+	s.RegisterService(&_HealthInspector_serviceDesc, srv)
+}
+
+func _HealthInspector_SegmentHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SegmentHealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HealthInspectorServer).SegmentHealth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/inspector.HealthInspector/SegmentHealth",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HealthInspectorServer).SegmentHealth(ctx, req.(*SegmentHealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _HealthInspector_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "inspector.HealthInspector",
+	HandlerType: (*HealthInspectorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SegmentHealth",
+			Handler:    _HealthInspector_SegmentHealth_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "inspector.proto",
+}
+
+// AuditInspectorClient is the client API for AuditInspector service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AuditInspectorClient interface {
+	// AuditHistory returns the most recent audit records of a node or of a segment
+	AuditHistory(ctx context.Context, in *AuditHistoryRequest, opts ...grpc.CallOption) (*AuditHistoryResponse, error)
+	// AuditReport returns the audits per day, the estimated coverage and the nodes nearing disqualification
+	AuditReport(ctx context.Context, in *AuditReportRequest, opts ...grpc.CallOption) (*AuditReportResponse, error)
+}
+
+type auditInspectorClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAuditInspectorClient(cc *grpc.ClientConn) AuditInspectorClient {
+	return &auditInspectorClient{cc}
+}
+
+func (c *auditInspectorClient) AuditHistory(ctx context.Context, in *AuditHistoryRequest, opts ...grpc.CallOption) (*AuditHistoryResponse, error) {
+	out := new(AuditHistoryResponse)
+	err := c.cc.Invoke(ctx, "/inspector.AuditInspector/AuditHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *auditInspectorClient) AuditReport(ctx context.Context, in *AuditReportRequest, opts ...grpc.CallOption) (*AuditReportResponse, error) {
+	out := new(AuditReportResponse)
+	err := c.cc.Invoke(ctx, "/inspector.AuditInspector/AuditReport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AuditInspectorServer is the server API for AuditInspector service.
+type AuditInspectorServer interface {
+	// AuditHistory returns the most recent audit records of a node or of a segment
+	AuditHistory(context.Context, *AuditHistoryRequest) (*AuditHistoryResponse, error)
+	// AuditReport returns the audits per day, the estimated coverage and the nodes nearing disqualification
+	AuditReport(context.Context, *AuditReportRequest) (*AuditReportResponse, error)
+}
+
+func RegisterAuditInspectorServer(s *grpc.Server, srv AuditInspectorServer) {
+	s.RegisterService(&_AuditInspector_serviceDesc, srv)
+}
+
+func _AuditInspector_AuditHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuditHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuditInspectorServer).AuditHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/inspector.AuditInspector/AuditHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuditInspectorServer).AuditHistory(ctx, req.(*AuditHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuditInspector_AuditReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuditReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuditInspectorServer).AuditReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/inspector.AuditInspector/AuditReport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuditInspectorServer).AuditReport(ctx, req.(*AuditReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _AuditInspector_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "inspector.AuditInspector",
+	HandlerType: (*AuditInspectorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AuditHistory",
+			Handler:    _AuditInspector_AuditHistory_Handler,
+		},
+		{
+			MethodName: "AuditReport",
+			Handler:    _AuditInspector_AuditReport_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "inspector.proto",
+}
+
+// DisqualificationInspectorClient is the client API for DisqualificationInspector service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type DisqualificationInspectorClient interface {
+	// Disqualified returns the latest event of every disqualified node
+	Disqualified(ctx context.Context, in *DisqualifiedRequest, opts ...grpc.CallOption) (*DisqualifiedResponse, error)
+	// DisqualificationEvents returns the most recent disqualifications and reinstatements of a node
+	DisqualificationEvents(ctx context.Context, in *DisqualificationEventsRequest, opts ...grpc.CallOption) (*DisqualificationEventsResponse, error)
+	// Reinstate clears the disqualification of a node
+	Reinstate(ctx context.Context, in *ReinstateRequest, opts ...grpc.CallOption) (*ReinstateResponse, error)
+}
+
+type disqualificationInspectorClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewDisqualificationInspectorClient(cc *grpc.ClientConn) DisqualificationInspectorClient {
+	return &disqualificationInspectorClient{cc}
+}
+
+func (c *disqualificationInspectorClient) Disqualified(ctx context.Context, in *DisqualifiedRequest, opts ...grpc.CallOption) (*DisqualifiedResponse, error) {
+	out := new(DisqualifiedResponse)
+	err := c.cc.Invoke(ctx, "/inspector.DisqualificationInspector/Disqualified", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *disqualificationInspectorClient) DisqualificationEvents(ctx context.Context, in *DisqualificationEventsRequest, opts ...grpc.CallOption) (*DisqualificationEventsResponse, error) {
+	out := new(DisqualificationEventsResponse)
+	err := c.cc.Invoke(ctx, "/inspector.DisqualificationInspector/DisqualificationEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *disqualificationInspectorClient) Reinstate(ctx context.Context, in *ReinstateRequest, opts ...grpc.CallOption) (*ReinstateResponse, error) {
+	out := new(ReinstateResponse)
+	err := c.cc.Invoke(ctx, "/inspector.DisqualificationInspector/Reinstate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DisqualificationInspectorServer is the server API for DisqualificationInspector service.
+type DisqualificationInspectorServer interface {
+	// Disqualified returns the latest event of every disqualified node
+	Disqualified(context.Context, *DisqualifiedRequest) (*DisqualifiedResponse, error)
+	// DisqualificationEvents returns the most recent disqualifications and reinstatements of a node
+	DisqualificationEvents(context.Context, *DisqualificationEventsRequest) (*DisqualificationEventsResponse, error)
+	// Reinstate clears the disqualification of a node
+	Reinstate(context.Context, *ReinstateRequest) (*ReinstateResponse, error)
+}
+
+func RegisterDisqualificationInspectorServer(s *grpc.Server, srv DisqualificationInspectorServer) {
+	s.RegisterService(&_DisqualificationInspector_serviceDesc, srv)
+}
+
+func _DisqualificationInspector_Disqualified_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisqualifiedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DisqualificationInspectorServer).Disqualified(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/inspector.DisqualificationInspector/Disqualified",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DisqualificationInspectorServer).Disqualified(ctx, req.(*DisqualifiedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DisqualificationInspector_DisqualificationEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisqualificationEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DisqualificationInspectorServer).DisqualificationEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/inspector.DisqualificationInspector/DisqualificationEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DisqualificationInspectorServer).DisqualificationEvents(ctx, req.(*DisqualificationEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DisqualificationInspector_Reinstate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReinstateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DisqualificationInspectorServer).Reinstate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/inspector.DisqualificationInspector/Reinstate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DisqualificationInspectorServer).Reinstate(ctx, req.(*ReinstateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _DisqualificationInspector_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "inspector.DisqualificationInspector",
+	HandlerType: (*DisqualificationInspectorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Disqualified",
+			Handler:    _DisqualificationInspector_Disqualified_Handler,
+		},
+		{
+			MethodName: "DisqualificationEvents",
+			Handler:    _DisqualificationInspector_DisqualificationEvents_Handler,
+		},
+		{
+			MethodName: "Reinstate",
+			Handler:    _DisqualificationInspector_Reinstate_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "inspector.proto",
+}
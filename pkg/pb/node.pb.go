@@ -7,6 +7,7 @@ import (
 	fmt "fmt"
 	_ "github.com/gogo/protobuf/gogoproto"
 	proto "github.com/gogo/protobuf/proto"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
 	math "math"
 )
 
@@ -524,6 +525,69 @@ func (m *NodeRestrictions) GetFreeDisk() int64 {
 	return 0
 }
 
+// NodeVersion contains version information about a node
+type NodeVersion struct {
+	Version              string               `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	CommitHash           string               `protobuf:"bytes,2,opt,name=commit_hash,json=commitHash,proto3" json:"commit_hash,omitempty"`
+	Timestamp            *timestamp.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Release              bool                 `protobuf:"varint,4,opt,name=release,proto3" json:"release,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *NodeVersion) Reset()         { *m = NodeVersion{} }
+func (m *NodeVersion) String() string { return proto.CompactTextString(m) }
+func (*NodeVersion) ProtoMessage()    {}
+func (*NodeVersion) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0c843d59d2d938e7, []int{7}
+}
+func (m *NodeVersion) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeVersion.Unmarshal(m, b)
+}
+func (m *NodeVersion) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeVersion.Marshal(b, m, deterministic)
+}
+func (m *NodeVersion) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeVersion.Merge(m, src)
+}
+func (m *NodeVersion) XXX_Size() int {
+	return xxx_messageInfo_NodeVersion.Size(m)
+}
+func (m *NodeVersion) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeVersion.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeVersion proto.InternalMessageInfo
+
+func (m *NodeVersion) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *NodeVersion) GetCommitHash() string {
+	if m != nil {
+		return m.CommitHash
+	}
+	return ""
+}
+
+func (m *NodeVersion) GetTimestamp() *timestamp.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+func (m *NodeVersion) GetRelease() bool {
+	if m != nil {
+		return m.Release
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterEnum("node.NodeType", NodeType_name, NodeType_value)
 	proto.RegisterEnum("node.NodeTransport", NodeTransport_name, NodeTransport_value)
@@ -534,53 +598,60 @@ func init() {
 	proto.RegisterType((*NodeCapacity)(nil), "node.NodeCapacity")
 	proto.RegisterType((*NodeMetadata)(nil), "node.NodeMetadata")
 	proto.RegisterType((*NodeRestrictions)(nil), "node.NodeRestrictions")
+	proto.RegisterType((*NodeVersion)(nil), "node.NodeVersion")
 }
 
 func init() { proto.RegisterFile("node.proto", fileDescriptor_0c843d59d2d938e7) }
 
 var fileDescriptor_0c843d59d2d938e7 = []byte{
-	// 683 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x94, 0xc1, 0x4e, 0xdb, 0x4a,
-	0x14, 0x86, 0x49, 0x6c, 0x9c, 0xf8, 0xd8, 0xc9, 0x35, 0x03, 0x42, 0xd6, 0xbd, 0xba, 0x97, 0x10,
-	0x74, 0xd5, 0x88, 0x4a, 0x29, 0xa5, 0x2b, 0xaa, 0x6e, 0x92, 0x80, 0x50, 0x54, 0x37, 0x89, 0x26,
-	0x86, 0x05, 0x1b, 0x6b, 0x88, 0xa7, 0x74, 0x44, 0x88, 0x2d, 0x7b, 0x2c, 0x94, 0x37, 0xec, 0xa2,
-	0x4f, 0xd0, 0x05, 0xaf, 0xd0, 0x57, 0xa8, 0x66, 0xc6, 0x26, 0x76, 0xab, 0x2e, 0x2a, 0x75, 0x17,
-	0xff, 0xff, 0x37, 0xe7, 0x4c, 0xce, 0x7f, 0x6c, 0x80, 0x55, 0x14, 0xd2, 0x7e, 0x9c, 0x44, 0x3c,
-	0x42, 0xba, 0xf8, 0xfd, 0x37, 0xdc, 0x45, 0x77, 0x91, 0x52, 0xba, 0xdf, 0x34, 0xd0, 0x27, 0x51,
-	0x48, 0xd1, 0x7f, 0x50, 0x67, 0xa1, 0x5b, 0xeb, 0xd4, 0x7a, 0xf6, 0xb0, 0xfd, 0xf9, 0xe9, 0x60,
-	0xeb, 0xeb, 0xd3, 0x81, 0x21, 0x9c, 0xf1, 0x39, 0xae, 0xb3, 0x10, 0xbd, 0x84, 0x06, 0x09, 0xc3,
-	0x84, 0xa6, 0xa9, 0x5b, 0xef, 0xd4, 0x7a, 0xd6, 0xe9, 0x4e, 0x5f, 0x16, 0x16, 0xc8, 0x40, 0x19,
-	0xb8, 0x20, 0x50, 0x17, 0x74, 0xbe, 0x8e, 0xa9, 0xab, 0x75, 0x6a, 0xbd, 0xf6, 0x69, 0x7b, 0x43,
-	0xfa, 0xeb, 0x98, 0x62, 0xe9, 0xa1, 0xb7, 0x60, 0x27, 0x34, 0xe5, 0x09, 0x5b, 0x70, 0x16, 0xad,
-	0x52, 0x57, 0x97, 0x55, 0xf7, 0x37, 0x2c, 0x2e, 0xb9, 0xb8, 0xc2, 0xa2, 0x57, 0x00, 0x09, 0x8d,
-	0x33, 0x4e, 0xc4, 0xa3, 0xbb, 0x2d, 0x4f, 0xfe, 0xb5, 0x39, 0x39, 0xe7, 0x84, 0xa7, 0xb8, 0x84,
-	0xa0, 0x3e, 0x34, 0x1f, 0x28, 0x27, 0x21, 0xe1, 0xc4, 0x35, 0x24, 0x8e, 0x36, 0xf8, 0x87, 0xdc,
-	0xc1, 0xcf, 0x0c, 0x3a, 0x04, 0x7b, 0x49, 0x38, 0x5d, 0x2d, 0xd6, 0xc1, 0x92, 0xa5, 0xdc, 0x6d,
-	0x74, 0xb4, 0x9e, 0x86, 0xad, 0x5c, 0xf3, 0x58, 0xca, 0xd1, 0x11, 0xb4, 0x48, 0x16, 0x32, 0x1e,
-	0xa4, 0xd9, 0x62, 0x21, 0xc6, 0xd2, 0xec, 0xd4, 0x7a, 0x4d, 0x6c, 0x4b, 0x71, 0xae, 0x34, 0xb4,
-	0x0b, 0xdb, 0x2c, 0x0d, 0xb2, 0xd8, 0x35, 0xa5, 0xa9, 0xb3, 0xf4, 0x2a, 0x46, 0xff, 0x43, 0x3b,
-	0x8b, 0x43, 0xc2, 0x69, 0x90, 0xd7, 0x73, 0x41, 0xba, 0x2d, 0xa5, 0x7a, 0x4a, 0x44, 0x27, 0xb0,
-	0x97, 0x63, 0xd5, 0x3e, 0x96, 0x84, 0x91, 0xf2, 0x06, 0xe5, 0x6e, 0x47, 0x90, 0x97, 0x08, 0xb2,
-	0x98, 0xb3, 0x07, 0xea, 0xda, 0xea, 0x4a, 0x4a, 0xbc, 0x92, 0x5a, 0xf7, 0x06, 0xac, 0x52, 0x66,
-	0xe8, 0x35, 0x98, 0x3c, 0x21, 0xab, 0x34, 0x8e, 0x12, 0x2e, 0xe3, 0x6f, 0x9f, 0xee, 0x96, 0xf2,
-	0x2a, 0x2c, 0xbc, 0xa1, 0x90, 0x5b, 0x5d, 0x05, 0xf3, 0x39, 0xf7, 0xee, 0x97, 0x3a, 0x98, 0xcf,
-	0x01, 0xa0, 0x17, 0xd0, 0x10, 0x85, 0x82, 0x5f, 0xee, 0x95, 0x21, 0xec, 0x71, 0x88, 0xfe, 0x05,
-	0x28, 0xa6, 0x7d, 0x76, 0x22, 0x6b, 0x6a, 0xd8, 0xcc, 0x95, 0xb3, 0x13, 0xd4, 0x87, 0xdd, 0xca,
-	0x04, 0x82, 0x44, 0x84, 0x2a, 0x97, 0xab, 0x86, 0x77, 0xca, 0xf3, 0xc6, 0xc2, 0x10, 0xe1, 0xa9,
-	0xff, 0x9f, 0x83, 0xba, 0x04, 0x2d, 0xa5, 0x29, 0xe4, 0x00, 0x2c, 0x55, 0x72, 0x11, 0x65, 0x2b,
-	0x2e, 0x37, 0x48, 0xc3, 0x20, 0xa5, 0x91, 0x50, 0x7e, 0xee, 0xa9, 0x40, 0x43, 0x82, 0x95, 0x9e,
-	0x8a, 0xdf, 0xf4, 0x54, 0x60, 0x43, 0x82, 0x79, 0x4f, 0x85, 0xc8, 0x3c, 0x25, 0x52, 0xad, 0xd9,
-	0x94, 0x28, 0x52, 0x5e, 0xb9, 0x68, 0xf7, 0x1d, 0xd8, 0x62, 0x52, 0xd3, 0x98, 0x26, 0x84, 0x47,
-	0x09, 0xda, 0x83, 0x6d, 0xfa, 0x40, 0xd8, 0x52, 0x8e, 0xd3, 0xc4, 0xea, 0x01, 0xed, 0x83, 0xf1,
-	0x48, 0x96, 0x4b, 0xca, 0xf3, 0x34, 0xf2, 0xa7, 0x2e, 0x56, 0xa7, 0x47, 0x24, 0x26, 0x0b, 0xc6,
-	0xd7, 0x62, 0xed, 0x3e, 0x26, 0x94, 0x06, 0xb7, 0x64, 0x15, 0x3e, 0xb2, 0x90, 0x7f, 0x92, 0x65,
-	0x34, 0xdc, 0x12, 0xea, 0xb0, 0x10, 0xd1, 0x3f, 0x60, 0x4a, 0x2c, 0x64, 0xe9, 0x7d, 0x9e, 0x45,
-	0x53, 0x08, 0xe7, 0x2c, 0xbd, 0x2f, 0x6e, 0x54, 0xbc, 0x31, 0xbf, 0x79, 0xa3, 0x6b, 0x70, 0x7e,
-	0x7c, 0xb1, 0xff, 0xc4, 0xad, 0x8e, 0x27, 0xd0, 0x2c, 0x3e, 0x2e, 0xc8, 0x82, 0xc6, 0x78, 0x72,
-	0x3d, 0xf0, 0xc6, 0xe7, 0xce, 0x16, 0x6a, 0x81, 0x39, 0x1f, 0xf8, 0x17, 0x9e, 0x37, 0xf6, 0x2f,
-	0x9c, 0x9a, 0xf0, 0xe6, 0xfe, 0x14, 0x0f, 0x2e, 0x2f, 0x9c, 0x3a, 0x02, 0x30, 0xae, 0x66, 0xde,
-	0x78, 0xf2, 0xde, 0xd1, 0x04, 0x37, 0x9c, 0x4e, 0xfd, 0xb9, 0x8f, 0x07, 0x33, 0x47, 0x3f, 0x3e,
-	0x84, 0x56, 0x65, 0xf9, 0x91, 0x03, 0xb6, 0x3f, 0x9a, 0x05, 0xbe, 0x37, 0x0f, 0x2e, 0xf1, 0x6c,
-	0xe4, 0x6c, 0x0d, 0xf5, 0x9b, 0x7a, 0x7c, 0x7b, 0x6b, 0xc8, 0x8f, 0xe8, 0x9b, 0xef, 0x01, 0x00,
-	0x00, 0xff, 0xff, 0x66, 0xf8, 0x7b, 0x9e, 0x64, 0x05, 0x00, 0x00,
+	// 773 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x94, 0xcf, 0x6e, 0xea, 0x46,
+	0x14, 0xc6, 0x63, 0xec, 0x00, 0x3e, 0x06, 0xea, 0x3b, 0xb9, 0xba, 0xb2, 0x52, 0xb5, 0x70, 0x89,
+	0xaa, 0xa2, 0x54, 0x22, 0x29, 0xdd, 0x34, 0x55, 0x37, 0x40, 0xa2, 0x14, 0x95, 0x02, 0x1a, 0x9c,
+	0x2c, 0xb2, 0xb1, 0x06, 0x3c, 0x81, 0x51, 0x00, 0x5b, 0x9e, 0x71, 0x23, 0xde, 0xa5, 0x0f, 0xd4,
+	0x45, 0x9f, 0xa0, 0x8b, 0xbc, 0x42, 0x5f, 0xa1, 0x9a, 0x19, 0x9b, 0x3f, 0xad, 0xba, 0xa8, 0x74,
+	0x77, 0xcc, 0xf7, 0xfd, 0x7c, 0xce, 0xe1, 0x9c, 0x33, 0x03, 0xb0, 0x89, 0x42, 0xda, 0x8e, 0x93,
+	0x48, 0x44, 0xc8, 0x92, 0xbf, 0xcf, 0x61, 0x11, 0x2d, 0x22, 0xad, 0x9c, 0xd7, 0x17, 0x51, 0xb4,
+	0x58, 0xd1, 0x2b, 0x75, 0x9a, 0xa5, 0xcf, 0x57, 0x82, 0xad, 0x29, 0x17, 0x64, 0x1d, 0x6b, 0xa0,
+	0xf9, 0x97, 0x09, 0xd6, 0x28, 0x0a, 0x29, 0xfa, 0x12, 0x0a, 0x2c, 0xf4, 0x8c, 0x86, 0xd1, 0xaa,
+	0xf4, 0x6a, 0xbf, 0xbf, 0xd5, 0x4f, 0xfe, 0x7c, 0xab, 0x17, 0xa5, 0x33, 0xb8, 0xc5, 0x05, 0x16,
+	0xa2, 0x6f, 0xa0, 0x44, 0xc2, 0x30, 0xa1, 0x9c, 0x7b, 0x85, 0x86, 0xd1, 0x72, 0x3a, 0xef, 0xda,
+	0x2a, 0xb3, 0x44, 0xba, 0xda, 0xc0, 0x39, 0x81, 0x9a, 0x60, 0x89, 0x6d, 0x4c, 0x3d, 0xb3, 0x61,
+	0xb4, 0x6a, 0x9d, 0xda, 0x9e, 0xf4, 0xb7, 0x31, 0xc5, 0xca, 0x43, 0x3f, 0x40, 0x25, 0xa1, 0x5c,
+	0x24, 0x6c, 0x2e, 0x58, 0xb4, 0xe1, 0x9e, 0xa5, 0xa2, 0x7e, 0xd8, 0xb3, 0xf8, 0xc0, 0xc5, 0x47,
+	0x2c, 0xba, 0x02, 0x48, 0x68, 0x9c, 0x0a, 0x22, 0x8f, 0xde, 0xa9, 0xfa, 0xf2, 0xb3, 0xfd, 0x97,
+	0x53, 0x41, 0x04, 0xc7, 0x07, 0x08, 0x6a, 0x43, 0x79, 0x4d, 0x05, 0x09, 0x89, 0x20, 0x5e, 0x51,
+	0xe1, 0x68, 0x8f, 0xff, 0x92, 0x39, 0x78, 0xc7, 0xa0, 0x8f, 0x50, 0x59, 0x11, 0x41, 0x37, 0xf3,
+	0x6d, 0xb0, 0x62, 0x5c, 0x78, 0xa5, 0x86, 0xd9, 0x32, 0xb1, 0x93, 0x69, 0x43, 0xc6, 0x05, 0xba,
+	0x80, 0x2a, 0x49, 0x43, 0x26, 0x02, 0x9e, 0xce, 0xe7, 0xb2, 0x2d, 0xe5, 0x86, 0xd1, 0x2a, 0xe3,
+	0x8a, 0x12, 0xa7, 0x5a, 0x43, 0x67, 0x70, 0xca, 0x78, 0x90, 0xc6, 0x9e, 0xad, 0x4c, 0x8b, 0xf1,
+	0x87, 0x18, 0x7d, 0x05, 0xb5, 0x34, 0x0e, 0x89, 0xa0, 0x41, 0x16, 0xcf, 0x03, 0xe5, 0x56, 0xb5,
+	0x3a, 0xd4, 0x22, 0xba, 0x86, 0xf7, 0x19, 0x76, 0x9c, 0xc7, 0x51, 0x30, 0xd2, 0x5e, 0xf7, 0x30,
+	0xdb, 0x05, 0x64, 0x21, 0x82, 0x34, 0x96, 0x83, 0xf6, 0x2a, 0xba, 0x24, 0x2d, 0x3e, 0x28, 0xad,
+	0xf9, 0x04, 0xce, 0xc1, 0xcc, 0xd0, 0xb7, 0x60, 0x8b, 0x84, 0x6c, 0x78, 0x1c, 0x25, 0x42, 0x8d,
+	0xbf, 0xd6, 0x39, 0x3b, 0x98, 0x57, 0x6e, 0xe1, 0x3d, 0x85, 0xbc, 0xe3, 0x55, 0xb0, 0x77, 0x73,
+	0x6f, 0xfe, 0x51, 0x00, 0x7b, 0x37, 0x00, 0xf4, 0x35, 0x94, 0x64, 0xa0, 0xe0, 0x3f, 0xf7, 0xaa,
+	0x28, 0xed, 0x41, 0x88, 0xbe, 0x00, 0xc8, 0xbb, 0x7d, 0x73, 0xad, 0x62, 0x9a, 0xd8, 0xce, 0x94,
+	0x9b, 0x6b, 0xd4, 0x86, 0xb3, 0xa3, 0x0e, 0x04, 0x89, 0x1c, 0xaa, 0x5a, 0x2e, 0x03, 0xbf, 0x3b,
+	0xec, 0x37, 0x96, 0x86, 0x1c, 0x9e, 0xfe, 0xff, 0x19, 0x68, 0x29, 0xd0, 0xd1, 0x9a, 0x46, 0xea,
+	0xe0, 0xe8, 0x90, 0xf3, 0x28, 0xdd, 0x08, 0xb5, 0x41, 0x26, 0x06, 0x25, 0xf5, 0xa5, 0xf2, 0xef,
+	0x9c, 0x1a, 0x2c, 0x2a, 0xf0, 0x28, 0xa7, 0xe6, 0xf7, 0x39, 0x35, 0x58, 0x52, 0x60, 0x96, 0x53,
+	0x23, 0x6a, 0x9e, 0x0a, 0x39, 0x8e, 0x59, 0x56, 0x28, 0xd2, 0xde, 0x61, 0xd0, 0xe6, 0x8f, 0x50,
+	0x91, 0x9d, 0x1a, 0xc7, 0x34, 0x21, 0x22, 0x4a, 0xd0, 0x7b, 0x38, 0xa5, 0x6b, 0xc2, 0x56, 0xaa,
+	0x9d, 0x36, 0xd6, 0x07, 0xf4, 0x01, 0x8a, 0xaf, 0x64, 0xb5, 0xa2, 0x22, 0x9b, 0x46, 0x76, 0x6a,
+	0x62, 0xfd, 0x75, 0x9f, 0xc4, 0x64, 0xce, 0xc4, 0x56, 0xae, 0xdd, 0x73, 0x42, 0x69, 0x30, 0x23,
+	0x9b, 0xf0, 0x95, 0x85, 0x62, 0xa9, 0xc2, 0x98, 0xb8, 0x2a, 0xd5, 0x5e, 0x2e, 0xa2, 0xcf, 0xc1,
+	0x56, 0x58, 0xc8, 0xf8, 0x4b, 0x36, 0x8b, 0xb2, 0x14, 0x6e, 0x19, 0x7f, 0xc9, 0x2b, 0xca, 0x6f,
+	0xcc, 0xff, 0xac, 0xe8, 0x11, 0xdc, 0x7f, 0x5e, 0xec, 0x4f, 0x52, 0xd5, 0x6f, 0x86, 0xde, 0xe9,
+	0x47, 0x9a, 0x70, 0x79, 0xdb, 0x3d, 0x28, 0xfd, 0xaa, 0x7f, 0x66, 0x75, 0xe5, 0x47, 0x39, 0xf7,
+	0x79, 0xb4, 0x5e, 0x33, 0x11, 0x2c, 0x09, 0x5f, 0x66, 0xe5, 0x81, 0x96, 0x7e, 0x22, 0x7c, 0x89,
+	0xbe, 0x07, 0x7b, 0xf7, 0x44, 0xaa, 0x0d, 0x73, 0x3a, 0xe7, 0x6d, 0xfd, 0x88, 0xb6, 0xf3, 0x47,
+	0xb4, 0xed, 0xe7, 0x04, 0xde, 0xc3, 0x32, 0x69, 0x42, 0x57, 0x94, 0x70, 0xaa, 0x16, 0xae, 0x8c,
+	0xf3, 0xe3, 0xe5, 0x08, 0xca, 0xf9, 0xdb, 0x87, 0x1c, 0x28, 0x0d, 0x46, 0x8f, 0xdd, 0xe1, 0xe0,
+	0xd6, 0x3d, 0x41, 0x55, 0xb0, 0xa7, 0x5d, 0xff, 0x6e, 0x38, 0x1c, 0xf8, 0x77, 0xae, 0x21, 0xbd,
+	0xa9, 0x3f, 0xc6, 0xdd, 0xfb, 0x3b, 0xb7, 0x80, 0x00, 0x8a, 0x0f, 0x93, 0xe1, 0x60, 0xf4, 0xb3,
+	0x6b, 0x4a, 0xae, 0x37, 0x1e, 0xfb, 0x53, 0x1f, 0x77, 0x27, 0xae, 0x75, 0xf9, 0x11, 0xaa, 0x47,
+	0x77, 0x13, 0xb9, 0x50, 0xf1, 0xfb, 0x93, 0xc0, 0x1f, 0x4e, 0x83, 0x7b, 0x3c, 0xe9, 0xbb, 0x27,
+	0x3d, 0xeb, 0xa9, 0x10, 0xcf, 0x66, 0x45, 0x55, 0xf1, 0x77, 0x7f, 0x0f, 0x00, 0x14, 0x03, 0x4c,
+	0x58, 0x24, 0x06, 0x00, 0x00,
 }
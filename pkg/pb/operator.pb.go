@@ -0,0 +1,419 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: operator.proto
+
+package pb
+
+import (
+	context "context"
+	fmt "fmt"
+	_ "github.com/gogo/protobuf/gogoproto"
+	proto "github.com/gogo/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
+
+type MaintenanceRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MaintenanceRequest) Reset()         { *m = MaintenanceRequest{} }
+func (m *MaintenanceRequest) String() string { return proto.CompactTextString(m) }
+func (*MaintenanceRequest) ProtoMessage()    {}
+func (*MaintenanceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb8d3714996346ac, []int{0}
+}
+func (m *MaintenanceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MaintenanceRequest.Unmarshal(m, b)
+}
+func (m *MaintenanceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MaintenanceRequest.Marshal(b, m, deterministic)
+}
+func (m *MaintenanceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MaintenanceRequest.Merge(m, src)
+}
+func (m *MaintenanceRequest) XXX_Size() int {
+	return xxx_messageInfo_MaintenanceRequest.Size(m)
+}
+func (m *MaintenanceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MaintenanceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MaintenanceRequest proto.InternalMessageInfo
+
+type SetMaintenanceRequest struct {
+	Enabled              bool     `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetMaintenanceRequest) Reset()         { *m = SetMaintenanceRequest{} }
+func (m *SetMaintenanceRequest) String() string { return proto.CompactTextString(m) }
+func (*SetMaintenanceRequest) ProtoMessage()    {}
+func (*SetMaintenanceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb8d3714996346ac, []int{1}
+}
+func (m *SetMaintenanceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetMaintenanceRequest.Unmarshal(m, b)
+}
+func (m *SetMaintenanceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetMaintenanceRequest.Marshal(b, m, deterministic)
+}
+func (m *SetMaintenanceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetMaintenanceRequest.Merge(m, src)
+}
+func (m *SetMaintenanceRequest) XXX_Size() int {
+	return xxx_messageInfo_SetMaintenanceRequest.Size(m)
+}
+func (m *SetMaintenanceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetMaintenanceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetMaintenanceRequest proto.InternalMessageInfo
+
+func (m *SetMaintenanceRequest) GetEnabled() bool {
+	if m != nil {
+		return m.Enabled
+	}
+	return false
+}
+
+type MaintenanceResponse struct {
+	Enabled              bool     `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MaintenanceResponse) Reset()         { *m = MaintenanceResponse{} }
+func (m *MaintenanceResponse) String() string { return proto.CompactTextString(m) }
+func (*MaintenanceResponse) ProtoMessage()    {}
+func (*MaintenanceResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb8d3714996346ac, []int{2}
+}
+func (m *MaintenanceResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MaintenanceResponse.Unmarshal(m, b)
+}
+func (m *MaintenanceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MaintenanceResponse.Marshal(b, m, deterministic)
+}
+func (m *MaintenanceResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MaintenanceResponse.Merge(m, src)
+}
+func (m *MaintenanceResponse) XXX_Size() int {
+	return xxx_messageInfo_MaintenanceResponse.Size(m)
+}
+func (m *MaintenanceResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MaintenanceResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MaintenanceResponse proto.InternalMessageInfo
+
+func (m *MaintenanceResponse) GetEnabled() bool {
+	if m != nil {
+		return m.Enabled
+	}
+	return false
+}
+
+type OperatorPayoutsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OperatorPayoutsRequest) Reset()         { *m = OperatorPayoutsRequest{} }
+func (m *OperatorPayoutsRequest) String() string { return proto.CompactTextString(m) }
+func (*OperatorPayoutsRequest) ProtoMessage()    {}
+func (*OperatorPayoutsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb8d3714996346ac, []int{3}
+}
+func (m *OperatorPayoutsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OperatorPayoutsRequest.Unmarshal(m, b)
+}
+func (m *OperatorPayoutsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OperatorPayoutsRequest.Marshal(b, m, deterministic)
+}
+func (m *OperatorPayoutsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OperatorPayoutsRequest.Merge(m, src)
+}
+func (m *OperatorPayoutsRequest) XXX_Size() int {
+	return xxx_messageInfo_OperatorPayoutsRequest.Size(m)
+}
+func (m *OperatorPayoutsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_OperatorPayoutsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OperatorPayoutsRequest proto.InternalMessageInfo
+
+type OperatorPayoutsResponse struct {
+	Payouts              []*SatellitePayout `protobuf:"bytes,1,rep,name=payouts,proto3" json:"payouts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *OperatorPayoutsResponse) Reset()         { *m = OperatorPayoutsResponse{} }
+func (m *OperatorPayoutsResponse) String() string { return proto.CompactTextString(m) }
+func (*OperatorPayoutsResponse) ProtoMessage()    {}
+func (*OperatorPayoutsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb8d3714996346ac, []int{4}
+}
+func (m *OperatorPayoutsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OperatorPayoutsResponse.Unmarshal(m, b)
+}
+func (m *OperatorPayoutsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OperatorPayoutsResponse.Marshal(b, m, deterministic)
+}
+func (m *OperatorPayoutsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OperatorPayoutsResponse.Merge(m, src)
+}
+func (m *OperatorPayoutsResponse) XXX_Size() int {
+	return xxx_messageInfo_OperatorPayoutsResponse.Size(m)
+}
+func (m *OperatorPayoutsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_OperatorPayoutsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OperatorPayoutsResponse proto.InternalMessageInfo
+
+func (m *OperatorPayoutsResponse) GetPayouts() []*SatellitePayout {
+	if m != nil {
+		return m.Payouts
+	}
+	return nil
+}
+
+// SatellitePayout is the payout of the node by a satellite for a month
+type SatellitePayout struct {
+	SatelliteId          NodeID   `protobuf:"bytes,1,opt,name=satellite_id,json=satelliteId,proto3,customtype=NodeID" json:"satellite_id"`
+	Payout               *Payout  `protobuf:"bytes,2,opt,name=payout,proto3" json:"payout,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SatellitePayout) Reset()         { *m = SatellitePayout{} }
+func (m *SatellitePayout) String() string { return proto.CompactTextString(m) }
+func (*SatellitePayout) ProtoMessage()    {}
+func (*SatellitePayout) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb8d3714996346ac, []int{5}
+}
+func (m *SatellitePayout) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SatellitePayout.Unmarshal(m, b)
+}
+func (m *SatellitePayout) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SatellitePayout.Marshal(b, m, deterministic)
+}
+func (m *SatellitePayout) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SatellitePayout.Merge(m, src)
+}
+func (m *SatellitePayout) XXX_Size() int {
+	return xxx_messageInfo_SatellitePayout.Size(m)
+}
+func (m *SatellitePayout) XXX_DiscardUnknown() {
+	xxx_messageInfo_SatellitePayout.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SatellitePayout proto.InternalMessageInfo
+
+func (m *SatellitePayout) GetPayout() *Payout {
+	if m != nil {
+		return m.Payout
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*MaintenanceRequest)(nil), "operator.MaintenanceRequest")
+	proto.RegisterType((*SetMaintenanceRequest)(nil), "operator.SetMaintenanceRequest")
+	proto.RegisterType((*MaintenanceResponse)(nil), "operator.MaintenanceResponse")
+	proto.RegisterType((*OperatorPayoutsRequest)(nil), "operator.OperatorPayoutsRequest")
+	proto.RegisterType((*OperatorPayoutsResponse)(nil), "operator.OperatorPayoutsResponse")
+	proto.RegisterType((*SatellitePayout)(nil), "operator.SatellitePayout")
+}
+
+func init() { proto.RegisterFile("operator.proto", fileDescriptor_cb8d3714996346ac) }
+
+var fileDescriptor_cb8d3714996346ac = []byte{
+	// 308 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x52, 0x4d, 0x4f, 0x83, 0x40,
+	0x10, 0x2d, 0xd5, 0x40, 0x33, 0x20, 0x4d, 0xd6, 0x2f, 0x24, 0x1a, 0x90, 0x8b, 0x9c, 0x30, 0xa5,
+	0xff, 0xa0, 0xf1, 0xd2, 0x44, 0x6b, 0x43, 0x6f, 0x5e, 0xcc, 0x22, 0x93, 0x86, 0x04, 0x59, 0x84,
+	0xed, 0xc1, 0xab, 0xbf, 0xce, 0xdf, 0xe0, 0xa1, 0xbf, 0xc5, 0xe8, 0xee, 0xf6, 0x43, 0xb4, 0x07,
+	0x8f, 0xb3, 0xef, 0xcd, 0x9b, 0x37, 0x6f, 0x16, 0x6c, 0x56, 0x61, 0x4d, 0x39, 0xab, 0xa3, 0xaa,
+	0x66, 0x9c, 0x91, 0x9e, 0xaa, 0x5d, 0x98, 0xb3, 0x39, 0x13, 0xaf, 0xee, 0x41, 0x45, 0x5f, 0xd9,
+	0x82, 0x37, 0xa2, 0x0c, 0x8e, 0x80, 0xdc, 0xd1, 0xbc, 0xe4, 0x58, 0xd2, 0xf2, 0x09, 0x13, 0x7c,
+	0x59, 0x60, 0xc3, 0x83, 0x01, 0x1c, 0xcf, 0x90, 0xb7, 0x01, 0xe2, 0x80, 0x81, 0x25, 0x4d, 0x0b,
+	0xcc, 0x1c, 0xcd, 0xd7, 0xc2, 0x5e, 0xa2, 0xca, 0xe0, 0x1a, 0x0e, 0xb7, 0xf8, 0x4d, 0xc5, 0xca,
+	0x06, 0x77, 0x34, 0x38, 0x70, 0x72, 0x2f, 0x0d, 0x4e, 0x85, 0x25, 0x35, 0x7d, 0x02, 0xa7, 0x2d,
+	0x44, 0xca, 0x0d, 0xc1, 0x90, 0xfe, 0x1d, 0xcd, 0xdf, 0x0b, 0xcd, 0xf8, 0x2c, 0x5a, 0x6d, 0x3d,
+	0xa3, 0x1c, 0x8b, 0x22, 0xe7, 0x28, 0x9a, 0x12, 0xc5, 0x0c, 0x9e, 0xa1, 0xff, 0x03, 0x23, 0x03,
+	0xb0, 0x1a, 0xf5, 0xf4, 0x98, 0x0b, 0x6f, 0xd6, 0xc8, 0x7e, 0x5f, 0x7a, 0x9d, 0x8f, 0xa5, 0xa7,
+	0x4f, 0x58, 0x86, 0xe3, 0x9b, 0xc4, 0x5c, 0x71, 0xc6, 0x19, 0xb9, 0x02, 0x5d, 0x08, 0x3a, 0x5d,
+	0x5f, 0x0b, 0xcd, 0xb8, 0x1f, 0xa9, 0x24, 0xe5, 0x3c, 0x09, 0xc7, 0x6f, 0x5d, 0xb0, 0xbe, 0x04,
+	0xd4, 0x0e, 0xe4, 0x16, 0xcc, 0x8d, 0x68, 0xc8, 0xf9, 0xda, 0x72, 0x3b, 0x61, 0xf7, 0xe2, 0x0f,
+	0x54, 0x04, 0x10, 0x74, 0x48, 0x02, 0xf6, 0xf6, 0x6d, 0x88, 0xb7, 0x91, 0x01, 0xf2, 0xff, 0x68,
+	0x4e, 0xc1, 0x90, 0x49, 0x13, 0x7f, 0xcd, 0xfd, 0xfd, 0x3c, 0xee, 0xe5, 0x0e, 0x86, 0x52, 0x1c,
+	0xed, 0x3f, 0x74, 0xab, 0x34, 0xd5, 0xbf, 0x3f, 0xd9, 0xf0, 0x73, 0x00, 0x73, 0xa2, 0x2d, 0xeb,
+	0x9b, 0x02, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// NodeOperatorClient is the client API for NodeOperator service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type NodeOperatorClient interface {
+	// Maintenance returns whether the node is in maintenance mode
+	Maintenance(ctx context.Context, in *MaintenanceRequest, opts ...grpc.CallOption) (*MaintenanceResponse, error)
+	// SetMaintenance enters or leaves maintenance mode, in which the node
+	// rejects uploads while still serving downloads and audits
+	SetMaintenance(ctx context.Context, in *SetMaintenanceRequest, opts ...grpc.CallOption) (*MaintenanceResponse, error)
+	// Payouts returns the payouts last reported by the satellites
+	Payouts(ctx context.Context, in *OperatorPayoutsRequest, opts ...grpc.CallOption) (*OperatorPayoutsResponse, error)
+}
+
+type nodeOperatorClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewNodeOperatorClient(cc *grpc.ClientConn) NodeOperatorClient {
+	return &nodeOperatorClient{cc}
+}
+
+func (c *nodeOperatorClient) Maintenance(ctx context.Context, in *MaintenanceRequest, opts ...grpc.CallOption) (*MaintenanceResponse, error) {
+	out := new(MaintenanceResponse)
+	err := c.cc.Invoke(ctx, "/operator.NodeOperator/Maintenance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeOperatorClient) SetMaintenance(ctx context.Context, in *SetMaintenanceRequest, opts ...grpc.CallOption) (*MaintenanceResponse, error) {
+	out := new(MaintenanceResponse)
+	err := c.cc.Invoke(ctx, "/operator.NodeOperator/SetMaintenance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeOperatorClient) Payouts(ctx context.Context, in *OperatorPayoutsRequest, opts ...grpc.CallOption) (*OperatorPayoutsResponse, error) {
+	out := new(OperatorPayoutsResponse)
+	err := c.cc.Invoke(ctx, "/operator.NodeOperator/Payouts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// NodeOperatorServer is the server API for NodeOperator service.
+type NodeOperatorServer interface {
+	// Maintenance returns whether the node is in maintenance mode
+	Maintenance(context.Context, *MaintenanceRequest) (*MaintenanceResponse, error)
+	// SetMaintenance enters or leaves maintenance mode, in which the node
+	// rejects uploads while still serving downloads and audits
+	SetMaintenance(context.Context, *SetMaintenanceRequest) (*MaintenanceResponse, error)
+	// Payouts returns the payouts last reported by the satellites
+	Payouts(context.Context, *OperatorPayoutsRequest) (*OperatorPayoutsResponse, error)
+}
+
+func RegisterNodeOperatorServer(s *grpc.Server, srv NodeOperatorServer) {
+	s.RegisterService(&_NodeOperator_serviceDesc, srv)
+}
+
+func _NodeOperator_Maintenance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MaintenanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeOperatorServer).Maintenance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/operator.NodeOperator/Maintenance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeOperatorServer).Maintenance(ctx, req.(*MaintenanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeOperator_SetMaintenance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetMaintenanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeOperatorServer).SetMaintenance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/operator.NodeOperator/SetMaintenance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeOperatorServer).SetMaintenance(ctx, req.(*SetMaintenanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeOperator_Payouts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OperatorPayoutsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeOperatorServer).Payouts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/operator.NodeOperator/Payouts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeOperatorServer).Payouts(ctx, req.(*OperatorPayoutsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _NodeOperator_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "operator.NodeOperator",
+	HandlerType: (*NodeOperatorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Maintenance",
+			Handler:    _NodeOperator_Maintenance_Handler,
+		},
+		{
+			MethodName: "SetMaintenance",
+			Handler:    _NodeOperator_SetMaintenance_Handler,
+		},
+		{
+			MethodName: "Payouts",
+			Handler:    _NodeOperator_Payouts_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "operator.proto",
+}
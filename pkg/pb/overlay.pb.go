@@ -285,6 +285,7 @@ type InfoResponse struct {
 	Type                 NodeType      `protobuf:"varint,2,opt,name=type,proto3,enum=node.NodeType" json:"type,omitempty"`
 	Operator             *NodeOperator `protobuf:"bytes,3,opt,name=operator,proto3" json:"operator,omitempty"`
 	Capacity             *NodeCapacity `protobuf:"bytes,4,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	Version              *NodeVersion  `protobuf:"bytes,5,opt,name=version,proto3" json:"version,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
 	XXX_unrecognized     []byte        `json:"-"`
 	XXX_sizecache        int32         `json:"-"`
@@ -335,6 +336,13 @@ func (m *InfoResponse) GetCapacity() *NodeCapacity {
 	return nil
 }
 
+func (m *InfoResponse) GetVersion() *NodeVersion {
+	if m != nil {
+		return m.Version
+	}
+	return nil
+}
+
 type Restriction struct {
 	Operator             Restriction_Operator `protobuf:"varint,1,opt,name=operator,proto3,enum=overlay.Restriction_Operator" json:"operator,omitempty"`
 	Operand              Restriction_Operand  `protobuf:"varint,2,opt,name=operand,proto3,enum=overlay.Restriction_Operand" json:"operand,omitempty"`
@@ -404,37 +412,38 @@ func init() {
 func init() { proto.RegisterFile("overlay.proto", fileDescriptor_61fc82527fbe24ad) }
 
 var fileDescriptor_61fc82527fbe24ad = []byte{
-	// 473 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x93, 0xcf, 0x8e, 0xd3, 0x3e,
-	0x10, 0xc7, 0xd7, 0x49, 0xda, 0xe6, 0x37, 0xfd, 0xa3, 0xc8, 0xda, 0xfd, 0x29, 0x8a, 0x40, 0xaa,
-	0x72, 0x40, 0x3d, 0xa0, 0x1c, 0xba, 0x68, 0x25, 0xb8, 0xb1, 0x34, 0x2c, 0x15, 0xab, 0x85, 0x35,
-	0x91, 0x90, 0xe0, 0x80, 0xd2, 0xc6, 0x44, 0x11, 0xc5, 0x0e, 0x8e, 0xbb, 0x52, 0xde, 0x80, 0x33,
-	0x4f, 0xc2, 0xf3, 0x71, 0x42, 0x76, 0x9c, 0x34, 0x14, 0x90, 0x38, 0xd9, 0x33, 0xdf, 0xcf, 0x64,
-	0x66, 0x3c, 0x13, 0x98, 0xf2, 0x3b, 0x2a, 0x76, 0x69, 0x1d, 0x95, 0x82, 0x4b, 0x8e, 0x47, 0xc6,
-	0x0c, 0x20, 0xe7, 0x39, 0x6f, 0x9c, 0x01, 0x30, 0x9e, 0xd1, 0xe6, 0x1e, 0x7e, 0x45, 0x30, 0xb9,
-	0xdd, 0x53, 0x51, 0x13, 0xfa, 0x65, 0x4f, 0x2b, 0x89, 0x43, 0x18, 0x56, 0x94, 0x65, 0x54, 0xf8,
-	0x68, 0x8e, 0x16, 0xe3, 0x25, 0x44, 0x9a, 0xbe, 0xe1, 0x19, 0x25, 0x46, 0x51, 0x8c, 0x4c, 0x45,
-	0x4e, 0xa5, 0x6f, 0xfd, 0xce, 0x34, 0x0a, 0x3e, 0x85, 0xc1, 0xae, 0xf8, 0x5c, 0x48, 0xdf, 0x9e,
-	0xa3, 0x85, 0x4d, 0x1a, 0x03, 0x07, 0xe0, 0x96, 0x05, 0xcb, 0x37, 0xe9, 0xf6, 0x93, 0xef, 0xcc,
-	0xd1, 0xc2, 0x25, 0x9d, 0x1d, 0xbe, 0x87, 0xa9, 0xa9, 0xa4, 0x2a, 0x39, 0xab, 0xe8, 0x3f, 0x95,
-	0xf2, 0x00, 0x5c, 0x61, 0x78, 0xdf, 0x9a, 0xdb, 0x47, 0x54, 0xa7, 0x85, 0x53, 0x18, 0xbf, 0x2e,
-	0x58, 0x6e, 0xba, 0x0c, 0x67, 0x30, 0x69, 0xcc, 0x83, 0xbc, 0x66, 0x1f, 0x79, 0x2b, 0x7f, 0x43,
-	0x30, 0x69, 0xec, 0xae, 0x14, 0x47, 0xd6, 0x25, 0xd5, 0xfd, 0xce, 0x96, 0xb3, 0x43, 0x8a, 0xa4,
-	0x2e, 0x29, 0xd1, 0x1a, 0x8e, 0xc0, 0xe5, 0x25, 0x15, 0xa9, 0xe4, 0x42, 0x37, 0x3d, 0x5e, 0xe2,
-	0x03, 0xf7, 0xca, 0x28, 0xa4, 0x63, 0x14, 0xbf, 0x4d, 0xcb, 0x74, 0x5b, 0xc8, 0x5a, 0xbf, 0xc5,
-	0x2f, 0xfc, 0x33, 0xa3, 0x90, 0x8e, 0x09, 0x7f, 0x20, 0x18, 0x13, 0x5a, 0x49, 0x51, 0x6c, 0x65,
-	0xc1, 0x19, 0x7e, 0xdc, 0xcb, 0x87, 0x74, 0x5d, 0xf7, 0xa3, 0x76, 0xfa, 0x3d, 0x2e, 0xfa, 0x43,
-	0xea, 0x0b, 0x18, 0xe9, 0x3b, 0xcb, 0x4c, 0x47, 0xf7, 0xfe, 0x1e, 0xc9, 0x32, 0xd2, 0xc2, 0x6a,
-	0xa8, 0x77, 0xe9, 0x6e, 0x4f, 0xdb, 0xa1, 0x6a, 0x23, 0x7c, 0x04, 0x6e, 0x9b, 0x03, 0x0f, 0xc1,
-	0xba, 0x4e, 0xbc, 0x13, 0x75, 0xc6, 0xb7, 0x1e, 0x52, 0xe7, 0x55, 0xe2, 0x59, 0x78, 0x04, 0xf6,
-	0x75, 0x12, 0x7b, 0xb6, 0xba, 0x5c, 0x25, 0xb1, 0xe7, 0x84, 0x0f, 0x61, 0x64, 0xbe, 0x8f, 0x31,
-	0xcc, 0x9e, 0x93, 0x38, 0xfe, 0x70, 0xf9, 0xf4, 0x66, 0xf5, 0x76, 0xbd, 0x4a, 0x5e, 0x78, 0x27,
-	0x78, 0x0a, 0xff, 0x69, 0xdf, 0x6a, 0xfd, 0xe6, 0xa5, 0x87, 0x96, 0xdf, 0x11, 0x0c, 0xd4, 0xbb,
-	0x54, 0xf8, 0x02, 0x06, 0x7a, 0x4d, 0xf0, 0x59, 0x57, 0x73, 0x7f, 0x81, 0x83, 0xff, 0x8f, 0xdd,
-	0x66, 0x84, 0xe7, 0xe0, 0xa8, 0x91, 0xe3, 0xd3, 0x4e, 0xef, 0x2d, 0x44, 0x70, 0x76, 0xe4, 0x35,
-	0x41, 0x4f, 0xd4, 0x93, 0x6b, 0x42, 0xad, 0x43, 0x2f, 0xb6, 0xb7, 0x2d, 0xbd, 0xd8, 0xfe, 0xce,
-	0x5c, 0x3a, 0xef, 0xac, 0x72, 0xb3, 0x19, 0xea, 0xff, 0xec, 0xfc, 0x67, 0x00, 0x00, 0x00, 0xff,
-	0xff, 0x33, 0xe5, 0x71, 0x1b, 0x99, 0x03, 0x00, 0x00,
+	// 486 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x93, 0x51, 0x8b, 0xd3, 0x40,
+	0x10, 0xc7, 0x6f, 0xdb, 0xb4, 0x8d, 0x93, 0xb6, 0xc4, 0xe5, 0x2a, 0x21, 0x28, 0x94, 0x3c, 0x48,
+	0x41, 0xc9, 0x43, 0x4f, 0x0e, 0xf4, 0xcd, 0xb3, 0xf1, 0x2c, 0x1e, 0xa7, 0xb7, 0x06, 0x05, 0x7d,
+	0x90, 0xb4, 0x59, 0x43, 0xb0, 0xee, 0xc6, 0xcd, 0xb6, 0x90, 0x6f, 0xe0, 0xc7, 0xf1, 0x1b, 0xf8,
+	0xbd, 0x7c, 0x92, 0x6c, 0x36, 0x69, 0x88, 0x0a, 0xf7, 0xb4, 0x33, 0xf3, 0xff, 0x4d, 0x76, 0x26,
+	0xf9, 0x07, 0x26, 0xfc, 0x40, 0xc5, 0x2e, 0x2a, 0xfc, 0x4c, 0x70, 0xc9, 0xf1, 0x48, 0xa7, 0x2e,
+	0x24, 0x3c, 0xe1, 0x55, 0xd1, 0x05, 0xc6, 0x63, 0x5a, 0xc5, 0xde, 0x0f, 0x04, 0xe3, 0x9b, 0x3d,
+	0x15, 0x05, 0xa1, 0xdf, 0xf7, 0x34, 0x97, 0xd8, 0x83, 0x61, 0x4e, 0x59, 0x4c, 0x85, 0x83, 0xe6,
+	0x68, 0x61, 0x2d, 0xc1, 0x57, 0xf4, 0x35, 0x8f, 0x29, 0xd1, 0x4a, 0xc9, 0xc8, 0x48, 0x24, 0x54,
+	0x3a, 0xbd, 0xbf, 0x99, 0x4a, 0xc1, 0xa7, 0x30, 0xd8, 0xa5, 0xdf, 0x52, 0xe9, 0xf4, 0xe7, 0x68,
+	0xd1, 0x27, 0x55, 0x82, 0x5d, 0x30, 0xb3, 0x94, 0x25, 0x9b, 0x68, 0xfb, 0xd5, 0x31, 0xe6, 0x68,
+	0x61, 0x92, 0x26, 0xf7, 0x3e, 0xc1, 0x44, 0x4f, 0x92, 0x67, 0x9c, 0xe5, 0xf4, 0x56, 0xa3, 0x3c,
+	0x04, 0x53, 0x68, 0xde, 0xe9, 0xcd, 0xfb, 0x1d, 0xaa, 0xd1, 0xbc, 0x09, 0x58, 0x6f, 0x53, 0x96,
+	0xe8, 0x2d, 0xbd, 0x29, 0x8c, 0xab, 0xf4, 0x28, 0xaf, 0xd9, 0x17, 0x5e, 0xcb, 0xbf, 0x10, 0x8c,
+	0xab, 0xbc, 0x19, 0xc5, 0x90, 0x45, 0x46, 0xd5, 0xbe, 0xd3, 0xe5, 0xf4, 0x78, 0x45, 0x58, 0x64,
+	0x94, 0x28, 0x0d, 0xfb, 0x60, 0xf2, 0x8c, 0x8a, 0x48, 0x72, 0xa1, 0x96, 0xb6, 0x96, 0xf8, 0xc8,
+	0xbd, 0xd1, 0x0a, 0x69, 0x98, 0x92, 0xdf, 0x46, 0x59, 0xb4, 0x4d, 0x65, 0xe1, 0x18, 0x5d, 0xfe,
+	0x85, 0x56, 0x48, 0xc3, 0xe0, 0x47, 0x30, 0x3a, 0x50, 0x91, 0xa7, 0x9c, 0x39, 0x03, 0x85, 0xdf,
+	0x3d, 0xe2, 0xef, 0x2b, 0x81, 0xd4, 0x84, 0xf7, 0x1b, 0x81, 0x45, 0x68, 0x2e, 0x45, 0xba, 0x95,
+	0x29, 0x67, 0xf8, 0x69, 0x6b, 0x38, 0xa4, 0x96, 0x78, 0xe0, 0xd7, 0x56, 0x69, 0x71, 0xfe, 0x3f,
+	0xe6, 0x3c, 0x87, 0x91, 0x8a, 0x59, 0xac, 0xd7, 0xbf, 0xff, 0xff, 0x4e, 0x16, 0x93, 0x1a, 0x2e,
+	0x1d, 0x70, 0x88, 0x76, 0x7b, 0x5a, 0x3b, 0x40, 0x25, 0xde, 0x13, 0x30, 0xeb, 0x3b, 0xf0, 0x10,
+	0x7a, 0x57, 0xa1, 0x7d, 0x52, 0x9e, 0xc1, 0x8d, 0x8d, 0xca, 0xf3, 0x32, 0xb4, 0x7b, 0x78, 0x04,
+	0xfd, 0xab, 0x30, 0xb0, 0xfb, 0x65, 0x70, 0x19, 0x06, 0xb6, 0xe1, 0x3d, 0x86, 0x91, 0x7e, 0x3e,
+	0xc6, 0x30, 0x7d, 0x49, 0x82, 0xe0, 0xf3, 0xc5, 0xf3, 0xeb, 0xd5, 0x87, 0xf5, 0x2a, 0x7c, 0x65,
+	0x9f, 0xe0, 0x09, 0xdc, 0x51, 0xb5, 0xd5, 0xfa, 0xdd, 0x6b, 0x1b, 0x2d, 0x7f, 0x22, 0x18, 0x94,
+	0x6f, 0x25, 0xc7, 0xe7, 0x30, 0x50, 0x9e, 0xc2, 0xb3, 0x66, 0xe6, 0xb6, 0xdb, 0xdd, 0x7b, 0xdd,
+	0xb2, 0xfe, 0xde, 0x67, 0x60, 0x94, 0xfe, 0xc0, 0xa7, 0x8d, 0xde, 0x72, 0x8f, 0x3b, 0xeb, 0x54,
+	0x75, 0xd3, 0x33, 0xb0, 0x34, 0x51, 0x7a, 0xa7, 0xd5, 0xdb, 0xb2, 0x96, 0x3b, 0xeb, 0x54, 0xab,
+	0xde, 0x0b, 0xe3, 0x63, 0x2f, 0xdb, 0x6c, 0x86, 0xea, 0xa7, 0x3c, 0xfb, 0x33, 0x00, 0x6b, 0xb9,
+	0x2c, 0xac, 0xc6, 0x03, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -0,0 +1,297 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: payouts.proto
+
+package pb
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/gogo/protobuf/proto"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	grpc "google.golang.org/grpc"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
+
+type PayoutHistoryRequest struct {
+	// only the months starting at or after since are returned, all the months
+	// since the node joined the satellite are returned when it is not set
+	Since                *timestamp.Timestamp `protobuf:"bytes,1,opt,name=since,proto3" json:"since,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *PayoutHistoryRequest) Reset()         { *m = PayoutHistoryRequest{} }
+func (m *PayoutHistoryRequest) String() string { return proto.CompactTextString(m) }
+func (*PayoutHistoryRequest) ProtoMessage()    {}
+func (*PayoutHistoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_abfb9c4b4f60e63a, []int{0}
+}
+func (m *PayoutHistoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PayoutHistoryRequest.Unmarshal(m, b)
+}
+func (m *PayoutHistoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PayoutHistoryRequest.Marshal(b, m, deterministic)
+}
+func (m *PayoutHistoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PayoutHistoryRequest.Merge(m, src)
+}
+func (m *PayoutHistoryRequest) XXX_Size() int {
+	return xxx_messageInfo_PayoutHistoryRequest.Size(m)
+}
+func (m *PayoutHistoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PayoutHistoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PayoutHistoryRequest proto.InternalMessageInfo
+
+func (m *PayoutHistoryRequest) GetSince() *timestamp.Timestamp {
+	if m != nil {
+		return m.Since
+	}
+	return nil
+}
+
+type PayoutHistoryResponse struct {
+	Payouts              []*Payout `protobuf:"bytes,1,rep,name=payouts,proto3" json:"payouts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *PayoutHistoryResponse) Reset()         { *m = PayoutHistoryResponse{} }
+func (m *PayoutHistoryResponse) String() string { return proto.CompactTextString(m) }
+func (*PayoutHistoryResponse) ProtoMessage()    {}
+func (*PayoutHistoryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_abfb9c4b4f60e63a, []int{1}
+}
+func (m *PayoutHistoryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PayoutHistoryResponse.Unmarshal(m, b)
+}
+func (m *PayoutHistoryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PayoutHistoryResponse.Marshal(b, m, deterministic)
+}
+func (m *PayoutHistoryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PayoutHistoryResponse.Merge(m, src)
+}
+func (m *PayoutHistoryResponse) XXX_Size() int {
+	return xxx_messageInfo_PayoutHistoryResponse.Size(m)
+}
+func (m *PayoutHistoryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PayoutHistoryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PayoutHistoryResponse proto.InternalMessageInfo
+
+func (m *PayoutHistoryResponse) GetPayouts() []*Payout {
+	if m != nil {
+		return m.Payouts
+	}
+	return nil
+}
+
+// Payout is the payment of a storage node for a month, the amounts are in micro dollars
+type Payout struct {
+	// start of the month
+	Period *timestamp.Timestamp `protobuf:"bytes,1,opt,name=period,proto3" json:"period,omitempty"`
+	// month of operation of the node, starting at 1
+	NodeMonth            int32    `protobuf:"varint,2,opt,name=node_month,json=nodeMonth,proto3" json:"node_month,omitempty"`
+	SurgePercent         int64    `protobuf:"varint,3,opt,name=surge_percent,json=surgePercent,proto3" json:"surge_percent,omitempty"`
+	HeldPercent          int64    `protobuf:"varint,4,opt,name=held_percent,json=heldPercent,proto3" json:"held_percent,omitempty"`
+	Gross                int64    `protobuf:"varint,5,opt,name=gross,proto3" json:"gross,omitempty"`
+	Held                 int64    `protobuf:"varint,6,opt,name=held,proto3" json:"held,omitempty"`
+	Paid                 int64    `protobuf:"varint,7,opt,name=paid,proto3" json:"paid,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Payout) Reset()         { *m = Payout{} }
+func (m *Payout) String() string { return proto.CompactTextString(m) }
+func (*Payout) ProtoMessage()    {}
+func (*Payout) Descriptor() ([]byte, []int) {
+	return fileDescriptor_abfb9c4b4f60e63a, []int{2}
+}
+func (m *Payout) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Payout.Unmarshal(m, b)
+}
+func (m *Payout) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Payout.Marshal(b, m, deterministic)
+}
+func (m *Payout) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Payout.Merge(m, src)
+}
+func (m *Payout) XXX_Size() int {
+	return xxx_messageInfo_Payout.Size(m)
+}
+func (m *Payout) XXX_DiscardUnknown() {
+	xxx_messageInfo_Payout.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Payout proto.InternalMessageInfo
+
+func (m *Payout) GetPeriod() *timestamp.Timestamp {
+	if m != nil {
+		return m.Period
+	}
+	return nil
+}
+
+func (m *Payout) GetNodeMonth() int32 {
+	if m != nil {
+		return m.NodeMonth
+	}
+	return 0
+}
+
+func (m *Payout) GetSurgePercent() int64 {
+	if m != nil {
+		return m.SurgePercent
+	}
+	return 0
+}
+
+func (m *Payout) GetHeldPercent() int64 {
+	if m != nil {
+		return m.HeldPercent
+	}
+	return 0
+}
+
+func (m *Payout) GetGross() int64 {
+	if m != nil {
+		return m.Gross
+	}
+	return 0
+}
+
+func (m *Payout) GetHeld() int64 {
+	if m != nil {
+		return m.Held
+	}
+	return 0
+}
+
+func (m *Payout) GetPaid() int64 {
+	if m != nil {
+		return m.Paid
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*PayoutHistoryRequest)(nil), "payouts.PayoutHistoryRequest")
+	proto.RegisterType((*PayoutHistoryResponse)(nil), "payouts.PayoutHistoryResponse")
+	proto.RegisterType((*Payout)(nil), "payouts.Payout")
+}
+
+func init() { proto.RegisterFile("payouts.proto", fileDescriptor_abfb9c4b4f60e63a) }
+
+var fileDescriptor_abfb9c4b4f60e63a = []byte{
+	// 305 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x91, 0x41, 0x4b, 0xc3, 0x30,
+	0x14, 0xc7, 0xcd, 0xb6, 0xb6, 0xf8, 0xb6, 0x21, 0x84, 0x09, 0x61, 0x30, 0xad, 0xf5, 0x52, 0x2f,
+	0x9d, 0xd4, 0x6f, 0xb0, 0xd3, 0x10, 0x84, 0x51, 0xf4, 0xe2, 0x65, 0x6c, 0xeb, 0xb3, 0x2b, 0x6c,
+	0x4d, 0x4c, 0xd2, 0xc3, 0x3e, 0xae, 0xdf, 0x44, 0x92, 0x34, 0x82, 0x03, 0xc1, 0x5b, 0xf2, 0x7b,
+	0xbf, 0xfe, 0x79, 0xfd, 0x07, 0xc6, 0x62, 0x73, 0xe2, 0xad, 0x56, 0x99, 0x90, 0x5c, 0x73, 0x1a,
+	0x75, 0xd7, 0xe9, 0x6d, 0xc5, 0x79, 0x75, 0xc0, 0xb9, 0xc5, 0xdb, 0xf6, 0x63, 0xae, 0xeb, 0x23,
+	0x2a, 0xbd, 0x39, 0x0a, 0x67, 0x26, 0x4b, 0x98, 0xac, 0xac, 0xbb, 0xac, 0x95, 0xe6, 0xf2, 0x54,
+	0xe0, 0x67, 0x8b, 0x4a, 0xd3, 0x47, 0x08, 0x54, 0xdd, 0xec, 0x90, 0x91, 0x98, 0xa4, 0xc3, 0x7c,
+	0x9a, 0xb9, 0xa0, 0xcc, 0x07, 0x65, 0xaf, 0x3e, 0xa8, 0x70, 0x62, 0xb2, 0x80, 0xeb, 0xb3, 0x24,
+	0x25, 0x78, 0xa3, 0x90, 0x3e, 0x80, 0x5f, 0x87, 0x91, 0xb8, 0x9f, 0x0e, 0xf3, 0xab, 0xcc, 0x6f,
+	0xeb, 0x3e, 0x28, 0xfc, 0x3c, 0xf9, 0x22, 0x10, 0x3a, 0x46, 0x73, 0x08, 0x05, 0xca, 0x9a, 0x97,
+	0xff, 0xd8, 0xa0, 0x33, 0xe9, 0x0c, 0xa0, 0xe1, 0x25, 0xae, 0x8f, 0xbc, 0xd1, 0x7b, 0xd6, 0x8b,
+	0x49, 0x1a, 0x14, 0x97, 0x86, 0xbc, 0x18, 0x40, 0xef, 0x61, 0xac, 0x5a, 0x59, 0xe1, 0x5a, 0xa0,
+	0xdc, 0x61, 0xa3, 0x59, 0x3f, 0x26, 0x69, 0xbf, 0x18, 0x59, 0xb8, 0x72, 0x8c, 0xde, 0xc1, 0x68,
+	0x8f, 0x87, 0xf2, 0xc7, 0x19, 0x58, 0x67, 0x68, 0x98, 0x57, 0x26, 0x10, 0x54, 0x92, 0x2b, 0xc5,
+	0x02, 0x3b, 0x73, 0x17, 0x4a, 0x61, 0x60, 0x24, 0x16, 0x5a, 0x68, 0xcf, 0x86, 0x89, 0x4d, 0x5d,
+	0xb2, 0xc8, 0x31, 0x73, 0xce, 0xdf, 0x20, 0x72, 0xbf, 0xa8, 0xe8, 0x33, 0x44, 0x5d, 0x59, 0x74,
+	0x76, 0xd6, 0xc9, 0xef, 0xe7, 0x98, 0xde, 0xfc, 0x35, 0x76, 0x1d, 0x27, 0x17, 0x8b, 0xc1, 0x7b,
+	0x4f, 0x6c, 0xb7, 0xa1, 0x6d, 0xe7, 0xe9, 0x7b, 0x00, 0xc7, 0xb9, 0xf2, 0xae, 0x10, 0x02, 0x00,
+	0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// PayoutsClient is the client API for Payouts service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type PayoutsClient interface {
+	// History returns the monthly payouts of the calling storage node
+	History(ctx context.Context, in *PayoutHistoryRequest, opts ...grpc.CallOption) (*PayoutHistoryResponse, error)
+}
+
+type payoutsClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewPayoutsClient(cc *grpc.ClientConn) PayoutsClient {
+	return &payoutsClient{cc}
+}
+
+func (c *payoutsClient) History(ctx context.Context, in *PayoutHistoryRequest, opts ...grpc.CallOption) (*PayoutHistoryResponse, error) {
+	out := new(PayoutHistoryResponse)
+	err := c.cc.Invoke(ctx, "/payouts.Payouts/History", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PayoutsServer is the server API for Payouts service.
+type PayoutsServer interface {
+	// History returns the monthly payouts of the calling storage node
+	History(context.Context, *PayoutHistoryRequest) (*PayoutHistoryResponse, error)
+}
+
+func RegisterPayoutsServer(s *grpc.Server, srv PayoutsServer) {
+	s.RegisterService(&_Payouts_serviceDesc, srv)
+}
+
+func _Payouts_History_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PayoutHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayoutsServer).History(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/payouts.Payouts/History",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayoutsServer).History(ctx, req.(*PayoutHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Payouts_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "payouts.Payouts",
+	HandlerType: (*PayoutsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "History",
+			Handler:    _Payouts_History_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "payouts.proto",
+}
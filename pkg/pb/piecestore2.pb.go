@@ -8,6 +8,7 @@ import (
 	fmt "fmt"
 	_ "github.com/gogo/protobuf/gogoproto"
 	proto "github.com/gogo/protobuf/proto"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
 	grpc "google.golang.org/grpc"
 	math "math"
 )
@@ -23,14 +24,39 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
 
+type PieceHeader_FormatVersion int32
+
+const (
+	PieceHeader_FORMAT_V0 PieceHeader_FormatVersion = 0
+	PieceHeader_FORMAT_V1 PieceHeader_FormatVersion = 1
+)
+
+var PieceHeader_FormatVersion_name = map[int32]string{
+	0: "FORMAT_V0",
+	1: "FORMAT_V1",
+}
+
+var PieceHeader_FormatVersion_value = map[string]int32{
+	"FORMAT_V0": 0,
+	"FORMAT_V1": 1,
+}
+
+func (x PieceHeader_FormatVersion) String() string {
+	return proto.EnumName(PieceHeader_FormatVersion_name, int32(x))
+}
+
+func (PieceHeader_FormatVersion) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_23ff32dd550c2439, []int{8, 0}
+}
+
 // Expected order of messages from uplink:
-//   OrderLimit ->
-//   repeated
-//      Order ->
-//      Chunk ->
-//   PieceHash signed by uplink ->
-//      <- PieceHash signed by storage node
 //
+//	OrderLimit ->
+//	repeated
+//	   Order ->
+//	   Chunk ->
+//	PieceHash signed by uplink ->
+//	   <- PieceHash signed by storage node
 type PieceUploadRequest struct {
 	// first message to show that we are allowed to upload
 	Limit *OrderLimit2 `protobuf:"bytes,1,opt,name=limit,proto3" json:"limit,omitempty"`
@@ -182,11 +208,12 @@ func (m *PieceUploadResponse) GetDone() *PieceHash {
 }
 
 // Expected order of messages from uplink:
-//   {OrderLimit, Chunk} ->
-//   go repeated
-//      Order -> (async)
-//   go repeated
-//      <- PieceDownloadResponse.Chunk
+//
+//	{OrderLimit, Chunk} ->
+//	go repeated
+//	   Order -> (async)
+//	go repeated
+//	   <- PieceDownloadResponse.Chunk
 type PieceDownloadRequest struct {
 	// first message to show that we are allowed to upload
 	Limit *OrderLimit2 `protobuf:"bytes,1,opt,name=limit,proto3" json:"limit,omitempty"`
@@ -292,10 +319,13 @@ func (m *PieceDownloadRequest_Chunk) GetChunkSize() int64 {
 }
 
 type PieceDownloadResponse struct {
-	Chunk                *PieceDownloadResponse_Chunk `protobuf:"bytes,1,opt,name=chunk,proto3" json:"chunk,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                     `json:"-"`
-	XXX_unrecognized     []byte                       `json:"-"`
-	XXX_sizecache        int32                        `json:"-"`
+	Chunk *PieceDownloadResponse_Chunk `protobuf:"bytes,1,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	// header of the piece, sent with the first chunk of an audit when the
+	// piece has one, so that the satellite can verify the uplink's signature
+	Header               *PieceHeader `protobuf:"bytes,2,opt,name=header,proto3" json:"header,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
 }
 
 func (m *PieceDownloadResponse) Reset()         { *m = PieceDownloadResponse{} }
@@ -329,6 +359,13 @@ func (m *PieceDownloadResponse) GetChunk() *PieceDownloadResponse_Chunk {
 	return nil
 }
 
+func (m *PieceDownloadResponse) GetHeader() *PieceHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
 // Chunk response for download request
 type PieceDownloadResponse_Chunk struct {
 	Offset               int64    `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
@@ -444,7 +481,243 @@ func (m *PieceDeleteResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_PieceDeleteResponse proto.InternalMessageInfo
 
+// RetainRequest is sent by a satellite with a bloom filter of the pieces
+// it expects the storage node to keep.
+type RetainRequest struct {
+	// pieces created after the creation date aren't in the filter
+	CreationDate         *timestamp.Timestamp `protobuf:"bytes,1,opt,name=creation_date,json=creationDate,proto3" json:"creation_date,omitempty"`
+	Filter               []byte               `protobuf:"bytes,2,opt,name=filter,proto3" json:"filter,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *RetainRequest) Reset()         { *m = RetainRequest{} }
+func (m *RetainRequest) String() string { return proto.CompactTextString(m) }
+func (*RetainRequest) ProtoMessage()    {}
+func (*RetainRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_23ff32dd550c2439, []int{6}
+}
+func (m *RetainRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RetainRequest.Unmarshal(m, b)
+}
+func (m *RetainRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RetainRequest.Marshal(b, m, deterministic)
+}
+func (m *RetainRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RetainRequest.Merge(m, src)
+}
+func (m *RetainRequest) XXX_Size() int {
+	return xxx_messageInfo_RetainRequest.Size(m)
+}
+func (m *RetainRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RetainRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RetainRequest proto.InternalMessageInfo
+
+func (m *RetainRequest) GetCreationDate() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreationDate
+	}
+	return nil
+}
+
+func (m *RetainRequest) GetFilter() []byte {
+	if m != nil {
+		return m.Filter
+	}
+	return nil
+}
+
+type RetainResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RetainResponse) Reset()         { *m = RetainResponse{} }
+func (m *RetainResponse) String() string { return proto.CompactTextString(m) }
+func (*RetainResponse) ProtoMessage()    {}
+func (*RetainResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_23ff32dd550c2439, []int{7}
+}
+func (m *RetainResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RetainResponse.Unmarshal(m, b)
+}
+func (m *RetainResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RetainResponse.Marshal(b, m, deterministic)
+}
+func (m *RetainResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RetainResponse.Merge(m, src)
+}
+func (m *RetainResponse) XXX_Size() int {
+	return xxx_messageInfo_RetainResponse.Size(m)
+}
+func (m *RetainResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RetainResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RetainResponse proto.InternalMessageInfo
+
+// PieceHeader is stored at the start of the piece files of format V1, so that
+// the storage node can verify and prove the provenance of a piece without the
+// piece database.
+type PieceHeader struct {
+	// the storage format of the piece file
+	FormatVersion PieceHeader_FormatVersion `protobuf:"varint,1,opt,name=format_version,json=formatVersion,proto3,enum=piecestore.PieceHeader_FormatVersion" json:"format_version,omitempty"`
+	// the hash of the piece data, and its signature by the uplink
+	Hash []byte `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+	// when the piece was uploaded
+	CreationTime *timestamp.Timestamp `protobuf:"bytes,3,opt,name=creation_time,json=creationTime,proto3" json:"creation_time,omitempty"`
+	Signature    []byte               `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	// the order limit which allowed the upload
+	OrderLimit *OrderLimit2 `protobuf:"bytes,5,opt,name=order_limit,json=orderLimit,proto3" json:"order_limit,omitempty"`
+	// the DER encoded certificates of the uplink, which verify the signature
+	UplinkCertChain      [][]byte `protobuf:"bytes,6,rep,name=uplink_cert_chain,json=uplinkCertChain,proto3" json:"uplink_cert_chain,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PieceHeader) Reset()         { *m = PieceHeader{} }
+func (m *PieceHeader) String() string { return proto.CompactTextString(m) }
+func (*PieceHeader) ProtoMessage()    {}
+func (*PieceHeader) Descriptor() ([]byte, []int) {
+	return fileDescriptor_23ff32dd550c2439, []int{8}
+}
+func (m *PieceHeader) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PieceHeader.Unmarshal(m, b)
+}
+func (m *PieceHeader) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PieceHeader.Marshal(b, m, deterministic)
+}
+func (m *PieceHeader) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PieceHeader.Merge(m, src)
+}
+func (m *PieceHeader) XXX_Size() int {
+	return xxx_messageInfo_PieceHeader.Size(m)
+}
+func (m *PieceHeader) XXX_DiscardUnknown() {
+	xxx_messageInfo_PieceHeader.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PieceHeader proto.InternalMessageInfo
+
+func (m *PieceHeader) GetFormatVersion() PieceHeader_FormatVersion {
+	if m != nil {
+		return m.FormatVersion
+	}
+	return PieceHeader_FORMAT_V0
+}
+
+func (m *PieceHeader) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+func (m *PieceHeader) GetCreationTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreationTime
+	}
+	return nil
+}
+
+func (m *PieceHeader) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *PieceHeader) GetOrderLimit() *OrderLimit2 {
+	if m != nil {
+		return m.OrderLimit
+	}
+	return nil
+}
+
+func (m *PieceHeader) GetUplinkCertChain() [][]byte {
+	if m != nil {
+		return m.UplinkCertChain
+	}
+	return nil
+}
+
+// RestoreTrashRequest is sent by a satellite to restore its pieces which
+// were deleted but are still in the trash of the storage node.
+type RestoreTrashRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RestoreTrashRequest) Reset()         { *m = RestoreTrashRequest{} }
+func (m *RestoreTrashRequest) String() string { return proto.CompactTextString(m) }
+func (*RestoreTrashRequest) ProtoMessage()    {}
+func (*RestoreTrashRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_23ff32dd550c2439, []int{9}
+}
+func (m *RestoreTrashRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreTrashRequest.Unmarshal(m, b)
+}
+func (m *RestoreTrashRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreTrashRequest.Marshal(b, m, deterministic)
+}
+func (m *RestoreTrashRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreTrashRequest.Merge(m, src)
+}
+func (m *RestoreTrashRequest) XXX_Size() int {
+	return xxx_messageInfo_RestoreTrashRequest.Size(m)
+}
+func (m *RestoreTrashRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreTrashRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreTrashRequest proto.InternalMessageInfo
+
+type RestoreTrashResponse struct {
+	RestoredPieces       int64    `protobuf:"varint,1,opt,name=restored_pieces,json=restoredPieces,proto3" json:"restored_pieces,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RestoreTrashResponse) Reset()         { *m = RestoreTrashResponse{} }
+func (m *RestoreTrashResponse) String() string { return proto.CompactTextString(m) }
+func (*RestoreTrashResponse) ProtoMessage()    {}
+func (*RestoreTrashResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_23ff32dd550c2439, []int{10}
+}
+func (m *RestoreTrashResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreTrashResponse.Unmarshal(m, b)
+}
+func (m *RestoreTrashResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreTrashResponse.Marshal(b, m, deterministic)
+}
+func (m *RestoreTrashResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreTrashResponse.Merge(m, src)
+}
+func (m *RestoreTrashResponse) XXX_Size() int {
+	return xxx_messageInfo_RestoreTrashResponse.Size(m)
+}
+func (m *RestoreTrashResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreTrashResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreTrashResponse proto.InternalMessageInfo
+
+func (m *RestoreTrashResponse) GetRestoredPieces() int64 {
+	if m != nil {
+		return m.RestoredPieces
+	}
+	return 0
+}
+
 func init() {
+	proto.RegisterEnum("piecestore.PieceHeader_FormatVersion", PieceHeader_FormatVersion_name, PieceHeader_FormatVersion_value)
 	proto.RegisterType((*PieceUploadRequest)(nil), "piecestore.PieceUploadRequest")
 	proto.RegisterType((*PieceUploadRequest_Chunk)(nil), "piecestore.PieceUploadRequest.Chunk")
 	proto.RegisterType((*PieceUploadResponse)(nil), "piecestore.PieceUploadResponse")
@@ -454,38 +727,62 @@ func init() {
 	proto.RegisterType((*PieceDownloadResponse_Chunk)(nil), "piecestore.PieceDownloadResponse.Chunk")
 	proto.RegisterType((*PieceDeleteRequest)(nil), "piecestore.PieceDeleteRequest")
 	proto.RegisterType((*PieceDeleteResponse)(nil), "piecestore.PieceDeleteResponse")
+	proto.RegisterType((*RetainRequest)(nil), "piecestore.RetainRequest")
+	proto.RegisterType((*RetainResponse)(nil), "piecestore.RetainResponse")
+	proto.RegisterType((*PieceHeader)(nil), "piecestore.PieceHeader")
+	proto.RegisterType((*RestoreTrashRequest)(nil), "piecestore.RestoreTrashRequest")
+	proto.RegisterType((*RestoreTrashResponse)(nil), "piecestore.RestoreTrashResponse")
 }
 
 func init() { proto.RegisterFile("piecestore2.proto", fileDescriptor_23ff32dd550c2439) }
 
 var fileDescriptor_23ff32dd550c2439 = []byte{
-	// 404 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x94, 0x51, 0x4f, 0xea, 0x30,
-	0x14, 0xc7, 0x29, 0x6c, 0xcb, 0xbd, 0xe7, 0x92, 0x9b, 0x50, 0xc4, 0x2c, 0x4b, 0x54, 0x5c, 0x50,
-	0xf1, 0x65, 0x31, 0xe3, 0xcd, 0xa0, 0x26, 0xca, 0x83, 0x89, 0x1a, 0x49, 0x0d, 0x2f, 0xbe, 0x98,
-	0xc1, 0x0a, 0x2c, 0xe2, 0x3a, 0xd7, 0x11, 0x13, 0xbe, 0x82, 0x9f, 0xd3, 0x8f, 0x61, 0x34, 0x6b,
-	0x37, 0xc8, 0x00, 0x21, 0x9a, 0xf8, 0x04, 0x3d, 0xe7, 0x7f, 0xfa, 0xff, 0xf5, 0xdf, 0x66, 0x50,
-	0x0a, 0x3c, 0xda, 0xa3, 0x3c, 0x62, 0x21, 0xb5, 0xad, 0x20, 0x64, 0x11, 0xc3, 0x30, 0x2b, 0x19,
-	0x30, 0x60, 0x03, 0x26, 0xeb, 0x46, 0x91, 0x85, 0x2e, 0x0d, 0xb9, 0x5c, 0x99, 0xef, 0x08, 0x70,
-	0x3b, 0x16, 0x76, 0x82, 0x11, 0x73, 0x5c, 0x42, 0x9f, 0xc7, 0x94, 0x47, 0xf8, 0x10, 0xd4, 0x91,
-	0xf7, 0xe4, 0x45, 0x3a, 0xaa, 0xa2, 0xfa, 0x3f, 0xbb, 0x6c, 0x25, 0x43, 0xb7, 0xf1, 0xcf, 0x75,
-	0xdc, 0xb1, 0x89, 0x54, 0xe0, 0x1a, 0xa8, 0xa2, 0xa9, 0xe7, 0x85, 0xf4, 0x7f, 0x46, 0x6a, 0x13,
-	0xd9, 0xc4, 0xc7, 0xa0, 0xf6, 0x86, 0x63, 0xff, 0x51, 0x2f, 0x08, 0x55, 0xcd, 0x9a, 0xd1, 0x59,
-	0x8b, 0xfe, 0xd6, 0x45, 0xac, 0x25, 0x72, 0x04, 0xef, 0x81, 0xe2, 0x32, 0x9f, 0xea, 0x8a, 0x18,
-	0x2d, 0xa5, 0x06, 0x62, 0xec, 0xd2, 0xe1, 0x43, 0x22, 0xda, 0x46, 0x03, 0x54, 0x31, 0x86, 0x37,
-	0x41, 0x63, 0xfd, 0x3e, 0xa7, 0x92, 0xbe, 0x40, 0x92, 0x15, 0xc6, 0xa0, 0xb8, 0x4e, 0xe4, 0x08,
-	0xd0, 0x22, 0x11, 0xff, 0xcd, 0x26, 0x94, 0x33, 0xf6, 0x3c, 0x60, 0x3e, 0xa7, 0x53, 0x4b, 0xb4,
-	0xd2, 0xd2, 0x7c, 0x43, 0xb0, 0x21, 0x6a, 0x2d, 0xf6, 0xe2, 0xff, 0x6a, 0x7e, 0xcd, 0x6c, 0x7e,
-	0xfb, 0x0b, 0xf9, 0xcd, 0x11, 0x64, 0x12, 0x34, 0x4e, 0xd7, 0x45, 0xb3, 0x05, 0x20, 0x94, 0x0f,
-	0xdc, 0x9b, 0x50, 0x41, 0x52, 0x20, 0x7f, 0x45, 0xe5, 0xce, 0x9b, 0x50, 0xf3, 0x15, 0x41, 0x65,
-	0xce, 0x25, 0x09, 0xea, 0x24, 0xe5, 0x92, 0x07, 0x3d, 0x58, 0xc1, 0x25, 0x27, 0xb2, 0x60, 0x3f,
-	0xba, 0xb3, 0xb3, 0xe4, 0xc9, 0xb6, 0xe8, 0x88, 0x46, 0xf4, 0xfb, 0x91, 0x9b, 0x95, 0xe4, 0xd2,
-	0xd3, 0x0d, 0x24, 0x99, 0xfd, 0x81, 0x00, 0xda, 0x53, 0x7c, 0x7c, 0x03, 0x9a, 0x7c, 0x15, 0x78,
-	0x7b, 0xf5, 0x6b, 0x35, 0x76, 0xbe, 0xec, 0xcb, 0x9d, 0xcd, 0x5c, 0x1d, 0xe1, 0x0e, 0xfc, 0x49,
-	0xb3, 0xc0, 0xd5, 0x75, 0xd7, 0x67, 0xec, 0xae, 0x0d, 0x32, 0xde, 0xf4, 0x08, 0xe1, 0x2b, 0xd0,
-	0xe4, 0x31, 0x96, 0x50, 0x66, 0x02, 0x5a, 0x42, 0x99, 0x3d, 0xbf, 0x99, 0x3b, 0x57, 0xee, 0xf3,
-	0x41, 0xb7, 0xab, 0x89, 0x4f, 0x43, 0xe3, 0x33, 0x00, 0x00, 0xff, 0xff, 0xf5, 0xfa, 0xf7, 0xbe,
-	0x55, 0x04, 0x00, 0x00,
+	// 710 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x54, 0xdd, 0x4e, 0xdb, 0x4a,
+	0x10, 0xc6, 0x24, 0xb1, 0x0e, 0x83, 0x13, 0x60, 0x81, 0x73, 0x72, 0xac, 0x73, 0x9a, 0xd4, 0x82,
+	0x42, 0x2b, 0xd5, 0xd0, 0xd0, 0xab, 0x8a, 0x16, 0x51, 0x10, 0x42, 0x2a, 0x08, 0xb4, 0xfc, 0x5c,
+	0xf4, 0xc6, 0x5a, 0x92, 0x4d, 0x6c, 0x91, 0x78, 0x5d, 0xef, 0xa6, 0x95, 0x78, 0x91, 0xbe, 0x4c,
+	0xdf, 0xa5, 0x57, 0x7d, 0x8c, 0x4a, 0xd5, 0xee, 0x7a, 0x93, 0x98, 0x10, 0x68, 0x2b, 0xf5, 0xca,
+	0x9e, 0x99, 0x6f, 0xfe, 0xbe, 0x99, 0x59, 0x58, 0x48, 0x22, 0xda, 0xa4, 0x5c, 0xb0, 0x94, 0x36,
+	0xfc, 0x24, 0x65, 0x82, 0x21, 0x18, 0xaa, 0x5c, 0xe8, 0xb0, 0x0e, 0xd3, 0x7a, 0xd7, 0x61, 0x69,
+	0x8b, 0xa6, 0x3c, 0x93, 0x6a, 0x1d, 0xc6, 0x3a, 0x5d, 0xba, 0xa1, 0xa4, 0xab, 0x7e, 0x7b, 0x43,
+	0x44, 0x3d, 0xca, 0x05, 0xe9, 0x25, 0x1a, 0xe0, 0x7d, 0xb7, 0x00, 0x9d, 0xca, 0x48, 0x17, 0x49,
+	0x97, 0x91, 0x16, 0xa6, 0x1f, 0xfa, 0x94, 0x0b, 0xf4, 0x14, 0x4a, 0xdd, 0xa8, 0x17, 0x89, 0xaa,
+	0x55, 0xb7, 0xd6, 0x67, 0x1b, 0x8b, 0x7e, 0x16, 0xf5, 0x44, 0x7e, 0x8e, 0xa4, 0xa5, 0x81, 0x35,
+	0x02, 0xad, 0x40, 0x49, 0x19, 0xab, 0xd3, 0x0a, 0x5a, 0xc9, 0x41, 0x1b, 0x58, 0x1b, 0xd1, 0x2b,
+	0x28, 0x35, 0xc3, 0x7e, 0x7c, 0x5d, 0x2d, 0x28, 0xd4, 0x8a, 0x3f, 0x2c, 0xdf, 0x1f, 0xcf, 0xef,
+	0xef, 0x49, 0x2c, 0xd6, 0x2e, 0x68, 0x15, 0x8a, 0x2d, 0x16, 0xd3, 0x6a, 0x51, 0xb9, 0x2e, 0x98,
+	0x04, 0xca, 0xed, 0x90, 0xf0, 0x10, 0x2b, 0xb3, 0xbb, 0x05, 0x25, 0xe5, 0x86, 0xfe, 0x06, 0x9b,
+	0xb5, 0xdb, 0x9c, 0xea, 0xea, 0x0b, 0x38, 0x93, 0x10, 0x82, 0x62, 0x8b, 0x08, 0xa2, 0x0a, 0x75,
+	0xb0, 0xfa, 0xf7, 0xb6, 0x61, 0x31, 0x97, 0x9e, 0x27, 0x2c, 0xe6, 0x74, 0x90, 0xd2, 0xba, 0x37,
+	0xa5, 0xf7, 0xcd, 0x82, 0x25, 0xa5, 0xdb, 0x67, 0x9f, 0xe2, 0x3f, 0xca, 0xdf, 0x76, 0x9e, 0xbf,
+	0x27, 0x63, 0xfc, 0xdd, 0xaa, 0x20, 0xc7, 0xa0, 0xfb, 0xe6, 0x21, 0x6a, 0xfe, 0x07, 0x50, 0xc8,
+	0x80, 0x47, 0x37, 0x54, 0x55, 0x52, 0xc0, 0x33, 0x4a, 0x73, 0x16, 0xdd, 0x50, 0xef, 0x8b, 0x05,
+	0xcb, 0xb7, 0xb2, 0x64, 0x44, 0xbd, 0x36, 0x75, 0xe9, 0x46, 0xd7, 0xee, 0xa9, 0x4b, 0x7b, 0xe4,
+	0x47, 0xbb, 0x01, 0x76, 0x48, 0xc9, 0xb0, 0xfb, 0x7f, 0xc6, 0xfc, 0x0f, 0x95, 0x19, 0x67, 0xb0,
+	0xdf, 0x1b, 0xf2, 0x4e, 0xb6, 0xe3, 0xfb, 0xb4, 0x4b, 0x05, 0xfd, 0xf5, 0x19, 0x79, 0xcb, 0xb0,
+	0x98, 0x0b, 0xa0, 0x5b, 0xf1, 0x42, 0x28, 0x63, 0x2a, 0x48, 0x14, 0x9b, 0x90, 0x3b, 0x50, 0x6e,
+	0xa6, 0x94, 0x88, 0x88, 0xc5, 0x41, 0x8b, 0x08, 0xb3, 0x3f, 0xae, 0xaf, 0xcf, 0xd0, 0x37, 0x67,
+	0xe8, 0x9f, 0x9b, 0x33, 0xc4, 0x8e, 0x71, 0xd8, 0x27, 0x82, 0xca, 0xae, 0xda, 0x51, 0x57, 0x64,
+	0x7c, 0x38, 0x38, 0x93, 0xbc, 0x79, 0xa8, 0x98, 0x4c, 0x59, 0xee, 0xaf, 0xd3, 0x30, 0x3b, 0x42,
+	0x10, 0x3a, 0x82, 0x4a, 0x9b, 0xa5, 0x3d, 0x22, 0x82, 0x8f, 0x34, 0xe5, 0x11, 0x8b, 0x55, 0xee,
+	0x4a, 0x63, 0x75, 0x02, 0xa3, 0xfe, 0x81, 0x42, 0x5f, 0x6a, 0x30, 0x2e, 0xb7, 0x47, 0x45, 0xc9,
+	0x62, 0x48, 0x78, 0x68, 0x58, 0x94, 0xff, 0xb9, 0xe6, 0xe4, 0x33, 0x52, 0x2d, 0xfc, 0x7c, 0x73,
+	0x52, 0x85, 0xfe, 0x83, 0x19, 0x1e, 0x75, 0x62, 0x22, 0xfa, 0xa9, 0x3e, 0x66, 0x07, 0x0f, 0x15,
+	0xe8, 0x25, 0xcc, 0xaa, 0x01, 0x04, 0x7a, 0x28, 0xa5, 0xc9, 0x43, 0x01, 0x36, 0x10, 0xd0, 0x33,
+	0x58, 0xe8, 0x27, 0xdd, 0x28, 0xbe, 0x0e, 0x9a, 0x34, 0x15, 0x41, 0x33, 0x24, 0x51, 0x5c, 0xb5,
+	0xeb, 0x85, 0x75, 0x07, 0xcf, 0x69, 0xc3, 0x1e, 0x4d, 0xc5, 0x9e, 0x54, 0x7b, 0xcf, 0xa1, 0x9c,
+	0x6b, 0x1a, 0x95, 0x61, 0xe6, 0xe0, 0x04, 0x1f, 0xef, 0x9e, 0x07, 0x97, 0x9b, 0xf3, 0x53, 0xa3,
+	0xe2, 0x8b, 0x79, 0x4b, 0x0e, 0x1d, 0x6b, 0xde, 0xce, 0x53, 0x79, 0xf2, 0x7a, 0xc6, 0xde, 0x0e,
+	0x2c, 0xe5, 0xd5, 0xd9, 0x25, 0xac, 0xc1, 0x5c, 0xaa, 0xf5, 0xad, 0x40, 0x53, 0x9e, 0x6d, 0x66,
+	0xc5, 0xa8, 0x15, 0xfb, 0xbc, 0xf1, 0xb9, 0x00, 0x70, 0x3a, 0x98, 0x09, 0x3a, 0x06, 0x5b, 0x3f,
+	0x3e, 0xe8, 0xd1, 0xfd, 0x8f, 0xa2, 0x5b, 0x9b, 0x68, 0xcf, 0x76, 0x62, 0x6a, 0xdd, 0x42, 0x17,
+	0xf0, 0x97, 0x39, 0x39, 0x54, 0x7f, 0xe8, 0x95, 0x70, 0x1f, 0x3f, 0x78, 0xaf, 0x32, 0xe8, 0xa6,
+	0x85, 0xde, 0x81, 0xad, 0x97, 0xff, 0x8e, 0x2a, 0x73, 0x67, 0xe5, 0xd6, 0x26, 0xda, 0x4d, 0x40,
+	0xb4, 0x0b, 0xb6, 0xde, 0x66, 0xf4, 0xef, 0x28, 0x38, 0x77, 0x4b, 0xae, 0x7b, 0x97, 0x69, 0x10,
+	0xe2, 0x0c, 0x9c, 0xd1, 0x29, 0xa0, 0x5a, 0x1e, 0x3d, 0x36, 0x36, 0xb7, 0x3e, 0x19, 0x60, 0x82,
+	0xbe, 0x2d, 0xbe, 0x9f, 0x4e, 0xae, 0xae, 0x6c, 0xb5, 0xc8, 0x5b, 0x3f, 0x06, 0x00, 0x43, 0xb1,
+	0xc0, 0x87, 0x75, 0x07, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -503,6 +800,8 @@ type PiecestoreClient interface {
 	Upload(ctx context.Context, opts ...grpc.CallOption) (Piecestore_UploadClient, error)
 	Download(ctx context.Context, opts ...grpc.CallOption) (Piecestore_DownloadClient, error)
 	Delete(ctx context.Context, in *PieceDeleteRequest, opts ...grpc.CallOption) (*PieceDeleteResponse, error)
+	Retain(ctx context.Context, in *RetainRequest, opts ...grpc.CallOption) (*RetainResponse, error)
+	RestoreTrash(ctx context.Context, in *RestoreTrashRequest, opts ...grpc.CallOption) (*RestoreTrashResponse, error)
 }
 
 type piecestoreClient struct {
@@ -587,11 +886,31 @@ func (c *piecestoreClient) Delete(ctx context.Context, in *PieceDeleteRequest, o
 	return out, nil
 }
 
+func (c *piecestoreClient) Retain(ctx context.Context, in *RetainRequest, opts ...grpc.CallOption) (*RetainResponse, error) {
+	out := new(RetainResponse)
+	err := c.cc.Invoke(ctx, "/piecestore.Piecestore/Retain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *piecestoreClient) RestoreTrash(ctx context.Context, in *RestoreTrashRequest, opts ...grpc.CallOption) (*RestoreTrashResponse, error) {
+	out := new(RestoreTrashResponse)
+	err := c.cc.Invoke(ctx, "/piecestore.Piecestore/RestoreTrash", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PiecestoreServer is the server API for Piecestore service.
 type PiecestoreServer interface {
 	Upload(Piecestore_UploadServer) error
 	Download(Piecestore_DownloadServer) error
 	Delete(context.Context, *PieceDeleteRequest) (*PieceDeleteResponse, error)
+	Retain(context.Context, *RetainRequest) (*RetainResponse, error)
+	RestoreTrash(context.Context, *RestoreTrashRequest) (*RestoreTrashResponse, error)
 }
 
 func RegisterPiecestoreServer(s *grpc.Server, srv PiecestoreServer) {
@@ -668,6 +987,42 @@ func _Piecestore_Delete_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Piecestore_Retain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RetainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PiecestoreServer).Retain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/piecestore.Piecestore/Retain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PiecestoreServer).Retain(ctx, req.(*RetainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Piecestore_RestoreTrash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreTrashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PiecestoreServer).RestoreTrash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/piecestore.Piecestore/RestoreTrash",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PiecestoreServer).RestoreTrash(ctx, req.(*RestoreTrashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Piecestore_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "piecestore.Piecestore",
 	HandlerType: (*PiecestoreServer)(nil),
@@ -676,6 +1031,14 @@ var _Piecestore_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Delete",
 			Handler:    _Piecestore_Delete_Handler,
 		},
+		{
+			MethodName: "Retain",
+			Handler:    _Piecestore_Retain_Handler,
+		},
+		{
+			MethodName: "RestoreTrash",
+			Handler:    _Piecestore_RestoreTrash_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
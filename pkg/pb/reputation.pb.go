@@ -0,0 +1,292 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: reputation.proto
+
+package pb
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/gogo/protobuf/proto"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	grpc "google.golang.org/grpc"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
+
+type ReputationStatsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReputationStatsRequest) Reset()         { *m = ReputationStatsRequest{} }
+func (m *ReputationStatsRequest) String() string { return proto.CompactTextString(m) }
+func (*ReputationStatsRequest) ProtoMessage()    {}
+func (*ReputationStatsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b35a2508345eddf0, []int{0}
+}
+func (m *ReputationStatsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReputationStatsRequest.Unmarshal(m, b)
+}
+func (m *ReputationStatsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReputationStatsRequest.Marshal(b, m, deterministic)
+}
+func (m *ReputationStatsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReputationStatsRequest.Merge(m, src)
+}
+func (m *ReputationStatsRequest) XXX_Size() int {
+	return xxx_messageInfo_ReputationStatsRequest.Size(m)
+}
+func (m *ReputationStatsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReputationStatsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReputationStatsRequest proto.InternalMessageInfo
+
+// ReputationStatsResponse is the reputation of a storage node as seen by the satellite
+type ReputationStatsResponse struct {
+	AuditCount         int64   `protobuf:"varint,1,opt,name=audit_count,json=auditCount,proto3" json:"audit_count,omitempty"`
+	AuditSuccessCount  int64   `protobuf:"varint,2,opt,name=audit_success_count,json=auditSuccessCount,proto3" json:"audit_success_count,omitempty"`
+	AuditSuccessRatio  float64 `protobuf:"fixed64,3,opt,name=audit_success_ratio,json=auditSuccessRatio,proto3" json:"audit_success_ratio,omitempty"`
+	UptimeCount        int64   `protobuf:"varint,4,opt,name=uptime_count,json=uptimeCount,proto3" json:"uptime_count,omitempty"`
+	UptimeSuccessCount int64   `protobuf:"varint,5,opt,name=uptime_success_count,json=uptimeSuccessCount,proto3" json:"uptime_success_count,omitempty"`
+	UptimeRatio        float64 `protobuf:"fixed64,6,opt,name=uptime_ratio,json=uptimeRatio,proto3" json:"uptime_ratio,omitempty"`
+	AuditScore         float64 `protobuf:"fixed64,7,opt,name=audit_score,json=auditScore,proto3" json:"audit_score,omitempty"`
+	UptimeScore        float64 `protobuf:"fixed64,8,opt,name=uptime_score,json=uptimeScore,proto3" json:"uptime_score,omitempty"`
+	// disqualified is when the satellite disqualified the node, unset when it didn't
+	Disqualified *timestamp.Timestamp `protobuf:"bytes,9,opt,name=disqualified,proto3" json:"disqualified,omitempty"`
+	// vetted is when the node passed vetting, unset when it didn't yet
+	Vetted *timestamp.Timestamp `protobuf:"bytes,10,opt,name=vetted,proto3" json:"vetted,omitempty"`
+	// vetting_audit_count and vetting_uptime_count are the audits and uptime
+	// checks a node needs to pass vetting
+	VettingAuditCount    int64    `protobuf:"varint,11,opt,name=vetting_audit_count,json=vettingAuditCount,proto3" json:"vetting_audit_count,omitempty"`
+	VettingUptimeCount   int64    `protobuf:"varint,12,opt,name=vetting_uptime_count,json=vettingUptimeCount,proto3" json:"vetting_uptime_count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReputationStatsResponse) Reset()         { *m = ReputationStatsResponse{} }
+func (m *ReputationStatsResponse) String() string { return proto.CompactTextString(m) }
+func (*ReputationStatsResponse) ProtoMessage()    {}
+func (*ReputationStatsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b35a2508345eddf0, []int{1}
+}
+func (m *ReputationStatsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReputationStatsResponse.Unmarshal(m, b)
+}
+func (m *ReputationStatsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReputationStatsResponse.Marshal(b, m, deterministic)
+}
+func (m *ReputationStatsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReputationStatsResponse.Merge(m, src)
+}
+func (m *ReputationStatsResponse) XXX_Size() int {
+	return xxx_messageInfo_ReputationStatsResponse.Size(m)
+}
+func (m *ReputationStatsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReputationStatsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReputationStatsResponse proto.InternalMessageInfo
+
+func (m *ReputationStatsResponse) GetAuditCount() int64 {
+	if m != nil {
+		return m.AuditCount
+	}
+	return 0
+}
+
+func (m *ReputationStatsResponse) GetAuditSuccessCount() int64 {
+	if m != nil {
+		return m.AuditSuccessCount
+	}
+	return 0
+}
+
+func (m *ReputationStatsResponse) GetAuditSuccessRatio() float64 {
+	if m != nil {
+		return m.AuditSuccessRatio
+	}
+	return 0
+}
+
+func (m *ReputationStatsResponse) GetUptimeCount() int64 {
+	if m != nil {
+		return m.UptimeCount
+	}
+	return 0
+}
+
+func (m *ReputationStatsResponse) GetUptimeSuccessCount() int64 {
+	if m != nil {
+		return m.UptimeSuccessCount
+	}
+	return 0
+}
+
+func (m *ReputationStatsResponse) GetUptimeRatio() float64 {
+	if m != nil {
+		return m.UptimeRatio
+	}
+	return 0
+}
+
+func (m *ReputationStatsResponse) GetAuditScore() float64 {
+	if m != nil {
+		return m.AuditScore
+	}
+	return 0
+}
+
+func (m *ReputationStatsResponse) GetUptimeScore() float64 {
+	if m != nil {
+		return m.UptimeScore
+	}
+	return 0
+}
+
+func (m *ReputationStatsResponse) GetDisqualified() *timestamp.Timestamp {
+	if m != nil {
+		return m.Disqualified
+	}
+	return nil
+}
+
+func (m *ReputationStatsResponse) GetVetted() *timestamp.Timestamp {
+	if m != nil {
+		return m.Vetted
+	}
+	return nil
+}
+
+func (m *ReputationStatsResponse) GetVettingAuditCount() int64 {
+	if m != nil {
+		return m.VettingAuditCount
+	}
+	return 0
+}
+
+func (m *ReputationStatsResponse) GetVettingUptimeCount() int64 {
+	if m != nil {
+		return m.VettingUptimeCount
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*ReputationStatsRequest)(nil), "reputation.ReputationStatsRequest")
+	proto.RegisterType((*ReputationStatsResponse)(nil), "reputation.ReputationStatsResponse")
+}
+
+func init() { proto.RegisterFile("reputation.proto", fileDescriptor_b35a2508345eddf0) }
+
+var fileDescriptor_b35a2508345eddf0 = []byte{
+	// 344 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x92, 0xcf, 0x4e, 0xc2, 0x40,
+	0x10, 0xc6, 0x2d, 0xff, 0xd4, 0x29, 0x07, 0x5d, 0x89, 0x36, 0x5c, 0xc0, 0x7a, 0xe1, 0x54, 0x08,
+	0xde, 0x4d, 0xd4, 0x37, 0x28, 0x7a, 0xf1, 0x82, 0xa5, 0x5d, 0xc8, 0x26, 0xd0, 0x2d, 0xdd, 0x59,
+	0x9f, 0xca, 0x87, 0x34, 0xbb, 0xb3, 0xd0, 0x36, 0xfe, 0xbb, 0x35, 0xf3, 0xfd, 0xfa, 0xcd, 0xce,
+	0x7c, 0x03, 0x17, 0x25, 0x2f, 0x34, 0x26, 0x28, 0x64, 0x1e, 0x15, 0xa5, 0x44, 0xc9, 0xa0, 0xaa,
+	0x0c, 0x47, 0x1b, 0x29, 0x37, 0x5b, 0x3e, 0xb5, 0xca, 0x4a, 0xaf, 0xa7, 0x28, 0x76, 0x5c, 0x61,
+	0xb2, 0x2b, 0x08, 0x0e, 0x03, 0xb8, 0x8e, 0x8f, 0xf8, 0x02, 0x13, 0x54, 0x31, 0xdf, 0x6b, 0xae,
+	0x30, 0xfc, 0xec, 0xc0, 0xcd, 0x37, 0x49, 0x15, 0x32, 0x57, 0x9c, 0x8d, 0xc0, 0x4f, 0x74, 0x26,
+	0x70, 0x99, 0x4a, 0x9d, 0x63, 0xe0, 0x8d, 0xbd, 0x49, 0x3b, 0x06, 0x5b, 0x7a, 0x36, 0x15, 0x16,
+	0xc1, 0x15, 0x01, 0x4a, 0xa7, 0x29, 0x57, 0xca, 0x81, 0x2d, 0x0b, 0x5e, 0x5a, 0x69, 0x41, 0xca,
+	0x2f, 0x7c, 0x69, 0xba, 0x06, 0xed, 0xb1, 0x37, 0xf1, 0x9a, 0x7c, 0x6c, 0x04, 0x76, 0x0b, 0x7d,
+	0x5d, 0x98, 0x59, 0x9c, 0x71, 0xc7, 0x1a, 0xfb, 0x54, 0x23, 0xcb, 0x19, 0x0c, 0x1c, 0xd2, 0x7c,
+	0x43, 0xd7, 0xa2, 0x8c, 0xb4, 0xc6, 0x23, 0x2a, 0x53, 0xea, 0xde, 0xb3, 0xdd, 0x9d, 0x29, 0xf5,
+	0x3d, 0x0e, 0xae, 0x52, 0x59, 0xf2, 0xe0, 0xd4, 0x12, 0x34, 0xf8, 0xc2, 0x54, 0x6a, 0x1e, 0x44,
+	0x9c, 0xd5, 0x3d, 0x08, 0x79, 0x80, 0x7e, 0x26, 0xd4, 0x5e, 0x27, 0x5b, 0xb1, 0x16, 0x3c, 0x0b,
+	0xce, 0xc7, 0xde, 0xc4, 0x9f, 0x0f, 0x23, 0x8a, 0x2a, 0x3a, 0x44, 0x15, 0xbd, 0x1c, 0xa2, 0x8a,
+	0x1b, 0x3c, 0x9b, 0x43, 0xef, 0x83, 0x23, 0xf2, 0x2c, 0x80, 0x7f, 0xff, 0x74, 0xa4, 0xd9, 0xaf,
+	0xf9, 0x12, 0xf9, 0x66, 0x59, 0x0f, 0xce, 0xa7, 0x3c, 0x9c, 0xf4, 0x58, 0xe5, 0x37, 0x83, 0xc1,
+	0x81, 0x6f, 0xec, 0xb9, 0x4f, 0xcb, 0x73, 0xda, 0x6b, 0xb5, 0xee, 0xf9, 0x3b, 0x40, 0x75, 0x2d,
+	0x2c, 0x86, 0xae, 0xbd, 0x18, 0x16, 0x46, 0xb5, 0xfb, 0xfc, 0xf9, 0xd2, 0x86, 0x77, 0x7f, 0x32,
+	0x74, 0x72, 0xe1, 0xc9, 0x53, 0xe7, 0xad, 0x55, 0xac, 0x56, 0x3d, 0x3b, 0xe5, 0xfd, 0xd7, 0x00,
+	0xd8, 0x08, 0xca, 0xe4, 0xf8, 0x02, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ReputationClient is the client API for Reputation service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ReputationClient interface {
+	// Stats returns the reputation of the calling storage node
+	Stats(ctx context.Context, in *ReputationStatsRequest, opts ...grpc.CallOption) (*ReputationStatsResponse, error)
+}
+
+type reputationClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewReputationClient(cc *grpc.ClientConn) ReputationClient {
+	return &reputationClient{cc}
+}
+
+func (c *reputationClient) Stats(ctx context.Context, in *ReputationStatsRequest, opts ...grpc.CallOption) (*ReputationStatsResponse, error) {
+	out := new(ReputationStatsResponse)
+	err := c.cc.Invoke(ctx, "/reputation.Reputation/Stats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ReputationServer is the server API for Reputation service.
+type ReputationServer interface {
+	// Stats returns the reputation of the calling storage node
+	Stats(context.Context, *ReputationStatsRequest) (*ReputationStatsResponse, error)
+}
+
+func RegisterReputationServer(s *grpc.Server, srv ReputationServer) {
+	s.RegisterService(&_Reputation_serviceDesc, srv)
+}
+
+func _Reputation_Stats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReputationStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReputationServer).Stats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reputation.Reputation/Stats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReputationServer).Stats(ctx, req.(*ReputationStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Reputation_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "reputation.Reputation",
+	HandlerType: (*ReputationServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Stats",
+			Handler:    _Reputation_Stats_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "reputation.proto",
+}
@@ -4,6 +4,7 @@
 package psserver
 
 import (
+	"strings"
 	"time"
 
 	"storj.io/storj/internal/memory"
@@ -11,7 +12,7 @@ import (
 
 // Config contains everything necessary for a server
 type Config struct {
-	Path string `help:"path to store data in" default:"$CONFDIR/storage"`
+	Path string `help:"path to store data in, a comma-separated list of paths spreads the pieces over several disks" default:"$CONFDIR/storage"`
 
 	WhitelistedSatelliteIDs string        `help:"a comma-separated list of approved satellite node ids" default:""`
 	SatelliteIDRestriction  bool          `help:"if true, only allow data from approved satellites" devDefault:"false" default:"true"`
@@ -22,3 +23,12 @@ type Config struct {
 	AgreementSenderCheckInterval time.Duration `help:"duration between agreement checks" default:"1h0m0s"`
 	CollectorInterval            time.Duration `help:"interval to check for expired pieces" default:"1h0m0s"`
 }
+
+// Paths returns the paths to store data in, the databases are kept in the first one.
+func (config Config) Paths() []string {
+	paths := strings.Split(config.Path, ",")
+	for i, path := range paths {
+		paths[i] = strings.TrimSpace(path)
+	}
+	return paths
+}
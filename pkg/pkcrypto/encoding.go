@@ -262,7 +262,7 @@ func PKIXExtensionToPEM(extension *pkix.Extension) ([]byte, error) {
 // PKIXExtensionToASN1 serializes a PKIX certificate extension to the
 // appropriate ASN.1 structure for such things. See RFC 5280, section 4.1.1.2.
 func PKIXExtensionToASN1(extension *pkix.Extension) ([]byte, error) {
-	extBytes, err := asn1.Marshal(extension)
+	extBytes, err := asn1.Marshal(*extension)
 	return extBytes, errs.Wrap(err)
 }
 
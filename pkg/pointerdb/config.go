@@ -24,6 +24,7 @@ type Config struct {
 	MaxInlineSegmentSize memory.Size `default:"8000" help:"maximum inline segment size"`
 	Overlay              bool        `default:"true" help:"toggle flag if overlay is enabled"`
 	BwExpiration         int         `default:"45"   help:"lifespan of bandwidth agreements in days"`
+	MaxLongTailMargin    int         `default:"0" help:"maximum number of nodes selected for new segments beyond their success threshold (0 means unlimited)"`
 }
 
 // NewStore returns database for storing pointer data
@@ -36,6 +37,8 @@ func NewStore(dbURLString string) (db storage.KeyValueStore, err error) {
 		db, err = boltdb.New(source, BoltPointerBucket)
 	} else if driver == "postgresql" || driver == "postgres" {
 		db, err = postgreskv.New(source)
+	} else if driver == "cockroach" {
+		db, err = postgreskv.NewCockroach(dbURLString)
 	} else {
 		err = Error.New("unsupported db scheme: %s", driver)
 	}
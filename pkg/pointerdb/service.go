@@ -45,6 +45,24 @@ func (s *Service) Put(path string, pointer *pb.Pointer) (err error) {
 	return nil
 }
 
+// CompareAndSwap replaces the pointer under path by newPointer when it is
+// still oldPointer, it returns storage.ErrValueChanged when the pointer was
+// changed in the meantime, e.g. by an upload or a concurrent repair. Unlike
+// Put, it doesn't update the creation date.
+func (s *Service) CompareAndSwap(path string, oldPointer, newPointer *pb.Pointer) (err error) {
+	oldPointerBytes, err := proto.Marshal(oldPointer)
+	if err != nil {
+		return err
+	}
+
+	newPointerBytes, err := proto.Marshal(newPointer)
+	if err != nil {
+		return err
+	}
+
+	return s.DB.CompareAndSwap([]byte(path), oldPointerBytes, newPointerBytes)
+}
+
 // Get gets pointer from db
 func (s *Service) Get(path string) (pointer *pb.Pointer, err error) {
 	pointerBytes, err := s.DB.Get([]byte(path))
@@ -156,3 +174,16 @@ func (s *Service) Iterate(prefix string, first string, recurse bool, reverse boo
 	}
 	return s.DB.Iterate(opts, f)
 }
+
+// IterateSnapshot iterates over items in db like Iterate, but over a
+// consistent view of the db, which doesn't change during the iteration
+func (s *Service) IterateSnapshot(prefix string, first string, recurse bool, reverse bool, f func(it storage.Iterator) error) (err error) {
+	opts := storage.IterateOptions{
+		Prefix:   storage.Key(prefix),
+		First:    storage.Key(first),
+		Recurse:  recurse,
+		Reverse:  reverse,
+		Snapshot: true,
+	}
+	return s.DB.Iterate(opts, f)
+}
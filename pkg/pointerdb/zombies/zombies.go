@@ -0,0 +1,229 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package zombies
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/zeebo/errs"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storage"
+)
+
+var (
+	mon = monkit.Package()
+
+	// Error is the default zombies errs class
+	Error = errs.Class("zombies error")
+)
+
+// Kind is the kind of inconsistency found for a segment
+type Kind string
+
+const (
+	// Orphaned is a segment of an object which has no last segment
+	Orphaned = Kind("orphaned")
+	// BrokenChain is a segment of an object which is missing some of its segments
+	BrokenChain = Kind("broken-chain")
+	// Invalid is a pointer which path or value can't be parsed
+	Invalid = Kind("invalid")
+)
+
+// Zombie is an inconsistent segment
+type Zombie struct {
+	Path   storj.Path
+	Kind   Kind
+	Reason string
+}
+
+// Report is the result of a detection
+type Report struct {
+	Segments int64
+	Objects  int64
+	Skipped  int64
+	Zombies  []Zombie
+}
+
+// Options configures a detection
+type Options struct {
+	// MinAge is the minimum age of the segments of an object, objects with
+	// younger segments are skipped as they may be still being uploaded
+	MinAge time.Duration
+	// Now is the time the age of segments is computed from
+	Now time.Time
+}
+
+// object contains the segments found for an object
+type object struct {
+	last     storj.Path
+	segments map[int64]storj.Path
+	newest   time.Time
+}
+
+// Detect scans the pointers of db for orphaned segments and inconsistent
+// object chains
+//
+// NB: the number of segments of an object is part of the encrypted stream info,
+// so only the segments below the highest segment found can be checked
+func Detect(ctx context.Context, db storage.KeyValueStore, opts Options) (_ *Report, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	report := &Report{}
+	objects := make(map[string]*object)
+
+	err = db.Iterate(storage.IterateOptions{Recurse: true, Snapshot: true},
+		func(it storage.Iterator) error {
+			var item storage.ListItem
+			for it.Next(&item) {
+				report.Segments++
+				path := storj.Path(item.Key)
+
+				pointer := &pb.Pointer{}
+				if err := proto.Unmarshal(item.Value, pointer); err != nil {
+					report.add(path, Invalid, fmt.Sprintf("unable to unmarshal pointer: %v", err))
+					continue
+				}
+
+				elements := storj.SplitPath(path)
+				if len(elements) < 3 {
+					report.add(path, Invalid, "path is not a segment path")
+					continue
+				}
+				index, ok := parseSegmentIndex(elements[1])
+				if !ok {
+					report.add(path, Invalid, fmt.Sprintf("invalid segment index %q", elements[1]))
+					continue
+				}
+
+				key := storj.JoinPaths(append([]string{elements[0]}, elements[2:]...)...)
+				obj, ok := objects[key]
+				if !ok {
+					obj = &object{segments: make(map[int64]storj.Path)}
+					objects[key] = obj
+				}
+				if index < 0 {
+					obj.last = path
+				} else {
+					obj.segments[index] = path
+				}
+
+				if created, err := ptypes.Timestamp(pointer.GetCreationDate()); err == nil && created.After(obj.newest) {
+					obj.newest = created
+				}
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	keys := make([]string, 0, len(objects))
+	for key := range objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		obj := objects[key]
+		report.Objects++
+
+		if opts.MinAge > 0 && opts.Now.Sub(obj.newest) < opts.MinAge {
+			report.Skipped++
+			continue
+		}
+
+		indexes := make([]int64, 0, len(obj.segments))
+		for index := range obj.segments {
+			indexes = append(indexes, index)
+		}
+		sort.Slice(indexes, func(i, k int) bool { return indexes[i] < indexes[k] })
+
+		if obj.last == "" {
+			for _, index := range indexes {
+				report.add(obj.segments[index], Orphaned, "object has no last segment")
+			}
+			continue
+		}
+
+		var missing []string
+		for index, next := int64(0), 0; next < len(indexes); index++ {
+			if indexes[next] == index {
+				next++
+				continue
+			}
+			missing = append(missing, "s"+strconv.FormatInt(index, 10))
+		}
+		if len(missing) == 0 {
+			continue
+		}
+
+		reason := "object is missing segments " + strings.Join(missing, ", ")
+		for _, index := range indexes {
+			report.add(obj.segments[index], BrokenChain, reason)
+		}
+		report.add(obj.last, BrokenChain, reason)
+	}
+
+	return report, nil
+}
+
+// add adds a zombie to the report
+func (report *Report) add(path storj.Path, kind Kind, reason string) {
+	report.Zombies = append(report.Zombies, Zombie{Path: path, Kind: kind, Reason: reason})
+}
+
+// WriteDeletionScript writes a shell script deleting the orphaned segments
+// and the segments of broken chains of the report, invalid pointers are
+// left for manual inspection
+func (report *Report) WriteDeletionScript(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	b.WriteString("# deletes the zombie segments found by detect-zombies\n")
+	b.WriteString("# usage: [SATELLITE=<satellite binary>] <script> <pointerdb database url>\n")
+	b.WriteString("set -e\n")
+	b.WriteString(`POINTERDB_URL="${1:?missing pointerdb database url}"` + "\n\n")
+
+	for _, zombie := range report.Zombies {
+		if zombie.Kind == Invalid {
+			fmt.Fprintf(&b, "# %s: %s: %s\n", zombie.Kind, strings.Replace(zombie.Path, "\n", " ", -1), zombie.Reason)
+			continue
+		}
+		fmt.Fprintf(&b, "\"${SATELLITE:-satellite}\" delete-segments --database-url \"$POINTERDB_URL\" %s # %s\n", shellQuote(zombie.Path), zombie.Kind)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// parseSegmentIndex parses the segment index element of a path, returning -1
+// for the last segment
+func parseSegmentIndex(element string) (int64, bool) {
+	if element == "l" {
+		return -1, true
+	}
+	if len(element) < 2 || element[0] != 's' {
+		return 0, false
+	}
+	index, err := strconv.ParseInt(element[1:], 10, 64)
+	if err != nil || index < 0 {
+		return 0, false
+	}
+	return index, true
+}
+
+// shellQuote quotes s for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
@@ -0,0 +1,90 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package zombies_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/pointerdb/zombies"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/teststore"
+)
+
+func TestDetect(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	now := time.Now()
+	db := teststore.New()
+
+	put := func(path string, created time.Time) {
+		creation, err := ptypes.TimestampProto(created)
+		require.NoError(t, err)
+		value, err := proto.Marshal(&pb.Pointer{
+			Type:          pb.Pointer_INLINE,
+			InlineSegment: []byte("data"),
+			CreationDate:  creation,
+		})
+		require.NoError(t, err)
+		require.NoError(t, db.Put(storage.Key(path), value))
+	}
+
+	old := now.Add(-48 * time.Hour)
+
+	// consistent objects
+	put("project/l/bucket/single", old)
+	put("project/s0/bucket/multi", old)
+	put("project/s1/bucket/multi", old)
+	put("project/l/bucket/multi", old)
+
+	// orphaned segments
+	put("project/s0/bucket/orphan", old)
+	put("project/s1/bucket/orphan", old)
+
+	// broken chain
+	put("project/s1/bucket/broken", old)
+	put("project/l/bucket/broken", old)
+
+	// recent upload without last segment
+	put("project/s0/bucket/uploading", now)
+
+	// invalid pointers
+	put("project/x/bucket/invalid", old)
+	require.NoError(t, db.Put(storage.Key("project/l/bucket/corrupted"), []byte{0xff}))
+
+	report, err := zombies.Detect(ctx, db, zombies.Options{MinAge: 24 * time.Hour, Now: now})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(11), report.Segments)
+	assert.Equal(t, int64(5), report.Objects)
+	assert.Equal(t, int64(1), report.Skipped)
+
+	kinds := map[string]zombies.Kind{}
+	for _, zombie := range report.Zombies {
+		kinds[zombie.Path] = zombie.Kind
+	}
+	assert.Equal(t, map[string]zombies.Kind{
+		"project/s0/bucket/orphan":   zombies.Orphaned,
+		"project/s1/bucket/orphan":   zombies.Orphaned,
+		"project/s1/bucket/broken":   zombies.BrokenChain,
+		"project/l/bucket/broken":    zombies.BrokenChain,
+		"project/x/bucket/invalid":   zombies.Invalid,
+		"project/l/bucket/corrupted": zombies.Invalid,
+	}, kinds)
+
+	var script bytes.Buffer
+	require.NoError(t, report.WriteDeletionScript(&script))
+	assert.Contains(t, script.String(), `delete-segments --database-url "$POINTERDB_URL" 'project/s0/bucket/orphan'`)
+	assert.Contains(t, script.String(), `delete-segments --database-url "$POINTERDB_URL" 'project/l/bucket/broken'`)
+	assert.NotContains(t, script.String(), `'project/x/bucket/invalid'`)
+}
@@ -4,11 +4,13 @@
 package process
 
 import (
+	"crypto/subtle"
 	"flag"
 	"fmt"
 	"net"
 	"net/http"
 	"net/http/pprof"
+	"strings"
 
 	"go.uber.org/zap"
 	monkit "gopkg.in/spacemonkeygo/monkit.v2"
@@ -16,7 +18,8 @@ import (
 )
 
 var (
-	debugAddr = flag.String("debug.addr", "localhost:0", "address to listen on for debug endpoints")
+	debugAddr  = flag.String("debug.addr", "localhost:0", "address to listen on for debug endpoints")
+	debugToken = flag.String("debug.token", "", "token required for the profiling and admin debug endpoints, empty only allows them from localhost")
 )
 
 func init() {
@@ -25,14 +28,17 @@ func init() {
 	*http.DefaultServeMux = http.ServeMux{}
 }
 
-func initDebug(logger *zap.Logger, r *monkit.Registry) (err error) {
+func initDebug(logger *zap.Logger, r *monkit.Registry, levels *logLevelSet) (err error) {
+	auth := debugAuth{token: *debugToken}
+
 	var mux http.ServeMux
-	mux.HandleFunc("/debug/pprof/", pprof.Index)
-	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
-	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
-	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
-	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/pprof/", auth.require(http.HandlerFunc(pprof.Index)))
+	mux.Handle("/debug/pprof/cmdline", auth.require(http.HandlerFunc(pprof.Cmdline)))
+	mux.Handle("/debug/pprof/profile", auth.require(http.HandlerFunc(pprof.Profile)))
+	mux.Handle("/debug/pprof/symbol", auth.require(http.HandlerFunc(pprof.Symbol)))
+	mux.Handle("/debug/pprof/trace", auth.require(http.HandlerFunc(pprof.Trace)))
 	mux.Handle("/mon/", http.StripPrefix("/mon", present.HTTP(r)))
+	mux.Handle("/log/level", auth.require(levels))
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		_, _ = fmt.Fprintln(w, "OK")
 	})
@@ -48,3 +54,41 @@ func initDebug(logger *zap.Logger, r *monkit.Registry) (err error) {
 	}()
 	return nil
 }
+
+// debugAuth restricts access to debug endpoints that expose or change the
+// internals of the process.
+type debugAuth struct {
+	token string
+}
+
+// require only serves requests to next that carry the token as a bearer
+// token. Without a configured token only requests from localhost are served.
+func (auth debugAuth) require(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !auth.allowed(r) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allowed checks whether the request is authorized
+func (auth debugAuth) allowed(r *http.Request) bool {
+	if auth.token == "" {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			return false
+		}
+		ip := net.ParseIP(host)
+		return ip != nil && ip.IsLoopback()
+	}
+
+	const prefix = "Bearer "
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, prefix) {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(header[len(prefix):]), []byte(auth.token)) == 1
+}
@@ -0,0 +1,41 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package process
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebugAuth(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	request := func(auth debugAuth, remoteAddr, header string) int {
+		req := httptest.NewRequest(http.MethodGet, "/debug/pprof/heap", nil)
+		req.RemoteAddr = remoteAddr
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		rec := httptest.NewRecorder()
+		auth.require(handler).ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// without a token only localhost is allowed
+	local := debugAuth{}
+	assert.Equal(t, http.StatusOK, request(local, "127.0.0.1:5000", ""))
+	assert.Equal(t, http.StatusOK, request(local, "[::1]:5000", ""))
+	assert.Equal(t, http.StatusUnauthorized, request(local, "10.0.0.1:5000", ""))
+	assert.Equal(t, http.StatusUnauthorized, request(local, "10.0.0.1:5000", "Bearer "))
+
+	// with a token it's required from everywhere
+	token := debugAuth{token: "secret"}
+	assert.Equal(t, http.StatusOK, request(token, "10.0.0.1:5000", "Bearer secret"))
+	assert.Equal(t, http.StatusUnauthorized, request(token, "10.0.0.1:5000", "Bearer wrong"))
+	assert.Equal(t, http.StatusUnauthorized, request(token, "127.0.0.1:5000", ""))
+	assert.Equal(t, http.StatusUnauthorized, request(token, "127.0.0.1:5000", "secret"))
+}
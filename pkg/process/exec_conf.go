@@ -152,6 +152,13 @@ func cleanup(cmd *cobra.Command) {
 		return
 	}
 	cmd.RunE = func(cmd *cobra.Command, args []string) (err error) {
+		// tracing has to start before the root task to observe its trace
+		stopTracing, err := initTracing(monkit.Default)
+		if err != nil {
+			return err
+		}
+		defer stopTracing()
+
 		ctx := context.Background()
 		defer mon.TaskNamed("root")(&ctx)(&err)
 
@@ -195,7 +202,7 @@ func cleanup(cmd *cobra.Command) {
 			}
 		}
 
-		logger, err := newLogger()
+		logger, levels, err := newLogger()
 		if err != nil {
 			return err
 		}
@@ -224,11 +231,14 @@ func cleanup(cmd *cobra.Command) {
 			logger.Sugar().Infof("Invalid configuration file value for key: %s", key)
 		}
 
-		err = initDebug(logger, monkit.Default)
+		err = initDebug(logger, monkit.Default, levels)
 		if err != nil {
 			logger.Error("failed to start debug endpoints", zap.Error(err))
 		}
 
+		// the log levels are reloaded together with the configuration
+		ctx = context.WithValue(ctx, logLevelsKey{}, levels)
+
 		contextMtx.Lock()
 		contexts[cmd] = ctx
 		contextMtx.Unlock()
@@ -242,6 +252,7 @@ func cleanup(cmd *cobra.Command) {
 		if err != nil {
 			_, _ = fmt.Fprintf(os.Stderr, "Fatal error: %v\n", err)
 			logger.Sugar().Debugf("Fatal error: %+v", err)
+			stopTracing()
 			_ = logger.Sync()
 			os.Exit(1)
 		}
@@ -11,21 +11,68 @@ import (
 	"github.com/zeebo/errs"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+
+	"storj.io/storj/internal/memory"
 )
 
 var (
 	// Error is a process error class
 	Error = errs.Class("process error")
 
-	logLevel    = zap.LevelFlag("log.level", zapcore.WarnLevel, "the minimum log level to log")
-	logDev      = flag.Bool("log.development", false, "if true, set logging to development mode")
-	logCaller   = flag.Bool("log.caller", false, "if true, log function filename and line number")
-	logStack    = flag.Bool("log.stack", false, "if true, log stack traces")
-	logEncoding = flag.String("log.encoding", "console", "configures log encoding. can either be 'console' or 'json'")
-	logOutput   = flag.String("log.output", "stderr", "can be stdout, stderr, or a filename")
+	logLevel      = zap.LevelFlag("log.level", zapcore.WarnLevel, "the minimum log level to log")
+	logLevels     = flag.String("log.levels", "", "comma separated minimum log levels of subsystems, overriding log.level, e.g. 'kademlia=info,overlay=debug'")
+	logDev        = flag.Bool("log.development", false, "if true, set logging to development mode")
+	logCaller     = flag.Bool("log.caller", false, "if true, log function filename and line number")
+	logStack      = flag.Bool("log.stack", false, "if true, log stack traces")
+	logEncoding   = flag.String("log.encoding", "console", "configures log encoding. can either be 'console' or 'json'")
+	logOutput     = flag.String("log.output", "stderr", "can be stdout, stderr, or a filename")
+	logMaxSize    = 100 * memory.MiB
+	logMaxBackups = flag.Int("log.max-backups", 5, "number of rotated log files to keep when logging to a file")
 )
 
-func newLogger() (*zap.Logger, error) {
+func init() {
+	flag.Var(&logMaxSize, "log.max-size", "size at which the log file is rotated when logging to a file, 0 disables rotation")
+}
+
+// newLogger creates the process logger and the levels that control it
+func newLogger() (*zap.Logger, *logLevelSet, error) {
+	levels, err := parseLogLevels(*logLevel, *logLevels)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encoder, err := newLogEncoder(*logEncoding)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output, err := openLogOutput(*logOutput, logMaxSize.Int64(), *logMaxBackups)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	options := []zap.Option{zap.ErrorOutput(output)}
+	if *logDev {
+		options = append(options, zap.Development())
+	}
+	if *logCaller {
+		options = append(options, zap.AddCaller())
+	}
+	if *logStack {
+		stackLevel := zapcore.ErrorLevel
+		if *logDev {
+			stackLevel = zapcore.WarnLevel
+		}
+		options = append(options, zap.AddStacktrace(stackLevel))
+	}
+
+	// the levels decide what is logged, the core itself logs everything
+	core := zapcore.NewCore(encoder, output, zapcore.DebugLevel)
+	return zap.New(levels.core(core), options...), levels, nil
+}
+
+// newLogEncoder creates the encoder for the named encoding
+func newLogEncoder(encoding string) (zapcore.Encoder, error) {
 	levelEncoder := zapcore.CapitalColorLevelEncoder
 	if runtime.GOOS == "windows" {
 		levelEncoder = zapcore.CapitalLevelEncoder
@@ -37,26 +84,40 @@ func newLogger() (*zap.Logger, error) {
 		timeKey = ""
 	}
 
-	return zap.Config{
-		Level:             zap.NewAtomicLevelAt(*logLevel),
-		Development:       *logDev,
-		DisableCaller:     !*logCaller,
-		DisableStacktrace: !*logStack,
-		Encoding:          *logEncoding,
-		EncoderConfig: zapcore.EncoderConfig{
-			TimeKey:        timeKey,
-			LevelKey:       "L",
-			NameKey:        "N",
-			CallerKey:      "C",
-			MessageKey:     "M",
-			StacktraceKey:  "S",
-			LineEnding:     zapcore.DefaultLineEnding,
-			EncodeLevel:    levelEncoder,
-			EncodeTime:     zapcore.ISO8601TimeEncoder,
-			EncodeDuration: zapcore.StringDurationEncoder,
-			EncodeCaller:   zapcore.ShortCallerEncoder,
-		},
-		OutputPaths:      []string{*logOutput},
-		ErrorOutputPaths: []string{*logOutput},
-	}.Build()
+	config := zapcore.EncoderConfig{
+		TimeKey:        timeKey,
+		LevelKey:       "L",
+		NameKey:        "N",
+		CallerKey:      "C",
+		MessageKey:     "M",
+		StacktraceKey:  "S",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    levelEncoder,
+		EncodeTime:     zapcore.ISO8601TimeEncoder,
+		EncodeDuration: zapcore.StringDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+	}
+
+	switch encoding {
+	case "console":
+		return zapcore.NewConsoleEncoder(config), nil
+	case "json":
+		// colors don't belong into machine readable logs
+		config.EncodeLevel = zapcore.CapitalLevelEncoder
+		return zapcore.NewJSONEncoder(config), nil
+	default:
+		return nil, Error.New("unknown log encoding %q", encoding)
+	}
+}
+
+// openLogOutput opens the output for the logs, files are rotated once they reach maxSize
+func openLogOutput(output string, maxSize int64, maxBackups int) (zapcore.WriteSyncer, error) {
+	switch output {
+	case "stdout":
+		return zapcore.Lock(os.Stdout), nil
+	case "stderr":
+		return zapcore.Lock(os.Stderr), nil
+	default:
+		return openRotatingFile(output, maxSize, maxBackups)
+	}
 }
@@ -0,0 +1,146 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package process
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"storj.io/storj/internal/testcontext"
+)
+
+func TestLogLevels(t *testing.T) {
+	for _, invalid := range []string{"overlay", "=debug", "overlay=verbose"} {
+		_, err := parseLogLevels(zapcore.WarnLevel, invalid)
+		assert.Error(t, err, invalid)
+	}
+
+	levels, err := parseLogLevels(zapcore.WarnLevel, "overlay=debug, overlay.cache=error")
+	require.NoError(t, err)
+
+	assert.Equal(t, zapcore.WarnLevel, levels.Level("kademlia"))
+	assert.Equal(t, zapcore.WarnLevel, levels.Level("overlayx"))
+	assert.Equal(t, zapcore.DebugLevel, levels.Level("overlay"))
+	assert.Equal(t, zapcore.DebugLevel, levels.Level("overlay.service"))
+	assert.Equal(t, zapcore.ErrorLevel, levels.Level("overlay.cache.db"))
+
+	observed, logs := observer.New(zapcore.DebugLevel)
+	log := zap.New(levels.core(observed))
+
+	log.Named("kademlia").Info("hidden")
+	log.Named("kademlia").Warn("kademlia warning")
+	log.Named("overlay").Debug("overlay debug")
+	log.Named("overlay").Named("cache").Warn("hidden")
+	assert.Equal(t, 2, logs.Len())
+	assert.Equal(t, 1, logs.FilterMessage("kademlia warning").Len())
+	assert.Equal(t, 1, logs.FilterMessage("overlay debug").Len())
+
+	server := httptest.NewServer(levels)
+	defer server.Close()
+
+	change := func(subsystem, level string) *http.Response {
+		data, err := json.Marshal(logLevelChange{Subsystem: subsystem, Level: level})
+		require.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPut, server.URL, bytes.NewReader(data))
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp
+	}
+
+	assert.Equal(t, http.StatusOK, change("kademlia", "debug").StatusCode)
+	assert.Equal(t, http.StatusOK, change("", "error").StatusCode)
+	assert.Equal(t, http.StatusOK, change("overlay", "").StatusCode)
+	assert.Equal(t, http.StatusBadRequest, change("", "").StatusCode)
+	assert.Equal(t, http.StatusBadRequest, change("kademlia", "verbose").StatusCode)
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	var state logLevelsState
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&state))
+	require.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, zapcore.ErrorLevel, state.Level)
+	assert.Equal(t, map[string]zapcore.Level{
+		"kademlia":      zapcore.DebugLevel,
+		"overlay.cache": zapcore.ErrorLevel,
+	}, state.Subsystems)
+
+	log.Named("kademlia").Debug("kademlia debug")
+	log.Named("overlay").Warn("hidden")
+	assert.Equal(t, 1, logs.FilterMessage("kademlia debug").Len())
+	assert.Equal(t, 3, logs.Len())
+}
+
+func TestRotatingFile(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	path := filepath.Join(ctx.Dir("logs"), "storj.log")
+	file, err := openRotatingFile(path, 10, 2)
+	require.NoError(t, err)
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := file.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, file.Close())
+
+	read := func(path string) string {
+		data, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	assert.Equal(t, "fourth\n", read(path))
+	assert.Equal(t, "third\n", read(path+".1"))
+	assert.Equal(t, "second\n", read(path+".2"))
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err))
+
+	// reopening continues the current file
+	file, err = openRotatingFile(path, 100, 2)
+	require.NoError(t, err)
+	_, err = file.Write([]byte("fifth\n"))
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	assert.Equal(t, "fourth\nfifth\n", read(path))
+}
+
+func TestRotatingFileRenameFailure(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	path := filepath.Join(ctx.Dir("logs"), "storj.log")
+	file, err := openRotatingFile(path, 10, 1)
+	require.NoError(t, err)
+
+	// the backup can't be replaced by the log file
+	require.NoError(t, os.MkdirAll(filepath.Join(path+".1", "dir"), 0755))
+
+	_, err = file.Write([]byte("first\n"))
+	require.NoError(t, err)
+	_, err = file.Write([]byte("second\n"))
+	require.Error(t, err)
+	_, err = file.Write([]byte("third\n"))
+	require.Error(t, err)
+	require.NoError(t, file.Close())
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "first\nsecond\nthird\n", string(data))
+}
@@ -0,0 +1,210 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package process
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// logLevelSet contains the minimum log levels of the process and its subsystems.
+//
+// A subsystem is identified by a logger name, its level also applies to all
+// the loggers named below it, e.g. "overlay" applies to "overlay.cache".
+type logLevelSet struct {
+	mu         sync.RWMutex
+	root       zapcore.Level
+	subsystems map[string]zapcore.Level
+}
+
+// parseLogLevels parses the comma separated subsystem=level list in spec
+func parseLogLevels(root zapcore.Level, spec string) (*logLevelSet, error) {
+	levels := &logLevelSet{
+		root:       root,
+		subsystems: map[string]zapcore.Level{},
+	}
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		tokens := strings.SplitN(entry, "=", 2)
+		if len(tokens) != 2 || tokens[0] == "" {
+			return nil, Error.New("invalid subsystem log level %q", entry)
+		}
+
+		var level zapcore.Level
+		if err := level.UnmarshalText([]byte(tokens[1])); err != nil {
+			return nil, Error.New("invalid subsystem log level %q: %v", entry, err)
+		}
+		levels.subsystems[tokens[0]] = level
+	}
+
+	return levels, nil
+}
+
+// Level returns the minimum level of the named logger
+func (levels *logLevelSet) Level(name string) zapcore.Level {
+	levels.mu.RLock()
+	defer levels.mu.RUnlock()
+
+	level, matched := levels.root, ""
+	for subsystem, subsystemLevel := range levels.subsystems {
+		if len(subsystem) <= len(matched) {
+			continue
+		}
+		if name == subsystem || strings.HasPrefix(name, subsystem+".") {
+			level, matched = subsystemLevel, subsystem
+		}
+	}
+	return level
+}
+
+// Enabled returns whether any logger logs at lvl
+func (levels *logLevelSet) Enabled(lvl zapcore.Level) bool {
+	levels.mu.RLock()
+	defer levels.mu.RUnlock()
+
+	if levels.root.Enabled(lvl) {
+		return true
+	}
+	for _, level := range levels.subsystems {
+		if level.Enabled(lvl) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetLevel changes the minimum level of a subsystem, an empty subsystem
+// changes the level of the process.
+func (levels *logLevelSet) SetLevel(subsystem string, level zapcore.Level) {
+	levels.mu.Lock()
+	defer levels.mu.Unlock()
+
+	if subsystem == "" {
+		levels.root = level
+		return
+	}
+	levels.subsystems[subsystem] = level
+}
+
+// ResetLevel makes the subsystem use the level of the process again
+func (levels *logLevelSet) ResetLevel(subsystem string) {
+	levels.mu.Lock()
+	defer levels.mu.Unlock()
+
+	delete(levels.subsystems, subsystem)
+}
+
+// replace replaces all the levels with the ones of other
+func (levels *logLevelSet) replace(other *logLevelSet) {
+	other.mu.RLock()
+	root := other.root
+	subsystems := make(map[string]zapcore.Level, len(other.subsystems))
+	for subsystem, level := range other.subsystems {
+		subsystems[subsystem] = level
+	}
+	other.mu.RUnlock()
+
+	levels.mu.Lock()
+	defer levels.mu.Unlock()
+	levels.root = root
+	levels.subsystems = subsystems
+}
+
+// logLevelsState is the representation of the levels served by the admin endpoint
+type logLevelsState struct {
+	Level      zapcore.Level            `json:"level"`
+	Subsystems map[string]zapcore.Level `json:"subsystems"`
+}
+
+// logLevelChange is a request to change a level through the admin endpoint.
+// An empty level resets the subsystem to the level of the process.
+type logLevelChange struct {
+	Subsystem string `json:"subsystem"`
+	Level     string `json:"level"`
+}
+
+// ServeHTTP reports the levels on GET and changes a level on PUT, e.g.
+//
+//	curl -X PUT -d '{"subsystem": "overlay", "level": "debug"}' localhost:port/log/level
+func (levels *logLevelSet) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var change logLevelChange
+		if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if change.Level == "" {
+			if change.Subsystem == "" {
+				http.Error(w, "missing level", http.StatusBadRequest)
+				return
+			}
+			levels.ResetLevel(change.Subsystem)
+			break
+		}
+
+		var level zapcore.Level
+		if err := level.UnmarshalText([]byte(change.Level)); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		levels.SetLevel(change.Subsystem, level)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	levels.mu.RLock()
+	state := logLevelsState{
+		Level:      levels.root,
+		Subsystems: make(map[string]zapcore.Level, len(levels.subsystems)),
+	}
+	for subsystem, level := range levels.subsystems {
+		state.Subsystems[subsystem] = level
+	}
+	levels.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(state)
+}
+
+// core wraps core to only log the entries enabled by the levels
+func (levels *logLevelSet) core(core zapcore.Core) zapcore.Core {
+	return &leveledCore{Core: core, levels: levels}
+}
+
+// leveledCore filters the entries of a core by the level of their logger
+type leveledCore struct {
+	zapcore.Core
+	levels *logLevelSet
+}
+
+// Enabled implements zapcore.LevelEnabler
+func (core *leveledCore) Enabled(lvl zapcore.Level) bool {
+	return core.levels.Enabled(lvl)
+}
+
+// With implements zapcore.Core
+func (core *leveledCore) With(fields []zapcore.Field) zapcore.Core {
+	return &leveledCore{Core: core.Core.With(fields), levels: core.levels}
+}
+
+// Check implements zapcore.Core
+func (core *leveledCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !core.levels.Level(entry.LoggerName).Enabled(entry.Level) {
+		return checked
+	}
+	return core.Core.Check(entry, checked)
+}
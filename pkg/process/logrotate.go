@@ -0,0 +1,126 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package process
+
+import (
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/zeebo/errs"
+)
+
+// rotatingFile is a log file that is moved aside once it reaches maxSize.
+//
+// The previous files are kept as path.1, path.2, ... up to maxBackups,
+// where path.1 is the most recent one.
+type rotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// openRotatingFile opens or creates the log file at path.
+// Files are never rotated when maxSize is 0.
+func openRotatingFile(path string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	if maxSize < 0 || maxBackups < 0 {
+		return nil, Error.New("invalid log rotation: max size %d, max backups %d", maxSize, maxBackups)
+	}
+
+	rotating := &rotatingFile{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+	if err := rotating.open(); err != nil {
+		return nil, err
+	}
+	return rotating, nil
+}
+
+// open opens the file at path for appending
+func (rotating *rotatingFile) open() error {
+	file, err := os.OpenFile(rotating.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		return Error.Wrap(errs.Combine(err, file.Close()))
+	}
+
+	rotating.file = file
+	rotating.size = stat.Size()
+	return nil
+}
+
+// Write writes p to the file, rotating it first when p doesn't fit.
+// p is still written when the rotation fails, the rotation is retried on
+// the next write.
+func (rotating *rotatingFile) Write(p []byte) (n int, err error) {
+	rotating.mu.Lock()
+	defer rotating.mu.Unlock()
+
+	var rotateErr error
+	if rotating.maxSize > 0 && rotating.size > 0 && rotating.size+int64(len(p)) > rotating.maxSize {
+		rotateErr = rotating.rotate()
+	}
+
+	n, err = rotating.file.Write(p)
+	rotating.size += int64(n)
+	return n, errs.Combine(rotateErr, err)
+}
+
+// Sync commits the file to stable storage
+func (rotating *rotatingFile) Sync() error {
+	rotating.mu.Lock()
+	defer rotating.mu.Unlock()
+
+	return rotating.file.Sync()
+}
+
+// Close closes the file
+func (rotating *rotatingFile) Close() error {
+	rotating.mu.Lock()
+	defer rotating.mu.Unlock()
+
+	return rotating.file.Close()
+}
+
+// rotate moves the current file to the backups and starts a new one. The
+// file at path is reopened even when moving it fails, so that the later
+// writes aren't lost.
+func (rotating *rotatingFile) rotate() error {
+	err := rotating.moveAside()
+	return errs.Combine(err, rotating.open())
+}
+
+// moveAside closes the current file and moves it to the backups
+func (rotating *rotatingFile) moveAside() error {
+	if err := rotating.file.Close(); err != nil {
+		return Error.Wrap(err)
+	}
+
+	if rotating.maxBackups == 0 {
+		return Error.Wrap(os.Remove(rotating.path))
+	}
+
+	for i := rotating.maxBackups - 1; i > 0; i-- {
+		err := os.Rename(rotating.backup(i), rotating.backup(i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return Error.Wrap(err)
+		}
+	}
+	return Error.Wrap(os.Rename(rotating.path, rotating.backup(1)))
+}
+
+// backup returns the path of the i-th most recent backup
+func (rotating *rotatingFile) backup(i int) string {
+	return rotating.path + "." + strconv.Itoa(i)
+}
@@ -0,0 +1,158 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package process
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// logLevelsKey is the context key of the log levels of the process
+type logLevelsKey struct{}
+
+// ReloadBinder binds a fresh configuration value to flags, the same way the
+// configuration of the command is bound, and returns the function that applies
+// the loaded configuration to the running process.
+//
+// apply must validate the configuration and leave the process unchanged when it
+// returns an error.
+type ReloadBinder func(flags *pflag.FlagSet) (apply func(ctx context.Context) error)
+
+// ReloadOnSignal reloads the configuration of cmd every time the process
+// receives SIGHUP, until ctx is canceled.
+//
+// Flags set on the command line keep their value. Besides the configuration
+// applied by bind, the log levels are reloaded. A configuration that fails to
+// load or apply is rejected as a whole and the process keeps running with the
+// previous one.
+func ReloadOnSignal(ctx context.Context, cmd *cobra.Command, bind ReloadBinder) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(signals)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signals:
+			}
+
+			zap.S().Info("Got SIGHUP, reloading configuration")
+			if err := Reload(ctx, cmd, bind); err != nil {
+				zap.S().Errorf("Configuration reload failed, keeping the previous configuration: %v", err)
+			}
+		}
+	}()
+}
+
+// Reload loads the configuration of cmd from its config file and environment
+// and applies it with bind.
+func Reload(ctx context.Context, cmd *cobra.Command, bind ReloadBinder) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	flags := pflag.NewFlagSet(cmd.Name(), pflag.ContinueOnError)
+	apply := bind(flags)
+
+	vip, err := readConfig(cmd)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if err := loadFlags(vip, cmd.Flags(), flags); err != nil {
+		return err
+	}
+
+	levels, _ := ctx.Value(logLevelsKey{}).(*logLevelSet)
+	var reloadedLevels *logLevelSet
+	if levels != nil {
+		reloadedLevels, err = reloadLogLevels(vip, cmd.Flags())
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := apply(ctx); err != nil {
+		return err
+	}
+
+	if levels != nil {
+		levels.replace(reloadedLevels)
+	}
+	return nil
+}
+
+// readConfig reads the config file and the environment the same way as when
+// the command was started
+func readConfig(cmd *cobra.Command) (*viper.Viper, error) {
+	vip := viper.New()
+	vip.SetEnvPrefix("storj")
+	vip.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
+	vip.AutomaticEnv()
+
+	cfgFlag := cmd.Flags().Lookup("config-dir")
+	if cfgFlag != nil && cfgFlag.Value.String() != "" {
+		path := filepath.Join(os.ExpandEnv(cfgFlag.Value.String()), "config.yaml")
+		if fileExists(path) {
+			vip.SetConfigFile(path)
+			if err := vip.ReadInConfig(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return vip, nil
+}
+
+// loadFlags sets flags from the configuration in vip, flags changed on the
+// command line in current take precedence.
+func loadFlags(vip *viper.Viper, current, flags *pflag.FlagSet) error {
+	var group errs.Group
+	flags.VisitAll(func(f *pflag.Flag) {
+		if original := current.Lookup(f.Name); original != nil && original.Changed {
+			group.Add(flags.Set(f.Name, original.Value.String()))
+			return
+		}
+		if vip.IsSet(f.Name) {
+			if err := flags.Set(f.Name, vip.GetString(f.Name)); err != nil {
+				group.Add(Error.New("invalid value for %s: %v", f.Name, err))
+			}
+		}
+	})
+	return group.Err()
+}
+
+// reloadLogLevels parses the log levels from the configuration in vip, flags
+// changed on the command line in current take precedence.
+func reloadLogLevels(vip *viper.Viper, current *pflag.FlagSet) (*logLevelSet, error) {
+	value := func(name string) string {
+		original := current.Lookup(name)
+		switch {
+		case original != nil && original.Changed:
+			return original.Value.String()
+		case vip.IsSet(name):
+			return vip.GetString(name)
+		case original != nil:
+			return original.DefValue
+		default:
+			return flag.Lookup(name).DefValue
+		}
+	}
+
+	var root zapcore.Level
+	if err := root.UnmarshalText([]byte(value("log.level"))); err != nil {
+		return nil, Error.New("invalid value for log.level: %v", err)
+	}
+	return parseLogLevels(root, value("log.levels"))
+}
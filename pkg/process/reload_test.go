@@ -0,0 +1,97 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package process
+
+import (
+	"context"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/pkg/cfgstruct"
+)
+
+type reloadConfig struct {
+	Interval time.Duration `help:"interval" default:"1m"`
+	Limit    int           `help:"limit" default:"10"`
+	Trusted  string        `help:"trusted" default:""`
+}
+
+func TestReload(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	confDir := ctx.Dir("config")
+	writeConfig := func(config string) {
+		err := ioutil.WriteFile(filepath.Join(confDir, "config.yaml"), []byte(config), 0644)
+		require.NoError(t, err)
+	}
+
+	var runCfg reloadConfig
+	cmd := &cobra.Command{Use: "run"}
+	cmd.Flags().String("config-dir", confDir, "")
+	cmd.Flags().AddGoFlag(flag.Lookup("log.level"))
+	cmd.Flags().AddGoFlag(flag.Lookup("log.levels"))
+	cfgstruct.Bind(cmd.Flags(), &runCfg, false)
+	require.NoError(t, cmd.Flags().Parse([]string{"--limit=20"}))
+
+	levels, err := parseLogLevels(zapcore.WarnLevel, "")
+	require.NoError(t, err)
+	reloadCtx := context.WithValue(ctx, logLevelsKey{}, levels)
+
+	var applied *reloadConfig
+	bind := func(flags *pflag.FlagSet) func(context.Context) error {
+		var reloadCfg reloadConfig
+		cfgstruct.Bind(flags, &reloadCfg, false)
+		return func(ctx context.Context) error {
+			if reloadCfg.Interval <= 0 {
+				return Error.New("interval must be positive")
+			}
+			applied = &reloadCfg
+			return nil
+		}
+	}
+
+	writeConfig("interval: 5m\nlimit: 30\ntrusted: a,b\nlog.level: info\nlog.levels: overlay=debug\n")
+	require.NoError(t, Reload(reloadCtx, cmd, bind))
+	require.NotNil(t, applied)
+	assert.Equal(t, reloadConfig{
+		Interval: 5 * time.Minute,
+		Limit:    20, // set on the command line
+		Trusted:  "a,b",
+	}, *applied)
+	assert.Equal(t, zapcore.InfoLevel, levels.Level("kademlia"))
+	assert.Equal(t, zapcore.DebugLevel, levels.Level("overlay"))
+
+	for _, invalid := range []string{
+		"interval: 0s\nlog.level: error\n",
+		"interval: later\nlog.level: error\n",
+		"interval: 1m\nlog.level: loud\n",
+		"interval: 1m\nlog.levels: overlay\n",
+	} {
+		applied = nil
+		writeConfig(invalid)
+		assert.Error(t, Reload(reloadCtx, cmd, bind), invalid)
+		assert.Nil(t, applied, invalid)
+
+		// the previous levels are kept
+		assert.Equal(t, zapcore.InfoLevel, levels.Level("kademlia"), invalid)
+		assert.Equal(t, zapcore.DebugLevel, levels.Level("overlay"), invalid)
+	}
+
+	// removed settings return to their defaults
+	writeConfig("interval: 2m\n")
+	require.NoError(t, Reload(reloadCtx, cmd, bind))
+	assert.Equal(t, reloadConfig{Interval: 2 * time.Minute, Limit: 20}, *applied)
+	assert.Equal(t, zapcore.WarnLevel, levels.Level("overlay"))
+}
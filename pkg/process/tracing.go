@@ -0,0 +1,64 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package process
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"go.uber.org/zap"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/pkg/telemetry"
+	"storj.io/storj/pkg/tracing"
+)
+
+var (
+	tracingCollector  = flag.String("tracing.collector", "", "OTLP/HTTP url to send trace spans to, e.g. http://localhost:4318/v1/traces, empty disables tracing")
+	tracingApp        = flag.String("tracing.app", filepath.Base(os.Args[0]), "service name for trace identification")
+	tracingSampleRate = flag.Float64("tracing.sample-rate", 1, "fraction of traces, between 0 and 1, started by this process that are sent")
+	tracingInterval   = flag.Duration("tracing.interval", tracing.DefaultInterval, "how frequently to send finished spans")
+	tracingBufferSize = flag.Int("tracing.buffer-size", tracing.DefaultBufferSize, "maximum number of finished spans kept between sends")
+)
+
+// initTracing starts exporting the traces of r to the configured collector.
+// The returned function stops the export after sending the remaining spans.
+func initTracing(r *monkit.Registry) (stop func(), err error) {
+	if *tracingCollector == "" {
+		return func() {}, nil
+	}
+
+	exporter, err := tracing.NewExporter(*tracingCollector, tracing.ExporterOpts{
+		Interval:    *tracingInterval,
+		Application: *tracingApp,
+		Instance:    telemetry.DefaultInstanceID(),
+		SampleRate:  *tracingSampleRate,
+		BufferSize:  *tracingBufferSize,
+		Registry:    r,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		exporter.Run(ctx)
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			cancel()
+			<-done
+			if err := exporter.Close(); err != nil {
+				zap.S().Errorf("failed sending spans: %v", err)
+			}
+		})
+	}, nil
+}
@@ -0,0 +1,72 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package revocation publishes certificate revocations to other peers and
+// stores the revocations published by them.
+package revocation
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/pkg/identity"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/peertls/extensions"
+)
+
+var (
+	mon = monkit.Package()
+	// Error is the default error class for revocation publication
+	Error = errs.Class("revocation error")
+)
+
+// Endpoint implements pb.RevocationsServer
+type Endpoint struct {
+	log *zap.Logger
+	db  extensions.RevocationDB
+}
+
+// NewEndpoint creates an endpoint that stores the published revocations in db
+func NewEndpoint(log *zap.Logger, db extensions.RevocationDB) *Endpoint {
+	return &Endpoint{
+		log: log,
+		db:  db,
+	}
+}
+
+// Revoke stores the revocation when it is signed by the CA of the calling peer
+func (endpoint *Endpoint) Revoke(ctx context.Context, req *pb.RevokeRequest) (_ *pb.RevokeResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	peer, err := identity.PeerIdentityFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	ext := pkix.Extension{
+		Id:    extensions.RevocationExtID,
+		Value: req.Revocation,
+	}
+
+	err = endpoint.db.Put([]*x509.Certificate{peer.Leaf, peer.CA}, ext)
+	switch {
+	case err == nil:
+	case err == extensions.ErrRevocationTimestamp:
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	case extensions.ErrRevocationDB.Has(err):
+		endpoint.log.Error("failed to store revocation", zap.Stringer("node", peer.ID), zap.Error(err))
+		return nil, status.Error(codes.Internal, err.Error())
+	default:
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	endpoint.log.Info("stored revocation", zap.Stringer("node", peer.ID))
+	return &pb.RevokeResponse{}, nil
+}
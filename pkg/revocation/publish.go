@@ -0,0 +1,93 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package revocation
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"strings"
+
+	"github.com/zeebo/errs"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/peertls/extensions"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/transport"
+)
+
+// ParsePeers parses a comma separated list of peers in the form nodeid@address
+func ParsePeers(list string) (peers []*pb.Node, err error) {
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		tokens := strings.SplitN(entry, "@", 2)
+		if len(tokens) != 2 || tokens[1] == "" {
+			return nil, Error.New("invalid peer %q, expected nodeid@address", entry)
+		}
+
+		id, err := storj.NodeIDFromString(tokens[0])
+		if err != nil {
+			return nil, Error.New("invalid peer %q: %v", entry, err)
+		}
+
+		// NB: the peers are usually satellites, the type isn't used when
+		// publishing the revocation
+		peers = append(peers, &pb.Node{
+			Id:   id,
+			Type: pb.NodeType_SATELLITE,
+			Address: &pb.NodeAddress{
+				Transport: pb.NodeTransport_TCP_TLS_GRPC,
+				Address:   tokens[1],
+			},
+		})
+	}
+	return peers, nil
+}
+
+// Publish sends the revocation to each of the peers. The revocation must be
+// signed by the CA of the identity of tc.
+//
+// A peer that can't be reached or rejects the revocation doesn't stop the
+// publication to the others, all the failures are returned together. A peer
+// that already knows the revocation, or a newer one, counts as published.
+func Publish(ctx context.Context, tc transport.Client, peers []*pb.Node, revocation pkix.Extension) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if !revocation.Id.Equal(extensions.RevocationExtID) {
+		return Error.New("not a revocation extension: %s", revocation.Id)
+	}
+
+	var group errs.Group
+	for _, peer := range peers {
+		group.Add(publish(ctx, tc, peer, revocation))
+	}
+	return group.Err()
+}
+
+// publish sends the revocation to a single peer
+func publish(ctx context.Context, tc transport.Client, peer *pb.Node, revocation pkix.Extension) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	conn, err := tc.DialNode(ctx, peer)
+	if err != nil {
+		return Error.New("%s: %v", peer.Id, err)
+	}
+	defer func() { err = errs.Combine(err, conn.Close()) }()
+
+	_, err = pb.NewRevocationsClient(conn).Revoke(ctx, &pb.RevokeRequest{
+		Revocation: revocation.Value,
+	})
+	if status.Code(err) == codes.AlreadyExists {
+		return nil
+	}
+	if err != nil {
+		return Error.New("%s: %v", peer.Id, err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package revocation_test
+
+import (
+	"crypto/x509/pkix"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testidentity"
+	"storj.io/storj/pkg/identity"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/peertls/extensions"
+	"storj.io/storj/pkg/peertls/tlsopts"
+	"storj.io/storj/pkg/pkcrypto"
+	"storj.io/storj/pkg/revocation"
+	"storj.io/storj/pkg/server"
+	"storj.io/storj/pkg/transport"
+)
+
+func TestParsePeers(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	ident, err := testidentity.NewTestIdentity(ctx)
+	require.NoError(t, err)
+
+	peers, err := revocation.ParsePeers(" " + ident.ID.String() + "@127.0.0.1:7777, ,")
+	require.NoError(t, err)
+	require.Len(t, peers, 1)
+	assert.Equal(t, ident.ID, peers[0].Id)
+	assert.Equal(t, "127.0.0.1:7777", peers[0].Address.Address)
+
+	peers, err = revocation.ParsePeers("")
+	require.NoError(t, err)
+	assert.Empty(t, peers)
+
+	for _, invalid := range []string{"127.0.0.1:7777", ident.ID.String() + "@", "invalid@127.0.0.1:7777"} {
+		_, err := revocation.ParsePeers(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestPublish(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	serverIdent, err := testidentity.NewTestIdentity(ctx)
+	require.NoError(t, err)
+	serverOpts, err := tlsopts.NewOptions(serverIdent, tlsopts.Config{})
+	require.NoError(t, err)
+
+	revDB, err := identity.NewRevocationDB("bolt://" + ctx.File("revocations.db"))
+	require.NoError(t, err)
+	defer ctx.Check(revDB.Close)
+
+	srv, err := server.New(serverOpts, "127.0.0.1:0", "127.0.0.1:0", nil)
+	require.NoError(t, err)
+	pb.RegisterRevocationsServer(srv.GRPC(), revocation.NewEndpoint(zaptest.NewLogger(t), revDB))
+	ctx.Go(func() error { return srv.Run(ctx) })
+	defer ctx.Check(srv.Close)
+
+	peers, err := revocation.ParsePeers(serverIdent.ID.String() + "@" + srv.Addr().String())
+	require.NoError(t, err)
+
+	ca, err := testidentity.NewTestCA(ctx)
+	require.NoError(t, err)
+	revokedIdent, err := ca.NewIdentity()
+	require.NoError(t, err)
+	ident, err := ca.NewIdentity()
+	require.NoError(t, err)
+
+	clientOpts, err := tlsopts.NewOptions(ident, tlsopts.Config{})
+	require.NoError(t, err)
+	tc := transport.NewClient(clientOpts)
+
+	ext, err := extensions.NewRevocationExt(ca.Key, revokedIdent.Leaf)
+	require.NoError(t, err)
+
+	require.NoError(t, revocation.Publish(ctx, tc, peers, ext))
+
+	rev, err := revDB.Get(ident.Chain())
+	require.NoError(t, err)
+	require.NotNil(t, rev)
+	assert.Equal(t, pkcrypto.SHA256Hash(revokedIdent.Leaf.Raw), rev.CertHash)
+
+	// publishing again is not an error
+	require.NoError(t, revocation.Publish(ctx, tc, peers, ext))
+
+	{ // revocations must be signed by the CA of the publishing peer
+		otherCA, err := testidentity.NewTestCA(ctx)
+		require.NoError(t, err)
+		forged, err := extensions.NewRevocationExt(otherCA.Key, ident.Leaf)
+		require.NoError(t, err)
+
+		assert.Error(t, revocation.Publish(ctx, tc, peers, forged))
+	}
+
+	{ // only revocation extensions are published
+		other := pkix.Extension{Id: extensions.SignedCertExtID, Value: ext.Value}
+		assert.Error(t, revocation.Publish(ctx, tc, peers, other))
+	}
+}
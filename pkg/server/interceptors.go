@@ -12,9 +12,51 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"storj.io/storj/pkg/identity"
+	"storj.io/storj/pkg/tracing"
 	"storj.io/storj/storage"
 )
 
+// PeerFilter decides whether the server accepts the requests of a peer. It
+// returns an error for the peers whose requests are rejected.
+type PeerFilter func(peer *identity.PeerIdentity) error
+
+// checkPeer checks the peer of the request against the filter of the server
+func (p *Server) checkPeer(ctx context.Context) error {
+	p.filterMu.RLock()
+	filter := p.filter
+	p.filterMu.RUnlock()
+
+	if filter == nil {
+		return nil
+	}
+
+	peer, err := identity.PeerIdentityFromContext(ctx)
+	if err != nil {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	if err := filter(peer); err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	return nil
+}
+
+func (p *Server) filterStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := p.checkPeer(ss.Context()); err != nil {
+		return err
+	}
+	return tracing.StreamServerInterceptor(srv, ss, info, func(srv interface{}, ss grpc.ServerStream) error {
+		return streamInterceptor(srv, ss, info, handler)
+	})
+}
+
+func (p *Server) filterUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := p.checkPeer(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
 func streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
 	err = handler(srv, ss)
 	if err != nil {
@@ -0,0 +1,85 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// PrivateFilter decides whether the server accepts a request made to the
+// private server. It returns an error for the requests which are rejected.
+type PrivateFilter func(ctx context.Context) error
+
+// RequireToken returns a private filter which only accepts the requests
+// carrying token as a bearer token in the authorization metadata. Without a
+// token only requests from localhost are accepted.
+func RequireToken(token string) PrivateFilter {
+	return func(ctx context.Context) error {
+		if token == "" {
+			if !fromLoopback(ctx) {
+				return status.Error(codes.PermissionDenied, "private requests are only accepted from localhost without a token")
+			}
+			return nil
+		}
+
+		const prefix = "Bearer "
+		md, _ := metadata.FromIncomingContext(ctx)
+		for _, header := range md.Get("authorization") {
+			if strings.HasPrefix(header, prefix) &&
+				subtle.ConstantTimeCompare([]byte(header[len(prefix):]), []byte(token)) == 1 {
+				return nil
+			}
+		}
+		return status.Error(codes.Unauthenticated, "invalid or missing token")
+	}
+}
+
+// fromLoopback returns whether the request comes from a loopback address.
+func fromLoopback(ctx context.Context) bool {
+	peer, ok := peer.FromContext(ctx)
+	if !ok {
+		return false
+	}
+	host, _, err := net.SplitHostPort(peer.Addr.String())
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// checkPrivate checks the request against the private filter of the server
+func (p *Server) checkPrivate(ctx context.Context) error {
+	p.filterMu.RLock()
+	filter := p.privateFilter
+	p.filterMu.RUnlock()
+
+	if filter == nil {
+		return nil
+	}
+	return filter(ctx)
+}
+
+func (p *Server) filterPrivateStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := p.checkPrivate(ss.Context()); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+func (p *Server) filterPrivateUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := p.checkPrivate(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
@@ -6,6 +6,7 @@ package server
 import (
 	"context"
 	"net"
+	"sync"
 
 	"github.com/zeebo/errs"
 	"golang.org/x/sync/errgroup"
@@ -13,6 +14,7 @@ import (
 
 	"storj.io/storj/pkg/identity"
 	"storj.io/storj/pkg/peertls/tlsopts"
+	"storj.io/storj/pkg/tracing"
 )
 
 // Service represents a specific gRPC method collection to be registered
@@ -39,12 +41,22 @@ type Server struct {
 	private  private
 	next     []Service
 	identity *identity.FullIdentity
+
+	filterMu      sync.RWMutex
+	filter        PeerFilter
+	privateFilter PrivateFilter
 }
 
 // New creates a Server out of an Identity, a net.Listener,
 // a UnaryServerInterceptor, and a set of services.
 func New(opts *tlsopts.Options, publicAddr, privateAddr string, interceptor grpc.UnaryServerInterceptor, services ...Service) (*Server, error) {
-	unaryInterceptor := unaryInterceptor
+	server := &Server{
+		next:     services,
+		identity: opts.Ident,
+	}
+
+	unaryInterceptor := combineInterceptors(tracing.UnaryServerInterceptor,
+		combineInterceptors(server.filterUnary, unaryInterceptor))
 	if interceptor != nil {
 		unaryInterceptor = combineInterceptors(unaryInterceptor, interceptor)
 	}
@@ -53,10 +65,10 @@ func New(opts *tlsopts.Options, publicAddr, privateAddr string, interceptor grpc
 	if err != nil {
 		return nil, err
 	}
-	public := public{
+	server.public = public{
 		listener: publicListener,
 		grpc: grpc.NewServer(
-			grpc.StreamInterceptor(streamInterceptor),
+			grpc.StreamInterceptor(server.filterStream),
 			grpc.UnaryInterceptor(unaryInterceptor),
 			opts.ServerOption(),
 		),
@@ -66,17 +78,15 @@ func New(opts *tlsopts.Options, publicAddr, privateAddr string, interceptor grpc
 	if err != nil {
 		return nil, errs.Combine(err, publicListener.Close())
 	}
-	private := private{
+	server.private = private{
 		listener: privateListener,
-		grpc:     grpc.NewServer(),
+		grpc: grpc.NewServer(
+			grpc.StreamInterceptor(server.filterPrivateStream),
+			grpc.UnaryInterceptor(server.filterPrivateUnary),
+		),
 	}
 
-	return &Server{
-		public:   public,
-		private:  private,
-		next:     services,
-		identity: opts.Ident,
-	}, nil
+	return server, nil
 }
 
 // Identity returns the server's identity
@@ -94,6 +104,22 @@ func (p *Server) GRPC() *grpc.Server { return p.public.grpc }
 // PrivateGRPC returns the server's gRPC handle for registration purposes
 func (p *Server) PrivateGRPC() *grpc.Server { return p.private.grpc }
 
+// SetPeerFilter sets the filter for the peers making requests to the public
+// server. A nil filter accepts all the peers.
+func (p *Server) SetPeerFilter(filter PeerFilter) {
+	p.filterMu.Lock()
+	defer p.filterMu.Unlock()
+	p.filter = filter
+}
+
+// SetPrivateFilter sets the filter for the requests made to the private
+// server. A nil filter accepts all the requests.
+func (p *Server) SetPrivateFilter(filter PrivateFilter) {
+	p.filterMu.Lock()
+	defer p.filterMu.Unlock()
+	p.privateFilter = filter
+}
+
 // Close shuts down the server
 func (p *Server) Close() error {
 	p.public.grpc.GracefulStop()
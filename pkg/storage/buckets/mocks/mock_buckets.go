@@ -12,6 +12,7 @@ import (
 
 	buckets "storj.io/storj/pkg/storage/buckets"
 	objects "storj.io/storj/pkg/storage/objects"
+	streams "storj.io/storj/pkg/storage/streams"
 	storj "storj.io/storj/pkg/storj"
 )
 
@@ -91,14 +92,14 @@ func (mr *MockStoreMockRecorder) List(arg0, arg1, arg2, arg3 interface{}) *gomoc
 }
 
 // Put mocks base method
-func (m *MockStore) Put(arg0 context.Context, arg1 string, arg2 storj.Cipher) (buckets.Meta, error) {
-	ret := m.ctrl.Call(m, "Put", arg0, arg1, arg2)
+func (m *MockStore) Put(arg0 context.Context, arg1 string, arg2 storj.Cipher, arg3 streams.Options) (buckets.Meta, error) {
+	ret := m.ctrl.Call(m, "Put", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(buckets.Meta)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Put indicates an expected call of Put
-func (mr *MockStoreMockRecorder) Put(arg0, arg1, arg2 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockStore)(nil).Put), arg0, arg1, arg2)
+func (mr *MockStoreMockRecorder) Put(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockStore)(nil).Put), arg0, arg1, arg2, arg3)
 }
@@ -25,7 +25,7 @@ var mon = monkit.Package()
 // Store creates an interface for interacting with buckets
 type Store interface {
 	Get(ctx context.Context, bucket string) (meta Meta, err error)
-	Put(ctx context.Context, bucket string, pathCipher storj.Cipher) (meta Meta, err error)
+	Put(ctx context.Context, bucket string, pathCipher storj.Cipher, options streams.Options) (meta Meta, err error)
 	Delete(ctx context.Context, bucket string) (err error)
 	List(ctx context.Context, startAfter, endBefore string, limit int) (items []ListItem, more bool, err error)
 	GetObjectStore(ctx context.Context, bucketName string) (store objects.Store, err error)
@@ -47,12 +47,14 @@ type BucketStore struct {
 type Meta struct {
 	Created            time.Time
 	PathEncryptionType storj.Cipher
+	Padding            storj.Padding
+	Convergent         bool
 }
 
 // NewStore instantiates BucketStore
 func NewStore(stream streams.Store) Store {
 	// root object store for storing the buckets with unencrypted names
-	store := objects.NewStore(stream, storj.Unencrypted)
+	store := objects.NewStore(stream, storj.Unencrypted, streams.Options{})
 	return &BucketStore{store: store, stream: stream}
 }
 
@@ -69,8 +71,12 @@ func (b *BucketStore) GetObjectStore(ctx context.Context, bucket string) (object
 		}
 		return nil, err
 	}
+	options := streams.Options{
+		Padding:    m.Padding,
+		Convergent: m.Convergent,
+	}
 	prefixed := prefixedObjStore{
-		store:  objects.NewStore(b.stream, m.PathEncryptionType),
+		store:  objects.NewStore(b.stream, m.PathEncryptionType, options),
 		prefix: bucket,
 	}
 	return &prefixed, nil
@@ -96,7 +102,7 @@ func (b *BucketStore) Get(ctx context.Context, bucket string) (meta Meta, err er
 }
 
 // Put calls objects store Put
-func (b *BucketStore) Put(ctx context.Context, bucket string, pathCipher storj.Cipher) (meta Meta, err error) {
+func (b *BucketStore) Put(ctx context.Context, bucket string, pathCipher storj.Cipher, options streams.Options) (meta Meta, err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	if bucket == "" {
@@ -107,10 +113,24 @@ func (b *BucketStore) Put(ctx context.Context, bucket string, pathCipher storj.C
 		return Meta{}, encryption.ErrInvalidConfig.New("encryption type %d is not supported", pathCipher)
 	}
 
+	padding := options.Padding
+	if padding.Boundary < 0 {
+		return Meta{}, encryption.ErrInvalidConfig.New("padding boundary %d must not be negative", padding.Boundary)
+	}
+
 	r := bytes.NewReader(nil)
 	userMeta := map[string]string{
 		"path-enc-type": strconv.Itoa(int(pathCipher)),
 	}
+	if padding.PowerOfTwo {
+		userMeta["padding-power-of-two"] = strconv.FormatBool(padding.PowerOfTwo)
+	}
+	if padding.Boundary > 0 {
+		userMeta["padding-boundary"] = strconv.FormatInt(padding.Boundary, 10)
+	}
+	if options.Convergent {
+		userMeta["convergent-encryption"] = strconv.FormatBool(options.Convergent)
+	}
 	var exp time.Time
 	m, err := b.store.Put(ctx, bucket, r, pb.SerializableMeta{UserDefined: userMeta}, exp)
 	if err != nil {
@@ -179,8 +199,38 @@ func convertMeta(m objects.Meta) (Meta, error) {
 		cipher = storj.Cipher(pet)
 	}
 
+	var padding storj.Padding
+
+	if powerOfTwo := m.UserDefined["padding-power-of-two"]; powerOfTwo != "" {
+		value, err := strconv.ParseBool(powerOfTwo)
+		if err != nil {
+			return Meta{}, err
+		}
+		padding.PowerOfTwo = value
+	}
+
+	if boundary := m.UserDefined["padding-boundary"]; boundary != "" {
+		value, err := strconv.ParseInt(boundary, 10, 64)
+		if err != nil {
+			return Meta{}, err
+		}
+		padding.Boundary = value
+	}
+
+	var convergent bool
+
+	if value := m.UserDefined["convergent-encryption"]; value != "" {
+		var err error
+		convergent, err = strconv.ParseBool(value)
+		if err != nil {
+			return Meta{}, err
+		}
+	}
+
 	return Meta{
 		Created:            m.Modified,
 		PathEncryptionType: cipher,
+		Padding:            padding,
+		Convergent:         convergent,
 	}, nil
 }
@@ -31,22 +31,31 @@ var mon = monkit.Package()
 type Client interface {
 	Put(ctx context.Context, limits []*pb.AddressedOrderLimit, rs eestream.RedundancyStrategy, data io.Reader, expiration time.Time) (successfulNodes []*pb.Node, successfulHashes []*pb.PieceHash, err error)
 	Repair(ctx context.Context, limits []*pb.AddressedOrderLimit, rs eestream.RedundancyStrategy, data io.Reader, expiration time.Time, timeout time.Duration) (successfulNodes []*pb.Node, successfulHashes []*pb.PieceHash, err error)
-	Get(ctx context.Context, limits []*pb.AddressedOrderLimit, es eestream.ErasureScheme, size int64) (ranger.Ranger, error)
+	// Get reports the numbers of the pieces found to be corrupted while reading
+	// from the returned ranger to corrupted, if it is not nil.
+	Get(ctx context.Context, limits []*pb.AddressedOrderLimit, es eestream.ErasureScheme, size int64, corrupted *eestream.CorruptedPieces) (ranger.Ranger, error)
 	Delete(ctx context.Context, limits []*pb.AddressedOrderLimit) error
 }
 
 type psClientHelper func(context.Context, *pb.Node) (*piecestore.Client, error)
 
 type ecClient struct {
-	transport   transport.Client
-	memoryLimit int
+	transport          transport.Client
+	memoryLimit        int
+	encoderConcurrency int
+	longTailMargin     int
 }
 
-// NewClient from the given identity and max buffer memory
-func NewClient(tc transport.Client, memoryLimit int) Client {
+// NewClient from the given identity, max buffer memory, the maximum number
+// of erasure shares encoded concurrently per segment (0 means unlimited) and
+// the maximum number of pieces uploaded beyond the success threshold of a
+// segment (0 means unlimited).
+func NewClient(tc transport.Client, memoryLimit int, encoderConcurrency int, longTailMargin int) Client {
 	return &ecClient{
-		transport:   tc,
-		memoryLimit: memoryLimit,
+		transport:          tc,
+		memoryLimit:        memoryLimit,
+		encoderConcurrency: encoderConcurrency,
+		longTailMargin:     longTailMargin,
 	}
 }
 
@@ -79,8 +88,10 @@ func (ec *ecClient) Put(ctx context.Context, limits []*pb.AddressedOrderLimit, r
 		return nil, nil, Error.New("duplicated nodes are not allowed")
 	}
 
+	limits = ec.trimLongTail(limits, rs)
+
 	padded := eestream.PadReader(ioutil.NopCloser(data), rs.StripeSize())
-	readers, err := eestream.EncodeReader(ctx, padded, rs)
+	readers, err := eestream.EncodeReaderWithConcurrency(ctx, padded, rs, ec.encoderConcurrency)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -91,6 +102,7 @@ func (ec *ecClient) Put(ctx context.Context, limits []*pb.AddressedOrderLimit, r
 		hash *pb.PieceHash
 	}
 	infos := make(chan info, len(limits))
+	var longTailCanceled int64
 
 	psCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -117,6 +129,9 @@ func (ec *ecClient) Put(ctx context.Context, limits []*pb.AddressedOrderLimit, r
 		}
 
 		if info.err != nil {
+			if info.err == context.Canceled && ctx.Err() == nil {
+				longTailCanceled++
+			}
 			zap.S().Debugf("Upload to storage node %s failed: %v", limits[info.i].GetLimit().StorageNodeId, info.err)
 			continue
 		}
@@ -155,6 +170,8 @@ func (ec *ecClient) Put(ctx context.Context, limits []*pb.AddressedOrderLimit, r
 		timer.Stop()
 	}
 
+	mon.IntVal("upload_long_tail_canceled").Observe(longTailCanceled)
+
 	defer func() {
 		select {
 		case <-ctx.Done():
@@ -186,7 +203,7 @@ func (ec *ecClient) Repair(ctx context.Context, limits []*pb.AddressedOrderLimit
 	}
 
 	padded := eestream.PadReader(ioutil.NopCloser(data), rs.StripeSize())
-	readers, err := eestream.EncodeReader(ctx, padded, rs)
+	readers, err := eestream.EncodeReaderWithConcurrency(ctx, padded, rs, ec.encoderConcurrency)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -273,11 +290,38 @@ func (ec *ecClient) Repair(ctx context.Context, limits []*pb.AddressedOrderLimit
 	return successfulNodes, successfulHashes, nil
 }
 
+// trimLongTail returns a copy of limits with the limits beyond the success
+// threshold plus the long-tail margin removed, so no upload is started for
+// them.
+func (ec *ecClient) trimLongTail(limits []*pb.AddressedOrderLimit, rs eestream.RedundancyStrategy) []*pb.AddressedOrderLimit {
+	if ec.longTailMargin <= 0 {
+		return limits
+	}
+
+	trimmed := make([]*pb.AddressedOrderLimit, len(limits))
+	maxCount := rs.OptimalThreshold() + ec.longTailMargin
+	var count, skipped int64
+	for i, limit := range limits {
+		if limit == nil {
+			continue
+		}
+		if count >= int64(maxCount) {
+			skipped++
+			continue
+		}
+		trimmed[i] = limit
+		count++
+	}
+
+	mon.IntVal("upload_long_tail_skipped").Observe(skipped)
+	return trimmed
+}
+
 func (ec *ecClient) putPiece(ctx, parent context.Context, limit *pb.AddressedOrderLimit, data io.ReadCloser, expiration time.Time) (hash *pb.PieceHash, err error) {
 	defer func() { err = errs.Combine(err, data.Close()) }()
 
 	if limit == nil {
-		_, _ = io.Copy(ioutil.Discard, data)
+		// the share of a piece that isn't uploaded is never encoded
 		return nil, nil
 	}
 
@@ -299,18 +343,14 @@ func (ec *ecClient) putPiece(ctx, parent context.Context, limit *pb.AddressedOrd
 		zap.S().Errorf("Failed requesting upload of piece %s to node %s: %v", pieceID, storageNodeID, err)
 		return nil, err
 	}
-	defer func() {
-		if ctx.Err() != nil || err != nil {
-			hash = nil
-			err = errs.Combine(err, upload.Cancel())
-			return
-		}
-		h, closeErr := upload.Commit()
-		hash = h
-		err = errs.Combine(err, closeErr)
-	}()
 
 	_, err = sync2.Copy(ctx, upload, data)
+	if ctx.Err() == nil && err == nil {
+		// NB: the node may only reject the upload as busy on commit
+		hash, err = upload.Commit()
+	} else {
+		err = errs.Combine(err, upload.Cancel())
+	}
 	// Canceled context means the piece upload was interrupted by user or due
 	// to slow connection. No error logging for this case.
 	if ctx.Err() == context.Canceled {
@@ -320,6 +360,9 @@ func (ec *ecClient) putPiece(ctx, parent context.Context, limit *pb.AddressedOrd
 			zap.S().Infof("Node %s cut from upload due to slow connection.", storageNodeID)
 		}
 		err = context.Canceled
+	} else if piecestore.IsBusy(err) {
+		mon.Meter("upload_busy_nodes").Mark(1)
+		zap.S().Infof("Node %s is busy, skipping it for piece %s.", storageNodeID, pieceID)
 	} else if err != nil {
 		nodeAddress := "nil"
 		if limit.GetStorageNodeAddress() != nil {
@@ -327,11 +370,14 @@ func (ec *ecClient) putPiece(ctx, parent context.Context, limit *pb.AddressedOrd
 		}
 		zap.S().Errorf("Failed uploading piece %s to node %s (%+v): %v", pieceID, storageNodeID, nodeAddress, err)
 	}
+	if err != nil {
+		return nil, err
+	}
 
-	return hash, err
+	return hash, nil
 }
 
-func (ec *ecClient) Get(ctx context.Context, limits []*pb.AddressedOrderLimit, es eestream.ErasureScheme, size int64) (rr ranger.Ranger, err error) {
+func (ec *ecClient) Get(ctx context.Context, limits []*pb.AddressedOrderLimit, es eestream.ErasureScheme, size int64, corrupted *eestream.CorruptedPieces) (rr ranger.Ranger, err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	if len(limits) != es.TotalCount() {
@@ -358,7 +404,7 @@ func (ec *ecClient) Get(ctx context.Context, limits []*pb.AddressedOrderLimit, e
 		}
 	}
 
-	rr, err = eestream.Decode(rrs, es, ec.memoryLimit)
+	rr, err = eestream.DecodeWithReport(rrs, es, ec.memoryLimit, corrupted)
 	if err != nil {
 		return nil, err
 	}
@@ -47,7 +47,7 @@ func TestECClient(t *testing.T) {
 
 	planet.Start(ctx)
 
-	ec := ecclient.NewClient(planet.Uplinks[0].Transport, 0)
+	ec := ecclient.NewClient(planet.Uplinks[0].Transport, 0, 0, 0)
 
 	k := storageNodes / 2
 	n := storageNodes
@@ -120,7 +120,8 @@ func testGet(ctx context.Context, t *testing.T, planet *testplanet.Planet, ec ec
 		}
 	}
 
-	rr, err := ec.Get(ctx, limits, es, dataSize.Int64())
+	corrupted := eestream.NewCorruptedPieces()
+	rr, err := ec.Get(ctx, limits, es, dataSize.Int64(), corrupted)
 	require.NoError(t, err)
 
 	r, err := rr.Range(ctx, 0, rr.Size())
@@ -129,6 +130,7 @@ func testGet(ctx context.Context, t *testing.T, planet *testplanet.Planet, ec ec
 	require.NoError(t, err)
 	assert.Equal(t, data, readData)
 	assert.NoError(t, r.Close())
+	assert.Empty(t, corrupted.List())
 	require.NoError(t, err)
 }
 
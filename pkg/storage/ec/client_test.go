@@ -8,8 +8,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vivint/infectious"
 
 	"storj.io/storj/internal/teststorj"
+	"storj.io/storj/pkg/eestream"
 	"storj.io/storj/pkg/pb"
 )
 
@@ -44,3 +47,43 @@ func TestUnique(t *testing.T) {
 		assert.Equal(t, tt.unique, unique(tt.limits), errTag)
 	}
 }
+
+func TestTrimLongTail(t *testing.T) {
+	fc, err := infectious.NewFEC(2, 8)
+	require.NoError(t, err)
+	rs, err := eestream.NewRedundancyStrategy(eestream.NewRSScheme(fc, 8), 3, 4)
+	require.NoError(t, err)
+
+	limits := make([]*pb.AddressedOrderLimit, 8)
+	for i := 0; i < len(limits); i++ {
+		if i == 1 {
+			continue
+		}
+		limits[i] = &pb.AddressedOrderLimit{
+			Limit: &pb.OrderLimit2{
+				StorageNodeId: teststorj.NodeIDFromString(fmt.Sprintf("node-%d", i)),
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		margin   int
+		expected int
+	}{
+		{0, 7},
+		{1, 5},
+		{2, 6},
+		{10, 7},
+	} {
+		ec := &ecClient{longTailMargin: tt.margin}
+		trimmed := ec.trimLongTail(limits, rs)
+		require.Len(t, trimmed, len(limits))
+		assert.Equal(t, tt.expected, nonNilCount(trimmed), "margin %d", tt.margin)
+		assert.Nil(t, trimmed[1])
+		for i, limit := range trimmed {
+			if limit != nil {
+				assert.Equal(t, limits[i], limit)
+			}
+		}
+	}
+}
@@ -49,11 +49,12 @@ type Store interface {
 type objStore struct {
 	store      streams.Store
 	pathCipher storj.Cipher
+	options    streams.Options
 }
 
 // NewStore for objects
-func NewStore(store streams.Store, pathCipher storj.Cipher) Store {
-	return &objStore{store: store, pathCipher: pathCipher}
+func NewStore(store streams.Store, pathCipher storj.Cipher, options streams.Options) Store {
+	return &objStore{store: store, pathCipher: pathCipher, options: options}
 }
 
 func (o *objStore) Meta(ctx context.Context, path storj.Path) (meta Meta, err error) {
@@ -103,7 +104,7 @@ func (o *objStore) Put(ctx context.Context, path storj.Path, data io.Reader, met
 	if err != nil {
 		return Meta{}, err
 	}
-	m, err := o.store.Put(ctx, path, o.pathCipher, data, b, expiration)
+	m, err := o.store.Put(ctx, path, o.pathCipher, o.options, data, b, expiration)
 	return convertMeta(m), err
 }
 
@@ -9,3 +9,7 @@ import (
 
 // Error is the errs class of standard segment errors
 var Error = errs.Class("segment error")
+
+// ErrIrreparable is the errs class of the segments that can't be repaired
+// because fewer pieces than the minimum required are healthy
+var ErrIrreparable = errs.Class("irreparable segment")
@@ -0,0 +1,81 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package segments
+
+import (
+	"context"
+
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+)
+
+// RepairEstimate is what the repair of a segment would transfer.
+type RepairEstimate struct {
+	// PieceSize is the size of every piece of the segment
+	PieceSize int64
+	// HealthyPieces is the number of pieces that stay in place
+	HealthyPieces int
+	// DownloadedPieces is the number of healthy pieces downloaded
+	DownloadedPieces int
+	// MissingPieces is the number of pieces to upload to new nodes
+	MissingPieces int
+	// SelectedNodes is the number of new nodes found for the missing pieces
+	SelectedNodes int
+	// SelectionError is why fewer new nodes than missing pieces were found
+	SelectionError error
+}
+
+// DownloadedBytes returns the number of bytes the repair would download.
+func (estimate *RepairEstimate) DownloadedBytes() int64 {
+	return int64(estimate.DownloadedPieces) * estimate.PieceSize
+}
+
+// UploadedBytes returns the number of bytes the repair would upload to the
+// selected nodes.
+func (estimate *RepairEstimate) UploadedBytes() int64 {
+	return int64(estimate.SelectedNodes) * estimate.PieceSize
+}
+
+// EstimateRepair computes what Repair would transfer to repair the lost pieces
+// of the segment and selects the nodes the repaired pieces would be uploaded
+// to, without transferring anything or updating the pointer. Like Repair, it
+// returns ErrIrreparable when fewer pieces than the minimum required are
+// healthy.
+func EstimateRepair(ctx context.Context, cache *overlay.Cache, path storj.Path, pointer *pb.Pointer, lostPieces []int32) (_ *RepairEstimate, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	plan, err := planRepair(path, pointer, lostPieces)
+	if err != nil {
+		return nil, err
+	}
+
+	estimate := &RepairEstimate{
+		PieceSize:     plan.pieceSize,
+		HealthyPieces: len(plan.healthyPieces),
+	}
+	if plan.missingCount <= 0 {
+		return estimate, nil
+	}
+
+	estimate.DownloadedPieces = plan.redundancy.RequiredCount() + downloadOverhead
+	if estimate.DownloadedPieces > estimate.HealthyPieces {
+		estimate.DownloadedPieces = estimate.HealthyPieces
+	}
+	estimate.MissingPieces = plan.missingCount
+
+	newNodes, err := cache.FindStorageNodes(ctx, plan.nodesRequest())
+	if err != nil && !overlay.ErrNotEnoughNodes.Has(err) {
+		return nil, Error.Wrap(err)
+	}
+	estimate.SelectedNodes = countNodes(newNodes)
+	if estimate.SelectedNodes > estimate.MissingPieces {
+		estimate.SelectedNodes = estimate.MissingPieces
+	}
+	if err == nil && estimate.SelectedNodes < estimate.MissingPieces {
+		err = overlay.ErrNotEnoughNodes.New("requested %d found %d", estimate.MissingPieces, estimate.SelectedNodes)
+	}
+	estimate.SelectionError = err
+	return estimate, nil
+}
@@ -5,9 +5,11 @@ package segments
 
 import (
 	"context"
+	"math/rand"
 	"time"
 
 	"github.com/zeebo/errs"
+	"go.uber.org/zap"
 
 	"storj.io/storj/pkg/eestream"
 	"storj.io/storj/pkg/identity"
@@ -17,31 +19,45 @@ import (
 	ecclient "storj.io/storj/pkg/storage/ec"
 	"storj.io/storj/pkg/storj"
 	"storj.io/storj/satellite/orders"
+	"storj.io/storj/storage"
 )
 
+// downloadOverhead is the number of pieces downloaded in addition to the
+// minimum required, so that a corrupted piece can be corrected and reported
+// and a slow or failing node doesn't fail the repair.
+const downloadOverhead = 2
+
 // Repairer for segments
 type Repairer struct {
-	pointerdb *pointerdb.Service
-	orders    *orders.Service
-	cache     *overlay.Cache
-	ec        ecclient.Client
-	identity  *identity.FullIdentity
-	timeout   time.Duration
+	pointerdb   *pointerdb.Service
+	orders      *orders.Service
+	cache       *overlay.Cache
+	reliability *overlay.ReliabilityCache
+	ec          ecclient.Client
+	identity    *identity.FullIdentity
+	timeout     time.Duration
+	throttle    *Throttle
 }
 
-// NewSegmentRepairer creates a new instance of SegmentRepairer
-func NewSegmentRepairer(pointerdb *pointerdb.Service, orders *orders.Service, cache *overlay.Cache, ec ecclient.Client, identity *identity.FullIdentity, timeout time.Duration) *Repairer {
+// NewSegmentRepairer creates a new instance of SegmentRepairer, the repair
+// traffic is limited by throttle unless it's nil.
+func NewSegmentRepairer(pointerdb *pointerdb.Service, orders *orders.Service, cache *overlay.Cache, reliability *overlay.ReliabilityCache, ec ecclient.Client, identity *identity.FullIdentity, timeout time.Duration, throttle *Throttle) *Repairer {
 	return &Repairer{
-		pointerdb: pointerdb,
-		orders:    orders,
-		cache:     cache,
-		ec:        ec,
-		identity:  identity,
-		timeout:   timeout,
+		pointerdb:   pointerdb,
+		orders:      orders,
+		cache:       cache,
+		reliability: reliability,
+		ec:          ec,
+		identity:    identity,
+		timeout:     timeout,
+		throttle:    throttle,
 	}
 }
 
-// Repair retrieves an at-risk segment and repairs and stores lost pieces on new nodes
+// Repair retrieves an at-risk segment and repairs and stores lost pieces on new
+// nodes. The healthy pieces stay in place, only the missing pieces are
+// re-encoded and uploaded. The pieces the repaired pointer doesn't reference
+// anymore are deleted from the nodes that are still reachable.
 func (repairer *Repairer) Repair(ctx context.Context, path storj.Path, lostPieces []int32) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
@@ -51,28 +67,23 @@ func (repairer *Repairer) Repair(ctx context.Context, path storj.Path, lostPiece
 		return Error.Wrap(err)
 	}
 
-	if pointer.GetType() != pb.Pointer_REMOTE {
-		return Error.New("cannot repair inline segment %s", path)
-	}
-
-	redundancy, err := eestream.NewRedundancyStrategyFromProto(pointer.GetRemote().GetRedundancy())
+	// The nodes of the pieces may have gone offline or been disqualified
+	// since the segment was checked, their pieces are repaired too
+	lostPieces, err = repairer.addDeadPieces(ctx, pointer, lostPieces)
 	if err != nil {
 		return Error.Wrap(err)
 	}
 
-	pieceSize := eestream.CalcPieceSize(pointer.GetSegmentSize(), redundancy)
+	plan, err := planRepair(path, pointer, lostPieces)
+	if err != nil {
+		return err
+	}
+	redundancy, pieceSize, healthyPieces := plan.redundancy, plan.pieceSize, plan.healthyPieces
 	expiration := pointer.GetExpirationDate()
 
-	var excludeNodeIDs storj.NodeIDList
-	var healthyPieces []*pb.RemotePiece
-	lostPiecesSet := sliceToSet(lostPieces)
-
-	// Populate healthyPieces with all pieces from the pointer except those correlating to indices in lostPieces
-	for _, piece := range pointer.GetRemote().GetRemotePieces() {
-		excludeNodeIDs = append(excludeNodeIDs, piece.NodeId)
-		if _, ok := lostPiecesSet[piece.GetPieceNum()]; !ok {
-			healthyPieces = append(healthyPieces, piece)
-		}
+	// the segment may have been repaired since it was queued
+	if plan.missingCount <= 0 {
+		return nil
 	}
 
 	bucketID := createBucketID(path)
@@ -82,27 +93,23 @@ func (repairer *Repairer) Repair(ctx context.Context, path storj.Path, lostPiece
 	if err != nil {
 		return Error.Wrap(err)
 	}
+	getOrderLimits = selectDownloadLimits(getOrderLimits, redundancy.RequiredCount()+downloadOverhead)
 
 	// Request Overlay for n-h new storage nodes
-	request := overlay.FindStorageNodesRequest{
-		RequestedCount: redundancy.TotalCount() - len(healthyPieces),
-		FreeBandwidth:  pieceSize,
-		FreeDisk:       pieceSize,
-		ExcludedNodes:  excludeNodeIDs,
-	}
-	newNodes, err := repairer.cache.FindStorageNodes(ctx, request)
+	newNodes, err := repairer.cache.FindStorageNodes(ctx, plan.nodesRequest())
 	if err != nil {
 		return Error.Wrap(err)
 	}
 
 	// Create the order limits for the PUT_REPAIR action
-	putLimits, err := repairer.orders.CreatePutRepairOrderLimits(ctx, repairer.identity.PeerIdentity(), bucketID, pointer, getOrderLimits, newNodes)
+	putLimits, err := repairer.orders.CreatePutRepairOrderLimits(ctx, repairer.identity.PeerIdentity(), bucketID, pointer, healthyPieces, newNodes)
 	if err != nil {
 		return Error.Wrap(err)
 	}
 
-	// Download the segment using just the healthy pieces
-	rr, err := repairer.ec.Get(ctx, getOrderLimits, redundancy, pointer.GetSegmentSize())
+	// Download the segment using just the selected healthy pieces
+	corrupted := eestream.NewCorruptedPieces()
+	rr, err := repairer.ec.Get(ctx, getOrderLimits, redundancy, pointer.GetSegmentSize(), corrupted)
 	if err != nil {
 		return Error.Wrap(err)
 	}
@@ -113,11 +120,27 @@ func (repairer *Repairer) Repair(ctx context.Context, path storj.Path, lostPiece
 	}
 	defer func() { err = errs.Combine(err, r.Close()) }()
 
-	// Upload the repaired pieces
-	successfulNodes, hashes, err := repairer.ec.Repair(ctx, putLimits, redundancy, r, convertTime(expiration), repairer.timeout)
+	downloadCount := int64(countLimits(getOrderLimits))
+	uploadCount := int64(countLimits(putLimits))
+	throttled := &throttledReader{
+		ctx:        ctx,
+		reader:     r,
+		throttle:   repairer.throttle,
+		downloaded: downloadCount,
+		uploaded:   uploadCount,
+		required:   int64(redundancy.RequiredCount()),
+	}
+
+	// Upload the repaired pieces, only the shares of the missing pieces are encoded
+	successfulNodes, hashes, err := repairer.ec.Repair(ctx, putLimits, redundancy, throttled, convertTime(expiration), repairer.timeout)
 	if err != nil {
 		return Error.Wrap(err)
 	}
+	mon.IntVal("repair_bytes_downloaded").Observe(downloadCount * pieceSize)
+	mon.IntVal("repair_bytes_uploaded").Observe(int64(countNodes(successfulNodes)) * pieceSize)
+
+	// Remove the pieces found to be corrupted during the download
+	healthyPieces = repairer.removeCorrupted(ctx, healthyPieces, getOrderLimits, corrupted.List())
 
 	// Add the successfully uploaded pieces to the healthyPieces
 	for i, node := range successfulNodes {
@@ -131,11 +154,218 @@ func (repairer *Repairer) Repair(ctx context.Context, path storj.Path, lostPiece
 		})
 	}
 
-	// Update the remote pieces in the pointer
-	pointer.GetRemote().RemotePieces = healthyPieces
+	// Update the remote pieces of the segment pointer, unless the pointer was
+	// changed since it was read, e.g. by an upload or a concurrent repair
+	repaired := *pointer
+	remote := *pointer.GetRemote()
+	remote.RemotePieces = healthyPieces
+	repaired.Remote = &remote
+
+	err = repairer.pointerdb.CompareAndSwap(path, pointer, &repaired)
+	if storage.ErrValueChanged.Has(err) {
+		mon.Meter("repair_pointer_changed").Mark(1)
+		return Error.Wrap(storage.ErrValueChanged.New("pointer of segment %s changed during the repair, the repaired pieces are dropped", path))
+	}
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	repairer.deletePieces(ctx, bucketID, pointer, removedPieces(pointer, healthyPieces))
+	return nil
+}
+
+// addDeadPieces adds the pieces stored on nodes that are offline or were
+// disqualified to lostPieces.
+func (repairer *Repairer) addDeadPieces(ctx context.Context, pointer *pb.Pointer, lostPieces []int32) (_ []int32, err error) {
+	missing, err := repairer.reliability.MissingPieces(ctx, pointer.GetRemote().GetRemotePieces())
+	if err != nil {
+		return nil, err
+	}
+
+	lostPiecesSet := sliceToSet(lostPieces)
+	for _, pieceNum := range missing {
+		if _, ok := lostPiecesSet[pieceNum]; !ok {
+			lostPiecesSet[pieceNum] = struct{}{}
+			lostPieces = append(lostPieces, pieceNum)
+		}
+	}
+	return lostPieces, nil
+}
+
+// deletePieces deletes the pieces of the segment from the nodes that are still
+// reachable, the pieces of the other nodes are left to garbage collection.
+func (repairer *Repairer) deletePieces(ctx context.Context, bucketID []byte, pointer *pb.Pointer, pieces []*pb.RemotePiece) {
+	if len(pieces) == 0 {
+		return
+	}
+	mon.IntVal("repair_pieces_removed").Observe(int64(len(pieces)))
 
-	// Update the segment pointer in the PointerDB
-	return repairer.pointerdb.Put(path, pointer)
+	removed := *pointer
+	remote := *pointer.GetRemote()
+	remote.RemotePieces = pieces
+	removed.Remote = &remote
+
+	limits, err := repairer.orders.CreateDeleteOrderLimits(ctx, repairer.identity.PeerIdentity(), bucketID, &removed)
+	if err != nil {
+		zap.L().Debug("no removed pieces deleted", zap.Error(err))
+		return
+	}
+	mon.IntVal("repair_pieces_deleted").Observe(int64(countLimits(limits)))
+
+	if err := repairer.ec.Delete(ctx, limits); err != nil {
+		zap.L().Debug("deleting the removed pieces failed", zap.Error(err))
+	}
+}
+
+// removedPieces returns the pieces of the pointer that aren't in pieces.
+func removedPieces(pointer *pb.Pointer, pieces []*pb.RemotePiece) []*pb.RemotePiece {
+	// the repaired pieces are stored on nodes that didn't store any piece of
+	// the segment, so the pieces are identified by their nodes
+	kept := make(map[storj.NodeID]struct{}, len(pieces))
+	for _, piece := range pieces {
+		kept[piece.NodeId] = struct{}{}
+	}
+
+	var removed []*pb.RemotePiece
+	for _, piece := range pointer.GetRemote().GetRemotePieces() {
+		if _, ok := kept[piece.NodeId]; !ok {
+			removed = append(removed, piece)
+		}
+	}
+	return removed
+}
+
+// repairPlan describes how the pieces of a segment are repaired.
+type repairPlan struct {
+	redundancy     eestream.RedundancyStrategy
+	pieceSize      int64
+	healthyPieces  []*pb.RemotePiece
+	excludeNodeIDs storj.NodeIDList
+	missingCount   int
+}
+
+// planRepair splits the pieces of the pointer into the healthy pieces, which
+// stay in place, and the missing pieces, which are repaired. It returns
+// ErrIrreparable when fewer pieces than the minimum required are healthy.
+func planRepair(path storj.Path, pointer *pb.Pointer, lostPieces []int32) (*repairPlan, error) {
+	if pointer.GetType() != pb.Pointer_REMOTE {
+		return nil, Error.New("cannot repair inline segment %s", path)
+	}
+
+	redundancy, err := eestream.NewRedundancyStrategyFromProto(pointer.GetRemote().GetRedundancy())
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	plan := &repairPlan{
+		redundancy: redundancy,
+		pieceSize:  eestream.CalcPieceSize(pointer.GetSegmentSize(), redundancy),
+	}
+	lostPiecesSet := sliceToSet(lostPieces)
+
+	// Populate healthyPieces with all pieces from the pointer except those correlating to indices in lostPieces
+	for _, piece := range pointer.GetRemote().GetRemotePieces() {
+		plan.excludeNodeIDs = append(plan.excludeNodeIDs, piece.NodeId)
+		if _, ok := lostPiecesSet[piece.GetPieceNum()]; !ok {
+			plan.healthyPieces = append(plan.healthyPieces, piece)
+		}
+	}
+
+	if int32(len(plan.healthyPieces)) < pointer.GetRemote().GetRedundancy().GetMinReq() {
+		return nil, ErrIrreparable.New("segment %s has %d healthy pieces, %d required",
+			path, len(plan.healthyPieces), pointer.GetRemote().GetRedundancy().GetMinReq())
+	}
+
+	plan.missingCount = redundancy.TotalCount() - len(plan.healthyPieces)
+	return plan, nil
+}
+
+// nodesRequest returns the request for the new nodes storing the repaired
+// pieces, the nodes storing any piece of the segment are excluded.
+func (plan *repairPlan) nodesRequest() overlay.FindStorageNodesRequest {
+	return overlay.FindStorageNodesRequest{
+		RequestedCount: plan.missingCount,
+		FreeBandwidth:  plan.pieceSize,
+		FreeDisk:       plan.pieceSize,
+		ExcludedNodes:  plan.excludeNodeIDs,
+	}
+}
+
+// selectDownloadLimits keeps count randomly selected order limits of limits,
+// the others are set to nil so that their pieces aren't downloaded.
+func selectDownloadLimits(limits []*pb.AddressedOrderLimit, count int) []*pb.AddressedOrderLimit {
+	var nonNil []int
+	for i, limit := range limits {
+		if limit != nil {
+			nonNil = append(nonNil, i)
+		}
+	}
+	if len(nonNil) <= count {
+		return limits
+	}
+
+	// a failed repair is retried with other pieces
+	rand.Shuffle(len(nonNil), func(i, k int) { nonNil[i], nonNil[k] = nonNil[k], nonNil[i] })
+
+	selected := make([]*pb.AddressedOrderLimit, len(limits))
+	for _, i := range nonNil[:count] {
+		selected[i] = limits[i]
+	}
+	return selected
+}
+
+// removeCorrupted removes the corrupted pieces from pieces and reports the
+// nodes storing them as failing an audit to the overlay
+func (repairer *Repairer) removeCorrupted(ctx context.Context, pieces []*pb.RemotePiece, limits []*pb.AddressedOrderLimit, corrupted []int) []*pb.RemotePiece {
+	if len(corrupted) == 0 {
+		return pieces
+	}
+
+	corruptedSet := make(map[int32]struct{}, len(corrupted))
+	for _, num := range corrupted {
+		corruptedSet[int32(num)] = struct{}{}
+
+		nodeID := limits[num].GetLimit().StorageNodeId
+		zap.S().Warnf("Piece %d from node %s is corrupted", num, nodeID)
+		_, err := repairer.cache.UpdateStats(ctx, &overlay.UpdateRequest{
+			NodeID:       nodeID,
+			AuditSuccess: false,
+			IsUp:         true,
+		})
+		if err != nil {
+			zap.S().Errorf("Failed updating stats of node %s: %v", nodeID, err)
+		}
+	}
+
+	var result []*pb.RemotePiece
+	for _, piece := range pieces {
+		if _, ok := corruptedSet[piece.GetPieceNum()]; !ok {
+			result = append(result, piece)
+		}
+	}
+	return result
+}
+
+// countLimits returns the number of non-nil order limits.
+func countLimits(limits []*pb.AddressedOrderLimit) int {
+	count := 0
+	for _, limit := range limits {
+		if limit != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// countNodes returns the number of non-nil nodes.
+func countNodes(nodes []*pb.Node) int {
+	count := 0
+	for _, node := range nodes {
+		if node != nil {
+			count++
+		}
+	}
+	return count
 }
 
 // sliceToSet converts the given slice to a set
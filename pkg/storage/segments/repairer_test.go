@@ -18,6 +18,7 @@ import (
 	ecclient "storj.io/storj/pkg/storage/ec"
 	"storj.io/storj/pkg/storage/segments"
 	"storj.io/storj/pkg/storj"
+	"storj.io/storj/satellite/disqualification"
 	"storj.io/storj/uplink"
 )
 
@@ -93,8 +94,8 @@ func TestSegmentStoreRepair(t *testing.T) {
 		// repair segment
 		os := satellite.Orders.Service
 		oc := satellite.Overlay.Service
-		ec := ecclient.NewClient(satellite.Transport, 0)
-		repairer := segments.NewSegmentRepairer(pdb, os, oc, ec, satellite.Identity, time.Minute)
+		ec := ecclient.NewClient(satellite.Transport, 0, 0, 0)
+		repairer := segments.NewSegmentRepairer(pdb, os, oc, satellite.Repair.ReliabilityCache, ec, satellite.Identity, time.Minute, nil)
 		assert.NotNil(t, repairer)
 
 		err = repairer.Repair(ctx, path, lostPieces)
@@ -123,3 +124,217 @@ func TestSegmentStoreRepair(t *testing.T) {
 		}
 	})
 }
+
+func TestSegmentStoreRepairIrreparable(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		ul := planet.Uplinks[0]
+		satellite := planet.Satellites[0]
+
+		satellite.Repair.Checker.Loop.Stop()
+
+		testData := make([]byte, 1*memory.MiB)
+		_, err := rand.Read(testData)
+		require.NoError(t, err)
+
+		err = ul.UploadWithConfig(ctx, satellite, &uplink.RSConfig{
+			MinThreshold:     2,
+			RepairThreshold:  3,
+			SuccessThreshold: 4,
+			MaxThreshold:     4,
+		}, "testbucket", "test/path", testData)
+		require.NoError(t, err)
+
+		pdb := satellite.Metainfo.Service
+		listResponse, _, err := pdb.List("", "", "", true, 0, 0)
+		require.NoError(t, err)
+
+		var path string
+		var pointer *pb.Pointer
+		for _, v := range listResponse {
+			path = v.GetPath()
+			pointer, err = pdb.Get(path)
+			require.NoError(t, err)
+			if pointer.GetType() == pb.Pointer_REMOTE {
+				break
+			}
+		}
+
+		// all pieces but one are lost, fewer than the minimum required
+		var lostPieces []int32
+		for _, piece := range pointer.GetRemote().GetRemotePieces()[1:] {
+			lostPieces = append(lostPieces, piece.GetPieceNum())
+		}
+
+		ec := ecclient.NewClient(satellite.Transport, 0, 0, 0)
+		repairer := segments.NewSegmentRepairer(pdb, satellite.Orders.Service, satellite.Overlay.Service, satellite.Repair.ReliabilityCache, ec, satellite.Identity, time.Minute, nil)
+
+		err = repairer.Repair(ctx, path, lostPieces)
+		require.Error(t, err)
+		assert.True(t, segments.ErrIrreparable.Has(err))
+
+		// the pointer is left untouched
+		after, err := pdb.Get(path)
+		require.NoError(t, err)
+		assert.Equal(t, pointer.GetRemote().GetRemotePieces(), after.GetRemote().GetRemotePieces())
+	})
+}
+
+func TestSegmentStoreRepairKeepsHealthyPieces(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 6, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		ul := planet.Uplinks[0]
+		satellite := planet.Satellites[0]
+
+		satellite.Repair.Checker.Loop.Stop()
+
+		testData := make([]byte, 1*memory.MiB)
+		_, err := rand.Read(testData)
+		require.NoError(t, err)
+
+		err = ul.UploadWithConfig(ctx, satellite, &uplink.RSConfig{
+			MinThreshold:     2,
+			RepairThreshold:  3,
+			SuccessThreshold: 4,
+			MaxThreshold:     4,
+		}, "testbucket", "test/path", testData)
+		require.NoError(t, err)
+
+		pdb := satellite.Metainfo.Service
+		listResponse, _, err := pdb.List("", "", "", true, 0, 0)
+		require.NoError(t, err)
+
+		var path string
+		var pointer *pb.Pointer
+		for _, v := range listResponse {
+			path = v.GetPath()
+			pointer, err = pdb.Get(path)
+			require.NoError(t, err)
+			if pointer.GetType() == pb.Pointer_REMOTE {
+				break
+			}
+		}
+
+		remotePieces := pointer.GetRemote().GetRemotePieces()
+		require.Len(t, remotePieces, 4)
+		lost := remotePieces[0]
+		healthy := make(map[int32]storj.NodeID)
+		usedNodes := make(map[storj.NodeID]bool)
+		for _, piece := range remotePieces {
+			usedNodes[piece.NodeId] = true
+			if piece != lost {
+				healthy[piece.GetPieceNum()] = piece.NodeId
+			}
+		}
+
+		// the checker loaded the reliable nodes when it started
+		require.NoError(t, satellite.Repair.ReliabilityCache.Refresh(ctx))
+
+		ec := ecclient.NewClient(satellite.Transport, 0, 0, 0)
+		repairer := segments.NewSegmentRepairer(pdb, satellite.Orders.Service, satellite.Overlay.Service, satellite.Repair.ReliabilityCache, ec, satellite.Identity, time.Minute, nil)
+
+		err = repairer.Repair(ctx, path, []int32{lost.GetPieceNum()})
+		require.NoError(t, err)
+
+		repaired, err := pdb.Get(path)
+		require.NoError(t, err)
+
+		// the healthy pieces stay in place and only the lost piece is
+		// uploaded to a new node
+		require.Len(t, repaired.GetRemote().GetRemotePieces(), 4)
+		for _, piece := range repaired.GetRemote().GetRemotePieces() {
+			if nodeID, ok := healthy[piece.GetPieceNum()]; ok {
+				assert.Equal(t, nodeID, piece.NodeId)
+				continue
+			}
+			assert.Equal(t, lost.GetPieceNum(), piece.GetPieceNum())
+			assert.False(t, usedNodes[piece.NodeId])
+		}
+
+		newData, err := ul.Download(ctx, satellite, "testbucket", "test/path")
+		require.NoError(t, err)
+		assert.Equal(t, testData, newData)
+	})
+}
+
+func TestSegmentStoreRepairRemovesDeadPieces(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 7, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		ul := planet.Uplinks[0]
+		satellite := planet.Satellites[0]
+
+		satellite.Repair.Checker.Loop.Stop()
+
+		testData := make([]byte, 1*memory.MiB)
+		_, err := rand.Read(testData)
+		require.NoError(t, err)
+
+		err = ul.UploadWithConfig(ctx, satellite, &uplink.RSConfig{
+			MinThreshold:     2,
+			RepairThreshold:  3,
+			SuccessThreshold: 4,
+			MaxThreshold:     4,
+		}, "testbucket", "test/path", testData)
+		require.NoError(t, err)
+
+		pdb := satellite.Metainfo.Service
+		listResponse, _, err := pdb.List("", "", "", true, 0, 0)
+		require.NoError(t, err)
+
+		var path string
+		var pointer *pb.Pointer
+		for _, v := range listResponse {
+			path = v.GetPath()
+			pointer, err = pdb.Get(path)
+			require.NoError(t, err)
+			if pointer.GetType() == pb.Pointer_REMOTE {
+				break
+			}
+		}
+
+		remotePieces := pointer.GetRemote().GetRemotePieces()
+		require.Len(t, remotePieces, 4)
+
+		// the first piece was found lost by the checker and the node of the
+		// second piece was disqualified since, both nodes are still online
+		lost, disqualified := remotePieces[0], remotePieces[1]
+		_, err = satellite.DB.Disqualification().Disqualify(ctx, disqualified.NodeId, disqualification.ReasonAuditScore, "")
+		require.NoError(t, err)
+
+		// the checker loaded the reliable nodes when it started
+		require.NoError(t, satellite.Repair.ReliabilityCache.Refresh(ctx))
+
+		ec := ecclient.NewClient(satellite.Transport, 0, 0, 0)
+		repairer := segments.NewSegmentRepairer(pdb, satellite.Orders.Service, satellite.Overlay.Service, satellite.Repair.ReliabilityCache, ec, satellite.Identity, time.Minute, nil)
+
+		err = repairer.Repair(ctx, path, []int32{lost.GetPieceNum()})
+		require.NoError(t, err)
+
+		repaired, err := pdb.Get(path)
+		require.NoError(t, err)
+
+		// both pieces are repaired and the pointer doesn't reference them
+		require.Len(t, repaired.GetRemote().GetRemotePieces(), 4)
+		for _, piece := range repaired.GetRemote().GetRemotePieces() {
+			assert.NotEqual(t, lost.NodeId, piece.NodeId)
+			assert.NotEqual(t, disqualified.NodeId, piece.NodeId)
+		}
+
+		// the removed pieces are deleted from their nodes
+		rootPieceID := pointer.GetRemote().RootPieceId
+		for _, node := range planet.StorageNodes {
+			if node.ID() != lost.NodeId && node.ID() != disqualified.NodeId {
+				continue
+			}
+			_, err := node.DB.PieceInfo().Get(ctx, satellite.ID(), rootPieceID.Derive(node.ID()))
+			assert.Error(t, err)
+		}
+
+		newData, err := ul.Download(ctx, satellite, "testbucket", "test/path")
+		require.NoError(t, err)
+		assert.Equal(t, testData, newData)
+	})
+}
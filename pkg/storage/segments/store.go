@@ -138,9 +138,16 @@ func (s *segmentStore) Put(ctx context.Context, data io.Reader, expiration time.
 			return Meta{}, Error.Wrap(err)
 		}
 
+		// the satellite hands out fewer limits when it lowers the long-tail
+		// margin, no piece is uploaded for the missing ones
+		padded := limits
+		if len(padded) < s.rs.TotalCount() {
+			padded = append(padded, make([]*pb.AddressedOrderLimit, s.rs.TotalCount()-len(padded))...)
+		}
+
 		sizedReader := SizeReader(peekReader)
 
-		successfulNodes, successfulHashes, err := s.ec.Put(ctx, limits, s.rs, sizedReader, expiration)
+		successfulNodes, successfulHashes, err := s.ec.Put(ctx, padded, s.rs, sizedReader, expiration)
 		if err != nil {
 			return Meta{}, Error.Wrap(err)
 		}
@@ -215,7 +222,7 @@ func (s *segmentStore) Get(ctx context.Context, path storj.Path) (rr ranger.Rang
 			return nil, Meta{}, err
 		}
 
-		rr, err = s.ec.Get(ctx, selected, redundancy, pointer.GetSegmentSize())
+		rr, err = s.ec.Get(ctx, selected, redundancy, pointer.GetSegmentSize(), nil)
 		if err != nil {
 			return nil, Meta{}, Error.Wrap(err)
 		}
@@ -0,0 +1,91 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package segments
+
+import (
+	"context"
+	"io"
+	"math"
+
+	"golang.org/x/time/rate"
+
+	"storj.io/storj/internal/memory"
+)
+
+// Throttle limits the rate of the data a repairer downloads and uploads. It's
+// shared by the concurrent repairs, so that the repair traffic can be kept
+// below the bandwidth needed by the uplinks.
+type Throttle struct {
+	ingress *rate.Limiter
+	egress  *rate.Limiter
+}
+
+// NewThrottle creates a throttle of maxIngress and maxEgress bytes per
+// second, a rate of 0 is unlimited.
+func NewThrottle(maxIngress, maxEgress memory.Size) *Throttle {
+	return &Throttle{
+		ingress: newByteLimiter(maxIngress),
+		egress:  newByteLimiter(maxEgress),
+	}
+}
+
+// newByteLimiter creates a limiter of bytesPerSecond, nil when unlimited.
+func newByteLimiter(bytesPerSecond memory.Size) *rate.Limiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+	// one second of data can be transferred at once
+	burst := bytesPerSecond.Int64()
+	if burst > math.MaxInt32 {
+		burst = math.MaxInt32
+	}
+	return rate.NewLimiter(rate.Limit(bytesPerSecond.Int64()), int(burst))
+}
+
+// waitBytes waits until limiter allows size bytes to be transferred.
+func waitBytes(ctx context.Context, limiter *rate.Limiter, size int64) error {
+	if limiter == nil {
+		return nil
+	}
+	for size > 0 {
+		step := size
+		if burst := int64(limiter.Burst()); step > burst {
+			step = burst
+		}
+		if err := limiter.WaitN(ctx, int(step)); err != nil {
+			return err
+		}
+		size -= step
+	}
+	return nil
+}
+
+// throttledReader throttles the repair of a segment by the data read from
+// the segment: reading n bytes of the segment downloads n/required bytes of
+// each downloaded piece and uploads n/required bytes of each repaired piece.
+type throttledReader struct {
+	ctx      context.Context
+	reader   io.Reader
+	throttle *Throttle
+
+	downloaded int64
+	uploaded   int64
+	required   int64
+}
+
+// Read reads from the segment and waits until the transfer of the pieces
+// is allowed.
+func (reader *throttledReader) Read(p []byte) (n int, err error) {
+	n, err = reader.reader.Read(p)
+	if n <= 0 || reader.throttle == nil {
+		return n, err
+	}
+	if err := waitBytes(reader.ctx, reader.throttle.ingress, int64(n)*reader.downloaded/reader.required); err != nil {
+		return n, err
+	}
+	if err := waitBytes(reader.ctx, reader.throttle.egress, int64(n)*reader.uploaded/reader.required); err != nil {
+		return n, err
+	}
+	return n, err
+}
@@ -0,0 +1,57 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package streams
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+
+	"storj.io/storj/pkg/storj"
+)
+
+// Options contains the per-bucket settings for storing the content of streams
+type Options struct {
+	// Padding obscures the size of the stored content
+	Padding storj.Padding
+
+	// Convergent enables convergent (deterministic) encryption of the content.
+	//
+	// The content key of every segment is derived from the hash of the segment
+	// plaintext and a secret derived from the root encryption key. Uploading
+	// identical content with the same root key therefore produces identical
+	// encrypted segments, which allows them to be deduplicated.
+	//
+	// This weakens the privacy guarantees: anyone knowing the root key can
+	// confirm whether a given plaintext is stored by encrypting it and
+	// comparing the result, and anyone able to see the encrypted segments
+	// learns which of them have equal content. The last segment is bound
+	// to the stream metadata as well, so it converges only if the metadata
+	// and the total size of the stream are equal too.
+	Convergent bool
+}
+
+// convergenceKeyMessage is used to derive the convergence secret from the
+// root encryption key
+const convergenceKeyMessage = "convergent-encryption"
+
+// deriveConvergentKey derives the content key of a segment from the hash of
+// its plaintext, keyed by the convergence secret. If streamInfo is not nil,
+// it is bound to the key too, so that the stream info, which is encrypted
+// with the same key and the zero nonce, never reuses a key with a different
+// plaintext.
+func deriveConvergentKey(secret *storj.Key, plaintext, streamInfo []byte) *storj.Key {
+	mac := hmac.New(sha256.New, secret[:])
+
+	plaintextHash := sha256.Sum256(plaintext)
+	_, _ = mac.Write(plaintextHash[:])
+
+	if streamInfo != nil {
+		streamInfoHash := sha256.Sum256(streamInfo)
+		_, _ = mac.Write(streamInfoHash[:])
+	}
+
+	var key storj.Key
+	copy(key[:], mac.Sum(nil))
+	return &key
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package streams
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/pkg/storage/segments"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storage"
+)
+
+func TestDeriveConvergentKey(t *testing.T) {
+	secret := &storj.Key{1}
+	otherSecret := &storj.Key{2}
+
+	key := deriveConvergentKey(secret, []byte("data"), nil)
+	assert.Equal(t, key, deriveConvergentKey(secret, []byte("data"), nil))
+
+	assert.NotEqual(t, key, deriveConvergentKey(otherSecret, []byte("data"), nil))
+	assert.NotEqual(t, key, deriveConvergentKey(secret, []byte("other"), nil))
+	assert.NotEqual(t, key, deriveConvergentKey(secret, []byte("data"), []byte("info")))
+	assert.NotEqual(t,
+		deriveConvergentKey(secret, []byte("data"), []byte("info")),
+		deriveConvergentKey(secret, []byte("data"), []byte("other info")),
+	)
+}
+
+func TestStreamStorePutConvergent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	data := bytes.Repeat([]byte("convergent"), 5)
+
+	upload := func(path storj.Path, options Options) [][]byte {
+		mockSegmentStore := segments.NewMockStore(ctrl)
+		mockSegmentStore.EXPECT().
+			Meta(gomock.Any(), gomock.Any()).
+			Return(segments.Meta{}, storage.ErrKeyNotFound.New("")).
+			AnyTimes()
+
+		var encrypted [][]byte
+		mockSegmentStore.EXPECT().
+			Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(segments.Meta{}, nil).
+			Do(func(ctx context.Context, data io.Reader, expiration time.Time, info func() (storj.Path, []byte, error)) {
+				segment, err := ioutil.ReadAll(data)
+				require.NoError(t, err)
+				encrypted = append(encrypted, segment)
+				_, _, err = info()
+				require.NoError(t, err)
+			}).
+			AnyTimes()
+
+		streamStore, err := NewStreamStore(mockSegmentStore, 16, &storj.Key{1}, 64, storj.AESGCM)
+		require.NoError(t, err)
+
+		_, err = streamStore.Put(ctx, path, storj.AESGCM, options, bytes.NewReader(data), []byte("metadata"), time.Time{})
+		require.NoError(t, err)
+
+		return encrypted
+	}
+
+	first := upload("bucket/first", Options{Convergent: true})
+	second := upload("bucket/second", Options{Convergent: true})
+	require.Len(t, first, 4)
+	assert.Equal(t, first, second)
+
+	random := upload("bucket/random", Options{})
+	require.Len(t, random, 4)
+	assert.NotEqual(t, first, random)
+}
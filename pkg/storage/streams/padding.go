@@ -0,0 +1,57 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package streams
+
+import "io"
+
+// PaddingReader appends zero bytes to the data of the wrapped reader
+type PaddingReader struct {
+	reader    io.Reader
+	size      int64
+	paddedTo  func(size int64) int64
+	remaining int64
+	eof       bool
+}
+
+// NewPaddingReader returns a reader that, once r reaches EOF, appends zero
+// bytes until the total size is paddedTo(size), where size is the number of
+// bytes read from r. paddedTo is called only once, after r reached EOF.
+func NewPaddingReader(r io.Reader, paddedTo func(size int64) int64) *PaddingReader {
+	return &PaddingReader{reader: r, paddedTo: paddedTo}
+}
+
+func (r *PaddingReader) Read(p []byte) (n int, err error) {
+	if !r.eof {
+		n, err = r.reader.Read(p)
+		r.size += int64(n)
+		if err != io.EOF {
+			return n, err
+		}
+		r.eof = true
+		r.remaining = r.paddedTo(r.size) - r.size
+		if n > 0 {
+			return n, nil
+		}
+	}
+
+	if r.remaining <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+	for i := range p {
+		p[i] = 0
+	}
+	r.remaining -= int64(len(p))
+	return len(p), nil
+}
+
+// roundUp rounds size up to a multiple of blockSize
+func roundUp(size int64, blockSize int) int64 {
+	if remainder := size % int64(blockSize); remainder > 0 {
+		size += int64(blockSize) - remainder
+	}
+	return size
+}
@@ -0,0 +1,47 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package streams
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaddingReader(t *testing.T) {
+	for _, tt := range []struct {
+		data     []byte
+		paddedTo int64
+	}{
+		{[]byte{}, 0},
+		{[]byte{}, 16},
+		{[]byte("data"), 4},
+		{[]byte("data"), 5},
+		{[]byte("data"), 1024},
+	} {
+		var size int64 = -1
+		reader := NewPaddingReader(bytes.NewReader(tt.data), func(n int64) int64 {
+			size = n
+			return tt.paddedTo
+		})
+
+		padded, err := ioutil.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(tt.data)), size)
+		if assert.Len(t, padded, int(tt.paddedTo)) {
+			assert.Equal(t, tt.data, padded[:len(tt.data)])
+			assert.Equal(t, make([]byte, int(tt.paddedTo)-len(tt.data)), padded[len(tt.data):])
+		}
+	}
+}
+
+func TestRoundUp(t *testing.T) {
+	assert.EqualValues(t, 0, roundUp(0, 16))
+	assert.EqualValues(t, 16, roundUp(1, 16))
+	assert.EqualValues(t, 16, roundUp(16, 16))
+	assert.EqualValues(t, 32, roundUp(17, 16))
+}
@@ -58,7 +58,7 @@ func convertMeta(lastSegmentMeta segments.Meta) (Meta, error) {
 type Store interface {
 	Meta(ctx context.Context, path storj.Path, pathCipher storj.Cipher) (Meta, error)
 	Get(ctx context.Context, path storj.Path, pathCipher storj.Cipher) (ranger.Ranger, Meta, error)
-	Put(ctx context.Context, path storj.Path, pathCipher storj.Cipher, data io.Reader, metadata []byte, expiration time.Time) (Meta, error)
+	Put(ctx context.Context, path storj.Path, pathCipher storj.Cipher, options Options, data io.Reader, metadata []byte, expiration time.Time) (Meta, error)
 	Delete(ctx context.Context, path storj.Path, pathCipher storj.Cipher) error
 	List(ctx context.Context, prefix, startAfter, endBefore storj.Path, pathCipher storj.Cipher, recursive bool, limit int, metaFlags uint32) (items []ListItem, more bool, err error)
 }
@@ -70,6 +70,9 @@ type streamStore struct {
 	rootKey      *storj.Key
 	encBlockSize int
 	cipher       storj.Cipher
+
+	// convergenceSecret keys the derivation of convergent content keys
+	convergenceSecret *storj.Key
 }
 
 // NewStreamStore stuff
@@ -84,20 +87,30 @@ func NewStreamStore(segments segments.Store, segmentSize int64, rootKey *storj.K
 		return nil, errs.New("encryption block size must be larger than 0")
 	}
 
+	convergenceSecret, err := encryption.DeriveKey(rootKey, convergenceKeyMessage)
+	if err != nil {
+		return nil, err
+	}
+
 	return &streamStore{
-		segments:     segments,
-		segmentSize:  segmentSize,
-		rootKey:      rootKey,
-		encBlockSize: encBlockSize,
-		cipher:       cipher,
+		segments:          segments,
+		segmentSize:       segmentSize,
+		rootKey:           rootKey,
+		encBlockSize:      encBlockSize,
+		cipher:            cipher,
+		convergenceSecret: convergenceSecret,
 	}, nil
 }
 
 // Put breaks up data as it comes in into s.segmentSize length pieces, then
 // store the first piece at s0/<path>, second piece at s1/<path>, and the
 // *last* piece at l/<path>. Store the given metadata, along with the number
-// of segments, in a new protobuf, in the metadata of l/<path>.
-func (s *streamStore) Put(ctx context.Context, path storj.Path, pathCipher storj.Cipher, data io.Reader, metadata []byte, expiration time.Time) (m Meta, err error) {
+// of segments, in a new protobuf, in the metadata of l/<path>. If padding is
+// configured, the encrypted content of the last segment is padded with it,
+// while its logical size is kept only in the encrypted stream info. If
+// convergent encryption is enabled, every segment is buffered in memory to
+// derive its content key from its plaintext.
+func (s *streamStore) Put(ctx context.Context, path storj.Path, pathCipher storj.Cipher, options Options, data io.Reader, metadata []byte, expiration time.Time) (m Meta, err error) {
 	defer mon.Task()(&ctx)(&err)
 	// previously file uploaded?
 	err = s.Delete(ctx, path, pathCipher)
@@ -107,7 +120,7 @@ func (s *streamStore) Put(ctx context.Context, path storj.Path, pathCipher storj
 		return Meta{}, err
 	}
 
-	m, lastSegment, err := s.upload(ctx, path, pathCipher, data, metadata, expiration)
+	m, lastSegment, err := s.upload(ctx, path, pathCipher, options, data, metadata, expiration)
 	if err != nil {
 		s.cancelHandler(context.Background(), lastSegment, path, pathCipher)
 	}
@@ -115,7 +128,7 @@ func (s *streamStore) Put(ctx context.Context, path storj.Path, pathCipher storj
 	return m, err
 }
 
-func (s *streamStore) upload(ctx context.Context, path storj.Path, pathCipher storj.Cipher, data io.Reader, metadata []byte, expiration time.Time) (m Meta, lastSegment int64, err error) {
+func (s *streamStore) upload(ctx context.Context, path storj.Path, pathCipher storj.Cipher, options Options, data io.Reader, metadata []byte, expiration time.Time) (m Meta, lastSegment int64, err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	var currentSegment int64
@@ -138,11 +151,36 @@ func (s *streamStore) upload(ctx context.Context, path storj.Path, pathCipher st
 	eofReader := NewEOFReader(data)
 
 	for !eofReader.isEOF() && !eofReader.hasError() {
-		// generate random key for encrypting the segment's content
 		var contentKey storj.Key
-		_, err = rand.Read(contentKey[:])
-		if err != nil {
-			return Meta{}, currentSegment, err
+		var segmentData io.Reader = eofReader
+		if options.Convergent {
+			// derive the key for encrypting the segment's content from its plaintext
+			plaintext, err := ioutil.ReadAll(io.LimitReader(eofReader, s.segmentSize))
+			if err != nil {
+				return Meta{}, currentSegment, err
+			}
+
+			var streamInfo []byte
+			if eofReader.isEOF() {
+				streamInfo, err = proto.Marshal(&pb.StreamInfo{
+					NumberOfSegments: currentSegment + 1,
+					SegmentsSize:     s.segmentSize,
+					LastSegmentSize:  int64(len(plaintext)),
+					Metadata:         metadata,
+				})
+				if err != nil {
+					return Meta{}, currentSegment, err
+				}
+			}
+
+			contentKey = *deriveConvergentKey(s.convergenceSecret, plaintext, streamInfo)
+			segmentData = bytes.NewReader(plaintext)
+		} else {
+			// generate random key for encrypting the segment's content
+			_, err = rand.Read(contentKey[:])
+			if err != nil {
+				return Meta{}, currentSegment, err
+			}
 		}
 
 		// Initialize the content nonce with the segment's index incremented by 1.
@@ -171,7 +209,7 @@ func (s *streamStore) upload(ctx context.Context, path storj.Path, pathCipher st
 			return Meta{}, currentSegment, err
 		}
 
-		sizeReader := NewSizeReader(eofReader)
+		sizeReader := NewSizeReader(segmentData)
 		segmentReader := io.LimitReader(sizeReader, s.segmentSize)
 		peekReader := segments.NewPeekThresholdReader(segmentReader)
 		largeData, err := peekReader.IsLargerThan(encrypter.InBlockSize())
@@ -179,7 +217,20 @@ func (s *streamStore) upload(ctx context.Context, path storj.Path, pathCipher st
 			return Meta{}, currentSegment, err
 		}
 		var transformedReader io.Reader
-		if largeData {
+		if padding := options.Padding; !padding.IsZero() {
+			blockSize := encrypter.InBlockSize()
+			paddedReader := NewPaddingReader(peekReader, func(size int64) int64 {
+				if eofReader.isEOF() {
+					size = padding.PaddedSize(size)
+				}
+				if size == 0 {
+					// pad empty content to a single block as well
+					size = 1
+				}
+				return roundUp(size, blockSize)
+			})
+			transformedReader = encryption.TransformReader(ioutil.NopCloser(paddedReader), encrypter, 0)
+		} else if largeData {
 			paddedReader := eestream.PadReader(ioutil.NopCloser(peekReader), encrypter.InBlockSize())
 			transformedReader = encryption.TransformReader(paddedReader, encrypter, 0)
 		} else {
@@ -168,7 +168,7 @@ func TestStreamStorePut(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		meta, err := streamStore.Put(ctx, test.path, storj.AESGCM, test.data, test.metadata, test.expiration)
+		meta, err := streamStore.Put(ctx, test.path, storj.AESGCM, Options{}, test.data, test.metadata, test.expiration)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -6,6 +6,7 @@ package storj // import "storj.io/storj/pkg/storj"
 import (
 	"crypto/sha256"
 	"database/sql/driver"
+	"encoding/json"
 	"math/bits"
 
 	"github.com/btcsuite/btcutil/base58"
@@ -160,8 +161,13 @@ func (id *NodeID) Scan(src interface{}) (err error) {
 
 // UnmarshalJSON deserializes a json string (as bytes) to a node ID
 func (id *NodeID) UnmarshalJSON(data []byte) error {
+	var unquoted string
+	if err := json.Unmarshal(data, &unquoted); err != nil {
+		return ErrNodeID.Wrap(err)
+	}
+
 	var err error
-	*id, err = NodeIDFromString(string(data))
+	*id, err = NodeIDFromString(unquoted)
 	if err != nil {
 		return err
 	}
@@ -5,6 +5,7 @@ package storj_test
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -80,3 +81,16 @@ func TestNodeValue(t *testing.T) {
 	require.IsType(t, v, []byte{})
 	require.Len(t, v, storj.NodeIDSize)
 }
+
+// TestNodeJSON tests that NodeID.MarshalJSON and (*NodeID).UnmarshalJSON round trip
+func TestNodeJSON(t *testing.T) {
+	id := storj.NodeID{1, 2, 3}
+	data, err := json.Marshal(id)
+	require.NoError(t, err)
+
+	var decoded storj.NodeID
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, id, decoded)
+
+	require.Error(t, json.Unmarshal([]byte(`1`), &decoded))
+}
@@ -28,6 +28,12 @@ type Bucket struct {
 	Name       string
 	Created    time.Time
 	PathCipher Cipher
+	Padding    Padding
+
+	// Convergent enables convergent encryption of the bucket content, which
+	// allows identical content to be deduplicated at the cost of revealing
+	// which objects have equal content. See streams.Options for details.
+	Convergent bool
 }
 
 // Object contains information about a specific object
@@ -0,0 +1,41 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storj
+
+// Padding specifies how the encrypted content of objects is padded to
+// prevent observers of the satellite or the storage nodes from inferring
+// the exact size of the objects.
+//
+// Padding is applied to the last segment of a stream, since all the other
+// segments already have the same size. The logical size of the content is
+// kept only in the encrypted stream metadata.
+type Padding struct {
+	// PowerOfTwo pads the content to the next power of two.
+	PowerOfTwo bool
+	// Boundary pads the content to a multiple of the given number of bytes.
+	Boundary int64
+}
+
+// IsZero returns true if no padding is configured
+func (padding Padding) IsZero() bool {
+	return padding == (Padding{})
+}
+
+// PaddedSize returns the size to which content of the given size is padded
+func (padding Padding) PaddedSize(size int64) int64 {
+	padded := size
+	if padding.PowerOfTwo && padded > 0 {
+		next := int64(1)
+		for next < padded {
+			next <<= 1
+		}
+		padded = next
+	}
+	if padding.Boundary > 0 {
+		if remainder := padded % padding.Boundary; remainder > 0 {
+			padded += padding.Boundary - remainder
+		}
+	}
+	return padded
+}
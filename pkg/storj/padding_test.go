@@ -0,0 +1,38 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storj_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/pkg/storj"
+)
+
+func TestPaddedSize(t *testing.T) {
+	for i, tt := range []struct {
+		padding  storj.Padding
+		size     int64
+		expected int64
+	}{
+		{storj.Padding{}, 0, 0},
+		{storj.Padding{}, 1000, 1000},
+		{storj.Padding{PowerOfTwo: true}, 0, 0},
+		{storj.Padding{PowerOfTwo: true}, 1, 1},
+		{storj.Padding{PowerOfTwo: true}, 1000, 1024},
+		{storj.Padding{PowerOfTwo: true}, 1024, 1024},
+		{storj.Padding{PowerOfTwo: true}, 1025, 2048},
+		{storj.Padding{Boundary: 256}, 1, 256},
+		{storj.Padding{Boundary: 256}, 512, 512},
+		{storj.Padding{Boundary: 256}, 513, 768},
+		{storj.Padding{PowerOfTwo: true, Boundary: 4096}, 1000, 4096},
+		{storj.Padding{PowerOfTwo: true, Boundary: 4096}, 5000, 8192},
+	} {
+		assert.Equal(t, tt.expected, tt.padding.PaddedSize(tt.size), i)
+	}
+
+	assert.True(t, storj.Padding{}.IsZero())
+	assert.False(t, storj.Padding{Boundary: 1}.IsZero())
+}
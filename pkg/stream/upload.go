@@ -49,7 +49,7 @@ func NewUpload(ctx context.Context, stream storj.MutableStream, streams streams.
 			return errs.Combine(err, reader.CloseWithError(err))
 		}
 
-		_, err = streams.Put(ctx, storj.JoinPaths(obj.Bucket.Name, obj.Path), obj.Bucket.PathCipher, reader, metadata, obj.Expires)
+		_, err = streams.Put(ctx, storj.JoinPaths(obj.Bucket.Name, obj.Path), obj.Bucket.PathCipher, bucketOptions(obj.Bucket), reader, metadata, obj.Expires)
 		if err != nil {
 			return errs.Combine(err, reader.CloseWithError(err))
 		}
@@ -60,6 +60,14 @@ func NewUpload(ctx context.Context, stream storj.MutableStream, streams streams.
 	return &upload
 }
 
+// bucketOptions returns the options for storing stream content in the bucket
+func bucketOptions(bucket storj.Bucket) streams.Options {
+	return streams.Options{
+		Padding:    bucket.Padding,
+		Convergent: bucket.Convergent,
+	}
+}
+
 // Write writes len(data) bytes from data to the underlying data stream.
 //
 // See io.Writer for more details.
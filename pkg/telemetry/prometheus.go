@@ -0,0 +1,97 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package telemetry
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+)
+
+// prometheusContentType is the content type of the prometheus text format
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// PrometheusHandler returns a handler serving the stats of registry in the
+// prometheus text format.
+func PrometheusHandler(registry *monkit.Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", prometheusContentType)
+		_ = WritePrometheus(w, registry)
+	})
+}
+
+// prometheusSample is a value of a metric from a monkit scope.
+type prometheusSample struct {
+	scope string
+	value float64
+}
+
+// WritePrometheus writes the stats of registry in the prometheus text format.
+//
+// The metric name is the stat name prefixed by the last element of the
+// package path of its scope, with the characters prometheus doesn't allow
+// replaced by underscores. The full scope is kept in the scope label.
+func WritePrometheus(w io.Writer, registry *monkit.Registry) error {
+	metrics := map[string][]prometheusSample{}
+	registry.Scopes(func(scope *monkit.Scope) {
+		scopeName := scope.Name()
+		prefix := path.Base(scopeName)
+		scope.Stats(func(name string, value float64) {
+			metric := prometheusName(prefix + "_" + name)
+			metrics[metric] = append(metrics[metric], prometheusSample{scope: scopeName, value: value})
+		})
+	})
+
+	names := make([]string, 0, len(metrics))
+	for name := range metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buffered := bufio.NewWriter(w)
+	for _, name := range names {
+		_, _ = buffered.WriteString("# TYPE " + name + " untyped\n")
+		for _, sample := range metrics[name] {
+			_, _ = buffered.WriteString(name + `{scope="` + prometheusLabelValue(sample.scope) + `"} `)
+			_, _ = buffered.WriteString(strconv.FormatFloat(sample.value, 'g', -1, 64) + "\n")
+		}
+	}
+	return buffered.Flush()
+}
+
+// prometheusName replaces the characters which aren't allowed in a metric
+// name with single underscores.
+func prometheusName(name string) string {
+	var b strings.Builder
+	underscore := false
+	for i, r := range name {
+		valid := r == '_' || r == ':' ||
+			('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') ||
+			('0' <= r && r <= '9' && i > 0)
+		if !valid {
+			r = '_'
+		}
+		if r == '_' {
+			if underscore {
+				continue
+			}
+			underscore = true
+		} else {
+			underscore = false
+		}
+		_, _ = b.WriteRune(r)
+	}
+	return strings.Trim(b.String(), "_")
+}
+
+// prometheusLabelValue escapes the backslashes, quotes and new lines of a label value.
+func prometheusLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
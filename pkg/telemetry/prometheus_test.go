@@ -0,0 +1,42 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package telemetry
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+)
+
+func TestPrometheusName(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		expected string
+	}{
+		{"piecestore_db_pieces.recent", "piecestore_db_pieces_recent"},
+		{"piecestore_(*Endpoint).Upload.success times r50", "piecestore_Endpoint_Upload_success_times_r50"},
+		{"9lives_x", "lives_x"},
+		{"a:b", "a:b"},
+	} {
+		assert.Equal(t, test.expected, prometheusName(test.name), test.name)
+	}
+}
+
+func TestWritePrometheus(t *testing.T) {
+	registry := monkit.NewRegistry()
+	registry.ScopeNamed("storj.io/storj/storagenode/orders").Counter("unsent").Inc(3)
+	registry.ScopeNamed("storj.io/storj/satellite/orders").Counter("unsent").Inc(5)
+
+	var buffer bytes.Buffer
+	require.NoError(t, WritePrometheus(&buffer, registry))
+
+	output := buffer.String()
+	assert.Contains(t, output, "# TYPE orders_unsent_val untyped\n")
+	assert.Contains(t, output, `orders_unsent_val{scope="storj.io/storj/storagenode/orders"} 3`+"\n")
+	assert.Contains(t, output, `orders_unsent_val{scope="storj.io/storj/satellite/orders"} 5`+"\n")
+	assert.Equal(t, 1, bytes.Count(buffer.Bytes(), []byte("# TYPE orders_unsent_val ")))
+}
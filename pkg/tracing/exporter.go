@@ -0,0 +1,355 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+)
+
+const (
+	// DefaultInterval is the default amount of time between span exports
+	DefaultInterval = 10 * time.Second
+
+	// DefaultBufferSize is the default number of finished spans kept in
+	// memory between exports. Spans are dropped when the buffer is full.
+	DefaultBufferSize = 10000
+
+	// DefaultRequestTimeout is the default timeout for sending spans to the collector
+	DefaultRequestTimeout = 10 * time.Second
+
+	// DefaultApplication is the default value for the service name. Should be used
+	// when value in ExporterOpts.Application is not set and len(os.Args) == 0
+	DefaultApplication = "unknown"
+)
+
+// traceKey is the type of the values stored on a monkit.Trace
+type traceKey int
+
+const (
+	// sampledKey stores whether the spans of a trace are exported
+	sampledKey traceKey = iota
+	// remoteParentKey stores the id of the span in another process that started the trace
+	remoteParentKey
+)
+
+// ExporterOpts allows you to set Exporter options
+type ExporterOpts struct {
+	// Interval is how frequently finished spans are sent to the collector.
+	// Defaults to DefaultInterval
+	Interval time.Duration
+
+	// Application is the service name the spans are reported under.
+	// By default it will be os.Args[0]
+	Application string
+
+	// Instance is a string that identifies this particular process, usually
+	// the node id.
+	Instance string
+
+	// SampleRate is the fraction of new traces, between 0 and 1, that are exported.
+	// Traces started by another process follow the decision of that process.
+	SampleRate float64
+
+	// BufferSize is the maximum number of finished spans kept between exports.
+	// Defaults to DefaultBufferSize
+	BufferSize int
+
+	// Registry is where to observe traces from. Defaults to monkit.Default
+	Registry *monkit.Registry
+
+	// Client is used for sending spans. Defaults to a client with DefaultRequestTimeout
+	Client *http.Client
+}
+
+// Exporter collects the spans of sampled traces and sends them in batches to
+// an OpenTelemetry (OTLP/HTTP JSON) collector, such as Jaeger.
+type Exporter struct {
+	collector string
+	opts      ExporterOpts
+	cancel    func()
+
+	mu      sync.Mutex
+	spans   []otlpSpan
+	dropped int
+}
+
+// NewExporter constructs an exporter that sends spans to the collector url,
+// e.g. http://localhost:4318/v1/traces. Traces are observed from the moment the
+// exporter is constructed until it is closed.
+func NewExporter(collector string, opts ExporterOpts) (*Exporter, error) {
+	u, err := url.Parse(collector)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, Error.New("collector %q is not an http url", collector)
+	}
+	if opts.SampleRate < 0 || opts.SampleRate > 1 {
+		return nil, Error.New("sample rate %v is not between 0 and 1", opts.SampleRate)
+	}
+
+	if opts.Interval == 0 {
+		opts.Interval = DefaultInterval
+	}
+	if opts.Application == "" {
+		if len(os.Args) > 0 {
+			opts.Application = os.Args[0]
+		} else {
+			opts.Application = DefaultApplication
+		}
+	}
+	if opts.BufferSize == 0 {
+		opts.BufferSize = DefaultBufferSize
+	}
+	if opts.Registry == nil {
+		opts.Registry = monkit.Default
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: DefaultRequestTimeout}
+	}
+
+	exporter := &Exporter{
+		collector: collector,
+		opts:      opts,
+	}
+	exporter.cancel = opts.Registry.ObserveTraces(exporter.observeTrace)
+	return exporter, nil
+}
+
+// observeTrace decides whether the spans of a new trace are exported
+func (exporter *Exporter) observeTrace(trace *monkit.Trace) {
+	sampled, ok := trace.Get(sampledKey).(bool)
+	if !ok {
+		sampled = exporter.opts.SampleRate > 0 && rand.Float64() < exporter.opts.SampleRate
+		trace.Set(sampledKey, sampled)
+	}
+	if sampled {
+		trace.ObserveSpans(exporter)
+	}
+}
+
+// Start implements monkit.SpanObserver
+func (exporter *Exporter) Start(s *monkit.Span) {}
+
+// Finish implements monkit.SpanObserver
+func (exporter *Exporter) Finish(s *monkit.Span, err error, panicked bool, finish time.Time) {
+	span := convertSpan(s, err, panicked, finish)
+
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+
+	if len(exporter.spans) >= exporter.opts.BufferSize {
+		exporter.dropped++
+		return
+	}
+	exporter.spans = append(exporter.spans, span)
+}
+
+// Run sends the finished spans every Interval until ctx is canceled
+func (exporter *Exporter) Run(ctx context.Context) {
+	zap.S().Debugf("Initialized trace exporter for %q", exporter.collector)
+
+	ticker := time.NewTicker(exporter.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := exporter.Flush(ctx); err != nil {
+			zap.S().Errorf("failed sending spans: %v", err)
+		}
+	}
+}
+
+// Flush sends all finished spans to the collector.
+//
+// Flush doesn't create spans itself, otherwise every export would be exported again.
+func (exporter *Exporter) Flush(ctx context.Context) (err error) {
+	exporter.mu.Lock()
+	spans, dropped := exporter.spans, exporter.dropped
+	exporter.spans, exporter.dropped = nil, 0
+	exporter.mu.Unlock()
+
+	if dropped > 0 {
+		mon.Counter("dropped_spans").Inc(int64(dropped))
+	}
+	if len(spans) == 0 {
+		return nil
+	}
+	mon.IntVal("exported_spans").Observe(int64(len(spans)))
+
+	data, err := json.Marshal(exporter.request(spans))
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, exporter.collector, bytes.NewReader(data))
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := exporter.opts.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, Error.Wrap(resp.Body.Close())) }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return Error.New("unexpected status %q", resp.Status)
+	}
+	return nil
+}
+
+// Close stops observing traces and sends the remaining spans
+func (exporter *Exporter) Close() error {
+	exporter.cancel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultRequestTimeout)
+	defer cancel()
+	return exporter.Flush(ctx)
+}
+
+// request creates the collector request for the spans
+func (exporter *Exporter) request(spans []otlpSpan) otlpRequest {
+	attributes := []otlpAttribute{stringAttribute("service.name", exporter.opts.Application)}
+	if exporter.opts.Instance != "" {
+		attributes = append(attributes, stringAttribute("service.instance.id", exporter.opts.Instance))
+	}
+
+	return otlpRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{Attributes: attributes},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: "storj.io/storj"},
+				Spans: spans,
+			}},
+		}},
+	}
+}
+
+// convertSpan converts a finished monkit span to its OTLP representation
+func convertSpan(s *monkit.Span, err error, panicked bool, finish time.Time) otlpSpan {
+	span := otlpSpan{
+		TraceID:           fmt.Sprintf("%032x", uint64(s.Trace().Id())),
+		SpanID:            formatSpanID(s.Id()),
+		Name:              s.Func().FullName(),
+		Kind:              spanKindInternal,
+		StartTimeUnixNano: strconv.FormatInt(s.Start().UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(finish.UnixNano(), 10),
+	}
+
+	switch scope := s.Func().Scope(); {
+	case scope == serverMon:
+		span.Kind = spanKindServer
+	case scope == clientMon:
+		span.Kind = spanKindClient
+	}
+
+	if parent := s.Parent(); parent != nil {
+		span.ParentSpanID = formatSpanID(parent.Id())
+	} else if parentID, ok := s.Trace().Get(remoteParentKey).(int64); ok {
+		span.ParentSpanID = formatSpanID(parentID)
+	}
+
+	for i, arg := range s.Args() {
+		span.Attributes = append(span.Attributes, stringAttribute("arg."+strconv.Itoa(i), arg))
+	}
+	for _, annotation := range s.Annotations() {
+		span.Attributes = append(span.Attributes, stringAttribute(annotation.Name, annotation.Value))
+	}
+
+	switch {
+	case panicked:
+		span.Status = &otlpStatus{Code: statusCodeError, Message: "panic"}
+	case err != nil:
+		span.Status = &otlpStatus{Code: statusCodeError, Message: err.Error()}
+	}
+
+	return span
+}
+
+// formatSpanID formats a monkit id as an OTLP span id
+func formatSpanID(id int64) string { return fmt.Sprintf("%016x", uint64(id)) }
+
+// stringAttribute creates a string valued attribute
+func stringAttribute(key, value string) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpValue{StringValue: value}}
+}
+
+// OTLP span kinds and status codes
+const (
+	spanKindInternal = 1
+	spanKindServer   = 2
+	spanKindClient   = 3
+
+	statusCodeError = 2
+)
+
+// otlpRequest is the JSON encoding of an OTLP ExportTraceServiceRequest
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            *otlpStatus     `json:"status,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,119 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package tracing
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+)
+
+// metadata keys used for propagating traces between processes
+const (
+	traceIDHeader  = "storj-trace-id"
+	parentIDHeader = "storj-parent-id"
+	sampledHeader  = "storj-trace-sampled"
+)
+
+var (
+	// serverMon contains a span for every handled request
+	serverMon = monkit.ScopeNamed("grpc.server")
+	// clientMon contains a span for every sent request
+	clientMon = monkit.ScopeNamed("grpc.client")
+)
+
+// UnaryServerInterceptor creates a span for the request, continuing the
+// trace of the caller when it sent one.
+func UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer serverTask(&ctx, info.FullMethod)(&err)
+	return handler(ctx, req)
+}
+
+// StreamServerInterceptor creates a span for the stream, continuing the
+// trace of the caller when it sent one.
+func StreamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	ctx := ss.Context()
+	defer serverTask(&ctx, info.FullMethod)(&err)
+	return handler(srv, &tracedServerStream{ServerStream: ss, ctx: ctx})
+}
+
+// UnaryClientInterceptor creates a span for the request and sends the trace to the server.
+func UnaryClientInterceptor(ctx context.Context, method string, req interface{}, reply interface{},
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) (err error) {
+	defer clientMon.FuncNamed(method).Task(&ctx)(&err)
+	return invoker(outgoingContext(ctx), method, req, reply, cc, opts...)
+}
+
+// StreamClientInterceptor sends the trace to the server when opening a stream.
+//
+// The stream outlives the call, hence no span is created for it.
+func StreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+	method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return streamer(outgoingContext(ctx), desc, cc, method, opts...)
+}
+
+// serverTask starts the span of a request
+func serverTask(ctx *context.Context, method string) func(*error) {
+	f := serverMon.FuncNamed(method)
+
+	md, ok := metadata.FromIncomingContext(*ctx)
+	if !ok {
+		return f.Task(ctx)
+	}
+	traceID, err := parseID(md, traceIDHeader)
+	if err != nil {
+		return f.Task(ctx)
+	}
+	parentID, err := parseID(md, parentIDHeader)
+	if err != nil {
+		return f.Task(ctx)
+	}
+
+	trace := monkit.NewTrace(traceID)
+	trace.Set(remoteParentKey, parentID)
+	if values := md.Get(sampledHeader); len(values) > 0 {
+		if sampled, err := strconv.ParseBool(values[0]); err == nil {
+			trace.Set(sampledKey, sampled)
+		}
+	}
+	return f.RemoteTrace(ctx, monkit.NewId(), trace)
+}
+
+// outgoingContext adds the current trace to the metadata of ctx
+func outgoingContext(ctx context.Context) context.Context {
+	span := monkit.SpanFromCtx(ctx)
+	if span == nil {
+		return ctx
+	}
+
+	pairs := []string{
+		traceIDHeader, strconv.FormatInt(span.Trace().Id(), 10),
+		parentIDHeader, strconv.FormatInt(span.Id(), 10),
+	}
+	if sampled, ok := span.Trace().Get(sampledKey).(bool); ok {
+		pairs = append(pairs, sampledHeader, strconv.FormatBool(sampled))
+	}
+	return metadata.AppendToOutgoingContext(ctx, pairs...)
+}
+
+// parseID parses the id stored in the metadata under key
+func parseID(md metadata.MD, key string) (int64, error) {
+	values := md.Get(key)
+	if len(values) == 0 {
+		return 0, Error.New("missing %s", key)
+	}
+	return strconv.ParseInt(values[0], 10, 64)
+}
+
+// tracedServerStream replaces the context of a server stream
+type tracedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context of the stream
+func (stream *tracedServerStream) Context() context.Context { return stream.ctx }
@@ -0,0 +1,189 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package tracing_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/pkg/tracing"
+)
+
+type span struct {
+	TraceID      string `json:"traceId"`
+	SpanID       string `json:"spanId"`
+	ParentSpanID string `json:"parentSpanId"`
+	Name         string `json:"name"`
+	Kind         int    `json:"kind"`
+	Status       *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"status"`
+}
+
+// collector records the spans sent to it
+type collector struct {
+	mu    sync.Mutex
+	spans []span
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		ResourceSpans []struct {
+			ScopeSpans []struct {
+				Spans []span `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, resource := range request.ResourceSpans {
+		for _, scope := range resource.ScopeSpans {
+			c.spans = append(c.spans, scope.Spans...)
+		}
+	}
+}
+
+func (c *collector) byName(name string) (span, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, s := range c.spans {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return span{}, false
+}
+
+func TestExporter(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	var spans collector
+	server := httptest.NewServer(&spans)
+	defer server.Close()
+
+	registry := monkit.NewRegistry()
+	scope := registry.ScopeNamed("test")
+
+	_, err := tracing.NewExporter("localhost:4318", tracing.ExporterOpts{Registry: registry})
+	assert.Error(t, err)
+	_, err = tracing.NewExporter(server.URL, tracing.ExporterOpts{Registry: registry, SampleRate: 2})
+	assert.Error(t, err)
+
+	exporter, err := tracing.NewExporter(server.URL, tracing.ExporterOpts{
+		Application: "test",
+		SampleRate:  1,
+		Registry:    registry,
+	})
+	require.NoError(t, err)
+
+	func() {
+		var err error
+		ctx := context.Context(ctx)
+		defer scope.FuncNamed("parent").Task(&ctx)(&err)
+
+		func() {
+			err := errors.New("failure")
+			ctx := ctx
+			defer scope.FuncNamed("child").Task(&ctx)(&err)
+		}()
+	}()
+
+	require.NoError(t, exporter.Close())
+
+	parent, ok := spans.byName("test.parent")
+	require.True(t, ok)
+	child, ok := spans.byName("test.child")
+	require.True(t, ok)
+
+	assert.Len(t, parent.TraceID, 32)
+	assert.Len(t, parent.SpanID, 16)
+	assert.Equal(t, parent.TraceID, child.TraceID)
+	assert.Equal(t, parent.SpanID, child.ParentSpanID)
+	assert.Empty(t, parent.ParentSpanID)
+	assert.Nil(t, parent.Status)
+	require.NotNil(t, child.Status)
+	assert.Equal(t, "failure", child.Status.Message)
+
+	{ // closed exporters don't observe traces anymore
+		var err error
+		ctx := context.Context(ctx)
+		scope.FuncNamed("closed").Task(&ctx)(&err)
+		require.NoError(t, exporter.Flush(ctx))
+
+		_, ok := spans.byName("test.closed")
+		assert.False(t, ok)
+	}
+}
+
+func TestPropagation(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	var spans collector
+	server := httptest.NewServer(&spans)
+	defer server.Close()
+
+	exporter, err := tracing.NewExporter(server.URL, tracing.ExporterOpts{SampleRate: 1})
+	require.NoError(t, err)
+
+	var serverTrace int64
+	// invoker passes the outgoing metadata of the client to the server
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		serverCtx := metadata.NewIncomingContext(context.Background(), md)
+
+		_, err := tracing.UnaryServerInterceptor(serverCtx, req, &grpc.UnaryServerInfo{FullMethod: method},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				serverTrace = monkit.SpanFromCtx(ctx).Trace().Id()
+				return nil, nil
+			})
+		return err
+	}
+
+	var clientTrace int64
+	func() {
+		var err error
+		ctx := context.Context(ctx)
+		defer monkit.ScopeNamed("test").FuncNamed("upload").Task(&ctx)(&err)
+		clientTrace = monkit.SpanFromCtx(ctx).Trace().Id()
+
+		err = tracing.UnaryClientInterceptor(ctx, "/test.Service/Method", nil, nil, nil, invoker)
+	}()
+
+	require.NoError(t, exporter.Close())
+	assert.Equal(t, clientTrace, serverTrace)
+
+	upload, ok := spans.byName("test.upload")
+	require.True(t, ok)
+	client, ok := spans.byName("grpc.client./test.Service/Method")
+	require.True(t, ok)
+	handler, ok := spans.byName("grpc.server./test.Service/Method")
+	require.True(t, ok)
+
+	assert.Equal(t, upload.TraceID, client.TraceID)
+	assert.Equal(t, upload.TraceID, handler.TraceID)
+	assert.Equal(t, upload.SpanID, client.ParentSpanID)
+	assert.Equal(t, client.SpanID, handler.ParentSpanID)
+	assert.Equal(t, 3, client.Kind)
+	assert.Equal(t, 2, handler.Kind)
+}
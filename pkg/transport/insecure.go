@@ -31,3 +31,20 @@ func DialAddressInsecure(ctx context.Context, address string, opts ...grpc.DialO
 	}
 	return conn, Error.Wrap(err)
 }
+
+// WithToken returns a dial option sending token as a bearer token with every
+// request, for the private servers requiring a token.
+func WithToken(token string) grpc.DialOption {
+	return grpc.WithPerRPCCredentials(tokenCredentials(token))
+}
+
+// tokenCredentials sends a bearer token, also over insecure connections.
+type tokenCredentials string
+
+// GetRequestMetadata returns the authorization metadata.
+func (token tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(token)}, nil
+}
+
+// RequireTransportSecurity returns false, since the private servers are reached without tls.
+func (token tokenCredentials) RequireTransportSecurity() bool { return false }
@@ -0,0 +1,22 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package transport
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// CombineUnaryInterceptors combines two unary interceptors into one, since
+// a connection only supports a single unary interceptor. a is called first.
+func CombineUnaryInterceptors(a, b grpc.UnaryClientInterceptor) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{},
+		cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return a(ctx, method, req, reply, cc, func(actx context.Context, method string, req, reply interface{},
+			cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return b(actx, method, req, reply, cc, invoker, opts...)
+		}, opts...)
+	}
+}
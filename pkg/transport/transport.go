@@ -12,6 +12,7 @@ import (
 	"storj.io/storj/pkg/identity"
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/peertls/tlsopts"
+	"storj.io/storj/pkg/tracing"
 )
 
 // Observer implements the ConnSuccess and ConnFailure methods
@@ -73,7 +74,8 @@ func (transport *Transport) DialNode(ctx context.Context, node *pb.Node, opts ..
 		dialOption,
 		grpc.WithBlock(),
 		grpc.FailOnNonTempDialError(true),
-		grpc.WithUnaryInterceptor(InvokeTimeout{transport.requestTimeout}.Intercept),
+		grpc.WithUnaryInterceptor(CombineUnaryInterceptors(tracing.UnaryClientInterceptor, InvokeTimeout{transport.requestTimeout}.Intercept)),
+		grpc.WithStreamInterceptor(tracing.StreamClientInterceptor),
 	}, opts...)
 
 	timedCtx, cancel := context.WithTimeout(ctx, defaultDialTimeout)
@@ -105,7 +107,8 @@ func (transport *Transport) DialAddress(ctx context.Context, address string, opt
 		transport.tlsOpts.DialUnverifiedIDOption(),
 		grpc.WithBlock(),
 		grpc.FailOnNonTempDialError(true),
-		grpc.WithUnaryInterceptor(InvokeTimeout{transport.requestTimeout}.Intercept),
+		grpc.WithUnaryInterceptor(CombineUnaryInterceptors(tracing.UnaryClientInterceptor, InvokeTimeout{transport.requestTimeout}.Intercept)),
+		grpc.WithStreamInterceptor(tracing.StreamClientInterceptor),
 	}, opts...)
 
 	timedCtx, cancel := context.WithTimeout(ctx, defaultDialTimeout)
@@ -0,0 +1,80 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package disqualification evaluates the storage nodes against the
+// disqualification policies, and keeps a record of the disqualifications and
+// reinstatements of the nodes.
+package disqualification
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeebo/errs"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/pkg/storj"
+)
+
+var (
+	// Error is the default error class for disqualification.
+	Error = errs.Class("disqualification")
+	// ErrNotDisqualified is returned when reinstating a node that isn't disqualified.
+	ErrNotDisqualified = errs.Class("node not disqualified")
+	mon                = monkit.Package()
+)
+
+// Reason is why a node was disqualified or reinstated.
+type Reason string
+
+const (
+	// ReasonAuditScore is a node whose audit score dropped below the threshold.
+	ReasonAuditScore = Reason("audit score")
+	// ReasonUptimeScore is a node whose uptime score dropped below the threshold.
+	ReasonUptimeScore = Reason("uptime score")
+	// ReasonContainedFailures is a contained node that failed too many reverifications in a row.
+	ReasonContainedFailures = Reason("contained failures")
+	// ReasonOffline is a node that couldn't be reached for too long.
+	ReasonOffline = Reason("offline")
+	// ReasonReinstated is a node that was reinstated by an admin.
+	ReasonReinstated = Reason("reinstated")
+)
+
+// Event is a disqualification or a reinstatement of a node.
+type Event struct {
+	NodeID    storj.NodeID
+	Reason    Reason
+	Detail    string
+	CreatedAt time.Time
+}
+
+// Observer is notified when a node is disqualified or reinstated.
+type Observer interface {
+	NodeDisqualificationChanged(nodeID storj.NodeID)
+}
+
+// DB stores the disqualifications of the nodes and finds the nodes violating
+// the policies.
+type DB interface {
+	// Disqualify marks the node as disqualified and records the event, it
+	// returns false when the node already was disqualified.
+	Disqualify(ctx context.Context, nodeID storj.NodeID, reason Reason, detail string) (disqualified bool, err error)
+	// Reinstate clears the disqualification of the node, resets its
+	// reputation and records the event.
+	Reinstate(ctx context.Context, nodeID storj.NodeID, detail string) error
+	// Events returns the most recent events of the node, newest first.
+	Events(ctx context.Context, nodeID storj.NodeID, limit int) ([]Event, error)
+	// Disqualified returns the latest event of every disqualified node, the
+	// most recently disqualified first.
+	Disqualified(ctx context.Context) ([]Event, error)
+
+	// LowAuditScores returns the nodes that aren't disqualified and whose
+	// audit score is below threshold.
+	LowAuditScores(ctx context.Context, threshold float64) (storj.NodeIDList, error)
+	// ReverifyFailures returns the number of reverifications every node
+	// failed in a row since its last successful one.
+	ReverifyFailures(ctx context.Context) (map[storj.NodeID]int, error)
+	// OfflineSince returns the nodes that aren't disqualified and haven't
+	// been reached since before.
+	OfflineSince(ctx context.Context, before time.Time) (storj.NodeIDList, error)
+}
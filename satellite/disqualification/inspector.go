@@ -0,0 +1,98 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package disqualification
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/pb"
+)
+
+// defaultEventLimit is the number of events returned when the request doesn't set a limit.
+const defaultEventLimit = 100
+
+// Inspector is a gRPC service for reviewing the disqualified nodes and reinstating them
+type Inspector struct {
+	service *Service
+}
+
+// NewInspector creates an Inspector
+func NewInspector(service *Service) *Inspector {
+	return &Inspector{service: service}
+}
+
+// Disqualified returns the latest event of every disqualified node
+func (srv *Inspector) Disqualified(ctx context.Context, req *pb.DisqualifiedRequest) (_ *pb.DisqualifiedResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	events, err := srv.service.Disqualified(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	response := &pb.DisqualifiedResponse{}
+	response.Events, err = convertEvents(events)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return response, nil
+}
+
+// DisqualificationEvents returns the most recent disqualifications and reinstatements of a node
+func (srv *Inspector) DisqualificationEvents(ctx context.Context, req *pb.DisqualificationEventsRequest) (_ *pb.DisqualificationEventsResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultEventLimit
+	}
+
+	events, err := srv.service.Events(ctx, req.NodeId, limit)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	response := &pb.DisqualificationEventsResponse{}
+	response.Events, err = convertEvents(events)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return response, nil
+}
+
+// Reinstate clears the disqualification of a node
+func (srv *Inspector) Reinstate(ctx context.Context, req *pb.ReinstateRequest) (_ *pb.ReinstateResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = srv.service.Reinstate(ctx, req.NodeId, req.Detail)
+	if ErrNotDisqualified.Has(err) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.ReinstateResponse{}, nil
+}
+
+// convertEvents converts the events to their protobuf form
+func convertEvents(events []Event) ([]*pb.DisqualificationEvent, error) {
+	var converted []*pb.DisqualificationEvent
+	for _, event := range events {
+		createdAt, err := ptypes.TimestampProto(event.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		converted = append(converted, &pb.DisqualificationEvent{
+			NodeId:    event.NodeID,
+			Reason:    string(event.Reason),
+			Detail:    event.Detail,
+			CreatedAt: createdAt,
+		})
+	}
+	return converted, nil
+}
@@ -0,0 +1,169 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package disqualification
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/internal/sync2"
+	"storj.io/storj/pkg/storj"
+)
+
+// Config contains configurable values for the disqualification policies.
+type Config struct {
+	Interval          time.Duration `help:"how frequently the nodes are evaluated against the disqualification policies" default:"1h"`
+	ContainedFailures int           `help:"the number of reverifications a contained node may fail in a row before it is disqualified, 0 disables the policy" default:"6"`
+	OfflineDuration   time.Duration `help:"how long a node may be offline before it is disqualified, 0 disables the policy" default:"720h"`
+}
+
+// Service disqualifies every interval the nodes that violate one of the
+// policies.
+type Service struct {
+	log        *zap.Logger
+	config     Config
+	auditScore float64
+	db         DB
+
+	observers []Observer
+
+	Loop sync2.Cycle
+}
+
+// NewService creates a disqualification service, the observers are notified
+// of the disqualifications and reinstatements. The nodes whose audit score is
+// below auditScore, the audit DQ of the reputation, are disqualified; 0
+// disables the policy.
+func NewService(log *zap.Logger, db DB, config Config, auditScore float64, observers ...Observer) *Service {
+	return &Service{
+		log:        log,
+		config:     config,
+		auditScore: auditScore,
+		db:         db,
+
+		observers: observers,
+
+		Loop: *sync2.NewCycle(config.Interval),
+	}
+}
+
+// Run evaluates the policies on every interval.
+func (service *Service) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return service.Loop.Run(ctx, func(ctx context.Context) error {
+		err := service.Evaluate(ctx)
+		if err != nil {
+			service.log.Error("evaluating the disqualification policies failed", zap.Error(err))
+		}
+		return nil
+	})
+}
+
+// Close stops the service.
+func (service *Service) Close() error {
+	service.Loop.Close()
+	return nil
+}
+
+// Evaluate disqualifies the nodes that violate one of the policies.
+func (service *Service) Evaluate(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if service.auditScore > 0 {
+		nodeIDs, err := service.db.LowAuditScores(ctx, service.auditScore)
+		if err != nil {
+			return err
+		}
+		detail := fmt.Sprintf("audit score below %v", service.auditScore)
+		for _, nodeID := range nodeIDs {
+			if err := service.disqualify(ctx, nodeID, ReasonAuditScore, detail); err != nil {
+				return err
+			}
+		}
+	}
+
+	if service.config.ContainedFailures > 0 {
+		failures, err := service.db.ReverifyFailures(ctx)
+		if err != nil {
+			return err
+		}
+		for nodeID, count := range failures {
+			if count < service.config.ContainedFailures {
+				continue
+			}
+			detail := fmt.Sprintf("%d reverifications failed in a row", count)
+			if err := service.disqualify(ctx, nodeID, ReasonContainedFailures, detail); err != nil {
+				return err
+			}
+		}
+	}
+
+	if service.config.OfflineDuration > 0 {
+		nodeIDs, err := service.db.OfflineSince(ctx, time.Now().Add(-service.config.OfflineDuration))
+		if err != nil {
+			return err
+		}
+		detail := fmt.Sprintf("offline for more than %s", service.config.OfflineDuration)
+		for _, nodeID := range nodeIDs {
+			if err := service.disqualify(ctx, nodeID, ReasonOffline, detail); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// disqualify disqualifies the node, unless it already was.
+func (service *Service) disqualify(ctx context.Context, nodeID storj.NodeID, reason Reason, detail string) error {
+	disqualified, err := service.db.Disqualify(ctx, nodeID, reason, detail)
+	if err != nil {
+		return err
+	}
+	if disqualified {
+		mon.Meter("disqualified_" + string(reason)).Mark(1)
+		service.log.Info("disqualified node",
+			zap.Stringer("node", nodeID),
+			zap.String("reason", string(reason)),
+			zap.String("detail", detail))
+		service.notify(nodeID)
+	}
+	return nil
+}
+
+// Reinstate clears the disqualification of the node, detail tells why.
+func (service *Service) Reinstate(ctx context.Context, nodeID storj.NodeID, detail string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = service.db.Reinstate(ctx, nodeID, detail)
+	if err != nil {
+		return err
+	}
+	service.log.Info("reinstated node", zap.Stringer("node", nodeID), zap.String("detail", detail))
+	service.notify(nodeID)
+	return nil
+}
+
+// notify notifies the observers that the disqualification of the node changed.
+func (service *Service) notify(nodeID storj.NodeID) {
+	for _, observer := range service.observers {
+		observer.NodeDisqualificationChanged(nodeID)
+	}
+}
+
+// Events returns the most recent events of the node, newest first.
+func (service *Service) Events(ctx context.Context, nodeID storj.NodeID, limit int) (_ []Event, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return service.db.Events(ctx, nodeID, limit)
+}
+
+// Disqualified returns the latest event of every disqualified node.
+func (service *Service) Disqualified(ctx context.Context) (_ []Event, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return service.db.Disqualified(ctx)
+}
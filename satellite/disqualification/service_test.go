@@ -0,0 +1,158 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package disqualification_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/pkg/audit"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/disqualification"
+	"storj.io/storj/satellite/satellitedb/satellitedbtest"
+)
+
+func TestPolicies(t *testing.T) {
+	satellitedbtest.Run(t, func(t *testing.T, db satellite.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		// the overlay itself doesn't disqualify anyone
+		cache := overlay.NewCache(zap.NewNop(), db.OverlayCache(), overlay.NodeSelectionConfig{}, overlay.ReputationConfig{
+			AuditAlpha0: 2, AuditLambda: 1, AuditWeight: 1,
+			UptimeAlpha0: 1, UptimeLambda: 1, UptimeWeight: 1,
+		})
+
+		lowScore, contained, offline, good := storj.NodeID{1}, storj.NodeID{2}, storj.NodeID{3}, storj.NodeID{4}
+		for _, id := range []storj.NodeID{lowScore, contained, offline, good} {
+			require.NoError(t, cache.Put(ctx, id, pb.Node{
+				Id:           id,
+				Type:         pb.NodeType_STORAGE,
+				Address:      &pb.NodeAddress{Address: "127.0.0.1:0"},
+				Restrictions: &pb.NodeRestrictions{},
+				Reputation:   &pb.NodeStats{},
+			}))
+		}
+
+		// audit score 2/4
+		for i := 0; i < 2; i++ {
+			_, err := cache.UpdateStats(ctx, &overlay.UpdateRequest{NodeID: lowScore, AuditSuccess: false, IsUp: true})
+			require.NoError(t, err)
+		}
+
+		// three reverifications failed in a row by contained, the good node
+		// passed its latest one
+		require.NoError(t, db.AuditHistory().Insert(ctx, []audit.Record{
+			{NodeID: contained, Outcome: audit.OutcomeSuccess, Reverify: true},
+			{NodeID: good, Outcome: audit.OutcomeContained, Reverify: true},
+			{NodeID: contained, Outcome: audit.OutcomeContained, Reverify: true},
+			{NodeID: contained, Outcome: audit.OutcomeContained, Reverify: true},
+			{NodeID: good, Outcome: audit.OutcomeSuccess, Reverify: true},
+			{NodeID: contained, Outcome: audit.OutcomeFailure, Reverify: true},
+			{NodeID: good, Outcome: audit.OutcomeFailure, Reverify: false},
+		}))
+
+		_, err := cache.UpdateUptime(ctx, offline, false)
+		require.NoError(t, err)
+		_, err = cache.UpdateUptime(ctx, good, true)
+		require.NoError(t, err)
+
+		service := disqualification.NewService(zaptest.NewLogger(t), db.Disqualification(), disqualification.Config{
+			Interval:          time.Hour,
+			ContainedFailures: 3,
+			OfflineDuration:   time.Nanosecond,
+		}, 0.6)
+		require.NoError(t, service.Evaluate(ctx))
+		// evaluating again doesn't disqualify anyone twice
+		require.NoError(t, service.Evaluate(ctx))
+
+		reasons := map[storj.NodeID]disqualification.Reason{}
+		disqualified, err := service.Disqualified(ctx)
+		require.NoError(t, err)
+		for _, event := range disqualified {
+			reasons[event.NodeID] = event.Reason
+		}
+		assert.Equal(t, map[storj.NodeID]disqualification.Reason{
+			lowScore:  disqualification.ReasonAuditScore,
+			contained: disqualification.ReasonContainedFailures,
+			offline:   disqualification.ReasonOffline,
+		}, reasons)
+
+		for _, id := range []storj.NodeID{lowScore, contained, offline} {
+			stats, err := cache.GetStats(ctx, id)
+			require.NoError(t, err)
+			assert.NotNil(t, stats.Disqualified, id.String())
+
+			events, err := service.Events(ctx, id, 10)
+			require.NoError(t, err)
+			assert.Len(t, events, 1, id.String())
+		}
+
+		stats, err := cache.GetStats(ctx, good)
+		require.NoError(t, err)
+		assert.Nil(t, stats.Disqualified)
+	})
+}
+
+func TestReinstate(t *testing.T) {
+	satellitedbtest.Run(t, func(t *testing.T, db satellite.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		cache := overlay.NewCache(zap.NewNop(), db.OverlayCache(), overlay.NodeSelectionConfig{}, overlay.ReputationConfig{
+			AuditAlpha0: 1, AuditLambda: 1, AuditWeight: 1, AuditDQ: 0.6,
+			UptimeAlpha0: 1, UptimeLambda: 1, UptimeWeight: 1,
+		})
+
+		node := storj.NodeID{1}
+		require.NoError(t, cache.Put(ctx, node, pb.Node{
+			Id:           node,
+			Type:         pb.NodeType_STORAGE,
+			Address:      &pb.NodeAddress{Address: "127.0.0.1:0"},
+			Restrictions: &pb.NodeRestrictions{},
+			Reputation:   &pb.NodeStats{},
+		}))
+
+		service := disqualification.NewService(zaptest.NewLogger(t), db.Disqualification(), disqualification.Config{}, 0)
+
+		err := service.Reinstate(ctx, node, "not disqualified")
+		assert.True(t, disqualification.ErrNotDisqualified.Has(err))
+
+		// the overlay disqualifies the node on the failed audit and records why
+		stats, err := cache.UpdateStats(ctx, &overlay.UpdateRequest{NodeID: node, AuditSuccess: false, IsUp: true})
+		require.NoError(t, err)
+		require.NotNil(t, stats.Disqualified)
+
+		events, err := service.Events(ctx, node, 10)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, disqualification.ReasonAuditScore, events[0].Reason)
+
+		require.NoError(t, service.Reinstate(ctx, node, "appeal accepted"))
+
+		stats, err = cache.GetStats(ctx, node)
+		require.NoError(t, err)
+		assert.Nil(t, stats.Disqualified)
+		assert.Equal(t, overlay.Reputation{}, stats.AuditReputation)
+
+		events, err = service.Events(ctx, node, 10)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, disqualification.ReasonReinstated, events[0].Reason)
+		assert.Equal(t, "appeal accepted", events[0].Detail)
+
+		disqualified, err := service.Disqualified(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, disqualified)
+	})
+}
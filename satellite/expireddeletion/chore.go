@@ -0,0 +1,210 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package expireddeletion deletes the segments whose expiration date passed.
+package expireddeletion
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/internal/sync2"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/satellite/metainfo"
+	"storj.io/storj/storage"
+)
+
+var (
+	// Error is the default error class for expired deletion.
+	Error = errs.Class("expired deletion")
+	mon   = monkit.Package()
+)
+
+// Config contains configurable values for expired segment deletion.
+type Config struct {
+	Interval  time.Duration `help:"how frequently the expired segments are collected and deleted" default:"1h"`
+	Enabled   bool          `help:"whether the expired segments are deleted" default:"true"`
+	BatchSize int           `help:"number of expired segments deleted in a batch, between the batches the chore checks whether it should stop" default:"100"`
+}
+
+// Chore collects the expired segments with the metainfo loop, and deletes
+// them in batches once the loop is done, so that the deletions don't modify
+// the pointerdb while the loop iterates over it.
+type Chore struct {
+	log       *zap.Logger
+	config    Config
+	pointerdb *pointerdb.Service
+
+	metainfoLoop *metainfo.Loop
+
+	Loop sync2.Cycle
+}
+
+// NewChore creates a chore that deletes the expired segments on every interval.
+func NewChore(log *zap.Logger, pointerdb *pointerdb.Service, metainfoLoop *metainfo.Loop, config Config) *Chore {
+	return &Chore{
+		log:       log,
+		config:    config,
+		pointerdb: pointerdb,
+
+		metainfoLoop: metainfoLoop,
+
+		Loop: *sync2.NewCycle(config.Interval),
+	}
+}
+
+// Run deletes the expired segments on every interval, when enabled.
+func (chore *Chore) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return chore.Loop.Run(ctx, func(ctx context.Context) (err error) {
+		defer mon.Task()(&ctx)(&err)
+
+		if !chore.config.Enabled {
+			return nil
+		}
+
+		collector := &expiredCollector{now: time.Now()}
+		err = chore.metainfoLoop.Join(ctx, collector)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			chore.log.Error("error joining metainfoloop", zap.Error(err))
+			return nil
+		}
+		mon.IntVal("expired_segments_found").Observe(int64(len(collector.paths)))
+
+		err = chore.deleteExpired(ctx, collector.paths, collector.now)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			chore.log.Error("error deleting expired segments", zap.Error(err))
+		}
+		return nil
+	})
+}
+
+// Close stops the chore.
+func (chore *Chore) Close() error {
+	chore.Loop.Close()
+	return nil
+}
+
+// deleteExpired deletes the segments at paths that are still expired at now,
+// one batch at a time.
+func (chore *Chore) deleteExpired(ctx context.Context, paths []storj.Path, now time.Time) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	batchSize := chore.config.BatchSize
+	if batchSize <= 0 {
+		batchSize = len(paths)
+	}
+
+	for len(paths) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		batch := paths
+		if len(batch) > batchSize {
+			batch = batch[:batchSize]
+		}
+		paths = paths[len(batch):]
+
+		deleted := 0
+		for _, path := range batch {
+			ok, err := chore.deleteIfExpired(path, now)
+			if err != nil {
+				return err
+			}
+			if ok {
+				deleted++
+			}
+		}
+
+		mon.Meter("expired_segments_deleted").Mark(deleted)
+		chore.log.Debug("deleted expired segments", zap.Int("count", deleted))
+	}
+	return nil
+}
+
+// deleteIfExpired deletes the segment at path, unless it was deleted or
+// replaced by a segment that isn't expired since the loop saw it.
+func (chore *Chore) deleteIfExpired(path storj.Path, now time.Time) (deleted bool, err error) {
+	pointer, err := chore.pointerdb.Get(path)
+	if err != nil {
+		if storage.ErrKeyNotFound.Has(err) {
+			return false, nil
+		}
+		return false, Error.Wrap(err)
+	}
+
+	expired, err := isExpired(pointer, now)
+	if err != nil || !expired {
+		return false, err
+	}
+
+	err = chore.pointerdb.Delete(path)
+	if err != nil {
+		if storage.ErrKeyNotFound.Has(err) {
+			return false, nil
+		}
+		return false, Error.Wrap(err)
+	}
+	return true, nil
+}
+
+// expiredCollector collects the paths of the segments that expired before now.
+type expiredCollector struct {
+	now   time.Time
+	paths []storj.Path
+}
+
+// RemoteSegment collects the segment when it is expired.
+func (collector *expiredCollector) RemoteSegment(ctx context.Context, path storj.Path, pointer *pb.Pointer) error {
+	return collector.collect(path, pointer)
+}
+
+// RemoteObject returns nil, the last segment is already collected by RemoteSegment.
+func (collector *expiredCollector) RemoteObject(ctx context.Context, path storj.Path, pointer *pb.Pointer) error {
+	return nil
+}
+
+// InlineSegment collects the segment when it is expired.
+func (collector *expiredCollector) InlineSegment(ctx context.Context, path storj.Path, pointer *pb.Pointer) error {
+	return collector.collect(path, pointer)
+}
+
+func (collector *expiredCollector) collect(path storj.Path, pointer *pb.Pointer) error {
+	expired, err := isExpired(pointer, collector.now)
+	if err != nil {
+		return err
+	}
+	if expired {
+		collector.paths = append(collector.paths, path)
+	}
+	return nil
+}
+
+// isExpired returns whether the pointer has an expiration date before now.
+func isExpired(pointer *pb.Pointer, now time.Time) (bool, error) {
+	expiration := pointer.GetExpirationDate()
+	if expiration == nil {
+		return false, nil
+	}
+
+	t, err := ptypes.Timestamp(expiration)
+	if err != nil {
+		return false, Error.Wrap(err)
+	}
+	return t.Before(now), nil
+}
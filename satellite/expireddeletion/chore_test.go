@@ -0,0 +1,86 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package expireddeletion_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storage/meta"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/satellite"
+	"storj.io/storj/storage"
+)
+
+func TestDeleteExpired(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				// more expired segments than fit in a batch
+				config.ExpiredDeletion.BatchSize = 2
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		pointerdb := satellite.Metainfo.Service
+
+		expired := &timestamp.Timestamp{Seconds: time.Now().Add(-time.Hour).Unix()}
+		future := &timestamp.Timestamp{Seconds: time.Now().Add(time.Hour).Unix()}
+
+		var expiredPaths []storj.Path
+		for i := 0; i < 5; i++ {
+			path := "expired/remote" + strconv.Itoa(i)
+			require.NoError(t, pointerdb.Put(path, remotePointer(expired)))
+			expiredPaths = append(expiredPaths, path)
+		}
+		require.NoError(t, pointerdb.Put("expired/inline", &pb.Pointer{
+			Type:           pb.Pointer_INLINE,
+			InlineSegment:  []byte("data"),
+			ExpirationDate: expired,
+		}))
+		expiredPaths = append(expiredPaths, "expired/inline")
+
+		require.NoError(t, pointerdb.Put("kept/future", remotePointer(future)))
+		require.NoError(t, pointerdb.Put("kept/forever", remotePointer(nil)))
+
+		satellite.ExpiredDeletion.Chore.Loop.TriggerWait()
+
+		for _, path := range expiredPaths {
+			_, err := pointerdb.Get(path)
+			require.True(t, storage.ErrKeyNotFound.Has(err), path)
+		}
+
+		list, _, err := pointerdb.List("", "", "", true, 10, meta.None)
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+	})
+}
+
+func remotePointer(expiration *timestamp.Timestamp) *pb.Pointer {
+	return &pb.Pointer{
+		ExpirationDate: expiration,
+		Type:           pb.Pointer_REMOTE,
+		Remote: &pb.RemoteSegment{
+			Redundancy: &pb.RedundancyScheme{
+				Type:             pb.RedundancyScheme_RS,
+				MinReq:           1,
+				Total:            3,
+				RepairThreshold:  2,
+				SuccessThreshold: 3,
+				ErasureShareSize: 2,
+			},
+			RemotePieces: []*pb.RemotePiece{{PieceNum: 1, NodeId: storj.NodeID{1}}},
+		},
+		SegmentSize: 10,
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package gc sends the storage nodes bloom filters of the pieces they should
+// keep, so that they can garbage collect the other pieces.
+package gc
+
+import (
+	"context"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/internal/sync2"
+	"storj.io/storj/pkg/bloomfilter"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/transport"
+	"storj.io/storj/storage"
+)
+
+var (
+	// Error is the default error class for garbage collection.
+	Error = errs.Class("garbage collection")
+	mon   = monkit.Package()
+)
+
+// Config contains configurable values for garbage collection.
+type Config struct {
+	Interval          time.Duration `help:"how frequently the bloom filters of the pieces are sent to the storage nodes" default:"120h0m0s"`
+	Enabled           bool          `help:"whether the bloom filters of the pieces are sent to the storage nodes" default:"false"`
+	InitialPieces     int           `help:"minimum expected number of pieces per node, used to size the bloom filters" default:"400000"`
+	FalsePositiveRate float64       `help:"false positive rate of the bloom filters, the share of garbage pieces a node keeps" default:"0.1"`
+}
+
+// Service creates every interval a bloom filter of the pieces of each storage
+// node from the pointers and sends it to the node.
+type Service struct {
+	log    *zap.Logger
+	config Config
+
+	pointerdb *pointerdb.Service
+	overlay   *overlay.Cache
+	transport transport.Client
+
+	// pieceCounts are the piece counts of the nodes of the last run, used
+	// to size the bloom filters
+	pieceCounts map[storj.NodeID]int
+
+	Loop sync2.Cycle
+}
+
+// NewService creates a garbage collection service.
+func NewService(log *zap.Logger, pointerdb *pointerdb.Service, overlay *overlay.Cache, transport transport.Client, config Config) *Service {
+	return &Service{
+		log:    log,
+		config: config,
+
+		pointerdb: pointerdb,
+		overlay:   overlay,
+		transport: transport,
+
+		pieceCounts: map[storj.NodeID]int{},
+
+		Loop: *sync2.NewCycle(config.Interval),
+	}
+}
+
+// Run sends the bloom filters on every interval, when enabled.
+func (service *Service) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return service.Loop.Run(ctx, func(ctx context.Context) error {
+		if !service.config.Enabled {
+			return nil
+		}
+		if err := service.Collect(ctx); err != nil {
+			service.log.Error("garbage collection", zap.Error(err))
+		}
+		return nil
+	})
+}
+
+// Close stops the service.
+func (service *Service) Close() error {
+	service.Loop.Close()
+	return nil
+}
+
+// Collect creates the bloom filters of the pieces of the nodes and sends them.
+func (service *Service) Collect(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	// NB: pieces uploaded while iterating may be missing from the filters,
+	// the nodes keep the pieces created after the creation date
+	creationDate := time.Now()
+
+	filters, err := service.createFilters(ctx)
+	if err != nil {
+		return err
+	}
+
+	mon.IntVal("gc_nodes").Observe(int64(len(filters)))
+
+	for nodeID, filter := range filters {
+		if err := service.send(ctx, nodeID, creationDate, filter); err != nil {
+			service.log.Warn("sending bloom filter", zap.Stringer("Node ID", nodeID), zap.Error(err))
+			mon.Meter("gc_send_failed").Mark(1)
+			continue
+		}
+		mon.IntVal("gc_filter_size").Observe(int64(filter.Size()))
+	}
+
+	return nil
+}
+
+// createFilters adds the pieces of the remote segments to the bloom filters of their nodes.
+func (service *Service) createFilters(ctx context.Context) (_ map[storj.NodeID]*bloomfilter.Filter, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	filters := map[storj.NodeID]*bloomfilter.Filter{}
+	pieceCounts := map[storj.NodeID]int{}
+
+	err = service.pointerdb.IterateSnapshot("", "", true, false,
+		func(it storage.Iterator) error {
+			var item storage.ListItem
+			for it.Next(&item) {
+				pointer := &pb.Pointer{}
+				if err := proto.Unmarshal(item.Value, pointer); err != nil {
+					return Error.New("error unmarshalling pointer %s", err)
+				}
+
+				remote := pointer.GetRemote()
+				if remote == nil {
+					continue
+				}
+
+				for _, piece := range remote.GetRemotePieces() {
+					filter, ok := filters[piece.NodeId]
+					if !ok {
+						filter = bloomfilter.NewOptimal(service.expectedPieces(piece.NodeId), service.config.FalsePositiveRate)
+						filters[piece.NodeId] = filter
+					}
+					filter.Add(remote.RootPieceId.Derive(piece.NodeId))
+					pieceCounts[piece.NodeId]++
+				}
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	service.pieceCounts = pieceCounts
+	return filters, nil
+}
+
+// expectedPieces returns the expected piece count of the node.
+func (service *Service) expectedPieces(nodeID storj.NodeID) int {
+	count := service.pieceCounts[nodeID]
+	if count < service.config.InitialPieces {
+		return service.config.InitialPieces
+	}
+	// NB: leave room for the pieces uploaded since the last run
+	return count + count/10
+}
+
+// send sends the bloom filter to the node.
+func (service *Service) send(ctx context.Context, nodeID storj.NodeID, creationDate time.Time, filter *bloomfilter.Filter) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	node, err := service.overlay.Get(ctx, nodeID)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	conn, err := service.transport.DialNode(ctx, node)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, Error.Wrap(conn.Close())) }()
+
+	timestamp, err := ptypes.TimestampProto(creationDate)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	_, err = pb.NewPiecestoreClient(conn).Retain(ctx, &pb.RetainRequest{
+		CreationDate: timestamp,
+		Filter:       filter.Bytes(),
+	})
+	return Error.Wrap(err)
+}
@@ -0,0 +1,116 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gc_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"storj.io/storj/internal/memory"
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/internal/testrand"
+	"storj.io/storj/pkg/auth/signing"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/satellite"
+	"storj.io/storj/storage"
+	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/pieces"
+)
+
+func TestGarbageCollection(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 5, UplinkCount: 1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				// keep the garbage piece from being a false positive
+				config.GarbageCollection.FalsePositiveRate = 0.000001
+			},
+			StorageNode: func(index int, config *storagenode.Config) {
+				// check the uploaded pieces against the filters too
+				config.Storage2.Retain.MaxTimeSkew = 0
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		uplink := planet.Uplinks[0]
+
+		err := uplink.Upload(ctx, satellite, "testbucket", "test/path", testrand.New(t).Bytes(10*memory.KiB.Int()))
+		require.NoError(t, err)
+
+		// find a node with a piece of the upload, the pointer doesn't
+		// include the pieces of the long tail which was cut off
+		var remote *pb.RemoteSegment
+		err = satellite.Metainfo.Service.Iterate("", "", true, false, func(it storage.Iterator) error {
+			var item storage.ListItem
+			for it.Next(&item) {
+				pointer := &pb.Pointer{}
+				if err := proto.Unmarshal(item.Value, pointer); err != nil {
+					return err
+				}
+				if pointer.GetRemote() != nil {
+					remote = pointer.GetRemote()
+				}
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		require.NotNil(t, remote)
+
+		var node *storagenode.Peer
+		for _, storageNode := range planet.StorageNodes {
+			if storageNode.ID() == remote.GetRemotePieces()[0].NodeId {
+				node = storageNode
+			}
+		}
+		require.NotNil(t, node)
+
+		// add a piece which the satellite doesn't know about
+		garbageID := storj.NewPieceID()
+		pieceHash, err := signing.SignPieceHash(
+			signing.SignerFromFullIdentity(uplink.Identity),
+			&pb.PieceHash{PieceId: garbageID})
+		require.NoError(t, err)
+
+		writer, err := node.Storage2.Store.Writer(ctx, satellite.ID(), garbageID, -1)
+		require.NoError(t, err)
+		_, err = writer.Write([]byte{1, 2, 3})
+		require.NoError(t, err)
+		require.NoError(t, writer.Commit(&pb.PieceHeader{}))
+
+		require.NoError(t, node.DB.PieceInfo().Add(ctx, &pieces.Info{
+			SatelliteID:     satellite.ID(),
+			PieceID:         garbageID,
+			PieceSize:       3,
+			PieceCreation:   time.Now().Add(-24 * time.Hour),
+			UplinkPieceHash: pieceHash,
+			Uplink:          uplink.Identity.PeerIdentity(),
+		}))
+
+		usedBefore, err := node.DB.PieceInfo().SpaceUsed(ctx)
+		require.NoError(t, err)
+
+		require.NoError(t, satellite.GarbageCollection.Service.Collect(ctx))
+
+		// the node retains the pieces in the background
+		deadline := time.Now().Add(10 * time.Second)
+		for {
+			_, err := node.DB.PieceInfo().Get(ctx, satellite.ID(), garbageID)
+			if err != nil {
+				break
+			}
+			require.True(t, time.Now().Before(deadline), "garbage piece wasn't deleted")
+			time.Sleep(50 * time.Millisecond)
+		}
+
+		usedAfter, err := node.DB.PieceInfo().SpaceUsed(ctx)
+		require.NoError(t, err)
+		require.Equal(t, usedBefore-3, usedAfter)
+	})
+}
@@ -0,0 +1,232 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo
+
+import (
+	"context"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storage"
+)
+
+var (
+	// LoopError is a standard error class for this component.
+	LoopError = errs.Class("metainfo loop error")
+	// LoopClosedError is a loop closed error
+	LoopClosedError = LoopError.New("loop closed")
+)
+
+// Observer is an interface defining an observer that can subscribe to the metainfo loop.
+//
+// An observer that returns an error is removed from the current iteration,
+// and the error is returned from its Join.
+type Observer interface {
+	// RemoteSegment is called for every remote segment.
+	RemoteSegment(ctx context.Context, path storj.Path, pointer *pb.Pointer) error
+	// RemoteObject is called for the last segment of every remote object,
+	// in addition to RemoteSegment.
+	RemoteObject(ctx context.Context, path storj.Path, pointer *pb.Pointer) error
+	// InlineSegment is called for every inline segment.
+	InlineSegment(ctx context.Context, path storj.Path, pointer *pb.Pointer) error
+}
+
+type observerContext struct {
+	Observer
+	ctx  context.Context
+	done chan error
+}
+
+func (observer *observerContext) HandleError(err error) bool {
+	if err != nil {
+		observer.done <- err
+		close(observer.done)
+		return true
+	}
+	return false
+}
+
+func (observer *observerContext) Finish() {
+	close(observer.done)
+}
+
+func (observer *observerContext) Wait() error {
+	return <-observer.done
+}
+
+// LoopConfig contains configurable values for the metainfo loop.
+type LoopConfig struct {
+	CoalesceDuration time.Duration `help:"how long to wait for new observers before starting iteration" default:"5s"`
+}
+
+// Loop is a metainfo loop service.
+//
+// Loop iterates over all the segments in the pointerdb once for every group
+// of observers that joined together, so that services that have to see every
+// segment, such as audit and repair, share a single pass over the database.
+type Loop struct {
+	config    LoopConfig
+	pointerdb *pointerdb.Service
+	join      chan *observerContext
+	done      chan struct{}
+}
+
+// NewLoop creates a new metainfo loop service.
+func NewLoop(config LoopConfig, pointerdb *pointerdb.Service) *Loop {
+	return &Loop{
+		config:    config,
+		pointerdb: pointerdb,
+		join:      make(chan *observerContext),
+		done:      make(chan struct{}),
+	}
+}
+
+// Join will join the looper for a single cycle until completion or failure.
+//
+// Join blocks until the observer has seen every segment or ctx is canceled.
+func (loop *Loop) Join(ctx context.Context, observer Observer) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	obsContext := &observerContext{
+		Observer: observer,
+		ctx:      ctx,
+		done:     make(chan error),
+	}
+
+	select {
+	case loop.join <- obsContext:
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-loop.done:
+		return LoopClosedError
+	}
+
+	return obsContext.Wait()
+}
+
+// Run starts the looping service.
+func (loop *Loop) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	for {
+		err := loop.runOnce(ctx)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Close closes the looping services, any waiting Join returns LoopClosedError.
+func (loop *Loop) Close() error {
+	close(loop.done)
+	return nil
+}
+
+// runOnce goes through the pointerdb once, calling every observer that joined
+// before the iteration started.
+func (loop *Loop) runOnce(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var observers []*observerContext
+
+	// wait for the first observer
+	select {
+	case observer := <-loop.join:
+		observers = append(observers, observer)
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-loop.done:
+		return LoopClosedError
+	}
+
+	defer func() {
+		for _, observer := range observers {
+			if !observer.HandleError(err) {
+				observer.Finish()
+			}
+		}
+	}()
+
+	// give the other observers a chance to join the same iteration
+	timer := time.NewTimer(loop.config.CoalesceDuration)
+	defer timer.Stop()
+
+waitformore:
+	for {
+		select {
+		case observer := <-loop.join:
+			observers = append(observers, observer)
+		case <-timer.C:
+			break waitformore
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-loop.done:
+			return LoopClosedError
+		}
+	}
+
+	return loop.pointerdb.Iterate("", "", true, false, func(it storage.Iterator) error {
+		var item storage.ListItem
+		for it.Next(&item) {
+			pointer := &pb.Pointer{}
+			if err := proto.Unmarshal(item.Value, pointer); err != nil {
+				return LoopError.New("unexpected error unmarshalling pointer %s: %v", item.Key, err)
+			}
+
+			path := storj.Path(item.Key.String())
+			pathElements := storj.SplitPath(path)
+			isLastSegment := len(pathElements) >= 2 && pathElements[1] == "l"
+
+			nextObservers := observers[:0]
+			for _, observer := range observers {
+				if handlePointer(observer, path, isLastSegment, pointer) {
+					nextObservers = append(nextObservers, observer)
+				}
+			}
+			observers = nextObservers
+			if len(observers) == 0 {
+				return nil
+			}
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+		}
+		return nil
+	})
+}
+
+// handlePointer calls the observer for the pointer and returns whether the
+// observer should keep receiving pointers. An observer that is dropped has
+// already been sent its error.
+func handlePointer(observer *observerContext, path storj.Path, isLastSegment bool, pointer *pb.Pointer) bool {
+	ctx := observer.ctx
+	select {
+	case <-ctx.Done():
+		observer.HandleError(ctx.Err())
+		return false
+	default:
+	}
+
+	if remote := pointer.GetRemote(); remote != nil {
+		if observer.HandleError(observer.RemoteSegment(ctx, path, pointer)) {
+			return false
+		}
+		if isLastSegment {
+			if observer.HandleError(observer.RemoteObject(ctx, path, pointer)) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return !observer.HandleError(observer.InlineSegment(ctx, path, pointer))
+}
@@ -0,0 +1,77 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"storj.io/storj/internal/memory"
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/internal/testrand"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+)
+
+// TestLoop checks that observers joining together see every segment in the
+// same iteration.
+func TestLoop(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 5, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		uplink := planet.Uplinks[0]
+		satellite := planet.Satellites[0]
+		random := testrand.New(t)
+
+		for i := 0; i < 5; i++ {
+			data := random.Bytes(10 * memory.KiB.Int())
+			require.NoError(t, uplink.Upload(ctx, satellite, "bucket", "remote"+strconv.Itoa(i), data))
+		}
+		require.NoError(t, uplink.Upload(ctx, satellite, "bucket", "inline", random.Bytes(1*memory.KiB.Int())))
+
+		observers := []*countObserver{{}, {}}
+		var group errgroup.Group
+		for _, observer := range observers {
+			observer := observer
+			group.Go(func() error {
+				return satellite.Metainfo.Loop.Join(ctx, observer)
+			})
+		}
+		require.NoError(t, group.Wait())
+
+		for _, observer := range observers {
+			require.Equal(t, 5, observer.remoteSegments)
+			require.Equal(t, 5, observer.remoteObjects)
+			// the bucket is stored as an inline pointer too
+			require.True(t, observer.inlineSegments >= 1)
+		}
+		require.Equal(t, observers[0], observers[1])
+	})
+}
+
+type countObserver struct {
+	remoteSegments int
+	remoteObjects  int
+	inlineSegments int
+}
+
+func (observer *countObserver) RemoteSegment(ctx context.Context, path storj.Path, pointer *pb.Pointer) error {
+	observer.remoteSegments++
+	return nil
+}
+
+func (observer *countObserver) RemoteObject(ctx context.Context, path storj.Path, pointer *pb.Pointer) error {
+	observer.remoteObjects++
+	return nil
+}
+
+func (observer *countObserver) InlineSegment(ctx context.Context, path storj.Path, pointer *pb.Pointer) error {
+	observer.inlineSegments++
+	return nil
+}
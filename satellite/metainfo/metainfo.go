@@ -40,22 +40,24 @@ type APIKeys interface {
 
 // Endpoint metainfo endpoint
 type Endpoint struct {
-	log       *zap.Logger
-	pointerdb *pointerdb.Service
-	orders    *orders.Service
-	cache     *overlay.Cache
-	apiKeys   APIKeys
+	log               *zap.Logger
+	pointerdb         *pointerdb.Service
+	orders            *orders.Service
+	cache             *overlay.Cache
+	apiKeys           APIKeys
+	maxLongTailMargin int
 }
 
 // NewEndpoint creates new metainfo endpoint instance
-func NewEndpoint(log *zap.Logger, pointerdb *pointerdb.Service, orders *orders.Service, cache *overlay.Cache, apiKeys APIKeys) *Endpoint {
+func NewEndpoint(log *zap.Logger, pointerdb *pointerdb.Service, orders *orders.Service, cache *overlay.Cache, apiKeys APIKeys, maxLongTailMargin int) *Endpoint {
 	// TODO do something with too many params
 	return &Endpoint{
-		log:       log,
-		pointerdb: pointerdb,
-		orders:    orders,
-		cache:     cache,
-		apiKeys:   apiKeys,
+		log:               log,
+		pointerdb:         pointerdb,
+		orders:            orders,
+		cache:             cache,
+		apiKeys:           apiKeys,
+		maxLongTailMargin: maxLongTailMargin,
 	}
 }
 
@@ -131,8 +133,15 @@ func (endpoint *Endpoint) CreateSegment(ctx context.Context, req *pb.SegmentWrit
 
 	maxPieceSize := eestream.CalcPieceSize(req.GetMaxEncryptedSegmentSize(), redundancy)
 
+	// the nodes beyond the maximum long-tail margin aren't selected, the
+	// uplink gets fewer order limits than the total of the redundancy scheme
+	requestedCount := redundancy.TotalCount()
+	if endpoint.maxLongTailMargin > 0 && requestedCount-redundancy.OptimalThreshold() > endpoint.maxLongTailMargin {
+		requestedCount = redundancy.OptimalThreshold() + endpoint.maxLongTailMargin
+	}
+
 	request := overlay.FindStorageNodesRequest{
-		RequestedCount: int(req.Redundancy.Total),
+		RequestedCount: requestedCount,
 		FreeBandwidth:  maxPieceSize,
 		FreeDisk:       maxPieceSize,
 	}
@@ -406,11 +415,15 @@ func (endpoint *Endpoint) validateCommit(req *pb.SegmentCommitRequest) error {
 	if req.Pointer.Type == pb.Pointer_REMOTE {
 		remote := req.Pointer.Remote
 
-		if int32(len(req.OriginalLimits)) != remote.Redundancy.Total {
+		// there are fewer limits than the total when the long-tail margin was lowered
+		if int32(len(req.OriginalLimits)) > remote.Redundancy.Total {
 			return Error.New("invalid no order limit for piece")
 		}
 
 		for _, piece := range remote.RemotePieces {
+			if piece.PieceNum < 0 || int(piece.PieceNum) >= len(req.OriginalLimits) {
+				return Error.New("invalid no order limit for piece")
+			}
 			limit := req.OriginalLimits[piece.PieceNum]
 
 			err := endpoint.orders.VerifyOrderLimitSignature(limit)
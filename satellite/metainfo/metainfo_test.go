@@ -16,12 +16,17 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/zeebo/errs"
+	"go.uber.org/zap"
 
+	"storj.io/storj/internal/memory"
 	"storj.io/storj/internal/testcontext"
 	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/internal/testrand"
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/storj"
+	"storj.io/storj/satellite"
 	"storj.io/storj/satellite/console"
+	"storj.io/storj/uplink"
 )
 
 // mockAPIKeys is mock for api keys store of pointerdb
@@ -158,3 +163,44 @@ func TestServiceList(t *testing.T) {
 		require.Equal(t, item.IsPrefix, list.Items[i].IsPrefix)
 	}
 }
+
+func TestCreateSegmentLongTailMargin(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 6, UplinkCount: 1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.PointerDB.MaxLongTailMargin = 1
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		client, err := planet.Uplinks[0].DialMetainfo(ctx, satellite, planet.Uplinks[0].APIKey[satellite.ID()])
+		require.NoError(t, err)
+
+		// the uplink declares a margin of 3, the satellite selects one node beyond the success threshold
+		limits, _, err := client.CreateSegment(ctx, "testbucket", "testpath", 0, &pb.RedundancyScheme{
+			Type:             pb.RedundancyScheme_RS,
+			MinReq:           1,
+			RepairThreshold:  2,
+			SuccessThreshold: 3,
+			Total:            6,
+			ErasureShareSize: 256,
+		}, 1024, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.Len(t, limits, 4)
+
+		// the uplink fills up the missing limits
+		expectedData := testrand.New(t).Bytes(10 * memory.KiB.Int())
+		err = planet.Uplinks[0].UploadWithConfig(ctx, satellite, &uplink.RSConfig{
+			MinThreshold:     1,
+			RepairThreshold:  2,
+			SuccessThreshold: 3,
+			MaxThreshold:     6,
+		}, "testbucket", "testpath", expectedData)
+		require.NoError(t, err)
+
+		data, err := planet.Uplinks[0].Download(ctx, satellite, "testbucket", "testpath")
+		require.NoError(t, err)
+		assert.Equal(t, expectedData, data)
+	})
+}
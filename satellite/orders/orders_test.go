@@ -11,6 +11,7 @@ import (
 	"storj.io/storj/internal/memory"
 	"storj.io/storj/internal/testcontext"
 	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/storagenode/orders"
 )
 
 func TestOrders(t *testing.T) {
@@ -52,7 +53,7 @@ func TestOrders(t *testing.T) {
 		require.NoError(t, err)
 		sumUnsent += len(infos)
 
-		archivedInfos, err := storageNode.DB.Orders().ListArchived(ctx, sumBeforeSend)
+		archivedInfos, _, err := storageNode.DB.Orders().ListArchived(ctx, orders.ArchiveCursor{}, sumBeforeSend)
 		require.NoError(t, err)
 		sumArchived += len(archivedInfos)
 	}
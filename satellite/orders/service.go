@@ -294,10 +294,30 @@ func (service *Service) CreateAuditOrderLimits(ctx context.Context, auditor *ide
 		return nil, err
 	}
 
+	var nodeIDs storj.NodeIDList
+	for _, piece := range pointer.GetRemote().GetRemotePieces() {
+		nodeIDs = append(nodeIDs, piece.NodeId)
+	}
+
+	outdatedNodes, err := service.cache.FindOutdatedNodes(ctx, nodeIDs)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	outdated := make(map[storj.NodeID]bool, len(outdatedNodes))
+	for _, id := range outdatedNodes {
+		outdated[id] = true
+	}
+
 	var combinedErrs error
 	var limitsCount int32
 	limits := make([]*pb.AddressedOrderLimit, totalPieces)
 	for _, piece := range pointer.GetRemote().GetRemotePieces() {
+		if outdated[piece.NodeId] {
+			service.log.Debug("node version is below minimum", zap.String("ID", piece.NodeId.String()))
+			combinedErrs = errs.Combine(combinedErrs, Error.New("node version is below minimum: %s", piece.NodeId.String()))
+			continue
+		}
+
 		node, err := service.cache.Get(ctx, piece.NodeId)
 		if err != nil {
 			service.log.Error("error getting node from the overlay cache", zap.Error(err))
@@ -350,14 +370,70 @@ func (service *Service) CreateAuditOrderLimits(ctx context.Context, auditor *ide
 	return limits, nil
 }
 
+// CreateAuditOrderLimit creates the order limit for downloading a single share of
+// the piece of nodeID, such as the share that a contained node has to deliver.
+func (service *Service) CreateAuditOrderLimit(ctx context.Context, auditor *identity.PeerIdentity, bucketID []byte, nodeID storj.NodeID, rootPieceID storj.PieceID, shareSize int32) (_ *pb.AddressedOrderLimit, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	// convert orderExpiration from duration to timestamp
+	orderExpirationTime := time.Now().Add(service.orderExpiration)
+	orderExpiration, err := ptypes.TimestampProto(orderExpirationTime)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	serialNumber, err := service.createSerial(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := service.cache.Get(ctx, nodeID)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	if node == nil || !node.IsUp {
+		return nil, overlay.ErrNodeOffline.New("%s", nodeID)
+	}
+	node.Type.DPanicOnInvalid("order service audit order limit")
+
+	orderLimit, err := signing.SignOrderLimit(service.satellite, &pb.OrderLimit2{
+		SerialNumber:    serialNumber,
+		SatelliteId:     service.satellite.ID(),
+		UplinkId:        auditor.ID,
+		StorageNodeId:   nodeID,
+		PieceId:         rootPieceID.Derive(nodeID),
+		Action:          pb.PieceAction_GET_AUDIT,
+		Limit:           int64(shareSize),
+		OrderExpiration: orderExpiration,
+	})
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	err = service.saveSerial(ctx, serialNumber, bucketID, orderExpirationTime)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	return &pb.AddressedOrderLimit{
+		Limit:              orderLimit,
+		StorageNodeAddress: node.Address,
+	}, nil
+}
+
 // CreateGetRepairOrderLimits creates the order limits for downloading the healthy pieces of pointer as the source for repair.
 func (service *Service) CreateGetRepairOrderLimits(ctx context.Context, repairer *identity.PeerIdentity, bucketID []byte, pointer *pb.Pointer, healthy []*pb.RemotePiece) (_ []*pb.AddressedOrderLimit, err error) {
 	rootPieceID := pointer.GetRemote().RootPieceId
 	redundancy := pointer.GetRemote().GetRedundancy()
-	shareSize := redundancy.GetErasureShareSize()
 	totalPieces := redundancy.GetTotal()
 	expiration := pointer.ExpirationDate
 
+	pieceSize, err := calcRepairPieceSize(pointer)
+	if err != nil {
+		return nil, err
+	}
+
 	// convert orderExpiration from duration to timestamp
 	orderExpirationTime := time.Now().Add(service.orderExpiration)
 	orderExpiration, err := ptypes.TimestampProto(orderExpirationTime)
@@ -398,7 +474,7 @@ func (service *Service) CreateGetRepairOrderLimits(ctx context.Context, repairer
 			StorageNodeId:   piece.NodeId,
 			PieceId:         rootPieceID.Derive(piece.NodeId),
 			Action:          pb.PieceAction_GET_REPAIR,
-			Limit:           int64(shareSize),
+			Limit:           pieceSize,
 			PieceExpiration: expiration,
 			OrderExpiration: orderExpiration,
 		})
@@ -410,6 +486,7 @@ func (service *Service) CreateGetRepairOrderLimits(ctx context.Context, repairer
 			Limit:              orderLimit,
 			StorageNodeAddress: node.Address,
 		}
+		limitsCount++
 	}
 
 	if limitsCount < redundancy.GetMinReq() {
@@ -426,12 +503,17 @@ func (service *Service) CreateGetRepairOrderLimits(ctx context.Context, repairer
 }
 
 // CreatePutRepairOrderLimits creates the order limits for uploading the repaired pieces of pointer to newNodes.
-func (service *Service) CreatePutRepairOrderLimits(ctx context.Context, repairer *identity.PeerIdentity, bucketID []byte, pointer *pb.Pointer, getOrderLimits []*pb.AddressedOrderLimit, newNodes []*pb.Node) (_ []*pb.AddressedOrderLimit, err error) {
+// The repaired pieces take the piece numbers that none of healthyPieces has.
+func (service *Service) CreatePutRepairOrderLimits(ctx context.Context, repairer *identity.PeerIdentity, bucketID []byte, pointer *pb.Pointer, healthyPieces []*pb.RemotePiece, newNodes []*pb.Node) (_ []*pb.AddressedOrderLimit, err error) {
 	rootPieceID := pointer.GetRemote().RootPieceId
-	shareSize := pointer.GetRemote().GetRedundancy().GetErasureShareSize()
 	totalPieces := pointer.GetRemote().GetRedundancy().GetTotal()
 	expiration := pointer.ExpirationDate
 
+	pieceSize, err := calcRepairPieceSize(pointer)
+	if err != nil {
+		return nil, err
+	}
+
 	// convert orderExpiration from duration to timestamp
 	orderExpirationTime := time.Now().Add(service.orderExpiration)
 	orderExpiration, err := ptypes.TimestampProto(orderExpirationTime)
@@ -444,6 +526,11 @@ func (service *Service) CreatePutRepairOrderLimits(ctx context.Context, repairer
 		return nil, err
 	}
 
+	healthy := make(map[int32]bool, len(healthyPieces))
+	for _, piece := range healthyPieces {
+		healthy[piece.GetPieceNum()] = true
+	}
+
 	limits := make([]*pb.AddressedOrderLimit, totalPieces)
 	var pieceNum int32
 	for _, node := range newNodes {
@@ -451,7 +538,7 @@ func (service *Service) CreatePutRepairOrderLimits(ctx context.Context, repairer
 			node.Type.DPanicOnInvalid("order service put repair order limits")
 		}
 
-		for pieceNum < totalPieces && getOrderLimits[pieceNum] != nil {
+		for pieceNum < totalPieces && healthy[pieceNum] {
 			pieceNum++
 		}
 
@@ -466,7 +553,7 @@ func (service *Service) CreatePutRepairOrderLimits(ctx context.Context, repairer
 			StorageNodeId:   node.Id,
 			PieceId:         rootPieceID.Derive(node.Id),
 			Action:          pb.PieceAction_PUT_REPAIR,
-			Limit:           int64(shareSize),
+			Limit:           pieceSize,
 			PieceExpiration: expiration,
 			OrderExpiration: orderExpiration,
 		})
@@ -488,3 +575,13 @@ func (service *Service) CreatePutRepairOrderLimits(ctx context.Context, repairer
 
 	return limits, nil
 }
+
+// calcRepairPieceSize returns the size of the pieces of pointer, which the
+// order limits of a repair allow to transfer.
+func calcRepairPieceSize(pointer *pb.Pointer) (int64, error) {
+	redundancy, err := eestream.NewRedundancyStrategyFromProto(pointer.GetRemote().GetRedundancy())
+	if err != nil {
+		return 0, Error.Wrap(err)
+	}
+	return eestream.CalcPieceSize(pointer.GetSegmentSize(), redundancy), nil
+}
@@ -21,7 +21,9 @@ import (
 
 	"storj.io/storj/internal/post"
 	"storj.io/storj/internal/post/oauth2"
+	"storj.io/storj/internal/version"
 	"storj.io/storj/pkg/accounting"
+	"storj.io/storj/pkg/accounting/payments"
 	"storj.io/storj/pkg/accounting/rollup"
 	"storj.io/storj/pkg/accounting/tally"
 	"storj.io/storj/pkg/audit"
@@ -40,12 +42,16 @@ import (
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/peertls/tlsopts"
 	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/pkg/revocation"
 	"storj.io/storj/pkg/server"
 	"storj.io/storj/pkg/storj"
 	"storj.io/storj/pkg/transport"
 	"storj.io/storj/satellite/console"
 	"storj.io/storj/satellite/console/consoleauth"
 	"storj.io/storj/satellite/console/consoleweb"
+	"storj.io/storj/satellite/disqualification"
+	"storj.io/storj/satellite/expireddeletion"
+	"storj.io/storj/satellite/gc"
 	"storj.io/storj/satellite/mailservice"
 	"storj.io/storj/satellite/mailservice/simulate"
 	"storj.io/storj/satellite/metainfo"
@@ -79,6 +85,18 @@ type DB interface {
 	RepairQueue() queue.RepairQueue
 	// Irreparable returns database for failed repairs
 	Irreparable() irreparable.DB
+	// Containment returns database for the pending audits of contained nodes
+	Containment() audit.Containment
+	// AuditQueue returns database for the segments waiting to be audited
+	AuditQueue() audit.QueueDB
+	// AuditHistory returns database for the audit records
+	AuditHistory() audit.HistoryDB
+	// AuditDryRun returns database for the audit records of the dry run
+	AuditDryRun() audit.HistoryDB
+	// AuditStats returns database for the daily aggregates of the audits
+	AuditStats() audit.StatsDB
+	// Disqualification returns database for the disqualifications of the nodes
+	Disqualification() disqualification.DB
 	// Console returns database for satellite console
 	Console() console.DB
 	// Orders returns database for orders
@@ -96,18 +114,28 @@ type Config struct {
 	Overlay   overlay.Config
 	Discovery discovery.Config
 
-	PointerDB   pointerdb.Config
-	BwAgreement bwagreement.Config // TODO: decide whether to keep empty configs for consistency
+	PointerDB    pointerdb.Config
+	MetainfoLoop metainfo.LoopConfig
+	BwAgreement  bwagreement.Config // TODO: decide whether to keep empty configs for consistency
 
 	Checker  checker.Config
 	Repairer repairer.Config
 	Audit    audit.Config
 
-	Tally  tally.Config
-	Rollup rollup.Config
+	GarbageCollection gc.Config
+	Disqualification  disqualification.Config
+	ExpiredDeletion   expireddeletion.Config
+
+	Bolt boltdb.ChoreConfig
+
+	Tally    tally.Config
+	Rollup   rollup.Config
+	Payments payments.Config
 
 	Mail    mailservice.Config
 	Console consoleweb.Config
+
+	Version version.Config
 }
 
 // Peer is the satellite
@@ -121,6 +149,8 @@ type Peer struct {
 
 	Server *server.Server
 
+	Version *version.Service
+
 	// services and endpoints
 	Kademlia struct {
 		kdb, ndb storage.KeyValueStore // TODO: move these into DB
@@ -132,8 +162,9 @@ type Peer struct {
 	}
 
 	Overlay struct {
-		Service   *overlay.Cache
-		Inspector *overlay.Inspector
+		Service    *overlay.Cache
+		Inspector  *overlay.Inspector
+		Reputation *overlay.ReputationEndpoint
 	}
 
 	Discovery struct {
@@ -144,29 +175,56 @@ type Peer struct {
 		Database  storage.KeyValueStore // TODO: move into pointerDB
 		Service   *pointerdb.Service
 		Endpoint2 *metainfo.Endpoint
+		Loop      *metainfo.Loop
 	}
 
 	Agreements struct {
 		Endpoint *bwagreement.Server
 	}
 
+	Revocations struct {
+		Endpoint *revocation.Endpoint
+	}
+
 	Orders struct {
 		Endpoint *orders.Endpoint
 		Service  *orders.Service
 	}
 
 	Repair struct {
-		Checker   *checker.Checker
-		Repairer  *repairer.Service
-		Inspector *irreparable.Inspector
+		ReliabilityCache *overlay.ReliabilityCache
+		Checker          *checker.Checker
+		Repairer         *repairer.Service
+		Inspector        *irreparable.Inspector
+		HealthInspector  *checker.Inspector
 	}
 	Audit struct {
-		Service *audit.Service
+		Service   *audit.Service
+		Chore     *audit.Chore
+		Inspector *audit.Inspector
+	}
+
+	GarbageCollection struct {
+		Service *gc.Service
+	}
+
+	Disqualification struct {
+		Service   *disqualification.Service
+		Inspector *disqualification.Inspector
+	}
+
+	ExpiredDeletion struct {
+		Chore *expireddeletion.Chore
+	}
+
+	Bolt struct {
+		Chore *boltdb.Chore
 	}
 
 	Accounting struct {
-		Tally  *tally.Tally
-		Rollup *rollup.Rollup
+		Tally    *tally.Tally
+		Rollup   *rollup.Rollup
+		Payments *payments.Endpoint
 	}
 
 	Mail struct {
@@ -204,6 +262,16 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, config *Config) (*
 		if err != nil {
 			return nil, errs.Combine(err, peer.Close())
 		}
+
+		if options.RevDB != nil {
+			peer.Revocations.Endpoint = revocation.NewEndpoint(peer.Log.Named("revocations"), options.RevDB)
+			pb.RegisterRevocationsServer(peer.Server.GRPC(), peer.Revocations.Endpoint)
+		}
+	}
+
+	{ // setup version control
+		log.Debug("Starting version check")
+		peer.Version = version.NewService(peer.Log.Named("version"), config.Version, version.Build, "satellite", peer.Identity.ID)
 	}
 
 	{ // setup overlay
@@ -217,13 +285,23 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, config *Config) (*
 			AuditCount:            config.Node.AuditCount,
 			NewNodeAuditThreshold: config.Node.NewNodeAuditThreshold,
 			NewNodePercentage:     config.Node.NewNodePercentage,
+			MinimumVersion:        config.Node.MinimumVersion,
+
+			SelectionCacheStaleness: config.Node.SelectionCacheStaleness,
+
+			DistinctIP:     config.Node.DistinctIP,
+			SubnetMaskIPv4: config.Node.SubnetMaskIPv4,
+			SubnetMaskIPv6: config.Node.SubnetMaskIPv6,
 		}
 
-		peer.Overlay.Service = overlay.NewCache(peer.Log.Named("overlay"), peer.DB.OverlayCache(), nodeSelectionConfig)
+		peer.Overlay.Service = overlay.NewCache(peer.Log.Named("overlay"), peer.DB.OverlayCache(), nodeSelectionConfig, config.Reputation)
 		peer.Transport = peer.Transport.WithObservers(peer.Overlay.Service)
 
 		peer.Overlay.Inspector = overlay.NewInspector(peer.Overlay.Service)
 		pb.RegisterOverlayInspectorServer(peer.Server.PrivateGRPC(), peer.Overlay.Inspector)
+
+		peer.Overlay.Reputation = overlay.NewReputationEndpoint(peer.Log.Named("overlay:reputation"), peer.Overlay.Service)
+		pb.RegisterReputationServer(peer.Server.GRPC(), peer.Overlay.Reputation)
 	}
 
 	{ // setup kademlia
@@ -317,15 +395,21 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, config *Config) (*
 		peer.Metainfo.Database = storelogger.New(peer.Log.Named("pdb"), db)
 		peer.Metainfo.Service = pointerdb.NewService(peer.Log.Named("pointerdb"), peer.Metainfo.Database)
 
+		// the routing table and the standalone pointer database are bolt databases
+		peer.Bolt.Chore = boltdb.NewChore(peer.Log.Named("bolt"), config.Bolt, peer.Kademlia.kdb, db)
+
 		peer.Metainfo.Endpoint2 = metainfo.NewEndpoint(
 			peer.Log.Named("metainfo:endpoint"),
 			peer.Metainfo.Service,
 			peer.Orders.Service,
 			peer.Overlay.Service,
 			peer.DB.Console().APIKeys(),
+			config.PointerDB.MaxLongTailMargin,
 		)
 
 		pb.RegisterMetainfoServer(peer.Server.GRPC(), peer.Metainfo.Endpoint2)
+
+		peer.Metainfo.Loop = metainfo.NewLoop(config.MetainfoLoop, peer.Metainfo.Service)
 	}
 
 	{ // setup agreements
@@ -337,16 +421,25 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, config *Config) (*
 
 	{ // setup datarepair
 		log.Debug("Setting up datarepair")
+		repairOverrides, err := checker.ParseRepairOverrides(config.Checker.RepairOverrides)
+		if err != nil {
+			return nil, errs.Combine(err, peer.Close())
+		}
+
+		peer.Repair.ReliabilityCache = overlay.NewReliabilityCache(peer.Overlay.Service, config.Checker.ReliabilityCacheStaleness)
+
 		// TODO: simplify argument list somehow
 		peer.Repair.Checker = checker.NewChecker(
+			peer.Metainfo.Loop,
 			peer.Metainfo.Service,
 			peer.DB.RepairQueue(),
-			peer.Overlay.Service, peer.DB.Irreparable(),
+			peer.Overlay.Service, peer.Repair.ReliabilityCache, peer.DB.Irreparable(),
 			0, peer.Log.Named("checker"),
-			config.Checker.Interval)
+			config.Checker.Interval, repairOverrides)
 
 		peer.Repair.Repairer = repairer.NewService(
 			peer.DB.RepairQueue(),
+			peer.DB.Irreparable(),
 			&config.Repairer,
 			config.Repairer.Interval,
 			config.Repairer.MaxRepair,
@@ -354,10 +447,35 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, config *Config) (*
 			peer.Metainfo.Service,
 			peer.Orders.Service,
 			peer.Overlay.Service,
+			peer.Repair.ReliabilityCache,
 		)
 
 		peer.Repair.Inspector = irreparable.NewInspector(peer.DB.Irreparable())
 		pb.RegisterIrreparableInspectorServer(peer.Server.PrivateGRPC(), peer.Repair.Inspector)
+
+		peer.Repair.HealthInspector = checker.NewInspector(peer.Repair.Checker)
+		pb.RegisterHealthInspectorServer(peer.Server.PrivateGRPC(), peer.Repair.HealthInspector)
+	}
+
+	{ // setup garbage collection
+		log.Debug("Setting up garbage collection")
+		peer.GarbageCollection.Service = gc.NewService(
+			peer.Log.Named("garbage collection"),
+			peer.Metainfo.Service,
+			peer.Overlay.Service,
+			peer.Transport,
+			config.GarbageCollection,
+		)
+	}
+
+	{ // setup expired segment deletion
+		log.Debug("Setting up expired segment deletion")
+		peer.ExpiredDeletion.Chore = expireddeletion.NewChore(
+			peer.Log.Named("expired deletion"),
+			peer.Metainfo.Service,
+			peer.Metainfo.Loop,
+			config.ExpiredDeletion,
+		)
 	}
 
 	{ // setup audit
@@ -370,17 +488,51 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, config *Config) (*
 			peer.Orders.Service,
 			peer.Transport,
 			peer.Overlay.Service,
+			peer.DB.Containment(),
+			peer.DB.AuditQueue(),
+			peer.DB.AuditHistory(),
+			peer.DB.AuditDryRun(),
+			peer.DB.AuditStats(),
 			peer.Identity,
 		)
 		if err != nil {
 			return nil, errs.Combine(err, peer.Close())
 		}
+
+		peer.Audit.Chore = audit.NewChore(peer.Log.Named("audit:chore"),
+			peer.Audit.Service.Cursor,
+			peer.DB.AuditHistory(),
+			peer.DB.AuditStats(),
+			peer.Overlay.Service,
+			peer.Metainfo.Loop,
+			config,
+		)
+
+		peer.Audit.Inspector = audit.NewInspector(peer.DB.AuditHistory(), peer.DB.AuditDryRun(), peer.DB.AuditStats(), config.ReportWarningScore)
+		pb.RegisterAuditInspectorServer(peer.Server.PrivateGRPC(), peer.Audit.Inspector)
+	}
+
+	{ // setup disqualification
+		log.Debug("Setting up disqualification")
+		peer.Disqualification.Service = disqualification.NewService(
+			peer.Log.Named("disqualification"),
+			peer.DB.Disqualification(),
+			config.Disqualification,
+			config.Overlay.Reputation.AuditDQ,
+			peer.Repair.ReliabilityCache,
+		)
+
+		peer.Disqualification.Inspector = disqualification.NewInspector(peer.Disqualification.Service)
+		pb.RegisterDisqualificationInspectorServer(peer.Server.PrivateGRPC(), peer.Disqualification.Inspector)
 	}
 
 	{ // setup accounting
 		log.Debug("Setting up accounting")
 		peer.Accounting.Tally = tally.New(peer.Log.Named("tally"), peer.DB.Accounting(), peer.DB.BandwidthAgreement(), peer.Metainfo.Service, peer.Overlay.Service, 0, config.Tally.Interval)
 		peer.Accounting.Rollup = rollup.New(peer.Log.Named("rollup"), peer.DB.Accounting(), config.Rollup.Interval)
+
+		peer.Accounting.Payments = payments.NewEndpoint(peer.Log.Named("payments"), peer.DB.Accounting(), config.Payments)
+		pb.RegisterPayoutsServer(peer.Server.GRPC(), peer.Accounting.Payments)
 	}
 
 	{ // setup mailservice
@@ -495,6 +647,9 @@ func ignoreCancel(err error) error {
 func (peer *Peer) Run(ctx context.Context) error {
 	group, ctx := errgroup.WithContext(ctx)
 
+	group.Go(func() error {
+		return ignoreCancel(peer.Version.Run(ctx))
+	})
 	group.Go(func() error {
 		return ignoreCancel(peer.Kademlia.Service.Bootstrap(ctx))
 	})
@@ -516,9 +671,27 @@ func (peer *Peer) Run(ctx context.Context) error {
 	group.Go(func() error {
 		return ignoreCancel(peer.Accounting.Rollup.Run(ctx))
 	})
+	group.Go(func() error {
+		return ignoreCancel(peer.Metainfo.Loop.Run(ctx))
+	})
 	group.Go(func() error {
 		return ignoreCancel(peer.Audit.Service.Run(ctx))
 	})
+	group.Go(func() error {
+		return ignoreCancel(peer.Audit.Chore.Run(ctx))
+	})
+	group.Go(func() error {
+		return ignoreCancel(peer.GarbageCollection.Service.Run(ctx))
+	})
+	group.Go(func() error {
+		return ignoreCancel(peer.Disqualification.Service.Run(ctx))
+	})
+	group.Go(func() error {
+		return ignoreCancel(peer.Bolt.Chore.Run(ctx))
+	})
+	group.Go(func() error {
+		return ignoreCancel(peer.ExpiredDeletion.Chore.Run(ctx))
+	})
 	group.Go(func() error {
 		// TODO: move the message into Server instead
 		// Don't change the format of this comment, it is used to figure out the node id.
@@ -554,6 +727,15 @@ func (peer *Peer) Close() error {
 	}
 
 	// close services in reverse initialization order
+	if peer.Bolt.Chore != nil {
+		errlist.Add(peer.Bolt.Chore.Close())
+	}
+	if peer.Disqualification.Service != nil {
+		errlist.Add(peer.Disqualification.Service.Close())
+	}
+	if peer.GarbageCollection.Service != nil {
+		errlist.Add(peer.GarbageCollection.Service.Close())
+	}
 	if peer.Repair.Repairer != nil {
 		errlist.Add(peer.Repair.Repairer.Close())
 	}
@@ -561,10 +743,20 @@ func (peer *Peer) Close() error {
 		errlist.Add(peer.Repair.Checker.Close())
 	}
 
+	if peer.Audit.Chore != nil {
+		errlist.Add(peer.Audit.Chore.Close())
+	}
+	if peer.ExpiredDeletion.Chore != nil {
+		errlist.Add(peer.ExpiredDeletion.Chore.Close())
+	}
+
 	if peer.Agreements.Endpoint != nil {
 		errlist.Add(peer.Agreements.Endpoint.Close())
 	}
 
+	if peer.Metainfo.Loop != nil {
+		errlist.Add(peer.Metainfo.Loop.Close())
+	}
 	if peer.Metainfo.Database != nil {
 		errlist.Add(peer.Metainfo.Database.Close())
 	}
@@ -593,6 +785,52 @@ func (peer *Peer) Close() error {
 	return errlist.Err()
 }
 
+// Reload applies the subset of config that can change while the satellite is
+// running: the chore intervals and the discovery refresh limit. Nothing is
+// changed when config is invalid.
+//
+// Reload waits for the affected chores to finish their current iteration.
+func (peer *Peer) Reload(ctx context.Context, config *Config) error {
+	intervals := []struct {
+		name     string
+		interval time.Duration
+	}{
+		{"discovery.refresh-interval", config.Discovery.RefreshInterval},
+		{"discovery.graveyard-interval", config.Discovery.GraveyardInterval},
+		{"discovery.discovery-interval", config.Discovery.DiscoveryInterval},
+		{"checker.interval", config.Checker.Interval},
+		{"audit.interval", config.Audit.Interval},
+		{"audit.chore-interval", config.Audit.ChoreInterval},
+		{"garbage-collection.interval", config.GarbageCollection.Interval},
+		{"disqualification.interval", config.Disqualification.Interval},
+		{"expired-deletion.interval", config.ExpiredDeletion.Interval},
+		{"bolt.interval", config.Bolt.Interval},
+	}
+	for _, chore := range intervals {
+		if chore.interval <= 0 {
+			return errs.New("%s must be positive, got %v", chore.name, chore.interval)
+		}
+	}
+	if config.Discovery.RefreshLimit <= 0 {
+		return errs.New("discovery.refresh-limit must be positive, got %d", config.Discovery.RefreshLimit)
+	}
+
+	peer.Discovery.Service.Refresh.ChangeInterval(config.Discovery.RefreshInterval)
+	peer.Discovery.Service.Graveyard.ChangeInterval(config.Discovery.GraveyardInterval)
+	peer.Discovery.Service.Discovery.ChangeInterval(config.Discovery.DiscoveryInterval)
+	peer.Discovery.Service.SetRefreshLimit(config.Discovery.RefreshLimit)
+	peer.Repair.Checker.Loop.ChangeInterval(config.Checker.Interval)
+	peer.Audit.Service.Loop.ChangeInterval(config.Audit.Interval)
+	peer.Audit.Chore.Loop.ChangeInterval(config.Audit.ChoreInterval)
+	peer.GarbageCollection.Service.Loop.ChangeInterval(config.GarbageCollection.Interval)
+	peer.Disqualification.Service.Loop.ChangeInterval(config.Disqualification.Interval)
+	peer.Bolt.Chore.Loop.ChangeInterval(config.Bolt.Interval)
+	peer.ExpiredDeletion.Chore.Loop.ChangeInterval(config.ExpiredDeletion.Interval)
+
+	peer.Log.Info("configuration reloaded")
+	return nil
+}
+
 // ID returns the peer ID.
 func (peer *Peer) ID() storj.NodeID { return peer.Identity.ID }
 
@@ -204,6 +204,33 @@ func (db *accountingDB) QueryPaymentInfo(ctx context.Context, start time.Time, e
 	return csv, nil
 }
 
+// QueryNodePaymentInfo queries Overlay, Accounting Rollup of a single node, it returns nil when the node doesn't exist
+func (db *accountingDB) QueryNodePaymentInfo(ctx context.Context, nodeID storj.NodeID, start time.Time, end time.Time) (*accounting.CSVRow, error) {
+	var sqlStmt = `SELECT n.created_at, n.audit_success_ratio,
+		COALESCE(SUM(r.at_rest_total), 0), COALESCE(SUM(r.get_repair_total), 0),
+		COALESCE(SUM(r.put_repair_total), 0), COALESCE(SUM(r.get_audit_total), 0),
+		COALESCE(SUM(r.put_total), 0), COALESCE(SUM(r.get_total), 0), n.wallet
+		FROM nodes n
+		LEFT JOIN accounting_rollups r ON r.node_id = n.id AND r.start_time >= ? AND r.start_time < ?
+		WHERE n.id = ?
+		GROUP BY n.id, n.created_at, n.audit_success_ratio, n.wallet`
+	r := &accounting.CSVRow{NodeID: nodeID}
+	var wallet sql.NullString
+	err := db.db.DB.QueryRowContext(ctx, db.db.Rebind(sqlStmt), start.UTC(), end.UTC(), nodeID.Bytes()).Scan(
+		&r.NodeCreationDate, &r.AuditSuccessRatio, &r.AtRestTotal, &r.GetRepairTotal,
+		&r.PutRepairTotal, &r.GetAuditTotal, &r.PutTotal, &r.GetTotal, &wallet)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if wallet.Valid {
+		r.Wallet = wallet.String
+	}
+	return r, nil
+}
+
 // DeleteRawBefore deletes all raw tallies prior to some time
 func (db *accountingDB) DeleteRawBefore(ctx context.Context, latestRollup time.Time) error {
 	var deleteRawSQL = `DELETE FROM accounting_raws WHERE interval_end_time < ?`
@@ -0,0 +1,107 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package satellitedb
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/audit"
+	"storj.io/storj/pkg/storj"
+	dbx "storj.io/storj/satellite/satellitedb/dbx"
+)
+
+// auditHistory stores the audit records in table, which is either
+// audit_history or audit_dry_run_history.
+type auditHistory struct {
+	db    *dbx.DB
+	table string
+}
+
+// Insert stores the records, setting their creation time.
+func (history *auditHistory) Insert(ctx context.Context, records []audit.Record) error {
+	now := time.Now().UTC()
+	return Error.Wrap(history.db.WithTx(ctx, func(ctx context.Context, tx *dbx.Tx) (err error) {
+		insert, err := tx.Tx.PrepareContext(ctx, history.db.Rebind(`
+			INSERT INTO `+history.table+` (
+				segment_path, stripe_index, node_id, outcome, reverify, created_at
+			) VALUES (?, ?, ?, ?, ?, ?)`))
+		if err != nil {
+			return err
+		}
+		defer func() { err = errs.Combine(err, insert.Close()) }()
+
+		for _, record := range records {
+			_, err = insert.ExecContext(ctx,
+				[]byte(record.SegmentPath), record.StripeIndex, record.NodeID.Bytes(),
+				int(record.Outcome), record.Reverify, now,
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+}
+
+// ByNode returns the most recent records of the node, newest first.
+func (history *auditHistory) ByNode(ctx context.Context, nodeID storj.NodeID, limit int) ([]audit.Record, error) {
+	rows, err := history.db.DB.QueryContext(ctx, history.db.Rebind(`
+		SELECT segment_path, stripe_index, node_id, outcome, reverify, created_at
+		FROM `+history.table+` WHERE node_id = ?
+		ORDER BY created_at DESC, id DESC LIMIT ?`), nodeID.Bytes(), limit)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return scanAuditRecords(rows)
+}
+
+// BySegment returns the most recent records of the segment, newest first.
+func (history *auditHistory) BySegment(ctx context.Context, path storj.Path, limit int) ([]audit.Record, error) {
+	rows, err := history.db.DB.QueryContext(ctx, history.db.Rebind(`
+		SELECT segment_path, stripe_index, node_id, outcome, reverify, created_at
+		FROM `+history.table+` WHERE segment_path = ?
+		ORDER BY created_at DESC, id DESC LIMIT ?`), []byte(path), limit)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return scanAuditRecords(rows)
+}
+
+// DeleteBefore removes the records created before the given time.
+func (history *auditHistory) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := history.db.DB.ExecContext(ctx, history.db.Rebind(`
+		DELETE FROM `+history.table+` WHERE created_at < ?`), before.UTC())
+	if err != nil {
+		return 0, Error.Wrap(err)
+	}
+	deleted, err := result.RowsAffected()
+	return deleted, Error.Wrap(err)
+}
+
+func scanAuditRecords(rows *sql.Rows) (records []audit.Record, err error) {
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	for rows.Next() {
+		var record audit.Record
+		var path, nodeID []byte
+		var outcome int
+		err := rows.Scan(&path, &record.StripeIndex, &nodeID, &outcome, &record.Reverify, &record.CreatedAt)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+
+		record.SegmentPath = storj.Path(path)
+		record.Outcome = audit.Outcome(outcome)
+		record.NodeID, err = storj.NodeIDFromBytes(nodeID)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		records = append(records, record)
+	}
+	return records, Error.Wrap(rows.Err())
+}
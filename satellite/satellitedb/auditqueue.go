@@ -0,0 +1,67 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package satellitedb
+
+import (
+	"context"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/storj"
+	dbx "storj.io/storj/satellite/satellitedb/dbx"
+)
+
+type auditQueue struct {
+	db *dbx.DB
+}
+
+// Swap replaces the segments waiting to be audited with paths.
+func (queue *auditQueue) Swap(ctx context.Context, paths []storj.Path) error {
+	return Error.Wrap(queue.db.WithTx(ctx, func(ctx context.Context, tx *dbx.Tx) (err error) {
+		_, err = tx.Tx.ExecContext(ctx, `DELETE FROM audit_queue`)
+		if err != nil {
+			return err
+		}
+
+		insert, err := tx.Tx.PrepareContext(ctx, queue.db.Rebind(`
+			INSERT INTO audit_queue (path, position) VALUES (?, ?)`))
+		if err != nil {
+			return err
+		}
+		defer func() { err = errs.Combine(err, insert.Close()) }()
+
+		for position, path := range paths {
+			_, err = insert.ExecContext(ctx, []byte(path), position)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+}
+
+// Remove removes the segment from the queue.
+func (queue *auditQueue) Remove(ctx context.Context, path storj.Path) error {
+	_, err := queue.db.DB.ExecContext(ctx, queue.db.Rebind(`
+		DELETE FROM audit_queue WHERE path = ?`), []byte(path))
+	return Error.Wrap(err)
+}
+
+// All returns the segments waiting to be audited, in order.
+func (queue *auditQueue) All(ctx context.Context) (paths []storj.Path, err error) {
+	rows, err := queue.db.DB.QueryContext(ctx, `SELECT path FROM audit_queue ORDER BY position`)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	for rows.Next() {
+		var path []byte
+		if err := rows.Scan(&path); err != nil {
+			return nil, Error.Wrap(err)
+		}
+		paths = append(paths, storj.Path(path))
+	}
+	return paths, Error.Wrap(rows.Err())
+}
@@ -0,0 +1,126 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package satellitedb
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/audit"
+	dbx "storj.io/storj/satellite/satellitedb/dbx"
+)
+
+type auditStats struct {
+	db *dbx.DB
+}
+
+// RecordSegment adds an audited segment and the outcomes of its nodes to the day of now.
+func (stats *auditStats) RecordSegment(ctx context.Context, now time.Time, outcomes map[audit.Outcome]int64) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	day := startOfDay(now)
+	return Error.Wrap(stats.db.WithTx(ctx, func(ctx context.Context, tx *dbx.Tx) error {
+		_, err := tx.Tx.ExecContext(ctx, stats.db.Rebind(`
+			INSERT INTO audit_daily_coverage (
+				interval_start, segments_audited, total_segments
+			) VALUES (?, 1, 0)
+			ON CONFLICT ( interval_start )
+			DO UPDATE SET segments_audited = audit_daily_coverage.segments_audited + 1`), day)
+		if err != nil {
+			return err
+		}
+
+		for outcome, count := range outcomes {
+			_, err := tx.Tx.ExecContext(ctx, stats.db.Rebind(`
+				INSERT INTO audit_daily_outcomes (
+					interval_start, outcome, count
+				) VALUES (?, ?, ?)
+				ON CONFLICT ( interval_start, outcome )
+				DO UPDATE SET count = audit_daily_outcomes.count + excluded.count`),
+				day, int(outcome), count)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+}
+
+// RecordTotalSegments stores the number of remote segments seen on the day of now.
+func (stats *auditStats) RecordTotalSegments(ctx context.Context, now time.Time, total int64) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = stats.db.DB.ExecContext(ctx, stats.db.Rebind(`
+		INSERT INTO audit_daily_coverage (
+			interval_start, segments_audited, total_segments
+		) VALUES (?, 0, ?)
+		ON CONFLICT ( interval_start )
+		DO UPDATE SET total_segments = excluded.total_segments`), startOfDay(now), total)
+	return Error.Wrap(err)
+}
+
+// Daily returns the stats of the days since the given time, oldest first.
+func (stats *auditStats) Daily(ctx context.Context, since time.Time) (days []audit.DailyStats, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := stats.db.DB.QueryContext(ctx, stats.db.Rebind(`
+		SELECT interval_start, segments_audited, total_segments
+		FROM audit_daily_coverage WHERE interval_start >= ?
+		ORDER BY interval_start`), since.UTC())
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	index := make(map[time.Time]int)
+	for rows.Next() {
+		day := audit.DailyStats{Outcomes: make(map[audit.Outcome]int64)}
+		if err := rows.Scan(&day.IntervalStart, &day.SegmentsAudited, &day.TotalSegments); err != nil {
+			return nil, Error.Wrap(err)
+		}
+		day.IntervalStart = day.IntervalStart.UTC()
+		index[day.IntervalStart] = len(days)
+		days = append(days, day)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	outcomes, err := stats.db.DB.QueryContext(ctx, stats.db.Rebind(`
+		SELECT interval_start, outcome, count
+		FROM audit_daily_outcomes WHERE interval_start >= ?`), since.UTC())
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, outcomes.Close()) }()
+
+	for outcomes.Next() {
+		var intervalStart time.Time
+		var outcome int
+		var count int64
+		if err := outcomes.Scan(&intervalStart, &outcome, &count); err != nil {
+			return nil, Error.Wrap(err)
+		}
+		// every audited segment also counts in the coverage of its day
+		if i, ok := index[intervalStart.UTC()]; ok {
+			days[i].Outcomes[audit.Outcome(outcome)] = count
+		}
+	}
+	return days, Error.Wrap(outcomes.Err())
+}
+
+// NodesBelow returns the nodes that aren't disqualified and whose audit
+// score is below score.
+func (stats *auditStats) NodesBelow(ctx context.Context, score float64) (_ []audit.NodeScore, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return lowAuditScores(ctx, stats.db, score)
+}
+
+// startOfDay returns the start of the UTC day of t.
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,92 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package satellitedb
+
+import (
+	"context"
+	"database/sql"
+
+	"storj.io/storj/pkg/audit"
+	"storj.io/storj/pkg/storj"
+	dbx "storj.io/storj/satellite/satellitedb/dbx"
+)
+
+type containment struct {
+	db *dbx.DB
+}
+
+// Get returns the pending audit of the node.
+func (containment *containment) Get(ctx context.Context, nodeID storj.NodeID) (*audit.PendingAudit, error) {
+	pending, err := getPendingAudit(ctx, containment.db.DB, containment.db.Rebind, nodeID)
+	if err == sql.ErrNoRows {
+		return nil, audit.ErrContainedNotFound.New("%v", nodeID)
+	}
+	return pending, Error.Wrap(err)
+}
+
+// IncrementPending creates the pending audit of the node, or increments the
+// reverify count of the pending audit the node already has.
+func (containment *containment) IncrementPending(ctx context.Context, pendingAudit *audit.PendingAudit) error {
+	return Error.Wrap(containment.db.WithTx(ctx, func(ctx context.Context, tx *dbx.Tx) error {
+		_, err := getPendingAudit(ctx, tx.Tx, containment.db.Rebind, pendingAudit.NodeID)
+		switch {
+		case err == sql.ErrNoRows:
+			_, err = tx.Tx.ExecContext(ctx, containment.db.Rebind(`
+				INSERT INTO pending_audits (
+					node_id, piece_id, stripe_index, share_size, expected_share_hash, reverify_count, path
+				) VALUES (?, ?, ?, ?, ?, ?, ?)`),
+				pendingAudit.NodeID.Bytes(), pendingAudit.PieceID.Bytes(), pendingAudit.StripeIndex,
+				pendingAudit.ShareSize, pendingAudit.ExpectedShareHash, pendingAudit.ReverifyCount,
+				[]byte(pendingAudit.Path),
+			)
+			return err
+		case err != nil:
+			return err
+		}
+
+		// the node has to deliver the share it was contained for first
+		_, err = tx.Tx.ExecContext(ctx, containment.db.Rebind(`
+			UPDATE pending_audits SET reverify_count = reverify_count + 1 WHERE node_id = ?`),
+			pendingAudit.NodeID.Bytes(),
+		)
+		return err
+	}))
+}
+
+// Delete removes the node from containment, it returns whether the node was
+// contained.
+func (containment *containment) Delete(ctx context.Context, nodeID storj.NodeID) (bool, error) {
+	result, err := containment.db.DB.ExecContext(ctx, containment.db.Rebind(`
+		DELETE FROM pending_audits WHERE node_id = ?`), nodeID.Bytes())
+	if err != nil {
+		return false, Error.Wrap(err)
+	}
+	deleted, err := result.RowsAffected()
+	return deleted > 0, Error.Wrap(err)
+}
+
+// queryer is implemented by both the database and its transactions.
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func getPendingAudit(ctx context.Context, db queryer, rebind func(string) string, nodeID storj.NodeID) (*audit.PendingAudit, error) {
+	var pending audit.PendingAudit
+	var pieceID, path []byte
+	err := db.QueryRowContext(ctx, rebind(`
+		SELECT piece_id, stripe_index, share_size, expected_share_hash, reverify_count, path
+		FROM pending_audits WHERE node_id = ?`), nodeID.Bytes(),
+	).Scan(&pieceID, &pending.StripeIndex, &pending.ShareSize, &pending.ExpectedShareHash, &pending.ReverifyCount, &path)
+	if err != nil {
+		return nil, err
+	}
+
+	pending.NodeID = nodeID
+	pending.Path = storj.Path(path)
+	pending.PieceID, err = storj.PieceIDFromBytes(pieceID)
+	if err != nil {
+		return nil, err
+	}
+	return &pending, nil
+}
@@ -10,6 +10,7 @@ import (
 	"storj.io/storj/internal/dbutil"
 	"storj.io/storj/internal/dbutil/pgutil"
 	"storj.io/storj/pkg/accounting"
+	"storj.io/storj/pkg/audit"
 	"storj.io/storj/pkg/bwagreement"
 	"storj.io/storj/pkg/certdb"
 	"storj.io/storj/pkg/datarepair/irreparable"
@@ -17,6 +18,7 @@ import (
 	"storj.io/storj/pkg/overlay"
 	"storj.io/storj/satellite"
 	"storj.io/storj/satellite/console"
+	"storj.io/storj/satellite/disqualification"
 	"storj.io/storj/satellite/orders"
 	dbx "storj.io/storj/satellite/satellitedb/dbx"
 )
@@ -119,6 +121,36 @@ func (db *DB) Accounting() accounting.DB {
 	return &accountingDB{db: db.db}
 }
 
+// Containment returns database for storing the pending audits of contained nodes
+func (db *DB) Containment() audit.Containment {
+	return &containment{db: db.db}
+}
+
+// AuditHistory returns database for storing the audit records
+func (db *DB) AuditHistory() audit.HistoryDB {
+	return &auditHistory{db: db.db, table: "audit_history"}
+}
+
+// AuditDryRun returns database for storing the audit records of the dry run
+func (db *DB) AuditDryRun() audit.HistoryDB {
+	return &auditHistory{db: db.db, table: "audit_dry_run_history"}
+}
+
+// AuditStats returns database for storing the daily aggregates of the audits
+func (db *DB) AuditStats() audit.StatsDB {
+	return &auditStats{db: db.db}
+}
+
+// AuditQueue returns database for storing the segments waiting to be audited
+func (db *DB) AuditQueue() audit.QueueDB {
+	return &auditQueue{db: db.db}
+}
+
+// Disqualification returns database for storing the disqualifications of the nodes
+func (db *DB) Disqualification() disqualification.DB {
+	return &disqualificationDB{db: db.db}
+}
+
 // Irreparable returns database for storing segments that failed repair
 func (db *DB) Irreparable() irreparable.DB {
 	return &irreparableDB{db: db.db}
@@ -26,6 +26,43 @@ CREATE TABLE accounting_timestamps (
 	value timestamp with time zone NOT NULL,
 	PRIMARY KEY ( name )
 );
+CREATE TABLE audit_daily_coverage (
+	interval_start timestamp with time zone NOT NULL,
+	segments_audited bigint NOT NULL,
+	total_segments bigint NOT NULL,
+	PRIMARY KEY ( interval_start )
+);
+CREATE TABLE audit_daily_outcomes (
+	interval_start timestamp with time zone NOT NULL,
+	outcome integer NOT NULL,
+	count bigint NOT NULL,
+	PRIMARY KEY ( interval_start, outcome )
+);
+CREATE TABLE audit_dry_run_history (
+	id bigserial NOT NULL,
+	segment_path bytea NOT NULL,
+	stripe_index bigint NOT NULL,
+	node_id bytea NOT NULL,
+	outcome integer NOT NULL,
+	reverify boolean NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY ( id )
+);
+CREATE TABLE audit_history (
+	id bigserial NOT NULL,
+	segment_path bytea NOT NULL,
+	stripe_index bigint NOT NULL,
+	node_id bytea NOT NULL,
+	outcome integer NOT NULL,
+	reverify boolean NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY ( id )
+);
+CREATE TABLE audit_queue (
+	path bytea NOT NULL,
+	position bigint NOT NULL,
+	PRIMARY KEY ( path )
+);
 CREATE TABLE bucket_bandwidth_rollups (
 	bucket_id bytea NOT NULL,
 	interval_start timestamp NOT NULL,
@@ -78,19 +115,49 @@ CREATE TABLE certRecords (
 	update_at timestamp with time zone NOT NULL,
 	PRIMARY KEY ( id )
 );
-CREATE TABLE injuredsegments (
+CREATE TABLE disqualification_events (
 	id bigserial NOT NULL,
-	info bytea NOT NULL,
+	node_id bytea NOT NULL,
+	reason text NOT NULL,
+	detail text NOT NULL,
+	created_at timestamp with time zone NOT NULL,
 	PRIMARY KEY ( id )
 );
 CREATE TABLE irreparabledbs (
 	segmentpath bytea NOT NULL,
 	segmentdetail bytea NOT NULL,
 	pieces_lost_count bigint NOT NULL,
+	lost_piece_nums text NOT NULL,
 	seg_damaged_unix_sec bigint NOT NULL,
 	repair_attempt_count bigint NOT NULL,
+	last_error text NOT NULL,
 	PRIMARY KEY ( segmentpath )
 );
+CREATE TABLE node_networks (
+	node_id bytea NOT NULL,
+	last_ip text NOT NULL,
+	last_net text NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY ( node_id )
+);
+CREATE TABLE node_reputation_history (
+	node_id bytea NOT NULL,
+	interval_start timestamp with time zone NOT NULL,
+	audit_score double precision NOT NULL,
+	uptime_score double precision NOT NULL,
+	PRIMARY KEY ( node_id, interval_start )
+);
+CREATE TABLE node_reputations (
+	node_id bytea NOT NULL,
+	audit_alpha double precision NOT NULL,
+	audit_beta double precision NOT NULL,
+	uptime_alpha double precision NOT NULL,
+	uptime_beta double precision NOT NULL,
+	disqualified timestamp with time zone,
+	vetted_at timestamp with time zone,
+	updated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY ( node_id )
+);
 CREATE TABLE nodes (
 	id bytea NOT NULL,
 	address text NOT NULL,
@@ -107,12 +174,28 @@ CREATE TABLE nodes (
 	uptime_success_count bigint NOT NULL,
 	total_uptime_count bigint NOT NULL,
 	uptime_ratio double precision NOT NULL,
+	major bigint NOT NULL,
+	minor bigint NOT NULL,
+	patch bigint NOT NULL,
+	hash text NOT NULL,
+	timestamp timestamp with time zone NOT NULL,
+	release boolean NOT NULL,
 	created_at timestamp with time zone NOT NULL,
 	updated_at timestamp with time zone NOT NULL,
 	last_contact_success timestamp with time zone NOT NULL,
 	last_contact_failure timestamp with time zone NOT NULL,
 	PRIMARY KEY ( id )
 );
+CREATE TABLE pending_audits (
+	node_id bytea NOT NULL,
+	piece_id bytea NOT NULL,
+	stripe_index bigint NOT NULL,
+	share_size bigint NOT NULL,
+	expected_share_hash bytea NOT NULL,
+	reverify_count bigint NOT NULL,
+	path bytea NOT NULL,
+	PRIMARY KEY ( node_id )
+);
 CREATE TABLE projects (
 	id bytea NOT NULL,
 	name text NOT NULL,
@@ -128,6 +211,16 @@ CREATE TABLE registration_tokens (
 	PRIMARY KEY ( secret ),
 	UNIQUE ( owner_id )
 );
+CREATE TABLE repair_queue (
+	path bytea NOT NULL,
+	data bytea NOT NULL,
+	segment_health double precision NOT NULL,
+	attempts bigint NOT NULL,
+	inserted_at timestamp with time zone NOT NULL,
+	attempted_at timestamp with time zone,
+	retry_at timestamp with time zone,
+	PRIMARY KEY ( path )
+);
 CREATE TABLE serial_numbers (
 	id serial NOT NULL,
 	serial_number bytea NOT NULL,
@@ -181,8 +274,14 @@ CREATE TABLE used_serials (
 	storage_node_id bytea NOT NULL,
 	PRIMARY KEY ( serial_number_id, storage_node_id )
 );
+CREATE INDEX audit_dry_run_history_node_id_created_at_index ON audit_dry_run_history ( node_id, created_at );
+CREATE INDEX audit_dry_run_history_segment_path_created_at_index ON audit_dry_run_history ( segment_path, created_at );
+CREATE INDEX audit_history_node_id_created_at_index ON audit_history ( node_id, created_at );
+CREATE INDEX audit_history_segment_path_created_at_index ON audit_history ( segment_path, created_at );
 CREATE INDEX bucket_id_interval_start_interval_seconds ON bucket_bandwidth_rollups ( bucket_id, interval_start, interval_seconds );
 CREATE UNIQUE INDEX bucket_id_rollup ON bucket_usages ( bucket_id, rollup_end_time );
+CREATE INDEX disqualification_events_node_id_created_at_index ON disqualification_events ( node_id, created_at );
+CREATE INDEX repair_queue_segment_health_inserted_at_index ON repair_queue ( segment_health, inserted_at );
 CREATE UNIQUE INDEX serial_number ON serial_numbers ( serial_number );
 CREATE INDEX serial_numbers_expires_at_index ON serial_numbers ( expires_at );
 CREATE INDEX storagenode_id_interval_start_interval_seconds ON storagenode_bandwidth_rollups ( storagenode_id, interval_start, interval_seconds );
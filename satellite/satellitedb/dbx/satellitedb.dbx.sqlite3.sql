@@ -26,6 +26,43 @@ CREATE TABLE accounting_timestamps (
 	value TIMESTAMP NOT NULL,
 	PRIMARY KEY ( name )
 );
+CREATE TABLE audit_daily_coverage (
+	interval_start TIMESTAMP NOT NULL,
+	segments_audited INTEGER NOT NULL,
+	total_segments INTEGER NOT NULL,
+	PRIMARY KEY ( interval_start )
+);
+CREATE TABLE audit_daily_outcomes (
+	interval_start TIMESTAMP NOT NULL,
+	outcome INTEGER NOT NULL,
+	count INTEGER NOT NULL,
+	PRIMARY KEY ( interval_start, outcome )
+);
+CREATE TABLE audit_dry_run_history (
+	id INTEGER NOT NULL,
+	segment_path BLOB NOT NULL,
+	stripe_index INTEGER NOT NULL,
+	node_id BLOB NOT NULL,
+	outcome INTEGER NOT NULL,
+	reverify INTEGER NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	PRIMARY KEY ( id )
+);
+CREATE TABLE audit_history (
+	id INTEGER NOT NULL,
+	segment_path BLOB NOT NULL,
+	stripe_index INTEGER NOT NULL,
+	node_id BLOB NOT NULL,
+	outcome INTEGER NOT NULL,
+	reverify INTEGER NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	PRIMARY KEY ( id )
+);
+CREATE TABLE audit_queue (
+	path BLOB NOT NULL,
+	position INTEGER NOT NULL,
+	PRIMARY KEY ( path )
+);
 CREATE TABLE bucket_bandwidth_rollups (
 	bucket_id BLOB NOT NULL,
 	interval_start TIMESTAMP NOT NULL,
@@ -78,19 +115,49 @@ CREATE TABLE certRecords (
 	update_at TIMESTAMP NOT NULL,
 	PRIMARY KEY ( id )
 );
-CREATE TABLE injuredsegments (
+CREATE TABLE disqualification_events (
 	id INTEGER NOT NULL,
-	info BLOB NOT NULL,
+	node_id BLOB NOT NULL,
+	reason TEXT NOT NULL,
+	detail TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL,
 	PRIMARY KEY ( id )
 );
 CREATE TABLE irreparabledbs (
 	segmentpath BLOB NOT NULL,
 	segmentdetail BLOB NOT NULL,
 	pieces_lost_count INTEGER NOT NULL,
+	lost_piece_nums TEXT NOT NULL,
 	seg_damaged_unix_sec INTEGER NOT NULL,
 	repair_attempt_count INTEGER NOT NULL,
+	last_error TEXT NOT NULL,
 	PRIMARY KEY ( segmentpath )
 );
+CREATE TABLE node_networks (
+	node_id BLOB NOT NULL,
+	last_ip TEXT NOT NULL,
+	last_net TEXT NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	PRIMARY KEY ( node_id )
+);
+CREATE TABLE node_reputation_history (
+	node_id BLOB NOT NULL,
+	interval_start TIMESTAMP NOT NULL,
+	audit_score REAL NOT NULL,
+	uptime_score REAL NOT NULL,
+	PRIMARY KEY ( node_id, interval_start )
+);
+CREATE TABLE node_reputations (
+	node_id BLOB NOT NULL,
+	audit_alpha REAL NOT NULL,
+	audit_beta REAL NOT NULL,
+	uptime_alpha REAL NOT NULL,
+	uptime_beta REAL NOT NULL,
+	disqualified TIMESTAMP,
+	vetted_at TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL,
+	PRIMARY KEY ( node_id )
+);
 CREATE TABLE nodes (
 	id BLOB NOT NULL,
 	address TEXT NOT NULL,
@@ -107,12 +174,28 @@ CREATE TABLE nodes (
 	uptime_success_count INTEGER NOT NULL,
 	total_uptime_count INTEGER NOT NULL,
 	uptime_ratio REAL NOT NULL,
+	major INTEGER NOT NULL,
+	minor INTEGER NOT NULL,
+	patch INTEGER NOT NULL,
+	hash TEXT NOT NULL,
+	timestamp TIMESTAMP NOT NULL,
+	release INTEGER NOT NULL,
 	created_at TIMESTAMP NOT NULL,
 	updated_at TIMESTAMP NOT NULL,
 	last_contact_success TIMESTAMP NOT NULL,
 	last_contact_failure TIMESTAMP NOT NULL,
 	PRIMARY KEY ( id )
 );
+CREATE TABLE pending_audits (
+	node_id BLOB NOT NULL,
+	piece_id BLOB NOT NULL,
+	stripe_index INTEGER NOT NULL,
+	share_size INTEGER NOT NULL,
+	expected_share_hash BLOB NOT NULL,
+	reverify_count INTEGER NOT NULL,
+	path BLOB NOT NULL,
+	PRIMARY KEY ( node_id )
+);
 CREATE TABLE projects (
 	id BLOB NOT NULL,
 	name TEXT NOT NULL,
@@ -128,6 +211,16 @@ CREATE TABLE registration_tokens (
 	PRIMARY KEY ( secret ),
 	UNIQUE ( owner_id )
 );
+CREATE TABLE repair_queue (
+	path BLOB NOT NULL,
+	data BLOB NOT NULL,
+	segment_health REAL NOT NULL,
+	attempts INTEGER NOT NULL,
+	inserted_at TIMESTAMP NOT NULL,
+	attempted_at TIMESTAMP,
+	retry_at TIMESTAMP,
+	PRIMARY KEY ( path )
+);
 CREATE TABLE serial_numbers (
 	id INTEGER NOT NULL,
 	serial_number BLOB NOT NULL,
@@ -181,8 +274,14 @@ CREATE TABLE used_serials (
 	storage_node_id BLOB NOT NULL,
 	PRIMARY KEY ( serial_number_id, storage_node_id )
 );
+CREATE INDEX audit_dry_run_history_node_id_created_at_index ON audit_dry_run_history ( node_id, created_at );
+CREATE INDEX audit_dry_run_history_segment_path_created_at_index ON audit_dry_run_history ( segment_path, created_at );
+CREATE INDEX audit_history_node_id_created_at_index ON audit_history ( node_id, created_at );
+CREATE INDEX audit_history_segment_path_created_at_index ON audit_history ( segment_path, created_at );
 CREATE INDEX bucket_id_interval_start_interval_seconds ON bucket_bandwidth_rollups ( bucket_id, interval_start, interval_seconds );
 CREATE UNIQUE INDEX bucket_id_rollup ON bucket_usages ( bucket_id, rollup_end_time );
+CREATE INDEX disqualification_events_node_id_created_at_index ON disqualification_events ( node_id, created_at );
+CREATE INDEX repair_queue_segment_health_inserted_at_index ON repair_queue ( segment_health, inserted_at );
 CREATE UNIQUE INDEX serial_number ON serial_numbers ( serial_number );
 CREATE INDEX serial_numbers_expires_at_index ON serial_numbers ( expires_at );
 CREATE INDEX storagenode_id_interval_start_interval_seconds ON storagenode_bandwidth_rollups ( storagenode_id, interval_start, interval_seconds );
@@ -0,0 +1,268 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package satellitedb
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/audit"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/satellite/disqualification"
+	dbx "storj.io/storj/satellite/satellitedb/dbx"
+)
+
+type disqualificationDB struct {
+	db *dbx.DB
+}
+
+// Disqualify marks the node as disqualified and records the event, it
+// returns false when the node already was disqualified.
+func (dq *disqualificationDB) Disqualify(ctx context.Context, nodeID storj.NodeID, reason disqualification.Reason, detail string) (disqualified bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = dq.db.WithTx(ctx, func(ctx context.Context, tx *dbx.Tx) error {
+		reputation, err := getNodeReputation(ctx, tx.Tx, dq.db.Rebind, nodeID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if !reputation.disqualify(now) {
+			return nil
+		}
+		disqualified = true
+
+		err = saveNodeReputation(ctx, tx.Tx, dq.db.Rebind, nodeID, reputation, now)
+		if err != nil {
+			return err
+		}
+		return insertDisqualificationEvent(ctx, tx.Tx, dq.db.Rebind, nodeID, reason, detail, now)
+	})
+	return disqualified, Error.Wrap(err)
+}
+
+// Reinstate clears the disqualification of the node, resets its reputation
+// and records the event.
+func (dq *disqualificationDB) Reinstate(ctx context.Context, nodeID storj.NodeID, detail string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = dq.db.WithTx(ctx, func(ctx context.Context, tx *dbx.Tx) error {
+		reputation, err := getNodeReputation(ctx, tx.Tx, dq.db.Rebind, nodeID)
+		if err != nil {
+			return err
+		}
+		if reputation.Disqualified == nil {
+			return disqualification.ErrNotDisqualified.New("%s", nodeID)
+		}
+
+		// the node starts over with the initial reputation of a new node
+		now := time.Now()
+		err = saveNodeReputation(ctx, tx.Tx, dq.db.Rebind, nodeID, nodeReputation{}, now)
+		if err != nil {
+			return err
+		}
+		return insertDisqualificationEvent(ctx, tx.Tx, dq.db.Rebind, nodeID, disqualification.ReasonReinstated, detail, now)
+	})
+	if disqualification.ErrNotDisqualified.Has(err) {
+		return err
+	}
+	return Error.Wrap(err)
+}
+
+// Events returns the most recent events of the node, newest first.
+func (dq *disqualificationDB) Events(ctx context.Context, nodeID storj.NodeID, limit int) (events []disqualification.Event, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := dq.db.DB.QueryContext(ctx, dq.db.Rebind(`
+		SELECT reason, detail, created_at
+		FROM disqualification_events WHERE node_id = ?
+		ORDER BY created_at DESC, id DESC LIMIT ?`), nodeID.Bytes(), limit)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	for rows.Next() {
+		event := disqualification.Event{NodeID: nodeID}
+		var reason string
+		if err := rows.Scan(&reason, &event.Detail, &event.CreatedAt); err != nil {
+			return nil, Error.Wrap(err)
+		}
+		event.Reason = disqualification.Reason(reason)
+		events = append(events, event)
+	}
+	return events, Error.Wrap(rows.Err())
+}
+
+// Disqualified returns the latest event of every disqualified node, the most
+// recently disqualified first.
+func (dq *disqualificationDB) Disqualified(ctx context.Context) (events []disqualification.Event, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	nodeIDs, err := dq.queryNodeIDs(ctx, `
+		SELECT node_id FROM node_reputations
+		WHERE disqualified IS NOT NULL
+		ORDER BY disqualified DESC`)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, nodeID := range nodeIDs {
+		latest, err := dq.Events(ctx, nodeID, 1)
+		if err != nil {
+			return nil, err
+		}
+		// the node was disqualified before the events were recorded
+		if len(latest) == 0 {
+			reputation, err := getNodeReputation(ctx, dq.db.DB, dq.db.Rebind, nodeID)
+			if err != nil {
+				return nil, Error.Wrap(err)
+			}
+			event := disqualification.Event{NodeID: nodeID}
+			if reputation.Disqualified != nil {
+				event.CreatedAt = *reputation.Disqualified
+			}
+			latest = append(latest, event)
+		}
+		events = append(events, latest[0])
+	}
+	return events, nil
+}
+
+// LowAuditScores returns the nodes that aren't disqualified and whose audit
+// score is below threshold.
+func (dq *disqualificationDB) LowAuditScores(ctx context.Context, threshold float64) (nodeIDs storj.NodeIDList, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	scores, err := lowAuditScores(ctx, dq.db, threshold)
+	if err != nil {
+		return nil, err
+	}
+	for _, score := range scores {
+		nodeIDs = append(nodeIDs, score.NodeID)
+	}
+	return nodeIDs, nil
+}
+
+// ReverifyFailures returns the number of reverifications every node failed in
+// a row since its last successful one.
+func (dq *disqualificationDB) ReverifyFailures(ctx context.Context) (failures map[storj.NodeID]int, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := dq.db.DB.QueryContext(ctx, dq.db.Rebind(`
+		SELECT node_id, outcome FROM audit_history
+		WHERE reverify = ?
+		ORDER BY node_id, created_at DESC, id DESC`), true)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	failures = make(map[storj.NodeID]int)
+	succeeded := make(map[storj.NodeID]bool)
+	for rows.Next() {
+		var id []byte
+		var outcome int
+		if err := rows.Scan(&id, &outcome); err != nil {
+			return nil, Error.Wrap(err)
+		}
+		nodeID, err := storj.NodeIDFromBytes(id)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+
+		// the rows of a node come newest first, so only the failures
+		// before its most recent success are counted
+		if succeeded[nodeID] {
+			continue
+		}
+		if audit.Outcome(outcome) == audit.OutcomeSuccess {
+			succeeded[nodeID] = true
+			continue
+		}
+		failures[nodeID]++
+	}
+	return failures, Error.Wrap(rows.Err())
+}
+
+// OfflineSince returns the nodes that aren't disqualified and haven't been
+// reached since before.
+func (dq *disqualificationDB) OfflineSince(ctx context.Context, before time.Time) (_ storj.NodeIDList, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return dq.queryNodeIDs(ctx, `
+		SELECT id FROM nodes
+		WHERE last_contact_success < ?
+		  AND last_contact_failure > last_contact_success
+		  AND `+notDisqualifiedCondition, before.UTC())
+}
+
+// queryNodeIDs returns the node ids selected by the query.
+func (dq *disqualificationDB) queryNodeIDs(ctx context.Context, query string, args ...interface{}) (nodeIDs storj.NodeIDList, err error) {
+	rows, err := dq.db.DB.QueryContext(ctx, dq.db.Rebind(query), args...)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	for rows.Next() {
+		var id []byte
+		if err := rows.Scan(&id); err != nil {
+			return nil, Error.Wrap(err)
+		}
+		nodeID, err := storj.NodeIDFromBytes(id)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	return nodeIDs, Error.Wrap(rows.Err())
+}
+
+// lowAuditScores returns the nodes that aren't disqualified and whose audit
+// score is below threshold, with their score.
+func lowAuditScores(ctx context.Context, db *dbx.DB, threshold float64) (nodes []audit.NodeScore, err error) {
+	// a node without audits has the score 1
+	rows, err := db.DB.QueryContext(ctx, db.Rebind(`
+		SELECT node_id, audit_alpha, audit_beta FROM node_reputations
+		WHERE disqualified IS NULL
+		  AND audit_alpha + audit_beta > 0
+		  AND audit_alpha < ? * (audit_alpha + audit_beta)`), threshold)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	for rows.Next() {
+		var id []byte
+		var alpha, beta float64
+		if err := rows.Scan(&id, &alpha, &beta); err != nil {
+			return nil, Error.Wrap(err)
+		}
+		nodeID, err := storj.NodeIDFromBytes(id)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		nodes = append(nodes, audit.NodeScore{
+			NodeID:     nodeID,
+			AuditScore: alpha / (alpha + beta),
+		})
+	}
+	return nodes, Error.Wrap(rows.Err())
+}
+
+// insertDisqualificationEvent records a disqualification or reinstatement of the node.
+func insertDisqualificationEvent(ctx context.Context, tx *sql.Tx, rebind func(string) string, nodeID storj.NodeID, reason disqualification.Reason, detail string, now time.Time) error {
+	_, err := tx.ExecContext(ctx, rebind(`
+		INSERT INTO disqualification_events (
+			node_id, reason, detail, created_at
+		) VALUES (?, ?, ?, ?)`),
+		nodeID.Bytes(), string(reason), detail, now.UTC(),
+	)
+	return err
+}
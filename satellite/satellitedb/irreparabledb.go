@@ -5,6 +5,8 @@ package satellitedb
 
 import (
 	"context"
+	"strconv"
+	"strings"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/zeebo/errs"
@@ -17,102 +19,168 @@ type irreparableDB struct {
 	db *dbx.DB
 }
 
-// IncrementRepairAttempts a db entry for to increment the repair attempts field
+// IncrementRepairAttempts adds the segment, or increments the repair attempts
+// of a segment that is already stored and updates its detail, lost pieces and
+// last error.
 func (db *irreparableDB) IncrementRepairAttempts(ctx context.Context, segmentInfo *pb.IrreparableSegment) (err error) {
-	tx, err := db.db.Open(ctx)
+	defer mon.Task()(&ctx)(&err)
+
+	detail, err := proto.Marshal(segmentInfo.SegmentDetail)
 	if err != nil {
 		return Error.Wrap(err)
 	}
 
-	bytes, err := proto.Marshal(segmentInfo.SegmentDetail)
-	if err != nil {
-		return err
-	}
+	_, err = db.db.DB.ExecContext(ctx, db.db.Rebind(`
+		INSERT INTO irreparabledbs (
+			segmentpath, segmentdetail, pieces_lost_count, lost_piece_nums,
+			seg_damaged_unix_sec, repair_attempt_count, last_error
+		) VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT ( segmentpath )
+		DO UPDATE SET
+			segmentdetail = excluded.segmentdetail,
+			pieces_lost_count = excluded.pieces_lost_count,
+			lost_piece_nums = excluded.lost_piece_nums,
+			seg_damaged_unix_sec = excluded.seg_damaged_unix_sec,
+			repair_attempt_count = irreparabledbs.repair_attempt_count + 1,
+			last_error = excluded.last_error`),
+		segmentInfo.Path, detail, segmentInfo.LostPieces, encodePieceNums(segmentInfo.LostPieceNums),
+		segmentInfo.LastRepairAttempt, segmentInfo.RepairAttemptCount, segmentInfo.LastError)
+	return Error.Wrap(err)
+}
+
+// Upsert adds the segment, or updates the detail and the lost pieces of a
+// segment that is already stored, keeping its repair attempts and last error.
+func (db *irreparableDB) Upsert(ctx context.Context, segmentInfo *pb.IrreparableSegment) (err error) {
+	defer mon.Task()(&ctx)(&err)
 
-	dbxInfo, err := tx.Get_Irreparabledb_By_Segmentpath(ctx, dbx.Irreparabledb_Segmentpath(segmentInfo.Path))
+	detail, err := proto.Marshal(segmentInfo.SegmentDetail)
 	if err != nil {
-		// no rows err, so create/insert an entry
-		_, err = tx.Create_Irreparabledb(
-			ctx,
-			dbx.Irreparabledb_Segmentpath(segmentInfo.Path),
-			dbx.Irreparabledb_Segmentdetail(bytes),
-			dbx.Irreparabledb_PiecesLostCount(int64(segmentInfo.LostPieces)),
-			dbx.Irreparabledb_SegDamagedUnixSec(segmentInfo.LastRepairAttempt),
-			dbx.Irreparabledb_RepairAttemptCount(segmentInfo.RepairAttemptCount),
-		)
-		if err != nil {
-			return Error.Wrap(errs.Combine(err, tx.Rollback()))
-		}
-	} else {
-		// row exits increment the attempt counter
-		dbxInfo.RepairAttemptCount++
-		updateFields := dbx.Irreparabledb_Update_Fields{}
-		updateFields.RepairAttemptCount = dbx.Irreparabledb_RepairAttemptCount(dbxInfo.RepairAttemptCount)
-		updateFields.SegDamagedUnixSec = dbx.Irreparabledb_SegDamagedUnixSec(segmentInfo.LastRepairAttempt)
-		_, err = tx.Update_Irreparabledb_By_Segmentpath(
-			ctx,
-			dbx.Irreparabledb_Segmentpath(dbxInfo.Segmentpath),
-			updateFields,
-		)
-		if err != nil {
-			return Error.Wrap(errs.Combine(err, tx.Rollback()))
-		}
+		return Error.Wrap(err)
 	}
 
-	return Error.Wrap(tx.Commit())
+	_, err = db.db.DB.ExecContext(ctx, db.db.Rebind(`
+		INSERT INTO irreparabledbs (
+			segmentpath, segmentdetail, pieces_lost_count, lost_piece_nums,
+			seg_damaged_unix_sec, repair_attempt_count, last_error
+		) VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT ( segmentpath )
+		DO UPDATE SET
+			segmentdetail = excluded.segmentdetail,
+			pieces_lost_count = excluded.pieces_lost_count,
+			lost_piece_nums = excluded.lost_piece_nums`),
+		segmentInfo.Path, detail, segmentInfo.LostPieces, encodePieceNums(segmentInfo.LostPieceNums),
+		segmentInfo.LastRepairAttempt, segmentInfo.RepairAttemptCount, segmentInfo.LastError)
+	return Error.Wrap(err)
 }
 
 // Get a irreparable's segment info from the db
 func (db *irreparableDB) Get(ctx context.Context, segmentPath []byte) (resp *pb.IrreparableSegment, err error) {
-	dbxInfo, err := db.db.Get_Irreparabledb_By_Segmentpath(ctx, dbx.Irreparabledb_Segmentpath(segmentPath))
-	if err != nil {
-		return &pb.IrreparableSegment{}, Error.Wrap(err)
-	}
+	defer mon.Task()(&ctx)(&err)
 
-	p := &pb.Pointer{}
-
-	err = proto.Unmarshal(dbxInfo.Segmentdetail, p)
+	row := db.db.DB.QueryRowContext(ctx, db.db.Rebind(`
+		SELECT segmentpath, segmentdetail, pieces_lost_count, lost_piece_nums,
+			seg_damaged_unix_sec, repair_attempt_count, last_error
+		FROM irreparabledbs WHERE segmentpath = ?`), segmentPath)
+	resp, err = scanIrreparableSegment(row)
 	if err != nil {
-		return &pb.IrreparableSegment{}, err
+		return &pb.IrreparableSegment{}, Error.Wrap(err)
 	}
-
-	return &pb.IrreparableSegment{
-		Path:               dbxInfo.Segmentpath,
-		SegmentDetail:      p,
-		LostPieces:         int32(dbxInfo.PiecesLostCount),
-		LastRepairAttempt:  dbxInfo.SegDamagedUnixSec,
-		RepairAttemptCount: dbxInfo.RepairAttemptCount,
-	}, nil
+	return resp, nil
 }
 
 // Getlimited number of irreparable segments by offset
 func (db *irreparableDB) GetLimited(ctx context.Context, limit int, offset int64) (resp []*pb.IrreparableSegment, err error) {
-	rows, err := db.db.Limited_Irreparabledb_OrderBy_Asc_Segmentpath(ctx, limit, offset)
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := db.db.DB.QueryContext(ctx, db.db.Rebind(`
+		SELECT segmentpath, segmentdetail, pieces_lost_count, lost_piece_nums,
+			seg_damaged_unix_sec, repair_attempt_count, last_error
+		FROM irreparabledbs ORDER BY segmentpath
+		LIMIT ? OFFSET ?`), limit, offset)
 	if err != nil {
-		return nil, err
+		return nil, Error.Wrap(err)
 	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
 
-	for _, row := range rows {
-		p := &pb.Pointer{}
-		err = proto.Unmarshal(row.Segmentdetail, p)
+	for rows.Next() {
+		segment, err := scanIrreparableSegment(rows)
 		if err != nil {
-			return nil, err
-		}
-		segment := &pb.IrreparableSegment{
-			Path:               row.Segmentpath,
-			SegmentDetail:      p,
-			LostPieces:         int32(row.PiecesLostCount),
-			LastRepairAttempt:  row.SegDamagedUnixSec,
-			RepairAttemptCount: row.RepairAttemptCount,
+			return nil, Error.Wrap(err)
 		}
 		resp = append(resp, segment)
 	}
-	return resp, err
+	return resp, Error.Wrap(rows.Err())
+}
+
+// Count returns the number of irreparable segments.
+func (db *irreparableDB) Count(ctx context.Context) (count int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = db.db.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM irreparabledbs`).Scan(&count)
+	return count, Error.Wrap(err)
 }
 
 // Delete a irreparable's segment info from the db
 func (db *irreparableDB) Delete(ctx context.Context, segmentPath []byte) (err error) {
-	_, err = db.db.Delete_Irreparabledb_By_Segmentpath(ctx, dbx.Irreparabledb_Segmentpath(segmentPath))
+	defer mon.Task()(&ctx)(&err)
 
+	_, err = db.db.DB.ExecContext(ctx, db.db.Rebind(`DELETE FROM irreparabledbs WHERE segmentpath = ?`), segmentPath)
 	return Error.Wrap(err)
 }
+
+// DeleteAll removes all irreparable segments and returns how many were removed.
+func (db *irreparableDB) DeleteAll(ctx context.Context) (count int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	result, err := db.db.DB.ExecContext(ctx, `DELETE FROM irreparabledbs`)
+	if err != nil {
+		return 0, Error.Wrap(err)
+	}
+	count, err = result.RowsAffected()
+	return count, Error.Wrap(err)
+}
+
+// scanIrreparableSegment scans the columns of an irreparable segment.
+func scanIrreparableSegment(row rowScanner) (*pb.IrreparableSegment, error) {
+	segment := &pb.IrreparableSegment{}
+	var detail []byte
+	var lostPieceNums string
+	err := row.Scan(&segment.Path, &detail, &segment.LostPieces, &lostPieceNums,
+		&segment.LastRepairAttempt, &segment.RepairAttemptCount, &segment.LastError)
+	if err != nil {
+		return nil, err
+	}
+
+	segment.SegmentDetail = &pb.Pointer{}
+	if err := proto.Unmarshal(detail, segment.SegmentDetail); err != nil {
+		return nil, err
+	}
+	segment.LostPieceNums, err = decodePieceNums(lostPieceNums)
+	return segment, err
+}
+
+// encodePieceNums encodes piece numbers as a comma separated list, which
+// stays readable when inspecting the database.
+func encodePieceNums(nums []int32) string {
+	strs := make([]string, len(nums))
+	for i, num := range nums {
+		strs[i] = strconv.Itoa(int(num))
+	}
+	return strings.Join(strs, ",")
+}
+
+// decodePieceNums decodes a list of piece numbers encoded by encodePieceNums.
+func decodePieceNums(encoded string) ([]int32, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+	var nums []int32
+	for _, str := range strings.Split(encoded, ",") {
+		num, err := strconv.ParseInt(str, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		nums = append(nums, int32(num))
+	}
+	return nums, nil
+}
@@ -13,7 +13,9 @@ import (
 
 	"github.com/skyrings/skyring-common/tools/uuid"
 
+	"storj.io/storj/internal/version"
 	"storj.io/storj/pkg/accounting"
+	"storj.io/storj/pkg/audit"
 	"storj.io/storj/pkg/bwagreement"
 	"storj.io/storj/pkg/certdb"
 	"storj.io/storj/pkg/datarepair/irreparable"
@@ -23,6 +25,7 @@ import (
 	"storj.io/storj/pkg/storj"
 	"storj.io/storj/satellite"
 	"storj.io/storj/satellite/console"
+	"storj.io/storj/satellite/disqualification"
 	"storj.io/storj/satellite/orders"
 )
 
@@ -85,6 +88,13 @@ func (m *lockedAccounting) QueryPaymentInfo(ctx context.Context, start time.Time
 	return m.db.QueryPaymentInfo(ctx, start, end)
 }
 
+// QueryNodePaymentInfo queries Overlay, Accounting Rollup of a single node, it returns nil when the node doesn't exist
+func (m *lockedAccounting) QueryNodePaymentInfo(ctx context.Context, nodeID storj.NodeID, start time.Time, end time.Time) (*accounting.CSVRow, error) {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.QueryNodePaymentInfo(ctx, nodeID, start, end)
+}
+
 // SaveAtRestRaw records raw tallies of at-rest-data.
 func (m *lockedAccounting) SaveAtRestRaw(ctx context.Context, latestTally time.Time, created time.Time, nodeData map[storj.NodeID]float64) error {
 	m.Lock()
@@ -106,6 +116,130 @@ func (m *lockedAccounting) SaveRollup(ctx context.Context, latestTally time.Time
 	return m.db.SaveRollup(ctx, latestTally, stats)
 }
 
+// AuditDryRun returns database for the audit records of the dry run
+func (m *locked) AuditDryRun() audit.HistoryDB {
+	m.Lock()
+	defer m.Unlock()
+	return &lockedAuditHistory{m.Locker, m.db.AuditDryRun()}
+}
+
+// AuditHistory returns database for the audit records
+func (m *locked) AuditHistory() audit.HistoryDB {
+	m.Lock()
+	defer m.Unlock()
+	return &lockedAuditHistory{m.Locker, m.db.AuditHistory()}
+}
+
+// lockedAuditHistory implements locking wrapper for audit.HistoryDB
+type lockedAuditHistory struct {
+	sync.Locker
+	db audit.HistoryDB
+}
+
+// ByNode returns the most recent records of the node, newest first.
+func (m *lockedAuditHistory) ByNode(ctx context.Context, nodeID storj.NodeID, limit int) ([]audit.Record, error) {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.ByNode(ctx, nodeID, limit)
+}
+
+// BySegment returns the most recent records of the segment, newest first.
+func (m *lockedAuditHistory) BySegment(ctx context.Context, path storj.Path, limit int) ([]audit.Record, error) {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.BySegment(ctx, path, limit)
+}
+
+// DeleteBefore removes the records created before the given time.
+func (m *lockedAuditHistory) DeleteBefore(ctx context.Context, before time.Time) (deleted int64, err error) {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.DeleteBefore(ctx, before)
+}
+
+// Insert stores the records, setting their creation time.
+func (m *lockedAuditHistory) Insert(ctx context.Context, records []audit.Record) error {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.Insert(ctx, records)
+}
+
+// AuditQueue returns database for the segments waiting to be audited
+func (m *locked) AuditQueue() audit.QueueDB {
+	m.Lock()
+	defer m.Unlock()
+	return &lockedAuditQueue{m.Locker, m.db.AuditQueue()}
+}
+
+// lockedAuditQueue implements locking wrapper for audit.QueueDB
+type lockedAuditQueue struct {
+	sync.Locker
+	db audit.QueueDB
+}
+
+// All returns the segments waiting to be audited, in order.
+func (m *lockedAuditQueue) All(ctx context.Context) ([]storj.Path, error) {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.All(ctx)
+}
+
+// Remove removes the segment from the queue.
+func (m *lockedAuditQueue) Remove(ctx context.Context, path storj.Path) error {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.Remove(ctx, path)
+}
+
+// Swap replaces the segments waiting to be audited with paths.
+func (m *lockedAuditQueue) Swap(ctx context.Context, paths []storj.Path) error {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.Swap(ctx, paths)
+}
+
+// AuditStats returns database for the daily aggregates of the audits
+func (m *locked) AuditStats() audit.StatsDB {
+	m.Lock()
+	defer m.Unlock()
+	return &lockedAuditStats{m.Locker, m.db.AuditStats()}
+}
+
+// lockedAuditStats implements locking wrapper for audit.StatsDB
+type lockedAuditStats struct {
+	sync.Locker
+	db audit.StatsDB
+}
+
+// Daily returns the stats of the days since the given time, oldest first.
+func (m *lockedAuditStats) Daily(ctx context.Context, since time.Time) ([]audit.DailyStats, error) {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.Daily(ctx, since)
+}
+
+// NodesBelow returns the nodes that aren't disqualified and whose audit
+// score is below score.
+func (m *lockedAuditStats) NodesBelow(ctx context.Context, score float64) ([]audit.NodeScore, error) {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.NodesBelow(ctx, score)
+}
+
+// RecordSegment adds an audited segment and the outcomes of its nodes to the day of now.
+func (m *lockedAuditStats) RecordSegment(ctx context.Context, now time.Time, outcomes map[audit.Outcome]int64) error {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.RecordSegment(ctx, now, outcomes)
+}
+
+// RecordTotalSegments stores the number of remote segments seen on the day of now.
+func (m *lockedAuditStats) RecordTotalSegments(ctx context.Context, now time.Time, total int64) error {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.RecordTotalSegments(ctx, now, total)
+}
+
 // BandwidthAgreement returns database for storing bandwidth agreements
 func (m *locked) BandwidthAgreement() bwagreement.DB {
 	m.Lock()
@@ -188,6 +322,40 @@ func (m *locked) Close() error {
 	return m.db.Close()
 }
 
+// Containment returns database for the pending audits of contained nodes
+func (m *locked) Containment() audit.Containment {
+	m.Lock()
+	defer m.Unlock()
+	return &lockedContainment{m.Locker, m.db.Containment()}
+}
+
+// lockedContainment implements locking wrapper for audit.Containment
+type lockedContainment struct {
+	sync.Locker
+	db audit.Containment
+}
+
+// Delete removes the node from containment, it returns whether the node was contained.
+func (m *lockedContainment) Delete(ctx context.Context, nodeID storj.NodeID) (bool, error) {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.Delete(ctx, nodeID)
+}
+
+// Get returns the pending audit of the node.
+func (m *lockedContainment) Get(ctx context.Context, nodeID storj.NodeID) (*audit.PendingAudit, error) {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.Get(ctx, nodeID)
+}
+
+// IncrementPending creates the pending audit of the node, or increments the reverify count of the pending audit the node already has.
+func (m *lockedContainment) IncrementPending(ctx context.Context, pendingAudit *audit.PendingAudit) error {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.IncrementPending(ctx, pendingAudit)
+}
+
 // Console returns database for satellite console
 func (m *locked) Console() console.DB {
 	m.Lock()
@@ -492,6 +660,74 @@ func (m *locked) CreateTables() error {
 	return m.db.CreateTables()
 }
 
+// Disqualification returns database for the disqualifications of the nodes
+func (m *locked) Disqualification() disqualification.DB {
+	m.Lock()
+	defer m.Unlock()
+	return &lockedDisqualification{m.Locker, m.db.Disqualification()}
+}
+
+// lockedDisqualification implements locking wrapper for disqualification.DB
+type lockedDisqualification struct {
+	sync.Locker
+	db disqualification.DB
+}
+
+// Disqualified returns the latest event of every disqualified node, the
+// most recently disqualified first.
+func (m *lockedDisqualification) Disqualified(ctx context.Context) ([]disqualification.Event, error) {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.Disqualified(ctx)
+}
+
+// Disqualify marks the node as disqualified and records the event, it
+// returns false when the node already was disqualified.
+func (m *lockedDisqualification) Disqualify(ctx context.Context, nodeID storj.NodeID, reason disqualification.Reason, detail string) (disqualified bool, err error) {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.Disqualify(ctx, nodeID, reason, detail)
+}
+
+// Events returns the most recent events of the node, newest first.
+func (m *lockedDisqualification) Events(ctx context.Context, nodeID storj.NodeID, limit int) ([]disqualification.Event, error) {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.Events(ctx, nodeID, limit)
+}
+
+// LowAuditScores returns the nodes that aren't disqualified and whose
+// audit score is below threshold.
+func (m *lockedDisqualification) LowAuditScores(ctx context.Context, threshold float64) (storj.NodeIDList, error) {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.LowAuditScores(ctx, threshold)
+}
+
+// OfflineSince returns the nodes that aren't disqualified and haven't
+// been reached since before.
+func (m *lockedDisqualification) OfflineSince(ctx context.Context, before time.Time) (storj.NodeIDList, error) {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.OfflineSince(ctx, before)
+}
+
+// Reinstate clears the disqualification of the node, resets its
+// reputation and records the event.
+func (m *lockedDisqualification) Reinstate(ctx context.Context, nodeID storj.NodeID, detail string) error {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.Reinstate(ctx, nodeID, detail)
+}
+
+// ReverifyFailures returns the number of reverifications every node
+// failed in a row since its last successful one.
+func (m *lockedDisqualification) ReverifyFailures(ctx context.Context) (map[storj.NodeID]int, error) {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.ReverifyFailures(ctx)
+}
+
 // DropSchema drops the schema
 func (m *locked) DropSchema(schema string) error {
 	m.Lock()
@@ -512,6 +748,13 @@ type lockedIrreparable struct {
 	db irreparable.DB
 }
 
+// Count returns the number of irreparable segments.
+func (m *lockedIrreparable) Count(ctx context.Context) (int64, error) {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.Count(ctx)
+}
+
 // Delete removes irreparable segment info based on segmentPath.
 func (m *lockedIrreparable) Delete(ctx context.Context, segmentPath []byte) error {
 	m.Lock()
@@ -519,6 +762,13 @@ func (m *lockedIrreparable) Delete(ctx context.Context, segmentPath []byte) erro
 	return m.db.Delete(ctx, segmentPath)
 }
 
+// DeleteAll removes all irreparable segments and returns how many were removed.
+func (m *lockedIrreparable) DeleteAll(ctx context.Context) (int64, error) {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.DeleteAll(ctx)
+}
+
 // Get returns irreparable segment info based on segmentPath.
 func (m *lockedIrreparable) Get(ctx context.Context, segmentPath []byte) (*pb.IrreparableSegment, error) {
 	m.Lock()
@@ -533,13 +783,23 @@ func (m *lockedIrreparable) GetLimited(ctx context.Context, limit int, offset in
 	return m.db.GetLimited(ctx, limit, offset)
 }
 
-// IncrementRepairAttempts increments the repair attempts.
+// IncrementRepairAttempts adds the segment, or increments the repair
+// attempts of a segment that is already stored and updates its detail,
+// lost pieces and last error.
 func (m *lockedIrreparable) IncrementRepairAttempts(ctx context.Context, segmentInfo *pb.IrreparableSegment) error {
 	m.Lock()
 	defer m.Unlock()
 	return m.db.IncrementRepairAttempts(ctx, segmentInfo)
 }
 
+// Upsert adds the segment, or updates the detail and the lost pieces of a
+// segment that is already stored, without counting a repair attempt.
+func (m *lockedIrreparable) Upsert(ctx context.Context, segmentInfo *pb.IrreparableSegment) error {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.Upsert(ctx, segmentInfo)
+}
+
 // Orders returns database for orders
 func (m *locked) Orders() orders.DB {
 	m.Lock()
@@ -614,13 +874,20 @@ func (m *lockedOverlayCache) Delete(ctx context.Context, id storj.NodeID) error
 	return m.db.Delete(ctx, id)
 }
 
-// FindInvalidNodes finds a subset of storagenodes that have stats below provided reputation requirements.
+// FindInvalidNodes finds a subset of storagenodes that have stats below provided reputation requirements or that were disqualified.
 func (m *lockedOverlayCache) FindInvalidNodes(ctx context.Context, nodeIDs storj.NodeIDList, maxStats *overlay.NodeStats) (invalid storj.NodeIDList, err error) {
 	m.Lock()
 	defer m.Unlock()
 	return m.db.FindInvalidNodes(ctx, nodeIDs, maxStats)
 }
 
+// FindOutdatedNodes finds a subset of storagenodes that run a version below the minimum.
+func (m *lockedOverlayCache) FindOutdatedNodes(ctx context.Context, nodeIDs storj.NodeIDList, minimum version.SemVer) (outdated storj.NodeIDList, err error) {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.FindOutdatedNodes(ctx, nodeIDs, minimum)
+}
+
 // Get looks up the node by nodeID
 func (m *lockedOverlayCache) Get(ctx context.Context, nodeID storj.NodeID) (*pb.Node, error) {
 	m.Lock()
@@ -642,6 +909,13 @@ func (m *lockedOverlayCache) GetStats(ctx context.Context, nodeID storj.NodeID)
 	return m.db.GetStats(ctx, nodeID)
 }
 
+// LastNets returns the network of the last IP of every node with a known IP.
+func (m *lockedOverlayCache) LastNets(ctx context.Context) (map[storj.NodeID]string, error) {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.LastNets(ctx)
+}
+
 // List lists nodes starting from cursor
 func (m *lockedOverlayCache) List(ctx context.Context, cursor storj.NodeID, limit int) ([]*pb.Node, error) {
 	m.Lock()
@@ -656,6 +930,20 @@ func (m *lockedOverlayCache) Paginate(ctx context.Context, offset int64, limit i
 	return m.db.Paginate(ctx, offset, limit)
 }
 
+// Reliable returns the nodes that weren't disqualified and whose last contact didn't fail.
+func (m *lockedOverlayCache) Reliable(ctx context.Context) (storj.NodeIDList, error) {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.Reliable(ctx)
+}
+
+// ReputationHistory returns the daily reputation scores of a node since the given time.
+func (m *lockedOverlayCache) ReputationHistory(ctx context.Context, nodeID storj.NodeID, since time.Time) ([]overlay.ReputationScore, error) {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.ReputationHistory(ctx, nodeID, since)
+}
+
 // SelectNewStorageNodes looks up nodes based on new node criteria
 func (m *lockedOverlayCache) SelectNewStorageNodes(ctx context.Context, count int, criteria *overlay.NewNodeCriteria) ([]*pb.Node, error) {
 	m.Lock()
@@ -684,6 +972,13 @@ func (m *lockedOverlayCache) UpdateBatch(ctx context.Context, requests []*overla
 	return m.db.UpdateBatch(ctx, requests)
 }
 
+// UpdateLastIP records the IP the node was last contacted at and its network.
+func (m *lockedOverlayCache) UpdateLastIP(ctx context.Context, nodeID storj.NodeID, lastIP string, lastNet string) error {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.UpdateLastIP(ctx, nodeID, lastIP, lastNet)
+}
+
 // UpdateOperator updates the email and wallet for a given node ID for satellite payments.
 func (m *lockedOverlayCache) UpdateOperator(ctx context.Context, node storj.NodeID, updatedOperator pb.NodeOperator) (stats *overlay.NodeStats, err error) {
 	m.Lock()
@@ -699,10 +994,24 @@ func (m *lockedOverlayCache) UpdateStats(ctx context.Context, request *overlay.U
 }
 
 // UpdateUptime updates a single storagenode's uptime stats.
-func (m *lockedOverlayCache) UpdateUptime(ctx context.Context, nodeID storj.NodeID, isUp bool) (stats *overlay.NodeStats, err error) {
+func (m *lockedOverlayCache) UpdateUptime(ctx context.Context, nodeID storj.NodeID, isUp bool, reputation overlay.ReputationUpdate) (stats *overlay.NodeStats, err error) {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.UpdateUptime(ctx, nodeID, isUp, reputation)
+}
+
+// UpdateVersion updates the software version a node reported.
+func (m *lockedOverlayCache) UpdateVersion(ctx context.Context, nodeID storj.NodeID, nodeVersion version.Info) error {
 	m.Lock()
 	defer m.Unlock()
-	return m.db.UpdateUptime(ctx, nodeID, isUp)
+	return m.db.UpdateVersion(ctx, nodeID, nodeVersion)
+}
+
+// UpdateVetted records that the node passed vetting, keeping the time of a node that already passed it.
+func (m *lockedOverlayCache) UpdateVetted(ctx context.Context, nodeID storj.NodeID, vettedAt time.Time) error {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.UpdateVetted(ctx, nodeID, vettedAt)
 }
 
 // RepairQueue returns queue for segments that need repairing
@@ -718,23 +1027,47 @@ type lockedRepairQueue struct {
 	db queue.RepairQueue
 }
 
-// Dequeue removes an injured segment.
-func (m *lockedRepairQueue) Dequeue(ctx context.Context) (pb.InjuredSegment, error) {
+// Delete removes a repaired segment.
+func (m *lockedRepairQueue) Delete(ctx context.Context, seg *pb.InjuredSegment) error {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.Delete(ctx, seg)
+}
+
+// Insert adds an injured segment, or updates the health and the lost
+// pieces of a segment that is already queued.
+func (m *lockedRepairQueue) Insert(ctx context.Context, seg *pb.InjuredSegment) (alreadyInserted bool, err error) {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.Insert(ctx, seg)
+}
+
+// Requeue releases the lease of a segment whose repair failed, the
+// segment isn't selected again before delay elapsed.
+func (m *lockedRepairQueue) Requeue(ctx context.Context, seg *pb.InjuredSegment, delay time.Duration) error {
+	m.Lock()
+	defer m.Unlock()
+	return m.db.Requeue(ctx, seg, delay)
+}
+
+// Select leases the injured segment with the lowest health and counts
+// the attempt, it returns storage.ErrEmptyQueue when no segment is left.
+func (m *lockedRepairQueue) Select(ctx context.Context) (*pb.InjuredSegment, error) {
 	m.Lock()
 	defer m.Unlock()
-	return m.db.Dequeue(ctx)
+	return m.db.Select(ctx)
 }
 
-// Enqueue adds an injured segment.
-func (m *lockedRepairQueue) Enqueue(ctx context.Context, qi *pb.InjuredSegment) error {
+// SelectN lists limit injured segments, the least healthy first.
+func (m *lockedRepairQueue) SelectN(ctx context.Context, limit int) ([]pb.InjuredSegment, error) {
 	m.Lock()
 	defer m.Unlock()
-	return m.db.Enqueue(ctx, qi)
+	return m.db.SelectN(ctx, limit)
 }
 
-// Peekqueue lists limit amount of injured segments.
-func (m *lockedRepairQueue) Peekqueue(ctx context.Context, limit int) ([]pb.InjuredSegment, error) {
+// Stats returns the number of queued segments and the age of the oldest.
+func (m *lockedRepairQueue) Stats(ctx context.Context) (queue.Stats, error) {
 	m.Lock()
 	defer m.Unlock()
-	return m.db.Peekqueue(ctx, limit)
+	return m.db.Stats(ctx)
 }
@@ -494,6 +494,206 @@ func (db *DB) PostgresMigration() *migrate.Migration {
 					`DROP TABLE overlay_cache_nodes CASCADE;`,
 				},
 			},
+			{
+				Description: "Add node version columns to nodes table",
+				Version:     13,
+				Action: migrate.SQL{
+					`ALTER TABLE nodes ADD major BIGINT NOT NULL DEFAULT 0;
+					 ALTER TABLE nodes ADD minor BIGINT NOT NULL DEFAULT 0;
+					 ALTER TABLE nodes ADD patch BIGINT NOT NULL DEFAULT 0;
+					 ALTER TABLE nodes ADD hash TEXT NOT NULL DEFAULT '';
+					 ALTER TABLE nodes ADD timestamp TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT 'epoch';
+					 ALTER TABLE nodes ADD release BOOLEAN NOT NULL DEFAULT FALSE;`,
+				},
+			},
+			{
+				Description: "Add pending audits table for the audit containment",
+				Version:     14,
+				Action: migrate.SQL{
+					`CREATE TABLE pending_audits (
+						node_id bytea NOT NULL,
+						piece_id bytea NOT NULL,
+						stripe_index bigint NOT NULL,
+						share_size bigint NOT NULL,
+						expected_share_hash bytea NOT NULL,
+						reverify_count bigint NOT NULL,
+						PRIMARY KEY ( node_id )
+					);`,
+				},
+			},
+			{
+				Description: "Add node reputation tables for the alpha/beta reputation",
+				Version:     15,
+				Action: migrate.SQL{
+					`CREATE TABLE node_reputations (
+						node_id bytea NOT NULL,
+						audit_alpha double precision NOT NULL,
+						audit_beta double precision NOT NULL,
+						uptime_alpha double precision NOT NULL,
+						uptime_beta double precision NOT NULL,
+						disqualified timestamp with time zone,
+						updated_at timestamp with time zone NOT NULL,
+						PRIMARY KEY ( node_id )
+					);`,
+					`CREATE TABLE node_reputation_history (
+						node_id bytea NOT NULL,
+						interval_start timestamp with time zone NOT NULL,
+						audit_score double precision NOT NULL,
+						uptime_score double precision NOT NULL,
+						PRIMARY KEY ( node_id, interval_start )
+					);`,
+				},
+			},
+			{
+				Description: "Add audit queue table to continue the audits after a restart",
+				Version:     16,
+				Action: migrate.SQL{
+					`CREATE TABLE audit_queue (
+						path bytea NOT NULL,
+						position bigint NOT NULL,
+						PRIMARY KEY ( path )
+					);`,
+				},
+			},
+			{
+				Description: "Add audit history table for the outcome of every audited node",
+				Version:     17,
+				Action: migrate.SQL{
+					`CREATE TABLE audit_history (
+						id bigserial NOT NULL,
+						segment_path bytea NOT NULL,
+						stripe_index bigint NOT NULL,
+						node_id bytea NOT NULL,
+						outcome integer NOT NULL,
+						reverify boolean NOT NULL,
+						created_at timestamp with time zone NOT NULL,
+						PRIMARY KEY ( id )
+					);`,
+					`CREATE INDEX audit_history_node_id_created_at_index ON audit_history ( node_id, created_at );`,
+					`CREATE INDEX audit_history_segment_path_created_at_index ON audit_history ( segment_path, created_at );`,
+				},
+			},
+			{
+				Description: "Add disqualification events table for the disqualifications and reinstatements of the nodes",
+				Version:     18,
+				Action: migrate.SQL{
+					`CREATE TABLE disqualification_events (
+						id bigserial NOT NULL,
+						node_id bytea NOT NULL,
+						reason text NOT NULL,
+						detail text NOT NULL,
+						created_at timestamp with time zone NOT NULL,
+						PRIMARY KEY ( id )
+					);`,
+					`CREATE INDEX disqualification_events_node_id_created_at_index ON disqualification_events ( node_id, created_at );`,
+				},
+			},
+			{
+				Description: "Add daily audit aggregate tables for the audit report",
+				Version:     19,
+				Action: migrate.SQL{
+					`CREATE TABLE audit_daily_outcomes (
+						interval_start timestamp with time zone NOT NULL,
+						outcome integer NOT NULL,
+						count bigint NOT NULL,
+						PRIMARY KEY ( interval_start, outcome )
+					);`,
+					`CREATE TABLE audit_daily_coverage (
+						interval_start timestamp with time zone NOT NULL,
+						segments_audited bigint NOT NULL,
+						total_segments bigint NOT NULL,
+						PRIMARY KEY ( interval_start )
+					);`,
+				},
+			},
+			{
+				Description: "Add audit dry run history table for the audit records of the dry run",
+				Version:     20,
+				Action: migrate.SQL{
+					`CREATE TABLE audit_dry_run_history (
+						id bigserial NOT NULL,
+						segment_path bytea NOT NULL,
+						stripe_index bigint NOT NULL,
+						node_id bytea NOT NULL,
+						outcome integer NOT NULL,
+						reverify boolean NOT NULL,
+						created_at timestamp with time zone NOT NULL,
+						PRIMARY KEY ( id )
+					);`,
+					`CREATE INDEX audit_dry_run_history_node_id_created_at_index ON audit_dry_run_history ( node_id, created_at );`,
+					`CREATE INDEX audit_dry_run_history_segment_path_created_at_index ON audit_dry_run_history ( segment_path, created_at );`,
+				},
+			},
+			{
+				Description: "Add segment health to injured segments so that the least healthy are repaired first",
+				Version:     21,
+				Action: migrate.SQL{
+					`ALTER TABLE injuredsegments ADD COLUMN segment_health double precision NOT NULL DEFAULT 1;`,
+					`ALTER TABLE injuredsegments ALTER COLUMN segment_health DROP DEFAULT;`,
+				},
+			},
+			{
+				Description: "Replace injured segments with the repair queue, the checker refills it on its next iteration",
+				Version:     22,
+				Action: migrate.SQL{
+					`CREATE TABLE repair_queue (
+						path bytea NOT NULL,
+						data bytea NOT NULL,
+						segment_health double precision NOT NULL,
+						attempts bigint NOT NULL,
+						inserted_at timestamp with time zone NOT NULL,
+						attempted_at timestamp with time zone,
+						PRIMARY KEY ( path )
+					);`,
+					`CREATE INDEX repair_queue_segment_health_inserted_at_index ON repair_queue ( segment_health, inserted_at );`,
+					`DROP TABLE injuredsegments;`,
+				},
+			},
+			{
+				Description: "Record the lost pieces and the last repair error of irreparable segments",
+				Version:     23,
+				Action: migrate.SQL{
+					`ALTER TABLE irreparabledbs ADD COLUMN lost_piece_nums text NOT NULL DEFAULT '';`,
+					`ALTER TABLE irreparabledbs ALTER COLUMN lost_piece_nums DROP DEFAULT;`,
+					`ALTER TABLE irreparabledbs ADD COLUMN last_error text NOT NULL DEFAULT '';`,
+					`ALTER TABLE irreparabledbs ALTER COLUMN last_error DROP DEFAULT;`,
+				},
+			},
+			{
+				Description: "Delay the retries of segments whose repair failed",
+				Version:     24,
+				Action: migrate.SQL{
+					`ALTER TABLE repair_queue ADD COLUMN retry_at timestamp with time zone;`,
+				},
+			},
+			{
+				Description: "Add node networks table for selecting nodes from distinct subnets",
+				Version:     25,
+				Action: migrate.SQL{
+					`CREATE TABLE node_networks (
+						node_id bytea NOT NULL,
+						last_ip text NOT NULL,
+						last_net text NOT NULL,
+						updated_at timestamp with time zone NOT NULL,
+						PRIMARY KEY ( node_id )
+					);`,
+				},
+			},
+			{
+				Description: "Record when the nodes passed vetting",
+				Version:     26,
+				Action: migrate.SQL{
+					`ALTER TABLE node_reputations ADD COLUMN vetted_at timestamp with time zone;`,
+				},
+			},
+			{
+				Description: "Add the segment path to the pending audits",
+				Version:     27,
+				Action: migrate.SQL{
+					`ALTER TABLE pending_audits ADD COLUMN path bytea NOT NULL DEFAULT ''::bytea;`,
+					`ALTER TABLE pending_audits ALTER COLUMN path DROP DEFAULT;`,
+				},
+			},
 		},
 	}
 }
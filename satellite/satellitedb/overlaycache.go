@@ -6,15 +6,20 @@ package satellitedb
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"strings"
 	"time"
 
+	"github.com/lib/pq"
+	sqlite3 "github.com/mattn/go-sqlite3"
 	"github.com/zeebo/errs"
 	monkit "gopkg.in/spacemonkeygo/monkit.v2"
 
+	"storj.io/storj/internal/version"
 	"storj.io/storj/pkg/overlay"
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/storj"
+	"storj.io/storj/satellite/disqualification"
 	dbx "storj.io/storj/satellite/satellitedb/dbx"
 	"storj.io/storj/storage"
 )
@@ -31,57 +36,109 @@ type overlaycache struct {
 	db *dbx.DB
 }
 
+// minimumVersionCondition matches nodes with a version at or above the
+// minimum; it takes major, major, minor, minor and patch as arguments.
+const minimumVersionCondition = `(major > ? OR (major = ? AND (minor > ? OR (minor = ? AND patch >= ?))))`
+
+// notDisqualifiedCondition matches nodes that weren't disqualified.
+const notDisqualifiedCondition = `id NOT IN (SELECT node_id FROM node_reputations WHERE disqualified IS NOT NULL)`
+
+// vettedCondition matches nodes that passed vetting, they stay vetted when
+// the vetting requirements are raised.
+const vettedCondition = `id IN (SELECT node_id FROM node_reputations WHERE vetted_at IS NOT NULL)`
+
+// notVettedCondition matches nodes that didn't pass vetting yet.
+const notVettedCondition = `id NOT IN (SELECT node_id FROM node_reputations WHERE vetted_at IS NOT NULL)`
+
 func (cache *overlaycache) SelectStorageNodes(ctx context.Context, count int, criteria *overlay.NodeCriteria) ([]*pb.Node, error) {
 	nodeType := int(pb.NodeType_STORAGE)
-	return cache.queryFilteredNodes(ctx, criteria.Excluded, count, `
+	return cache.queryFilteredNodes(ctx, criteria.Excluded, criteria.DistinctIP, count, `
 		WHERE type = ? AND free_bandwidth >= ? AND free_disk >= ?
-		  AND total_audit_count >= ?
+		  AND (`+vettedCondition+` OR (total_audit_count >= ? AND total_uptime_count >= ?))
 		  AND audit_success_ratio >= ?
-		  AND total_uptime_count >= ?
 		  AND uptime_ratio >= ?
 		  AND last_contact_success > ?
 		  AND last_contact_success > last_contact_failure
+		  AND `+minimumVersionCondition+`
+		  AND `+notDisqualifiedCondition+`
 		`, nodeType, criteria.FreeBandwidth, criteria.FreeDisk,
-		criteria.AuditCount, criteria.AuditSuccessRatio, criteria.UptimeCount, criteria.UptimeSuccessRatio,
+		criteria.AuditCount, criteria.UptimeCount, criteria.AuditSuccessRatio, criteria.UptimeSuccessRatio,
 		time.Now().Add(-1*time.Hour),
+		criteria.MinimumVersion.Major, criteria.MinimumVersion.Major,
+		criteria.MinimumVersion.Minor, criteria.MinimumVersion.Minor,
+		criteria.MinimumVersion.Patch,
 	)
 }
 
 func (cache *overlaycache) SelectNewStorageNodes(ctx context.Context, count int, criteria *overlay.NewNodeCriteria) ([]*pb.Node, error) {
 	nodeType := int(pb.NodeType_STORAGE)
-	return cache.queryFilteredNodes(ctx, criteria.Excluded, count, `
+	return cache.queryFilteredNodes(ctx, criteria.Excluded, criteria.DistinctIP, count, `
 		WHERE type = ? AND free_bandwidth >= ? AND free_disk >= ?
-		  AND total_audit_count < ?
+		  AND `+notVettedCondition+`
+		  AND (total_audit_count < ? OR total_uptime_count < ?)
 		  AND last_contact_success > ?
 		  AND last_contact_success > last_contact_failure
+		  AND `+minimumVersionCondition+`
+		  AND `+notDisqualifiedCondition+`
 	`, nodeType, criteria.FreeBandwidth, criteria.FreeDisk,
-		criteria.AuditThreshold,
+		criteria.AuditThreshold, criteria.UptimeThreshold,
 		time.Now().Add(-1*time.Hour),
+		criteria.MinimumVersion.Major, criteria.MinimumVersion.Major,
+		criteria.MinimumVersion.Minor, criteria.MinimumVersion.Minor,
+		criteria.MinimumVersion.Patch,
 	)
 }
 
-func (cache *overlaycache) queryFilteredNodes(ctx context.Context, excluded []storj.NodeID, count int, safeQuery string, args ...interface{}) (_ []*pb.Node, err error) {
+// selectedNodeColumns are the columns of the nodes returned by queryFilteredNodes.
+const selectedNodeColumns = `id,
+		type, address, free_bandwidth, free_disk, audit_success_ratio,
+		uptime_ratio, total_audit_count, audit_success_count, total_uptime_count,
+		uptime_success_count`
+
+// distinctNetwork groups the nodes by the network of their last IP, every
+// node without a known IP is a group of its own.
+const distinctNetwork = `node_networks.last_net, CASE WHEN node_networks.last_net IS NULL THEN nodes.id END`
+
+// queryFilteredNodes selects count random nodes matching safeQuery that
+// aren't excluded. When distinct is set, the nodes are in distinct networks
+// which aren't the networks of the excluded nodes.
+func (cache *overlaycache) queryFilteredNodes(ctx context.Context, excluded []storj.NodeID, distinct bool, count int, safeQuery string, args ...interface{}) (_ []*pb.Node, err error) {
 	if count == 0 {
 		return nil, nil
 	}
 
 	safeExcludeNodes := ""
 	if len(excluded) > 0 {
-		safeExcludeNodes = ` AND id NOT IN (?` + strings.Repeat(", ?", len(excluded)-1) + `)`
+		safeExcluded := `(?` + strings.Repeat(", ?", len(excluded)-1) + `)`
+		safeExcludeNodes = ` AND id NOT IN ` + safeExcluded
+		if distinct {
+			safeExcludeNodes += ` AND (node_networks.last_net IS NULL OR node_networks.last_net NOT IN (
+				SELECT last_net FROM node_networks WHERE node_id IN ` + safeExcluded + `))`
+		}
 	}
 	for _, id := range excluded {
 		args = append(args, id.Bytes())
 	}
+	if distinct {
+		for _, id := range excluded {
+			args = append(args, id.Bytes())
+		}
+	}
 	args = append(args, count)
 
-	rows, err := cache.db.Query(cache.db.Rebind(`SELECT id,
-		type, address, free_bandwidth, free_disk, audit_success_ratio,
-		uptime_ratio, total_audit_count, audit_success_count, total_uptime_count,
-		uptime_success_count
+	query := `SELECT ` + selectedNodeColumns + `
 		FROM nodes
-		`+safeQuery+safeExcludeNodes+`
+		` + safeQuery + safeExcludeNodes + `
 		ORDER BY RANDOM()
-		LIMIT ?`), args...)
+		LIMIT ?`
+	if distinct {
+		query, err = cache.distinctNetworkQuery(safeQuery + safeExcludeNodes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := cache.db.Query(cache.db.Rebind(query), args...)
 	if err != nil {
 		return nil, err
 	}
@@ -109,6 +166,40 @@ func (cache *overlaycache) queryFilteredNodes(ctx context.Context, excluded []st
 	return nodes, rows.Err()
 }
 
+// distinctNetworkQuery returns the query selecting random nodes matching
+// safeQuery with one node per network.
+func (cache *overlaycache) distinctNetworkQuery(safeQuery string) (string, error) {
+	switch t := cache.db.DB.Driver().(type) {
+	case *sqlite3.SQLiteDriver:
+		// NB: sqlite takes the bare columns of a group from the row with the
+		// minimum, which is a random row of the network
+		return `SELECT ` + selectedNodeColumns + `
+			FROM (
+				SELECT ` + selectedNodeColumns + `, MIN(random_order)
+				FROM (
+					SELECT ` + selectedNodeColumns + `, node_networks.last_net AS last_net, RANDOM() AS random_order
+					FROM nodes LEFT JOIN node_networks ON node_networks.node_id = nodes.id
+					` + safeQuery + `
+				) shuffled
+				GROUP BY last_net, CASE WHEN last_net IS NULL THEN id END
+			) filtered
+			ORDER BY RANDOM()
+			LIMIT ?`, nil
+	case *pq.Driver:
+		return `SELECT ` + selectedNodeColumns + `
+			FROM (
+				SELECT DISTINCT ON (` + distinctNetwork + `) ` + selectedNodeColumns + `
+				FROM nodes LEFT JOIN node_networks ON node_networks.node_id = nodes.id
+				` + safeQuery + `
+				ORDER BY ` + distinctNetwork + `, RANDOM()
+			) filtered
+			ORDER BY RANDOM()
+			LIMIT ?`, nil
+	default:
+		return "", Error.New("unsupported database %T", t)
+	}
+}
+
 // Get looks up the node by nodeID
 func (cache *overlaycache) Get(ctx context.Context, id storj.NodeID) (*pb.Node, error) {
 	if id.IsZero() {
@@ -248,6 +339,13 @@ func (cache *overlaycache) Update(ctx context.Context, info *pb.Node) (err error
 			dbx.Node_UptimeSuccessCount(reputation.UptimeSuccessCount),
 			dbx.Node_TotalUptimeCount(reputation.UptimeCount),
 			dbx.Node_UptimeRatio(reputation.UptimeRatio),
+
+			dbx.Node_Major(0),
+			dbx.Node_Minor(0),
+			dbx.Node_Patch(0),
+			dbx.Node_Hash(""),
+			dbx.Node_Timestamp(time.Time{}),
+			dbx.Node_Release(false),
 		)
 		if err != nil {
 			return Error.Wrap(errs.Combine(err, tx.Rollback()))
@@ -348,11 +446,18 @@ func (cache *overlaycache) GetStats(ctx context.Context, nodeID storj.NodeID) (s
 		return nil, Error.Wrap(err)
 	}
 
+	reputation, err := getNodeReputation(ctx, cache.db.DB, cache.db.Rebind, nodeID)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
 	nodeStats := getNodeStats(nodeID, dbNode)
+	reputation.apply(nodeStats)
 	return nodeStats, nil
 }
 
 // FindInvalidNodes finds a subset of storagenodes that fail to meet minimum reputation requirements
+// or that were disqualified
 func (cache *overlaycache) FindInvalidNodes(ctx context.Context, nodeIDs storj.NodeIDList, maxStats *overlay.NodeStats) (invalidIDs storj.NodeIDList, err error) {
 	defer mon.Task()(&ctx)(&err)
 
@@ -398,12 +503,16 @@ func (cache *overlaycache) findInvalidNodesQuery(nodeIds storj.NodeIDList, audit
 		nodes.uptime_ratio
 		FROM nodes
 		WHERE nodes.id IN (?`+strings.Repeat(", ?", len(nodeIds)-1)+`)
-		AND nodes.total_audit_count > 0
-		AND nodes.total_uptime_count > 0
-		AND (
-			nodes.audit_success_ratio < ?
-			OR nodes.uptime_ratio < ?
-		)`), args...)
+		AND ((
+			nodes.total_audit_count > 0
+			AND nodes.total_uptime_count > 0
+			AND (
+				nodes.audit_success_ratio < ?
+				OR nodes.uptime_ratio < ?
+			)
+		) OR nodes.id IN (
+			SELECT node_id FROM node_reputations WHERE disqualified IS NOT NULL
+		))`), args...)
 
 	return rows, err
 }
@@ -462,7 +571,24 @@ func (cache *overlaycache) UpdateStats(ctx context.Context, updateReq *overlay.U
 		return nil, Error.Wrap(errs.Combine(err, tx.Rollback()))
 	}
 
+	reputation, err := getNodeReputation(ctx, tx.Tx, cache.db.Rebind, nodeID)
+	if err != nil {
+		return nil, Error.Wrap(errs.Combine(err, tx.Rollback()))
+	}
+	reputation.Audit = updateReq.AuditReputation.Apply(reputation.Audit, updateReq.AuditSuccess)
+	reputation.Uptime = updateReq.UptimeReputation.Apply(reputation.Uptime, updateReq.IsUp)
+	err = reputation.disqualifyByScore(ctx, tx.Tx, cache.db.Rebind, nodeID, updateReq.AuditReputation, updateReq.UptimeReputation, time.Now())
+	if err != nil {
+		return nil, Error.Wrap(errs.Combine(err, tx.Rollback()))
+	}
+
+	err = saveNodeReputation(ctx, tx.Tx, cache.db.Rebind, nodeID, reputation, time.Now())
+	if err != nil {
+		return nil, Error.Wrap(errs.Combine(err, tx.Rollback()))
+	}
+
 	nodeStats := getNodeStats(nodeID, dbNode)
+	reputation.apply(nodeStats)
 	return nodeStats, Error.Wrap(tx.Commit())
 }
 
@@ -490,8 +616,146 @@ func (cache *overlaycache) UpdateOperator(ctx context.Context, nodeID storj.Node
 	return updated, errs.Combine(err, tx.Commit())
 }
 
+// UpdateVersion updates the software version a node reported
+func (cache *overlaycache) UpdateVersion(ctx context.Context, nodeID storj.NodeID, nodeVersion version.Info) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	updateFields := dbx.Node_Update_Fields{
+		Major:     dbx.Node_Major(nodeVersion.Version.Major),
+		Minor:     dbx.Node_Minor(nodeVersion.Version.Minor),
+		Patch:     dbx.Node_Patch(nodeVersion.Version.Patch),
+		Hash:      dbx.Node_Hash(nodeVersion.CommitHash),
+		Timestamp: dbx.Node_Timestamp(nodeVersion.Timestamp),
+		Release:   dbx.Node_Release(nodeVersion.Release),
+	}
+
+	_, err = cache.db.Update_Node_By_Id(ctx, dbx.Node_Id(nodeID.Bytes()), updateFields)
+	return Error.Wrap(err)
+}
+
+// FindOutdatedNodes finds a subset of storagenodes that run a version below the minimum
+func (cache *overlaycache) FindOutdatedNodes(ctx context.Context, nodeIDs storj.NodeIDList, minimum version.SemVer) (outdated storj.NodeIDList, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(nodeIDs) == 0 {
+		return nil, nil
+	}
+
+	args := make([]interface{}, 0, len(nodeIDs)+5)
+	for _, id := range nodeIDs {
+		args = append(args, id.Bytes())
+	}
+	args = append(args, minimum.Major, minimum.Major, minimum.Minor, minimum.Minor, minimum.Patch)
+
+	rows, err := cache.db.Query(cache.db.Rebind(`SELECT id
+		FROM nodes
+		WHERE id IN (?`+strings.Repeat(", ?", len(nodeIDs)-1)+`)
+		AND NOT `+minimumVersionCondition), args...)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	for rows.Next() {
+		var idBytes []byte
+		if err := rows.Scan(&idBytes); err != nil {
+			return nil, Error.Wrap(err)
+		}
+		id, err := storj.NodeIDFromBytes(idBytes)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		outdated = append(outdated, id)
+	}
+
+	return outdated, Error.Wrap(rows.Err())
+}
+
+// UpdateLastIP records the IP the node was last contacted at and its network
+func (cache *overlaycache) UpdateLastIP(ctx context.Context, nodeID storj.NodeID, lastIP, lastNet string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = cache.db.ExecContext(ctx, cache.db.Rebind(`
+		INSERT INTO node_networks (node_id, last_ip, last_net, updated_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (node_id) DO UPDATE SET
+			last_ip = excluded.last_ip,
+			last_net = excluded.last_net,
+			updated_at = excluded.updated_at`),
+		nodeID.Bytes(), lastIP, lastNet, time.Now().UTC(),
+	)
+	return Error.Wrap(err)
+}
+
+// LastNets returns the network of the last IP of every node with a known IP
+func (cache *overlaycache) LastNets(ctx context.Context) (nets map[storj.NodeID]string, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := cache.db.Query(`SELECT node_id, last_net FROM node_networks`)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	nets = make(map[storj.NodeID]string)
+	for rows.Next() {
+		var idBytes []byte
+		var lastNet string
+		if err := rows.Scan(&idBytes, &lastNet); err != nil {
+			return nil, Error.Wrap(err)
+		}
+		id, err := storj.NodeIDFromBytes(idBytes)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		nets[id] = lastNet
+	}
+
+	return nets, Error.Wrap(rows.Err())
+}
+
+// UpdateVetted records that the node passed vetting at vettedAt, it keeps
+// the time of a node that already passed it
+func (cache *overlaycache) UpdateVetted(ctx context.Context, nodeID storj.NodeID, vettedAt time.Time) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = cache.db.ExecContext(ctx, cache.db.Rebind(`
+		UPDATE node_reputations SET vetted_at = ?
+		WHERE node_id = ? AND vetted_at IS NULL`),
+		vettedAt.UTC(), nodeID.Bytes(),
+	)
+	return Error.Wrap(err)
+}
+
+// Reliable returns the nodes that weren't disqualified and whose last contact didn't fail
+func (cache *overlaycache) Reliable(ctx context.Context) (nodes storj.NodeIDList, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := cache.db.Query(cache.db.Rebind(`SELECT id
+		FROM nodes
+		WHERE NOT (last_contact_failure > last_contact_success)
+		AND `+notDisqualifiedCondition))
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	for rows.Next() {
+		var idBytes []byte
+		if err := rows.Scan(&idBytes); err != nil {
+			return nil, Error.Wrap(err)
+		}
+		id, err := storj.NodeIDFromBytes(idBytes)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		nodes = append(nodes, id)
+	}
+
+	return nodes, Error.Wrap(rows.Err())
+}
+
 // UpdateUptime updates a single storagenode's uptime stats in the db
-func (cache *overlaycache) UpdateUptime(ctx context.Context, nodeID storj.NodeID, isUp bool) (stats *overlay.NodeStats, err error) {
+func (cache *overlaycache) UpdateUptime(ctx context.Context, nodeID storj.NodeID, isUp bool, uptimeReputation overlay.ReputationUpdate) (stats *overlay.NodeStats, err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	tx, err := cache.db.Open(ctx)
@@ -530,7 +794,23 @@ func (cache *overlaycache) UpdateUptime(ctx context.Context, nodeID storj.NodeID
 		return nil, Error.Wrap(errs.Combine(err, tx.Rollback()))
 	}
 
+	reputation, err := getNodeReputation(ctx, tx.Tx, cache.db.Rebind, nodeID)
+	if err != nil {
+		return nil, Error.Wrap(errs.Combine(err, tx.Rollback()))
+	}
+	reputation.Uptime = uptimeReputation.Apply(reputation.Uptime, isUp)
+	err = reputation.disqualifyByScore(ctx, tx.Tx, cache.db.Rebind, nodeID, overlay.ReputationUpdate{}, uptimeReputation, time.Now())
+	if err != nil {
+		return nil, Error.Wrap(errs.Combine(err, tx.Rollback()))
+	}
+
+	err = saveNodeReputation(ctx, tx.Tx, cache.db.Rebind, nodeID, reputation, time.Now())
+	if err != nil {
+		return nil, Error.Wrap(errs.Combine(err, tx.Rollback()))
+	}
+
 	nodeStats := getNodeStats(nodeID, dbNode)
+	reputation.apply(nodeStats)
 	return nodeStats, Error.Wrap(tx.Commit())
 }
 
@@ -577,6 +857,130 @@ func (cache *overlaycache) CreateEntryIfNotExists(ctx context.Context, node *pb.
 	return getNodeStats(node.Id, dbNode), nil
 }
 
+// ReputationHistory returns the daily reputation scores of a node since the given time.
+func (cache *overlaycache) ReputationHistory(ctx context.Context, nodeID storj.NodeID, since time.Time) (history []overlay.ReputationScore, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := cache.db.QueryContext(ctx, cache.db.Rebind(`
+		SELECT interval_start, audit_score, uptime_score
+		FROM node_reputation_history
+		WHERE node_id = ? AND interval_start >= ?
+		ORDER BY interval_start`), nodeID.Bytes(), since.UTC())
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	for rows.Next() {
+		score := overlay.ReputationScore{NodeID: nodeID}
+		if err := rows.Scan(&score.IntervalStart, &score.AuditScore, &score.UptimeScore); err != nil {
+			return nil, Error.Wrap(err)
+		}
+		history = append(history, score)
+	}
+	return history, Error.Wrap(rows.Err())
+}
+
+// nodeReputation is the reputation of a node as stored in node_reputations,
+// a node without any audits or uptime checks has the zero reputation.
+type nodeReputation struct {
+	Audit        overlay.Reputation
+	Uptime       overlay.Reputation
+	Disqualified *time.Time
+	VettedAt     *time.Time
+}
+
+// disqualify marks the node as disqualified at now, it returns false when the
+// node already was disqualified.
+func (reputation *nodeReputation) disqualify(now time.Time) bool {
+	if reputation.Disqualified != nil {
+		return false
+	}
+	reputation.Disqualified = &now
+	return true
+}
+
+// disqualifyByScore disqualifies the node when one of its scores is too low,
+// recording the disqualification event.
+func (reputation *nodeReputation) disqualifyByScore(ctx context.Context, tx *sql.Tx, rebind func(string) string, nodeID storj.NodeID, audit, uptime overlay.ReputationUpdate, now time.Time) error {
+	var reason disqualification.Reason
+	var detail string
+	switch {
+	case audit.Disqualifies(reputation.Audit):
+		reason = disqualification.ReasonAuditScore
+		detail = fmt.Sprintf("audit score %v below %v", reputation.Audit.Score(), audit.DQ)
+	case uptime.Disqualifies(reputation.Uptime):
+		reason = disqualification.ReasonUptimeScore
+		detail = fmt.Sprintf("uptime score %v below %v", reputation.Uptime.Score(), uptime.DQ)
+	default:
+		return nil
+	}
+
+	if !reputation.disqualify(now) {
+		return nil
+	}
+	return insertDisqualificationEvent(ctx, tx, rebind, nodeID, reason, detail, now)
+}
+
+// apply copies the reputation into stats.
+func (reputation *nodeReputation) apply(stats *overlay.NodeStats) {
+	stats.AuditReputation = reputation.Audit
+	stats.UptimeReputation = reputation.Uptime
+	stats.Disqualified = reputation.Disqualified
+	stats.VettedAt = reputation.VettedAt
+}
+
+func getNodeReputation(ctx context.Context, db queryer, rebind func(string) string, nodeID storj.NodeID) (reputation nodeReputation, err error) {
+	err = db.QueryRowContext(ctx, rebind(`
+		SELECT audit_alpha, audit_beta, uptime_alpha, uptime_beta, disqualified, vetted_at
+		FROM node_reputations WHERE node_id = ?`), nodeID.Bytes(),
+	).Scan(
+		&reputation.Audit.Alpha, &reputation.Audit.Beta,
+		&reputation.Uptime.Alpha, &reputation.Uptime.Beta,
+		&reputation.Disqualified, &reputation.VettedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nodeReputation{}, nil
+	}
+	return reputation, err
+}
+
+// saveNodeReputation stores the reputation of the node and records its
+// scores for the day of now.
+func saveNodeReputation(ctx context.Context, tx *sql.Tx, rebind func(string) string, nodeID storj.NodeID, reputation nodeReputation, now time.Time) error {
+	now = now.UTC()
+	_, err := tx.ExecContext(ctx, rebind(`
+		INSERT INTO node_reputations (
+			node_id, audit_alpha, audit_beta, uptime_alpha, uptime_beta, disqualified, vetted_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (node_id) DO UPDATE SET
+			audit_alpha = excluded.audit_alpha,
+			audit_beta = excluded.audit_beta,
+			uptime_alpha = excluded.uptime_alpha,
+			uptime_beta = excluded.uptime_beta,
+			disqualified = excluded.disqualified,
+			vetted_at = COALESCE(node_reputations.vetted_at, excluded.vetted_at),
+			updated_at = excluded.updated_at`),
+		nodeID.Bytes(), reputation.Audit.Alpha, reputation.Audit.Beta,
+		reputation.Uptime.Alpha, reputation.Uptime.Beta, reputation.Disqualified, reputation.VettedAt, now,
+	)
+	if err != nil {
+		return err
+	}
+
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	_, err = tx.ExecContext(ctx, rebind(`
+		INSERT INTO node_reputation_history (
+			node_id, interval_start, audit_score, uptime_score
+		) VALUES (?, ?, ?, ?)
+		ON CONFLICT (node_id, interval_start) DO UPDATE SET
+			audit_score = excluded.audit_score,
+			uptime_score = excluded.uptime_score`),
+		nodeID.Bytes(), day, reputation.Audit.Score(), reputation.Uptime.Score(),
+	)
+	return err
+}
+
 func convertDBNode(info *dbx.Node) (*pb.Node, error) {
 	if info == nil {
 		return nil, Error.New("missing info")
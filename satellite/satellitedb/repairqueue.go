@@ -7,98 +7,206 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/lib/pq"
 	sqlite3 "github.com/mattn/go-sqlite3"
+	"github.com/zeebo/errs"
 
+	"storj.io/storj/pkg/datarepair/queue"
 	"storj.io/storj/pkg/pb"
 	dbx "storj.io/storj/satellite/satellitedb/dbx"
 	"storj.io/storj/storage"
 )
 
+// repairLeaseTimeout is how long a selected segment is skipped by Select, a
+// segment whose repair didn't finish in time, e.g. because the satellite
+// restarted, is selected again.
+const repairLeaseTimeout = time.Hour
+
 type repairQueue struct {
 	db *dbx.DB
 }
 
-func (r *repairQueue) Enqueue(ctx context.Context, seg *pb.InjuredSegment) error {
-	val, err := proto.Marshal(seg)
+// Insert adds an injured segment, or updates the health and the lost pieces
+// of a segment that is already queued.
+func (r *repairQueue) Insert(ctx context.Context, seg *pb.InjuredSegment) (alreadyInserted bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	data, err := proto.Marshal(seg)
 	if err != nil {
-		return err
+		return false, Error.Wrap(err)
 	}
 
-	_, err = r.db.Create_Injuredsegment(
-		ctx,
-		dbx.Injuredsegment_Info(val),
-	)
-	return err
+	err = r.db.WithTx(ctx, func(ctx context.Context, tx *dbx.Tx) error {
+		var count int
+		err := tx.Tx.QueryRowContext(ctx, r.db.Rebind(`SELECT COUNT(*) FROM repair_queue WHERE path = ?`),
+			[]byte(seg.Path)).Scan(&count)
+		if err != nil {
+			return err
+		}
+		alreadyInserted = count > 0
+
+		// NB: the attempts and the lease of a queued segment are kept
+		_, err = tx.Tx.ExecContext(ctx, r.db.Rebind(`
+			INSERT INTO repair_queue (
+				path, data, segment_health, attempts, inserted_at
+			) VALUES (?, ?, ?, 0, ?)
+			ON CONFLICT ( path )
+			DO UPDATE SET data = excluded.data, segment_health = excluded.segment_health`),
+			[]byte(seg.Path), data, seg.Health, time.Now().UTC())
+		return err
+	})
+	return alreadyInserted, Error.Wrap(err)
+}
+
+// Select leases the injured segment with the lowest health and counts the
+// attempt, it returns storage.ErrEmptyQueue when no segment is left.
+func (r *repairQueue) Select(ctx context.Context) (seg *pb.InjuredSegment, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	switch t := r.db.DB.Driver().(type) {
+	case *sqlite3.SQLiteDriver:
+		return r.sqliteSelect(ctx)
+	case *pq.Driver:
+		return r.postgresSelect(ctx)
+	default:
+		return nil, fmt.Errorf("Unsupported database %t", t)
+	}
 }
 
-func (r *repairQueue) postgresDequeue(ctx context.Context) (seg pb.InjuredSegment, err error) {
-	err = r.db.DB.QueryRowContext(ctx, `
-	DELETE FROM injuredsegments
-		WHERE id = ( SELECT id FROM injuredsegments ORDER BY id FOR UPDATE SKIP LOCKED LIMIT 1 )
-		RETURNING info
-	`).Scan(&seg)
+func (r *repairQueue) postgresSelect(ctx context.Context) (seg *pb.InjuredSegment, err error) {
+	now := time.Now().UTC()
+	row := r.db.DB.QueryRowContext(ctx, `
+		UPDATE repair_queue SET attempts = attempts + 1, attempted_at = $1
+		WHERE path = (
+			SELECT path FROM repair_queue
+			WHERE (attempted_at IS NULL OR attempted_at < $2)
+				AND (retry_at IS NULL OR retry_at <= $1)
+			ORDER BY segment_health, inserted_at
+			FOR UPDATE SKIP LOCKED LIMIT 1
+		)
+		RETURNING data, attempts, inserted_at`, now, now.Add(-repairLeaseTimeout))
+	seg, err = scanInjuredSegment(row)
 	if err == sql.ErrNoRows {
-		err = storage.ErrEmptyQueue.New("")
+		return nil, storage.ErrEmptyQueue.New("")
 	}
-	return seg, err
+	return seg, Error.Wrap(err)
 }
 
-func (r *repairQueue) sqliteDequeue(ctx context.Context) (seg pb.InjuredSegment, err error) {
+func (r *repairQueue) sqliteSelect(ctx context.Context) (seg *pb.InjuredSegment, err error) {
+	now := time.Now().UTC()
 	err = r.db.WithTx(ctx, func(ctx context.Context, tx *dbx.Tx) error {
-		var id int64
-		err = tx.Tx.QueryRowContext(ctx, `SELECT id, info FROM injuredsegments ORDER BY id LIMIT 1`).Scan(&id, &seg)
+		row := tx.Tx.QueryRowContext(ctx, r.db.Rebind(`
+			SELECT data, attempts, inserted_at FROM repair_queue
+			WHERE (attempted_at IS NULL OR attempted_at < ?)
+				AND (retry_at IS NULL OR retry_at <= ?)
+			ORDER BY segment_health, inserted_at
+			LIMIT 1`), now.Add(-repairLeaseTimeout), now)
+		seg, err = scanInjuredSegment(row)
 		if err != nil {
 			return err
 		}
-		res, err := tx.Tx.ExecContext(ctx, r.db.Rebind(`DELETE FROM injuredsegments WHERE id = ?`), id)
-		if err != nil {
-			return err
-		}
-		count, err := res.RowsAffected()
-		if err != nil {
-			return err
-		}
-		if count != 1 {
-			return fmt.Errorf("Expected 1, got %d segments deleted", count)
-		}
-		return nil
+		seg.Attempts++
+
+		_, err = tx.Tx.ExecContext(ctx, r.db.Rebind(`
+			UPDATE repair_queue SET attempts = ?, attempted_at = ?
+			WHERE path = ?`), seg.Attempts, now, []byte(seg.Path))
+		return err
 	})
 	if err == sql.ErrNoRows {
-		err = storage.ErrEmptyQueue.New("")
+		return nil, storage.ErrEmptyQueue.New("")
 	}
-	return seg, err
+	return seg, Error.Wrap(err)
 }
 
-func (r *repairQueue) Dequeue(ctx context.Context) (seg pb.InjuredSegment, err error) {
-	switch t := r.db.DB.Driver().(type) {
-	case *sqlite3.SQLiteDriver:
-		return r.sqliteDequeue(ctx)
-	case *pq.Driver:
-		return r.postgresDequeue(ctx)
-	default:
-		return seg, fmt.Errorf("Unsupported database %t", t)
-	}
+// Requeue releases the lease of a segment whose repair failed, the segment
+// isn't selected again before delay elapsed.
+func (r *repairQueue) Requeue(ctx context.Context, seg *pb.InjuredSegment, delay time.Duration) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = r.db.DB.ExecContext(ctx, r.db.Rebind(`
+		UPDATE repair_queue SET attempted_at = NULL, retry_at = ? WHERE path = ?`),
+		time.Now().UTC().Add(delay), []byte(seg.Path))
+	return Error.Wrap(err)
+}
+
+// Delete removes a repaired segment.
+func (r *repairQueue) Delete(ctx context.Context, seg *pb.InjuredSegment) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = r.db.DB.ExecContext(ctx, r.db.Rebind(`DELETE FROM repair_queue WHERE path = ?`), []byte(seg.Path))
+	return Error.Wrap(err)
 }
 
-func (r *repairQueue) Peekqueue(ctx context.Context, limit int) ([]pb.InjuredSegment, error) {
+// SelectN lists limit injured segments, the least healthy first.
+func (r *repairQueue) SelectN(ctx context.Context, limit int) (segs []pb.InjuredSegment, err error) {
+	defer mon.Task()(&ctx)(&err)
+
 	if limit <= 0 || limit > storage.LookupLimit {
 		limit = storage.LookupLimit
 	}
-	rows, err := r.db.Limited_Injuredsegment(ctx, limit, 0)
+	rows, err := r.db.DB.QueryContext(ctx, r.db.Rebind(`
+		SELECT data, attempts, inserted_at FROM repair_queue
+		ORDER BY segment_health, inserted_at
+		LIMIT ?`), limit)
 	if err != nil {
-		return nil, err
+		return nil, Error.Wrap(err)
 	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
 
-	segments := make([]pb.InjuredSegment, 0)
-	for _, entry := range rows {
-		seg := &pb.InjuredSegment{}
-		if err = proto.Unmarshal(entry.Info, seg); err != nil {
-			return nil, err
+	for rows.Next() {
+		seg, err := scanInjuredSegment(rows)
+		if err != nil {
+			return nil, Error.Wrap(err)
 		}
-		segments = append(segments, *seg)
+		segs = append(segs, *seg)
+	}
+	return segs, Error.Wrap(rows.Err())
+}
+
+// Stats returns the number of queued segments and the age of the oldest.
+func (r *repairQueue) Stats(ctx context.Context) (stats queue.Stats, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = r.db.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM repair_queue`).Scan(&stats.Count)
+	if err != nil || stats.Count == 0 {
+		return stats, Error.Wrap(err)
 	}
-	return segments, nil
+
+	err = r.db.DB.QueryRowContext(ctx, `SELECT inserted_at FROM repair_queue ORDER BY inserted_at LIMIT 1`).Scan(&stats.Oldest)
+	if err == sql.ErrNoRows {
+		// the queue was emptied in the meantime
+		return queue.Stats{}, nil
+	}
+	stats.Oldest = stats.Oldest.UTC()
+	return stats, Error.Wrap(err)
+}
+
+// rowScanner is a *sql.Row or *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanInjuredSegment scans the data, attempts and inserted_at columns of a
+// queued segment.
+func scanInjuredSegment(row rowScanner) (*pb.InjuredSegment, error) {
+	var data []byte
+	var attempts int32
+	var insertedAt time.Time
+	if err := row.Scan(&data, &attempts, &insertedAt); err != nil {
+		return nil, err
+	}
+
+	seg := &pb.InjuredSegment{}
+	if err := proto.Unmarshal(data, seg); err != nil {
+		return nil, err
+	}
+	seg.Attempts = attempts
+
+	var err error
+	seg.InsertedAt, err = ptypes.TimestampProto(insertedAt)
+	return seg, err
 }
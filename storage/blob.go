@@ -6,6 +6,7 @@ package storage
 import (
 	"context"
 	"io"
+	"time"
 
 	"github.com/zeebo/errs"
 )
@@ -24,6 +25,28 @@ func (ref *BlobRef) IsValid() bool {
 	return len(ref.Namespace) > 0 && len(ref.Key) > 0
 }
 
+// FormatVersion is the storage format of a blob.
+type FormatVersion int
+
+const (
+	// FormatV0 is the format of the blobs which contain only their data.
+	FormatV0 FormatVersion = 0
+	// FormatV1 is the format of the blobs which start with a header, written
+	// by the user of the blob.
+	FormatV1 FormatVersion = 1
+)
+
+// BlobInfo describes a stored blob.
+type BlobInfo struct {
+	Ref    BlobRef
+	Format FormatVersion
+	// Size and Allocated are the size of the blob file and the disk space
+	// allocated to it, which is less than the size when the file is sparse.
+	// They're only set by Walk.
+	Size      int64
+	Allocated int64
+}
+
 // BlobReader is an interface that groups Read, ReadAt, Seek and Close.
 type BlobReader interface {
 	io.Reader
@@ -32,28 +55,45 @@ type BlobReader interface {
 	io.Closer
 	// Size returns the size of the blob
 	Size() (int64, error)
+	// StorageFormatVersion returns the storage format of the blob
+	StorageFormatVersion() FormatVersion
 }
 
-// BlobWriter is an interface that groups Read, ReadAt, Seek and Close.
+// BlobWriter is an interface that groups Write, WriteAt, Cancel and Commit.
 type BlobWriter interface {
 	io.Writer
+	// WriterAt writes at an offset without moving the position of Write,
+	// so that a header can be written once the data is known.
+	io.WriterAt
 	// Cancel discards the blob.
 	Cancel() error
 	// Commit ensures that the blob is readable by others.
 	Commit() error
 	// Size returns the size of the blob
 	Size() (int64, error)
+	// StorageFormatVersion returns the storage format of the blob
+	StorageFormatVersion() FormatVersion
 }
 
 // Blobs is a blob storage interface
 type Blobs interface {
-	// Create creates a new blob that can be written
+	// Create creates a new blob of the format that can be written
 	// optionally takes a size argument for performance improvements, -1 is unknown size
-	Create(ctx context.Context, ref BlobRef, size int64) (BlobWriter, error)
-	// Open opens a reader with the specified namespace and key
+	Create(ctx context.Context, ref BlobRef, format FormatVersion, size int64) (BlobWriter, error)
+	// Open opens a reader with the specified namespace and key, whatever its format
 	Open(ctx context.Context, ref BlobRef) (BlobReader, error)
-	// Delete deletes the blob with the namespace and key
+	// Delete deletes the blob with the namespace and key, in every format
 	Delete(ctx context.Context, ref BlobRef) error
+	// Trash moves the blob with the namespace and key, in every format, to the trash
+	Trash(ctx context.Context, ref BlobRef) error
+	// RestoreTrash moves the trashed blobs of the namespace back, returning the restored blobs
+	RestoreTrash(ctx context.Context, namespace []byte) ([]BlobInfo, error)
+	// EmptyTrash deletes the blobs trashed before trashedBefore, returning their total size
+	EmptyTrash(ctx context.Context, trashedBefore time.Time) (int64, error)
+	// TrashSize returns the total size of the trashed blobs
+	TrashSize(ctx context.Context) (int64, error)
+	// Walk calls fn for every stored blob, stopping at the first error
+	Walk(ctx context.Context, fn func(BlobInfo) error) error
 	// FreeSpace return how much free space left for writing
 	FreeSpace() (int64, error)
 }
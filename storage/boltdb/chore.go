@@ -0,0 +1,100 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package boltdb
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/internal/sync2"
+	"storj.io/storj/storage"
+)
+
+// ChoreConfig defines the online compaction of the bolt databases.
+type ChoreConfig struct {
+	Interval  time.Duration `help:"how frequently the free pages of the bolt databases are reported and checked" default:"24h0m0s"`
+	FreePages int           `help:"number of free pages above which a bolt database is compacted, 0 disables the compaction" default:"25600"`
+}
+
+// Chore reports the statistics of the bolt databases on every interval and
+// compacts the databases with too many free pages.
+type Chore struct {
+	log     *zap.Logger
+	config  ChoreConfig
+	clients []*Client
+
+	Loop sync2.Cycle
+}
+
+// NewChore creates a chore for the bolt databases of stores, the stores of
+// other kinds are ignored. The clients sharing a database are checked once.
+func NewChore(log *zap.Logger, config ChoreConfig, stores ...storage.KeyValueStore) *Chore {
+	chore := &Chore{
+		log:    log,
+		config: config,
+
+		Loop: *sync2.NewCycle(config.Interval),
+	}
+
+	for _, store := range stores {
+		client, ok := store.(*Client)
+		if !ok {
+			continue
+		}
+		shared := false
+		for _, added := range chore.clients {
+			if added.db == client.db {
+				shared = true
+				break
+			}
+		}
+		if !shared {
+			chore.clients = append(chore.clients, client)
+		}
+	}
+	return chore
+}
+
+// Run checks the databases on every interval.
+func (chore *Chore) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return chore.Loop.Run(ctx, func(ctx context.Context) error {
+		chore.check(ctx)
+		return nil
+	})
+}
+
+// check reports the statistics of every database and compacts the databases
+// whose free pages exceed the threshold, the failures are only logged.
+func (chore *Chore) check(ctx context.Context) {
+	for _, client := range chore.clients {
+		if ctx.Err() != nil {
+			return
+		}
+
+		stats, err := client.Stats()
+		if err != nil {
+			chore.log.Error("reading the statistics of the bolt database failed", zap.String("path", client.Path), zap.Error(err))
+			continue
+		}
+		if chore.config.FreePages <= 0 || stats.FreePages <= chore.config.FreePages {
+			continue
+		}
+
+		chore.log.Info("compacting the bolt database", zap.String("path", client.Path),
+			zap.Int64("file size", stats.FileSize), zap.Int("free pages", stats.FreePages))
+		if err := client.Compact(); err != nil {
+			chore.log.Error("compacting the bolt database failed", zap.String("path", client.Path), zap.Error(err))
+		}
+	}
+}
+
+// Close stops the chore.
+func (chore *Chore) Close() error {
+	chore.Loop.Close()
+	return nil
+}
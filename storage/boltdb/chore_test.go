@@ -0,0 +1,69 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package boltdb
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/teststore"
+)
+
+func TestChore(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	tempdir, err := ioutil.TempDir("", "storj-bolt")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(tempdir) }()
+
+	stores, err := NewShared(filepath.Join(tempdir, "bolt.db"), "alpha", "beta")
+	require.NoError(t, err)
+	defer func() {
+		for _, store := range stores {
+			assert.NoError(t, store.Close())
+		}
+	}()
+
+	value := make(storage.Value, 1024)
+	for i := 0; i < 1000; i++ {
+		key := storage.Key(fmt.Sprintf("key-%04d", i))
+		require.NoError(t, stores[0].Put(key, value))
+	}
+	for i := 0; i < 1000; i++ {
+		key := storage.Key(fmt.Sprintf("key-%04d", i))
+		require.NoError(t, stores[0].Delete(key))
+	}
+
+	before, err := stores[0].Stats()
+	require.NoError(t, err)
+	require.NotZero(t, before.FreePages)
+
+	// the shared databases are checked once and the other stores are skipped
+	chore := NewChore(zaptest.NewLogger(t), ChoreConfig{Interval: time.Hour, FreePages: before.FreePages + 1},
+		stores[0], stores[1], teststore.New())
+	require.Len(t, chore.clients, 1)
+
+	// below the threshold the database isn't compacted
+	chore.check(ctx)
+	stats, err := stores[0].Stats()
+	require.NoError(t, err)
+	assert.Equal(t, before.FileSize, stats.FileSize)
+
+	chore.config.FreePages = before.FreePages - 1
+	chore.check(ctx)
+	stats, err = stores[1].Stats()
+	require.NoError(t, err)
+	assert.True(t, stats.FileSize < before.FileSize)
+}
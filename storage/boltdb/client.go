@@ -5,6 +5,7 @@ package boltdb
 
 import (
 	"bytes"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -19,13 +20,21 @@ var Error = errs.Class("boltdb error")
 
 // Client is the entrypoint into a bolt data store
 type Client struct {
-	db     *bolt.DB
+	db     *database
 	Path   string
 	Bucket []byte
 
 	referenceCount *int32
 }
 
+// database is the bolt database shared by the clients of its buckets.
+// The bolt database is replaced when it's compacted online, hence the
+// access to it is guarded by the mutex.
+type database struct {
+	mu sync.RWMutex
+	db *bolt.DB
+}
+
 const (
 	// fileMode sets permissions so owner can read and write
 	fileMode       = 0600
@@ -54,7 +63,7 @@ func New(path, bucket string) (*Client, error) {
 	*refCount = 1
 
 	return &Client{
-		db:             db,
+		db:             &database{db: db},
 		referenceCount: refCount,
 		Path:           path,
 		Bucket:         []byte(bucket),
@@ -87,10 +96,12 @@ func NewShared(path string, buckets ...string) ([]*Client, error) {
 	refCount := new(int32)
 	*refCount = int32(len(buckets))
 
+	shared := &database{db: db}
+
 	clients := []*Client{}
 	for _, bucket := range buckets {
 		clients = append(clients, &Client{
-			db:             db,
+			db:             shared,
 			referenceCount: refCount,
 			Path:           path,
 			Bucket:         []byte(bucket),
@@ -101,13 +112,19 @@ func NewShared(path string, buckets ...string) ([]*Client, error) {
 }
 
 func (client *Client) update(fn func(*bolt.Bucket) error) error {
-	return Error.Wrap(client.db.Update(func(tx *bolt.Tx) error {
+	client.db.mu.RLock()
+	defer client.db.mu.RUnlock()
+
+	return Error.Wrap(client.db.db.Update(func(tx *bolt.Tx) error {
 		return fn(tx.Bucket(client.Bucket))
 	}))
 }
 
 func (client *Client) view(fn func(*bolt.Bucket) error) error {
-	return Error.Wrap(client.db.View(func(tx *bolt.Tx) error {
+	client.db.mu.RLock()
+	defer client.db.mu.RUnlock()
+
+	return Error.Wrap(client.db.db.View(func(tx *bolt.Tx) error {
 		return fn(tx.Bucket(client.Bucket))
 	}))
 }
@@ -152,6 +169,35 @@ func (client *Client) Delete(key storage.Key) error {
 	})
 }
 
+// CompareAndSwap atomically compares and swaps oldValue with newValue
+func (client *Client) CompareAndSwap(key storage.Key, oldValue, newValue storage.Value) error {
+	if key.IsZero() {
+		return storage.ErrEmptyKey.New("")
+	}
+
+	return client.update(func(bucket *bolt.Bucket) error {
+		data := bucket.Get([]byte(key))
+		if data == nil {
+			if oldValue != nil {
+				return storage.ErrKeyNotFound.New(key.String())
+			}
+			if newValue == nil {
+				return nil
+			}
+			return bucket.Put(key, newValue)
+		}
+
+		if oldValue == nil || !bytes.Equal(storage.Value(data), oldValue) {
+			return storage.ErrValueChanged.New(key.String())
+		}
+
+		if newValue == nil {
+			return bucket.Delete(key)
+		}
+		return bucket.Put(key, newValue)
+	})
+}
+
 // List returns either a list of keys for which boltdb has values or an error.
 func (client *Client) List(first storage.Key, limit int) (storage.Keys, error) {
 	rv, err := storage.ListKeys(client, first, limit)
@@ -161,7 +207,10 @@ func (client *Client) List(first storage.Key, limit int) (storage.Keys, error) {
 // Close closes a BoltDB client
 func (client *Client) Close() error {
 	if atomic.AddInt32(client.referenceCount, -1) == 0 {
-		return Error.Wrap(client.db.Close())
+		client.db.mu.Lock()
+		defer client.db.mu.Unlock()
+
+		return Error.Wrap(client.db.db.Close())
 	}
 	return nil
 }
@@ -188,7 +237,8 @@ func (client *Client) GetAll(keys storage.Keys) (storage.Values, error) {
 	return vals, err
 }
 
-// Iterate iterates over items based on opts
+// Iterate iterates over items based on opts. The iteration runs in a single
+// read transaction, so it always sees a snapshot of the database.
 func (client *Client) Iterate(opts storage.IterateOptions, fn func(storage.Iterator) error) error {
 	return client.view(func(bucket *bolt.Bucket) error {
 		var cursor advancer
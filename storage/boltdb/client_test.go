@@ -90,9 +90,9 @@ type boltLongBenchmarkStore struct {
 
 func (store *boltLongBenchmarkStore) BulkImport(iter storage.Iterator) (err error) {
 	// turn off syncing during import
-	oldval := store.db.NoSync
-	store.db.NoSync = true
-	defer func() { store.db.NoSync = oldval }()
+	oldval := store.db.db.NoSync
+	store.db.db.NoSync = true
+	defer func() { store.db.db.NoSync = oldval }()
 
 	var item storage.ListItem
 	for iter.Next(&item) {
@@ -101,7 +101,7 @@ func (store *boltLongBenchmarkStore) BulkImport(iter storage.Iterator) (err erro
 		}
 	}
 
-	return store.db.Sync()
+	return store.db.db.Sync()
 }
 
 func (store *boltLongBenchmarkStore) BulkDelete() error {
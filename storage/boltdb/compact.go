@@ -0,0 +1,250 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package boltdb
+
+import (
+	"os"
+
+	"github.com/boltdb/bolt"
+	"github.com/zeebo/errs"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+)
+
+var mon = monkit.Package()
+
+// compactTxMaxSize is the amount of data copied in a single transaction while compacting
+const compactTxMaxSize = 64 << 20
+
+// Stats contains the size and the free page statistics of a bolt database
+type Stats struct {
+	// FileSize is the size of the database file
+	FileSize int64
+	// FreePages is the number of free pages
+	FreePages int
+	// PendingPages is the number of pages that will become free once the
+	// transactions using them are closed
+	PendingPages int
+	// FreeBytes is the number of bytes allocated in free pages
+	FreeBytes int
+}
+
+// Stats returns the size and the free page statistics of the database
+func (client *Client) Stats() (Stats, error) {
+	client.db.mu.RLock()
+	defer client.db.mu.RUnlock()
+
+	return stats(client.db.db)
+}
+
+func stats(db *bolt.DB) (Stats, error) {
+	info, err := os.Stat(db.Path())
+	if err != nil {
+		return Stats{}, Error.Wrap(err)
+	}
+
+	dbStats := db.Stats()
+	stats := Stats{
+		FileSize:     info.Size(),
+		FreePages:    dbStats.FreePageN,
+		PendingPages: dbStats.PendingPageN,
+		FreeBytes:    dbStats.FreeAlloc,
+	}
+
+	mon.IntVal("file_size").Observe(stats.FileSize)
+	mon.IntVal("free_pages").Observe(int64(stats.FreePages))
+	mon.IntVal("pending_pages").Observe(int64(stats.PendingPages))
+	mon.IntVal("free_bytes").Observe(int64(stats.FreeBytes))
+
+	return stats, nil
+}
+
+// Compact rewrites the database file of the client without the free pages,
+// so the file shrinks to the size of the stored data. The database is
+// replaced for all the clients sharing it. Reads and writes are blocked
+// while the database is being compacted.
+func (client *Client) Compact() (err error) {
+	client.db.mu.Lock()
+	defer client.db.mu.Unlock()
+
+	before, err := stats(client.db.db)
+	if err != nil {
+		return err
+	}
+
+	path := client.db.db.Path()
+	compacted := path + ".compact"
+
+	// remove the leftovers of an interrupted compaction
+	if err := os.Remove(compacted); err != nil && !os.IsNotExist(err) {
+		return Error.Wrap(err)
+	}
+
+	dst, err := bolt.Open(compacted, fileMode, &bolt.Options{Timeout: defaultTimeout})
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	err = compact(dst, client.db.db)
+	if err != nil {
+		return errs.Combine(err, Error.Wrap(dst.Close()), Error.Wrap(os.Remove(compacted)))
+	}
+
+	err = Error.Wrap(dst.Close())
+	if err != nil {
+		return errs.Combine(err, Error.Wrap(os.Remove(compacted)))
+	}
+
+	err = Error.Wrap(client.db.db.Close())
+	if err != nil {
+		return errs.Combine(err, Error.Wrap(os.Remove(compacted)))
+	}
+
+	// when the compacted database can't replace the original, the original is reopened
+	err = Error.Wrap(os.Rename(compacted, path))
+	if err != nil {
+		err = errs.Combine(err, Error.Wrap(os.Remove(compacted)))
+	}
+
+	db, openErr := bolt.Open(path, fileMode, &bolt.Options{Timeout: defaultTimeout})
+	if openErr != nil {
+		return errs.Combine(err, Error.Wrap(openErr))
+	}
+	client.db.db = db
+
+	if err != nil {
+		return err
+	}
+
+	after, err := stats(db)
+	if err != nil {
+		return err
+	}
+	mon.IntVal("compaction_reclaimed_bytes").Observe(before.FileSize - after.FileSize)
+
+	return nil
+}
+
+// Compact copies all the buckets of the bolt database at src into a new
+// database at dst, without the free pages of src. The database at src must
+// not be in use.
+func Compact(src, dst string) (err error) {
+	if _, err := os.Stat(src); err != nil {
+		return Error.Wrap(err)
+	}
+
+	srcDB, err := bolt.Open(src, fileMode, &bolt.Options{Timeout: defaultTimeout, ReadOnly: true})
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, Error.Wrap(srcDB.Close())) }()
+
+	dstDB, err := bolt.Open(dst, fileMode, &bolt.Options{Timeout: defaultTimeout})
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, Error.Wrap(dstDB.Close())) }()
+
+	return compact(dstDB, srcDB)
+}
+
+// compact copies all the buckets of src into dst, committing the copied data
+// after every compactTxMaxSize bytes.
+func compact(dst, src *bolt.DB) error {
+	tx, err := dst.Begin(true)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() {
+		if tx != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var size int64
+	err = src.View(func(srcTx *bolt.Tx) error {
+		return walk(srcTx, func(keys [][]byte, k, v []byte, seq uint64) error {
+			// commit the transaction when it gets too large
+			size += int64(len(k) + len(v))
+			if size > compactTxMaxSize {
+				err := tx.Commit()
+				tx = nil
+				if err != nil {
+					return err
+				}
+				tx, err = dst.Begin(true)
+				if err != nil {
+					return err
+				}
+				size = 0
+			}
+
+			// create the bucket on the root level
+			if len(keys) == 0 {
+				bucket, err := tx.CreateBucket(k)
+				if err != nil {
+					return err
+				}
+				bucket.FillPercent = 1.0
+				return bucket.SetSequence(seq)
+			}
+
+			// find the parent bucket
+			parent := tx.Bucket(keys[0])
+			for _, key := range keys[1:] {
+				parent = parent.Bucket(key)
+			}
+			parent.FillPercent = 1.0
+
+			// create a nested bucket
+			if v == nil {
+				bucket, err := parent.CreateBucket(k)
+				if err != nil {
+					return err
+				}
+				bucket.FillPercent = 1.0
+				return bucket.SetSequence(seq)
+			}
+
+			return parent.Put(k, v)
+		})
+	})
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	err = tx.Commit()
+	tx = nil
+	return Error.Wrap(err)
+}
+
+// walkFunc is called for every bucket and key-value pair, with the keys of
+// the parent buckets. v is nil for buckets.
+type walkFunc func(keys [][]byte, k, v []byte, seq uint64) error
+
+// walk walks all the buckets and the key-value pairs of the transaction
+func walk(tx *bolt.Tx, fn walkFunc) error {
+	return tx.ForEach(func(name []byte, bucket *bolt.Bucket) error {
+		return walkBucket(bucket, nil, name, nil, bucket.Sequence(), fn)
+	})
+}
+
+func walkBucket(bucket *bolt.Bucket, keys [][]byte, k, v []byte, seq uint64, fn walkFunc) error {
+	if err := fn(keys, k, v, seq); err != nil {
+		return err
+	}
+
+	// key-value pairs have no nested items
+	if v != nil {
+		return nil
+	}
+
+	keys = append(keys, k)
+	return bucket.ForEach(func(k, v []byte) error {
+		if v == nil {
+			nested := bucket.Bucket(k)
+			return walkBucket(nested, keys, k, nil, nested.Sequence(), fn)
+		}
+		return walkBucket(bucket, keys, k, v, 0, fn)
+	})
+}
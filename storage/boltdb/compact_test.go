@@ -0,0 +1,117 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package boltdb
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/boltdb/bolt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/storage"
+)
+
+func TestCompactOnline(t *testing.T) {
+	tempdir, err := ioutil.TempDir("", "storj-bolt")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(tempdir) }()
+
+	stores, err := NewShared(filepath.Join(tempdir, "bolt.db"), "alpha", "beta")
+	require.NoError(t, err)
+	defer func() {
+		for _, store := range stores {
+			assert.NoError(t, store.Close())
+		}
+	}()
+
+	value := make(storage.Value, 1024)
+	for i := 0; i < 1000; i++ {
+		key := storage.Key(fmt.Sprintf("key-%04d", i))
+		for _, store := range stores {
+			require.NoError(t, store.Put(key, value))
+		}
+	}
+	for i := 10; i < 1000; i++ {
+		key := storage.Key(fmt.Sprintf("key-%04d", i))
+		for _, store := range stores {
+			require.NoError(t, store.Delete(key))
+		}
+	}
+
+	before, err := stores[0].Stats()
+	require.NoError(t, err)
+	assert.NotZero(t, before.FreePages)
+
+	require.NoError(t, stores[0].Compact())
+
+	after, err := stores[1].Stats()
+	require.NoError(t, err)
+	assert.True(t, after.FileSize < before.FileSize)
+
+	for _, store := range stores {
+		keys, err := store.List(nil, 0)
+		require.NoError(t, err)
+		assert.Len(t, keys, 10)
+
+		require.NoError(t, store.Put(storage.Key("new"), value))
+		got, err := store.Get(storage.Key("new"))
+		require.NoError(t, err)
+		assert.Equal(t, value, got)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	tempdir, err := ioutil.TempDir("", "storj-bolt")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(tempdir) }()
+
+	src := filepath.Join(tempdir, "src.db")
+	dst := filepath.Join(tempdir, "dst.db")
+
+	db, err := bolt.Open(src, fileMode, nil)
+	require.NoError(t, err)
+	require.NoError(t, db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucket([]byte("bucket"))
+		if err != nil {
+			return err
+		}
+		if err := bucket.SetSequence(5); err != nil {
+			return err
+		}
+		if err := bucket.Put([]byte("key"), []byte("value")); err != nil {
+			return err
+		}
+		nested, err := bucket.CreateBucket([]byte("nested"))
+		if err != nil {
+			return err
+		}
+		return nested.Put([]byte("nested-key"), []byte("nested-value"))
+	}))
+	require.NoError(t, db.Close())
+
+	require.NoError(t, Compact(src, dst))
+
+	db, err = bolt.Open(dst, fileMode, nil)
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, db.Close()) }()
+
+	require.NoError(t, db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte("bucket"))
+		require.NotNil(t, bucket)
+		assert.Equal(t, uint64(5), bucket.Sequence())
+		assert.Equal(t, []byte("value"), bucket.Get([]byte("key")))
+
+		nested := bucket.Bucket([]byte("nested"))
+		require.NotNil(t, nested)
+		assert.Equal(t, []byte("nested-value"), nested.Get([]byte("nested-key")))
+		return nil
+	}))
+
+	assert.Error(t, Compact(filepath.Join(tempdir, "missing.db"), dst))
+}
@@ -22,6 +22,9 @@ var ErrEmptyKey = errs.Class("empty key")
 // ErrEmptyQueue is returned when attempting to Dequeue from an empty queue
 var ErrEmptyQueue = errs.Class("empty queue")
 
+// ErrValueChanged is returned when the current value of the key does not match the oldValue in CompareAndSwap
+var ErrValueChanged = errs.Class("value changed")
+
 // ErrLimitExceeded is returned when request limit is exceeded
 var ErrLimitExceeded = errors.New("limit exceeded")
 
@@ -60,6 +63,11 @@ type KeyValueStore interface {
 	GetAll(Keys) (Values, error)
 	// Delete deletes key and the value
 	Delete(Key) error
+	// CompareAndSwap atomically replaces the value of key by newValue when
+	// its current value is oldValue, a nil oldValue means that the key
+	// doesn't exist yet and a nil newValue deletes the key. It returns
+	// ErrValueChanged when the current value doesn't match oldValue.
+	CompareAndSwap(key Key, oldValue, newValue Value) error
 	// List lists all keys starting from start and upto limit items
 	List(start Key, limit int) (Keys, error)
 	// Iterate iterates over items based on opts
@@ -90,6 +98,10 @@ type IterateOptions struct {
 	Recurse bool
 	// Reverse iterates in reverse order
 	Reverse bool
+	// Snapshot iterates over a consistent view of the store, which doesn't
+	// include the changes made after the iteration started. Stores which
+	// can't provide such a view return an error.
+	Snapshot bool
 }
 
 // Iterator iterates over a sequence of ListItems
@@ -15,10 +15,11 @@ import (
 // blobReader implements reading blobs
 type blobReader struct {
 	*os.File
+	format storage.FormatVersion
 }
 
-func newBlobReader(file *os.File) *blobReader {
-	return &blobReader{file}
+func newBlobReader(file *os.File, format storage.FormatVersion) *blobReader {
+	return &blobReader{file, format}
 }
 
 // Size returns how large is the blob.
@@ -30,17 +31,23 @@ func (blob *blobReader) Size() (int64, error) {
 	return stat.Size(), err
 }
 
+// StorageFormatVersion returns the storage format of the blob.
+func (blob *blobReader) StorageFormatVersion() storage.FormatVersion {
+	return blob.format
+}
+
 // blobWriter implements writing blobs
 type blobWriter struct {
 	ref    storage.BlobRef
+	format storage.FormatVersion
 	store  *Store
 	closed bool
 
 	*os.File
 }
 
-func newBlobWriter(ref storage.BlobRef, store *Store, file *os.File) *blobWriter {
-	return &blobWriter{ref, store, false, file}
+func newBlobWriter(ref storage.BlobRef, format storage.FormatVersion, store *Store, file *os.File) *blobWriter {
+	return &blobWriter{ref, format, store, false, file}
 }
 
 // Cancel discards the blob.
@@ -60,7 +67,7 @@ func (blob *blobWriter) Commit() error {
 		return Error.New("already closed")
 	}
 	blob.closed = true
-	err := blob.store.dir.Commit(blob.File, blob.ref)
+	err := blob.store.dir.Commit(blob.File, blob.ref, blob.format)
 	return Error.Wrap(err)
 }
 
@@ -72,3 +79,8 @@ func (blob *blobWriter) Size() (int64, error) {
 	}
 	return pos, err
 }
+
+// StorageFormatVersion returns the storage format of the blob.
+func (blob *blobWriter) StorageFormatVersion() storage.FormatVersion {
+	return blob.format
+}
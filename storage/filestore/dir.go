@@ -10,7 +10,9 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/zeebo/errs"
 
@@ -24,9 +26,27 @@ const (
 
 var pathEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
 
+// v1Extension is the extension of the blob files of format V1, the files of
+// format V0 have no extension.
+const v1Extension = ".sj1"
+
+// formats lists the blob formats, the newest first, in the order they are
+// looked up.
+var formats = []storage.FormatVersion{storage.FormatV1, storage.FormatV0}
+
+// Config configures the blob directories.
+type Config struct {
+	// Preallocate allocates the disk space of the blobs of known size when
+	// they are created, which reduces the fragmentation on filesystems like
+	// ext4 or NTFS. Copy-on-write filesystems like ZFS or btrfs gain nothing
+	// from it, the files are then only extended.
+	Preallocate bool
+}
+
 // Dir represents single folder for storing blobs
 type Dir struct {
-	path string
+	path   string
+	config Config
 
 	mu          sync.Mutex
 	deleteQueue []string
@@ -34,13 +54,20 @@ type Dir struct {
 
 // NewDir returns folder for storing blobs
 func NewDir(path string) (*Dir, error) {
+	return NewDirConfig(path, Config{})
+}
+
+// NewDirConfig returns folder for storing blobs configured by config
+func NewDirConfig(path string, config Config) (*Dir, error) {
 	dir := &Dir{
-		path: path,
+		path:   path,
+		config: config,
 	}
 
 	return dir, errs.Combine(
 		os.MkdirAll(dir.blobdir(), dirPermission),
 		os.MkdirAll(dir.tempdir(), dirPermission),
+		os.MkdirAll(dir.garbagedir(), dirPermission),
 		os.MkdirAll(dir.trashdir(), dirPermission),
 	)
 }
@@ -48,12 +75,14 @@ func NewDir(path string) (*Dir, error) {
 // Path returns the directory path
 func (dir *Dir) Path() string { return dir.path }
 
-func (dir *Dir) blobdir() string  { return filepath.Join(dir.path, "blob") }
-func (dir *Dir) tempdir() string  { return filepath.Join(dir.path, "tmp") }
-func (dir *Dir) trashdir() string { return filepath.Join(dir.path, "trash") }
+func (dir *Dir) blobdir() string    { return filepath.Join(dir.path, "blob") }
+func (dir *Dir) tempdir() string    { return filepath.Join(dir.path, "tmp") }
+func (dir *Dir) garbagedir() string { return filepath.Join(dir.path, "garbage") }
+func (dir *Dir) trashdir() string   { return filepath.Join(dir.path, "trash") }
 
 // CreateTemporaryFile creates a preallocated temporary file in the temp directory
-// prealloc preallocates file to make writing faster
+// prealloc preallocates file to make writing faster, the disk space is only
+// allocated when the dir is configured to, otherwise the file is sparse
 func (dir *Dir) CreateTemporaryFile(prealloc int64) (*os.File, error) {
 	const preallocLimit = 5 << 20 // 5 MB
 	if prealloc > preallocLimit {
@@ -66,8 +95,13 @@ func (dir *Dir) CreateTemporaryFile(prealloc int64) (*os.File, error) {
 	}
 
 	if prealloc >= 0 {
-		if err := file.Truncate(prealloc); err != nil {
-			return nil, errs.Combine(err, file.Close())
+		if dir.config.Preallocate {
+			err = preallocate(file, prealloc)
+		} else {
+			err = file.Truncate(prealloc)
+		}
+		if err != nil {
+			return nil, errs.Combine(err, file.Close(), os.Remove(file.Name()))
 		}
 	}
 	return file, nil
@@ -80,7 +114,18 @@ func (dir *Dir) DeleteTemporary(file *os.File) error {
 }
 
 // blobToPath converts blob reference to a filepath in permanent storage
-func (dir *Dir) blobToPath(ref storage.BlobRef) (string, error) {
+func (dir *Dir) blobToPath(ref storage.BlobRef, format storage.FormatVersion) (string, error) {
+	return refToPath(dir.blobdir(), ref, format)
+}
+
+// blobToTrashPath converts blob reference to a filepath in the trash of its namespace
+func (dir *Dir) blobToTrashPath(ref storage.BlobRef, format storage.FormatVersion) (string, error) {
+	return refToPath(dir.trashdir(), ref, format)
+}
+
+// refToPath converts blob reference to a filepath in root, where the blobs
+// are grouped by namespace and by the first characters of their key
+func refToPath(root string, ref storage.BlobRef, format storage.FormatVersion) (string, error) {
 	if !ref.IsValid() {
 		return "", storage.ErrInvalidBlobRef.New("")
 	}
@@ -91,20 +136,64 @@ func (dir *Dir) blobToPath(ref storage.BlobRef) (string, error) {
 		// ensure we always have at least
 		key = "11" + key
 	}
-	return filepath.Join(dir.blobdir(), namespace, key[:2], key[2:]), nil
+	return filepath.Join(root, namespace, key[:2], key[2:]) + formatExtension(format), nil
+}
+
+// pathToBlob converts a filepath in root, the permanent storage or the trash,
+// to its blob reference, ok is false when the path isn't a blob.
+func pathToBlob(root, path string) (_ storage.BlobInfo, ok bool) {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return storage.BlobInfo{}, false
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	if len(parts) != 3 {
+		return storage.BlobInfo{}, false
+	}
+
+	format := storage.FormatV0
+	key := parts[1] + parts[2]
+	if strings.HasSuffix(key, v1Extension) {
+		format, key = storage.FormatV1, strings.TrimSuffix(key, v1Extension)
+	}
+
+	// NB: the keys shorter than three characters, which blobToPath pads,
+	// aren't recognized, the stored keys are longer
+	namespace, err := pathEncoding.DecodeString(parts[0])
+	if err != nil {
+		return storage.BlobInfo{}, false
+	}
+	keyBytes, err := pathEncoding.DecodeString(key)
+	if err != nil {
+		return storage.BlobInfo{}, false
+	}
+
+	info := storage.BlobInfo{
+		Ref:    storage.BlobRef{Namespace: namespace, Key: keyBytes},
+		Format: format,
+	}
+	return info, info.Ref.IsValid()
+}
+
+// formatExtension returns the file extension of the blobs of format.
+func formatExtension(format storage.FormatVersion) string {
+	if format == storage.FormatV1 {
+		return v1Extension
+	}
+	return ""
 }
 
-// blobToTrashPath converts blob reference to a filepath in transient storage
-// the files in trash are deleted in an interval (in case the initial deletion didn't work for some reason)
-func (dir *Dir) blobToTrashPath(ref storage.BlobRef) string {
+// blobToGarbagePath converts blob reference to a filepath in transient storage
+// the files in garbage are deleted in an interval (in case the initial deletion didn't work for some reason)
+func (dir *Dir) blobToGarbagePath(ref storage.BlobRef, format storage.FormatVersion) string {
 	name := []byte{}
 	name = append(name, ref.Namespace...)
 	name = append(name, ref.Key...)
-	return filepath.Join(dir.trashdir(), pathEncoding.EncodeToString(name))
+	return filepath.Join(dir.garbagedir(), pathEncoding.EncodeToString(name)+formatExtension(format))
 }
 
-// Commit commits temporary file to the permanent storage
-func (dir *Dir) Commit(file *os.File, ref storage.BlobRef) error {
+// Commit commits temporary file to the permanent storage in the format
+func (dir *Dir) Commit(file *os.File, ref storage.BlobRef, format storage.FormatVersion) error {
 	position, seekErr := file.Seek(0, io.SeekCurrent)
 	truncErr := file.Truncate(position)
 	syncErr := file.Sync()
@@ -116,7 +205,7 @@ func (dir *Dir) Commit(file *os.File, ref storage.BlobRef) error {
 		return errs.Combine(seekErr, truncErr, syncErr, chmodErr, closeErr, removeErr)
 	}
 
-	path, err := dir.blobToPath(ref)
+	path, err := dir.blobToPath(ref, format)
 	if err != nil {
 		removeErr := os.Remove(file.Name())
 		return errs.Combine(err, removeErr)
@@ -141,41 +230,58 @@ func (dir *Dir) Commit(file *os.File, ref storage.BlobRef) error {
 	return nil
 }
 
-// Open opens the file with the specified ref
-func (dir *Dir) Open(ref storage.BlobRef) (*os.File, error) {
-	path, err := dir.blobToPath(ref)
-	if err != nil {
-		return nil, err
-	}
-	file, err := openFileReadOnly(path, blobPermission)
-	if err != nil {
-		return nil, Error.New("unable to open %q: %v", path, err)
+// Open opens the file with the specified ref, in the newest format it's stored in
+func (dir *Dir) Open(ref storage.BlobRef) (*os.File, storage.FormatVersion, error) {
+	var notExist error
+	for _, format := range formats {
+		path, err := dir.blobToPath(ref, format)
+		if err != nil {
+			return nil, format, err
+		}
+		file, err := openFileReadOnly(path, blobPermission)
+		if err != nil {
+			if os.IsNotExist(err) {
+				notExist = err
+				continue
+			}
+			return nil, format, Error.New("unable to open %q: %v", path, err)
+		}
+		return file, format, nil
 	}
-	return file, nil
+	return nil, storage.FormatV0, notExist
 }
 
-// Delete deletes file with the specified ref
+// Delete deletes the files with the specified ref, in every format
 func (dir *Dir) Delete(ref storage.BlobRef) error {
-	path, err := dir.blobToPath(ref)
+	var group errs.Group
+	for _, format := range formats {
+		group.Add(dir.deleteFormat(ref, format))
+	}
+	return group.Err()
+}
+
+// deleteFormat deletes the file with the specified ref of the format
+func (dir *Dir) deleteFormat(ref storage.BlobRef, format storage.FormatVersion) error {
+	path, err := dir.blobToPath(ref, format)
 	if err != nil {
 		return err
 	}
 
-	trashPath := dir.blobToTrashPath(ref)
+	garbagePath := dir.blobToGarbagePath(ref, format)
 
-	// move to trash folder, this is allowed for some OS-es
-	moveErr := rename(path, trashPath)
+	// move to garbage folder, this is allowed for some OS-es
+	moveErr := rename(path, garbagePath)
 
 	// ignore concurrent delete
 	if os.IsNotExist(moveErr) {
 		return nil
 	}
 	if moveErr != nil {
-		trashPath = path
+		garbagePath = path
 	}
 
 	// try removing the file
-	err = os.Remove(trashPath)
+	err = os.Remove(garbagePath)
 
 	// ignore concurrent deletes
 	if os.IsNotExist(err) {
@@ -185,7 +291,7 @@ func (dir *Dir) Delete(ref storage.BlobRef) error {
 	// this may fail, because someone might be still reading it
 	if err != nil {
 		dir.mu.Lock()
-		dir.deleteQueue = append(dir.deleteQueue, trashPath)
+		dir.deleteQueue = append(dir.deleteQueue, garbagePath)
 		dir.mu.Unlock()
 	}
 
@@ -198,6 +304,157 @@ func (dir *Dir) Delete(ref storage.BlobRef) error {
 	return err
 }
 
+// Trash moves the files with the specified ref, in every format, to the trash
+// of its namespace, from which they can be restored until the trash is emptied
+func (dir *Dir) Trash(ref storage.BlobRef) error {
+	var group errs.Group
+	for _, format := range formats {
+		group.Add(dir.trashFormat(ref, format))
+	}
+	return group.Err()
+}
+
+// trashFormat moves the file with the specified ref of the format to the trash
+func (dir *Dir) trashFormat(ref storage.BlobRef, format storage.FormatVersion) error {
+	path, err := dir.blobToPath(ref, format)
+	if err != nil {
+		return err
+	}
+	trashPath, err := dir.blobToTrashPath(ref, format)
+	if err != nil {
+		return err
+	}
+
+	// ignore the formats the blob isn't stored in
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(trashPath), dirPermission); err != nil {
+		return err
+	}
+
+	moveErr := rename(path, trashPath)
+	// ignore concurrent delete
+	if os.IsNotExist(moveErr) {
+		return nil
+	}
+	// the files which can't be moved, like the ones being read on some
+	// OS-es, are deleted instead
+	if moveErr != nil {
+		return dir.deleteFormat(ref, format)
+	}
+
+	// NB: the modification time of a trashed file is when it was trashed,
+	// which renaming doesn't change
+	now := time.Now()
+	return os.Chtimes(trashPath, now, now)
+}
+
+// RestoreTrash moves the trashed files of the namespace back to the permanent
+// storage, returning the restored blobs. A trashed file whose blob was stored
+// again since is deleted instead.
+func (dir *Dir) RestoreTrash(namespace []byte) (restored []storage.BlobInfo, err error) {
+	err = dir.walkTrash(namespace, func(blob storage.BlobInfo, path string, info os.FileInfo) error {
+		blobPath, err := dir.blobToPath(blob.Ref, blob.Format)
+		if err != nil {
+			return err
+		}
+
+		if _, err := os.Stat(blobPath); err == nil {
+			return os.Remove(path)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(blobPath), dirPermission); err != nil {
+			return err
+		}
+		if err := rename(path, blobPath); err != nil {
+			return err
+		}
+		restored = append(restored, blob)
+		return nil
+	})
+	return restored, err
+}
+
+// EmptyTrash deletes the files trashed before trashedBefore, returning their
+// total size
+func (dir *Dir) EmptyTrash(trashedBefore time.Time) (emptied int64, err error) {
+	err = dir.walkTrash(nil, func(blob storage.BlobInfo, path string, info os.FileInfo) error {
+		if !info.ModTime().Before(trashedBefore) {
+			return nil
+		}
+		err := os.Remove(path)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		emptied += info.Size()
+		return nil
+	})
+	return emptied, err
+}
+
+// TrashSize returns the total size of the trashed files
+func (dir *Dir) TrashSize() (total int64, err error) {
+	err = dir.walkTrash(nil, func(blob storage.BlobInfo, path string, info os.FileInfo) error {
+		total += info.Size()
+		return nil
+	})
+	return total, err
+}
+
+// walkTrash calls fn for every trashed file of the namespace, or of every
+// namespace when it's nil, stopping at the first error
+func (dir *Dir) walkTrash(namespace []byte, fn func(blob storage.BlobInfo, path string, info os.FileInfo) error) error {
+	root := dir.trashdir()
+	if namespace != nil {
+		root = filepath.Join(root, pathEncoding.EncodeToString(namespace))
+	}
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// the trash of the namespace may not exist, and the trashed files
+			// can be restored or emptied while walking
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		blob, ok := pathToBlob(dir.trashdir(), path)
+		if !ok {
+			return nil
+		}
+		return fn(blob, path, info)
+	})
+}
+
+// Walk calls fn for every blob file, stopping at the first error
+func (dir *Dir) Walk(fn func(storage.BlobInfo) error) error {
+	return filepath.Walk(dir.blobdir(), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// the blobs can be deleted while walking
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		blob, ok := pathToBlob(dir.blobdir(), path)
+		if !ok {
+			return nil
+		}
+		blob.Size = info.Size()
+		blob.Allocated = diskUsage(path, info)
+		return fn(blob)
+	})
+}
+
 // GarbageCollect collects files that are pending deletion
 func (dir *Dir) GarbageCollect() error {
 	offset := int(math.MaxInt32)
@@ -224,8 +481,11 @@ func (dir *Dir) GarbageCollect() error {
 		dir.mu.Unlock()
 	}
 
-	// remove anything left in the trashdir
-	_ = removeAllContent(dir.trashdir())
+	// remove anything left in the garbagedir
+	_ = removeAllContent(dir.garbagedir())
+	// remove the files pending deletion which were put directly in the
+	// trashdir, before it kept the trash of each namespace
+	_ = removeFiles(dir.trashdir())
 	return nil
 }
 
@@ -251,10 +511,26 @@ func removeAllContent(path string) error {
 	}
 }
 
+// removeFiles deletes the files in the folder, keeping its subfolders
+func removeFiles(path string) error {
+	infos, err := ioutil.ReadDir(path)
+	if err != nil {
+		return err
+	}
+	for _, info := range infos {
+		if !info.IsDir() {
+			// the file might be still in use, so ignore the error
+			_ = os.Remove(filepath.Join(path, info.Name()))
+		}
+	}
+	return nil
+}
+
 // DiskInfo contains statistics about this dir
 type DiskInfo struct {
 	ID             string
 	AvailableSpace int64
+	TotalSpace     int64
 }
 
 // Info returns information about the current state of the dir
@@ -8,6 +8,7 @@ package filestore
 import (
 	"fmt"
 	"os"
+	"syscall"
 
 	"golang.org/x/sys/unix"
 )
@@ -21,14 +22,26 @@ func diskInfoFromPath(path string) (info DiskInfo, err error) {
 	var stat unix.Statfs_t
 	err = unix.Statfs(path, &stat)
 	if err != nil {
-		return DiskInfo{"", -1}, err
+		return DiskInfo{"", -1, -1}, err
 	}
 
 	// the Bsize size depends on the OS and unconvert gives a false-positive
 	availableSpace := int64(stat.Bavail) * int64(stat.Bsize) //nolint
+	totalSpace := int64(stat.Blocks) * int64(stat.Bsize)     //nolint
 	filesystemID := fmt.Sprintf("%08x%08x", stat.Fsid.Val[0], stat.Fsid.Val[1])
 
-	return DiskInfo{filesystemID, availableSpace}, nil
+	return DiskInfo{filesystemID, availableSpace, totalSpace}, nil
+}
+
+// diskUsage returns the disk space allocated to the file, which is less than its
+// size when it's sparse
+func diskUsage(path string, info os.FileInfo) int64 {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return info.Size()
+	}
+	// the blocks are counted in 512-byte units whatever the block size of the filesystem
+	return int64(stat.Blocks) * 512 //nolint
 }
 
 // rename renames oldpath to newpath
@@ -27,36 +27,54 @@ func diskInfoFromPath(path string) (info DiskInfo, err error) {
 		absPath = path
 	}
 	var filesystemID string
-	var availableSpace int64
+	var availableSpace, totalSpace int64
 
-	availableSpace, err = getDiskFreeSpace(absPath)
+	availableSpace, totalSpace, err = getDiskFreeSpace(absPath)
 	if err != nil {
-		return DiskInfo{"", -1}, err
+		return DiskInfo{"", -1, -1}, err
 	}
 
 	filesystemID, err = getVolumeSerialNumber(absPath)
 	if err != nil {
-		return DiskInfo{"", availableSpace}, err
+		return DiskInfo{"", availableSpace, totalSpace}, err
 	}
 
-	return DiskInfo{filesystemID, availableSpace}, nil
+	return DiskInfo{filesystemID, availableSpace, totalSpace}, nil
 }
 
 var (
-	kernel32             = windows.MustLoadDLL("kernel32.dll")
-	procGetDiskFreeSpace = kernel32.MustFindProc("GetDiskFreeSpaceExW")
+	kernel32                  = windows.MustLoadDLL("kernel32.dll")
+	procGetDiskFreeSpace      = kernel32.MustFindProc("GetDiskFreeSpaceExW")
+	procGetCompressedFileSize = kernel32.MustFindProc("GetCompressedFileSizeW")
 )
 
-func getDiskFreeSpace(path string) (int64, error) {
+func getDiskFreeSpace(path string) (available, total int64, err error) {
 	path16, err := windows.UTF16PtrFromString(path)
 	if err != nil {
-		return -1, err
+		return -1, -1, err
 	}
 
-	var bytes int64
-	_, _, err = procGetDiskFreeSpace.Call(uintptr(unsafe.Pointer(path16)), uintptr(unsafe.Pointer(&bytes)), 0, 0)
+	_, _, err = procGetDiskFreeSpace.Call(uintptr(unsafe.Pointer(path16)), uintptr(unsafe.Pointer(&available)), uintptr(unsafe.Pointer(&total)), 0)
 	err = ignoreSuccess(err)
-	return bytes, err
+	return available, total, err
+}
+
+// diskUsage returns the disk space allocated to the file, which is less than its
+// size when it's sparse or compressed
+func diskUsage(path string, info os.FileInfo) int64 {
+	const invalidFileSize = 0xFFFFFFFF
+
+	path16, err := windows.UTF16PtrFromString(tryFixLongPath(path))
+	if err != nil {
+		return info.Size()
+	}
+
+	var high uint32
+	low, _, err := procGetCompressedFileSize.Call(uintptr(unsafe.Pointer(path16)), uintptr(unsafe.Pointer(&high)))
+	if uint32(low) == invalidFileSize && ignoreSuccess(err) != nil {
+		return info.Size()
+	}
+	return int64(high)<<32 | int64(uint32(low))
 }
 
 func getVolumeSerialNumber(path string) (string, error) {
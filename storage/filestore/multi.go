@@ -0,0 +1,224 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package filestore
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/storage"
+)
+
+var _ storage.Blobs = (*MultiStore)(nil)
+
+// MultiStore implements a blob store spread over the directories of several
+// disks. New blobs are created in the directory whose disk is the least full
+// and blobs are looked up in every directory.
+type MultiStore struct {
+	stores []*Store
+}
+
+// NewMulti creates a blob store spread over the stores.
+func NewMulti(stores ...*Store) *MultiStore {
+	return &MultiStore{stores}
+}
+
+// NewMultiAt creates a blob store spread over the specified directories.
+func NewMultiAt(paths ...string) (*MultiStore, error) {
+	return NewMultiAtConfig(Config{}, paths...)
+}
+
+// NewMultiAtConfig creates a blob store spread over the specified directories
+// configured by config.
+func NewMultiAtConfig(config Config, paths ...string) (*MultiStore, error) {
+	if len(paths) == 0 {
+		return nil, Error.New("no directories")
+	}
+
+	stores := make([]*Store, 0, len(paths))
+	for _, path := range paths {
+		store, err := NewAtConfig(path, config)
+		if err != nil {
+			return nil, err
+		}
+		stores = append(stores, store)
+	}
+	return NewMulti(stores...), nil
+}
+
+// Close closes the stores.
+func (multi *MultiStore) Close() error {
+	var group errs.Group
+	for _, store := range multi.stores {
+		group.Add(store.Close())
+	}
+	return group.Err()
+}
+
+// Open loads the blob with the specified ref from the directory which contains it.
+func (multi *MultiStore) Open(ctx context.Context, ref storage.BlobRef) (_ storage.BlobReader, err error) {
+	for _, store := range multi.stores {
+		var reader storage.BlobReader
+		reader, err = store.Open(ctx, ref)
+		if err == nil || !os.IsNotExist(err) {
+			return reader, err
+		}
+	}
+	return nil, err
+}
+
+// Delete deletes the blob with the specified ref from every directory.
+func (multi *MultiStore) Delete(ctx context.Context, ref storage.BlobRef) error {
+	var group errs.Group
+	for _, store := range multi.stores {
+		group.Add(store.Delete(ctx, ref))
+	}
+	return group.Err()
+}
+
+// Trash moves the blob with the specified ref to the trash of every directory.
+func (multi *MultiStore) Trash(ctx context.Context, ref storage.BlobRef) error {
+	var group errs.Group
+	for _, store := range multi.stores {
+		group.Add(store.Trash(ctx, ref))
+	}
+	return group.Err()
+}
+
+// RestoreTrash moves the trashed blobs of the namespace back in every directory.
+func (multi *MultiStore) RestoreTrash(ctx context.Context, namespace []byte) ([]storage.BlobInfo, error) {
+	var restored []storage.BlobInfo
+	var group errs.Group
+	for _, store := range multi.stores {
+		storeRestored, err := store.RestoreTrash(ctx, namespace)
+		restored = append(restored, storeRestored...)
+		group.Add(err)
+	}
+	return restored, group.Err()
+}
+
+// EmptyTrash deletes the blobs trashed before trashedBefore in every directory.
+func (multi *MultiStore) EmptyTrash(ctx context.Context, trashedBefore time.Time) (int64, error) {
+	var total int64
+	var group errs.Group
+	for _, store := range multi.stores {
+		emptied, err := store.EmptyTrash(ctx, trashedBefore)
+		total += emptied
+		group.Add(err)
+	}
+	return total, group.Err()
+}
+
+// TrashSize returns the total size of the trashed blobs of every directory.
+func (multi *MultiStore) TrashSize(ctx context.Context) (int64, error) {
+	var total int64
+	for _, store := range multi.stores {
+		size, err := store.TrashSize(ctx)
+		if err != nil {
+			return 0, err
+		}
+		total += size
+	}
+	return total, nil
+}
+
+// GarbageCollect tries to delete any files that haven't yet been deleted in every directory.
+func (multi *MultiStore) GarbageCollect(ctx context.Context) error {
+	var group errs.Group
+	for _, store := range multi.stores {
+		group.Add(store.GarbageCollect(ctx))
+	}
+	return group.Err()
+}
+
+// Create creates a new blob in the directory whose disk is the least full,
+// or in the directory which already contains the blob, so that it's replaced.
+func (multi *MultiStore) Create(ctx context.Context, ref storage.BlobRef, format storage.FormatVersion, size int64) (storage.BlobWriter, error) {
+	store := multi.containing(ref)
+	if store == nil {
+		var err error
+		store, err = multi.leastFull()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return store.Create(ctx, ref, format, size)
+}
+
+// Walk calls fn for every blob of every directory, stopping at the first error.
+func (multi *MultiStore) Walk(ctx context.Context, fn func(storage.BlobInfo) error) error {
+	for _, store := range multi.stores {
+		if err := store.Walk(ctx, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// containing returns the store which contains the blob in any format, or nil.
+func (multi *MultiStore) containing(ref storage.BlobRef) *Store {
+	for _, store := range multi.stores {
+		for _, format := range formats {
+			path, err := store.dir.blobToPath(ref, format)
+			if err != nil {
+				continue
+			}
+			if _, err := os.Stat(path); err == nil {
+				return store
+			}
+		}
+	}
+	return nil
+}
+
+// leastFull returns the store whose disk has the lowest ratio of used space.
+// The directories whose disk info is unavailable are skipped.
+func (multi *MultiStore) leastFull() (*Store, error) {
+	var best *Store
+	var bestRatio float64
+	var group errs.Group
+	for _, store := range multi.stores {
+		info, err := store.dir.Info()
+		if err != nil {
+			group.Add(err)
+			continue
+		}
+
+		ratio := 1.0
+		if info.TotalSpace > 0 {
+			ratio = 1 - float64(info.AvailableSpace)/float64(info.TotalSpace)
+		}
+		if best == nil || ratio < bestRatio {
+			best, bestRatio = store, ratio
+		}
+	}
+	if best == nil {
+		return nil, Error.Wrap(group.Err())
+	}
+	return best, nil
+}
+
+// FreeSpace returns how much space is left on the disks of the directories,
+// counting the disks shared by several directories once.
+func (multi *MultiStore) FreeSpace() (int64, error) {
+	var total int64
+	seen := map[string]bool{}
+	for _, store := range multi.stores {
+		info, err := store.dir.Info()
+		if err != nil {
+			return 0, err
+		}
+		if info.ID != "" {
+			if seen[info.ID] {
+				continue
+			}
+			seen[info.ID] = true
+		}
+		total += info.AvailableSpace
+	}
+	return total, nil
+}
@@ -0,0 +1,81 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package filestore_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/filestore"
+)
+
+func TestMultiStore(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	first, err := filestore.NewAt(ctx.Dir("first"))
+	require.NoError(t, err)
+	second, err := filestore.NewAt(ctx.Dir("second"))
+	require.NoError(t, err)
+
+	write := func(store storage.Blobs, ref storage.BlobRef, data []byte) {
+		writer, err := store.Create(ctx, ref, storage.FormatV0, -1)
+		require.NoError(t, err)
+		_, err = writer.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, writer.Commit())
+	}
+	read := func(store storage.Blobs, ref storage.BlobRef) []byte {
+		reader, err := store.Open(ctx, ref)
+		require.NoError(t, err)
+		data, err := ioutil.ReadAll(reader)
+		require.NoError(t, err)
+		require.NoError(t, reader.Close())
+		return data
+	}
+
+	namespace := randomValue()
+	existing := storage.BlobRef{Namespace: namespace, Key: randomValue()}
+	write(second, existing, []byte{1})
+
+	multi := filestore.NewMulti(first, second)
+	defer ctx.Check(multi.Close)
+
+	// blobs are found in any directory
+	require.Equal(t, []byte{1}, read(multi, existing))
+
+	// replacing a blob keeps it in its directory
+	write(multi, existing, []byte{2})
+	require.Equal(t, []byte{2}, read(second, existing))
+	_, err = first.Open(ctx, existing)
+	require.True(t, os.IsNotExist(err))
+
+	// new blobs are created in one of the directories
+	var refs []storage.BlobRef
+	for i := 0; i < 10; i++ {
+		ref := storage.BlobRef{Namespace: namespace, Key: randomValue()}
+		write(multi, ref, []byte{byte(i)})
+		require.Equal(t, []byte{byte(i)}, read(multi, ref))
+		refs = append(refs, ref)
+	}
+
+	for _, ref := range append(refs, existing) {
+		require.NoError(t, multi.Delete(ctx, ref))
+		_, err := multi.Open(ctx, ref)
+		require.True(t, os.IsNotExist(err))
+	}
+
+	// the directories are on the same disk, which is counted once
+	free, err := multi.FreeSpace()
+	require.NoError(t, err)
+	single, err := first.FreeSpace()
+	require.NoError(t, err)
+	require.True(t, free > 0)
+	require.True(t, free < 2*single)
+}
@@ -0,0 +1,26 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// +build linux
+
+package filestore
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// preallocate allocates the disk space of the first size bytes of the file and
+// extends it to size. The file is only extended when the filesystem doesn't
+// support allocating.
+func preallocate(file *os.File, size int64) error {
+	if size == 0 {
+		return file.Truncate(size)
+	}
+	err := unix.Fallocate(int(file.Fd()), 0, 0, size)
+	if err == unix.EOPNOTSUPP || err == unix.ENOSYS {
+		return file.Truncate(size)
+	}
+	return err
+}
@@ -0,0 +1,16 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// +build !linux
+
+package filestore
+
+import (
+	"os"
+)
+
+// preallocate extends the file to size. Extending a file allocates its disk
+// space on NTFS, the other filesystems are left to allocate it when written.
+func preallocate(file *os.File, size int64) error {
+	return file.Truncate(size)
+}
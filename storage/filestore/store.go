@@ -6,6 +6,7 @@ package filestore
 import (
 	"context"
 	"os"
+	"time"
 
 	"github.com/zeebo/errs"
 
@@ -29,7 +30,12 @@ func New(dir *Dir) *Store {
 
 // NewAt creates a new disk blob store in the specified directory
 func NewAt(path string) (*Store, error) {
-	dir, err := NewDir(path)
+	return NewAtConfig(path, Config{})
+}
+
+// NewAtConfig creates a new disk blob store in the specified directory configured by config
+func NewAtConfig(path string, config Config) (*Store, error) {
+	dir, err := NewDirConfig(path, config)
 	if err != nil {
 		return nil, Error.Wrap(err)
 	}
@@ -41,36 +47,65 @@ func (store *Store) Close() error { return nil }
 
 // Open loads blob with the specified hash
 func (store *Store) Open(ctx context.Context, ref storage.BlobRef) (storage.BlobReader, error) {
-	file, openErr := store.dir.Open(ref)
+	file, format, openErr := store.dir.Open(ref)
 	if openErr != nil {
 		if os.IsNotExist(openErr) {
 			return nil, openErr
 		}
 		return nil, Error.Wrap(openErr)
 	}
-	return newBlobReader(file), nil
+	return newBlobReader(file, format), nil
 }
 
-// Delete deletes blobs with the specified ref
+// Delete deletes blobs with the specified ref, in every format
 func (store *Store) Delete(ctx context.Context, ref storage.BlobRef) error {
 	err := store.dir.Delete(ref)
 	return Error.Wrap(err)
 }
 
+// Trash moves blobs with the specified ref, in every format, to the trash
+func (store *Store) Trash(ctx context.Context, ref storage.BlobRef) error {
+	err := store.dir.Trash(ref)
+	return Error.Wrap(err)
+}
+
+// RestoreTrash moves the trashed blobs of the namespace back, returning the restored blobs
+func (store *Store) RestoreTrash(ctx context.Context, namespace []byte) ([]storage.BlobInfo, error) {
+	restored, err := store.dir.RestoreTrash(namespace)
+	return restored, Error.Wrap(err)
+}
+
+// EmptyTrash deletes the blobs trashed before trashedBefore, returning their total size
+func (store *Store) EmptyTrash(ctx context.Context, trashedBefore time.Time) (int64, error) {
+	emptied, err := store.dir.EmptyTrash(trashedBefore)
+	return emptied, Error.Wrap(err)
+}
+
+// TrashSize returns the total size of the trashed blobs
+func (store *Store) TrashSize(ctx context.Context) (int64, error) {
+	total, err := store.dir.TrashSize()
+	return total, Error.Wrap(err)
+}
+
 // GarbageCollect tries to delete any files that haven't yet been deleted
 func (store *Store) GarbageCollect(ctx context.Context) error {
 	err := store.dir.GarbageCollect()
 	return Error.Wrap(err)
 }
 
-// Create creates a new blob that can be written
+// Create creates a new blob of the format that can be written
 // optionally takes a size argument for performance improvements, -1 is unknown size
-func (store *Store) Create(ctx context.Context, ref storage.BlobRef, size int64) (storage.BlobWriter, error) {
+func (store *Store) Create(ctx context.Context, ref storage.BlobRef, format storage.FormatVersion, size int64) (storage.BlobWriter, error) {
 	file, err := store.dir.CreateTemporaryFile(size)
 	if err != nil {
 		return nil, Error.Wrap(err)
 	}
-	return newBlobWriter(ref, store, file), nil
+	return newBlobWriter(ref, format, store, file), nil
+}
+
+// Walk calls fn for every stored blob, stopping at the first error
+func (store *Store) Walk(ctx context.Context, fn func(storage.BlobInfo) error) error {
+	return store.dir.Walk(fn)
 }
 
 // FreeSpace returns how much space left in underlying directory
@@ -5,12 +5,15 @@ package filestore_test
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"math/rand"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -50,7 +53,7 @@ func TestStoreLoad(t *testing.T) {
 		}
 		refs = append(refs, ref)
 
-		writer, err := store.Create(ctx, ref, -1)
+		writer, err := store.Create(ctx, ref, storage.FormatV0, -1)
 		require.NoError(t, err)
 
 		n, err := writer.Write(data)
@@ -73,7 +76,7 @@ func TestStoreLoad(t *testing.T) {
 		}
 		refs = append(refs, ref)
 
-		writer, err := store.Create(ctx, ref, int64(len(data)))
+		writer, err := store.Create(ctx, ref, storage.FormatV0, int64(len(data)))
 		require.NoError(t, err)
 
 		n, err := writer.Write(data)
@@ -92,7 +95,7 @@ func TestStoreLoad(t *testing.T) {
 		}
 		refs = append(refs, ref)
 
-		writer, err := store.Create(ctx, ref, int64(len(data)*2))
+		writer, err := store.Create(ctx, ref, storage.FormatV0, int64(len(data)*2))
 		require.NoError(t, err)
 
 		n, err := writer.Write(data)
@@ -110,7 +113,7 @@ func TestStoreLoad(t *testing.T) {
 			Key:       randomValue(),
 		}
 
-		writer, err := store.Create(ctx, ref, -1)
+		writer, err := store.Create(ctx, ref, storage.FormatV0, -1)
 		require.NoError(t, err)
 
 		n, err := writer.Write(data)
@@ -172,7 +175,7 @@ func TestDeleteWhileReading(t *testing.T) {
 		Key:       []byte{1},
 	}
 
-	writer, err := store.Create(ctx, ref, -1)
+	writer, err := store.Create(ctx, ref, storage.FormatV0, -1)
 	require.NoError(t, err)
 
 	_, err = writer.Write(data)
@@ -226,3 +229,204 @@ func TestDeleteWhileReading(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestStoreFormats(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	store, err := filestore.NewAt(ctx.Dir("store"))
+	require.NoError(t, err)
+
+	write := func(ref storage.BlobRef, format storage.FormatVersion, data []byte) {
+		writer, err := store.Create(ctx, ref, format, -1)
+		require.NoError(t, err)
+		require.Equal(t, format, writer.StorageFormatVersion())
+		_, err = writer.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, writer.Commit())
+	}
+	read := func(ref storage.BlobRef) (storage.FormatVersion, []byte) {
+		reader, err := store.Open(ctx, ref)
+		require.NoError(t, err)
+		data, err := ioutil.ReadAll(reader)
+		require.NoError(t, err)
+		require.NoError(t, reader.Close())
+		return reader.StorageFormatVersion(), data
+	}
+
+	namespace := randomValue()
+	v0 := storage.BlobRef{Namespace: namespace, Key: randomValue()}
+	v1 := storage.BlobRef{Namespace: namespace, Key: randomValue()}
+	write(v0, storage.FormatV0, []byte{0})
+	write(v1, storage.FormatV1, []byte{1})
+
+	format, data := read(v0)
+	require.Equal(t, storage.FormatV0, format)
+	require.Equal(t, []byte{0}, data)
+	format, data = read(v1)
+	require.Equal(t, storage.FormatV1, format)
+	require.Equal(t, []byte{1}, data)
+
+	// the header written at the start keeps the data written after it
+	writer, err := store.Create(ctx, v1, storage.FormatV1, -1)
+	require.NoError(t, err)
+	_, err = writer.Write([]byte{0, 0, 2, 3})
+	require.NoError(t, err)
+	_, err = writer.WriteAt([]byte{1}, 0)
+	require.NoError(t, err)
+	require.NoError(t, writer.Commit())
+	_, data = read(v1)
+	require.Equal(t, []byte{1, 0, 2, 3}, data)
+
+	// the newest format is read, when both are stored
+	write(v0, storage.FormatV1, []byte{2})
+	format, data = read(v0)
+	require.Equal(t, storage.FormatV1, format)
+	require.Equal(t, []byte{2}, data)
+
+	walked := map[string]storage.FormatVersion{}
+	require.NoError(t, store.Walk(ctx, func(info storage.BlobInfo) error {
+		require.Equal(t, namespace, info.Ref.Namespace)
+		walked[string(info.Ref.Key)+"/"+fmt.Sprint(info.Format)] = info.Format
+		return nil
+	}))
+	require.Len(t, walked, 3)
+	require.Contains(t, walked, string(v0.Key)+"/0")
+	require.Contains(t, walked, string(v0.Key)+"/1")
+	require.Contains(t, walked, string(v1.Key)+"/1")
+
+	// deleting removes every format
+	require.NoError(t, store.Delete(ctx, v0))
+	_, err = store.Open(ctx, v0)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestStoreTrash(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	store, err := filestore.NewAt(ctx.Dir("store"))
+	require.NoError(t, err)
+
+	write := func(ref storage.BlobRef, format storage.FormatVersion, data []byte) {
+		writer, err := store.Create(ctx, ref, format, -1)
+		require.NoError(t, err)
+		_, err = writer.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, writer.Commit())
+	}
+	exists := func(ref storage.BlobRef) bool {
+		reader, err := store.Open(ctx, ref)
+		if err != nil {
+			require.True(t, os.IsNotExist(err))
+			return false
+		}
+		require.NoError(t, reader.Close())
+		return true
+	}
+
+	namespace, other := randomValue(), randomValue()
+	v0 := storage.BlobRef{Namespace: namespace, Key: randomValue()}
+	v1 := storage.BlobRef{Namespace: namespace, Key: randomValue()}
+	kept := storage.BlobRef{Namespace: other, Key: randomValue()}
+	write(v0, storage.FormatV0, []byte{0})
+	write(v1, storage.FormatV1, []byte{1, 1})
+	write(kept, storage.FormatV1, []byte{2, 2, 2})
+
+	// trashed blobs are moved away, in every format
+	require.NoError(t, store.Trash(ctx, v0))
+	require.NoError(t, store.Trash(ctx, v1))
+	require.NoError(t, store.Trash(ctx, kept))
+	require.False(t, exists(v0))
+	require.False(t, exists(v1))
+
+	size, err := store.TrashSize(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(6), size)
+
+	// the trash of the namespace is restored, a blob stored again is kept
+	write(v1, storage.FormatV1, []byte{3})
+	restored, err := store.RestoreTrash(ctx, namespace)
+	require.NoError(t, err)
+	require.Len(t, restored, 1)
+	require.Equal(t, v0.Key, restored[0].Ref.Key)
+	require.Equal(t, storage.FormatV0, restored[0].Format)
+	require.True(t, exists(v0))
+	reader, err := store.Open(ctx, v1)
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	require.Equal(t, []byte{3}, data)
+
+	// only the blobs trashed before the time are emptied
+	emptied, err := store.EmptyTrash(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, int64(0), emptied)
+
+	emptied, err = store.EmptyTrash(ctx, time.Now().Add(time.Second))
+	require.NoError(t, err)
+	require.Equal(t, int64(3), emptied)
+
+	restored, err = store.RestoreTrash(ctx, other)
+	require.NoError(t, err)
+	require.Empty(t, restored)
+	require.False(t, exists(kept))
+
+	size, err = store.TrashSize(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), size)
+}
+
+func TestStorePreallocate(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	store, err := filestore.NewAtConfig(ctx.Dir("store"), filestore.Config{Preallocate: true})
+	require.NoError(t, err)
+
+	const size = 1 << 20
+	ref := storage.BlobRef{Namespace: randomValue(), Key: randomValue()}
+	data := randomValue()
+
+	// the preallocated space beyond the written data is released on commit
+	writer, err := store.Create(ctx, ref, storage.FormatV0, size)
+	require.NoError(t, err)
+	_, err = writer.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, writer.Commit())
+
+	reader, err := store.Open(ctx, ref)
+	require.NoError(t, err)
+	readSize, err := reader.Size()
+	require.NoError(t, err)
+	require.Equal(t, int64(len(data)), readSize)
+	read, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, data, read)
+	require.NoError(t, reader.Close())
+
+	if runtime.GOOS == "windows" {
+		t.Skip("extending a file allocates its space on NTFS")
+	}
+
+	// a sparse blob reports the space allocated to it
+	var paths []string
+	require.NoError(t, filepath.Walk(ctx.Dir("store", "blob"), func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			paths = append(paths, path)
+		}
+		return err
+	}))
+	require.Len(t, paths, 1)
+	require.NoError(t, os.Truncate(paths[0], size))
+
+	var blobs []storage.BlobInfo
+	require.NoError(t, store.Walk(ctx, func(blob storage.BlobInfo) error {
+		blobs = append(blobs, blob)
+		return nil
+	}))
+	require.Len(t, blobs, 1)
+	require.Equal(t, int64(size), blobs[0].Size)
+	require.True(t, blobs[0].Allocated < size, blobs[0].Allocated)
+}
@@ -124,10 +124,10 @@ func (opi *alternateOrderedPostgresIterator) doNextQuery() (*sql.Rows, error) {
 	} else {
 		query = alternateForwardQuery
 	}
-	return opi.client.pgConn.Query(query, []byte(opi.bucket), []byte(opi.opts.Prefix), []byte(start), opi.batchSize+1)
+	return opi.db.Query(query, []byte(opi.bucket), []byte(opi.opts.Prefix), []byte(start), opi.batchSize+1)
 }
 
-func newAlternateOrderedPostgresIterator(altClient *AlternateClient, opts storage.IterateOptions, batchSize int) (*alternateOrderedPostgresIterator, error) {
+func newAlternateOrderedPostgresIterator(altClient *AlternateClient, db querier, opts storage.IterateOptions, batchSize int) (*alternateOrderedPostgresIterator, error) {
 	if opts.Prefix == nil {
 		opts.Prefix = storage.Key("")
 	}
@@ -136,6 +136,7 @@ func newAlternateOrderedPostgresIterator(altClient *AlternateClient, opts storag
 	}
 	opi1 := &orderedPostgresIterator{
 		client:    altClient.Client,
+		db:        db,
 		opts:      &opts,
 		bucket:    storage.Key(defaultBucket),
 		delimiter: byte('/'),
@@ -154,13 +155,15 @@ func newAlternateOrderedPostgresIterator(altClient *AlternateClient, opts storag
 
 // Iterate iterates over items based on opts
 func (altClient *AlternateClient) Iterate(opts storage.IterateOptions, fn func(storage.Iterator) error) (err error) {
-	opi, err := newAlternateOrderedPostgresIterator(altClient, opts, defaultBatchSize)
-	if err != nil {
-		return err
-	}
-	defer func() {
-		err = errs.Combine(err, opi.Close())
-	}()
-
-	return fn(opi)
+	return altClient.withSnapshot(opts.Snapshot, func(db querier) (err error) {
+		opi, err := newAlternateOrderedPostgresIterator(altClient, db, opts, defaultBatchSize)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err = errs.Combine(err, opi.Close())
+		}()
+
+		return fn(opi)
+	})
 }
@@ -4,6 +4,7 @@
 package postgreskv
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
@@ -21,8 +22,9 @@ const (
 
 // Client is the entrypoint into a postgreskv data store
 type Client struct {
-	URL    string
-	pgConn *sql.DB
+	URL     string
+	pgConn  *sql.DB
+	dialect dialect
 }
 
 // New instantiates a new postgreskv client given db URL
@@ -110,6 +112,81 @@ func (client *Client) DeletePath(bucket, key storage.Key) error {
 	return nil
 }
 
+// CompareAndSwap atomically compares and swaps oldValue with newValue
+func (client *Client) CompareAndSwap(key storage.Key, oldValue, newValue storage.Value) error {
+	return client.CompareAndSwapPath(storage.Key(defaultBucket), key, oldValue, newValue)
+}
+
+// CompareAndSwapPath atomically compares and swaps oldValue with newValue (in
+// the given bucket).
+func (client *Client) CompareAndSwapPath(bucket, key storage.Key, oldValue, newValue storage.Value) error {
+	if key.IsZero() {
+		return storage.ErrEmptyKey.New("")
+	}
+
+	if oldValue == nil && newValue == nil {
+		q := "SELECT EXISTS(SELECT 1 FROM pathdata WHERE bucket = $1::BYTEA AND fullpath = $2::BYTEA)"
+		var exists bool
+		if err := client.pgConn.QueryRow(q, []byte(bucket), []byte(key)).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return storage.ErrValueChanged.New(key.String())
+		}
+		return nil
+	}
+
+	if oldValue == nil {
+		q := `
+			INSERT INTO pathdata (bucket, fullpath, metadata)
+				VALUES ($1::BYTEA, $2::BYTEA, $3::BYTEA)
+				ON CONFLICT DO NOTHING
+		`
+		return client.expectAffected(key, storage.ErrValueChanged, q, []byte(bucket), []byte(key), []byte(newValue))
+	}
+
+	var q string
+	var args []interface{}
+	if newValue == nil {
+		q = "DELETE FROM pathdata WHERE bucket = $1::BYTEA AND fullpath = $2::BYTEA AND metadata = $3::BYTEA"
+		args = []interface{}{[]byte(bucket), []byte(key), []byte(oldValue)}
+	} else {
+		q = `
+			UPDATE pathdata SET metadata = $4::BYTEA
+			WHERE bucket = $1::BYTEA AND fullpath = $2::BYTEA AND metadata = $3::BYTEA
+		`
+		args = []interface{}{[]byte(bucket), []byte(key), []byte(oldValue), []byte(newValue)}
+	}
+
+	err := client.expectAffected(key, storage.ErrValueChanged, q, args...)
+	if !storage.ErrValueChanged.Has(err) {
+		return err
+	}
+
+	// tell a changed value from a missing key
+	_, getErr := client.GetPath(bucket, key)
+	if storage.ErrKeyNotFound.Has(getErr) {
+		return getErr
+	}
+	return err
+}
+
+// expectAffected executes q and returns an error of class when no row was affected.
+func (client *Client) expectAffected(key storage.Key, class errs.Class, q string, args ...interface{}) error {
+	result, err := client.pgConn.Exec(q, args...)
+	if err != nil {
+		return err
+	}
+	numRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if numRows == 0 {
+		return class.New(key.String())
+	}
+	return nil
+}
+
 // List returns either a list of known keys, in order, or an error.
 func (client *Client) List(first storage.Key, limit int) (storage.Keys, error) {
 	return storage.ListKeys(client, first, limit)
@@ -157,8 +234,15 @@ func (client *Client) GetAllPath(bucket storage.Key, keys storage.Keys) (storage
 	return values, errs.Combine(rows.Err(), rows.Close())
 }
 
+// querier is the part of *sql.DB and *sql.Tx used by the iterators
+type querier interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
 type orderedPostgresIterator struct {
 	client         *Client
+	db             querier
 	opts           *storage.IterateOptions
 	bucket         storage.Key
 	delimiter      byte
@@ -245,20 +329,36 @@ func (opi *orderedPostgresIterator) doNextQuery() (*sql.Rows, error) {
 			  FROM pathdata
 			 WHERE bucket = $1::BYTEA
 			   AND ($2::BYTEA = ''::BYTEA OR fullpath >= $2::BYTEA)
-			   AND ($2::BYTEA = ''::BYTEA OR fullpath < bytea_increment($2::BYTEA))
+			   AND ($2::BYTEA = ''::BYTEA OR fullpath < %s)
 			   AND ($3::BYTEA = ''::BYTEA OR fullpath %s $3::BYTEA)
 			 ORDER BY fullpath%s
 			 LIMIT $4
-		`, startCmp, orderDir)
+		`, opi.prefixBound(), startCmp, orderDir)
+		if opi.client.dialect == dialectCockroach {
+			// cockroach has no bytea_increment, so the bound is computed here
+			var bound interface{}
+			if upper := prefixUpperBound(opi.opts.Prefix); upper != nil {
+				bound = upper
+			}
+			return opi.db.Query(query, []byte(opi.bucket), []byte(opi.opts.Prefix), []byte(start), opi.batchSize+1, bound)
+		}
+	}
+	return opi.db.Query(query, []byte(opi.bucket), []byte(opi.opts.Prefix), []byte(start), opi.batchSize+1)
+}
+
+// prefixBound returns the SQL expression for the first key after the prefix
+func (opi *orderedPostgresIterator) prefixBound() string {
+	if opi.client.dialect == dialectCockroach {
+		return "$5::BYTEA OR $5::BYTEA IS NULL"
 	}
-	return opi.client.pgConn.Query(query, []byte(opi.bucket), []byte(opi.opts.Prefix), []byte(start), opi.batchSize+1)
+	return "bytea_increment($2::BYTEA)"
 }
 
 func (opi *orderedPostgresIterator) Close() error {
 	return errs.Combine(opi.errEncountered, opi.curRows.Close())
 }
 
-func newOrderedPostgresIterator(pgClient *Client, opts storage.IterateOptions, batchSize int) (*orderedPostgresIterator, error) {
+func newOrderedPostgresIterator(pgClient *Client, db querier, opts storage.IterateOptions, batchSize int) (*orderedPostgresIterator, error) {
 	if opts.Prefix == nil {
 		opts.Prefix = storage.Key("")
 	}
@@ -267,6 +367,7 @@ func newOrderedPostgresIterator(pgClient *Client, opts storage.IterateOptions, b
 	}
 	opi := &orderedPostgresIterator{
 		client:    pgClient,
+		db:        db,
 		opts:      &opts,
 		bucket:    storage.Key(defaultBucket),
 		delimiter: byte('/'),
@@ -284,13 +385,53 @@ func newOrderedPostgresIterator(pgClient *Client, opts storage.IterateOptions, b
 
 // Iterate iterates over items based on opts
 func (client *Client) Iterate(opts storage.IterateOptions, fn func(storage.Iterator) error) (err error) {
-	opi, err := newOrderedPostgresIterator(client, opts, defaultBatchSize)
+	return client.withSnapshot(opts.Snapshot, func(db querier) (err error) {
+		if client.dialect == dialectCockroach && !opts.Recurse {
+			it := newCockroachDirectoryIterator(client, db, opts)
+			defer func() {
+				err = errs.Combine(err, it.Close())
+			}()
+			return fn(it)
+		}
+
+		opi, err := newOrderedPostgresIterator(client, db, opts, defaultBatchSize)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err = errs.Combine(err, opi.Close())
+		}()
+
+		return fn(opi)
+	})
+}
+
+// withSnapshot calls fn with the connection to iterate with. When snapshot is
+// set, fn runs in a read-only transaction, so all the queries of the
+// iteration see the database as it was when the iteration started.
+func (client *Client) withSnapshot(snapshot bool, fn func(db querier) error) (err error) {
+	if !snapshot {
+		return fn(client.pgConn)
+	}
+
+	// cockroach runs every transaction as serializable, which includes
+	// the guarantees of repeatable read
+	isolation := sql.LevelRepeatableRead
+	if client.dialect == dialectCockroach {
+		isolation = sql.LevelSerializable
+	}
+
+	tx, err := client.pgConn.BeginTx(context.Background(), &sql.TxOptions{
+		Isolation: isolation,
+		ReadOnly:  true,
+	})
 	if err != nil {
-		return err
+		return errs.Wrap(err)
 	}
 	defer func() {
-		err = errs.Combine(err, opi.Close())
+		// the transaction only reads, so there's nothing to commit
+		err = errs.Combine(err, tx.Rollback())
 	}()
 
-	return fn(opi)
+	return fn(tx)
 }
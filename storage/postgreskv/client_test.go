@@ -50,6 +50,7 @@ func TestSuite(t *testing.T) {
 
 	zap := zaptest.NewLogger(t)
 	testsuite.RunTests(t, storelogger.New(zap, store))
+	testsuite.RunSnapshotTests(t, store)
 }
 
 func BenchmarkSuite(b *testing.B) {
@@ -0,0 +1,278 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package postgreskv
+
+import (
+	"bytes"
+	"database/sql"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/storage"
+)
+
+// dialect is the SQL dialect of the database behind the client
+type dialect int
+
+const (
+	// dialectPostgres is the PostgreSQL dialect
+	dialectPostgres dialect = iota
+	// dialectCockroach is the CockroachDB dialect, which has no stored
+	// functions or advisory locks
+	dialectCockroach
+)
+
+const (
+	// cockroachMaxRetries is the number of times a transaction is retried
+	// when CockroachDB aborts it because of a conflict
+	cockroachMaxRetries = 10
+
+	// cockroachSerializationFailure is the SQLSTATE returned for a
+	// transaction which must be retried
+	cockroachSerializationFailure = "40001"
+)
+
+// cockroachSchema is the schema of the CockroachDB dialect. It's compatible
+// with the tables of the PostgreSQL schema, but has no stored functions, and
+// is idempotent, so it can be applied without the migration locks.
+var cockroachSchema = []string{
+	`CREATE TABLE IF NOT EXISTS buckets (
+		bucketname BYTEA
+			PRIMARY KEY,
+		delim INT
+			NOT NULL
+			CHECK (delim > 0 AND delim < 255)
+	)`,
+	`UPSERT INTO buckets (bucketname, delim) VALUES (''::BYTEA, 47)`,
+	`CREATE TABLE IF NOT EXISTS pathdata (
+		bucket BYTEA
+			NOT NULL
+			REFERENCES buckets (bucketname),
+		fullpath BYTEA
+			NOT NULL
+			CHECK (fullpath <> ''),
+		metadata BYTEA
+			NOT NULL,
+
+		PRIMARY KEY (bucket, fullpath)
+	)`,
+}
+
+// NewCockroach instantiates a new postgreskv client for a CockroachDB
+// database given its URL. The URL may use the cockroach:// scheme.
+func NewCockroach(dbURL string) (*Client, error) {
+	if strings.HasPrefix(dbURL, "cockroach://") {
+		dbURL = "postgres://" + strings.TrimPrefix(dbURL, "cockroach://")
+	}
+
+	pgConn, err := sql.Open("postgres", dbURL)
+	if err != nil {
+		return nil, err
+	}
+
+	err = withRetryableTx(pgConn, func(tx *sql.Tx) error {
+		for _, statement := range cockroachSchema {
+			if _, err := tx.Exec(statement); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errs.Combine(Error.Wrap(err), pgConn.Close())
+	}
+
+	return &Client{
+		URL:     dbURL,
+		pgConn:  pgConn,
+		dialect: dialectCockroach,
+	}, nil
+}
+
+// withRetryableTx runs fn in a transaction, retrying the whole transaction
+// when the database asks the client to retry it.
+func withRetryableTx(db *sql.DB, fn func(tx *sql.Tx) error) (err error) {
+	for retry := 0; ; retry++ {
+		err = withTx(db, fn)
+		if err == nil || retry >= cockroachMaxRetries || !isRetryable(err) {
+			return err
+		}
+	}
+}
+
+// withTx runs fn in a transaction, committing it when fn succeeds
+func withTx(db *sql.DB, fn func(tx *sql.Tx) error) (err error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			err = tx.Commit()
+		} else {
+			err = errs.Combine(err, tx.Rollback())
+		}
+	}()
+	return fn(tx)
+}
+
+// isRetryable returns whether the transaction that failed with err can be retried
+func isRetryable(err error) bool {
+	pqErr, ok := errs.Unwrap(err).(*pq.Error)
+	return ok && pqErr.Code == cockroachSerializationFailure
+}
+
+// prefixUpperBound returns the first key after all the keys with the prefix,
+// or nil when there's no such key. It's the equivalent of bytea_increment.
+func prefixUpperBound(prefix storage.Key) []byte {
+	bound := append([]byte{}, prefix...)
+	for len(bound) > 0 && bound[len(bound)-1] == 0xFF {
+		bound = bound[:len(bound)-1]
+	}
+	if len(bound) == 0 {
+		return nil
+	}
+	bound[len(bound)-1]++
+	return bound
+}
+
+// cockroachDirectoryIterator lists a directory without stored functions, by
+// seeking past the contents of every listed prefix.
+type cockroachDirectoryIterator struct {
+	client    *Client
+	db        querier
+	opts      storage.IterateOptions
+	bucket    storage.Key
+	delimiter byte
+
+	// cursor is where the next lookup starts, and after is whether the
+	// cursor itself is skipped
+	cursor []byte
+	after  bool
+	done   bool
+	err    error
+}
+
+func newCockroachDirectoryIterator(client *Client, db querier, opts storage.IterateOptions) *cockroachDirectoryIterator {
+	it := &cockroachDirectoryIterator{
+		client:    client,
+		db:        db,
+		opts:      opts,
+		bucket:    storage.Key(defaultBucket),
+		delimiter: byte('/'),
+	}
+
+	if !opts.Reverse {
+		it.cursor = opts.Prefix
+		if !opts.First.IsZero() && opts.Prefix.Less(opts.First) {
+			it.cursor = opts.First
+		}
+	} else {
+		it.cursor = opts.First
+		if bound := prefixUpperBound(opts.Prefix); !opts.Prefix.IsZero() && bound != nil &&
+			(opts.First.IsZero() || !storage.Key(opts.First).Less(bound)) {
+			it.cursor, it.after = bound, true
+		}
+	}
+	return it
+}
+
+// Next fills in info for the next item in the directory listing
+func (it *cockroachDirectoryIterator) Next(item *storage.ListItem) bool {
+	if it.done || it.err != nil {
+		return false
+	}
+
+	key, value, ok, err := it.lookup()
+	if err != nil {
+		it.err = err
+		return false
+	}
+	if !ok {
+		it.done = true
+		return false
+	}
+
+	if p := bytes.IndexByte(key[len(it.opts.Prefix):], it.delimiter); p >= 0 {
+		// collapse the key into the nested prefix
+		item.Key = append(item.Key[:0], key[:len(it.opts.Prefix)+p+1]...)
+		item.Value = nil
+		item.IsPrefix = true
+	} else {
+		item.Key = append(item.Key[:0], key...)
+		item.Value = append(item.Value[:0], value...)
+		item.IsPrefix = false
+	}
+
+	if !it.opts.Reverse && item.IsPrefix {
+		// continue after the contents of the prefix
+		it.cursor, it.after = prefixUpperBound(item.Key), false
+		if it.cursor == nil {
+			it.done = true
+		}
+	} else {
+		// the contents of a prefix come after it, so in the reverse
+		// order they are skipped by continuing before the prefix
+		it.cursor, it.after = append([]byte{}, item.Key...), true
+	}
+	return true
+}
+
+// lookup finds the first key from the cursor in the iteration order
+func (it *cockroachDirectoryIterator) lookup() (key, value []byte, ok bool, err error) {
+	var query string
+	var bound interface{}
+	if !it.opts.Reverse {
+		cmp := ">="
+		if it.after {
+			cmp = ">"
+		}
+		query = `
+			SELECT fullpath, metadata
+			  FROM pathdata
+			 WHERE bucket = $1::BYTEA
+			   AND fullpath ` + cmp + ` $2::BYTEA
+			   AND ($3::BYTEA IS NULL OR fullpath < $3::BYTEA)
+			 ORDER BY fullpath
+			 LIMIT 1
+		`
+		if upper := prefixUpperBound(it.opts.Prefix); upper != nil {
+			bound = upper
+		}
+	} else {
+		cmp := "<="
+		if it.after {
+			cmp = "<"
+		}
+		query = `
+			SELECT fullpath, metadata
+			  FROM pathdata
+			 WHERE bucket = $1::BYTEA
+			   AND ($2::BYTEA = ''::BYTEA OR fullpath ` + cmp + ` $2::BYTEA)
+			   AND ($3::BYTEA IS NULL OR fullpath >= $3::BYTEA)
+			 ORDER BY fullpath DESC
+			 LIMIT 1
+		`
+		if !it.opts.Prefix.IsZero() {
+			bound = []byte(it.opts.Prefix)
+		}
+	}
+
+	row := it.db.QueryRow(query, []byte(it.bucket), []byte(it.cursor), bound)
+	err = row.Scan(&key, &value)
+	if err == sql.ErrNoRows {
+		return nil, nil, false, nil
+	}
+	if err != nil {
+		return nil, nil, false, errs.Wrap(err)
+	}
+	return key, value, true, nil
+}
+
+// Close closes the iterator
+func (it *cockroachDirectoryIterator) Close() error {
+	return it.err
+}
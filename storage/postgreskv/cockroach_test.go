@@ -0,0 +1,78 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package postgreskv
+
+import (
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/storelogger"
+	"storj.io/storj/storage/testsuite"
+)
+
+const (
+	// this connstring is expected to work under the storj-test docker-compose instance
+	defaultCockroachConn = "cockroach://root@test-cockroach:26257/teststorj?sslmode=disable"
+)
+
+var (
+	testCockroach = flag.String("cockroach-test-db", os.Getenv("STORJ_COCKROACH_TEST"), "CockroachDB test database connection string")
+)
+
+func newTestCockroach(t testing.TB) (store *Client, cleanup func()) {
+	if *testCockroach == "" {
+		t.Skipf("cockroach flag missing, example:\n-cockroach-test-db=%s", defaultCockroachConn)
+	}
+
+	db, err := NewCockroach(*testCockroach)
+	if err != nil {
+		t.Fatalf("init: %v", err)
+	}
+
+	return db, func() {
+		if err := db.Close(); err != nil {
+			t.Fatalf("failed to close db: %v", err)
+		}
+	}
+}
+
+func TestSuiteCockroach(t *testing.T) {
+	store, cleanup := newTestCockroach(t)
+	defer cleanup()
+
+	zap := zaptest.NewLogger(t)
+	testsuite.RunTests(t, storelogger.New(zap, store))
+	testsuite.RunSnapshotTests(t, store)
+}
+
+func TestPrefixUpperBound(t *testing.T) {
+	for _, tt := range []struct {
+		prefix storage.Key
+		bound  []byte
+	}{
+		{nil, nil},
+		{storage.Key(""), nil},
+		{storage.Key("a/"), []byte("a0")},
+		{storage.Key("a/b"), []byte("a/c")},
+		{storage.Key{'a', 0xFF}, []byte("b")},
+		{storage.Key{0xFF, 0xFF}, nil},
+	} {
+		assert.Equal(t, tt.bound, prefixUpperBound(tt.prefix), string(tt.prefix))
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	retry := &pq.Error{Code: cockroachSerializationFailure}
+	assert.True(t, isRetryable(retry))
+	assert.True(t, isRetryable(errs.Combine(retry, errs.New("rollback failed"))))
+	assert.False(t, isRetryable(&pq.Error{Code: "23505"}))
+	assert.False(t, isRetryable(errs.New("failure")))
+}
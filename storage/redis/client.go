@@ -4,9 +4,9 @@
 package redis
 
 import (
-	"net/url"
+	"bytes"
 	"sort"
-	"strconv"
+	"sync"
 	"time"
 
 	"github.com/go-redis/redis"
@@ -26,48 +26,45 @@ const defaultNodeExpiration = 0 * time.Minute
 
 // Client is the entrypoint into Redis
 type Client struct {
-	db  *redis.Client
+	db  redis.UniversalClient
 	TTL time.Duration
 }
 
 // NewClient returns a configured Client instance, verifying a successful connection to redis
 func NewClient(address, password string, db int) (*Client, error) {
+	return NewClientWithOptions(Options{
+		Mode:     Single,
+		Addrs:    []string{address},
+		Password: password,
+		DB:       db,
+	})
+}
+
+// NewClientWithOptions returns a configured Client instance for a single
+// instance, sentinel or cluster mode, verifying a successful connection to redis
+func NewClientWithOptions(options Options) (*Client, error) {
 	client := &Client{
-		db: redis.NewClient(&redis.Options{
-			Addr:     address,
-			Password: password,
-			DB:       db,
-		}),
+		db:  newUniversalClient(options),
 		TTL: defaultNodeExpiration,
 	}
 
 	// ping here to verify we are able to connect to redis with the initialized client.
 	if err := client.db.Ping().Err(); err != nil {
-		return nil, Error.New("ping failed: %v", err)
+		return nil, errs.Combine(Error.New("ping failed: %v", err), client.db.Close())
 	}
 
 	return client, nil
 }
 
-// NewClientFrom returns a configured Client instance from a redis address, verifying a successful connection to redis
+// NewClientFrom returns a configured Client instance from a redis address, verifying a successful connection to redis.
+// See ParseOptions for the supported address formats.
 func NewClientFrom(address string) (*Client, error) {
-	redisurl, err := url.Parse(address)
-	if err != nil {
-		return nil, err
-	}
-
-	if redisurl.Scheme != "redis" {
-		return nil, Error.New("not a redis:// formatted address")
-	}
-
-	q := redisurl.Query()
-
-	db, err := strconv.Atoi(q.Get("db"))
+	options, err := ParseOptions(address)
 	if err != nil {
 		return nil, err
 	}
 
-	return NewClient(redisurl.Host, q.Get("password"), db)
+	return NewClientWithOptions(options)
 }
 
 // Get looks up the provided key from redis returning either an error or the result.
@@ -117,6 +114,52 @@ func (client *Client) Delete(key storage.Key) error {
 	return nil
 }
 
+// CompareAndSwap atomically compares and swaps oldValue with newValue
+func (client *Client) CompareAndSwap(key storage.Key, oldValue, newValue storage.Value) error {
+	if key.IsZero() {
+		return storage.ErrEmptyKey.New("")
+	}
+
+	txf := func(tx *redis.Tx) error {
+		value, err := tx.Get(key.String()).Bytes()
+		if err == redis.Nil {
+			if oldValue != nil {
+				return storage.ErrKeyNotFound.New(key.String())
+			}
+			if newValue == nil {
+				return nil
+			}
+		} else if err != nil {
+			return Error.New("get error: %v", err)
+		} else if oldValue == nil || !bytes.Equal(value, oldValue) {
+			return storage.ErrValueChanged.New(key.String())
+		}
+
+		_, err = tx.Pipelined(func(pipe redis.Pipeliner) error {
+			if newValue == nil {
+				pipe.Del(key.String())
+			} else {
+				pipe.Set(key.String(), []byte(newValue), client.TTL)
+			}
+			return nil
+		})
+		if err == redis.TxFailedErr {
+			// the key was modified since it was watched
+			return storage.ErrValueChanged.New(key.String())
+		}
+		if err != nil {
+			return Error.New("compare and swap error: %v", err)
+		}
+		return nil
+	}
+
+	err := client.db.Watch(txf, key.String())
+	if err == redis.TxFailedErr {
+		return storage.ErrValueChanged.New(key.String())
+	}
+	return err
+}
+
 // Close closes a redis client
 func (client *Client) Close() error {
 	return client.db.Close()
@@ -135,6 +178,10 @@ func (client *Client) GetAll(keys storage.Keys) (storage.Values, error) {
 		keyStrings[i] = v.String()
 	}
 
+	if cluster, ok := client.db.(*redis.ClusterClient); ok {
+		return getAllFromCluster(cluster, keyStrings)
+	}
+
 	results, err := client.db.MGet(keyStrings...).Result()
 	if err != nil {
 		return nil, err
@@ -155,8 +202,13 @@ func (client *Client) GetAll(keys storage.Keys) (storage.Values, error) {
 	return values, nil
 }
 
-// Iterate iterates over items based on opts
+// Iterate iterates over items based on opts. Redis can't list the keys and
+// get their values atomically, so snapshot iteration isn't supported.
 func (client *Client) Iterate(opts storage.IterateOptions, fn func(it storage.Iterator) error) error {
+	if opts.Snapshot {
+		return Error.New("snapshot iteration is not supported")
+	}
+
 	var all storage.Items
 	var err error
 	if !opts.Reverse {
@@ -180,30 +232,93 @@ func (client *Client) Iterate(opts storage.IterateOptions, fn func(it storage.It
 
 // FlushDB deletes all keys in the currently selected DB.
 func (client *Client) FlushDB() error {
+	if cluster, ok := client.db.(*redis.ClusterClient); ok {
+		return cluster.ForEachMaster(func(master *redis.Client) error {
+			return master.FlushDB().Err()
+		})
+	}
+
 	_, err := client.db.FlushDB().Result()
 	return err
 }
 
+// getAllFromCluster gets the values of the keys one by one, since the keys
+// of a single MGET must belong to the same hash slot in a cluster.
+func getAllFromCluster(cluster *redis.ClusterClient, keys []string) (storage.Values, error) {
+	cmds := make([]*redis.StringCmd, len(keys))
+	_, err := cluster.Pipelined(func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = pipe.Get(key)
+		}
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	values := make(storage.Values, 0, len(keys))
+	for _, cmd := range cmds {
+		value, err := cmd.Bytes()
+		if err == redis.Nil {
+			values = append(values, nil)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, storage.Value(value))
+	}
+	return values, nil
+}
+
+// scan calls fn for the keys matching the pattern. In the cluster mode every
+// master is scanned.
+func (client *Client) scan(match string, fn func(key string)) error {
+	if cluster, ok := client.db.(*redis.ClusterClient); ok {
+		var mu sync.Mutex
+		return cluster.ForEachMaster(func(master *redis.Client) error {
+			it := master.Scan(0, match, 0).Iterator()
+			for it.Next() {
+				mu.Lock()
+				fn(it.Val())
+				mu.Unlock()
+			}
+			return it.Err()
+		})
+	}
+
+	it := client.db.Scan(0, match, 0).Iterator()
+	for it.Next() {
+		fn(it.Val())
+	}
+	return it.Err()
+}
+
 func (client *Client) allPrefixedItems(prefix, first, last storage.Key) (storage.Items, error) {
-	var all storage.Items
+	var keys []string
 	seen := map[string]struct{}{}
 
 	match := string(escapeMatch([]byte(prefix))) + "*"
-	it := client.db.Scan(0, match, 0).Iterator()
-	for it.Next() {
-		key := it.Val()
+	err := client.scan(match, func(key string) {
 		if !first.IsZero() && storage.Key(key).Less(first) {
-			continue
+			return
 		}
 		if !last.IsZero() && last.Less(storage.Key(key)) {
-			continue
+			return
 		}
 
 		if _, ok := seen[key]; ok {
-			continue
+			return
 		}
 		seen[key] = struct{}{}
+		keys = append(keys, key)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	var all storage.Items
+	for _, key := range keys {
 		value, err := client.db.Get(key).Bytes()
 		if err != nil {
 			return nil, err
@@ -6,6 +6,7 @@ package redis
 import (
 	"testing"
 
+	"storj.io/storj/storage"
 	"storj.io/storj/storage/redis/redisserver"
 	"storj.io/storj/storage/testsuite"
 )
@@ -23,6 +24,11 @@ func TestSuite(t *testing.T) {
 	}
 
 	testsuite.RunTests(t, client)
+
+	err = client.Iterate(storage.IterateOptions{Snapshot: true}, func(storage.Iterator) error { return nil })
+	if err == nil {
+		t.Fatal("expected snapshot iteration to fail")
+	}
 }
 
 func TestInvalidConnection(t *testing.T) {
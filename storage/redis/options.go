@@ -0,0 +1,133 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package redis
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-redis/redis"
+)
+
+// Mode is the way of connecting to redis
+type Mode int
+
+const (
+	// Single connects to a single redis instance
+	Single Mode = iota
+	// Sentinel connects to the master of a redis instance monitored by
+	// sentinels, following it when it fails over
+	Sentinel
+	// Cluster connects to a redis cluster
+	Cluster
+)
+
+// defaultFailoverRetries is the number of times a failed command is retried
+// in the sentinel and cluster modes, where commands can fail while the
+// nodes fail over
+const defaultFailoverRetries = 3
+
+// Options are the options for connecting to redis
+type Options struct {
+	Mode Mode
+	// Addrs are the addresses of the instance, the sentinels or the cluster
+	// nodes, depending on the mode
+	Addrs []string
+	// MasterName is the name of the master monitored by the sentinels
+	MasterName string
+	Password   string
+	DB         int
+	// MaxRetries is the number of times a failed command is retried
+	MaxRetries int
+}
+
+// ParseOptions parses the options from a redis address. The address is one of
+//
+//	redis://host:port?db=0&password=secret
+//	redis-sentinel://host:port,host:port?master=name&db=0&password=secret
+//	redis-cluster://host:port,host:port?password=secret
+//
+// The number of retries can be set with the retries query parameter.
+func ParseOptions(address string) (Options, error) {
+	redisurl, err := url.Parse(address)
+	if err != nil {
+		return Options{}, err
+	}
+
+	q := redisurl.Query()
+
+	var options Options
+	switch redisurl.Scheme {
+	case "redis":
+		options.Mode = Single
+	case "redis-sentinel":
+		options.Mode = Sentinel
+		options.MaxRetries = defaultFailoverRetries
+		options.MasterName = q.Get("master")
+		if options.MasterName == "" {
+			return Options{}, Error.New("master name is required for redis-sentinel:// formatted addresses")
+		}
+	case "redis-cluster":
+		options.Mode = Cluster
+		options.MaxRetries = defaultFailoverRetries
+	default:
+		return Options{}, Error.New("not a redis://, redis-sentinel:// or redis-cluster:// formatted address")
+	}
+
+	options.Addrs = strings.Split(redisurl.Host, ",")
+	options.Password = q.Get("password")
+
+	if options.Mode == Cluster {
+		// redis cluster supports only the database 0
+		if db := q.Get("db"); db != "" && db != "0" {
+			return Options{}, Error.New("redis cluster supports only db 0")
+		}
+	} else {
+		options.DB, err = strconv.Atoi(q.Get("db"))
+		if err != nil {
+			return Options{}, err
+		}
+	}
+
+	if retries := q.Get("retries"); retries != "" {
+		options.MaxRetries, err = strconv.Atoi(retries)
+		if err != nil {
+			return Options{}, err
+		}
+	}
+
+	return options, nil
+}
+
+// newUniversalClient creates the redis client for the options
+func newUniversalClient(options Options) redis.UniversalClient {
+	switch options.Mode {
+	case Sentinel:
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:    options.MasterName,
+			SentinelAddrs: options.Addrs,
+			Password:      options.Password,
+			DB:            options.DB,
+			MaxRetries:    options.MaxRetries,
+		})
+	case Cluster:
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:      options.Addrs,
+			Password:   options.Password,
+			MaxRetries: options.MaxRetries,
+		})
+	default:
+		var addr string
+		if len(options.Addrs) > 0 {
+			addr = options.Addrs[0]
+		}
+		return redis.NewClient(&redis.Options{
+			Addr:       addr,
+			Password:   options.Password,
+			DB:         options.DB,
+			MaxRetries: options.MaxRetries,
+		})
+	}
+}
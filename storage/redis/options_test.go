@@ -0,0 +1,65 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package redis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/storage/redis/redisserver"
+	"storj.io/storj/storage/testsuite"
+)
+
+func TestParseOptions(t *testing.T) {
+	for _, tt := range []struct {
+		address string
+		options Options
+	}{
+		{"redis://127.0.0.1:6379?db=2&password=secret", Options{
+			Mode: Single, Addrs: []string{"127.0.0.1:6379"}, Password: "secret", DB: 2,
+		}},
+		{"redis://127.0.0.1:6379?db=0&retries=5", Options{
+			Mode: Single, Addrs: []string{"127.0.0.1:6379"}, MaxRetries: 5,
+		}},
+		{"redis-sentinel://10.0.0.1:26379,10.0.0.2:26379?master=main&db=1", Options{
+			Mode: Sentinel, Addrs: []string{"10.0.0.1:26379", "10.0.0.2:26379"}, MasterName: "main", DB: 1,
+			MaxRetries: defaultFailoverRetries,
+		}},
+		{"redis-cluster://10.0.0.1:6379,10.0.0.2:6379,10.0.0.3:6379?password=secret&retries=0", Options{
+			Mode: Cluster, Addrs: []string{"10.0.0.1:6379", "10.0.0.2:6379", "10.0.0.3:6379"}, Password: "secret",
+		}},
+		{"redis-cluster://10.0.0.1:6379?db=0", Options{
+			Mode: Cluster, Addrs: []string{"10.0.0.1:6379"}, MaxRetries: defaultFailoverRetries,
+		}},
+	} {
+		options, err := ParseOptions(tt.address)
+		require.NoError(t, err, tt.address)
+		assert.Equal(t, tt.options, options, tt.address)
+	}
+
+	for _, address := range []string{
+		"http://127.0.0.1:6379?db=0",
+		"redis://127.0.0.1:6379",
+		"redis://127.0.0.1:6379?db=0&retries=many",
+		"redis-sentinel://10.0.0.1:26379?db=0",
+		"redis-cluster://10.0.0.1:6379?db=1",
+	} {
+		_, err := ParseOptions(address)
+		assert.Error(t, err, address)
+	}
+}
+
+func TestSuiteFromAddress(t *testing.T) {
+	addr, cleanup, err := redisserver.Start()
+	require.NoError(t, err)
+	defer cleanup()
+
+	client, err := NewClientFrom("redis://" + addr + "?db=1&retries=2")
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, client.Close()) }()
+
+	testsuite.RunTests(t, client)
+}
@@ -0,0 +1,92 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package teststore
+
+import (
+	"bytes"
+	"time"
+
+	"storj.io/storj/storage"
+)
+
+// Faults configures the errors and the latency injected into the operations
+// of the store. The operations are counted from the first one, so the faults
+// are deterministic.
+type Faults struct {
+	// FailEvery fails every Nth operation, when it's greater than zero
+	FailEvery int
+	// FailPrefixes fails the operations on keys with any of the prefixes.
+	// Iterations fail when they could list a key with any of the prefixes.
+	FailPrefixes []storage.Key
+	// Latency delays every operation. The store is locked during the delay,
+	// so the operations stay serialized.
+	Latency time.Duration
+	// Err is the error returned by the failed operations, errInternal when nil
+	Err error
+}
+
+// SetFaults sets the faults injected into the operations and resets the count
+// of operations
+func (store *Client) SetFaults(faults Faults) {
+	defer store.locked()()
+
+	store.faults = faults
+	store.operations = 0
+}
+
+// forcedError returns the error to fail the operation on the keys with, or
+// nil when the operation shouldn't fail. It must be called with the store
+// locked.
+func (store *Client) forcedError(keys ...storage.Key) error {
+	store.operations++
+
+	if store.faults.Latency > 0 {
+		time.Sleep(store.faults.Latency)
+	}
+
+	if store.ForceError > 0 {
+		store.ForceError--
+		return store.faultError()
+	}
+
+	if store.faults.FailEvery > 0 && store.operations%store.faults.FailEvery == 0 {
+		return store.faultError()
+	}
+
+	for _, key := range keys {
+		for _, prefix := range store.faults.FailPrefixes {
+			if bytes.HasPrefix(key, prefix) {
+				return store.faultError()
+			}
+		}
+	}
+
+	return nil
+}
+
+// forcedIterateError returns the error to fail an iteration over the keys
+// with the prefix with, or nil when it shouldn't fail. It must be called with
+// the store locked.
+func (store *Client) forcedIterateError(prefix storage.Key) error {
+	if err := store.forcedError(); err != nil {
+		return err
+	}
+
+	for _, failPrefix := range store.faults.FailPrefixes {
+		// the iteration lists keys with the failing prefix when either of
+		// the prefixes contains the other
+		if bytes.HasPrefix(prefix, failPrefix) || bytes.HasPrefix(failPrefix, prefix) {
+			return store.faultError()
+		}
+	}
+	return nil
+}
+
+// faultError returns the error of a failed operation
+func (store *Client) faultError() error {
+	if store.faults.Err != nil {
+		return store.faults.Err
+	}
+	return errInternal
+}
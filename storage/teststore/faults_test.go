@@ -0,0 +1,68 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package teststore
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/storage"
+)
+
+func TestFaultsFailEvery(t *testing.T) {
+	store := New()
+	store.SetFaults(Faults{FailEvery: 3})
+
+	var failed []int
+	for i := 1; i <= 9; i++ {
+		if err := store.Put(storage.Key("key"), storage.Value("value")); err != nil {
+			assert.Equal(t, errInternal, err)
+			failed = append(failed, i)
+		}
+	}
+	assert.Equal(t, []int{3, 6, 9}, failed)
+
+	store.SetFaults(Faults{})
+	require.NoError(t, store.Put(storage.Key("key"), storage.Value("value")))
+}
+
+func TestFaultsFailPrefixes(t *testing.T) {
+	store := New()
+	require.NoError(t, store.Put(storage.Key("a/1"), storage.Value("1")))
+	require.NoError(t, store.Put(storage.Key("b/1"), storage.Value("1")))
+
+	errFault := errors.New("fault")
+	store.SetFaults(Faults{
+		FailPrefixes: []storage.Key{storage.Key("b/")},
+		Err:          errFault,
+	})
+
+	_, err := store.Get(storage.Key("a/1"))
+	assert.NoError(t, err)
+	_, err = store.Get(storage.Key("b/1"))
+	assert.Equal(t, errFault, err)
+	assert.Equal(t, errFault, store.Put(storage.Key("b/2"), storage.Value("2")))
+	assert.Equal(t, errFault, store.Delete(storage.Key("b/1")))
+	_, err = store.GetAll(storage.Keys{storage.Key("a/1"), storage.Key("b/1")})
+	assert.Equal(t, errFault, err)
+
+	noop := func(storage.Iterator) error { return nil }
+	assert.NoError(t, store.Iterate(storage.IterateOptions{Prefix: storage.Key("a/")}, noop))
+	assert.Equal(t, errFault, store.Iterate(storage.IterateOptions{Prefix: storage.Key("b/1")}, noop))
+	assert.Equal(t, errFault, store.Iterate(storage.IterateOptions{Recurse: true}, noop))
+}
+
+func TestFaultsLatency(t *testing.T) {
+	store := New()
+	store.SetFaults(Faults{Latency: 10 * time.Millisecond})
+
+	start := time.Now()
+	_, err := store.Get(storage.Key("key"))
+	assert.True(t, storage.ErrKeyNotFound.Has(err))
+	assert.True(t, time.Since(start) >= 10*time.Millisecond)
+}
@@ -22,17 +22,21 @@ type Client struct {
 	ForceError int
 
 	CallCount struct {
-		Get         int
-		Put         int
-		List        int
-		GetAll      int
-		ReverseList int
-		Delete      int
-		Close       int
-		Iterate     int
+		Get            int
+		Put            int
+		List           int
+		GetAll         int
+		ReverseList    int
+		Delete         int
+		CompareAndSwap int
+		Close          int
+		Iterate        int
 	}
 
 	version int
+
+	faults     Faults
+	operations int
 }
 
 // New creates a new in-memory key-value store
@@ -55,22 +59,14 @@ func (store *Client) locked() func() {
 	return store.mu.Unlock
 }
 
-func (store *Client) forcedError() bool {
-	if store.ForceError > 0 {
-		store.ForceError--
-		return true
-	}
-	return false
-}
-
 // Put adds a value to store
 func (store *Client) Put(key storage.Key, value storage.Value) error {
 	defer store.locked()()
 
 	store.version++
 	store.CallCount.Put++
-	if store.forcedError() {
-		return errInternal
+	if err := store.forcedError(key); err != nil {
+		return err
 	}
 
 	if key.IsZero() {
@@ -100,8 +96,8 @@ func (store *Client) Get(key storage.Key) (storage.Value, error) {
 
 	store.CallCount.Get++
 
-	if store.forcedError() {
-		return nil, errors.New("internal error")
+	if err := store.forcedError(key); err != nil {
+		return nil, err
 	}
 
 	if key.IsZero() {
@@ -125,8 +121,8 @@ func (store *Client) GetAll(keys storage.Keys) (storage.Values, error) {
 		return nil, storage.ErrLimitExceeded
 	}
 
-	if store.forcedError() {
-		return nil, errors.New("internal error")
+	if err := store.forcedError(keys...); err != nil {
+		return nil, err
 	}
 
 	values := storage.Values{}
@@ -148,8 +144,8 @@ func (store *Client) Delete(key storage.Key) error {
 	store.version++
 	store.CallCount.Delete++
 
-	if store.forcedError() {
-		return errInternal
+	if err := store.forcedError(key); err != nil {
+		return err
 	}
 
 	if key.IsZero() {
@@ -166,13 +162,60 @@ func (store *Client) Delete(key storage.Key) error {
 	return nil
 }
 
+// CompareAndSwap atomically compares and swaps oldValue with newValue
+func (store *Client) CompareAndSwap(key storage.Key, oldValue, newValue storage.Value) error {
+	defer store.locked()()
+
+	store.version++
+	store.CallCount.CompareAndSwap++
+	if err := store.forcedError(key); err != nil {
+		return err
+	}
+
+	if key.IsZero() {
+		return storage.ErrEmptyKey.New("")
+	}
+
+	keyIndex, found := store.indexOf(key)
+	if !found {
+		if oldValue != nil {
+			return storage.ErrKeyNotFound.New(key.String())
+		}
+		if newValue == nil {
+			return nil
+		}
+
+		store.Items = append(store.Items, storage.ListItem{})
+		copy(store.Items[keyIndex+1:], store.Items[keyIndex:])
+		store.Items[keyIndex] = storage.ListItem{
+			Key:   storage.CloneKey(key),
+			Value: storage.CloneValue(newValue),
+		}
+		return nil
+	}
+
+	kv := &store.Items[keyIndex]
+	if oldValue == nil || !bytes.Equal(kv.Value, oldValue) {
+		return storage.ErrValueChanged.New(key.String())
+	}
+
+	if newValue == nil {
+		copy(store.Items[keyIndex:], store.Items[keyIndex+1:])
+		store.Items = store.Items[:len(store.Items)-1]
+		return nil
+	}
+
+	kv.Value = storage.CloneValue(newValue)
+	return nil
+}
+
 // List lists all keys starting from start and upto limit items
 func (store *Client) List(first storage.Key, limit int) (storage.Keys, error) {
 	store.mu.Lock()
 	store.CallCount.List++
-	if store.forcedError() {
+	if err := store.forcedError(); err != nil {
 		store.mu.Unlock()
-		return nil, errors.New("internal error")
+		return nil, err
 	}
 	store.mu.Unlock()
 	return storage.ListKeys(store, first, limit)
@@ -183,19 +226,20 @@ func (store *Client) Close() error {
 	defer store.locked()()
 
 	store.CallCount.Close++
-	if store.forcedError() {
-		return errInternal
+	if err := store.forcedError(); err != nil {
+		return err
 	}
 	return nil
 }
 
-// Iterate iterates over items based on opts
+// Iterate iterates over items based on opts. The store is locked during the
+// iteration, so it always sees a snapshot of the store.
 func (store *Client) Iterate(opts storage.IterateOptions, fn func(storage.Iterator) error) error {
 	defer store.locked()()
 
 	store.CallCount.Iterate++
-	if store.forcedError() {
-		return errInternal
+	if err := store.forcedIterateError(opts.Prefix); err != nil {
+		return err
 	}
 
 	var cursor advancer
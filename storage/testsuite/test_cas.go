@@ -0,0 +1,63 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package testsuite
+
+import (
+	"bytes"
+	"testing"
+
+	"storj.io/storj/storage"
+)
+
+func testCompareAndSwap(t *testing.T, store storage.KeyValueStore) {
+	key := storage.Key("cas/key")
+	defer func() { _ = store.Delete(key) }()
+
+	t.Run("Insert missing key", func(t *testing.T) {
+		if err := store.CompareAndSwap(key, nil, storage.Value("a")); err != nil {
+			t.Fatalf("failed to insert: %v", err)
+		}
+		if err := store.CompareAndSwap(key, nil, storage.Value("b")); !storage.ErrValueChanged.Has(err) {
+			t.Fatalf("inserting an existing key should fail with value changed: %v", err)
+		}
+	})
+
+	t.Run("Swap", func(t *testing.T) {
+		if err := store.CompareAndSwap(key, storage.Value("a"), storage.Value("b")); err != nil {
+			t.Fatalf("failed to swap: %v", err)
+		}
+		if err := store.CompareAndSwap(key, storage.Value("a"), storage.Value("c")); !storage.ErrValueChanged.Has(err) {
+			t.Fatalf("swapping a changed value should fail with value changed: %v", err)
+		}
+
+		value, err := store.Get(key)
+		if err != nil {
+			t.Fatalf("failed to get: %v", err)
+		}
+		if !bytes.Equal(value, storage.Value("b")) {
+			t.Fatalf("invalid value: got %q", value)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		if err := store.CompareAndSwap(key, storage.Value("a"), nil); !storage.ErrValueChanged.Has(err) {
+			t.Fatalf("deleting a changed value should fail with value changed: %v", err)
+		}
+		if err := store.CompareAndSwap(key, storage.Value("b"), nil); err != nil {
+			t.Fatalf("failed to delete: %v", err)
+		}
+		if _, err := store.Get(key); !storage.ErrKeyNotFound.Has(err) {
+			t.Fatalf("the key should be deleted: %v", err)
+		}
+	})
+
+	t.Run("Missing key", func(t *testing.T) {
+		if err := store.CompareAndSwap(key, storage.Value("b"), storage.Value("c")); !storage.ErrKeyNotFound.Has(err) {
+			t.Fatalf("swapping a missing key should fail with key not found: %v", err)
+		}
+		if err := store.CompareAndSwap(key, nil, nil); err != nil {
+			t.Fatalf("deleting a missing key should succeed: %v", err)
+		}
+	})
+}
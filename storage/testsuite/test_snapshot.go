@@ -0,0 +1,88 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package testsuite
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"storj.io/storj/storage"
+)
+
+// RunSnapshotTests runs the tests of snapshot iteration. The store is
+// modified from another goroutine during the iteration, so it must not
+// block writes while iterating.
+func RunSnapshotTests(t *testing.T, store storage.KeyValueStore) {
+	t.Run("Snapshot", func(t *testing.T) { testSnapshot(t, store) })
+}
+
+func testSnapshot(t *testing.T, store storage.KeyValueStore) {
+	items := storage.Items{
+		newItem("a", "a", false),
+		newItem("b/1", "b/1", false),
+		newItem("b/2", "b/2", false),
+		newItem("c", "c", false),
+		newItem("d", "d", false),
+	}
+	defer cleanupItems(store, items)
+	defer func() { _ = store.Delete(storage.Key("e")) }()
+	if err := storage.PutAll(store, items...); err != nil {
+		t.Fatalf("failed to setup: %v", err)
+	}
+
+	for _, recurse := range []bool{true, false} {
+		var got storage.Items
+		err := store.Iterate(storage.IterateOptions{Recurse: recurse, Snapshot: true}, func(it storage.Iterator) error {
+			var item storage.ListItem
+			if !it.Next(&item) {
+				return nil
+			}
+			got = append(got, storage.CloneItem(item))
+
+			// modify the store while iterating
+			done := make(chan error, 1)
+			go func() {
+				err := storage.PutAll(store, newItem("c", "changed", false), newItem("e", "e", false))
+				if err == nil {
+					err = store.Delete(storage.Key("d"))
+				}
+				done <- err
+			}()
+			if err := <-done; err != nil {
+				return err
+			}
+
+			for it.Next(&item) {
+				got = append(got, storage.CloneItem(item))
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("recurse %v: %v", recurse, err)
+		}
+
+		expected := items
+		if !recurse {
+			expected = storage.Items{
+				newItem("a", "a", false),
+				newItem("b/", "", true),
+				newItem("c", "c", false),
+				newItem("d", "d", false),
+			}
+		}
+		if diff := cmp.Diff(expected, got, cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("recurse %v: (-want +got)\n%s", recurse, diff)
+		}
+
+		// restore the store for the next iteration
+		if err := storage.PutAll(store, items...); err != nil {
+			t.Fatalf("failed to restore: %v", err)
+		}
+		if err := store.Delete(storage.Key("e")); err != nil {
+			t.Fatalf("failed to restore: %v", err)
+		}
+	}
+}
@@ -97,5 +97,36 @@ func TestDB(t *testing.T) {
 		usageBySatellite, err = bandwidthdb.SummaryBySatellite(ctx, now.Add(time.Hour), now.Add(10*time.Hour))
 		require.NoError(t, err)
 		require.Equal(t, expectedUsageBySatellite, usageBySatellite)
+
+		// rolling up keeps the summaries
+		rolledUp, err := bandwidthdb.Rollup(ctx, now.Add(3*time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, int64(2*len(actions)), rolledUp)
+
+		usage, err = bandwidthdb.Summary(ctx, now.Add(-10*time.Hour), now.Add(10*time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, expectedUsageTotal, usage)
+
+		usageBySatellite, err = bandwidthdb.SummaryBySatellite(ctx, now.Add(time.Hour), now.Add(10*time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, expectedUsageBySatellite, usageBySatellite)
+
+		// usage added to a rolled up hour is rolled up into it
+		err = bandwidthdb.Add(ctx, satellite0, pb.PieceAction_PUT, 1, now)
+		require.NoError(t, err)
+		_, err = bandwidthdb.Rollup(ctx, now.Add(3*time.Hour))
+		require.NoError(t, err)
+		expectedUsageTotal.Include(pb.PieceAction_PUT, 1)
+
+		summaries, err := bandwidthdb.DailySummaries(ctx, now.Add(-24*time.Hour), now.Add(24*time.Hour))
+		require.NoError(t, err)
+		var ingress, egress int64
+		for _, summary := range summaries {
+			require.Equal(t, summary.Day, summary.Day.Truncate(24*time.Hour))
+			ingress += summary.Ingress
+			egress += summary.Egress
+		}
+		require.Equal(t, expectedUsageTotal.Ingress(), ingress)
+		require.Equal(t, expectedUsageTotal.Egress(), egress)
 	})
 }
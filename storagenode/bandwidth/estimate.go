@@ -0,0 +1,36 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package bandwidth
+
+import (
+	"time"
+
+	"storj.io/storj/pkg/accounting"
+	"storj.io/storj/pkg/accounting/payments"
+)
+
+// EstimatePayout estimates the egress and the gross payout, in micro dollars,
+// of a satellite for the month from start to end. The egress from start until
+// now is extrapolated to the whole month and the stored bytes are assumed to
+// stay the same until the end of the month.
+func EstimatePayout(config payments.Config, usage Usage, stored int64, start, end, now time.Time) (egress Usage, gross int64) {
+	elapsed := now.Sub(start)
+	if elapsed <= 0 {
+		elapsed = time.Second
+	}
+	scale := float64(end.Sub(start)) / float64(elapsed)
+
+	egress = Usage{
+		Get:       int64(float64(usage.Get) * scale),
+		GetAudit:  int64(float64(usage.GetAudit) * scale),
+		GetRepair: int64(float64(usage.GetRepair) * scale),
+	}
+	gross = payments.Gross(config, &accounting.CSVRow{
+		AtRestTotal:    float64(stored) * end.Sub(start).Hours(),
+		GetTotal:       egress.Get,
+		GetAuditTotal:  egress.GetAudit,
+		GetRepairTotal: egress.GetRepair,
+	})
+	return egress, gross
+}
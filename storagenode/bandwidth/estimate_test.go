@@ -0,0 +1,40 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package bandwidth_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/pkg/accounting/payments"
+	"storj.io/storj/storagenode/bandwidth"
+)
+
+func TestEstimatePayout(t *testing.T) {
+	config := payments.Config{
+		AtRestPrice:       1.5,
+		EgressPrice:       20,
+		RepairEgressPrice: 10,
+		AuditEgressPrice:  10,
+		SurgePercent:      100,
+	}
+
+	start := time.Date(2019, 4, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	// a third of the month elapsed
+	now := start.Add(10 * 24 * time.Hour)
+
+	// 1TB stored during the 720 hours of the month is $1.50
+	egress, gross := bandwidth.EstimatePayout(config, bandwidth.Usage{}, 1e12, start, end, now)
+	assert.Equal(t, int64(0), egress.Total())
+	assert.Equal(t, int64(1500000), gross)
+
+	// 0.1TB of egress so far is extrapolated to 0.3TB, which is $6, the
+	// ingress isn't paid
+	egress, gross = bandwidth.EstimatePayout(config, bandwidth.Usage{Get: 1e11, Put: 1e12}, 0, start, end, now)
+	assert.Equal(t, bandwidth.Usage{Get: 3e11}, egress)
+	assert.Equal(t, int64(6000000), gross)
+}
@@ -0,0 +1,79 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package bandwidth
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/internal/sync2"
+)
+
+var (
+	// Error is the default error class for the bandwidth usage.
+	Error = errs.Class("bandwidth")
+	mon   = monkit.Package()
+)
+
+// Config defines parameters for the bandwidth usage rollups.
+type Config struct {
+	Interval time.Duration `help:"how frequently bandwidth usage is rolled up into hourly rollups" default:"1h0m0s"`
+}
+
+// Service implements rolling up the bandwidth usage on the storage node.
+type Service struct {
+	log *zap.Logger
+	db  DB
+
+	Loop sync2.Cycle
+}
+
+// NewService creates a new bandwidth rollup service.
+func NewService(log *zap.Logger, db DB, config Config) *Service {
+	return &Service{
+		log: log,
+		db:  db,
+
+		Loop: *sync2.NewCycle(config.Interval),
+	}
+}
+
+// Run rolls up the bandwidth usage on every interval.
+func (service *Service) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return service.Loop.Run(ctx, func(ctx context.Context) error {
+		if err := service.Rollup(ctx, time.Now()); err != nil {
+			service.log.Error("rolling up bandwidth usage", zap.Error(err))
+		}
+		return nil
+	})
+}
+
+// Close stops the bandwidth rollup service.
+func (service *Service) Close() error {
+	service.Loop.Close()
+	return nil
+}
+
+// Rollup rolls up the bandwidth usage of the hours which ended before now.
+func (service *Service) Rollup(ctx context.Context, now time.Time) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	// NB: the current hour is left alone, usage is still being added to it
+	rolledUp, err := service.db.Rollup(ctx, now.UTC().Truncate(time.Hour))
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	mon.IntVal("bandwidth_usage_rolled_up").Observe(rolledUp)
+	if rolledUp > 0 {
+		service.log.Debug("rolled up bandwidth usage", zap.Int64("count", rolledUp))
+	}
+	return nil
+}
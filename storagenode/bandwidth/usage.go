@@ -16,6 +16,22 @@ type DB interface {
 	Add(ctx context.Context, satelliteID storj.NodeID, action pb.PieceAction, amount int64, created time.Time) error
 	Summary(ctx context.Context, from, to time.Time) (*Usage, error)
 	SummaryBySatellite(ctx context.Context, from, to time.Time) (map[storj.NodeID]*Usage, error)
+	// DailySummaries returns the ingress and egress of each satellite on
+	// each day from the day of from until the day of to, in UTC.
+	DailySummaries(ctx context.Context, from, to time.Time) ([]Summary, error)
+	// Rollup sums the bandwidth usage created before into hourly rollups,
+	// returning the number of rolled up usages.
+	Rollup(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Summary contains the ingress and egress of a satellite on a day.
+type Summary struct {
+	SatelliteID storj.NodeID
+	// Day is the start of the day in UTC
+	Day time.Time
+
+	Ingress int64
+	Egress  int64
 }
 
 // Usage contains bandwidth usage information based on the type
@@ -65,6 +81,16 @@ func (usage *Usage) Add(b *Usage) {
 	usage.Delete += b.Delete
 }
 
+// Ingress sums the bandwidth of the uploads.
+func (usage *Usage) Ingress() int64 {
+	return usage.Put + usage.PutRepair
+}
+
+// Egress sums the bandwidth of the downloads.
+func (usage *Usage) Egress() int64 {
+	return usage.Get + usage.GetAudit + usage.GetRepair
+}
+
 // Total sums all type of bandwidths
 func (usage *Usage) Total() int64 {
 	return usage.Invalid +
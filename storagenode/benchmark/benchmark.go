@@ -0,0 +1,242 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package benchmark
+
+import (
+	"context"
+	"crypto/rand"
+	"io"
+	"io/ioutil"
+	"math"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/zeebo/errs"
+	"golang.org/x/sync/errgroup"
+
+	"storj.io/storj/internal/memory"
+	"storj.io/storj/pkg/identity"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storagenode/orders"
+	"storj.io/storj/storagenode/pieces"
+)
+
+// Error is the default error class for the benchmark.
+var Error = errs.Class("benchmark")
+
+// Config defines the operations benchmarking the storage node hardware.
+type Config struct {
+	Concurrency int         `help:"how many operations run at once, like concurrent uploads" default:"4"`
+	PieceSize   memory.Size `help:"size of the written, read and deleted pieces" default:"2MiB"`
+	Pieces      int         `help:"how many pieces are written, read and deleted" default:"200"`
+	Orders      int         `help:"how many orders are enqueued and archived" default:"2000"`
+}
+
+// Result is the measure of an operation run several times.
+type Result struct {
+	Name       string
+	Operations int
+	// Bytes is the number of bytes transferred by the operations, zero for
+	// the operations transferring no data
+	Bytes   int64
+	Elapsed time.Duration
+	// Latencies are the durations of the operations, from the fastest to the
+	// slowest
+	Latencies []time.Duration
+}
+
+// OperationsPerSecond returns how many operations completed per second.
+func (result Result) OperationsPerSecond() float64 {
+	return float64(result.Operations) / result.Elapsed.Seconds()
+}
+
+// BytesPerSecond returns how many bytes were transferred per second.
+func (result Result) BytesPerSecond() float64 {
+	return float64(result.Bytes) / result.Elapsed.Seconds()
+}
+
+// Percentile returns the latency which the percentage p of the operations
+// didn't exceed, using the nearest rank.
+func (result Result) Percentile(p float64) time.Duration {
+	if len(result.Latencies) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(result.Latencies))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(result.Latencies) {
+		rank = len(result.Latencies)
+	}
+	return result.Latencies[rank-1]
+}
+
+// Run benchmarks writing, reading and deleting pieces in store, then
+// enqueuing and archiving orders in ordersDB. The written pieces are deleted
+// and the enqueued orders archived by the benchmark itself.
+func Run(ctx context.Context, store *pieces.Store, ordersDB orders.DB, config Config) (results []Result, err error) {
+	if config.Concurrency < 1 {
+		return nil, Error.New("concurrency must be positive, got %d", config.Concurrency)
+	}
+
+	var satelliteID storj.NodeID
+	if _, err := rand.Read(satelliteID[:]); err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	data := make([]byte, config.PieceSize.Int())
+	if _, err := rand.Read(data); err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	pieceIDs := make([]storj.PieceID, config.Pieces)
+	for i := range pieceIDs {
+		pieceIDs[i] = storj.NewPieceID()
+	}
+
+	infos, err := generateOrders(ctx, satelliteID, config.Orders)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	benchmarks := []struct {
+		name  string
+		count int
+		bytes int64
+		op    func(ctx context.Context, i int) error
+	}{
+		{"Piece/Write", len(pieceIDs), int64(len(data)), func(ctx context.Context, i int) error {
+			writer, err := store.Writer(ctx, satelliteID, pieceIDs[i], int64(len(data)))
+			if err != nil {
+				return err
+			}
+			if _, err := writer.Write(data); err != nil {
+				return errs.Combine(err, writer.Cancel())
+			}
+			return writer.Commit(&pb.PieceHeader{})
+		}},
+		{"Piece/Read", len(pieceIDs), int64(len(data)), func(ctx context.Context, i int) (err error) {
+			reader, err := store.Reader(ctx, satelliteID, pieceIDs[i])
+			if err != nil {
+				return err
+			}
+			defer func() { err = errs.Combine(err, reader.Close()) }()
+			_, err = io.CopyN(ioutil.Discard, reader, reader.Size())
+			return err
+		}},
+		{"Piece/Delete", len(pieceIDs), 0, func(ctx context.Context, i int) error {
+			return store.Delete(ctx, satelliteID, pieceIDs[i])
+		}},
+		{"Orders/Enqueue", len(infos), 0, func(ctx context.Context, i int) error {
+			return ordersDB.Enqueue(ctx, infos[i])
+		}},
+		{"Orders/Archive", len(infos), 0, func(ctx context.Context, i int) error {
+			return ordersDB.Archive(ctx, satelliteID, infos[i].Limit.SerialNumber, orders.StatusAccepted)
+		}},
+	}
+
+	for _, benchmark := range benchmarks {
+		result, err := measure(ctx, benchmark.count, config.Concurrency, benchmark.op)
+		if err != nil {
+			return results, Error.New("%s: %v", benchmark.name, err)
+		}
+		result.Name = benchmark.name
+		result.Bytes = benchmark.bytes * int64(result.Operations)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// measure runs op for every index below count from concurrency goroutines,
+// timing every operation. The first failed operation stops the others.
+func measure(ctx context.Context, count, concurrency int, op func(ctx context.Context, i int) error) (Result, error) {
+	latencies := make([]time.Duration, count)
+	next := int64(-1)
+
+	group, ctx := errgroup.WithContext(ctx)
+	start := time.Now()
+	for worker := 0; worker < concurrency; worker++ {
+		group.Go(func() error {
+			for {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= count {
+					return nil
+				}
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+
+				opStart := time.Now()
+				if err := op(ctx, i); err != nil {
+					return err
+				}
+				latencies[i] = time.Since(opStart)
+			}
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return Result{}, err
+	}
+	elapsed := time.Since(start)
+
+	sort.Slice(latencies, func(i, k int) bool { return latencies[i] < latencies[k] })
+	return Result{
+		Operations: count,
+		Elapsed:    elapsed,
+		Latencies:  latencies,
+	}, nil
+}
+
+// generateOrders generates count unsigned orders of the satellite, uploaded
+// by a new uplink.
+func generateOrders(ctx context.Context, satelliteID storj.NodeID, count int) ([]*orders.Info, error) {
+	ca, err := identity.NewCA(ctx, identity.NewCAOptions{Difficulty: 0, Concurrency: 1})
+	if err != nil {
+		return nil, err
+	}
+	uplink, err := ca.NewIdentity()
+	if err != nil {
+		return nil, err
+	}
+
+	var storagenodeID storj.NodeID
+	if _, err := rand.Read(storagenodeID[:]); err != nil {
+		return nil, err
+	}
+
+	expiration, err := ptypes.TimestampProto(time.Now().Add(24 * time.Hour))
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]*orders.Info, 0, count)
+	for i := 0; i < count; i++ {
+		var serialNumber storj.SerialNumber
+		if _, err := rand.Read(serialNumber[:]); err != nil {
+			return nil, err
+		}
+		infos = append(infos, &orders.Info{
+			Limit: &pb.OrderLimit2{
+				SerialNumber:    serialNumber,
+				SatelliteId:     satelliteID,
+				UplinkId:        uplink.ID,
+				StorageNodeId:   storagenodeID,
+				PieceId:         storj.NewPieceID(),
+				Limit:           2 * memory.MiB.Int64(),
+				Action:          pb.PieceAction_PUT,
+				PieceExpiration: expiration,
+				OrderExpiration: expiration,
+			},
+			Order: &pb.Order2{
+				SerialNumber: serialNumber,
+				Amount:       2 * memory.MiB.Int64(),
+			},
+			Uplink: uplink.PeerIdentity(),
+		})
+	}
+	return infos, nil
+}
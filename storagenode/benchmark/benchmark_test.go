@@ -0,0 +1,66 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package benchmark_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/memory"
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/benchmark"
+	"storj.io/storj/storagenode/pieces"
+	"storj.io/storj/storagenode/storagenodedb/storagenodedbtest"
+)
+
+func TestRun(t *testing.T) {
+	storagenodedbtest.Run(t, func(t *testing.T, db storagenode.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		store := pieces.NewStore(zaptest.NewLogger(t), db.Pieces())
+		results, err := benchmark.Run(ctx, store, db.Orders(), benchmark.Config{
+			Concurrency: 2,
+			PieceSize:   4 * memory.KiB,
+			Pieces:      10,
+			Orders:      20,
+		})
+		require.NoError(t, err)
+
+		expected := []struct {
+			name       string
+			operations int
+			bytes      int64
+		}{
+			{"Piece/Write", 10, 40 * memory.KiB.Int64()},
+			{"Piece/Read", 10, 40 * memory.KiB.Int64()},
+			{"Piece/Delete", 10, 0},
+			{"Orders/Enqueue", 20, 0},
+			{"Orders/Archive", 20, 0},
+		}
+		require.Len(t, results, len(expected))
+		for i, result := range results {
+			assert.Equal(t, expected[i].name, result.Name)
+			assert.Equal(t, expected[i].operations, result.Operations)
+			assert.Equal(t, expected[i].bytes, result.Bytes)
+			assert.Len(t, result.Latencies, result.Operations)
+			assert.True(t, sort.SliceIsSorted(result.Latencies, func(i, k int) bool {
+				return result.Latencies[i] < result.Latencies[k]
+			}))
+			assert.Equal(t, result.Latencies[0], result.Percentile(0))
+			assert.Equal(t, result.Latencies[len(result.Latencies)-1], result.Percentile(100))
+			assert.True(t, result.Percentile(50) <= result.Percentile(99))
+		}
+
+		// the pieces are deleted and the orders archived
+		unsent, err := db.Orders().ListUnsent(ctx, 100)
+		require.NoError(t, err)
+		assert.Empty(t, unsent)
+	})
+}
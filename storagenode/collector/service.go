@@ -4,30 +4,110 @@
 package collector
 
 import (
+	"context"
 	"time"
 
+	"github.com/zeebo/errs"
 	"go.uber.org/zap"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
 
+	"storj.io/storj/internal/sync2"
 	"storj.io/storj/storagenode/pieces"
 )
 
+var (
+	// Error is the default error class for the collector.
+	Error = errs.Class("piece collector")
+	mon   = monkit.Package()
+)
+
 // Config defines parameters for storage node Collector.
 type Config struct {
-	Interval time.Duration
+	Interval  time.Duration `help:"how frequently expired pieces are collected" default:"1h0m0s"`
+	BatchSize int           `help:"maximum number of expired pieces deleted at once" default:"1000"`
 }
 
 // Service implements collecting expired pieces on the storage node.
 type Service struct {
 	log        *zap.Logger
+	config     Config
 	pieces     *pieces.Store
 	pieceinfos pieces.DB
+
+	Loop sync2.Cycle
 }
 
 // NewService creates a new collector service.
-func NewService(log *zap.Logger, pieces *pieces.Store, pieceinfos pieces.DB) *Service {
+func NewService(log *zap.Logger, pieces *pieces.Store, pieceinfos pieces.DB, config Config) *Service {
 	return &Service{
 		log:        log,
+		config:     config,
 		pieces:     pieces,
 		pieceinfos: pieceinfos,
+
+		Loop: *sync2.NewCycle(config.Interval),
+	}
+}
+
+// Run deletes the expired pieces on every interval.
+func (service *Service) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return service.Loop.Run(ctx, func(ctx context.Context) error {
+		if err := service.Collect(ctx, time.Now()); err != nil {
+			service.log.Error("collecting expired pieces", zap.Error(err))
+		}
+		return nil
+	})
+}
+
+// Close stops the collector.
+func (service *Service) Close() error {
+	service.Loop.Close()
+	return nil
+}
+
+// Collect deletes the pieces which expired before now, in batches.
+func (service *Service) Collect(ctx context.Context, now time.Time) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var count, size int64
+	defer func() {
+		mon.IntVal("expired_pieces_deleted").Observe(count)
+		mon.IntVal("expired_pieces_deleted_bytes").Observe(size)
+		if count > 0 {
+			service.log.Info("deleted expired pieces", zap.Int64("count", count), zap.Int64("bytes", size))
+		}
+	}()
+
+	for {
+		expired, err := service.pieceinfos.GetExpired(ctx, now, service.config.BatchSize)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+
+		var deleted int
+		for _, info := range expired {
+			// NB: the data is deleted first, so that a failure leaves the
+			// piece information to retry the deletion
+			if err := service.pieces.Delete(ctx, info.SatelliteID, info.PieceID); err != nil {
+				service.log.Warn("deleting expired piece", zap.Stringer("Piece ID", info.PieceID), zap.Error(err))
+				continue
+			}
+			if err := service.pieceinfos.Delete(ctx, info.SatelliteID, info.PieceID); err != nil {
+				service.log.Warn("deleting expired piece information", zap.Stringer("Piece ID", info.PieceID), zap.Error(err))
+				continue
+			}
+
+			deleted++
+			count++
+			size += info.PieceSize
+		}
+
+		// stop when every expired piece was deleted, or when none of the
+		// batch could be deleted, which would return the same batch again
+		if len(expired) < service.config.BatchSize || deleted == 0 {
+			return nil
+		}
 	}
 }
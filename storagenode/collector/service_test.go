@@ -0,0 +1,97 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package collector_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/pkg/auth/signing"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/collector"
+	"storj.io/storj/storagenode/pieces"
+	"storj.io/storj/storagenode/storagenodedb/storagenodedbtest"
+)
+
+func TestCollect(t *testing.T) {
+	storagenodedbtest.Run(t, func(t *testing.T, db storagenode.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		pieceinfos := db.PieceInfo()
+		store := pieces.NewStore(zaptest.NewLogger(t), db.Pieces())
+
+		satellite := testplanet.MustPregeneratedSignedIdentity(0)
+		uplink := testplanet.MustPregeneratedSignedIdentity(1)
+
+		now := time.Now()
+		add := func(expiration *time.Time) storj.PieceID {
+			pieceID := storj.NewPieceID()
+
+			pieceHash, err := signing.SignPieceHash(
+				signing.SignerFromFullIdentity(uplink),
+				&pb.PieceHash{PieceId: pieceID})
+			require.NoError(t, err)
+
+			require.NoError(t, pieceinfos.Add(ctx, &pieces.Info{
+				SatelliteID:     satellite.ID,
+				PieceID:         pieceID,
+				PieceSize:       2,
+				PieceExpiration: expiration,
+				PieceCreation:   now,
+				UplinkPieceHash: pieceHash,
+				Uplink:          uplink.PeerIdentity(),
+			}))
+
+			writer, err := store.Writer(ctx, satellite.ID, pieceID, -1)
+			require.NoError(t, err)
+			_, err = writer.Write([]byte{1, 2})
+			require.NoError(t, err)
+			require.NoError(t, writer.Commit(&pb.PieceHeader{}))
+			return pieceID
+		}
+
+		expired := now.Add(-time.Hour)
+		unexpired := now.Add(time.Hour)
+
+		var expiredIDs, keptIDs []storj.PieceID
+		for i := 0; i < 5; i++ {
+			expiredIDs = append(expiredIDs, add(&expired))
+			keptIDs = append(keptIDs, add(&unexpired))
+			keptIDs = append(keptIDs, add(nil))
+		}
+
+		infos, err := pieceinfos.GetExpired(ctx, now, 3)
+		require.NoError(t, err)
+		require.Len(t, infos, 3)
+
+		service := collector.NewService(zaptest.NewLogger(t), store, pieceinfos, collector.Config{
+			Interval:  time.Hour,
+			BatchSize: 2,
+		})
+		require.NoError(t, service.Collect(ctx, now))
+
+		for _, pieceID := range expiredIDs {
+			_, err := pieceinfos.Get(ctx, satellite.ID, pieceID)
+			require.Error(t, err)
+			_, err = store.Reader(ctx, satellite.ID, pieceID)
+			require.Error(t, err)
+		}
+		for _, pieceID := range keptIDs {
+			_, err := pieceinfos.Get(ctx, satellite.ID, pieceID)
+			require.NoError(t, err)
+		}
+
+		used, err := pieceinfos.SpaceUsed(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(2*len(keptIDs)), used)
+	})
+}
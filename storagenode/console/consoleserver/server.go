@@ -0,0 +1,240 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package consoleserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/internal/memory"
+	"storj.io/storj/pkg/accounting/payments"
+	"storj.io/storj/pkg/telemetry"
+	"storj.io/storj/storagenode/console"
+)
+
+const (
+	contentType = "Content-Type"
+
+	applicationJSON = "application/json"
+	textHTML        = "text/html; charset=utf-8"
+)
+
+// Error is storagenode console web error type
+var Error = errs.Class("storagenode console web error")
+
+// Config contains configuration for the storagenode console web server
+type Config struct {
+	Address string `help:"server address of the operator dashboard, empty disables it" default:"127.0.0.1:14002"`
+	Metrics bool   `help:"serve the stats of the node in the prometheus format at /metrics" default:"false"`
+}
+
+// Server represents the storagenode console web server, which serves the
+// dashboard page and its json api
+type Server struct {
+	log *zap.Logger
+
+	service *console.Service
+
+	listener net.Listener
+	server   http.Server
+}
+
+// NewServer creates new instance of the storagenode console web server
+func NewServer(logger *zap.Logger, config Config, service *console.Service, listener net.Listener) *Server {
+	server := Server{
+		log:      logger,
+		service:  service,
+		listener: listener,
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/dashboard", http.HandlerFunc(server.dashboardHandler))
+	mux.Handle("/api/payouts", http.HandlerFunc(server.payoutsHandler))
+	if config.Metrics {
+		mux.Handle("/metrics", telemetry.PrometheusHandler(monkit.Default))
+	}
+	mux.Handle("/", http.HandlerFunc(server.appHandler))
+
+	server.server = http.Server{
+		Handler: mux,
+	}
+
+	return &server
+}
+
+// appHandler renders the dashboard page
+func (server *Server) appHandler(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path != "/" {
+		http.NotFound(w, req)
+		return
+	}
+
+	dashboard, err := server.service.Dashboard(req.Context(), time.Now())
+	if err != nil {
+		server.log.Error("failed to load the dashboard", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(contentType, textHTML)
+	if err := dashboardPage.Execute(w, dashboard); err != nil {
+		server.log.Error("failed to render the dashboard", zap.Error(err))
+	}
+}
+
+// dashboardHandler returns the dashboard as json
+func (server *Server) dashboardHandler(w http.ResponseWriter, req *http.Request) {
+	dashboard, err := server.service.Dashboard(req.Context(), time.Now())
+	if err != nil {
+		server.log.Error("failed to load the dashboard", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(contentType, applicationJSON)
+	if err := json.NewEncoder(w).Encode(dashboard); err != nil {
+		server.log.Error("failed to encode the dashboard", zap.Error(err))
+	}
+}
+
+// payoutsHandler serves the payouts reported by the satellites as JSON
+func (server *Server) payoutsHandler(w http.ResponseWriter, req *http.Request) {
+	payouts, err := server.service.Payouts(req.Context())
+	if err != nil {
+		server.log.Error("failed to load the payouts", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(contentType, applicationJSON)
+	if err := json.NewEncoder(w).Encode(payouts); err != nil {
+		server.log.Error("failed to encode the payouts", zap.Error(err))
+	}
+}
+
+// Run starts the server that hosts the dashboard
+func (server *Server) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	var group errgroup.Group
+	group.Go(func() error {
+		<-ctx.Done()
+		return server.server.Shutdown(context.Background())
+	})
+	group.Go(func() error {
+		defer cancel()
+		err := server.server.Serve(server.listener)
+		if err == http.ErrServerClosed {
+			return nil
+		}
+		return Error.Wrap(err)
+	})
+
+	return group.Wait()
+}
+
+// Close closes server and underlying listener
+func (server *Server) Close() error {
+	return server.server.Close()
+}
+
+// dashboardPage renders the dashboard, the bandwidth graph is drawn with bars
+// scaled to the busiest day.
+var dashboardPage = template.Must(template.New("dashboard").Funcs(template.FuncMap{
+	"size":    func(bytes int64) string { return memory.Size(bytes).Base10String() },
+	"percent": func(ratio float64) string { return fmt.Sprintf("%.2f%%", ratio*100) },
+	"dollars": payments.FormatDollars,
+	"height": func(bytes int64, days []console.DailyBandwidth) int64 {
+		var max int64
+		for _, day := range days {
+			if day.Ingress > max {
+				max = day.Ingress
+			}
+			if day.Egress > max {
+				max = day.Egress
+			}
+		}
+		if max == 0 {
+			return 0
+		}
+		return bytes * 100 / max
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+	<meta charset="utf-8">
+	<title>Storage Node Dashboard</title>
+	<style>
+		body { font-family: sans-serif; margin: 2em; }
+		table { border-collapse: collapse; margin-bottom: 2em; }
+		th, td { padding: 0.3em 1em; border-bottom: 1px solid #ddd; text-align: right; }
+		th:first-child, td:first-child { text-align: left; }
+		.graph { display: flex; align-items: flex-end; height: 120px; margin-bottom: 2em; }
+		.day { display: flex; align-items: flex-end; margin-right: 2px; height: 100%; }
+		.ingress, .egress { width: 6px; }
+		.ingress { background: #2683ff; }
+		.egress { background: #ff8a00; }
+		.outdated { color: #d00; }
+	</style>
+</head>
+<body>
+	<h1>Storage Node Dashboard</h1>
+	<p>Node ID: {{.NodeID}}</p>
+	<p>Version: v{{.Version.Version}}{{if .Refused}} <span class="outdated">(below the version enforced by the satellites)</span>{{else if not .UpToDate}} <span class="outdated">(below the minimum allowed version)</span>{{else if .UpdateAvailable}} ({{.SuggestedVersion}} is available){{end}}</p>
+
+	<h2>Usage</h2>
+	<table>
+		<tr><th></th><th>Used</th><th>Available</th></tr>
+		<tr><td>Disk</td><td>{{size .UsedSpace}}</td><td>{{size .AvailableSpace}}</td></tr>
+		<tr><td>Bandwidth (this month)</td><td>{{size .UsedBandwidth}}</td><td>{{size .AvailableBandwidth}}</td></tr>
+	</table>
+	<p>Estimated payout (this month): ${{dollars .EstimatedPayout}}</p>
+
+	<h2>Bandwidth (this month)</h2>
+	<div class="graph">
+		{{- $days := .Bandwidth}}
+		{{- range .Bandwidth}}
+		<div class="day" title="{{.Day.Format "2006-01-02"}}: ingress {{size .Ingress}}, egress {{size .Egress}}">
+			<div class="ingress" style="height: {{height .Ingress $days}}%"></div>
+			<div class="egress" style="height: {{height .Egress $days}}%"></div>
+		</div>
+		{{- end}}
+	</div>
+
+	<h2>Satellites</h2>
+	<table>
+		<tr>
+			<th>Satellite</th><th>Disk used</th><th>Ingress</th><th>Egress</th>
+			<th>Unsent orders</th><th>Estimated payout ($)</th><th>Audit success</th><th>Uptime</th>
+		</tr>
+		{{- range .Satellites}}
+		<tr>
+			<td>{{.ID}}</td>
+			<td>{{size .SpaceUsed}}</td>
+			<td>{{size .Ingress}}</td>
+			<td>{{size .Egress}}</td>
+			<td>{{.UnsentOrders}}</td>
+			<td>{{dollars .EstimatedPayout}}</td>
+			{{- if .Reputation}}
+			<td>{{percent .Reputation.AuditSuccessRatio}} ({{.Reputation.AuditSuccessCount}}/{{.Reputation.AuditCount}})</td>
+			<td>{{percent .Reputation.UptimeRatio}} ({{.Reputation.UptimeSuccessCount}}/{{.Reputation.UptimeCount}})</td>
+			{{- else}}
+			<td>-</td>
+			<td>-</td>
+			{{- end}}
+		</tr>
+		{{- end}}
+	</table>
+</body>
+</html>
+`))
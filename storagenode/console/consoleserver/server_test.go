@@ -0,0 +1,95 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package consoleserver_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/storagenode/console"
+	"storj.io/storj/storagenode/reputation"
+)
+
+func TestDashboard(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	planet, err := testplanet.New(t, 1, 1, 0)
+	require.NoError(t, err)
+	defer ctx.Check(planet.Shutdown)
+
+	planet.Start(ctx)
+
+	node := planet.StorageNodes[0]
+	satelliteID := planet.Satellites[0].ID()
+
+	err = node.DB.Reputation().Store(ctx, reputation.Stats{
+		SatelliteID:       satelliteID,
+		AuditCount:        4,
+		AuditSuccessCount: 3,
+		AuditSuccessRatio: 0.75,
+		UpdatedAt:         time.Now(),
+	})
+	require.NoError(t, err)
+
+	address := "http://" + node.Console.Listener.Addr().String()
+
+	response, err := http.Get(address + "/api/dashboard")
+	require.NoError(t, err)
+	defer ctx.Check(response.Body.Close)
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	var dashboard console.Dashboard
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&dashboard))
+	assert.Equal(t, node.ID(), dashboard.NodeID)
+	assert.True(t, dashboard.AvailableSpace > 0)
+	assert.NotEmpty(t, dashboard.Bandwidth)
+	require.Len(t, dashboard.Satellites, 1)
+	assert.Equal(t, satelliteID, dashboard.Satellites[0].ID)
+	require.NotNil(t, dashboard.Satellites[0].Reputation)
+	assert.Equal(t, int64(3), dashboard.Satellites[0].Reputation.AuditSuccessCount)
+
+	page, err := http.Get(address + "/")
+	require.NoError(t, err)
+	defer ctx.Check(page.Body.Close)
+	require.Equal(t, http.StatusOK, page.StatusCode)
+
+	body, err := ioutil.ReadAll(page.Body)
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(string(body), node.ID().String()))
+	assert.True(t, strings.Contains(string(body), "75.00%"))
+}
+
+func TestMetrics(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	planet, err := testplanet.New(t, 1, 1, 0)
+	require.NoError(t, err)
+	defer ctx.Check(planet.Shutdown)
+
+	planet.Start(ctx)
+
+	node := planet.StorageNodes[0]
+	_, err = node.Console.Service.Dashboard(ctx, time.Now())
+	require.NoError(t, err)
+
+	response, err := http.Get("http://" + node.Console.Listener.Addr().String() + "/metrics")
+	require.NoError(t, err)
+	defer ctx.Check(response.Body.Close)
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err := ioutil.ReadAll(response.Body)
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(string(body), `{scope="storj.io/storj/storagenode/console"}`))
+}
@@ -0,0 +1,300 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package console
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/internal/version"
+	"storj.io/storj/pkg/accounting/payments"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storagenode/bandwidth"
+	"storj.io/storj/storagenode/heldamount"
+	"storj.io/storj/storagenode/orders"
+	"storj.io/storj/storagenode/pieces"
+	"storj.io/storj/storagenode/reputation"
+	"storj.io/storj/storagenode/storageusage"
+)
+
+var (
+	// Error is the default error class for the storage node console.
+	Error = errs.Class("storagenode console")
+	mon   = monkit.Package()
+)
+
+// Dashboard is the state of the storage node shown to its operator.
+type Dashboard struct {
+	NodeID  storj.NodeID `json:"nodeID"`
+	Version version.Info `json:"version"`
+	// UpToDate is whether the last version check allowed the running binary
+	UpToDate bool `json:"upToDate"`
+	// UpdateAvailable is whether the version server suggests updating the node
+	UpdateAvailable bool `json:"updateAvailable"`
+	// Refused is whether the running binary is below the version enforced by
+	// the satellites
+	Refused bool `json:"refused"`
+	// MinimumVersion and SuggestedVersion are from the last version check,
+	// they are empty until the version server has been reached
+	MinimumVersion   string `json:"minimumVersion"`
+	SuggestedVersion string `json:"suggestedVersion"`
+
+	UsedSpace      int64 `json:"usedSpace"`
+	AvailableSpace int64 `json:"availableSpace"`
+	// UsedBandwidth and AvailableBandwidth are of the current month
+	UsedBandwidth      int64 `json:"usedBandwidth"`
+	AvailableBandwidth int64 `json:"availableBandwidth"`
+	// EstimatedPayout is the estimated payout of all the satellites for the
+	// current month, in micro dollars
+	EstimatedPayout int64 `json:"estimatedPayout"`
+
+	// Bandwidth is the bandwidth of every day of the current month
+	Bandwidth  []DailyBandwidth `json:"bandwidth"`
+	Satellites []Satellite      `json:"satellites"`
+}
+
+// Payouts are the payouts reported by the satellites, by satellite and month.
+type Payouts struct {
+	Payouts    []heldamount.Payout `json:"payouts"`
+	Satellites []SatellitePayouts  `json:"satellites"`
+}
+
+// SatellitePayouts are the totals of the payouts of a satellite, in micro dollars.
+type SatellitePayouts struct {
+	ID    storj.NodeID `json:"id"`
+	Gross int64        `json:"gross"`
+	Held  int64        `json:"held"`
+	Paid  int64        `json:"paid"`
+}
+
+// DailyBandwidth is the bandwidth of all satellites on a day.
+type DailyBandwidth struct {
+	// Day is the start of the day in UTC
+	Day     time.Time `json:"day"`
+	Ingress int64     `json:"ingress"`
+	Egress  int64     `json:"egress"`
+}
+
+// Satellite is the state of the storage node on a satellite.
+type Satellite struct {
+	ID        storj.NodeID `json:"id"`
+	SpaceUsed int64        `json:"spaceUsed"`
+	// AverageStored is the average bytes stored during the current month
+	AverageStored int64 `json:"averageStored"`
+	// Ingress and Egress are of the current month
+	Ingress int64 `json:"ingress"`
+	Egress  int64 `json:"egress"`
+
+	UnsentOrders      int64 `json:"unsentOrders"`
+	UnsentOrderAmount int64 `json:"unsentOrderAmount"`
+
+	// EstimatedPayout is the estimated payout for the current month, after
+	// the held amount, in micro dollars
+	EstimatedPayout int64 `json:"estimatedPayout"`
+
+	// Reputation is nil until the satellite reports it
+	Reputation *reputation.Stats `json:"reputation"`
+}
+
+// Service aggregates the data of the local databases shown on the dashboard.
+type Service struct {
+	log *zap.Logger
+
+	nodeID         storj.NodeID
+	version        *version.Service
+	spaceUsed      pieces.SpaceUsed
+	bandwidthDB    bandwidth.DB
+	ordersDB       orders.DB
+	reputationDB   reputation.DB
+	heldAmountDB   heldamount.DB
+	storageUsageDB storageusage.DB
+
+	payments payments.Config
+
+	allocatedDiskSpace int64
+	allocatedBandwidth int64
+}
+
+// NewService creates a new storage node console service.
+func NewService(log *zap.Logger, nodeID storj.NodeID, version *version.Service, spaceUsed pieces.SpaceUsed, bandwidthDB bandwidth.DB, ordersDB orders.DB, reputationDB reputation.DB, heldAmountDB heldamount.DB, storageUsageDB storageusage.DB, payments payments.Config, allocatedDiskSpace, allocatedBandwidth int64) *Service {
+	return &Service{
+		log: log,
+
+		nodeID:         nodeID,
+		version:        version,
+		spaceUsed:      spaceUsed,
+		bandwidthDB:    bandwidthDB,
+		ordersDB:       ordersDB,
+		reputationDB:   reputationDB,
+		heldAmountDB:   heldAmountDB,
+		storageUsageDB: storageUsageDB,
+
+		payments: payments,
+
+		allocatedDiskSpace: allocatedDiskSpace,
+		allocatedBandwidth: allocatedBandwidth,
+	}
+}
+
+// Dashboard returns the state of the storage node at now.
+func (service *Service) Dashboard(ctx context.Context, now time.Time) (_ *Dashboard, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	dashboard := &Dashboard{
+		NodeID:   service.nodeID,
+		Version:  service.version.Info(),
+		UpToDate: service.version.IsAllowed(),
+	}
+	if latest, ok := service.version.Latest(); ok {
+		current := dashboard.Version.Version
+		dashboard.UpdateAvailable = latest.Suggests(service.nodeID, current)
+		dashboard.Refused = latest.Refuses(current)
+		dashboard.MinimumVersion = latest.Minimum.String()
+		dashboard.SuggestedVersion = latest.Suggested.String()
+	}
+
+	satellites := map[storj.NodeID]*Satellite{}
+	satellite := func(id storj.NodeID) *Satellite {
+		if satellites[id] == nil {
+			satellites[id] = &Satellite{ID: id}
+		}
+		return satellites[id]
+	}
+
+	spaceUsed, err := service.spaceUsed.SpaceUsedBySatellite(ctx)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	for id, used := range spaceUsed {
+		satellite(id).SpaceUsed = used
+		dashboard.UsedSpace += used
+	}
+	dashboard.AvailableSpace = service.allocatedDiskSpace - dashboard.UsedSpace
+
+	now = now.UTC()
+	beginningOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	usages, err := service.bandwidthDB.SummaryBySatellite(ctx, beginningOfMonth, now)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	storageUsages, err := service.storageUsageDB.SummaryBySatellite(ctx, beginningOfMonth, now)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	for id, usage := range usages {
+		satellite(id).Ingress = usage.Ingress()
+		satellite(id).Egress = usage.Egress()
+		dashboard.UsedBandwidth += usage.Total()
+	}
+	dashboard.AvailableBandwidth = service.allocatedBandwidth - dashboard.UsedBandwidth
+
+	summaries, err := service.bandwidthDB.DailySummaries(ctx, beginningOfMonth, now)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	dashboard.Bandwidth = dailyBandwidth(beginningOfMonth, now, summaries)
+
+	unsent, err := service.ordersDB.SummarizeUnsent(ctx)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	for id, summary := range unsent {
+		satellite(id).UnsentOrders = summary.Count
+		satellite(id).UnsentOrderAmount = summary.Amount
+	}
+
+	reputations, err := service.reputationDB.All(ctx)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	for i := range reputations {
+		satellite(reputations[i].SatelliteID).Reputation = &reputations[i]
+	}
+
+	endOfMonth := beginningOfMonth.AddDate(0, 1, 0)
+	for id, satellite := range satellites {
+		// NB: the space used now is the best estimate until the storage
+		// usage is recorded
+		satellite.AverageStored = satellite.SpaceUsed
+		if summary, ok := storageUsages[id]; ok && summary.Hours > 0 {
+			satellite.AverageStored = summary.AverageStored()
+		}
+
+		var heldPercent int64
+		last, err := service.heldAmountDB.Last(ctx, id)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		if last != nil {
+			heldPercent = last.HeldPercent
+		}
+
+		var usage bandwidth.Usage
+		if usages[id] != nil {
+			usage = *usages[id]
+		}
+		_, gross := bandwidth.EstimatePayout(service.payments, usage, satellite.AverageStored, beginningOfMonth, endOfMonth, now)
+		satellite.EstimatedPayout = gross - gross*heldPercent/100
+		dashboard.EstimatedPayout += satellite.EstimatedPayout
+
+		dashboard.Satellites = append(dashboard.Satellites, *satellite)
+	}
+	sort.Slice(dashboard.Satellites, func(i, k int) bool {
+		return dashboard.Satellites[i].ID.Less(dashboard.Satellites[k].ID)
+	})
+
+	return dashboard, nil
+}
+
+// Payouts returns the payouts reported by the satellites with their totals.
+func (service *Service) Payouts(ctx context.Context) (_ *Payouts, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	all, err := service.heldAmountDB.All(ctx)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	payouts := &Payouts{Payouts: all}
+	for _, payout := range all {
+		n := len(payouts.Satellites)
+		if n == 0 || payouts.Satellites[n-1].ID != payout.SatelliteID {
+			payouts.Satellites = append(payouts.Satellites, SatellitePayouts{ID: payout.SatelliteID})
+			n++
+		}
+		totals := &payouts.Satellites[n-1]
+		totals.Gross += payout.Gross
+		totals.Held += payout.Held
+		totals.Paid += payout.Paid
+	}
+	return payouts, nil
+}
+
+// dailyBandwidth sums the summaries of the satellites by day, including the
+// days without any bandwidth from the day of from until the day of to.
+func dailyBandwidth(from, to time.Time, summaries []bandwidth.Summary) []DailyBandwidth {
+	var days []DailyBandwidth
+	index := map[time.Time]int{}
+	for day := from.Truncate(24 * time.Hour); !day.After(to); day = day.Add(24 * time.Hour) {
+		index[day] = len(days)
+		days = append(days, DailyBandwidth{Day: day})
+	}
+
+	for _, summary := range summaries {
+		i, ok := index[summary.Day.UTC()]
+		if !ok {
+			continue
+		}
+		days[i].Ingress += summary.Ingress
+		days[i].Egress += summary.Egress
+	}
+	return days
+}
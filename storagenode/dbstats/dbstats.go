@@ -0,0 +1,114 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package dbstats
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/internal/sync2"
+)
+
+var (
+	mon = monkit.Package()
+
+	// Error is the default error class for database statistics errors
+	Error = errs.Class("database statistics")
+)
+
+// Stats are the statistics of the storage node database.
+type Stats struct {
+	UnsentOrders   int64
+	ArchivedOrders int64
+	Pieces         int64
+	UsedSerials    int64
+	// CorruptedPieces are the pieces whose data didn't match their hash.
+	CorruptedPieces int64
+
+	// FileSizes are the sizes of the database files, by database name.
+	FileSizes map[string]int64
+
+	// ConnectionWaits and ConnectionWaitTime are the total number of waits
+	// for a database connection and the total time waited.
+	ConnectionWaits    int64
+	ConnectionWaitTime time.Duration
+	// WriteLockWait is the time waited for the write lock by a probe.
+	WriteLockWait time.Duration
+}
+
+// DB implements collecting the statistics of the storage node database.
+type DB interface {
+	// Stats returns the current statistics of the database.
+	Stats(ctx context.Context) (*Stats, error)
+}
+
+// Service reports the database statistics to monkit every interval, so that
+// operators can alert on the growth of the database.
+type Service struct {
+	log *zap.Logger
+	db  DB
+
+	Loop sync2.Cycle
+}
+
+// NewService creates a new database statistics service.
+func NewService(log *zap.Logger, db DB, interval time.Duration) *Service {
+	return &Service{
+		log: log,
+		db:  db,
+
+		Loop: *sync2.NewCycle(interval),
+	}
+}
+
+// Run reports the statistics on every interval.
+func (service *Service) Run(ctx context.Context) error {
+	return service.Loop.Run(ctx, func(ctx context.Context) error {
+		if err := service.Collect(ctx); err != nil {
+			service.log.Error("collecting database statistics", zap.Error(err))
+		}
+		return nil
+	})
+}
+
+// Collect reports the current statistics.
+func (service *Service) Collect(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	stats, err := service.db.Stats(ctx)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	mon.IntVal("db_unsent_orders").Observe(stats.UnsentOrders)
+	mon.IntVal("db_archived_orders").Observe(stats.ArchivedOrders)
+	mon.IntVal("db_pieces").Observe(stats.Pieces)
+	mon.IntVal("db_used_serials").Observe(stats.UsedSerials)
+	mon.IntVal("db_corrupted_pieces").Observe(stats.CorruptedPieces)
+	for name, size := range stats.FileSizes {
+		mon.IntVal("db_file_size_bytes." + name).Observe(size)
+	}
+	mon.IntVal("db_connection_waits_total").Observe(stats.ConnectionWaits)
+	mon.IntVal("db_connection_wait_total_ms").Observe(stats.ConnectionWaitTime.Nanoseconds() / int64(time.Millisecond))
+	mon.IntVal("db_write_lock_wait_ms").Observe(stats.WriteLockWait.Nanoseconds() / int64(time.Millisecond))
+
+	service.log.Debug("database statistics",
+		zap.Int64("unsent orders", stats.UnsentOrders),
+		zap.Int64("archived orders", stats.ArchivedOrders),
+		zap.Int64("pieces", stats.Pieces),
+		zap.Int64("used serials", stats.UsedSerials),
+		zap.Int64("corrupted pieces", stats.CorruptedPieces),
+		zap.Duration("write lock wait", stats.WriteLockWait))
+	return nil
+}
+
+// Close stops the statistics service.
+func (service *Service) Close() error {
+	service.Loop.Stop()
+	return nil
+}
@@ -0,0 +1,41 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package dbstats_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/dbstats"
+	"storj.io/storj/storagenode/storagenodedb/storagenodedbtest"
+)
+
+func TestStats(t *testing.T) {
+	storagenodedbtest.Run(t, func(t *testing.T, db storagenode.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		satelliteID := testplanet.MustPregeneratedSignedIdentity(1).ID
+		for i := byte(0); i < 3; i++ {
+			require.NoError(t, db.UsedSerials().Add(ctx, satelliteID, storj.SerialNumber{i}, time.Now().Add(time.Hour)))
+		}
+
+		stats, err := db.Stats().Stats(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(3), stats.UsedSerials)
+		require.Equal(t, int64(0), stats.UnsentOrders)
+		require.Equal(t, int64(0), stats.ArchivedOrders)
+		require.Equal(t, int64(0), stats.Pieces)
+
+		service := dbstats.NewService(zaptest.NewLogger(t), db.Stats(), time.Hour)
+		require.NoError(t, service.Collect(ctx))
+	})
+}
@@ -0,0 +1,260 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package diskhealth
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/internal/memory"
+	"storj.io/storj/internal/sync2"
+)
+
+var (
+	// Error is the default error class for the disk health checks.
+	Error = errs.Class("disk health")
+	mon   = monkit.Package()
+)
+
+// Config defines parameters for the storage node disk health checks.
+type Config struct {
+	Interval      time.Duration `help:"how frequently the disk of the storage directories is checked" default:"5m0s"`
+	TestSize      memory.Size   `help:"how many bytes are written, read back and verified in every storage directory on every check" default:"64KiB"`
+	FailThreshold int           `help:"how many consecutive failed write tests mark the disk as failing, refusing new uploads" default:"3"`
+	SmartctlPath  string        `help:"path of smartctl reading the SMART attributes of the disk on every check, not read when empty" default:""`
+	SmartDevice   string        `help:"device whose SMART attributes are read, like /dev/sda" default:""`
+}
+
+// Status is the health of the disk found by the last check.
+type Status struct {
+	// Degraded is set when a check found a problem
+	Degraded bool
+	// Failing is set when the disk is expected to fail, the new uploads are
+	// then refused
+	Failing bool
+	// Reason describes the problems found
+	Reason string
+	// LastChecked is when the disk was last checked, zero before the first check
+	LastChecked time.Time
+}
+
+// Service checks the health of the disk periodically, writing, reading back
+// and verifying a small file in every storage directory, and reading the
+// SMART attributes of the disk when smartctl is configured.
+type Service struct {
+	log    *zap.Logger
+	config Config
+	dirs   []string
+
+	mu       sync.Mutex
+	status   Status
+	failures int
+
+	Loop sync2.Cycle
+}
+
+// NewService creates a new disk health service checking the storage directories.
+func NewService(log *zap.Logger, dirs []string, config Config) *Service {
+	return &Service{
+		log:    log,
+		config: config,
+		dirs:   dirs,
+
+		Loop: *sync2.NewCycle(config.Interval),
+	}
+}
+
+// Run checks the disk on every interval.
+func (service *Service) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return service.Loop.Run(ctx, func(ctx context.Context) error {
+		service.Check(ctx)
+		return nil
+	})
+}
+
+// Close stops the disk health checks.
+func (service *Service) Close() error {
+	service.Loop.Close()
+	return nil
+}
+
+// Status returns the health of the disk found by the last check.
+func (service *Service) Status() Status {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	return service.status
+}
+
+// Failing returns whether the last checks found the disk failing.
+func (service *Service) Failing() bool {
+	return service.Status().Failing
+}
+
+// Check checks the disk and returns its health. A failed write test degrades
+// the disk and several consecutive ones mark it failing, as does a failed
+// SMART self-assessment.
+func (service *Service) Check(ctx context.Context) (status Status) {
+	defer mon.Task()(&ctx)(nil)
+
+	var problems []string
+	writeFailed := false
+	for _, dir := range service.dirs {
+		if err := writeTest(dir, service.config.TestSize.Int()); err != nil {
+			mon.Meter("disk_health_write_test_failures").Mark(1)
+			problems = append(problems, fmt.Sprintf("write test in %s failed: %v", dir, err))
+			writeFailed = true
+		}
+	}
+
+	smartFailing := false
+	if service.config.SmartctlPath != "" {
+		output, err := service.readSMART(ctx)
+		if err != nil {
+			service.log.Warn("unable to read the SMART attributes", zap.String("device", service.config.SmartDevice), zap.Error(err))
+		} else {
+			var smartProblems []string
+			smartFailing, smartProblems = parseSMART(output)
+			problems = append(problems, smartProblems...)
+		}
+	}
+
+	threshold := service.config.FailThreshold
+	if threshold < 1 {
+		threshold = 1
+	}
+
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	if writeFailed {
+		service.failures++
+	} else {
+		service.failures = 0
+	}
+
+	status = Status{
+		Degraded:    len(problems) > 0,
+		Failing:     smartFailing || service.failures >= threshold,
+		Reason:      strings.Join(problems, "; "),
+		LastChecked: time.Now(),
+	}
+
+	switch {
+	case status.Failing && !service.status.Failing:
+		service.log.Error("disk is failing, refusing new uploads", zap.String("reason", status.Reason))
+	case status.Degraded:
+		service.log.Warn("disk is degraded", zap.String("reason", status.Reason))
+	case service.status.Degraded:
+		service.log.Info("disk is healthy again")
+	}
+
+	service.status = status
+	return status
+}
+
+// readSMART returns the output of smartctl for the health and the attributes of the device.
+func (service *Service) readSMART(ctx context.Context) (string, error) {
+	var output bytes.Buffer
+	cmd := exec.CommandContext(ctx, service.config.SmartctlPath, "-H", "-A", service.config.SmartDevice)
+	cmd.Stdout = &output
+	err := cmd.Run()
+	// NB: smartctl exits with a non-zero status for the problems it found,
+	// which are read from the output
+	if _, ok := err.(*exec.ExitError); ok && output.Len() > 0 {
+		err = nil
+	}
+	return output.String(), Error.Wrap(err)
+}
+
+// badSectorAttributes are the SMART attributes counting bad sectors in their
+// raw value, any of them above zero degrades the disk.
+var badSectorAttributes = []string{"Reallocated_Sector_Ct", "Current_Pending_Sector", "Offline_Uncorrectable"}
+
+// parseSMART parses the output of smartctl, returning whether the disk failed
+// its self-assessment and the problems found.
+func parseSMART(output string) (failing bool, problems []string) {
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		// ATA disks report the self-assessment, SCSI disks the health status
+		if result := strings.TrimPrefix(line, "SMART overall-health self-assessment test result:"); result != line {
+			if strings.TrimSpace(result) != "PASSED" {
+				failing = true
+				problems = append(problems, "SMART self-assessment "+strings.TrimSpace(result))
+			}
+			continue
+		}
+		if result := strings.TrimPrefix(line, "SMART Health Status:"); result != line {
+			if strings.TrimSpace(result) != "OK" {
+				failing = true
+				problems = append(problems, "SMART health status "+strings.TrimSpace(result))
+			}
+			continue
+		}
+
+		// ID# ATTRIBUTE_NAME FLAG VALUE WORST THRESH TYPE UPDATED WHEN_FAILED RAW_VALUE
+		fields := strings.Fields(line)
+		if len(fields) < 10 {
+			continue
+		}
+		for _, attribute := range badSectorAttributes {
+			if fields[1] != attribute {
+				continue
+			}
+			raw, err := strconv.ParseInt(fields[9], 10, 64)
+			if err == nil && raw > 0 {
+				problems = append(problems, fmt.Sprintf("SMART %s is %d", attribute, raw))
+			}
+		}
+	}
+	return failing, problems
+}
+
+// writeTest writes size random bytes into a temporary file of dir, syncs
+// them to the disk, then reads them back and verifies them.
+func writeTest(dir string, size int) (err error) {
+	data := make([]byte, size)
+	if _, err := rand.Read(data); err != nil {
+		return err
+	}
+
+	file, err := ioutil.TempFile(dir, "disk-health-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, os.Remove(file.Name())) }()
+
+	_, writeErr := file.Write(data)
+	syncErr := file.Sync()
+	closeErr := file.Close()
+	if err := errs.Combine(writeErr, syncErr, closeErr); err != nil {
+		return err
+	}
+
+	read, err := ioutil.ReadFile(file.Name())
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(data, read) {
+		return errs.New("read %d bytes differing from the %d bytes written", len(read), len(data))
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package diskhealth
+
+import (
+	"io/ioutil"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/memory"
+	"storj.io/storj/internal/testcontext"
+)
+
+const smartPassed = `smartctl 6.6 2016-05-31 r4324 [x86_64-linux-4.19.0] (local build)
+
+=== START OF READ SMART DATA SECTION ===
+SMART overall-health self-assessment test result: PASSED
+
+SMART Attributes Data Structure revision number: 16
+Vendor Specific SMART Attributes with Thresholds:
+ID# ATTRIBUTE_NAME          FLAG     VALUE WORST THRESH TYPE      UPDATED  WHEN_FAILED RAW_VALUE
+  1 Raw_Read_Error_Rate     0x000f   117   099   006    Pre-fail  Always       -       158766328
+  5 Reallocated_Sector_Ct   0x0033   100   100   010    Pre-fail  Always       -       0
+  9 Power_On_Hours          0x0032   086   086   000    Old_age   Always       -       12731
+197 Current_Pending_Sector  0x0012   100   100   000    Old_age   Always       -       0
+198 Offline_Uncorrectable   0x0010   100   100   000    Old_age   Offline      -       0
+`
+
+func TestParseSMART(t *testing.T) {
+	failing, problems := parseSMART(smartPassed)
+	assert.False(t, failing)
+	assert.Empty(t, problems)
+
+	failing, problems = parseSMART(`SMART overall-health self-assessment test result: FAILED!
+  5 Reallocated_Sector_Ct   0x0033   001   001   010    Pre-fail  Always   FAILING_NOW 3920
+197 Current_Pending_Sector  0x0012   100   100   000    Old_age   Always       -       8
+`)
+	assert.True(t, failing)
+	assert.Equal(t, []string{
+		"SMART self-assessment FAILED!",
+		"SMART Reallocated_Sector_Ct is 3920",
+		"SMART Current_Pending_Sector is 8",
+	}, problems)
+
+	failing, problems = parseSMART("SMART Health Status: OK\n")
+	assert.False(t, failing)
+	assert.Empty(t, problems)
+
+	failing, problems = parseSMART("SMART Health Status: FIRMWARE IMPENDING FAILURE\n")
+	assert.True(t, failing)
+	assert.Len(t, problems, 1)
+}
+
+func TestCheck(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	dir := ctx.Dir("storage")
+	service := NewService(zaptest.NewLogger(t), []string{dir}, Config{
+		Interval:      time.Hour,
+		TestSize:      memory.KiB,
+		FailThreshold: 2,
+	})
+
+	require.True(t, service.Status().LastChecked.IsZero())
+
+	status := service.Check(ctx)
+	assert.False(t, status.Degraded)
+	assert.False(t, status.Failing)
+	assert.False(t, status.LastChecked.IsZero())
+
+	// the test file is removed
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, files)
+
+	// a failed write test degrades the disk, consecutive ones fail it
+	require.NoError(t, os.RemoveAll(dir))
+	status = service.Check(ctx)
+	assert.True(t, status.Degraded)
+	assert.False(t, status.Failing)
+	assert.Contains(t, status.Reason, "write test")
+
+	status = service.Check(ctx)
+	assert.True(t, status.Degraded)
+	assert.True(t, status.Failing)
+	assert.True(t, service.Failing())
+
+	require.NoError(t, os.MkdirAll(dir, 0700))
+	status = service.Check(ctx)
+	assert.False(t, status.Degraded)
+	assert.False(t, status.Failing)
+}
+
+func TestCheckSMART(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("smartctl is faked with a shell script")
+	}
+
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	output := ctx.File("smartctl.out")
+	smartctl := ctx.File("smartctl")
+	require.NoError(t, ioutil.WriteFile(smartctl, []byte("#!/bin/sh\ncat "+output+"\n"), 0700))
+
+	service := NewService(zaptest.NewLogger(t), []string{ctx.Dir("storage")}, Config{
+		Interval:      time.Hour,
+		TestSize:      memory.KiB,
+		FailThreshold: 3,
+		SmartctlPath:  smartctl,
+		SmartDevice:   "/dev/sda",
+	})
+
+	require.NoError(t, ioutil.WriteFile(output, []byte(smartPassed), 0600))
+	status := service.Check(ctx)
+	assert.False(t, status.Degraded)
+
+	require.NoError(t, ioutil.WriteFile(output, []byte("SMART overall-health self-assessment test result: FAILED!\n"), 0600))
+	status = service.Check(ctx)
+	assert.True(t, status.Degraded)
+	assert.True(t, status.Failing)
+
+	// the SMART attributes are ignored when smartctl fails to run
+	require.NoError(t, os.Remove(smartctl))
+	status = service.Check(ctx)
+	assert.False(t, status.Degraded)
+}
@@ -0,0 +1,78 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package heldamount_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/heldamount"
+	"storj.io/storj/storagenode/storagenodedb/storagenodedbtest"
+)
+
+func TestDB(t *testing.T) {
+	storagenodedbtest.Run(t, func(t *testing.T, db storagenode.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		heldamountdb := db.HeldAmount()
+
+		satellite0 := testplanet.MustPregeneratedSignedIdentity(0).ID
+		satellite1 := testplanet.MustPregeneratedSignedIdentity(1).ID
+
+		last, err := heldamountdb.Last(ctx, satellite0)
+		require.NoError(t, err)
+		require.Nil(t, last)
+
+		now := time.Now().UTC().Truncate(time.Second)
+		march := time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC)
+		april := time.Date(2019, 4, 1, 0, 0, 0, 0, time.UTC)
+
+		expected := heldamount.Payout{
+			SatelliteID:  satellite0,
+			Period:       april,
+			NodeMonth:    2,
+			SurgePercent: 100,
+			HeldPercent:  75,
+			Gross:        4000,
+			Held:         3000,
+			Paid:         1000,
+			UpdatedAt:    now,
+		}
+		require.NoError(t, heldamountdb.Store(ctx, heldamount.Payout{SatelliteID: satellite0, Period: march, NodeMonth: 1, UpdatedAt: now}))
+		require.NoError(t, heldamountdb.Store(ctx, expected))
+		require.NoError(t, heldamountdb.Store(ctx, heldamount.Payout{SatelliteID: satellite1, Period: march, NodeMonth: 1, UpdatedAt: now}))
+
+		// storing again replaces the payout of the month
+		expected.Gross, expected.Held, expected.Paid = 8000, 6000, 2000
+		expected.UpdatedAt = now.Add(time.Hour)
+		require.NoError(t, heldamountdb.Store(ctx, expected))
+
+		last, err = heldamountdb.Last(ctx, satellite0)
+		require.NoError(t, err)
+		require.NotNil(t, last)
+		require.True(t, expected.Period.Equal(last.Period))
+		require.True(t, expected.UpdatedAt.Equal(last.UpdatedAt))
+		last.Period, last.UpdatedAt = expected.Period, expected.UpdatedAt
+		require.Equal(t, expected, *last)
+
+		all, err := heldamountdb.All(ctx)
+		require.NoError(t, err)
+		require.Len(t, all, 3)
+
+		// the months of a satellite are ordered
+		var periods []time.Time
+		for _, payout := range all {
+			if payout.SatelliteID == satellite0 {
+				periods = append(periods, payout.Period.UTC())
+			}
+		}
+		require.Equal(t, []time.Time{march, april}, periods)
+	})
+}
@@ -0,0 +1,42 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package heldamount
+
+import (
+	"context"
+	"time"
+
+	"storj.io/storj/pkg/storj"
+)
+
+// Payout is the payment of the node by a satellite for a month, the amounts
+// are in micro dollars.
+type Payout struct {
+	SatelliteID storj.NodeID `json:"satelliteID"`
+	// Period is the start of the month.
+	Period time.Time `json:"period"`
+	// NodeMonth is the month of operation of the node, starting at 1.
+	NodeMonth int `json:"nodeMonth"`
+
+	SurgePercent int64 `json:"surgePercent"`
+	HeldPercent  int64 `json:"heldPercent"`
+
+	Gross int64 `json:"gross"`
+	Held  int64 `json:"held"`
+	Paid  int64 `json:"paid"`
+
+	// UpdatedAt is when the satellite reported the payout.
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// DB stores the payouts reported by the satellites.
+type DB interface {
+	// Store replaces the payout of the satellite for the period of payout.
+	Store(ctx context.Context, payout Payout) error
+	// Last returns the payout of the satellite for the latest period, it
+	// returns nil when the satellite didn't report any.
+	Last(ctx context.Context, satelliteID storj.NodeID) (*Payout, error)
+	// All returns the payouts of every satellite ordered by satellite and period.
+	All(ctx context.Context) ([]Payout, error)
+}
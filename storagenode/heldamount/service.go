@@ -0,0 +1,142 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package heldamount
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/internal/sync2"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/transport"
+	"storj.io/storj/storagenode/trust"
+)
+
+var (
+	// Error is the default error class for the held amount service.
+	Error = errs.Class("heldamount")
+	mon   = monkit.Package()
+)
+
+// Config defines parameters for fetching the payouts from the satellites.
+type Config struct {
+	Interval time.Duration `help:"how frequently the payouts are fetched from the trusted satellites" default:"24h0m0s"`
+}
+
+// Service fetches the payouts of the node from the trusted satellites and
+// stores them locally.
+type Service struct {
+	log *zap.Logger
+	db  DB
+
+	transport transport.Client
+	trust     *trust.Pool
+
+	Loop sync2.Cycle
+}
+
+// NewService creates a new held amount service.
+func NewService(log *zap.Logger, transport transport.Client, trust *trust.Pool, db DB, config Config) *Service {
+	return &Service{
+		log:       log,
+		db:        db,
+		transport: transport,
+		trust:     trust,
+
+		Loop: *sync2.NewCycle(config.Interval),
+	}
+}
+
+// Run fetches the payouts from the trusted satellites on every interval.
+func (service *Service) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return service.Loop.Run(ctx, func(ctx context.Context) error {
+		service.Poll(ctx)
+		return nil
+	})
+}
+
+// Poll fetches and stores the payouts from every trusted satellite.
+func (service *Service) Poll(ctx context.Context) {
+	defer mon.Task()(&ctx)(nil)
+
+	for _, satelliteID := range service.trust.GetSatellites(ctx) {
+		if err := service.fetch(ctx, satelliteID); err != nil {
+			service.log.Warn("failed to fetch payouts", zap.Stringer("satellite", satelliteID), zap.Error(err))
+		}
+	}
+}
+
+// fetch fetches and stores the payouts of a single satellite. Only the
+// latest stored period and the following ones are fetched, since the
+// payouts of the previous periods don't change anymore.
+func (service *Service) fetch(ctx context.Context, satelliteID storj.NodeID) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	req := &pb.PayoutHistoryRequest{}
+	last, err := service.db.Last(ctx, satelliteID)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if last != nil {
+		req.Since, err = ptypes.TimestampProto(last.Period)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+	}
+
+	satellite, err := service.trust.FindSatellite(ctx, satelliteID)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	conn, err := service.transport.DialNode(ctx, &satellite)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() {
+		err = errs.Combine(err, Error.Wrap(conn.Close()))
+	}()
+
+	resp, err := pb.NewPayoutsClient(conn).History(ctx, req)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	now := time.Now().UTC()
+	for _, payout := range resp.Payouts {
+		period, err := ptypes.Timestamp(payout.Period)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		err = service.db.Store(ctx, Payout{
+			SatelliteID:  satelliteID,
+			Period:       period,
+			NodeMonth:    int(payout.NodeMonth),
+			SurgePercent: payout.SurgePercent,
+			HeldPercent:  payout.HeldPercent,
+			Gross:        payout.Gross,
+			Held:         payout.Held,
+			Paid:         payout.Paid,
+			UpdatedAt:    now,
+		})
+		if err != nil {
+			return Error.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// Close stops the held amount service.
+func (service *Service) Close() error {
+	service.Loop.Close()
+	return nil
+}
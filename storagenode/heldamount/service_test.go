@@ -0,0 +1,45 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package heldamount_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+)
+
+func TestPoll(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 1, UplinkCount: 0,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		node := planet.StorageNodes[0]
+
+		// the node is known by the satellite once it answered the satellite
+		_, err := satellite.Overlay.Service.UpdateUptime(ctx, node.ID(), true)
+		require.NoError(t, err)
+
+		node.Storage2.HeldAmount.Poll(ctx)
+		// polling again only refreshes the latest month
+		node.Storage2.HeldAmount.Poll(ctx)
+
+		all, err := node.DB.HeldAmount().All(ctx)
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+
+		now := time.Now().UTC()
+		payout := all[0]
+		assert.Equal(t, satellite.ID(), payout.SatelliteID)
+		assert.True(t, time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).Equal(payout.Period))
+		assert.Equal(t, 1, payout.NodeMonth)
+		assert.Equal(t, int64(100), payout.SurgePercent)
+		assert.Equal(t, int64(75), payout.HeldPercent)
+		assert.Equal(t, payout.Gross, payout.Held+payout.Paid)
+	})
+}
@@ -5,6 +5,7 @@ package inspector
 
 import (
 	"context"
+	"sort"
 	"strings"
 	"time"
 
@@ -19,7 +20,10 @@ import (
 	"storj.io/storj/pkg/piecestore/psserver/psdb"
 	"storj.io/storj/pkg/storj"
 	"storj.io/storj/storagenode/bandwidth"
+	"storj.io/storj/storagenode/diskhealth"
+	"storj.io/storj/storagenode/orders"
 	"storj.io/storj/storagenode/pieces"
+	"storj.io/storj/storagenode/reputation"
 )
 
 var (
@@ -31,33 +35,41 @@ var (
 
 // Endpoint does inspectory things
 type Endpoint struct {
-	log       *zap.Logger
-	pieceInfo pieces.DB
-	kademlia  *kademlia.Kademlia
-	usageDB   bandwidth.DB
-	psdbDB    *psdb.DB // TODO remove after complete migration
+	log        *zap.Logger
+	spaceUsed  pieces.SpaceUsed
+	pieceinfos pieces.DB
+	kademlia   *kademlia.Kademlia
+	usageDB    bandwidth.DB
+	ordersDB   orders.DB
+	reputation reputation.DB
+	psdbDB     *psdb.DB // TODO remove after complete migration
+	diskHealth *diskhealth.Service
 
 	startTime time.Time
 	config    psserver.Config
 }
 
 // NewEndpoint creates piecestore inspector instance
-func NewEndpoint(log *zap.Logger, pieceInfo pieces.DB, kademlia *kademlia.Kademlia, usageDB bandwidth.DB, psdbDB *psdb.DB, config psserver.Config) *Endpoint {
+func NewEndpoint(log *zap.Logger, spaceUsed pieces.SpaceUsed, pieceinfos pieces.DB, kademlia *kademlia.Kademlia, usageDB bandwidth.DB, ordersDB orders.DB, reputationDB reputation.DB, psdbDB *psdb.DB, diskHealth *diskhealth.Service, config psserver.Config) *Endpoint {
 	return &Endpoint{
-		log:       log,
-		pieceInfo: pieceInfo,
-		kademlia:  kademlia,
-		usageDB:   usageDB,
-		psdbDB:    psdbDB,
-		config:    config,
-		startTime: time.Now(),
+		log:        log,
+		spaceUsed:  spaceUsed,
+		pieceinfos: pieceinfos,
+		kademlia:   kademlia,
+		usageDB:    usageDB,
+		ordersDB:   ordersDB,
+		reputation: reputationDB,
+		psdbDB:     psdbDB,
+		diskHealth: diskHealth,
+		config:     config,
+		startTime:  time.Now(),
 	}
 }
 
 func (inspector *Endpoint) retrieveStats(ctx context.Context) (*pb.StatSummaryResponse, error) {
 
 	// Space Usage
-	totalUsedSpace, err := inspector.pieceInfo.SpaceUsed(ctx)
+	totalUsedSpace, err := inspector.spaceUsed.SpaceUsed(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -140,19 +152,173 @@ func (inspector *Endpoint) getDashboardData(ctx context.Context) (*pb.DashboardR
 		queried = nil
 	}
 
+	backoffs, err := inspector.ordersDB.ListBackoffs(ctx)
+	if err != nil {
+		return &pb.DashboardResponse{}, Error.Wrap(err)
+	}
+
+	settlementBackoffs := make([]*pb.SettlementBackoff, 0, len(backoffs))
+	for _, backoff := range backoffs {
+		nextRetry, err := ptypes.TimestampProto(backoff.NextRetry)
+		if err != nil {
+			inspector.log.Warn("next retry time bad", zap.Error(err))
+			nextRetry = nil
+		}
+		settlementBackoffs = append(settlementBackoffs, &pb.SettlementBackoff{
+			SatelliteId: backoff.SatelliteID,
+			Failures:    int32(backoff.Failures),
+			NextRetry:   nextRetry,
+		})
+	}
+
+	unsent, err := inspector.ordersDB.SummarizeUnsent(ctx)
+	if err != nil {
+		return &pb.DashboardResponse{}, Error.Wrap(err)
+	}
+
+	unsentOrders := make([]*pb.UnsentOrderSummary, 0, len(unsent))
+	for satelliteID, summary := range unsent {
+		unsentOrders = append(unsentOrders, &pb.UnsentOrderSummary{
+			SatelliteId: satelliteID,
+			OrderCount:  summary.Count,
+			Amount:      summary.Amount,
+		})
+	}
+	sort.Slice(unsentOrders, func(i, k int) bool {
+		return unsentOrders[i].SatelliteId.Less(unsentOrders[k].SatelliteId)
+	})
+
+	corrupted, err := inspector.pieceinfos.CountCorrupted(ctx)
+	if err != nil {
+		return &pb.DashboardResponse{}, Error.Wrap(err)
+	}
+
+	now := time.Now()
+	summaries, err := inspector.usageDB.DailySummaries(ctx, now, now)
+	if err != nil {
+		return &pb.DashboardResponse{}, Error.Wrap(err)
+	}
+
+	bandwidthToday := make([]*pb.BandwidthSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		day, err := ptypes.TimestampProto(summary.Day)
+		if err != nil {
+			inspector.log.Warn("bandwidth day bad", zap.Error(err))
+			day = nil
+		}
+		bandwidthToday = append(bandwidthToday, &pb.BandwidthSummary{
+			SatelliteId: summary.SatelliteID,
+			Day:         day,
+			Ingress:     summary.Ingress,
+			Egress:      summary.Egress,
+		})
+	}
+
+	satellites, err := inspector.summarizeSatellites(ctx, now, unsent)
+	if err != nil {
+		return &pb.DashboardResponse{}, Error.Wrap(err)
+	}
+
+	health := inspector.diskHealth.Status()
+	diskHealth := &pb.DiskHealth{
+		Degraded: health.Degraded,
+		Failing:  health.Failing,
+		Reason:   health.Reason,
+	}
+	if !health.LastChecked.IsZero() {
+		diskHealth.LastChecked, err = ptypes.TimestampProto(health.LastChecked)
+		if err != nil {
+			inspector.log.Warn("disk health last checked bad", zap.Error(err))
+		}
+	}
+
 	return &pb.DashboardResponse{
-		NodeId:           inspector.kademlia.Local().Id,
-		NodeConnections:  int64(len(nodes)),
-		BootstrapAddress: strings.Join(bsNodes[:], ", "),
-		InternalAddress:  "",
-		ExternalAddress:  inspector.kademlia.Local().Address.Address,
-		LastPinged:       pinged,
-		LastQueried:      queried,
-		Uptime:           ptypes.DurationProto(time.Since(inspector.startTime)),
-		Stats:            statsSummary,
+		NodeId:             inspector.kademlia.Local().Id,
+		NodeConnections:    int64(len(nodes)),
+		BootstrapAddress:   strings.Join(bsNodes[:], ", "),
+		InternalAddress:    "",
+		ExternalAddress:    inspector.kademlia.Local().Address.Address,
+		LastPinged:         pinged,
+		LastQueried:        queried,
+		Uptime:             ptypes.DurationProto(time.Since(inspector.startTime)),
+		Stats:              statsSummary,
+		SettlementBackoffs: settlementBackoffs,
+		UnsentOrders:       unsentOrders,
+		CorruptedPieces:    corrupted,
+		BandwidthToday:     bandwidthToday,
+		DiskHealth:         diskHealth,
+		Satellites:         satellites,
 	}, nil
 }
 
+// summarizeSatellites returns the space, bandwidth of the current month,
+// unsent orders and reputation of every satellite the node has data of.
+func (inspector *Endpoint) summarizeSatellites(ctx context.Context, now time.Time, unsent map[storj.NodeID]orders.UnsentSummary) (_ []*pb.SatelliteSummary, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	summaries := map[storj.NodeID]*pb.SatelliteSummary{}
+	summary := func(id storj.NodeID) *pb.SatelliteSummary {
+		if summaries[id] == nil {
+			summaries[id] = &pb.SatelliteSummary{SatelliteId: id}
+		}
+		return summaries[id]
+	}
+
+	spaceUsed, err := inspector.spaceUsed.SpaceUsedBySatellite(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for id, used := range spaceUsed {
+		summary(id).SpaceUsed = used
+	}
+
+	usages, err := inspector.usageDB.SummaryBySatellite(ctx, getBeginningOfMonth(), now)
+	if err != nil {
+		return nil, err
+	}
+	for id, usage := range usages {
+		summary(id).Ingress = usage.Ingress()
+		summary(id).Egress = usage.Egress()
+	}
+
+	for id, orders := range unsent {
+		summary(id).UnsentOrderCount = orders.Count
+		summary(id).UnsentAmount = orders.Amount
+	}
+
+	stats, err := inspector.reputation.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, stat := range stats {
+		satellite := summary(stat.SatelliteID)
+		satellite.AuditCount = stat.AuditCount
+		satellite.AuditSuccessRatio = stat.AuditSuccessRatio
+		satellite.UptimeCount = stat.UptimeCount
+		satellite.UptimeRatio = stat.UptimeRatio
+
+		satellite.ReputationUpdatedAt, err = ptypes.TimestampProto(stat.UpdatedAt)
+		if err != nil {
+			inspector.log.Warn("reputation updated at bad", zap.Error(err))
+		}
+		if stat.Disqualified != nil {
+			satellite.Disqualified, err = ptypes.TimestampProto(*stat.Disqualified)
+			if err != nil {
+				inspector.log.Warn("disqualified time bad", zap.Error(err))
+			}
+		}
+	}
+
+	satellites := make([]*pb.SatelliteSummary, 0, len(summaries))
+	for _, satellite := range summaries {
+		satellites = append(satellites, satellite)
+	}
+	sort.Slice(satellites, func(i, k int) bool {
+		return satellites[i].SatelliteId.Less(satellites[k].SatelliteId)
+	})
+	return satellites, nil
+}
+
 // Dashboard returns dashboard information
 func (inspector *Endpoint) Dashboard(ctx context.Context, in *pb.DashboardRequest) (out *pb.DashboardResponse, err error) {
 	defer mon.Task()(&ctx)(&err)
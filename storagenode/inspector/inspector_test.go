@@ -137,5 +137,14 @@ func TestInspectorDashboard(t *testing.T) {
 		assert.Equal(t, storageNode.Addr(), response.ExternalAddress)
 		assert.Equal(t, int64(len(planet.StorageNodes)+len(planet.Satellites)), response.NodeConnections)
 		assert.NotNil(t, response.Stats)
+
+		var ingress, spaceUsed int64
+		for _, satellite := range response.Satellites {
+			assert.Equal(t, planet.Satellites[0].ID(), satellite.SatelliteId)
+			ingress += satellite.Ingress
+			spaceUsed += satellite.SpaceUsed
+		}
+		assert.Equal(t, response.Stats.UsedIngress, ingress)
+		assert.Equal(t, response.Stats.UsedSpace, spaceUsed)
 	}
 }
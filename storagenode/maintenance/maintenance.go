@@ -0,0 +1,108 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package maintenance
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/internal/sync2"
+)
+
+var (
+	mon = monkit.Package()
+
+	// Error is the default error class for database maintenance errors
+	Error = errs.Class("database maintenance")
+)
+
+// vacuumStep is the number of free pages reclaimed at once, so that uploads
+// and downloads starting during the maintenance don't wait for all of it.
+const vacuumStep = 1000
+
+// DB implements the maintenance of the storage node database.
+type DB interface {
+	// IncrementalVacuum reclaims at most maxPages free pages of the database
+	// and returns the number of reclaimed bytes.
+	IncrementalVacuum(ctx context.Context, maxPages int) (int64, error)
+	// Analyze updates the statistics used for planning the queries.
+	Analyze(ctx context.Context) error
+}
+
+// Service vacuums and analyzes the database every interval when the storage
+// node is idle.
+type Service struct {
+	log  *zap.Logger
+	db   DB
+	idle func() bool
+
+	Loop sync2.Cycle
+}
+
+// NewService creates a new database maintenance service, idle reports whether
+// the storage node isn't handling any requests.
+func NewService(log *zap.Logger, db DB, idle func() bool, interval time.Duration) *Service {
+	return &Service{
+		log:  log,
+		db:   db,
+		idle: idle,
+
+		Loop: *sync2.NewCycle(interval),
+	}
+}
+
+// Run runs the maintenance on every interval.
+func (service *Service) Run(ctx context.Context) error {
+	return service.Loop.Run(ctx, func(ctx context.Context) error {
+		if err := service.Maintain(ctx); err != nil {
+			service.log.Error("maintaining database", zap.Error(err))
+		}
+		return nil
+	})
+}
+
+// Maintain vacuums and analyzes the database, it stops as soon as the storage
+// node isn't idle anymore.
+func (service *Service) Maintain(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if !service.idle() {
+		mon.Counter("db_maintenance_skipped").Inc(1)
+		service.log.Debug("storage node is busy, skipping database maintenance")
+		return nil
+	}
+
+	var total int64
+	for service.idle() {
+		reclaimed, err := service.db.IncrementalVacuum(ctx, vacuumStep)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		total += reclaimed
+		if reclaimed == 0 {
+			break
+		}
+	}
+
+	mon.IntVal("db_vacuum_reclaimed_bytes").Observe(total)
+	mon.Counter("db_vacuum_reclaimed_bytes_total").Inc(total)
+	if total > 0 {
+		service.log.Info("vacuumed database", zap.Int64("reclaimed bytes", total))
+	}
+
+	if !service.idle() {
+		return nil
+	}
+	return Error.Wrap(service.db.Analyze(ctx))
+}
+
+// Close stops the maintenance service.
+func (service *Service) Close() error {
+	service.Loop.Stop()
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package maintenance_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/maintenance"
+	"storj.io/storj/storagenode/storagenodedb/storagenodedbtest"
+)
+
+func TestMaintain(t *testing.T) {
+	storagenodedbtest.Run(t, func(t *testing.T, db storagenode.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		satelliteID := testplanet.MustPregeneratedSignedIdentity(1).ID
+		now := time.Now()
+
+		// fill and empty a table to leave free pages behind
+		for i := 0; i < 2000; i++ {
+			var serialNumber storj.SerialNumber
+			serialNumber[0], serialNumber[1] = byte(i), byte(i>>8)
+			require.NoError(t, db.UsedSerials().Add(ctx, satelliteID, serialNumber, now.Add(-time.Hour)))
+		}
+		require.NoError(t, db.UsedSerials().DeleteExpired(ctx, now))
+
+		service := maintenance.NewService(zaptest.NewLogger(t), db.Maintenance(), func() bool { return true }, time.Hour)
+		require.NoError(t, service.Maintain(ctx))
+
+		// everything was reclaimed
+		reclaimed, err := db.Maintenance().IncrementalVacuum(ctx, 1000)
+		require.NoError(t, err)
+		require.Equal(t, int64(0), reclaimed)
+
+		// the database keeps working
+		require.NoError(t, db.UsedSerials().Add(ctx, satelliteID, storj.SerialNumber{1}, now.Add(time.Hour)))
+	})
+}
+
+func TestMaintainBusy(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	db := &countingDB{}
+	service := maintenance.NewService(zaptest.NewLogger(t), db, func() bool { return false }, time.Hour)
+	require.NoError(t, service.Maintain(ctx))
+
+	// nothing is done while the node is busy
+	require.Equal(t, 0, db.vacuums)
+	require.Equal(t, 0, db.analyzes)
+}
+
+// countingDB counts the maintenance operations.
+type countingDB struct {
+	vacuums  int
+	analyzes int
+}
+
+func (db *countingDB) IncrementalVacuum(ctx context.Context, maxPages int) (int64, error) {
+	db.vacuums++
+	return 0, nil
+}
+
+func (db *countingDB) Analyze(ctx context.Context) error {
+	db.analyzes++
+	return nil
+}
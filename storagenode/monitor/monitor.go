@@ -5,6 +5,7 @@ package monitor
 
 import (
 	"context"
+	"sync/atomic"
 	"time"
 
 	"github.com/zeebo/errs"
@@ -35,22 +36,23 @@ type Service struct {
 	log                *zap.Logger
 	routingTable       *kademlia.RoutingTable
 	store              *pieces.Store
-	pieceInfo          pieces.DB
+	spaceUsed          pieces.SpaceUsed
 	usageDB            bandwidth.DB
 	allocatedDiskSpace int64
 	allocatedBandwidth int64
+	readOnly           int32
 	Loop               sync2.Cycle
 }
 
 // TODO: should it be responsible for monitoring actual bandwidth as well?
 
 // NewService creates a new storage node monitoring service.
-func NewService(log *zap.Logger, routingTable *kademlia.RoutingTable, store *pieces.Store, pieceInfo pieces.DB, usageDB bandwidth.DB, allocatedDiskSpace, allocatedBandwidth int64, interval time.Duration) *Service {
+func NewService(log *zap.Logger, routingTable *kademlia.RoutingTable, store *pieces.Store, spaceUsed pieces.SpaceUsed, usageDB bandwidth.DB, allocatedDiskSpace, allocatedBandwidth int64, interval time.Duration) *Service {
 	return &Service{
 		log:                log,
 		routingTable:       routingTable,
 		store:              store,
-		pieceInfo:          pieceInfo,
+		spaceUsed:          spaceUsed,
 		usageDB:            usageDB,
 		allocatedDiskSpace: allocatedDiskSpace,
 		allocatedBandwidth: allocatedBandwidth,
@@ -58,6 +60,16 @@ func NewService(log *zap.Logger, routingTable *kademlia.RoutingTable, store *pie
 	}
 }
 
+// SetReadOnly sets whether the node advertises no free disk space, so that
+// satellites stop selecting it for uploads.
+func (service *Service) SetReadOnly(readOnly bool) {
+	var value int32
+	if readOnly {
+		value = 1
+	}
+	atomic.StoreInt32(&service.readOnly, value)
+}
+
 // Run runs monitor service
 func (service *Service) Run(ctx context.Context) (err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -127,11 +139,16 @@ func (service *Service) updateNodeInformation(ctx context.Context) error {
 		return Error.Wrap(err)
 	}
 
+	freeDisk := service.allocatedDiskSpace - usedSpace
+	if atomic.LoadInt32(&service.readOnly) != 0 {
+		freeDisk = 0
+	}
+
 	self := service.routingTable.Local()
 
 	self.Restrictions = &pb.NodeRestrictions{
 		FreeBandwidth: service.allocatedBandwidth - usedBandwidth,
-		FreeDisk:      service.allocatedDiskSpace - usedSpace,
+		FreeDisk:      freeDisk,
 	}
 
 	// Update the routing table with latest restrictions
@@ -143,7 +160,7 @@ func (service *Service) updateNodeInformation(ctx context.Context) error {
 }
 
 func (service *Service) usedSpace(ctx context.Context) (int64, error) {
-	usedSpace, err := service.pieceInfo.SpaceUsed(ctx)
+	usedSpace, err := service.spaceUsed.SpaceUsed(ctx)
 	if err != nil {
 		return 0, err
 	}
@@ -0,0 +1,145 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/internal/post"
+)
+
+// NewSenders creates the senders enabled by config, operatorEmail receives
+// the emails when no recipients are configured.
+func NewSenders(config Config, operatorEmail string) (senders []Sender, err error) {
+	if config.WebhookURL != "" {
+		senders = append(senders, NewWebhook(config.WebhookURL, config.WebhookTimeout))
+	}
+
+	if config.SMTPServerAddress != "" {
+		email, err := newEmail(config, operatorEmail)
+		if err != nil {
+			return nil, err
+		}
+		senders = append(senders, email)
+	}
+
+	return senders, nil
+}
+
+// Webhook posts the notifications as json to an url.
+type Webhook struct {
+	url    string
+	client http.Client
+}
+
+// NewWebhook creates a sender posting the notifications to url.
+func NewWebhook(url string, timeout time.Duration) *Webhook {
+	return &Webhook{
+		url:    url,
+		client: http.Client{Timeout: timeout},
+	}
+}
+
+// Send posts the notification.
+func (webhook *Webhook) Send(ctx context.Context, notification *Notification) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	request, err := http.NewRequest(http.MethodPost, webhook.url, bytes.NewReader(body))
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := webhook.client.Do(request.WithContext(ctx))
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() {
+		_, _ = io.Copy(ioutil.Discard, response.Body)
+		err = errs.Combine(err, Error.Wrap(response.Body.Close()))
+	}()
+
+	if response.StatusCode/100 != 2 {
+		return Error.New("webhook responded with %s", response.Status)
+	}
+	return nil
+}
+
+// Email sends the notifications as emails through an smtp server.
+type Email struct {
+	sender *post.SMTPSender
+	to     []post.Address
+}
+
+// newEmail creates a sender emailing the notifications.
+func newEmail(config Config, operatorEmail string) (*Email, error) {
+	from, err := mail.ParseAddress(config.EmailFrom)
+	if err != nil {
+		return nil, Error.New("invalid notification email sender %q: %v", config.EmailFrom, err)
+	}
+
+	recipients := config.EmailTo
+	if recipients == "" {
+		recipients = operatorEmail
+	}
+	to, err := mail.ParseAddressList(recipients)
+	if err != nil {
+		return nil, Error.New("invalid notification email recipients %q: %v", recipients, err)
+	}
+
+	host, _, err := net.SplitHostPort(config.SMTPServerAddress)
+	if err != nil {
+		return nil, Error.New("invalid smtp server address %q: %v", config.SMTPServerAddress, err)
+	}
+
+	email := &Email{
+		sender: &post.SMTPSender{
+			From:          *from,
+			Auth:          smtp.PlainAuth("", config.SMTPLogin, config.SMTPPassword, host),
+			ServerAddress: config.SMTPServerAddress,
+		},
+	}
+	for _, address := range to {
+		email.to = append(email.to, *address)
+	}
+	return email, nil
+}
+
+// Send emails the notification.
+func (email *Email) Send(ctx context.Context, notification *Notification) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var text strings.Builder
+	_, _ = text.WriteString(notification.Message + "\n\n")
+	_, _ = text.WriteString("Node: " + notification.NodeID.String() + "\n")
+	if notification.SatelliteID != nil {
+		_, _ = text.WriteString("Satellite: " + notification.SatelliteID.String() + "\n")
+	}
+	_, _ = text.WriteString("Time: " + notification.Time.UTC().Format(time.RFC3339) + "\n")
+
+	return Error.Wrap(email.sender.SendEmail(&post.Message{
+		From:      email.sender.From,
+		To:        email.to,
+		Subject:   "Storage node notification: " + string(notification.Kind),
+		Date:      notification.Time,
+		PlainText: text.String(),
+	}))
+}
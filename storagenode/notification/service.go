@@ -0,0 +1,272 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package notification
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/internal/sync2"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storagenode/pieces"
+	"storj.io/storj/storagenode/reputation"
+)
+
+var (
+	// Error is the default error class for the operator notifications.
+	Error = errs.Class("notification")
+	mon   = monkit.Package()
+)
+
+// Config defines parameters for the operator notifications.
+type Config struct {
+	Interval         time.Duration `help:"how frequently the conditions the operator is notified about are checked" default:"5m0s"`
+	OfflineThreshold time.Duration `help:"how long the node can go without being contacted before the operator is notified" default:"2h0m0s"`
+	DiskUsedRatio    float64       `help:"ratio of the allocated disk space used above which the operator is notified" default:"0.9"`
+
+	WebhookURL     string        `help:"url the notifications are posted to as json, empty disables the webhook" default:""`
+	WebhookTimeout time.Duration `help:"timeout of posting a notification to the webhook" default:"30s"`
+
+	SMTPServerAddress string `help:"smtp server address the notification emails are sent through, empty disables the emails" default:""`
+	SMTPLogin         string `help:"login of the smtp server" default:""`
+	SMTPPassword      string `help:"password of the smtp server" default:""`
+	EmailFrom         string `help:"sender address of the notification emails" default:""`
+	EmailTo           string `help:"comma separated recipients of the notification emails, the operator email when empty" default:""`
+}
+
+// Kind is the kind of event the operator is notified about.
+type Kind string
+
+const (
+	// KindOffline is sent when the node wasn't contacted for too long.
+	KindOffline Kind = "offline"
+	// KindDiskFull is sent when the disk space used nears the allocated disk space.
+	KindDiskFull Kind = "disk-full"
+	// KindAuditFailures is sent when a satellite reports failed audits.
+	KindAuditFailures Kind = "audit-failures"
+	// KindDisqualified is sent when a satellite disqualified the node.
+	KindDisqualified Kind = "disqualified"
+	// KindVersion is sent when the running version is no longer allowed.
+	KindVersion Kind = "version"
+)
+
+// Notification is an event the operator is notified about.
+type Notification struct {
+	Kind   Kind         `json:"kind"`
+	NodeID storj.NodeID `json:"nodeID"`
+	// SatelliteID is set when the event is about a satellite
+	SatelliteID *storj.NodeID `json:"satelliteID,omitempty"`
+	Message     string        `json:"message"`
+	Time        time.Time     `json:"time"`
+}
+
+// Sender delivers notifications to the operator.
+type Sender interface {
+	Send(ctx context.Context, notification *Notification) error
+}
+
+// Activity returns when the node was last contacted by other nodes.
+type Activity interface {
+	LastPinged() time.Time
+	LastQueried() time.Time
+}
+
+// VersionChecker returns whether the running version is allowed.
+type VersionChecker interface {
+	IsAllowed() bool
+}
+
+// Service checks periodically for the events the operator should know about
+// and notifies the operator through the configured senders. Every condition
+// is notified once, when it starts.
+type Service struct {
+	log     *zap.Logger
+	config  Config
+	nodeID  storj.NodeID
+	senders []Sender
+
+	activity     Activity
+	version      VersionChecker
+	spaceUsed    pieces.SpaceUsed
+	reputationDB reputation.DB
+
+	allocatedDiskSpace int64
+	started            time.Time
+
+	// the conditions found by the last check
+	offline       bool
+	diskFull      bool
+	outdated      bool
+	auditFailures map[storj.NodeID]int64
+	disqualified  map[storj.NodeID]bool
+
+	Loop sync2.Cycle
+}
+
+// NewService creates a new notification service, which notifies through
+// the senders.
+func NewService(log *zap.Logger, nodeID storj.NodeID, senders []Sender, activity Activity, version VersionChecker, spaceUsed pieces.SpaceUsed, reputationDB reputation.DB, allocatedDiskSpace int64, config Config) *Service {
+	return &Service{
+		log:     log,
+		config:  config,
+		nodeID:  nodeID,
+		senders: senders,
+
+		activity:     activity,
+		version:      version,
+		spaceUsed:    spaceUsed,
+		reputationDB: reputationDB,
+
+		allocatedDiskSpace: allocatedDiskSpace,
+		started:            time.Now(),
+
+		Loop: *sync2.NewCycle(config.Interval),
+	}
+}
+
+// Run checks the conditions on every interval. The loop runs even without
+// senders, so that its interval can be changed on reload.
+func (service *Service) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return service.Loop.Run(ctx, func(ctx context.Context) error {
+		if len(service.senders) == 0 {
+			return nil
+		}
+		if err := service.Check(ctx, time.Now()); err != nil {
+			service.log.Error("checking the notification conditions", zap.Error(err))
+		}
+		return nil
+	})
+}
+
+// Close stops the notification service.
+func (service *Service) Close() error {
+	service.Loop.Close()
+	return nil
+}
+
+// Check notifies the operator about the conditions which started since the
+// last check.
+func (service *Service) Check(ctx context.Context, now time.Time) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	lastContact := service.started
+	if pinged := service.activity.LastPinged(); pinged.After(lastContact) {
+		lastContact = pinged
+	}
+	if queried := service.activity.LastQueried(); queried.After(lastContact) {
+		lastContact = queried
+	}
+	offline := now.Sub(lastContact) > service.config.OfflineThreshold
+	if offline && !service.offline {
+		service.notify(ctx, &Notification{
+			Kind:    KindOffline,
+			Message: fmt.Sprintf("the node wasn't contacted since %s", lastContact.UTC().Format(time.RFC3339)),
+			Time:    now,
+		})
+	}
+	service.offline = offline
+
+	outdated := !service.version.IsAllowed()
+	if outdated && !service.outdated {
+		service.notify(ctx, &Notification{
+			Kind:    KindVersion,
+			Message: "the running version is below the minimum allowed version, the node must be updated",
+			Time:    now,
+		})
+	}
+	service.outdated = outdated
+
+	var group errs.Group
+	group.Add(service.checkDiskSpace(ctx, now))
+	group.Add(service.checkReputation(ctx, now))
+	return group.Err()
+}
+
+// checkDiskSpace notifies when the disk space used nears the allocated disk space.
+func (service *Service) checkDiskSpace(ctx context.Context, now time.Time) error {
+	if service.allocatedDiskSpace <= 0 {
+		return nil
+	}
+
+	used, err := service.spaceUsed.SpaceUsed(ctx)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	ratio := float64(used) / float64(service.allocatedDiskSpace)
+	diskFull := ratio >= service.config.DiskUsedRatio
+	if diskFull && !service.diskFull {
+		service.notify(ctx, &Notification{
+			Kind:    KindDiskFull,
+			Message: fmt.Sprintf("the node uses %.1f%% of the allocated disk space", ratio*100),
+			Time:    now,
+		})
+	}
+	service.diskFull = diskFull
+	return nil
+}
+
+// checkReputation notifies about the audit failures and the
+// disqualifications reported by the satellites since the last check. The
+// failures before the first check aren't notified.
+func (service *Service) checkReputation(ctx context.Context, now time.Time) error {
+	all, err := service.reputationDB.All(ctx)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	first := service.auditFailures == nil
+	auditFailures := map[storj.NodeID]int64{}
+	disqualified := map[storj.NodeID]bool{}
+	for _, stats := range all {
+		satelliteID := stats.SatelliteID
+
+		failures := stats.AuditCount - stats.AuditSuccessCount
+		auditFailures[satelliteID] = failures
+		if previous := service.auditFailures[satelliteID]; !first && failures > previous {
+			service.notify(ctx, &Notification{
+				Kind:        KindAuditFailures,
+				SatelliteID: &satelliteID,
+				Message: fmt.Sprintf("the satellite reported %d new failed audits, the audit success ratio is %.2f%%",
+					failures-previous, stats.AuditSuccessRatio*100),
+				Time: now,
+			})
+		}
+
+		if stats.Disqualified != nil {
+			disqualified[satelliteID] = true
+			if !service.disqualified[satelliteID] {
+				service.notify(ctx, &Notification{
+					Kind:        KindDisqualified,
+					SatelliteID: &satelliteID,
+					Message:     fmt.Sprintf("the satellite disqualified the node at %s", stats.Disqualified.UTC().Format(time.RFC3339)),
+					Time:        now,
+				})
+			}
+		}
+	}
+	service.auditFailures = auditFailures
+	service.disqualified = disqualified
+	return nil
+}
+
+// notify sends the notification through every sender, the failures are only logged.
+func (service *Service) notify(ctx context.Context, notification *Notification) {
+	notification.NodeID = service.nodeID
+	service.log.Info("notifying the operator", zap.String("kind", string(notification.Kind)), zap.String("message", notification.Message))
+	mon.Meter("notifications_" + string(notification.Kind)).Mark(1)
+
+	for _, sender := range service.senders {
+		if err := sender.Send(ctx, notification); err != nil {
+			service.log.Error("failed to send notification", zap.String("kind", string(notification.Kind)), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package notification_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/notification"
+	"storj.io/storj/storagenode/reputation"
+	"storj.io/storj/storagenode/storagenodedb/storagenodedbtest"
+)
+
+type activity struct{ pinged, queried time.Time }
+
+func (activity *activity) LastPinged() time.Time  { return activity.pinged }
+func (activity *activity) LastQueried() time.Time { return activity.queried }
+
+type versionChecker struct{ allowed bool }
+
+func (checker *versionChecker) IsAllowed() bool { return checker.allowed }
+
+type spaceUsed struct{ used int64 }
+
+func (space *spaceUsed) SpaceUsed(ctx context.Context) (int64, error) { return space.used, nil }
+func (space *spaceUsed) SpaceUsedBySatellite(ctx context.Context) (map[storj.NodeID]int64, error) {
+	return nil, nil
+}
+
+type sender struct{ sent []notification.Notification }
+
+func (sender *sender) Send(ctx context.Context, notification *notification.Notification) error {
+	sender.sent = append(sender.sent, *notification)
+	return nil
+}
+
+// kinds returns the kinds of the notifications sent since the last call.
+func (sender *sender) kinds() []notification.Kind {
+	var kinds []notification.Kind
+	for _, sent := range sender.sent {
+		kinds = append(kinds, sent.Kind)
+	}
+	sender.sent = nil
+	return kinds
+}
+
+func TestService(t *testing.T) {
+	storagenodedbtest.Run(t, func(t *testing.T, db storagenode.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		nodeID := testplanet.MustPregeneratedSignedIdentity(0).ID
+		satelliteID := testplanet.MustPregeneratedSignedIdentity(1).ID
+
+		now := time.Now()
+		activity := &activity{pinged: now}
+		version := &versionChecker{allowed: true}
+		space := &spaceUsed{used: 10}
+		sender := &sender{}
+
+		service := notification.NewService(zaptest.NewLogger(t), nodeID, []notification.Sender{sender},
+			activity, version, space, db.Reputation(), 100,
+			notification.Config{
+				Interval:         time.Hour,
+				OfflineThreshold: time.Hour,
+				DiskUsedRatio:    0.9,
+			})
+
+		stats := reputation.Stats{
+			SatelliteID:       satelliteID,
+			AuditCount:        10,
+			AuditSuccessCount: 9,
+			AuditSuccessRatio: 0.9,
+			UpdatedAt:         now,
+		}
+		require.NoError(t, db.Reputation().Store(ctx, stats))
+
+		// the failures before the first check aren't notified
+		require.NoError(t, service.Check(ctx, now))
+		assert.Empty(t, sender.kinds())
+
+		space.used = 95
+		version.allowed = false
+		stats.AuditCount, stats.AuditSuccessCount = 12, 9
+		disqualified := now
+		stats.Disqualified = &disqualified
+		require.NoError(t, db.Reputation().Store(ctx, stats))
+
+		now = now.Add(2 * time.Hour)
+		require.NoError(t, service.Check(ctx, now))
+		assert.ElementsMatch(t, []notification.Kind{
+			notification.KindOffline,
+			notification.KindVersion,
+			notification.KindDiskFull,
+			notification.KindAuditFailures,
+			notification.KindDisqualified,
+		}, sender.kinds())
+
+		// the conditions which continue aren't notified again
+		require.NoError(t, service.Check(ctx, now.Add(time.Minute)))
+		assert.Empty(t, sender.kinds())
+
+		// the conditions are notified again once they ended and started again
+		activity.pinged = now.Add(time.Minute)
+		require.NoError(t, service.Check(ctx, now.Add(2*time.Minute)))
+		assert.Empty(t, sender.kinds())
+		require.NoError(t, service.Check(ctx, now.Add(3*time.Hour)))
+		assert.Equal(t, []notification.Kind{notification.KindOffline}, sender.kinds())
+	})
+}
+
+func TestWebhook(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	received := make(chan notification.Notification, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var notification notification.Notification
+		if err := json.NewDecoder(r.Body).Decode(&notification); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		received <- notification
+	}))
+	defer server.Close()
+
+	satelliteID := testplanet.MustPregeneratedSignedIdentity(1).ID
+	expected := notification.Notification{
+		Kind:        notification.KindAuditFailures,
+		NodeID:      testplanet.MustPregeneratedSignedIdentity(0).ID,
+		SatelliteID: &satelliteID,
+		Message:     "failed audits",
+		Time:        time.Now().UTC().Truncate(time.Second),
+	}
+
+	webhook := notification.NewWebhook(server.URL, time.Minute)
+	require.NoError(t, webhook.Send(ctx, &expected))
+	assert.Equal(t, expected, <-received)
+
+	failing := notification.NewWebhook(server.URL+"/missing", time.Minute)
+	server.Config.Handler = http.NotFoundHandler()
+	require.Error(t, failing.Send(ctx, &expected))
+}
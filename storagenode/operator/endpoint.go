@@ -0,0 +1,101 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package operator
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/storagenode/heldamount"
+	"storj.io/storj/storagenode/monitor"
+	"storj.io/storj/storagenode/piecestore"
+)
+
+var (
+	// Error is the default error class for the node operator endpoint.
+	Error = errs.Class("node operator")
+	mon   = monkit.Package()
+)
+
+// Config defines the access of the node operator to the private address.
+type Config struct {
+	Token string `help:"token required by the operator clients of the private address, such as the dashboard, empty only allows them from localhost" default:""`
+}
+
+// Endpoint serves the requests of the node operator on the private address.
+type Endpoint struct {
+	log        *zap.Logger
+	piecestore *piecestore.Endpoint
+	monitor    *monitor.Service
+	heldAmount heldamount.DB
+}
+
+// NewEndpoint creates a new node operator endpoint.
+func NewEndpoint(log *zap.Logger, piecestore *piecestore.Endpoint, monitor *monitor.Service, heldAmount heldamount.DB) *Endpoint {
+	return &Endpoint{
+		log:        log,
+		piecestore: piecestore,
+		monitor:    monitor,
+		heldAmount: heldAmount,
+	}
+}
+
+// Maintenance returns whether the node is in maintenance mode.
+func (endpoint *Endpoint) Maintenance(ctx context.Context, req *pb.MaintenanceRequest) (_ *pb.MaintenanceResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return &pb.MaintenanceResponse{Enabled: endpoint.piecestore.ReadOnly()}, nil
+}
+
+// SetMaintenance enters or leaves maintenance mode, which is the read-only
+// mode of the node until a configuration reload applies storage2.read-only.
+func (endpoint *Endpoint) SetMaintenance(ctx context.Context, req *pb.SetMaintenanceRequest) (_ *pb.MaintenanceResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if req.Enabled != endpoint.piecestore.ReadOnly() {
+		endpoint.piecestore.SetReadOnly(req.Enabled)
+		endpoint.monitor.SetReadOnly(req.Enabled)
+		// advertise the free disk space of the new mode right away
+		endpoint.monitor.Loop.Trigger()
+		endpoint.log.Info("maintenance mode changed by the operator", zap.Bool("enabled", req.Enabled))
+	}
+	return &pb.MaintenanceResponse{Enabled: endpoint.piecestore.ReadOnly()}, nil
+}
+
+// Payouts returns the payouts last reported by the satellites.
+func (endpoint *Endpoint) Payouts(ctx context.Context, req *pb.OperatorPayoutsRequest) (_ *pb.OperatorPayoutsResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	payouts, err := endpoint.heldAmount.All(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, Error.Wrap(err).Error())
+	}
+
+	response := &pb.OperatorPayoutsResponse{}
+	for _, payout := range payouts {
+		period, err := ptypes.TimestampProto(payout.Period)
+		if err != nil {
+			return nil, status.Error(codes.Internal, Error.Wrap(err).Error())
+		}
+		response.Payouts = append(response.Payouts, &pb.SatellitePayout{
+			SatelliteId: payout.SatelliteID,
+			Payout: &pb.Payout{
+				Period:       period,
+				NodeMonth:    int32(payout.NodeMonth),
+				SurgePercent: payout.SurgePercent,
+				HeldPercent:  payout.HeldPercent,
+				Gross:        payout.Gross,
+				Held:         payout.Held,
+				Paid:         payout.Paid,
+			},
+		})
+	}
+	return response, nil
+}
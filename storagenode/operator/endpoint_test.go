@@ -0,0 +1,64 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package operator_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/transport"
+	"storj.io/storj/storagenode"
+)
+
+func TestMaintenance(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 1, UplinkCount: 0,
+		Reconfigure: testplanet.Reconfigure{
+			StorageNode: func(index int, config *storagenode.Config) {
+				config.Operator.Token = "secret"
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		node := planet.StorageNodes[0]
+
+		// requests without the token are rejected, even from localhost
+		conn, err := transport.DialAddressInsecure(ctx, node.PrivateAddr())
+		require.NoError(t, err)
+		defer ctx.Check(conn.Close)
+
+		_, err = pb.NewNodeOperatorClient(conn).Maintenance(ctx, &pb.MaintenanceRequest{})
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+		_, err = pb.NewPieceStoreInspectorClient(conn).Dashboard(ctx, &pb.DashboardRequest{})
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+		conn, err = transport.DialAddressInsecure(ctx, node.PrivateAddr(), transport.WithToken("secret"))
+		require.NoError(t, err)
+		defer ctx.Check(conn.Close)
+		client := pb.NewNodeOperatorClient(conn)
+
+		response, err := client.Maintenance(ctx, &pb.MaintenanceRequest{})
+		require.NoError(t, err)
+		require.False(t, response.Enabled)
+
+		response, err = client.SetMaintenance(ctx, &pb.SetMaintenanceRequest{Enabled: true})
+		require.NoError(t, err)
+		require.True(t, response.Enabled)
+		require.True(t, node.Storage2.Endpoint.ReadOnly())
+
+		response, err = client.SetMaintenance(ctx, &pb.SetMaintenanceRequest{Enabled: false})
+		require.NoError(t, err)
+		require.False(t, response.Enabled)
+		require.False(t, node.Storage2.Endpoint.ReadOnly())
+
+		payouts, err := client.Payouts(ctx, &pb.OperatorPayoutsRequest{})
+		require.NoError(t, err)
+		require.Empty(t, payouts.Payouts)
+	})
+}
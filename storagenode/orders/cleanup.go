@@ -0,0 +1,102 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package orders
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/internal/sync2"
+)
+
+var mon = monkit.Package()
+
+// CleanupConfig defines configuration for archiving expired orders and deleting old archived orders.
+type CleanupConfig struct {
+	Interval   time.Duration `help:"duration between archiving expired orders and deleting old archived orders" default:"1h0m0s"`
+	ArchiveTTL time.Duration `help:"how long archived orders are kept, zero keeps them forever" default:"0s"`
+}
+
+// Cleanup archives every interval the unsent orders which expired, and deletes
+// the archived orders older than the archive TTL.
+type Cleanup struct {
+	log    *zap.Logger
+	config CleanupConfig
+
+	orders DB
+
+	Loop sync2.Cycle
+}
+
+// NewCleanup creates an orders cleanup chore.
+func NewCleanup(log *zap.Logger, orders DB, config CleanupConfig) *Cleanup {
+	return &Cleanup{
+		log:    log,
+		orders: orders,
+		config: config,
+
+		Loop: *sync2.NewCycle(config.Interval),
+	}
+}
+
+// Run archives the expired orders and deletes the old archived orders on every interval.
+func (cleanup *Cleanup) Run(ctx context.Context) error {
+	return cleanup.Loop.Run(ctx, func(ctx context.Context) error {
+		if err := cleanup.ArchiveExpired(ctx, time.Now()); err != nil {
+			cleanup.log.Error("archiving expired orders", zap.Error(err))
+		}
+		if err := cleanup.DeleteExpired(ctx, time.Now()); err != nil {
+			cleanup.log.Error("deleting archived orders", zap.Error(err))
+		}
+		return nil
+	})
+}
+
+// ArchiveExpired archives the unsent orders whose order limit expired before
+// now, since they can't be settled anymore.
+func (cleanup *Cleanup) ArchiveExpired(ctx context.Context, now time.Time) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	count, err := cleanup.orders.CleanExpired(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	mon.IntVal("expired_orders_archived").Observe(count)
+	mon.Counter("expired_orders_archived_total").Inc(count)
+	if count > 0 {
+		cleanup.log.Info("archived expired orders", zap.Int64("count", count))
+	}
+	return nil
+}
+
+// DeleteExpired deletes the orders archived before now minus the archive TTL.
+func (cleanup *Cleanup) DeleteExpired(ctx context.Context, now time.Time) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if cleanup.config.ArchiveTTL <= 0 {
+		return nil
+	}
+
+	count, err := cleanup.orders.DeleteArchived(ctx, now.Add(-cleanup.config.ArchiveTTL))
+	if err != nil {
+		return err
+	}
+
+	mon.IntVal("archived_orders_deleted").Observe(count)
+	mon.Counter("archived_orders_deleted_total").Inc(count)
+	if count > 0 {
+		cleanup.log.Info("deleted archived orders", zap.Int64("count", count))
+	}
+	return nil
+}
+
+// Close stops the cleanup chore.
+func (cleanup *Cleanup) Close() error {
+	cleanup.Loop.Stop()
+	return nil
+}
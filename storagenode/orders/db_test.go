@@ -6,10 +6,13 @@ package orders_test
 import (
 	"crypto/rand"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/ptypes"
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/require"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap/zaptest"
 
 	"storj.io/storj/internal/testcontext"
 	"storj.io/storj/internal/testplanet"
@@ -42,7 +45,7 @@ func TestOrders(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, emptyUnsent, 0)
 
-		emptyArchive, err := ordersdb.ListArchived(ctx, 100)
+		emptyArchive, _, err := ordersdb.ListArchived(ctx, orders.ArchiveCursor{}, 100)
 		require.NoError(t, err)
 		require.Len(t, emptyArchive, 0)
 
@@ -79,14 +82,14 @@ func TestOrders(t *testing.T) {
 
 		// duplicate add
 		err = ordersdb.Enqueue(ctx, info)
-		require.Error(t, err, "duplicate add")
+		require.True(t, orders.ErrSerialAlreadyExists.Has(err), "duplicate add")
 
 		unsent, err := ordersdb.ListUnsent(ctx, 100)
 		require.NoError(t, err)
 		require.Empty(t, cmp.Diff([]*orders.Info{info}, unsent, cmp.Comparer(pb.Equal)))
 
 		// list by group
-		unsentGrouped, err := ordersdb.ListUnsentBySatellite(ctx)
+		unsentGrouped, err := ordersdb.ListUnsentBySatellite(ctx, 100)
 		require.NoError(t, err)
 
 		expectedGrouped := map[storj.NodeID][]*orders.Info{
@@ -110,7 +113,7 @@ func TestOrders(t *testing.T) {
 		require.Len(t, unsent, 0)
 
 		// it should now be in the archive
-		archived, err := ordersdb.ListArchived(ctx, 100)
+		archived, _, err := ordersdb.ListArchived(ctx, orders.ArchiveCursor{}, 100)
 		require.NoError(t, err)
 		require.Len(t, archived, 1)
 
@@ -128,6 +131,663 @@ func TestOrders(t *testing.T) {
 	})
 }
 
+func TestListUnsentBatches(t *testing.T) {
+	storagenodedbtest.Run(t, func(t *testing.T, db storagenode.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		ordersdb := db.Orders()
+
+		storagenode := testplanet.MustPregeneratedSignedIdentity(0)
+		satellite0 := testplanet.MustPregeneratedSignedIdentity(1)
+		satellite1 := testplanet.MustPregeneratedSignedIdentity(2)
+		uplink := testplanet.MustPregeneratedSignedIdentity(3)
+
+		now := ptypes.TimestampNow()
+
+		counts := map[storj.NodeID]int{satellite0.ID: 7, satellite1.ID: 3}
+		for satelliteID, count := range counts {
+			for i := 0; i < count; i++ {
+				serialNumber := newRandomSerial()
+				err := ordersdb.Enqueue(ctx, &orders.Info{
+					Limit: &pb.OrderLimit2{
+						SerialNumber:    serialNumber,
+						SatelliteId:     satelliteID,
+						UplinkId:        uplink.ID,
+						StorageNodeId:   storagenode.ID,
+						PieceId:         storj.NewPieceID(),
+						Limit:           100,
+						Action:          pb.PieceAction_GET,
+						PieceExpiration: now,
+						OrderExpiration: now,
+					},
+					Order: &pb.Order2{
+						SerialNumber: serialNumber,
+						Amount:       50,
+					},
+					Uplink: uplink.PeerIdentity(),
+				})
+				require.NoError(t, err)
+			}
+		}
+
+		satellites, err := ordersdb.ListUnsentSatellites(ctx)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []storj.NodeID{satellite0.ID, satellite1.ID}, satellites)
+
+		// per satellite limit
+		grouped, err := ordersdb.ListUnsentBySatellite(ctx, 5)
+		require.NoError(t, err)
+		require.Len(t, grouped[satellite0.ID], 5)
+		require.Len(t, grouped[satellite1.ID], 3)
+
+		// batches
+		for satelliteID, count := range counts {
+			serials := map[storj.SerialNumber]bool{}
+			cursor := orders.UnsentCursor{SatelliteID: satelliteID}
+			for {
+				batch, next, err := ordersdb.ListUnsentBatch(ctx, cursor, 2)
+				require.NoError(t, err)
+				require.True(t, len(batch) <= 2)
+				for _, info := range batch {
+					require.Equal(t, satelliteID, info.Limit.SatelliteId)
+					serials[info.Limit.SerialNumber] = true
+				}
+				if len(batch) < 2 {
+					break
+				}
+				cursor = next
+			}
+			require.Len(t, serials, count)
+		}
+	})
+}
+
+func TestListArchivedPages(t *testing.T) {
+	storagenodedbtest.Run(t, func(t *testing.T, db storagenode.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		ordersdb := db.Orders()
+
+		storagenode := testplanet.MustPregeneratedSignedIdentity(0)
+		satellite0 := testplanet.MustPregeneratedSignedIdentity(1)
+		satellite1 := testplanet.MustPregeneratedSignedIdentity(2)
+		uplink := testplanet.MustPregeneratedSignedIdentity(3)
+
+		now := ptypes.TimestampNow()
+
+		const count = 10
+		serials := map[storj.NodeID][]storj.SerialNumber{}
+		for i := 0; i < count; i++ {
+			satellite := satellite0
+			if i%2 == 1 {
+				satellite = satellite1
+			}
+
+			serialNumber := newRandomSerial()
+			err := ordersdb.Enqueue(ctx, &orders.Info{
+				Limit: &pb.OrderLimit2{
+					SerialNumber:    serialNumber,
+					SatelliteId:     satellite.ID,
+					UplinkId:        uplink.ID,
+					StorageNodeId:   storagenode.ID,
+					PieceId:         storj.NewPieceID(),
+					Limit:           100,
+					Action:          pb.PieceAction_GET,
+					PieceExpiration: now,
+					OrderExpiration: now,
+				},
+				Order: &pb.Order2{
+					SerialNumber: serialNumber,
+					Amount:       50,
+				},
+				Uplink: uplink.PeerIdentity(),
+			})
+			require.NoError(t, err)
+
+			err = ordersdb.Archive(ctx, satellite.ID, serialNumber, orders.StatusAccepted)
+			require.NoError(t, err)
+			serials[satellite.ID] = append(serials[satellite.ID], serialNumber)
+		}
+
+		listAll := func(cursor orders.ArchiveCursor, limit int) []storj.SerialNumber {
+			var listed []storj.SerialNumber
+			for {
+				archived, next, err := ordersdb.ListArchived(ctx, cursor, limit)
+				require.NoError(t, err)
+				require.True(t, len(archived) <= limit)
+				for _, info := range archived {
+					listed = append(listed, info.Limit.SerialNumber)
+				}
+				if len(archived) < limit {
+					return listed
+				}
+				require.True(t, next.Position > cursor.Position)
+				cursor = next
+			}
+		}
+
+		// paging through all the orders
+		all := listAll(orders.ArchiveCursor{}, 3)
+		require.Len(t, all, count)
+
+		// filtering by satellite
+		require.Equal(t, serials[satellite0.ID], listAll(orders.ArchiveCursor{SatelliteID: satellite0.ID}, 2))
+		require.Equal(t, serials[satellite1.ID], listAll(orders.ArchiveCursor{SatelliteID: satellite1.ID}, 100))
+
+		// filtering by archival time
+		require.Len(t, listAll(orders.ArchiveCursor{ArchivedAfter: time.Now().Add(-time.Hour)}, 4), count)
+		require.Empty(t, listAll(orders.ArchiveCursor{ArchivedAfter: time.Now().Add(time.Hour)}, 4))
+		require.Empty(t, listAll(orders.ArchiveCursor{ArchivedBefore: time.Now().Add(-time.Hour)}, 4))
+	})
+}
+
+func TestArchiveBatch(t *testing.T) {
+	storagenodedbtest.Run(t, func(t *testing.T, db storagenode.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		ordersdb := db.Orders()
+
+		storagenode := testplanet.MustPregeneratedSignedIdentity(0)
+		satellite0 := testplanet.MustPregeneratedSignedIdentity(1)
+		uplink := testplanet.MustPregeneratedSignedIdentity(3)
+
+		now := ptypes.TimestampNow()
+
+		const count = 6
+		var requests []orders.ArchiveRequest
+		for i := 0; i < count; i++ {
+			serialNumber := newRandomSerial()
+			err := ordersdb.Enqueue(ctx, &orders.Info{
+				Limit: &pb.OrderLimit2{
+					SerialNumber:    serialNumber,
+					SatelliteId:     satellite0.ID,
+					UplinkId:        uplink.ID,
+					StorageNodeId:   storagenode.ID,
+					PieceId:         storj.NewPieceID(),
+					Limit:           100,
+					Action:          pb.PieceAction_GET,
+					PieceExpiration: now,
+					OrderExpiration: now,
+				},
+				Order: &pb.Order2{
+					SerialNumber: serialNumber,
+					Amount:       50,
+				},
+				Uplink: uplink.PeerIdentity(),
+			})
+			require.NoError(t, err)
+
+			status := orders.StatusAccepted
+			if i%2 == 1 {
+				status = orders.StatusRejected
+			}
+			requests = append(requests, orders.ArchiveRequest{Serial: serialNumber, Status: status})
+		}
+
+		// an unknown order is skipped, the others are archived
+		unknown := newRandomSerial()
+		missing, err := ordersdb.ArchiveBatch(ctx, satellite0.ID, append(requests[:1:1], orders.ArchiveRequest{
+			Serial: unknown,
+			Status: orders.StatusAccepted,
+		}))
+		require.NoError(t, err)
+		require.Equal(t, []storj.SerialNumber{unknown}, missing)
+
+		unsent, err := ordersdb.ListUnsent(ctx, 100)
+		require.NoError(t, err)
+		require.Len(t, unsent, count-1)
+
+		// the orders that were already archived are skipped
+		missing, err = ordersdb.ArchiveBatch(ctx, satellite0.ID, requests)
+		require.NoError(t, err)
+		require.Equal(t, []storj.SerialNumber{requests[0].Serial}, missing)
+
+		unsent, err = ordersdb.ListUnsent(ctx, 100)
+		require.NoError(t, err)
+		require.Empty(t, unsent)
+
+		archived, _, err := ordersdb.ListArchived(ctx, orders.ArchiveCursor{}, 100)
+		require.NoError(t, err)
+		require.Len(t, archived, count)
+
+		statuses := map[storj.SerialNumber]orders.Status{}
+		for _, info := range archived {
+			statuses[info.Limit.SerialNumber] = info.Status
+		}
+		for _, request := range requests {
+			require.Equal(t, request.Status, statuses[request.Serial])
+		}
+	})
+}
+
+func TestCleanupArchived(t *testing.T) {
+	storagenodedbtest.Run(t, func(t *testing.T, db storagenode.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		ordersdb := db.Orders()
+
+		storagenode := testplanet.MustPregeneratedSignedIdentity(0)
+		satellite0 := testplanet.MustPregeneratedSignedIdentity(1)
+		uplink := testplanet.MustPregeneratedSignedIdentity(3)
+
+		now := ptypes.TimestampNow()
+
+		const count = 5
+		for i := 0; i < count; i++ {
+			serialNumber := newRandomSerial()
+			err := ordersdb.Enqueue(ctx, &orders.Info{
+				Limit: &pb.OrderLimit2{
+					SerialNumber:    serialNumber,
+					SatelliteId:     satellite0.ID,
+					UplinkId:        uplink.ID,
+					StorageNodeId:   storagenode.ID,
+					PieceId:         storj.NewPieceID(),
+					Limit:           100,
+					Action:          pb.PieceAction_GET,
+					PieceExpiration: now,
+					OrderExpiration: now,
+				},
+				Order: &pb.Order2{
+					SerialNumber: serialNumber,
+					Amount:       50,
+				},
+				Uplink: uplink.PeerIdentity(),
+			})
+			require.NoError(t, err)
+
+			err = ordersdb.Archive(ctx, satellite0.ID, serialNumber, orders.StatusAccepted)
+			require.NoError(t, err)
+		}
+
+		cleanup := orders.NewCleanup(zaptest.NewLogger(t), ordersdb, orders.CleanupConfig{
+			Interval:   time.Hour,
+			ArchiveTTL: 24 * time.Hour,
+		})
+
+		// orders archived within the ttl are kept
+		require.NoError(t, cleanup.DeleteExpired(ctx, time.Now()))
+		archived, _, err := ordersdb.ListArchived(ctx, orders.ArchiveCursor{}, 100)
+		require.NoError(t, err)
+		require.Len(t, archived, count)
+
+		// orders archived before the ttl are deleted
+		require.NoError(t, cleanup.DeleteExpired(ctx, time.Now().Add(48*time.Hour)))
+		archived, _, err = ordersdb.ListArchived(ctx, orders.ArchiveCursor{}, 100)
+		require.NoError(t, err)
+		require.Empty(t, archived)
+
+		deleted, err := ordersdb.DeleteArchived(ctx, time.Now().Add(48*time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, int64(0), deleted)
+	})
+}
+
+func TestCleanupExpired(t *testing.T) {
+	storagenodedbtest.Run(t, func(t *testing.T, db storagenode.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		ordersdb := db.Orders()
+
+		storagenode := testplanet.MustPregeneratedSignedIdentity(0)
+		satellite0 := testplanet.MustPregeneratedSignedIdentity(1)
+		uplink := testplanet.MustPregeneratedSignedIdentity(3)
+
+		now := time.Now()
+		expired, err := ptypes.TimestampProto(now.Add(-time.Hour))
+		require.NoError(t, err)
+		valid, err := ptypes.TimestampProto(now.Add(time.Hour))
+		require.NoError(t, err)
+
+		var validSerials []storj.SerialNumber
+		for i := 0; i < 6; i++ {
+			expiration := expired
+			serialNumber := newRandomSerial()
+			if i%2 == 0 {
+				expiration = valid
+				validSerials = append(validSerials, serialNumber)
+			}
+
+			err := ordersdb.Enqueue(ctx, &orders.Info{
+				Limit: &pb.OrderLimit2{
+					SerialNumber:    serialNumber,
+					SatelliteId:     satellite0.ID,
+					UplinkId:        uplink.ID,
+					StorageNodeId:   storagenode.ID,
+					PieceId:         storj.NewPieceID(),
+					Limit:           100,
+					Action:          pb.PieceAction_GET,
+					PieceExpiration: valid,
+					OrderExpiration: expiration,
+				},
+				Order: &pb.Order2{
+					SerialNumber: serialNumber,
+					Amount:       50,
+				},
+				Uplink: uplink.PeerIdentity(),
+			})
+			require.NoError(t, err)
+		}
+
+		cleanup := orders.NewCleanup(zaptest.NewLogger(t), ordersdb, orders.CleanupConfig{
+			Interval:   time.Hour,
+			ArchiveTTL: 24 * time.Hour,
+		})
+		require.NoError(t, cleanup.ArchiveExpired(ctx, now))
+
+		// the orders which didn't expire are still unsent
+		unsent, err := ordersdb.ListUnsent(ctx, 100)
+		require.NoError(t, err)
+		require.Len(t, unsent, len(validSerials))
+		for i, info := range unsent {
+			require.Equal(t, validSerials[i], info.Limit.SerialNumber)
+		}
+
+		// the expired orders are archived
+		archived, _, err := ordersdb.ListArchived(ctx, orders.ArchiveCursor{}, 100)
+		require.NoError(t, err)
+		require.Len(t, archived, 3)
+		for _, info := range archived {
+			require.Equal(t, orders.StatusExpired, info.Status)
+			require.NotContains(t, validSerials, info.Limit.SerialNumber)
+		}
+
+		count, err := ordersdb.CleanExpired(ctx, now)
+		require.NoError(t, err)
+		require.Equal(t, int64(0), count)
+	})
+}
+
+func TestSummarizeUnsent(t *testing.T) {
+	storagenodedbtest.Run(t, func(t *testing.T, db storagenode.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		ordersdb := db.Orders()
+
+		storagenode := testplanet.MustPregeneratedSignedIdentity(0)
+		satellite0 := testplanet.MustPregeneratedSignedIdentity(1)
+		satellite1 := testplanet.MustPregeneratedSignedIdentity(2)
+		uplink := testplanet.MustPregeneratedSignedIdentity(3)
+
+		summaries, err := ordersdb.SummarizeUnsent(ctx)
+		require.NoError(t, err)
+		require.Empty(t, summaries)
+
+		now := ptypes.TimestampNow()
+
+		var lastSerial storj.SerialNumber
+		for i := 0; i < 5; i++ {
+			satelliteID := satellite0.ID
+			if i%2 == 1 {
+				satelliteID = satellite1.ID
+			}
+
+			serialNumber := newRandomSerial()
+			err := ordersdb.Enqueue(ctx, &orders.Info{
+				Limit: &pb.OrderLimit2{
+					SerialNumber:    serialNumber,
+					SatelliteId:     satelliteID,
+					UplinkId:        uplink.ID,
+					StorageNodeId:   storagenode.ID,
+					PieceId:         storj.NewPieceID(),
+					Limit:           1000,
+					Action:          pb.PieceAction_GET,
+					PieceExpiration: now,
+					OrderExpiration: now,
+				},
+				Order: &pb.Order2{
+					SerialNumber: serialNumber,
+					Amount:       int64(100 * (i + 1)),
+				},
+				Uplink: uplink.PeerIdentity(),
+			})
+			require.NoError(t, err)
+			lastSerial = serialNumber
+		}
+
+		summaries, err = ordersdb.SummarizeUnsent(ctx)
+		require.NoError(t, err)
+		require.Equal(t, map[storj.NodeID]orders.UnsentSummary{
+			satellite0.ID: {Count: 3, Amount: 100 + 300 + 500},
+			satellite1.ID: {Count: 2, Amount: 200 + 400},
+		}, summaries)
+
+		// archived orders aren't unsent anymore
+		require.NoError(t, ordersdb.Archive(ctx, satellite0.ID, lastSerial, orders.StatusAccepted))
+
+		summaries, err = ordersdb.SummarizeUnsent(ctx)
+		require.NoError(t, err)
+		require.Equal(t, orders.UnsentSummary{Count: 2, Amount: 100 + 300}, summaries[satellite0.ID])
+	})
+}
+
+func TestSummarizeOrders(t *testing.T) {
+	storagenodedbtest.Run(t, func(t *testing.T, db storagenode.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		ordersdb := db.Orders()
+
+		storagenode := testplanet.MustPregeneratedSignedIdentity(0)
+		satellite0 := testplanet.MustPregeneratedSignedIdentity(1)
+		satellite1 := testplanet.MustPregeneratedSignedIdentity(2)
+		uplink := testplanet.MustPregeneratedSignedIdentity(3)
+
+		summaries, err := ordersdb.SummarizeOrders(ctx)
+		require.NoError(t, err)
+		require.Empty(t, summaries)
+
+		now := ptypes.TimestampNow()
+
+		enqueue := func(satelliteID storj.NodeID, action pb.PieceAction, amount int64) storj.SerialNumber {
+			serialNumber := newRandomSerial()
+			err := ordersdb.Enqueue(ctx, &orders.Info{
+				Limit: &pb.OrderLimit2{
+					SerialNumber:    serialNumber,
+					SatelliteId:     satelliteID,
+					UplinkId:        uplink.ID,
+					StorageNodeId:   storagenode.ID,
+					PieceId:         storj.NewPieceID(),
+					Limit:           1000,
+					Action:          action,
+					PieceExpiration: now,
+					OrderExpiration: now,
+				},
+				Order: &pb.Order2{
+					SerialNumber: serialNumber,
+					Amount:       amount,
+				},
+				Uplink: uplink.PeerIdentity(),
+			})
+			require.NoError(t, err)
+			return serialNumber
+		}
+
+		enqueue(satellite0.ID, pb.PieceAction_GET, 100)
+		enqueue(satellite0.ID, pb.PieceAction_GET, 200)
+		enqueue(satellite0.ID, pb.PieceAction_PUT, 300)
+		accepted := enqueue(satellite0.ID, pb.PieceAction_GET_AUDIT, 400)
+		rejected := enqueue(satellite1.ID, pb.PieceAction_GET, 500)
+
+		require.NoError(t, ordersdb.Archive(ctx, satellite0.ID, accepted, orders.StatusAccepted))
+		require.NoError(t, ordersdb.Archive(ctx, satellite1.ID, rejected, orders.StatusRejected))
+
+		summaries, err = ordersdb.SummarizeOrders(ctx)
+		require.NoError(t, err)
+
+		expected := []orders.Summary{
+			{SatelliteID: satellite0.ID, Status: orders.StatusUnsent, Action: pb.PieceAction_PUT, Count: 1, Amount: 300},
+			{SatelliteID: satellite0.ID, Status: orders.StatusUnsent, Action: pb.PieceAction_GET, Count: 2, Amount: 300},
+			{SatelliteID: satellite0.ID, Status: orders.StatusAccepted, Action: pb.PieceAction_GET_AUDIT, Count: 1, Amount: 400},
+			{SatelliteID: satellite1.ID, Status: orders.StatusRejected, Action: pb.PieceAction_GET, Count: 1, Amount: 500},
+		}
+		if satellite1.ID.Less(satellite0.ID) {
+			expected = append(expected[3:], expected[:3]...)
+		}
+		require.Equal(t, expected, summaries)
+	})
+}
+
+func TestIterateOrders(t *testing.T) {
+	storagenodedbtest.Run(t, func(t *testing.T, db storagenode.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		ordersdb := db.Orders()
+
+		storagenode := testplanet.MustPregeneratedSignedIdentity(0)
+		satellite0 := testplanet.MustPregeneratedSignedIdentity(1)
+		satellite1 := testplanet.MustPregeneratedSignedIdentity(2)
+		uplink := testplanet.MustPregeneratedSignedIdentity(3)
+
+		now := time.Now()
+
+		var serials []storj.SerialNumber
+		for i := 0; i < 6; i++ {
+			satelliteID := satellite0.ID
+			if i%2 == 1 {
+				satelliteID = satellite1.ID
+			}
+
+			expiration, err := ptypes.TimestampProto(now.Add(time.Duration(i) * time.Hour))
+			require.NoError(t, err)
+
+			serialNumber := newRandomSerial()
+			serials = append(serials, serialNumber)
+			err = ordersdb.Enqueue(ctx, &orders.Info{
+				Limit: &pb.OrderLimit2{
+					SerialNumber:    serialNumber,
+					SatelliteId:     satelliteID,
+					UplinkId:        uplink.ID,
+					StorageNodeId:   storagenode.ID,
+					PieceId:         storj.NewPieceID(),
+					Limit:           100,
+					Action:          pb.PieceAction_GET,
+					PieceExpiration: expiration,
+					OrderExpiration: expiration,
+				},
+				Order: &pb.Order2{
+					SerialNumber: serialNumber,
+					Amount:       50,
+				},
+				Uplink: uplink.PeerIdentity(),
+			})
+			require.NoError(t, err)
+		}
+
+		iterateUnsent := func(filter orders.UnsentFilter) (listed []storj.SerialNumber) {
+			err := ordersdb.IterateUnsent(ctx, filter, func(info *orders.Info) error {
+				listed = append(listed, info.Limit.SerialNumber)
+				return nil
+			})
+			require.NoError(t, err)
+			return listed
+		}
+
+		// unsent orders are iterated in insertion order
+		require.Equal(t, serials, iterateUnsent(orders.UnsentFilter{}))
+		require.Equal(t, []storj.SerialNumber{serials[0], serials[2], serials[4]},
+			iterateUnsent(orders.UnsentFilter{SatelliteID: satellite0.ID}))
+		require.Equal(t, []storj.SerialNumber{serials[1], serials[3], serials[5]},
+			iterateUnsent(orders.UnsentFilter{SatelliteID: satellite1.ID}))
+		require.Equal(t, []storj.SerialNumber{serials[2], serials[4]},
+			iterateUnsent(orders.UnsentFilter{
+				SatelliteID:   satellite0.ID,
+				ExpiresAfter:  now.Add(90 * time.Minute),
+				ExpiresBefore: now.Add(5 * time.Hour),
+			}))
+
+		// ListUnsentBySatellite keeps the limit per satellite
+		bySatellite, err := ordersdb.ListUnsentBySatellite(ctx, 2)
+		require.NoError(t, err)
+		require.Len(t, bySatellite, 2)
+		require.Len(t, bySatellite[satellite0.ID], 2)
+		require.Equal(t, serials[1], bySatellite[satellite1.ID][0].Limit.SerialNumber)
+
+		// an error stops the iteration
+		var count int
+		errStop := errs.New("stop")
+		err = ordersdb.IterateUnsent(ctx, orders.UnsentFilter{}, func(info *orders.Info) error {
+			count++
+			return errStop
+		})
+		require.Equal(t, errStop, err)
+		require.Equal(t, 1, count)
+
+		for _, serialNumber := range []storj.SerialNumber{serials[4], serials[0], serials[2]} {
+			require.NoError(t, ordersdb.Archive(ctx, satellite0.ID, serialNumber, orders.StatusAccepted))
+		}
+
+		iterateArchived := func(cursor orders.ArchiveCursor) (listed []storj.SerialNumber) {
+			err := ordersdb.IterateArchived(ctx, cursor, func(info *orders.ArchivedInfo) error {
+				require.Equal(t, orders.StatusAccepted, info.Status)
+				listed = append(listed, info.Limit.SerialNumber)
+				return nil
+			})
+			require.NoError(t, err)
+			return listed
+		}
+
+		// archived orders are iterated in archival order
+		require.Equal(t, []storj.SerialNumber{serials[4], serials[0], serials[2]},
+			iterateArchived(orders.ArchiveCursor{SatelliteID: satellite0.ID}))
+		require.Empty(t, iterateArchived(orders.ArchiveCursor{SatelliteID: satellite1.ID}))
+		require.Empty(t, iterateArchived(orders.ArchiveCursor{ArchivedAfter: time.Now().Add(time.Hour)}))
+		require.Equal(t, []storj.SerialNumber{serials[1], serials[3], serials[5]},
+			iterateUnsent(orders.UnsentFilter{}))
+	})
+}
+
+func TestSettlementBackoff(t *testing.T) {
+	storagenodedbtest.Run(t, func(t *testing.T, db storagenode.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		ordersdb := db.Orders()
+
+		satellite0 := testplanet.MustPregeneratedSignedIdentity(1)
+		satellite1 := testplanet.MustPregeneratedSignedIdentity(2)
+
+		backoffs, err := ordersdb.ListBackoffs(ctx)
+		require.NoError(t, err)
+		require.Empty(t, backoffs)
+
+		nextRetry := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+		require.NoError(t, ordersdb.SetBackoff(ctx, &orders.Backoff{SatelliteID: satellite0.ID, Failures: 1, NextRetry: nextRetry}))
+		require.NoError(t, ordersdb.SetBackoff(ctx, &orders.Backoff{SatelliteID: satellite1.ID, Failures: 1, NextRetry: nextRetry}))
+
+		// setting the backoff again replaces it
+		require.NoError(t, ordersdb.SetBackoff(ctx, &orders.Backoff{SatelliteID: satellite0.ID, Failures: 2, NextRetry: nextRetry.Add(time.Hour)}))
+
+		backoffs, err = ordersdb.ListBackoffs(ctx)
+		require.NoError(t, err)
+		require.Len(t, backoffs, 2)
+		for _, backoff := range backoffs {
+			switch backoff.SatelliteID {
+			case satellite0.ID:
+				require.Equal(t, 2, backoff.Failures)
+				require.True(t, nextRetry.Add(time.Hour).Equal(backoff.NextRetry))
+			case satellite1.ID:
+				require.Equal(t, 1, backoff.Failures)
+				require.True(t, nextRetry.Equal(backoff.NextRetry))
+			default:
+				t.Fatalf("unexpected satellite %v", backoff.SatelliteID)
+			}
+		}
+
+		require.NoError(t, ordersdb.DeleteBackoff(ctx, satellite0.ID))
+		backoffs, err = ordersdb.ListBackoffs(ctx)
+		require.NoError(t, err)
+		require.Len(t, backoffs, 1)
+		require.Equal(t, satellite1.ID, backoffs[0].SatelliteID)
+	})
+}
+
 // TODO: move somewhere better
 func newRandomSerial() storj.SerialNumber {
 	var serial storj.SerialNumber
@@ -5,20 +5,26 @@ package orders
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"time"
 
+	"github.com/zeebo/errs"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 
 	"storj.io/storj/internal/sync2"
 	"storj.io/storj/pkg/identity"
-	"storj.io/storj/pkg/kademlia"
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/storj"
 	"storj.io/storj/pkg/transport"
+	"storj.io/storj/storagenode/trust"
 )
 
+// ErrSerialAlreadyExists is returned when an order or an order limit reuses
+// the serial number of a satellite.
+var ErrSerialAlreadyExists = errs.Class("serial number already exists")
+
 // Info contains full information about an order.
 type Info struct {
 	Limit  *pb.OrderLimit2
@@ -44,28 +50,160 @@ const (
 	StatusUnsent Status = iota
 	StatusAccepted
 	StatusRejected
+	// StatusExpired is set for orders which expired before being sent.
+	StatusExpired
 )
 
+// String returns the name of the status.
+func (status Status) String() string {
+	switch status {
+	case StatusUnsent:
+		return "unsent"
+	case StatusAccepted:
+		return "accepted"
+	case StatusRejected:
+		return "rejected"
+	case StatusExpired:
+		return "expired"
+	default:
+		return fmt.Sprintf("status(%d)", byte(status))
+	}
+}
+
 // DB implements storing orders for sending to the satellite.
 type DB interface {
-	// Enqueue inserts order to the list of orders needing to be sent to the satellite.
+	// Enqueue inserts order to the list of orders needing to be sent to the satellite,
+	// it fails with ErrSerialAlreadyExists when the serial number is already in the list.
 	Enqueue(ctx context.Context, info *Info) error
 	// ListUnsent returns orders that haven't been sent yet.
 	ListUnsent(ctx context.Context, limit int) ([]*Info, error)
-	// ListUnsentBySatellite returns orders that haven't been sent yet grouped by satellite.
-	ListUnsentBySatellite(ctx context.Context) (map[storj.NodeID][]*Info, error)
+	// ListUnsentBySatellite returns at most limit orders per satellite that haven't been sent yet grouped by satellite.
+	ListUnsentBySatellite(ctx context.Context, limit int) (map[storj.NodeID][]*Info, error)
+	// ListUnsentSatellites returns the satellites which have orders that haven't been sent yet.
+	ListUnsentSatellites(ctx context.Context) ([]storj.NodeID, error)
+	// ListUnsentBatch returns at most limit orders of the cursor satellite that haven't been sent yet,
+	// after the cursor position, and the cursor of the next batch.
+	ListUnsentBatch(ctx context.Context, cursor UnsentCursor, limit int) ([]*Info, UnsentCursor, error)
+	// IterateUnsent calls fn for the orders that haven't been sent yet matching filter, in insertion order.
+	// Does not return uplink identity. Note, fn must not use the database.
+	IterateUnsent(ctx context.Context, filter UnsentFilter, fn func(*Info) error) error
+	// SummarizeUnsent returns the number and the total amount of the orders that haven't been sent yet by satellite.
+	SummarizeUnsent(ctx context.Context) (map[storj.NodeID]UnsentSummary, error)
+	// SummarizeOrders returns the number and the total amount of the unsent and archived orders
+	// by satellite, status and action, the unsent orders have StatusUnsent.
+	SummarizeOrders(ctx context.Context) ([]Summary, error)
 
 	// Archive marks order as being handled.
 	Archive(ctx context.Context, satellite storj.NodeID, serial storj.SerialNumber, status Status) error
+	// ArchiveBatch marks the orders of a satellite as being handled, all in one transaction.
+	// The orders that aren't in the unsent list are skipped and returned.
+	ArchiveBatch(ctx context.Context, satellite storj.NodeID, requests []ArchiveRequest) (missing []storj.SerialNumber, err error)
+
+	// ListArchived returns at most limit orders that have been sent, after the
+	// cursor position and matching its filters, and the cursor of the next page.
+	ListArchived(ctx context.Context, cursor ArchiveCursor, limit int) ([]*ArchivedInfo, ArchiveCursor, error)
+	// IterateArchived calls fn for the orders that have been sent, after the cursor position and
+	// matching its filters, in archival order. Note, fn must not use the database.
+	IterateArchived(ctx context.Context, cursor ArchiveCursor, fn func(*ArchivedInfo) error) error
+
+	// DeleteArchived deletes the orders archived before the given time and returns how many were deleted.
+	DeleteArchived(ctx context.Context, before time.Time) (int64, error)
+
+	// CleanExpired archives the unsent orders whose order limit expired before
+	// the given time with StatusExpired and returns how many were archived.
+	CleanExpired(ctx context.Context, before time.Time) (int64, error)
+
+	// ListBackoffs returns the settlement backoffs of the satellites.
+	ListBackoffs(ctx context.Context) ([]*Backoff, error)
+	// SetBackoff stores the settlement backoff of a satellite.
+	SetBackoff(ctx context.Context, backoff *Backoff) error
+	// DeleteBackoff removes the settlement backoff of a satellite.
+	DeleteBackoff(ctx context.Context, satelliteID storj.NodeID) error
+}
+
+// ArchiveRequest defines an order that should be archived and its status.
+type ArchiveRequest struct {
+	Serial storj.SerialNumber
+	Status Status
+}
+
+// UnsentSummary summarizes the orders of a satellite that haven't been sent yet.
+type UnsentSummary struct {
+	Count  int64
+	Amount int64
+}
 
-	// ListArchived returns orders that have been sent.
-	ListArchived(ctx context.Context, limit int) ([]*ArchivedInfo, error)
+// Summary summarizes the orders of a satellite with a status and an action.
+type Summary struct {
+	SatelliteID storj.NodeID
+	Status      Status
+	Action      pb.PieceAction
+	Count       int64
+	Amount      int64
+}
+
+// Backoff is the settlement backoff of a satellite which failed settling.
+type Backoff struct {
+	SatelliteID storj.NodeID
+	// Failures is the number of consecutive failed settlements.
+	Failures int
+	// NextRetry is when the orders are settled again with the satellite.
+	NextRetry time.Time
+}
+
+// UnsentCursor is a position in the unsent orders of a satellite.
+type UnsentCursor struct {
+	// SatelliteID is the satellite the orders are listed for.
+	SatelliteID storj.NodeID
+	// Position is the position of the last order listed, zero for the first batch.
+	Position int64
+}
+
+// UnsentFilter selects the unsent orders iterated.
+type UnsentFilter struct {
+	// SatelliteID only selects the orders of a satellite, when not zero.
+	SatelliteID storj.NodeID
+	// ExpiresAfter only selects the orders whose limit expires at or after it, when not zero.
+	ExpiresAfter time.Time
+	// ExpiresBefore only selects the orders whose limit expires before it, when not zero.
+	ExpiresBefore time.Time
+}
+
+// ArchiveCursor is a position in the archived orders, with filters on the
+// orders listed.
+type ArchiveCursor struct {
+	// Position is the position of the last order listed, zero for the first page.
+	Position int64
+
+	// SatelliteID only lists the orders of a satellite, when not zero.
+	SatelliteID storj.NodeID
+	// ArchivedAfter only lists the orders archived at or after it, when not zero.
+	ArchivedAfter time.Time
+	// ArchivedBefore only lists the orders archived before it, when not zero.
+	ArchivedBefore time.Time
 }
 
 // SenderConfig defines configuration for sending orders.
 type SenderConfig struct {
-	Interval time.Duration `help:"duration between sending" default:"1h0m0s"`
-	Timeout  time.Duration `help:"timeout for sending" default:"1h0m0s"`
+	Interval  time.Duration `help:"duration between sending" default:"1h0m0s"`
+	Timeout   time.Duration `help:"timeout for sending" default:"1h0m0s"`
+	BatchSize int           `help:"maximum number of settled orders archived in one transaction" default:"1000"`
+
+	RetryBackoff    time.Duration `help:"duration to wait before settling again with a satellite after a failed settlement, doubled on each consecutive failure" default:"1h0m0s"`
+	MaxRetryBackoff time.Duration `help:"maximum duration to wait before settling again with a satellite after failed settlements" default:"24h0m0s"`
+}
+
+// retryBackoff returns the duration to wait after the given number of
+// consecutive failed settlements.
+func (config SenderConfig) retryBackoff(failures int) time.Duration {
+	backoff := config.RetryBackoff
+	for i := 1; i < failures && backoff < config.MaxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > config.MaxRetryBackoff {
+		backoff = config.MaxRetryBackoff
+	}
+	return backoff
 }
 
 // Sender sends every interval unsent orders to the satellite.
@@ -74,18 +212,19 @@ type Sender struct {
 	config SenderConfig
 
 	transport transport.Client
-	kademlia  *kademlia.Kademlia
+	trust     *trust.Pool
 	orders    DB
 
 	Loop sync2.Cycle
 }
 
-// NewSender creates an order sender.
-func NewSender(log *zap.Logger, transport transport.Client, kademlia *kademlia.Kademlia, orders DB, config SenderConfig) *Sender {
+// NewSender creates an order sender, which sends orders only to the satellites
+// trusted by trust.
+func NewSender(log *zap.Logger, transport transport.Client, trust *trust.Pool, orders DB, config SenderConfig) *Sender {
 	return &Sender{
 		log:       log,
 		transport: transport,
-		kademlia:  kademlia,
+		trust:     trust,
 		orders:    orders,
 		config:    config,
 
@@ -98,22 +237,43 @@ func (sender *Sender) Run(ctx context.Context) error {
 	return sender.Loop.Run(ctx, func(ctx context.Context) error {
 		sender.log.Debug("sending")
 
-		ordersBySatellite, err := sender.orders.ListUnsentBySatellite(ctx)
+		satellites, err := sender.orders.ListUnsentSatellites(ctx)
 		if err != nil {
-			sender.log.Error("listing orders", zap.Error(err))
+			sender.log.Error("listing satellites with orders", zap.Error(err))
 			return nil
 		}
 
-		if len(ordersBySatellite) > 0 {
+		backoffs, err := sender.backoffs(ctx)
+		if err != nil {
+			sender.log.Error("listing settlement backoffs", zap.Error(err))
+		}
+
+		if len(satellites) > 0 {
 			var group errgroup.Group
 
-			for satelliteID, orders := range ordersBySatellite {
-				satelliteID, orders := satelliteID, orders
+			now := time.Now()
+			for _, satelliteID := range satellites {
+				satelliteID := satelliteID
+				// NB: the orders of untrusted satellites are kept, in case they
+				// are trusted again
+				if err := sender.trust.VerifySatelliteID(ctx, satelliteID); err != nil {
+					sender.log.Debug("skipping untrusted satellite", zap.Stringer("satellite", satelliteID))
+					continue
+				}
+
+				backoff := backoffs[satelliteID]
+				if backoff != nil && now.Before(backoff.NextRetry) {
+					sender.log.Debug("skipping satellite until next retry",
+						zap.Stringer("satellite", satelliteID), zap.Time("next retry", backoff.NextRetry))
+					continue
+				}
+
 				group.Go(func() error {
-					ctx, cancel := context.WithTimeout(ctx, sender.config.Timeout)
+					settleCtx, cancel := context.WithTimeout(ctx, sender.config.Timeout)
 					defer cancel()
 
-					sender.Settle(ctx, satelliteID, orders)
+					err := sender.settleAll(settleCtx, satelliteID)
+					sender.updateBackoff(ctx, satelliteID, backoff, err)
 					return nil
 				})
 			}
@@ -126,23 +286,92 @@ func (sender *Sender) Run(ctx context.Context) error {
 	})
 }
 
-// Settle uploads orders to the satellite.
-func (sender *Sender) Settle(ctx context.Context, satelliteID storj.NodeID, orders []*Info) {
+// backoffs returns the settlement backoffs by satellite.
+func (sender *Sender) backoffs(ctx context.Context) (map[storj.NodeID]*Backoff, error) {
+	list, err := sender.orders.ListBackoffs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	backoffs := make(map[storj.NodeID]*Backoff, len(list))
+	for _, backoff := range list {
+		backoffs[backoff.SatelliteID] = backoff
+	}
+	return backoffs, nil
+}
+
+// updateBackoff resets the settlement backoff of a satellite after a
+// successful settlement, or increases it after a failed settlement.
+func (sender *Sender) updateBackoff(ctx context.Context, satelliteID storj.NodeID, previous *Backoff, settleErr error) {
+	if settleErr == nil {
+		if previous != nil {
+			if err := sender.orders.DeleteBackoff(ctx, satelliteID); err != nil {
+				sender.log.Error("resetting settlement backoff", zap.Stringer("satellite", satelliteID), zap.Error(err))
+			}
+		}
+		return
+	}
+
+	backoff := &Backoff{SatelliteID: satelliteID, Failures: 1}
+	if previous != nil {
+		backoff.Failures = previous.Failures + 1
+	}
+	backoff.NextRetry = time.Now().Add(sender.config.retryBackoff(backoff.Failures))
+
+	sender.log.Warn("settlement failed, retrying later", zap.Stringer("satellite", satelliteID),
+		zap.Int("failures", backoff.Failures), zap.Time("next retry", backoff.NextRetry))
+	if err := sender.orders.SetBackoff(ctx, backoff); err != nil {
+		sender.log.Error("storing settlement backoff", zap.Stringer("satellite", satelliteID), zap.Error(err))
+	}
+}
+
+// settleAll uploads the unsent orders of a satellite, streaming them from the
+// database so they don't all have to be loaded at once.
+func (sender *Sender) settleAll(ctx context.Context, satelliteID storj.NodeID) error {
+	return sender.settle(ctx, satelliteID, func(fn func(*Info) error) error {
+		return sender.orders.IterateUnsent(ctx, UnsentFilter{SatelliteID: satelliteID}, fn)
+	})
+}
+
+// Settle uploads orders to the satellite, returning an error when the
+// settlement didn't complete.
+func (sender *Sender) Settle(ctx context.Context, satelliteID storj.NodeID, orders []*Info) error {
+	return sender.settle(ctx, satelliteID, func(fn func(*Info) error) error {
+		for _, order := range orders {
+			if err := fn(order); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// settle uploads the orders iterated by forEach to the satellite and archives
+// them, in batches, once the satellite responded.
+//
+// NB: the orders are archived only after they were all sent, since archiving
+// while forEach reads the orders could wait on the database.
+func (sender *Sender) settle(ctx context.Context, satelliteID storj.NodeID, forEach func(fn func(*Info) error) error) error {
 	log := sender.log.Named(satelliteID.String())
 
-	log.Info("sending", zap.Int("count", len(orders)))
+	log.Info("sending")
 	defer log.Info("finished")
 
-	satellite, err := sender.kademlia.FindNode(ctx, satelliteID)
+	if err := sender.trust.VerifySatelliteID(ctx, satelliteID); err != nil {
+		log.Error("refusing to send orders", zap.Error(err))
+		return err
+	}
+
+	satellite, err := sender.trust.FindSatellite(ctx, satelliteID)
 	if err != nil {
 		log.Error("unable to find satellite on the network", zap.Error(err))
-		return
+		return err
 	}
 
 	conn, err := sender.transport.DialNode(ctx, &satellite)
 	if err != nil {
 		log.Error("unable to connect to the satellite", zap.Error(err))
-		return
+		return err
 	}
 	defer func() {
 		if err := conn.Close(); err != nil {
@@ -153,23 +382,25 @@ func (sender *Sender) Settle(ctx context.Context, satelliteID storj.NodeID, orde
 	client, err := pb.NewOrdersClient(conn).Settlement(ctx)
 	if err != nil {
 		log.Error("failed to start settlement", zap.Error(err))
-		return
+		return err
 	}
 
 	var group errgroup.Group
 	group.Go(func() error {
-		for _, order := range orders {
-			err := client.Send(&pb.SettlementRequest{
+		var count int
+		err := forEach(func(order *Info) error {
+			count++
+			return client.Send(&pb.SettlementRequest{
 				Limit: order.Limit,
 				Order: order.Order,
 			})
-			if err != nil {
-				return err
-			}
-		}
-		return client.CloseSend()
+		})
+		log.Debug("sent", zap.Int("count", count))
+		return errs.Combine(err, client.CloseSend())
 	})
 
+	var recvErr error
+	var requests []ArchiveRequest
 	for {
 		response, err := client.Recv()
 		if err != nil {
@@ -178,28 +409,44 @@ func (sender *Sender) Settle(ctx context.Context, satelliteID storj.NodeID, orde
 			}
 
 			log.Error("failed to receive response", zap.Error(err))
+			recvErr = err
 			break
 		}
 
 		switch response.Status {
 		case pb.SettlementResponse_ACCEPTED:
-			err = sender.orders.Archive(ctx, satelliteID, response.SerialNumber, StatusAccepted)
-			if err != nil {
-				log.Error("failed to archive order as accepted", zap.Stringer("serial", response.SerialNumber), zap.Error(err))
-			}
+			requests = append(requests, ArchiveRequest{Serial: response.SerialNumber, Status: StatusAccepted})
 		case pb.SettlementResponse_REJECTED:
-			err = sender.orders.Archive(ctx, satelliteID, response.SerialNumber, StatusRejected)
-			if err != nil {
-				log.Error("failed to archive order as rejected", zap.Stringer("serial", response.SerialNumber), zap.Error(err))
-			}
+			requests = append(requests, ArchiveRequest{Serial: response.SerialNumber, Status: StatusRejected})
 		default:
-			log.Error("unexpected response", zap.Error(err))
+			log.Error("unexpected response", zap.Stringer("status", response.Status))
 		}
 	}
 
-	if err := group.Wait(); err != nil {
-		log.Error("sending agreements returned an error", zap.Error(err))
+	sendErr := group.Wait()
+	if sendErr != nil {
+		log.Error("sending agreements returned an error", zap.Error(sendErr))
+	}
+
+	var archiveErr error
+	for len(requests) > 0 && archiveErr == nil {
+		batch := requests
+		if sender.config.BatchSize > 0 && len(batch) > sender.config.BatchSize {
+			batch = batch[:sender.config.BatchSize]
+		}
+		requests = requests[len(batch):]
+
+		var missing []storj.SerialNumber
+		missing, archiveErr = sender.orders.ArchiveBatch(ctx, satelliteID, batch)
+		if archiveErr != nil {
+			log.Error("failed to archive orders", zap.Int("count", len(batch)), zap.Error(archiveErr))
+		}
+		for _, serial := range missing {
+			log.Warn("order was not in unsent list", zap.Stringer("serial", serial))
+		}
 	}
+
+	return errs.Combine(recvErr, sendErr, archiveErr)
 }
 
 // Close stops the sending service.
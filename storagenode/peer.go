@@ -5,12 +5,17 @@ package storagenode
 
 import (
 	"context"
+	"net"
+	"path/filepath"
+	"strings"
 
 	"github.com/zeebo/errs"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 
+	"storj.io/storj/internal/version"
+	"storj.io/storj/pkg/accounting/payments"
 	"storj.io/storj/pkg/auth/signing"
 	"storj.io/storj/pkg/identity"
 	"storj.io/storj/pkg/kademlia"
@@ -19,16 +24,30 @@ import (
 	"storj.io/storj/pkg/piecestore/psserver"
 	"storj.io/storj/pkg/piecestore/psserver/agreementsender"
 	"storj.io/storj/pkg/piecestore/psserver/psdb"
+	"storj.io/storj/pkg/revocation"
 	"storj.io/storj/pkg/server"
 	"storj.io/storj/pkg/storj"
 	"storj.io/storj/pkg/transport"
 	"storj.io/storj/storage"
+	"storj.io/storj/storage/boltdb"
 	"storj.io/storj/storagenode/bandwidth"
+	"storj.io/storj/storagenode/collector"
+	"storj.io/storj/storagenode/console"
+	"storj.io/storj/storagenode/console/consoleserver"
+	"storj.io/storj/storagenode/dbstats"
+	"storj.io/storj/storagenode/diskhealth"
+	"storj.io/storj/storagenode/heldamount"
 	"storj.io/storj/storagenode/inspector"
+	"storj.io/storj/storagenode/maintenance"
 	"storj.io/storj/storagenode/monitor"
+	"storj.io/storj/storagenode/notification"
+	"storj.io/storj/storagenode/operator"
 	"storj.io/storj/storagenode/orders"
 	"storj.io/storj/storagenode/pieces"
 	"storj.io/storj/storagenode/piecestore"
+	"storj.io/storj/storagenode/reputation"
+	"storj.io/storj/storagenode/scrubber"
+	"storj.io/storj/storagenode/storageusage"
 	"storj.io/storj/storagenode/trust"
 )
 
@@ -43,9 +62,20 @@ type DB interface {
 
 	Orders() orders.DB
 	PieceInfo() pieces.DB
+	PieceSpaceUsed() pieces.SpaceUsedDB
+	PendingDeletes() pieces.PendingDeletesDB
 	CertDB() trust.CertDB
 	Bandwidth() bandwidth.DB
 	UsedSerials() piecestore.UsedSerials
+	Maintenance() maintenance.DB
+	Stats() dbstats.DB
+	Reputation() reputation.DB
+	HeldAmount() heldamount.DB
+	StorageUsage() storageusage.DB
+
+	// WithTx runs fn in a transaction of the orders, piece information
+	// and bandwidth usage tables
+	WithTx(ctx context.Context, fn func(ctx context.Context, tx piecestore.Tx) error) error
 
 	// TODO: use better interfaces
 	PSDB() *psdb.DB
@@ -60,7 +90,26 @@ type Config struct {
 	Kademlia kademlia.Config
 	Storage  psserver.Config
 
-	Storage2 piecestore.Config
+	Storage2   piecestore.Config
+	Collector  collector.Config
+	Scrubber   scrubber.Config
+	Bandwidth  bandwidth.Config
+	Trust      trust.Config
+	DiskHealth diskhealth.Config
+	Bolt       boltdb.ChoreConfig
+
+	Notification notification.Config
+	Reputation   reputation.Config
+	HeldAmount   heldamount.Config
+	StorageUsage storageusage.Config
+
+	// Payments are the prices used to estimate the earnings
+	Payments payments.Config
+
+	Console  consoleserver.Config
+	Operator operator.Config
+
+	Version version.Config
 }
 
 // Verify verifies whether configuration is consistent and acceptable.
@@ -79,6 +128,8 @@ type Peer struct {
 
 	Server *server.Server
 
+	Version *version.Service
+
 	// services and endpoints
 	// TODO: similar grouping to satellite.Peer
 	Kademlia struct {
@@ -92,13 +143,45 @@ type Peer struct {
 		Sender *agreementsender.AgreementSender
 	}
 
+	Revocations struct {
+		Endpoint *revocation.Endpoint
+	}
+
 	Storage2 struct {
 		Trust     *trust.Pool
 		Store     *pieces.Store
+		Usage     *pieces.BlobsUsageCache
+		Cache     *pieces.CacheService
 		Endpoint  *piecestore.Endpoint
 		Inspector *inspector.Endpoint
 		Monitor   *monitor.Service
 		Sender    *orders.Sender
+		Cleanup   *orders.Cleanup
+		Retain    *pieces.RetainService
+		Deleter   *pieces.Deleter
+		Trash     *pieces.TrashChore
+		Collector *collector.Service
+		Scrubber  *scrubber.Service
+		Bandwidth *bandwidth.Service
+		Health    *diskhealth.Service
+
+		Notification *notification.Service
+		Reputation   *reputation.Service
+		HeldAmount   *heldamount.Service
+		StorageUsage *storageusage.Service
+		Maintenance  *maintenance.Service
+		Stats        *dbstats.Service
+		Bolt         *boltdb.Chore
+	}
+
+	Console struct {
+		Listener net.Listener
+		Service  *console.Service
+		Endpoint *consoleserver.Server
+	}
+
+	Operator struct {
+		Endpoint *operator.Endpoint
 	}
 }
 
@@ -125,6 +208,15 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, config Config) (*P
 		if err != nil {
 			return nil, errs.Combine(err, peer.Close())
 		}
+
+		if options.RevDB != nil {
+			peer.Revocations.Endpoint = revocation.NewEndpoint(peer.Log.Named("revocations"), options.RevDB)
+			pb.RegisterRevocationsServer(peer.Server.GRPC(), peer.Revocations.Endpoint)
+		}
+	}
+
+	{ // setup version control
+		peer.Version = version.NewService(peer.Log.Named("version"), config.Version, version.Build, "storagenode", peer.Identity.ID)
 	}
 
 	{ // setup kademlia
@@ -176,23 +268,68 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, config Config) (*P
 	}
 
 	{ // setup storage 2
-		trustAllSatellites := !config.Storage.SatelliteIDRestriction
-		peer.Storage2.Trust, err = trust.NewPool(peer.Kademlia.Service, trustAllSatellites, config.Storage.WhitelistedSatelliteIDs)
+		trustAllSatellites, trustConfig := trustConfig(config)
+		peer.Storage2.Trust, err = trust.NewPool(peer.Log.Named("trust"), peer.Kademlia.Service, trustAllSatellites, trustConfig)
 		if err != nil {
 			return nil, errs.Combine(err, peer.Close())
 		}
 
-		peer.Storage2.Store = pieces.NewStore(peer.Log.Named("pieces"), peer.DB.Pieces())
+		peer.Storage2.Usage = pieces.NewBlobsUsageCache(peer.DB.Pieces())
+		peer.Storage2.Store = pieces.NewStore(peer.Log.Named("pieces"), peer.Storage2.Usage)
+
+		peer.Storage2.Cache = pieces.NewCacheService(
+			peer.Log.Named("piecestore:cache"),
+			peer.Storage2.Usage,
+			peer.DB.PieceSpaceUsed(),
+			config.Storage2.Cache,
+		)
+
+		peer.Storage2.Retain, err = pieces.NewRetainService(
+			peer.Log.Named("piecestore:retain"),
+			peer.Storage2.Store,
+			peer.DB.PieceInfo(),
+			config.Storage2.Retain,
+		)
+		if err != nil {
+			return nil, errs.Combine(err, peer.Close())
+		}
+
+		peer.Storage2.Deleter, err = pieces.NewDeleter(
+			peer.Log.Named("piecestore:deleter"),
+			peer.Storage2.Store,
+			peer.DB.PieceInfo(),
+			peer.DB.PendingDeletes(),
+			config.Storage2.Deleter,
+		)
+		if err != nil {
+			return nil, errs.Combine(err, peer.Close())
+		}
+
+		peer.Storage2.Trash = pieces.NewTrashChore(
+			peer.Log.Named("piecestore:trash"),
+			peer.Storage2.Store,
+			config.Storage2.Trash,
+		)
+
+		peer.Storage2.Health = diskhealth.NewService(
+			log.Named("piecestore:diskhealth"),
+			config.Storage.Paths(),
+			config.DiskHealth,
+		)
 
 		peer.Storage2.Endpoint, err = piecestore.NewEndpoint(
 			peer.Log.Named("piecestore"),
 			signing.SignerFromFullIdentity(peer.Identity),
 			peer.Storage2.Trust,
 			peer.Storage2.Store,
+			peer.Storage2.Retain,
+			peer.Storage2.Deleter,
 			peer.DB.PieceInfo(),
-			peer.DB.Orders(),
-			peer.DB.Bandwidth(),
 			peer.DB.UsedSerials(),
+			peer.DB,
+			peer.Storage2.Usage,
+			peer.DB.Bandwidth(),
+			peer.Storage2.Health,
 			config.Storage2,
 		)
 		if err != nil {
@@ -202,10 +339,14 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, config Config) (*P
 
 		peer.Storage2.Inspector = inspector.NewEndpoint(
 			peer.Log.Named("pieces:inspector"),
+			peer.Storage2.Usage,
 			peer.DB.PieceInfo(),
 			peer.Kademlia.Service,
 			peer.DB.Bandwidth(),
+			peer.DB.Orders(),
+			peer.DB.Reputation(),
 			peer.DB.PSDB(),
+			peer.Storage2.Health,
 			config.Storage,
 		)
 		pb.RegisterPieceStoreInspectorServer(peer.Server.PrivateGRPC(), peer.Storage2.Inspector)
@@ -214,21 +355,151 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, config Config) (*P
 			log.Named("piecestore:monitor"),
 			peer.Kademlia.RoutingTable,
 			peer.Storage2.Store,
-			peer.DB.PieceInfo(),
+			peer.Storage2.Usage,
 			peer.DB.Bandwidth(),
 			config.Storage.AllocatedDiskSpace.Int64(),
 			config.Storage.AllocatedBandwidth.Int64(),
 			//TODO use config.Storage.Monitor.Interval, but for some reason is not set
 			config.Storage.KBucketRefreshInterval,
 		)
+		peer.Storage2.Monitor.SetReadOnly(config.Storage2.ReadOnly)
 
 		peer.Storage2.Sender = orders.NewSender(
 			log.Named("piecestore:orderssender"),
 			peer.Transport,
-			peer.Kademlia.Service,
+			peer.Storage2.Trust,
 			peer.DB.Orders(),
 			config.Storage2.Sender,
 		)
+
+		peer.Storage2.Cleanup = orders.NewCleanup(
+			log.Named("piecestore:orderscleanup"),
+			peer.DB.Orders(),
+			config.Storage2.Orders,
+		)
+
+		peer.Storage2.Collector = collector.NewService(
+			log.Named("piecestore:collector"),
+			peer.Storage2.Store,
+			peer.DB.PieceInfo(),
+			config.Collector,
+		)
+
+		quarantineDir := config.Scrubber.QuarantineDir
+		if quarantineDir == "" {
+			quarantineDir = filepath.Join(config.Storage.Paths()[0], "quarantine")
+		}
+		peer.Storage2.Scrubber = scrubber.NewService(
+			log.Named("piecestore:scrubber"),
+			peer.Storage2.Store,
+			peer.DB.PieceInfo(),
+			quarantineDir,
+			config.Scrubber,
+		)
+
+		peer.Storage2.Bandwidth = bandwidth.NewService(
+			log.Named("piecestore:bandwidth"),
+			peer.DB.Bandwidth(),
+			config.Bandwidth,
+		)
+
+		senders, err := notification.NewSenders(config.Notification, config.Kademlia.Operator.Email)
+		if err != nil {
+			return nil, errs.Combine(err, peer.Close())
+		}
+		peer.Storage2.Notification = notification.NewService(
+			log.Named("piecestore:notification"),
+			peer.Identity.ID,
+			senders,
+			peer.Kademlia.Service,
+			peer.Version,
+			peer.Storage2.Usage,
+			peer.DB.Reputation(),
+			config.Storage.AllocatedDiskSpace.Int64(),
+			config.Notification,
+		)
+
+		peer.Storage2.Reputation = reputation.NewService(
+			log.Named("piecestore:reputation"),
+			peer.Transport,
+			peer.Storage2.Trust,
+			peer.DB.Reputation(),
+			config.Reputation,
+		)
+
+		peer.Storage2.HeldAmount = heldamount.NewService(
+			log.Named("piecestore:heldamount"),
+			peer.Transport,
+			peer.Storage2.Trust,
+			peer.DB.HeldAmount(),
+			config.HeldAmount,
+		)
+
+		peer.Storage2.StorageUsage = storageusage.NewService(
+			log.Named("piecestore:storageusage"),
+			peer.DB.StorageUsage(),
+			peer.Storage2.Usage,
+			config.StorageUsage,
+		)
+
+		peer.Storage2.Maintenance = maintenance.NewService(
+			log.Named("piecestore:dbmaintenance"),
+			peer.DB.Maintenance(),
+			func() bool { return peer.Storage2.Endpoint.LiveRequests() == 0 },
+			config.Storage2.DBMaintenanceInterval,
+		)
+
+		peer.Storage2.Stats = dbstats.NewService(
+			log.Named("piecestore:dbstats"),
+			peer.DB.Stats(),
+			config.Storage2.DBStatsInterval,
+		)
+
+		kdb, ndb := peer.DB.RoutingTable()
+		peer.Storage2.Bolt = boltdb.NewChore(log.Named("piecestore:bolt"), config.Bolt, kdb, ndb)
+	}
+
+	{ // setup node operator
+		// NB: the private address serves the operator, so it's never open to
+		// anyone else
+		peer.Server.SetPrivateFilter(server.RequireToken(config.Operator.Token))
+
+		peer.Operator.Endpoint = operator.NewEndpoint(
+			peer.Log.Named("operator"),
+			peer.Storage2.Endpoint,
+			peer.Storage2.Monitor,
+			peer.DB.HeldAmount(),
+		)
+		pb.RegisterNodeOperatorServer(peer.Server.PrivateGRPC(), peer.Operator.Endpoint)
+	}
+
+	if config.Console.Address != "" { // setup console
+		peer.Console.Listener, err = net.Listen("tcp", config.Console.Address)
+		if err != nil {
+			return nil, errs.Combine(err, peer.Close())
+		}
+
+		peer.Console.Service = console.NewService(
+			peer.Log.Named("console:service"),
+			peer.Identity.ID,
+			peer.Version,
+			peer.Storage2.Usage,
+			peer.DB.Bandwidth(),
+			peer.DB.Orders(),
+			peer.DB.Reputation(),
+			peer.DB.HeldAmount(),
+			peer.DB.StorageUsage(),
+			config.Payments,
+			config.Storage.AllocatedDiskSpace.Int64(),
+			config.Storage.AllocatedBandwidth.Int64(),
+		)
+
+		peer.Console.Endpoint = consoleserver.NewServer(
+			peer.Log.Named("console:endpoint"),
+			config.Console,
+			peer.Console.Service,
+			peer.Console.Listener,
+		)
 	}
 
 	return peer, nil
@@ -236,8 +507,19 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, config Config) (*P
 
 // Run runs storage node until it's either closed or it errors.
 func (peer *Peer) Run(ctx context.Context) error {
+	// NB: the space used is loaded before the services using it start
+	if err := peer.Storage2.Cache.Init(ctx); err != nil {
+		return err
+	}
+	if err := peer.Version.CheckStartup(ctx); err != nil {
+		return err
+	}
+
 	group, ctx := errgroup.WithContext(ctx)
 
+	group.Go(func() error {
+		return ignoreCancel(peer.Version.Run(ctx))
+	})
 	group.Go(func() error {
 		return ignoreCancel(peer.Kademlia.Service.Bootstrap(ctx))
 	})
@@ -250,9 +532,65 @@ func (peer *Peer) Run(ctx context.Context) error {
 	group.Go(func() error {
 		return ignoreCancel(peer.Storage2.Sender.Run(ctx))
 	})
+	group.Go(func() error {
+		return ignoreCancel(peer.Storage2.Trust.Run(ctx))
+	})
+	group.Go(func() error {
+		return ignoreCancel(peer.Storage2.Cleanup.Run(ctx))
+	})
+	group.Go(func() error {
+		return ignoreCancel(peer.Storage2.Cache.Run(ctx))
+	})
+	group.Go(func() error {
+		return ignoreCancel(peer.Storage2.Retain.Run(ctx))
+	})
+	group.Go(func() error {
+		return ignoreCancel(peer.Storage2.Deleter.Run(ctx))
+	})
+	group.Go(func() error {
+		return ignoreCancel(peer.Storage2.Trash.Run(ctx))
+	})
+	group.Go(func() error {
+		return ignoreCancel(peer.Storage2.Collector.Run(ctx))
+	})
+	group.Go(func() error {
+		return ignoreCancel(peer.Storage2.Scrubber.Run(ctx))
+	})
+	group.Go(func() error {
+		return ignoreCancel(peer.Storage2.Bandwidth.Run(ctx))
+	})
+	group.Go(func() error {
+		return ignoreCancel(peer.Storage2.Health.Run(ctx))
+	})
+	group.Go(func() error {
+		return ignoreCancel(peer.Storage2.Notification.Run(ctx))
+	})
+	group.Go(func() error {
+		return ignoreCancel(peer.Storage2.Reputation.Run(ctx))
+	})
+	group.Go(func() error {
+		return ignoreCancel(peer.Storage2.HeldAmount.Run(ctx))
+	})
+	group.Go(func() error {
+		return ignoreCancel(peer.Storage2.StorageUsage.Run(ctx))
+	})
+	group.Go(func() error {
+		return ignoreCancel(peer.Storage2.Maintenance.Run(ctx))
+	})
+	group.Go(func() error {
+		return ignoreCancel(peer.Storage2.Stats.Run(ctx))
+	})
+	group.Go(func() error {
+		return ignoreCancel(peer.Storage2.Bolt.Run(ctx))
+	})
 	group.Go(func() error {
 		return ignoreCancel(peer.Storage2.Monitor.Run(ctx))
 	})
+	if peer.Console.Endpoint != nil {
+		group.Go(func() error {
+			return ignoreCancel(peer.Console.Endpoint.Run(ctx))
+		})
+	}
 	group.Go(func() error {
 		// TODO: move the message into Server instead
 		// Don't change the format of this comment, it is used to figure out the node id.
@@ -283,7 +621,18 @@ func (peer *Peer) Close() error {
 		errlist.Add(peer.Server.Close())
 	}
 
+	if peer.Console.Endpoint != nil {
+		errlist.Add(peer.Console.Endpoint.Close())
+	} else {
+		if peer.Console.Listener != nil {
+			errlist.Add(peer.Console.Listener.Close())
+		}
+	}
+
 	// close services in reverse initialization order
+	if peer.Storage2.Bolt != nil {
+		errlist.Add(peer.Storage2.Bolt.Close())
+	}
 	if peer.Kademlia.Service != nil {
 		errlist.Add(peer.Kademlia.Service.Close())
 	}
@@ -294,6 +643,116 @@ func (peer *Peer) Close() error {
 	return errlist.Err()
 }
 
+// Reload applies the subset of config that can change while the storage node
+// is running: the trusted satellites, the chore intervals and whether the node
+// is read-only. Nothing is changed when config is invalid.
+//
+// Reload waits for the affected chores to finish their current iteration.
+func (peer *Peer) Reload(ctx context.Context, config Config) error {
+	if config.Storage.KBucketRefreshInterval <= 0 {
+		return errs.New("storage.k-bucket-refresh-interval must be positive, got %v", config.Storage.KBucketRefreshInterval)
+	}
+	if config.Storage2.Sender.Interval <= 0 {
+		return errs.New("storage2.sender.interval must be positive, got %v", config.Storage2.Sender.Interval)
+	}
+	if config.Storage2.Orders.Interval <= 0 {
+		return errs.New("storage2.orders.interval must be positive, got %v", config.Storage2.Orders.Interval)
+	}
+	if config.Collector.Interval <= 0 {
+		return errs.New("collector.interval must be positive, got %v", config.Collector.Interval)
+	}
+	if config.Scrubber.Interval <= 0 {
+		return errs.New("scrubber.interval must be positive, got %v", config.Scrubber.Interval)
+	}
+	if config.Bandwidth.Interval <= 0 {
+		return errs.New("bandwidth.interval must be positive, got %v", config.Bandwidth.Interval)
+	}
+	if config.Trust.RefreshInterval <= 0 {
+		return errs.New("trust.refresh-interval must be positive, got %v", config.Trust.RefreshInterval)
+	}
+	if config.DiskHealth.Interval <= 0 {
+		return errs.New("disk-health.interval must be positive, got %v", config.DiskHealth.Interval)
+	}
+	if config.Notification.Interval <= 0 {
+		return errs.New("notification.interval must be positive, got %v", config.Notification.Interval)
+	}
+	if config.Reputation.Interval <= 0 {
+		return errs.New("reputation.interval must be positive, got %v", config.Reputation.Interval)
+	}
+	if config.HeldAmount.Interval <= 0 {
+		return errs.New("held-amount.interval must be positive, got %v", config.HeldAmount.Interval)
+	}
+	if config.StorageUsage.Interval <= 0 {
+		return errs.New("storage-usage.interval must be positive, got %v", config.StorageUsage.Interval)
+	}
+	if config.Storage2.Cache.PersistInterval <= 0 {
+		return errs.New("storage2.cache.persist-interval must be positive, got %v", config.Storage2.Cache.PersistInterval)
+	}
+	if config.Storage2.Cache.ReconcileInterval <= 0 {
+		return errs.New("storage2.cache.reconcile-interval must be positive, got %v", config.Storage2.Cache.ReconcileInterval)
+	}
+	if config.Storage2.Trash.Interval <= 0 {
+		return errs.New("storage2.trash.interval must be positive, got %v", config.Storage2.Trash.Interval)
+	}
+	if config.Storage2.DBMaintenanceInterval <= 0 {
+		return errs.New("storage2.db-maintenance-interval must be positive, got %v", config.Storage2.DBMaintenanceInterval)
+	}
+	if config.Storage2.DBStatsInterval <= 0 {
+		return errs.New("storage2.db-stats-interval must be positive, got %v", config.Storage2.DBStatsInterval)
+	}
+	if config.Bolt.Interval <= 0 {
+		return errs.New("bolt.interval must be positive, got %v", config.Bolt.Interval)
+	}
+
+	trustAllSatellites, trustConfig := trustConfig(config)
+	err := peer.Storage2.Trust.SetTrusted(trustAllSatellites, trustConfig)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	peer.Storage2.Monitor.Loop.ChangeInterval(config.Storage.KBucketRefreshInterval)
+	peer.Storage2.Sender.Loop.ChangeInterval(config.Storage2.Sender.Interval)
+	peer.Storage2.Cleanup.Loop.ChangeInterval(config.Storage2.Orders.Interval)
+	peer.Storage2.Cache.Persist.ChangeInterval(config.Storage2.Cache.PersistInterval)
+	peer.Storage2.Cache.Reconcile.ChangeInterval(config.Storage2.Cache.ReconcileInterval)
+	peer.Storage2.Trash.Loop.ChangeInterval(config.Storage2.Trash.Interval)
+	peer.Storage2.Collector.Loop.ChangeInterval(config.Collector.Interval)
+	peer.Storage2.Scrubber.Loop.ChangeInterval(config.Scrubber.Interval)
+	peer.Storage2.Bandwidth.Loop.ChangeInterval(config.Bandwidth.Interval)
+	peer.Storage2.Health.Loop.ChangeInterval(config.DiskHealth.Interval)
+	peer.Storage2.Notification.Loop.ChangeInterval(config.Notification.Interval)
+	peer.Storage2.Reputation.Loop.ChangeInterval(config.Reputation.Interval)
+	peer.Storage2.HeldAmount.Loop.ChangeInterval(config.HeldAmount.Interval)
+	peer.Storage2.StorageUsage.Loop.ChangeInterval(config.StorageUsage.Interval)
+	peer.Storage2.Trust.Loop.ChangeInterval(config.Trust.RefreshInterval)
+	// fetch the trust lists which were added, without waiting for the fetch
+	peer.Storage2.Trust.Loop.Trigger()
+	peer.Storage2.Maintenance.Loop.ChangeInterval(config.Storage2.DBMaintenanceInterval)
+	peer.Storage2.Stats.Loop.ChangeInterval(config.Storage2.DBStatsInterval)
+	peer.Storage2.Bolt.Loop.ChangeInterval(config.Bolt.Interval)
+
+	if config.Storage2.ReadOnly != peer.Storage2.Endpoint.ReadOnly() {
+		peer.Storage2.Endpoint.SetReadOnly(config.Storage2.ReadOnly)
+		peer.Storage2.Monitor.SetReadOnly(config.Storage2.ReadOnly)
+		// advertise the free disk space of the new mode right away
+		peer.Storage2.Monitor.Loop.Trigger()
+		peer.Log.Info("read-only mode changed", zap.Bool("read-only", config.Storage2.ReadOnly))
+	}
+
+	peer.Log.Info("configuration reloaded")
+	return nil
+}
+
+// trustConfig returns whether all the satellites are trusted and the trusted
+// satellites, which include the whitelisted satellite IDs.
+func trustConfig(config Config) (trustAll bool, _ trust.Config) {
+	trusted := config.Trust
+	if config.Storage.WhitelistedSatelliteIDs != "" {
+		trusted.Satellites = strings.Join([]string{config.Storage.WhitelistedSatelliteIDs, trusted.Satellites}, ",")
+	}
+	return !config.Storage.SatelliteIDRestriction, trusted
+}
+
 // ID returns the peer ID.
 func (peer *Peer) ID() storj.NodeID { return peer.Identity.ID }
 
@@ -0,0 +1,42 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storagenode_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/storagenode"
+)
+
+func TestReload(t *testing.T) {
+	var config storagenode.Config
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 1, UplinkCount: 0,
+		Reconfigure: testplanet.Reconfigure{
+			StorageNode: func(index int, c *storagenode.Config) {
+				config = *c
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		node := planet.StorageNodes[0]
+
+		// no notification senders are configured
+		config.Notification.Interval = 2 * time.Hour
+
+		reloaded := make(chan error, 1)
+		go func() { reloaded <- node.Reload(ctx, config) }()
+
+		select {
+		case err := <-reloaded:
+			require.NoError(t, err)
+		case <-time.After(time.Minute):
+			t.Fatal("reload didn't return")
+		}
+	})
+}
@@ -0,0 +1,414 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pieces
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"storj.io/storj/internal/sync2"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storage"
+)
+
+// SpaceUsed returns the space used by the pieces.
+type SpaceUsed interface {
+	// SpaceUsed returns the space used by all pieces
+	SpaceUsed(ctx context.Context) (int64, error)
+	// SpaceUsedBySatellite returns the space used by the pieces of each satellite
+	SpaceUsedBySatellite(ctx context.Context) (map[storj.NodeID]int64, error)
+}
+
+// SpaceUsedDB stores the space used by the pieces of each satellite between restarts.
+type SpaceUsedDB interface {
+	// GetSpaceUsedTotals returns the saved space used by the pieces of each
+	// satellite, ok is false when the totals were never saved.
+	GetSpaceUsedTotals(ctx context.Context) (totals map[storj.NodeID]int64, ok bool, err error)
+	// UpdateSpaceUsedTotals replaces the saved space used by the pieces of each satellite.
+	UpdateSpaceUsedTotals(ctx context.Context, totals map[storj.NodeID]int64) error
+}
+
+// BlobsUsageCache is a blob storage which keeps the space used by the blobs
+// of each satellite and by the trash up to date when blobs are committed,
+// deleted, trashed or restored, so that it doesn't need to be calculated on
+// every request.
+type BlobsUsageCache struct {
+	storage.Blobs
+
+	mu     sync.Mutex
+	totals map[storj.NodeID]int64
+	trash  int64
+}
+
+var _ SpaceUsed = (*BlobsUsageCache)(nil)
+
+// NewBlobsUsageCache creates a usage cache of blobs, which is empty until the
+// totals are set.
+func NewBlobsUsageCache(blobs storage.Blobs) *BlobsUsageCache {
+	return &BlobsUsageCache{
+		Blobs:  blobs,
+		totals: map[storj.NodeID]int64{},
+	}
+}
+
+// Create creates a new blob, which is counted once committed.
+func (cache *BlobsUsageCache) Create(ctx context.Context, ref storage.BlobRef, format storage.FormatVersion, size int64) (storage.BlobWriter, error) {
+	writer, err := cache.Blobs.Create(ctx, ref, format, size)
+	if err != nil {
+		return nil, err
+	}
+	return &usageWriter{BlobWriter: writer, cache: cache, namespace: ref.Namespace}, nil
+}
+
+// Delete deletes the blob and subtracts its size.
+func (cache *BlobsUsageCache) Delete(ctx context.Context, ref storage.BlobRef) error {
+	_, size := cache.blobSize(ctx, ref)
+
+	if err := cache.Blobs.Delete(ctx, ref); err != nil {
+		return err
+	}
+
+	cache.update(ref.Namespace, -size)
+	return nil
+}
+
+// Trash trashes the blob and moves its size to the trash.
+func (cache *BlobsUsageCache) Trash(ctx context.Context, ref storage.BlobRef) error {
+	fileSize, size := cache.blobSize(ctx, ref)
+
+	if err := cache.Blobs.Trash(ctx, ref); err != nil {
+		return err
+	}
+
+	cache.update(ref.Namespace, -size)
+	cache.updateTrash(fileSize)
+	return nil
+}
+
+// RestoreTrash restores the trashed blobs of the namespace and moves their
+// size back from the trash.
+func (cache *BlobsUsageCache) RestoreTrash(ctx context.Context, namespace []byte) ([]storage.BlobInfo, error) {
+	restored, err := cache.Blobs.RestoreTrash(ctx, namespace)
+	for _, blob := range restored {
+		fileSize, size := cache.blobSize(ctx, blob.Ref)
+		cache.update(namespace, size)
+		cache.updateTrash(-fileSize)
+	}
+	return restored, err
+}
+
+// EmptyTrash empties the trash and subtracts the size of the deleted blobs.
+func (cache *BlobsUsageCache) EmptyTrash(ctx context.Context, trashedBefore time.Time) (int64, error) {
+	emptied, err := cache.Blobs.EmptyTrash(ctx, trashedBefore)
+	cache.updateTrash(-emptied)
+	return emptied, err
+}
+
+// blobSize returns the size of the file of the blob and of its piece data,
+// zero when the blob can't be opened.
+func (cache *BlobsUsageCache) blobSize(ctx context.Context, ref storage.BlobRef) (fileSize, size int64) {
+	reader, err := cache.Blobs.Open(ctx, ref)
+	if err != nil {
+		return 0, 0
+	}
+	fileSize, _ = reader.Size()
+	size = dataSize(reader.StorageFormatVersion(), fileSize)
+	_ = reader.Close()
+	return fileSize, size
+}
+
+// updateTrash adds delta to the size of the trash.
+func (cache *BlobsUsageCache) updateTrash(delta int64) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.trash += delta
+	if cache.trash < 0 {
+		cache.trash = 0
+	}
+}
+
+// update adds delta to the space used by the satellite of namespace.
+func (cache *BlobsUsageCache) update(namespace []byte, delta int64) {
+	satelliteID, err := storj.NodeIDFromBytes(namespace)
+	if err != nil || delta == 0 {
+		return
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.totals[satelliteID] += delta
+	if cache.totals[satelliteID] <= 0 {
+		delete(cache.totals, satelliteID)
+	}
+}
+
+// SpaceUsed returns the space used by all blobs, including the trashed ones.
+func (cache *BlobsUsageCache) SpaceUsed(ctx context.Context) (int64, error) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	total := cache.trash
+	for _, used := range cache.totals {
+		total += used
+	}
+	return total, nil
+}
+
+// SpaceUsedByTrash returns the space used by the trashed blobs.
+func (cache *BlobsUsageCache) SpaceUsedByTrash(ctx context.Context) (int64, error) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	return cache.trash, nil
+}
+
+// SpaceUsedBySatellite returns the space used by the blobs of each satellite.
+func (cache *BlobsUsageCache) SpaceUsedBySatellite(ctx context.Context) (map[storj.NodeID]int64, error) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	totals := make(map[storj.NodeID]int64, len(cache.totals))
+	for satelliteID, used := range cache.totals {
+		totals[satelliteID] = used
+	}
+	return totals, nil
+}
+
+// SetTotals replaces the space used by the blobs of each satellite.
+func (cache *BlobsUsageCache) SetTotals(totals map[storj.NodeID]int64) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.totals = make(map[storj.NodeID]int64, len(totals))
+	for satelliteID, used := range totals {
+		cache.totals[satelliteID] = used
+	}
+}
+
+// SetTrashTotal replaces the space used by the trashed blobs.
+func (cache *BlobsUsageCache) SetTrashTotal(trash int64) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.trash = trash
+}
+
+// usageWriter adds the size of the blob to the cache on commit.
+type usageWriter struct {
+	storage.BlobWriter
+	cache     *BlobsUsageCache
+	namespace []byte
+}
+
+// Commit commits the blob and adds its size.
+func (writer *usageWriter) Commit() error {
+	size, err := writer.BlobWriter.Size()
+	if err != nil {
+		return err
+	}
+	if err := writer.BlobWriter.Commit(); err != nil {
+		return err
+	}
+	writer.cache.update(writer.namespace, dataSize(writer.StorageFormatVersion(), size))
+	return nil
+}
+
+// dataSize returns the size of the piece data of a blob of size bytes, so
+// that the cache matches the piece sizes of the piece information.
+func dataSize(format storage.FormatVersion, size int64) int64 {
+	if format == storage.FormatV1 {
+		size -= V1PieceHeaderReservedArea
+		if size < 0 {
+			size = 0
+		}
+	}
+	return size
+}
+
+// CacheConfig defines parameters for the space used cache.
+type CacheConfig struct {
+	PersistInterval   time.Duration `help:"how frequently the cached space used by the pieces is saved to the database" default:"1m0s"`
+	ReconcileInterval time.Duration `help:"how frequently the cached space used by the pieces is recalculated from the stored blobs, correcting drift" default:"24h0m0s"`
+}
+
+// CacheService loads the space used cache on startup, saves it regularly and
+// corrects it with the stored blobs in the background.
+type CacheService struct {
+	log        *zap.Logger
+	usageCache *BlobsUsageCache
+	spaceUsed  SpaceUsedDB
+
+	Persist   sync2.Cycle
+	Reconcile sync2.Cycle
+}
+
+// NewCacheService creates a space used cache service.
+func NewCacheService(log *zap.Logger, usageCache *BlobsUsageCache, spaceUsed SpaceUsedDB, config CacheConfig) *CacheService {
+	return &CacheService{
+		log:        log,
+		usageCache: usageCache,
+		spaceUsed:  spaceUsed,
+
+		Persist:   *sync2.NewCycle(config.PersistInterval),
+		Reconcile: *sync2.NewCycle(config.ReconcileInterval),
+	}
+}
+
+// Init loads the saved totals into the cache, or calculates them when they
+// were never saved.
+func (service *CacheService) Init(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	totals, ok, err := service.spaceUsed.GetSpaceUsedTotals(ctx)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if !ok {
+		return service.Recalculate(ctx)
+	}
+
+	service.usageCache.SetTotals(totals)
+	return service.recalculateTrash(ctx)
+}
+
+// Run saves and reconciles the cache on every interval, it is loaded with
+// Init beforehand. The first reconcile waits for an interval, so that it
+// doesn't walk all the blobs on every start. The cache is saved once more
+// when the context is canceled.
+func (service *CacheService) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var group errgroup.Group
+	group.Go(func() error {
+		return service.Persist.Run(ctx, func(ctx context.Context) error {
+			if err := service.PersistTotals(ctx); err != nil {
+				service.log.Error("saving space used", zap.Error(err))
+			}
+			return nil
+		})
+	})
+	group.Go(func() error {
+		started := false
+		return service.Reconcile.Run(ctx, func(ctx context.Context) error {
+			if !started {
+				started = true
+				return nil
+			}
+			if err := service.Recalculate(ctx); err != nil {
+				service.log.Error("recalculating space used", zap.Error(err))
+			}
+			return nil
+		})
+	})
+	err = group.Wait()
+
+	// NB: ctx is canceled, the database is still open until the peer closes
+	return errs.Combine(err, service.PersistTotals(context.Background()))
+}
+
+// PersistTotals saves the cached totals.
+func (service *CacheService) PersistTotals(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	totals, err := service.usageCache.SpaceUsedBySatellite(ctx)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	var total int64
+	for _, used := range totals {
+		total += used
+	}
+	mon.IntVal("space_used").Observe(total)
+
+	trash, err := service.usageCache.SpaceUsedByTrash(ctx)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	mon.IntVal("space_used_by_trash").Observe(trash)
+
+	return Error.Wrap(service.spaceUsed.UpdateSpaceUsedTotals(ctx, totals))
+}
+
+// Recalculate replaces the cached totals with the totals of the stored
+// blobs, correcting the drift of the cache. A blob counts at most the disk
+// space allocated to it, so that sparse files aren't overcounted.
+//
+// NB: pieces added or deleted while recalculating may be counted twice or
+// not at all, until the next recalculation.
+func (service *CacheService) Recalculate(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	totals := map[storj.NodeID]int64{}
+	err = service.usageCache.Blobs.Walk(ctx, func(blob storage.BlobInfo) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		satelliteID, err := storj.NodeIDFromBytes(blob.Ref.Namespace)
+		if err != nil {
+			return nil
+		}
+		// the unallocated parts of sparse files use no disk space
+		fileSize := blob.Size
+		if blob.Allocated < fileSize {
+			fileSize = blob.Allocated
+		}
+		if size := dataSize(blob.Format, fileSize); size > 0 {
+			totals[satelliteID] += size
+		}
+		return nil
+	})
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	cachedTotals, err := service.usageCache.SpaceUsedBySatellite(ctx)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	service.usageCache.SetTotals(totals)
+
+	var cached, total int64
+	for _, used := range cachedTotals {
+		cached += used
+	}
+	for _, used := range totals {
+		total += used
+	}
+	mon.IntVal("space_used_drift").Observe(cached - total)
+	if cached != total {
+		service.log.Debug("corrected space used", zap.Int64("cached", cached), zap.Int64("actual", total))
+	}
+
+	if err := service.recalculateTrash(ctx); err != nil {
+		return err
+	}
+	return service.PersistTotals(ctx)
+}
+
+// recalculateTrash replaces the cached size of the trash with the size of
+// the trashed blobs, which isn't saved.
+func (service *CacheService) recalculateTrash(ctx context.Context) error {
+	trash, err := service.usageCache.Blobs.TrashSize(ctx)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	service.usageCache.SetTrashTotal(trash)
+	return nil
+}
+
+// Close stops the service.
+func (service *CacheService) Close() error {
+	service.Persist.Close()
+	service.Reconcile.Close()
+	return nil
+}
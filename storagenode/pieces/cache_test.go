@@ -0,0 +1,115 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pieces_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/pkg/auth/signing"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/pieces"
+	"storj.io/storj/storagenode/storagenodedb/storagenodedbtest"
+)
+
+func TestSpaceUsedCache(t *testing.T) {
+	storagenodedbtest.Run(t, func(t *testing.T, db storagenode.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		usage := pieces.NewBlobsUsageCache(db.Pieces())
+		store := pieces.NewStore(zaptest.NewLogger(t), usage)
+		pieceinfos := db.PieceInfo()
+
+		satellite0 := testplanet.MustPregeneratedSignedIdentity(0)
+		satellite1 := testplanet.MustPregeneratedSignedIdentity(1)
+		uplink := testplanet.MustPregeneratedSignedIdentity(2)
+
+		add := func(satelliteID storj.NodeID, size int) storj.PieceID {
+			pieceID := storj.NewPieceID()
+
+			writer, err := store.Writer(ctx, satelliteID, pieceID, -1)
+			require.NoError(t, err)
+			_, err = writer.Write(make([]byte, size))
+			require.NoError(t, err)
+			require.NoError(t, writer.Commit(&pb.PieceHeader{}))
+
+			pieceHash, err := signing.SignPieceHash(
+				signing.SignerFromFullIdentity(uplink),
+				&pb.PieceHash{PieceId: pieceID})
+			require.NoError(t, err)
+
+			require.NoError(t, pieceinfos.Add(ctx, &pieces.Info{
+				SatelliteID:     satelliteID,
+				PieceID:         pieceID,
+				PieceSize:       int64(size),
+				PieceCreation:   time.Now(),
+				UplinkPieceHash: pieceHash,
+				Uplink:          uplink.PeerIdentity(),
+			}))
+			return pieceID
+		}
+
+		deleted := add(satellite0.ID, 100)
+		add(satellite0.ID, 200)
+		add(satellite1.ID, 300)
+
+		require.NoError(t, store.Delete(ctx, satellite0.ID, deleted))
+		require.NoError(t, pieceinfos.Delete(ctx, satellite0.ID, deleted))
+		// deleting a missing piece doesn't change the space used
+		require.NoError(t, store.Delete(ctx, satellite0.ID, deleted))
+
+		expected := map[storj.NodeID]int64{
+			satellite0.ID: 200,
+			satellite1.ID: 300,
+		}
+
+		used, err := usage.SpaceUsed(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(500), used)
+
+		bySatellite, err := usage.SpaceUsedBySatellite(ctx)
+		require.NoError(t, err)
+		require.Equal(t, expected, bySatellite)
+
+		config := pieces.CacheConfig{PersistInterval: time.Hour, ReconcileInterval: time.Hour}
+
+		{ // the totals are calculated when they were never saved
+			reloaded := pieces.NewBlobsUsageCache(db.Pieces())
+			service := pieces.NewCacheService(zaptest.NewLogger(t), reloaded, db.PieceSpaceUsed(), config)
+			require.NoError(t, service.Init(ctx))
+
+			bySatellite, err := reloaded.SpaceUsedBySatellite(ctx)
+			require.NoError(t, err)
+			require.Equal(t, expected, bySatellite)
+		}
+
+		{ // the saved totals are loaded
+			usage.SetTotals(map[storj.NodeID]int64{satellite0.ID: 1})
+			service := pieces.NewCacheService(zaptest.NewLogger(t), usage, db.PieceSpaceUsed(), config)
+			require.NoError(t, service.PersistTotals(ctx))
+
+			reloaded := pieces.NewBlobsUsageCache(db.Pieces())
+			service = pieces.NewCacheService(zaptest.NewLogger(t), reloaded, db.PieceSpaceUsed(), config)
+			require.NoError(t, service.Init(ctx))
+
+			bySatellite, err := reloaded.SpaceUsedBySatellite(ctx)
+			require.NoError(t, err)
+			require.Equal(t, map[storj.NodeID]int64{satellite0.ID: 1}, bySatellite)
+
+			// and corrected by recalculating
+			require.NoError(t, service.Recalculate(ctx))
+			bySatellite, err = reloaded.SpaceUsedBySatellite(ctx)
+			require.NoError(t, err)
+			require.Equal(t, expected, bySatellite)
+		}
+	})
+}
@@ -97,6 +97,23 @@ func TestPieceInfo(t *testing.T) {
 		require.NoError(t, err)
 		require.Empty(t, cmp.Diff(info1, info1loaded, cmp.Comparer(pb.Equal)))
 
+		// space used by each satellite
+		usage, err := pieceinfos.SpaceUsedBySatellite(ctx)
+		require.NoError(t, err)
+		require.Equal(t, map[storj.NodeID]int64{
+			info0.SatelliteID: info0.PieceSize,
+			info1.SatelliteID: info1.PieceSize,
+		}, usage)
+
+		// corrupted pieces are only counted as corrupted
+		require.NoError(t, pieceinfos.MarkCorrupted(ctx, info1.SatelliteID, info1.PieceID, now))
+		summaries, err := pieceinfos.SummarizeBySatellite(ctx)
+		require.NoError(t, err)
+		require.Equal(t, map[storj.NodeID]pieces.Summary{
+			info0.SatelliteID: {Count: 1, Size: info0.PieceSize},
+			info1.SatelliteID: {Corrupted: 1},
+		}, summaries)
+
 		// deleting
 		err = pieceinfos.Delete(ctx, info0.SatelliteID, info0.PieceID)
 		require.NoError(t, err)
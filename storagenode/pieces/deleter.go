@@ -0,0 +1,174 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pieces
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/internal/sync2"
+	"storj.io/storj/pkg/storj"
+)
+
+// PendingDelete is a piece queued for deletion.
+type PendingDelete struct {
+	SatelliteID storj.NodeID
+	PieceID     storj.PieceID
+	QueuedAt    time.Time
+}
+
+// PendingDeletesDB stores the pieces queued for deletion, so that the queue
+// survives restarts.
+type PendingDeletesDB interface {
+	// Enqueue queues the piece for deletion, a piece already queued is kept.
+	Enqueue(ctx context.Context, satelliteID storj.NodeID, pieceID storj.PieceID, queuedAt time.Time) error
+	// List returns at most limit queued pieces, the oldest first.
+	List(ctx context.Context, limit int) ([]PendingDelete, error)
+	// Remove removes the piece from the queue.
+	Remove(ctx context.Context, satelliteID storj.NodeID, pieceID storj.PieceID) error
+	// Count returns the number of queued pieces.
+	Count(ctx context.Context) (int64, error)
+}
+
+// DeleterConfig defines parameters for the queue of piece deletions.
+type DeleterConfig struct {
+	Workers   int `help:"how many pieces of the deletion queue are deleted at once" default:"2"`
+	BatchSize int `help:"how many pieces of the deletion queue are loaded from the database at once" default:"100"`
+}
+
+// Deleter deletes the pieces queued by the satellites in the background, so
+// that the delete requests return immediately.
+type Deleter struct {
+	log    *zap.Logger
+	config DeleterConfig
+
+	store      *Store
+	pieceinfos DB
+	queue      PendingDeletesDB
+
+	pending chan struct{}
+}
+
+// NewDeleter creates a deleter of the pieces queued in queue.
+func NewDeleter(log *zap.Logger, store *Store, pieceinfos DB, queue PendingDeletesDB, config DeleterConfig) (*Deleter, error) {
+	if config.Workers <= 0 {
+		return nil, Error.New("invalid number of deletion workers %d", config.Workers)
+	}
+	if config.BatchSize <= 0 {
+		return nil, Error.New("invalid deletion batch size %d", config.BatchSize)
+	}
+
+	deleter := &Deleter{
+		log:    log,
+		config: config,
+
+		store:      store,
+		pieceinfos: pieceinfos,
+		queue:      queue,
+
+		pending: make(chan struct{}, 1),
+	}
+	// NB: the pieces queued before a restart are deleted once running
+	deleter.pending <- struct{}{}
+	return deleter, nil
+}
+
+// Enqueue queues the piece for deletion.
+func (deleter *Deleter) Enqueue(ctx context.Context, satelliteID storj.NodeID, pieceID storj.PieceID) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if err := deleter.queue.Enqueue(ctx, satelliteID, pieceID, time.Now()); err != nil {
+		return Error.Wrap(err)
+	}
+
+	select {
+	case deleter.pending <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run deletes the queued pieces until the context is canceled.
+func (deleter *Deleter) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-deleter.pending:
+		}
+
+		if _, err := deleter.Process(ctx); err != nil {
+			deleter.log.Error("deleting queued pieces", zap.Error(err))
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+// Process deletes the queued pieces until the queue is empty, returning the
+// number of deleted pieces. The pieces which couldn't be deleted stay queued
+// until the next call.
+func (deleter *Deleter) Process(ctx context.Context) (deleted int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	defer func() {
+		if count, err := deleter.queue.Count(ctx); err == nil {
+			mon.IntVal("pending_deletes").Observe(count)
+		}
+		if deleted > 0 {
+			deleter.log.Debug("deleted queued pieces", zap.Int64("count", deleted))
+		}
+	}()
+
+	for {
+		pending, err := deleter.queue.List(ctx, deleter.config.BatchSize)
+		if err != nil {
+			return deleted, Error.Wrap(err)
+		}
+
+		var batchDeleted int64
+		limiter := sync2.NewLimiter(deleter.config.Workers)
+		for _, piece := range pending {
+			piece := piece
+			started := limiter.Go(ctx, func() {
+				if err := deleter.delete(ctx, piece); err != nil {
+					deleter.log.Error("deleting queued piece", zap.Stringer("Piece ID", piece.PieceID), zap.Error(err))
+					return
+				}
+				atomic.AddInt64(&batchDeleted, 1)
+			})
+			if !started {
+				break
+			}
+		}
+		limiter.Wait()
+
+		deleted += batchDeleted
+		mon.Meter("queued_pieces_deleted").Mark64(batchDeleted)
+
+		// stop when the queue is empty, or when none of the batch could be
+		// deleted, which would return the same batch again
+		if len(pending) < deleter.config.BatchSize || batchDeleted == 0 {
+			return deleted, ctx.Err()
+		}
+	}
+}
+
+// delete deletes the information of the piece, moves it to the trash and
+// removes it from the queue.
+func (deleter *Deleter) delete(ctx context.Context, piece PendingDelete) error {
+	// NB: the information is deleted first, so that a failure doesn't leave
+	// information about a missing piece
+	if err := deleter.pieceinfos.Delete(ctx, piece.SatelliteID, piece.PieceID); err != nil {
+		return Error.Wrap(err)
+	}
+	if err := deleter.store.Trash(ctx, piece.SatelliteID, piece.PieceID); err != nil {
+		deleter.log.Warn("trashing queued piece data", zap.Stringer("Piece ID", piece.PieceID), zap.Error(err))
+	}
+	return Error.Wrap(deleter.queue.Remove(ctx, piece.SatelliteID, piece.PieceID))
+}
@@ -0,0 +1,108 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pieces_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/pkg/auth/signing"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/pieces"
+	"storj.io/storj/storagenode/storagenodedb/storagenodedbtest"
+)
+
+func TestDeleter(t *testing.T) {
+	storagenodedbtest.Run(t, func(t *testing.T, db storagenode.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		pieceinfos := db.PieceInfo()
+		queue := db.PendingDeletes()
+		store := pieces.NewStore(zaptest.NewLogger(t), db.Pieces())
+
+		satellite := testplanet.MustPregeneratedSignedIdentity(0)
+		uplink := testplanet.MustPregeneratedSignedIdentity(1)
+
+		add := func() storj.PieceID {
+			pieceID := storj.NewPieceID()
+
+			pieceHash, err := signing.SignPieceHash(
+				signing.SignerFromFullIdentity(uplink),
+				&pb.PieceHash{PieceId: pieceID})
+			require.NoError(t, err)
+
+			require.NoError(t, pieceinfos.Add(ctx, &pieces.Info{
+				SatelliteID:     satellite.ID,
+				PieceID:         pieceID,
+				PieceSize:       1,
+				PieceCreation:   time.Now(),
+				UplinkPieceHash: pieceHash,
+				Uplink:          uplink.PeerIdentity(),
+			}))
+
+			writer, err := store.Writer(ctx, satellite.ID, pieceID, -1)
+			require.NoError(t, err)
+			_, err = writer.Write([]byte{1})
+			require.NoError(t, err)
+			require.NoError(t, writer.Commit(&pb.PieceHeader{}))
+			return pieceID
+		}
+
+		_, err := pieces.NewDeleter(zaptest.NewLogger(t), store, pieceinfos, queue, pieces.DeleterConfig{Workers: 0, BatchSize: 2})
+		require.Error(t, err)
+
+		deleter, err := pieces.NewDeleter(zaptest.NewLogger(t), store, pieceinfos, queue, pieces.DeleterConfig{Workers: 2, BatchSize: 2})
+		require.NoError(t, err)
+
+		var deleted, kept []storj.PieceID
+		for i := 0; i < 5; i++ {
+			deleted = append(deleted, add())
+			kept = append(kept, add())
+		}
+
+		for _, pieceID := range deleted {
+			require.NoError(t, deleter.Enqueue(ctx, satellite.ID, pieceID))
+		}
+		// queueing a piece twice deletes it once
+		require.NoError(t, deleter.Enqueue(ctx, satellite.ID, deleted[0]))
+
+		count, err := queue.Count(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(deleted)), count)
+
+		// the queue is persisted, a new deleter picks it up
+		deleter, err = pieces.NewDeleter(zaptest.NewLogger(t), store, pieceinfos, queue, pieces.DeleterConfig{Workers: 2, BatchSize: 2})
+		require.NoError(t, err)
+
+		n, err := deleter.Process(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(deleted)), n)
+
+		count, err = queue.Count(ctx)
+		require.NoError(t, err)
+		require.Zero(t, count)
+
+		for _, pieceID := range deleted {
+			_, err := pieceinfos.Get(ctx, satellite.ID, pieceID)
+			require.Error(t, err)
+			_, err = store.Reader(ctx, satellite.ID, pieceID)
+			require.Error(t, err)
+		}
+		for _, pieceID := range kept {
+			_, err := pieceinfos.Get(ctx, satellite.ID, pieceID)
+			require.NoError(t, err)
+			reader, err := store.Reader(ctx, satellite.ID, pieceID)
+			require.NoError(t, err)
+			require.NoError(t, reader.Close())
+		}
+	})
+}
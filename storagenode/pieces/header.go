@@ -0,0 +1,174 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pieces
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/auth/signing"
+	"storj.io/storj/pkg/identity"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/pkcrypto"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storage"
+)
+
+// NewPieceHeader creates the header of a piece uploaded with the order limit,
+// whose hash was signed by the uplink.
+func NewPieceHeader(limit *pb.OrderLimit2, uplinkPieceHash *pb.PieceHash, uplink *identity.PeerIdentity, creation time.Time) (*pb.PieceHeader, error) {
+	creationTime, err := ptypes.TimestampProto(creation)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	chain := [][]byte{uplink.Leaf.Raw, uplink.CA.Raw}
+	for _, cert := range uplink.RestChain {
+		chain = append(chain, cert.Raw)
+	}
+
+	return &pb.PieceHeader{
+		Hash:            uplinkPieceHash.Hash,
+		CreationTime:    creationTime,
+		Signature:       uplinkPieceHash.Signature,
+		OrderLimit:      limit,
+		UplinkCertChain: chain,
+	}, nil
+}
+
+// InfoFromHeader returns the information of a piece of size bytes from its
+// header, verifying that the uplink signed the hash of the piece.
+func InfoFromHeader(header *pb.PieceHeader, size int64) (*Info, error) {
+	limit := header.OrderLimit
+	if limit == nil {
+		return nil, Error.New("piece header without order limit")
+	}
+
+	certs, err := pkcrypto.CertsFromDER(header.UplinkCertChain)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if len(certs) < 2 {
+		return nil, Error.New("piece header without uplink certificates")
+	}
+	uplink, err := identity.PeerIdentityFromCerts(certs[0], certs[1], certs[2:])
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if uplink.ID != limit.UplinkId {
+		return nil, Error.New("piece header uplink %v doesn't match the order limit uplink %v", uplink.ID, limit.UplinkId)
+	}
+
+	uplinkPieceHash := &pb.PieceHash{
+		PieceId:   limit.PieceId,
+		Hash:      header.Hash,
+		Signature: header.Signature,
+	}
+	if err := signing.VerifyPieceHashSignature(signing.SigneeFromPeerIdentity(uplink), uplinkPieceHash); err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	creation, err := ptypes.Timestamp(header.CreationTime)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	var expiration *time.Time
+	if limit.PieceExpiration != nil {
+		exp, err := ptypes.Timestamp(limit.PieceExpiration)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		expiration = &exp
+	}
+
+	return &Info{
+		SatelliteID: limit.SatelliteId,
+
+		PieceID:         limit.PieceId,
+		PieceSize:       size,
+		PieceExpiration: expiration,
+		PieceCreation:   creation,
+
+		UplinkPieceHash: uplinkPieceHash,
+		Uplink:          uplink,
+	}, nil
+}
+
+// RebuildStats counts the pieces found by RebuildPieceInfo.
+type RebuildStats struct {
+	// Restored is the number of pieces whose information was restored
+	Restored int64
+	// Existing is the number of pieces whose information already existed
+	Existing int64
+	// Unrecoverable is the number of pieces whose information is missing and
+	// can't be restored, because they are of format V0 or their header is invalid
+	Unrecoverable int64
+}
+
+// RebuildPieceInfo scans the stored pieces and restores the missing piece
+// information from the headers of the pieces of format V1, after the piece
+// database was lost.
+func (store *Store) RebuildPieceInfo(ctx context.Context, pieceinfos DB) (stats RebuildStats, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = store.blobs.Walk(ctx, func(blob storage.BlobInfo) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		satelliteID, err := storj.NodeIDFromBytes(blob.Ref.Namespace)
+		if err != nil {
+			return nil
+		}
+		pieceID, err := storj.PieceIDFromBytes(blob.Ref.Key)
+		if err != nil {
+			return nil
+		}
+
+		if _, err := pieceinfos.Get(ctx, satelliteID, pieceID); err == nil {
+			stats.Existing++
+			return nil
+		}
+
+		log := store.log.With(zap.Stringer("Satellite ID", satelliteID), zap.Stringer("Piece ID", pieceID))
+		info, err := store.readInfo(ctx, satelliteID, pieceID)
+		if err != nil {
+			log.Warn("unable to restore piece information", zap.Error(err))
+			stats.Unrecoverable++
+			return nil
+		}
+		if info.SatelliteID != satelliteID || info.PieceID != pieceID {
+			log.Warn("piece header doesn't match the piece")
+			stats.Unrecoverable++
+			return nil
+		}
+
+		if err := pieceinfos.Add(ctx, info); err != nil {
+			return Error.Wrap(err)
+		}
+		stats.Restored++
+		return nil
+	})
+	return stats, err
+}
+
+// readInfo reads the information of the piece from its header.
+func (store *Store) readInfo(ctx context.Context, satelliteID storj.NodeID, pieceID storj.PieceID) (_ *Info, err error) {
+	reader, err := store.Reader(ctx, satelliteID, pieceID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = errs.Combine(err, reader.Close()) }()
+
+	header, err := reader.GetPieceHeader()
+	if err != nil {
+		return nil, err
+	}
+	return InfoFromHeader(header, reader.Size())
+}
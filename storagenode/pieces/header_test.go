@@ -0,0 +1,118 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pieces_test
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/pkg/auth/signing"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storage"
+	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/pieces"
+	"storj.io/storj/storagenode/storagenodedb/storagenodedbtest"
+)
+
+func TestRebuildPieceInfo(t *testing.T) {
+	storagenodedbtest.Run(t, func(t *testing.T, db storagenode.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		pieceinfos := db.PieceInfo()
+		store := pieces.NewStore(zaptest.NewLogger(t), db.Pieces())
+
+		satellite := testplanet.MustPregeneratedSignedIdentity(0)
+		uplink := testplanet.MustPregeneratedSignedIdentity(1)
+		data := []byte{1, 2, 3}
+
+		// a piece of format V1, with its header
+		v1 := storj.NewPieceID()
+		expiration, err := ptypes.TimestampProto(time.Now().Add(time.Hour).UTC())
+		require.NoError(t, err)
+		limit := &pb.OrderLimit2{
+			SatelliteId:     satellite.ID,
+			UplinkId:        uplink.ID,
+			PieceId:         v1,
+			Action:          pb.PieceAction_PUT,
+			Limit:           int64(len(data)),
+			PieceExpiration: expiration,
+		}
+
+		writer, err := store.Writer(ctx, satellite.ID, v1, -1)
+		require.NoError(t, err)
+		_, err = writer.Write(data)
+		require.NoError(t, err)
+		pieceHash, err := signing.SignPieceHash(signing.SignerFromFullIdentity(uplink), &pb.PieceHash{
+			PieceId: v1,
+			Hash:    writer.Hash(),
+		})
+		require.NoError(t, err)
+		creation := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+		header, err := pieces.NewPieceHeader(limit, pieceHash, uplink.PeerIdentity(), creation)
+		require.NoError(t, err)
+		require.NoError(t, writer.Commit(header))
+
+		// a piece of format V0, written before the header existed
+		v0 := storj.NewPieceID()
+		blob, err := db.Pieces().Create(ctx, storage.BlobRef{
+			Namespace: satellite.ID.Bytes(),
+			Key:       v0.Bytes(),
+		}, storage.FormatV0, -1)
+		require.NoError(t, err)
+		_, err = blob.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, blob.Commit())
+
+		// both formats are read without their header
+		for _, pieceID := range []storj.PieceID{v0, v1} {
+			reader, err := store.Reader(ctx, satellite.ID, pieceID)
+			require.NoError(t, err)
+			require.Equal(t, int64(len(data)), reader.Size())
+			read := make([]byte, len(data))
+			_, err = io.ReadFull(reader, read)
+			require.NoError(t, err)
+			require.Equal(t, data, read)
+
+			buf := make([]byte, 2)
+			_, err = reader.ReadAt(buf, 1)
+			require.NoError(t, err)
+			require.Equal(t, data[1:], buf)
+
+			_, err = reader.GetPieceHeader()
+			if pieceID == v0 {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.NoError(t, reader.Close())
+		}
+
+		// the information of the piece of format V1 is restored from its header
+		stats, err := store.RebuildPieceInfo(ctx, pieceinfos)
+		require.NoError(t, err)
+		require.Equal(t, pieces.RebuildStats{Restored: 1, Unrecoverable: 1}, stats)
+
+		info, err := pieceinfos.Get(ctx, satellite.ID, v1)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(data)), info.PieceSize)
+		require.Equal(t, creation, info.PieceCreation.UTC())
+		require.NotNil(t, info.PieceExpiration)
+		require.Equal(t, pieceHash.Hash, info.UplinkPieceHash.Hash)
+		require.Equal(t, pieceHash.Signature, info.UplinkPieceHash.Signature)
+		require.Equal(t, uplink.ID, info.Uplink.ID)
+
+		stats, err = store.RebuildPieceInfo(ctx, pieceinfos)
+		require.NoError(t, err)
+		require.Equal(t, pieces.RebuildStats{Existing: 1, Unrecoverable: 1}, stats)
+	})
+}
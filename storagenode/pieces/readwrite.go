@@ -5,15 +5,28 @@ package pieces
 
 import (
 	"bufio"
+	"encoding/binary"
 	"hash"
 	"io"
 
+	"github.com/gogo/protobuf/proto"
 	"github.com/zeebo/errs"
 
+	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/pkcrypto"
 	"storj.io/storj/storage"
 )
 
+// V1PieceHeaderReservedArea is the size of the area at the start of the piece
+// files of format V1 which is reserved for the header. The header is framed by
+// its size, as a 2-byte big-endian integer, and the rest of the area is zero.
+//
+// NB: the area is a single filesystem block, so that the data stays aligned.
+const V1PieceHeaderReservedArea = 4096
+
+// headerFrameSize is the size of the header frame prefix.
+const headerFrameSize = 2
+
 // Writer implements a piece writer that writes content to blob store and calculates a hash.
 type Writer struct {
 	buf  bufio.Writer
@@ -24,12 +37,19 @@ type Writer struct {
 	closed bool
 }
 
-// NewWriter creates a new writer for storage.BlobWriter.
+// NewWriter creates a new writer for storage.BlobWriter. The blobs of format
+// V1 start with the reserved header area, which is written on commit.
 func NewWriter(blob storage.BlobWriter, bufferSize int) (*Writer, error) {
 	w := &Writer{}
 	w.buf = *bufio.NewWriterSize(blob, bufferSize)
 	w.blob = blob
 	w.hash = pkcrypto.NewHash()
+
+	if blob.StorageFormatVersion() == storage.FormatV1 {
+		if _, err := w.buf.Write(make([]byte, V1PieceHeaderReservedArea)); err != nil {
+			return nil, Error.Wrap(err)
+		}
+	}
 	return w, nil
 }
 
@@ -47,8 +67,9 @@ func (w *Writer) Size() int64 { return w.size }
 // Hash returns the hash of data written so far.
 func (w *Writer) Hash() []byte { return w.hash.Sum(nil) }
 
-// Commit commits piece to permanent storage.
-func (w *Writer) Commit() error {
+// Commit commits piece to permanent storage. The header is written at the
+// start of the blobs of format V1 and ignored for the blobs of format V0.
+func (w *Writer) Commit(header *pb.PieceHeader) error {
 	if w.closed {
 		return Error.New("already closed")
 	}
@@ -56,9 +77,40 @@ func (w *Writer) Commit() error {
 	if err := w.buf.Flush(); err != nil {
 		return Error.Wrap(errs.Combine(err, w.blob.Cancel()))
 	}
+
+	if w.blob.StorageFormatVersion() == storage.FormatV1 {
+		if err := w.writeHeader(header); err != nil {
+			return Error.Wrap(errs.Combine(err, w.blob.Cancel()))
+		}
+	}
 	return Error.Wrap(w.blob.Commit())
 }
 
+// writeHeader writes the framed header into the reserved header area.
+func (w *Writer) writeHeader(header *pb.PieceHeader) error {
+	if header == nil {
+		return Error.New("missing piece header")
+	}
+
+	versioned := *header
+	versioned.FormatVersion = pb.PieceHeader_FORMAT_V1
+
+	data, err := proto.Marshal(&versioned)
+	if err != nil {
+		return err
+	}
+	if headerFrameSize+len(data) > V1PieceHeaderReservedArea {
+		return Error.New("piece header too large: %d bytes", len(data))
+	}
+
+	frame := make([]byte, headerFrameSize+len(data))
+	binary.BigEndian.PutUint16(frame, uint16(len(data)))
+	copy(frame[headerFrameSize:], data)
+
+	_, err = w.blob.WriteAt(frame, 0)
+	return err
+}
+
 // Cancel deletes any temporarily written data.
 func (w *Writer) Cancel() error {
 	if w.closed {
@@ -75,9 +127,13 @@ type Reader struct {
 	blob storage.BlobReader
 	pos  int64
 	size int64
+
+	// dataOffset is the offset of the piece data in the blob
+	dataOffset int64
 }
 
-// NewReader creates a new reader for storage.BlobReader.
+// NewReader creates a new reader for storage.BlobReader. The header area of
+// the blobs of format V1 is skipped, so that only the data is read.
 func NewReader(blob storage.BlobReader, bufferSize int) (*Reader, error) {
 	size, err := blob.Size()
 	if err != nil {
@@ -89,9 +145,49 @@ func NewReader(blob storage.BlobReader, bufferSize int) (*Reader, error) {
 	reader.blob = blob
 	reader.size = size
 
+	if blob.StorageFormatVersion() == storage.FormatV1 {
+		if size < V1PieceHeaderReservedArea {
+			return nil, Error.New("piece file too small for its header: %d bytes", size)
+		}
+		if _, err := blob.Seek(V1PieceHeaderReservedArea, io.SeekStart); err != nil {
+			return nil, Error.Wrap(err)
+		}
+		reader.dataOffset = V1PieceHeaderReservedArea
+		reader.size -= V1PieceHeaderReservedArea
+	}
+
 	return reader, nil
 }
 
+// StorageFormatVersion returns the storage format of the piece.
+func (r *Reader) StorageFormatVersion() storage.FormatVersion {
+	return r.blob.StorageFormatVersion()
+}
+
+// GetPieceHeader reads the header of a piece of format V1. The pieces of
+// format V0 have no header.
+func (r *Reader) GetPieceHeader() (*pb.PieceHeader, error) {
+	if r.blob.StorageFormatVersion() != storage.FormatV1 {
+		return nil, Error.New("piece of format %d has no header", r.blob.StorageFormatVersion())
+	}
+
+	area := make([]byte, V1PieceHeaderReservedArea)
+	if _, err := r.blob.ReadAt(area, 0); err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	size := int(binary.BigEndian.Uint16(area))
+	if headerFrameSize+size > len(area) {
+		return nil, Error.New("invalid piece header size %d", size)
+	}
+
+	header := &pb.PieceHeader{}
+	if err := proto.Unmarshal(area[headerFrameSize:headerFrameSize+size], header); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return header, nil
+}
+
 // Read reads data from the underlying blob, buffering as necessary.
 func (r *Reader) Read(data []byte) (int, error) {
 	n, err := r.blob.Read(data)
@@ -104,16 +200,19 @@ func (r *Reader) Seek(offset int64, whence int) (int64, error) {
 	if whence == io.SeekStart && r.pos == offset {
 		return r.pos, nil
 	}
+	if whence == io.SeekStart {
+		offset += r.dataOffset
+	}
 
 	r.buf.Reset(r.blob)
 	pos, err := r.blob.Seek(offset, whence)
-	r.pos = pos
-	return pos, Error.Wrap(err)
+	r.pos = pos - r.dataOffset
+	return r.pos, Error.Wrap(err)
 }
 
 // ReadAt reads data at the specified offset
 func (r *Reader) ReadAt(data []byte, offset int64) (int, error) {
-	n, err := r.blob.ReadAt(data, offset)
+	n, err := r.blob.ReadAt(data, offset+r.dataOffset)
 	return n, Error.Wrap(err)
 }
 
@@ -0,0 +1,233 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pieces
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/pkg/bloomfilter"
+	"storj.io/storj/pkg/storj"
+)
+
+var mon = monkit.Package()
+
+// RetainStatus defines whether the pieces missing from the retain requests are deleted.
+type RetainStatus int
+
+const (
+	// RetainDisabled ignores the retain requests.
+	RetainDisabled RetainStatus = iota
+	// RetainDebug only logs and counts the pieces which would be deleted.
+	RetainDebug
+	// RetainEnabled deletes the pieces missing from the retain requests.
+	RetainEnabled
+)
+
+// ParseRetainStatus parses the retain status of the configuration.
+func ParseRetainStatus(status string) (RetainStatus, error) {
+	switch status {
+	case "disabled":
+		return RetainDisabled, nil
+	case "debug":
+		return RetainDebug, nil
+	case "enabled":
+		return RetainEnabled, nil
+	}
+	return RetainDisabled, Error.New("invalid retain status %q, expected disabled, debug or enabled", status)
+}
+
+// String returns the retain status as in the configuration.
+func (status RetainStatus) String() string {
+	switch status {
+	case RetainDebug:
+		return "debug"
+	case RetainEnabled:
+		return "enabled"
+	default:
+		return "disabled"
+	}
+}
+
+// RetainConfig defines parameters for garbage collecting the pieces with the bloom filters of the satellites.
+type RetainConfig struct {
+	Status      string        `help:"whether the pieces missing from the bloom filters of the satellites are deleted (disabled, debug or enabled), debug only logs them" default:"debug"`
+	MaxTimeSkew time.Duration `help:"pieces created less than this before the creation date of a bloom filter are kept, allowing for clock differences with the satellite" default:"1h0m0s"`
+	PageSize    int           `help:"number of piece ids checked against a bloom filter at once" default:"1000"`
+}
+
+// RetainRequest is a bloom filter of the pieces of a satellite, the pieces
+// created before CreatedBefore which aren't in it are garbage.
+type RetainRequest struct {
+	SatelliteID   storj.NodeID
+	CreatedBefore time.Time
+	Filter        *bloomfilter.Filter
+}
+
+// RetainService deletes the pieces which are missing from the bloom filters
+// sent by the satellites, one satellite at a time.
+type RetainService struct {
+	log    *zap.Logger
+	config RetainConfig
+	status RetainStatus
+
+	store      *Store
+	pieceinfos DB
+
+	mu      sync.Mutex
+	queued  map[storj.NodeID]RetainRequest
+	pending chan struct{}
+}
+
+// NewRetainService creates a retain service.
+func NewRetainService(log *zap.Logger, store *Store, pieceinfos DB, config RetainConfig) (*RetainService, error) {
+	status, err := ParseRetainStatus(config.Status)
+	if err != nil {
+		return nil, err
+	}
+	if config.PageSize <= 0 {
+		return nil, Error.New("invalid retain page size %d", config.PageSize)
+	}
+
+	return &RetainService{
+		log:    log,
+		config: config,
+		status: status,
+
+		store:      store,
+		pieceinfos: pieceinfos,
+
+		queued:  map[storj.NodeID]RetainRequest{},
+		pending: make(chan struct{}, 1),
+	}, nil
+}
+
+// Status returns whether the retain requests are ignored, logged or executed.
+func (service *RetainService) Status() RetainStatus { return service.status }
+
+// Queue queues the request, replacing a request of the same satellite which
+// wasn't processed yet. It returns false when the retain requests are disabled.
+func (service *RetainService) Queue(req RetainRequest) bool {
+	if service.status == RetainDisabled {
+		return false
+	}
+
+	service.mu.Lock()
+	service.queued[req.SatelliteID] = req
+	service.mu.Unlock()
+
+	select {
+	case service.pending <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// Run processes the queued requests until the context is canceled.
+func (service *RetainService) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-service.pending:
+		}
+
+		for {
+			req, ok := service.next()
+			if !ok {
+				break
+			}
+			if err := service.Retain(ctx, req); err != nil {
+				service.log.Error("retaining pieces", zap.Stringer("Satellite ID", req.SatelliteID), zap.Error(err))
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// next removes a queued request from the queue.
+func (service *RetainService) next() (RetainRequest, bool) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	for satelliteID, req := range service.queued {
+		delete(service.queued, satelliteID)
+		return req, true
+	}
+	return RetainRequest{}, false
+}
+
+// Retain deletes the pieces of the satellite created before the request minus
+// the maximum time skew which are missing from the filter, in debug mode it
+// only logs them.
+func (service *RetainService) Retain(ctx context.Context, req RetainRequest) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	createdBefore := req.CreatedBefore.Add(-service.config.MaxTimeSkew)
+
+	var checked, garbage int64
+	defer func() {
+		mon.IntVal("retain_pieces_checked").Observe(checked)
+		mon.IntVal("retain_pieces_garbage").Observe(garbage)
+		service.log.Info("retained pieces",
+			zap.Stringer("Satellite ID", req.SatelliteID),
+			zap.Stringer("Status", service.status),
+			zap.Int64("checked", checked),
+			zap.Int64("garbage", garbage))
+	}()
+
+	offset := 0
+	for {
+		pieceIDs, err := service.pieceinfos.GetPieceIDs(ctx, req.SatelliteID, createdBefore, service.config.PageSize, offset)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+
+		for _, pieceID := range pieceIDs {
+			checked++
+			if req.Filter.Contains(pieceID) {
+				offset++
+				continue
+			}
+			garbage++
+
+			if service.status != RetainEnabled {
+				service.log.Debug("piece not retained", zap.Stringer("Satellite ID", req.SatelliteID), zap.Stringer("Piece ID", pieceID))
+				offset++
+				continue
+			}
+
+			if err := service.delete(ctx, req.SatelliteID, pieceID); err != nil {
+				service.log.Error("deleting garbage piece", zap.Stringer("Piece ID", pieceID), zap.Error(err))
+				offset++
+				continue
+			}
+			mon.Counter("retain_pieces_deleted").Inc(1)
+		}
+
+		if len(pieceIDs) < service.config.PageSize {
+			return nil
+		}
+	}
+}
+
+// delete deletes the information of the piece and moves it to the trash, from
+// which the satellite can restore it when its bloom filter was wrong.
+func (service *RetainService) delete(ctx context.Context, satelliteID storj.NodeID, pieceID storj.PieceID) error {
+	// NB: the information is deleted first, so that a failure doesn't leave
+	// information about a missing piece
+	if err := service.pieceinfos.Delete(ctx, satelliteID, pieceID); err != nil {
+		return Error.Wrap(err)
+	}
+	if err := service.store.Trash(ctx, satelliteID, pieceID); err != nil {
+		service.log.Warn("trashing garbage piece data", zap.Stringer("Piece ID", pieceID), zap.Error(err))
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pieces_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/pkg/auth/signing"
+	"storj.io/storj/pkg/bloomfilter"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/pieces"
+	"storj.io/storj/storagenode/storagenodedb/storagenodedbtest"
+)
+
+func TestRetain(t *testing.T) {
+	storagenodedbtest.Run(t, func(t *testing.T, db storagenode.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		pieceinfos := db.PieceInfo()
+		store := pieces.NewStore(zaptest.NewLogger(t), db.Pieces())
+
+		satellite0 := testplanet.MustPregeneratedSignedIdentity(0)
+		satellite1 := testplanet.MustPregeneratedSignedIdentity(1)
+		uplink := testplanet.MustPregeneratedSignedIdentity(2)
+
+		now := time.Now()
+		filter := bloomfilter.NewOptimal(100, 0.01)
+
+		var kept, garbage, recent, otherSatellite []storj.PieceID
+		add := func(satelliteID storj.NodeID, creation time.Time, inFilter bool) storj.PieceID {
+			pieceID := storj.NewPieceID()
+			if inFilter {
+				filter.Add(pieceID)
+			}
+
+			pieceHash, err := signing.SignPieceHash(
+				signing.SignerFromFullIdentity(uplink),
+				&pb.PieceHash{PieceId: pieceID})
+			require.NoError(t, err)
+
+			require.NoError(t, pieceinfos.Add(ctx, &pieces.Info{
+				SatelliteID:     satelliteID,
+				PieceID:         pieceID,
+				PieceSize:       1,
+				PieceCreation:   creation,
+				UplinkPieceHash: pieceHash,
+				Uplink:          uplink.PeerIdentity(),
+			}))
+
+			writer, err := store.Writer(ctx, satelliteID, pieceID, -1)
+			require.NoError(t, err)
+			_, err = writer.Write([]byte{1})
+			require.NoError(t, err)
+			require.NoError(t, writer.Commit(&pb.PieceHeader{}))
+			return pieceID
+		}
+
+		for i := 0; i < 5; i++ {
+			kept = append(kept, add(satellite0.ID, now.Add(-time.Hour), true))
+			garbage = append(garbage, add(satellite0.ID, now.Add(-time.Hour), false))
+			recent = append(recent, add(satellite0.ID, now.Add(-time.Second), false))
+			otherSatellite = append(otherSatellite, add(satellite1.ID, now.Add(-time.Hour), false))
+		}
+
+		request := pieces.RetainRequest{
+			SatelliteID:   satellite0.ID,
+			CreatedBefore: now,
+			Filter:        filter,
+		}
+		config := pieces.RetainConfig{MaxTimeSkew: time.Minute, PageSize: 2}
+
+		exists := func(satelliteID storj.NodeID, pieceID storj.PieceID) bool {
+			_, infoErr := pieceinfos.Get(ctx, satelliteID, pieceID)
+			reader, readErr := store.Reader(ctx, satelliteID, pieceID)
+			if readErr == nil {
+				require.NoError(t, reader.Close())
+			}
+			require.Equal(t, infoErr == nil, readErr == nil)
+			return infoErr == nil
+		}
+
+		{ // debug only logs the garbage
+			config.Status = "debug"
+			service, err := pieces.NewRetainService(zaptest.NewLogger(t), store, pieceinfos, config)
+			require.NoError(t, err)
+			require.NoError(t, service.Retain(ctx, request))
+
+			for _, pieceID := range garbage {
+				require.True(t, exists(satellite0.ID, pieceID))
+			}
+		}
+
+		{ // enabled deletes the garbage
+			config.Status = "enabled"
+			service, err := pieces.NewRetainService(zaptest.NewLogger(t), store, pieceinfos, config)
+			require.NoError(t, err)
+			require.NoError(t, service.Retain(ctx, request))
+
+			for _, pieceID := range garbage {
+				require.False(t, exists(satellite0.ID, pieceID))
+			}
+			for _, pieceID := range append(kept, recent...) {
+				require.True(t, exists(satellite0.ID, pieceID))
+			}
+			for _, pieceID := range otherSatellite {
+				require.True(t, exists(satellite1.ID, pieceID))
+			}
+		}
+
+		{ // disabled ignores the requests
+			config.Status = "disabled"
+			service, err := pieces.NewRetainService(zaptest.NewLogger(t), store, pieceinfos, config)
+			require.NoError(t, err)
+			require.False(t, service.Queue(request))
+		}
+
+		_, err := pieces.NewRetainService(zaptest.NewLogger(t), store, pieceinfos, pieces.RetainConfig{Status: "invalid", PageSize: 1})
+		require.Error(t, err)
+	})
+}
@@ -20,7 +20,6 @@ import (
 const (
 	readBufferSize  = 256 * memory.KiB
 	writeBufferSize = 256 * memory.KiB
-	preallocSize    = 4 * memory.MiB
 )
 
 // Error is the default error class.
@@ -33,11 +32,29 @@ type Info struct {
 	PieceID         storj.PieceID
 	PieceSize       int64
 	PieceExpiration *time.Time
+	PieceCreation   time.Time
 
 	UplinkPieceHash *pb.PieceHash
 	Uplink          *identity.PeerIdentity
 }
 
+// ExpiredInfo is the information of an expired piece needed to delete it.
+type ExpiredInfo struct {
+	SatelliteID storj.NodeID
+	PieceID     storj.PieceID
+	PieceSize   int64
+}
+
+// ScrubInfo is the information of a piece needed to verify its data.
+type ScrubInfo struct {
+	SatelliteID storj.NodeID
+	PieceID     storj.PieceID
+	// PieceHash is the hash of the piece data signed by the uplink
+	PieceHash []byte
+	// Corrupted is true when the piece was already flagged as corrupted
+	Corrupted bool
+}
+
 // DB stores meta information about a piece, the actual piece is stored in storage.Blobs
 type DB interface {
 	// Add inserts Info to the database.
@@ -46,8 +63,31 @@ type DB interface {
 	Get(ctx context.Context, satelliteID storj.NodeID, pieceID storj.PieceID) (*Info, error)
 	// Delete deletes Info about a piece.
 	Delete(ctx context.Context, satelliteID storj.NodeID, pieceID storj.PieceID) error
-	// SpaceUsed calculates disk space used by all pieces
+	// MarkCorrupted flags the piece as corrupted, when its data doesn't match its hash.
+	MarkCorrupted(ctx context.Context, satelliteID storj.NodeID, pieceID storj.PieceID, now time.Time) error
+	// CountCorrupted returns the number of pieces flagged as corrupted.
+	CountCorrupted(ctx context.Context) (int64, error)
+	// GetExpired returns at most limit pieces which expired before the time.
+	GetExpired(ctx context.Context, expiredAt time.Time, limit int) ([]ExpiredInfo, error)
+	// GetPieceIDs returns a page of the piece IDs of the satellite created before createdBefore.
+	GetPieceIDs(ctx context.Context, satelliteID storj.NodeID, createdBefore time.Time, limit, offset int) ([]storj.PieceID, error)
+	// GetScrubInfos returns at most limit pieces ordered by satellite and piece id,
+	// starting after the piece afterPiece of the satellite afterSatellite.
+	GetScrubInfos(ctx context.Context, afterSatellite storj.NodeID, afterPiece storj.PieceID, limit int) ([]ScrubInfo, error)
+	// SpaceUsed calculates disk space used by all pieces, except the corrupted pieces
 	SpaceUsed(ctx context.Context) (int64, error)
+	// SpaceUsedBySatellite calculates disk space used by the pieces of each satellite, except the corrupted pieces
+	SpaceUsedBySatellite(ctx context.Context) (map[storj.NodeID]int64, error)
+	// SummarizeBySatellite returns the number and total size of the pieces of each satellite
+	SummarizeBySatellite(ctx context.Context) (map[storj.NodeID]Summary, error)
+}
+
+// Summary is the number and total size of the pieces of a satellite, the
+// corrupted pieces are only counted by Corrupted.
+type Summary struct {
+	Count     int64
+	Size      int64
+	Corrupted int64
 }
 
 // Store implements storing pieces onto a blob storage implementation.
@@ -64,12 +104,17 @@ func NewStore(log *zap.Logger, blobs storage.Blobs) *Store {
 	}
 }
 
-// Writer returns a new piece writer.
-func (store *Store) Writer(ctx context.Context, satellite storj.NodeID, pieceID storj.PieceID) (*Writer, error) {
+// Writer returns a new piece writer, new pieces are written in format V1.
+// The disk space of the piece is preallocated when its size is known,
+// otherwise size is -1.
+func (store *Store) Writer(ctx context.Context, satellite storj.NodeID, pieceID storj.PieceID, size int64) (*Writer, error) {
+	if size >= 0 {
+		size += V1PieceHeaderReservedArea
+	}
 	blob, err := store.blobs.Create(ctx, storage.BlobRef{
 		Namespace: satellite.Bytes(),
 		Key:       pieceID.Bytes(),
-	}, preallocSize.Int64())
+	}, storage.FormatV1, size)
 	if err != nil {
 		return nil, Error.Wrap(err)
 	}
@@ -78,7 +123,7 @@ func (store *Store) Writer(ctx context.Context, satellite storj.NodeID, pieceID
 	return writer, Error.Wrap(err)
 }
 
-// Reader returns a new piece reader.
+// Reader returns a new piece reader, whatever the format of the piece.
 func (store *Store) Reader(ctx context.Context, satellite storj.NodeID, pieceID storj.PieceID) (*Reader, error) {
 	blob, err := store.blobs.Open(ctx, storage.BlobRef{
 		Namespace: satellite.Bytes(),
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/pkcrypto"
 	"storj.io/storj/pkg/storj"
 
@@ -41,7 +42,7 @@ func TestPieces(t *testing.T) {
 	_, _ = rand.Read(source[:])
 
 	{ // write data
-		writer, err := store.Writer(ctx, satelliteID, pieceID)
+		writer, err := store.Writer(ctx, satelliteID, pieceID, -1)
 		require.NoError(t, err)
 
 		n, err := io.Copy(writer, bytes.NewReader(source))
@@ -55,7 +56,7 @@ func TestPieces(t *testing.T) {
 		assert.Equal(t, hash.Sum(nil), writer.Hash())
 
 		// commit
-		require.NoError(t, writer.Commit())
+		require.NoError(t, writer.Commit(&pb.PieceHeader{}))
 		// after commit we should be able to call cancel without an error
 		require.NoError(t, writer.Cancel())
 	}
@@ -93,7 +94,7 @@ func TestPieces(t *testing.T) {
 
 	{ // write cancel
 		cancelledPieceID := storj.NewPieceID()
-		writer, err := store.Writer(ctx, satelliteID, cancelledPieceID)
+		writer, err := store.Writer(ctx, satelliteID, cancelledPieceID, -1)
 		require.NoError(t, err)
 
 		n, err := io.Copy(writer, bytes.NewReader(source))
@@ -104,7 +105,7 @@ func TestPieces(t *testing.T) {
 		// cancel writing
 		require.NoError(t, writer.Cancel())
 		// commit should not fail
-		require.Error(t, writer.Commit())
+		require.Error(t, writer.Commit(&pb.PieceHeader{}))
 
 		// read should fail
 		_, err = store.Reader(ctx, satelliteID, cancelledPieceID)
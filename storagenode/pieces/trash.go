@@ -0,0 +1,125 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pieces
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/internal/sync2"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storage"
+)
+
+// TrashConfig defines parameters for the trash of the deleted pieces.
+type TrashConfig struct {
+	RestoreWindow time.Duration `help:"how long the pieces deleted by the satellites or by garbage collection are kept in the trash, from which the satellites can restore them" default:"168h0m0s"`
+	Interval      time.Duration `help:"how frequently the pieces trashed for longer than the restore window are deleted" default:"24h0m0s"`
+}
+
+// Trash moves the specified piece to the trash of its satellite.
+func (store *Store) Trash(ctx context.Context, satellite storj.NodeID, pieceID storj.PieceID) error {
+	err := store.blobs.Trash(ctx, storage.BlobRef{
+		Namespace: satellite.Bytes(),
+		Key:       pieceID.Bytes(),
+	})
+	return Error.Wrap(err)
+}
+
+// RestoreTrash moves the trashed pieces of the satellite back and restores
+// their information from the headers of the pieces of format V1, returning
+// the number of restored pieces. The restored pieces of format V0 have no
+// information, like after RebuildPieceInfo.
+func (store *Store) RestoreTrash(ctx context.Context, satellite storj.NodeID, pieceinfos DB) (restored int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	// NB: the pieces restored before an error are still processed
+	blobs, err := store.blobs.RestoreTrash(ctx, satellite.Bytes())
+
+	seen := map[storj.PieceID]bool{}
+	for _, blob := range blobs {
+		pieceID, err := storj.PieceIDFromBytes(blob.Ref.Key)
+		if err != nil || seen[pieceID] {
+			continue
+		}
+		seen[pieceID] = true
+		restored++
+
+		if _, err := pieceinfos.Get(ctx, satellite, pieceID); err == nil {
+			continue
+		}
+
+		log := store.log.With(zap.Stringer("Satellite ID", satellite), zap.Stringer("Piece ID", pieceID))
+		info, err := store.readInfo(ctx, satellite, pieceID)
+		if err != nil {
+			log.Warn("unable to restore the information of the restored piece", zap.Error(err))
+			continue
+		}
+		if info.SatelliteID != satellite || info.PieceID != pieceID {
+			log.Warn("piece header doesn't match the restored piece")
+			continue
+		}
+		if err := pieceinfos.Add(ctx, info); err != nil {
+			return restored, Error.Wrap(err)
+		}
+	}
+
+	mon.Meter("restored_trashed_pieces").Mark64(restored)
+	return restored, Error.Wrap(err)
+}
+
+// EmptyTrash deletes the pieces trashed before trashedBefore, returning the
+// number of freed bytes.
+func (store *Store) EmptyTrash(ctx context.Context, trashedBefore time.Time) (emptied int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	emptied, err = store.blobs.EmptyTrash(ctx, trashedBefore)
+	return emptied, Error.Wrap(err)
+}
+
+// TrashChore deletes the pieces which were trashed for longer than the
+// restore window.
+type TrashChore struct {
+	log    *zap.Logger
+	store  *Store
+	config TrashConfig
+
+	Loop sync2.Cycle
+}
+
+// NewTrashChore creates a chore emptying the trash of store.
+func NewTrashChore(log *zap.Logger, store *Store, config TrashConfig) *TrashChore {
+	return &TrashChore{
+		log:    log,
+		store:  store,
+		config: config,
+
+		Loop: *sync2.NewCycle(config.Interval),
+	}
+}
+
+// Run empties the trash on every interval.
+func (chore *TrashChore) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return chore.Loop.Run(ctx, func(ctx context.Context) error {
+		emptied, err := chore.store.EmptyTrash(ctx, time.Now().Add(-chore.config.RestoreWindow))
+		if err != nil {
+			chore.log.Error("emptying trash", zap.Error(err))
+		}
+		if emptied > 0 {
+			chore.log.Debug("emptied trash", zap.Int64("bytes", emptied))
+		}
+		mon.Meter("trash_emptied_bytes").Mark64(emptied)
+		return nil
+	})
+}
+
+// Close stops the chore.
+func (chore *TrashChore) Close() error {
+	chore.Loop.Close()
+	return nil
+}
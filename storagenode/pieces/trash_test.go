@@ -0,0 +1,124 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pieces_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/pkg/auth/signing"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/pieces"
+	"storj.io/storj/storagenode/storagenodedb/storagenodedbtest"
+)
+
+func TestTrash(t *testing.T) {
+	storagenodedbtest.Run(t, func(t *testing.T, db storagenode.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		pieceinfos := db.PieceInfo()
+		usage := pieces.NewBlobsUsageCache(db.Pieces())
+		store := pieces.NewStore(zaptest.NewLogger(t), usage)
+
+		satellite := testplanet.MustPregeneratedSignedIdentity(0)
+		uplink := testplanet.MustPregeneratedSignedIdentity(1)
+		data := []byte{1, 2, 3}
+
+		pieceID := storj.NewPieceID()
+		expiration, err := ptypes.TimestampProto(time.Now().Add(time.Hour).UTC())
+		require.NoError(t, err)
+		limit := &pb.OrderLimit2{
+			SatelliteId:     satellite.ID,
+			UplinkId:        uplink.ID,
+			PieceId:         pieceID,
+			Action:          pb.PieceAction_PUT,
+			Limit:           int64(len(data)),
+			PieceExpiration: expiration,
+		}
+
+		writer, err := store.Writer(ctx, satellite.ID, pieceID, -1)
+		require.NoError(t, err)
+		_, err = writer.Write(data)
+		require.NoError(t, err)
+		pieceHash, err := signing.SignPieceHash(signing.SignerFromFullIdentity(uplink), &pb.PieceHash{
+			PieceId: pieceID,
+			Hash:    writer.Hash(),
+		})
+		require.NoError(t, err)
+		header, err := pieces.NewPieceHeader(limit, pieceHash, uplink.PeerIdentity(), time.Now())
+		require.NoError(t, err)
+		require.NoError(t, writer.Commit(header))
+
+		info, err := pieces.InfoFromHeader(header, int64(len(data)))
+		require.NoError(t, err)
+		require.NoError(t, pieceinfos.Add(ctx, info))
+
+		spaceUsed := func() (pieces, trash int64) {
+			bySatellite, err := usage.SpaceUsedBySatellite(ctx)
+			require.NoError(t, err)
+			trash, err = usage.SpaceUsedByTrash(ctx)
+			require.NoError(t, err)
+			total, err := usage.SpaceUsed(ctx)
+			require.NoError(t, err)
+			require.Equal(t, bySatellite[satellite.ID]+trash, total)
+			return bySatellite[satellite.ID], trash
+		}
+		used, trash := spaceUsed()
+		require.Equal(t, int64(len(data)), used)
+		require.Zero(t, trash)
+
+		// a deleted piece is kept in the trash, and counted as such
+		require.NoError(t, pieceinfos.Delete(ctx, satellite.ID, pieceID))
+		require.NoError(t, store.Trash(ctx, satellite.ID, pieceID))
+		_, err = store.Reader(ctx, satellite.ID, pieceID)
+		require.Error(t, err)
+
+		used, trash = spaceUsed()
+		require.Zero(t, used)
+		require.Equal(t, int64(len(data))+pieces.V1PieceHeaderReservedArea, trash)
+
+		// the restored piece is readable again, with its information
+		restored, err := store.RestoreTrash(ctx, satellite.ID, pieceinfos)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), restored)
+
+		reader, err := store.Reader(ctx, satellite.ID, pieceID)
+		require.NoError(t, err)
+		require.NoError(t, reader.Close())
+		restoredInfo, err := pieceinfos.Get(ctx, satellite.ID, pieceID)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(data)), restoredInfo.PieceSize)
+
+		used, trash = spaceUsed()
+		require.Equal(t, int64(len(data)), used)
+		require.Zero(t, trash)
+
+		// the trash is emptied of the pieces trashed before the time
+		require.NoError(t, store.Trash(ctx, satellite.ID, pieceID))
+		emptied, err := store.EmptyTrash(ctx, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		require.Zero(t, emptied)
+
+		emptied, err = store.EmptyTrash(ctx, time.Now().Add(time.Second))
+		require.NoError(t, err)
+		require.Equal(t, int64(len(data))+pieces.V1PieceHeaderReservedArea, emptied)
+
+		restored, err = store.RestoreTrash(ctx, satellite.ID, pieceinfos)
+		require.NoError(t, err)
+		require.Zero(t, restored)
+
+		used, trash = spaceUsed()
+		require.Zero(t, used)
+		require.Zero(t, trash)
+	})
+}
@@ -6,20 +6,28 @@ package piecestore
 import (
 	"context"
 	"io"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang/protobuf/ptypes"
 	"github.com/zeebo/errs"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	monkit "gopkg.in/spacemonkeygo/monkit.v2"
 
 	"storj.io/storj/internal/memory"
 	"storj.io/storj/internal/sync2"
 	"storj.io/storj/pkg/auth/signing"
+	"storj.io/storj/pkg/bloomfilter"
 	"storj.io/storj/pkg/identity"
 	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storage"
 	"storj.io/storj/storagenode/bandwidth"
+	"storj.io/storj/storagenode/diskhealth"
 	"storj.io/storj/storagenode/monitor"
 	"storj.io/storj/storagenode/orders"
 	"storj.io/storj/storagenode/pieces"
@@ -35,15 +43,37 @@ var (
 	ErrProtocol = errs.Class("piecestore protocol")
 	// ErrInternal is the default error class for internal piecestore errors.
 	ErrInternal = errs.Class("piecestore internal")
+	// ErrBusy is the error class for requests rejected because too many are
+	// being handled, they can be retried on another storage node.
+	ErrBusy = errs.Class("piecestore busy")
+	// ErrReadOnly is the error class for uploads rejected because the storage
+	// node is read-only, they can be retried on another storage node.
+	ErrReadOnly = errs.Class("piecestore read-only")
 )
 var _ pb.PiecestoreServer = (*Endpoint)(nil)
 
 // Config defines parameters for piecestore endpoint.
 type Config struct {
 	ExpirationGracePeriod time.Duration `help:"how soon before expiration date should things be considered expired" default:"48h0m0s"`
+	DatabaseURL           string        `help:"url of the database for orders, pieces information, bandwidth usage and used serials, info.db in the storage path when empty" default:""`
+	DBMaintenanceInterval time.Duration `help:"duration between vacuuming and analyzing the database when the node is idle" default:"24h0m0s"`
+	DBStatsInterval       time.Duration `help:"how frequently the database statistics are reported" default:"1h0m0s"`
+	DatabaseAutoRecover   bool          `help:"move corrupt sqlite databases aside on startup and recreate them empty, losing their state" default:"false"`
+	DatabaseKeyFile       string        `help:"file with the hex encoded 32 byte key encrypting the order limits and uplink identities in the database, unencrypted when empty" default:""`
+	VerifyOnRead          bool          `help:"verify the hash of the piece before downloads of the whole piece, failing the download and flagging the piece when it's corrupted" default:"false"`
+	SatelliteLimits       string        `help:"comma-separated caps of the bandwidth per month and the disk space of satellites, as <satellite id>:<bandwidth>:<disk>, 0 is unlimited" default:""`
+	MaxConcurrentRequests int           `help:"how many uploads and downloads are handled at once, further ones are rejected as busy, unlimited when 0" default:"0"`
+	MaxTransferRate       memory.Size   `help:"how many bytes per second each upload and download may transfer, unlimited when 0" default:"0"`
+	ReadOnly              bool          `help:"reject new uploads while still serving downloads and audits, to drain a full disk or do maintenance, applied on configuration reloads" default:"false"`
+	Preallocate           bool          `help:"allocate the disk space of the uploaded pieces when they are created, to reduce fragmentation on ext4 or NTFS, unnecessary on ZFS or btrfs" default:"false"`
 
 	Monitor monitor.Config
 	Sender  orders.SenderConfig
+	Orders  orders.CleanupConfig
+	Retain  pieces.RetainConfig
+	Deleter pieces.DeleterConfig
+	Cache   pieces.CacheConfig
+	Trash   pieces.TrashConfig
 }
 
 // Endpoint implements uploading, downloading and deleting for a storage node.
@@ -55,15 +85,30 @@ type Endpoint struct {
 	trust  *trust.Pool
 
 	store       *pieces.Store
+	retain      *pieces.RetainService
+	deleter     *pieces.Deleter
 	pieceinfo   pieces.DB
-	orders      orders.DB
-	usage       bandwidth.DB
 	usedSerials UsedSerials
+	db          TxDB
+
+	spaceUsed       pieces.SpaceUsed
+	usage           bandwidth.DB
+	satelliteLimits map[storj.NodeID]SatelliteLimit
+	diskHealth      *diskhealth.Service
+
+	liveRequests  int32
+	liveTransfers int32
+	readOnly      int32
 }
 
 // NewEndpoint creates a new piecestore endpoint.
-func NewEndpoint(log *zap.Logger, signer signing.Signer, trust *trust.Pool, store *pieces.Store, pieceinfo pieces.DB, orders orders.DB, usage bandwidth.DB, usedSerials UsedSerials, config Config) (*Endpoint, error) {
-	return &Endpoint{
+func NewEndpoint(log *zap.Logger, signer signing.Signer, trust *trust.Pool, store *pieces.Store, retain *pieces.RetainService, deleter *pieces.Deleter, pieceinfo pieces.DB, usedSerials UsedSerials, db TxDB, spaceUsed pieces.SpaceUsed, usage bandwidth.DB, diskHealth *diskhealth.Service, config Config) (*Endpoint, error) {
+	satelliteLimits, err := ParseSatelliteLimits(config.SatelliteLimits)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	endpoint := &Endpoint{
 		log:    log,
 		config: config,
 
@@ -71,17 +116,66 @@ func NewEndpoint(log *zap.Logger, signer signing.Signer, trust *trust.Pool, stor
 		trust:  trust,
 
 		store:       store,
+		retain:      retain,
+		deleter:     deleter,
 		pieceinfo:   pieceinfo,
-		orders:      orders,
-		usage:       usage,
 		usedSerials: usedSerials,
-	}, nil
+		db:          db,
+
+		spaceUsed:       spaceUsed,
+		usage:           usage,
+		satelliteLimits: satelliteLimits,
+		diskHealth:      diskHealth,
+	}
+	endpoint.SetReadOnly(config.ReadOnly)
+	return endpoint, nil
+}
+
+// SetReadOnly sets whether new uploads are rejected, the downloads and audits
+// are still served.
+func (endpoint *Endpoint) SetReadOnly(readOnly bool) {
+	var value int32
+	if readOnly {
+		value = 1
+	}
+	atomic.StoreInt32(&endpoint.readOnly, value)
+}
+
+// ReadOnly returns whether new uploads are rejected.
+func (endpoint *Endpoint) ReadOnly() bool {
+	return atomic.LoadInt32(&endpoint.readOnly) != 0
+}
+
+// LiveRequests returns the number of requests being handled.
+func (endpoint *Endpoint) LiveRequests() int {
+	return int(atomic.LoadInt32(&endpoint.liveRequests))
+}
+
+// beginTransfer counts an upload or a download, rejecting it with ErrBusy
+// when the maximum number of concurrent transfers is reached.
+func (endpoint *Endpoint) beginTransfer() error {
+	transfers := atomic.AddInt32(&endpoint.liveTransfers, 1)
+	if max := endpoint.config.MaxConcurrentRequests; max > 0 && int(transfers) > max {
+		atomic.AddInt32(&endpoint.liveTransfers, -1)
+		mon.Meter("busy_rejected_requests").Mark(1)
+		// NB: the status code lets the uplink tell a busy node from a failing one
+		return status.Error(codes.ResourceExhausted, ErrBusy.New("too many concurrent requests, maximum %d", max).Error())
+	}
+	return nil
+}
+
+// endTransfer uncounts an upload or a download started by beginTransfer.
+func (endpoint *Endpoint) endTransfer() {
+	atomic.AddInt32(&endpoint.liveTransfers, -1)
 }
 
 // Delete handles deleting a piece on piece store.
 func (endpoint *Endpoint) Delete(ctx context.Context, delete *pb.PieceDeleteRequest) (_ *pb.PieceDeleteResponse, err error) {
 	defer mon.Task()(&ctx)(&err)
 
+	atomic.AddInt32(&endpoint.liveRequests, 1)
+	defer atomic.AddInt32(&endpoint.liveRequests, -1)
+
 	if delete.Limit.Action != pb.PieceAction_DELETE {
 		return nil, Error.New("expected delete action got %v", delete.Limit.Action) // TODO: report grpc status unauthorized or bad request
 	}
@@ -91,28 +185,102 @@ func (endpoint *Endpoint) Delete(ctx context.Context, delete *pb.PieceDeleteRequ
 		return nil, Error.Wrap(err)
 	}
 
-	// TODO: parallelize this and maybe return early
-	pieceInfoErr := endpoint.pieceinfo.Delete(ctx, delete.Limit.SatelliteId, delete.Limit.PieceId)
-	pieceErr := endpoint.store.Delete(ctx, delete.Limit.SatelliteId, delete.Limit.PieceId)
-
-	if err := errs.Combine(pieceInfoErr, pieceErr); err != nil {
+	// NB: the piece is deleted in the background, so that the satellite doesn't
+	// wait for the disk
+	if err := endpoint.deleter.Enqueue(ctx, delete.Limit.SatelliteId, delete.Limit.PieceId); err != nil {
 		// explicitly ignoring error because the errors
 		// TODO: add more debug info
-		endpoint.log.Error("delete failed", zap.Stringer("Piece ID", delete.Limit.PieceId), zap.Error(err))
-		// TODO: report internal server internal or missing error using grpc status,
-		// e.g. missing might happen when we get a deletion request after garbage collection has deleted it
+		endpoint.log.Error("queueing delete failed", zap.Stringer("Piece ID", delete.Limit.PieceId), zap.Error(err))
+		// TODO: report internal server internal or missing error using grpc status
 	} else {
-		endpoint.log.Debug("deleted", zap.Stringer("Piece ID", delete.Limit.PieceId))
+		endpoint.log.Debug("queued for deletion", zap.Stringer("Piece ID", delete.Limit.PieceId))
 	}
 
 	return &pb.PieceDeleteResponse{}, nil
 }
 
+// Retain queues the garbage collection of the pieces of the satellite which
+// are missing from the bloom filter of the request.
+func (endpoint *Endpoint) Retain(ctx context.Context, retainReq *pb.RetainRequest) (_ *pb.RetainResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if endpoint.retain.Status() == pieces.RetainDisabled {
+		return &pb.RetainResponse{}, nil
+	}
+
+	peer, err := identity.PeerIdentityFromContext(ctx)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	if err := endpoint.trust.VerifySatelliteID(ctx, peer.ID); err != nil {
+		return nil, Error.New("retain called with untrusted ID")
+	}
+
+	createdBefore, err := ptypes.Timestamp(retainReq.GetCreationDate())
+	if err != nil {
+		return nil, ErrProtocol.Wrap(err)
+	}
+
+	filter, err := bloomfilter.NewFromBytes(retainReq.GetFilter())
+	if err != nil {
+		return nil, ErrProtocol.Wrap(err)
+	}
+
+	endpoint.retain.Queue(pieces.RetainRequest{
+		SatelliteID:   peer.ID,
+		CreatedBefore: createdBefore,
+		Filter:        filter,
+	})
+
+	return &pb.RetainResponse{}, nil
+}
+
+// RestoreTrash restores the pieces of the satellite which are in the trash.
+func (endpoint *Endpoint) RestoreTrash(ctx context.Context, restoreReq *pb.RestoreTrashRequest) (_ *pb.RestoreTrashResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	peer, err := identity.PeerIdentityFromContext(ctx)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	if err := endpoint.trust.VerifySatelliteID(ctx, peer.ID); err != nil {
+		return nil, Error.New("restore trash called with untrusted ID")
+	}
+
+	restored, err := endpoint.store.RestoreTrash(ctx, peer.ID, endpoint.pieceinfo)
+	if err != nil {
+		return nil, ErrInternal.Wrap(err)
+	}
+	endpoint.log.Info("restored trash", zap.Stringer("Satellite ID", peer.ID), zap.Int64("pieces", restored))
+
+	return &pb.RestoreTrashResponse{RestoredPieces: restored}, nil
+}
+
 // Upload handles uploading a piece on piece store.
 func (endpoint *Endpoint) Upload(stream pb.Piecestore_UploadServer) (err error) {
 	ctx := stream.Context()
 	defer mon.Task()(&ctx)(&err)
 
+	if endpoint.diskHealth.Failing() {
+		mon.Meter("disk_failing_rejected_uploads").Mark(1)
+		return status.Error(codes.Unavailable, ErrInternal.New("disk is failing, not accepting uploads").Error())
+	}
+
+	if endpoint.ReadOnly() {
+		mon.Meter("read_only_rejected_uploads").Mark(1)
+		return status.Error(codes.Unavailable, ErrReadOnly.New("node is read-only, not accepting uploads").Error())
+	}
+
+	if err := endpoint.beginTransfer(); err != nil {
+		return err
+	}
+	defer endpoint.endTransfer()
+
+	atomic.AddInt32(&endpoint.liveRequests, 1)
+	defer atomic.AddInt32(&endpoint.liveRequests, -1)
+
 	// TODO: set connection timeouts
 	// TODO: set maximum message size
 
@@ -138,6 +306,9 @@ func (endpoint *Endpoint) Upload(stream pb.Piecestore_UploadServer) (err error)
 	if err := endpoint.VerifyOrderLimit(ctx, limit); err != nil {
 		return err // TODO: report grpc status unauthorized or bad request
 	}
+	if err := endpoint.VerifySatelliteLimit(ctx, limit); err != nil {
+		return err
+	}
 
 	defer func() {
 		if err != nil {
@@ -152,7 +323,7 @@ func (endpoint *Endpoint) Upload(stream pb.Piecestore_UploadServer) (err error)
 		return Error.Wrap(err)
 	}
 
-	pieceWriter, err := endpoint.store.Writer(ctx, limit.SatelliteId, limit.PieceId)
+	pieceWriter, err := endpoint.store.Writer(ctx, limit.SatelliteId, limit.PieceId, limit.Limit)
 	if err != nil {
 		return ErrInternal.Wrap(err) // TODO: report grpc status internal server error
 	}
@@ -163,8 +334,16 @@ func (endpoint *Endpoint) Upload(stream pb.Piecestore_UploadServer) (err error)
 		}
 	}()
 
+	shaper := newShaper(endpoint.config.MaxTransferRate)
+
 	largestOrder := pb.Order2{}
-	defer endpoint.SaveOrder(ctx, limit, &largestOrder, peer)
+	committed := false
+	defer func() {
+		// the order of a completed upload is saved with its piece information
+		if !committed {
+			endpoint.SaveOrder(ctx, limit, &largestOrder, peer)
+		}
+	}()
 
 	for {
 		message, err = stream.Recv() // TODO: reuse messages to avoid allocations
@@ -197,6 +376,9 @@ func (endpoint *Endpoint) Upload(stream pb.Piecestore_UploadServer) (err error)
 				return ErrProtocol.New("not enough allocated, allocated=%v writing=%v", largestOrder.Amount, pieceWriter.Size()+int64(len(message.Chunk.Data))) // TODO: report grpc status ?
 			}
 
+			if err := shaper.wait(ctx, int64(len(message.Chunk.Data))); err != nil {
+				return ErrProtocol.Wrap(err)
+			}
 			if _, err := pieceWriter.Write(message.Chunk.Data); err != nil {
 				return ErrInternal.Wrap(err) // TODO: report grpc status internal server error
 			}
@@ -207,7 +389,13 @@ func (endpoint *Endpoint) Upload(stream pb.Piecestore_UploadServer) (err error)
 				return err // TODO: report grpc status internal server error
 			}
 
-			if err := pieceWriter.Commit(); err != nil {
+			// the header lets the piece be verified without its information
+			creation := time.Now()
+			header, err := pieces.NewPieceHeader(limit, message.Done, peer, creation)
+			if err != nil {
+				return ErrInternal.Wrap(err)
+			}
+			if err := pieceWriter.Commit(header); err != nil {
 				return ErrInternal.Wrap(err) // TODO: report grpc status internal server error
 			}
 
@@ -229,14 +417,28 @@ func (endpoint *Endpoint) Upload(stream pb.Piecestore_UploadServer) (err error)
 					PieceID:         limit.PieceId,
 					PieceSize:       pieceWriter.Size(),
 					PieceExpiration: expiration,
+					PieceCreation:   creation,
 
 					UplinkPieceHash: message.Done,
 					Uplink:          peer,
 				}
 
-				if err := endpoint.pieceinfo.Add(ctx, info); err != nil {
+				// the piece information, the order and the bandwidth usage
+				// are saved atomically, so that a crash doesn't leave the
+				// piece unaccounted for
+				err := endpoint.db.WithTx(ctx, func(ctx context.Context, tx Tx) error {
+					if err := tx.PieceInfo().Add(ctx, info); err != nil {
+						return err
+					}
+					return endpoint.saveOrder(ctx, tx, limit, &largestOrder, peer)
+				})
+				if err != nil {
+					if deleteErr := endpoint.store.Delete(ctx, limit.SatelliteId, limit.PieceId); deleteErr != nil {
+						endpoint.log.Error("failed to delete piece without information", zap.Stringer("Piece ID", limit.PieceId), zap.Error(deleteErr))
+					}
 					return ErrInternal.Wrap(err)
 				}
+				committed = true
 			}
 
 			storageNodeHash, err := signing.SignPieceHash(endpoint.signer, &pb.PieceHash{
@@ -260,6 +462,14 @@ func (endpoint *Endpoint) Download(stream pb.Piecestore_DownloadServer) (err err
 	ctx := stream.Context()
 	defer mon.Task()(&ctx)(&err)
 
+	if err := endpoint.beginTransfer(); err != nil {
+		return err
+	}
+	defer endpoint.endTransfer()
+
+	atomic.AddInt32(&endpoint.liveRequests, 1)
+	defer atomic.AddInt32(&endpoint.liveRequests, -1)
+
 	// TODO: set connection timeouts
 	// TODO: set maximum message size
 
@@ -286,6 +496,9 @@ func (endpoint *Endpoint) Download(stream pb.Piecestore_DownloadServer) (err err
 	if err := endpoint.VerifyOrderLimit(ctx, limit); err != nil {
 		return Error.Wrap(err) // TODO: report grpc status unauthorized or bad request
 	}
+	if err := endpoint.VerifySatelliteLimit(ctx, limit); err != nil {
+		return Error.Wrap(err)
+	}
 
 	defer func() {
 		if err != nil {
@@ -293,6 +506,14 @@ func (endpoint *Endpoint) Download(stream pb.Piecestore_DownloadServer) (err err
 		} else {
 			endpoint.log.Debug("downloaded", zap.Stringer("Piece ID", limit.PieceId))
 		}
+
+		if limit.Action == pb.PieceAction_GET_AUDIT {
+			if err != nil {
+				mon.Meter("audit_failure").Mark(1)
+			} else {
+				mon.Meter("audit_success").Mark(1)
+			}
+		}
 	}()
 
 	peer, err := identity.PeerIdentityFromContext(ctx)
@@ -317,6 +538,25 @@ func (endpoint *Endpoint) Download(stream pb.Piecestore_DownloadServer) (err err
 		return Error.New("requested more data than available, requesting=%v available=%v", chunk.Offset+chunk.ChunkSize, pieceReader.Size())
 	}
 
+	// NB: hashing the whole piece for a partial download would read much more
+	// than is sent, only the full-piece downloads are verified
+	if endpoint.config.VerifyOnRead && chunk.Offset == 0 && chunk.ChunkSize == pieceReader.Size() {
+		if err := endpoint.VerifyPieceData(ctx, limit, pieceReader); err != nil {
+			return err
+		}
+	}
+
+	// the header of the piece proves to the auditing satellite that the
+	// uplink signed the hash of the piece, the pieces of format V0 have none
+	var header *pb.PieceHeader
+	if limit.Action == pb.PieceAction_GET_AUDIT && pieceReader.StorageFormatVersion() == storage.FormatV1 {
+		header, err = pieceReader.GetPieceHeader()
+		if err != nil {
+			return ErrInternal.Wrap(err)
+		}
+	}
+
+	shaper := newShaper(endpoint.config.MaxTransferRate)
 	throttle := sync2.NewThrottle()
 	// TODO: see whether this can be implemented without a goroutine
 
@@ -336,6 +576,10 @@ func (endpoint *Endpoint) Download(stream pb.Piecestore_DownloadServer) (err err
 				return nil
 			}
 
+			if err := shaper.wait(ctx, chunkSize); err != nil {
+				return ErrProtocol.Wrap(err)
+			}
+
 			chunkData := make([]byte, chunkSize)
 			_, err = pieceReader.Seek(currentOffset, io.SeekStart)
 			if err != nil {
@@ -352,12 +596,15 @@ func (endpoint *Endpoint) Download(stream pb.Piecestore_DownloadServer) (err err
 					Offset: currentOffset,
 					Data:   chunkData,
 				},
+				Header: header,
 			})
 			if err != nil {
 				// err is io.EOF when uplink asked for a piece, but decided not to retrieve it,
 				// no need to propagate it
 				return ErrProtocol.Wrap(ignoreEOF(err))
 			}
+			// only the first chunk carries the header
+			header = nil
 
 			currentOffset += chunkSize
 			unsentAmount -= chunkSize
@@ -408,19 +655,38 @@ func (endpoint *Endpoint) SaveOrder(ctx context.Context, limit *pb.OrderLimit2,
 	if order == nil || order.Amount <= 0 {
 		return
 	}
-	err := endpoint.orders.Enqueue(ctx, &orders.Info{
+	err := endpoint.db.WithTx(ctx, func(ctx context.Context, tx Tx) error {
+		return endpoint.saveOrder(ctx, tx, limit, order, uplink)
+	})
+	if err != nil {
+		endpoint.log.Error("failed to save order", zap.Error(err))
+	}
+}
+
+// saveOrder adds the order and its bandwidth usage in tx, a duplicate order
+// is only logged.
+func (endpoint *Endpoint) saveOrder(ctx context.Context, tx Tx, limit *pb.OrderLimit2, order *pb.Order2, uplink *identity.PeerIdentity) error {
+	if order == nil || order.Amount <= 0 {
+		return nil
+	}
+	err := tx.Orders().Enqueue(ctx, &orders.Info{
 		Limit:  limit,
 		Order:  order,
 		Uplink: uplink,
 	})
 	if err != nil {
-		endpoint.log.Error("failed to add order", zap.Error(err))
-	} else {
-		err := endpoint.usage.Add(ctx, limit.SatelliteId, limit.Action, order.Amount, time.Now())
-		if err != nil {
-			endpoint.log.Error("failed to add bandwidth usage", zap.Error(err))
+		if orders.ErrSerialAlreadyExists.Has(err) {
+			endpoint.log.Warn("duplicate order", zap.Error(err))
+			return nil
 		}
+		return err
+	}
+	if err := tx.Bandwidth().Add(ctx, limit.SatelliteId, limit.Action, order.Amount, time.Now()); err != nil {
+		return err
 	}
+
+	mon.Meter("bandwidth_" + strings.ToLower(limit.Action.String())).Mark64(order.Amount)
+	return nil
 }
 
 // min finds the min of two values
@@ -5,13 +5,17 @@ package piecestore_test
 
 import (
 	"io"
+	"io/ioutil"
 	"math/rand"
+	"path/filepath"
+	"runtime"
 	"testing"
 	"time"
 
 	"github.com/golang/protobuf/ptypes"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/zeebo/errs"
 
 	"storj.io/storj/internal/memory"
 	"storj.io/storj/internal/testcontext"
@@ -20,6 +24,7 @@ import (
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/pkcrypto"
 	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storagenode"
 	"storj.io/storj/storagenode/bandwidth"
 	"storj.io/storj/uplink/piecestore"
 )
@@ -396,3 +401,387 @@ func GenerateOrderLimit(t *testing.T, satellite storj.NodeID, uplink storj.NodeI
 	}
 	return orderLimit
 }
+
+func TestDownloadVerifyOnRead(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 1, UplinkCount: 1,
+		Reconfigure: testplanet.Reconfigure{
+			StorageNode: func(index int, config *storagenode.Config) {
+				config.Storage2.VerifyOnRead = true
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite, node := planet.Satellites[0], planet.StorageNodes[0]
+
+		client, err := planet.Uplinks[0].DialPiecestore(ctx, node)
+		require.NoError(t, err)
+		defer ctx.Check(client.Close)
+
+		expectedData := make([]byte, 10*memory.KiB)
+		_, _ = rand.Read(expectedData)
+
+		signer := signing.SignerFromFullIdentity(satellite.Identity)
+		signedLimit := func(action pb.PieceAction) *pb.OrderLimit2 {
+			var serialNumber storj.SerialNumber
+			_, _ = rand.Read(serialNumber[:])
+
+			orderLimit, err := signing.SignOrderLimit(signer, GenerateOrderLimit(
+				t,
+				satellite.ID(),
+				planet.Uplinks[0].ID(),
+				node.ID(),
+				storj.PieceID{1},
+				action,
+				serialNumber,
+				24*time.Hour,
+				24*time.Hour,
+				int64(len(expectedData)),
+			))
+			require.NoError(t, err)
+			return orderLimit
+		}
+
+		uploader, err := client.Upload(ctx, signedLimit(pb.PieceAction_PUT))
+		require.NoError(t, err)
+		_, err = uploader.Write(expectedData)
+		require.NoError(t, err)
+		_, err = uploader.Commit()
+		require.NoError(t, err)
+
+		download := func(offset, size int64) ([]byte, error) {
+			downloader, err := client.Download(ctx, signedLimit(pb.PieceAction_GET), offset, size)
+			if err != nil {
+				return nil, err
+			}
+			buffer := make([]byte, size)
+			_, readErr := io.ReadFull(downloader, buffer)
+			return buffer, errs.Combine(readErr, downloader.Close())
+		}
+
+		// full and partial downloads of an intact piece succeed
+		data, err := download(0, int64(len(expectedData)))
+		require.NoError(t, err)
+		require.Equal(t, expectedData, data)
+
+		data, err = download(100, 1000)
+		require.NoError(t, err)
+		require.Equal(t, expectedData[100:1100], data)
+
+		// corrupt the data of the piece
+		corrupted := append([]byte{}, expectedData...)
+		corrupted[5000]++
+		writer, err := node.Storage2.Store.Writer(ctx, satellite.ID(), storj.PieceID{1}, -1)
+		require.NoError(t, err)
+		_, err = writer.Write(corrupted)
+		require.NoError(t, err)
+		require.NoError(t, writer.Commit(&pb.PieceHeader{}))
+
+		// partial downloads aren't verified
+		data, err = download(100, 1000)
+		require.NoError(t, err)
+		require.Equal(t, corrupted[100:1100], data)
+
+		_, err = download(0, int64(len(expectedData)))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "corrupted piece")
+
+		stats, err := node.DB.Stats().Stats(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), stats.CorruptedPieces)
+	})
+}
+
+func TestMaxConcurrentRequests(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 1, UplinkCount: 1,
+		Reconfigure: testplanet.Reconfigure{
+			StorageNode: func(index int, config *storagenode.Config) {
+				config.Storage2.MaxConcurrentRequests = 1
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite, node := planet.Satellites[0], planet.StorageNodes[0]
+
+		client, err := planet.Uplinks[0].DialPiecestore(ctx, node)
+		require.NoError(t, err)
+		defer ctx.Check(client.Close)
+
+		data := make([]byte, 10*memory.KiB)
+		_, _ = rand.Read(data)
+
+		signer := signing.SignerFromFullIdentity(satellite.Identity)
+		upload := func() (piecestore.Uploader, error) {
+			var serialNumber storj.SerialNumber
+			_, _ = rand.Read(serialNumber[:])
+
+			orderLimit, err := signing.SignOrderLimit(signer, GenerateOrderLimit(
+				t,
+				satellite.ID(),
+				planet.Uplinks[0].ID(),
+				node.ID(),
+				storj.NewPieceID(),
+				pb.PieceAction_PUT,
+				serialNumber,
+				24*time.Hour,
+				24*time.Hour,
+				int64(len(data)),
+			))
+			require.NoError(t, err)
+
+			uploader, err := client.Upload(ctx, orderLimit)
+			if err != nil {
+				return nil, err
+			}
+			_, err = uploader.Write(data)
+			return uploader, err
+		}
+
+		first, err := upload()
+		require.NoError(t, err)
+		for node.Storage2.Endpoint.LiveRequests() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+
+		// the second upload is rejected while the first one is in progress
+		second, err := upload()
+		if err == nil {
+			_, err = second.Commit()
+		}
+		require.Error(t, err)
+		require.True(t, piecestore.IsBusy(err), err)
+
+		_, err = first.Commit()
+		require.NoError(t, err)
+
+		third, err := upload()
+		require.NoError(t, err)
+		_, err = third.Commit()
+		require.NoError(t, err)
+	})
+}
+
+func TestMaxTransferRate(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 1, UplinkCount: 1,
+		Reconfigure: testplanet.Reconfigure{
+			StorageNode: func(index int, config *storagenode.Config) {
+				config.Storage2.MaxTransferRate = 100 * memory.KiB
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite, node := planet.Satellites[0], planet.StorageNodes[0]
+
+		client, err := planet.Uplinks[0].DialPiecestore(ctx, node)
+		require.NoError(t, err)
+		defer ctx.Check(client.Close)
+
+		data := make([]byte, 150*memory.KiB)
+		_, _ = rand.Read(data)
+
+		var serialNumber storj.SerialNumber
+		_, _ = rand.Read(serialNumber[:])
+		orderLimit, err := signing.SignOrderLimit(signing.SignerFromFullIdentity(satellite.Identity), GenerateOrderLimit(
+			t,
+			satellite.ID(),
+			planet.Uplinks[0].ID(),
+			node.ID(),
+			storj.NewPieceID(),
+			pb.PieceAction_PUT,
+			serialNumber,
+			24*time.Hour,
+			24*time.Hour,
+			int64(len(data)),
+		))
+		require.NoError(t, err)
+
+		// the first 100KiB are the burst, the rest takes half a second
+		start := time.Now()
+		uploader, err := client.Upload(ctx, orderLimit)
+		require.NoError(t, err)
+		_, err = uploader.Write(data)
+		require.NoError(t, err)
+		_, err = uploader.Commit()
+		require.NoError(t, err)
+		require.True(t, time.Since(start) >= 400*time.Millisecond, time.Since(start))
+	})
+}
+
+func TestUploadDiskFailing(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("smartctl is faked with a shell script")
+	}
+
+	var smartctl string
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 1, UplinkCount: 1,
+		Reconfigure: testplanet.Reconfigure{
+			StorageNode: func(index int, config *storagenode.Config) {
+				smartctl = filepath.Join(config.Kademlia.DBPath, "smartctl")
+				config.DiskHealth.SmartctlPath = smartctl
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite, node := planet.Satellites[0], planet.StorageNodes[0]
+
+		script := "#!/bin/sh\necho 'SMART overall-health self-assessment test result: FAILED!'\n"
+		require.NoError(t, ioutil.WriteFile(smartctl, []byte(script), 0700))
+		require.True(t, node.Storage2.Health.Check(ctx).Failing)
+
+		client, err := planet.Uplinks[0].DialPiecestore(ctx, node)
+		require.NoError(t, err)
+		defer ctx.Check(client.Close)
+
+		orderLimit, err := signing.SignOrderLimit(signing.SignerFromFullIdentity(satellite.Identity), GenerateOrderLimit(
+			t,
+			satellite.ID(),
+			planet.Uplinks[0].ID(),
+			node.ID(),
+			storj.NewPieceID(),
+			pb.PieceAction_PUT,
+			storj.SerialNumber{1},
+			24*time.Hour,
+			24*time.Hour,
+			int64(memory.KiB),
+		))
+		require.NoError(t, err)
+
+		// the upload is refused while the disk is failing
+		uploader, err := client.Upload(ctx, orderLimit)
+		if err == nil {
+			_, err = uploader.Write(make([]byte, memory.KiB))
+			if err == nil {
+				_, err = uploader.Commit()
+			}
+		}
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "disk is failing")
+	})
+}
+
+func TestUploadReadOnly(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 1, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite, node := planet.Satellites[0], planet.StorageNodes[0]
+
+		client, err := planet.Uplinks[0].DialPiecestore(ctx, node)
+		require.NoError(t, err)
+		defer ctx.Check(client.Close)
+
+		expectedData := make([]byte, 10*memory.KiB)
+		_, _ = rand.Read(expectedData)
+
+		signer := signing.SignerFromFullIdentity(satellite.Identity)
+		signedLimit := func(pieceID storj.PieceID, action pb.PieceAction) *pb.OrderLimit2 {
+			var serialNumber storj.SerialNumber
+			_, _ = rand.Read(serialNumber[:])
+
+			orderLimit, err := signing.SignOrderLimit(signer, GenerateOrderLimit(
+				t,
+				satellite.ID(),
+				planet.Uplinks[0].ID(),
+				node.ID(),
+				pieceID,
+				action,
+				serialNumber,
+				24*time.Hour,
+				24*time.Hour,
+				int64(len(expectedData)),
+			))
+			require.NoError(t, err)
+			return orderLimit
+		}
+
+		upload := func(pieceID storj.PieceID) error {
+			uploader, err := client.Upload(ctx, signedLimit(pieceID, pb.PieceAction_PUT))
+			if err != nil {
+				return err
+			}
+			if _, err := uploader.Write(expectedData); err != nil {
+				return err
+			}
+			_, err = uploader.Commit()
+			return err
+		}
+
+		require.NoError(t, upload(storj.PieceID{1}))
+
+		// the uploads are refused while the node is read-only
+		node.Storage2.Endpoint.SetReadOnly(true)
+		err = upload(storj.PieceID{2})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "read-only")
+
+		// the downloads are still served
+		downloader, err := client.Download(ctx, signedLimit(storj.PieceID{1}, pb.PieceAction_GET), 0, int64(len(expectedData)))
+		require.NoError(t, err)
+		data := make([]byte, len(expectedData))
+		_, err = io.ReadFull(downloader, data)
+		require.NoError(t, err)
+		require.NoError(t, downloader.Close())
+		require.Equal(t, expectedData, data)
+
+		node.Storage2.Endpoint.SetReadOnly(false)
+		require.NoError(t, upload(storj.PieceID{2}))
+	})
+}
+
+func TestRestoreTrash(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 1, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite, node := planet.Satellites[0], planet.StorageNodes[0]
+
+		client, err := planet.Uplinks[0].DialPiecestore(ctx, node)
+		require.NoError(t, err)
+		defer ctx.Check(client.Close)
+
+		data := make([]byte, 10*memory.KiB)
+		_, _ = rand.Read(data)
+
+		orderLimit, err := signing.SignOrderLimit(signing.SignerFromFullIdentity(satellite.Identity), GenerateOrderLimit(
+			t,
+			satellite.ID(),
+			planet.Uplinks[0].ID(),
+			node.ID(),
+			storj.PieceID{1},
+			pb.PieceAction_PUT,
+			storj.SerialNumber{1},
+			24*time.Hour,
+			24*time.Hour,
+			int64(len(data)),
+		))
+		require.NoError(t, err)
+
+		uploader, err := client.Upload(ctx, orderLimit)
+		require.NoError(t, err)
+		_, err = uploader.Write(data)
+		require.NoError(t, err)
+		_, err = uploader.Commit()
+		require.NoError(t, err)
+
+		// delete the piece as the deletion queue does
+		require.NoError(t, node.DB.PieceInfo().Delete(ctx, satellite.ID(), storj.PieceID{1}))
+		require.NoError(t, node.Storage2.Store.Trash(ctx, satellite.ID(), storj.PieceID{1}))
+
+		local := node.Local()
+
+		// only the satellites restore their trash
+		conn, err := planet.Uplinks[0].Transport.DialNode(ctx, &local)
+		require.NoError(t, err)
+		_, err = pb.NewPiecestoreClient(conn).RestoreTrash(ctx, &pb.RestoreTrashRequest{})
+		require.Error(t, err)
+		require.NoError(t, conn.Close())
+
+		conn, err = satellite.Transport.DialNode(ctx, &local)
+		require.NoError(t, err)
+		defer ctx.Check(conn.Close)
+		resp, err := pb.NewPiecestoreClient(conn).RestoreTrash(ctx, &pb.RestoreTrashRequest{})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), resp.RestoredPieces)
+
+		info, err := node.DB.PieceInfo().Get(ctx, satellite.ID(), storj.PieceID{1})
+		require.NoError(t, err)
+		require.Equal(t, int64(len(data)), info.PieceSize)
+	})
+}
@@ -0,0 +1,53 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package piecestore
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/pkcrypto"
+	"storj.io/storj/storagenode/pieces"
+)
+
+// ErrCorrupted is returned when the data of a piece doesn't match the hash signed by the uplink.
+var ErrCorrupted = errs.Class("corrupted piece")
+
+// VerifyPieceData verifies that the data of the piece matches the hash signed
+// by the uplink on upload, flagging the piece as corrupted when it doesn't.
+func (endpoint *Endpoint) VerifyPieceData(ctx context.Context, limit *pb.OrderLimit2, reader *pieces.Reader) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	info, err := endpoint.pieceinfo.Get(ctx, limit.SatelliteId, limit.PieceId)
+	if err != nil {
+		return ErrInternal.Wrap(err)
+	}
+
+	hash := pkcrypto.NewHash()
+	if _, err := io.Copy(hash, io.NewSectionReader(reader, 0, reader.Size())); err != nil {
+		return ErrInternal.Wrap(err)
+	}
+
+	if bytes.Equal(hash.Sum(nil), info.UplinkPieceHash.GetHash()) {
+		return nil
+	}
+
+	mon.Meter("corrupted_pieces").Mark(1)
+	endpoint.log.Error("piece is corrupted",
+		zap.Stringer("Satellite ID", limit.SatelliteId),
+		zap.Stringer("Piece ID", limit.PieceId))
+
+	err = endpoint.pieceinfo.MarkCorrupted(ctx, limit.SatelliteId, limit.PieceId, time.Now())
+	if err != nil {
+		endpoint.log.Error("flagging corrupted piece", zap.Stringer("Piece ID", limit.PieceId), zap.Error(err))
+	}
+
+	return ErrCorrupted.New("%v", limit.PieceId)
+}
@@ -0,0 +1,114 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package piecestore
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/internal/memory"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+)
+
+// ErrSatelliteLimit is returned when a satellite exceeds the bandwidth or disk
+// space it's allowed to use.
+var ErrSatelliteLimit = errs.Class("satellite limit exceeded")
+
+// SatelliteLimit caps the bandwidth and the disk space a satellite may use,
+// zero is unlimited.
+type SatelliteLimit struct {
+	// Bandwidth is the bandwidth per month
+	Bandwidth memory.Size
+	// Disk is the space used by the pieces
+	Disk memory.Size
+}
+
+// ParseSatelliteLimits parses comma-separated satellite limits, each as
+// <satellite id>:<bandwidth per month>:<disk space>.
+func ParseSatelliteLimits(s string) (map[storj.NodeID]SatelliteLimit, error) {
+	limits := map[storj.NodeID]SatelliteLimit{}
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 {
+			return nil, errs.New("invalid satellite limit %q, expected <satellite id>:<bandwidth>:<disk>", entry)
+		}
+
+		satelliteID, err := storj.NodeIDFromString(parts[0])
+		if err != nil {
+			return nil, errs.New("invalid satellite id in satellite limit %q: %v", entry, err)
+		}
+		if _, ok := limits[satelliteID]; ok {
+			return nil, errs.New("duplicate satellite limit for %v", satelliteID)
+		}
+
+		var limit SatelliteLimit
+		if err := limit.Bandwidth.Set(parts[1]); err != nil {
+			return nil, errs.New("invalid bandwidth in satellite limit %q: %v", entry, err)
+		}
+		if err := limit.Disk.Set(parts[2]); err != nil {
+			return nil, errs.New("invalid disk space in satellite limit %q: %v", entry, err)
+		}
+		if limit.Bandwidth < 0 || limit.Disk < 0 {
+			return nil, errs.New("negative satellite limit %q", entry)
+		}
+		limits[satelliteID] = limit
+	}
+	return limits, nil
+}
+
+// VerifySatelliteLimit verifies that the order limit doesn't make its
+// satellite exceed its configured bandwidth and disk space.
+func (endpoint *Endpoint) VerifySatelliteLimit(ctx context.Context, limit *pb.OrderLimit2) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	satelliteLimit, ok := endpoint.satelliteLimits[limit.SatelliteId]
+	if !ok {
+		return nil
+	}
+
+	if satelliteLimit.Bandwidth > 0 {
+		usages, err := endpoint.usage.SummaryBySatellite(ctx, beginningOfMonth(time.Now()), time.Now())
+		if err != nil {
+			return ErrInternal.Wrap(err)
+		}
+		var used int64
+		if usage, ok := usages[limit.SatelliteId]; ok {
+			used = usage.Total()
+		}
+		if used+limit.Limit > satelliteLimit.Bandwidth.Int64() {
+			mon.Meter("satellite_bandwidth_limit_exceeded").Mark(1)
+			return ErrSatelliteLimit.New("bandwidth of satellite %v: used %v, allowed %v", limit.SatelliteId, memory.Size(used), satelliteLimit.Bandwidth)
+		}
+	}
+
+	isPut := limit.Action == pb.PieceAction_PUT || limit.Action == pb.PieceAction_PUT_REPAIR
+	if isPut && satelliteLimit.Disk > 0 {
+		totals, err := endpoint.spaceUsed.SpaceUsedBySatellite(ctx)
+		if err != nil {
+			return ErrInternal.Wrap(err)
+		}
+		used := totals[limit.SatelliteId]
+		if used+limit.Limit > satelliteLimit.Disk.Int64() {
+			mon.Meter("satellite_disk_limit_exceeded").Mark(1)
+			return ErrSatelliteLimit.New("disk space of satellite %v: used %v, allowed %v", limit.SatelliteId, memory.Size(used), satelliteLimit.Disk)
+		}
+	}
+
+	return nil
+}
+
+// beginningOfMonth returns the start of the month of now.
+func beginningOfMonth(now time.Time) time.Time {
+	y, m, _ := now.Date()
+	return time.Date(y, m, 1, 0, 0, 0, 0, now.Location())
+}
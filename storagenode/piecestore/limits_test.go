@@ -0,0 +1,122 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package piecestore_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/memory"
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/pkg/auth/signing"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/satellite"
+	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/piecestore"
+)
+
+func TestParseSatelliteLimits(t *testing.T) {
+	satellite := testplanet.MustPregeneratedSignedIdentity(0).ID
+
+	limits, err := piecestore.ParseSatelliteLimits("")
+	require.NoError(t, err)
+	require.Empty(t, limits)
+
+	limits, err = piecestore.ParseSatelliteLimits(satellite.String() + ":2TB:0, ")
+	require.NoError(t, err)
+	require.Equal(t, map[storj.NodeID]piecestore.SatelliteLimit{
+		satellite: {Bandwidth: 2 * memory.TB},
+	}, limits)
+
+	for _, invalid := range []string{
+		satellite.String(),
+		satellite.String() + ":1TB",
+		"invalid:1TB:1TB",
+		satellite.String() + ":1XB:1TB",
+		satellite.String() + ":-1TB:1TB",
+		satellite.String() + ":1TB:1TB," + satellite.String() + ":2TB:2TB",
+	} {
+		_, err := piecestore.ParseSatelliteLimits(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestSatelliteLimits(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	// the first satellite is limited on disk space, the second on bandwidth,
+	// the identities of the satellites follow the identity of the bootstrap
+	diskLimited := testplanet.MustPregeneratedSignedIdentity(1).ID
+	bandwidthLimited := testplanet.MustPregeneratedSignedIdentity(2).ID
+
+	planet, err := testplanet.NewCustom(zaptest.NewLogger(t), testplanet.Config{
+		SatelliteCount: 2, StorageNodeCount: 1, UplinkCount: 1,
+		Reconfigure: testplanet.Reconfigure{
+			StorageNode: func(index int, config *storagenode.Config) {
+				config.Storage2.SatelliteLimits = fmt.Sprintf("%s:0:80KiB,%s:120KiB:0", diskLimited, bandwidthLimited)
+			},
+		},
+	})
+	require.NoError(t, err)
+	defer ctx.Check(planet.Shutdown)
+
+	planet.Start(ctx)
+
+	client, err := planet.Uplinks[0].DialPiecestore(ctx, planet.StorageNodes[0])
+	require.NoError(t, err)
+	defer ctx.Check(client.Close)
+
+	upload := func(satellite *satellite.Peer) error {
+		data := make([]byte, 50*memory.KiB)
+		_, _ = rand.Read(data)
+
+		var serialNumber storj.SerialNumber
+		_, _ = rand.Read(serialNumber[:])
+
+		orderLimit := GenerateOrderLimit(
+			t,
+			satellite.ID(),
+			planet.Uplinks[0].ID(),
+			planet.StorageNodes[0].ID(),
+			storj.NewPieceID(),
+			pb.PieceAction_PUT,
+			serialNumber,
+			24*time.Hour,
+			24*time.Hour,
+			int64(len(data)),
+		)
+		orderLimit, err := signing.SignOrderLimit(signing.SignerFromFullIdentity(satellite.Identity), orderLimit)
+		require.NoError(t, err)
+
+		uploader, err := client.Upload(ctx, orderLimit)
+		require.NoError(t, err)
+		_, err = uploader.Write(data)
+		if err != nil {
+			return err
+		}
+		_, err = uploader.Commit()
+		return err
+	}
+
+	require.Equal(t, diskLimited, planet.Satellites[0].ID())
+	require.NoError(t, upload(planet.Satellites[0]))
+	err = upload(planet.Satellites[0])
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "disk space of satellite")
+
+	require.Equal(t, bandwidthLimited, planet.Satellites[1].ID())
+	require.NoError(t, upload(planet.Satellites[1]))
+	require.NoError(t, upload(planet.Satellites[1]))
+	err = upload(planet.Satellites[1])
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "bandwidth of satellite")
+}
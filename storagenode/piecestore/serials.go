@@ -16,12 +16,13 @@ type SerialNumberFn func(satelliteID storj.NodeID, serialNumber storj.SerialNumb
 // UsedSerials is a persistent store for serial numbers.
 // TODO: maybe this should be in orders.UsedSerials
 type UsedSerials interface {
-	// Add adds a serial to the database.
+	// Add adds a serial to the database, it fails with orders.ErrSerialAlreadyExists
+	// when the serial was already added.
 	Add(ctx context.Context, satelliteID storj.NodeID, serialNumber storj.SerialNumber, expiration time.Time) error
 	// DeleteExpired deletes expired serial numbers
 	DeleteExpired(ctx context.Context, now time.Time) error
 
 	// IterateAll iterates all serials.
-	// Note, this will lock the database and should only be used during startup.
+	// Note, fn must not use the database and this should only be used during startup.
 	IterateAll(ctx context.Context, fn SerialNumberFn) error
 }
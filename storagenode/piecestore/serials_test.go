@@ -16,6 +16,7 @@ import (
 	"storj.io/storj/internal/testplanet"
 	"storj.io/storj/pkg/storj"
 	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/orders"
 	"storj.io/storj/storagenode/storagenodedb/storagenodedbtest"
 )
 
@@ -68,7 +69,7 @@ func TestUsedSerials(t *testing.T) {
 		for _, serial := range serialNumbers {
 			expirationDelta := time.Duration(rand.Intn(10)-5) * time.Hour
 			err = usedSerials.Add(ctx, serial.SatelliteID, serial.SerialNumber, serial.Expiration.Add(expirationDelta))
-			assert.Error(t, err)
+			assert.True(t, orders.ErrSerialAlreadyExists.Has(err), err)
 		}
 
 		// ensure we can list all of them
@@ -0,0 +1,52 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package piecestore
+
+import (
+	"context"
+	"math"
+
+	"golang.org/x/time/rate"
+
+	"storj.io/storj/internal/memory"
+)
+
+// shaper limits the rate of the data of a single upload or download, so that
+// a few transfers don't saturate the link of the storage node.
+type shaper struct {
+	limiter *rate.Limiter
+}
+
+// newShaper creates a shaper of bytesPerSecond, nil when unlimited.
+func newShaper(bytesPerSecond memory.Size) *shaper {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+	// NB: the burst is one second of data, so that a transfer can start at once
+	burst := bytesPerSecond.Int64()
+	if burst > math.MaxInt32 {
+		burst = math.MaxInt32
+	}
+	return &shaper{
+		limiter: rate.NewLimiter(rate.Limit(bytesPerSecond.Int64()), int(burst)),
+	}
+}
+
+// wait waits until size bytes can be transferred.
+func (shaper *shaper) wait(ctx context.Context, size int64) error {
+	if shaper == nil {
+		return nil
+	}
+	for size > 0 {
+		step := size
+		if burst := int64(shaper.limiter.Burst()); step > burst {
+			step = burst
+		}
+		if err := shaper.limiter.WaitN(ctx, int(step)); err != nil {
+			return err
+		}
+		size -= step
+	}
+	return nil
+}
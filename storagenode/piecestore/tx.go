@@ -0,0 +1,27 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package piecestore
+
+import (
+	"context"
+
+	"storj.io/storj/storagenode/bandwidth"
+	"storj.io/storj/storagenode/orders"
+	"storj.io/storj/storagenode/pieces"
+)
+
+// Tx gives access to the tables written when a piece upload completes, all
+// within one transaction.
+type Tx interface {
+	PieceInfo() pieces.DB
+	Orders() orders.DB
+	Bandwidth() bandwidth.DB
+}
+
+// TxDB runs several writes atomically.
+type TxDB interface {
+	// WithTx runs fn in a transaction, which is committed when fn returns nil
+	// and rolled back otherwise.
+	WithTx(ctx context.Context, fn func(ctx context.Context, tx Tx) error) error
+}
@@ -15,6 +15,7 @@ import (
 	"storj.io/storj/pkg/auth/signing"
 	"storj.io/storj/pkg/identity"
 	"storj.io/storj/pkg/pb"
+	"storj.io/storj/storagenode/orders"
 )
 
 var (
@@ -75,7 +76,10 @@ func (endpoint *Endpoint) VerifyOrderLimit(ctx context.Context, limit *pb.OrderL
 		return ErrInternal.Wrap(err)
 	}
 	if err := endpoint.usedSerials.Add(ctx, limit.SatelliteId, limit.SerialNumber, serialExpiration); err != nil {
-		return ErrVerifyDuplicateRequest.Wrap(err)
+		if orders.ErrSerialAlreadyExists.Has(err) {
+			return ErrVerifyDuplicateRequest.Wrap(err)
+		}
+		return ErrInternal.Wrap(err)
 	}
 
 	return nil
@@ -0,0 +1,79 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package reputation_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/reputation"
+	"storj.io/storj/storagenode/storagenodedb/storagenodedbtest"
+)
+
+func TestDB(t *testing.T) {
+	storagenodedbtest.Run(t, func(t *testing.T, db storagenode.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		reputationdb := db.Reputation()
+
+		satellite0 := testplanet.MustPregeneratedSignedIdentity(0).ID
+		satellite1 := testplanet.MustPregeneratedSignedIdentity(1).ID
+
+		stats, err := reputationdb.Get(ctx, satellite0)
+		require.NoError(t, err)
+		require.Nil(t, stats)
+
+		all, err := reputationdb.All(ctx)
+		require.NoError(t, err)
+		require.Empty(t, all)
+
+		now := time.Now().UTC().Truncate(time.Second)
+		expected := reputation.Stats{
+			SatelliteID:        satellite0,
+			AuditCount:         10,
+			AuditSuccessCount:  9,
+			AuditSuccessRatio:  0.9,
+			UptimeCount:        4,
+			UptimeSuccessCount: 4,
+			UptimeRatio:        1,
+			VettingAuditCount:  50,
+			VettingUptimeCount: 100,
+			UpdatedAt:          now,
+		}
+		require.NoError(t, reputationdb.Store(ctx, expected))
+
+		// storing again replaces the reputation of the satellite
+		expected.AuditCount, expected.AuditSuccessCount, expected.AuditSuccessRatio = 20, 19, 0.95
+		expected.UpdatedAt = now.Add(time.Hour)
+		disqualified := now.Add(time.Minute)
+		expected.Disqualified = &disqualified
+		vettedAt := now.Add(time.Second)
+		expected.VettedAt = &vettedAt
+		require.NoError(t, reputationdb.Store(ctx, expected))
+		require.NoError(t, reputationdb.Store(ctx, reputation.Stats{SatelliteID: satellite1, UpdatedAt: now}))
+
+		stats, err = reputationdb.Get(ctx, satellite0)
+		require.NoError(t, err)
+		require.NotNil(t, stats)
+		require.True(t, expected.UpdatedAt.Equal(stats.UpdatedAt))
+		stats.UpdatedAt = expected.UpdatedAt
+		require.NotNil(t, stats.Disqualified)
+		require.True(t, expected.Disqualified.Equal(*stats.Disqualified))
+		stats.Disqualified = expected.Disqualified
+		require.NotNil(t, stats.VettedAt)
+		require.True(t, expected.VettedAt.Equal(*stats.VettedAt))
+		stats.VettedAt = expected.VettedAt
+		require.Equal(t, expected, *stats)
+
+		all, err = reputationdb.All(ctx)
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+	})
+}
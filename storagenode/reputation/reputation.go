@@ -0,0 +1,49 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package reputation
+
+import (
+	"context"
+	"time"
+
+	"storj.io/storj/pkg/storj"
+)
+
+// Stats are the audit and uptime reputation of the node as reported by a satellite.
+type Stats struct {
+	SatelliteID storj.NodeID `json:"satelliteID"`
+
+	AuditCount        int64   `json:"auditCount"`
+	AuditSuccessCount int64   `json:"auditSuccessCount"`
+	AuditSuccessRatio float64 `json:"auditSuccessRatio"`
+
+	UptimeCount        int64   `json:"uptimeCount"`
+	UptimeSuccessCount int64   `json:"uptimeSuccessCount"`
+	UptimeRatio        float64 `json:"uptimeRatio"`
+
+	// Disqualified is when the satellite disqualified the node, nil when
+	// the node isn't disqualified.
+	Disqualified *time.Time `json:"disqualified"`
+
+	// VettedAt is when the node passed vetting, nil when it didn't yet.
+	VettedAt *time.Time `json:"vettedAt"`
+	// VettingAuditCount and VettingUptimeCount are the audits and uptime
+	// checks the node needs to pass vetting.
+	VettingAuditCount  int64 `json:"vettingAuditCount"`
+	VettingUptimeCount int64 `json:"vettingUptimeCount"`
+
+	// UpdatedAt is when the satellite reported the reputation.
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// DB stores the latest reputation reported by each satellite.
+type DB interface {
+	// Store replaces the reputation reported by the satellite of stats.
+	Store(ctx context.Context, stats Stats) error
+	// Get returns the reputation reported by the satellite, it returns
+	// nil when the satellite didn't report any.
+	Get(ctx context.Context, satelliteID storj.NodeID) (*Stats, error)
+	// All returns the reputation reported by each satellite.
+	All(ctx context.Context) ([]Stats, error)
+}
@@ -0,0 +1,135 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package reputation
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/internal/sync2"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/transport"
+	"storj.io/storj/storagenode/trust"
+)
+
+var (
+	// Error is the default error class for the reputation cache.
+	Error = errs.Class("reputation")
+	mon   = monkit.Package()
+)
+
+// Config defines parameters for polling the reputation from the satellites.
+type Config struct {
+	Interval time.Duration `help:"how frequently the reputation is fetched from the trusted satellites" default:"4h0m0s"`
+}
+
+// Service polls the trusted satellites for the reputation of the node and
+// stores it locally.
+type Service struct {
+	log *zap.Logger
+	db  DB
+
+	transport transport.Client
+	trust     *trust.Pool
+
+	Loop sync2.Cycle
+}
+
+// NewService creates a new reputation polling service.
+func NewService(log *zap.Logger, transport transport.Client, trust *trust.Pool, db DB, config Config) *Service {
+	return &Service{
+		log:       log,
+		db:        db,
+		transport: transport,
+		trust:     trust,
+
+		Loop: *sync2.NewCycle(config.Interval),
+	}
+}
+
+// Run fetches the reputation from the trusted satellites on every interval.
+func (service *Service) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return service.Loop.Run(ctx, func(ctx context.Context) error {
+		service.Poll(ctx)
+		return nil
+	})
+}
+
+// Poll fetches and stores the reputation from every trusted satellite. The
+// satellites which can't be reached keep their previous reputation.
+func (service *Service) Poll(ctx context.Context) {
+	defer mon.Task()(&ctx)(nil)
+
+	for _, satelliteID := range service.trust.GetSatellites(ctx) {
+		if err := service.fetch(ctx, satelliteID); err != nil {
+			service.log.Warn("failed to fetch reputation", zap.Stringer("satellite", satelliteID), zap.Error(err))
+		}
+	}
+}
+
+// fetch fetches and stores the reputation from a single satellite.
+func (service *Service) fetch(ctx context.Context, satelliteID storj.NodeID) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	satellite, err := service.trust.FindSatellite(ctx, satelliteID)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	conn, err := service.transport.DialNode(ctx, &satellite)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() {
+		err = errs.Combine(err, Error.Wrap(conn.Close()))
+	}()
+
+	resp, err := pb.NewReputationClient(conn).Stats(ctx, &pb.ReputationStatsRequest{})
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	stats := Stats{
+		SatelliteID:        satelliteID,
+		AuditCount:         resp.AuditCount,
+		AuditSuccessCount:  resp.AuditSuccessCount,
+		AuditSuccessRatio:  resp.AuditSuccessRatio,
+		UptimeCount:        resp.UptimeCount,
+		UptimeSuccessCount: resp.UptimeSuccessCount,
+		UptimeRatio:        resp.UptimeRatio,
+		VettingAuditCount:  resp.VettingAuditCount,
+		VettingUptimeCount: resp.VettingUptimeCount,
+		UpdatedAt:          time.Now().UTC(),
+	}
+	if resp.Disqualified != nil {
+		disqualified, err := ptypes.Timestamp(resp.Disqualified)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		stats.Disqualified = &disqualified
+	}
+	if resp.Vetted != nil {
+		vettedAt, err := ptypes.Timestamp(resp.Vetted)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		stats.VettedAt = &vettedAt
+	}
+
+	return service.db.Store(ctx, stats)
+}
+
+// Close stops the reputation polling service.
+func (service *Service) Close() error {
+	service.Loop.Close()
+	return nil
+}
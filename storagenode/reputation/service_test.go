@@ -0,0 +1,76 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package reputation_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/pkg/overlay"
+)
+
+func TestPoll(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 1, UplinkCount: 0,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		node := planet.StorageNodes[0]
+
+		_, err := satellite.Overlay.Service.UpdateStats(ctx, &overlay.UpdateRequest{
+			NodeID:       node.ID(),
+			AuditSuccess: false,
+			IsUp:         true,
+		})
+		require.NoError(t, err)
+		expected, err := satellite.Overlay.Service.GetStats(ctx, node.ID())
+		require.NoError(t, err)
+
+		node.Storage2.Reputation.Poll(ctx)
+
+		stats, err := node.DB.Reputation().Get(ctx, satellite.ID())
+		require.NoError(t, err)
+		require.NotNil(t, stats)
+		assert.Equal(t, expected.AuditCount, stats.AuditCount)
+		assert.Equal(t, expected.AuditSuccessCount, stats.AuditSuccessCount)
+		assert.Equal(t, expected.AuditSuccessRatio, stats.AuditSuccessRatio)
+		// NB: the connection of the node to the satellite counts as uptime
+		assert.True(t, stats.UptimeCount >= expected.UptimeCount)
+		assert.True(t, stats.UptimeSuccessCount >= expected.UptimeSuccessCount)
+		assert.False(t, stats.UpdatedAt.IsZero())
+	})
+}
+
+func TestPollDisqualified(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 1, UplinkCount: 0,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		node := planet.StorageNodes[0]
+
+		var disqualified *time.Time
+		for i := 0; i < 100 && disqualified == nil; i++ {
+			stats, err := satellite.Overlay.Service.UpdateStats(ctx, &overlay.UpdateRequest{
+				NodeID:       node.ID(),
+				AuditSuccess: false,
+				IsUp:         true,
+			})
+			require.NoError(t, err)
+			disqualified = stats.Disqualified
+		}
+		require.NotNil(t, disqualified)
+
+		node.Storage2.Reputation.Poll(ctx)
+
+		stats, err := node.DB.Reputation().Get(ctx, satellite.ID())
+		require.NoError(t, err)
+		require.NotNil(t, stats)
+		require.NotNil(t, stats.Disqualified)
+		assert.True(t, disqualified.Equal(*stats.Disqualified))
+	})
+}
@@ -0,0 +1,200 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package scrubber
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/internal/sync2"
+	"storj.io/storj/pkg/pkcrypto"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storagenode/pieces"
+)
+
+var (
+	// Error is the default error class for the scrubber.
+	Error = errs.Class("piece scrubber")
+	mon   = monkit.Package()
+)
+
+// Config defines parameters for the storage node scrubber.
+type Config struct {
+	Interval       time.Duration `help:"how frequently a batch of pieces is verified by the scrubber" default:"10m0s"`
+	PiecesPerCycle int           `help:"maximum number of pieces verified on every interval, 0 disables the scrubber" default:"100"`
+	QuarantineDir  string        `help:"directory where the corrupted pieces are moved, defaults to quarantine in the storage path" default:""`
+}
+
+// Service verifies the data of the stored pieces against the hashes signed by
+// the uplinks in the background, a few pieces at a time. The corrupted pieces
+// are flagged and moved into the quarantine directory.
+type Service struct {
+	log           *zap.Logger
+	config        Config
+	pieces        *pieces.Store
+	pieceinfos    pieces.DB
+	quarantineDir string
+
+	// the last verified piece, the next cycle continues after it
+	lastSatellite storj.NodeID
+	lastPiece     storj.PieceID
+
+	Loop sync2.Cycle
+}
+
+// NewService creates a new scrubber service, which moves the corrupted pieces into quarantineDir.
+func NewService(log *zap.Logger, pieces *pieces.Store, pieceinfos pieces.DB, quarantineDir string, config Config) *Service {
+	return &Service{
+		log:           log,
+		config:        config,
+		pieces:        pieces,
+		pieceinfos:    pieceinfos,
+		quarantineDir: quarantineDir,
+
+		Loop: *sync2.NewCycle(config.Interval),
+	}
+}
+
+// Run verifies a batch of pieces on every interval.
+func (service *Service) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return service.Loop.Run(ctx, func(ctx context.Context) error {
+		if service.config.PiecesPerCycle <= 0 {
+			return nil
+		}
+		if err := service.Scrub(ctx); err != nil {
+			service.log.Error("scrubbing pieces", zap.Error(err))
+		}
+		return nil
+	})
+}
+
+// Close stops the scrubber.
+func (service *Service) Close() error {
+	service.Loop.Close()
+	return nil
+}
+
+// Scrub verifies the next batch of pieces, starting again from the first
+// piece after all pieces were verified.
+func (service *Service) Scrub(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	infos, err := service.pieceinfos.GetScrubInfos(ctx, service.lastSatellite, service.lastPiece, service.config.PiecesPerCycle)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	var checked, corrupted, failed int64
+	defer func() {
+		mon.IntVal("scrubber_pieces_checked").Observe(checked)
+		mon.IntVal("scrubber_corrupted_pieces").Observe(corrupted)
+		mon.IntVal("scrubber_failed_pieces").Observe(failed)
+	}()
+
+	for _, info := range infos {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// NB: a piece that can't be verified mustn't stop the scrubber at
+		// the same position in every cycle, it is checked again in the next pass
+		quarantined, err := service.verify(ctx, info)
+		switch {
+		case err != nil && ctx.Err() != nil:
+			return ctx.Err()
+		case err != nil:
+			service.log.Error("failed to scrub piece", zap.Stringer("Piece ID", info.PieceID), zap.Stringer("Satellite ID", info.SatelliteID), zap.Error(err))
+			failed++
+		default:
+			checked++
+			if quarantined {
+				corrupted++
+			}
+		}
+
+		service.lastSatellite, service.lastPiece = info.SatelliteID, info.PieceID
+	}
+
+	if len(infos) < service.config.PiecesPerCycle {
+		service.lastSatellite, service.lastPiece = storj.NodeID{}, storj.PieceID{}
+	}
+	return nil
+}
+
+// verify hashes the data of the piece, quarantining it when it doesn't match
+// the hash signed by the uplink. The pieces already flagged as corrupted are
+// quarantined without hashing them again.
+func (service *Service) verify(ctx context.Context, info pieces.ScrubInfo) (quarantined bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	reader, err := service.pieces.Reader(ctx, info.SatelliteID, info.PieceID)
+	if err != nil {
+		if os.IsNotExist(errs.Unwrap(err)) {
+			if !info.Corrupted {
+				// NB: the piece may have been deleted since the page was listed
+				service.log.Debug("piece to scrub is missing", zap.Stringer("Piece ID", info.PieceID))
+			}
+			return false, nil
+		}
+		return false, err
+	}
+
+	if !info.Corrupted {
+		hash := pkcrypto.NewHash()
+		if _, err := io.Copy(hash, io.NewSectionReader(reader, 0, reader.Size())); err != nil {
+			return false, errs.Combine(err, reader.Close())
+		}
+		if bytes.Equal(hash.Sum(nil), info.PieceHash) {
+			return false, reader.Close()
+		}
+
+		mon.Meter("corrupted_pieces").Mark(1)
+		service.log.Error("piece is corrupted",
+			zap.Stringer("Satellite ID", info.SatelliteID),
+			zap.Stringer("Piece ID", info.PieceID))
+
+		if err := service.pieceinfos.MarkCorrupted(ctx, info.SatelliteID, info.PieceID, time.Now()); err != nil {
+			return false, errs.Combine(err, reader.Close())
+		}
+	}
+
+	err = service.quarantine(ctx, info, reader)
+	if err := errs.Combine(err, reader.Close()); err != nil {
+		return false, err
+	}
+
+	service.log.Info("quarantined corrupted piece",
+		zap.Stringer("Satellite ID", info.SatelliteID),
+		zap.Stringer("Piece ID", info.PieceID))
+
+	return true, service.pieces.Delete(ctx, info.SatelliteID, info.PieceID)
+}
+
+// quarantine copies the data of the corrupted piece into the quarantine
+// directory, where it is kept for inspection after it is deleted.
+func (service *Service) quarantine(ctx context.Context, info pieces.ScrubInfo, reader *pieces.Reader) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	dir := filepath.Join(service.quarantineDir, info.SatelliteID.String())
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	file, err := os.Create(filepath.Join(dir, info.PieceID.String()))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(file, io.NewSectionReader(reader, 0, reader.Size()))
+	return errs.Combine(err, file.Close())
+}
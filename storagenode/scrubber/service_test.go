@@ -0,0 +1,111 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package scrubber_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/pkg/auth/signing"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/pkcrypto"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/pieces"
+	"storj.io/storj/storagenode/scrubber"
+	"storj.io/storj/storagenode/storagenodedb/storagenodedbtest"
+)
+
+func TestScrub(t *testing.T) {
+	storagenodedbtest.Run(t, func(t *testing.T, db storagenode.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		pieceinfos := db.PieceInfo()
+		store := pieces.NewStore(zaptest.NewLogger(t), db.Pieces())
+
+		satellite := testplanet.MustPregeneratedSignedIdentity(0)
+		uplink := testplanet.MustPregeneratedSignedIdentity(1)
+
+		add := func(data, hashed []byte) storj.PieceID {
+			pieceID := storj.NewPieceID()
+
+			pieceHash, err := signing.SignPieceHash(
+				signing.SignerFromFullIdentity(uplink),
+				&pb.PieceHash{PieceId: pieceID, Hash: pkcrypto.SHA256Hash(hashed)})
+			require.NoError(t, err)
+
+			require.NoError(t, pieceinfos.Add(ctx, &pieces.Info{
+				SatelliteID:     satellite.ID,
+				PieceID:         pieceID,
+				PieceSize:       int64(len(data)),
+				PieceCreation:   time.Now(),
+				UplinkPieceHash: pieceHash,
+				Uplink:          uplink.PeerIdentity(),
+			}))
+
+			writer, err := store.Writer(ctx, satellite.ID, pieceID, -1)
+			require.NoError(t, err)
+			_, err = writer.Write(data)
+			require.NoError(t, err)
+			require.NoError(t, writer.Commit(&pb.PieceHeader{}))
+			return pieceID
+		}
+
+		var validIDs []storj.PieceID
+		for i := 0; i < 3; i++ {
+			validIDs = append(validIDs, add([]byte{1, 2, 3}, []byte{1, 2, 3}))
+		}
+		corruptedID := add([]byte{1, 2, 3}, []byte{3, 2, 1})
+
+		// a piece flagged as corrupted on download is quarantined without verifying it again
+		flaggedID := add([]byte{4, 5, 6}, []byte{4, 5, 6})
+		require.NoError(t, pieceinfos.MarkCorrupted(ctx, satellite.ID, flaggedID, time.Now()))
+
+		quarantineDir := ctx.Dir("quarantine")
+		service := scrubber.NewService(zaptest.NewLogger(t), store, pieceinfos, quarantineDir, scrubber.Config{
+			Interval:       time.Hour,
+			PiecesPerCycle: 2,
+		})
+
+		// every piece is verified after enough cycles
+		for i := 0; i < 3; i++ {
+			require.NoError(t, service.Scrub(ctx))
+		}
+
+		for _, pieceID := range []storj.PieceID{corruptedID, flaggedID} {
+			_, err := store.Reader(ctx, satellite.ID, pieceID)
+			require.Error(t, err)
+
+			data, err := ioutil.ReadFile(filepath.Join(quarantineDir, satellite.ID.String(), pieceID.String()))
+			require.NoError(t, err)
+			require.Len(t, data, 3)
+		}
+		for _, pieceID := range validIDs {
+			reader, err := store.Reader(ctx, satellite.ID, pieceID)
+			require.NoError(t, err)
+			require.NoError(t, reader.Close())
+		}
+
+		corrupted, err := pieceinfos.CountCorrupted(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), corrupted)
+
+		used, err := pieceinfos.SpaceUsed(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(3*len(validIDs)), used)
+
+		// scrubbing again starts over and skips the quarantined pieces
+		require.NoError(t, service.Scrub(ctx))
+		require.NoError(t, service.Scrub(ctx))
+		require.NoError(t, service.Scrub(ctx))
+	})
+}
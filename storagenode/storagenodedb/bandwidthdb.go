@@ -6,6 +6,7 @@ package storagenodedb
 import (
 	"context"
 	"database/sql"
+	"sort"
 	"time"
 
 	"github.com/zeebo/errs"
@@ -25,27 +26,25 @@ func (db *infodb) Bandwidth() bandwidth.DB { return &bandwidthdb{db} }
 
 // Add adds bandwidth usage to the table
 func (db *bandwidthdb) Add(ctx context.Context, satelliteID storj.NodeID, action pb.PieceAction, amount int64, created time.Time) error {
-	defer db.locked()()
-
-	_, err := db.db.Exec(`
-		INSERT INTO 
-			bandwidth_usage(satellite_id, action, amount, created_at)
-		VALUES(?, ?, ?, ?)`, satelliteID, action, amount, created)
+	_, err := db.execStatement(ctx, stmtAddBandwidth, satelliteID, action, amount, created.UTC())
 
 	return ErrInfo.Wrap(err)
 }
 
 // Summary returns summary of bandwidth usages
 func (db *bandwidthdb) Summary(ctx context.Context, from, to time.Time) (_ *bandwidth.Usage, err error) {
-	defer db.locked()()
-
 	usage := &bandwidth.Usage{}
 
-	rows, err := db.db.Query(`
-		SELECT action, sum(amount) 
-		FROM bandwidth_usage
-		WHERE ? <= created_at AND created_at <= ?
-		GROUP BY action`, from, to)
+	rows, err := db.conn().QueryContext(ctx, db.Rebind(`
+		SELECT action, sum(amount)
+		FROM (
+			SELECT action, amount FROM bandwidth_usage
+			WHERE ? <= created_at AND created_at <= ?
+			UNION ALL
+			SELECT action, amount FROM bandwidth_usage_rollups
+			WHERE ? <= interval_start AND interval_start <= ?
+		) usages
+		GROUP BY action`), from.UTC(), to.UTC(), rollupStart(from), to.UTC())
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return usage, nil
@@ -69,15 +68,18 @@ func (db *bandwidthdb) Summary(ctx context.Context, from, to time.Time) (_ *band
 
 // SummaryBySatellite returns summary of bandwidth usage grouping by satellite.
 func (db *bandwidthdb) SummaryBySatellite(ctx context.Context, from, to time.Time) (_ map[storj.NodeID]*bandwidth.Usage, err error) {
-	defer db.locked()()
-
 	entries := map[storj.NodeID]*bandwidth.Usage{}
 
-	rows, err := db.db.Query(`
-		SELECT satellite_id, action, sum(amount) 
-		FROM bandwidth_usage
-		WHERE ? <= created_at AND created_at <= ?
-		GROUP BY satellite_id, action`, from, to)
+	rows, err := db.conn().QueryContext(ctx, db.Rebind(`
+		SELECT satellite_id, action, sum(amount)
+		FROM (
+			SELECT satellite_id, action, amount FROM bandwidth_usage
+			WHERE ? <= created_at AND created_at <= ?
+			UNION ALL
+			SELECT satellite_id, action, amount FROM bandwidth_usage_rollups
+			WHERE ? <= interval_start AND interval_start <= ?
+		) usages
+		GROUP BY satellite_id, action`), from.UTC(), to.UTC(), rollupStart(from), to.UTC())
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return entries, nil
@@ -107,3 +109,137 @@ func (db *bandwidthdb) SummaryBySatellite(ctx context.Context, from, to time.Tim
 
 	return entries, ErrInfo.Wrap(rows.Err())
 }
+
+// rollupStart returns the start of the first hourly rollup included in the
+// usage from the specified time, the rollup containing it is included whole.
+func rollupStart(from time.Time) time.Time {
+	return from.UTC().Truncate(time.Hour)
+}
+
+// DailySummaries returns the ingress and egress of each satellite on each day
+// from the day of from until the day of to, in UTC.
+func (db *bandwidthdb) DailySummaries(ctx context.Context, from, to time.Time) (_ []bandwidth.Summary, err error) {
+	from = from.UTC().Truncate(24 * time.Hour)
+	to = to.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+
+	rows, err := db.conn().QueryContext(ctx, db.Rebind(`
+		SELECT satellite_id, action, amount, created_at FROM bandwidth_usage
+		WHERE ? <= created_at AND created_at < ?
+		UNION ALL
+		SELECT satellite_id, action, amount, interval_start FROM bandwidth_usage_rollups
+		WHERE ? <= interval_start AND interval_start < ?`), from, to, from, to)
+	if err != nil {
+		return nil, ErrInfo.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	type key struct {
+		satelliteID storj.NodeID
+		day         time.Time
+	}
+	usages := map[key]*bandwidth.Usage{}
+	for rows.Next() {
+		var satelliteID storj.NodeID
+		var action pb.PieceAction
+		var amount int64
+		var created time.Time
+
+		err := rows.Scan(&satelliteID, &action, &amount, &created)
+		if err != nil {
+			return nil, ErrInfo.Wrap(err)
+		}
+
+		k := key{satelliteID, created.UTC().Truncate(24 * time.Hour)}
+		usage, ok := usages[k]
+		if !ok {
+			usage = &bandwidth.Usage{}
+			usages[k] = usage
+		}
+		usage.Include(action, amount)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, ErrInfo.Wrap(err)
+	}
+
+	summaries := make([]bandwidth.Summary, 0, len(usages))
+	for k, usage := range usages {
+		summaries = append(summaries, bandwidth.Summary{
+			SatelliteID: k.satelliteID,
+			Day:         k.day,
+			Ingress:     usage.Ingress(),
+			Egress:      usage.Egress(),
+		})
+	}
+	sort.Slice(summaries, func(i, k int) bool {
+		if !summaries[i].Day.Equal(summaries[k].Day) {
+			return summaries[i].Day.Before(summaries[k].Day)
+		}
+		return summaries[i].SatelliteID.Less(summaries[k].SatelliteID)
+	})
+	return summaries, nil
+}
+
+// Rollup sums the bandwidth usage created before into hourly rollups and
+// deletes the summed usage, returning the number of rolled up usages.
+func (db *bandwidthdb) Rollup(ctx context.Context, before time.Time) (rolledUp int64, err error) {
+	before = before.UTC()
+
+	tx, err := db.beginTx(ctx)
+	if err != nil {
+		return 0, ErrInfo.Wrap(err)
+	}
+	defer func() {
+		if err != nil {
+			err = errs.Combine(err, ErrInfo.Wrap(tx.Rollback()))
+		} else {
+			err = ErrInfo.Wrap(tx.Commit())
+		}
+	}()
+
+	type key struct {
+		intervalStart time.Time
+		satelliteID   storj.NodeID
+		action        pb.PieceAction
+	}
+	amounts := map[key]int64{}
+
+	rows, err := tx.QueryContext(ctx, db.Rebind(`
+		SELECT satellite_id, action, amount, created_at
+		FROM bandwidth_usage
+		WHERE created_at < ?`), before)
+	if err != nil {
+		return 0, ErrInfo.Wrap(err)
+	}
+	for rows.Next() {
+		var k key
+		var amount int64
+		var created time.Time
+		if err := rows.Scan(&k.satelliteID, &k.action, &amount, &created); err != nil {
+			return 0, ErrInfo.Wrap(errs.Combine(err, rows.Close()))
+		}
+		k.intervalStart = created.UTC().Truncate(time.Hour)
+		amounts[k] += amount
+		rolledUp++
+	}
+	if err := errs.Combine(rows.Err(), rows.Close()); err != nil {
+		return 0, ErrInfo.Wrap(err)
+	}
+
+	for k, amount := range amounts {
+		_, err := tx.ExecContext(ctx, db.Rebind(`
+			INSERT INTO bandwidth_usage_rollups (interval_start, satellite_id, action, amount)
+			VALUES (?, ?, ?, ?)
+			ON CONFLICT (interval_start, satellite_id, action)
+			DO UPDATE SET amount = bandwidth_usage_rollups.amount + excluded.amount`),
+			k.intervalStart, k.satelliteID, k.action, amount)
+		if err != nil {
+			return 0, ErrInfo.Wrap(err)
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, db.Rebind(`DELETE FROM bandwidth_usage WHERE created_at < ?`), before)
+	if err != nil {
+		return 0, ErrInfo.Wrap(err)
+	}
+	return rolledUp, nil
+}
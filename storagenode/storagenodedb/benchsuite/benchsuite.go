@@ -0,0 +1,530 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package benchsuite
+
+import (
+	"context"
+	"crypto/rand"
+	"io"
+	"io/ioutil"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"storj.io/storj/internal/memory"
+	"storj.io/storj/pkg/identity"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/orders"
+	"storj.io/storj/storagenode/pieces"
+)
+
+// Config contains the cardinalities the benchmarks run with
+type Config struct {
+	Satellites int `help:"number of satellites the storage node works with" default:"10"`
+	Orders     int `help:"number of unsent orders and bandwidth entries per satellite already in the database" default:"1000"`
+
+	PieceSize memory.Size `help:"size of the written, read and deleted pieces" default:"2MiB"`
+}
+
+// Benchmark is a benchmark of a storage node database operation
+//
+// NB: testing runs a benchmark several times with an increasing b.N, each run
+// must be given a new empty database
+type Benchmark struct {
+	Name string
+	Run  func(ctx context.Context, b *testing.B, db storagenode.DB, config Config)
+}
+
+// Benchmarks are the benchmarks of the orders, bandwidth and pieces hot path
+var Benchmarks = []Benchmark{
+	{Name: "Orders/Enqueue", Run: BenchmarkEnqueue},
+	{Name: "Orders/EnqueueWhileListing", Run: BenchmarkEnqueueWhileListing},
+	{Name: "Orders/ParallelEnqueueWhileListing", Run: BenchmarkParallelEnqueueWhileListing},
+	{Name: "Orders/ListUnsentBySatellite", Run: BenchmarkListUnsentBySatellite},
+	{Name: "Orders/ListUnsentBatch", Run: BenchmarkListUnsentBatch},
+	{Name: "Orders/IterateUnsent", Run: BenchmarkIterateUnsent},
+	{Name: "Orders/Archive", Run: BenchmarkArchive},
+	{Name: "Orders/ArchiveBatch", Run: BenchmarkArchiveBatch},
+	{Name: "Bandwidth/Add", Run: BenchmarkBandwidthAdd},
+	{Name: "Bandwidth/SummaryBySatellite", Run: BenchmarkBandwidthSummaryBySatellite},
+	{Name: "PieceInfo/Add", Run: BenchmarkPieceInfoAdd},
+	{Name: "Piece/Write", Run: BenchmarkPieceWrite},
+	{Name: "Piece/Read", Run: BenchmarkPieceRead},
+	{Name: "Piece/Delete", Run: BenchmarkPieceDelete},
+	{Name: "Upload", Run: BenchmarkUpload},
+}
+
+// BenchmarkEnqueue benchmarks adding unsent orders
+func BenchmarkEnqueue(ctx context.Context, b *testing.B, db storagenode.DB, config Config) {
+	gen := newGenerator(ctx, b, config)
+	gen.enqueue(ctx, b, db, config.Satellites*config.Orders)
+
+	infos := gen.infos(b.N)
+	b.ResetTimer()
+	for _, info := range infos {
+		if err := db.Orders().Enqueue(ctx, info); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkEnqueueWhileListing benchmarks adding unsent orders while the
+// unsent orders are listed for sending
+func BenchmarkEnqueueWhileListing(ctx context.Context, b *testing.B, db storagenode.DB, config Config) {
+	gen := newGenerator(ctx, b, config)
+	gen.enqueue(ctx, b, db, config.Satellites*config.Orders)
+
+	infos := gen.infos(b.N)
+	stop := listContinuously(ctx, b, db)
+	defer stop()
+
+	b.ResetTimer()
+	for _, info := range infos {
+		if err := db.Orders().Enqueue(ctx, info); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkParallelEnqueueWhileListing benchmarks adding unsent orders from
+// concurrent uploads while the unsent orders are listed for sending
+func BenchmarkParallelEnqueueWhileListing(ctx context.Context, b *testing.B, db storagenode.DB, config Config) {
+	gen := newGenerator(ctx, b, config)
+	gen.enqueue(ctx, b, db, config.Satellites*config.Orders)
+
+	infos := gen.infos(b.N)
+	stop := listContinuously(ctx, b, db)
+	defer stop()
+
+	var next int64 = -1
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			info := infos[atomic.AddInt64(&next, 1)]
+			if err := db.Orders().Enqueue(ctx, info); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+// listContinuously lists the unsent orders in batches until the returned
+// function is called
+func listContinuously(ctx context.Context, b *testing.B, db storagenode.DB) (stop func()) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	var group errgroup.Group
+	group.Go(func() error {
+		const batchSize = 100
+		for ctx.Err() == nil {
+			satellites, err := db.Orders().ListUnsentSatellites(ctx)
+			if err != nil {
+				return err
+			}
+			for _, satelliteID := range satellites {
+				cursor := orders.UnsentCursor{SatelliteID: satelliteID}
+				for {
+					batch, next, err := db.Orders().ListUnsentBatch(ctx, cursor, batchSize)
+					if err != nil {
+						return err
+					}
+					if len(batch) < batchSize {
+						break
+					}
+					cursor = next
+				}
+			}
+		}
+		return nil
+	})
+
+	return func() {
+		cancel()
+		if err := group.Wait(); err != nil && ctx.Err() == nil {
+			b.Error(err)
+		}
+	}
+}
+
+// BenchmarkListUnsentBySatellite benchmarks listing the unsent orders, as done
+// before sending them to the satellites
+func BenchmarkListUnsentBySatellite(ctx context.Context, b *testing.B, db storagenode.DB, config Config) {
+	gen := newGenerator(ctx, b, config)
+	gen.enqueue(ctx, b, db, config.Satellites*config.Orders)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := db.Orders().ListUnsentBySatellite(ctx, config.Orders); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkIterateUnsent benchmarks streaming the unsent orders of every
+// satellite, as done by the sender
+func BenchmarkIterateUnsent(ctx context.Context, b *testing.B, db storagenode.DB, config Config) {
+	gen := newGenerator(ctx, b, config)
+	gen.enqueue(ctx, b, db, config.Satellites*config.Orders)
+
+	satellites, err := db.Orders().ListUnsentSatellites(ctx)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, satelliteID := range satellites {
+			err := db.Orders().IterateUnsent(ctx, orders.UnsentFilter{SatelliteID: satelliteID}, func(info *orders.Info) error {
+				return nil
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// BenchmarkListUnsentBatch benchmarks listing the unsent orders of every
+// satellite in batches, as done by the sender
+func BenchmarkListUnsentBatch(ctx context.Context, b *testing.B, db storagenode.DB, config Config) {
+	gen := newGenerator(ctx, b, config)
+	gen.enqueue(ctx, b, db, config.Satellites*config.Orders)
+
+	const batchSize = 100
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		satellites, err := db.Orders().ListUnsentSatellites(ctx)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, satelliteID := range satellites {
+			cursor := orders.UnsentCursor{SatelliteID: satelliteID}
+			for {
+				batch, next, err := db.Orders().ListUnsentBatch(ctx, cursor, batchSize)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(batch) < batchSize {
+					break
+				}
+				cursor = next
+			}
+		}
+	}
+}
+
+// BenchmarkArchive benchmarks archiving orders once they are sent
+func BenchmarkArchive(ctx context.Context, b *testing.B, db storagenode.DB, config Config) {
+	gen := newGenerator(ctx, b, config)
+	gen.enqueue(ctx, b, db, config.Satellites*config.Orders)
+	infos := gen.enqueue(ctx, b, db, b.N)
+
+	b.ResetTimer()
+	for _, info := range infos {
+		err := db.Orders().Archive(ctx, info.Limit.SatelliteId, info.Limit.SerialNumber, orders.StatusAccepted)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkArchiveBatch benchmarks archiving orders in batches, as done once
+// a settlement completes
+func BenchmarkArchiveBatch(ctx context.Context, b *testing.B, db storagenode.DB, config Config) {
+	gen := newGenerator(ctx, b, config)
+	gen.enqueue(ctx, b, db, config.Satellites*config.Orders)
+	infos := gen.enqueue(ctx, b, db, b.N)
+
+	batches := make(map[storj.NodeID][]orders.ArchiveRequest)
+	for _, info := range infos {
+		satelliteID := info.Limit.SatelliteId
+		batches[satelliteID] = append(batches[satelliteID], orders.ArchiveRequest{
+			Serial: info.Limit.SerialNumber,
+			Status: orders.StatusAccepted,
+		})
+	}
+
+	b.ResetTimer()
+	for satelliteID, requests := range batches {
+		if _, err := db.Orders().ArchiveBatch(ctx, satelliteID, requests); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkBandwidthAdd benchmarks recording bandwidth usage
+func BenchmarkBandwidthAdd(ctx context.Context, b *testing.B, db storagenode.DB, config Config) {
+	gen := newGenerator(ctx, b, config)
+	gen.addBandwidth(ctx, b, db, config.Satellites*config.Orders)
+
+	b.ResetTimer()
+	gen.addBandwidth(ctx, b, db, b.N)
+}
+
+// BenchmarkPieceInfoAdd benchmarks adding the information of uploaded pieces
+func BenchmarkPieceInfoAdd(ctx context.Context, b *testing.B, db storagenode.DB, config Config) {
+	gen := newGenerator(ctx, b, config)
+	gen.addPieceInfos(ctx, b, db, config.Satellites*config.Orders)
+
+	infos := gen.pieceInfos(b.N)
+	b.ResetTimer()
+	for _, info := range infos {
+		if err := db.PieceInfo().Add(ctx, info); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkPieceWrite benchmarks writing pieces to the disk
+func BenchmarkPieceWrite(ctx context.Context, b *testing.B, db storagenode.DB, config Config) {
+	gen := newGenerator(ctx, b, config)
+	store := pieces.NewStore(zap.NewNop(), db.Pieces())
+	data := gen.pieceData(b, config)
+
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	gen.writePieces(ctx, b, store, data, b.N)
+}
+
+// BenchmarkPieceRead benchmarks reading whole pieces from the disk
+func BenchmarkPieceRead(ctx context.Context, b *testing.B, db storagenode.DB, config Config) {
+	gen := newGenerator(ctx, b, config)
+	store := pieces.NewStore(zap.NewNop(), db.Pieces())
+	data := gen.pieceData(b, config)
+	infos := gen.writePieces(ctx, b, store, data, b.N)
+
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for _, info := range infos {
+		reader, err := store.Reader(ctx, info.SatelliteID, info.PieceID)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := io.CopyN(ioutil.Discard, reader, reader.Size()); err != nil {
+			b.Fatal(errs.Combine(err, reader.Close()))
+		}
+		if err := reader.Close(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkPieceDelete benchmarks deleting pieces from the disk
+func BenchmarkPieceDelete(ctx context.Context, b *testing.B, db storagenode.DB, config Config) {
+	gen := newGenerator(ctx, b, config)
+	store := pieces.NewStore(zap.NewNop(), db.Pieces())
+	infos := gen.writePieces(ctx, b, store, gen.pieceData(b, config), b.N)
+
+	b.ResetTimer()
+	for _, info := range infos {
+		if err := store.Delete(ctx, info.SatelliteID, info.PieceID); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkUpload benchmarks the database writes of an upload: adding the
+// piece information, the bandwidth usage and the order
+func BenchmarkUpload(ctx context.Context, b *testing.B, db storagenode.DB, config Config) {
+	gen := newGenerator(ctx, b, config)
+	gen.enqueue(ctx, b, db, config.Satellites*config.Orders)
+	gen.addPieceInfos(ctx, b, db, config.Satellites*config.Orders)
+
+	infos := gen.infos(b.N)
+	pieceInfos := gen.pieceInfos(b.N)
+	now := time.Now()
+	b.ResetTimer()
+	for i, info := range infos {
+		if err := db.PieceInfo().Add(ctx, pieceInfos[i]); err != nil {
+			b.Fatal(err)
+		}
+		if err := db.Bandwidth().Add(ctx, info.Limit.SatelliteId, info.Limit.Action, info.Order.Amount, now); err != nil {
+			b.Fatal(err)
+		}
+		if err := db.Orders().Enqueue(ctx, info); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkBandwidthSummaryBySatellite benchmarks summarizing the bandwidth
+// usage of the month, as done for the dashboard and the monitor
+func BenchmarkBandwidthSummaryBySatellite(ctx context.Context, b *testing.B, db storagenode.DB, config Config) {
+	gen := newGenerator(ctx, b, config)
+	gen.addBandwidth(ctx, b, db, config.Satellites*config.Orders)
+
+	now := time.Now()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := db.Bandwidth().SummaryBySatellite(ctx, now.AddDate(0, -1, 0), now); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// actions are the actions of the generated orders and bandwidth usage
+var actions = []pb.PieceAction{
+	pb.PieceAction_PUT,
+	pb.PieceAction_GET,
+	pb.PieceAction_GET_AUDIT,
+	pb.PieceAction_GET_REPAIR,
+	pb.PieceAction_PUT_REPAIR,
+}
+
+// generator generates orders and bandwidth usage spread over satellites
+type generator struct {
+	satellites  []storj.NodeID
+	storagenode storj.NodeID
+	uplink      *identity.PeerIdentity
+	next        int
+}
+
+// newGenerator creates a generator for config, with at least one satellite
+func newGenerator(ctx context.Context, b *testing.B, config Config) *generator {
+	ca, err := identity.NewCA(ctx, identity.NewCAOptions{Difficulty: 0, Concurrency: 1})
+	if err != nil {
+		b.Fatal(err)
+	}
+	uplink, err := ca.NewIdentity()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	gen := &generator{
+		storagenode: randomNodeID(b),
+		uplink:      uplink.PeerIdentity(),
+	}
+	for i := 0; i < config.Satellites || i == 0; i++ {
+		gen.satellites = append(gen.satellites, randomNodeID(b))
+	}
+	return gen
+}
+
+// infos generates count unsigned orders
+func (gen *generator) infos(count int) []*orders.Info {
+	now := ptypes.TimestampNow()
+
+	infos := make([]*orders.Info, 0, count)
+	for i := 0; i < count; i++ {
+		var serialNumber storj.SerialNumber
+		_, _ = rand.Read(serialNumber[:])
+
+		gen.next++
+		infos = append(infos, &orders.Info{
+			Limit: &pb.OrderLimit2{
+				SerialNumber:    serialNumber,
+				SatelliteId:     gen.satellites[gen.next%len(gen.satellites)],
+				UplinkId:        gen.uplink.ID,
+				StorageNodeId:   gen.storagenode,
+				PieceId:         storj.NewPieceID(),
+				Limit:           2 * 1024 * 1024,
+				Action:          actions[gen.next%len(actions)],
+				PieceExpiration: now,
+				OrderExpiration: now,
+			},
+			Order: &pb.Order2{
+				SerialNumber: serialNumber,
+				Amount:       1024 * 1024,
+			},
+			Uplink: gen.uplink,
+		})
+	}
+	return infos
+}
+
+// enqueue adds count orders to db
+func (gen *generator) enqueue(ctx context.Context, b *testing.B, db storagenode.DB, count int) []*orders.Info {
+	infos := gen.infos(count)
+	for _, info := range infos {
+		if err := db.Orders().Enqueue(ctx, info); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return infos
+}
+
+// addBandwidth adds count bandwidth usage entries to db
+func (gen *generator) addBandwidth(ctx context.Context, b *testing.B, db storagenode.DB, count int) {
+	now := time.Now()
+	for i := 0; i < count; i++ {
+		gen.next++
+		satelliteID := gen.satellites[gen.next%len(gen.satellites)]
+		action := actions[gen.next%len(actions)]
+		if err := db.Bandwidth().Add(ctx, satelliteID, action, 1024*1024, now); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// pieceInfos generates the information of count pieces
+func (gen *generator) pieceInfos(count int) []*pieces.Info {
+	expiration := time.Now().Add(24 * time.Hour)
+
+	infos := make([]*pieces.Info, 0, count)
+	for i := 0; i < count; i++ {
+		gen.next++
+		pieceID := storj.NewPieceID()
+		infos = append(infos, &pieces.Info{
+			SatelliteID:     gen.satellites[gen.next%len(gen.satellites)],
+			PieceID:         pieceID,
+			PieceSize:       1024 * 1024,
+			PieceExpiration: &expiration,
+			UplinkPieceHash: &pb.PieceHash{PieceId: pieceID, Hash: make([]byte, 32)},
+			Uplink:          gen.uplink,
+		})
+	}
+	return infos
+}
+
+// addPieceInfos adds the information of count pieces to db
+func (gen *generator) addPieceInfos(ctx context.Context, b *testing.B, db storagenode.DB, count int) {
+	for _, info := range gen.pieceInfos(count) {
+		if err := db.PieceInfo().Add(ctx, info); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// pieceData generates the random content of the benchmarked pieces
+func (gen *generator) pieceData(b *testing.B, config Config) []byte {
+	data := make([]byte, config.PieceSize.Int())
+	if _, err := rand.Read(data); err != nil {
+		b.Fatal(err)
+	}
+	return data
+}
+
+// writePieces writes count pieces with data to store
+func (gen *generator) writePieces(ctx context.Context, b *testing.B, store *pieces.Store, data []byte, count int) []*pieces.Info {
+	infos := gen.pieceInfos(count)
+	for _, info := range infos {
+		writer, err := store.Writer(ctx, info.SatelliteID, info.PieceID, int64(len(data)))
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := writer.Write(data); err != nil {
+			b.Fatal(errs.Combine(err, writer.Cancel()))
+		}
+		if err := writer.Commit(&pb.PieceHeader{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return infos
+}
+
+func randomNodeID(b *testing.B) storj.NodeID {
+	var id storj.NodeID
+	if _, err := rand.Read(id[:]); err != nil {
+		b.Fatal(err)
+	}
+	return id
+}
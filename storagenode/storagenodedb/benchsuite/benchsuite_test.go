@@ -0,0 +1,48 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package benchsuite_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/internal/memory"
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/storagenode/storagenodedb"
+	"storj.io/storj/storagenode/storagenodedb/benchsuite"
+)
+
+func BenchmarkHotPath(b *testing.B) {
+	config := benchsuite.Config{Satellites: 10, Orders: 100, PieceSize: 256 * memory.KiB}
+
+	for _, bench := range benchsuite.Benchmarks {
+		bench := bench
+		b.Run(bench.Name, func(b *testing.B) {
+			ctx := testcontext.New(b)
+			defer ctx.Cleanup()
+
+			// NB: an on disk database, as the in memory one uses a single connection
+			dir := ctx.Dir("storage")
+			db, err := storagenodedb.New(zap.NewNop(), storagenodedb.Config{
+				Storage:  dir,
+				Info:     filepath.Join(dir, "piecestore.db"),
+				Info2:    filepath.Join(dir, "info.db"),
+				Pieces:   dir,
+				Kademlia: filepath.Join(dir, "kademlia"),
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer ctx.Check(db.Close)
+
+			if err := db.CreateTables(); err != nil {
+				b.Fatal(err)
+			}
+
+			bench.Run(ctx, b, db, config)
+		})
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"context"
 	"crypto/x509"
 	"encoding/asn1"
-	"strings"
 
 	"storj.io/storj/pkg/identity"
 	"storj.io/storj/pkg/pkcrypto"
@@ -26,29 +25,26 @@ func (db *infodb) CertDB() trust.CertDB { return &certdb{db} }
 
 // Include includes the certificate in the table and returns an unique id.
 func (db *certdb) Include(ctx context.Context, pi *identity.PeerIdentity) (certid int64, err error) {
-	chain := encodePeerIdentity(pi)
+	chain := db.cipher.Encrypt(encodePeerIdentity(pi))
 
-	defer db.locked()()
-
-	result, err := db.db.Exec(`INSERT INTO certificate(node_id, peer_identity) VALUES(?, ?)`, pi.ID, chain)
-	if err != nil && strings.Contains(err.Error(), "UNIQUE constraint") {
-		err = db.db.QueryRow(`SELECT cert_id FROM certificate WHERE peer_identity = ?`, chain).Scan(&certid)
-		return certid, ErrInfo.Wrap(err)
-	} else if err != nil {
+	// NB: LastInsertId isn't supported by postgres, so the id is always queried
+	_, err = db.execStatement(ctx, stmtIncludeCertificate, pi.ID, chain)
+	if err != nil {
 		return -1, ErrInfo.Wrap(err)
 	}
 
-	certid, err = result.LastInsertId()
-	return certid, ErrInfo.Wrap(err)
+	err = db.queryRowStatement(ctx, stmtLookupCertificateID, chain).Scan(&certid)
+	if err != nil {
+		return -1, ErrInfo.Wrap(err)
+	}
+	return certid, nil
 }
 
 // LookupByCertID finds certificate by the certid returned by Include.
 func (db *certdb) LookupByCertID(ctx context.Context, id int64) (*identity.PeerIdentity, error) {
 	var pem *[]byte
 
-	db.mu.Lock()
-	err := db.db.QueryRow(`SELECT peer_identity FROM certificate WHERE cert_id = ?`, id).Scan(&pem)
-	db.mu.Unlock()
+	err := db.conn().QueryRowContext(ctx, db.Rebind(`SELECT peer_identity FROM certificate WHERE cert_id = ?`), id).Scan(&pem)
 
 	if err != nil {
 		return nil, ErrInfo.Wrap(err)
@@ -57,7 +53,7 @@ func (db *certdb) LookupByCertID(ctx context.Context, id int64) (*identity.PeerI
 		return nil, ErrInfo.New("did not find certificate")
 	}
 
-	peer, err := decodePeerIdentity(*pem)
+	peer, err := db.decodePeerIdentity(*pem)
 	return peer, ErrInfo.Wrap(err)
 }
 
@@ -72,11 +68,16 @@ func encodePeerIdentity(pi *identity.PeerIdentity) []byte {
 	return chain
 }
 
-func decodePeerIdentity(chain []byte) (*identity.PeerIdentity, error) {
+// decodePeerIdentity decrypts and decodes the peer identity stored by Include.
+func (db *infodb) decodePeerIdentity(chain []byte) (*identity.PeerIdentity, error) {
+	chain, err := db.cipher.Decrypt(chain)
+	if err != nil {
+		return nil, ErrInfo.Wrap(err)
+	}
+
 	var certs []*x509.Certificate
 	for len(chain) > 0 {
 		var raw asn1.RawValue
-		var err error
 
 		chain, err = asn1.Unmarshal(chain, &raw)
 		if err != nil {
@@ -26,18 +26,35 @@ type Config struct {
 	Info2    string
 	Kademlia string
 
+	// DatabaseURL is the url of the database replacing Info2, when not empty
+	DatabaseURL string
+	// AutoRecover moves corrupt sqlite databases aside and recreates them
+	AutoRecover bool
+	// KeyFile is the file with the key encrypting the order limits and
+	// uplink identities, when not empty
+	KeyFile string
+
 	Pieces string
+	// ExtraPieces are the directories, usually on other disks, the pieces are
+	// spread over in addition to Pieces
+	ExtraPieces []string
+	// Preallocate allocates the disk space of the pieces when they are created
+	Preallocate bool
+}
+
+// closableBlobs is a blob storage which needs to be closed.
+type closableBlobs interface {
+	storage.Blobs
+	Close() error
 }
 
 // DB contains access to different database tables
 type DB struct {
-	log  *zap.Logger
-	psdb *psdb.DB
+	log    *zap.Logger
+	config Config
+	psdb   *psdb.DB
 
-	pieces interface {
-		storage.Blobs
-		Close() error
-	}
+	pieces closableBlobs
 
 	info *infodb
 
@@ -46,17 +63,32 @@ type DB struct {
 
 // New creates a new master database for storage node
 func New(log *zap.Logger, config Config) (*DB, error) {
-	piecesDir, err := filestore.NewDir(config.Pieces)
+	if err := checkDatabases(log, config); err != nil {
+		return nil, err
+	}
+
+	pieces, err := newPieces(config)
 	if err != nil {
 		return nil, err
 	}
-	pieces := filestore.New(piecesDir)
 
-	infodb, err := newInfo(config.Info2)
+	var infodb *infodb
+	if config.DatabaseURL != "" {
+		infodb, err = newInfoURL(config.DatabaseURL)
+	} else {
+		infodb, err = newInfo(config.Info2)
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	if config.KeyFile != "" {
+		infodb.cipher, err = loadBlobCipher(config.KeyFile)
+		if err != nil {
+			return nil, errs.Combine(err, infodb.Close())
+		}
+	}
+
 	psdb, err := psdb.Open(config.Info)
 	if err != nil {
 		return nil, err
@@ -68,8 +100,9 @@ func New(log *zap.Logger, config Config) (*DB, error) {
 	}
 
 	return &DB{
-		log:  log,
-		psdb: psdb,
+		log:    log,
+		config: config,
+		psdb:   psdb,
 
 		pieces: pieces,
 
@@ -80,6 +113,21 @@ func New(log *zap.Logger, config Config) (*DB, error) {
 	}, nil
 }
 
+// newPieces opens the blob storage of the pieces, which is spread over
+// several directories when there are extra directories.
+func newPieces(config Config) (closableBlobs, error) {
+	storeConfig := filestore.Config{Preallocate: config.Preallocate}
+	if len(config.ExtraPieces) == 0 {
+		piecesDir, err := filestore.NewDirConfig(config.Pieces, storeConfig)
+		if err != nil {
+			return nil, err
+		}
+		return filestore.New(piecesDir), nil
+	}
+
+	return filestore.NewMultiAtConfig(storeConfig, append([]string{config.Pieces}, config.ExtraPieces...)...)
+}
+
 // NewInMemory creates new inmemory master database for storage node
 // TODO: still stores data on disk
 func NewInMemory(log *zap.Logger, storageDir string) (*DB, error) {
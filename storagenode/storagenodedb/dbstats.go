@@ -0,0 +1,93 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storagenodedb
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"storj.io/storj/storagenode/dbstats"
+)
+
+type statsdb struct {
+	*DB
+}
+
+// Stats returns database for collecting the database statistics.
+func (db *DB) Stats() dbstats.DB { return &statsdb{db} }
+
+// Stats returns the row counts of the largest tables, the sizes of the
+// database files and the time waited for the database.
+func (db *statsdb) Stats(ctx context.Context) (*dbstats.Stats, error) {
+	stats := &dbstats.Stats{FileSizes: map[string]int64{}}
+
+	counts := []struct {
+		table string
+		count *int64
+	}{
+		{"unsent_order", &stats.UnsentOrders},
+		{"order_archive", &stats.ArchivedOrders},
+		{"pieceinfo", &stats.Pieces},
+		{"used_serial", &stats.UsedSerials},
+	}
+	for _, count := range counts {
+		err := db.info.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM `+count.table).Scan(count.count)
+		if err != nil {
+			return nil, ErrInfo.Wrap(err)
+		}
+	}
+
+	err := db.info.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM pieceinfo WHERE corrupted_at IS NOT NULL`).Scan(&stats.CorruptedPieces)
+	if err != nil {
+		return nil, ErrInfo.Wrap(err)
+	}
+
+	if db.info.driver == "postgres" {
+		var size int64
+		err := db.info.db.QueryRowContext(ctx, `SELECT pg_database_size(current_database())`).Scan(&size)
+		if err != nil {
+			return nil, ErrInfo.Wrap(err)
+		}
+		stats.FileSizes["info"] = size
+	}
+	for _, database := range sqliteDatabases(db.config) {
+		if database.Path == "" {
+			continue
+		}
+		name := strings.TrimSuffix(filepath.Base(database.Path), filepath.Ext(database.Path))
+		stats.FileSizes[name] = fileSize(database.Path) + fileSize(database.Path+"-wal")
+	}
+	if db.config.Kademlia != "" {
+		stats.FileSizes["kademlia"] = fileSize(db.config.Kademlia)
+	}
+
+	poolStats := db.info.db.Stats()
+	stats.ConnectionWaits = poolStats.WaitCount
+	stats.ConnectionWaitTime = poolStats.WaitDuration
+
+	// NB: sqlite transactions take the write lock when they begin
+	start := time.Now()
+	tx, err := db.info.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, ErrInfo.Wrap(err)
+	}
+	stats.WriteLockWait = time.Since(start)
+	if err := tx.Rollback(); err != nil {
+		return nil, ErrInfo.Wrap(err)
+	}
+
+	return stats, nil
+}
+
+// fileSize returns the size of the file at path, zero when it doesn't exist.
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
@@ -0,0 +1,125 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storagenodedb
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/zeebo/errs"
+)
+
+// ErrEncryption is the error class for encrypting and decrypting database blobs.
+var ErrEncryption = errs.Class("database encryption")
+
+// blobHeader prefixes the encrypted blobs, serialized protobufs and
+// certificates never start with a zero byte, so that the blobs written before
+// the encryption was enabled are still read as is.
+var blobHeader = []byte{0, 1}
+
+// blobCipher encrypts the blobs stored in the database with AES-GCM.
+//
+// The nonce is derived from the blob, so that equal blobs are encrypted
+// equally and can still be compared by the database, as the certificates are.
+type blobCipher struct {
+	aead     cipher.AEAD
+	nonceKey []byte
+}
+
+// loadBlobCipher loads the hex encoded 32 byte key in keyFile.
+func loadBlobCipher(keyFile string) (*blobCipher, error) {
+	data, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, ErrEncryption.Wrap(err)
+	}
+
+	key, err := hex.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil {
+		return nil, ErrEncryption.New("invalid key in %s: %v", keyFile, err)
+	}
+	if len(key) != 32 {
+		return nil, ErrEncryption.New("invalid key in %s: expected 32 bytes, got %d", keyFile, len(key))
+	}
+
+	return newBlobCipher(key)
+}
+
+// newBlobCipher creates a cipher deriving the encryption and nonce keys from key.
+func newBlobCipher(key []byte) (*blobCipher, error) {
+	block, err := aes.NewCipher(deriveKey(key, "encryption"))
+	if err != nil {
+		return nil, ErrEncryption.Wrap(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, ErrEncryption.Wrap(err)
+	}
+	return &blobCipher{aead: aead, nonceKey: deriveKey(key, "nonce")}, nil
+}
+
+func deriveKey(key []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
+
+// Encrypt encrypts the blob, the blob is returned as is when the cipher is nil.
+func (c *blobCipher) Encrypt(blob []byte) []byte {
+	if c == nil {
+		return blob
+	}
+
+	mac := hmac.New(sha256.New, c.nonceKey)
+	_, _ = mac.Write(blob)
+	nonce := mac.Sum(nil)[:c.aead.NonceSize()]
+
+	out := append(append([]byte{}, blobHeader...), nonce...)
+	return c.aead.Seal(out, nonce, blob, blobHeader)
+}
+
+// Decrypt decrypts the blob, blobs that weren't encrypted are returned as is.
+func (c *blobCipher) Decrypt(blob []byte) ([]byte, error) {
+	if !bytes.HasPrefix(blob, blobHeader) {
+		return blob, nil
+	}
+	if c == nil {
+		return nil, ErrEncryption.New("blob is encrypted, but no key is configured")
+	}
+
+	blob = blob[len(blobHeader):]
+	if len(blob) < c.aead.NonceSize() {
+		return nil, ErrEncryption.New("blob too short")
+	}
+	nonce, ciphertext := blob[:c.aead.NonceSize()], blob[c.aead.NonceSize():]
+
+	plain, err := c.aead.Open(nil, nonce, ciphertext, blobHeader)
+	if err != nil {
+		return nil, ErrEncryption.Wrap(err)
+	}
+	return plain, nil
+}
+
+// marshal serializes and encrypts msg.
+func (db *infodb) marshal(msg proto.Message) ([]byte, error) {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	return db.cipher.Encrypt(data), nil
+}
+
+// unmarshal decrypts and deserializes data into msg.
+func (db *infodb) unmarshal(data []byte, msg proto.Message) error {
+	data, err := db.cipher.Decrypt(data)
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(data, msg)
+}
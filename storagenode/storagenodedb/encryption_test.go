@@ -0,0 +1,120 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storagenodedb_test
+
+import (
+	"bytes"
+	"database/sql"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storagenode/orders"
+	"storj.io/storj/storagenode/storagenodedb"
+)
+
+func TestEncryptedInfo(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	log := zaptest.NewLogger(t)
+	dir := ctx.Dir("storage")
+	infoPath := filepath.Join(dir, "info.db")
+
+	keyFile := filepath.Join(ctx.Dir("key"), "key")
+	require.NoError(t, ioutil.WriteFile(keyFile, []byte("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f\n"), 0600))
+
+	open := func(keyFile string) (*storagenodedb.DB, error) {
+		db, err := storagenodedb.New(log, storagenodedb.Config{
+			Storage:  dir,
+			Info:     filepath.Join(dir, "piecestore.db"),
+			Info2:    infoPath,
+			Pieces:   dir,
+			Kademlia: filepath.Join(dir, "kademlia"),
+
+			KeyFile: keyFile,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return db, db.CreateTables()
+	}
+
+	satelliteID := testplanet.MustPregeneratedSignedIdentity(1).ID
+	uplink := testplanet.MustPregeneratedSignedIdentity(3)
+	enqueue := func(db *storagenodedb.DB, serialNumber storj.SerialNumber) {
+		expiration, err := ptypes.TimestampProto(time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		err = db.Orders().Enqueue(ctx, &orders.Info{
+			Limit: &pb.OrderLimit2{
+				SerialNumber:    serialNumber,
+				SatelliteId:     satelliteID,
+				UplinkId:        uplink.ID,
+				StorageNodeId:   testplanet.MustPregeneratedSignedIdentity(0).ID,
+				PieceId:         storj.NewPieceID(),
+				Limit:           100,
+				Action:          pb.PieceAction_GET,
+				PieceExpiration: expiration,
+				OrderExpiration: expiration,
+			},
+			Order: &pb.Order2{
+				SerialNumber: serialNumber,
+				Amount:       50,
+			},
+			Uplink: uplink.PeerIdentity(),
+		})
+		require.NoError(t, err)
+	}
+
+	// an order written before the encryption was enabled
+	db, err := open("")
+	require.NoError(t, err)
+	enqueue(db, storj.SerialNumber{1})
+	require.NoError(t, db.Close())
+
+	db, err = open(keyFile)
+	require.NoError(t, err)
+	enqueue(db, storj.SerialNumber{2})
+
+	// both orders are readable with the key
+	unsent, err := db.Orders().ListUnsent(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, unsent, 2)
+	for _, info := range unsent {
+		require.Equal(t, uplink.ID, info.Uplink.ID)
+		require.Equal(t, int64(50), info.Order.Amount)
+	}
+	require.NoError(t, db.Close())
+
+	// the new order limit and uplink identity aren't stored in plain text
+	raw, err := sql.Open("sqlite3", "file:"+infoPath)
+	require.NoError(t, err)
+	var limitSerialized, peerIdentity []byte
+	err = raw.QueryRow(`
+		SELECT order_limit_serialized, certificate.peer_identity
+		FROM unsent_order
+		INNER JOIN certificate on unsent_order.uplink_cert_id = certificate.cert_id
+		WHERE serial_number = ?
+	`, storj.SerialNumber{2}).Scan(&limitSerialized, &peerIdentity)
+	require.NoError(t, err)
+	require.NoError(t, raw.Close())
+	require.False(t, bytes.Contains(limitSerialized, satelliteID.Bytes()))
+	require.False(t, bytes.Contains(peerIdentity, uplink.PeerIdentity().Leaf.Raw))
+
+	// the encrypted orders can't be read without the key
+	db, err = open("")
+	require.NoError(t, err)
+	defer ctx.Check(db.Close)
+	_, err = db.Orders().ListUnsent(ctx, 10)
+	require.True(t, storagenodedb.ErrEncryption.Has(err), err)
+}
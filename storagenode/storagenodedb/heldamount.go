@@ -0,0 +1,96 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storagenodedb
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storagenode/heldamount"
+)
+
+type heldamountdb struct{ *infodb }
+
+// HeldAmount returns database for storing the payouts reported by the satellites.
+func (db *DB) HeldAmount() heldamount.DB { return db.info.HeldAmount() }
+
+// HeldAmount returns database for storing the payouts reported by the satellites.
+func (db *infodb) HeldAmount() heldamount.DB { return &heldamountdb{db} }
+
+// Store replaces the payout of the satellite for the period of payout.
+func (db *heldamountdb) Store(ctx context.Context, payout heldamount.Payout) error {
+	_, err := db.conn().ExecContext(ctx, db.Rebind(`
+		INSERT INTO payouts (
+			satellite_id, period, node_month,
+			surge_percent, held_percent,
+			gross, held, paid, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (satellite_id, period) DO UPDATE SET
+			node_month = excluded.node_month,
+			surge_percent = excluded.surge_percent,
+			held_percent = excluded.held_percent,
+			gross = excluded.gross,
+			held = excluded.held,
+			paid = excluded.paid,
+			updated_at = excluded.updated_at
+	`), payout.SatelliteID, payout.Period.UTC(), payout.NodeMonth,
+		payout.SurgePercent, payout.HeldPercent,
+		payout.Gross, payout.Held, payout.Paid, payout.UpdatedAt.UTC())
+	return ErrInfo.Wrap(err)
+}
+
+// Last returns the payout of the satellite for the latest period, it returns
+// nil when the satellite didn't report any.
+func (db *heldamountdb) Last(ctx context.Context, satelliteID storj.NodeID) (*heldamount.Payout, error) {
+	payout := &heldamount.Payout{}
+	err := db.conn().QueryRowContext(ctx, db.Rebind(`
+		SELECT satellite_id, period, node_month,
+			surge_percent, held_percent,
+			gross, held, paid, updated_at
+		FROM payouts
+		WHERE satellite_id = ?
+		ORDER BY period DESC
+		LIMIT 1
+	`), satelliteID).Scan(&payout.SatelliteID, &payout.Period, &payout.NodeMonth,
+		&payout.SurgePercent, &payout.HeldPercent,
+		&payout.Gross, &payout.Held, &payout.Paid, &payout.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, ErrInfo.Wrap(err)
+	}
+	return payout, nil
+}
+
+// All returns the payouts of every satellite ordered by satellite and period.
+func (db *heldamountdb) All(ctx context.Context) (_ []heldamount.Payout, err error) {
+	rows, err := db.conn().QueryContext(ctx, `
+		SELECT satellite_id, period, node_month,
+			surge_percent, held_percent,
+			gross, held, paid, updated_at
+		FROM payouts
+		ORDER BY satellite_id, period
+	`)
+	if err != nil {
+		return nil, ErrInfo.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	var all []heldamount.Payout
+	for rows.Next() {
+		var payout heldamount.Payout
+		err := rows.Scan(&payout.SatelliteID, &payout.Period, &payout.NodeMonth,
+			&payout.SurgePercent, &payout.HeldPercent,
+			&payout.Gross, &payout.Held, &payout.Paid, &payout.UpdatedAt)
+		if err != nil {
+			return nil, ErrInfo.Wrap(err)
+		}
+		all = append(all, payout)
+	}
+	return all, ErrInfo.Wrap(rows.Err())
+}
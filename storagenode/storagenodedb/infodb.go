@@ -4,24 +4,83 @@
 package storagenodedb
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"os"
 	"path/filepath"
-	"sync"
 
 	"github.com/zeebo/errs"
 	"go.uber.org/zap"
 
+	"storj.io/storj/internal/dbutil"
 	"storj.io/storj/internal/migrate"
 )
 
 // ErrInfo is the default error class for infodb
 var ErrInfo = errs.Class("infodb")
 
+const (
+	// busyTimeout is how long a statement waits for the write lock held by
+	// another connection, in milliseconds
+	busyTimeout = 10000
+	// maxIdleConns is the number of connections kept open for reuse
+	maxIdleConns = 8
+)
+
 // infodb implements information database for piecestore.
+//
+// The sqlite database is opened in WAL mode, so that reads on the connections of
+// the pool don't wait for the writes, writes wait for each other up to busyTimeout.
 type infodb struct {
-	mu sync.Mutex
-	db *sql.DB
+	db     *sql.DB
+	driver string
+	// cipher encrypts the order limits and uplink identities, when not nil
+	cipher *blobCipher
+	// prepared are the statements of the hot path, once the tables exist
+	prepared []*sql.Stmt
+	// tx is the transaction of WithTx the queries run in, when not nil
+	tx *sql.Tx
+}
+
+// queryer is implemented by both *sql.DB and *sql.Tx.
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// conn returns the transaction of WithTx, or the database outside of it.
+func (db *infodb) conn() queryer {
+	if db.tx != nil {
+		return db.tx
+	}
+	return db.db
+}
+
+// beginTx begins a transaction, transactions can't be nested in WithTx.
+func (db *infodb) beginTx(ctx context.Context) (*sql.Tx, error) {
+	if db.tx != nil {
+		return nil, ErrInfo.New("nested transaction")
+	}
+	return db.db.BeginTx(ctx, nil)
+}
+
+// newInfoURL creates or opens infodb at the specified database url.
+func newInfoURL(databaseURL string) (*infodb, error) {
+	driver, source, err := dbutil.SplitConnstr(databaseURL)
+	if err != nil {
+		return nil, ErrInfo.Wrap(err)
+	}
+
+	switch driver {
+	case "sqlite3":
+		return newInfo(source)
+	case "postgres":
+		return newInfoPostgres(source)
+	default:
+		return nil, ErrInfo.New("unsupported database driver %q", driver)
+	}
 }
 
 // newInfo creates or opens infodb at the specified path.
@@ -30,12 +89,17 @@ func newInfo(path string) (*infodb, error) {
 		return nil, err
 	}
 
-	db, err := sql.Open("sqlite3", "file:"+path+"?_journal=WAL")
+	// NB: transactions take the write lock immediately, otherwise two
+	// transactions upgrading from a read to a write would fail on each other.
+	// The free pages are kept until the maintenance reclaims them.
+	dsn := fmt.Sprintf("file:%s?_journal=WAL&_busy_timeout=%d&_txlock=immediate&_auto_vacuum=incremental", path, busyTimeout)
+	db, err := sql.Open("sqlite3", dsn)
 	if err != nil {
 		return nil, ErrInfo.Wrap(err)
 	}
+	db.SetMaxIdleConns(maxIdleConns)
 
-	return &infodb{db: db}, nil
+	return &infodb{db: db, driver: "sqlite3"}, nil
 }
 
 // newInfoInMemory creates a new inmemory infodb.
@@ -44,25 +108,24 @@ func newInfoInMemory() (*infodb, error) {
 	if err != nil {
 		return nil, ErrInfo.Wrap(err)
 	}
+	// every connection to :memory: opens a separate database
+	db.SetMaxOpenConns(1)
 
-	return &infodb{db: db}, nil
+	return &infodb{db: db, driver: "sqlite3"}, nil
 }
 
 // Close closes any resources.
 func (db *infodb) Close() error {
-	return db.db.Close()
-}
-
-// locked allows easy locking the database.
-func (db *infodb) locked() func() {
-	db.mu.Lock()
-	return db.mu.Unlock
+	return errs.Combine(closeStatements(db.prepared), db.db.Close())
 }
 
 // CreateTables creates any necessary tables.
 func (db *infodb) CreateTables(log *zap.Logger) error {
 	migration := db.Migration()
-	return migration.Run(log.Named("migration"), db)
+	if err := migration.Run(log.Named("migration"), db); err != nil {
+		return err
+	}
+	return db.prepareStatements(context.Background())
 }
 
 // RawDB returns access to the raw database, only for migration tests.
@@ -72,13 +135,22 @@ func (db *infodb) RawDB() *sql.DB { return db.db }
 func (db *infodb) Begin() (*sql.Tx, error) { return db.db.Begin() }
 
 // Rebind rebind parameters
-func (db *infodb) Rebind(s string) string { return s }
+func (db *infodb) Rebind(s string) string {
+	if db.driver == "postgres" {
+		return rebindPostgres(s)
+	}
+	return s
+}
 
 // Schema returns schema
 func (db *infodb) Schema() string { return "" }
 
 // Migration returns table migrations.
 func (db *infodb) Migration() *migrate.Migration {
+	if db.driver == "postgres" {
+		return db.postgresMigration()
+	}
+
 	return &migrate.Migration{
 		Table: "versions",
 		Steps: []*migrate.Step{
@@ -163,6 +235,157 @@ func (db *infodb) Migration() *migrate.Migration {
 					`CREATE INDEX idx_order_archive_status ON order_archive(status)`,
 				},
 			},
+			{
+				Description: "Index archived orders by archival time",
+				Version:     1,
+				Action: migrate.SQL{
+					// archival time index to allow fast deletion
+					`CREATE INDEX idx_order_archive_archived_at ON order_archive(archived_at)`,
+				},
+			},
+			{
+				Description: "Add settlement backoff of satellites",
+				Version:     2,
+				Action: migrate.SQL{
+					`CREATE TABLE order_settlement_backoff (
+						satellite_id BLOB      NOT NULL PRIMARY KEY,
+						failures     INTEGER   NOT NULL, -- consecutive failed settlements
+						next_retry   TIMESTAMP NOT NULL  -- when to settle again
+					)`,
+				},
+			},
+			{
+				Description: "Add order amount to unsent orders",
+				Version:     3,
+				Action:      db.addUnsentOrderAmount(`ALTER TABLE unsent_order ADD COLUMN order_amount INTEGER NOT NULL DEFAULT 0`),
+			},
+			{
+				Description: "Add creation time to piece info",
+				Version:     4,
+				Action:      db.addPieceCreation(`ALTER TABLE pieceinfo ADD COLUMN piece_creation TIMESTAMP`),
+			},
+			{
+				Description: "Index piece info by expiration",
+				Version:     5,
+				Action: migrate.SQL{
+					// expiration index to allow fast collection of expired pieces
+					`CREATE INDEX idx_pieceinfo_expiration ON pieceinfo(piece_expiration)`,
+				},
+			},
+			{
+				Description: "Add cached space used by satellite",
+				Version:     6,
+				Action: migrate.SQL{
+					`CREATE TABLE piece_space_used (
+						satellite_id BLOB    NOT NULL PRIMARY KEY,
+						total        INTEGER NOT NULL -- bytes used by the pieces of the satellite
+					)`,
+				},
+			},
+			{
+				Description: "Add corruption flag to piece info",
+				Version:     7,
+				Action: migrate.SQL{
+					`ALTER TABLE pieceinfo ADD COLUMN corrupted_at TIMESTAMP`,
+				},
+			},
+			{
+				Description: "Add hourly rollups of bandwidth usage",
+				Version:     8,
+				Action: migrate.SQL{
+					`CREATE TABLE bandwidth_usage_rollups (
+						interval_start TIMESTAMP NOT NULL, -- start of the hour
+						satellite_id   BLOB      NOT NULL,
+						action         INTEGER   NOT NULL,
+						amount         BIGINT    NOT NULL,
+						PRIMARY KEY (interval_start, satellite_id, action)
+					)`,
+				},
+			},
+			{
+				Description: "Add queue of pending piece deletions",
+				Version:     9,
+				Action: migrate.SQL{
+					`CREATE TABLE pending_deletes (
+						satellite_id BLOB      NOT NULL,
+						piece_id     BLOB      NOT NULL,
+						queued_at    TIMESTAMP NOT NULL,
+						PRIMARY KEY (satellite_id, piece_id)
+					)`,
+				},
+			},
+			{
+				Description: "Add reputation reported by the satellites",
+				Version:     10,
+				Action: migrate.SQL{
+					`CREATE TABLE reputation (
+						satellite_id         BLOB      NOT NULL PRIMARY KEY,
+						audit_count          INTEGER   NOT NULL,
+						audit_success_count  INTEGER   NOT NULL,
+						audit_success_ratio  REAL      NOT NULL,
+						uptime_count         INTEGER   NOT NULL,
+						uptime_success_count INTEGER   NOT NULL,
+						uptime_ratio         REAL      NOT NULL,
+						updated_at           TIMESTAMP NOT NULL -- when the satellite reported it
+					)`,
+				},
+			},
+			{
+				Description: "Add disqualification to reputation",
+				Version:     11,
+				Action: migrate.SQL{
+					`ALTER TABLE reputation ADD COLUMN disqualified_at TIMESTAMP`,
+				},
+			},
+			{
+				Description: "Add payouts reported by the satellites",
+				Version:     12,
+				Action: migrate.SQL{
+					`CREATE TABLE payouts (
+						satellite_id  BLOB      NOT NULL,
+						period        TIMESTAMP NOT NULL, -- start of the month
+						node_month    INTEGER   NOT NULL,
+						surge_percent INTEGER   NOT NULL,
+						held_percent  INTEGER   NOT NULL,
+						gross         INTEGER   NOT NULL, -- in micro dollars
+						held          INTEGER   NOT NULL,
+						paid          INTEGER   NOT NULL,
+						updated_at    TIMESTAMP NOT NULL, -- when the satellite reported it
+						PRIMARY KEY (satellite_id, period)
+					)`,
+				},
+			},
+			{
+				Description: "Add data stored for each satellite over time",
+				Version:     13,
+				Action: migrate.SQL{
+					`CREATE TABLE storage_usage (
+						satellite_id   BLOB      NOT NULL,
+						at_rest_total  REAL      NOT NULL, -- in byte-hours
+						interval_start TIMESTAMP NOT NULL,
+						interval_end   TIMESTAMP NOT NULL,
+						PRIMARY KEY (satellite_id, interval_start)
+					)`,
+				},
+			},
+			{
+				Description: "Add action to the orders and amount to the archived orders",
+				Version:     14,
+				Action: db.addOrderActions(
+					`ALTER TABLE unsent_order ADD COLUMN order_action INTEGER NOT NULL DEFAULT 0`,
+					`ALTER TABLE order_archive ADD COLUMN order_amount INTEGER NOT NULL DEFAULT 0`,
+					`ALTER TABLE order_archive ADD COLUMN order_action INTEGER NOT NULL DEFAULT 0`,
+				),
+			},
+			{
+				Description: "Add vetting progress to reputation",
+				Version:     15,
+				Action: migrate.SQL{
+					`ALTER TABLE reputation ADD COLUMN vetted_at TIMESTAMP`,
+					`ALTER TABLE reputation ADD COLUMN vetting_audit_count INTEGER NOT NULL DEFAULT 0`,
+					`ALTER TABLE reputation ADD COLUMN vetting_uptime_count INTEGER NOT NULL DEFAULT 0`,
+				},
+			},
 		},
 	}
 }
@@ -0,0 +1,293 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storagenodedb
+
+import (
+	"database/sql"
+	"strconv"
+
+	_ "github.com/lib/pq" // register postgres to sql
+
+	"storj.io/storj/internal/migrate"
+)
+
+// newInfoPostgres opens infodb at the specified postgres connection string.
+func newInfoPostgres(source string) (*infodb, error) {
+	db, err := sql.Open("postgres", source)
+	if err != nil {
+		return nil, ErrInfo.Wrap(err)
+	}
+	db.SetMaxIdleConns(maxIdleConns)
+
+	return &infodb{db: db, driver: "postgres"}, nil
+}
+
+// rebindPostgres replaces the ? parameters with numbered $n parameters.
+func rebindPostgres(sql string) string {
+	out := make([]byte, 0, len(sql)+10)
+
+	j := 1
+	for i := 0; i < len(sql); i++ {
+		ch := sql[i]
+		if ch != '?' {
+			out = append(out, ch)
+			continue
+		}
+
+		out = append(out, '$')
+		out = append(out, strconv.Itoa(j)...)
+		j++
+	}
+
+	return string(out)
+}
+
+// postgresMigration returns the table migrations for postgres, the versions
+// match the sqlite migration steps.
+//
+// NB: unsent_order and order_archive have an explicit rowid column, which is
+// implicit in sqlite, so that orders can be listed in insertion order.
+func (db *infodb) postgresMigration() *migrate.Migration {
+	return &migrate.Migration{
+		Table: "versions",
+		Steps: []*migrate.Step{
+			{
+				Description: "Initial setup",
+				Version:     0,
+				Action: migrate.SQL{
+					// table for keeping serials that need to be verified against
+					`CREATE TABLE used_serial (
+						satellite_id  BYTEA NOT NULL,
+						serial_number BYTEA NOT NULL,
+						expiration    TIMESTAMP WITH TIME ZONE NOT NULL
+					)`,
+					// primary key on satellite id and serial number
+					`CREATE UNIQUE INDEX pk_used_serial ON used_serial(satellite_id, serial_number)`,
+					// expiration index to allow fast deletion
+					`CREATE INDEX idx_used_serial ON used_serial(expiration)`,
+
+					// certificate table for storing uplink/satellite certificates
+					`CREATE TABLE certificate (
+						cert_id       BIGSERIAL PRIMARY KEY NOT NULL,
+						node_id       BYTEA        NOT NULL, -- same NodeID can have multiple valid leaf certificates
+						peer_identity BYTEA UNIQUE NOT NULL  -- PEM encoded
+					)`,
+
+					// table for storing piece meta info
+					`CREATE TABLE pieceinfo (
+						satellite_id     BYTEA  NOT NULL,
+						piece_id         BYTEA  NOT NULL,
+						piece_size       BIGINT NOT NULL,
+						piece_expiration TIMESTAMP WITH TIME ZONE, -- date when it can be deleted
+
+						uplink_piece_hash BYTEA  NOT NULL, -- serialized pb.PieceHash signed by uplink
+						uplink_cert_id    BIGINT NOT NULL,
+
+						FOREIGN KEY(uplink_cert_id) REFERENCES certificate(cert_id)
+					)`,
+					// primary key by satellite id and piece id
+					`CREATE UNIQUE INDEX pk_pieceinfo ON pieceinfo(satellite_id, piece_id)`,
+
+					// table for storing bandwidth usage
+					`CREATE TABLE bandwidth_usage (
+						satellite_id  BYTEA   NOT NULL,
+						action        INTEGER NOT NULL,
+						amount        BIGINT  NOT NULL,
+						created_at    TIMESTAMP WITH TIME ZONE NOT NULL
+					)`,
+					`CREATE INDEX idx_bandwidth_usage_satellite ON bandwidth_usage(satellite_id)`,
+					`CREATE INDEX idx_bandwidth_usage_created   ON bandwidth_usage(created_at)`,
+
+					// table for storing all unsent orders
+					`CREATE TABLE unsent_order (
+						rowid         BIGSERIAL NOT NULL,
+						satellite_id  BYTEA NOT NULL,
+						serial_number BYTEA NOT NULL,
+
+						order_limit_serialized BYTEA NOT NULL, -- serialized pb.OrderLimit
+						order_serialized       BYTEA NOT NULL, -- serialized pb.Order
+						order_limit_expiration TIMESTAMP WITH TIME ZONE NOT NULL, -- when is the deadline for sending it
+
+						uplink_cert_id BIGINT NOT NULL,
+
+						FOREIGN KEY(uplink_cert_id) REFERENCES certificate(cert_id)
+					)`,
+					`CREATE UNIQUE INDEX idx_orders ON unsent_order(satellite_id, serial_number)`,
+					`CREATE INDEX idx_unsent_order_rowid ON unsent_order(rowid)`,
+
+					// table for storing all sent orders
+					`CREATE TABLE order_archive (
+						rowid         BIGSERIAL NOT NULL,
+						satellite_id  BYTEA NOT NULL,
+						serial_number BYTEA NOT NULL,
+
+						order_limit_serialized BYTEA NOT NULL, -- serialized pb.OrderLimit
+						order_serialized       BYTEA NOT NULL, -- serialized pb.Order
+
+						uplink_cert_id BIGINT NOT NULL,
+
+						status      INTEGER NOT NULL, -- accepted, rejected, confirmed
+						archived_at TIMESTAMP WITH TIME ZONE NOT NULL, -- when was it rejected
+
+						FOREIGN KEY(uplink_cert_id) REFERENCES certificate(cert_id)
+					)`,
+					`CREATE INDEX idx_order_archive_satellite ON order_archive(satellite_id)`,
+					`CREATE INDEX idx_order_archive_status ON order_archive(status)`,
+					`CREATE INDEX idx_order_archive_rowid ON order_archive(rowid)`,
+				},
+			},
+			{
+				Description: "Index archived orders by archival time",
+				Version:     1,
+				Action: migrate.SQL{
+					// archival time index to allow fast deletion
+					`CREATE INDEX idx_order_archive_archived_at ON order_archive(archived_at)`,
+				},
+			},
+			{
+				Description: "Add settlement backoff of satellites",
+				Version:     2,
+				Action: migrate.SQL{
+					`CREATE TABLE order_settlement_backoff (
+						satellite_id BYTEA   NOT NULL PRIMARY KEY,
+						failures     INTEGER NOT NULL, -- consecutive failed settlements
+						next_retry   TIMESTAMP WITH TIME ZONE NOT NULL -- when to settle again
+					)`,
+				},
+			},
+			{
+				Description: "Add order amount to unsent orders",
+				Version:     3,
+				Action:      db.addUnsentOrderAmount(`ALTER TABLE unsent_order ADD COLUMN order_amount BIGINT NOT NULL DEFAULT 0`),
+			},
+			{
+				Description: "Add creation time to piece info",
+				Version:     4,
+				Action:      db.addPieceCreation(`ALTER TABLE pieceinfo ADD COLUMN piece_creation TIMESTAMP WITH TIME ZONE`),
+			},
+			{
+				Description: "Index piece info by expiration",
+				Version:     5,
+				Action: migrate.SQL{
+					// expiration index to allow fast collection of expired pieces
+					`CREATE INDEX idx_pieceinfo_expiration ON pieceinfo(piece_expiration)`,
+				},
+			},
+			{
+				Description: "Add cached space used by satellite",
+				Version:     6,
+				Action: migrate.SQL{
+					`CREATE TABLE piece_space_used (
+						satellite_id BYTEA  NOT NULL PRIMARY KEY,
+						total        BIGINT NOT NULL -- bytes used by the pieces of the satellite
+					)`,
+				},
+			},
+			{
+				Description: "Add corruption flag to piece info",
+				Version:     7,
+				Action: migrate.SQL{
+					`ALTER TABLE pieceinfo ADD COLUMN corrupted_at TIMESTAMP WITH TIME ZONE`,
+				},
+			},
+			{
+				Description: "Add hourly rollups of bandwidth usage",
+				Version:     8,
+				Action: migrate.SQL{
+					`CREATE TABLE bandwidth_usage_rollups (
+						interval_start TIMESTAMP WITH TIME ZONE NOT NULL, -- start of the hour
+						satellite_id   BYTEA   NOT NULL,
+						action         INTEGER NOT NULL,
+						amount         BIGINT  NOT NULL,
+						PRIMARY KEY (interval_start, satellite_id, action)
+					)`,
+				},
+			},
+			{
+				Description: "Add queue of pending piece deletions",
+				Version:     9,
+				Action: migrate.SQL{
+					`CREATE TABLE pending_deletes (
+						satellite_id BYTEA NOT NULL,
+						piece_id     BYTEA NOT NULL,
+						queued_at    TIMESTAMP WITH TIME ZONE NOT NULL,
+						PRIMARY KEY (satellite_id, piece_id)
+					)`,
+				},
+			},
+			{
+				Description: "Add reputation reported by the satellites",
+				Version:     10,
+				Action: migrate.SQL{
+					`CREATE TABLE reputation (
+						satellite_id         BYTEA     NOT NULL PRIMARY KEY,
+						audit_count          BIGINT    NOT NULL,
+						audit_success_count  BIGINT    NOT NULL,
+						audit_success_ratio  DOUBLE PRECISION NOT NULL,
+						uptime_count         BIGINT    NOT NULL,
+						uptime_success_count BIGINT    NOT NULL,
+						uptime_ratio         DOUBLE PRECISION NOT NULL,
+						updated_at           TIMESTAMP WITH TIME ZONE NOT NULL -- when the satellite reported it
+					)`,
+				},
+			},
+			{
+				Description: "Add disqualification to reputation",
+				Version:     11,
+				Action: migrate.SQL{
+					`ALTER TABLE reputation ADD COLUMN disqualified_at TIMESTAMP WITH TIME ZONE`,
+				},
+			},
+			{
+				Description: "Add payouts reported by the satellites",
+				Version:     12,
+				Action: migrate.SQL{
+					`CREATE TABLE payouts (
+						satellite_id  BYTEA     NOT NULL,
+						period        TIMESTAMP WITH TIME ZONE NOT NULL, -- start of the month
+						node_month    INTEGER   NOT NULL,
+						surge_percent BIGINT    NOT NULL,
+						held_percent  BIGINT    NOT NULL,
+						gross         BIGINT    NOT NULL, -- in micro dollars
+						held          BIGINT    NOT NULL,
+						paid          BIGINT    NOT NULL,
+						updated_at    TIMESTAMP WITH TIME ZONE NOT NULL, -- when the satellite reported it
+						PRIMARY KEY (satellite_id, period)
+					)`,
+				},
+			},
+			{
+				Description: "Add data stored for each satellite over time",
+				Version:     13,
+				Action: migrate.SQL{
+					`CREATE TABLE storage_usage (
+						satellite_id   BYTEA     NOT NULL,
+						at_rest_total  DOUBLE PRECISION NOT NULL, -- in byte-hours
+						interval_start TIMESTAMP WITH TIME ZONE NOT NULL,
+						interval_end   TIMESTAMP WITH TIME ZONE NOT NULL,
+						PRIMARY KEY (satellite_id, interval_start)
+					)`,
+				},
+			},
+			{
+				Description: "Add action to the orders and amount to the archived orders",
+				Version:     14,
+				Action: db.addOrderActions(
+					`ALTER TABLE unsent_order ADD COLUMN order_action INTEGER NOT NULL DEFAULT 0`,
+					`ALTER TABLE order_archive ADD COLUMN order_amount BIGINT NOT NULL DEFAULT 0`,
+					`ALTER TABLE order_archive ADD COLUMN order_action INTEGER NOT NULL DEFAULT 0`,
+				),
+			},
+			{
+				Description: "Add vetting progress to reputation",
+				Version:     15,
+				Action: migrate.SQL{
+					`ALTER TABLE reputation ADD COLUMN vetted_at TIMESTAMP WITH TIME ZONE`,
+					`ALTER TABLE reputation ADD COLUMN vetting_audit_count INTEGER NOT NULL DEFAULT 0`,
+					`ALTER TABLE reputation ADD COLUMN vetting_uptime_count INTEGER NOT NULL DEFAULT 0`,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storagenodedb
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/storagenode/maintenance"
+)
+
+// autoVacuumIncremental is the auto_vacuum mode of sqlite keeping the free
+// pages for the incremental_vacuum pragma.
+const autoVacuumIncremental = 2
+
+type maintenancedb struct {
+	*infodb
+}
+
+// Maintenance returns database for maintaining the database.
+func (db *DB) Maintenance() maintenance.DB { return db.info.Maintenance() }
+
+// Maintenance returns database for maintaining the database.
+func (db *infodb) Maintenance() maintenance.DB { return &maintenancedb{db} }
+
+// IncrementalVacuum reclaims at most maxPages free pages of the database
+// and returns the number of reclaimed bytes.
+//
+// Databases created before incremental vacuuming was enabled are converted
+// with a full VACUUM first, which rewrites the whole database.
+func (db *maintenancedb) IncrementalVacuum(ctx context.Context, maxPages int) (reclaimed int64, err error) {
+	if db.driver == "postgres" {
+		// postgres reclaims the space with autovacuum
+		return 0, nil
+	}
+
+	var autoVacuum int
+	if err := db.db.QueryRowContext(ctx, `PRAGMA auto_vacuum`).Scan(&autoVacuum); err != nil {
+		return 0, ErrInfo.Wrap(err)
+	}
+
+	before, err := db.size(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if autoVacuum != autoVacuumIncremental {
+		_, err = db.db.ExecContext(ctx, `PRAGMA auto_vacuum = INCREMENTAL; VACUUM`)
+		if err != nil {
+			return 0, ErrInfo.Wrap(err)
+		}
+	} else if err := db.incrementalVacuum(ctx, maxPages); err != nil {
+		return 0, err
+	}
+
+	after, err := db.size(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return before - after, nil
+}
+
+// incrementalVacuum frees at most maxPages free pages.
+func (db *maintenancedb) incrementalVacuum(ctx context.Context, maxPages int) (err error) {
+	// NB: the pragma frees a page on every step, so the rows have to be
+	// read, and pragmas don't take query arguments
+	rows, err := db.db.QueryContext(ctx, `PRAGMA incremental_vacuum(`+strconv.Itoa(maxPages)+`)`)
+	if err != nil {
+		return ErrInfo.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	for rows.Next() {
+	}
+	return ErrInfo.Wrap(rows.Err())
+}
+
+// size returns the size of the sqlite database in bytes.
+func (db *maintenancedb) size(ctx context.Context) (int64, error) {
+	var pageCount, pageSize int64
+	if err := db.db.QueryRowContext(ctx, `PRAGMA page_count`).Scan(&pageCount); err != nil {
+		return 0, ErrInfo.Wrap(err)
+	}
+	if err := db.db.QueryRowContext(ctx, `PRAGMA page_size`).Scan(&pageSize); err != nil {
+		return 0, ErrInfo.Wrap(err)
+	}
+	return pageCount * pageSize, nil
+}
+
+// Analyze updates the statistics used for planning the queries.
+func (db *maintenancedb) Analyze(ctx context.Context) error {
+	_, err := db.db.ExecContext(ctx, `ANALYZE`)
+	return ErrInfo.Wrap(err)
+}
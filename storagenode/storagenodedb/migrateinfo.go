@@ -0,0 +1,136 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storagenodedb
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"strings"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+)
+
+// infoTable is a table of infodb with the columns copied by MigrateInfo.
+type infoTable struct {
+	Name    string
+	Columns []string
+}
+
+// infoTables are the tables of infodb, in an order satisfying the foreign keys.
+var infoTables = []infoTable{
+	{"certificate", []string{"cert_id", "node_id", "peer_identity"}},
+	{"used_serial", []string{"satellite_id", "serial_number", "expiration"}},
+	{"pieceinfo", []string{"satellite_id", "piece_id", "piece_size", "piece_expiration", "uplink_piece_hash", "uplink_cert_id", "piece_creation", "corrupted_at"}},
+	{"bandwidth_usage", []string{"satellite_id", "action", "amount", "created_at"}},
+	{"bandwidth_usage_rollups", []string{"interval_start", "satellite_id", "action", "amount"}},
+	{"pending_deletes", []string{"satellite_id", "piece_id", "queued_at"}},
+	{"unsent_order", []string{"satellite_id", "serial_number", "order_limit_serialized", "order_serialized", "order_limit_expiration", "uplink_cert_id", "order_amount", "order_action"}},
+	{"order_archive", []string{"satellite_id", "serial_number", "order_limit_serialized", "order_serialized", "uplink_cert_id", "status", "archived_at", "order_amount", "order_action"}},
+	{"order_settlement_backoff", []string{"satellite_id", "failures", "next_retry"}},
+	{"piece_space_used", []string{"satellite_id", "total"}},
+	{"reputation", []string{"satellite_id", "audit_count", "audit_success_count", "audit_success_ratio", "uptime_count", "uptime_success_count", "uptime_ratio", "updated_at", "disqualified_at", "vetted_at", "vetting_audit_count", "vetting_uptime_count"}},
+	{"payouts", []string{"satellite_id", "period", "node_month", "surge_percent", "held_percent", "gross", "held", "paid", "updated_at"}},
+	{"storage_usage", []string{"satellite_id", "at_rest_total", "interval_start", "interval_end"}},
+}
+
+// MigrateInfo copies the content of the sqlite info.db at infoPath into the
+// empty database at databaseURL, after creating the tables of both.
+func MigrateInfo(ctx context.Context, log *zap.Logger, infoPath, databaseURL string) (err error) {
+	if _, err := os.Stat(infoPath); err != nil {
+		return ErrInfo.Wrap(err)
+	}
+
+	source, err := newInfo(infoPath)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, source.Close()) }()
+
+	target, err := newInfoURL(databaseURL)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, target.Close()) }()
+
+	if err := source.CreateTables(log.Named("source")); err != nil {
+		return err
+	}
+	if err := target.CreateTables(log.Named("target")); err != nil {
+		return err
+	}
+
+	for _, table := range infoTables {
+		var count int64
+		err := target.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM `+table.Name).Scan(&count)
+		if err != nil {
+			return ErrInfo.Wrap(err)
+		}
+		if count > 0 {
+			return ErrInfo.New("table %s of the target database is not empty", table.Name)
+		}
+	}
+
+	tx, err := target.db.BeginTx(ctx, nil)
+	if err != nil {
+		return ErrInfo.Wrap(err)
+	}
+	defer func() {
+		if err != nil {
+			err = errs.Combine(err, ErrInfo.Wrap(tx.Rollback()))
+		} else {
+			err = ErrInfo.Wrap(tx.Commit())
+		}
+	}()
+
+	for _, table := range infoTables {
+		count, err := copyTable(ctx, source, target, tx, table)
+		if err != nil {
+			return err
+		}
+		log.Info("copied", zap.String("table", table.Name), zap.Int64("rows", count))
+	}
+
+	if target.driver == "postgres" {
+		// the copied cert_id-s don't advance the sequence
+		_, err = tx.ExecContext(ctx, `SELECT setval(pg_get_serial_sequence('certificate', 'cert_id'), MAX(cert_id)) FROM certificate`)
+		if err != nil {
+			return ErrInfo.Wrap(err)
+		}
+	}
+
+	return nil
+}
+
+// copyTable copies the rows of table from source to target in insertion order.
+func copyTable(ctx context.Context, source, target *infodb, tx *sql.Tx, table infoTable) (count int64, err error) {
+	columns := strings.Join(table.Columns, ", ")
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(table.Columns)), ", ")
+	insert := target.Rebind(`INSERT INTO ` + table.Name + `(` + columns + `) VALUES (` + placeholders + `)`)
+
+	rows, err := source.db.QueryContext(ctx, `SELECT `+columns+` FROM `+table.Name+` ORDER BY rowid`)
+	if err != nil {
+		return 0, ErrInfo.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	values := make([]interface{}, len(table.Columns))
+	pointers := make([]interface{}, len(table.Columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return count, ErrInfo.Wrap(err)
+		}
+		if _, err := tx.ExecContext(ctx, insert, values...); err != nil {
+			return count, ErrInfo.Wrap(err)
+		}
+		count++
+	}
+
+	return count, ErrInfo.Wrap(rows.Err())
+}
@@ -0,0 +1,106 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storagenodedb_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storagenode/orders"
+	"storj.io/storj/storagenode/storagenodedb"
+)
+
+func TestMigrateInfo(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	log := zaptest.NewLogger(t)
+
+	satelliteID := testplanet.MustPregeneratedSignedIdentity(1).ID
+	uplink := testplanet.MustPregeneratedSignedIdentity(3)
+
+	open := func(name, databaseURL string) *storagenodedb.DB {
+		dir := ctx.Dir(name)
+		db, err := storagenodedb.New(log, storagenodedb.Config{
+			Storage:  dir,
+			Info:     filepath.Join(dir, "piecestore.db"),
+			Info2:    filepath.Join(dir, "info.db"),
+			Pieces:   dir,
+			Kademlia: filepath.Join(dir, "kademlia"),
+
+			DatabaseURL: databaseURL,
+		})
+		require.NoError(t, err)
+		require.NoError(t, db.CreateTables())
+		return db
+	}
+
+	source := open("source", "")
+	now := time.Now()
+	timestamp := ptypes.TimestampNow()
+	for i := byte(0); i < 3; i++ {
+		serialNumber := storj.SerialNumber{i}
+		err := source.Orders().Enqueue(ctx, &orders.Info{
+			Limit: &pb.OrderLimit2{
+				SerialNumber:    serialNumber,
+				SatelliteId:     satelliteID,
+				UplinkId:        uplink.ID,
+				StorageNodeId:   testplanet.MustPregeneratedSignedIdentity(0).ID,
+				PieceId:         storj.NewPieceID(),
+				Limit:           100,
+				Action:          pb.PieceAction_GET,
+				PieceExpiration: timestamp,
+				OrderExpiration: timestamp,
+			},
+			Order: &pb.Order2{
+				SerialNumber: serialNumber,
+				Amount:       50,
+			},
+			Uplink: uplink.PeerIdentity(),
+		})
+		require.NoError(t, err)
+		require.NoError(t, source.Bandwidth().Add(ctx, satelliteID, pb.PieceAction_GET, 50, now))
+	}
+	require.NoError(t, source.Orders().Archive(ctx, satelliteID, storj.SerialNumber{0}, orders.StatusAccepted))
+	require.NoError(t, source.Close())
+
+	targetURL := "sqlite3://" + filepath.Join(ctx.Dir("target"), "target.db")
+	err := storagenodedb.MigrateInfo(ctx, log, filepath.Join(ctx.Dir("source"), "info.db"), targetURL)
+	require.NoError(t, err)
+
+	// copying into a database which isn't empty fails
+	err = storagenodedb.MigrateInfo(ctx, log, filepath.Join(ctx.Dir("source"), "info.db"), targetURL)
+	require.Error(t, err)
+
+	target := open("copy", targetURL)
+	defer ctx.Check(target.Close)
+
+	unsent, err := target.Orders().ListUnsent(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, unsent, 2)
+	require.Equal(t, uplink.ID, unsent[0].Uplink.ID)
+
+	archived, _, err := target.Orders().ListArchived(ctx, orders.ArchiveCursor{}, 10)
+	require.NoError(t, err)
+	require.Len(t, archived, 1)
+	require.Equal(t, orders.StatusAccepted, archived[0].Status)
+
+	usage, err := target.Bandwidth().Summary(ctx, now.Add(-time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, int64(150), usage.Get)
+
+	// the copied certificates are reused
+	_, err = target.CertDB().Include(ctx, uplink.PeerIdentity())
+	require.NoError(t, err)
+	require.NoError(t, target.Orders().Archive(ctx, satelliteID, storj.SerialNumber{1}, orders.StatusRejected))
+}
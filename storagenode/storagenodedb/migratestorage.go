@@ -0,0 +1,436 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storagenodedb
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/pkcrypto"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/filestore"
+	"storj.io/storj/storagenode/pieces"
+)
+
+// ErrMigrateStorage is the error class for migrating the storage directory.
+var ErrMigrateStorage = errs.Class("migrate storage")
+
+// migrateStorageLogInterval is the number of blobs copied between logging the progress.
+const migrateStorageLogInterval = 1000
+
+// migrateStorageDirs are the directories with the trashed and the quarantined
+// pieces in the storage directory, which are copied as plain files.
+var migrateStorageDirs = []string{"trash", "quarantine"}
+
+// MigrateStorage copies the blobs stored in the directory from, with the
+// trashed and the quarantined pieces, and the sqlite databases of config in
+// that directory, into the directory to. The directory from is left untouched,
+// the storage node uses the copies once its storage path is changed to the
+// directory to.
+//
+// The copy of every file is verified against the source and the pieces are
+// checked against the hash signed by the uplink. An interrupted migration
+// skips the files already copied. At most bytesPerSecond bytes are copied per
+// second, unlimited when 0. The storage node must be stopped.
+func MigrateStorage(ctx context.Context, log *zap.Logger, config Config, from, to string, bytesPerSecond int64) (err error) {
+	if filepath.Clean(from) == filepath.Clean(to) {
+		return ErrMigrateStorage.New("the directories are the same")
+	}
+	if config.DatabaseURL == "" {
+		if _, err := os.Stat(config.Info2); err != nil {
+			return ErrMigrateStorage.Wrap(err)
+		}
+	}
+
+	var info *infodb
+	if config.DatabaseURL != "" {
+		info, err = newInfoURL(config.DatabaseURL)
+	} else {
+		info, err = newInfo(config.Info2)
+	}
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, info.Close()) }()
+
+	if err := info.CreateTables(log); err != nil {
+		return err
+	}
+
+	source, err := filestore.NewAt(from)
+	if err != nil {
+		return ErrMigrateStorage.Wrap(err)
+	}
+	target, err := filestore.NewAtConfig(to, filestore.Config{Preallocate: config.Preallocate})
+	if err != nil {
+		return ErrMigrateStorage.Wrap(err)
+	}
+
+	migration := &storageMigration{
+		log:        log,
+		pieceinfos: info.PieceInfo(),
+		source:     source,
+		target:     target,
+		throttle:   throttle{bytesPerSecond: bytesPerSecond, start: time.Now()},
+	}
+	if err := migration.copyBlobs(ctx); err != nil {
+		return err
+	}
+	for _, dir := range migrateStorageDirs {
+		if err := migration.copyFiles(ctx, filepath.Join(from, dir), filepath.Join(to, dir)); err != nil {
+			return err
+		}
+	}
+
+	for _, database := range sqliteDatabases(config) {
+		if filepath.Clean(filepath.Dir(database.Path)) != filepath.Clean(from) {
+			continue
+		}
+		if err := copyDatabase(database.Path, filepath.Join(to, filepath.Base(database.Path))); err != nil {
+			return err
+		}
+		log.Info("copied database", zap.String("database", database.Path))
+	}
+	return nil
+}
+
+// storageMigration copies the pieces from one blob store into another.
+type storageMigration struct {
+	log        *zap.Logger
+	pieceinfos pieces.DB
+	source     *filestore.Store
+	target     *filestore.Store
+	throttle   throttle
+
+	copied      int64
+	copiedBytes int64
+}
+
+// copyBlobs copies the blobs of every namespace of the source.
+func (migration *storageMigration) copyBlobs(ctx context.Context) error {
+	err := migration.source.Walk(ctx, func(blob storage.BlobInfo) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		size, err := migration.copyBlob(ctx, blob)
+		if err != nil {
+			return err
+		}
+		migration.addCopied(size)
+		return nil
+	})
+	if err != nil {
+		return ErrMigrateStorage.Wrap(err)
+	}
+	migration.log.Info("copied blobs", zap.Int64("count", migration.copied), zap.Int64("bytes", migration.copiedBytes))
+	return nil
+}
+
+// addCopied counts a copied file of size, logging the progress periodically.
+// The files which were copied before are counted with size -1.
+func (migration *storageMigration) addCopied(size int64) {
+	if size < 0 {
+		return
+	}
+	migration.copied++
+	migration.copiedBytes += size
+	if migration.copied%migrateStorageLogInterval == 0 {
+		migration.log.Info("copied files", zap.Int64("count", migration.copied), zap.Int64("bytes", migration.copiedBytes))
+	}
+}
+
+// copyBlob copies the blob and verifies the copy, returning its size or -1
+// when the blob was already copied.
+func (migration *storageMigration) copyBlob(ctx context.Context, blob storage.BlobInfo) (_ int64, err error) {
+	reader, err := migration.source.Open(ctx, blob.Ref)
+	if err != nil {
+		// the blob is trashed or deleted while walking, when the storage
+		// node is running despite the warnings
+		if os.IsNotExist(err) {
+			return -1, nil
+		}
+		return 0, ErrMigrateStorage.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, reader.Close()) }()
+
+	size, err := reader.Size()
+	if err != nil {
+		return 0, ErrMigrateStorage.Wrap(err)
+	}
+	format := reader.StorageFormatVersion()
+
+	// the blobs are committed at once, a blob of the same size is a copy
+	// of an interrupted migration
+	if copied, err := migration.target.Open(ctx, blob.Ref); err == nil {
+		copiedSize, err := copied.Size()
+		copiedFormat := copied.StorageFormatVersion()
+		if err := errs.Combine(err, copied.Close()); err != nil {
+			return 0, ErrMigrateStorage.Wrap(err)
+		}
+		if copiedSize == size && copiedFormat == format {
+			return -1, nil
+		}
+	}
+
+	if err := migration.throttle.wait(ctx, size); err != nil {
+		return 0, err
+	}
+
+	// NB: the blobs keep their format, the header of the pieces of format
+	// V1 is copied as is and isn't part of the hash
+	writer, err := migration.target.Create(ctx, blob.Ref, format, size)
+	if err != nil {
+		return 0, ErrMigrateStorage.Wrap(err)
+	}
+	if _, err := io.CopyN(writer, reader, headerSize(reader)); err != nil {
+		return 0, ErrMigrateStorage.Wrap(errs.Combine(err, writer.Cancel()))
+	}
+	sourceHash := pkcrypto.NewHash()
+	if _, err := io.Copy(io.MultiWriter(writer, sourceHash), reader); err != nil {
+		return 0, ErrMigrateStorage.Wrap(errs.Combine(err, writer.Cancel()))
+	}
+	if err := writer.Commit(); err != nil {
+		return 0, ErrMigrateStorage.Wrap(err)
+	}
+
+	targetHash, err := hashBlob(ctx, migration.target, blob.Ref)
+	if err != nil {
+		return 0, err
+	}
+	if !bytes.Equal(targetHash, sourceHash.Sum(nil)) {
+		return 0, ErrMigrateStorage.New("copy of blob %x in namespace %x doesn't match the source", blob.Ref.Key, blob.Ref.Namespace)
+	}
+
+	if err := migration.checkPiece(ctx, blob.Ref, targetHash); err != nil {
+		return 0, err
+	}
+	return size, nil
+}
+
+// checkPiece warns when the hash of the piece stored in the blob ref doesn't
+// match the hash signed by the uplink. The blobs which aren't pieces with
+// stored info aren't checked.
+func (migration *storageMigration) checkPiece(ctx context.Context, ref storage.BlobRef, hash []byte) error {
+	satelliteID, err := storj.NodeIDFromBytes(ref.Namespace)
+	if err != nil {
+		return nil
+	}
+	pieceID, err := storj.PieceIDFromBytes(ref.Key)
+	if err != nil {
+		return nil
+	}
+
+	info, err := migration.pieceinfos.Get(ctx, satelliteID, pieceID)
+	if err != nil {
+		if errs.Unwrap(err) == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	if !bytes.Equal(hash, info.UplinkPieceHash.GetHash()) {
+		// NB: the piece is copied as is, the scrubber quarantines it
+		migration.log.Warn("piece was corrupted before the migration",
+			zap.Stringer("Satellite ID", satelliteID),
+			zap.Stringer("Piece ID", pieceID))
+	}
+	return nil
+}
+
+// copyFiles copies the files in the directory from into the directory to,
+// keeping their modification time, which is when the pieces were trashed.
+// The files already copied are skipped.
+func (migration *storageMigration) copyFiles(ctx context.Context, from, to string) error {
+	err := filepath.Walk(from, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// the directory doesn't exist when nothing was trashed or
+			// quarantined
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(from, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(to, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, 0700)
+		}
+
+		// the files are replaced at once, a file of the same size is a copy
+		// of an interrupted migration
+		if copied, err := os.Stat(target); err == nil && copied.Size() == info.Size() {
+			return nil
+		}
+
+		size, err := migration.copyFile(ctx, path, target, info)
+		if err != nil {
+			return err
+		}
+		migration.addCopied(size)
+		return nil
+	})
+	if err != nil {
+		return ErrMigrateStorage.Wrap(err)
+	}
+	migration.log.Info("copied directory", zap.String("directory", from))
+	return nil
+}
+
+// copyFile copies the file at path into target and verifies the copy,
+// returning its size.
+func (migration *storageMigration) copyFile(ctx context.Context, path, target string, info os.FileInfo) (int64, error) {
+	if err := migration.throttle.wait(ctx, info.Size()); err != nil {
+		return 0, err
+	}
+	if err := copyFileAtomic(path, target); err != nil {
+		return 0, err
+	}
+	if err := os.Chtimes(target, info.ModTime(), info.ModTime()); err != nil {
+		return 0, err
+	}
+
+	sourceHash, err := hashFile(path)
+	if err != nil {
+		return 0, err
+	}
+	targetHash, err := hashFile(target)
+	if err != nil {
+		return 0, err
+	}
+	if !bytes.Equal(sourceHash, targetHash) {
+		return 0, ErrMigrateStorage.New("copy of %s doesn't match the source", path)
+	}
+	return info.Size(), nil
+}
+
+// hashFile hashes the content of the file at path.
+func hashFile(path string) (_ []byte, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = errs.Combine(err, file.Close()) }()
+
+	hash := pkcrypto.NewHash()
+	if _, err := io.Copy(hash, file); err != nil {
+		return nil, err
+	}
+	return hash.Sum(nil), nil
+}
+
+// hashBlob hashes the piece data of the blob.
+func hashBlob(ctx context.Context, blobs storage.Blobs, ref storage.BlobRef) (_ []byte, err error) {
+	reader, err := blobs.Open(ctx, ref)
+	if err != nil {
+		return nil, ErrMigrateStorage.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, reader.Close()) }()
+
+	if _, err := reader.Seek(headerSize(reader), io.SeekStart); err != nil {
+		return nil, ErrMigrateStorage.Wrap(err)
+	}
+	hash := pkcrypto.NewHash()
+	if _, err := io.Copy(hash, reader); err != nil {
+		return nil, ErrMigrateStorage.Wrap(err)
+	}
+	return hash.Sum(nil), nil
+}
+
+// headerSize returns the size of the piece header at the start of the blob.
+func headerSize(reader storage.BlobReader) int64 {
+	if reader.StorageFormatVersion() == storage.FormatV1 {
+		return pieces.V1PieceHeaderReservedArea
+	}
+	return 0
+}
+
+// copyDatabase copies the sqlite database at path, with its write-ahead log,
+// into target. Each file is replaced at once, so that an interrupted copy
+// doesn't leave a partial database behind.
+func copyDatabase(path, target string) error {
+	for _, suffix := range []string{"", "-wal"} {
+		err := copyFileAtomic(path+suffix, target+suffix)
+		if os.IsNotExist(err) {
+			err = os.Remove(target + suffix)
+			if os.IsNotExist(err) {
+				err = nil
+			}
+		}
+		if err != nil {
+			return ErrMigrateStorage.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// copyFileAtomic replaces the file at target with a copy of the file at path.
+func copyFileAtomic(path, target string) (err error) {
+	source, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, source.Close()) }()
+
+	temp := target + ".tmp"
+	file, err := os.OpenFile(temp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(file, source)
+	if err == nil {
+		err = file.Sync()
+	}
+	if err := errs.Combine(err, file.Close()); err != nil {
+		return err
+	}
+	return os.Rename(temp, target)
+}
+
+// throttle limits the rate of the copied bytes.
+type throttle struct {
+	bytesPerSecond int64
+	start          time.Time
+	total          int64
+}
+
+// wait waits until size more bytes can be copied.
+func (throttle *throttle) wait(ctx context.Context, size int64) error {
+	if throttle.bytesPerSecond <= 0 {
+		return nil
+	}
+
+	expected := time.Duration(float64(throttle.total) / float64(throttle.bytesPerSecond) * float64(time.Second))
+	throttle.total += size
+
+	delay := expected - time.Since(throttle.start)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storagenodedb_test
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/pkg/auth/signing"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/pkcrypto"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storage"
+	"storj.io/storj/storagenode/pieces"
+	"storj.io/storj/storagenode/storagenodedb"
+)
+
+func TestMigrateStorage(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	log := zaptest.NewLogger(t)
+	from, to := ctx.Dir("from"), ctx.Dir("to")
+
+	config := func(dir string) storagenodedb.Config {
+		return storagenodedb.Config{
+			Storage:  dir,
+			Info:     filepath.Join(dir, "piecestore.db"),
+			Info2:    filepath.Join(dir, "info.db"),
+			Pieces:   dir,
+			Kademlia: filepath.Join(ctx.Dir("kademlia"), "kademlia"),
+		}
+	}
+
+	satellite := testplanet.MustPregeneratedSignedIdentity(0)
+	uplink := testplanet.MustPregeneratedSignedIdentity(1)
+
+	db, err := storagenodedb.New(log, config(from))
+	require.NoError(t, err)
+	require.NoError(t, db.CreateTables())
+
+	store := pieces.NewStore(log, db.Pieces())
+	data := map[storj.PieceID][]byte{}
+	for i := 0; i < 5; i++ {
+		pieceID := storj.NewPieceID()
+		data[pieceID] = []byte{byte(i), 1, 2, 3}
+
+		writer, err := store.Writer(ctx, satellite.ID, pieceID, -1)
+		require.NoError(t, err)
+		_, err = writer.Write(data[pieceID])
+		require.NoError(t, err)
+		require.NoError(t, writer.Commit(&pb.PieceHeader{}))
+
+		pieceHash, err := signing.SignPieceHash(
+			signing.SignerFromFullIdentity(uplink),
+			&pb.PieceHash{PieceId: pieceID, Hash: pkcrypto.SHA256Hash(data[pieceID])})
+		require.NoError(t, err)
+
+		require.NoError(t, db.PieceInfo().Add(ctx, &pieces.Info{
+			SatelliteID:     satellite.ID,
+			PieceID:         pieceID,
+			PieceSize:       int64(len(data[pieceID])),
+			PieceCreation:   time.Now(),
+			UplinkPieceHash: pieceHash,
+			Uplink:          uplink.PeerIdentity(),
+		}))
+	}
+
+	// the pieces without info, the trashed and the quarantined pieces are copied too
+	unknownID := storj.NewPieceID()
+	data[unknownID] = []byte{5, 6, 7}
+	writer, err := store.Writer(ctx, satellite.ID, unknownID, -1)
+	require.NoError(t, err)
+	_, err = writer.Write(data[unknownID])
+	require.NoError(t, err)
+	require.NoError(t, writer.Commit(&pb.PieceHeader{}))
+
+	trashedID := storj.NewPieceID()
+	writer, err = store.Writer(ctx, satellite.ID, trashedID, -1)
+	require.NoError(t, err)
+	_, err = writer.Write([]byte{8, 9})
+	require.NoError(t, err)
+	require.NoError(t, writer.Commit(&pb.PieceHeader{}))
+	trashedRef := storage.BlobRef{Namespace: satellite.ID.Bytes(), Key: trashedID.Bytes()}
+	require.NoError(t, db.Pieces().Trash(ctx, trashedRef))
+
+	quarantined := filepath.Join("quarantine", satellite.ID.String(), storj.NewPieceID().String())
+	require.NoError(t, os.MkdirAll(filepath.Join(from, filepath.Dir(quarantined)), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(from, quarantined), []byte{10, 11}, 0600))
+	require.NoError(t, db.Close())
+
+	require.NoError(t, storagenodedb.MigrateStorage(ctx, log, config(from), from, to, 0))
+	// a repeated migration skips the files already copied
+	require.NoError(t, storagenodedb.MigrateStorage(ctx, log, config(from), from, to, 0))
+
+	_, err = os.Stat(filepath.Join(from, "info.db"))
+	require.NoError(t, err, "the source is left untouched")
+
+	db, err = storagenodedb.New(log, config(to))
+	require.NoError(t, err)
+	defer ctx.Check(db.Close)
+	require.NoError(t, db.CreateTables())
+
+	store = pieces.NewStore(log, db.Pieces())
+	for pieceID, expected := range data {
+		reader, err := store.Reader(ctx, satellite.ID, pieceID)
+		require.NoError(t, err)
+		actual := make([]byte, reader.Size())
+		_, err = io.ReadFull(reader, actual)
+		require.NoError(t, err)
+		require.NoError(t, reader.Close())
+		require.Equal(t, expected, actual)
+	}
+
+	restored, err := db.Pieces().RestoreTrash(ctx, satellite.ID.Bytes())
+	require.NoError(t, err)
+	require.Len(t, restored, 1)
+	require.Equal(t, trashedRef, restored[0].Ref)
+
+	quarantinedData, err := ioutil.ReadFile(filepath.Join(to, quarantined))
+	require.NoError(t, err)
+	require.Equal(t, []byte{10, 11}, quarantinedData)
+}
@@ -6,12 +6,14 @@ package storagenodedb
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"time"
 
-	"github.com/gogo/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/zeebo/errs"
+	"go.uber.org/zap"
 
+	"storj.io/storj/internal/migrate"
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/storj"
 	"storj.io/storj/storagenode/orders"
@@ -25,7 +27,8 @@ func (db *DB) Orders() orders.DB { return db.info.Orders() }
 // Orders returns database for storing orders
 func (db *infodb) Orders() orders.DB { return &ordersdb{db} }
 
-// Enqueue inserts order to the unsent list
+// Enqueue inserts order to the unsent list, it fails with
+// orders.ErrSerialAlreadyExists when the serial number is already in the list.
 func (db *ordersdb) Enqueue(ctx context.Context, info *orders.Info) error {
 	certdb := db.CertDB()
 
@@ -34,12 +37,12 @@ func (db *ordersdb) Enqueue(ctx context.Context, info *orders.Info) error {
 		return ErrInfo.Wrap(err)
 	}
 
-	limitSerialized, err := proto.Marshal(info.Limit)
+	limitSerialized, err := db.marshal(info.Limit)
 	if err != nil {
 		return ErrInfo.Wrap(err)
 	}
 
-	orderSerialized, err := proto.Marshal(info.Order)
+	orderSerialized, err := db.marshal(info.Order)
 	if err != nil {
 		return ErrInfo.Wrap(err)
 	}
@@ -49,29 +52,29 @@ func (db *ordersdb) Enqueue(ctx context.Context, info *orders.Info) error {
 		return ErrInfo.Wrap(err)
 	}
 
-	defer db.locked()()
-
-	_, err = db.db.Exec(`
-		INSERT INTO unsent_order(
-			satellite_id, serial_number,
-			order_limit_serialized, order_serialized, order_limit_expiration,
-			uplink_cert_id
-		) VALUES (?,?, ?,?,?, ?)
-	`, info.Limit.SatelliteId, info.Limit.SerialNumber, limitSerialized, orderSerialized, expirationTime, uplinkCertID)
+	result, err := db.execStatement(ctx, stmtEnqueueOrder, info.Limit.SatelliteId, info.Limit.SerialNumber, limitSerialized, orderSerialized, expirationTime, uplinkCertID, info.Order.Amount, int(info.Limit.Action))
+	if err != nil {
+		return ErrInfo.Wrap(err)
+	}
 
-	return ErrInfo.Wrap(err)
+	count, err := result.RowsAffected()
+	if err != nil {
+		return ErrInfo.Wrap(err)
+	}
+	if count == 0 {
+		return orders.ErrSerialAlreadyExists.New("order %s of satellite %s", info.Limit.SerialNumber, info.Limit.SatelliteId)
+	}
+	return nil
 }
 
 // ListUnsent returns orders that haven't been sent yet.
 func (db *ordersdb) ListUnsent(ctx context.Context, limit int) (_ []*orders.Info, err error) {
-	defer db.locked()()
-
-	rows, err := db.db.Query(`
+	rows, err := db.conn().QueryContext(ctx, db.Rebind(`
 		SELECT order_limit_serialized, order_serialized, certificate.peer_identity
 		FROM unsent_order
 		INNER JOIN certificate on unsent_order.uplink_cert_id = certificate.cert_id
 		LIMIT ?
-	`, limit)
+	`), limit)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -95,17 +98,17 @@ func (db *ordersdb) ListUnsent(ctx context.Context, limit int) (_ []*orders.Info
 		info.Limit = &pb.OrderLimit2{}
 		info.Order = &pb.Order2{}
 
-		err = proto.Unmarshal(limitSerialized, info.Limit)
+		err = db.unmarshal(limitSerialized, info.Limit)
 		if err != nil {
 			return nil, ErrInfo.Wrap(err)
 		}
 
-		err = proto.Unmarshal(orderSerialized, info.Order)
+		err = db.unmarshal(orderSerialized, info.Order)
 		if err != nil {
 			return nil, ErrInfo.Wrap(err)
 		}
 
-		info.Uplink, err = decodePeerIdentity(uplinkIdentity)
+		info.Uplink, err = db.decodePeerIdentity(uplinkIdentity)
 		if err != nil {
 			return nil, ErrInfo.Wrap(err)
 		}
@@ -116,16 +119,41 @@ func (db *ordersdb) ListUnsent(ctx context.Context, limit int) (_ []*orders.Info
 	return infos, ErrInfo.Wrap(rows.Err())
 }
 
-// ListUnsentBySatellite returns orders that haven't been sent yet grouped by satellite.
+// errLimitReached stops IterateUnsent once enough orders were listed.
+var errLimitReached = errs.New("limit reached")
+
+// ListUnsentBySatellite returns at most limit orders per satellite that haven't been sent yet grouped by satellite.
 // Does not return uplink identity.
-func (db *ordersdb) ListUnsentBySatellite(ctx context.Context) (map[storj.NodeID][]*orders.Info, error) {
-	defer db.locked()()
-	// TODO: add some limiting
+func (db *ordersdb) ListUnsentBySatellite(ctx context.Context, limit int) (map[storj.NodeID][]*orders.Info, error) {
+	satellites, err := db.ListUnsentSatellites(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	rows, err := db.db.Query(`
-		SELECT order_limit_serialized, order_serialized
-		FROM unsent_order
-	`)
+	infos := map[storj.NodeID][]*orders.Info{}
+	for _, satelliteID := range satellites {
+		var batch []*orders.Info
+		err := db.IterateUnsent(ctx, orders.UnsentFilter{SatelliteID: satelliteID}, func(info *orders.Info) error {
+			if len(batch) >= limit {
+				return errLimitReached
+			}
+			batch = append(batch, info)
+			return nil
+		})
+		if err != nil && err != errLimitReached {
+			return nil, err
+		}
+		if len(batch) > 0 {
+			infos[satelliteID] = batch
+		}
+	}
+
+	return infos, nil
+}
+
+// ListUnsentSatellites returns the satellites which have orders that haven't been sent yet.
+func (db *ordersdb) ListUnsentSatellites(ctx context.Context) (_ []storj.NodeID, err error) {
+	rows, err := db.conn().QueryContext(ctx, db.Rebind(`SELECT DISTINCT satellite_id FROM unsent_order`))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -134,103 +162,473 @@ func (db *ordersdb) ListUnsentBySatellite(ctx context.Context) (map[storj.NodeID
 	}
 	defer func() { err = errs.Combine(err, rows.Close()) }()
 
-	infos := map[storj.NodeID][]*orders.Info{}
+	var satellites []storj.NodeID
+	for rows.Next() {
+		var satelliteID storj.NodeID
+		if err := rows.Scan(&satelliteID); err != nil {
+			return nil, ErrInfo.Wrap(err)
+		}
+		satellites = append(satellites, satelliteID)
+	}
+
+	return satellites, ErrInfo.Wrap(rows.Err())
+}
+
+// ListUnsentBatch returns at most limit orders of the cursor satellite that haven't been sent yet,
+// after the cursor position, and the cursor of the next batch.
+// Does not return uplink identity.
+func (db *ordersdb) ListUnsentBatch(ctx context.Context, cursor orders.UnsentCursor, limit int) (_ []*orders.Info, _ orders.UnsentCursor, err error) {
+	rows, err := db.conn().QueryContext(ctx, db.Rebind(`
+		SELECT rowid, order_limit_serialized, order_serialized
+		FROM unsent_order
+		WHERE satellite_id = ? AND rowid > ?
+		ORDER BY rowid
+		LIMIT ?
+	`), cursor.SatelliteID, cursor.Position, limit)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, cursor, nil
+		}
+		return nil, cursor, ErrInfo.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	var infos []*orders.Info
+	for rows.Next() {
+		var position int64
+		var limitSerialized []byte
+		var orderSerialized []byte
+
+		err := rows.Scan(&position, &limitSerialized, &orderSerialized)
+		if err != nil {
+			return nil, cursor, ErrInfo.Wrap(err)
+		}
+		cursor.Position = position
+
+		var info orders.Info
+		info.Limit = &pb.OrderLimit2{}
+		info.Order = &pb.Order2{}
+
+		err = db.unmarshal(limitSerialized, info.Limit)
+		if err != nil {
+			return nil, cursor, ErrInfo.Wrap(err)
+		}
+
+		err = db.unmarshal(orderSerialized, info.Order)
+		if err != nil {
+			return nil, cursor, ErrInfo.Wrap(err)
+		}
+
+		infos = append(infos, &info)
+	}
+
+	return infos, cursor, ErrInfo.Wrap(rows.Err())
+}
+
+// IterateUnsent calls fn for the orders that haven't been sent yet matching
+// filter, in insertion order.
+// Does not return uplink identity.
+// Note, fn must not use the database.
+func (db *ordersdb) IterateUnsent(ctx context.Context, filter orders.UnsentFilter, fn func(*orders.Info) error) (err error) {
+	var conditions []string
+	var args []interface{}
+	if !filter.SatelliteID.IsZero() {
+		conditions = append(conditions, "satellite_id = ?")
+		args = append(args, filter.SatelliteID)
+	}
+	if !filter.ExpiresAfter.IsZero() {
+		conditions = append(conditions, "order_limit_expiration >= ?")
+		args = append(args, filter.ExpiresAfter)
+	}
+	if !filter.ExpiresBefore.IsZero() {
+		conditions = append(conditions, "order_limit_expiration < ?")
+		args = append(args, filter.ExpiresBefore)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	rows, err := db.conn().QueryContext(ctx, db.Rebind(`
+		SELECT order_limit_serialized, order_serialized
+		FROM unsent_order
+		`+where+`
+		ORDER BY rowid
+	`), args...)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return ErrInfo.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
 	for rows.Next() {
 		var limitSerialized []byte
 		var orderSerialized []byte
 
 		err := rows.Scan(&limitSerialized, &orderSerialized)
 		if err != nil {
-			return nil, ErrInfo.Wrap(err)
+			return ErrInfo.Wrap(err)
 		}
 
 		var info orders.Info
 		info.Limit = &pb.OrderLimit2{}
 		info.Order = &pb.Order2{}
 
-		err = proto.Unmarshal(limitSerialized, info.Limit)
+		err = db.unmarshal(limitSerialized, info.Limit)
 		if err != nil {
+			return ErrInfo.Wrap(err)
+		}
+
+		err = db.unmarshal(orderSerialized, info.Order)
+		if err != nil {
+			return ErrInfo.Wrap(err)
+		}
+
+		if err := fn(&info); err != nil {
+			return err
+		}
+	}
+
+	return ErrInfo.Wrap(rows.Err())
+}
+
+// SummarizeUnsent returns the number and the total amount of the orders that
+// haven't been sent yet by satellite.
+func (db *ordersdb) SummarizeUnsent(ctx context.Context) (_ map[storj.NodeID]orders.UnsentSummary, err error) {
+	rows, err := db.conn().QueryContext(ctx, `
+		SELECT satellite_id, COUNT(*), SUM(order_amount)
+		FROM unsent_order
+		GROUP BY satellite_id
+	`)
+	if err != nil {
+		return nil, ErrInfo.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	summaries := map[storj.NodeID]orders.UnsentSummary{}
+	for rows.Next() {
+		var satelliteID storj.NodeID
+		var summary orders.UnsentSummary
+		if err := rows.Scan(&satelliteID, &summary.Count, &summary.Amount); err != nil {
+			return nil, ErrInfo.Wrap(err)
+		}
+		summaries[satelliteID] = summary
+	}
+
+	return summaries, ErrInfo.Wrap(rows.Err())
+}
+
+// SummarizeOrders returns the number and the total amount of the unsent and
+// archived orders by satellite, status and action.
+func (db *ordersdb) SummarizeOrders(ctx context.Context) (_ []orders.Summary, err error) {
+	// NB: the unsent orders have status 0, which is orders.StatusUnsent
+	rows, err := db.conn().QueryContext(ctx, `
+		SELECT satellite_id, 0, order_action, COUNT(*), SUM(order_amount)
+		FROM unsent_order
+		GROUP BY satellite_id, order_action
+		UNION ALL
+		SELECT satellite_id, status, order_action, COUNT(*), SUM(order_amount)
+		FROM order_archive
+		GROUP BY satellite_id, status, order_action
+		ORDER BY 1, 2, 3
+	`)
+	if err != nil {
+		return nil, ErrInfo.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	var summaries []orders.Summary
+	for rows.Next() {
+		var summary orders.Summary
+		var status, action int
+		if err := rows.Scan(&summary.SatelliteID, &status, &action, &summary.Count, &summary.Amount); err != nil {
 			return nil, ErrInfo.Wrap(err)
 		}
+		summary.Status = orders.Status(status)
+		summary.Action = pb.PieceAction(action)
+		summaries = append(summaries, summary)
+	}
+	return summaries, ErrInfo.Wrap(rows.Err())
+}
+
+// addOrderActions adds the order action column to the orders and the order
+// amount column to the archived orders with alters and fills them from the
+// serialized orders, so that the orders can be summarized without
+// unmarshaling them.
+func (db *infodb) addOrderActions(alters ...string) migrate.Func {
+	return migrate.Func(func(log *zap.Logger, _ migrate.DB, tx *sql.Tx) (err error) {
+		for _, alter := range alters {
+			if _, err := tx.Exec(alter); err != nil {
+				return ErrInfo.Wrap(err)
+			}
+		}
+
+		for _, table := range []string{"unsent_order", "order_archive"} {
+			count, err := db.fillOrderColumns(tx, table)
+			if err != nil {
+				return err
+			}
+			log.Info("added order actions", zap.String("table", table), zap.Int("orders", count))
+		}
+		return nil
+	})
+}
 
-		err = proto.Unmarshal(orderSerialized, info.Order)
+// fillOrderColumns sets the amount and the action of the orders in table from
+// the serialized orders and returns the number of orders.
+func (db *infodb) fillOrderColumns(tx *sql.Tx, table string) (int, error) {
+	type orderColumns struct {
+		rowid  int64
+		amount int64
+		action int
+	}
+
+	// NB: the orders are read before updating, since postgres can't
+	// execute queries on a transaction while reading the rows
+	columns, err := func() (columns []orderColumns, err error) {
+		rows, err := tx.Query(`SELECT rowid, order_limit_serialized, order_serialized FROM ` + table)
 		if err != nil {
 			return nil, ErrInfo.Wrap(err)
 		}
+		defer func() { err = errs.Combine(err, rows.Close()) }()
+
+		for rows.Next() {
+			var rowid int64
+			var limitSerialized, orderSerialized []byte
+			if err := rows.Scan(&rowid, &limitSerialized, &orderSerialized); err != nil {
+				return nil, ErrInfo.Wrap(err)
+			}
+
+			var limit pb.OrderLimit2
+			if err := db.unmarshal(limitSerialized, &limit); err != nil {
+				return nil, ErrInfo.Wrap(err)
+			}
+			var order pb.Order2
+			if err := db.unmarshal(orderSerialized, &order); err != nil {
+				return nil, ErrInfo.Wrap(err)
+			}
+			columns = append(columns, orderColumns{rowid, order.Amount, int(limit.Action)})
+		}
+		return columns, ErrInfo.Wrap(rows.Err())
+	}()
+	if err != nil {
+		return 0, err
+	}
 
-		infos[info.Limit.SatelliteId] = append(infos[info.Limit.SatelliteId], &info)
+	for _, order := range columns {
+		_, err := tx.Exec(db.Rebind(`UPDATE `+table+` SET order_amount = ?, order_action = ? WHERE rowid = ?`), order.amount, order.action, order.rowid)
+		if err != nil {
+			return 0, ErrInfo.Wrap(err)
+		}
 	}
+	return len(columns), nil
+}
 
-	return infos, ErrInfo.Wrap(rows.Err())
+// addUnsentOrderAmount adds the order amount column to the unsent orders with
+// alter and fills it from the serialized orders, so that the amounts can be
+// summarized without unmarshaling the orders.
+func (db *infodb) addUnsentOrderAmount(alter string) migrate.Func {
+	return migrate.Func(func(log *zap.Logger, _ migrate.DB, tx *sql.Tx) (err error) {
+		if _, err := tx.Exec(alter); err != nil {
+			return ErrInfo.Wrap(err)
+		}
+
+		type unsentAmount struct {
+			rowid  int64
+			amount int64
+		}
+
+		// NB: the amounts are read before updating, since postgres can't
+		// execute queries on a transaction while reading the rows
+		amounts, err := func() (amounts []unsentAmount, err error) {
+			rows, err := tx.Query(`SELECT rowid, order_serialized FROM unsent_order`)
+			if err != nil {
+				return nil, ErrInfo.Wrap(err)
+			}
+			defer func() { err = errs.Combine(err, rows.Close()) }()
+
+			for rows.Next() {
+				var rowid int64
+				var orderSerialized []byte
+				if err := rows.Scan(&rowid, &orderSerialized); err != nil {
+					return nil, ErrInfo.Wrap(err)
+				}
+
+				var order pb.Order2
+				if err := db.unmarshal(orderSerialized, &order); err != nil {
+					return nil, ErrInfo.Wrap(err)
+				}
+				amounts = append(amounts, unsentAmount{rowid, order.Amount})
+			}
+			return amounts, ErrInfo.Wrap(rows.Err())
+		}()
+		if err != nil {
+			return err
+		}
+
+		for _, unsent := range amounts {
+			_, err := tx.Exec(db.Rebind(`UPDATE unsent_order SET order_amount = ? WHERE rowid = ?`), unsent.amount, unsent.rowid)
+			if err != nil {
+				return ErrInfo.Wrap(err)
+			}
+		}
+
+		log.Info("added order amounts", zap.Int("unsent orders", len(amounts)))
+		return nil
+	})
 }
 
 // Archive marks order as being handled.
 func (db *ordersdb) Archive(ctx context.Context, satellite storj.NodeID, serial storj.SerialNumber, status orders.Status) error {
-	defer db.locked()()
-
-	result, err := db.db.Exec(`
-		INSERT INTO order_archive (
-			satellite_id, serial_number,
-			order_limit_serialized, order_serialized,
-			uplink_cert_id,
-			status, archived_at
-		) SELECT 
-			satellite_id, serial_number,
-			order_limit_serialized, order_serialized, 
-			uplink_cert_id,
-			?, ?
-		FROM unsent_order
-		WHERE satellite_id = ? AND serial_number = ?;
-
-		DELETE FROM unsent_order 
-		WHERE satellite_id = ? AND serial_number = ?;
-	`, int(status), time.Now(), satellite, serial, satellite, serial)
+	// NB: the order is moved in a transaction, so that concurrent archiving
+	// can't insert it twice
+	missing, err := db.ArchiveBatch(ctx, satellite, []orders.ArchiveRequest{
+		{Serial: serial, Status: status},
+	})
 	if err != nil {
-		return ErrInfo.Wrap(err)
+		return err
 	}
+	if len(missing) > 0 {
+		return ErrInfo.New("order %s was not in unsent list", serial)
+	}
+	return nil
+}
 
-	count, err := result.RowsAffected()
+// ArchiveBatch marks the orders of a satellite as being handled, all in one transaction.
+// The orders that aren't in the unsent list are skipped and returned.
+func (db *ordersdb) ArchiveBatch(ctx context.Context, satellite storj.NodeID, requests []orders.ArchiveRequest) (missing []storj.SerialNumber, err error) {
+	tx, err := db.beginTx(ctx)
 	if err != nil {
-		return ErrInfo.Wrap(err)
+		return nil, ErrInfo.Wrap(err)
 	}
-	if count == 0 {
-		return ErrInfo.New("order was not in unsent list")
+	defer func() {
+		if err != nil {
+			err = errs.Combine(err, ErrInfo.Wrap(tx.Rollback()))
+		} else {
+			err = ErrInfo.Wrap(tx.Commit())
+		}
+	}()
+
+	archivedAt := time.Now()
+	for _, request := range requests {
+		_, err := tx.ExecContext(ctx, db.Rebind(`
+			INSERT INTO order_archive (
+				satellite_id, serial_number,
+				order_limit_serialized, order_serialized,
+				uplink_cert_id, order_amount, order_action,
+				status, archived_at
+			) SELECT
+				satellite_id, serial_number,
+				order_limit_serialized, order_serialized,
+				uplink_cert_id, order_amount, order_action,
+				?, ?
+			FROM unsent_order
+			WHERE satellite_id = ? AND serial_number = ?
+		`), int(request.Status), archivedAt, satellite, request.Serial)
+		if err != nil {
+			return nil, ErrInfo.Wrap(err)
+		}
+
+		result, err := tx.ExecContext(ctx, db.Rebind(`
+			DELETE FROM unsent_order
+			WHERE satellite_id = ? AND serial_number = ?
+		`), satellite, request.Serial)
+		if err != nil {
+			return nil, ErrInfo.Wrap(err)
+		}
+
+		count, err := result.RowsAffected()
+		if err != nil {
+			return nil, ErrInfo.Wrap(err)
+		}
+		if count == 0 {
+			missing = append(missing, request.Serial)
+		}
 	}
 
-	return nil
+	return missing, nil
+}
+
+// ListArchived returns at most limit orders that have been sent, after the
+// cursor position and matching its filters, and the cursor of the next page.
+func (db *ordersdb) ListArchived(ctx context.Context, cursor orders.ArchiveCursor, limit int) (_ []*orders.ArchivedInfo, _ orders.ArchiveCursor, err error) {
+	var infos []*orders.ArchivedInfo
+	err = db.iterateArchived(ctx, cursor, limit, func(position int64, info *orders.ArchivedInfo) error {
+		cursor.Position = position
+		infos = append(infos, info)
+		return nil
+	})
+	if err != nil {
+		return nil, cursor, err
+	}
+	return infos, cursor, nil
+}
+
+// IterateArchived calls fn for the orders that have been sent, after the
+// cursor position and matching its filters, in archival order.
+// Note, fn must not use the database.
+func (db *ordersdb) IterateArchived(ctx context.Context, cursor orders.ArchiveCursor, fn func(*orders.ArchivedInfo) error) error {
+	return db.iterateArchived(ctx, cursor, 0, func(_ int64, info *orders.ArchivedInfo) error {
+		return fn(info)
+	})
 }
 
-// ListArchived returns orders that have been sent.
-func (db *ordersdb) ListArchived(ctx context.Context, limit int) ([]*orders.ArchivedInfo, error) {
-	defer db.locked()()
+// iterateArchived calls fn for at most limit orders that have been sent, or
+// for all of them when limit is zero, after the cursor position and matching
+// its filters, with their position.
+func (db *ordersdb) iterateArchived(ctx context.Context, cursor orders.ArchiveCursor, limit int, fn func(position int64, info *orders.ArchivedInfo) error) (err error) {
+	conditions := []string{"order_archive.rowid > ?"}
+	args := []interface{}{cursor.Position}
+	if !cursor.SatelliteID.IsZero() {
+		conditions = append(conditions, "order_archive.satellite_id = ?")
+		args = append(args, cursor.SatelliteID)
+	}
+	if !cursor.ArchivedAfter.IsZero() {
+		conditions = append(conditions, "order_archive.archived_at >= ?")
+		args = append(args, cursor.ArchivedAfter)
+	}
+	if !cursor.ArchivedBefore.IsZero() {
+		conditions = append(conditions, "order_archive.archived_at < ?")
+		args = append(args, cursor.ArchivedBefore)
+	}
 
-	rows, err := db.db.Query(`
-		SELECT order_limit_serialized, order_serialized, certificate.peer_identity, 
+	query := `
+		SELECT order_archive.rowid, order_limit_serialized, order_serialized, certificate.peer_identity, 
 			status, archived_at
 		FROM order_archive
 		INNER JOIN certificate on order_archive.uplink_cert_id = certificate.cert_id
-		LIMIT ?
-	`, limit)
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY order_archive.rowid`
+	if limit > 0 {
+		query += `
+		LIMIT ?`
+		args = append(args, limit)
+	}
+
+	rows, err := db.conn().QueryContext(ctx, db.Rebind(query), args...)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, nil
+			return nil
 		}
-		return nil, ErrInfo.Wrap(err)
+		return ErrInfo.Wrap(err)
 	}
 	defer func() { err = errs.Combine(err, rows.Close()) }()
 
-	var infos []*orders.ArchivedInfo
 	for rows.Next() {
 		var limitSerialized []byte
 		var orderSerialized []byte
 		var uplinkIdentity []byte
 
+		var position int64
 		var status int
 		var archivedAt time.Time
 
-		err := rows.Scan(&limitSerialized, &orderSerialized, &uplinkIdentity, &status, &archivedAt)
+		err := rows.Scan(&position, &limitSerialized, &orderSerialized, &uplinkIdentity, &status, &archivedAt)
 		if err != nil {
-			return nil, ErrInfo.Wrap(err)
+			return ErrInfo.Wrap(err)
 		}
 
 		var info orders.ArchivedInfo
@@ -240,23 +638,144 @@ func (db *ordersdb) ListArchived(ctx context.Context, limit int) ([]*orders.Arch
 		info.Status = orders.Status(status)
 		info.ArchivedAt = archivedAt
 
-		err = proto.Unmarshal(limitSerialized, info.Limit)
+		err = db.unmarshal(limitSerialized, info.Limit)
 		if err != nil {
-			return nil, ErrInfo.Wrap(err)
+			return ErrInfo.Wrap(err)
 		}
 
-		err = proto.Unmarshal(orderSerialized, info.Order)
+		err = db.unmarshal(orderSerialized, info.Order)
 		if err != nil {
-			return nil, ErrInfo.Wrap(err)
+			return ErrInfo.Wrap(err)
 		}
 
-		info.Uplink, err = decodePeerIdentity(uplinkIdentity)
+		info.Uplink, err = db.decodePeerIdentity(uplinkIdentity)
 		if err != nil {
-			return nil, ErrInfo.Wrap(err)
+			return ErrInfo.Wrap(err)
 		}
 
-		infos = append(infos, &info)
+		if err := fn(position, &info); err != nil {
+			return err
+		}
 	}
 
-	return infos, ErrInfo.Wrap(rows.Err())
+	return ErrInfo.Wrap(rows.Err())
+}
+
+// DeleteArchived deletes the orders archived before the given time and returns how many were deleted.
+func (db *ordersdb) DeleteArchived(ctx context.Context, before time.Time) (int64, error) {
+	result, err := db.conn().ExecContext(ctx, db.Rebind(`DELETE FROM order_archive WHERE archived_at < ?`), before)
+	if err != nil {
+		return 0, ErrInfo.Wrap(err)
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, ErrInfo.Wrap(err)
+	}
+	return count, nil
+}
+
+// CleanExpired archives the unsent orders whose order limit expired before
+// the given time with StatusExpired and returns how many were archived.
+func (db *ordersdb) CleanExpired(ctx context.Context, before time.Time) (_ int64, err error) {
+	tx, err := db.beginTx(ctx)
+	if err != nil {
+		return 0, ErrInfo.Wrap(err)
+	}
+	defer func() {
+		if err != nil {
+			err = errs.Combine(err, ErrInfo.Wrap(tx.Rollback()))
+		} else {
+			err = ErrInfo.Wrap(tx.Commit())
+		}
+	}()
+
+	// NB: the orders are bounded by rowid, so that orders enqueued between the
+	// two statements aren't deleted without being archived
+	var last sql.NullInt64
+	err = tx.QueryRowContext(ctx, `SELECT MAX(rowid) FROM unsent_order`).Scan(&last)
+	if err != nil {
+		return 0, ErrInfo.Wrap(err)
+	}
+	if !last.Valid {
+		return 0, nil
+	}
+
+	_, err = tx.ExecContext(ctx, db.Rebind(`
+		INSERT INTO order_archive (
+			satellite_id, serial_number,
+			order_limit_serialized, order_serialized,
+			uplink_cert_id, order_amount, order_action,
+			status, archived_at
+		) SELECT
+			satellite_id, serial_number,
+			order_limit_serialized, order_serialized,
+			uplink_cert_id, order_amount, order_action,
+			?, ?
+		FROM unsent_order
+		WHERE order_limit_expiration < ? AND rowid <= ?
+	`), int(orders.StatusExpired), time.Now(), before, last.Int64)
+	if err != nil {
+		return 0, ErrInfo.Wrap(err)
+	}
+
+	result, err := tx.ExecContext(ctx, db.Rebind(`
+		DELETE FROM unsent_order
+		WHERE order_limit_expiration < ? AND rowid <= ?
+	`), before, last.Int64)
+	if err != nil {
+		return 0, ErrInfo.Wrap(err)
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, ErrInfo.Wrap(err)
+	}
+	return count, nil
+}
+
+// ListBackoffs returns the settlement backoffs of the satellites.
+func (db *ordersdb) ListBackoffs(ctx context.Context) (_ []*orders.Backoff, err error) {
+	rows, err := db.conn().QueryContext(ctx, db.Rebind(`
+		SELECT satellite_id, failures, next_retry
+		FROM order_settlement_backoff
+	`))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, ErrInfo.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	var backoffs []*orders.Backoff
+	for rows.Next() {
+		backoff := &orders.Backoff{}
+		if err := rows.Scan(&backoff.SatelliteID, &backoff.Failures, &backoff.NextRetry); err != nil {
+			return nil, ErrInfo.Wrap(err)
+		}
+		backoffs = append(backoffs, backoff)
+	}
+
+	return backoffs, ErrInfo.Wrap(rows.Err())
+}
+
+// SetBackoff stores the settlement backoff of a satellite.
+func (db *ordersdb) SetBackoff(ctx context.Context, backoff *orders.Backoff) error {
+	_, err := db.conn().ExecContext(ctx, db.Rebind(`
+		INSERT INTO order_settlement_backoff(satellite_id, failures, next_retry)
+		VALUES (?, ?, ?)
+		ON CONFLICT (satellite_id) DO UPDATE SET
+			failures = excluded.failures,
+			next_retry = excluded.next_retry
+	`), backoff.SatelliteID, backoff.Failures, backoff.NextRetry)
+	return ErrInfo.Wrap(err)
+}
+
+// DeleteBackoff removes the settlement backoff of a satellite.
+func (db *ordersdb) DeleteBackoff(ctx context.Context, satelliteID storj.NodeID) error {
+	_, err := db.conn().ExecContext(ctx, db.Rebind(`
+		DELETE FROM order_settlement_backoff WHERE satellite_id = ?
+	`), satelliteID)
+	return ErrInfo.Wrap(err)
 }
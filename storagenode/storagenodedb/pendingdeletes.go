@@ -0,0 +1,71 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storagenodedb
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storagenode/pieces"
+)
+
+type pendingdeletes struct{ *infodb }
+
+// PendingDeletes returns database for storing the pieces queued for deletion.
+func (db *DB) PendingDeletes() pieces.PendingDeletesDB { return db.info.PendingDeletes() }
+
+// PendingDeletes returns database for storing the pieces queued for deletion.
+func (db *infodb) PendingDeletes() pieces.PendingDeletesDB { return &pendingdeletes{db} }
+
+// Enqueue queues the piece for deletion, a piece already queued is kept.
+func (db *pendingdeletes) Enqueue(ctx context.Context, satelliteID storj.NodeID, pieceID storj.PieceID, queuedAt time.Time) error {
+	_, err := db.conn().ExecContext(ctx, db.Rebind(`
+		INSERT INTO pending_deletes (satellite_id, piece_id, queued_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (satellite_id, piece_id) DO NOTHING
+	`), satelliteID, pieceID, queuedAt.UTC())
+	return ErrInfo.Wrap(err)
+}
+
+// List returns at most limit queued pieces, the oldest first.
+func (db *pendingdeletes) List(ctx context.Context, limit int) (_ []pieces.PendingDelete, err error) {
+	rows, err := db.conn().QueryContext(ctx, db.Rebind(`
+		SELECT satellite_id, piece_id, queued_at
+		FROM pending_deletes
+		ORDER BY queued_at
+		LIMIT ?
+	`), limit)
+	if err != nil {
+		return nil, ErrInfo.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	var pending []pieces.PendingDelete
+	for rows.Next() {
+		var piece pieces.PendingDelete
+		if err := rows.Scan(&piece.SatelliteID, &piece.PieceID, &piece.QueuedAt); err != nil {
+			return nil, ErrInfo.Wrap(err)
+		}
+		pending = append(pending, piece)
+	}
+	return pending, ErrInfo.Wrap(rows.Err())
+}
+
+// Remove removes the piece from the queue.
+func (db *pendingdeletes) Remove(ctx context.Context, satelliteID storj.NodeID, pieceID storj.PieceID) error {
+	_, err := db.conn().ExecContext(ctx, db.Rebind(`
+		DELETE FROM pending_deletes
+		WHERE satellite_id = ? AND piece_id = ?
+	`), satelliteID, pieceID)
+	return ErrInfo.Wrap(err)
+}
+
+// Count returns the number of queued pieces.
+func (db *pendingdeletes) Count(ctx context.Context) (count int64, err error) {
+	err = db.conn().QueryRowContext(ctx, `SELECT COUNT(*) FROM pending_deletes`).Scan(&count)
+	return count, ErrInfo.Wrap(err)
+}
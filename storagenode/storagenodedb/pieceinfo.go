@@ -6,9 +6,13 @@ package storagenodedb
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/gogo/protobuf/proto"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
 
+	"storj.io/storj/internal/migrate"
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/storj"
 	"storj.io/storj/storagenode/pieces"
@@ -35,13 +39,13 @@ func (db *pieceinfo) Add(ctx context.Context, info *pieces.Info) error {
 		return ErrInfo.Wrap(err)
 	}
 
-	defer db.locked()()
+	var pieceExpiration *time.Time
+	if info.PieceExpiration != nil {
+		utcExpiration := info.PieceExpiration.UTC()
+		pieceExpiration = &utcExpiration
+	}
 
-	_, err = db.db.Exec(`
-		INSERT INTO
-			pieceinfo(satellite_id, piece_id, piece_size, piece_expiration, uplink_piece_hash, uplink_cert_id)
-		VALUES (?,?,?,?,?,?)
-	`, info.SatelliteID, info.PieceID, info.PieceSize, info.PieceExpiration, uplinkPieceHash, certid)
+	_, err = db.execStatement(ctx, stmtAddPieceInfo, info.SatelliteID, info.PieceID, info.PieceSize, pieceExpiration, uplinkPieceHash, certid, info.PieceCreation.UTC())
 
 	return ErrInfo.Wrap(err)
 }
@@ -52,21 +56,23 @@ func (db *pieceinfo) Get(ctx context.Context, satelliteID storj.NodeID, pieceID
 	info.SatelliteID = satelliteID
 	info.PieceID = pieceID
 
+	var pieceCreation *time.Time
 	var uplinkPieceHash []byte
 	var uplinkIdentity []byte
 
-	db.mu.Lock()
-	err := db.db.QueryRow(`
-		SELECT piece_size, piece_expiration, uplink_piece_hash, certificate.peer_identity
+	err := db.conn().QueryRowContext(ctx, db.Rebind(`
+		SELECT piece_size, piece_expiration, piece_creation, uplink_piece_hash, certificate.peer_identity
 		FROM pieceinfo
 		INNER JOIN certificate ON pieceinfo.uplink_cert_id = certificate.cert_id
 		WHERE satellite_id = ? AND piece_id = ?
-	`, satelliteID, pieceID).Scan(&info.PieceSize, &info.PieceExpiration, &uplinkPieceHash, &uplinkIdentity)
-	db.mu.Unlock()
+	`), satelliteID, pieceID).Scan(&info.PieceSize, &info.PieceExpiration, &pieceCreation, &uplinkPieceHash, &uplinkIdentity)
 
 	if err != nil {
 		return nil, ErrInfo.Wrap(err)
 	}
+	if pieceCreation != nil {
+		info.PieceCreation = *pieceCreation
+	}
 
 	info.UplinkPieceHash = &pb.PieceHash{}
 	err = proto.Unmarshal(uplinkPieceHash, info.UplinkPieceHash)
@@ -74,7 +80,7 @@ func (db *pieceinfo) Get(ctx context.Context, satelliteID storj.NodeID, pieceID
 		return nil, ErrInfo.Wrap(err)
 	}
 
-	info.Uplink, err = decodePeerIdentity(uplinkIdentity)
+	info.Uplink, err = db.decodePeerIdentity(uplinkIdentity)
 	if err != nil {
 		return nil, ErrInfo.Wrap(err)
 	}
@@ -82,23 +88,185 @@ func (db *pieceinfo) Get(ctx context.Context, satelliteID storj.NodeID, pieceID
 	return info, nil
 }
 
+// MarkCorrupted flags the piece as corrupted at now.
+func (db *pieceinfo) MarkCorrupted(ctx context.Context, satelliteID storj.NodeID, pieceID storj.PieceID, now time.Time) error {
+	_, err := db.conn().ExecContext(ctx, db.Rebind(`
+		UPDATE pieceinfo SET corrupted_at = ?
+		WHERE satellite_id = ? AND piece_id = ? AND corrupted_at IS NULL
+	`), now.UTC(), satelliteID, pieceID)
+	return ErrInfo.Wrap(err)
+}
+
+// CountCorrupted returns the number of pieces flagged as corrupted.
+func (db *pieceinfo) CountCorrupted(ctx context.Context) (count int64, err error) {
+	err = db.conn().QueryRowContext(ctx, `SELECT COUNT(*) FROM pieceinfo WHERE corrupted_at IS NOT NULL`).Scan(&count)
+	return count, ErrInfo.Wrap(err)
+}
+
+// GetExpired returns at most limit pieces which expired before expiredAt, oldest first.
+func (db *pieceinfo) GetExpired(ctx context.Context, expiredAt time.Time, limit int) (_ []pieces.ExpiredInfo, err error) {
+	rows, err := db.conn().QueryContext(ctx, db.Rebind(`
+		SELECT satellite_id, piece_id, piece_size
+		FROM pieceinfo
+		WHERE piece_expiration IS NOT NULL AND piece_expiration < ?
+		ORDER BY piece_expiration
+		LIMIT ?
+	`), expiredAt.UTC(), limit)
+	if err != nil {
+		return nil, ErrInfo.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	var infos []pieces.ExpiredInfo
+	for rows.Next() {
+		var info pieces.ExpiredInfo
+		if err := rows.Scan(&info.SatelliteID, &info.PieceID, &info.PieceSize); err != nil {
+			return nil, ErrInfo.Wrap(err)
+		}
+		infos = append(infos, info)
+	}
+	return infos, ErrInfo.Wrap(rows.Err())
+}
+
+// GetPieceIDs returns a page of the piece IDs of the satellite created before createdBefore,
+// ordered by piece id.
+func (db *pieceinfo) GetPieceIDs(ctx context.Context, satelliteID storj.NodeID, createdBefore time.Time, limit, offset int) (_ []storj.PieceID, err error) {
+	rows, err := db.conn().QueryContext(ctx, db.Rebind(`
+		SELECT piece_id
+		FROM pieceinfo
+		WHERE satellite_id = ? AND piece_creation < ?
+		ORDER BY piece_id
+		LIMIT ? OFFSET ?
+	`), satelliteID, createdBefore.UTC(), limit, offset)
+	if err != nil {
+		return nil, ErrInfo.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	var pieceIDs []storj.PieceID
+	for rows.Next() {
+		var pieceID storj.PieceID
+		if err := rows.Scan(&pieceID); err != nil {
+			return nil, ErrInfo.Wrap(err)
+		}
+		pieceIDs = append(pieceIDs, pieceID)
+	}
+	return pieceIDs, ErrInfo.Wrap(rows.Err())
+}
+
+// GetScrubInfos returns at most limit pieces ordered by satellite and piece id,
+// starting after the piece afterPiece of the satellite afterSatellite.
+func (db *pieceinfo) GetScrubInfos(ctx context.Context, afterSatellite storj.NodeID, afterPiece storj.PieceID, limit int) (_ []pieces.ScrubInfo, err error) {
+	rows, err := db.conn().QueryContext(ctx, db.Rebind(`
+		SELECT satellite_id, piece_id, uplink_piece_hash, corrupted_at IS NOT NULL
+		FROM pieceinfo
+		WHERE satellite_id > ? OR (satellite_id = ? AND piece_id > ?)
+		ORDER BY satellite_id, piece_id
+		LIMIT ?
+	`), afterSatellite, afterSatellite, afterPiece, limit)
+	if err != nil {
+		return nil, ErrInfo.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	var infos []pieces.ScrubInfo
+	for rows.Next() {
+		var info pieces.ScrubInfo
+		var uplinkPieceHash []byte
+		if err := rows.Scan(&info.SatelliteID, &info.PieceID, &uplinkPieceHash, &info.Corrupted); err != nil {
+			return nil, ErrInfo.Wrap(err)
+		}
+
+		var pieceHash pb.PieceHash
+		if err := proto.Unmarshal(uplinkPieceHash, &pieceHash); err != nil {
+			return nil, ErrInfo.Wrap(err)
+		}
+		info.PieceHash = pieceHash.Hash
+
+		infos = append(infos, info)
+	}
+	return infos, ErrInfo.Wrap(rows.Err())
+}
+
 // Delete deletes piece information.
 func (db *pieceinfo) Delete(ctx context.Context, satelliteID storj.NodeID, pieceID storj.PieceID) error {
-	defer db.locked()()
-
-	_, err := db.db.Exec(`DELETE FROM pieceinfo WHERE satellite_id = ? AND piece_id = ?`, satelliteID, pieceID)
+	_, err := db.conn().ExecContext(ctx, db.Rebind(`DELETE FROM pieceinfo WHERE satellite_id = ? AND piece_id = ?`), satelliteID, pieceID)
 
 	return ErrInfo.Wrap(err)
 }
 
-// SpaceUsed calculates disk space used by all pieces
+// SpaceUsed calculates disk space used by all pieces, except the corrupted pieces
 func (db *pieceinfo) SpaceUsed(ctx context.Context) (int64, error) {
-	defer db.locked()()
-
 	var sum *int64
-	err := db.db.QueryRow(`SELECT SUM(piece_size) FROM pieceinfo;`).Scan(&sum)
-	if err == sql.ErrNoRows || sum == nil {
+	err := db.conn().QueryRowContext(ctx, db.Rebind(`SELECT SUM(piece_size) FROM pieceinfo WHERE corrupted_at IS NULL;`)).Scan(&sum)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, ErrInfo.Wrap(err)
+	}
+	if sum == nil {
 		return 0, nil
 	}
-	return *sum, err
+	return *sum, nil
+}
+
+// SpaceUsedBySatellite calculates disk space used by the pieces of each satellite, except the corrupted pieces
+func (db *pieceinfo) SpaceUsedBySatellite(ctx context.Context) (_ map[storj.NodeID]int64, err error) {
+	rows, err := db.conn().QueryContext(ctx, db.Rebind(`SELECT satellite_id, SUM(piece_size) FROM pieceinfo WHERE corrupted_at IS NULL GROUP BY satellite_id;`))
+	if err != nil {
+		return nil, ErrInfo.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	usage := map[storj.NodeID]int64{}
+	for rows.Next() {
+		var satelliteID storj.NodeID
+		var sum int64
+		if err := rows.Scan(&satelliteID, &sum); err != nil {
+			return nil, ErrInfo.Wrap(err)
+		}
+		usage[satelliteID] = sum
+	}
+	return usage, ErrInfo.Wrap(rows.Err())
+}
+
+// SummarizeBySatellite returns the number and total size of the pieces of each satellite.
+func (db *pieceinfo) SummarizeBySatellite(ctx context.Context) (_ map[storj.NodeID]pieces.Summary, err error) {
+	rows, err := db.conn().QueryContext(ctx, `
+		SELECT satellite_id,
+			SUM(CASE WHEN corrupted_at IS NULL THEN 1 ELSE 0 END),
+			COALESCE(SUM(CASE WHEN corrupted_at IS NULL THEN piece_size ELSE 0 END), 0),
+			SUM(CASE WHEN corrupted_at IS NULL THEN 0 ELSE 1 END)
+		FROM pieceinfo
+		GROUP BY satellite_id
+	`)
+	if err != nil {
+		return nil, ErrInfo.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	summaries := map[storj.NodeID]pieces.Summary{}
+	for rows.Next() {
+		var satelliteID storj.NodeID
+		var summary pieces.Summary
+		if err := rows.Scan(&satelliteID, &summary.Count, &summary.Size, &summary.Corrupted); err != nil {
+			return nil, ErrInfo.Wrap(err)
+		}
+		summaries[satelliteID] = summary
+	}
+	return summaries, ErrInfo.Wrap(rows.Err())
+}
+
+// addPieceCreation adds the piece creation column with alter and sets it to
+// the current time for the existing pieces, so that they are only garbage
+// collected with a bloom filter created after the migration.
+func (db *infodb) addPieceCreation(alter string) migrate.Func {
+	return migrate.Func(func(log *zap.Logger, _ migrate.DB, tx *sql.Tx) error {
+		if _, err := tx.Exec(alter); err != nil {
+			return ErrInfo.Wrap(err)
+		}
+		_, err := tx.Exec(db.Rebind(`UPDATE pieceinfo SET piece_creation = ?`), time.Now().UTC())
+		return ErrInfo.Wrap(err)
+	})
 }
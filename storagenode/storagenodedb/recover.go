@@ -0,0 +1,145 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storagenodedb
+
+import (
+	"database/sql"
+	"os"
+	"strings"
+	"time"
+
+	sqlite3 "github.com/mattn/go-sqlite3"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/storj/internal/dbutil"
+)
+
+// ErrCorrupt is returned when a database fails the integrity check.
+var ErrCorrupt = errs.Class("corrupt database")
+
+// sqliteDatabase is a sqlite database of the storage node checked on startup.
+type sqliteDatabase struct {
+	Path string
+	// Contents describes the state lost when the database is recreated.
+	Contents string
+}
+
+// sqliteDatabases returns the sqlite databases of the configuration.
+func sqliteDatabases(config Config) []sqliteDatabase {
+	databases := []sqliteDatabase{
+		{config.Info, "piece expirations and bandwidth agreements of the previous piecestore"},
+	}
+
+	infoPath := config.Info2
+	if config.DatabaseURL != "" {
+		driver, source, err := dbutil.SplitConnstr(config.DatabaseURL)
+		if err != nil || driver != "sqlite3" {
+			return databases
+		}
+		infoPath = source
+	}
+
+	var tables []string
+	for _, table := range infoTables {
+		tables = append(tables, table.Name)
+	}
+	return append(databases, sqliteDatabase{infoPath, strings.Join(tables, ", ")})
+}
+
+// checkDatabases checks the integrity of the sqlite databases. When
+// autoRecover is set, a corrupt database is moved aside so that it's
+// recreated empty, otherwise ErrCorrupt is returned.
+func checkDatabases(log *zap.Logger, config Config) error {
+	for _, database := range sqliteDatabases(config) {
+		err := checkIntegrity(database.Path)
+		if err == nil {
+			continue
+		}
+		if !ErrCorrupt.Has(err) {
+			return err
+		}
+		if !config.AutoRecover {
+			log.Error("database is corrupt, enable storage2.database-auto-recover to move it aside and recreate it empty",
+				zap.String("database", database.Path),
+				zap.String("lost on recovery", database.Contents),
+				zap.Error(err))
+			return err
+		}
+
+		moved, moveErr := moveAside(database.Path)
+		if moveErr != nil {
+			return errs.Combine(err, moveErr)
+		}
+		log.Error("database is corrupt, moved it aside and recreating it empty",
+			zap.String("database", database.Path),
+			zap.String("moved to", moved),
+			zap.String("lost", database.Contents),
+			zap.Error(err))
+	}
+	return nil
+}
+
+// checkIntegrity runs the integrity check of the sqlite database at path,
+// databases that don't exist yet are skipped.
+func checkIntegrity(path string) (err error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+
+	db, err := sql.Open("sqlite3", "file:"+path)
+	if err != nil {
+		return ErrInfo.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, db.Close()) }()
+
+	rows, err := db.Query(`PRAGMA integrity_check`)
+	if err != nil {
+		return corruptError(path, err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	var problems []string
+	for rows.Next() {
+		var problem string
+		if err := rows.Scan(&problem); err != nil {
+			return corruptError(path, err)
+		}
+		if problem != "ok" {
+			problems = append(problems, problem)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return corruptError(path, err)
+	}
+
+	if len(problems) > 0 {
+		return ErrCorrupt.New("%s: %s", path, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// corruptError returns ErrCorrupt when sqlite reports the database at path as
+// malformed.
+func corruptError(path string, err error) error {
+	if sqliteErr, ok := err.(sqlite3.Error); ok {
+		if sqliteErr.Code == sqlite3.ErrCorrupt || sqliteErr.Code == sqlite3.ErrNotADB {
+			return ErrCorrupt.New("%s: %v", path, err)
+		}
+	}
+	return ErrInfo.New("%s: %v", path, err)
+}
+
+// moveAside renames the database at path, with its write-ahead log, so that
+// it's kept for inspection, and returns the new path.
+func moveAside(path string) (string, error) {
+	moved := path + ".corrupt-" + time.Now().UTC().Format("20060102T150405Z")
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		err := os.Rename(path+suffix, moved+suffix)
+		if err != nil && !os.IsNotExist(err) {
+			return "", ErrCorrupt.Wrap(err)
+		}
+	}
+	return moved, nil
+}
@@ -0,0 +1,88 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storagenodedb_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storagenode/storagenodedb"
+)
+
+func TestAutoRecover(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	log := zaptest.NewLogger(t)
+	dir := ctx.Dir("storage")
+	infoPath := filepath.Join(dir, "info.db")
+	satelliteID := testplanet.MustPregeneratedSignedIdentity(1).ID
+
+	open := func(autoRecover bool) (*storagenodedb.DB, error) {
+		return storagenodedb.New(log, storagenodedb.Config{
+			Storage:  dir,
+			Info:     filepath.Join(dir, "piecestore.db"),
+			Info2:    infoPath,
+			Pieces:   dir,
+			Kademlia: filepath.Join(dir, "kademlia"),
+
+			AutoRecover: autoRecover,
+		})
+	}
+
+	db, err := open(false)
+	require.NoError(t, err)
+	require.NoError(t, db.CreateTables())
+	require.NoError(t, db.UsedSerials().Add(ctx, satelliteID, storj.SerialNumber{1}, time.Now().Add(time.Hour)))
+	require.NoError(t, db.Close())
+
+	// healthy databases pass the check
+	db, err = open(false)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	// overwrite the header of info.db
+	file, err := os.OpenFile(infoPath, os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = file.WriteAt(make([]byte, 100), 0)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	_, err = open(false)
+	require.True(t, storagenodedb.ErrCorrupt.Has(err), err)
+
+	db, err = open(true)
+	require.NoError(t, err)
+	defer ctx.Check(db.Close)
+	require.NoError(t, db.CreateTables())
+
+	// the corrupt database was kept next to the recreated one
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	var moved int
+	for _, file := range files {
+		if matched, _ := filepath.Match("info.db.corrupt-*", file.Name()); matched {
+			moved++
+		}
+	}
+	require.Equal(t, 1, moved)
+
+	// the state was lost and the database works again
+	serials := 0
+	err = db.UsedSerials().IterateAll(ctx, func(storj.NodeID, storj.SerialNumber, time.Time) {
+		serials++
+	})
+	require.NoError(t, err)
+	require.Equal(t, 0, serials)
+	require.NoError(t, db.UsedSerials().Add(ctx, satelliteID, storj.SerialNumber{1}, time.Now().Add(time.Hour)))
+}
@@ -0,0 +1,121 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storagenodedb
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storagenode/reputation"
+)
+
+type reputationdb struct{ *infodb }
+
+// Reputation returns database for storing the reputation reported by the satellites.
+func (db *DB) Reputation() reputation.DB { return db.info.Reputation() }
+
+// Reputation returns database for storing the reputation reported by the satellites.
+func (db *infodb) Reputation() reputation.DB { return &reputationdb{db} }
+
+// Store replaces the reputation reported by the satellite of stats.
+func (db *reputationdb) Store(ctx context.Context, stats reputation.Stats) error {
+	var disqualified *time.Time
+	if stats.Disqualified != nil {
+		utcDisqualified := stats.Disqualified.UTC()
+		disqualified = &utcDisqualified
+	}
+	var vettedAt *time.Time
+	if stats.VettedAt != nil {
+		utcVettedAt := stats.VettedAt.UTC()
+		vettedAt = &utcVettedAt
+	}
+
+	_, err := db.conn().ExecContext(ctx, db.Rebind(`
+		INSERT INTO reputation (
+			satellite_id,
+			audit_count, audit_success_count, audit_success_ratio,
+			uptime_count, uptime_success_count, uptime_ratio,
+			disqualified_at, vetted_at, vetting_audit_count, vetting_uptime_count,
+			updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (satellite_id) DO UPDATE SET
+			audit_count = excluded.audit_count,
+			audit_success_count = excluded.audit_success_count,
+			audit_success_ratio = excluded.audit_success_ratio,
+			uptime_count = excluded.uptime_count,
+			uptime_success_count = excluded.uptime_success_count,
+			uptime_ratio = excluded.uptime_ratio,
+			disqualified_at = excluded.disqualified_at,
+			vetted_at = excluded.vetted_at,
+			vetting_audit_count = excluded.vetting_audit_count,
+			vetting_uptime_count = excluded.vetting_uptime_count,
+			updated_at = excluded.updated_at
+	`), stats.SatelliteID,
+		stats.AuditCount, stats.AuditSuccessCount, stats.AuditSuccessRatio,
+		stats.UptimeCount, stats.UptimeSuccessCount, stats.UptimeRatio,
+		disqualified, vettedAt, stats.VettingAuditCount, stats.VettingUptimeCount,
+		stats.UpdatedAt.UTC())
+	return ErrInfo.Wrap(err)
+}
+
+// Get returns the reputation reported by the satellite, it returns nil when
+// the satellite didn't report any.
+func (db *reputationdb) Get(ctx context.Context, satelliteID storj.NodeID) (*reputation.Stats, error) {
+	stats := &reputation.Stats{}
+	err := db.conn().QueryRowContext(ctx, db.Rebind(`
+		SELECT satellite_id,
+			audit_count, audit_success_count, audit_success_ratio,
+			uptime_count, uptime_success_count, uptime_ratio,
+			disqualified_at, vetted_at, vetting_audit_count, vetting_uptime_count,
+			updated_at
+		FROM reputation
+		WHERE satellite_id = ?
+	`), satelliteID).Scan(&stats.SatelliteID,
+		&stats.AuditCount, &stats.AuditSuccessCount, &stats.AuditSuccessRatio,
+		&stats.UptimeCount, &stats.UptimeSuccessCount, &stats.UptimeRatio,
+		&stats.Disqualified, &stats.VettedAt, &stats.VettingAuditCount, &stats.VettingUptimeCount,
+		&stats.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, ErrInfo.Wrap(err)
+	}
+	return stats, nil
+}
+
+// All returns the reputation reported by each satellite.
+func (db *reputationdb) All(ctx context.Context) (_ []reputation.Stats, err error) {
+	rows, err := db.conn().QueryContext(ctx, `
+		SELECT satellite_id,
+			audit_count, audit_success_count, audit_success_ratio,
+			uptime_count, uptime_success_count, uptime_ratio,
+			disqualified_at, vetted_at, vetting_audit_count, vetting_uptime_count,
+			updated_at
+		FROM reputation
+	`)
+	if err != nil {
+		return nil, ErrInfo.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	var all []reputation.Stats
+	for rows.Next() {
+		var stats reputation.Stats
+		err := rows.Scan(&stats.SatelliteID,
+			&stats.AuditCount, &stats.AuditSuccessCount, &stats.AuditSuccessRatio,
+			&stats.UptimeCount, &stats.UptimeSuccessCount, &stats.UptimeRatio,
+			&stats.Disqualified, &stats.VettedAt, &stats.VettingAuditCount, &stats.VettingUptimeCount,
+			&stats.UpdatedAt)
+		if err != nil {
+			return nil, ErrInfo.Wrap(err)
+		}
+		all = append(all, stats)
+	}
+	return all, ErrInfo.Wrap(rows.Err())
+}
@@ -0,0 +1,68 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storagenodedb
+
+import (
+	"context"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storagenode/pieces"
+)
+
+type spaceused struct{ *infodb }
+
+// PieceSpaceUsed returns database for storing the cached space used by the pieces.
+func (db *DB) PieceSpaceUsed() pieces.SpaceUsedDB { return db.info.PieceSpaceUsed() }
+
+// PieceSpaceUsed returns database for storing the cached space used by the pieces.
+func (db *infodb) PieceSpaceUsed() pieces.SpaceUsedDB { return &spaceused{db} }
+
+// GetSpaceUsedTotals returns the saved space used by the pieces of each satellite.
+func (db *spaceused) GetSpaceUsedTotals(ctx context.Context) (_ map[storj.NodeID]int64, ok bool, err error) {
+	rows, err := db.conn().QueryContext(ctx, `SELECT satellite_id, total FROM piece_space_used`)
+	if err != nil {
+		return nil, false, ErrInfo.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	totals := map[storj.NodeID]int64{}
+	for rows.Next() {
+		var satelliteID storj.NodeID
+		var total int64
+		if err := rows.Scan(&satelliteID, &total); err != nil {
+			return nil, false, ErrInfo.Wrap(err)
+		}
+		totals[satelliteID] = total
+		ok = true
+	}
+	return totals, ok, ErrInfo.Wrap(rows.Err())
+}
+
+// UpdateSpaceUsedTotals replaces the saved space used by the pieces of each satellite.
+func (db *spaceused) UpdateSpaceUsedTotals(ctx context.Context, totals map[storj.NodeID]int64) (err error) {
+	tx, err := db.beginTx(ctx)
+	if err != nil {
+		return ErrInfo.Wrap(err)
+	}
+	defer func() {
+		if err != nil {
+			err = errs.Combine(err, ErrInfo.Wrap(tx.Rollback()))
+		} else {
+			err = ErrInfo.Wrap(tx.Commit())
+		}
+	}()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM piece_space_used`); err != nil {
+		return ErrInfo.Wrap(err)
+	}
+	for satelliteID, total := range totals {
+		_, err := tx.ExecContext(ctx, db.Rebind(`INSERT INTO piece_space_used(satellite_id, total) VALUES (?, ?)`), satelliteID, total)
+		if err != nil {
+			return ErrInfo.Wrap(err)
+		}
+	}
+	return nil
+}
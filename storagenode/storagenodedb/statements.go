@@ -0,0 +1,113 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storagenodedb
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/zeebo/errs"
+)
+
+// statement is a query of the upload hot path, prepared once the tables exist.
+type statement int
+
+const (
+	stmtIncludeCertificate statement = iota
+	stmtLookupCertificateID
+	stmtEnqueueOrder
+	stmtAddBandwidth
+	stmtAddPieceInfo
+
+	statementCount
+)
+
+// statementQueries are the queries of the statements, in statement order.
+var statementQueries = [statementCount]string{
+	stmtIncludeCertificate: `
+		INSERT INTO certificate(node_id, peer_identity) VALUES(?, ?)
+		ON CONFLICT (peer_identity) DO NOTHING`,
+	stmtLookupCertificateID: `SELECT cert_id FROM certificate WHERE peer_identity = ?`,
+	stmtEnqueueOrder: `
+		INSERT INTO unsent_order(
+			satellite_id, serial_number,
+			order_limit_serialized, order_serialized, order_limit_expiration,
+			uplink_cert_id, order_amount, order_action
+		) VALUES (?,?, ?,?,?, ?,?,?)
+		ON CONFLICT (satellite_id, serial_number) DO NOTHING`,
+	stmtAddBandwidth: `
+		INSERT INTO
+			bandwidth_usage(satellite_id, action, amount, created_at)
+		VALUES(?, ?, ?, ?)`,
+	stmtAddPieceInfo: `
+		INSERT INTO
+			pieceinfo(satellite_id, piece_id, piece_size, piece_expiration, uplink_piece_hash, uplink_cert_id, piece_creation)
+		VALUES (?,?,?,?,?,?,?)`,
+}
+
+// prepareStatements prepares the statements, the tables must exist.
+func (db *infodb) prepareStatements(ctx context.Context) (err error) {
+	if db.prepared != nil {
+		return nil
+	}
+
+	prepared := make([]*sql.Stmt, statementCount)
+	defer func() {
+		if err != nil {
+			err = errs.Combine(err, closeStatements(prepared))
+		}
+	}()
+
+	for stmt, query := range statementQueries {
+		prepared[stmt], err = db.db.PrepareContext(ctx, db.Rebind(query))
+		if err != nil {
+			return ErrInfo.Wrap(err)
+		}
+	}
+
+	db.prepared = prepared
+	return nil
+}
+
+// execStatement executes stmt, with the prepared statement when the
+// statements were prepared.
+func (db *infodb) execStatement(ctx context.Context, stmt statement, args ...interface{}) (sql.Result, error) {
+	if prepared := db.statement(ctx, stmt); prepared != nil {
+		return prepared.ExecContext(ctx, args...)
+	}
+	return db.conn().ExecContext(ctx, db.Rebind(statementQueries[stmt]), args...)
+}
+
+// queryRowStatement queries a row with stmt, with the prepared statement when
+// the statements were prepared.
+func (db *infodb) queryRowStatement(ctx context.Context, stmt statement, args ...interface{}) *sql.Row {
+	if prepared := db.statement(ctx, stmt); prepared != nil {
+		return prepared.QueryRowContext(ctx, args...)
+	}
+	return db.conn().QueryRowContext(ctx, db.Rebind(statementQueries[stmt]), args...)
+}
+
+// statement returns the prepared stmt, bound to the transaction of WithTx,
+// or nil when the statements weren't prepared.
+func (db *infodb) statement(ctx context.Context, stmt statement) *sql.Stmt {
+	switch {
+	case db.prepared == nil:
+		return nil
+	case db.tx != nil:
+		return db.tx.StmtContext(ctx, db.prepared[stmt])
+	default:
+		return db.prepared[stmt]
+	}
+}
+
+// closeStatements closes the prepared statements.
+func closeStatements(prepared []*sql.Stmt) error {
+	var group errs.Group
+	for _, stmt := range prepared {
+		if stmt != nil {
+			group.Add(stmt.Close())
+		}
+	}
+	return group.Err()
+}
@@ -6,11 +6,16 @@ package storagenodedbtest
 // This package should be referenced only in test files!
 
 import (
+	"database/sql"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"go.uber.org/zap/zaptest"
 
+	"storj.io/storj/internal/dbutil/pgutil"
 	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/satellite/satellitedb/satellitedbtest"
 	"storj.io/storj/storagenode"
 	"storj.io/storj/storagenode/storagenodedb"
 )
@@ -38,4 +43,53 @@ func Run(t *testing.T, test func(t *testing.T, db storagenode.DB)) {
 
 		test(t, db)
 	})
+
+	t.Run("Postgres", func(t *testing.T) {
+		t.Parallel()
+		if *satellitedbtest.TestPostgres == "" {
+			t.Skip("Postgres flag missing, example: -postgres-test-db=" + satellitedbtest.DefaultPostgresConn)
+		}
+
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		log := zaptest.NewLogger(t)
+
+		schema := strings.ToLower(t.Name() + "-storagenode/x-" + pgutil.RandomString(8))
+		schemadb, err := sql.Open("postgres", *satellitedbtest.TestPostgres)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ctx.Check(schemadb.Close)
+
+		if err := pgutil.CreateSchema(schemadb, schema); err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			if err := pgutil.DropSchema(schemadb, schema); err != nil {
+				t.Error(err)
+			}
+		}()
+
+		dir := ctx.Dir("storage")
+		db, err := storagenodedb.New(log, storagenodedb.Config{
+			Storage:  dir,
+			Info:     filepath.Join(dir, "piecestore.db"),
+			Pieces:   dir,
+			Kademlia: filepath.Join(dir, "kademlia"),
+
+			DatabaseURL: pgutil.ConnstrWithSchema(*satellitedbtest.TestPostgres, schema),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ctx.Check(db.Close)
+
+		err = db.CreateTables()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		test(t, db)
+	})
 }
@@ -4,9 +4,22 @@
 package storagenodedbtest_test
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/require"
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
 	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/orders"
+	"storj.io/storj/storagenode/pieces"
+	"storj.io/storj/storagenode/piecestore"
 	"storj.io/storj/storagenode/storagenodedb/storagenodedbtest"
 )
 
@@ -14,3 +27,162 @@ func TestDatabase(t *testing.T) {
 	storagenodedbtest.Run(t, func(t *testing.T, db storagenode.DB) {
 	})
 }
+
+func TestCanceledContext(t *testing.T) {
+	storagenodedbtest.Run(t, func(t *testing.T, db storagenode.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		satelliteID := testplanet.MustPregeneratedSignedIdentity(1).ID
+		uplink := testplanet.MustPregeneratedSignedIdentity(3)
+		now := time.Now()
+
+		// every query is aborted once the context is canceled
+		_, err := db.CertDB().Include(canceled, uplink.PeerIdentity())
+		require.Error(t, err)
+		_, err = db.CertDB().LookupByCertID(canceled, 1)
+		require.Error(t, err)
+
+		require.Error(t, db.Bandwidth().Add(canceled, satelliteID, pb.PieceAction_GET, 100, now))
+		_, err = db.Bandwidth().Summary(canceled, now.Add(-time.Hour), now)
+		require.Error(t, err)
+		_, err = db.Bandwidth().SummaryBySatellite(canceled, now.Add(-time.Hour), now)
+		require.Error(t, err)
+
+		_, err = db.PieceInfo().SpaceUsed(canceled)
+		require.Error(t, err)
+		_, err = db.PieceInfo().SpaceUsedBySatellite(canceled)
+		require.Error(t, err)
+		require.Error(t, db.PieceInfo().Delete(canceled, satelliteID, storj.NewPieceID()))
+
+		require.Error(t, db.UsedSerials().Add(canceled, satelliteID, storj.SerialNumber{1}, now))
+		require.Error(t, db.UsedSerials().DeleteExpired(canceled, now))
+
+		_, err = db.Orders().ListUnsent(canceled, 10)
+		require.Error(t, err)
+		_, err = db.Orders().ListUnsentSatellites(canceled)
+		require.Error(t, err)
+		_, _, err = db.Orders().ListUnsentBatch(canceled, orders.UnsentCursor{SatelliteID: satelliteID}, 10)
+		require.Error(t, err)
+		_, err = db.Orders().SummarizeUnsent(canceled)
+		require.Error(t, err)
+		_, _, err = db.Orders().ListArchived(canceled, orders.ArchiveCursor{}, 10)
+		require.Error(t, err)
+		_, err = db.Orders().DeleteArchived(canceled, now)
+		require.Error(t, err)
+		_, err = db.Orders().CleanExpired(canceled, now)
+		require.Error(t, err)
+
+		// the database keeps working after a canceled query
+		serialNumber := storj.SerialNumber{2}
+		timestamp := ptypes.TimestampNow()
+		err = db.Orders().Enqueue(ctx, &orders.Info{
+			Limit: &pb.OrderLimit2{
+				SerialNumber:    serialNumber,
+				SatelliteId:     satelliteID,
+				UplinkId:        uplink.ID,
+				StorageNodeId:   testplanet.MustPregeneratedSignedIdentity(0).ID,
+				PieceId:         storj.NewPieceID(),
+				Limit:           100,
+				Action:          pb.PieceAction_GET,
+				PieceExpiration: timestamp,
+				OrderExpiration: timestamp,
+			},
+			Order: &pb.Order2{
+				SerialNumber: serialNumber,
+				Amount:       50,
+			},
+			Uplink: uplink.PeerIdentity(),
+		})
+		require.NoError(t, err)
+
+		// a canceled batch leaves the orders unsent
+		_, err = db.Orders().ArchiveBatch(canceled, satelliteID, []orders.ArchiveRequest{
+			{Serial: serialNumber, Status: orders.StatusAccepted},
+		})
+		require.Error(t, err)
+
+		unsent, err := db.Orders().ListUnsent(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, unsent, 1)
+
+		_, err = db.Orders().ArchiveBatch(ctx, satelliteID, []orders.ArchiveRequest{
+			{Serial: serialNumber, Status: orders.StatusAccepted},
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestWithTx(t *testing.T) {
+	storagenodedbtest.Run(t, func(t *testing.T, db storagenode.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		satelliteID := testplanet.MustPregeneratedSignedIdentity(1).ID
+		uplink := testplanet.MustPregeneratedSignedIdentity(3)
+		now := time.Now()
+		expiration, err := ptypes.TimestampProto(now.Add(time.Hour))
+		require.NoError(t, err)
+
+		commit := func(serialNumber storj.SerialNumber, fail error) error {
+			return db.WithTx(ctx, func(ctx context.Context, tx piecestore.Tx) error {
+				pieceID := storj.NewPieceID()
+				err := tx.PieceInfo().Add(ctx, &pieces.Info{
+					SatelliteID:     satelliteID,
+					PieceID:         pieceID,
+					PieceSize:       100,
+					UplinkPieceHash: &pb.PieceHash{PieceId: pieceID},
+					Uplink:          uplink.PeerIdentity(),
+				})
+				if err != nil {
+					return err
+				}
+				err = tx.Orders().Enqueue(ctx, &orders.Info{
+					Limit: &pb.OrderLimit2{
+						SerialNumber:    serialNumber,
+						SatelliteId:     satelliteID,
+						UplinkId:        uplink.ID,
+						PieceId:         pieceID,
+						Action:          pb.PieceAction_PUT,
+						OrderExpiration: expiration,
+					},
+					Order:  &pb.Order2{SerialNumber: serialNumber, Amount: 100},
+					Uplink: uplink.PeerIdentity(),
+				})
+				if err != nil {
+					return err
+				}
+				if err := tx.Bandwidth().Add(ctx, satelliteID, pb.PieceAction_PUT, 100, now); err != nil {
+					return err
+				}
+				return fail
+			})
+		}
+
+		check := func(expected int64) {
+			used, err := db.PieceInfo().SpaceUsed(ctx)
+			require.NoError(t, err)
+			require.Equal(t, expected, used)
+
+			unsent, err := db.Orders().ListUnsent(ctx, 10)
+			require.NoError(t, err)
+			require.Len(t, unsent, int(expected/100))
+
+			usage, err := db.Bandwidth().Summary(ctx, now.Add(-time.Hour), now.Add(time.Hour))
+			require.NoError(t, err)
+			require.Equal(t, expected, usage.Put)
+		}
+
+		// every write is rolled back on failure
+		errFail := errs.New("fail")
+		require.Equal(t, errFail, commit(storj.SerialNumber{1}, errFail))
+		check(0)
+
+		// and committed otherwise
+		require.NoError(t, commit(storj.SerialNumber{2}, nil))
+		check(100)
+	})
+}
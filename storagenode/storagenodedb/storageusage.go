@@ -0,0 +1,85 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storagenodedb
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storagenode/storageusage"
+)
+
+type storageusagedb struct{ *infodb }
+
+// StorageUsage returns database for storing the data stored for each satellite over time.
+func (db *DB) StorageUsage() storageusage.DB { return db.info.StorageUsage() }
+
+// StorageUsage returns database for storing the data stored for each satellite over time.
+func (db *infodb) StorageUsage() storageusage.DB { return &storageusagedb{db} }
+
+// Store stores the stamps.
+func (db *storageusagedb) Store(ctx context.Context, stamps []storageusage.Stamp) (err error) {
+	if len(stamps) == 0 {
+		return nil
+	}
+
+	tx, err := db.beginTx(ctx)
+	if err != nil {
+		return ErrInfo.Wrap(err)
+	}
+	defer func() {
+		if err != nil {
+			err = errs.Combine(err, ErrInfo.Wrap(tx.Rollback()))
+		} else {
+			err = ErrInfo.Wrap(tx.Commit())
+		}
+	}()
+
+	for _, stamp := range stamps {
+		_, err := tx.ExecContext(ctx, db.Rebind(`
+			INSERT INTO storage_usage (satellite_id, at_rest_total, interval_start, interval_end)
+			VALUES (?, ?, ?, ?)
+			ON CONFLICT (satellite_id, interval_start) DO UPDATE SET
+				at_rest_total = excluded.at_rest_total,
+				interval_end = excluded.interval_end
+		`), stamp.SatelliteID, stamp.AtRestTotal, stamp.IntervalStart.UTC(), stamp.IntervalEnd.UTC())
+		if err != nil {
+			return ErrInfo.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// SummaryBySatellite returns the data stored for each satellite during the
+// intervals starting from from until to.
+func (db *storageusagedb) SummaryBySatellite(ctx context.Context, from, to time.Time) (_ map[storj.NodeID]storageusage.Summary, err error) {
+	rows, err := db.conn().QueryContext(ctx, db.Rebind(`
+		SELECT satellite_id, at_rest_total, interval_start, interval_end
+		FROM storage_usage
+		WHERE ? <= interval_start AND interval_start <= ?
+	`), from.UTC(), to.UTC())
+	if err != nil {
+		return nil, ErrInfo.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	summaries := map[storj.NodeID]storageusage.Summary{}
+	for rows.Next() {
+		var satelliteID storj.NodeID
+		var atRest float64
+		var start, end time.Time
+		if err := rows.Scan(&satelliteID, &atRest, &start, &end); err != nil {
+			return nil, ErrInfo.Wrap(err)
+		}
+
+		summary := summaries[satelliteID]
+		summary.AtRestTotal += atRest
+		summary.Hours += end.Sub(start).Hours()
+		summaries[satelliteID] = summary
+	}
+	return summaries, ErrInfo.Wrap(rows.Err())
+}
@@ -0,0 +1,40 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storagenodedb
+
+import (
+	"context"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/storagenode/piecestore"
+)
+
+// WithTx runs fn in a transaction of the information database, which is
+// committed when fn returns nil and rolled back otherwise.
+//
+// The tables of tx mustn't be used outside of fn, and the methods that run
+// their own transaction, such as archiving orders, fail within it.
+func (db *DB) WithTx(ctx context.Context, fn func(ctx context.Context, tx piecestore.Tx) error) error {
+	return db.info.WithTx(ctx, fn)
+}
+
+// WithTx runs fn in a transaction, see DB.WithTx.
+func (db *infodb) WithTx(ctx context.Context, fn func(ctx context.Context, tx piecestore.Tx) error) (err error) {
+	sqltx, err := db.beginTx(ctx)
+	if err != nil {
+		return ErrInfo.Wrap(err)
+	}
+	defer func() {
+		if err != nil {
+			err = errs.Combine(err, ErrInfo.Wrap(sqltx.Rollback()))
+		} else {
+			err = ErrInfo.Wrap(sqltx.Commit())
+		}
+	}()
+
+	txdb := *db
+	txdb.tx = sqltx
+	return fn(ctx, &txdb)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/zeebo/errs"
 
 	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storagenode/orders"
 	"storj.io/storj/storagenode/piecestore"
 )
 
@@ -23,33 +24,39 @@ func (db *DB) UsedSerials() piecestore.UsedSerials { return db.info.UsedSerials(
 // UsedSerials returns certificate database.
 func (db *infodb) UsedSerials() piecestore.UsedSerials { return &usedSerials{db} }
 
-// Add adds a serial to the database.
+// Add adds a serial to the database, it fails with orders.ErrSerialAlreadyExists
+// when the serial was already added.
 func (db *usedSerials) Add(ctx context.Context, satelliteID storj.NodeID, serialNumber storj.SerialNumber, expiration time.Time) error {
-	defer db.locked()()
-
-	_, err := db.db.Exec(`
+	result, err := db.conn().ExecContext(ctx, db.Rebind(`
 		INSERT INTO 
 			used_serial(satellite_id, serial_number, expiration) 
-		VALUES(?, ?, ?)`, satelliteID, serialNumber, expiration)
+		VALUES(?, ?, ?)
+		ON CONFLICT (satellite_id, serial_number) DO NOTHING`), satelliteID, serialNumber, expiration)
+	if err != nil {
+		return ErrInfo.Wrap(err)
+	}
 
-	return ErrInfo.Wrap(err)
+	count, err := result.RowsAffected()
+	if err != nil {
+		return ErrInfo.Wrap(err)
+	}
+	if count == 0 {
+		return orders.ErrSerialAlreadyExists.New("serial %s of satellite %s", serialNumber, satelliteID)
+	}
+	return nil
 }
 
 // DeleteExpired deletes expired serial numbers
 func (db *usedSerials) DeleteExpired(ctx context.Context, now time.Time) error {
-	defer db.locked()()
-
-	_, err := db.db.Exec(`DELETE FROM used_serial WHERE expiration < ?`, now)
+	_, err := db.conn().ExecContext(ctx, db.Rebind(`DELETE FROM used_serial WHERE expiration < ?`), now)
 
 	return ErrInfo.Wrap(err)
 }
 
 // IterateAll iterates all serials.
-// Note, this will lock the database and should only be used during startup.
+// Note, fn must not use the database and this should only be used during startup.
 func (db *usedSerials) IterateAll(ctx context.Context, fn piecestore.SerialNumberFn) (err error) {
-	defer db.locked()()
-
-	rows, err := db.db.Query(`SELECT satellite_id, serial_number, expiration FROM used_serial`)
+	rows, err := db.conn().QueryContext(ctx, db.Rebind(`SELECT satellite_id, serial_number, expiration FROM used_serial`))
 	if err != nil {
 		return ErrInfo.Wrap(err)
 	}
@@ -0,0 +1,104 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storageusage
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/internal/sync2"
+	"storj.io/storj/storagenode/pieces"
+)
+
+var (
+	// Error is the default error class for the storage usage.
+	Error = errs.Class("storageusage")
+	mon   = monkit.Package()
+)
+
+// Config defines parameters for recording the storage usage.
+type Config struct {
+	Interval time.Duration `help:"how frequently the data stored for each satellite is recorded" default:"1h0m0s"`
+}
+
+// Service records the data stored for each satellite on every interval.
+type Service struct {
+	log       *zap.Logger
+	db        DB
+	spaceUsed pieces.SpaceUsed
+
+	// last is when the storage usage was last recorded
+	last time.Time
+
+	Loop sync2.Cycle
+}
+
+// NewService creates a new storage usage service.
+func NewService(log *zap.Logger, db DB, spaceUsed pieces.SpaceUsed, config Config) *Service {
+	return &Service{
+		log:       log,
+		db:        db,
+		spaceUsed: spaceUsed,
+
+		Loop: *sync2.NewCycle(config.Interval),
+	}
+}
+
+// Run records the storage usage on every interval.
+func (service *Service) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return service.Loop.Run(ctx, func(ctx context.Context) error {
+		if err := service.Record(ctx, time.Now()); err != nil {
+			service.log.Error("recording storage usage", zap.Error(err))
+		}
+		return nil
+	})
+}
+
+// Record stores the data stored for each satellite since the previous call,
+// assuming the space used didn't change in between. The first call only
+// starts the interval, since the node may have been stopped before.
+func (service *Service) Record(ctx context.Context, now time.Time) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	now = now.UTC()
+	last := service.last
+	if last.IsZero() || !now.After(last) {
+		service.last = now
+		return nil
+	}
+
+	spaceUsed, err := service.spaceUsed.SpaceUsedBySatellite(ctx)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	hours := now.Sub(last).Hours()
+	stamps := make([]Stamp, 0, len(spaceUsed))
+	for satelliteID, used := range spaceUsed {
+		stamps = append(stamps, Stamp{
+			SatelliteID:   satelliteID,
+			AtRestTotal:   float64(used) * hours,
+			IntervalStart: last,
+			IntervalEnd:   now,
+		})
+	}
+	if err := service.db.Store(ctx, stamps); err != nil {
+		return Error.Wrap(err)
+	}
+
+	service.last = now
+	return nil
+}
+
+// Close stops the storage usage service.
+func (service *Service) Close() error {
+	service.Loop.Close()
+	return nil
+}
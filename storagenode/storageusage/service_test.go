@@ -0,0 +1,73 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storageusage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/storagenodedb/storagenodedbtest"
+	"storj.io/storj/storagenode/storageusage"
+)
+
+type spaceUsed map[storj.NodeID]int64
+
+func (used spaceUsed) SpaceUsed(ctx context.Context) (total int64, _ error) {
+	for _, size := range used {
+		total += size
+	}
+	return total, nil
+}
+
+func (used spaceUsed) SpaceUsedBySatellite(ctx context.Context) (map[storj.NodeID]int64, error) {
+	return used, nil
+}
+
+func TestRecord(t *testing.T) {
+	storagenodedbtest.Run(t, func(t *testing.T, db storagenode.DB) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		satellite0 := testplanet.MustPregeneratedSignedIdentity(0).ID
+		satellite1 := testplanet.MustPregeneratedSignedIdentity(1).ID
+
+		used := spaceUsed{satellite0: 1000, satellite1: 10}
+		service := storageusage.NewService(zaptest.NewLogger(t), db.StorageUsage(), used, storageusage.Config{Interval: time.Hour})
+
+		start := time.Date(2019, 4, 1, 0, 0, 0, 0, time.UTC)
+
+		// the first record only starts the interval
+		require.NoError(t, service.Record(ctx, start))
+		summaries, err := db.StorageUsage().SummaryBySatellite(ctx, start, start.Add(24*time.Hour))
+		require.NoError(t, err)
+		assert.Empty(t, summaries)
+
+		require.NoError(t, service.Record(ctx, start.Add(time.Hour)))
+		used[satellite0] = 3000
+		require.NoError(t, service.Record(ctx, start.Add(3*time.Hour)))
+
+		summaries, err = db.StorageUsage().SummaryBySatellite(ctx, start, start.Add(24*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, summaries, 2)
+
+		assert.Equal(t, 1000.0+3000*2, summaries[satellite0].AtRestTotal)
+		assert.Equal(t, 3.0, summaries[satellite0].Hours)
+		assert.Equal(t, int64(7000/3), summaries[satellite0].AverageStored())
+		assert.Equal(t, int64(10), summaries[satellite1].AverageStored())
+
+		// the intervals starting outside of the period are excluded
+		summaries, err = db.StorageUsage().SummaryBySatellite(ctx, start.Add(time.Hour), start.Add(24*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 2.0, summaries[satellite0].Hours)
+	})
+}
@@ -0,0 +1,46 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storageusage
+
+import (
+	"context"
+	"time"
+
+	"storj.io/storj/pkg/storj"
+)
+
+// Stamp is the data stored for a satellite during an interval.
+type Stamp struct {
+	SatelliteID storj.NodeID
+	// AtRestTotal is the data stored during the interval in byte-hours.
+	AtRestTotal   float64
+	IntervalStart time.Time
+	IntervalEnd   time.Time
+}
+
+// Summary is the data stored for a satellite during a period.
+type Summary struct {
+	// AtRestTotal is the data stored during the period in byte-hours.
+	AtRestTotal float64
+	// Hours is the duration of the intervals recorded during the period.
+	Hours float64
+}
+
+// AverageStored returns the average bytes stored during the recorded
+// intervals, or 0 when no interval was recorded.
+func (summary Summary) AverageStored() int64 {
+	if summary.Hours <= 0 {
+		return 0
+	}
+	return int64(summary.AtRestTotal / summary.Hours)
+}
+
+// DB stores the data stored for each satellite over time.
+type DB interface {
+	// Store stores the stamps.
+	Store(ctx context.Context, stamps []Stamp) error
+	// SummaryBySatellite returns the data stored for each satellite during the
+	// intervals starting from from until to.
+	SummaryBySatellite(ctx context.Context, from, to time.Time) (map[storj.NodeID]Summary, error)
+}
@@ -0,0 +1,139 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package trust
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/internal/memory"
+	"storj.io/storj/pkg/storj"
+)
+
+// maxListSize is the largest trust list which is read.
+const maxListSize = memory.MiB
+
+// Config defines the satellites trusted by the storage node.
+type Config struct {
+	Satellites      string        `help:"comma-separated list of trusted satellite URLs, as <node id>@<address>" default:""`
+	Lists           string        `help:"comma-separated list of HTTPS URLs of trust lists, which contain a trusted satellite URL per line" default:""`
+	RefreshInterval time.Duration `help:"how frequently the trust lists are fetched" default:"6h0m0s"`
+	FetchTimeout    time.Duration `help:"how long fetching a trust list can take before it is abandoned" default:"1m0s"`
+}
+
+// SatelliteURL identifies a trusted satellite, the address is optional.
+type SatelliteURL struct {
+	ID      storj.NodeID
+	Address string
+}
+
+// ParseSatelliteURL parses a satellite URL, as <node id>@<address> or <node id>.
+func ParseSatelliteURL(s string) (SatelliteURL, error) {
+	s = strings.TrimSpace(s)
+
+	idPart, address := s, ""
+	if i := strings.IndexByte(s, '@'); i >= 0 {
+		idPart, address = s[:i], s[i+1:]
+		if address == "" {
+			return SatelliteURL{}, Error.New("missing address in satellite URL %q", s)
+		}
+	}
+
+	id, err := storj.NodeIDFromString(idPart)
+	if err != nil {
+		return SatelliteURL{}, Error.New("invalid node id in satellite URL %q: %v", s, err)
+	}
+	return SatelliteURL{ID: id, Address: address}, nil
+}
+
+// String returns the satellite URL as <node id>@<address>.
+func (u SatelliteURL) String() string {
+	if u.Address == "" {
+		return u.ID.String()
+	}
+	return u.ID.String() + "@" + u.Address
+}
+
+// parseSatelliteURLs parses a comma-separated list of satellite URLs.
+func parseSatelliteURLs(s string) ([]SatelliteURL, error) {
+	var urls []SatelliteURL
+	for _, entry := range strings.Split(s, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		u, err := ParseSatelliteURL(entry)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, u)
+	}
+	return urls, nil
+}
+
+// parseListURLs parses a comma-separated list of trust list URLs, which must
+// use HTTPS so that the list can't be tampered with.
+func parseListURLs(s string) ([]string, error) {
+	var lists []string
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		u, err := url.Parse(entry)
+		if err != nil {
+			return nil, Error.New("invalid trust list URL %q: %v", entry, err)
+		}
+		if u.Scheme != "https" || u.Host == "" {
+			return nil, Error.New("trust list URL %q must be an https URL", entry)
+		}
+		lists = append(lists, entry)
+	}
+	return lists, nil
+}
+
+// fetchList downloads the trust list at listURL. Empty lines and lines
+// starting with # are ignored, any invalid entry rejects the whole list.
+func fetchList(ctx context.Context, client *http.Client, listURL string) (_ []SatelliteURL, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	req, err := http.NewRequest(http.MethodGet, listURL, nil)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, resp.Body.Close()) }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, Error.New("fetching trust list %q: %s", listURL, resp.Status)
+	}
+
+	var urls []SatelliteURL
+	scanner := bufio.NewScanner(io.LimitReader(resp.Body, maxListSize.Int64()))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		u, err := ParseSatelliteURL(line)
+		if err != nil {
+			return nil, Error.New("trust list %q: %v", listURL, err)
+		}
+		urls = append(urls, u)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return urls, nil
+}
@@ -0,0 +1,108 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package trust
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testrand"
+)
+
+func TestParseSatelliteURL(t *testing.T) {
+	id := testrand.New(t).NodeID()
+
+	u, err := ParseSatelliteURL(id.String() + "@127.0.0.1:7777")
+	require.NoError(t, err)
+	assert.Equal(t, SatelliteURL{ID: id, Address: "127.0.0.1:7777"}, u)
+	assert.Equal(t, id.String()+"@127.0.0.1:7777", u.String())
+
+	u, err = ParseSatelliteURL(" " + id.String() + " ")
+	require.NoError(t, err)
+	assert.Equal(t, SatelliteURL{ID: id}, u)
+
+	for _, invalid := range []string{"", "invalid@127.0.0.1:7777", id.String() + "@"} {
+		_, err := ParseSatelliteURL(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	rand := testrand.New(t)
+	configured, listed, replaced := rand.NodeID(), rand.NodeID(), rand.NodeID()
+
+	var mu sync.Mutex
+	list := fmt.Sprintf("# trusted satellites\n\n%s@listed.example.com:7777\n%s@listed.example.com:7777\n", listed, configured)
+	status := http.StatusOK
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(list))
+	}))
+	defer server.Close()
+
+	pool, err := NewPool(zaptest.NewLogger(t), nil, false, Config{
+		Satellites: configured.String() + "@configured.example.com:7777",
+		Lists:      server.URL,
+	})
+	require.NoError(t, err)
+	pool.client = server.Client()
+
+	// the listed satellites are trusted once fetched
+	assert.Error(t, pool.VerifySatelliteID(ctx, listed))
+	require.NoError(t, pool.Refresh(ctx))
+	assert.NoError(t, pool.VerifySatelliteID(ctx, configured))
+	assert.NoError(t, pool.VerifySatelliteID(ctx, listed))
+	assert.Error(t, pool.VerifySatelliteID(ctx, replaced))
+
+	// the configured address takes precedence
+	assert.Equal(t, "configured.example.com:7777", pool.GetAddress(ctx, configured))
+	assert.Equal(t, "listed.example.com:7777", pool.GetAddress(ctx, listed))
+	satellite, err := pool.FindSatellite(ctx, configured)
+	require.NoError(t, err)
+	assert.Equal(t, configured, satellite.Id)
+	assert.Equal(t, "configured.example.com:7777", satellite.Address.Address)
+
+	// a failed fetch keeps the previous list
+	mu.Lock()
+	status = http.StatusInternalServerError
+	mu.Unlock()
+	assert.Error(t, pool.Refresh(ctx))
+	assert.NoError(t, pool.VerifySatelliteID(ctx, listed))
+
+	// so does an invalid list
+	mu.Lock()
+	status, list = http.StatusOK, replaced.String()+"\ninvalid\n"
+	mu.Unlock()
+	assert.Error(t, pool.Refresh(ctx))
+	assert.NoError(t, pool.VerifySatelliteID(ctx, listed))
+	assert.Error(t, pool.VerifySatelliteID(ctx, replaced))
+
+	// an updated list replaces the previous one
+	mu.Lock()
+	list = replaced.String() + "\n"
+	mu.Unlock()
+	require.NoError(t, pool.Refresh(ctx))
+	assert.Error(t, pool.VerifySatelliteID(ctx, listed))
+	assert.NoError(t, pool.VerifySatelliteID(ctx, replaced))
+	assert.Equal(t, "", pool.GetAddress(ctx, replaced))
+
+	// removing the list untrusts its satellites
+	require.NoError(t, pool.SetTrusted(false, Config{Satellites: configured.String()}))
+	assert.NoError(t, pool.VerifySatelliteID(ctx, configured))
+	assert.Error(t, pool.VerifySatelliteID(ctx, replaced))
+}
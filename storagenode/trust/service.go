@@ -6,23 +6,46 @@ package trust
 import (
 	"context"
 	"fmt"
-	"strings"
+	"net/http"
+	"sort"
 	"sync"
 
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/internal/sync2"
 	"storj.io/storj/pkg/auth/signing"
 	"storj.io/storj/pkg/identity"
 	"storj.io/storj/pkg/kademlia"
+	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/storj"
 )
 
+var (
+	// Error is the default error class for the trust package.
+	Error = errs.Class("trust")
+
+	mon = monkit.Package()
+)
+
 // Pool implements different peer verifications.
 type Pool struct {
+	log      *zap.Logger
 	kademlia *kademlia.Kademlia
+	client   *http.Client
 
 	mu sync.RWMutex
 
 	trustAllSatellites bool
-	trustedSatellites  map[storj.NodeID]*satelliteInfoCache
+	configured         []SatelliteURL
+	lists              []string
+	listed             map[string][]SatelliteURL
+
+	trustedSatellites map[storj.NodeID]*satelliteInfoCache
+	addresses         map[storj.NodeID]string
+
+	Loop sync2.Cycle
 }
 
 // satelliteInfoCache caches identity information about a satellite
@@ -32,51 +55,159 @@ type satelliteInfoCache struct {
 	err      error
 }
 
-// NewPool creates a new trust pool using kademlia to find certificates and with the specified list of trusted satellites.
-func NewPool(kademlia *kademlia.Kademlia, trustAll bool, trustedSatelliteIDs string) (*Pool, error) {
-	if trustAll {
-		return &Pool{
-			kademlia: kademlia,
+// NewPool creates a new trust pool using kademlia to find certificates and
+// with the trusted satellites of config. The trust lists are fetched by Run.
+func NewPool(log *zap.Logger, kademlia *kademlia.Kademlia, trustAll bool, config Config) (*Pool, error) {
+	pool := &Pool{
+		log:      log,
+		kademlia: kademlia,
+		client:   &http.Client{Timeout: config.FetchTimeout},
 
-			trustAllSatellites: true,
-			trustedSatellites:  map[storj.NodeID]*satelliteInfoCache{},
-		}, nil
+		listed:            map[string][]SatelliteURL{},
+		trustedSatellites: map[storj.NodeID]*satelliteInfoCache{},
+		addresses:         map[storj.NodeID]string{},
+
+		Loop: *sync2.NewCycle(config.RefreshInterval),
 	}
+	if err := pool.SetTrusted(trustAll, config); err != nil {
+		return nil, err
+	}
+	return pool, nil
+}
 
+// SetTrusted replaces the trusted satellites and trust lists. The pool is left
+// unchanged when the configuration is invalid. The satellites of the lists
+// which remain configured stay trusted until the lists are fetched again.
+func (pool *Pool) SetTrusted(trustAll bool, config Config) error {
 	// TODO: preload all satellite peer identities
 
-	// parse the comma separated list of approved satellite IDs into an array of storj.NodeIDs
+	var configured []SatelliteURL
+	var lists []string
+	if !trustAll {
+		var err error
+		configured, err = parseSatelliteURLs(config.Satellites)
+		if err != nil {
+			return err
+		}
+		lists, err = parseListURLs(config.Lists)
+		if err != nil {
+			return err
+		}
+	}
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	listed := make(map[string][]SatelliteURL, len(lists))
+	for _, list := range lists {
+		listed[list] = pool.listed[list]
+	}
+
+	pool.trustAllSatellites = trustAll
+	pool.configured = configured
+	pool.lists = lists
+	pool.listed = listed
+	pool.rebuild()
+	return nil
+}
+
+// rebuild recomputes the trusted satellites from the configured satellites
+// and the fetched lists, keeping the identities of satellites that remain
+// trusted. The configured addresses take precedence over the listed ones.
+// The caller must hold the write lock.
+func (pool *Pool) rebuild() {
 	trusted := make(map[storj.NodeID]*satelliteInfoCache)
+	addresses := make(map[storj.NodeID]string)
 
-	for _, s := range strings.Split(trustedSatelliteIDs, ",") {
-		if s == "" {
-			continue
+	if pool.trustAllSatellites {
+		for id, info := range pool.trustedSatellites {
+			trusted[id] = info
+		}
+	}
+
+	add := func(u SatelliteURL) {
+		info, ok := pool.trustedSatellites[u.ID]
+		if !ok {
+			info = &satelliteInfoCache{} // we will set these later
+		}
+		trusted[u.ID] = info
+		if u.Address != "" {
+			addresses[u.ID] = u.Address
+		}
+	}
+	for _, list := range pool.lists {
+		for _, u := range pool.listed[list] {
+			add(u)
+		}
+	}
+	for _, u := range pool.configured {
+		add(u)
+	}
+
+	pool.trustedSatellites = trusted
+	pool.addresses = addresses
+}
+
+// Run fetches the trust lists on every interval.
+func (pool *Pool) Run(ctx context.Context) error {
+	return pool.Loop.Run(ctx, func(ctx context.Context) error {
+		if err := pool.Refresh(ctx); err != nil {
+			pool.log.Warn("refreshing trust lists", zap.Error(err))
 		}
+		return nil
+	})
+}
+
+// Refresh fetches the trust lists. The satellites of a list which can't be
+// fetched stay trusted until the next successful fetch.
+func (pool *Pool) Refresh(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	pool.mu.RLock()
+	lists := pool.lists
+	pool.mu.RUnlock()
 
-		satelliteID, err := storj.NodeIDFromString(s)
+	fetched := make(map[string][]SatelliteURL, len(lists))
+	var group errs.Group
+	for _, list := range lists {
+		urls, err := fetchList(ctx, pool.client, list)
 		if err != nil {
-			return nil, err
+			group.Add(err)
+			continue
 		}
-		trusted[satelliteID] = &satelliteInfoCache{} // we will set these later
+		fetched[list] = urls
 	}
 
-	return &Pool{
-		kademlia: kademlia,
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	for list, urls := range fetched {
+		// the lists could have been reconfigured while fetching
+		if _, ok := pool.listed[list]; ok {
+			pool.listed[list] = urls
+		}
+	}
+	pool.rebuild()
 
-		trustAllSatellites: false,
-		trustedSatellites:  trusted,
-	}, nil
+	mon.IntVal("trusted_satellites").Observe(int64(len(pool.trustedSatellites)))
+	return group.Err()
+}
+
+// Close stops refreshing the trust lists.
+func (pool *Pool) Close() error {
+	pool.Loop.Close()
+	return nil
 }
 
 // VerifySatelliteID checks whether id corresponds to a trusted satellite.
 func (pool *Pool) VerifySatelliteID(ctx context.Context, id storj.NodeID) error {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
 	if pool.trustAllSatellites {
 		return nil
 	}
 
-	pool.mu.RLock()
-	defer pool.mu.RUnlock()
-
 	_, ok := pool.trustedSatellites[id]
 	if !ok {
 		return fmt.Errorf("satellite %q is untrusted", id)
@@ -84,6 +215,46 @@ func (pool *Pool) VerifySatelliteID(ctx context.Context, id storj.NodeID) error
 	return nil
 }
 
+// GetAddress returns the address of a trusted satellite, or an empty string
+// when its address isn't known and has to be looked up.
+func (pool *Pool) GetAddress(ctx context.Context, id storj.NodeID) string {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	return pool.addresses[id]
+}
+
+// FindSatellite returns the satellite at its trusted address, or looks it up
+// on the network when the address isn't known.
+func (pool *Pool) FindSatellite(ctx context.Context, id storj.NodeID) (_ pb.Node, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if address := pool.GetAddress(ctx, id); address != "" {
+		return pb.Node{
+			Id: id,
+			Address: &pb.NodeAddress{
+				Transport: pb.NodeTransport_TCP_TLS_GRPC,
+				Address:   address,
+			},
+		}, nil
+	}
+	return pool.kademlia.FindNode(ctx, id)
+}
+
+// GetSatellites returns the trusted satellites. When all the satellites are
+// trusted, only the satellites which were seen so far are returned.
+func (pool *Pool) GetSatellites(ctx context.Context) storj.NodeIDList {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	satellites := make(storj.NodeIDList, 0, len(pool.trustedSatellites))
+	for id := range pool.trustedSatellites {
+		satellites = append(satellites, id)
+	}
+	sort.Sort(satellites)
+	return satellites
+}
+
 // VerifyUplinkID verifides whether id corresponds to a trusted uplink.
 func (pool *Pool) VerifyUplinkID(ctx context.Context, id storj.NodeID) error {
 	// trusting all the uplinks for now
@@ -95,9 +266,10 @@ func (pool *Pool) GetSignee(ctx context.Context, id storj.NodeID) (signing.Signe
 	// lookup peer identity with id
 	pool.mu.RLock()
 	info, ok := pool.trustedSatellites[id]
+	trustAll := pool.trustAllSatellites
 	pool.mu.RUnlock()
 
-	if pool.trustAllSatellites {
+	if trustAll {
 		// add a new entry
 		if !ok {
 			pool.mu.Lock()
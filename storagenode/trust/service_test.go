@@ -0,0 +1,45 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package trust_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testrand"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storagenode/trust"
+)
+
+func TestSetTrusted(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	rand := testrand.New(t)
+	a, b := rand.NodeID(), rand.NodeID()
+
+	pool, err := trust.NewPool(zaptest.NewLogger(t), nil, false, trust.Config{Satellites: a.String()})
+	require.NoError(t, err)
+	assert.NoError(t, pool.VerifySatelliteID(ctx, a))
+	assert.Error(t, pool.VerifySatelliteID(ctx, b))
+
+	require.NoError(t, pool.SetTrusted(false, trust.Config{Satellites: b.String()}))
+	assert.Error(t, pool.VerifySatelliteID(ctx, a))
+	assert.NoError(t, pool.VerifySatelliteID(ctx, b))
+	assert.Equal(t, storj.NodeIDList{b}, pool.GetSatellites(ctx))
+
+	// invalid lists leave the pool unchanged
+	assert.Error(t, pool.SetTrusted(false, trust.Config{Satellites: a.String() + ",invalid"}))
+	assert.Error(t, pool.SetTrusted(false, trust.Config{Satellites: a.String(), Lists: "http://example.com/satellites"}))
+	assert.Error(t, pool.VerifySatelliteID(ctx, a))
+	assert.NoError(t, pool.VerifySatelliteID(ctx, b))
+
+	require.NoError(t, pool.SetTrusted(true, trust.Config{}))
+	assert.NoError(t, pool.VerifySatelliteID(ctx, a))
+	assert.NoError(t, pool.VerifySatelliteID(ctx, b))
+}
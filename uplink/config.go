@@ -35,6 +35,9 @@ type RSConfig struct {
 	RepairThreshold  int         `help:"the minimum safe pieces before a repair is triggered. m." default:"35" devDefault:"6"`
 	SuccessThreshold int         `help:"the desired total pieces for a segment. o." default:"80" devDefault:"8"`
 	MaxThreshold     int         `help:"the largest amount of pieces to encode to. n." default:"95" devDefault:"10"`
+
+	EncoderConcurrency int `help:"maximum number of erasure shares encoded concurrently per segment (0 means unlimited)" default:"0"`
+	LongTailMargin     int `help:"maximum number of pieces uploaded beyond the success threshold (0 means unlimited), the satellite hands out no more order limits than its own maximum allows" default:"0"`
 }
 
 // EncryptionConfig is a configuration struct that keeps details about
@@ -89,7 +92,7 @@ func (c Config) GetMetainfo(ctx context.Context, identity *identity.FullIdentity
 		return nil, nil, Error.New("failed to connect to metainfo service: %v", err)
 	}
 
-	ec := ecclient.NewClient(tc, c.RS.MaxBufferMem.Int())
+	ec := ecclient.NewClient(tc, c.RS.MaxBufferMem.Int(), c.RS.EncoderConcurrency, c.RS.LongTailMargin)
 	fc, err := infectious.NewFEC(c.RS.MinThreshold, c.RS.MaxThreshold)
 	if err != nil {
 		return nil, nil, Error.New("failed to create erasure coding client: %v", err)
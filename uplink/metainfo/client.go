@@ -18,6 +18,7 @@ import (
 	"storj.io/storj/pkg/auth/grpcauth"
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/tracing"
 	"storj.io/storj/pkg/transport"
 	"storj.io/storj/storage"
 )
@@ -65,7 +66,7 @@ func NewClient(ctx context.Context, tc transport.Client, address string, APIKey
 	conn, err := tc.DialAddress(
 		ctx,
 		address,
-		grpc.WithUnaryInterceptor(apiKeyInjector),
+		grpc.WithUnaryInterceptor(transport.CombineUnaryInterceptors(tracing.UnaryClientInterceptor, apiKeyInjector)),
 	)
 	if err != nil {
 		return nil, Error.Wrap(err)
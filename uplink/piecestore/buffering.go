@@ -67,6 +67,11 @@ func (download *BufferedDownload) Close() error {
 	return download.download.Close()
 }
 
+// GetPieceHeader returns the header of the piece sent with an audit download.
+func (download *BufferedDownload) GetPieceHeader() *pb.PieceHeader {
+	return download.download.GetPieceHeader()
+}
+
 // LockingUpload adds a lock around upload making it safe to use concurrently.
 // TODO: this shouldn't be needed.
 type LockingUpload struct {
@@ -115,3 +120,10 @@ func (download *LockingDownload) Close() error {
 	defer download.mu.Unlock()
 	return download.download.Close()
 }
+
+// GetPieceHeader returns the header of the piece sent with an audit download.
+func (download *LockingDownload) GetPieceHeader() *pb.PieceHeader {
+	download.mu.Lock()
+	defer download.mu.Unlock()
+	return download.download.GetPieceHeader()
+}
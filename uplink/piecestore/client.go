@@ -10,6 +10,8 @@ import (
 	"github.com/zeebo/errs"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"storj.io/storj/internal/memory"
 	"storj.io/storj/pkg/auth/signing"
@@ -19,6 +21,12 @@ import (
 // Error is the default error class for piecestore client.
 var Error = errs.Class("piecestore")
 
+// IsBusy returns whether the storage node rejected the request because it's
+// handling too many requests, the request can be retried on another node.
+func IsBusy(err error) bool {
+	return status.Code(errs.Unwrap(err)) == codes.ResourceExhausted
+}
+
 // Config defines piecestore client parameters fro upload and download.
 type Config struct {
 	UploadBufferSize   int64
@@ -15,10 +15,14 @@ import (
 )
 
 // Downloader is interface that can be used for downloading content.
-// It matches signature of `io.ReadCloser`.
+// It matches signature of `io.ReadCloser`, with the addition of the
+// header of the piece that storage nodes send to audits.
 type Downloader interface {
 	Read([]byte) (int, error)
 	Close() error
+	// GetPieceHeader returns the header of the piece, which is nil until
+	// data was read and for downloads other than audits.
+	GetPieceHeader() *pb.PieceHeader
 }
 
 // Download implements downloading from a piecestore.
@@ -37,6 +41,7 @@ type Download struct {
 	allocationStep int64
 
 	unread ReadBuffer
+	header *pb.PieceHeader
 }
 
 // Download starts a new download using the specified order limit at the specified offset and size.
@@ -155,6 +160,9 @@ func (client *Download) Read(data []byte) (read int, _ error) {
 			client.downloaded += int64(len(response.Chunk.Data))
 			client.unread.Fill(response.Chunk.Data)
 		}
+		if response != nil && response.Header != nil {
+			client.header = response.Header
+		}
 
 		// we still need to continue until we have actually handled all of the errors
 		client.unread.IncludeError(err)
@@ -167,6 +175,11 @@ func (client *Download) Read(data []byte) (read int, _ error) {
 	return read, nil
 }
 
+// GetPieceHeader returns the header of the piece sent with an audit download.
+func (client *Download) GetPieceHeader() *pb.PieceHeader {
+	return client.header
+}
+
 // Close closes the downloading.
 func (client *Download) Close() error {
 	alldone := client.read == client.downloadSize
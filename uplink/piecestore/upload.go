@@ -121,7 +121,7 @@ func (client *Upload) Write(data []byte) (written int, _ error) {
 			Order: order,
 		})
 		if err != nil {
-			client.sendError = err
+			client.sendError = client.closeReason(err)
 			return written, ErrProtocol.Wrap(client.sendError)
 		}
 
@@ -133,7 +133,7 @@ func (client *Upload) Write(data []byte) (written int, _ error) {
 			},
 		})
 		if err != nil {
-			client.sendError = err
+			client.sendError = client.closeReason(err)
 			return written, ErrProtocol.Wrap(client.sendError)
 		}
 
@@ -148,6 +148,18 @@ func (client *Upload) Write(data []byte) (written int, _ error) {
 	return written, nil
 }
 
+// closeReason returns why the storage node closed the stream when sending
+// failed with io.EOF, such as the storage node being busy.
+func (client *Upload) closeReason(sendErr error) error {
+	if sendErr != io.EOF {
+		return sendErr
+	}
+	if _, err := client.stream.CloseAndRecv(); err != nil && err != io.EOF {
+		return err
+	}
+	return sendErr
+}
+
 // Cancel cancels the uploading.
 func (client *Upload) Cancel() error {
 	if client.finished {
@@ -194,8 +206,9 @@ func (client *Upload) Commit() (*pb.PieceHash, error) {
 	if response == nil || response.Done == nil {
 		// combine all the errors from before
 		// sendErr is io.EOF when failed to send, so don't care
-		// closeErr is io.EOF when storage node closed before sending us a response
-		return nil, errs.Combine(ErrProtocol.New("expected piece hash"), ignoreEOF(sendErr), ignoreEOF(closeErr))
+		// closeErr is io.EOF when storage node closed before sending us a response,
+		// otherwise it's the reason of the storage node, which comes first
+		return nil, errs.Combine(ignoreEOF(closeErr), ErrProtocol.New("expected piece hash"), ignoreEOF(sendErr))
 	}
 
 	// verification
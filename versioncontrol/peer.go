@@ -0,0 +1,180 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package versioncontrol
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"storj.io/storj/internal/version"
+)
+
+// Error is the error class for the version control server
+var Error = errs.Class("version control error")
+
+// Config is all the configuration parameters for the version control server
+type Config struct {
+	Address  string `help:"public address to listen on" default:":8080"`
+	Versions ServiceVersions
+}
+
+// ServiceVersions contains the version requirements for every process type
+type ServiceVersions struct {
+	Satellite   ProcessConfig
+	Storagenode ProcessConfig
+	Uplink      ProcessConfig
+	Gateway     ProcessConfig
+	Bootstrap   ProcessConfig
+}
+
+// ProcessConfig contains the version requirements of a single process type
+type ProcessConfig struct {
+	Minimum       string `help:"minimum allowed version" default:"v0.0.1"`
+	Enforced      string `help:"oldest version the satellites still work with, empty when no version is enforced" default:""`
+	Suggested     string `help:"version processes should upgrade to" default:"v0.0.1"`
+	RolloutSeed   string `help:"seed that selects the nodes of a rollout" default:""`
+	RolloutCursor int    `help:"percentage of nodes, between 0 and 100, that should upgrade to the suggested version" default:"0"`
+}
+
+// Peer is the representation of a version control server.
+type Peer struct {
+	Log *zap.Logger
+
+	Server struct {
+		Endpoint http.Server
+		Listener net.Listener
+	}
+
+	Versions version.AllowedVersions
+	response []byte
+}
+
+// New creates a new version control server.
+func New(log *zap.Logger, config *Config) (peer *Peer, err error) {
+	peer = &Peer{
+		Log: log,
+	}
+
+	peer.Versions.Satellite, err = config.Versions.Satellite.parse()
+	if err != nil {
+		return nil, Error.New("satellite: %v", err)
+	}
+	peer.Versions.Storagenode, err = config.Versions.Storagenode.parse()
+	if err != nil {
+		return nil, Error.New("storagenode: %v", err)
+	}
+	peer.Versions.Uplink, err = config.Versions.Uplink.parse()
+	if err != nil {
+		return nil, Error.New("uplink: %v", err)
+	}
+	peer.Versions.Gateway, err = config.Versions.Gateway.parse()
+	if err != nil {
+		return nil, Error.New("gateway: %v", err)
+	}
+	peer.Versions.Bootstrap, err = config.Versions.Bootstrap.parse()
+	if err != nil {
+		return nil, Error.New("bootstrap: %v", err)
+	}
+
+	peer.response, err = json.Marshal(peer.Versions)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", peer.handleGet)
+	peer.Server.Endpoint = http.Server{
+		Handler: mux,
+	}
+
+	peer.Server.Listener, err = net.Listen("tcp", config.Address)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	return peer, nil
+}
+
+// parse converts the configuration to version requirements
+func (config ProcessConfig) parse() (process version.Process, err error) {
+	process.Minimum, err = version.NewSemVer(config.Minimum)
+	if err != nil {
+		return process, err
+	}
+	if config.Enforced != "" {
+		process.Enforced, err = version.NewSemVer(config.Enforced)
+		if err != nil {
+			return process, err
+		}
+		if process.Minimum.Compare(process.Enforced) < 0 {
+			return process, Error.New("minimum version %s is below enforced version %s", process.Minimum, process.Enforced)
+		}
+	}
+	process.Suggested, err = version.NewSemVer(config.Suggested)
+	if err != nil {
+		return process, err
+	}
+	if process.Suggested.Compare(process.Minimum) < 0 {
+		return process, Error.New("suggested version %s is below minimum version %s", process.Suggested, process.Minimum)
+	}
+	if config.RolloutCursor < 0 || config.RolloutCursor > 100 {
+		return process, Error.New("rollout cursor %d is not a percentage", config.RolloutCursor)
+	}
+	process.Rollout = version.Rollout{
+		Seed:   config.RolloutSeed,
+		Cursor: config.RolloutCursor,
+	}
+	return process, nil
+}
+
+// handleGet serves the allowed versions
+func (peer *Peer) handleGet(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(peer.response); err != nil {
+		peer.Log.Error("failed to write response", zap.Error(err))
+	}
+}
+
+// Run runs the version control server until it's either closed or it errors.
+func (peer *Peer) Run(ctx context.Context) (err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	var group errgroup.Group
+	group.Go(func() error {
+		<-ctx.Done()
+		return Error.Wrap(peer.Server.Endpoint.Shutdown(context.Background()))
+	})
+	group.Go(func() error {
+		defer cancel()
+		err := peer.Server.Endpoint.Serve(peer.Server.Listener)
+		if err == http.ErrServerClosed {
+			return nil
+		}
+		return Error.Wrap(err)
+	})
+
+	return group.Wait()
+}
+
+// Close closes all the resources.
+func (peer *Peer) Close() error {
+	err := peer.Server.Endpoint.Close()
+	// the endpoint only owns the listener once Run has started serving,
+	// closing it again releases the port otherwise
+	_ = peer.Server.Listener.Close()
+	return Error.Wrap(err)
+}
+
+// Addr returns the public address.
+func (peer *Peer) Addr() string { return peer.Server.Listener.Addr().String() }
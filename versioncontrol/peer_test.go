@@ -0,0 +1,102 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package versioncontrol_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testrand"
+	"storj.io/storj/internal/version"
+	"storj.io/storj/versioncontrol"
+)
+
+func TestPeer(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	config := versioncontrol.Config{Address: "127.0.0.1:0"}
+	for _, process := range []*versioncontrol.ProcessConfig{
+		&config.Versions.Satellite,
+		&config.Versions.Storagenode,
+		&config.Versions.Uplink,
+		&config.Versions.Gateway,
+		&config.Versions.Bootstrap,
+	} {
+		process.Minimum = "v0.0.1"
+		process.Suggested = "v0.0.1"
+	}
+	config.Versions.Storagenode = versioncontrol.ProcessConfig{
+		Minimum:       "v0.10.0",
+		Suggested:     "v0.11.0",
+		Enforced:      "v0.9.0",
+		RolloutSeed:   "release-0.11",
+		RolloutCursor: 50,
+	}
+
+	peer, err := versioncontrol.New(zaptest.NewLogger(t), &config)
+	require.NoError(t, err)
+	ctx.Go(func() error { return peer.Run(ctx) })
+	defer ctx.Check(peer.Close)
+
+	address := "http://" + peer.Addr()
+
+	versions, err := version.QueryVersions(ctx, http.DefaultClient, address)
+	require.NoError(t, err)
+	assert.Equal(t, peer.Versions, versions)
+	assert.Equal(t, version.SemVer{Major: 0, Minor: 10, Patch: 0}, versions.Storagenode.Minimum)
+	assert.Equal(t, version.Rollout{Seed: "release-0.11", Cursor: 50}, versions.Storagenode.Rollout)
+
+	nodeID := testrand.New(t).NodeID()
+	check := func(process string, semver version.SemVer) bool {
+		service := version.NewService(zaptest.NewLogger(t), version.Config{
+			ServerAddress:  address,
+			RequestTimeout: time.Minute,
+			CheckInterval:  time.Minute,
+		}, version.Info{Version: semver}, process, nodeID)
+
+		allowed, err := service.CheckVersion(ctx)
+		require.NoError(t, err)
+		return allowed
+	}
+
+	assert.False(t, check("storagenode", version.SemVer{Major: 0, Minor: 9, Patch: 0}))
+	assert.True(t, check("storagenode", version.SemVer{Major: 0, Minor: 10, Patch: 0}))
+	assert.True(t, check("satellite", version.SemVer{Major: 0, Minor: 9, Patch: 0}))
+	assert.False(t, check("satellite", version.SemVer{}))
+
+	startup := func(semver version.SemVer) error {
+		service := version.NewService(zaptest.NewLogger(t), version.Config{
+			ServerAddress:  address,
+			RequestTimeout: time.Minute,
+			CheckInterval:  time.Minute,
+			RefuseEnforced: true,
+		}, version.Info{Version: semver}, "storagenode", nodeID)
+		return service.CheckStartup(ctx)
+	}
+
+	assert.Error(t, startup(version.SemVer{Major: 0, Minor: 8, Patch: 0}))
+	assert.NoError(t, startup(version.SemVer{Major: 0, Minor: 9, Patch: 0}))
+}
+
+func TestInvalidConfig(t *testing.T) {
+	for _, process := range []versioncontrol.ProcessConfig{
+		{Minimum: "latest", Suggested: "v0.0.1"},
+		{Minimum: "v0.2.0", Suggested: "v0.1.0"},
+		{Minimum: "v0.1.0", Suggested: "v0.1.0", RolloutCursor: 101},
+		{Minimum: "v0.1.0", Suggested: "v0.1.0", Enforced: "v0.2.0"},
+	} {
+		config := versioncontrol.Config{Address: "127.0.0.1:0"}
+		config.Versions.Satellite = process
+
+		_, err := versioncontrol.New(zaptest.NewLogger(t), &config)
+		assert.Error(t, err)
+	}
+}